@@ -93,6 +93,12 @@ export const appRoutes: Route[] = [
     loadComponent: () =>
       import('./pages/chat/chat-page.component').then((m) => m.ChatPageComponent),
   },
+  {
+    path: 'device',
+    canActivate: [authGuard],
+    loadComponent: () =>
+      import('./pages/device/device-page.component').then((m) => m.DevicePageComponent),
+  },
   {
     path: '**',
     redirectTo: 'dashboard',
@@ -0,0 +1,108 @@
+import { Component, inject, OnInit, signal } from '@angular/core';
+import { HttpErrorResponse } from '@angular/common/http';
+import { FormsModule } from '@angular/forms';
+import { ActivatedRoute } from '@angular/router';
+import { AuthApiService } from '@devjournal/data-access-api';
+import { DeviceAuthorization } from '@devjournal/shared-models';
+
+// Lets the signed-in user approve an editor plugin showing a device sign-in code
+@Component({
+  selector: 'app-device-page',
+  standalone: true,
+  imports: [FormsModule],
+  template: `
+    <div class="device-page">
+      <div class="device-card">
+        <h2>Connect an editor</h2>
+
+        @switch (state()) {
+          @case ('approved') {
+            <p>{{ request()?.clientName }} is signed in. You can return to your editor.</p>
+          }
+          @case ('denied') {
+            <p>The sign-in was denied. Nothing was connected.</p>
+          }
+          @default {
+            @if (request(); as req) {
+              <p><strong>{{ req.clientName }}</strong> wants to access your DevJournal account.</p>
+              <p class="code">{{ req.userCode }}</p>
+              <p class="hint">Only approve if this matches the code shown in your editor.</p>
+              <div class="actions">
+                <button class="btn-primary" (click)="approve()" [disabled]="busy()">Approve</button>
+                <button class="btn-secondary" (click)="deny()" [disabled]="busy()">Deny</button>
+              </div>
+            } @else {
+              <form (ngSubmit)="lookup()">
+                <label for="user-code">Enter the code shown in your editor</label>
+                <input
+                  id="user-code"
+                  name="userCode"
+                  [(ngModel)]="code"
+                  placeholder="XXXX-XXXX"
+                  autocomplete="off"
+                  autocapitalize="characters"
+                />
+                <button class="btn-primary" type="submit" [disabled]="busy() || !code">Continue</button>
+              </form>
+            }
+          }
+        }
+
+        @if (error()) {
+          <p class="error">{{ error() }}</p>
+        }
+      </div>
+    </div>
+  `,
+  styleUrl: './device-page.component.scss',
+})
+export class DevicePageComponent implements OnInit {
+  private readonly authApi = inject(AuthApiService);
+  private readonly route = inject(ActivatedRoute);
+
+  readonly request = signal<DeviceAuthorization | null>(null);
+  readonly state = signal<'pending' | 'approved' | 'denied'>('pending');
+  readonly busy = signal(false);
+  readonly error = signal<string | null>(null);
+
+  code = '';
+
+  ngOnInit(): void {
+    const code = this.route.snapshot.queryParamMap.get('code');
+    if (code) {
+      this.code = code;
+      this.lookup();
+    }
+  }
+
+  lookup(): void {
+    this.run(this.authApi.getDeviceAuthorization(this.code.trim()), (req) => this.request.set(req));
+  }
+
+  approve(): void {
+    this.run(this.authApi.approveDevice(this.request()!.userCode), () => this.state.set('approved'));
+  }
+
+  deny(): void {
+    this.run(this.authApi.denyDevice(this.request()!.userCode), () => this.state.set('denied'));
+  }
+
+  private run(
+    call: ReturnType<AuthApiService['approveDevice']>,
+    done: (req: DeviceAuthorization) => void
+  ): void {
+    this.busy.set(true);
+    this.error.set(null);
+    call.subscribe({
+      next: (req) => {
+        this.busy.set(false);
+        done(req);
+      },
+      error: (err: HttpErrorResponse) => {
+        this.busy.set(false);
+        this.request.set(null);
+        this.error.set(err.error?.message || 'That code was not found or has expired.');
+      },
+    });
+  }
+}
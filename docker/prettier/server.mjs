@@ -0,0 +1,40 @@
+// Prettier sidecar for the API's snippet formatting (PRETTIER_URL).
+// POST /format {"code", "parser"} -> 200 {"code"}, or 400 {"error"} when the code does not parse.
+import http from 'node:http';
+import * as prettier from 'prettier';
+
+const port = Number(process.env.PORT ?? 3001);
+const maxBodyBytes = 1 << 20;
+
+http
+  .createServer(async (req, res) => {
+    const reply = (status, body) => {
+      res.writeHead(status, { 'Content-Type': 'application/json' });
+      res.end(JSON.stringify(body));
+    };
+    if (req.method !== 'POST' || req.url !== '/format') {
+      return reply(404, { error: 'not found' });
+    }
+
+    let body = '';
+    for await (const chunk of req) {
+      body += chunk;
+      if (body.length > maxBodyBytes) {
+        return reply(413, { error: 'code too large' });
+      }
+    }
+
+    let code, parser;
+    try {
+      ({ code, parser } = JSON.parse(body));
+    } catch {
+      return reply(400, { error: 'invalid request body' });
+    }
+    try {
+      reply(200, { code: await prettier.format(code, { parser }) });
+    } catch (err) {
+      // Prettier reports unparseable code as a SyntaxError; anything else is ours
+      reply(err instanceof SyntaxError || err.loc ? 400 : 500, { error: err.message });
+    }
+  })
+  .listen(port, () => console.log(`prettier sidecar listening on :${port}`));
@@ -26,7 +26,7 @@ function resolveWsUrl(): string {
 
 /**
  * Default API configuration using relative URLs
- * - /api/* requests are proxied by SSR server to API_URL
+ * - /api/v1/* requests are proxied by SSR server to API_URL
  * - /ws/* requests are proxied for WebSocket
  * - /grpc/* requests are proxied for gRPC-Web
  */
@@ -3,6 +3,7 @@ import { inject, Injectable } from '@angular/core';
 import { Observable } from 'rxjs';
 import {
   AuthResponse,
+  DeviceAuthorization,
   LoginRequest,
   RegisterRequest,
   User,
@@ -14,7 +15,7 @@ export class AuthApiService {
   private readonly http = inject(HttpClient);
   private readonly config = inject(API_CONFIG, { optional: true }) ?? defaultApiConfig;
 
-  private readonly baseUrl = `${this.config.baseUrl}/api/auth`;
+  private readonly baseUrl = `${this.config.baseUrl}/api/v1/auth`;
 
   register(request: RegisterRequest): Observable<AuthResponse> {
     return this.http.post<AuthResponse>(`${this.baseUrl}/register`, request);
@@ -31,4 +32,16 @@ export class AuthApiService {
   refreshToken(): Observable<AuthResponse> {
     return this.http.post<AuthResponse>(`${this.baseUrl}/refresh`, {});
   }
+
+  getDeviceAuthorization(userCode: string): Observable<DeviceAuthorization> {
+    return this.http.get<DeviceAuthorization>(`${this.baseUrl}/device/${encodeURIComponent(userCode)}`);
+  }
+
+  approveDevice(userCode: string): Observable<DeviceAuthorization> {
+    return this.http.post<DeviceAuthorization>(`${this.baseUrl}/device/approve`, { userCode });
+  }
+
+  denyDevice(userCode: string): Observable<DeviceAuthorization> {
+    return this.http.post<DeviceAuthorization>(`${this.baseUrl}/device/deny`, { userCode });
+  }
 }
@@ -15,7 +15,7 @@ export class JournalApiService {
   private readonly http = inject(HttpClient);
   private readonly config = inject(API_CONFIG, { optional: true }) ?? defaultApiConfig;
 
-  private readonly baseUrl = `${this.config.baseUrl}/api/entries`;
+  private readonly baseUrl = `${this.config.baseUrl}/api/v1/entries`;
 
   list(
     page = 1,
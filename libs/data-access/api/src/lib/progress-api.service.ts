@@ -24,7 +24,7 @@ export class ProgressApiService {
   private readonly http = inject(HttpClient);
   private readonly config = inject(API_CONFIG, { optional: true }) ?? defaultApiConfig;
 
-  private readonly baseUrl = `${this.config.baseUrl}/api/progress`;
+  private readonly baseUrl = `${this.config.baseUrl}/api/v1/progress`;
 
   getSummary(): Observable<ProgressSummary> {
     return this.http.get<ProgressSummary>(`${this.baseUrl}/summary`);
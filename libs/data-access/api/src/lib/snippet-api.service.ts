@@ -15,7 +15,7 @@ export class SnippetApiService {
   private readonly http = inject(HttpClient);
   private readonly config = inject(API_CONFIG, { optional: true }) ?? defaultApiConfig;
 
-  private readonly baseUrl = `${this.config.baseUrl}/api/snippets`;
+  private readonly baseUrl = `${this.config.baseUrl}/api/v1/snippets`;
 
   list(
     page = 1,
@@ -15,7 +15,7 @@ export class StudyGroupApiService {
   private readonly config = inject(API_CONFIG);
 
   private get baseUrl(): string {
-    return `${this.config.baseUrl}/api/groups`;
+    return `${this.config.baseUrl}/api/v1/groups`;
   }
 
   list(): Observable<StudyGroup[]> {
@@ -1,10 +1,12 @@
 import { Injectable, inject, OnDestroy } from '@angular/core';
+import { HttpClient } from '@angular/common/http';
 import { webSocket, WebSocketSubject } from 'rxjs/webSocket';
 import {
   Observable,
   Subject,
   BehaviorSubject,
   timer,
+  defer,
   EMPTY,
 } from 'rxjs';
 import {
@@ -12,6 +14,7 @@ import {
   filter,
   map,
   retry,
+  switchMap,
   takeUntil,
 } from 'rxjs/operators';
 import { ChatMessage } from '@devjournal/shared-models';
@@ -29,10 +32,17 @@ export interface WsMessage {
   roomId?: string;
 }
 
+interface ChatTicket {
+  ticket: string;
+  room: string;
+  expiresAt: string;
+}
+
 const TOKEN_KEY = 'devjournal_token';
 
 @Injectable({ providedIn: 'root' })
 export class WebSocketService implements OnDestroy {
+  private readonly http = inject(HttpClient);
   private readonly config = inject(API_CONFIG);
 
   private socket$: WebSocketSubject<WsMessage> | null = null;
@@ -73,24 +83,29 @@ export class WebSocketService implements OnDestroy {
     } else {
       wsUrl = 'ws://localhost:8080';
     }
-    const url = `${wsUrl}/ws/chat/${roomId}?token=${token}`;
-
-    this.socket$ = webSocket<WsMessage>({
-      url,
-      openObserver: {
-        next: () => {
-          this.connectionStatus$.next('connected');
-        },
-      },
-      closeObserver: {
-        next: () => {
-          this.connectionStatus$.next('disconnected');
-        },
-      },
-    });
-
-    this.socket$
+
+    // Each connection, including reconnects, is opened with a fresh one-time ticket, so the
+    // long-lived token never appears in a WebSocket URL
+    defer(() =>
+      this.http.post<ChatTicket>(`${this.config.baseUrl}/api/v1/ws/ticket`, { room: roomId })
+    )
       .pipe(
+        switchMap(({ ticket }) => {
+          this.socket$ = webSocket<WsMessage>({
+            url: `${wsUrl}/ws/chat/${roomId}?ticket=${encodeURIComponent(ticket)}`,
+            openObserver: {
+              next: () => {
+                this.connectionStatus$.next('connected');
+              },
+            },
+            closeObserver: {
+              next: () => {
+                this.connectionStatus$.next('disconnected');
+              },
+            },
+          });
+          return this.socket$;
+        }),
         takeUntil(this.destroy$),
         retry({
           delay: (error, retryCount) => {
@@ -0,0 +1,3 @@
+export type { paths, components, operations } from './generated/schema';
+export { createApiClient, ApiError } from './lib/api-client';
+export type { ApiClient, ApiClientOptions, Schemas, ErrorBody } from './lib/api-client';
@@ -0,0 +1,143 @@
+import type { components, paths } from '../generated/schema';
+
+/**
+ * Named schemas from the OpenAPI spec, e.g. Schemas['JournalEntry']
+ */
+export type Schemas = components['schemas'];
+export type ErrorBody = Schemas['Error'];
+
+type Method = 'get' | 'post' | 'put' | 'delete';
+type Path = keyof paths;
+
+/** Operation for a path and method; undefined when the path does not support the method */
+type Operation<P extends Path, M extends Method> = M extends keyof paths[P] ? paths[P][M] : undefined;
+
+/** Paths that support the given method */
+type PathsWith<M extends Method> = {
+  [P in Path]: [Operation<P, M>] extends [undefined] ? never : P;
+}[Path];
+
+type JsonContent<T> = T extends { content: { 'application/json': infer Body } } ? Body : never;
+
+type RequestBody<O> = O extends { requestBody?: infer Body } ? JsonContent<NonNullable<Body>> : never;
+
+type Params<O> = O extends { parameters: infer P }
+  ? {
+      [K in keyof P as K extends 'path' | 'query' ? ([P[K]] extends [undefined] ? never : K) : never]: P[K];
+    }
+  : never;
+
+/** Body of the operation's 2xx response; void for responses without content */
+type SuccessBody<O> = O extends { responses: infer R }
+  ? {
+      [S in keyof R]: S extends 200 | 201 | 202 | 204 ? ([JsonContent<R[S]>] extends [never] ? void : JsonContent<R[S]>) : never;
+    }[keyof R]
+  : never;
+
+type RequestInit<O> = ({} extends Params<O> ? { params?: Params<O> } : { params: Params<O> }) &
+  ([RequestBody<O>] extends [never] ? { body?: never } : { body: RequestBody<O> }) & {
+    signal?: AbortSignal;
+  };
+
+/** Init is optional when the operation has no required path params or body */
+type InitArgs<O> = {} extends RequestInit<O> ? [init?: RequestInit<O>] : [init: RequestInit<O>];
+
+export interface ApiClientOptions {
+  /** Base URL including the version prefix, e.g. http://localhost:8080/api/v1 */
+  baseUrl: string;
+  /** Returns the JWT to send, read before every request */
+  token?: () => string | null | undefined;
+  /** fetch implementation; defaults to the global fetch */
+  fetch?: typeof fetch;
+}
+
+/**
+ * Error response from the API, carrying the {code, message, details} envelope
+ */
+export class ApiError extends Error {
+  constructor(
+    readonly status: number,
+    readonly code: string,
+    message: string,
+    readonly details?: Record<string, unknown>,
+  ) {
+    super(message);
+    this.name = 'ApiError';
+  }
+}
+
+type Requester<M extends Method> = <P extends PathsWith<M>>(
+  path: P,
+  ...init: InitArgs<Operation<P, M>>
+) => Promise<SuccessBody<Operation<P, M>>>;
+
+export interface ApiClient {
+  GET: Requester<'get'>;
+  POST: Requester<'post'>;
+  PUT: Requester<'put'>;
+  DELETE: Requester<'delete'>;
+}
+
+interface UntypedInit {
+  params?: { path?: Record<string, unknown>; query?: Record<string, unknown> };
+  body?: unknown;
+  signal?: AbortSignal;
+}
+
+/**
+ * Creates a client whose paths, params, bodies, and responses are typed from openapi.yaml
+ */
+export function createApiClient(options: ApiClientOptions): ApiClient {
+  const baseUrl = options.baseUrl.replace(/\/+$/, '');
+  const doFetch = options.fetch ?? ((input, init) => fetch(input, init));
+
+  async function request(method: string, path: string, init: UntypedInit = {}): Promise<unknown> {
+    const pathParams = init.params?.path ?? {};
+    let url = baseUrl + path.replace(/\{(\w+)\}/g, (_, name: string) => encodeURIComponent(String(pathParams[name])));
+
+    const query = new URLSearchParams();
+    for (const [key, value] of Object.entries(init.params?.query ?? {})) {
+      if (value === undefined || value === null || value === '') continue;
+      query.set(key, Array.isArray(value) ? value.join(',') : String(value));
+    }
+    if (query.size > 0) url += `?${query}`;
+
+    const headers = new Headers({ Accept: 'application/json' });
+    const token = options.token?.();
+    if (token) headers.set('Authorization', `Bearer ${token}`);
+    if (init.body !== undefined) headers.set('Content-Type', 'application/json');
+
+    const response = await doFetch(url, {
+      method,
+      headers,
+      body: init.body === undefined ? undefined : JSON.stringify(init.body),
+      signal: init.signal,
+    });
+
+    const text = await response.text();
+    const isJson = response.headers.get('Content-Type')?.includes('application/json') ?? false;
+    const payload: unknown = text && isJson ? JSON.parse(text) : undefined;
+    if (!response.ok) {
+      const envelope = (payload ?? {}) as Partial<ErrorBody>;
+      throw new ApiError(
+        response.status,
+        envelope.code ?? 'UNKNOWN',
+        envelope.message ?? response.statusText,
+        envelope.details,
+      );
+    }
+    return payload;
+  }
+
+  const requester =
+    (method: string) =>
+    (path: string, init?: UntypedInit): Promise<unknown> =>
+      request(method, path, init);
+
+  return {
+    GET: requester('GET'),
+    POST: requester('POST'),
+    PUT: requester('PUT'),
+    DELETE: requester('DELETE'),
+  } as ApiClient;
+}
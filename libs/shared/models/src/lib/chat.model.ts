@@ -31,6 +31,7 @@ export interface ChatMessage {
   content: string;
   timestamp: Date;
   type: ChatMessageType;
+  source?: 'slack' | 'discord'; // set when relayed from a connected channel
 }
 
 export type ChatMessageType = 'message' | 'join' | 'leave' | 'system';
@@ -30,3 +30,15 @@ export interface AuthState {
   isLoading: boolean;
   error: string | null;
 }
+
+export type DeviceAuthorizationStatus = 'pending' | 'approved' | 'denied' | 'consumed';
+
+// A sign-in requested by an editor plugin, waiting for the user to approve it
+export interface DeviceAuthorization {
+  id: string;
+  userCode: string;
+  clientName: string;
+  status: DeviceAuthorizationStatus;
+  expiresAt: Date;
+  createdAt: Date;
+}
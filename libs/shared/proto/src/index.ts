@@ -79,8 +79,8 @@ export {
   GetStreakRequestSchema,
 } from './lib/devjournal/v1/progress_pb';
 
-// Connect RPC clients (services)
-export { JournalService } from './lib/devjournal/v1/journal_connect';
-export { SnippetService } from './lib/devjournal/v1/snippet_connect';
-export { AuthService } from './lib/devjournal/v1/user_connect';
-export { ProgressService } from './lib/devjournal/v1/progress_connect';
+// Connect RPC service descriptors, for createClient from @connectrpc/connect
+export { JournalService } from './lib/devjournal/v1/journal_pb';
+export { SnippetService } from './lib/devjournal/v1/snippet_pb';
+export { AuthService } from './lib/devjournal/v1/user_pb';
+export { ProgressService } from './lib/devjournal/v1/progress_pb';
@@ -14,15 +14,26 @@ import (
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
+	"devjournal/internal/backup"
 	"devjournal/internal/config"
 	"devjournal/internal/database"
+	"devjournal/internal/domain"
+	"devjournal/internal/errreport"
+	"devjournal/internal/flags"
+	"devjournal/internal/formatter"
 	grpcHandler "devjournal/internal/handler/grpc"
 	"devjournal/internal/handler/rest"
 	"devjournal/internal/handler/websocket"
+	"devjournal/internal/i18n"
+	"devjournal/internal/jobs"
+	"devjournal/internal/mail"
 	"devjournal/internal/middleware"
+	"devjournal/internal/playground"
+	"devjournal/internal/push"
 	"devjournal/internal/repository/mongodb"
 	"devjournal/internal/repository/postgres"
 	"devjournal/internal/service"
+	"devjournal/internal/sitepublish"
 	"devjournal/proto/devjournal/v1/devjournalv1connect"
 )
 
@@ -30,6 +41,18 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	featureFlags, err := flags.Load(cfg.FeatureFlagsFile)
+	if err != nil {
+		log.Fatalf("Failed to load feature flags: %v", err)
+	}
+	flags.Use(featureFlags)
+
+	catalog, err := i18n.Load(cfg.LocalesDir)
+	if err != nil {
+		log.Fatalf("Failed to load message catalogs: %v", err)
+	}
+	i18n.Use(catalog)
+
 	// Initialize database connections
 	ctx := context.Background()
 
@@ -39,6 +62,18 @@ func main() {
 	}
 	defer pgPool.Close()
 
+	// Refuse to run against a schema this build doesn't support, e.g. mid rolling deploy
+	if err := database.CheckSchema(ctx, pgPool); err != nil {
+		log.Fatalf("Failed to check PostgreSQL schema: %v", err)
+	}
+
+	// Reads that tolerate replication lag go to the nearest of the primary and its replicas
+	pgNearest, err := database.NewNearestPool(ctx, pgPool, cfg.DbReadURLs)
+	if err != nil {
+		log.Fatalf("Failed to connect to PostgreSQL read replicas: %v", err)
+	}
+	defer pgNearest.Close()
+
 	mongoClient, err := database.NewMongoClient(ctx, cfg.MongoURL)
 	if err != nil {
 		log.Fatalf("Failed to connect to MongoDB: %v", err)
@@ -50,20 +85,241 @@ func main() {
 	journalRepo := postgres.NewJournalRepository(pgPool)
 	progressRepo := postgres.NewProgressRepository(pgPool)
 	studyGroupRepo := postgres.NewStudyGroupRepository(pgPool)
+	moderationRepo := postgres.NewModerationRepository(pgPool)
+	settingsRepo := postgres.NewSettingsRepository(pgPool)
+	reviewRepo := postgres.NewReviewRepository(pgPool)
+	tilRepo := postgres.NewTILRepository(pgPool)
+	workspaceRepo := postgres.NewWorkspaceRepository(pgPool)
+	orgRepo := postgres.NewOrganizationRepository(pgPool)
+	subscriptionRepo := postgres.NewSubscriptionRepository(pgPool)
+	deviceAuthRepo := postgres.NewDeviceAuthRepository(pgPool)
+	integrationRepo := postgres.NewIntegrationRepository(pgPool)
+	calendarFeedRepo := postgres.NewCalendarFeedRepository(pgPool)
+	pushRepo := postgres.NewPushRepository(pgPool)
+	mentionRepo := postgres.NewMentionRepository(pgPool)
+	followRepo := postgres.NewFollowRepository(pgPool)
+	entrySnippetRepo := postgres.NewEntrySnippetRepository(pgPool).ReadNearest(pgNearest)
+	projectRepo := postgres.NewProjectRepository(pgPool)
+	learningPathRepo := postgres.NewLearningPathRepository(pgPool)
+	snippetCommentRepo := postgres.NewSnippetCommentRepository(pgPool)
+	identityRepo := postgres.NewIdentityRepository(pgPool)
+	impersonationRepo := postgres.NewImpersonationRepository(pgPool)
+	refreshTokenRepo := postgres.NewRefreshTokenRepository(pgPool)
 	snippetRepo := mongodb.NewSnippetRepository(mongoClient, cfg.MongoDB)
+	switch cfg.SnippetReadPreference {
+	case "nearest":
+		snippetRepo.ReadNearest(cfg.SnippetMaxStaleness)
+	case "primary":
+	default:
+		log.Fatalf("Unknown SNIPPET_READ_PREFERENCE %q, expected primary or nearest", cfg.SnippetReadPreference)
+	}
+	quizRepo := mongodb.NewQuizRepository(mongoClient, cfg.MongoDB)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, cfg.JWTSecret)
-	journalService := service.NewJournalService(journalRepo)
-	snippetService := service.NewSnippetService(snippetRepo)
+	authService := service.NewAuthService(userRepo, workspaceRepo, cfg.JWTSecret)
+	authService.UseImpersonations(impersonationRepo)
+	authService.UseRefreshTokens(refreshTokenRepo)
+	switch cfg.AuthBackend {
+	case "ldap":
+		ldapService := service.NewLDAPService(identityRepo, userRepo, authService, service.LDAPConfig{
+			URL:            cfg.LDAPURL,
+			StartTLS:       cfg.LDAPStartTLS,
+			BindDN:         cfg.LDAPBindDN,
+			BindPassword:   cfg.LDAPBindPassword,
+			SearchBase:     cfg.LDAPSearchBase,
+			UserFilter:     cfg.LDAPUserFilter,
+			EmailAttribute: cfg.LDAPEmailAttribute,
+			NameAttribute:  cfg.LDAPNameAttribute,
+		})
+		if !ldapService.Configured() {
+			log.Fatalf("AUTH_BACKEND=ldap needs LDAP_URL and LDAP_SEARCH_BASE")
+		}
+		authService.UseDirectory(ldapService)
+		log.Printf("Passwords are checked against LDAP at %s", cfg.LDAPURL)
+	case "local":
+	default:
+		log.Fatalf("Unknown AUTH_BACKEND %q, expected local or ldap", cfg.AuthBackend)
+	}
+	quotaService := service.NewQuotaService(subscriptionRepo, workspaceRepo, snippetRepo, cfg.StripeSecretKey != "")
+	billingService := service.NewBillingService(subscriptionRepo, userRepo, service.BillingConfig{
+		SecretKey:     cfg.StripeSecretKey,
+		WebhookSecret: cfg.StripeWebhookSecret,
+		PriceIDs:      map[string]string{domain.PlanPro: cfg.StripePricePro, domain.PlanTeam: cfg.StripePriceTeam},
+		SuccessURL:    cfg.BillingSuccessURL,
+		CancelURL:     cfg.BillingCancelURL,
+	})
+	snippetService := service.NewSnippetService(snippetRepo, entrySnippetRepo, snippetCommentRepo, quotaService, formatter.New(cfg.PrettierURL))
 	progressService := service.NewProgressService(progressRepo)
 	studyGroupService := service.NewStudyGroupService(studyGroupRepo)
-	// Initialize WebSocket hub
-	hub := websocket.NewHub()
+	moderationService := service.NewModerationService(moderationRepo, studyGroupRepo, snippetRepo)
+	settingsService := service.NewSettingsService(settingsRepo)
+	reviewService := service.NewReviewService(reviewRepo, journalRepo, snippetRepo)
+	yearlyReviewService := service.NewYearlyReviewService(postgres.NewYearlyReviewRepository(pgPool), snippetRepo)
+	tilService := service.NewTILService(tilRepo)
+	problemService := service.NewProblemService(postgres.NewProblemRepository(pgPool), snippetRepo)
+	workspaceService := service.NewWorkspaceService(workspaceRepo, userRepo, authService, quotaService)
+	orgService := service.NewOrganizationService(orgRepo, progressRepo, workspaceService, quotaService)
+	deviceAuthService := service.NewDeviceAuthService(deviceAuthRepo, authService, cfg.DeviceVerificationURL)
+	calendarService := service.NewCalendarService(calendarFeedRepo, progressRepo, reviewRepo, cfg.CalendarFeedURL)
+
+	// Push providers are enabled by their credentials
+	pushSenders := map[string]push.Sender{}
+	if cfg.FCMCredentials != "" {
+		fcm, err := push.NewFCM([]byte(cfg.FCMCredentials))
+		if err != nil {
+			log.Fatalf("Failed to configure FCM: %v", err)
+		}
+		pushSenders[domain.PushPlatformAndroid] = fcm
+	}
+	if cfg.APNsKey != "" {
+		apns, err := push.NewAPNs(push.APNsConfig{
+			KeyPEM:  []byte(cfg.APNsKey),
+			KeyID:   cfg.APNsKeyID,
+			TeamID:  cfg.APNsTeamID,
+			Topic:   cfg.APNsTopic,
+			Sandbox: cfg.APNsSandbox,
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure APNs: %v", err)
+		}
+		pushSenders[domain.PushPlatformIOS] = apns
+	}
+	pushService := service.NewPushService(pushRepo, settingsService, pushSenders)
+
+	// Mention emails go to users without a mobile device registered
+	var mailer mail.Sender
+	if cfg.SMTPURL != "" {
+		smtpMailer, err := mail.NewSMTP(cfg.SMTPURL, cfg.MailFrom)
+		if err != nil {
+			log.Fatalf("Failed to configure SMTP: %v", err)
+		}
+		mailer = smtpMailer
+	}
+
+	// Panics and WebSocket errors are reported to Sentry when a DSN is set
+	var reporter errreport.Reporter
+	var sentry *errreport.Sentry
+	if cfg.SentryDSN != "" {
+		var err error
+		if sentry, err = errreport.NewSentry(cfg.SentryDSN, cfg.SentryEnvironment); err != nil {
+			log.Fatalf("Failed to configure Sentry: %v", err)
+		}
+		reporter = sentry
+	}
+	mentionService := service.NewMentionService(mentionRepo, studyGroupRepo, workspaceRepo, userRepo, pushService, mailer)
+	journalService := service.NewJournalService(journalRepo, mentionService)
+	groupPermissionService := service.NewGroupPermissionService(postgres.NewGroupPermissionRepository(pgPool), studyGroupRepo)
+	codeReviewService := service.NewCodeReviewService(postgres.NewCodeReviewRepository(pgPool), studyGroupRepo, snippetRepo, userRepo, groupPermissionService, pushService, mailer)
+	socialService := service.NewSocialService(followRepo, userRepo, journalRepo, snippetRepo)
+	embedService := service.NewEmbedService(snippetRepo, userRepo, cfg.EmbedURL, cfg.SnippetPageURL)
+	seoService := service.NewSEOService(snippetRepo, journalRepo, userRepo, followRepo, progressRepo, cfg.SnippetPageURL, cfg.ProfilePageURL, cfg.EmbedURL)
+	entrySnippetService := service.NewEntrySnippetService(entrySnippetRepo, journalRepo, snippetRepo)
+	snippetCommentService := service.NewSnippetCommentService(snippetCommentRepo, snippetRepo, userRepo)
+	projectService := service.NewProjectService(projectRepo, journalRepo, snippetRepo)
+	learningPathService := service.NewLearningPathService(learningPathRepo, studyGroupRepo, journalRepo, snippetRepo)
+	importService := service.NewImportService(postgres.NewImportRepository(pgPool), journalRepo)
+	gitActivityService := service.NewGitActivityService(postgres.NewGitActivityRepository(pgPool), journalService, cfg.GitWebhookURL)
+	codingService := service.NewCodingService(postgres.NewCodingRepository(pgPool), progressService, cfg.WakaTimeAPIURL, cfg.CodingAPIURL)
+	bookmarkService := service.NewBookmarkService(postgres.NewBookmarkRepository(pgPool))
+	captureService := service.NewCaptureService(postgres.NewCaptureRepository(pgPool), journalService, snippetService, bookmarkService)
+	sitePublishService := service.NewSitePublishService(postgres.NewSiteRepository(pgPool), journalRepo, sitepublish.NewGitHub(cfg.GitHubAPIURL))
+	journalService.OnPublish(sitePublishService.EntryPublished)
+	playgroundService := service.NewPlaygroundService(snippetRepo, playground.New(playground.Playgrounds{
+		GoURL:         cfg.GoPlaygroundURL,
+		RustURL:       cfg.RustPlaygroundURL,
+		TypeScriptURL: cfg.TypeScriptPlaygroundURL,
+	}))
+	groupChannelService := service.NewGroupChannelService(postgres.NewGroupChannelRepository(pgPool), studyGroupRepo)
+	groupArchiveService := service.NewGroupArchiveService(studyGroupRepo, userRepo, pushService, mailer,
+		time.Duration(cfg.GroupArchiveAfterDays)*24*time.Hour, time.Duration(cfg.GroupArchiveWarningDays)*24*time.Hour)
+	chatTicketService := service.NewChatTicketService(postgres.NewChatTicketRepository(pgPool), groupChannelService, groupPermissionService)
+	entryTemplateService := service.NewEntryTemplateService(postgres.NewEntryTemplateRepository(pgPool), studyGroupRepo, groupPermissionService, journalService)
+
+	// Initialize WebSocket hub with the chat moderation pipeline
+	chatFilters := websocket.NewFilterPipeline(
+		websocket.MaxLengthFilter(cfg.ChatMaxMessageLength),
+		websocket.RateLimitFilter(cfg.ChatRateLimit, time.Minute),
+		websocket.GroupRulesFilter(moderationService, 30*time.Second),
+	)
+	hub := websocket.NewHub(chatFilters)
+	hub.ReportErrors(reporter)
+
+	// Mirror group chat to connected Slack/Discord channels
+	integrationService := service.NewIntegrationService(integrationRepo, studyGroupRepo, hub, service.IntegrationConfig{
+		SlackClientID:       cfg.SlackClientID,
+		SlackClientSecret:   cfg.SlackClientSecret,
+		SlackSigningSecret:  cfg.SlackSigningSecret,
+		DiscordClientID:     cfg.DiscordClientID,
+		DiscordClientSecret: cfg.DiscordClientSecret,
+		DiscordPublicKey:    cfg.DiscordPublicKey,
+		CallbackURL:         cfg.IntegrationCallbackURL,
+		ReturnURL:           cfg.IntegrationReturnURL,
+		StateSecret:         cfg.JWTSecret,
+	})
+	quizService := service.NewQuizService(quizRepo, postgres.NewQuizAttemptRepository(pgPool), studyGroupRepo, userRepo, hub)
+	pollService := service.NewPollService(postgres.NewPollRepository(pgPool), studyGroupRepo, userRepo, groupChannelService, hub)
+	announcementService := service.NewAnnouncementService(postgres.NewAnnouncementRepository(pgPool), hub, mailer)
+	inviteService := service.NewInviteService(postgres.NewInviteRepository(pgPool))
+	ssoService := service.NewSSOService(identityRepo, userRepo, authService, service.SSOConfig{
+		IssuerURL:     cfg.OIDCIssuerURL,
+		ClientID:      cfg.OIDCClientID,
+		ClientSecret:  cfg.OIDCClientSecret,
+		CallbackURL:   cfg.OIDCCallbackURL,
+		ReturnURL:     cfg.OIDCReturnURL,
+		AutoProvision: cfg.OIDCAutoProvision,
+		LinkByEmail:   cfg.OIDCLinkByEmail,
+		StateSecret:   cfg.JWTSecret,
+	})
+	scimService := service.NewSCIMService(userRepo, identityRepo, authService)
+	impersonationService := service.NewImpersonationService(impersonationRepo, userRepo, authService, pushService, mailer)
+	backupService := service.NewBackupService(backup.NewDumper(pgPool, mongoClient.Database(cfg.MongoDB)), cfg.BackupDir)
+	celebrationService := service.NewCelebrationService(studyGroupRepo, userRepo, settingsService, hub)
+	progressService.OnMilestone(pushService.StreakMilestone)
+	progressService.OnMilestone(celebrationService.StreakMilestone)
+	hub.OnMessage(integrationService.Mirror)
+	hub.OnMessage(pushService.OnChatMessage)
+	hub.OnMessage(mentionService.OnChatMessage)
+	hub.OnMessage(groupArchiveService.OnChatMessage)
+	hub.LimitConnections(cfg.ChatMaxConnsPerUser, cfg.ChatMaxConnsPerRoom)
+	hub.LimitMessageRate(cfg.ChatMessageRate, cfg.ChatMessageBurst)
 	go hub.Run()
 
+	// Start background jobs
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	go jobs.Every(jobsCtx, "trending-snippets", cfg.TrendingRefreshInterval,
+		snippetService.TrendingRefresher(cfg.TrendingRefreshInterval, cfg.TrendingHalfLife))
+	if cfg.FeatureFlagsFile != "" {
+		go jobs.Every(jobsCtx, "feature-flags", cfg.FeatureFlagsPollInterval, featureFlags.Poller())
+	}
+	go featureFlags.ReloadOnSIGHUP(jobsCtx)
+	if len(cfg.DbReadURLs) > 0 {
+		go jobs.Every(jobsCtx, "postgres-nearest", 30*time.Second, pgNearest.Measurer())
+	}
+	go jobs.Every(jobsCtx, "device-authorizations", time.Hour, deviceAuthService.ExpiredCleaner())
+	go jobs.Every(jobsCtx, "chat-tickets", time.Hour, chatTicketService.ExpiredCleaner())
+	go jobs.Every(jobsCtx, "refresh-tokens", time.Hour, authService.RefreshTokenCleaner())
+	go jobs.Every(jobsCtx, "group-invites", time.Hour, studyGroupService.ExpiredInviteCleaner())
+	go jobs.Every(jobsCtx, "integration-deliveries", time.Hour, integrationService.DeliveryCleaner())
+	go integrationService.Run(jobsCtx)
+	go jobs.Every(jobsCtx, "group-activity", time.Minute, groupArchiveService.ActivityRecorder())
+	go jobs.Every(jobsCtx, "group-archival", time.Hour, groupArchiveService.Archiver())
+	go jobs.Every(jobsCtx, "announcements", time.Minute, announcementService.Publisher())
+	go jobs.Every(jobsCtx, "yearly-reviews", time.Hour, yearlyReviewService.Refresher())
+	go jobs.Every(jobsCtx, "streak-reminders", 15*time.Minute, pushService.StreakReminder(cfg.PushStreakReminderHour))
+	go pushService.Run(jobsCtx)
+	go celebrationService.Run(jobsCtx)
+	go mentionService.Run(jobsCtx)
+	go codeReviewService.Run(jobsCtx)
+	go importService.Run(jobsCtx)
+	go codingService.Run(jobsCtx)
+	go sitePublishService.Run(jobsCtx)
+	if sentry != nil {
+		go sentry.Run(jobsCtx)
+	}
+
 	// Setup HTTP router
-	router := setupHTTPRouter(cfg, authService, journalService, snippetService, studyGroupService, progressService, hub)
+	router := setupHTTPRouter(cfg, authService, journalService, snippetService, studyGroupService, progressService, moderationService, settingsService, reviewService, tilService, workspaceService, orgService, billingService, deviceAuthService, integrationService, calendarService, pushService, mentionService, socialService, embedService, entrySnippetService, projectService, learningPathService, quizService, codeReviewService, snippetCommentService, seoService, importService, gitActivityService, codingService, problemService, captureService, bookmarkService, sitePublishService, playgroundService, groupChannelService, chatTicketService, entryTemplateService, pollService, groupPermissionService, yearlyReviewService, announcementService, backupService, inviteService, ssoService, scimService, impersonationService, hub, reporter)
 
 	// Create HTTP server
 	httpServer := &http.Server{
@@ -84,33 +340,7 @@ func main() {
 
 	// Start Connect RPC server (gRPC-Web compatible)
 	go func() {
-		// Create Connect RPC handlers
-		journalConnectHandler := grpcHandler.NewJournalConnectHandler(journalService)
-		snippetConnectHandler := grpcHandler.NewSnippetConnectHandler(snippetService)
-
-		// Create auth interceptor
-		authInterceptor := grpcHandler.AuthInterceptor(authService)
-		interceptors := connect.WithInterceptors(authInterceptor)
-
-		// Create mux for Connect RPC
-		mux := http.NewServeMux()
-
-		// Register Journal service
-		journalPath, journalHandler := devjournalv1connect.NewJournalServiceHandler(
-			journalConnectHandler,
-			interceptors,
-		)
-		mux.Handle(journalPath, journalHandler)
-
-		// Register Snippet service
-		snippetPath, snippetHandler := devjournalv1connect.NewSnippetServiceHandler(
-			snippetConnectHandler,
-			interceptors,
-		)
-		mux.Handle(snippetPath, snippetHandler)
-
-		// Apply CORS for gRPC-Web
-		handler := middleware.CORS(mux)
+		handler := setupConnectRouter(authService, journalService, snippetService, settingsService, reporter)
 
 		// Create server with h2c for HTTP/2 without TLS (for development)
 		connectServer := &http.Server{
@@ -130,6 +360,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down servers...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -141,6 +372,48 @@ func main() {
 	log.Println("Servers stopped gracefully")
 }
 
+func setupConnectRouter(
+	authService *service.AuthService,
+	journalService *service.JournalService,
+	snippetService *service.SnippetService,
+	settingsService *service.SettingsService,
+	reporter errreport.Reporter,
+) http.Handler {
+	// Create Connect RPC handlers
+	journalConnectHandler := grpcHandler.NewJournalConnectHandler(journalService, settingsService)
+	snippetConnectHandler := grpcHandler.NewSnippetConnectHandler(snippetService, settingsService)
+
+	// Interceptors mirror the REST middleware stack, with logging outside auth so rejected
+	// calls are logged too
+	authInterceptor := grpcHandler.AuthInterceptor(authService)
+	interceptors := connect.WithHandlerOptions(
+		connect.WithInterceptors(grpcHandler.LoggingInterceptor(), authInterceptor),
+		// Inside the auth interceptor, so panics are reported with the user
+		connect.WithRecover(grpcHandler.RecoverHandler(reporter)),
+	)
+
+	// Create mux for Connect RPC
+	mux := http.NewServeMux()
+
+	// Register Journal service
+	journalPath, journalHandler := devjournalv1connect.NewJournalServiceHandler(
+		journalConnectHandler,
+		interceptors,
+	)
+	mux.Handle(journalPath, journalHandler)
+
+	// Register Snippet service
+	snippetPath, snippetHandler := devjournalv1connect.NewSnippetServiceHandler(
+		snippetConnectHandler,
+		interceptors,
+	)
+	mux.Handle(snippetPath, snippetHandler)
+
+	// The same edge as the REST API: request IDs, recovery from panics in the interceptors
+	// themselves (outside connect.WithRecover), and CORS preflights answered before auth
+	return middleware.Chain(middleware.Edge(reporter)...)(mux)
+}
+
 func setupHTTPRouter(
 	cfg *config.Config,
 	authService *service.AuthService,
@@ -148,7 +421,49 @@ func setupHTTPRouter(
 	snippetService *service.SnippetService,
 	studyGroupService *service.StudyGroupService,
 	progressService *service.ProgressService,
+	moderationService *service.ModerationService,
+	settingsService *service.SettingsService,
+	reviewService *service.ReviewService,
+	tilService *service.TILService,
+	workspaceService *service.WorkspaceService,
+	orgService *service.OrganizationService,
+	billingService *service.BillingService,
+	deviceAuthService *service.DeviceAuthService,
+	integrationService *service.IntegrationService,
+	calendarService *service.CalendarService,
+	pushService *service.PushService,
+	mentionService *service.MentionService,
+	socialService *service.SocialService,
+	embedService *service.EmbedService,
+	entrySnippetService *service.EntrySnippetService,
+	projectService *service.ProjectService,
+	learningPathService *service.LearningPathService,
+	quizService *service.QuizService,
+	codeReviewService *service.CodeReviewService,
+	snippetCommentService *service.SnippetCommentService,
+	seoService *service.SEOService,
+	importService *service.ImportService,
+	gitActivityService *service.GitActivityService,
+	codingService *service.CodingService,
+	problemService *service.ProblemService,
+	captureService *service.CaptureService,
+	bookmarkService *service.BookmarkService,
+	sitePublishService *service.SitePublishService,
+	playgroundService *service.PlaygroundService,
+	groupChannelService *service.GroupChannelService,
+	chatTicketService *service.ChatTicketService,
+	entryTemplateService *service.EntryTemplateService,
+	pollService *service.PollService,
+	groupPermissionService *service.GroupPermissionService,
+	yearlyReviewService *service.YearlyReviewService,
+	announcementService *service.AnnouncementService,
+	backupService *service.BackupService,
+	inviteService *service.InviteService,
+	ssoService *service.SSOService,
+	scimService *service.SCIMService,
+	impersonationService *service.ImpersonationService,
 	hub *websocket.Hub,
+	reporter errreport.Reporter,
 ) http.Handler {
 	mux := http.NewServeMux()
 
@@ -159,57 +474,408 @@ func setupHTTPRouter(
 		w.Write([]byte(`{"status":"ok"}`))
 	})
 
+	// Feature flags (public, so clients can hide disabled features)
+	mux.HandleFunc("GET /api/flags", rest.ListFlags)
+
 	// Auth handlers (public routes)
-	authHandler := rest.NewAuthHandler(authService)
+	authHandler := rest.NewAuthHandler(authService, inviteService)
 	mux.HandleFunc("POST /api/auth/register", authHandler.Register)
 	mux.HandleFunc("POST /api/auth/login", authHandler.Login)
+	mux.HandleFunc("POST /api/auth/refresh", authHandler.Refresh)
+
+	// Single sign-on through an OpenID Connect provider (the callback is verified by state)
+	ssoHandler := rest.NewSSOHandler(ssoService)
+	mux.HandleFunc("GET /api/auth/oidc", ssoHandler.Status)
+	mux.HandleFunc("GET /api/auth/oidc/login", ssoHandler.Login)
+	mux.HandleFunc("GET /api/auth/oidc/callback", ssoHandler.Callback)
+
+	// SCIM 2.0 provisioning for identity providers, authenticated with SCIM_TOKEN
+	scimHandler := rest.NewSCIMHandler(scimService, cfg.SCIMToken)
+	mux.HandleFunc("GET /scim/v2/ServiceProviderConfig", scimHandler.Authenticate(scimHandler.ServiceProviderConfig))
+	mux.HandleFunc("GET /scim/v2/Users", scimHandler.Authenticate(scimHandler.List))
+	mux.HandleFunc("POST /scim/v2/Users", scimHandler.Authenticate(scimHandler.Create))
+	mux.HandleFunc("GET /scim/v2/Users/{id}", scimHandler.Authenticate(scimHandler.Get))
+	mux.HandleFunc("PUT /scim/v2/Users/{id}", scimHandler.Authenticate(scimHandler.Replace))
+	mux.HandleFunc("PATCH /scim/v2/Users/{id}", scimHandler.Authenticate(scimHandler.Patch))
+	mux.HandleFunc("DELETE /scim/v2/Users/{id}", scimHandler.Authenticate(scimHandler.Delete))
+
+	// Device sign-in for editor plugins (start and poll are public)
+	deviceAuthHandler := rest.NewDeviceAuthHandler(deviceAuthService)
+	mux.HandleFunc("POST /api/auth/device/code", deviceAuthHandler.Start)
+	mux.HandleFunc("POST /api/auth/device/token", deviceAuthHandler.Token)
 
 	// Protected routes with auth middleware
 	authMiddleware := middleware.AuthMiddleware(authService)
+	// Public content that signed-out guests can read when GUEST_ACCESS is enabled
+	guestOrAuth := middleware.GuestOrAuth(authService, cfg.GuestAccess)
+	// Routes that read or edit journal entries, where a vault session unlocks vault entries
+	vaultSession := middleware.VaultSession(authService)
+	entryAuth := func(next http.Handler) http.Handler { return authMiddleware(vaultSession(next)) }
+
+	// Device sign-in approval from the web app
+	mux.Handle("GET /api/auth/device/{userCode}", authMiddleware(http.HandlerFunc(deviceAuthHandler.Get)))
+	mux.Handle("POST /api/auth/device/approve", authMiddleware(http.HandlerFunc(deviceAuthHandler.Approve)))
+	mux.Handle("POST /api/auth/device/deny", authMiddleware(http.HandlerFunc(deviceAuthHandler.Deny)))
+
+	// Step-up verification that opens a vault session
+	mux.Handle("POST /api/auth/vault", authMiddleware(http.HandlerFunc(authHandler.OpenVault)))
+
+	// User settings handlers
+	settingsHandler := rest.NewSettingsHandler(settingsService)
+	mux.Handle("GET /api/users/me/settings", authMiddleware(http.HandlerFunc(settingsHandler.Get)))
+	mux.Handle("PUT /api/users/me/settings", authMiddleware(http.HandlerFunc(settingsHandler.Update)))
+
+	// Calendar feed (the .ics is public and authenticated by its token, for calendar apps)
+	calendarHandler := rest.NewCalendarHandler(calendarService)
+	mux.HandleFunc("GET /api/users/me/calendar.ics", calendarHandler.Feed)
+	mux.Handle("GET /api/users/me/calendar", authMiddleware(http.HandlerFunc(calendarHandler.Get)))
+	mux.Handle("POST /api/users/me/calendar/token", authMiddleware(http.HandlerFunc(calendarHandler.Rotate)))
+	mux.Handle("DELETE /api/users/me/calendar/token", authMiddleware(http.HandlerFunc(calendarHandler.Revoke)))
+
+	// Mobile push notification devices
+	pushHandler := rest.NewPushHandler(pushService)
+	mux.Handle("GET /api/users/me/push-devices", authMiddleware(http.HandlerFunc(pushHandler.List)))
+	mux.Handle("POST /api/users/me/push-devices", authMiddleware(http.HandlerFunc(pushHandler.Register)))
+	mux.Handle("DELETE /api/users/me/push-devices/{id}", authMiddleware(http.HandlerFunc(pushHandler.Unregister)))
+
+	// Where the user was @mentioned
+	mentionHandler := rest.NewMentionHandler(mentionService, settingsService)
+	mux.Handle("GET /api/mentions", authMiddleware(http.HandlerFunc(mentionHandler.List)))
+	mux.Handle("POST /api/mentions/read", authMiddleware(http.HandlerFunc(mentionHandler.MarkRead)))
+
+	// System announcements in the user's notification center
+	announcementHandler := rest.NewAnnouncementHandler(announcementService, settingsService)
+	mux.Handle("GET /api/announcements", authMiddleware(http.HandlerFunc(announcementHandler.List)))
+	mux.Handle("POST /api/announcements/read", authMiddleware(http.HandlerFunc(announcementHandler.MarkRead)))
+
+	// Public profiles, follows, and the feed of followed users' public work
+	socialHandler := rest.NewSocialHandler(socialService, settingsService)
+	mux.Handle("GET /api/feed", authMiddleware(http.HandlerFunc(socialHandler.Feed)))
+	mux.Handle("GET /api/users/{userId}", guestOrAuth(http.HandlerFunc(socialHandler.Profile)))
+	mux.Handle("GET /api/users/{userId}/activity", guestOrAuth(http.HandlerFunc(socialHandler.Activity)))
+	mux.Handle("PUT /api/users/{userId}/follow", authMiddleware(http.HandlerFunc(socialHandler.Follow)))
+	mux.Handle("DELETE /api/users/{userId}/follow", authMiddleware(http.HandlerFunc(socialHandler.Unfollow)))
+	mux.Handle("GET /api/users/{userId}/followers", guestOrAuth(http.HandlerFunc(socialHandler.Followers)))
+	mux.Handle("GET /api/users/{userId}/following", guestOrAuth(http.HandlerFunc(socialHandler.Following)))
+
+	// Workspace handlers (protected)
+	workspaceHandler := rest.NewWorkspaceHandler(workspaceService)
+	mux.Handle("GET /api/workspaces", authMiddleware(http.HandlerFunc(workspaceHandler.List)))
+	mux.Handle("POST /api/workspaces", authMiddleware(http.HandlerFunc(workspaceHandler.Create)))
+	mux.Handle("POST /api/workspaces/{id}/switch", authMiddleware(http.HandlerFunc(workspaceHandler.Switch)))
+	mux.Handle("GET /api/workspaces/{id}/members", authMiddleware(http.HandlerFunc(workspaceHandler.ListMembers)))
+	mux.Handle("POST /api/workspaces/{id}/members", authMiddleware(http.HandlerFunc(workspaceHandler.AddMember)))
+	mux.Handle("PUT /api/workspaces/{id}/members/{userId}", authMiddleware(http.HandlerFunc(workspaceHandler.UpdateMemberRole)))
+	mux.Handle("DELETE /api/workspaces/{id}/members/{userId}", authMiddleware(http.HandlerFunc(workspaceHandler.RemoveMember)))
+
+	// Organization handlers (protected); an organization's members are its workspace's members
+	orgHandler := rest.NewOrganizationHandler(orgService)
+	mux.Handle("GET /api/orgs", authMiddleware(http.HandlerFunc(orgHandler.List)))
+	mux.Handle("POST /api/orgs", authMiddleware(http.HandlerFunc(orgHandler.Create)))
+	mux.Handle("GET /api/orgs/{id}", authMiddleware(http.HandlerFunc(orgHandler.Get)))
+	mux.Handle("PUT /api/orgs/{id}/seats", authMiddleware(http.HandlerFunc(orgHandler.UpdateSeats)))
+	mux.Handle("GET /api/orgs/{id}/analytics", authMiddleware(http.HandlerFunc(orgHandler.Analytics)))
+	mux.Handle("GET /api/orgs/{id}/members", authMiddleware(http.HandlerFunc(workspaceHandler.ListMembers)))
+	mux.Handle("POST /api/orgs/{id}/members", authMiddleware(http.HandlerFunc(workspaceHandler.AddMember)))
+	mux.Handle("PUT /api/orgs/{id}/members/{userId}", authMiddleware(http.HandlerFunc(workspaceHandler.UpdateMemberRole)))
+	mux.Handle("DELETE /api/orgs/{id}/members/{userId}", authMiddleware(http.HandlerFunc(workspaceHandler.RemoveMember)))
+
+	// Billing handlers; the Stripe webhook authenticates by signature
+	billingHandler := rest.NewBillingHandler(billingService)
+	mux.HandleFunc("GET /api/billing/plans", billingHandler.ListPlans)
+	mux.HandleFunc("POST /api/billing/webhook", billingHandler.Webhook)
+	mux.Handle("GET /api/billing/subscription", authMiddleware(http.HandlerFunc(billingHandler.GetSubscription)))
+	mux.Handle("POST /api/billing/checkout", authMiddleware(http.HandlerFunc(billingHandler.Checkout)))
 
 	// Journal handlers
-	journalHandler := rest.NewJournalHandler(journalService, progressService)
-	mux.Handle("GET /api/entries", authMiddleware(http.HandlerFunc(journalHandler.List)))
-	mux.Handle("GET /api/entries/{id}", authMiddleware(http.HandlerFunc(journalHandler.Get)))
-	mux.Handle("POST /api/entries", authMiddleware(http.HandlerFunc(journalHandler.Create)))
-	mux.Handle("PUT /api/entries/{id}", authMiddleware(http.HandlerFunc(journalHandler.Update)))
-	mux.Handle("DELETE /api/entries/{id}", authMiddleware(http.HandlerFunc(journalHandler.Delete)))
+	journalHandler := rest.NewJournalHandler(journalService, snippetService, entrySnippetService, progressService, settingsService)
+	mux.Handle("GET /api/entries", entryAuth(http.HandlerFunc(journalHandler.List)))
+	mux.Handle("GET /api/entries/export", entryAuth(http.HandlerFunc(journalHandler.Export)))
+	mux.Handle("GET /api/entries/{id}", entryAuth(http.HandlerFunc(journalHandler.Get)))
+	mux.Handle("POST /api/entries", entryAuth(http.HandlerFunc(journalHandler.Create)))
+	mux.Handle("PUT /api/entries/{id}", entryAuth(http.HandlerFunc(journalHandler.Update)))
+	mux.Handle("DELETE /api/entries/{id}", entryAuth(http.HandlerFunc(journalHandler.Delete)))
+	mux.Handle("POST /api/entries/{id}/extract-snippets", entryAuth(http.HandlerFunc(journalHandler.ExtractSnippets)))
+	mux.Handle("PUT /api/entries/{id}/snippets/{snippetId}", entryAuth(http.HandlerFunc(journalHandler.LinkSnippet)))
+	mux.Handle("DELETE /api/entries/{id}/snippets/{snippetId}", entryAuth(http.HandlerFunc(journalHandler.UnlinkSnippet)))
+
+	// Import handlers; Notion, Obsidian, and Day One exports become entries in the background
+	importHandler := rest.NewImportHandler(importService)
+	mux.Handle("POST /api/imports", authMiddleware(http.HandlerFunc(importHandler.Create)))
+	mux.Handle("GET /api/imports", authMiddleware(http.HandlerFunc(importHandler.List)))
+	mux.Handle("GET /api/imports/{id}", authMiddleware(http.HandlerFunc(importHandler.Get)))
+
+	// Git activity handlers; the webhook authenticates by its secret, for GitHub and GitLab
+	gitActivityHandler := rest.NewGitActivityHandler(gitActivityService)
+	mux.HandleFunc("POST /api/git/webhooks/{id}", gitActivityHandler.Webhook)
+	mux.Handle("GET /api/git/hook", authMiddleware(http.HandlerFunc(gitActivityHandler.GetHook)))
+	mux.Handle("PUT /api/git/hook", authMiddleware(http.HandlerFunc(gitActivityHandler.UpdateHook)))
+	mux.Handle("DELETE /api/git/hook", authMiddleware(http.HandlerFunc(gitActivityHandler.DeleteHook)))
+	mux.Handle("POST /api/git/hook/secret", authMiddleware(http.HandlerFunc(gitActivityHandler.RotateSecret)))
+	mux.Handle("GET /api/git/drafts", authMiddleware(http.HandlerFunc(gitActivityHandler.ListDrafts)))
+	mux.Handle("PUT /api/git/drafts/{id}", authMiddleware(http.HandlerFunc(gitActivityHandler.UpdateDraft)))
+	mux.Handle("DELETE /api/git/drafts/{id}", authMiddleware(http.HandlerFunc(gitActivityHandler.DismissDraft)))
+	mux.Handle("POST /api/git/drafts/{id}/publish", authMiddleware(http.HandlerFunc(gitActivityHandler.PublishDraft)))
+
+	// Quick-capture inbox handlers
+	captureHandler := rest.NewCaptureHandler(captureService, progressService, settingsService)
+	mux.Handle("POST /api/capture", authMiddleware(http.HandlerFunc(captureHandler.Capture)))
+	mux.Handle("GET /api/inbox", authMiddleware(http.HandlerFunc(captureHandler.List)))
+	mux.Handle("PUT /api/inbox/{id}", authMiddleware(http.HandlerFunc(captureHandler.Update)))
+	mux.Handle("DELETE /api/inbox/{id}", authMiddleware(http.HandlerFunc(captureHandler.Dismiss)))
+	mux.Handle("POST /api/inbox/{id}/convert", authMiddleware(http.HandlerFunc(captureHandler.Convert)))
+	mux.Handle("POST /api/inbox/{id}/archive", authMiddleware(http.HandlerFunc(captureHandler.Archive)))
+
+	// Bookmark handlers
+	bookmarkHandler := rest.NewBookmarkHandler(bookmarkService, settingsService)
+	mux.Handle("GET /api/bookmarks", authMiddleware(http.HandlerFunc(bookmarkHandler.List)))
+	mux.Handle("POST /api/bookmarks", authMiddleware(http.HandlerFunc(bookmarkHandler.Create)))
+	mux.Handle("DELETE /api/bookmarks/{id}", authMiddleware(http.HandlerFunc(bookmarkHandler.Delete)))
+
+	// Site publishing handlers
+	siteHandler := rest.NewSiteHandler(sitePublishService, settingsService)
+	mux.Handle("GET /api/site/publisher", authMiddleware(http.HandlerFunc(siteHandler.GetPublisher)))
+	mux.Handle("PUT /api/site/publisher", authMiddleware(http.HandlerFunc(siteHandler.UpdatePublisher)))
+	mux.Handle("DELETE /api/site/publisher", authMiddleware(http.HandlerFunc(siteHandler.DeletePublisher)))
+	mux.Handle("GET /api/site/deliveries", authMiddleware(http.HandlerFunc(siteHandler.ListDeliveries)))
+
+	// Coding time handlers; heartbeats authenticate by the heartbeat token, as WakaTime plugins send it
+	codingHandler := rest.NewCodingHandler(codingService)
+	mux.HandleFunc("POST /api/coding/users/current/heartbeats", codingHandler.Heartbeats)
+	mux.HandleFunc("POST /api/coding/users/current/heartbeats.bulk", codingHandler.Heartbeats)
+	mux.Handle("GET /api/coding/source", authMiddleware(http.HandlerFunc(codingHandler.GetSource)))
+	mux.Handle("DELETE /api/coding/source", authMiddleware(http.HandlerFunc(codingHandler.Disconnect)))
+	mux.Handle("PUT /api/coding/wakatime", authMiddleware(http.HandlerFunc(codingHandler.ConnectWakaTime)))
+	mux.Handle("POST /api/coding/heartbeat-token", authMiddleware(http.HandlerFunc(codingHandler.CreateHeartbeatToken)))
+
+	// Project handlers
+	projectHandler := rest.NewProjectHandler(projectService, progressService, settingsService)
+	mux.Handle("GET /api/projects", authMiddleware(http.HandlerFunc(projectHandler.List)))
+	mux.Handle("POST /api/projects", authMiddleware(http.HandlerFunc(projectHandler.Create)))
+	mux.Handle("GET /api/projects/{id}", authMiddleware(http.HandlerFunc(projectHandler.Get)))
+	mux.Handle("PUT /api/projects/{id}", authMiddleware(http.HandlerFunc(projectHandler.Update)))
+	mux.Handle("DELETE /api/projects/{id}", authMiddleware(http.HandlerFunc(projectHandler.Delete)))
+	mux.Handle("GET /api/projects/{id}/timeline", entryAuth(http.HandlerFunc(projectHandler.Timeline)))
+	mux.Handle("PUT /api/projects/{id}/entries/{entryId}", authMiddleware(http.HandlerFunc(projectHandler.AttachEntry)))
+	mux.Handle("DELETE /api/projects/{id}/entries/{entryId}", authMiddleware(http.HandlerFunc(projectHandler.DetachEntry)))
+	mux.Handle("PUT /api/projects/{id}/snippets/{snippetId}", authMiddleware(http.HandlerFunc(projectHandler.AttachSnippet)))
+	mux.Handle("DELETE /api/projects/{id}/snippets/{snippetId}", authMiddleware(http.HandlerFunc(projectHandler.DetachSnippet)))
+	mux.Handle("POST /api/projects/{id}/focus", authMiddleware(http.HandlerFunc(projectHandler.LogFocus)))
+	mux.Handle("DELETE /api/projects/{id}/focus/{sessionId}", authMiddleware(http.HandlerFunc(projectHandler.DeleteFocus)))
+
+	// Learning path handlers
+	learningPathHandler := rest.NewLearningPathHandler(learningPathService, settingsService)
+	mux.Handle("GET /api/paths", authMiddleware(http.HandlerFunc(learningPathHandler.List)))
+	mux.Handle("POST /api/paths", authMiddleware(http.HandlerFunc(learningPathHandler.Create)))
+	mux.Handle("GET /api/paths/{id}", authMiddleware(http.HandlerFunc(learningPathHandler.Get)))
+	mux.Handle("PUT /api/paths/{id}", authMiddleware(http.HandlerFunc(learningPathHandler.Update)))
+	mux.Handle("DELETE /api/paths/{id}", authMiddleware(http.HandlerFunc(learningPathHandler.Delete)))
+	mux.Handle("PUT /api/paths/{id}/share", authMiddleware(http.HandlerFunc(learningPathHandler.Share)))
+	mux.Handle("DELETE /api/paths/{id}/share", authMiddleware(http.HandlerFunc(learningPathHandler.Unshare)))
+	mux.Handle("PUT /api/paths/{id}/order", authMiddleware(http.HandlerFunc(learningPathHandler.ReorderTopics)))
+	mux.Handle("POST /api/paths/{id}/topics", authMiddleware(http.HandlerFunc(learningPathHandler.AddTopic)))
+	mux.Handle("PUT /api/paths/{id}/topics/{topicId}", authMiddleware(http.HandlerFunc(learningPathHandler.UpdateTopic)))
+	mux.Handle("DELETE /api/paths/{id}/topics/{topicId}", authMiddleware(http.HandlerFunc(learningPathHandler.DeleteTopic)))
+	mux.Handle("PUT /api/paths/{id}/topics/{topicId}/complete", authMiddleware(http.HandlerFunc(learningPathHandler.CompleteTopic)))
+	mux.Handle("DELETE /api/paths/{id}/topics/{topicId}/complete", authMiddleware(http.HandlerFunc(learningPathHandler.ReopenTopic)))
+	mux.Handle("PUT /api/paths/{id}/topics/{topicId}/entries/{entryId}", authMiddleware(http.HandlerFunc(learningPathHandler.LinkEntry)))
+	mux.Handle("DELETE /api/paths/{id}/topics/{topicId}/entries/{entryId}", authMiddleware(http.HandlerFunc(learningPathHandler.UnlinkEntry)))
+	mux.Handle("PUT /api/paths/{id}/topics/{topicId}/snippets/{snippetId}", authMiddleware(http.HandlerFunc(learningPathHandler.LinkSnippet)))
+	mux.Handle("DELETE /api/paths/{id}/topics/{topicId}/snippets/{snippetId}", authMiddleware(http.HandlerFunc(learningPathHandler.UnlinkSnippet)))
+
+	// TIL micro-entry handlers
+	tilHandler := rest.NewTILHandler(tilService, progressService, settingsService)
+	mux.Handle("GET /api/til", authMiddleware(http.HandlerFunc(tilHandler.List)))
+	mux.Handle("POST /api/til", authMiddleware(http.HandlerFunc(tilHandler.Create)))
+	mux.Handle("DELETE /api/til/{id}", authMiddleware(http.HandlerFunc(tilHandler.Delete)))
+
+	// Solved problem log handlers
+	problemHandler := rest.NewProblemHandler(problemService, progressService, settingsService)
+	mux.Handle("GET /api/problems", authMiddleware(http.HandlerFunc(problemHandler.List)))
+	mux.Handle("POST /api/problems", authMiddleware(http.HandlerFunc(problemHandler.Create)))
+	mux.Handle("GET /api/problems/stats", authMiddleware(http.HandlerFunc(problemHandler.Stats)))
+	mux.Handle("GET /api/problems/{id}", authMiddleware(http.HandlerFunc(problemHandler.Get)))
+	mux.Handle("PUT /api/problems/{id}", authMiddleware(http.HandlerFunc(problemHandler.Update)))
+	mux.Handle("DELETE /api/problems/{id}", authMiddleware(http.HandlerFunc(problemHandler.Delete)))
 
 	// Snippet handlers
-	snippetHandler := rest.NewSnippetHandler(snippetService, progressService)
+	snippetHandler := rest.NewSnippetHandler(snippetService, entrySnippetService, progressService, settingsService, playgroundService)
+	mux.HandleFunc("GET /api/public/snippets/trending", snippetHandler.Trending)
+	mux.HandleFunc("GET /api/public/snippets/{slug}/raw", snippetHandler.Raw)
+	mux.HandleFunc("GET /api/public/snippets/{slug}/download", snippetHandler.Download)
+
+	// Public snippet embeds for blogs (HTML page, or a loader script when the slug ends in .js) and oEmbed
+	embedHandler := rest.NewEmbedHandler(embedService)
+	mux.HandleFunc("GET /embed/snippets/{slug}", embedHandler.Snippet)
+	mux.HandleFunc("GET /api/oembed", embedHandler.OEmbed)
+
+	// Sitemap, robots.txt, and link preview metadata of public pages
+	seoHandler := rest.NewSEOHandler(seoService, cfg.SitemapURL)
+	mux.HandleFunc("GET /sitemap.xml", seoHandler.Sitemap)
+	mux.HandleFunc("GET /robots.txt", seoHandler.Robots)
+	mux.HandleFunc("GET /api/public/meta/snippets/{slug}", seoHandler.SnippetMeta)
+	mux.HandleFunc("GET /api/public/meta/users/{userId}", seoHandler.ProfileMeta)
+	mux.HandleFunc("GET /api/public/og/snippets/{slug}", seoHandler.SnippetImage)
+	mux.HandleFunc("GET /api/public/og/entries/{id}", seoHandler.EntryImage)
 	mux.Handle("GET /api/snippets", authMiddleware(http.HandlerFunc(snippetHandler.List)))
-	mux.Handle("GET /api/snippets/{id}", authMiddleware(http.HandlerFunc(snippetHandler.Get)))
+	mux.Handle("GET /api/snippets/export", authMiddleware(http.HandlerFunc(snippetHandler.Export)))
+	mux.Handle("GET /api/snippets/stats", authMiddleware(http.HandlerFunc(snippetHandler.Stats)))
+	mux.Handle("GET /api/snippets/languages", authMiddleware(http.HandlerFunc(snippetHandler.LanguageStats)))
+	mux.Handle("GET /api/snippets/dependencies", authMiddleware(http.HandlerFunc(snippetHandler.DependencyStats)))
+	mux.Handle("GET /api/snippets/{id}", guestOrAuth(http.HandlerFunc(snippetHandler.Get)))
 	mux.Handle("POST /api/snippets", authMiddleware(http.HandlerFunc(snippetHandler.Create)))
+	mux.Handle("POST /api/snippets/merge", authMiddleware(http.HandlerFunc(snippetHandler.Merge)))
 	mux.Handle("PUT /api/snippets/{id}", authMiddleware(http.HandlerFunc(snippetHandler.Update)))
+	mux.Handle("POST /api/snippets/{id}/scan", authMiddleware(http.HandlerFunc(snippetHandler.Scan)))
 	mux.Handle("DELETE /api/snippets/{id}", authMiddleware(http.HandlerFunc(snippetHandler.Delete)))
 
+	// Snippet line comment handlers
+	snippetCommentHandler := rest.NewSnippetCommentHandler(snippetCommentService)
+	mux.Handle("GET /api/snippets/{id}/comments", guestOrAuth(http.HandlerFunc(snippetCommentHandler.List)))
+	mux.Handle("POST /api/snippets/{id}/comments", authMiddleware(http.HandlerFunc(snippetCommentHandler.Create)))
+	mux.Handle("PUT /api/snippets/{id}/comments/{commentId}/resolved", authMiddleware(http.HandlerFunc(snippetCommentHandler.Resolve)))
+	mux.Handle("DELETE /api/snippets/{id}/comments/{commentId}/resolved", authMiddleware(http.HandlerFunc(snippetCommentHandler.Resolve)))
+
+	// Editor plugin handlers
+	editorHandler := rest.NewEditorHandler(snippetService, progressService)
+	mux.Handle("POST /api/editor/snippets", authMiddleware(http.HandlerFunc(editorHandler.Capture)))
+	mux.Handle("GET /api/editor/snippets/recent", authMiddleware(http.HandlerFunc(editorHandler.Recent)))
+
 	// Study group handlers
-	studyGroupHandler := rest.NewStudyGroupHandler(studyGroupService)
+	studyGroupHandler := rest.NewStudyGroupHandler(studyGroupService, settingsService)
 	mux.Handle("GET /api/groups", authMiddleware(http.HandlerFunc(studyGroupHandler.List)))
 	mux.Handle("GET /api/groups/discover", authMiddleware(http.HandlerFunc(studyGroupHandler.ListPublic)))
 	mux.Handle("GET /api/groups/{id}", authMiddleware(http.HandlerFunc(studyGroupHandler.Get)))
 	mux.Handle("POST /api/groups", authMiddleware(http.HandlerFunc(studyGroupHandler.Create)))
 	mux.Handle("POST /api/groups/{id}/join", authMiddleware(http.HandlerFunc(studyGroupHandler.Join)))
+	mux.Handle("POST /api/groups/{id}/invites", authMiddleware(http.HandlerFunc(studyGroupHandler.CreateInvite)))
 	mux.Handle("POST /api/groups/{id}/leave", authMiddleware(http.HandlerFunc(studyGroupHandler.Leave)))
 	mux.Handle("GET /api/groups/{id}/members", authMiddleware(http.HandlerFunc(studyGroupHandler.GetMembers)))
 	mux.Handle("DELETE /api/groups/{id}", authMiddleware(http.HandlerFunc(studyGroupHandler.Delete)))
+	mux.Handle("POST /api/groups/{id}/reactivate", authMiddleware(http.HandlerFunc(studyGroupHandler.Reactivate)))
+	mux.Handle("GET /api/groups/{id}/paths", authMiddleware(http.HandlerFunc(learningPathHandler.ListByGroup)))
+
+	// Study group quiz handlers
+	quizHandler := rest.NewQuizHandler(quizService)
+	mux.Handle("GET /api/groups/{id}/quizzes", authMiddleware(http.HandlerFunc(quizHandler.List)))
+	mux.Handle("POST /api/groups/{id}/quizzes", authMiddleware(http.HandlerFunc(quizHandler.Create)))
+	mux.Handle("GET /api/groups/{id}/quizzes/{quizId}", authMiddleware(http.HandlerFunc(quizHandler.Get)))
+	mux.Handle("PUT /api/groups/{id}/quizzes/{quizId}", authMiddleware(http.HandlerFunc(quizHandler.Update)))
+	mux.Handle("DELETE /api/groups/{id}/quizzes/{quizId}", authMiddleware(http.HandlerFunc(quizHandler.Delete)))
+	mux.Handle("POST /api/groups/{id}/quizzes/{quizId}/attempts", authMiddleware(http.HandlerFunc(quizHandler.Submit)))
+	mux.Handle("GET /api/groups/{id}/leaderboard", authMiddleware(http.HandlerFunc(quizHandler.Leaderboard)))
+
+	// Study group code review handlers
+	codeReviewHandler := rest.NewCodeReviewHandler(codeReviewService, settingsService)
+	mux.Handle("GET /api/groups/{id}/code-reviews", authMiddleware(http.HandlerFunc(codeReviewHandler.List)))
+	mux.Handle("POST /api/groups/{id}/code-reviews", authMiddleware(http.HandlerFunc(codeReviewHandler.Create)))
+	mux.Handle("GET /api/groups/{id}/code-reviews/{reviewId}", authMiddleware(http.HandlerFunc(codeReviewHandler.Get)))
+	mux.Handle("DELETE /api/groups/{id}/code-reviews/{reviewId}", authMiddleware(http.HandlerFunc(codeReviewHandler.Delete)))
+	mux.Handle("PUT /api/groups/{id}/code-reviews/{reviewId}/resolved", authMiddleware(http.HandlerFunc(codeReviewHandler.Resolve)))
+	mux.Handle("DELETE /api/groups/{id}/code-reviews/{reviewId}/resolved", authMiddleware(http.HandlerFunc(codeReviewHandler.Resolve)))
+	mux.Handle("POST /api/groups/{id}/code-reviews/{reviewId}/comments", authMiddleware(http.HandlerFunc(codeReviewHandler.Comment)))
+	mux.Handle("PUT /api/groups/{id}/code-reviews/{reviewId}/comments/{commentId}/resolved", authMiddleware(http.HandlerFunc(codeReviewHandler.ResolveComment)))
+	mux.Handle("DELETE /api/groups/{id}/code-reviews/{reviewId}/comments/{commentId}/resolved", authMiddleware(http.HandlerFunc(codeReviewHandler.ResolveComment)))
+
+	// Chat moderation handlers
+	moderationHandler := rest.NewModerationHandler(moderationService, settingsService, hub)
+	adminOnly := middleware.AdminOnly(authService)
+	mux.Handle("GET /api/groups/{id}/moderation", authMiddleware(http.HandlerFunc(moderationHandler.GetSettings)))
+	mux.Handle("PUT /api/groups/{id}/moderation", authMiddleware(http.HandlerFunc(moderationHandler.UpdateSettings)))
+	groupPermissionHandler := rest.NewGroupPermissionHandler(groupPermissionService)
+	mux.Handle("GET /api/groups/{id}/permissions", authMiddleware(http.HandlerFunc(groupPermissionHandler.Get)))
+	mux.Handle("PUT /api/groups/{id}/permissions", authMiddleware(http.HandlerFunc(groupPermissionHandler.Update)))
+	mux.Handle("POST /api/groups/{id}/messages/{messageId}/report", authMiddleware(http.HandlerFunc(moderationHandler.ReportMessage)))
+	chatHandler := rest.NewChatHandler(studyGroupService, hub)
+	mux.Handle("GET /api/groups/{id}/messages/{messageId}/thread", authMiddleware(http.HandlerFunc(chatHandler.Thread)))
+	groupChannelHandler := rest.NewGroupChannelHandler(groupChannelService)
+	mux.Handle("GET /api/groups/{id}/channels", authMiddleware(http.HandlerFunc(groupChannelHandler.List)))
+	mux.Handle("POST /api/groups/{id}/channels", authMiddleware(http.HandlerFunc(groupChannelHandler.Create)))
+	mux.Handle("PUT /api/groups/{id}/channels/{name}", authMiddleware(http.HandlerFunc(groupChannelHandler.Update)))
+	mux.Handle("DELETE /api/groups/{id}/channels/{name}", authMiddleware(http.HandlerFunc(groupChannelHandler.Delete)))
+	entryTemplateHandler := rest.NewEntryTemplateHandler(entryTemplateService)
+	mux.Handle("GET /api/groups/{id}/templates", authMiddleware(http.HandlerFunc(entryTemplateHandler.List)))
+	mux.Handle("POST /api/groups/{id}/templates", authMiddleware(http.HandlerFunc(entryTemplateHandler.Create)))
+	mux.Handle("PUT /api/groups/{id}/templates/{templateId}", authMiddleware(http.HandlerFunc(entryTemplateHandler.Update)))
+	mux.Handle("DELETE /api/groups/{id}/templates/{templateId}", authMiddleware(http.HandlerFunc(entryTemplateHandler.Delete)))
+	mux.Handle("POST /api/groups/{id}/templates/{templateId}/entries", authMiddleware(http.HandlerFunc(entryTemplateHandler.Instantiate)))
+	mux.Handle("GET /api/groups/{id}/templates/{templateId}/instances", authMiddleware(http.HandlerFunc(entryTemplateHandler.ListInstances)))
+	mux.Handle("GET /api/groups/{id}/templates/{templateId}/instances/{instance}", authMiddleware(http.HandlerFunc(entryTemplateHandler.GetInstance)))
+	pollHandler := rest.NewPollHandler(pollService)
+	mux.Handle("GET /api/groups/{id}/polls", authMiddleware(http.HandlerFunc(pollHandler.List)))
+	mux.Handle("POST /api/groups/{id}/polls", authMiddleware(http.HandlerFunc(pollHandler.Create)))
+	mux.Handle("POST /api/groups/{id}/polls/{pollId}/votes", authMiddleware(http.HandlerFunc(pollHandler.Vote)))
+	mux.Handle("GET /api/admin/moderation/reports", authMiddleware(adminOnly(http.HandlerFunc(moderationHandler.ListReports))))
+	mux.Handle("POST /api/admin/moderation/reports/{id}/resolve", authMiddleware(adminOnly(http.HandlerFunc(moderationHandler.ResolveReport))))
+	mux.Handle("POST /api/public/snippets/{id}/report", authMiddleware(http.HandlerFunc(moderationHandler.ReportSnippet)))
+	mux.Handle("GET /api/admin/reports", authMiddleware(adminOnly(http.HandlerFunc(moderationHandler.ListContentReports))))
+	mux.Handle("POST /api/admin/reports/{id}/resolve", authMiddleware(adminOnly(http.HandlerFunc(moderationHandler.ResolveContentReport))))
+	mux.Handle("GET /api/users/me/warnings", authMiddleware(http.HandlerFunc(moderationHandler.ListMyWarnings)))
+	mux.Handle("GET /api/admin/announcements", authMiddleware(adminOnly(http.HandlerFunc(announcementHandler.ListAll))))
+	mux.Handle("POST /api/admin/announcements", authMiddleware(adminOnly(http.HandlerFunc(announcementHandler.Create))))
+	mux.Handle("DELETE /api/admin/announcements/{id}", authMiddleware(adminOnly(http.HandlerFunc(announcementHandler.Delete))))
+	backupHandler := rest.NewBackupHandler(backupService)
+	mux.Handle("GET /api/admin/backups", authMiddleware(adminOnly(http.HandlerFunc(backupHandler.List))))
+	mux.Handle("POST /api/admin/backups", authMiddleware(adminOnly(http.HandlerFunc(backupHandler.Create))))
+	mux.Handle("POST /api/admin/backups/{name}/verify", authMiddleware(adminOnly(http.HandlerFunc(backupHandler.Verify))))
+
+	inviteHandler := rest.NewInviteHandler(inviteService, settingsService)
+	mux.Handle("GET /api/admin/invites", authMiddleware(adminOnly(http.HandlerFunc(inviteHandler.List))))
+	mux.Handle("POST /api/admin/invites", authMiddleware(adminOnly(http.HandlerFunc(inviteHandler.Create))))
+	mux.Handle("DELETE /api/admin/invites/{id}", authMiddleware(adminOnly(http.HandlerFunc(inviteHandler.Delete))))
+
+	// Admins acting as users for support, read-only unless asked otherwise; users see when they were
+	impersonationHandler := rest.NewImpersonationHandler(impersonationService, settingsService)
+	mux.Handle("GET /api/admin/impersonations", authMiddleware(adminOnly(http.HandlerFunc(impersonationHandler.List))))
+	mux.Handle("POST /api/admin/impersonations", authMiddleware(adminOnly(http.HandlerFunc(impersonationHandler.Start))))
+	mux.Handle("DELETE /api/admin/impersonations/{id}", authMiddleware(adminOnly(http.HandlerFunc(impersonationHandler.End))))
+	mux.Handle("GET /api/users/me/impersonations", authMiddleware(http.HandlerFunc(impersonationHandler.ListMine)))
+
+	// Slack/Discord group integrations (callbacks and provider events are public, verified by state or signature)
+	integrationHandler := rest.NewIntegrationHandler(integrationService)
+	mux.Handle("GET /api/groups/{id}/integrations", authMiddleware(http.HandlerFunc(integrationHandler.List)))
+	mux.Handle("POST /api/groups/{id}/integrations/{provider}/install", authMiddleware(http.HandlerFunc(integrationHandler.Install)))
+	mux.Handle("PUT /api/groups/{id}/integrations/{provider}", authMiddleware(http.HandlerFunc(integrationHandler.Update)))
+	mux.Handle("DELETE /api/groups/{id}/integrations/{provider}", authMiddleware(http.HandlerFunc(integrationHandler.Delete)))
+	mux.HandleFunc("GET /api/integrations/{provider}/callback", integrationHandler.Callback)
+	mux.HandleFunc("POST /api/integrations/slack/events", integrationHandler.SlackEvents)
+	mux.HandleFunc("POST /api/integrations/discord/interactions", integrationHandler.DiscordInteractions)
+
+	// Spaced-repetition review handlers
+	reviewHandler := rest.NewReviewHandler(reviewService)
+	mux.Handle("GET /api/review/next", entryAuth(http.HandlerFunc(reviewHandler.Next)))
+	mux.Handle("POST /api/review/{id}/feedback", authMiddleware(http.HandlerFunc(reviewHandler.Feedback)))
+
+	// Year in review handlers
+	yearlyReviewHandler := rest.NewYearlyReviewHandler(yearlyReviewService)
+	mux.Handle("GET /api/reviews/yearly/{year}", authMiddleware(http.HandlerFunc(yearlyReviewHandler.Get)))
 
 	// Progress handlers
-	progressHandler := rest.NewProgressHandler(progressService)
+	progressHandler := rest.NewProgressHandler(progressService, journalService, learningPathService, codingService)
 	mux.Handle("GET /api/progress/summary", authMiddleware(http.HandlerFunc(progressHandler.GetSummary)))
 	mux.Handle("GET /api/progress/today", authMiddleware(http.HandlerFunc(progressHandler.GetToday)))
 	mux.Handle("GET /api/progress/weekly", authMiddleware(http.HandlerFunc(progressHandler.GetWeekly)))
 	mux.Handle("GET /api/progress/monthly", authMiddleware(http.HandlerFunc(progressHandler.GetMonthly)))
+	mux.Handle("POST /api/progress/query", authMiddleware(http.HandlerFunc(progressHandler.Query)))
 	mux.Handle("GET /api/progress/streak", authMiddleware(http.HandlerFunc(progressHandler.GetStreak)))
+	mux.Handle("GET /api/progress/achievements", authMiddleware(http.HandlerFunc(progressHandler.ListAchievements)))
+	mux.Handle("GET /api/progress/writing", authMiddleware(http.HandlerFunc(progressHandler.GetWriting)))
+	mux.Handle("GET /api/progress/languages", authMiddleware(http.HandlerFunc(progressHandler.GetLanguages)))
+	mux.Handle("GET /api/progress/compare", authMiddleware(http.HandlerFunc(progressHandler.Compare)))
 
 	// WebSocket handler for chat
-	wsHandler := websocket.NewChatHandler(hub, authService)
-	mux.Handle("GET /ws/chat/{room}", authMiddleware(http.HandlerFunc(wsHandler.HandleWebSocket)))
-
-	// Apply global middleware
-	handler := middleware.CORS(mux)
-	handler = middleware.Logging(handler)
-	handler = middleware.Recovery(handler)
+	// Chat WebSockets authenticate with a one-time ticket rather than a token in the URL
+	chatTicketHandler := rest.NewChatTicketHandler(chatTicketService)
+	mux.Handle("POST /api/ws/ticket", authMiddleware(http.HandlerFunc(chatTicketHandler.Create)))
+	wsHandler := websocket.NewChatHandler(hub, chatTicketService, cfg.WSAllowedOrigins)
+	mux.HandleFunc("GET /ws/chat/{room}", wsHandler.HandleWebSocket)
+
+	// Apply global middleware, outermost first, after the edge shared with the Connect server
+	stack := append(middleware.Edge(reporter), middleware.Logging)
+	if cfg.DebugBodyLogging {
+		stack = append(stack, middleware.BodyLogging(cfg.DebugBodySampleRate, cfg.DebugBodyMaxBytes))
+	}
+	stack = append(stack, middleware.Locale(authService, settingsService), middleware.APIVersion(cfg.APILegacySunset))
 
-	return handler
+	return middleware.Chain(stack...)(mux)
 }
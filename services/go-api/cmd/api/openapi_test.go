@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// routePattern matches REST route registrations such as mux.Handle("GET /api/entries", ...)
+var routePattern = regexp.MustCompile(`mux\.Handle(?:Func)?\("([A-Z]+) (/api/[^"]*)"`)
+
+// TestOpenAPICoversRoutes keeps openapi.yaml, which the TypeScript REST client is generated
+// from, in step with the routes registered in setupRouter
+func TestOpenAPICoversRoutes(t *testing.T) {
+	source, err := os.ReadFile("main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := make(map[string]bool)
+	for _, match := range routePattern.FindAllStringSubmatch(string(source), -1) {
+		routes[match[1]+" "+strings.TrimPrefix(match[2], "/api")] = true
+	}
+	if len(routes) == 0 {
+		t.Fatal("found no routes in main.go")
+	}
+
+	raw, err := os.ReadFile("../../openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec struct {
+		Paths map[string]map[string]yaml.Node `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(raw, &spec); err != nil {
+		t.Fatalf("parse openapi.yaml: %v", err)
+	}
+	documented := make(map[string]bool)
+	for path, item := range spec.Paths {
+		for method := range item {
+			if method != "parameters" {
+				documented[strings.ToUpper(method)+" "+path] = true
+			}
+		}
+	}
+
+	for route := range routes {
+		if !documented[route] {
+			t.Errorf("%s is registered but missing from openapi.yaml", route)
+		}
+	}
+	for route := range documented {
+		if !routes[route] {
+			t.Errorf("%s is in openapi.yaml but not registered", route)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Command mcp is a Model Context Protocol server that lets AI assistants and editors
+// journal and query a DevJournal account through tools such as create_entry,
+// search_snippets, and get_streak.
+//
+//	go run ./cmd/mcp -api http://localhost:8080
+//
+// It talks to a running API server over stdio, so register it with an MCP client as a
+// command, e.g. in Claude Desktop's or an editor's MCP settings:
+//
+//	{"command": "devjournal-mcp", "env": {"DEVJOURNAL_TOKEN": "<jwt>"}}
+//
+// Environment:
+//
+//	DEVJOURNAL_API_URL  - API base URL (default: http://localhost:8080; -api overrides)
+//	DEVJOURNAL_TOKEN    - JWT to act as
+//	DEVJOURNAL_EMAIL    - with DEVJOURNAL_PASSWORD, log in at startup instead of using a token
+//	DEVJOURNAL_PASSWORD
+//
+// Stdout carries the protocol, so logs go to stderr.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"devjournal/internal/mcp"
+	"devjournal/pkg/client"
+)
+
+const version = "1.0.0"
+
+func main() {
+	log.SetOutput(os.Stderr)
+	log.SetPrefix("devjournal-mcp: ")
+
+	apiURL := flag.String("api", envOr("DEVJOURNAL_API_URL", "http://localhost:8080"), "base URL of the DevJournal API")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	api := client.New(*apiURL, client.WithToken(os.Getenv("DEVJOURNAL_TOKEN")), client.WithUserAgent("devjournal-mcp/"+version))
+	if email := os.Getenv("DEVJOURNAL_EMAIL"); email != "" {
+		if _, err := api.Login(ctx, email, os.Getenv("DEVJOURNAL_PASSWORD")); err != nil {
+			log.Fatalf("login as %s: %v", email, err)
+		}
+	}
+	if api.Token() == "" {
+		log.Fatal("set DEVJOURNAL_TOKEN, or DEVJOURNAL_EMAIL and DEVJOURNAL_PASSWORD")
+	}
+
+	server := mcp.NewServer("devjournal", version)
+	registerTools(server, api)
+
+	if err := server.Serve(ctx, os.Stdin, os.Stdout); err != nil {
+		log.Fatalf("serve: %v", err)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/mcp"
+	"devjournal/pkg/client"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+	excerptLength      = 280
+)
+
+// registerTools adds the DevJournal tools, each backed by one or two API calls
+func registerTools(server *mcp.Server, api *client.Client) {
+	server.AddTool(mcp.Tool{
+		Name:        "create_entry",
+		Description: "Write a new journal entry. Content is Markdown.",
+		InputSchema: object(map[string]interface{}{
+			"title":   str("Entry title"),
+			"content": str("Entry body in Markdown"),
+			"mood":    str("Optional mood, e.g. productive, curious, frustrated"),
+			"tags":    strList("Optional tags, e.g. [\"go\", \"databases\"]"),
+		}, "title", "content"),
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				Title   string   `json:"title"`
+				Content string   `json:"content"`
+				Mood    string   `json:"mood"`
+				Tags    []string `json:"tags"`
+			}
+			if err := decode(args, &in); err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(in.Title) == "" || strings.TrimSpace(in.Content) == "" {
+				return nil, errors.New("title and content are required")
+			}
+			entry, err := api.CreateEntry(ctx, &client.CreateJournalEntryRequest{
+				Title: in.Title, Content: in.Content, Mood: in.Mood, Tags: in.Tags,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return entrySummary(entry), nil
+		},
+	})
+
+	server.AddTool(mcp.Tool{
+		Name:        "search_entries",
+		Description: "Find journal entries by full-text query, tags, or mood, newest first. Returns excerpts; use get_entry for the full text.",
+		InputSchema: object(map[string]interface{}{
+			"query": str("Full-text search query"),
+			"tags":  strList("Only entries with any of these tags (ignored when query is set)"),
+			"mood":  str("Only entries with this mood (ignored when query or tags are set)"),
+			"limit": limitSchema(),
+		}),
+		ReadOnly: true,
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				Query string   `json:"query"`
+				Tags  []string `json:"tags"`
+				Mood  string   `json:"mood"`
+				Limit int      `json:"limit"`
+			}
+			if err := decode(args, &in); err != nil {
+				return nil, err
+			}
+			page, err := api.ListEntries(ctx, 1, client.EntryListOptions{
+				PageSize: clampLimit(in.Limit), Search: in.Query, Tags: in.Tags, Mood: in.Mood,
+			})
+			if err != nil {
+				return nil, err
+			}
+			entries := make([]map[string]interface{}, 0, len(page.Data))
+			for i := range page.Data {
+				entries = append(entries, entrySummary(&page.Data[i]))
+			}
+			return map[string]interface{}{"total": page.Total, "entries": entries}, nil
+		},
+	})
+
+	server.AddTool(mcp.Tool{
+		Name:        "get_entry",
+		Description: "Read a journal entry in full by ID.",
+		InputSchema: object(map[string]interface{}{"id": str("Entry ID")}, "id"),
+		ReadOnly:    true,
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				ID string `json:"id"`
+			}
+			if err := decode(args, &in); err != nil {
+				return nil, err
+			}
+			entry, err := api.GetEntry(ctx, in.ID)
+			if err != nil {
+				return nil, err
+			}
+			if entry.ContentFormat == domain.ContentFormatE2EE {
+				return nil, errors.New("this entry is end-to-end encrypted and can only be read in the DevJournal app")
+			}
+			return entry, nil
+		},
+	})
+
+	server.AddTool(mcp.Tool{
+		Name:        "create_snippet",
+		Description: "Save a code snippet. Snippets are private unless isPublic is true. Fails listing the matches if a very similar snippet exists, unless allowDuplicate is true.",
+		InputSchema: object(map[string]interface{}{
+			"title":          str("Snippet title"),
+			"code":           str("The code"),
+			"language":       str("Language, e.g. go, typescript, sql"),
+			"description":    str("Optional explanation"),
+			"tags":           strList("Optional tags"),
+			"isPublic":       map[string]interface{}{"type": "boolean", "description": "Share on the public trending feed"},
+			"allowDuplicate": map[string]interface{}{"type": "boolean", "description": "Save even if a very similar snippet exists"},
+		}, "title", "code", "language"),
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				Title          string   `json:"title"`
+				Code           string   `json:"code"`
+				Language       string   `json:"language"`
+				Description    string   `json:"description"`
+				Tags           []string `json:"tags"`
+				IsPublic       bool     `json:"isPublic"`
+				AllowDuplicate bool     `json:"allowDuplicate"`
+			}
+			if err := decode(args, &in); err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(in.Title) == "" || in.Code == "" || in.Language == "" {
+				return nil, errors.New("title, code, and language are required")
+			}
+			snippet, err := api.CreateSnippet(ctx, &client.CreateSnippetRequest{
+				Title: in.Title, Code: in.Code, Language: in.Language,
+				Description: in.Description, Tags: in.Tags, IsPublic: in.IsPublic,
+				AllowDuplicate: in.AllowDuplicate,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return snippetSummary(snippet), nil
+		},
+	})
+
+	server.AddTool(mcp.Tool{
+		Name:        "search_snippets",
+		Description: "Find saved code snippets by query, language, or tags, newest first. Returns metadata; use get_snippet for the code.",
+		InputSchema: object(map[string]interface{}{
+			"query":    str("Search titles, descriptions, and code"),
+			"language": str("Only snippets in this language"),
+			"tags":     strList("Only snippets with these tags"),
+			"limit":    limitSchema(),
+		}),
+		ReadOnly: true,
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				Query    string   `json:"query"`
+				Language string   `json:"language"`
+				Tags     []string `json:"tags"`
+				Limit    int      `json:"limit"`
+			}
+			if err := decode(args, &in); err != nil {
+				return nil, err
+			}
+			page, err := api.ListSnippets(ctx, 1, client.SnippetListOptions{
+				PageSize: clampLimit(in.Limit), Search: in.Query, Language: in.Language, Tags: in.Tags,
+			})
+			if err != nil {
+				return nil, err
+			}
+			snippets := make([]map[string]interface{}, 0, len(page.Data))
+			for i := range page.Data {
+				snippets = append(snippets, snippetSummary(&page.Data[i]))
+			}
+			return map[string]interface{}{"total": page.Total, "snippets": snippets}, nil
+		},
+	})
+
+	server.AddTool(mcp.Tool{
+		Name:        "get_snippet",
+		Description: "Read a code snippet, including its code, by ID.",
+		InputSchema: object(map[string]interface{}{"id": str("Snippet ID")}, "id"),
+		ReadOnly:    true,
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				ID string `json:"id"`
+			}
+			if err := decode(args, &in); err != nil {
+				return nil, err
+			}
+			return api.GetSnippet(ctx, in.ID)
+		},
+	})
+
+	server.AddTool(mcp.Tool{
+		Name:        "get_streak",
+		Description: "Get the current and longest learning streaks in days, with entry, snippet, and TIL totals.",
+		InputSchema: object(map[string]interface{}{}),
+		ReadOnly:    true,
+		Handler: func(ctx context.Context, _ json.RawMessage) (interface{}, error) {
+			return api.ProgressSummary(ctx)
+		},
+	})
+
+	server.AddTool(mcp.Tool{
+		Name:        "get_writing_stats",
+		Description: "Get journal word counts per week.",
+		InputSchema: object(map[string]interface{}{
+			"weeks": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 52, "description": "Weeks of history (default 12)"},
+		}),
+		ReadOnly: true,
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				Weeks int `json:"weeks"`
+			}
+			if err := decode(args, &in); err != nil {
+				return nil, err
+			}
+			return api.WritingStats(ctx, in.Weeks)
+		},
+	})
+}
+
+// decode unmarshals tool arguments, reporting problems in terms the model can act on
+func decode(args json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(args, v); err != nil {
+		return fmt.Errorf("invalid arguments: %v", err)
+	}
+	return nil
+}
+
+// entrySummary is an entry with its content cut to an excerpt
+func entrySummary(entry *client.JournalEntry) map[string]interface{} {
+	summary := map[string]interface{}{
+		"id":        entry.ID,
+		"title":     entry.Title,
+		"mood":      entry.Mood,
+		"tags":      entry.Tags,
+		"wordCount": entry.WordCount,
+		"createdAt": entry.CreatedAt.Format(time.RFC3339),
+	}
+	if entry.ContentFormat == domain.ContentFormatE2EE {
+		summary["encrypted"] = true
+	} else {
+		summary["excerpt"] = excerpt(entry.Content)
+	}
+	return summary
+}
+
+// snippetSummary is a snippet without its code
+func snippetSummary(snippet *client.Snippet) map[string]interface{} {
+	summary := map[string]interface{}{
+		"id":          snippet.ID,
+		"title":       snippet.Title,
+		"description": snippet.Description,
+		"language":    snippet.Language,
+		"tags":        snippet.Tags,
+		"isPublic":    snippet.IsPublic,
+		"lines":       snippet.Stats.Lines,
+		"createdAt":   snippet.CreatedAt.Format(time.RFC3339),
+	}
+	if len(snippet.SecretWarnings) > 0 {
+		summary["secretWarnings"] = snippet.SecretWarnings
+	}
+	return summary
+}
+
+// excerpt shortens content to excerptLength runes
+func excerpt(content string) string {
+	if utf8.RuneCountInString(content) <= excerptLength {
+		return content
+	}
+	return string([]rune(content)[:excerptLength]) + "…"
+}
+
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return defaultSearchLimit
+	}
+	return min(limit, maxSearchLimit)
+}
+
+// object is a JSON Schema for an arguments object
+func object(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func str(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+func strList(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "array", "items": map[string]string{"type": "string"}, "description": description}
+}
+
+func limitSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "integer", "minimum": 1, "maximum": maxSearchLimit,
+		"description": fmt.Sprintf("Maximum results (default %d)", defaultSearchLimit),
+	}
+}
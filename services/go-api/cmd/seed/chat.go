@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// seedGroup is a created study group and the users who belong to it
+type seedGroup struct {
+	ID      uuid.UUID
+	Members []*seedUser
+}
+
+// chatPause spaces out messages so the server's per-user chat rate limit is not hit
+const chatPause = 50 * time.Millisecond
+
+// seedChat sends perGroup messages to each group's room from random members of that group.
+// The hub only keeps chat history in memory, so this goes through a running server's WebSocket.
+func seedChat(api string, groups []*seedGroup, perGroup int, gen *generator) (int, error) {
+	base, err := url.Parse(strings.TrimSuffix(api, "/"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid -api URL: %w", err)
+	}
+	switch base.Scheme {
+	case "https":
+		base.Scheme = "wss"
+	default:
+		base.Scheme = "ws"
+	}
+
+	sent := 0
+	for _, group := range groups {
+		conns := make(map[uuid.UUID]*websocket.Conn)
+		for i := 0; i < perGroup; i++ {
+			member := group.Members[gen.rng.Intn(len(group.Members))]
+			conn, ok := conns[member.ID]
+			if !ok {
+				ticket, err := chatTicket(api, member.Token, group.ID.String())
+				if err != nil {
+					closeAll(conns)
+					return sent, err
+				}
+				roomURL := *base
+				roomURL.Path += "/ws/chat/" + group.ID.String()
+				roomURL.RawQuery = url.Values{"ticket": {ticket}}.Encode()
+				conn, _, err = websocket.DefaultDialer.Dial(roomURL.String(), nil)
+				if err != nil {
+					closeAll(conns)
+					return sent, fmt.Errorf("failed to join chat room %s: %w", group.ID, err)
+				}
+				conns[member.ID] = conn
+			}
+
+			if err := conn.WriteJSON(map[string]string{"type": "message", "content": gen.chatLine()}); err != nil {
+				closeAll(conns)
+				return sent, fmt.Errorf("failed to send chat message: %w", err)
+			}
+			sent++
+			time.Sleep(chatPause)
+		}
+		closeAll(conns)
+	}
+	return sent, nil
+}
+
+// chatTicket asks the server for the one-time ticket a member opens a room's WebSocket with
+func chatTicket(api, token, room string) (string, error) {
+	body, _ := json.Marshal(domain.CreateChatTicketRequest{Room: room})
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(api, "/")+"/api/v1/ws/ticket", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("invalid -api URL: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get chat ticket: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("failed to get chat ticket for room %s: server returned %s", room, resp.Status)
+	}
+	var ticket domain.ChatTicketResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ticket); err != nil {
+		return "", fmt.Errorf("failed to decode chat ticket: %w", err)
+	}
+	return ticket.Ticket, nil
+}
+
+func closeAll(conns map[uuid.UUID]*websocket.Conn) {
+	for _, conn := range conns {
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		conn.Close()
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"devjournal/internal/domain"
+)
+
+var (
+	firstNames = []string{"Ada", "Alan", "Grace", "Linus", "Margaret", "Ken", "Barbara", "Dennis", "Frances", "Guido", "Radia", "Rob", "Katherine", "Bjarne", "Hedy", "Anders"}
+	lastNames  = []string{"Lovelace", "Turing", "Hopper", "Torvalds", "Hamilton", "Thompson", "Liskov", "Ritchie", "Allen", "Rossum", "Perlman", "Pike", "Johnson", "Stroustrup", "Lamarr", "Hejlsberg"}
+
+	moods = []string{"excited", "productive", "frustrated", "confused", "accomplished", ""}
+
+	topics = []string{"goroutines", "channels", "generics", "Postgres indexes", "MongoDB aggregations", "Angular signals", "RxJS operators", "gRPC streaming", "WebSockets", "JWT auth", "Docker layers", "Kubernetes probes", "SQL window functions", "TypeScript types", "unit testing", "profiling"}
+	tags   = []string{"go", "typescript", "angular", "postgres", "mongodb", "docker", "testing", "performance", "grpc", "security", "til", "debugging"}
+
+	openers = []string{
+		"Spent most of today digging into %s.",
+		"Finally understood how %s work under the hood.",
+		"Hit a confusing bug involving %s.",
+		"Paired with a teammate on %s.",
+		"Read the docs on %s end to end.",
+	}
+	middles = []string{
+		"The key insight was that the simplest version was also the fastest.",
+		"I wrote a small benchmark and the numbers surprised me.",
+		"Stack Overflow was wrong, the spec was right.",
+		"Turns out the problem was a missing index all along.",
+		"I refactored the code twice before it clicked.",
+		"Tomorrow I want to try the same thing with a bigger dataset.",
+	}
+
+	languages   = []string{"go", "typescript", "python", "sql", "rust", "javascript"}
+	codeSamples = map[string][]string{
+		"go":         {"func sum(xs []int) int {\n\ttotal := 0\n\tfor _, x := range xs {\n\t\ttotal += x\n\t}\n\treturn total\n}", "ch := make(chan int)\ngo func() { ch <- 42 }()\nfmt.Println(<-ch)"},
+		"typescript": {"const unique = <T>(xs: T[]): T[] => [...new Set(xs)];", "export function debounce(fn: () => void, ms: number) {\n  let t: ReturnType<typeof setTimeout>;\n  return () => { clearTimeout(t); t = setTimeout(fn, ms); };\n}"},
+		"python":     {"def chunks(xs, n):\n    for i in range(0, len(xs), n):\n        yield xs[i:i + n]", "from collections import Counter\nprint(Counter('mississippi').most_common(2))"},
+		"sql":        {"SELECT user_id, COUNT(*)\nFROM journal_entries\nGROUP BY user_id\nORDER BY 2 DESC\nLIMIT 10;", "CREATE INDEX CONCURRENTLY idx_entries_user ON journal_entries (user_id, created_at DESC);"},
+		"rust":       {"fn main() {\n    let v: Vec<i32> = (1..=10).filter(|x| x % 2 == 0).collect();\n    println!(\"{:?}\", v);\n}"},
+		"javascript": {"const sleep = (ms) => new Promise((r) => setTimeout(r, ms));", "document.querySelectorAll('a').forEach((a) => a.setAttribute('rel', 'noopener'));"},
+	}
+	chatLines = []string{
+		"Has anyone tried the new release yet?",
+		"I just pushed my solution, feedback welcome",
+		"Does this query look right to you?",
+		"Pomodoro starting now, see you in 25",
+		"TIL you can range over an integer in Go 1.22",
+		"Anyone up for a pairing session tomorrow?",
+		"That talk on profiling was great",
+		"Stuck on a flaky test again",
+	}
+)
+
+// generator produces deterministic fake data for a given seed
+type generator struct {
+	rng *rand.Rand
+}
+
+func newGenerator(seed int64) *generator {
+	return &generator{rng: rand.New(rand.NewSource(seed))}
+}
+
+func (g *generator) pick(xs []string) string {
+	return xs[g.rng.Intn(len(xs))]
+}
+
+func (g *generator) displayName(i int) string {
+	return fmt.Sprintf("%s %s %d", g.pick(firstNames), g.pick(lastNames), i)
+}
+
+func (g *generator) tags() []string {
+	n := g.rng.Intn(4)
+	seen := make(map[string]bool, n)
+	out := []string{}
+	for len(out) < n {
+		tag := g.pick(tags)
+		if !seen[tag] {
+			seen[tag] = true
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// entry builds a journal entry request with a few paragraphs of prose
+func (g *generator) entry() *domain.CreateJournalEntryRequest {
+	topic := g.pick(topics)
+	var b strings.Builder
+	paragraphs := 1 + g.rng.Intn(3)
+	for p := 0; p < paragraphs; p++ {
+		if p > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, g.pick(openers), topic)
+		sentences := 2 + g.rng.Intn(4)
+		for s := 0; s < sentences; s++ {
+			b.WriteString(" ")
+			b.WriteString(g.pick(middles))
+		}
+	}
+	return &domain.CreateJournalEntryRequest{
+		Title:   fmt.Sprintf("Notes on %s", topic),
+		Content: b.String(),
+		Mood:    g.pick(moods),
+		Tags:    g.tags(),
+	}
+}
+
+// snippet builds a snippet request; about a third are public
+func (g *generator) snippet() *domain.CreateSnippetRequest {
+	language := g.pick(languages)
+	return &domain.CreateSnippetRequest{
+		Title:       fmt.Sprintf("%s: %s", language, g.pick(topics)),
+		Description: g.pick(middles),
+		Code:        g.pick(codeSamples[language]),
+		Language:    language,
+		Tags:        g.tags(),
+		IsPublic:    g.rng.Intn(3) == 0,
+	}
+}
+
+func (g *generator) groupName(i int) string {
+	topic := g.pick(topics)
+	return fmt.Sprintf("%s study group %d", strings.ToUpper(topic[:1])+topic[1:], i)
+}
+
+func (g *generator) chatLine() string {
+	return g.pick(chatLines)
+}
@@ -0,0 +1,268 @@
+// Command seed fills the databases with realistic fake users, journal entries, snippets,
+// study groups, and chat history for load testing.
+//
+//	go run ./cmd/seed -users 200 -entries 60 -snippets 20 -groups 25 -api http://localhost:8080
+//
+// It reads the same environment as the API server. Chat history lives in the server's
+// memory, so it is only seeded when -api points at a running server. The generated
+// credentials are written to -out for the load-test scenarios in loadtest/.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"devjournal/internal/config"
+	"devjournal/internal/database"
+	"devjournal/internal/domain"
+	"devjournal/internal/formatter"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// seedUser is a generated account as written to the credentials file
+type seedUser struct {
+	ID       uuid.UUID `json:"id"`
+	Email    string    `json:"email"`
+	Password string    `json:"password"`
+	Token    string    `json:"-"`
+}
+
+type options struct {
+	users, entries, snippets, groups, chat, days int
+	seed                                         int64
+	password, emailDomain, api, out              string
+}
+
+func main() {
+	var opts options
+	flag.IntVar(&opts.users, "users", 50, "number of users")
+	flag.IntVar(&opts.entries, "entries", 30, "journal entries per user")
+	flag.IntVar(&opts.snippets, "snippets", 10, "snippets per user")
+	flag.IntVar(&opts.groups, "groups", 10, "number of study groups")
+	flag.IntVar(&opts.chat, "chat", 50, "chat messages per group (needs -api)")
+	flag.IntVar(&opts.days, "days", 90, "spread journal entries over this many past days")
+	flag.Int64Var(&opts.seed, "seed", 1, "random seed; the same seed produces the same data")
+	flag.StringVar(&opts.password, "password", "loadtest-password", "password for every generated user")
+	flag.StringVar(&opts.emailDomain, "email-domain", "seed.devjournal.test", "email domain for generated users")
+	flag.StringVar(&opts.api, "api", "", "base URL of a running API server, used to seed chat history")
+	flag.StringVar(&opts.out, "out", "seed-users.json", "file the generated credentials are written to")
+	flag.Parse()
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	pgPool, err := database.NewPostgresPool(ctx, cfg.DbURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to PostgreSQL: %v", err)
+	}
+	defer pgPool.Close()
+
+	mongoClient, err := database.NewMongoClient(ctx, cfg.MongoURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer mongoClient.Disconnect(ctx)
+
+	userRepo := postgres.NewUserRepository(pgPool)
+	journalRepo := postgres.NewJournalRepository(pgPool)
+	progressRepo := postgres.NewProgressRepository(pgPool)
+	studyGroupRepo := postgres.NewStudyGroupRepository(pgPool)
+	workspaceRepo := postgres.NewWorkspaceRepository(pgPool)
+	snippetRepo := mongodb.NewSnippetRepository(mongoClient, cfg.MongoDB)
+
+	// Quotas are disabled so seeding is not limited by the free plan
+	quotaService := service.NewQuotaService(postgres.NewSubscriptionRepository(pgPool), workspaceRepo, snippetRepo, false)
+	s := &seeder{
+		opts:              opts,
+		gen:               newGenerator(opts.seed),
+		authService:       service.NewAuthService(userRepo, workspaceRepo, cfg.JWTSecret),
+		snippetService:    service.NewSnippetService(snippetRepo, postgres.NewEntrySnippetRepository(pgPool), postgres.NewSnippetCommentRepository(pgPool), quotaService, formatter.New("")),
+		studyGroupService: service.NewStudyGroupService(studyGroupRepo),
+		journalRepo:       journalRepo,
+		progressRepo:      progressRepo,
+	}
+
+	start := time.Now()
+	if err := s.run(ctx); err != nil {
+		log.Fatalf("Seeding failed: %v", err)
+	}
+	log.Printf("Seeding finished in %s", time.Since(start).Round(time.Millisecond))
+}
+
+// seeder creates the data set through the same services the API uses
+type seeder struct {
+	opts              options
+	gen               *generator
+	authService       *service.AuthService
+	snippetService    *service.SnippetService
+	studyGroupService *service.StudyGroupService
+	journalRepo       *postgres.JournalRepository
+	progressRepo      *postgres.ProgressRepository
+}
+
+func (s *seeder) run(ctx context.Context) error {
+	users, err := s.seedUsers(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("Seeded %d users", len(users))
+
+	for _, user := range users {
+		if err := s.seedEntries(ctx, user); err != nil {
+			return err
+		}
+		if err := s.seedSnippets(ctx, user); err != nil {
+			return err
+		}
+	}
+	log.Printf("Seeded %d journal entries and %d snippets", len(users)*s.opts.entries, len(users)*s.opts.snippets)
+
+	groups, err := s.seedGroups(ctx, users)
+	if err != nil {
+		return err
+	}
+	log.Printf("Seeded %d study groups", len(groups))
+
+	if s.opts.api != "" {
+		sent, err := seedChat(s.opts.api, groups, s.opts.chat, s.gen)
+		if err != nil {
+			return err
+		}
+		log.Printf("Seeded %d chat messages", sent)
+	} else if s.opts.chat > 0 {
+		log.Printf("Skipping chat history: pass -api with a running server to seed it")
+	}
+
+	return writeCredentials(s.opts.out, users)
+}
+
+// seedUsers registers the users, logging in instead when a previous run already created them
+func (s *seeder) seedUsers(ctx context.Context) ([]*seedUser, error) {
+	users := make([]*seedUser, 0, s.opts.users)
+	for i := 1; i <= s.opts.users; i++ {
+		email := fmt.Sprintf("user%d@%s", i, s.opts.emailDomain)
+		user, token, err := s.authService.Register(ctx, email, s.opts.password, s.gen.displayName(i))
+		if errors.Is(err, service.ErrEmailAlreadyExists) {
+			user, token, err = s.authService.Login(ctx, email, s.opts.password)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to seed user %s: %w", email, err)
+		}
+		users = append(users, &seedUser{ID: user.ID, Email: email, Password: s.opts.password, Token: token})
+	}
+	return users, nil
+}
+
+// seedEntries backdates entries over the last opts.days days and records the matching daily progress
+func (s *seeder) seedEntries(ctx context.Context, user *seedUser) error {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	perDay := make(map[time.Time]*domain.LearningProgress)
+
+	for i := 0; i < s.opts.entries; i++ {
+		req := s.gen.entry()
+		entry := domain.NewJournalEntry(user.ID, req.Title, req.Content, req.Mood, req.Tags)
+		day := today.AddDate(0, 0, -s.gen.rng.Intn(max(s.opts.days, 1)))
+		entry.CreatedAt = day.Add(time.Duration(s.gen.rng.Intn(24*60)) * time.Minute)
+		entry.UpdatedAt = entry.CreatedAt
+		if err := s.journalRepo.Create(ctx, entry); err != nil {
+			return fmt.Errorf("failed to seed journal entry: %w", err)
+		}
+
+		progress, ok := perDay[day]
+		if !ok {
+			progress = domain.NewLearningProgress(user.ID, day)
+			perDay[day] = progress
+		}
+		progress.EntriesCount++
+		progress.TotalLearningTime += 15 + s.gen.rng.Intn(45)
+	}
+
+	for _, progress := range perDay {
+		if err := s.progressRepo.Upsert(ctx, progress); err != nil {
+			return fmt.Errorf("failed to seed progress: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *seeder) seedSnippets(ctx context.Context, user *seedUser) error {
+	for i := 0; i < s.opts.snippets; i++ {
+		req := s.gen.snippet()
+		// Seeded code holds no real credentials, so lookalike findings must not block publishing
+		req.AcknowledgeSecrets = true
+		// Generated snippets reuse a few code samples, so many are duplicates of each other
+		req.AllowDuplicate = true
+		if _, err := s.snippetService.Create(ctx, user.ID.String(), req); err != nil {
+			return fmt.Errorf("failed to seed snippet: %w", err)
+		}
+	}
+	return nil
+}
+
+// seedGroups creates groups owned by random users and fills each with random members
+func (s *seeder) seedGroups(ctx context.Context, users []*seedUser) ([]*seedGroup, error) {
+	groups := make([]*seedGroup, 0, s.opts.groups)
+	if len(users) == 0 {
+		return groups, nil
+	}
+
+	for i := 1; i <= s.opts.groups; i++ {
+		owner := users[s.gen.rng.Intn(len(users))]
+		maxMembers := 5 + s.gen.rng.Intn(20)
+		group, err := s.studyGroupService.Create(ctx, owner.ID, &service.CreateGroupRequest{
+			Name:        s.gen.groupName(i),
+			Description: s.gen.pick(middles),
+			IsPublic:    s.gen.rng.Intn(4) != 0,
+			MaxMembers:  maxMembers,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to seed study group: %w", err)
+		}
+
+		// Private groups are joined with an invite from the owner
+		members := []*seedUser{owner}
+		for _, idx := range s.gen.rng.Perm(len(users))[:min(maxMembers-1, len(users))] {
+			member := users[idx]
+			if member == owner {
+				continue
+			}
+			var code string
+			if !group.IsPublic {
+				invite, err := s.studyGroupService.CreateInvite(ctx, group.ID, owner.ID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to seed group invite: %w", err)
+				}
+				code = invite.Code
+			}
+			if err := s.studyGroupService.Join(ctx, group.ID, member.ID, code); err != nil {
+				return nil, fmt.Errorf("failed to seed group member: %w", err)
+			}
+			members = append(members, member)
+		}
+		groups = append(groups, &seedGroup{ID: group.ID, Members: members})
+	}
+	return groups, nil
+}
+
+// writeCredentials saves the generated logins for the load-test scenarios
+func writeCredentials(path string, users []*seedUser) error {
+	data, err := json.MarshalIndent(users, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+	log.Printf("Wrote %d credentials to %s", len(users), path)
+	return nil
+}
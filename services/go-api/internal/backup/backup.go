@@ -0,0 +1,223 @@
+// Package backup writes backups of the Postgres and Mongo databases to a single archive, and
+// verifies that an archive can be restored.
+//
+// An archive is a gzipped tar holding manifest.json, then each Postgres table as
+// postgres/<table>.csv (COPY's CSV format, with a header) and each Mongo collection as
+// mongo/<collection>.jsonl (one canonical Extended JSON document per line).
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// manifestFile is the archive's first file
+const manifestFile = "manifest.json"
+
+// Dumper writes backups of a Postgres database and a Mongo database
+type Dumper struct {
+	pool    *pgxpool.Pool
+	mongoDB *mongo.Database
+}
+
+// NewDumper creates a dumper for the given databases
+func NewDumper(pool *pgxpool.Pool, mongoDB *mongo.Database) *Dumper {
+	return &Dumper{pool: pool, mongoDB: mongoDB}
+}
+
+// Write writes a backup archive to w and returns its manifest. Parts are staged in a temporary
+// directory first, since tar needs each file's size up front.
+func (d *Dumper) Write(ctx context.Context, w io.Writer) (*domain.BackupManifest, error) {
+	dir, err := os.MkdirTemp("", "devjournal-backup-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifest := &domain.BackupManifest{Version: domain.BackupFormatVersion}
+	if err := d.dumpPostgres(ctx, dir, manifest); err != nil {
+		return nil, err
+	}
+	if err := d.dumpMongo(ctx, dir, manifest); err != nil {
+		return nil, err
+	}
+	if err := writeArchive(w, dir, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// dumpPostgres copies every table out of one read-only snapshot, and records when it was taken
+func (d *Dumper) dumpPostgres(ctx context.Context, dir string, manifest *domain.BackupManifest) error {
+	tx, err := d.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("failed to begin snapshot: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// The first query fixes the snapshot, and now() is the transaction's start
+	if err := tx.QueryRow(ctx, `SELECT now()`).Scan(&manifest.SnapshotAt); err != nil {
+		return fmt.Errorf("failed to take snapshot: %w", err)
+	}
+	manifest.SnapshotAt = manifest.SnapshotAt.UTC()
+
+	rows, err := tx.Query(ctx, `SELECT tablename FROM pg_tables WHERE schemaname = 'public' ORDER BY tablename`)
+	if err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	for _, table := range tables {
+		copyOut := fmt.Sprintf(`COPY %s TO STDOUT WITH (FORMAT csv, HEADER true)`, pgx.Identifier{table}.Sanitize())
+		part, err := writePart(dir, "postgres/"+table+".csv", table, func(w io.Writer) (int64, error) {
+			tag, err := tx.Conn().PgConn().CopyTo(ctx, w, copyOut)
+			return tag.RowsAffected(), err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to dump table %s: %w", table, err)
+		}
+		manifest.Postgres = append(manifest.Postgres, part)
+	}
+	return nil
+}
+
+// dumpMongo writes every collection, leaving out documents created after the Postgres snapshot.
+// The reads aren't in a snapshot (that needs a replica set), so documents edited or deleted since,
+// and those without created_at, are written as they are now.
+func (d *Dumper) dumpMongo(ctx context.Context, dir string, manifest *domain.BackupManifest) error {
+	collections, err := d.mongoDB.ListCollectionNames(ctx, bson.D{{Key: "type", Value: "collection"}})
+	if err != nil {
+		return fmt.Errorf("failed to list collections: %w", err)
+	}
+	sort.Strings(collections)
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"created_at": bson.M{"$lte": manifest.SnapshotAt}},
+		bson.M{"created_at": bson.M{"$exists": false}},
+	}}
+	for _, collection := range collections {
+		part, err := writePart(dir, "mongo/"+collection+".jsonl", collection, func(w io.Writer) (int64, error) {
+			cursor, err := d.mongoDB.Collection(collection).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+			if err != nil {
+				return 0, err
+			}
+			defer cursor.Close(ctx)
+
+			var count int64
+			for cursor.Next(ctx) {
+				line, err := bson.MarshalExtJSON(cursor.Current, true, false)
+				if err != nil {
+					return count, err
+				}
+				if _, err := w.Write(append(line, '\n')); err != nil {
+					return count, err
+				}
+				count++
+			}
+			return count, cursor.Err()
+		})
+		if err != nil {
+			return fmt.Errorf("failed to dump collection %s: %w", collection, err)
+		}
+		manifest.Mongo = append(manifest.Mongo, part)
+	}
+	return nil
+}
+
+// writePart stages one table or collection in dir with dump, which returns how many rows it
+// wrote, and describes it for the manifest
+func writePart(dir, file, name string, dump func(w io.Writer) (int64, error)) (domain.BackupPart, error) {
+	part := domain.BackupPart{Name: name, File: file}
+	path := filepath.Join(dir, filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return part, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return part, err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if part.Rows, err = dump(io.MultiWriter(f, hash)); err != nil {
+		return part, err
+	}
+	part.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return part, f.Close()
+}
+
+// writeArchive writes the manifest, then the staged parts in manifest order, as a gzipped tar
+func writeArchive(w io.Writer, dir string, manifest *domain.BackupManifest) error {
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	header := &tar.Header{Name: manifestFile, Mode: 0o600, Size: int64(len(data)), ModTime: manifest.SnapshotAt}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	for _, part := range append(manifest.Postgres, manifest.Mongo...) {
+		if err := addFile(tw, dir, part.File, manifest.SnapshotAt); err != nil {
+			return fmt.Errorf("failed to write %s: %w", part.File, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return zw.Close()
+}
+
+// addFile copies a staged file into the archive
+func addFile(tw *tar.Writer, dir, file string, modTime time.Time) error {
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(file)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: file, Mode: 0o600, Size: info.Size(), ModTime: modTime}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
@@ -0,0 +1,78 @@
+package backup
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"devjournal/internal/domain"
+)
+
+// testArchive builds an archive from table and collection contents, as Dumper.Write lays it out
+func testArchive(t *testing.T, tables, collections map[string]string, edit func(*domain.BackupManifest)) *bytes.Buffer {
+	t.Helper()
+	dir := t.TempDir()
+	manifest := &domain.BackupManifest{Version: domain.BackupFormatVersion, SnapshotAt: time.Now().UTC()}
+	add := func(parts *[]domain.BackupPart, prefix, ext string, contents map[string]string, rows func(string) int64) {
+		for name, content := range contents {
+			part, err := writePart(dir, prefix+name+ext, name, func(w io.Writer) (int64, error) {
+				_, err := io.WriteString(w, content)
+				return rows(content), err
+			})
+			if err != nil {
+				t.Fatalf("writePart(%s): %v", name, err)
+			}
+			*parts = append(*parts, part)
+		}
+	}
+	add(&manifest.Postgres, "postgres/", ".csv", tables, func(s string) int64 { return int64(strings.Count(s, "\n") - 1) })
+	add(&manifest.Mongo, "mongo/", ".jsonl", collections, func(s string) int64 { return int64(strings.Count(s, "\n")) })
+	if edit != nil {
+		edit(manifest)
+	}
+
+	var buf bytes.Buffer
+	if err := writeArchive(&buf, dir, manifest); err != nil {
+		t.Fatalf("writeArchive: %v", err)
+	}
+	return &buf
+}
+
+func TestVerify(t *testing.T) {
+	tables := map[string]string{
+		"users":          "id,email\n1,ada@devjournal.test\n2,\"grace,hopper@devjournal.test\"\n",
+		"entry_snippets": "entry_id,snippet_id\ne1,65f000000000000000000001\ne2,65f000000000000000000002\n",
+	}
+	collections := map[string]string{
+		"snippets": `{"_id":{"$oid":"65f000000000000000000001"},"title":"Binary search"}` + "\n",
+	}
+
+	result := Verify(testArchive(t, tables, collections, nil))
+	if !result.Valid || len(result.Manifest.Postgres) != 2 || len(result.Manifest.Mongo) != 1 {
+		t.Fatalf("Verify = %+v, want a valid archive", result)
+	}
+	if len(result.Dangling) != 1 || result.Dangling[0].Table != "entry_snippets" || result.Dangling[0].Count != 1 {
+		t.Fatalf("Dangling = %+v, want the link to the missing snippet", result.Dangling)
+	}
+
+	corrupt := map[string]func(*domain.BackupManifest){
+		"checksum":  func(m *domain.BackupManifest) { m.Postgres[0].SHA256 = strings.Repeat("0", 64) },
+		"row count": func(m *domain.BackupManifest) { m.Mongo[0].Rows++ },
+		"version":   func(m *domain.BackupManifest) { m.Version++ },
+	}
+	for name, edit := range corrupt {
+		if result := Verify(testArchive(t, tables, collections, edit)); result.Valid || result.Error == "" {
+			t.Errorf("Verify with a bad %s = %+v, want invalid", name, result)
+		}
+	}
+
+	broken := map[string]string{"snippets": "{not json}\n"}
+	if result := Verify(testArchive(t, tables, broken, nil)); result.Valid || !strings.Contains(result.Error, "mongo/snippets.jsonl") {
+		t.Errorf("Verify with an unparsable document = %+v, want invalid", result)
+	}
+	if result := Verify(strings.NewReader("not an archive")); result.Valid {
+		t.Error("Verify accepted something that isn't an archive")
+	}
+}
@@ -0,0 +1,239 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"devjournal/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxDocumentLine is the longest Extended JSON line read, well over Mongo's 16 MB document limit
+const maxDocumentLine = 64 << 20
+
+// reference is a Postgres column holding IDs of documents in a Mongo collection, which no
+// foreign key keeps in step
+type reference struct {
+	table, column, collection string
+}
+
+// references are checked by Verify; empty values don't refer to anything
+var references = []reference{
+	{"entry_snippets", "snippet_id", "snippets"},
+	{"code_reviews", "snippet_id", "snippets"},
+	{"snippet_comments", "snippet_id", "snippets"},
+	{"problems", "snippet_id", "snippets"},
+	{"quiz_attempts", "quiz_id", "quizzes"},
+}
+
+// errCorrupt marks problems with the archive's contents, as opposed to reading it
+var errCorrupt = errors.New("backup is corrupt")
+
+// Verify reads a whole backup archive and checks it can be restored: the manifest is readable
+// and of a supported version, every part it lists is there once with the recorded checksum and
+// row count, every CSV row and Extended JSON document parses, and nothing else is in the
+// archive. References from Postgres to Mongo documents the archive doesn't hold are counted,
+// but don't make it invalid.
+func Verify(r io.Reader) *domain.BackupVerification {
+	v := &verifier{ids: make(map[string]map[string]bool), refs: make(map[reference][]string)}
+	result := &domain.BackupVerification{Dangling: []domain.DanglingReference{}}
+	if err := v.verify(r); err != nil {
+		result.Error = err.Error()
+		result.Manifest = v.manifest
+		return result
+	}
+	result.Valid = true
+	result.Manifest = v.manifest
+	result.Dangling = v.dangling()
+	return result
+}
+
+// verifier keeps what Verify learned about an archive so far
+type verifier struct {
+	manifest *domain.BackupManifest
+	ids      map[string]map[string]bool // document IDs of referenced collections
+	refs     map[reference][]string     // values of referencing columns
+}
+
+func (v *verifier) verify(r io.Reader) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("%w: not a gzip archive: %v", errCorrupt, err)
+	}
+	tr := tar.NewReader(zr)
+
+	header, err := tr.Next()
+	if err != nil || header.Name != manifestFile {
+		return fmt.Errorf("%w: %s must come first", errCorrupt, manifestFile)
+	}
+	if err := json.NewDecoder(tr).Decode(&v.manifest); err != nil {
+		return fmt.Errorf("%w: unreadable manifest: %v", errCorrupt, err)
+	}
+	if v.manifest.Version != domain.BackupFormatVersion {
+		return fmt.Errorf("%w: format version %d, this build reads %d", errCorrupt, v.manifest.Version, domain.BackupFormatVersion)
+	}
+
+	pending := make(map[string]domain.BackupPart)
+	for _, part := range append(slices.Clone(v.manifest.Postgres), v.manifest.Mongo...) {
+		pending[part.File] = part
+	}
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", errCorrupt, err)
+		}
+		part, ok := pending[header.Name]
+		if !ok {
+			return fmt.Errorf("%w: unexpected file %s", errCorrupt, header.Name)
+		}
+		delete(pending, header.Name)
+		if err := v.verifyPart(tr, part); err != nil {
+			return fmt.Errorf("%w: %s: %v", errCorrupt, part.File, err)
+		}
+	}
+	for file := range pending {
+		return fmt.Errorf("%w: %s is missing", errCorrupt, file)
+	}
+	return nil
+}
+
+// verifyPart parses one part, and checks its checksum and row count against the manifest
+func (v *verifier) verifyPart(r io.Reader, part domain.BackupPart) error {
+	hash := sha256.New()
+	tee := io.TeeReader(r, hash)
+
+	var rows int64
+	var err error
+	switch {
+	case strings.HasPrefix(part.File, "postgres/"):
+		rows, err = v.readTable(tee, part.Name)
+	case strings.HasPrefix(part.File, "mongo/"):
+		rows, err = v.readCollection(tee, part.Name)
+	default:
+		err = errors.New("not a table or collection")
+	}
+	if err != nil {
+		return err
+	}
+	// The checksum covers the whole file, whatever the parser left unread
+	if _, err := io.Copy(io.Discard, tee); err != nil {
+		return err
+	}
+
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != part.SHA256 {
+		return fmt.Errorf("checksum %s, manifest has %s", sum, part.SHA256)
+	}
+	if rows != part.Rows {
+		return fmt.Errorf("%d rows, manifest has %d", rows, part.Rows)
+	}
+	return nil
+}
+
+// readTable parses a table's CSV, keeping the values of columns referencing Mongo documents
+func (v *verifier) readTable(r io.Reader, table string) (int64, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return 0, errors.New("no header")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	columns := make(map[reference]int)
+	for _, ref := range references {
+		if ref.table == table {
+			if i := slices.Index(header, ref.column); i >= 0 {
+				columns[ref] = i
+			}
+		}
+	}
+
+	var rows int64
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, err
+		}
+		rows++
+		for ref, i := range columns {
+			if record[i] != "" {
+				v.refs[ref] = append(v.refs[ref], record[i])
+			}
+		}
+	}
+}
+
+// readCollection parses a collection's documents, keeping the IDs of referenced collections
+func (v *verifier) readCollection(r io.Reader, collection string) (int64, error) {
+	var ids map[string]bool
+	for _, ref := range references {
+		if ref.collection == collection {
+			ids = make(map[string]bool)
+			v.ids[collection] = ids
+			break
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxDocumentLine)
+	var rows int64
+	for scanner.Scan() {
+		var doc bson.D
+		if err := bson.UnmarshalExtJSON(scanner.Bytes(), true, &doc); err != nil {
+			return rows, fmt.Errorf("document %d: %v", rows+1, err)
+		}
+		rows++
+		if ids == nil {
+			continue
+		}
+		for _, field := range doc {
+			if field.Key != "_id" {
+				continue
+			}
+			switch id := field.Value.(type) {
+			case primitive.ObjectID:
+				ids[id.Hex()] = true
+			case string:
+				ids[id] = true
+			}
+		}
+	}
+	return rows, scanner.Err()
+}
+
+// dangling counts the references to documents the archive doesn't hold
+func (v *verifier) dangling() []domain.DanglingReference {
+	dangling := []domain.DanglingReference{}
+	for _, ref := range references {
+		count := 0
+		for _, id := range v.refs[ref] {
+			if !v.ids[ref.collection][id] {
+				count++
+			}
+		}
+		if count > 0 {
+			dangling = append(dangling, domain.DanglingReference{Table: ref.table, Column: ref.column, Collection: ref.collection, Count: count})
+		}
+	}
+	return dangling
+}
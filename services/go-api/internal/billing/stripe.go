@@ -0,0 +1,174 @@
+// Package billing is a minimal Stripe client covering checkout sessions and webhook verification
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"devjournal/pkg/apperr"
+)
+
+const stripeAPI = "https://api.stripe.com/v1"
+
+// webhookTolerance is how old a webhook signature timestamp may be before it is rejected as a replay
+const webhookTolerance = 5 * time.Minute
+
+// ErrInvalidSignature is returned when a webhook's Stripe-Signature header does not verify
+var ErrInvalidSignature = apperr.New(apperr.ErrValidation, "invalid webhook signature")
+
+// Client calls the Stripe REST API
+type Client struct {
+	secretKey  string
+	httpClient *http.Client
+}
+
+// NewClient creates a Stripe client authenticated with a secret key
+func NewClient(secretKey string) *Client {
+	return &Client{
+		secretKey:  secretKey,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// CheckoutParams describes a subscription checkout session
+type CheckoutParams struct {
+	PriceID           string
+	CustomerID        string // existing Stripe customer, if any
+	CustomerEmail     string // used when there is no customer yet
+	ClientReferenceID string
+	SuccessURL        string
+	CancelURL         string
+	Metadata          map[string]string // copied onto the session and the subscription
+}
+
+// CheckoutSession is the subset of a Stripe checkout session the API returns to clients
+type CheckoutSession struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// CreateCheckoutSession starts a hosted checkout for a subscription
+func (c *Client) CreateCheckoutSession(ctx context.Context, p CheckoutParams) (*CheckoutSession, error) {
+	form := url.Values{}
+	form.Set("mode", "subscription")
+	form.Set("line_items[0][price]", p.PriceID)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("success_url", p.SuccessURL)
+	form.Set("cancel_url", p.CancelURL)
+	form.Set("client_reference_id", p.ClientReferenceID)
+	if p.CustomerID != "" {
+		form.Set("customer", p.CustomerID)
+	} else if p.CustomerEmail != "" {
+		form.Set("customer_email", p.CustomerEmail)
+	}
+	for k, v := range p.Metadata {
+		form.Set("metadata["+k+"]", v)
+		form.Set("subscription_data[metadata]["+k+"]", v)
+	}
+
+	var session CheckoutSession
+	if err := c.post(ctx, "/checkout/sessions", form, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (c *Client) post(ctx context.Context, path string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, stripeAPI+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.secretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read stripe response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.Unmarshal(body, &apiErr)
+		return fmt.Errorf("stripe returned %d: %s", resp.StatusCode, apiErr.Error.Message)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// Event is a Stripe webhook event
+type Event struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// ConstructEvent verifies a webhook payload against its Stripe-Signature header and parses it
+func ConstructEvent(payload []byte, sigHeader, secret string) (*Event, error) {
+	if err := verifySignature(payload, sigHeader, secret, time.Now()); err != nil {
+		return nil, err
+	}
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	return &event, nil
+}
+
+// verifySignature checks the header's "t=<unix>,v1=<hex hmac>" against HMAC-SHA256("<t>.<payload>")
+func verifySignature(payload []byte, sigHeader, secret string, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(sigHeader, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > webhookTolerance || age < -webhookTolerance {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	for _, sig := range signatures {
+		decoded, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
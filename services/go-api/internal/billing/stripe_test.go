@@ -0,0 +1,67 @@
+package billing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testPayload = `{"id": "evt_1", "type": "checkout.session.completed", "data": {"object": {"id": "cs_1"}}}`
+
+// sign returns the hex HMAC Stripe sends for a payload signed at t
+func sign(secret string, t time.Time, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(t.Unix(), 10) + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Unix(1714550400, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	valid := sign("whsec_test", now, testPayload)
+
+	tests := []struct {
+		name    string
+		payload string
+		header  string
+		wantErr bool
+	}{
+		{name: "valid", payload: testPayload, header: "t=" + ts + ",v1=" + valid},
+		{name: "spaces and other schemes", payload: testPayload, header: "t=" + ts + ", v0=ignored, v1=" + valid},
+		{name: "one of several v1 values", payload: testPayload, header: "t=" + ts + ",v1=" + sign("whsec_old", now, testPayload) + ",v1=" + valid},
+		{name: "wrong secret", payload: testPayload, header: "t=" + ts + ",v1=" + sign("whsec_other", now, testPayload), wantErr: true},
+		{name: "tampered payload", payload: `{"id": "evt_1", "type": "customer.subscription.updated"}`, header: "t=" + ts + ",v1=" + valid, wantErr: true},
+		{name: "stale timestamp", payload: testPayload, header: "t=" + strconv.FormatInt(now.Add(-6*time.Minute).Unix(), 10) + ",v1=" + sign("whsec_test", now.Add(-6*time.Minute), testPayload), wantErr: true},
+		{name: "future timestamp", payload: testPayload, header: "t=" + strconv.FormatInt(now.Add(6*time.Minute).Unix(), 10) + ",v1=" + sign("whsec_test", now.Add(6*time.Minute), testPayload), wantErr: true},
+		{name: "timestamp changed after signing", payload: testPayload, header: "t=" + strconv.FormatInt(now.Add(time.Minute).Unix(), 10) + ",v1=" + valid, wantErr: true},
+		{name: "missing timestamp", payload: testPayload, header: "v1=" + valid, wantErr: true},
+		{name: "non-numeric timestamp", payload: testPayload, header: "t=yesterday,v1=" + valid, wantErr: true},
+		{name: "missing signature", payload: testPayload, header: "t=" + ts, wantErr: true},
+		{name: "non-hex signature", payload: testPayload, header: "t=" + ts + ",v1=not-hex-" + valid[9:], wantErr: true},
+		{name: "empty header", payload: testPayload, header: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		err := verifySignature([]byte(tt.payload), tt.header, "whsec_test", now)
+		if tt.wantErr && err != ErrInvalidSignature {
+			t.Errorf("%s: verifySignature = %v, want ErrInvalidSignature", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: verifySignature = %v", tt.name, err)
+		}
+	}
+}
+
+func TestConstructEvent(t *testing.T) {
+	now := time.Now()
+	header := "t=" + strconv.FormatInt(now.Unix(), 10) + ",v1=" + sign("whsec_test", now, testPayload)
+	event, err := ConstructEvent([]byte(testPayload), header, "whsec_test")
+	if err != nil || event.ID != "evt_1" || event.Type != "checkout.session.completed" {
+		t.Fatalf("ConstructEvent = %+v, %v", event, err)
+	}
+	if _, err := ConstructEvent([]byte(testPayload), header, "whsec_other"); err != ErrInvalidSignature {
+		t.Fatalf("ConstructEvent with the wrong secret = %v, want ErrInvalidSignature", err)
+	}
+}
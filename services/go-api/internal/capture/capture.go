@@ -0,0 +1,161 @@
+// Package capture works out what text sent to the quick-capture inbox is: a journal entry or a
+// snippet, with a title and suggested tags
+package capture
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+)
+
+const (
+	// MaxTags caps the tags suggested for one capture
+	MaxTags = 10
+
+	// maxTitleLength is the longest suggested title, in characters
+	maxTitleLength = 100
+)
+
+// hashtag matches #tags in prose; Markdown headings ("# Title") and anchors in links don't match
+var hashtag = regexp.MustCompile(`(?:^|[\s(])#([\p{L}\p{N}_/-]*[\p{L}_/-][\p{L}\p{N}_/-]*)`)
+
+// link matches http and https URLs in text, up to whitespace or a closing bracket or quote
+var link = regexp.MustCompile("https?://[^\\s<>()\\[\\]\"'`]+")
+
+// keywords maps words that name a technology to the tag suggested for it. Words that are also
+// common English, such as "go", "rust", or "react", are left out.
+var keywords = map[string]string{
+	"golang": "go", "python": "python", "javascript": "javascript", "typescript": "typescript",
+	"rustlang": "rust", "kotlin": "kotlin", "java": "java", "haskell": "haskell", "elixir": "elixir",
+	"reactjs": "react", "vue": "vue", "angular": "angular", "svelte": "svelte", "nextjs": "nextjs",
+	"nodejs": "nodejs", "django": "django", "laravel": "laravel", "graphql": "graphql",
+	"docker": "docker", "kubernetes": "kubernetes", "k8s": "kubernetes", "terraform": "terraform",
+	"aws": "aws", "gcp": "gcp", "azure": "azure", "linux": "linux", "git": "git", "regex": "regex",
+	"sql": "sql", "postgres": "postgres", "postgresql": "postgres", "mysql": "mysql", "sqlite": "sqlite",
+	"mongodb": "mongodb", "redis": "redis", "kafka": "kafka", "css": "css", "html": "html", "wasm": "wasm",
+}
+
+// Kind returns the kind of capture content suits: a snippet when it is nothing but one fenced code
+// block, and a journal entry otherwise
+func Kind(content string) string {
+	if _, ok := soleCodeBlock(content); ok {
+		return domain.CaptureSnippet
+	}
+	return domain.CaptureEntry
+}
+
+// Snippet returns the code and language of content captured as a snippet: the code inside its
+// fence if it is one fenced code block, or the content as is with no language
+func Snippet(content string) (code, language string) {
+	if block, ok := soleCodeBlock(content); ok {
+		return block.Code, block.Language
+	}
+	return strings.TrimSpace(content), ""
+}
+
+// soleCodeBlock returns the code block that makes up all of content, apart from blank lines
+func soleCodeBlock(content string) (domain.CodeBlock, bool) {
+	blocks := domain.ExtractCodeBlocks(content)
+	if len(blocks) != 1 || strings.TrimSpace(blocks[0].Code) == "" {
+		return domain.CodeBlock{}, false
+	}
+	block := blocks[0]
+	// The block runs from its opening fence past its code to the closing fence, if there is one
+	last := block.Line + strings.Count(block.Code, "\n") + 2
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if n := i + 1; (n < block.Line || n > last) && strings.TrimSpace(line) != "" {
+			return domain.CodeBlock{}, false
+		}
+	}
+	return block, true
+}
+
+// Title suggests a title from the first line of prose in content, without Markdown heading,
+// quote, or list markers, shortened at a word to at most maxTitleLength characters. It returns
+// an empty string if content has no prose.
+func Title(content string) string {
+	for _, line := range prose(content) {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#>*-+ \t"))
+		if line == "" {
+			continue
+		}
+		if utf8.RuneCountInString(line) <= maxTitleLength {
+			return line
+		}
+		cut := string([]rune(line)[:maxTitleLength-1])
+		if i := strings.LastIndexByte(cut, ' '); i > maxTitleLength/2 {
+			cut = cut[:i]
+		}
+		return strings.TrimRightFunc(cut, unicode.IsPunct) + "…"
+	}
+	return ""
+}
+
+// Tags suggests tags for captured content: the capture tag and source, then #tags in its prose,
+// the languages of its code blocks, and technologies its prose mentions, up to MaxTags in all
+func Tags(source, content string) []string {
+	tags := []string{domain.CaptureTag}
+	add := func(tag string) {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && len(tags) < MaxTags && !contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	add(source)
+
+	lines := prose(content)
+	for _, line := range lines {
+		for _, m := range hashtag.FindAllStringSubmatch(line, -1) {
+			add(m[1])
+		}
+	}
+	for _, block := range domain.ExtractCodeBlocks(content) {
+		add(block.Language)
+	}
+	for _, line := range lines {
+		words := strings.FieldsFunc(strings.ToLower(line), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range words {
+			if tag, ok := keywords[word]; ok {
+				add(tag)
+			}
+		}
+	}
+	return tags
+}
+
+// Link returns the first http or https link in content, without trailing punctuation, or an
+// empty string if there is none
+func Link(content string) string {
+	return strings.TrimRight(link.FindString(content), ".,;:!?")
+}
+
+// prose returns the lines of content outside fenced code blocks
+func prose(content string) []string {
+	var lines []string
+	inFence := false
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
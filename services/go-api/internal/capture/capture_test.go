@@ -0,0 +1,92 @@
+package capture
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"devjournal/internal/domain"
+)
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"prose", "Learned how context cancellation works", domain.CaptureEntry},
+		{"one code block", "\n```go\nfunc main() {}\n```\n", domain.CaptureSnippet},
+		{"unclosed code block", "```py\nprint(1)", domain.CaptureSnippet},
+		{"code with prose", "Use this:\n```go\nfunc main() {}\n```", domain.CaptureEntry},
+		{"two code blocks", "```go\na()\n```\n```go\nb()\n```", domain.CaptureEntry},
+		{"empty code block", "```go\n```", domain.CaptureEntry},
+	}
+	for _, tt := range tests {
+		if got := Kind(tt.content); got != tt.want {
+			t.Errorf("%s: Kind = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	code, language := Snippet("```golang\nfunc main() {}\n```")
+	if code != "func main() {}" || language != "go" {
+		t.Errorf("Snippet(fenced) = %q, %q", code, language)
+	}
+	code, language = Snippet("  SELECT 1;\n")
+	if code != "SELECT 1;" || language != "" {
+		t.Errorf("Snippet(unfenced) = %q, %q", code, language)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"## Goroutine leaks\n\nThey happen when...", "Goroutine leaks"},
+		{"\n> Quoted answer", "Quoted answer"},
+		{"```go\nx()\n```\n- list item", "list item"},
+		{"```go\nx()\n```", ""},
+	}
+	for _, tt := range tests {
+		if got := Title(tt.content); got != tt.want {
+			t.Errorf("Title(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+
+	long := strings.Repeat("word ", 40)
+	got := Title(long)
+	if !strings.HasSuffix(got, "word…") || len([]rune(got)) > maxTitleLength {
+		t.Errorf("Title(long) = %q, want it cut at a word to at most %d characters", got, maxTitleLength)
+	}
+}
+
+func TestTags(t *testing.T) {
+	content := "Asked about #concurrency in Golang with Docker.\n```python\n# not a tag, and docker here is code\n```\nAlso Kubernetes and k8s."
+	want := []string{"capture", "chatgpt", "concurrency", "python", "go", "docker", "kubernetes"}
+	if got := Tags("chatgpt", content); !reflect.DeepEqual(got, want) {
+		t.Errorf("Tags = %v, want %v", got, want)
+	}
+
+	many := "#a #b #c #d #e #f #g #h #i #j #k"
+	if got := Tags("browser-extension", many); len(got) != MaxTags {
+		t.Errorf("Tags(many) has %d tags, want %d", len(got), MaxTags)
+	}
+}
+
+func TestLink(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"See https://go.dev/blog/pipelines.", "https://go.dev/blog/pipelines"},
+		{"[docs](http://example.com/a?b=c) and https://other.example", "http://example.com/a?b=c"},
+		{"no links, just ftp://files.example", ""},
+	}
+	for _, tt := range tests {
+		if got := Link(tt.content); got != tt.want {
+			t.Errorf("Link(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
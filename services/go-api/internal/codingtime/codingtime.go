@@ -0,0 +1,228 @@
+// Package codingtime reads coding time from the WakaTime API, and totals the heartbeats
+// WakaTime-compatible editor plugins send into time per day and language
+package codingtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/pkg/apperr"
+)
+
+// ErrInvalidAPIKey is returned when WakaTime refuses an API key
+var ErrInvalidAPIKey = apperr.New(apperr.ErrValidation, "WakaTime did not accept the API key")
+
+const (
+	// HeartbeatTimeout is the longest gap between heartbeats still counted as coding, matching
+	// WakaTime's default keystroke timeout
+	HeartbeatTimeout = 15 * time.Minute
+
+	// MaxHeartbeats caps the heartbeats accepted in one request; plugins send at most 25
+	MaxHeartbeats = 1000
+
+	// maxLanguageLength caps language names, which come from the client
+	maxLanguageLength = 100
+
+	// otherLanguage is used for heartbeats without a language
+	otherLanguage = "Other"
+)
+
+// WakaTime reads daily summaries from the WakaTime API, or a compatible server such as Wakapi
+type WakaTime struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewWakaTime creates a WakaTime client for an API base URL, e.g. https://wakatime.com/api/v1
+func NewWakaTime(baseURL string) *WakaTime {
+	return &WakaTime{baseURL: strings.TrimSuffix(baseURL, "/"), client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Verify checks that an API key is accepted
+func (w *WakaTime) Verify(ctx context.Context, apiKey string) error {
+	return w.get(ctx, apiKey, "/users/current", nil, &struct{}{})
+}
+
+// Summaries returns the coding time of each day from start to end, inclusive
+func (w *WakaTime) Summaries(ctx context.Context, apiKey string, start, end time.Time) ([]domain.CodingDay, error) {
+	q := url.Values{}
+	q.Set("start", start.Format("2006-01-02"))
+	q.Set("end", end.Format("2006-01-02"))
+
+	var resp struct {
+		Data []struct {
+			Range struct {
+				Date string `json:"date"`
+			} `json:"range"`
+			Languages []struct {
+				Name         string  `json:"name"`
+				TotalSeconds float64 `json:"total_seconds"`
+			} `json:"languages"`
+		} `json:"data"`
+	}
+	if err := w.get(ctx, apiKey, "/users/current/summaries", q, &resp); err != nil {
+		return nil, err
+	}
+
+	days := make([]domain.CodingDay, 0, len(resp.Data))
+	for _, d := range resp.Data {
+		date, err := time.Parse("2006-01-02", d.Range.Date)
+		if err != nil {
+			return nil, fmt.Errorf("wakatime summary has invalid date %q", d.Range.Date)
+		}
+		day := domain.CodingDay{Date: date, Seconds: make(map[string]int)}
+		for _, l := range d.Languages {
+			if seconds := int(math.Round(l.TotalSeconds)); seconds > 0 {
+				day.Seconds[language(l.Name)] += seconds
+			}
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func (w *WakaTime) get(ctx context.Context, apiKey, path string, q url.Values, out interface{}) error {
+	endpoint := w.baseURL + path
+	if len(q) > 0 {
+		endpoint += "?" + q.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(apiKey)))
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("wakatime request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read wakatime response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrInvalidAPIKey
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("wakatime returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body[:min(len(body), 512)])))
+	}
+	return json.Unmarshal(body, out)
+}
+
+// Heartbeat is a WakaTime heartbeat: a moment an editor saw the user working on a file
+type Heartbeat struct {
+	Time     float64 `json:"time"` // unix seconds
+	Language string  `json:"language"`
+	Entity   string  `json:"entity"`
+	Project  string  `json:"project"`
+}
+
+// ParseHeartbeats reads a heartbeat, or a list of them as sent to heartbeats.bulk
+func ParseHeartbeats(body []byte) ([]Heartbeat, error) {
+	body = bytes.TrimSpace(body)
+	var beats []Heartbeat
+	if len(body) > 0 && body[0] == '{' {
+		var beat Heartbeat
+		if err := json.Unmarshal(body, &beat); err != nil {
+			return nil, fmt.Errorf("invalid heartbeat: %w", err)
+		}
+		beats = append(beats, beat)
+	} else if err := json.Unmarshal(body, &beats); err != nil {
+		return nil, fmt.Errorf("invalid heartbeats: %w", err)
+	}
+	if len(beats) > MaxHeartbeats {
+		return nil, fmt.Errorf("at most %d heartbeats can be sent at once", MaxHeartbeats)
+	}
+	for _, b := range beats {
+		if b.Time <= 0 || math.IsInf(b.Time, 0) || math.IsNaN(b.Time) {
+			return nil, errors.New("heartbeat time must be a unix timestamp")
+		}
+	}
+	return beats, nil
+}
+
+// APIKey reads the key a plugin authenticates with, sent as WakaTime does (HTTP Basic with the
+// base64 key as the whole credential) or as a bearer token
+func APIKey(authorization string) string {
+	scheme, credential, _ := strings.Cut(strings.TrimSpace(authorization), " ")
+	credential = strings.TrimSpace(credential)
+	switch strings.ToLower(scheme) {
+	case "bearer":
+		return credential
+	case "basic":
+		decoded, err := base64.StdEncoding.DecodeString(credential)
+		if err != nil {
+			return ""
+		}
+		// Some clients send key: or :key as username and password
+		return strings.Trim(string(decoded), ":")
+	}
+	return ""
+}
+
+// Tally turns heartbeats into coding time. Each gap of at most HeartbeatTimeout between a
+// heartbeat and the next counts toward the earlier heartbeat's language and UTC day. last and
+// lastLanguage are the latest heartbeat already counted, if any; heartbeats at or before it are
+// skipped. It returns the time per day and the new latest heartbeat.
+func Tally(last *time.Time, lastLanguage string, beats []Heartbeat) ([]domain.CodingDay, *time.Time, string) {
+	sorted := make([]Heartbeat, len(beats))
+	copy(sorted, beats)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time < sorted[j].Time })
+
+	days := make(map[time.Time]map[string]int)
+	for _, b := range sorted {
+		at := heartbeatTime(b.Time)
+		if last != nil && !at.After(*last) {
+			continue
+		}
+		if last != nil {
+			if gap := at.Sub(*last); gap <= HeartbeatTimeout {
+				day := last.Truncate(24 * time.Hour)
+				if days[day] == nil {
+					days[day] = make(map[string]int)
+				}
+				days[day][lastLanguage] += int(gap.Round(time.Second) / time.Second)
+			}
+		}
+		last, lastLanguage = &at, language(b.Language)
+	}
+
+	out := make([]domain.CodingDay, 0, len(days))
+	for date, seconds := range days {
+		out = append(out, domain.CodingDay{Date: date, Seconds: seconds})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out, last, lastLanguage
+}
+
+func heartbeatTime(unix float64) time.Time {
+	sec, frac := math.Modf(unix)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+}
+
+// language normalizes a language name, counting unnamed ones as Other
+func language(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "unknown") {
+		return otherLanguage
+	}
+	if runes := []rune(name); len(runes) > maxLanguageLength {
+		name = string(runes[:maxLanguageLength])
+	}
+	return name
+}
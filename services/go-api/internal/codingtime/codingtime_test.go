@@ -0,0 +1,110 @@
+package codingtime
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTally(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) float64 { return float64(day.Add(d).Unix()) }
+
+	beats := []Heartbeat{
+		{Time: at(9*time.Hour + 10*time.Minute), Language: "Go"},
+		{Time: at(9 * time.Hour), Language: "Go"},
+		{Time: at(9*time.Hour + 20*time.Minute), Language: "SQL"},
+		// A 40 minute break is not counted
+		{Time: at(10 * time.Hour), Language: ""},
+		{Time: at(10*time.Hour + 5*time.Minute), Language: "Go"},
+		// Past midnight the gap counts toward the day of the earlier heartbeat
+		{Time: at(23*time.Hour + 55*time.Minute), Language: "Go"},
+		{Time: at(24*time.Hour + 5*time.Minute), Language: "Go"},
+	}
+	days, last, lastLanguage := Tally(nil, "", beats)
+	if len(days) != 1 || !days[0].Date.Equal(day) {
+		t.Fatalf("Tally days = %+v; want only May 1", days)
+	}
+	want := map[string]int{"Go": 30 * 60, "Other": 5 * 60}
+	for lang, seconds := range want {
+		if days[0].Seconds[lang] != seconds {
+			t.Errorf("%s = %ds; want %d (all: %v)", lang, days[0].Seconds[lang], seconds, days[0].Seconds)
+		}
+	}
+	if len(days[0].Seconds) != len(want) {
+		t.Errorf("languages = %v; want %v", days[0].Seconds, want)
+	}
+	if !last.Equal(day.Add(24*time.Hour+5*time.Minute)) || lastLanguage != "Go" {
+		t.Errorf("last = %v %q", last, lastLanguage)
+	}
+
+	// Heartbeats already counted are skipped; the gap from the last one counts
+	again, _, _ := Tally(last, lastLanguage, append(beats, Heartbeat{Time: at(24*time.Hour + 10*time.Minute), Language: "Rust"}))
+	if len(again) != 1 || again[0].Seconds["Go"] != 5*60 || len(again[0].Seconds) != 1 {
+		t.Errorf("Tally after last = %+v; want 5 minutes of Go on May 2", again)
+	}
+}
+
+func TestParseHeartbeats(t *testing.T) {
+	if beats, err := ParseHeartbeats([]byte(` {"time": 1714554000.25, "language": "Go"}`)); err != nil || len(beats) != 1 {
+		t.Errorf("ParseHeartbeats(object) = %+v, %v", beats, err)
+	}
+	if beats, err := ParseHeartbeats([]byte(`[{"time": 1714554000}, {"time": 1714554060}]`)); err != nil || len(beats) != 2 {
+		t.Errorf("ParseHeartbeats(array) = %+v, %v", beats, err)
+	}
+	for _, body := range []string{``, `{"time": "soon"}`, `[{"language": "Go"}]`, `{"time": -1}`} {
+		if _, err := ParseHeartbeats([]byte(body)); err == nil {
+			t.Errorf("ParseHeartbeats(%q) succeeded; want an error", body)
+		}
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("waka_123"))
+	for header, want := range map[string]string{
+		basic:             "waka_123",
+		"Bearer waka_123": "waka_123",
+		"Basic " + base64.StdEncoding.EncodeToString([]byte("waka_123:")): "waka_123",
+		"Basic !!":  "",
+		"Token abc": "",
+		"":          "",
+	} {
+		if got := APIKey(header); got != want {
+			t.Errorf("APIKey(%q) = %q; want %q", header, got, want)
+		}
+	}
+}
+
+func TestWakaTimeSummaries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte("good")) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/users/current/summaries" || r.URL.Query().Get("start") != "2024-05-01" || r.URL.Query().Get("end") != "2024-05-02" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"data": [
+			{"range": {"date": "2024-05-01"}, "languages": [{"name": "Go", "total_seconds": 3599.6}, {"name": "Unknown", "total_seconds": 30}]},
+			{"range": {"date": "2024-05-02"}, "languages": []}
+		]}`))
+	}))
+	defer server.Close()
+
+	waka := NewWakaTime(server.URL + "/")
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	days, err := waka.Summaries(context.Background(), "good", start, start.AddDate(0, 0, 1))
+	if err != nil || len(days) != 2 {
+		t.Fatalf("Summaries = %+v, %v", days, err)
+	}
+	if days[0].Seconds["Go"] != 3600 || days[0].Seconds["Other"] != 30 || len(days[1].Seconds) != 0 {
+		t.Errorf("Summaries = %+v", days)
+	}
+	if _, err := waka.Summaries(context.Background(), "bad", start, start); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Summaries(bad key) = %v; want ErrInvalidAPIKey", err)
+	}
+}
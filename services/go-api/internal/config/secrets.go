@@ -0,0 +1,105 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// secretSource resolves secret values by environment variable name
+type secretSource interface {
+	Lookup(key string) (string, bool)
+}
+
+// getSecret resolves a secret in order of precedence:
+// KEY_FILE (e.g. a Docker secret), the external secret store, KEY, then defaultValue
+func getSecret(store secretSource, key, defaultValue string) string {
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			return strings.TrimSpace(string(data))
+		}
+	}
+	if store != nil {
+		if value, ok := store.Lookup(key); ok && value != "" {
+			return value
+		}
+	}
+	return getEnv(key, defaultValue)
+}
+
+// loadSecretStore connects to the secret store selected by SECRETS_PROVIDER, if any
+func loadSecretStore() (secretSource, error) {
+	switch provider := getEnv("SECRETS_PROVIDER", ""); provider {
+	case "":
+		return nil, nil
+	case "vault":
+		return loadVaultSecrets(
+			getEnv("VAULT_ADDR", "http://127.0.0.1:8200"),
+			getSecret(nil, "VAULT_TOKEN", ""),
+			getEnv("VAULT_SECRET_PATH", "secret/data/devjournal"),
+		)
+	default:
+		return nil, fmt.Errorf("unsupported SECRETS_PROVIDER %q", provider)
+	}
+}
+
+// vaultSecrets is a snapshot of a Vault KV secret whose keys are environment variable names
+type vaultSecrets map[string]string
+
+func (v vaultSecrets) Lookup(key string) (string, bool) {
+	value, ok := v[key]
+	return value, ok
+}
+
+// loadVaultSecrets reads one secret from Vault's HTTP API. Both KV v2
+// (secret/data/...) and KV v1 response shapes are supported.
+func loadVaultSecrets(addr, token, path string) (vaultSecrets, error) {
+	if token == "" {
+		return nil, fmt.Errorf("VAULT_TOKEN or VAULT_TOKEN_FILE is required")
+	}
+
+	url := strings.TrimRight(addr, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned %s for %s", resp.Status, path)
+	}
+
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	data := body.Data
+	// KV v2 nests the secret under data.data
+	if nested, ok := data["data"]; ok {
+		if err := json.Unmarshal(nested, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode vault secret: %w", err)
+		}
+	}
+
+	secrets := make(vaultSecrets, len(data))
+	for key, raw := range data {
+		var value string
+		if err := json.Unmarshal(raw, &value); err == nil {
+			secrets[key] = value
+		}
+	}
+	return secrets, nil
+}
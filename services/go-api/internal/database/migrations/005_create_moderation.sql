@@ -0,0 +1,37 @@
+-- Migration: Create chat moderation tables
+-- Description: Per-group chat filter settings, message reports and admin flag
+
+-- Up Migration
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS study_group_moderation (
+    group_id UUID PRIMARY KEY REFERENCES study_groups(id) ON DELETE CASCADE,
+    profanity_filter BOOLEAN NOT NULL DEFAULT false,
+    blocked_patterns TEXT[] NOT NULL DEFAULT '{}',
+    max_message_length INTEGER NOT NULL DEFAULT 0, -- 0 uses the server default
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS message_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    group_id UUID NOT NULL REFERENCES study_groups(id) ON DELETE CASCADE,
+    message_id VARCHAR(64) NOT NULL,
+    message_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    message_content TEXT NOT NULL,
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, dismissed, actioned
+    resolution_note TEXT,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (message_id, reporter_id)
+);
+
+-- Index for the admin moderation queue
+CREATE INDEX IF NOT EXISTS idx_message_reports_status ON message_reports(status, created_at);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS message_reports;
+-- DROP TABLE IF EXISTS study_group_moderation;
+-- ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
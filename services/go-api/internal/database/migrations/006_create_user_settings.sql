@@ -0,0 +1,12 @@
+-- Migration: Create user_settings table
+-- Description: Per-user preferences such as the default page size for list endpoints
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    default_page_size INTEGER NOT NULL DEFAULT 10,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS user_settings;
@@ -0,0 +1,23 @@
+-- Migration: Create review_items table
+-- Description: Spaced-repetition (SM-2) schedule for resurfacing past journal entries and snippets
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS review_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_type VARCHAR(20) NOT NULL CHECK (item_type IN ('journal', 'snippet')),
+    item_id VARCHAR(64) NOT NULL, -- journal entry UUID or snippet ObjectID hex
+    ease_factor REAL NOT NULL DEFAULT 2.5,
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    repetitions INTEGER NOT NULL DEFAULT 0,
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, item_type, item_id)
+);
+
+-- Index for finding the next due item
+CREATE INDEX IF NOT EXISTS idx_review_items_due ON review_items(user_id, due_at);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS review_items;
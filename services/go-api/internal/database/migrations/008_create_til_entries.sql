@@ -0,0 +1,26 @@
+-- Migration: Create til_entries table
+-- Description: "Today I Learned" one-liner micro-entries that count toward streaks
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS til_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content VARCHAR(280) NOT NULL,
+    tags TEXT[] DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for the user's TIL feed
+CREATE INDEX IF NOT EXISTS idx_til_entries_user_created ON til_entries(user_id, created_at DESC);
+
+-- Daily TIL count alongside entries and snippets
+ALTER TABLE learning_progress ADD COLUMN IF NOT EXISTS tils_count INTEGER DEFAULT 0;
+
+-- Streak index now counts TIL-only days as active
+DROP INDEX IF EXISTS idx_progress_user_recent;
+CREATE INDEX IF NOT EXISTS idx_progress_user_recent ON learning_progress(user_id, date)
+    WHERE entries_count > 0 OR snippets_count > 0 OR tils_count > 0;
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE learning_progress DROP COLUMN IF EXISTS tils_count;
+-- DROP TABLE IF EXISTS til_entries;
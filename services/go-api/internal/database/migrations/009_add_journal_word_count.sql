@@ -0,0 +1,17 @@
+-- Migration: Add word_count to journal_entries
+-- Description: Word counts are computed on write and aggregated for writing stats
+
+-- Up Migration
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS word_count INTEGER NOT NULL DEFAULT 0;
+
+-- Backfill existing entries (whitespace-separated words, matching the API's count)
+UPDATE journal_entries
+SET word_count = COALESCE(array_length(regexp_split_to_array(btrim(content), '\s+'), 1), 0)
+WHERE word_count = 0 AND btrim(content) <> '';
+
+-- Index for weekly writing trends
+CREATE INDEX IF NOT EXISTS idx_journal_entries_user_created ON journal_entries(user_id, created_at);
+
+-- Down Migration (commented out for safety)
+-- DROP INDEX IF EXISTS idx_journal_entries_user_created;
+-- ALTER TABLE journal_entries DROP COLUMN IF EXISTS word_count;
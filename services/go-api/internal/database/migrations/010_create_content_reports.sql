@@ -0,0 +1,38 @@
+-- Migration: Create content_reports and user_warnings tables
+-- Description: Abuse reports against public content (snippets) and admin warnings issued to users
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS content_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    content_type VARCHAR(20) NOT NULL CHECK (content_type IN ('snippet')),
+    content_id VARCHAR(64) NOT NULL, -- snippet ObjectID hex
+    content_owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    content_snapshot TEXT NOT NULL DEFAULT '', -- title at report time
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'dismissed', 'actioned')),
+    resolution_note TEXT,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(content_type, content_id, reporter_id)
+);
+
+-- Index for the admin review queue
+CREATE INDEX IF NOT EXISTS idx_content_reports_status ON content_reports(status, created_at);
+
+CREATE TABLE IF NOT EXISTS user_warnings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    issued_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL,
+    report_id UUID, -- content_reports or message_reports row that prompted the warning
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for a user's warnings
+CREATE INDEX IF NOT EXISTS idx_user_warnings_user ON user_warnings(user_id, created_at DESC);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS user_warnings;
+-- DROP TABLE IF EXISTS content_reports;
@@ -0,0 +1,15 @@
+-- Migration: Add end-to-end encryption fields to journal_entries
+-- Description: E2EE entries store client-side ciphertext in content plus the metadata needed to decrypt it
+
+-- Up Migration
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS content_format VARCHAR(20) NOT NULL DEFAULT 'plain';
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS encryption JSONB;
+
+ALTER TABLE journal_entries DROP CONSTRAINT IF EXISTS journal_entries_content_format_check;
+ALTER TABLE journal_entries ADD CONSTRAINT journal_entries_content_format_check
+    CHECK (content_format IN ('plain', 'e2ee'));
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE journal_entries DROP CONSTRAINT IF EXISTS journal_entries_content_format_check;
+-- ALTER TABLE journal_entries DROP COLUMN IF EXISTS encryption;
+-- ALTER TABLE journal_entries DROP COLUMN IF EXISTS content_format;
@@ -0,0 +1,59 @@
+-- Migration: Create workspaces and workspace_members tables
+-- Description: Workspaces partition journal entries, snippets, study groups, and review schedules.
+-- Every user has a personal workspace whose ID equals their user ID.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS workspaces (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    is_personal BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'admin', 'member')),
+    joined_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+-- Index for the workspace switcher
+CREATE INDEX IF NOT EXISTS idx_workspace_members_user ON workspace_members(user_id);
+
+-- Backfill personal workspaces for existing users
+INSERT INTO workspaces (id, name, slug, owner_id, is_personal, created_at)
+SELECT id, 'Personal', 'personal-' || id, id, true, created_at FROM users
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO workspace_members (workspace_id, user_id, role, joined_at)
+SELECT id, id, 'owner', created_at FROM users
+ON CONFLICT (workspace_id, user_id) DO NOTHING;
+
+-- Scope journal entries
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+UPDATE journal_entries SET workspace_id = user_id WHERE workspace_id IS NULL;
+ALTER TABLE journal_entries ALTER COLUMN workspace_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_journal_entries_workspace_user ON journal_entries(workspace_id, user_id, created_at DESC);
+
+-- Scope study groups to the creator's personal workspace
+ALTER TABLE study_groups ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+UPDATE study_groups SET workspace_id = created_by WHERE workspace_id IS NULL;
+ALTER TABLE study_groups ALTER COLUMN workspace_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_study_groups_workspace ON study_groups(workspace_id, is_public);
+
+-- Scope review schedules
+ALTER TABLE review_items ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+UPDATE review_items SET workspace_id = user_id WHERE workspace_id IS NULL;
+ALTER TABLE review_items ALTER COLUMN workspace_id SET NOT NULL;
+DROP INDEX IF EXISTS idx_review_items_due;
+CREATE INDEX IF NOT EXISTS idx_review_items_due ON review_items(user_id, workspace_id, due_at);
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE review_items DROP COLUMN IF EXISTS workspace_id;
+-- ALTER TABLE study_groups DROP COLUMN IF EXISTS workspace_id;
+-- ALTER TABLE journal_entries DROP COLUMN IF EXISTS workspace_id;
+-- DROP TABLE IF EXISTS workspace_members;
+-- DROP TABLE IF EXISTS workspaces;
@@ -0,0 +1,13 @@
+-- Migration: Create organizations table
+-- Description: Organizations are shared workspaces with a billed seat limit, for bootcamp cohorts and teams.
+-- An organization's ID equals its workspace's ID; members are the workspace's members.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    seat_limit INTEGER NOT NULL DEFAULT 10 CHECK (seat_limit > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,17 @@
+-- Migration: Create subscriptions table
+-- Description: Billing state per user, kept in sync with Stripe by webhooks. Users without a row are on the free plan.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS subscriptions (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    plan VARCHAR(20) NOT NULL DEFAULT 'free' CHECK (plan IN ('free', 'pro', 'team')),
+    status VARCHAR(30) NOT NULL DEFAULT 'active',
+    stripe_customer_id VARCHAR(255) UNIQUE,
+    stripe_subscription_id VARCHAR(255) UNIQUE,
+    current_period_end TIMESTAMP WITH TIME ZONE,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS subscriptions;
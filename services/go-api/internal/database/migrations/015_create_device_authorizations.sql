@@ -0,0 +1,22 @@
+-- Migration: Create device_authorizations table
+-- Description: OAuth-style device authorization grants (RFC 8628) that let editor plugins sign in.
+-- Only a SHA-256 hash of the device code is stored; the user code is what people type on the verification page.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS device_authorizations (
+    id UUID PRIMARY KEY,
+    device_code_hash VARCHAR(64) NOT NULL UNIQUE,
+    user_code VARCHAR(9) NOT NULL UNIQUE,
+    client_name VARCHAR(100) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied', 'consumed')),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    poll_interval INTEGER NOT NULL,
+    last_polled_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_authorizations_expires_at ON device_authorizations(expires_at);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS device_authorizations;
@@ -0,0 +1,40 @@
+-- Migration: Create group integrations tables
+-- Description: Slack/Discord channels that mirror a study group's chat, and the outbound delivery queue with retry state
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS group_integrations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    group_id UUID NOT NULL REFERENCES study_groups(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('slack', 'discord')),
+    team_id VARCHAR(100) NOT NULL DEFAULT '',
+    channel_id VARCHAR(100) NOT NULL,
+    channel_name VARCHAR(255) NOT NULL DEFAULT '',
+    webhook_url TEXT NOT NULL,
+    bot_token TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    sync_inbound BOOLEAN NOT NULL DEFAULT false,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (group_id, provider)
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_integrations_channel ON group_integrations(provider, channel_id);
+
+CREATE TABLE IF NOT EXISTS integration_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    integration_id UUID NOT NULL REFERENCES group_integrations(id) ON DELETE CASCADE,
+    message_id VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_integration_deliveries_due ON integration_deliveries(next_attempt_at) WHERE status = 'pending';
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS integration_deliveries;
+-- DROP TABLE IF EXISTS group_integrations;
@@ -0,0 +1,13 @@
+-- Migration: Create calendar_feeds table
+-- Description: Private iCal feed tokens. Only a hash of the token is stored; rotating replaces it.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_accessed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS calendar_feeds;
@@ -0,0 +1,23 @@
+-- Migration: Create push_devices and push_reminders tables
+-- Description: Mobile app device tokens for push notifications, and the last day each user was sent a streak reminder
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS push_devices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform VARCHAR(20) NOT NULL,
+    token VARCHAR(512) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_devices_user ON push_devices(user_id);
+
+CREATE TABLE IF NOT EXISTS push_reminders (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    last_sent_on DATE NOT NULL
+);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS push_reminders;
+-- DROP TABLE IF EXISTS push_devices;
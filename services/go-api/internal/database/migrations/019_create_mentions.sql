@@ -0,0 +1,24 @@
+-- Migration: Create mentions table
+-- Description: @mentions of users in study group chat and shared journal entries
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS mentions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_type VARCHAR(20) NOT NULL,
+    source_id VARCHAR(100) NOT NULL,
+    context_id UUID NOT NULL,
+    context_name VARCHAR(255) NOT NULL DEFAULT '',
+    excerpt TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    read_at TIMESTAMP WITH TIME ZONE,
+    -- Editing an entry doesn't mention the same user again
+    UNIQUE (user_id, source_type, source_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mentions_user_created ON mentions(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_mentions_unread ON mentions(user_id) WHERE read_at IS NULL;
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS mentions;
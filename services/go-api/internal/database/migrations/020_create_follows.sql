@@ -0,0 +1,22 @@
+-- Migration: Create follows table and public journal entries
+-- Description: Users follow each other; public entries and snippets of followed users make up the feed
+
+-- Up Migration
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS is_public BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_public ON journal_entries(user_id, created_at DESC) WHERE is_public = true;
+
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_follows_followee ON follows(followee_id, created_at DESC);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS follows;
+-- DROP INDEX IF EXISTS idx_journal_entries_public;
+-- ALTER TABLE journal_entries DROP COLUMN IF EXISTS is_public;
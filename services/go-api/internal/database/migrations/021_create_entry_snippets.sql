@@ -0,0 +1,17 @@
+-- Migration: Create entry_snippets table
+-- Description: Links between journal entries and snippets. Snippets live in MongoDB, so links to a
+-- deleted snippet are removed by the API rather than a foreign key.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS entry_snippets (
+    entry_id UUID NOT NULL REFERENCES journal_entries(id) ON DELETE CASCADE,
+    snippet_id VARCHAR(24) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (entry_id, snippet_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_entry_snippets_snippet ON entry_snippets(snippet_id);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS entry_snippets;
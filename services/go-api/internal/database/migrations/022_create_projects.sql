@@ -0,0 +1,39 @@
+-- Migration: Create projects and focus_sessions tables
+-- Description: Projects group journal entries, snippets, and focus time. Snippets store their
+-- project ID in MongoDB.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS projects (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'completed', 'archived')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_projects_workspace_user ON projects(workspace_id, user_id, updated_at DESC);
+
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS project_id UUID REFERENCES projects(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_project ON journal_entries(project_id, created_at DESC) WHERE project_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS focus_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    minutes INTEGER NOT NULL CHECK (minutes > 0),
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_focus_sessions_project ON focus_sessions(project_id, started_at DESC);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS focus_sessions;
+-- DROP INDEX IF EXISTS idx_journal_entries_project;
+-- ALTER TABLE journal_entries DROP COLUMN IF EXISTS project_id;
+-- DROP TABLE IF EXISTS projects;
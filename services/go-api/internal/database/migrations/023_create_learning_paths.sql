@@ -0,0 +1,56 @@
+-- Migration: Create learning path tables
+-- Description: Learning paths are ordered topics a user works through. A path can be shared into
+-- a study group, and every member tracks their own completion and evidence.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS learning_paths (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    group_id UUID REFERENCES study_groups(id) ON DELETE SET NULL,
+    title VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_learning_paths_workspace_user ON learning_paths(workspace_id, user_id, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_learning_paths_group ON learning_paths(group_id) WHERE group_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS learning_path_topics (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    path_id UUID NOT NULL REFERENCES learning_paths(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_learning_path_topics_path ON learning_path_topics(path_id, position);
+
+-- Completion is tracked per user so members of a group can follow a shared path
+CREATE TABLE IF NOT EXISTS learning_path_completions (
+    topic_id UUID NOT NULL REFERENCES learning_path_topics(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    completed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (topic_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_learning_path_completions_user ON learning_path_completions(user_id);
+
+-- Entries and snippets a user linked to a topic as evidence. Snippets live in MongoDB, so
+-- evidence IDs are stored as text.
+CREATE TABLE IF NOT EXISTS learning_path_evidence (
+    topic_id UUID NOT NULL REFERENCES learning_path_topics(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('entry', 'snippet')),
+    ref_id VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (topic_id, user_id, type, ref_id)
+);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS learning_path_evidence;
+-- DROP TABLE IF EXISTS learning_path_completions;
+-- DROP TABLE IF EXISTS learning_path_topics;
+-- DROP TABLE IF EXISTS learning_paths;
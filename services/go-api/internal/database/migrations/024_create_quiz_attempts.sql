@@ -0,0 +1,23 @@
+-- Migration: Create quiz_attempts table
+-- Description: Scored attempts at study group quizzes, which feed the group leaderboard. Quizzes
+-- are stored in MongoDB, so quiz IDs are ObjectID hex strings.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    quiz_id VARCHAR(24) NOT NULL,
+    group_id UUID NOT NULL REFERENCES study_groups(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    score INTEGER NOT NULL CHECK (score >= 0),
+    total INTEGER NOT NULL CHECK (total > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for the group leaderboard
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_group_user ON quiz_attempts(group_id, user_id, quiz_id);
+
+-- Index for a quiz's attempts
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_quiz ON quiz_attempts(quiz_id);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS quiz_attempts;
@@ -0,0 +1,37 @@
+-- Migration: Create code_reviews and code_review_comments tables
+-- Description: Snippets shared into a study group for review. The snippet's files are copied into
+-- the review so comments stay anchored to the lines they were written against.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS code_reviews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    group_id UUID NOT NULL REFERENCES study_groups(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    snippet_id VARCHAR(24) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    files JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved')),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_code_reviews_group ON code_reviews(group_id, status, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS code_review_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    review_id UUID NOT NULL REFERENCES code_reviews(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    line INTEGER NOT NULL CHECK (line > 0),
+    body TEXT NOT NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_code_review_comments_review ON code_review_comments(review_id, created_at);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS code_review_comments;
+-- DROP TABLE IF EXISTS code_reviews;
@@ -0,0 +1,26 @@
+-- Migration: Create snippet_comments table
+-- Description: Comments on a line or range of lines of a snippet's file. Snippets are stored in
+-- MongoDB, so snippet IDs are ObjectID hex strings. Comments are moved when the snippet is edited,
+-- or marked outdated when their lines change.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS snippet_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    snippet_id VARCHAR(24) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    start_line INTEGER NOT NULL CHECK (start_line > 0),
+    end_line INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    outdated BOOLEAN NOT NULL DEFAULT FALSE,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_line >= start_line)
+);
+
+-- Index for a snippet's comments
+CREATE INDEX IF NOT EXISTS idx_snippet_comments_snippet ON snippet_comments(snippet_id, created_at);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS snippet_comments;
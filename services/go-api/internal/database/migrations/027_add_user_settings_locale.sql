@@ -0,0 +1,9 @@
+-- Migration: Add locale to user_settings
+-- Description: The language of a user's error messages, notifications, and emails as a BCP 47
+-- tag. Empty follows the Accept-Language header of each request.
+
+-- Up Migration
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT '';
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE user_settings DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,9 @@
+-- Migration: Add is_vault to journal_entries
+-- Description: Vault entries can only be read or edited within a vault session, which the user
+-- opens by re-entering their password.
+
+-- Up Migration
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS is_vault BOOLEAN NOT NULL DEFAULT false;
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE journal_entries DROP COLUMN IF EXISTS is_vault;
@@ -0,0 +1,29 @@
+-- Migration: Create imports table
+-- Description: Notion, Obsidian, and Day One exports being imported as journal entries by a
+-- background worker, with their progress. The upload is kept until the import finishes.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS imports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('notion', 'obsidian', 'dayone')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    archive BYTEA,
+    total INTEGER NOT NULL DEFAULT 0,
+    processed INTEGER NOT NULL DEFAULT 0,
+    imported INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    -- A running import whose worker stops renewing its lease is picked up again where it left off
+    lease_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_imports_user_created ON imports(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_imports_unfinished ON imports(created_at) WHERE status IN ('pending', 'running');
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS imports;
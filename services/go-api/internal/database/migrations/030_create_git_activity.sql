@@ -0,0 +1,60 @@
+-- Migration: Create git activity tables
+-- Description: Per-user GitHub/GitLab webhooks, the commits and pull requests they report, and the
+-- daily journal entry drafts summarizing them. Webhook secrets are kept as is, since GitHub
+-- signatures are verified with them.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS git_hooks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    include_commits BOOLEAN NOT NULL DEFAULT true,
+    include_pull_requests BOOLEAN NOT NULL DEFAULT true,
+    repos TEXT[] NOT NULL DEFAULT '{}',
+    authors TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS git_activity (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('github', 'gitlab')),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('commit', 'pull_request')),
+    external_id VARCHAR(500) NOT NULL,
+    repo VARCHAR(255) NOT NULL,
+    ref VARCHAR(100) NOT NULL DEFAULT '',
+    action VARCHAR(20) NOT NULL DEFAULT '',
+    title TEXT NOT NULL,
+    url TEXT NOT NULL DEFAULT '',
+    additions INTEGER NOT NULL DEFAULT 0,
+    deletions INTEGER NOT NULL DEFAULT 0,
+    files_changed INTEGER NOT NULL DEFAULT 0,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- Providers redeliver webhooks, and the same commit is pushed to several branches
+    UNIQUE (user_id, provider, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_git_activity_user_occurred ON git_activity(user_id, occurred_at);
+
+CREATE TABLE IF NOT EXISTS git_drafts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'published', 'dismissed')),
+    edited BOOLEAN NOT NULL DEFAULT false,
+    entry_id UUID REFERENCES journal_entries(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, day)
+);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS git_drafts;
+-- DROP TABLE IF EXISTS git_activity;
+-- DROP TABLE IF EXISTS git_hooks;
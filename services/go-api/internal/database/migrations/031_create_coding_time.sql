@@ -0,0 +1,35 @@
+-- Migration: Create coding time tables
+-- Description: Where each user's coding time comes from (a WakaTime API key to sync from, or a
+-- heartbeat token editor plugins send to), and the coding time per day and language. Only a hash
+-- of the heartbeat token is stored; the WakaTime key is kept as is, since syncs need it.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS coding_sources (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('wakatime', 'heartbeat')),
+    api_key TEXT NOT NULL DEFAULT '',
+    token_hash VARCHAR(64) UNIQUE,
+    last_heartbeat_at TIMESTAMP WITH TIME ZONE,
+    last_language VARCHAR(100) NOT NULL DEFAULT '',
+    next_sync_at TIMESTAMP WITH TIME ZONE,
+    synced_at TIMESTAMP WITH TIME ZONE,
+    sync_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for the sync worker picking up WakaTime sources that are due
+CREATE INDEX IF NOT EXISTS idx_coding_sources_next_sync ON coding_sources(next_sync_at)
+    WHERE provider = 'wakatime';
+
+CREATE TABLE IF NOT EXISTS coding_time (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    language VARCHAR(100) NOT NULL,
+    seconds INTEGER NOT NULL DEFAULT 0 CHECK (seconds >= 0),
+    PRIMARY KEY (user_id, day, language)
+);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS coding_time;
+-- DROP TABLE IF EXISTS coding_sources;
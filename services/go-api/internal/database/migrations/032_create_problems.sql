@@ -0,0 +1,35 @@
+-- Migration: Create problems table
+-- Description: Coding problems solved on LeetCode, Advent of Code, and similar sites, which count
+-- toward streaks on the day they were solved
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS problems (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform VARCHAR(20) NOT NULL CHECK (platform IN ('leetcode', 'adventofcode', 'codeforces', 'hackerrank', 'other')),
+    problem_id VARCHAR(100) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    difficulty VARCHAR(10) NOT NULL DEFAULT '' CHECK (difficulty IN ('', 'easy', 'medium', 'hard')),
+    url TEXT NOT NULL DEFAULT '',
+    snippet_id VARCHAR(24) NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    solved_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, platform, problem_id)
+);
+
+-- Index for the user's problem log and weekly stats
+CREATE INDEX IF NOT EXISTS idx_problems_user_solved ON problems(user_id, solved_at DESC);
+
+-- Daily solved count alongside entries, snippets, and TILs
+ALTER TABLE learning_progress ADD COLUMN IF NOT EXISTS problems_count INTEGER DEFAULT 0;
+
+-- Streak index now counts days with only solved problems as active
+DROP INDEX IF EXISTS idx_progress_user_recent;
+CREATE INDEX IF NOT EXISTS idx_progress_user_recent ON learning_progress(user_id, date)
+    WHERE entries_count > 0 OR snippets_count > 0 OR tils_count > 0 OR problems_count > 0;
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE learning_progress DROP COLUMN IF EXISTS problems_count;
+-- DROP TABLE IF EXISTS problems;
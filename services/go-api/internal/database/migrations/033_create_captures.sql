@@ -0,0 +1,27 @@
+-- Migration: Create captures table
+-- Description: Quick-capture inbox of text sent by browser extensions, AI chats, and other
+-- clients, reviewed and then published as journal entries or snippets
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS captures (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source VARCHAR(40) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('entry', 'snippet')),
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    language VARCHAR(50) NOT NULL DEFAULT '',
+    url TEXT NOT NULL DEFAULT '',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'published', 'dismissed')),
+    -- A journal entry UUID or a snippet's MongoDB ObjectID, depending on kind
+    published_id VARCHAR(36) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for the inbox, newest first
+CREATE INDEX IF NOT EXISTS idx_captures_user_inbox ON captures(user_id, created_at DESC) WHERE status = 'draft';
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS captures;
@@ -0,0 +1,35 @@
+-- Migration: Triage captures in an inbox, and add bookmarks
+-- Description: Captures wait in the inbox until they are converted into an entry, snippet, or
+-- bookmark, archived, or dismissed
+
+-- Up Migration
+ALTER TABLE captures DROP CONSTRAINT IF EXISTS captures_status_check;
+UPDATE captures SET status = 'inbox' WHERE status = 'draft';
+UPDATE captures SET status = 'converted' WHERE status = 'published';
+ALTER TABLE captures ALTER COLUMN status SET DEFAULT 'inbox';
+ALTER TABLE captures ADD CONSTRAINT captures_status_check
+    CHECK (status IN ('inbox', 'converted', 'archived', 'dismissed'));
+
+ALTER TABLE captures DROP CONSTRAINT IF EXISTS captures_kind_check;
+ALTER TABLE captures ADD CONSTRAINT captures_kind_check CHECK (kind IN ('entry', 'snippet', 'bookmark'));
+
+-- Inbox and archive listings, newest first
+DROP INDEX IF EXISTS idx_captures_user_inbox;
+CREATE INDEX IF NOT EXISTS idx_captures_user_status ON captures(user_id, status, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS bookmarks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, url)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_user_created ON bookmarks(user_id, created_at DESC);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS bookmarks;
+-- DROP INDEX IF EXISTS idx_captures_user_status;
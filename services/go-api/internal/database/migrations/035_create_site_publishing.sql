@@ -0,0 +1,38 @@
+-- Migration: Create site publishing tables
+-- Description: Per-user publishers that send newly public journal entries to a static site, by
+-- calling a build webhook or committing them to a GitHub repository, and the queue of entries
+-- waiting to be sent. GitHub tokens are kept as is, since they are used to commit.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS site_publishers (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('webhook', 'github')),
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    webhook_url TEXT NOT NULL DEFAULT '',
+    repo VARCHAR(200) NOT NULL DEFAULT '',
+    branch VARCHAR(255) NOT NULL DEFAULT '',
+    path VARCHAR(255) NOT NULL DEFAULT '',
+    token TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS site_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    entry_id UUID NOT NULL REFERENCES journal_entries(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- Entries are published once, when they first become public
+    UNIQUE (user_id, entry_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_site_deliveries_due ON site_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_site_deliveries_user_created ON site_deliveries(user_id, created_at DESC);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS site_deliveries;
+-- DROP TABLE IF EXISTS site_publishers;
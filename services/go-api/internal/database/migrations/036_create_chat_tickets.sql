@@ -0,0 +1,17 @@
+-- Migration: Create chat tickets table
+-- Description: One-time, short-lived passes to open a chat WebSocket in one room, so long-lived
+-- tokens never appear in WebSocket URLs. Tickets are stored hashed and deleted when redeemed.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS chat_tickets (
+    ticket_hash CHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    room VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_tickets_expires ON chat_tickets(expires_at);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS chat_tickets;
@@ -0,0 +1,19 @@
+-- Migration: Create group chat channels
+-- Description: Named chat channels in study groups, each with who may post in it. The default
+-- "general" channel has no row until its posting policy is changed. Chat tickets record whether
+-- they were issued to someone who can only read the room.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS group_channels (
+    group_id UUID NOT NULL REFERENCES study_groups(id) ON DELETE CASCADE,
+    name VARCHAR(32) NOT NULL,
+    post_policy VARCHAR(20) NOT NULL DEFAULT 'members' CHECK (post_policy IN ('members', 'admins')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id, name)
+);
+
+ALTER TABLE chat_tickets ADD COLUMN IF NOT EXISTS read_only BOOLEAN NOT NULL DEFAULT false;
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE chat_tickets DROP COLUMN IF EXISTS read_only;
+-- DROP TABLE IF EXISTS group_channels;
@@ -0,0 +1,33 @@
+-- Migration: Create group entry templates
+-- Description: Journal entry outlines, such as weekly retrospectives, that study group admins
+-- publish to their group. A response links a member's entry made from a template to the instance
+-- of the template it answers, one entry per member per instance.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS entry_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    group_id UUID NOT NULL REFERENCES study_groups(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prompts TEXT[] NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_entry_templates_group ON entry_templates(group_id, created_at);
+
+CREATE TABLE IF NOT EXISTS entry_template_responses (
+    template_id UUID NOT NULL REFERENCES entry_templates(id) ON DELETE CASCADE,
+    instance VARCHAR(40) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    entry_id UUID NOT NULL REFERENCES journal_entries(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (template_id, instance, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_entry_template_responses_entry ON entry_template_responses(entry_id);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS entry_template_responses;
+-- DROP TABLE IF EXISTS entry_templates;
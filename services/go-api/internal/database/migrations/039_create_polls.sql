@@ -0,0 +1,30 @@
+-- Migration: Create group chat polls
+-- Description: Polls posted to study group chat channels and the members' votes. A member's
+-- vote is a row per option they picked, replaced whenever they vote again.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS polls (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    group_id UUID NOT NULL REFERENCES study_groups(id) ON DELETE CASCADE,
+    channel VARCHAR(32) NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    question VARCHAR(300) NOT NULL,
+    options TEXT[] NOT NULL,
+    multiple_choice BOOLEAN NOT NULL DEFAULT FALSE,
+    closes_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_polls_group ON polls(group_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS poll_votes (
+    poll_id UUID NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    option_index SMALLINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id, user_id, option_index)
+);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS poll_votes;
+-- DROP TABLE IF EXISTS polls;
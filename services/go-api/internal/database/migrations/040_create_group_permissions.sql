@@ -0,0 +1,19 @@
+-- Migration: Create study group permissions
+-- Description: Who in a study group may share entries, post snippets, and start voice sessions:
+-- any member, or only owners and admins. Groups without a row let members do everything. Chat
+-- tickets record whether the user may start a voice session in the room or only join one.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS study_group_permissions (
+    group_id UUID PRIMARY KEY REFERENCES study_groups(id) ON DELETE CASCADE,
+    share_entries VARCHAR(20) NOT NULL DEFAULT 'members',
+    post_snippets VARCHAR(20) NOT NULL DEFAULT 'members',
+    start_sessions VARCHAR(20) NOT NULL DEFAULT 'members',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE chat_tickets ADD COLUMN IF NOT EXISTS join_voice_only BOOLEAN NOT NULL DEFAULT false;
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE chat_tickets DROP COLUMN IF EXISTS join_voice_only;
+-- DROP TABLE IF EXISTS study_group_permissions;
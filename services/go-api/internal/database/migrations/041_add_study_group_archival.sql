@@ -0,0 +1,22 @@
+-- Migration: Add study group archival
+-- Description: Groups record when members last chatted or joined. Groups inactive for too long
+-- are flagged (their owner is warned) and then archived: read-only and hidden from discovery
+-- until an owner or admin reactivates them.
+
+-- Up Migration
+ALTER TABLE study_groups ADD COLUMN IF NOT EXISTS last_active_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE study_groups ADD COLUMN IF NOT EXISTS archive_warned_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE study_groups ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+
+-- Index for finding inactive groups
+CREATE INDEX IF NOT EXISTS idx_study_groups_last_active ON study_groups(last_active_at) WHERE archived_at IS NULL;
+
+-- Public groups discovery only lists active groups
+DROP INDEX IF EXISTS idx_study_groups_public;
+CREATE INDEX IF NOT EXISTS idx_study_groups_public ON study_groups(is_public) WHERE is_public = true AND archived_at IS NULL;
+
+-- Down Migration (commented out for safety)
+-- DROP INDEX IF EXISTS idx_study_groups_last_active;
+-- ALTER TABLE study_groups DROP COLUMN IF EXISTS archived_at;
+-- ALTER TABLE study_groups DROP COLUMN IF EXISTS archive_warned_at;
+-- ALTER TABLE study_groups DROP COLUMN IF EXISTS last_active_at;
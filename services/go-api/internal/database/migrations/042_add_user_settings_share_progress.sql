@@ -0,0 +1,9 @@
+-- Migration: Add share_progress to user_settings
+-- Description: Whether a user agrees to their streak and entry counts being compared with the
+-- people they mutually follow or share a study group with. Off until the user turns it on.
+
+-- Up Migration
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS share_progress BOOLEAN NOT NULL DEFAULT false;
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE user_settings DROP COLUMN IF EXISTS share_progress;
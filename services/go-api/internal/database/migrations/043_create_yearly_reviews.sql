@@ -0,0 +1,20 @@
+-- Migration: Create yearly_reviews table
+-- Description: Cached "year in review" reports, one per user, workspace, and year. A background
+-- job recomputes reports of the current year as it goes on; reports computed after their year
+-- ended are final.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS yearly_reviews (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL,
+    year INTEGER NOT NULL,
+    report JSONB NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, workspace_id, year)
+);
+
+-- Index for finding reports due for a recompute
+CREATE INDEX IF NOT EXISTS idx_yearly_reviews_computed ON yearly_reviews(computed_at);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS yearly_reviews;
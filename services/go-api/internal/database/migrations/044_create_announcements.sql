@@ -0,0 +1,37 @@
+-- Migration: Create announcements tables
+-- Description: System announcements admins publish to all users, group owners, or inactive
+-- users, right away or at a scheduled time. Recipients are resolved when an announcement is
+-- published, and each one's read state backs their notification center.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS announcements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    audience VARCHAR(20) NOT NULL DEFAULT 'all', -- all, group_owners, inactive
+    inactive_days INTEGER NOT NULL DEFAULT 0,
+    send_email BOOLEAN NOT NULL DEFAULT FALSE,
+    publish_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    published_at TIMESTAMP WITH TIME ZONE,
+    emailed_at TIMESTAMP WITH TIME ZONE,
+    recipients INTEGER NOT NULL DEFAULT 0,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS announcement_recipients (
+    announcement_id UUID NOT NULL REFERENCES announcements(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    read_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (announcement_id, user_id)
+);
+
+-- Index for finding scheduled announcements that are due
+CREATE INDEX IF NOT EXISTS idx_announcements_due ON announcements(publish_at) WHERE published_at IS NULL;
+
+-- Index for a user's notification center
+CREATE INDEX IF NOT EXISTS idx_announcement_recipients_user ON announcement_recipients(user_id, read_at);
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS announcement_recipients;
+-- DROP TABLE IF EXISTS announcements;
@@ -0,0 +1,26 @@
+-- Migration: Create schema_version table
+-- Description: Records the schema version each migration leaves the database at, and the
+-- oldest build that still runs correctly against it. The server refuses to start against a
+-- schema it doesn't support, so old and new builds can share a database during a rolling
+-- deploy only while the migrations in between are compatible with both.
+--
+-- Every migration from here on ends by recording its version:
+--   INSERT INTO schema_version (version, compatible_from) VALUES (<its number>, <oldest build>)
+--   ON CONFLICT (version) DO NOTHING;
+-- An additive migration (new tables, nullable or defaulted columns) keeps the previous
+-- compatible_from; one that drops, renames, or changes what older builds read sets it to its own
+-- number, so older builds stop instead of corrupting data.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS schema_version (
+    version INT PRIMARY KEY,
+    compatible_from INT NOT NULL CHECK (compatible_from <= version),
+    applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Builds of schema 44 predate the check and never read this table
+INSERT INTO schema_version (version, compatible_from) VALUES (45, 44)
+ON CONFLICT (version) DO NOTHING;
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS schema_version;
@@ -0,0 +1,21 @@
+-- Migration: Create achievements table
+-- Description: Achievements users earn once, such as reaching a 7, 30, 100, or 365-day streak,
+-- and whether a user wants their streak milestones celebrated in their study groups' chats.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS achievements (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL, -- e.g. streak_30
+    earned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, kind)
+);
+
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS celebrate_milestones BOOLEAN NOT NULL DEFAULT false;
+
+-- Older builds award no achievements, and celebrations stay off by default
+INSERT INTO schema_version (version, compatible_from) VALUES (46, 44)
+ON CONFLICT (version) DO NOTHING;
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE user_settings DROP COLUMN IF EXISTS celebrate_milestones;
+-- DROP TABLE IF EXISTS achievements;
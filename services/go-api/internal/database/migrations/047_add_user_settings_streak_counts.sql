@@ -0,0 +1,15 @@
+-- Migration: Add streak_counts to user_settings
+-- Description: Which activity keeps a user's streak going: any activity (the default), journal
+-- entries only, snippets only, an entry or a snippet, or at least streak_minutes of learning time.
+
+-- Up Migration
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS streak_counts VARCHAR(20) NOT NULL DEFAULT 'any'; -- any, entries, snippets, entries_or_snippets, minutes
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS streak_minutes INTEGER NOT NULL DEFAULT 0;
+
+-- Builds of schema 44 don't read the new columns, and count any activity as before
+INSERT INTO schema_version (version, compatible_from) VALUES (47, 44)
+ON CONFLICT (version) DO NOTHING;
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE user_settings DROP COLUMN IF EXISTS streak_minutes;
+-- ALTER TABLE user_settings DROP COLUMN IF EXISTS streak_counts;
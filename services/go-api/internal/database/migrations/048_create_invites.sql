@@ -0,0 +1,21 @@
+-- Migration: Create invites table
+-- Description: Invite codes admins create for invite-only registration. Only a hash of each
+-- code is stored; every registration with a code counts one of its uses.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS invites (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code_hash VARCHAR(64) NOT NULL UNIQUE, -- SHA-256 of the code, hex
+    note VARCHAR(200) NOT NULL DEFAULT '',
+    max_uses INTEGER NOT NULL DEFAULT 1,
+    uses INTEGER NOT NULL DEFAULT 0 CHECK (uses >= 0),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO schema_version (version, compatible_from) VALUES (48, 44)
+ON CONFLICT (version) DO NOTHING;
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS invites;
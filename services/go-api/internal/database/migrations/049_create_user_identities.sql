@@ -0,0 +1,23 @@
+-- Migration: Create user identities table
+-- Description: Links users to their accounts at the OpenID Connect provider used for single
+-- sign-on, so they are recognized by the provider's subject even if their email changes.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS user_identities (
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+-- Only single sign-on reads this table
+INSERT INTO schema_version (version, compatible_from) VALUES (49, 44)
+ON CONFLICT (version) DO NOTHING;
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,14 @@
+-- Migration: Add deactivated_at to users
+-- Description: When a user was deactivated, e.g. deprovisioned by the company's identity provider
+-- over SCIM. Deactivated users can't sign in and their tokens stop working, but their data is kept
+-- so they can be reactivated.
+
+-- Up Migration
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP WITH TIME ZONE;
+
+-- Builds before this one don't read the new column, so they would let deactivated users sign in
+INSERT INTO schema_version (version, compatible_from) VALUES (50, 50)
+ON CONFLICT (version) DO NOTHING;
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE users DROP COLUMN IF EXISTS deactivated_at;
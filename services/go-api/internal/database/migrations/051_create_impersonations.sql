@@ -0,0 +1,25 @@
+-- Migration: Create impersonations table
+-- Description: Audit log of admins acting as users to debug their issues, with the reason given.
+-- Impersonation tokens are checked against it, so ending a session revokes its token.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS impersonations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    admin_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    read_only BOOLEAN NOT NULL DEFAULT TRUE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonations_started ON impersonations(started_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_impersonations_user ON impersonations(user_id, started_at DESC);
+
+INSERT INTO schema_version (version, compatible_from) VALUES (51, 50)
+ON CONFLICT (version) DO NOTHING;
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS impersonations;
@@ -0,0 +1,13 @@
+-- Migration: Add avatar_url to user_settings
+-- Description: The picture shown next to a user on their public member profile in study groups.
+-- Empty shows the client's default avatar.
+
+-- Up Migration
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS avatar_url TEXT NOT NULL DEFAULT '';
+
+-- The default keeps inserts from builds that don't set the column working
+INSERT INTO schema_version (version, compatible_from) VALUES (52, 50)
+ON CONFLICT (version) DO NOTHING;
+
+-- Down Migration (commented out for safety)
+-- ALTER TABLE user_settings DROP COLUMN IF EXISTS avatar_url;
@@ -0,0 +1,28 @@
+-- Migration: Create refresh_tokens table
+-- Description: Long-lived tokens the web app exchanges for a new sign-in token without asking for
+-- the password again. Each is used once and replaced by the next in its family; using one twice
+-- means it was stolen, and revokes the whole family.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens(user_id);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens(family_id);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires ON refresh_tokens(expires_at);
+
+-- Sessions signed in before this keep working until their token expires, with no refresh token
+INSERT INTO schema_version (version, compatible_from) VALUES (53, 50)
+ON CONFLICT (version) DO NOTHING;
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,22 @@
+-- Migration: Create study_group_invites table
+-- Description: Single-use invites to private study groups, which non-members can't see or join
+-- otherwise. Only a hash of each code is stored.
+
+-- Up Migration
+CREATE TABLE IF NOT EXISTS study_group_invites (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    group_id UUID NOT NULL REFERENCES study_groups(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL UNIQUE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_study_group_invites_expires ON study_group_invites(expires_at);
+
+-- A new table that older builds never query
+INSERT INTO schema_version (version, compatible_from) VALUES (54, 50)
+ON CONFLICT (version) DO NOTHING;
+
+-- Down Migration (commented out for safety)
+-- DROP TABLE IF EXISTS study_group_invites;
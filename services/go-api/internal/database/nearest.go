@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// nearestPingTimeout is how long an endpoint has to answer before it is passed over
+	nearestPingTimeout = 2 * time.Second
+
+	// nearestThreshold is how much faster a replica must answer for reads to leave the primary,
+	// which never lags. It matches MongoDB's default localThresholdMS.
+	nearestThreshold = 15 * time.Millisecond
+)
+
+// NearestPool picks which of a PostgreSQL primary and its read replicas reads go to: the one
+// answering fastest, so each region reads from the closest copy. Replicas may lag behind the
+// primary, so it is only for reads that tolerate that.
+type NearestPool struct {
+	pools   []*pgxpool.Pool // the primary first
+	nearest atomic.Pointer[pgxpool.Pool]
+}
+
+// NewNearestPool connects to the read replicas and measures which endpoint is nearest. Without
+// replicas every read goes to the primary. Replicas that don't answer yet are used once they do.
+func NewNearestPool(ctx context.Context, primary *pgxpool.Pool, replicaURLs []string) (*NearestPool, error) {
+	p := &NearestPool{pools: []*pgxpool.Pool{primary}}
+	p.nearest.Store(primary)
+	for _, url := range replicaURLs {
+		replica, err := newPool(ctx, url)
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("read replica: %w", err)
+		}
+		p.pools = append(p.pools, replica)
+	}
+	if len(replicaURLs) > 0 {
+		p.measure(ctx)
+	}
+	return p, nil
+}
+
+// Pool returns the pool reads currently go to
+func (p *NearestPool) Pool() *pgxpool.Pool {
+	return p.nearest.Load()
+}
+
+// Measurer returns a job that pings every endpoint and moves reads to the nearest one
+func (p *NearestPool) Measurer() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		p.measure(ctx)
+		return nil
+	}
+}
+
+// Close closes the replicas' pools; the primary's belongs to the caller
+func (p *NearestPool) Close() {
+	for _, pool := range p.pools[1:] {
+		pool.Close()
+	}
+}
+
+func (p *NearestPool) measure(ctx context.Context) {
+	latencies := make([]time.Duration, len(p.pools))
+	for i, pool := range p.pools {
+		pingCtx, cancel := context.WithTimeout(ctx, nearestPingTimeout)
+		start := time.Now()
+		if err := pool.Ping(pingCtx); err != nil {
+			latencies[i] = -1
+		} else {
+			latencies[i] = time.Since(start)
+		}
+		cancel()
+	}
+
+	nearest := p.pools[pickNearest(latencies, nearestThreshold)]
+	if p.nearest.Swap(nearest) != nearest {
+		log.Printf("PostgreSQL reads moved to %s", nearest.Config().ConnConfig.Host)
+	}
+}
+
+// pickNearest returns the index of the lowest latency, preferring the primary at index 0 unless
+// another is faster by more than threshold. Negative latencies are endpoints that didn't answer;
+// if none did, the primary is picked.
+func pickNearest(latencies []time.Duration, threshold time.Duration) int {
+	best := -1
+	for i, latency := range latencies {
+		if latency >= 0 && (best < 0 || latency < latencies[best]) {
+			best = i
+		}
+	}
+	if best < 0 || latencies[0] >= 0 && latencies[0]-latencies[best] <= threshold {
+		return 0
+	}
+	return best
+}
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPickNearest(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name      string
+		latencies []time.Duration
+		want      int
+	}{
+		{"primary only", []time.Duration{5 * ms}, 0},
+		{"replica much faster", []time.Duration{80 * ms, 3 * ms, 40 * ms}, 1},
+		{"replica barely faster", []time.Duration{12 * ms, 2 * ms}, 0},
+		{"fastest replica wins", []time.Duration{90 * ms, 40 * ms, 4 * ms}, 2},
+		{"primary down", []time.Duration{-1, 60 * ms}, 1},
+		{"replica down", []time.Duration{60 * ms, -1}, 0},
+		{"all down", []time.Duration{-1, -1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pickNearest(tt.latencies, nearestThreshold); got != tt.want {
+				t.Errorf("pickNearest(%v) = %d, want %d", tt.latencies, got, tt.want)
+			}
+		})
+	}
+}
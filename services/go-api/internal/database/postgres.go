@@ -11,6 +11,22 @@ import (
 // NewPostgresPool creates a new PostgreSQL connection pool
 // This uses pgx directly without ORM for learning raw SQL patterns
 func NewPostgresPool(ctx context.Context, connString string) (*pgxpool.Pool, error) {
+	pool, err := newPool(ctx, connString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify connection
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	return pool, nil
+}
+
+// newPool creates a connection pool without waiting for the server to answer
+func newPool(ctx context.Context, connString string) (*pgxpool.Pool, error) {
 	config, err := pgxpool.ParseConfig(connString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse postgres config: %w", err)
@@ -27,11 +43,5 @@ func NewPostgresPool(ctx context.Context, connString string) (*pgxpool.Pool, err
 	if err != nil {
 		return nil, fmt.Errorf("failed to create postgres pool: %w", err)
 	}
-
-	// Verify connection
-	if err := pool.Ping(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ping postgres: %w", err)
-	}
-
 	return pool, nil
 }
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrations are the SQL migrations this build was written against
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// ErrUnsupportedSchema is returned by CheckSchema when the database's schema isn't one this
+// build can run against
+var ErrUnsupportedSchema = errors.New("unsupported schema version")
+
+// SchemaVersion is the schema version this build expects: the number of its latest migration
+func SchemaVersion() int {
+	version, err := latestMigration(migrations)
+	if err != nil {
+		panic(err)
+	}
+	return version
+}
+
+// latestMigration returns the highest number of the NNN_*.sql files in a migrations directory
+func latestMigration(fsys fs.FS) (int, error) {
+	files, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return 0, err
+	}
+	latest := 0
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "migrations/")
+		prefix, _, ok := strings.Cut(name, "_")
+		number, err := strconv.Atoi(prefix)
+		if !ok || err != nil {
+			return 0, fmt.Errorf("migration %s isn't named NNN_description.sql", name)
+		}
+		latest = max(latest, number)
+	}
+	if latest == 0 {
+		return 0, errors.New("no migrations embedded")
+	}
+	return latest, nil
+}
+
+// CheckSchema refuses a database whose schema this build doesn't support: one missing
+// migrations this build relies on, or one migrated past what this build can safely run against.
+// During a rolling deploy the old build keeps running after the new build's migrations are
+// applied, as long as they are compatible with it.
+func CheckSchema(ctx context.Context, pool *pgxpool.Pool) error {
+	var tracked bool
+	if err := pool.QueryRow(ctx, `SELECT to_regclass('schema_version') IS NOT NULL`).Scan(&tracked); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	var version, compatibleFrom int
+	err := pgx.ErrNoRows
+	if tracked {
+		err = pool.QueryRow(ctx, `
+			SELECT version, compatible_from FROM schema_version ORDER BY version DESC LIMIT 1
+		`).Scan(&version, &compatibleFrom)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: the database doesn't record its schema version, apply the migrations up to %03d",
+			ErrUnsupportedSchema, SchemaVersion())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return checkSchemaVersion(SchemaVersion(), version, compatibleFrom)
+}
+
+// checkSchemaVersion compares the schema version a build expects with the database's version,
+// and the oldest build that database supports
+func checkSchemaVersion(build, version, compatibleFrom int) error {
+	if version < build {
+		return fmt.Errorf("%w: the database is at schema %03d and this build needs %03d, apply the migrations first",
+			ErrUnsupportedSchema, version, build)
+	}
+	if compatibleFrom > build {
+		return fmt.Errorf("%w: the database is at schema %03d, which needs builds of schema %03d or later, and this build is %03d",
+			ErrUnsupportedSchema, version, compatibleFrom, build)
+	}
+	return nil
+}
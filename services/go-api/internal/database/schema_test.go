@@ -0,0 +1,78 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// firstVersionedMigration is the migration that created schema_version
+const firstVersionedMigration = 45
+
+var recordVersion = regexp.MustCompile(`INSERT INTO schema_version \(version, compatible_from\) VALUES \((\d+), (\d+)\)`)
+
+// TestMigrationsRecordSchemaVersion keeps every migration since schema_version existed
+// recording its own version, with a compatible_from that never moves backwards
+func TestMigrationsRecordSchemaVersion(t *testing.T) {
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := 0
+	for _, file := range files {
+		number, _ := strconv.Atoi(strings.SplitN(strings.TrimPrefix(file, "migrations/"), "_", 2)[0])
+		if number < firstVersionedMigration {
+			continue
+		}
+		sql, err := fs.ReadFile(migrations, file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		match := recordVersion.FindSubmatch(sql)
+		if match == nil {
+			t.Errorf("%s doesn't record its version in schema_version", file)
+			continue
+		}
+		version, _ := strconv.Atoi(string(match[1]))
+		compatibleFrom, _ := strconv.Atoi(string(match[2]))
+		if version != number {
+			t.Errorf("%s records version %d", file, version)
+		}
+		if compatibleFrom > version || compatibleFrom < previous {
+			t.Errorf("%s records compatible_from %d, want between %d and %d", file, compatibleFrom, previous, version)
+		}
+		previous = compatibleFrom
+	}
+
+	if got := SchemaVersion(); got < firstVersionedMigration {
+		t.Errorf("SchemaVersion() = %d", got)
+	}
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	tests := []struct {
+		build, version, compatibleFrom int
+		ok                             bool
+	}{
+		{build: 45, version: 45, compatibleFrom: 44, ok: true},
+		{build: 45, version: 44, compatibleFrom: 44, ok: false}, // migrations not applied yet
+		{build: 45, version: 47, compatibleFrom: 45, ok: true},  // old build during a rolling deploy
+		{build: 45, version: 47, compatibleFrom: 46, ok: false}, // a migration this build can't run against
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("build %d schema %d from %d", tt.build, tt.version, tt.compatibleFrom), func(t *testing.T) {
+			err := checkSchemaVersion(tt.build, tt.version, tt.compatibleFrom)
+			if tt.ok && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrUnsupportedSchema) {
+				t.Errorf("error = %v, want ErrUnsupportedSchema", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Who an announcement is delivered to
+const (
+	AnnouncementAudienceAll         = "all"
+	AnnouncementAudienceGroupOwners = "group_owners" // users who created a study group that isn't archived
+	AnnouncementAudienceInactive    = "inactive"     // users with no activity for InactiveDays
+)
+
+// Announcement limits
+const (
+	MaxAnnouncementTitleLength      = 200
+	MaxAnnouncementBodyLength       = 5000
+	DefaultAnnouncementInactiveDays = 30
+	MaxAnnouncementInactiveDays     = 365
+)
+
+// Announcement is a system message admins send to users. Once published it appears in each
+// recipient's notification center, is sent as a system message to their open chat connections,
+// and is optionally emailed. The audience is resolved when it is published.
+type Announcement struct {
+	ID           uuid.UUID  `json:"id"`
+	Title        string     `json:"title"`
+	Body         string     `json:"body"`
+	Audience     string     `json:"audience"`
+	InactiveDays int        `json:"inactiveDays,omitempty"` // Only for the inactive audience
+	SendEmail    bool       `json:"sendEmail"`
+	PublishAt    time.Time  `json:"publishAt"`
+	PublishedAt  *time.Time `json:"publishedAt,omitempty"`
+	Recipients   int        `json:"recipients"` // How many users it was delivered to, once published
+	CreatedBy    uuid.UUID  `json:"createdBy"`
+	CreatedAt    time.Time  `json:"createdAt"`
+
+	// ReadAt is when the requesting user read it, in their notification center
+	ReadAt *time.Time `json:"readAt,omitempty"`
+}
+
+// AnnouncementRecipient is a user an announcement is emailed to
+type AnnouncementRecipient struct {
+	UserID uuid.UUID
+	Email  string
+}
+
+// CreateAnnouncementRequest is the payload for an admin publishing or scheduling an announcement
+type CreateAnnouncementRequest struct {
+	Title        string     `json:"title"`
+	Body         string     `json:"body"`
+	Audience     string     `json:"audience"`     // Defaults to all
+	InactiveDays int        `json:"inactiveDays"` // Defaults to 30 for the inactive audience
+	SendEmail    bool       `json:"sendEmail"`
+	PublishAt    *time.Time `json:"publishAt"` // Publishes right away when empty or past
+}
+
+// MarkAnnouncementsReadRequest marks announcements as read. No IDs marks all of them.
+type MarkAnnouncementsReadRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+// ValidAnnouncementAudience reports whether audience is one announcements can be sent to
+func ValidAnnouncementAudience(audience string) bool {
+	switch audience {
+	case AnnouncementAudienceAll, AnnouncementAudienceGroupOwners, AnnouncementAudienceInactive:
+		return true
+	}
+	return false
+}
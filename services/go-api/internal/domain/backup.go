@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// Backup statuses
+const (
+	BackupRunning   = "running"
+	BackupCompleted = "completed"
+	BackupFailed    = "failed"
+)
+
+// BackupFormatVersion is the version of the backup archive layout this build writes and reads
+const BackupFormatVersion = 1
+
+// Backup is a backup archive in the server's backup directory, or one being written
+type Backup struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Size        int64      `json:"size,omitempty"` // Bytes, once completed
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	Error       string     `json:"error,omitempty"` // Why a failed backup failed
+}
+
+// BackupManifest describes what a backup archive holds. Postgres tables are dumped from one
+// snapshot taken at SnapshotAt; Mongo documents created after it are left out, but Mongo is
+// otherwise read live, so it only approximately matches that moment.
+type BackupManifest struct {
+	Version    int          `json:"version"`
+	SnapshotAt time.Time    `json:"snapshotAt"`
+	Postgres   []BackupPart `json:"postgres"`
+	Mongo      []BackupPart `json:"mongo"`
+}
+
+// BackupPart is one table or collection in a backup archive
+type BackupPart struct {
+	Name   string `json:"name"`
+	File   string `json:"file"` // Path in the archive
+	Rows   int64  `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+// BackupVerification is the result of checking that a backup archive can be restored: every
+// part is present, intact, and parses, and references from Postgres to Mongo resolve
+type BackupVerification struct {
+	Name     string              `json:"name,omitempty"`
+	Valid    bool                `json:"valid"`
+	Error    string              `json:"error,omitempty"` // The first problem found in an invalid archive
+	Manifest *BackupManifest     `json:"manifest,omitempty"`
+	Dangling []DanglingReference `json:"dangling"` // Don't make an archive invalid; the API tolerates them
+}
+
+// DanglingReference counts rows of a Postgres table pointing at Mongo documents missing from a
+// backup
+type DanglingReference struct {
+	Table      string `json:"table"`
+	Column     string `json:"column"`
+	Collection string `json:"collection"`
+	Count      int    `json:"count"`
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Subscription plan IDs
+const (
+	PlanFree = "free"
+	PlanPro  = "pro"
+	PlanTeam = "team"
+)
+
+// Plan-gated features
+const (
+	FeatureOrgAnalytics = "org_analytics"
+)
+
+// Subscription statuses mirrored from Stripe
+const (
+	SubscriptionActive   = "active"
+	SubscriptionTrialing = "trialing"
+	SubscriptionPastDue  = "past_due"
+	SubscriptionCanceled = "canceled"
+)
+
+// PlanLimits caps resource usage on a plan; zero means unlimited
+type PlanLimits struct {
+	MaxSnippets   int `json:"maxSnippets"`
+	MaxWorkspaces int `json:"maxWorkspaces"` // shared workspaces and organizations owned
+	MaxSeats      int `json:"maxSeats"`      // seats per organization
+}
+
+// Plan is a subscription tier with its limits and gated features
+type Plan struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name"`
+	Limits   PlanLimits `json:"limits"`
+	Features []string   `json:"features"`
+}
+
+// HasFeature reports whether the plan includes a gated feature
+func (p *Plan) HasFeature(feature string) bool {
+	for _, f := range p.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// Plans lists every plan, cheapest first
+var Plans = []Plan{
+	{
+		ID:       PlanFree,
+		Name:     "Free",
+		Limits:   PlanLimits{MaxSnippets: 100, MaxWorkspaces: 1, MaxSeats: 10},
+		Features: []string{},
+	},
+	{
+		ID:       PlanPro,
+		Name:     "Pro",
+		Limits:   PlanLimits{MaxWorkspaces: 5, MaxSeats: 25},
+		Features: []string{},
+	},
+	{
+		ID:       PlanTeam,
+		Name:     "Team",
+		Limits:   PlanLimits{},
+		Features: []string{FeatureOrgAnalytics},
+	},
+}
+
+// FindPlan returns the plan with the given ID, or nil
+func FindPlan(id string) *Plan {
+	for i := range Plans {
+		if Plans[i].ID == id {
+			return &Plans[i]
+		}
+	}
+	return nil
+}
+
+// Subscription is a user's billing state, kept in sync with Stripe by webhooks
+type Subscription struct {
+	UserID               uuid.UUID  `json:"userId"`
+	Plan                 string     `json:"plan"`
+	Status               string     `json:"status"`
+	StripeCustomerID     string     `json:"-"`
+	StripeSubscriptionID string     `json:"-"`
+	CurrentPeriodEnd     *time.Time `json:"currentPeriodEnd,omitempty"`
+	CancelAtPeriodEnd    bool       `json:"cancelAtPeriodEnd"`
+	UpdatedAt            time.Time  `json:"updatedAt"`
+}
+
+// EffectivePlan returns the plan the subscription currently grants.
+// Past-due subscriptions keep their plan while Stripe retries payment.
+func (s *Subscription) EffectivePlan() string {
+	if s == nil {
+		return PlanFree
+	}
+	switch s.Status {
+	case SubscriptionActive, SubscriptionTrialing, SubscriptionPastDue:
+		return s.Plan
+	default:
+		return PlanFree
+	}
+}
+
+// CheckoutRequest represents the request to start a Stripe checkout for a plan
+type CheckoutRequest struct {
+	Plan string `json:"plan"`
+}
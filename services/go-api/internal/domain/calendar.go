@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeed is a user's private iCal feed. The token is shown once when the feed is created.
+type CalendarFeed struct {
+	UserID         uuid.UUID  `json:"-"`
+	TokenHash      string     `json:"-"`
+	URL            string     `json:"url,omitempty"` // includes the token; only set when created
+	CreatedAt      time.Time  `json:"createdAt"`
+	LastAccessedAt *time.Time `json:"lastAccessedAt,omitempty"`
+}
+
+// ReviewDay counts the review items falling due on one day
+type ReviewDay struct {
+	Date  time.Time
+	Count int
+}
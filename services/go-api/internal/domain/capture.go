@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Capture kinds: what a capture becomes when it is converted. Captures are suggested as an
+// entry or snippet; bookmarks are only made by converting one.
+const (
+	CaptureEntry    = "entry"
+	CaptureSnippet  = "snippet"
+	CaptureBookmark = "bookmark"
+)
+
+// Capture statuses. Captures wait in the inbox until they are converted, archived for later
+// reference, or dismissed.
+const (
+	CaptureInbox     = "inbox"
+	CaptureConverted = "converted"
+	CaptureArchived  = "archived"
+	CaptureDismissed = "dismissed"
+)
+
+// CaptureTag is added to everything captured through the inbox
+const CaptureTag = "capture"
+
+// Capture is text sent to the quick-capture inbox by a browser extension, an AI chat, or any
+// other client, waiting to be triaged into a journal entry, snippet, or bookmark
+type Capture struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"-"`
+	Source      string    `json:"source"` // where it was captured, e.g. browser-extension or chatgpt
+	Kind        string    `json:"kind"`
+	Title       string    `json:"title"`
+	Content     string    `json:"content"`            // Markdown for entries, code for snippets
+	Language    string    `json:"language,omitempty"` // for snippets
+	URL         string    `json:"url,omitempty"`      // the page or conversation it was captured from
+	Tags        []string  `json:"tags"`
+	Status      string    `json:"status"`
+	PublishedID string    `json:"publishedId,omitempty"` // the entry, snippet, or bookmark made from it, once converted
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// CaptureRequest sends text to the inbox. Only source and content are required; the kind,
+// title, and tags are worked out from the content when omitted.
+type CaptureRequest struct {
+	Source  string   `json:"source"`
+	Content string   `json:"content"`
+	Title   string   `json:"title"`
+	URL     string   `json:"url"`
+	Kind    string   `json:"kind"`
+	Tags    []string `json:"tags"` // added to the suggested tags
+}
+
+// UpdateCaptureRequest represents the request to edit a capture before converting it
+type UpdateCaptureRequest struct {
+	Kind     string   `json:"kind"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Language string   `json:"language"`
+	Tags     []string `json:"tags"`
+}
+
+// ConvertCaptureRequest represents the request to turn a capture into an entry, snippet, or
+// bookmark. To defaults to the capture's kind.
+type ConvertCaptureRequest struct {
+	To                 string `json:"to"`
+	AcknowledgeSecrets bool   `json:"acknowledgeSecrets"` // create a snippet even if high severity secrets are detected
+	AllowDuplicate     bool   `json:"allowDuplicate"`     // create a snippet even if a very similar one exists
+}
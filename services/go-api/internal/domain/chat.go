@@ -1,38 +1,51 @@
 package domain
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// StudyGroup represents a chat room for study collaboration
+// StudyGroup represents a chat room for study collaboration. Groups inactive for too long are
+// archived: read-only and hidden from discovery until reactivated.
 type StudyGroup struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	IsPublic    bool      `json:"isPublic"`
-	MaxMembers  int       `json:"maxMembers"`
-	CreatedBy   uuid.UUID `json:"createdBy"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID              uuid.UUID  `json:"id"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	IsPublic        bool       `json:"isPublic"`
+	MaxMembers      int        `json:"maxMembers"`
+	CreatedBy       uuid.UUID  `json:"createdBy"`
+	WorkspaceID     uuid.UUID  `json:"workspaceId"`
+	LastActiveAt    time.Time  `json:"lastActiveAt"`              // Last chat message or join
+	ArchiveWarnedAt *time.Time `json:"archiveWarnedAt,omitempty"` // When the owner was warned of archival
+	ArchivedAt      *time.Time `json:"archivedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
 }
 
 // NewStudyGroup creates a new study group
 func NewStudyGroup(name, description string, isPublic bool, maxMembers int, createdBy uuid.UUID) *StudyGroup {
 	now := time.Now().UTC()
 	return &StudyGroup{
-		ID:          uuid.New(),
-		Name:        name,
-		Description: description,
-		IsPublic:    isPublic,
-		MaxMembers:  maxMembers,
-		CreatedBy:   createdBy,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:           uuid.New(),
+		Name:         name,
+		Description:  description,
+		IsPublic:     isPublic,
+		MaxMembers:   maxMembers,
+		CreatedBy:    createdBy,
+		LastActiveAt: now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
 	}
 }
 
+// Archived reports whether the group was archived for inactivity
+func (g *StudyGroup) Archived() bool {
+	return g.ArchivedAt != nil
+}
+
 // StudyGroupMember represents membership in a study group
 type StudyGroupMember struct {
 	GroupID     uuid.UUID `json:"groupId"`
@@ -42,6 +55,37 @@ type StudyGroupMember struct {
 	JoinedAt    time.Time `json:"joinedAt"`
 }
 
+// GroupInviteTTL is how long an invite to a private study group can be used
+const GroupInviteTTL = 7 * 24 * time.Hour
+
+// GroupInvite lets one person join a private study group, which non-members can't otherwise see.
+// Only a hash of its code is stored, and joining with it uses it up.
+type GroupInvite struct {
+	ID        uuid.UUID `json:"id"`
+	GroupID   uuid.UUID `json:"groupId"`
+	Code      string    `json:"code,omitempty"` // Only returned when the invite is created
+	CodeHash  string    `json:"-"`
+	CreatedBy uuid.UUID `json:"createdBy"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// JoinGroupRequest is the optional payload for joining a study group
+type JoinGroupRequest struct {
+	InviteCode string `json:"inviteCode"` // required to join a private group
+}
+
+// MemberProfile is the public profile of a study group member, a projection of their account
+// that leaves out their email and other private fields
+type MemberProfile struct {
+	UserID          uuid.UUID
+	DisplayName     string
+	AvatarURL       string // empty for the default avatar
+	StreakMilestone int    // the longest of StreakMilestones they reached, or 0
+	Role            string
+	JoinedAt        time.Time
+}
+
 // ChatMessage represents a message in a study group
 type ChatMessage struct {
 	ID              string    `json:"id"`
@@ -49,8 +93,29 @@ type ChatMessage struct {
 	UserID          string    `json:"userId"`
 	UserDisplayName string    `json:"userDisplayName"`
 	Content         string    `json:"content"`
-	Type            string    `json:"type"` // message, join, leave
+	Type            string    `json:"type"`             // message, join, leave, system, poll, poll_results, or a WebRTC signal type
+	Source          string    `json:"source,omitempty"` // integration the message was relayed from, e.g. slack
 	Timestamp       time.Time `json:"timestamp"`
+
+	// ReplyTo is the ID of the message a reply starts a thread under. Threads are one level
+	// deep, so it is always the thread's first message. ReplyCount on a reply is the number of
+	// replies in the thread including it, for clients to update the thread's counter.
+	ReplyTo    string `json:"replyTo,omitempty"`
+	ReplyCount int    `json:"replyCount,omitempty"`
+
+	// ConnectionID identifies the recipient's WebSocket session on system notices sent only to
+	// them, matching the X-Request-ID of the upgrade request
+	ConnectionID string `json:"connectionId,omitempty"`
+
+	// WebRTC signaling fields (offer/answer/ice are relayed only to TargetUserID)
+	TargetUserID string          `json:"targetUserId,omitempty"`
+	Signal       json.RawMessage `json:"signal,omitempty"` // Opaque SDP or ICE candidate payload
+
+	// Poll is the poll a poll message posts, or its new counts on a poll_results message
+	Poll *Poll `json:"poll,omitempty"`
+
+	// Announcement is the announcement a system message from an admin delivers
+	Announcement *Announcement `json:"announcement,omitempty"`
 }
 
 // NewChatMessage creates a new chat message
@@ -65,3 +130,111 @@ func NewChatMessage(room, userID, displayName, content, msgType string) *ChatMes
 		Timestamp:       time.Now().UTC(),
 	}
 }
+
+// NewSignalMessage creates a WebRTC signaling message addressed to a single peer
+func NewSignalMessage(room, userID, displayName, msgType, targetUserID string, signal json.RawMessage) *ChatMessage {
+	message := NewChatMessage(room, userID, displayName, "", msgType)
+	message.TargetUserID = targetUserID
+	message.Signal = signal
+	return message
+}
+
+// ChatThread is a message and the replies to it, oldest first
+type ChatThread struct {
+	Root    *ChatMessage   `json:"root"`
+	Replies []*ChatMessage `json:"replies"`
+}
+
+// Group chat channel posting policies
+const (
+	ChannelPostMembers = "members" // Any group member can post
+	ChannelPostAdmins  = "admins"  // Only group owners and admins can post, e.g. announcements
+)
+
+// DefaultChannel is the channel every group has. Its room is the bare group ID, the room group
+// chat used before groups had channels.
+const DefaultChannel = "general"
+
+// GroupChannel is a named chat channel in a study group
+type GroupChannel struct {
+	GroupID    uuid.UUID `json:"groupId"`
+	Name       string    `json:"name"`
+	PostPolicy string    `json:"postPolicy"` // members, admins
+	Room       string    `json:"room"`       // Chat room to request a ticket for
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// NewGroupChannel creates a channel in a group
+func NewGroupChannel(groupID uuid.UUID, name, postPolicy string) *GroupChannel {
+	return &GroupChannel{
+		GroupID:    groupID,
+		Name:       name,
+		PostPolicy: postPolicy,
+		Room:       ChannelRoom(groupID, name),
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+// CreateGroupChannelRequest is the payload for adding a channel to a group
+type CreateGroupChannelRequest struct {
+	Name       string `json:"name"`
+	PostPolicy string `json:"postPolicy"` // Defaults to members
+}
+
+// UpdateGroupChannelRequest is the payload for changing who can post in a channel
+type UpdateGroupChannelRequest struct {
+	PostPolicy string `json:"postPolicy"`
+}
+
+// ChannelRoom returns the chat room for a group channel: "<group>" for the default channel and
+// "<group>:<channel>" for the rest
+func ChannelRoom(groupID uuid.UUID, channel string) string {
+	if channel == "" || channel == DefaultChannel {
+		return groupID.String()
+	}
+	return groupID.String() + ":" + channel
+}
+
+// GroupRoom returns the group and channel of a group chat room, or false if room is not one
+func GroupRoom(room string) (uuid.UUID, string, bool) {
+	group, channel, hasChannel := strings.Cut(room, ":")
+	groupID, err := uuid.Parse(group)
+	if err != nil || (hasChannel && channel == "") {
+		return uuid.Nil, "", false
+	}
+	if !hasChannel {
+		channel = DefaultChannel
+	}
+	return groupID, channel, true
+}
+
+// ChatTicket is a one-time pass to open a chat WebSocket in one room. Browsers can't send
+// headers on a WebSocket, so a short-lived ticket goes in its URL instead of a token.
+type ChatTicket struct {
+	TicketHash string
+	UserID     uuid.UUID
+	Room       string
+	ReadOnly   bool // The user can't post chat messages in the room, e.g. an announcements channel
+	ExpiresAt  time.Time
+
+	// The user can join a voice session in the room but not start one
+	JoinVoiceOnly bool
+
+	// The user's current name and email, read when the ticket is redeemed
+	DisplayName string
+	Email       string
+}
+
+// CreateChatTicketRequest is the payload for requesting a chat ticket
+type CreateChatTicketRequest struct {
+	Room string `json:"room"`
+}
+
+// ChatTicketResponse is a chat ticket, to pass as ?ticket= when opening /ws/chat/{room}
+type ChatTicketResponse struct {
+	Ticket        string    `json:"ticket"`
+	Room          string    `json:"room"`
+	ReadOnly      bool      `json:"readOnly"`
+	JoinVoiceOnly bool      `json:"joinVoiceOnly"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Code review statuses
+const (
+	CodeReviewOpen     = "open"
+	CodeReviewResolved = "resolved"
+)
+
+// CodeReview is a snippet shared into a study group for review. The snippet's files are copied
+// when the review is requested, so comments stay anchored to the lines they were written against
+// even if the snippet is edited later.
+type CodeReview struct {
+	ID           uuid.UUID           `json:"id"`
+	GroupID      uuid.UUID           `json:"groupId"`
+	UserID       uuid.UUID           `json:"userId"`
+	AuthorName   string              `json:"authorName"`
+	SnippetID    string              `json:"snippetId"`
+	Title        string              `json:"title"`
+	Note         string              `json:"note"`
+	Files        []SnippetFile       `json:"files,omitempty"` // left out of lists
+	Status       string              `json:"status"`          // open, resolved
+	CommentCount int                 `json:"commentCount"`
+	OpenComments int                 `json:"openComments"` // comments not yet marked resolved
+	Comments     []CodeReviewComment `json:"comments,omitempty"`
+	ResolvedAt   *time.Time          `json:"resolvedAt,omitempty"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	UpdatedAt    time.Time           `json:"updatedAt"`
+}
+
+// CodeReviewComment is a reviewer's comment on a line of one of a review's files
+type CodeReviewComment struct {
+	ID         uuid.UUID  `json:"id"`
+	ReviewID   uuid.UUID  `json:"reviewId"`
+	UserID     uuid.UUID  `json:"userId"`
+	AuthorName string     `json:"authorName"`
+	File       string     `json:"file"`
+	Line       int        `json:"line"` // 1-based
+	Body       string     `json:"body"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreateCodeReviewRequest represents the request to share a snippet into a group for review
+type CreateCodeReviewRequest struct {
+	SnippetID string `json:"snippetId"`
+	Note      string `json:"note"`
+}
+
+// CodeReviewCommentRequest represents the request to comment on a line of a review
+type CodeReviewCommentRequest struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Body string `json:"body"`
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"strings"
+)
+
+// MaxExtractedSnippets caps the snippets created from one journal entry
+const MaxExtractedSnippets = 50
+
+// CodeBlock is a fenced code block in Markdown
+type CodeBlock struct {
+	Language string // from the info string, normalized to a snippet language
+	Code     string
+	Line     int // 1-based line of the opening fence
+}
+
+// EntrySnippetSource links a snippet back to the journal entry it was extracted from.
+// It is stored under the "entry" key of the snippet's metadata.
+type EntrySnippetSource struct {
+	EntryID string `json:"entryId" bson:"entryId"`
+	Block   int    `json:"block" bson:"block"` // 0-based index of the code block in the entry
+	Line    int    `json:"line" bson:"line"`
+}
+
+// ExtractSnippetsResult lists the snippets created from an entry's code blocks
+type ExtractSnippetsResult struct {
+	Created []Snippet `json:"created"`
+	Skipped int       `json:"skipped"` // blocks already extracted, or empty
+}
+
+// fenceLanguages maps info string names that are not file extensions to snippet languages
+var fenceLanguages = map[string]string{
+	"golang": "go", "shell": "bash", "console": "bash", "c++": "cpp", "c#": "csharp",
+	"node": "javascript", "py3": "python", "docker": "dockerfile", "make": "makefile",
+}
+
+// ExtractCodeBlocks returns the fenced code blocks of a Markdown document in order. Fences are
+// three or more backticks or tildes, indented at most three spaces, and an unclosed block runs
+// to the end of the document as in CommonMark.
+func ExtractCodeBlocks(markdown string) []CodeBlock {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	var blocks []CodeBlock
+	for i := 0; i < len(lines); i++ {
+		indent, fence, info, ok := openingFence(lines[i])
+		if !ok {
+			continue
+		}
+
+		block := CodeBlock{Language: FenceLanguage(info), Line: i + 1}
+		var code []string
+		for i++; i < len(lines); i++ {
+			if closesFence(lines[i], fence) {
+				break
+			}
+			code = append(code, stripIndent(lines[i], indent))
+		}
+		block.Code = strings.Join(code, "\n")
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// FenceLanguage maps a code fence info string like "ts" or "go title=main.go" to a snippet language
+func FenceLanguage(info string) string {
+	fields := strings.Fields(info)
+	if len(fields) == 0 {
+		return "text"
+	}
+	name := strings.ToLower(strings.TrimPrefix(strings.Trim(fields[0], "{}"), "."))
+	if language, ok := fenceLanguages[name]; ok {
+		return language
+	}
+	if language, ok := extensionLanguages["."+name]; ok {
+		return language
+	}
+	if language := NormalizeEditorLanguage(name); language != "" {
+		return language
+	}
+	return "text"
+}
+
+// openingFence parses a line that opens a fenced code block
+func openingFence(line string) (indent int, fence, info string, ok bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	indent = len(line) - len(trimmed)
+	if indent > 3 || len(trimmed) < 3 || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return 0, "", "", false
+	}
+	n := len(trimmed) - len(strings.TrimLeft(trimmed, trimmed[:1]))
+	if n < 3 {
+		return 0, "", "", false
+	}
+	fence, info = trimmed[:n], strings.TrimSpace(trimmed[n:])
+	// Backticks can't appear in the info string of a backtick fence
+	if fence[0] == '`' && strings.Contains(info, "`") {
+		return 0, "", "", false
+	}
+	return indent, fence, info, true
+}
+
+// closesFence reports whether a line closes a block opened with fence
+func closesFence(line, fence string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return false
+	}
+	trimmed = strings.TrimRight(trimmed, " \t")
+	return len(trimmed) >= len(fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// stripIndent removes up to n leading spaces, the indentation of the opening fence
+func stripIndent(line string, n int) string {
+	for i := 0; i < n && strings.HasPrefix(line, " "); i++ {
+		line = line[1:]
+	}
+	return line
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CodeStats holds line counts and rough complexity metrics for a snippet's code
+type CodeStats struct {
+	Lines        int `json:"lines" bson:"lines"`
+	CodeLines    int `json:"codeLines" bson:"code_lines"`
+	CommentLines int `json:"commentLines" bson:"comment_lines"`
+	BlankLines   int `json:"blankLines" bson:"blank_lines"`
+	Complexity   int `json:"complexity" bson:"complexity"`  // approximate cyclomatic complexity: 1 + branch points
+	MaxNesting   int `json:"maxNesting" bson:"max_nesting"` // deepest brace or indentation level
+}
+
+// lineCommentPrefixes maps languages to their single-line comment markers
+var lineCommentPrefixes = map[string]string{
+	"python":     "#",
+	"ruby":       "#",
+	"shell":      "#",
+	"bash":       "#",
+	"yaml":       "#",
+	"r":          "#",
+	"perl":       "#",
+	"dockerfile": "#",
+	"sql":        "--",
+	"lua":        "--",
+	"haskell":    "--",
+	"html":       "<!--",
+	"css":        "/*",
+}
+
+// indentLanguages use indentation rather than braces for nesting
+var indentLanguages = map[string]bool{
+	"python": true,
+	"yaml":   true,
+}
+
+// branchPattern matches common branch points across languages
+var branchPattern = regexp.MustCompile(`\b(if|elif|for|foreach|while|case|catch|except|when|guard)\b|&&|\|\||\?\?`)
+
+// ComputeCodeStats counts lines and estimates complexity for code in the given language
+func ComputeCodeStats(code, language string) CodeStats {
+	language = strings.ToLower(language)
+	commentPrefix, ok := lineCommentPrefixes[language]
+	if !ok {
+		commentPrefix = "//"
+	}
+
+	stats := CodeStats{Complexity: 1}
+	if code == "" {
+		return stats
+	}
+
+	depth := 0
+	inBlockComment := false
+	for _, line := range strings.Split(strings.TrimRight(code, "\n"), "\n") {
+		stats.Lines++
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			stats.BlankLines++
+			continue
+		case inBlockComment:
+			stats.CommentLines++
+			if strings.Contains(trimmed, "*/") {
+				inBlockComment = false
+			}
+			continue
+		case strings.HasPrefix(trimmed, "/*"):
+			stats.CommentLines++
+			inBlockComment = !strings.Contains(trimmed, "*/")
+			continue
+		case strings.HasPrefix(trimmed, commentPrefix):
+			stats.CommentLines++
+			continue
+		}
+
+		stats.CodeLines++
+		stats.Complexity += len(branchPattern.FindAllString(trimmed, -1))
+
+		if indentLanguages[language] {
+			stats.MaxNesting = max(stats.MaxNesting, indentLevel(line))
+			continue
+		}
+		for _, ch := range trimmed {
+			switch ch {
+			case '{':
+				depth++
+				stats.MaxNesting = max(stats.MaxNesting, depth)
+			case '}':
+				if depth > 0 {
+					depth--
+				}
+			}
+		}
+	}
+
+	return stats
+}
+
+// indentLevel returns the indentation depth of a line, treating a tab or four spaces as one level
+func indentLevel(line string) int {
+	width := 0
+	for _, ch := range line {
+		switch ch {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width / 4
+		}
+	}
+	return width / 4
+}
+
+// LanguageLinesPeriod aggregates snippet lines of code for one language over one period
+type LanguageLinesPeriod struct {
+	Period   string `json:"period" bson:"period"` // YYYY-MM, or YYYY-Www when grouped by week
+	Language string `json:"language" bson:"language"`
+	Snippets int    `json:"snippets" bson:"snippets"`
+	Lines    int    `json:"lines" bson:"lines"`
+}
+
+// SnippetCodeStats summarizes lines of code by language over time
+type SnippetCodeStats struct {
+	GroupBy    string                `json:"groupBy"` // week or month
+	TotalLines int                   `json:"totalLines"`
+	ByLanguage map[string]int        `json:"byLanguage"`
+	Periods    []LanguageLinesPeriod `json:"periods"` // oldest first
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Coding time sources. A user has one at a time: syncing from WakaTime, or editor plugins sending
+// heartbeats straight to DevJournal.
+const (
+	CodingSourceWakaTime  = "wakatime"
+	CodingSourceHeartbeat = "heartbeat"
+)
+
+// CodingSource is where a user's coding time comes from. The heartbeat token is shown once when
+// it is created.
+type CodingSource struct {
+	UserID          uuid.UUID  `json:"-"`
+	Provider        string     `json:"provider"`
+	APIKey          string     `json:"-"` // WakaTime API key
+	TokenHash       string     `json:"-"` // heartbeat token hash
+	Token           string     `json:"token,omitempty"`
+	APIURL          string     `json:"apiUrl,omitempty"` // api_url for editor plugins sending heartbeats
+	LastHeartbeatAt *time.Time `json:"-"`
+	LastLanguage    string     `json:"-"`
+	SyncedAt        *time.Time `json:"syncedAt,omitempty"`
+	SyncError       string     `json:"syncError,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// ConnectWakaTimeRequest is the body of PUT /api/coding/wakatime
+type ConnectWakaTimeRequest struct {
+	APIKey string `json:"apiKey"`
+}
+
+// CodingDay is the coding time of one day, in seconds by language
+type CodingDay struct {
+	Date    time.Time
+	Seconds map[string]int
+}
+
+// LanguageTime is the coding time spent in a language
+type LanguageTime struct {
+	Language string `json:"language"`
+	Minutes  int    `json:"minutes"`
+}
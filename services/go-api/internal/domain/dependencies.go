@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxDependencyLength caps dependency names, which come from snippet code
+const maxDependencyLength = 200
+
+var (
+	// import x from "pkg", import "pkg", export * from "pkg", require("pkg"), import("pkg")
+	jsImportPattern = regexp.MustCompile(`(?m)(?:^\s*(?:import|export)\b[^'"` + "`" + `;]*?\bfrom\s*|^\s*import\s*|\brequire\s*\(\s*|\bimport\s*\(\s*)['"]([^'"\s]+)['"]`)
+
+	// import a.b, c as d / from a.b import x
+	pythonImportPattern = regexp.MustCompile(`(?m)^\s*import\s+([\w., \t]+)`)
+	pythonFromPattern   = regexp.MustCompile(`(?m)^\s*from\s+([\w.]+)\s+import\b`)
+
+	// import "fmt", import alias "path", and the lines of import ( ... ) blocks
+	goImportPattern = regexp.MustCompile(`(?m)^\s*import\s+(?:[\w.]+\s+)?"([^"]+)"`)
+	goBlockPattern  = regexp.MustCompile(`(?s)\bimport\s*\(([^)]*)\)`)
+	goSpecPattern   = regexp.MustCompile(`(?m)^\s*(?:[\w.]+\s+)?"([^"]+)"`)
+
+	// use serde::Deserialize, extern crate rand
+	rustUsePattern = regexp.MustCompile(`(?m)^\s*(?:pub\s+)?(?:use\s+(?:::)?|extern\s+crate\s+)(\w+)`)
+
+	// require 'json', gem 'rails'
+	rubyRequirePattern = regexp.MustCompile(`(?m)^\s*(?:require|gem)\s*\(?\s*['"]([^'"]+)['"]`)
+
+	// import java.util.List; import static org.junit.Assert.*; import kotlinx.coroutines.launch
+	jvmImportPattern = regexp.MustCompile(`(?m)^\s*import\s+(?:static\s+)?([\w.]+)`)
+)
+
+// rustBuiltinCrates are paths of a crate's own modules or the standard library, not dependencies
+var rustBuiltinCrates = map[string]bool{
+	"std": true, "core": true, "alloc": true, "crate": true, "self": true, "super": true,
+}
+
+// ExtractDependencies returns the packages code imports, sorted: npm packages for JavaScript
+// and TypeScript, top-level modules for Python, import paths for Go, crates for Rust, gems
+// for Ruby, and packages for Java and Kotlin. Relative imports are left out.
+func ExtractDependencies(code, language string) []string {
+	found := make(map[string]bool)
+	add := func(name string) {
+		// Relative imports are part of the snippet's own project
+		name = strings.TrimSpace(name)
+		if name != "" && !strings.HasPrefix(name, ".") && len(name) <= maxDependencyLength {
+			found[name] = true
+		}
+	}
+
+	switch strings.ToLower(language) {
+	case "javascript", "typescript", "vue", "svelte":
+		for _, m := range jsImportPattern.FindAllStringSubmatch(code, -1) {
+			add(npmPackage(m[1]))
+		}
+	case "python":
+		for _, m := range pythonImportPattern.FindAllStringSubmatch(code, -1) {
+			for _, module := range strings.Split(m[1], ",") {
+				module, _, _ = strings.Cut(strings.TrimSpace(module), " ")
+				add(pythonPackage(module))
+			}
+		}
+		for _, m := range pythonFromPattern.FindAllStringSubmatch(code, -1) {
+			add(pythonPackage(m[1]))
+		}
+	case "go":
+		for _, m := range goImportPattern.FindAllStringSubmatch(code, -1) {
+			add(m[1])
+		}
+		for _, block := range goBlockPattern.FindAllStringSubmatch(code, -1) {
+			for _, m := range goSpecPattern.FindAllStringSubmatch(block[1], -1) {
+				add(m[1])
+			}
+		}
+	case "rust":
+		for _, m := range rustUsePattern.FindAllStringSubmatch(code, -1) {
+			if !rustBuiltinCrates[m[1]] {
+				add(m[1])
+			}
+		}
+	case "ruby":
+		for _, m := range rubyRequirePattern.FindAllStringSubmatch(code, -1) {
+			add(m[1])
+		}
+	case "java", "kotlin", "scala":
+		for _, m := range jvmImportPattern.FindAllStringSubmatch(code, -1) {
+			add(jvmPackage(m[1]))
+		}
+	}
+
+	deps := make([]string, 0, len(found))
+	for name := range found {
+		deps = append(deps, name)
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// npmPackage returns the package of a module specifier: "lodash/fp" is lodash and
+// "@angular/core/testing" is @angular/core. Relative paths and URLs have no package.
+func npmPackage(spec string) string {
+	if strings.HasPrefix(spec, ".") || strings.HasPrefix(spec, "/") || strings.Contains(spec, "://") {
+		return ""
+	}
+	parts := strings.SplitN(spec, "/", 3)
+	if strings.HasPrefix(spec, "@") {
+		if len(parts) < 2 {
+			return ""
+		}
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
+
+// pythonPackage returns the top-level package of a module path; relative imports have none
+func pythonPackage(module string) string {
+	if strings.HasPrefix(module, ".") {
+		return ""
+	}
+	top, _, _ := strings.Cut(module, ".")
+	return top
+}
+
+// jvmPackage drops the class, member, or wildcard from an import to leave its package
+func jvmPackage(path string) string {
+	parts := strings.Split(strings.TrimSuffix(path, "."), ".")
+	for i, part := range parts {
+		if part == "" {
+			return ""
+		}
+		// Packages are lowercase by convention; the first capitalized part is a class
+		if part[0] >= 'A' && part[0] <= 'Z' {
+			parts = parts[:i]
+			break
+		}
+	}
+	if len(parts) < 2 {
+		return ""
+	}
+	return strings.Join(parts, ".")
+}
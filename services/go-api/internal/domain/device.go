@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Device authorization statuses
+const (
+	DeviceAuthPending  = "pending"
+	DeviceAuthApproved = "approved"
+	DeviceAuthDenied   = "denied"
+	DeviceAuthConsumed = "consumed" // approved and exchanged for a token
+)
+
+// Device authorization polling errors, named as in RFC 8628 so plugins can reuse OAuth client code
+const (
+	DeviceErrAuthorizationPending = "authorization_pending"
+	DeviceErrSlowDown             = "slow_down"
+	DeviceErrAccessDenied         = "access_denied"
+	DeviceErrExpiredToken         = "expired_token"
+)
+
+// DeviceAuthorization is a pending sign-in from a device that cannot show a login form,
+// such as an editor plugin. The plugin polls with the device code while the user enters
+// the user code on the web app.
+type DeviceAuthorization struct {
+	ID             uuid.UUID  `json:"id"`
+	DeviceCodeHash string     `json:"-"`
+	UserCode       string     `json:"userCode"`
+	ClientName     string     `json:"clientName"`
+	Status         string     `json:"status"`
+	UserID         *uuid.UUID `json:"-"`
+	PollInterval   int        `json:"-"` // seconds
+	LastPolledAt   *time.Time `json:"-"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// Expired reports whether the authorization can no longer be approved or exchanged
+func (d *DeviceAuthorization) Expired(now time.Time) bool {
+	return !now.Before(d.ExpiresAt)
+}
+
+// StartDeviceAuthRequest starts a device sign-in
+type StartDeviceAuthRequest struct {
+	ClientName string `json:"clientName"` // shown on the approval page, e.g. "VS Code"
+}
+
+// DeviceCodeResponse tells the device what to show the user and how to poll
+type DeviceCodeResponse struct {
+	DeviceCode              string `json:"deviceCode"`
+	UserCode                string `json:"userCode"`
+	VerificationURI         string `json:"verificationUri"`
+	VerificationURIComplete string `json:"verificationUriComplete"`
+	ExpiresIn               int    `json:"expiresIn"` // seconds
+	Interval                int    `json:"interval"`  // minimum seconds between polls
+}
+
+// DeviceTokenRequest polls for the result of a device sign-in
+type DeviceTokenRequest struct {
+	DeviceCode string `json:"deviceCode"`
+}
+
+// DeviceApprovalRequest approves or denies a device sign-in from the web app
+type DeviceApprovalRequest struct {
+	UserCode string `json:"userCode"`
+}
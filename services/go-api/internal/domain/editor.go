@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// MaxCaptureLength caps the code captured from an editor selection, and the text sent to the
+// capture inbox
+const MaxCaptureLength = 100_000
+
+// SnippetSource records where in a codebase a snippet was captured from.
+// It is stored under the "source" key of the snippet's metadata.
+type SnippetSource struct {
+	FilePath   string `json:"filePath" bson:"filePath"` // relative to the workspace root
+	StartLine  int    `json:"startLine,omitempty" bson:"startLine,omitempty"`
+	EndLine    int    `json:"endLine,omitempty" bson:"endLine,omitempty"`
+	Repository string `json:"repository,omitempty" bson:"repository,omitempty"`
+	Branch     string `json:"branch,omitempty" bson:"branch,omitempty"`
+	Commit     string `json:"commit,omitempty" bson:"commit,omitempty"`
+	Editor     string `json:"editor,omitempty" bson:"editor,omitempty"` // e.g. "vscode"
+}
+
+// CaptureSnippetRequest saves an editor selection as a snippet. Only Code and FilePath
+// are required; language and title are derived from the file when omitted.
+type CaptureSnippetRequest struct {
+	Code        string   `json:"code"`
+	Language    string   `json:"language"` // editor language ID, e.g. "typescriptreact"
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	IsPublic    bool     `json:"isPublic"`
+	// AllowDuplicate saves the selection even if a very similar snippet exists
+	AllowDuplicate bool `json:"allowDuplicate"`
+	SnippetSource
+}
+
+// SnippetRequest builds the snippet to create, filling in language and title from the source
+func (r *CaptureSnippetRequest) SnippetRequest() *CreateSnippetRequest {
+	language := NormalizeEditorLanguage(r.Language)
+	if language == "" {
+		language = LanguageFromPath(r.FilePath)
+	}
+
+	title := strings.TrimSpace(r.Title)
+	if title == "" {
+		title = path.Base(strings.ReplaceAll(r.FilePath, "\\", "/"))
+		switch {
+		case r.StartLine > 0 && r.EndLine > r.StartLine:
+			title += fmt.Sprintf(" (lines %d-%d)", r.StartLine, r.EndLine)
+		case r.StartLine > 0:
+			title += fmt.Sprintf(" (line %d)", r.StartLine)
+		}
+	}
+
+	return &CreateSnippetRequest{
+		Title:          title,
+		Description:    r.Description,
+		Code:           r.Code,
+		Language:       language,
+		Tags:           r.Tags,
+		Metadata:       map[string]interface{}{"source": r.SnippetSource},
+		IsPublic:       r.IsPublic,
+		AllowDuplicate: r.AllowDuplicate,
+	}
+}
+
+// EditorSnippet is a snippet trimmed to what an editor needs to insert it
+type EditorSnippet struct {
+	ID        string         `json:"id"`
+	Title     string         `json:"title"`
+	Language  string         `json:"language"`
+	Code      string         `json:"code"`
+	Tags      []string       `json:"tags"`
+	Source    *SnippetSource `json:"source,omitempty"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// NewEditorSnippet trims a snippet for an editor, recovering its capture source if it has one
+func NewEditorSnippet(s *Snippet) EditorSnippet {
+	out := EditorSnippet{
+		ID:        s.ID,
+		Title:     s.Title,
+		Language:  s.Language,
+		Code:      s.Code,
+		Tags:      s.Tags,
+		CreatedAt: s.CreatedAt,
+	}
+	switch source := s.Metadata["source"].(type) {
+	case SnippetSource:
+		out.Source = &source
+	case map[string]interface{}:
+		if filePath, _ := source["filePath"].(string); filePath != "" {
+			out.Source = &SnippetSource{FilePath: filePath}
+			out.Source.StartLine = intValue(source["startLine"])
+			out.Source.EndLine = intValue(source["endLine"])
+			out.Source.Repository, _ = source["repository"].(string)
+			out.Source.Branch, _ = source["branch"].(string)
+			out.Source.Commit, _ = source["commit"].(string)
+			out.Source.Editor, _ = source["editor"].(string)
+		}
+	}
+	return out
+}
+
+// intValue reads a number decoded from JSON or BSON
+func intValue(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
+
+// editorLanguages maps editor language IDs to the names snippets use
+var editorLanguages = map[string]string{
+	"typescriptreact": "typescript",
+	"javascriptreact": "javascript",
+	"shellscript":     "bash",
+	"objective-c":     "objectivec",
+	"plaintext":       "",
+}
+
+// NormalizeEditorLanguage maps an editor language ID to a snippet language
+func NormalizeEditorLanguage(id string) string {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if language, ok := editorLanguages[id]; ok {
+		return language
+	}
+	return id
+}
+
+// extensionLanguages maps file extensions to snippet languages
+var extensionLanguages = map[string]string{
+	".go": "go", ".ts": "typescript", ".tsx": "typescript", ".mts": "typescript", ".cts": "typescript",
+	".js": "javascript", ".jsx": "javascript", ".mjs": "javascript", ".cjs": "javascript",
+	".py": "python", ".rb": "ruby", ".rs": "rust", ".java": "java", ".kt": "kotlin", ".kts": "kotlin",
+	".swift": "swift", ".c": "c", ".h": "c", ".cc": "cpp", ".cpp": "cpp", ".hpp": "cpp", ".cs": "csharp",
+	".php": "php", ".scala": "scala", ".sh": "bash", ".bash": "bash", ".zsh": "bash", ".ps1": "powershell",
+	".sql": "sql", ".html": "html", ".css": "css", ".scss": "scss", ".json": "json", ".yaml": "yaml",
+	".yml": "yaml", ".toml": "toml", ".md": "markdown", ".proto": "protobuf", ".dart": "dart",
+	".ex": "elixir", ".exs": "elixir", ".hs": "haskell", ".lua": "lua", ".r": "r", ".vue": "vue",
+	".svelte": "svelte", ".tf": "hcl",
+}
+
+// LanguageFromPath guesses a snippet language from a file name, or "text" if unknown
+func LanguageFromPath(filePath string) string {
+	base := path.Base(strings.ReplaceAll(filePath, "\\", "/"))
+	if strings.EqualFold(base, "Dockerfile") {
+		return "dockerfile"
+	}
+	if strings.EqualFold(base, "Makefile") {
+		return "makefile"
+	}
+	if language, ok := extensionLanguages[strings.ToLower(path.Ext(base))]; ok {
+		return language
+	}
+	return "text"
+}
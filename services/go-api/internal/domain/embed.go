@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// nonSlugChars matches runs of characters that are replaced by a dash in slugs
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// SnippetEmbed is a public snippet ready to be embedded in another site
+type SnippetEmbed struct {
+	Snippet    *Snippet
+	AuthorName string
+	Slug       string
+	EmbedURL   string // the embeddable HTML page
+	PageURL    string // the snippet in the web app
+	OEmbedURL  string // oEmbed discovery URL of the embed
+	Height     int    // suggested iframe height in pixels
+	LicenseURL string // the text of the snippet's license, if it has one
+}
+
+// OEmbed is an oEmbed 1.0 "rich" response (https://oembed.com)
+type OEmbed struct {
+	Type         string `json:"type"`
+	Version      string `json:"version"`
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ProviderName string `json:"provider_name"`
+	ProviderURL  string `json:"provider_url"`
+	HTML         string `json:"html"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	CacheAge     int    `json:"cache_age"`
+}
+
+// SnippetSlug returns the readable URL slug of a snippet: its title followed by its ID,
+// e.g. binary-search-in-go-6650f1c2a4b3c2d1e0f9a8b7
+func SnippetSlug(s *Snippet) string {
+	title := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(s.Title), "-"), "-")
+	if len(title) > 60 {
+		title = strings.TrimRight(title[:60], "-")
+	}
+	if title == "" {
+		return s.ID
+	}
+	return title + "-" + s.ID
+}
+
+// SnippetIDFromSlug returns the snippet ID at the end of a slug. A bare ID is also a valid slug,
+// so links keep working when the title changes.
+func SnippetIDFromSlug(slug string) string {
+	if i := strings.LastIndexByte(slug, '-'); i >= 0 {
+		return slug[i+1:]
+	}
+	return slug
+}
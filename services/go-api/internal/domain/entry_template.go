@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxTemplatePrompts is how many prompts one entry template can have
+const MaxTemplatePrompts = 20
+
+// EntryTemplate is a journal entry outline a study group's admins publish, such as a weekly
+// retrospective. Members instantiate it into an entry of their own for an instance of the
+// template, like one week, and the group sees everyone's answers for that instance side by side.
+type EntryTemplate struct {
+	ID        uuid.UUID `json:"id"`
+	GroupID   uuid.UUID `json:"groupId"`
+	CreatedBy uuid.UUID `json:"createdBy"`
+	Name      string    `json:"name"`
+	Prompts   []string  `json:"prompts"` // each becomes a heading to answer under
+	Tags      []string  `json:"tags"`    // added to entries made from the template
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// EntryTemplateRequest represents the request to publish or replace an entry template
+type EntryTemplateRequest struct {
+	Name    string   `json:"name"`
+	Prompts []string `json:"prompts"`
+	Tags    []string `json:"tags"`
+}
+
+// InstantiateTemplateRequest represents the request to start an entry from a template. Instance
+// names the round being answered and defaults to the current ISO week, e.g. 2026-W42.
+type InstantiateTemplateRequest struct {
+	Instance string `json:"instance"`
+}
+
+// TemplateInstance is one round of a template members have answered
+type TemplateInstance struct {
+	Instance       string    `json:"instance"`
+	Responses      int       `json:"responses"`
+	LastResponseAt time.Time `json:"lastResponseAt"`
+}
+
+// TemplateInstanceResponses is the group's view of an instance: every member's answer to each
+// prompt. Entries that are encrypted or in a vault cannot be read and are only counted.
+type TemplateInstanceResponses struct {
+	TemplateID uuid.UUID                `json:"templateId"`
+	Instance   string                   `json:"instance"`
+	Responses  int                      `json:"responses"`
+	Withheld   int                      `json:"withheld"`
+	Prompts    []TemplatePromptResponse `json:"prompts"`
+}
+
+// TemplatePromptResponse collects the answers to one prompt of a template
+type TemplatePromptResponse struct {
+	Prompt  string           `json:"prompt"`
+	Answers []TemplateAnswer `json:"answers"`
+}
+
+// TemplateAnswer is one member's answer to a prompt
+type TemplateAnswer struct {
+	UserID      uuid.UUID `json:"userId"`
+	DisplayName string    `json:"displayName"`
+	EntryID     uuid.UUID `json:"entryId"`
+	Answer      string    `json:"answer"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TemplateResponseEntry is a member's entry for a template instance, as read for the group view
+type TemplateResponseEntry struct {
+	UserID        uuid.UUID
+	DisplayName   string
+	EntryID       uuid.UUID
+	Content       string
+	ContentFormat string
+	IsVault       bool
+	UpdatedAt     time.Time
+}
+
+// TemplateInstanceKey names the instance of a template for the ISO week t falls in
+func TemplateInstanceKey(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// Title is the title of an entry made from the template for an instance
+func (t *EntryTemplate) Title(instance string) string {
+	return t.Name + " " + instance
+}
+
+// Content is the starting content of an entry made from the template: a heading per prompt
+func (t *EntryTemplate) Content() string {
+	var b strings.Builder
+	for _, prompt := range t.Prompts {
+		b.WriteString("## " + prompt + "\n\n\n")
+	}
+	return b.String()
+}
+
+// TemplateAnswers splits entry content made from a template into the answer to each prompt,
+// the text under the prompt's heading up to the next heading of the same level. Prompts whose
+// heading was removed get an empty answer.
+func TemplateAnswers(prompts []string, content string) []string {
+	sections := map[string]*strings.Builder{}
+	var current *strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if heading, ok := strings.CutPrefix(line, "## "); ok {
+			current = &strings.Builder{}
+			key := strings.TrimSpace(heading)
+			if _, seen := sections[key]; !seen {
+				sections[key] = current
+			}
+			continue
+		}
+		if current != nil {
+			current.WriteString(line + "\n")
+		}
+	}
+
+	answers := make([]string, len(prompts))
+	for i, prompt := range prompts {
+		if section, ok := sections[strings.TrimSpace(prompt)]; ok {
+			answers[i] = strings.TrimSpace(section.String())
+		}
+	}
+	return answers
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Git providers that can send activity webhooks
+const (
+	GitProviderGitHub = "github"
+	GitProviderGitLab = "gitlab"
+)
+
+// Git activity kinds. Pull requests cover GitLab merge requests too.
+const (
+	GitActivityCommit      = "commit"
+	GitActivityPullRequest = "pull_request"
+)
+
+// Pull request actions that are recorded; others, such as pushes to a pull request, are ignored
+const (
+	GitPullRequestOpened   = "opened"
+	GitPullRequestMerged   = "merged"
+	GitPullRequestClosed   = "closed"
+	GitPullRequestReopened = "reopened"
+)
+
+// Git draft statuses. Published and dismissed drafts no longer follow new activity.
+const (
+	GitDraftPending   = "draft"
+	GitDraftPublished = "published"
+	GitDraftDismissed = "dismissed"
+)
+
+// GitActivityTag is added to entries drafted from Git activity
+const GitActivityTag = "git-activity"
+
+// GitHook is a user's Git activity webhook. The same URL and secret work for GitHub and GitLab;
+// the secret is only shown when it is created or rotated.
+type GitHook struct {
+	ID                  uuid.UUID `json:"id"`
+	UserID              uuid.UUID `json:"-"`
+	Secret              string    `json:"secret,omitempty"`
+	URL                 string    `json:"url"`
+	Enabled             bool      `json:"enabled"`
+	IncludeCommits      bool      `json:"includeCommits"`
+	IncludePullRequests bool      `json:"includePullRequests"`
+	Repos               []string  `json:"repos"`   // full names such as owner/repo; empty means every repository
+	Authors             []string  `json:"authors"` // usernames, names, or emails counted as the user's; empty means everyone
+	CreatedAt           time.Time `json:"createdAt"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+}
+
+// UpdateGitHookRequest represents the request to configure a Git activity webhook
+type UpdateGitHookRequest struct {
+	Enabled             bool     `json:"enabled"`
+	IncludeCommits      bool     `json:"includeCommits"`
+	IncludePullRequests bool     `json:"includePullRequests"`
+	Repos               []string `json:"repos"`
+	Authors             []string `json:"authors"`
+}
+
+// GitActivity is one commit or pull request event received from a provider
+type GitActivity struct {
+	ID           uuid.UUID `json:"id"`
+	UserID       uuid.UUID `json:"-"`
+	Provider     string    `json:"provider"`
+	Kind         string    `json:"kind"`
+	ExternalID   string    `json:"-"` // identifies the event at the provider, so redeliveries are recorded once
+	Repo         string    `json:"repo"`
+	Ref          string    `json:"ref,omitempty"`    // a short commit SHA, or a pull request number such as #12
+	Action       string    `json:"action,omitempty"` // for pull requests: opened, merged, closed, or reopened
+	Title        string    `json:"title"`            // a commit message's first line, or a pull request title
+	URL          string    `json:"url,omitempty"`
+	Authors      []string  `json:"-"`         // usernames, names, and emails of whoever made the change
+	Additions    int       `json:"additions"` // lines; only pull requests from GitHub report them
+	Deletions    int       `json:"deletions"`
+	FilesChanged int       `json:"filesChanged"`
+	OccurredAt   time.Time `json:"occurredAt"`
+}
+
+// GitDraft is a journal entry drafted from a day's Git activity, which the user edits and then
+// publishes as an entry. Drafts follow new activity until they are edited.
+type GitDraft struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"-"`
+	Day       time.Time  `json:"day"` // midnight UTC
+	Title     string     `json:"title"`
+	Content   string     `json:"content"`
+	Tags      []string   `json:"tags"`
+	Status    string     `json:"status"`
+	Edited    bool       `json:"edited"`
+	EntryID   *uuid.UUID `json:"entryId,omitempty"` // set once published
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// UpdateGitDraftRequest represents the request to edit a Git activity draft
+type UpdateGitDraftRequest struct {
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
+}
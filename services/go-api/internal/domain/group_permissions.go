@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions a study group's permissions control
+const (
+	GroupActionShareEntries  = "shareEntries"  // Share journal entries with the group, e.g. by answering a template
+	GroupActionPostSnippets  = "postSnippets"  // Post snippets to the group, e.g. for code review
+	GroupActionStartSessions = "startSessions" // Start a voice session in a group chat channel
+)
+
+// Who in a group may take an action. Owners and admins can always take every action.
+const (
+	GroupPermissionMembers = "members" // Any group member
+	GroupPermissionAdmins  = "admins"  // Only group owners and admins
+)
+
+// GroupPermissions says who in a study group may take each action
+type GroupPermissions struct {
+	GroupID       uuid.UUID `json:"groupId"`
+	ShareEntries  string    `json:"shareEntries"`  // members, admins
+	PostSnippets  string    `json:"postSnippets"`  // members, admins
+	StartSessions string    `json:"startSessions"` // members, admins
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// NewGroupPermissions returns the default permissions for a group: members can take every action
+func NewGroupPermissions(groupID uuid.UUID) *GroupPermissions {
+	return &GroupPermissions{
+		GroupID:       groupID,
+		ShareEntries:  GroupPermissionMembers,
+		PostSnippets:  GroupPermissionMembers,
+		StartSessions: GroupPermissionMembers,
+		UpdatedAt:     time.Now().UTC(),
+	}
+}
+
+// UpdateGroupPermissionsRequest represents the request to change a group's permissions. Actions
+// left empty are open to members.
+type UpdateGroupPermissionsRequest struct {
+	ShareEntries  string `json:"shareEntries"`
+	PostSnippets  string `json:"postSnippets"`
+	StartSessions string `json:"startSessions"`
+}
+
+// Policy returns who may take an action, or "" for an unknown action
+func (p *GroupPermissions) Policy(action string) string {
+	switch action {
+	case GroupActionShareEntries:
+		return p.ShareEntries
+	case GroupActionPostSnippets:
+		return p.PostSnippets
+	case GroupActionStartSessions:
+		return p.StartSessions
+	}
+	return ""
+}
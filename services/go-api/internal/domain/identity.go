@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to the account they sign in with at an OpenID Connect provider
+type UserIdentity struct {
+	UserID      uuid.UUID `json:"userId"`
+	Issuer      string    `json:"issuer"`
+	Subject     string    `json:"subject"` // the provider's stable ID for the account
+	Email       string    `json:"email"`   // as the provider last reported it
+	CreatedAt   time.Time `json:"createdAt"`
+	LastLoginAt time.Time `json:"lastLoginAt"`
+}
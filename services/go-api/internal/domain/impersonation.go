@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxImpersonationReasonLength bounds the reason an admin gives for impersonating a user
+const MaxImpersonationReasonLength = 500
+
+// Impersonation is a session of an admin acting as a user to debug an issue only that user
+// sees. Sessions are read-only unless the admin asks for write access, and are kept as an audit
+// log that the user can see too.
+type Impersonation struct {
+	ID        uuid.UUID  `json:"id"`
+	AdminID   uuid.UUID  `json:"adminId"`
+	AdminName string     `json:"adminName"`
+	UserID    uuid.UUID  `json:"userId"`
+	Reason    string     `json:"reason"`
+	ReadOnly  bool       `json:"readOnly"`
+	StartedAt time.Time  `json:"startedAt"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"` // when the admin ended it early
+}
+
+// StartImpersonationRequest is the payload for an admin starting to impersonate a user
+type StartImpersonationRequest struct {
+	UserID uuid.UUID `json:"userId"`
+	Reason string    `json:"reason"`
+	Write  bool      `json:"write"` // also allow changes; sessions are read-only by default
+}
+
+// ImpersonationResponse is a started impersonation and the token that acts as the user
+type ImpersonationResponse struct {
+	Impersonation *Impersonation `json:"impersonation"`
+	Token         string         `json:"token"`
+}
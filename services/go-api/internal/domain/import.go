@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Import sources
+const (
+	ImportNotion   = "notion"   // a Notion Markdown & CSV export zip
+	ImportObsidian = "obsidian" // a zip of an Obsidian vault folder
+	ImportDayOne   = "dayone"   // a Day One JSON export, zipped or not
+)
+
+// Import statuses
+const (
+	ImportPending   = "pending"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+)
+
+// Import is an export from another journaling app being turned into journal entries in the
+// background. Progress is Processed out of Total notes, which is known once the upload is read.
+type Import struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"userId"`
+	Source     string     `json:"source"`
+	Status     string     `json:"status"`
+	Total      int        `json:"total"`
+	Processed  int        `json:"processed"`
+	Imported   int        `json:"imported"`
+	Skipped    int        `json:"skipped"` // empty notes, or notes past the import limits
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	WorkspaceID uuid.UUID `json:"-"` // where entries are created
+	Archive     []byte    `json:"-"` // the upload; set only on claimed imports
+}
+
+// ImportedNote is a note read from an export, before it becomes a journal entry
+type ImportedNote struct {
+	Title     string
+	Content   string
+	Tags      []string
+	CreatedAt time.Time // zero when the export doesn't say
+	UpdatedAt time.Time
+}
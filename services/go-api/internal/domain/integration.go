@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Chat integration providers
+const (
+	IntegrationSlack   = "slack"
+	IntegrationDiscord = "discord"
+)
+
+// Delivery statuses for messages mirrored to an integration
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed" // gave up after the last retry
+)
+
+// GroupIntegration mirrors a study group's chat to a Slack or Discord channel.
+// WebhookURL and BotToken are credentials and are never returned to clients.
+type GroupIntegration struct {
+	ID          uuid.UUID `json:"id"`
+	GroupID     uuid.UUID `json:"groupId"`
+	Provider    string    `json:"provider"` // slack, discord
+	TeamID      string    `json:"teamId"`   // Slack workspace or Discord server
+	ChannelID   string    `json:"channelId"`
+	ChannelName string    `json:"channelName"`
+	WebhookURL  string    `json:"-"`
+	BotToken    string    `json:"-"`
+	Enabled     bool      `json:"enabled"`
+	SyncInbound bool      `json:"syncInbound"` // also relay channel messages back into the group
+	CreatedBy   uuid.UUID `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// UpdateIntegrationRequest changes a group integration's settings
+type UpdateIntegrationRequest struct {
+	Enabled     *bool `json:"enabled"`
+	SyncInbound *bool `json:"syncInbound"`
+}
+
+// IntegrationDelivery is one chat message queued for delivery to an integration
+type IntegrationDelivery struct {
+	ID            uuid.UUID
+	IntegrationID uuid.UUID
+	MessageID     string
+	Payload       []byte // provider-specific webhook body
+	WebhookURL    string // joined from the integration when claimed for delivery
+	Status        string
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+}
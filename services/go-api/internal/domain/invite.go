@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invite limits
+const (
+	DefaultInviteUses   = 1
+	MaxInviteUses       = 1000
+	MaxInviteNoteLength = 200
+)
+
+// Invite is a code admins hand out to let people register while registration is invite-only.
+// Each registration uses it up once, until MaxUses.
+type Invite struct {
+	ID        uuid.UUID  `json:"id"`
+	Code      string     `json:"code,omitempty"` // Only returned when the invite is created
+	Note      string     `json:"note"`           // Who or what the invite is for
+	MaxUses   int        `json:"maxUses"`
+	Uses      int        `json:"uses"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedBy uuid.UUID  `json:"createdBy"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// CreateInviteRequest is the payload for an admin creating an invite
+type CreateInviteRequest struct {
+	Note      string     `json:"note"`
+	MaxUses   int        `json:"maxUses"`   // Defaults to 1
+	ExpiresAt *time.Time `json:"expiresAt"` // Never expires when empty
+}
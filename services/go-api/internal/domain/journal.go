@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,14 +9,52 @@ import (
 
 // JournalEntry represents a learning journal entry
 type JournalEntry struct {
-	ID        uuid.UUID `json:"id"`
-	UserID    uuid.UUID `json:"userId"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	Mood      string    `json:"mood"` // excited, productive, frustrated, confused, accomplished
-	Tags      []string  `json:"tags"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID            uuid.UUID           `json:"id"`
+	UserID        uuid.UUID           `json:"userId"`
+	Title         string              `json:"title"`
+	Content       string              `json:"content"` // base64 ciphertext when ContentFormat is e2ee
+	Mood          string              `json:"mood"`    // excited, productive, frustrated, confused, accomplished
+	Tags          []string            `json:"tags"`
+	WordCount     int                 `json:"wordCount"`
+	ContentFormat string              `json:"contentFormat"`
+	Encryption    *EncryptionMetadata `json:"encryption,omitempty"`
+	IsPublic      bool                `json:"isPublic"`         // shown on the author's profile and followers' feeds
+	IsVault       bool                `json:"isVault"`          // readable and editable only within a vault session
+	Sealed        bool                `json:"sealed,omitempty"` // content withheld because no vault session is open
+	ProjectID     *uuid.UUID          `json:"projectId,omitempty"`
+	CreatedAt     time.Time           `json:"createdAt"`
+	UpdatedAt     time.Time           `json:"updatedAt"`
+
+	RelatedSnippets []RelatedSnippet `json:"relatedSnippets,omitempty"` // set when an entry is fetched by ID
+}
+
+// Journal entry content formats
+const (
+	ContentFormatPlain = "plain"
+	ContentFormatE2EE  = "e2ee" // encrypted on the client; the server only stores ciphertext
+)
+
+// EncryptionMetadata is what a client needs, besides its own key, to decrypt an e2ee entry.
+// The server never sees key material.
+type EncryptionMetadata struct {
+	Algorithm string `json:"algorithm"`      // e.g. AES-256-GCM
+	KeyID     string `json:"keyId"`          // identifies which client key encrypted the entry
+	Nonce     string `json:"nonce"`          // base64 IV/nonce
+	KDF       string `json:"kdf,omitempty"`  // key derivation for passphrase keys, e.g. PBKDF2-SHA256
+	Salt      string `json:"salt,omitempty"` // base64 KDF salt
+}
+
+// Seal withholds a vault entry's content, keeping only what lists need to show it as locked
+func (e *JournalEntry) Seal() {
+	e.Content = ""
+	e.Encryption = nil
+	e.RelatedSnippets = nil
+	e.Sealed = true
+}
+
+// CountWords returns the number of whitespace-separated words in content
+func CountWords(content string) int {
+	return len(strings.Fields(content))
 }
 
 // NewJournalEntry creates a new journal entry with generated ID and timestamps
@@ -25,29 +64,72 @@ func NewJournalEntry(userID uuid.UUID, title, content, mood string, tags []strin
 		tags = []string{}
 	}
 	return &JournalEntry{
-		ID:        uuid.New(),
-		UserID:    userID,
-		Title:     title,
-		Content:   content,
-		Mood:      mood,
-		Tags:      tags,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:            uuid.New(),
+		UserID:        userID,
+		Title:         title,
+		Content:       content,
+		Mood:          mood,
+		Tags:          tags,
+		WordCount:     CountWords(content),
+		ContentFormat: ContentFormatPlain,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 }
 
 // CreateJournalEntryRequest represents the request to create a journal entry
 type CreateJournalEntryRequest struct {
-	Title   string   `json:"title"`
-	Content string   `json:"content"`
-	Mood    string   `json:"mood"`
-	Tags    []string `json:"tags"`
+	Title         string              `json:"title"`
+	Content       string              `json:"content"`
+	Mood          string              `json:"mood"`
+	Tags          []string            `json:"tags"`
+	ContentFormat string              `json:"contentFormat"` // plain (default) or e2ee
+	Encryption    *EncryptionMetadata `json:"encryption"`    // required for e2ee
+	IsPublic      bool                `json:"isPublic"`
+	IsVault       bool                `json:"isVault"`
 }
 
 // UpdateJournalEntryRequest represents the request to update a journal entry
 type UpdateJournalEntryRequest struct {
-	Title   string   `json:"title"`
-	Content string   `json:"content"`
-	Mood    string   `json:"mood"`
-	Tags    []string `json:"tags"`
+	Title         string              `json:"title"`
+	Content       string              `json:"content"`
+	Mood          string              `json:"mood"`
+	Tags          []string            `json:"tags"`
+	ContentFormat string              `json:"contentFormat"` // plain (default) or e2ee
+	Encryption    *EncryptionMetadata `json:"encryption"`    // required for e2ee
+	IsPublic      *bool               `json:"isPublic"`      // omitted keeps the current visibility
+	IsVault       *bool               `json:"isVault"`       // omitted keeps the entry in or out of the vault
+}
+
+// Tag match modes for filtering journal entries by multiple tags
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+// FilterNone is the special tags/mood filter value matching entries with no tags or no mood
+const FilterNone = "none"
+
+// WeeklyWordCount is the number of words written in journal entries during one week
+type WeeklyWordCount struct {
+	WeekStart time.Time `json:"weekStart"`
+	Words     int       `json:"words"`
+	Entries   int       `json:"entries"`
+}
+
+// WritingStats summarizes how much a user writes in their journal
+type WritingStats struct {
+	TotalWords         int               `json:"totalWords"`
+	TotalEntries       int               `json:"totalEntries"`
+	AverageEntryLength int               `json:"averageEntryLength"` // words per entry
+	Weekly             []WeeklyWordCount `json:"weekly"`             // oldest week first
+}
+
+// JournalExport is a full dump of a user's journal. Encrypted entries are exported as-is
+// with their metadata so they can be decrypted client-side later.
+type JournalExport struct {
+	ExportedAt     time.Time      `json:"exportedAt"`
+	EntryCount     int            `json:"entryCount"`
+	EncryptedCount int            `json:"encryptedCount"`
+	Entries        []JournalEntry `json:"entries"`
 }
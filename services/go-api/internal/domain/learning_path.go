@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxPathTopics caps the topics in one learning path
+const MaxPathTopics = 100
+
+// Learning path evidence types
+const (
+	EvidenceEntry   = "entry"
+	EvidenceSnippet = "snippet"
+)
+
+// LearningPath is an ordered list of topics to work through. Owners can share a path into one
+// of their study groups so other members can follow it; progress is always the requesting user's.
+type LearningPath struct {
+	ID          uuid.UUID    `json:"id"`
+	UserID      uuid.UUID    `json:"userId"`
+	GroupID     *uuid.UUID   `json:"groupId,omitempty"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Progress    PathProgress `json:"progress"`
+	Topics      []PathTopic  `json:"topics,omitempty"` // set when a path is fetched by ID
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+}
+
+// NewLearningPath creates a new learning path with timestamps
+func NewLearningPath(userID uuid.UUID, title, description string) *LearningPath {
+	now := time.Now().UTC()
+	return &LearningPath{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Title:       title,
+		Description: description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// PathTopic is one step of a learning path
+type PathTopic struct {
+	ID          uuid.UUID      `json:"id"`
+	PathID      uuid.UUID      `json:"pathId"`
+	Position    int            `json:"position"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	CompletedAt *time.Time     `json:"completedAt,omitempty"`
+	Evidence    []PathEvidence `json:"evidence"`
+}
+
+// PathEvidence is an entry or snippet linked to a topic to show it was completed
+type PathEvidence struct {
+	Type     string    `json:"type"` // entry, snippet
+	ID       string    `json:"id"`
+	Title    string    `json:"title"`
+	LinkedAt time.Time `json:"linkedAt"`
+}
+
+// PathProgress counts the completed topics of a learning path
+type PathProgress struct {
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+	Percent   int `json:"percent"`
+}
+
+// NewPathProgress rounds the completed share of total down to a whole percent
+func NewPathProgress(completed, total int) PathProgress {
+	progress := PathProgress{Completed: completed, Total: total}
+	if total > 0 {
+		progress.Percent = completed * 100 / total
+	}
+	return progress
+}
+
+// LearningPathRequest creates or updates a learning path. Topics are only read on create.
+type LearningPathRequest struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Topics      []TopicRequest `json:"topics"`
+}
+
+// TopicRequest creates or updates a learning path topic
+type TopicRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// ReorderTopicsRequest lists every topic of a path in its new order
+type ReorderTopicsRequest struct {
+	TopicIDs []uuid.UUID `json:"topicIds"`
+}
+
+// SharePathRequest shares a learning path into a study group
+type SharePathRequest struct {
+	GroupID uuid.UUID `json:"groupId"`
+}
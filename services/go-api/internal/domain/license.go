@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// licenseHeaderBytes is how much of a file's start is searched for a license header
+const licenseHeaderBytes = 4096
+
+// MaxSourceURLLength caps a snippet's source URL
+const MaxSourceURLLength = 2048
+
+// licenses maps the lowercase SPDX IDs snippets accept, and the deprecated IDs still common in
+// headers, to their canonical IDs
+var licenses = map[string]string{
+	"0bsd": "0BSD", "mit": "MIT", "mit-0": "MIT-0", "isc": "ISC", "zlib": "Zlib", "wtfpl": "WTFPL",
+	"apache-2.0": "Apache-2.0", "bsd-2-clause": "BSD-2-Clause", "bsd-3-clause": "BSD-3-Clause",
+	"bsl-1.0": "BSL-1.0", "mpl-2.0": "MPL-2.0", "epl-2.0": "EPL-2.0", "unlicense": "Unlicense",
+	"gpl-2.0-only": "GPL-2.0-only", "gpl-2.0-or-later": "GPL-2.0-or-later",
+	"gpl-3.0-only": "GPL-3.0-only", "gpl-3.0-or-later": "GPL-3.0-or-later",
+	"lgpl-2.1-only": "LGPL-2.1-only", "lgpl-2.1-or-later": "LGPL-2.1-or-later",
+	"lgpl-3.0-only": "LGPL-3.0-only", "lgpl-3.0-or-later": "LGPL-3.0-or-later",
+	"agpl-3.0-only": "AGPL-3.0-only", "agpl-3.0-or-later": "AGPL-3.0-or-later",
+	"cc0-1.0": "CC0-1.0", "cc-by-4.0": "CC-BY-4.0", "cc-by-sa-4.0": "CC-BY-SA-4.0",
+
+	"gpl-2.0": "GPL-2.0-only", "gpl-2.0+": "GPL-2.0-or-later", "gpl-3.0": "GPL-3.0-only", "gpl-3.0+": "GPL-3.0-or-later",
+	"lgpl-2.1": "LGPL-2.1-only", "lgpl-2.1+": "LGPL-2.1-or-later", "lgpl-3.0": "LGPL-3.0-only", "lgpl-3.0+": "LGPL-3.0-or-later",
+	"agpl-3.0": "AGPL-3.0-only", "agpl-3.0+": "AGPL-3.0-or-later",
+}
+
+var (
+	spdxPattern = regexp.MustCompile(`SPDX-License-Identifier:\s*([\w.+-]+)`)
+	gplPattern  = regexp.MustCompile(`(?is)GNU\s+(Affero\s+|Lesser\s+|Library\s+)?General\s+Public\s+License.{0,300}?version\s+(\d)`)
+
+	// licenseHeaders are phrases from the standard headers and texts of licenses, checked in order
+	licenseHeaders = []struct {
+		pattern *regexp.Regexp
+		license string
+	}{
+		{regexp.MustCompile(`(?i)Apache\s+License,?\s+Version\s+2\.0`), "Apache-2.0"},
+		{regexp.MustCompile(`(?i)Mozilla\s+Public\s+License,?\s+v(?:ersion|\.)?\s*2\.0`), "MPL-2.0"},
+		{regexp.MustCompile(`(?i)Eclipse\s+Public\s+License\s+v?(?:ersion\s+)?2\.0`), "EPL-2.0"},
+		{regexp.MustCompile(`(?i)Boost\s+Software\s+License,?\s+Version\s+1\.0`), "BSL-1.0"},
+		{regexp.MustCompile(`(?i)free\s+and\s+unencumbered\s+software\s+released\s+into\s+the\s+public\s+domain`), "Unlicense"},
+		{regexp.MustCompile(`(?i)\bCC0\s+1\.0\b`), "CC0-1.0"},
+		{regexp.MustCompile(`(?i)Creative\s+Commons\s+Attribution[\s-]+ShareAlike\s+4\.0|\bCC\s+BY-SA\s+4\.0\b`), "CC-BY-SA-4.0"},
+		{regexp.MustCompile(`(?i)Creative\s+Commons\s+Attribution\s+4\.0|\bCC\s+BY\s+4\.0\b`), "CC-BY-4.0"},
+		{regexp.MustCompile(`(?i)Redistribution\s+and\s+use\s+in\s+source\s+and\s+binary\s+forms(?s:.*)Neither\s+the\s+name`), "BSD-3-Clause"},
+		{regexp.MustCompile(`(?i)Redistribution\s+and\s+use\s+in\s+source\s+and\s+binary\s+forms`), "BSD-2-Clause"},
+		{regexp.MustCompile(`(?i)\bISC\s+License\b|Permission\s+to\s+use,\s+copy,\s+modify,\s+and/or\s+distribute\s+this\s+software\s+for\s+any\s+purpose`), "ISC"},
+		{regexp.MustCompile(`(?i)\bMIT\s+License\b|Licensed\s+under\s+the\s+MIT\b|Permission\s+is\s+hereby\s+granted,\s+free\s+of\s+charge`), "MIT"},
+	}
+)
+
+// NormalizeLicense returns the canonical SPDX ID of a license ID given in any case, and whether
+// it is one snippets accept
+func NormalizeLicense(id string) (string, bool) {
+	license, ok := licenses[strings.ToLower(strings.TrimSpace(id))]
+	return license, ok
+}
+
+// LicenseURL links to the text of a license on the SPDX license list
+func LicenseURL(license string) string {
+	return "https://spdx.org/licenses/" + license + ".html"
+}
+
+// DetectLicense returns the SPDX ID of the license declared at the start of code, from an
+// SPDX-License-Identifier line or the standard header of a common license, or "" if none is found
+func DetectLicense(code string) string {
+	header := code
+	if len(header) > licenseHeaderBytes {
+		header = header[:licenseHeaderBytes]
+	}
+
+	if m := spdxPattern.FindStringSubmatch(header); m != nil {
+		if license, ok := NormalizeLicense(m[1]); ok {
+			return license
+		}
+	}
+
+	if m := gplPattern.FindStringSubmatch(header); m != nil {
+		var id string
+		switch kind, version := strings.ToLower(strings.TrimSpace(m[1])), m[2]; {
+		case kind == "affero" && version == "3":
+			id = "agpl-3.0"
+		case (kind == "lesser" || kind == "library") && version == "2":
+			id = "lgpl-2.1"
+		case (kind == "lesser" || kind == "library") && version == "3":
+			id = "lgpl-3.0"
+		case kind == "" && (version == "2" || version == "3"):
+			id = "gpl-" + version + ".0"
+		}
+		if id != "" {
+			if strings.Contains(strings.ToLower(header), "any later version") {
+				id += "+"
+			}
+			license, _ := NormalizeLicense(id)
+			return license
+		}
+	}
+
+	for _, h := range licenseHeaders {
+		if h.pattern.MatchString(header) {
+			return h.license
+		}
+	}
+	return ""
+}
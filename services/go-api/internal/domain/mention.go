@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Where a mention was made
+const (
+	MentionSourceChat  = "chat"  // a study group chat message; SourceID is the message ID
+	MentionSourceEntry = "entry" // a journal entry in a shared workspace; SourceID is the entry ID
+)
+
+// mentionExcerptLength is how much of the surrounding text a mention keeps
+const mentionExcerptLength = 200
+
+// Mention records that a user was @mentioned
+type Mention struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"userId"` // who was mentioned
+	AuthorID    uuid.UUID  `json:"authorId"`
+	AuthorName  string     `json:"authorName"`
+	SourceType  string     `json:"sourceType"`
+	SourceID    string     `json:"sourceId"`
+	ContextID   uuid.UUID  `json:"contextId"`   // the study group or workspace
+	ContextName string     `json:"contextName"` // group name or entry title
+	Excerpt     string     `json:"excerpt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ReadAt      *time.Time `json:"readAt,omitempty"`
+}
+
+// MarkMentionsReadRequest marks mentions as read. No IDs marks all of them.
+type MarkMentionsReadRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+// MentionHandles returns the lowercased @handles mentioned in text, without duplicates
+func MentionHandles(content string) []string {
+	var handles []string
+	seen := make(map[string]bool)
+	for _, word := range strings.Fields(content) {
+		handle, ok := strings.CutPrefix(word, "@")
+		if !ok {
+			continue
+		}
+		handle = strings.ToLower(strings.TrimRight(handle, ".,:;!?)"))
+		if handle != "" && !seen[handle] {
+			seen[handle] = true
+			handles = append(handles, handle)
+		}
+	}
+	return handles
+}
+
+// MentionHandle is the @handle that mentions a user: their display name lowercased without spaces
+func MentionHandle(displayName string) string {
+	return strings.ToLower(strings.Join(strings.Fields(displayName), ""))
+}
+
+// MentionExcerpt returns the part of content around the first mention of handle
+func MentionExcerpt(content, handle string) string {
+	content = strings.Join(strings.Fields(content), " ")
+	if utf8.RuneCountInString(content) <= mentionExcerptLength {
+		return content
+	}
+
+	runes := []rune(content)
+	start := 0
+	// ToLower maps rune for rune, so rune offsets in lower match content
+	lower := strings.ToLower(content)
+	if i := strings.Index(lower, "@"+handle); i > 0 {
+		start = utf8.RuneCountInString(lower[:i]) - mentionExcerptLength/4
+	}
+	start = max(0, min(start, len(runes)-mentionExcerptLength))
+	excerpt := string(runes[start : start+mentionExcerptLength])
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if start+mentionExcerptLength < len(runes) {
+		excerpt += "…"
+	}
+	return excerpt
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report statuses (shared by message and content reports)
+const (
+	ReportStatusPending   = "pending"
+	ReportStatusDismissed = "dismissed"
+	ReportStatusActioned  = "actioned"
+)
+
+// GroupModerationSettings holds the chat filter configuration for a study group
+type GroupModerationSettings struct {
+	GroupID          uuid.UUID `json:"groupId"`
+	ProfanityFilter  bool      `json:"profanityFilter"`
+	BlockedPatterns  []string  `json:"blockedPatterns"`  // Regular expressions that reject a message
+	MaxMessageLength int       `json:"maxMessageLength"` // 0 uses the server default
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// NewGroupModerationSettings returns the default (permissive) settings for a group
+func NewGroupModerationSettings(groupID uuid.UUID) *GroupModerationSettings {
+	return &GroupModerationSettings{
+		GroupID:         groupID,
+		BlockedPatterns: []string{},
+		UpdatedAt:       time.Now().UTC(),
+	}
+}
+
+// MessageReport is a user report against a chat message, queued for admin review
+type MessageReport struct {
+	ID             uuid.UUID  `json:"id"`
+	GroupID        uuid.UUID  `json:"groupId"`
+	MessageID      string     `json:"messageId"`
+	MessageUserID  uuid.UUID  `json:"messageUserId"`
+	MessageContent string     `json:"messageContent"` // Snapshot taken when the report was filed
+	ReporterID     uuid.UUID  `json:"reporterId"`
+	Reason         string     `json:"reason"`
+	Status         string     `json:"status"` // pending, dismissed, actioned
+	ResolutionNote string     `json:"resolutionNote,omitempty"`
+	ResolvedBy     *uuid.UUID `json:"resolvedBy,omitempty"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// NewMessageReport creates a pending report for a chat message
+func NewMessageReport(groupID uuid.UUID, message *ChatMessage, reporterID uuid.UUID, reason string) *MessageReport {
+	messageUserID, _ := uuid.Parse(message.UserID)
+	return &MessageReport{
+		ID:             uuid.New(),
+		GroupID:        groupID,
+		MessageID:      message.ID,
+		MessageUserID:  messageUserID,
+		MessageContent: message.Content,
+		ReporterID:     reporterID,
+		Reason:         reason,
+		Status:         ReportStatusPending,
+		CreatedAt:      time.Now().UTC(),
+	}
+}
+
+// UpdateModerationSettingsRequest represents the request to change a group's chat filters
+type UpdateModerationSettingsRequest struct {
+	ProfanityFilter  bool     `json:"profanityFilter"`
+	BlockedPatterns  []string `json:"blockedPatterns"`
+	MaxMessageLength int      `json:"maxMessageLength"`
+}
+
+// ReportMessageRequest represents the request to report a chat message
+type ReportMessageRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ResolveReportRequest represents an admin decision on a message or content report.
+// HideContent and WarnUser only apply when the status is actioned.
+type ResolveReportRequest struct {
+	Status      string `json:"status"` // dismissed or actioned
+	Note        string `json:"note"`
+	HideContent bool   `json:"hideContent"` // Hide reported content from public views
+	WarnUser    bool   `json:"warnUser"`    // Issue a warning to the content's author (Note is the reason)
+}
+
+// Reportable public content types
+const (
+	ContentTypeSnippet = "snippet"
+)
+
+// ContentReport is a user report against public content, queued for admin review
+type ContentReport struct {
+	ID              uuid.UUID  `json:"id"`
+	ContentType     string     `json:"contentType"`
+	ContentID       string     `json:"contentId"`
+	ContentOwnerID  uuid.UUID  `json:"contentOwnerId"`
+	ContentSnapshot string     `json:"contentSnapshot"` // Title when the report was filed
+	ReporterID      uuid.UUID  `json:"reporterId"`
+	Reason          string     `json:"reason"`
+	Status          string     `json:"status"` // pending, dismissed, actioned
+	ResolutionNote  string     `json:"resolutionNote,omitempty"`
+	ResolvedBy      *uuid.UUID `json:"resolvedBy,omitempty"`
+	ResolvedAt      *time.Time `json:"resolvedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// NewSnippetReport creates a pending report for a public snippet
+func NewSnippetReport(snippet *Snippet, reporterID uuid.UUID, reason string) *ContentReport {
+	ownerID, _ := uuid.Parse(snippet.UserID)
+	return &ContentReport{
+		ID:              uuid.New(),
+		ContentType:     ContentTypeSnippet,
+		ContentID:       snippet.ID,
+		ContentOwnerID:  ownerID,
+		ContentSnapshot: snippet.Title,
+		ReporterID:      reporterID,
+		Reason:          reason,
+		Status:          ReportStatusPending,
+		CreatedAt:       time.Now().UTC(),
+	}
+}
+
+// UserWarning is an admin warning issued to a user, usually in response to a report
+type UserWarning struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"userId"`
+	IssuedBy  uuid.UUID  `json:"issuedBy"`
+	Reason    string     `json:"reason"`
+	ReportID  *uuid.UUID `json:"reportId,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// NewUserWarning creates a warning for a user
+func NewUserWarning(userID, issuedBy uuid.UUID, reason string, reportID *uuid.UUID) *UserWarning {
+	return &UserWarning{
+		ID:        uuid.New(),
+		UserID:    userID,
+		IssuedBy:  issuedBy,
+		Reason:    reason,
+		ReportID:  reportID,
+		CreatedAt: time.Now().UTC(),
+	}
+}
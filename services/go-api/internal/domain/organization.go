@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Organization seat limits
+const (
+	DefaultOrgSeatLimit = 10
+	MaxOrgSeatLimit     = 10000
+)
+
+// Organization is a shared workspace with a billed seat limit, e.g. a bootcamp cohort or a team.
+// Its ID equals its workspace's ID, so members switch into it like any other workspace.
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	OwnerID   uuid.UUID `json:"ownerId"`
+	SeatLimit int       `json:"seatLimit"`
+	SeatsUsed int       `json:"seatsUsed"`
+	Role      string    `json:"role,omitempty"` // the requesting user's role
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreateOrganizationRequest represents the request to create an organization
+type CreateOrganizationRequest struct {
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	SeatLimit int    `json:"seatLimit"` // defaults to DefaultOrgSeatLimit
+}
+
+// UpdateSeatsRequest represents the request to change an organization's seat limit
+type UpdateSeatsRequest struct {
+	SeatLimit int `json:"seatLimit"`
+}
+
+// UpdateMemberRoleRequest represents the request to change a member's role
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role"` // admin or member
+}
+
+// OrgContributor summarizes one member's activity within an organization
+type OrgContributor struct {
+	UserID        uuid.UUID `json:"userId"`
+	DisplayName   string    `json:"displayName"`
+	EntriesCount  int       `json:"entriesCount"` // journal entries in the org workspace during the window
+	CurrentStreak int       `json:"currentStreak"`
+}
+
+// OrgAnalytics aggregates member activity across an organization
+type OrgAnalytics struct {
+	OrganizationID  uuid.UUID        `json:"organizationId"`
+	Days            int              `json:"days"`
+	MemberCount     int              `json:"memberCount"`
+	ActiveMembers   int              `json:"activeMembers"` // members with any learning activity during the window
+	EntriesCount    int              `json:"entriesCount"`
+	AverageStreak   float64          `json:"averageStreak"`
+	LongestStreak   int              `json:"longestStreak"`
+	MembersOnStreak int              `json:"membersOnStreak"`
+	TopContributors []OrgContributor `json:"topContributors"`
+}
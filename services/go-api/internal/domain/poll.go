@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Poll limits
+const (
+	MinPollOptions = 2
+	MaxPollOptions = 10
+)
+
+// Poll is a question posted to a study group chat channel for members to vote on. It is posted
+// as a chat message of type poll, and each vote broadcasts a poll_results message with the
+// new counts to everyone in the room.
+type Poll struct {
+	ID             uuid.UUID    `json:"id"`
+	GroupID        uuid.UUID    `json:"groupId"`
+	Channel        string       `json:"channel"`
+	Room           string       `json:"room"`
+	CreatedBy      uuid.UUID    `json:"createdBy"`
+	Question       string       `json:"question"`
+	Options        []PollOption `json:"options"`
+	MultipleChoice bool         `json:"multipleChoice"`
+	ClosesAt       *time.Time   `json:"closesAt,omitempty"` // No more votes are taken from then on
+	Voters         int          `json:"voters"`
+	MyVotes        []int        `json:"myVotes,omitempty"` // The options the requesting member voted for
+	CreatedAt      time.Time    `json:"createdAt"`
+}
+
+// PollOption is one answer to a poll and how many members voted for it
+type PollOption struct {
+	Text  string `json:"text"`
+	Votes int    `json:"votes"`
+}
+
+// CreatePollRequest is the payload for posting a poll to a group channel
+type CreatePollRequest struct {
+	Question       string     `json:"question"`
+	Options        []string   `json:"options"`
+	Channel        string     `json:"channel"` // Defaults to general
+	MultipleChoice bool       `json:"multipleChoice"`
+	ClosesAt       *time.Time `json:"closesAt"`
+}
+
+// PollVoteRequest is the payload for voting in a poll. It replaces the member's earlier vote.
+type PollVoteRequest struct {
+	Options []int `json:"options"` // Indexes into the poll's options
+}
+
+// PollPage is a page of a group's polls, newest first. Pass NextCursor as before to get the
+// next page; it is empty on the last page.
+type PollPage struct {
+	Data       []*Poll `json:"data"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
+
+// OptionTexts returns the text of each of the poll's options
+func (p *Poll) OptionTexts() []string {
+	texts := make([]string, len(p.Options))
+	for i, option := range p.Options {
+		texts[i] = option.Text
+	}
+	return texts
+}
+
+// Closed reports whether the poll stopped taking votes by now
+func (p *Poll) Closed(now time.Time) bool {
+	return p.ClosesAt != nil && !now.Before(*p.ClosesAt)
+}
+
+// Ballot returns the options of a vote sorted and without repeats, or false if the vote picks no
+// option, an option the poll doesn't have, or more than one option of a single-choice poll
+func (p *Poll) Ballot(options []int) ([]int, bool) {
+	ballot := slices.Clone(options)
+	slices.Sort(ballot)
+	ballot = slices.Compact(ballot)
+	if len(ballot) == 0 || ballot[0] < 0 || ballot[len(ballot)-1] >= len(p.Options) {
+		return nil, false
+	}
+	if !p.MultipleChoice && len(ballot) > 1 {
+		return nil, false
+	}
+	return ballot, true
+}
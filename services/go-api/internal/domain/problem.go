@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Problem platforms
+const (
+	PlatformLeetCode     = "leetcode"
+	PlatformAdventOfCode = "adventofcode"
+	PlatformCodeforces   = "codeforces"
+	PlatformHackerRank   = "hackerrank"
+	PlatformOther        = "other"
+)
+
+// Problem difficulties. Advent of Code and some other platforms don't rate problems, so a
+// difficulty is optional.
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
+
+// MaxProblemNotesLength caps a problem's notes, in characters
+const MaxProblemNotesLength = 10000
+
+// Problem is a coding problem the user solved on LeetCode, Advent of Code, or a similar site
+type Problem struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"userId"`
+	Platform   string    `json:"platform"`
+	ProblemID  string    `json:"problemId"` // the platform's ID or slug, e.g. two-sum or 2023/12
+	Title      string    `json:"title"`
+	Difficulty string    `json:"difficulty,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	SnippetID  string    `json:"snippetId,omitempty"` // the user's snippet with their solution
+	Notes      string    `json:"notes"`
+	SolvedAt   time.Time `json:"solvedAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// ValidPlatform reports whether platform is one of the problem platforms
+func ValidPlatform(platform string) bool {
+	switch platform {
+	case PlatformLeetCode, PlatformAdventOfCode, PlatformCodeforces, PlatformHackerRank, PlatformOther:
+		return true
+	}
+	return false
+}
+
+// ValidDifficulty reports whether difficulty is one of the problem difficulties, or unrated
+func ValidDifficulty(difficulty string) bool {
+	switch difficulty {
+	case "", DifficultyEasy, DifficultyMedium, DifficultyHard:
+		return true
+	}
+	return false
+}
+
+// ProblemRequest logs or updates a solved problem. SolvedAt defaults to now.
+type ProblemRequest struct {
+	Platform   string     `json:"platform"`
+	ProblemID  string     `json:"problemId"`
+	Title      string     `json:"title"`
+	Difficulty string     `json:"difficulty"`
+	URL        string     `json:"url"`
+	SnippetID  string     `json:"snippetId"`
+	Notes      string     `json:"notes"`
+	SolvedAt   *time.Time `json:"solvedAt"`
+}
+
+// ProblemStats summarizes the problems a user solved
+type ProblemStats struct {
+	Total        int                `json:"total"`
+	ByDifficulty map[string]int     `json:"byDifficulty"` // easy, medium, hard, and unrated
+	ByPlatform   map[string]int     `json:"byPlatform"`
+	Weekly       []WeeklySolveCount `json:"weekly"` // oldest week first
+}
+
+// WeeklySolveCount counts the problems solved in a week starting on Monday
+type WeeklySolveCount struct {
+	WeekStart time.Time `json:"weekStart"`
+	Solved    int       `json:"solved"`
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,6 +14,8 @@ type LearningProgress struct {
 	Date              time.Time `json:"date"` // Date only (no time component)
 	EntriesCount      int       `json:"entriesCount"`
 	SnippetsCount     int       `json:"snippetsCount"`
+	TILsCount         int       `json:"tilsCount"`
+	ProblemsCount     int       `json:"problemsCount"`
 	StreakDays        int       `json:"streakDays"`
 	TotalLearningTime int       `json:"totalLearningTime"` // in minutes
 	CreatedAt         time.Time `json:"createdAt"`
@@ -26,6 +29,8 @@ func NewLearningProgress(userID uuid.UUID, date time.Time) *LearningProgress {
 		Date:              date.Truncate(24 * time.Hour),
 		EntriesCount:      0,
 		SnippetsCount:     0,
+		TILsCount:         0,
+		ProblemsCount:     0,
 		StreakDays:        0,
 		TotalLearningTime: 0,
 		CreatedAt:         time.Now().UTC(),
@@ -38,7 +43,130 @@ type ProgressSummary struct {
 	LongestStreak     int `json:"longestStreak"`
 	TotalEntries      int `json:"totalEntries"`
 	TotalSnippets     int `json:"totalSnippets"`
+	TotalTILs         int `json:"totalTils"`
+	TotalProblems     int `json:"totalProblems"`
 	TotalLearningTime int `json:"totalLearningTime"` // in minutes
 	ThisWeekEntries   int `json:"thisWeekEntries"`
 	ThisMonthEntries  int `json:"thisMonthEntries"`
+
+	// LearningPaths are the paths the user owns or has started, with their progress
+	LearningPaths []LearningPath `json:"learningPaths"`
+
+	// CodingLanguages is all-time coding time by language, from WakaTime or editor heartbeats
+	CodingLanguages []LanguageTime `json:"codingLanguages"`
+}
+
+// Progress comparison limits
+const (
+	ProgressComparisonDays     = 30 // Days of activity a comparison covers
+	MaxProgressComparisonUsers = 10 // Other users one comparison can include
+)
+
+// ProgressComparison compares the consistency of the requesting user with people they mutually
+// follow or share a study group with, for those who turned on shareProgress. Users are listed
+// most consistent first, by rank only, so the response doesn't say who had which counts.
+type ProgressComparison struct {
+	Days  int                       `json:"days"`
+	Users []ProgressComparisonEntry `json:"users"`
+}
+
+// ProgressComparisonEntry is one user's consistency over the compared days. It holds counts
+// only, never what the user wrote.
+type ProgressComparisonEntry struct {
+	UserID        uuid.UUID `json:"-"`
+	Rank          int       `json:"rank"` // 1 for the most consistent user
+	You           bool      `json:"you"`
+	CurrentStreak int       `json:"currentStreak"`
+	LongestStreak int       `json:"longestStreak"`
+	ActiveDays    int       `json:"activeDays"`  // Days with an entry, snippet, TIL, or problem
+	Entries       int       `json:"entries"`     // Journal entries written
+	Consistency   float64   `json:"consistency"` // Share of the days the user was active, from 0 to 1
+}
+
+// Progress query limits
+const (
+	MaxProgressQuerySeries  = 10  // Series one progress query can ask for
+	MaxProgressQueryBuckets = 400 // Buckets one series can span, a little over a year of days
+)
+
+// ProgressQueryRequest asks for several series of progress in one call, e.g. everything a
+// dashboard charts
+type ProgressQueryRequest struct {
+	Series []ProgressSeriesQuery `json:"series"`
+}
+
+// ProgressSeriesQuery is one series: progress between two dates, inclusive, summed per day, week
+// (starting Monday), or month
+type ProgressSeriesQuery struct {
+	From    string `json:"from"`    // YYYY-MM-DD
+	To      string `json:"to"`      // YYYY-MM-DD
+	GroupBy string `json:"groupBy"` // day, week, or month
+}
+
+// ProgressSeries is the progress of one ProgressSeriesQuery, with a bucket for every period in
+// the range, including empty ones
+type ProgressSeries struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	GroupBy string           `json:"groupBy"`
+	Buckets []ProgressBucket `json:"buckets"`
+}
+
+// ProgressBucket sums the progress of one period. The first and last periods may start before or
+// end after the range; only days within it count.
+type ProgressBucket struct {
+	PeriodStart       time.Time `json:"periodStart"`
+	EntriesCount      int       `json:"entriesCount"`
+	SnippetsCount     int       `json:"snippetsCount"`
+	TILsCount         int       `json:"tilsCount"`
+	ProblemsCount     int       `json:"problemsCount"`
+	TotalLearningTime int       `json:"totalLearningTime"` // in minutes
+	ActiveDays        int       `json:"activeDays"`        // Days with an entry, snippet, TIL, or problem
+}
+
+// ProgressPeriodStart returns the start of the day, week (Monday), or month a date falls in
+func ProgressPeriodStart(date time.Time, groupBy string) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	switch groupBy {
+	case StatsIntervalWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case StatsIntervalMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+// NextProgressPeriod returns the start of the period after the one starting at start
+func NextProgressPeriod(start time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case StatsIntervalWeek:
+		return start.AddDate(0, 0, 7)
+	case StatsIntervalMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// StreakMilestones are the streak lengths, in days, that earn an achievement
+var StreakMilestones = []int{7, 30, 100, 365}
+
+// StreakMilestone is a user reaching one of StreakMilestones for the first time
+type StreakMilestone struct {
+	UserID      uuid.UUID
+	WorkspaceID uuid.UUID // The workspace the user was active in
+	Days        int
+	ReachedAt   time.Time
+}
+
+// Achievement is something a user earned once, such as a 30-day streak
+type Achievement struct {
+	Kind     string    `json:"kind"` // e.g. streak_30
+	EarnedAt time.Time `json:"earnedAt"`
+}
+
+// StreakAchievement returns the achievement kind for reaching a streak milestone
+func StreakAchievement(days int) string {
+	return fmt.Sprintf("streak_%d", days)
 }
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Project statuses
+const (
+	ProjectActive    = "active"
+	ProjectPaused    = "paused"
+	ProjectCompleted = "completed"
+	ProjectArchived  = "archived"
+)
+
+// MaxFocusMinutes caps a single focus session at one day
+const MaxFocusMinutes = 24 * 60
+
+// Timeline item types
+const (
+	TimelineEntry   = "entry"
+	TimelineSnippet = "snippet"
+	TimelineFocus   = "focus"
+)
+
+// Project groups journal entries, snippets, and focus time spent on the same piece of work
+type Project struct {
+	ID          uuid.UUID     `json:"id"`
+	UserID      uuid.UUID     `json:"userId"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Status      string        `json:"status"`
+	Stats       *ProjectStats `json:"stats,omitempty"` // set when a project is fetched by ID
+	CreatedAt   time.Time     `json:"createdAt"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+}
+
+// ProjectStats counts the work attached to a project
+type ProjectStats struct {
+	Entries      int `json:"entries"`
+	Snippets     int `json:"snippets"`
+	FocusMinutes int `json:"focusMinutes"`
+}
+
+// NewProject creates a new active project with timestamps
+func NewProject(userID uuid.UUID, name, description string) *Project {
+	now := time.Now().UTC()
+	return &Project{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Name:        name,
+		Description: description,
+		Status:      ProjectActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// ValidProjectStatus reports whether status is one of the project statuses
+func ValidProjectStatus(status string) bool {
+	switch status {
+	case ProjectActive, ProjectPaused, ProjectCompleted, ProjectArchived:
+		return true
+	}
+	return false
+}
+
+// ProjectRequest creates or updates a project. An empty status keeps the current one, or
+// starts a new project as active.
+type ProjectRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+}
+
+// FocusSession is time spent focused on a project
+type FocusSession struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"userId"`
+	ProjectID uuid.UUID `json:"projectId"`
+	StartedAt time.Time `json:"startedAt"`
+	Minutes   int       `json:"minutes"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// LogFocusRequest records a focus session. StartedAt defaults to Minutes before now.
+type LogFocusRequest struct {
+	StartedAt *time.Time `json:"startedAt"`
+	Minutes   int        `json:"minutes"`
+	Note      string     `json:"note"`
+}
+
+// TimelineItem is one piece of work on a project. Exactly one of Entry, Snippet, and Focus is set,
+// matching Type.
+type TimelineItem struct {
+	Type    string        `json:"type"`
+	At      time.Time     `json:"at"`
+	Entry   *JournalEntry `json:"entry,omitempty"`
+	Snippet *Snippet      `json:"snippet,omitempty"`
+	Focus   *FocusSession `json:"focus,omitempty"`
+}
+
+// TimelinePage is a page of a project's timeline, newest first. Pass NextCursor as before to
+// get the next page; it is empty on the last page.
+type TimelinePage struct {
+	Project    *Project       `json:"project"`
+	Data       []TimelineItem `json:"data"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
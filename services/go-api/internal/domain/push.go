@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Push platforms
+const (
+	PushPlatformIOS     = "ios"     // Apple Push Notification service
+	PushPlatformAndroid = "android" // Firebase Cloud Messaging
+)
+
+// Push notification types, sent to apps in the "type" data field
+const (
+	PushStreakReminder  = "streak_reminder"
+	PushStreakMilestone = "streak_milestone"
+	PushMention         = "mention"
+	PushDirectMessage   = "direct_message"
+	PushCodeReview      = "code_review"
+	PushGroupArchival   = "group_archival"
+	PushImpersonation   = "impersonation"
+)
+
+// directRoomPrefix marks chat rooms shared by exactly two users
+const directRoomPrefix = "dm:"
+
+// PushDevice is a mobile app installation that receives push notifications
+type PushDevice struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"userId"`
+	Platform  string    `json:"platform"`
+	Token     string    `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// RegisterPushDeviceRequest registers a device token from the mobile app
+type RegisterPushDeviceRequest struct {
+	Platform string `json:"platform"`
+	Token    string `json:"token"`
+}
+
+// StreakReminder is a user due a reminder to keep yesterday's streak going
+type StreakReminder struct {
+	UserID        uuid.UUID
+	StreakDays    int
+	StreakCounts  string // The user's StreakCounts* setting
+	StreakMinutes int    // Minutes a day needs with StreakCountsMinutes
+}
+
+// DirectRoom returns the chat room for direct messages between two users. Either order gives the same room.
+func DirectRoom(a, b uuid.UUID) string {
+	first, second := a.String(), b.String()
+	if second < first {
+		first, second = second, first
+	}
+	return directRoomPrefix + first + ":" + second
+}
+
+// DirectRoomMembers returns the two users of a direct message room, or false if room is not one
+func DirectRoomMembers(room string) (uuid.UUID, uuid.UUID, bool) {
+	rest, ok := strings.CutPrefix(room, directRoomPrefix)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	first, second, ok := strings.Cut(rest, ":")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	a, errA := uuid.Parse(first)
+	b, errB := uuid.Parse(second)
+	if errA != nil || errB != nil || a == b {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return a, b, true
+}
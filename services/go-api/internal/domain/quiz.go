@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Quiz limits
+const (
+	MaxQuizQuestions = 50
+	MaxQuizOptions   = 10
+)
+
+// Quiz is a multiple-choice quiz for a study group. Quizzes are stored in MongoDB; attempts
+// are stored in Postgres next to the group's members.
+type Quiz struct {
+	ID            string         `json:"id"`
+	GroupID       string         `json:"groupId"`
+	CreatedBy     string         `json:"createdBy"`
+	Title         string         `json:"title"`
+	Description   string         `json:"description"`
+	Questions     []QuizQuestion `json:"questions,omitempty"` // left out of lists
+	QuestionCount int            `json:"questionCount"`
+	BestScore     *int           `json:"bestScore,omitempty"` // the requesting member's best attempt
+	CreatedAt     time.Time      `json:"createdAt"`
+	UpdatedAt     time.Time      `json:"updatedAt"`
+}
+
+// QuizQuestion is a question with one correct option. Answer and Explanation are only shown to
+// group owners and admins, and to members in their attempt results.
+type QuizQuestion struct {
+	Prompt      string   `json:"prompt" bson:"prompt"`
+	Options     []string `json:"options" bson:"options"`
+	Answer      *int     `json:"answer,omitempty" bson:"answer"` // index into Options
+	Explanation string   `json:"explanation,omitempty" bson:"explanation"`
+}
+
+// Redact hides the quiz's answers from members who have yet to take it
+func (q *Quiz) Redact() {
+	for i := range q.Questions {
+		q.Questions[i].Answer = nil
+		q.Questions[i].Explanation = ""
+	}
+}
+
+// QuizRequest creates or replaces a quiz
+type QuizRequest struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Questions   []QuizQuestion `json:"questions"`
+}
+
+// QuizAttemptRequest answers every question of a quiz in order. -1 skips a question.
+type QuizAttemptRequest struct {
+	Answers []int `json:"answers"`
+}
+
+// QuizAttempt is a member's graded attempt at a quiz
+type QuizAttempt struct {
+	ID        uuid.UUID        `json:"id"`
+	QuizID    string           `json:"quizId"`
+	GroupID   uuid.UUID        `json:"groupId"`
+	UserID    uuid.UUID        `json:"userId"`
+	Score     int              `json:"score"`
+	Total     int              `json:"total"`
+	Results   []QuestionResult `json:"results,omitempty"` // set when the attempt is submitted
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+// QuestionResult grades one answer of an attempt
+type QuestionResult struct {
+	Answer        int    `json:"answer"`
+	Correct       bool   `json:"correct"`
+	CorrectAnswer int    `json:"correctAnswer"`
+	Explanation   string `json:"explanation,omitempty"`
+}
+
+// GradeQuiz scores answers against a quiz's questions. Answers must line up with the questions.
+func GradeQuiz(questions []QuizQuestion, answers []int) (score int, results []QuestionResult) {
+	results = make([]QuestionResult, len(questions))
+	for i, q := range questions {
+		correct := 0
+		if q.Answer != nil {
+			correct = *q.Answer
+		}
+		results[i] = QuestionResult{Answer: answers[i], Correct: answers[i] == correct, CorrectAnswer: correct, Explanation: q.Explanation}
+		if results[i].Correct {
+			score++
+		}
+	}
+	return score, results
+}
+
+// LeaderboardEntry ranks a study group member by the sum of their best score on each quiz
+type LeaderboardEntry struct {
+	Rank        int       `json:"rank"`
+	UserID      uuid.UUID `json:"userId"`
+	DisplayName string    `json:"displayName"`
+	Points      int       `json:"points"`
+	Quizzes     int       `json:"quizzes"` // quizzes taken
+}
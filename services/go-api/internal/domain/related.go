@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RelatedSnippet is a snippet linked to a journal entry
+type RelatedSnippet struct {
+	ID       string    `json:"id"`
+	Title    string    `json:"title"`
+	Language string    `json:"language"`
+	LinkedAt time.Time `json:"linkedAt"`
+}
+
+// RelatedEntry is a journal entry linked to a snippet
+type RelatedEntry struct {
+	ID       uuid.UUID `json:"id"`
+	Title    string    `json:"title"`
+	LinkedAt time.Time `json:"linkedAt"`
+}
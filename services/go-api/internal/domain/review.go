@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review item types
+const (
+	ReviewItemJournal = "journal"
+	ReviewItemSnippet = "snippet"
+)
+
+// Review feedback results
+const (
+	ReviewGotIt = "got_it"
+	ReviewAgain = "again"
+)
+
+// SM-2 ease factor bounds
+const (
+	defaultEaseFactor = 2.5
+	minEaseFactor     = 1.3
+)
+
+// ReviewItem is a journal entry or snippet scheduled for spaced-repetition review
+type ReviewItem struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"userId"`
+	ItemType       string     `json:"itemType"`
+	ItemID         string     `json:"itemId"`
+	EaseFactor     float64    `json:"easeFactor"`
+	IntervalDays   int        `json:"intervalDays"`
+	Repetitions    int        `json:"repetitions"`
+	DueAt          time.Time  `json:"dueAt"`
+	LastReviewedAt *time.Time `json:"lastReviewedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// NewReviewItem enrolls an item for review, due immediately
+func NewReviewItem(userID uuid.UUID, itemType, itemID string) *ReviewItem {
+	now := time.Now().UTC()
+	return &ReviewItem{
+		ID:         uuid.New(),
+		UserID:     userID,
+		ItemType:   itemType,
+		ItemID:     itemID,
+		EaseFactor: defaultEaseFactor,
+		DueAt:      now,
+		CreatedAt:  now,
+	}
+}
+
+// Schedule applies SM-2 to the item: "got it" is graded as quality 4 and grows the
+// interval (1 day, 6 days, then interval x ease); "again" is quality 1 and restarts it.
+func (item *ReviewItem) Schedule(result string, now time.Time) {
+	quality := 4
+	if result == ReviewAgain {
+		quality = 1
+	}
+
+	if quality >= 3 {
+		switch item.Repetitions {
+		case 0:
+			item.IntervalDays = 1
+		case 1:
+			item.IntervalDays = 6
+		default:
+			item.IntervalDays = int(math.Round(float64(item.IntervalDays) * item.EaseFactor))
+		}
+		item.Repetitions++
+	} else {
+		item.Repetitions = 0
+		item.IntervalDays = 1
+	}
+
+	miss := float64(5 - quality)
+	item.EaseFactor = math.Max(minEaseFactor, item.EaseFactor+0.1-miss*(0.08+miss*0.02))
+	item.LastReviewedAt = &now
+	item.DueAt = now.AddDate(0, 0, item.IntervalDays)
+}
+
+// ReviewCard is the next item to review along with its content
+type ReviewCard struct {
+	Item    *ReviewItem   `json:"item"`
+	Entry   *JournalEntry `json:"entry,omitempty"`
+	Snippet *Snippet      `json:"snippet,omitempty"`
+}
+
+// ReviewFeedbackRequest represents a "got it / review again" response to a review card
+type ReviewFeedbackRequest struct {
+	Result string `json:"result"`
+}
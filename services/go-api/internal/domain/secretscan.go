@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Secret finding severities. High severity findings block publishing unless acknowledged.
+const (
+	SecretSeverityHigh   = "high"
+	SecretSeverityMedium = "medium"
+)
+
+// SecretFinding is a likely credential detected in snippet code
+type SecretFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	File     string `json:"file,omitempty"` // the snippet file the line is in
+	Line     int    `json:"line"`
+	Preview  string `json:"preview"` // redacted match
+}
+
+// SecretScanResult is the outcome of scanning a snippet for secrets
+type SecretScanResult struct {
+	SnippetID string          `json:"snippetId"`
+	Clean     bool            `json:"clean"`
+	Findings  []SecretFinding `json:"findings"`
+}
+
+type secretRule struct {
+	name     string
+	severity string
+	pattern  *regexp.Regexp
+}
+
+var secretRules = []secretRule{
+	{"aws_access_key_id", SecretSeverityHigh, regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"aws_secret_access_key", SecretSeverityHigh, regexp.MustCompile(`(?i)aws.{0,20}secret.{0,20}[:=]\s*["']?[0-9a-zA-Z/+]{40}\b`)},
+	{"private_key", SecretSeverityHigh, regexp.MustCompile(`-----BEGIN (RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY( BLOCK)?-----`)},
+	{"github_token", SecretSeverityHigh, regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"slack_token", SecretSeverityHigh, regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z-]{10,}\b`)},
+	{"stripe_secret_key", SecretSeverityHigh, regexp.MustCompile(`\b[sr]k_live_[0-9A-Za-z]{20,}\b`)},
+	{"google_api_key", SecretSeverityHigh, regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"jwt", SecretSeverityMedium, regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"generic_secret", SecretSeverityMedium, regexp.MustCompile(`(?i)(api[_-]?key|secret|token|passw(or)?d)["']?\s*[:=]\s*["'][^"'\s]{12,}["']`)},
+}
+
+// ScanForSecrets reports lines of code that look like they contain credentials.
+// Each line reports at most one finding per rule.
+func ScanForSecrets(code string) []SecretFinding {
+	findings := []SecretFinding{}
+	for i, line := range strings.Split(code, "\n") {
+		for _, rule := range secretRules {
+			match := rule.pattern.FindString(line)
+			if match == "" {
+				continue
+			}
+			findings = append(findings, SecretFinding{
+				Rule:     rule.name,
+				Severity: rule.severity,
+				Line:     i + 1,
+				Preview:  redactSecret(match),
+			})
+		}
+	}
+	return findings
+}
+
+// ScanSnippetForSecrets scans every file of a snippet, naming the file of each finding
+func ScanSnippetForSecrets(s *Snippet) []SecretFinding {
+	findings := []SecretFinding{}
+	for _, file := range s.Files {
+		for _, f := range ScanForSecrets(file.Code) {
+			f.File = file.Name
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// HasHighSeveritySecret reports whether any finding should block publishing
+func HasHighSeveritySecret(findings []SecretFinding) bool {
+	for _, f := range findings {
+		if f.Severity == SecretSeverityHigh {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSecret keeps only the first few characters of a match so previews never leak the secret
+func redactSecret(match string) string {
+	const keep = 4
+	if len(match) <= keep {
+		return strings.Repeat("*", len(match))
+	}
+	return match[:keep] + strings.Repeat("*", min(len(match)-keep, 16))
+}
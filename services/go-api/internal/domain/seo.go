@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxSitemapURLs is how many URLs one sitemap file may list
+const MaxSitemapURLs = 50000
+
+// Page meta limits, matching what search engines and link unfurlers show
+const (
+	metaDescriptionLength = 200
+	shareImageLines       = 12
+)
+
+// SitemapURL is a page listed in the sitemap
+type SitemapURL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// PageMeta describes a public page for search engines and link previews (Open Graph and
+// Twitter cards). The web app renders it into the page's <head>.
+type PageMeta struct {
+	Type        string     `json:"type"` // og:type: article for snippets, profile for users
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	URL         string     `json:"url"` // canonical page URL
+	SiteName    string     `json:"siteName"`
+	AuthorName  string     `json:"authorName,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+	ModifiedAt  *time.Time `json:"modifiedAt,omitempty"`
+	Image       ShareImage `json:"image"`
+	ImageURL    string     `json:"imageUrl,omitempty"` // rendered share image, for og:image
+	OEmbedURL   string     `json:"oembedUrl,omitempty"`
+}
+
+// ShareImage is what the web app draws on a page's preview image
+type ShareImage struct {
+	Title    string   `json:"title"`
+	Subtitle string   `json:"subtitle"`
+	Language string   `json:"language,omitempty"`
+	Lines    []string `json:"lines,omitempty"` // the start of the code, for snippets
+}
+
+// MetaDescription collapses whitespace in s and shortens it to a length search engines show
+func MetaDescription(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= metaDescriptionLength {
+		return s
+	}
+	return string([]rune(s)[:metaDescriptionLength-1]) + "…"
+}
+
+// ShareImageLines returns the first lines of code for a preview image, with trailing blank lines removed
+func ShareImageLines(code string) []string {
+	lines := codeLines(code)
+	if len(lines) > shareImageLines {
+		lines = lines[:shareImageLines]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Sitemap renders urls as a sitemap.xml document (https://www.sitemaps.org/protocol.html)
+func Sitemap(urls []SitemapURL) ([]byte, error) {
+	type entry struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod,omitempty"`
+	}
+	doc := struct {
+		XMLName xml.Name `xml:"urlset"`
+		XMLNS   string   `xml:"xmlns,attr"`
+		URLs    []entry  `xml:"url"`
+	}{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for _, u := range urls {
+		e := entry{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			e.LastMod = u.LastMod.UTC().Format("2006-01-02")
+		}
+		doc.URLs = append(doc.URLs, e)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
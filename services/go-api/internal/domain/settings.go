@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultPageSize is used when neither the request nor the user's settings specify a page size
+	DefaultPageSize = 10
+
+	// MaxPageSize is the server-side cap on any list endpoint's page size
+	MaxPageSize = 100
+)
+
+// What counts toward a user's streak, chosen in their settings
+const (
+	StreakCountsAny               = "any"                 // An entry, snippet, TIL, or problem (default)
+	StreakCountsEntries           = "entries"             // Journal entries only
+	StreakCountsSnippets          = "snippets"            // Snippets only
+	StreakCountsEntriesOrSnippets = "entries_or_snippets" // An entry or a snippet
+	StreakCountsMinutes           = "minutes"             // At least StreakMinutes of learning time logged
+
+	// MaxStreakMinutes is the most learning time a day can require, a whole day
+	MaxStreakMinutes = 24 * 60
+)
+
+// MaxAvatarURLLength bounds the avatar URL a user can set
+const MaxAvatarURLLength = 2048
+
+// UserSettings holds per-user preferences
+type UserSettings struct {
+	UserID              uuid.UUID `json:"userId"`
+	DefaultPageSize     int       `json:"defaultPageSize"`
+	Locale              string    `json:"locale"`              // BCP 47 tag; empty follows Accept-Language
+	ShareProgress       bool      `json:"shareProgress"`       // Consents to progress comparisons with friends
+	CelebrateMilestones bool      `json:"celebrateMilestones"` // Announces streak milestones in the user's study groups
+	StreakCounts        string    `json:"streakCounts"`        // StreakCounts*: which activity keeps the streak going
+	StreakMinutes       int       `json:"streakMinutes"`       // Minutes a day needs with StreakCountsMinutes
+	AvatarURL           string    `json:"avatarUrl"`           // Shown on the user's member profile; empty for the default
+	UpdatedAt           time.Time `json:"updatedAt"`
+}
+
+// NewUserSettings returns the default settings for a user
+func NewUserSettings(userID uuid.UUID) *UserSettings {
+	return &UserSettings{
+		UserID:          userID,
+		DefaultPageSize: DefaultPageSize,
+		StreakCounts:    StreakCountsAny,
+		UpdatedAt:       time.Now().UTC(),
+	}
+}
+
+// UpdateUserSettingsRequest represents the request to change user settings
+type UpdateUserSettingsRequest struct {
+	DefaultPageSize     int     `json:"defaultPageSize"`
+	Locale              *string `json:"locale"`              // omitted keeps the current locale; empty follows Accept-Language
+	ShareProgress       *bool   `json:"shareProgress"`       // omitted keeps the current choice
+	CelebrateMilestones *bool   `json:"celebrateMilestones"` // omitted keeps the current choice
+	StreakCounts        *string `json:"streakCounts"`        // omitted keeps the current choice
+	StreakMinutes       *int    `json:"streakMinutes"`       // omitted keeps the current value
+	AvatarURL           *string `json:"avatarUrl"`           // omitted keeps the current avatar; empty removes it
+}
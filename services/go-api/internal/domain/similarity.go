@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxDuplicateDistance is the most bits the simhashes of two snippets can differ by for them to
+// count as near-duplicates, about 95% similar
+const MaxDuplicateDistance = 3
+
+// Code is compared as overlapping runs of shingleTokens tokens; code with fewer than
+// minSimHashTokens tokens is too short to compare meaningfully
+const (
+	shingleTokens    = 3
+	minSimHashTokens = 12
+)
+
+// codeToken matches identifiers, numbers, and single punctuation characters, so whitespace and
+// layout do not affect the tokens
+var codeToken = regexp.MustCompile(`[\p{L}_][\p{L}\p{N}_]*|\p{N}+|[^\s\p{L}\p{N}_]`)
+
+// SimilarSnippet is an existing snippet that is nearly the same as one being created
+type SimilarSnippet struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Language   string    `json:"language"`
+	Similarity float64   `json:"similarity"` // 0 to 1, from how many bits of the simhashes match
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// SnippetSimHash returns a 64-bit simhash of the code of a snippet's files, normalized by
+// lowercasing it and ignoring whitespace. Similar code gets hashes that differ in few bits. It is
+// 0 for code too short to compare.
+func SnippetSimHash(files []SnippetFile) uint64 {
+	var tokens []string
+	for _, f := range files {
+		tokens = append(tokens, codeToken.FindAllString(strings.ToLower(f.Code), -1)...)
+	}
+	if len(tokens) < minSimHashTokens {
+		return 0
+	}
+
+	var weights [64]int
+	for i := 0; i+shingleTokens <= len(tokens); i++ {
+		h := fnv.New64a()
+		for _, token := range tokens[i : i+shingleTokens] {
+			h.Write([]byte(token))
+			h.Write([]byte{0})
+		}
+		sum := h.Sum64()
+		for bit := range weights {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var hash uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			hash |= 1 << bit
+		}
+	}
+	if hash == 0 {
+		// 0 means too short to compare, so code that happens to hash to it still gets compared
+		hash = 1
+	}
+	return hash
+}
+
+// SimHashDistance returns how many bits two simhashes differ by
+func SimHashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// SimHashSimilarity returns the share of bits two simhashes have in common
+func SimHashSimilarity(a, b uint64) float64 {
+	return 1 - float64(SimHashDistance(a, b))/64
+}
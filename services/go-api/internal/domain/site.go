@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Site publisher kinds
+const (
+	SitePublisherWebhook = "webhook" // POSTs each entry to a URL, such as a Netlify or Vercel build hook
+	SitePublisherGitHub  = "github"  // commits each entry as a Markdown file to a GitHub repository
+)
+
+// SitePublisher sends a user's newly public journal entries to their own static site, making the
+// journal double as a blog. Token is a credential and is never returned to clients.
+type SitePublisher struct {
+	UserID     uuid.UUID `json:"-"`
+	Kind       string    `json:"kind"`
+	Enabled    bool      `json:"enabled"`
+	WebhookURL string    `json:"webhookUrl,omitempty"`
+	Repo       string    `json:"repo,omitempty"`   // owner/name
+	Branch     string    `json:"branch,omitempty"` // empty means the repository's default branch
+	Path       string    `json:"path,omitempty"`   // directory posts are committed to, e.g. _posts
+	Token      string    `json:"-"`
+	HasToken   bool      `json:"hasToken,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// UpdateSitePublisherRequest represents the request to set up a site publisher. An omitted
+// token keeps the current one.
+type UpdateSitePublisherRequest struct {
+	Kind       string `json:"kind"`
+	Enabled    bool   `json:"enabled"`
+	WebhookURL string `json:"webhookUrl"`
+	Repo       string `json:"repo"`
+	Branch     string `json:"branch"`
+	Path       string `json:"path"`
+	Token      string `json:"token"`
+}
+
+// SiteDelivery is a public entry queued to be sent to the user's site
+type SiteDelivery struct {
+	ID            uuid.UUID `json:"id"`
+	UserID        uuid.UUID `json:"-"`
+	EntryID       uuid.UUID `json:"entryId"`
+	EntryTitle    string    `json:"entryTitle"`
+	Status        string    `json:"status"` // pending, delivered, or failed
+	Attempts      int       `json:"attempts"`
+	LastError     string    `json:"lastError,omitempty"`
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// SitePost is the body POSTed to a site webhook for each newly public entry
+type SitePost struct {
+	Event string        `json:"event"` // entry.published
+	Entry SitePostEntry `json:"entry"`
+}
+
+// SitePostEntry is an entry as sent to a site, with the Markdown file a Git-based site would get
+type SitePostEntry struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	Content   string    `json:"content"`
+	Mood      string    `json:"mood,omitempty"`
+	Tags      []string  `json:"tags"`
+	Markdown  string    `json:"markdown"` // front matter and content
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -1,27 +1,65 @@
 package domain
 
 import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
 	"time"
 )
 
 // Snippet represents a code snippet stored in MongoDB
 // Uses flexible schema with metadata for different snippet types
 type Snippet struct {
-	ID          string                 `json:"id" bson:"_id,omitempty"`
-	UserID      string                 `json:"userId" bson:"user_id"`
-	Title       string                 `json:"title" bson:"title"`
-	Description string                 `json:"description" bson:"description"`
-	Code        string                 `json:"code" bson:"code"`
-	Language    string                 `json:"language" bson:"language"` // typescript, go, python, etc.
-	Tags        []string               `json:"tags" bson:"tags"`
-	Metadata    map[string]interface{} `json:"metadata" bson:"metadata"` // Flexible fields
-	IsPublic    bool                   `json:"isPublic" bson:"is_public"`
-	ViewsCount  int                    `json:"viewsCount" bson:"views_count"`
-	CreatedAt   time.Time              `json:"createdAt" bson:"created_at"`
-	UpdatedAt   time.Time              `json:"updatedAt" bson:"updated_at"`
+	ID            string                 `json:"id" bson:"_id,omitempty"`
+	UserID        string                 `json:"userId" bson:"user_id"`
+	WorkspaceID   string                 `json:"workspaceId" bson:"workspace_id"`
+	Title         string                 `json:"title" bson:"title"`
+	Description   string                 `json:"description" bson:"description"`
+	Code          string                 `json:"code" bson:"code"`         // the first file's code, for single-file clients
+	Language      string                 `json:"language" bson:"language"` // the first file's language: typescript, go, python, etc.
+	Files         []SnippetFile          `json:"files" bson:"files"`       // named files, like a gist; never empty
+	Tags          []string               `json:"tags" bson:"tags"`
+	Dependencies  []string               `json:"dependencies" bson:"dependencies"` // packages the files import
+	Metadata      map[string]interface{} `json:"metadata" bson:"metadata"`         // Flexible fields
+	IsPublic      bool                   `json:"isPublic" bson:"is_public"`
+	IsHidden      bool                   `json:"isHidden,omitempty" bson:"is_hidden"` // Hidden by moderators from everyone but the owner
+	ProjectID     string                 `json:"projectId,omitempty" bson:"project_id"`
+	SourceURL     string                 `json:"sourceUrl,omitempty" bson:"source_url"` // where the code was copied from
+	License       string                 `json:"license,omitempty" bson:"license"`      // SPDX license ID, e.g. MIT
+	ViewsCount    int                    `json:"viewsCount" bson:"views_count"`
+	UniqueViewers int                    `json:"uniqueViewers" bson:"unique_viewers"`
+	Stats         CodeStats              `json:"stats" bson:"stats"`                            // totals across files
+	Trending      float64                `json:"trendingScore,omitempty" bson:"trending_score"` // time-decayed recent views
+	MergedFrom    []MergedSnippet        `json:"mergedFrom,omitempty" bson:"merged_from"`       // snippets merged into this one, oldest merge first
+	SimHash       uint64                 `json:"-" bson:"simhash"`                              // for finding near-duplicates; 0 when too short
+	CreatedAt     time.Time              `json:"createdAt" bson:"created_at"`
+	UpdatedAt     time.Time              `json:"updatedAt" bson:"updated_at"`
+
+	// SecretWarnings lists acknowledged or low severity secret findings from the last publish
+	SecretWarnings []SecretFinding `json:"secretWarnings,omitempty" bson:"-"`
+	// RelatedEntries lists the owner's journal entries linked to the snippet, when fetched by ID
+	RelatedEntries []RelatedEntry `json:"relatedEntries,omitempty" bson:"-"`
+	// PlaygroundURL opens the snippet's first file in its language's online playground, for
+	// languages that have one
+	PlaygroundURL string `json:"playgroundUrl,omitempty" bson:"-"`
+}
+
+// MaxSnippetFiles is how many files one snippet can hold
+const MaxSnippetFiles = 20
+
+// SnippetFile is one named file of a snippet
+type SnippetFile struct {
+	Name     string    `json:"name"`
+	Language string    `json:"language"` // detected from the name when empty
+	Code     string    `json:"code"`
+	Stats    CodeStats `json:"stats"`
+
+	// OriginalCode is the code as it was given, when it was formatted on save
+	OriginalCode string `json:"originalCode,omitempty"`
 }
 
-// NewSnippet creates a new snippet with timestamps
+// NewSnippet creates a new single-file snippet with timestamps
 func NewSnippet(userID, title, description, code, language string, tags []string, metadata map[string]interface{}, isPublic bool) *Snippet {
 	now := time.Now().UTC()
 	if tags == nil {
@@ -30,12 +68,10 @@ func NewSnippet(userID, title, description, code, language string, tags []string
 	if metadata == nil {
 		metadata = make(map[string]interface{})
 	}
-	return &Snippet{
+	snippet := &Snippet{
 		UserID:      userID,
 		Title:       title,
 		Description: description,
-		Code:        code,
-		Language:    language,
 		Tags:        tags,
 		Metadata:    metadata,
 		IsPublic:    isPublic,
@@ -43,26 +79,181 @@ func NewSnippet(userID, title, description, code, language string, tags []string
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
+	snippet.SetFiles(SingleSnippetFile(title, code, language))
+	return snippet
+}
+
+// SingleSnippetFile returns the file list of a snippet given as one piece of code, named after its title
+func SingleSnippetFile(title, code, language string) []SnippetFile {
+	return []SnippetFile{{
+		Name:     SnippetFileName(&Snippet{Title: title, Language: language}),
+		Language: language,
+		Code:     code,
+	}}
+}
+
+// SetFiles replaces a snippet's files, detecting missing languages from file names and computing
+// stats, dependencies, and the simhash. Code and Language mirror the first file so single-file clients keep working.
+func (s *Snippet) SetFiles(files []SnippetFile) {
+	s.Files = make([]SnippetFile, len(files))
+	s.Stats = CodeStats{}
+	s.Dependencies = []string{}
+	for i, f := range files {
+		if f.Language == "" {
+			f.Language = LanguageFromPath(f.Name)
+		}
+		f.Stats = ComputeCodeStats(f.Code, f.Language)
+		s.Files[i] = f
+
+		s.Stats.Lines += f.Stats.Lines
+		s.Stats.CodeLines += f.Stats.CodeLines
+		s.Stats.CommentLines += f.Stats.CommentLines
+		s.Stats.BlankLines += f.Stats.BlankLines
+		s.Stats.Complexity += f.Stats.Complexity
+		s.Stats.MaxNesting = max(s.Stats.MaxNesting, f.Stats.MaxNesting)
+
+		for _, dep := range ExtractDependencies(f.Code, f.Language) {
+			if !slices.Contains(s.Dependencies, dep) {
+				s.Dependencies = append(s.Dependencies, dep)
+			}
+		}
+	}
+	slices.Sort(s.Dependencies)
+	s.SimHash = SnippetSimHash(s.Files)
+	s.Code, s.Language = "", ""
+	if len(s.Files) > 0 {
+		s.Code, s.Language = s.Files[0].Code, s.Files[0].Language
+	}
+}
+
+// MergeSnippetFiles appends the files of a snippet being merged to another's. A file with the
+// same name and code as one already there is dropped; other files whose names are taken are
+// renamed, e.g. main.go to main-2.go. renamed maps the old names of renamed files to the new.
+func MergeSnippetFiles(into, from []SnippetFile) (files []SnippetFile, renamed map[string]string) {
+	files = slices.Clone(into)
+	renamed = make(map[string]string)
+	taken := make(map[string]string, len(into)+len(from)) // lowercase name to code
+	for _, f := range files {
+		taken[strings.ToLower(f.Name)] = f.Code
+	}
+
+	for _, f := range from {
+		if code, ok := taken[strings.ToLower(f.Name)]; ok {
+			if code == f.Code {
+				continue
+			}
+			ext := path.Ext(f.Name)
+			base := strings.TrimSuffix(f.Name, ext)
+			name := f.Name
+			for n := 2; ; n++ {
+				name = fmt.Sprintf("%s-%d%s", base, n, ext)
+				if _, used := taken[strings.ToLower(name)]; !used {
+					break
+				}
+			}
+			renamed[f.Name] = name
+			f.Name = name
+		}
+		files = append(files, f)
+		taken[strings.ToLower(f.Name)] = f.Code
+	}
+	return files, renamed
 }
 
-// CreateSnippetRequest represents the request to create a snippet
+// File returns the snippet's file with the given name, or nil
+func (s *Snippet) File(name string) *SnippetFile {
+	for i := range s.Files {
+		if s.Files[i].Name == name {
+			return &s.Files[i]
+		}
+	}
+	return nil
+}
+
+// CreateSnippetRequest represents the request to create a snippet. Give either Files, or Code
+// and Language for a single file.
 type CreateSnippetRequest struct {
-	Title       string                 `json:"title"`
-	Description string                 `json:"description"`
-	Code        string                 `json:"code"`
-	Language    string                 `json:"language"`
-	Tags        []string               `json:"tags"`
-	Metadata    map[string]interface{} `json:"metadata"`
-	IsPublic    bool                   `json:"isPublic"`
+	Title              string                 `json:"title"`
+	Description        string                 `json:"description"`
+	Code               string                 `json:"code"`
+	Language           string                 `json:"language"`
+	Files              []SnippetFile          `json:"files"`
+	Tags               []string               `json:"tags"`
+	Metadata           map[string]interface{} `json:"metadata"`
+	IsPublic           bool                   `json:"isPublic"`
+	SourceURL          string                 `json:"sourceUrl"`
+	License            string                 `json:"license"`            // SPDX license ID; detected from the code's header when empty
+	AcknowledgeSecrets bool                   `json:"acknowledgeSecrets"` // publish even if high severity secrets are detected
+	Format             bool                   `json:"format"`             // format the code before saving, keeping the original
+	AllowDuplicate     bool                   `json:"allowDuplicate"`     // create even if a very similar snippet exists
 }
 
-// UpdateSnippetRequest represents the request to update a snippet
+// UpdateSnippetRequest represents the request to update a snippet. Files replaces every file;
+// without it, Code and Language replace only the first file.
 type UpdateSnippetRequest struct {
-	Title       string                 `json:"title"`
-	Description string                 `json:"description"`
-	Code        string                 `json:"code"`
-	Language    string                 `json:"language"`
-	Tags        []string               `json:"tags"`
-	Metadata    map[string]interface{} `json:"metadata"`
-	IsPublic    bool                   `json:"isPublic"`
+	Title              string                 `json:"title"`
+	Description        string                 `json:"description"`
+	Code               string                 `json:"code"`
+	Language           string                 `json:"language"`
+	Files              []SnippetFile          `json:"files"`
+	Tags               []string               `json:"tags"`
+	Metadata           map[string]interface{} `json:"metadata"`
+	IsPublic           bool                   `json:"isPublic"`
+	SourceURL          *string                `json:"sourceUrl"`          // unchanged when omitted
+	License            *string                `json:"license"`            // unchanged when omitted; detected from the code's header when empty
+	AcknowledgeSecrets bool                   `json:"acknowledgeSecrets"` // publish even if high severity secrets are detected
+	Format             bool                   `json:"format"`             // format the code before saving, keeping the original
+}
+
+// MergedSnippet records a snippet that was merged into another
+type MergedSnippet struct {
+	ID        string    `json:"id" bson:"id"`
+	Title     string    `json:"title" bson:"title"`
+	CreatedAt time.Time `json:"createdAt" bson:"created_at"`
+	MergedAt  time.Time `json:"mergedAt" bson:"merged_at"`
+}
+
+// MergeSnippetsRequest represents the request to merge the source snippet into the target,
+// which keeps its ID, title, and visibility
+type MergeSnippetsRequest struct {
+	TargetID           string `json:"targetId"`
+	SourceID           string `json:"sourceId"`
+	AcknowledgeSecrets bool   `json:"acknowledgeSecrets"` // merge even if high severity secrets are detected in a public target
+}
+
+// Snippet visibility values accepted by SnippetFilter
+const (
+	SnippetVisibilityPublic  = "public"
+	SnippetVisibilityPrivate = "private"
+)
+
+// SnippetFilter combines optional snippet list criteria; every set field must match
+type SnippetFilter struct {
+	Search     string     // Full-text query over title, description, and code
+	Tags       []string   // Matches snippets carrying any of these tags
+	Language   string     // Exact programming language
+	Dependency string     // Package imported by any of the snippet's files
+	Visibility string     // "public", "private", or empty for both
+	From       *time.Time // Created at or after
+	To         *time.Time // Created before
+}
+
+// DependencyCount is how many of a user's snippets import a package
+type DependencyCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// Time bucket sizes for language stats over time
+const (
+	StatsIntervalDay   = "day"
+	StatsIntervalWeek  = "week"
+	StatsIntervalMonth = "month"
+	StatsIntervalYear  = "year"
+)
+
+// LanguageStatsBucket holds snippet counts per language for one time bucket
+type LanguageStatsBucket struct {
+	PeriodStart time.Time        `json:"periodStart"`
+	Counts      map[string]int64 `json:"counts"`
 }
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxLineDiffCells caps the table used to match changed lines between two versions of a file.
+// Larger edits leave comments on the changed lines outdated rather than matching them.
+const maxLineDiffCells = 1 << 20
+
+// SnippetComment is a comment on a line, or a range of lines, of one of a snippet's files.
+// When the snippet is edited the comment follows its lines; if they were changed or removed it
+// is marked outdated and keeps the lines it was written against.
+type SnippetComment struct {
+	ID         uuid.UUID  `json:"id"`
+	SnippetID  string     `json:"snippetId"`
+	UserID     uuid.UUID  `json:"userId"`
+	AuthorName string     `json:"authorName"`
+	File       string     `json:"file"`
+	StartLine  int        `json:"startLine"` // 1-based
+	EndLine    int        `json:"endLine"`   // inclusive; equal to StartLine for a single line
+	Body       string     `json:"body"`
+	Outdated   bool       `json:"outdated"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// SnippetCommentRequest represents the request to comment on a snippet. EndLine defaults to StartLine.
+type SnippetCommentRequest struct {
+	File      string `json:"file"`
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	Body      string `json:"body"`
+}
+
+// Reanchor moves the comment to where its lines are after an edit, given the edit's line map
+// (see LineMap), or marks it outdated if they changed. A nil map means its file was removed or
+// renamed. It reports whether the comment changed.
+func (c *SnippetComment) Reanchor(lineMap []int) bool {
+	if c.Outdated {
+		return false
+	}
+	start, end := 0, 0
+	if c.StartLine >= 1 && c.EndLine <= len(lineMap) && c.StartLine <= c.EndLine {
+		start, end = lineMap[c.StartLine-1], lineMap[c.EndLine-1]
+	}
+	// A range stays anchored only if its first and last lines survived in order
+	if start == 0 || end == 0 || end < start {
+		c.Outdated = true
+		return true
+	}
+	if start == c.StartLine && end == c.EndLine {
+		return false
+	}
+	c.StartLine, c.EndLine = start, end
+	return true
+}
+
+// LineMap maps each line of before to its line number in after, or 0 if the line was changed or
+// removed, by matching the longest common sequence of unchanged lines
+func LineMap(before, after string) []int {
+	a, b := codeLines(before), codeLines(after)
+	lineMap := make([]int, len(a))
+
+	// Lines before and after the edited region match one for one
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		lineMap[prefix] = prefix + 1
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		lineMap[len(a)-1-suffix] = len(b) - suffix
+		suffix++
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA) == 0 || len(midB) == 0 || len(midA)*len(midB) > maxLineDiffCells {
+		return lineMap
+	}
+
+	// lcs[i][j] is the length of the longest common sequence of midA[i:] and midB[j:]
+	lcs := make([][]int32, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(midA) && j < len(midB); {
+		switch {
+		case midA[i] == midB[j]:
+			lineMap[prefix+i] = prefix + j + 1
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return lineMap
+}
+
+// codeLines splits code into lines the way ComputeCodeStats counts them
+func codeLines(code string) []string {
+	if code == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(code, "\n"), "\n")
+}
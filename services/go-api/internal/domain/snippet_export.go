@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Editor snippet export formats
+const (
+	SnippetExportVSCode    = "vscode"    // a .code-snippets file
+	SnippetExportJetBrains = "jetbrains" // a live template set
+)
+
+// prefixStopWords are left out of prefixes derived from titles
+var prefixStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "in": true, "of": true, "for": true,
+	"to": true, "with": true, "on": true, "by": true, "using": true, "from": true,
+}
+
+// maxPrefixWords caps how many title words a prefix keeps
+const maxPrefixWords = 3
+
+// SnippetPrefix returns the abbreviation that expands to a snippet in an editor, from the first
+// words of its title that carry meaning, e.g. "Binary search in Go" becomes binary-search-go.
+// Titles without letters or digits fall back to the first tag, then to "snippet".
+func SnippetPrefix(s *Snippet) string {
+	var words []string
+	for _, word := range strings.Fields(nonSlugChars.ReplaceAllString(strings.ToLower(s.Title), " ")) {
+		if !prefixStopWords[word] {
+			words = append(words, word)
+		}
+		if len(words) == maxPrefixWords {
+			break
+		}
+	}
+	if len(words) > 0 {
+		return strings.Join(words, "-")
+	}
+	for _, tag := range s.Tags {
+		if tag = strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(tag), "-"), "-"); tag != "" {
+			return tag
+		}
+	}
+	return "snippet"
+}
+
+// EditorSnippetTemplate is one snippet file as an editor template
+type EditorSnippetTemplate struct {
+	Name        string // unique within an export
+	Prefix      string // unique within an export
+	Description string
+	Language    string
+	Code        string
+	Tags        []string
+}
+
+// EditorSnippetTemplates turns snippets into editor templates, one per file. Names and prefixes
+// that repeat get a numeric suffix, since editors keep only one template per name.
+func EditorSnippetTemplates(snippets []Snippet) []EditorSnippetTemplate {
+	names, prefixes := make(map[string]int), make(map[string]int)
+	unique := func(seen map[string]int, value, sep string) string {
+		seen[value]++
+		if n := seen[value]; n > 1 {
+			return fmt.Sprintf("%s%s%d", value, sep, n)
+		}
+		return value
+	}
+
+	var templates []EditorSnippetTemplate
+	for i := range snippets {
+		s := &snippets[i]
+		description := strings.TrimSpace(s.Description)
+		if description == "" {
+			description = s.Title
+		}
+		prefix := SnippetPrefix(s)
+		for _, f := range s.Files {
+			name, filePrefix := s.Title, prefix
+			if len(s.Files) > 1 {
+				name = fmt.Sprintf("%s (%s)", s.Title, f.Name)
+				stem := strings.TrimSuffix(f.Name, path.Ext(f.Name))
+				if stem = strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(stem), "-"), "-"); stem != "" {
+					filePrefix += "-" + stem
+				}
+			}
+			templates = append(templates, EditorSnippetTemplate{
+				Name:        unique(names, name, " "),
+				Prefix:      unique(prefixes, filePrefix, "-"),
+				Description: description,
+				Language:    strings.ToLower(f.Language),
+				Code:        f.Code,
+				Tags:        s.Tags,
+			})
+		}
+	}
+	return templates
+}
+
+// vscodeLanguages maps snippet languages to VS Code language IDs where they differ
+var vscodeLanguages = map[string]string{
+	"bash":       "shellscript",
+	"shell":      "shellscript",
+	"objectivec": "objective-c",
+	"hcl":        "terraform",
+	"protobuf":   "proto3",
+	"text":       "plaintext",
+}
+
+// vscodeSnippet is one entry of a VS Code snippets file
+type vscodeSnippet struct {
+	Prefix      []string `json:"prefix"`
+	Body        []string `json:"body"`
+	Description string   `json:"description,omitempty"`
+	Scope       string   `json:"scope,omitempty"`
+}
+
+// VSCodeSnippets renders templates as a VS Code .code-snippets file. Each snippet also expands
+// from its tags, and is scoped to its language.
+func VSCodeSnippets(templates []EditorSnippetTemplate) ([]byte, error) {
+	file := make(map[string]vscodeSnippet, len(templates))
+	for _, t := range templates {
+		scope := t.Language
+		if id, ok := vscodeLanguages[scope]; ok {
+			scope = id
+		}
+		prefixes := append([]string{t.Prefix}, t.Tags...)
+		file[t.Name] = vscodeSnippet{
+			Prefix:      prefixes,
+			Body:        strings.Split(escapeVSCodeSnippet(t.Code), "\n"),
+			Description: t.Description,
+			Scope:       scope,
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// escapeVSCodeSnippet escapes the characters VS Code reads as tab stops and variables, so code
+// is inserted as written
+func escapeVSCodeSnippet(code string) string {
+	code = strings.ReplaceAll(code, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, `$`, `\$`, `}`, `\}`).Replace(code)
+}
+
+// jetbrainsContexts maps snippet languages to the live template context they apply in.
+// Others apply in the "Other" context.
+var jetbrainsContexts = map[string]string{
+	"go": "GO", "java": "JAVA_CODE", "kotlin": "KOTLIN", "python": "Python", "javascript": "JAVASCRIPT",
+	"typescript": "TYPE_SCRIPT", "php": "PHP", "ruby": "RUBY", "rust": "RUST_FILE", "sql": "SQL",
+	"html": "HTML", "css": "CSS", "scss": "SCSS", "bash": "SHELL_SCRIPT", "shell": "SHELL_SCRIPT",
+	"json": "JSON", "yaml": "YAML", "markdown": "MARKDOWN", "xml": "XML", "csharp": "CSHARP",
+	"cpp": "OC_SOURCE_FILE", "c": "OC_SOURCE_FILE", "swift": "SWIFT",
+}
+
+// JetBrains live template XML
+type (
+	jetbrainsTemplateSet struct {
+		XMLName   xml.Name            `xml:"templateSet"`
+		Group     string              `xml:"group,attr"`
+		Templates []jetbrainsTemplate `xml:"template"`
+	}
+	jetbrainsTemplate struct {
+		Name             string            `xml:"name,attr"`
+		Value            string            `xml:"value,attr"`
+		Description      string            `xml:"description,attr"`
+		ToReformat       bool              `xml:"toReformat,attr"`
+		ToShortenFQNames bool              `xml:"toShortenFQNames,attr"`
+		Context          []jetbrainsOption `xml:"context>option"`
+	}
+	jetbrainsOption struct {
+		Name  string `xml:"name,attr"`
+		Value bool   `xml:"value,attr"`
+	}
+)
+
+// JetBrainsTemplates renders templates as a JetBrains live template set in the given group. Live
+// templates expand from their name, so each is named after its prefix.
+func JetBrainsTemplates(group string, templates []EditorSnippetTemplate) ([]byte, error) {
+	set := jetbrainsTemplateSet{Group: group, Templates: make([]jetbrainsTemplate, 0, len(templates))}
+	for _, t := range templates {
+		context, ok := jetbrainsContexts[t.Language]
+		if !ok {
+			context = "OTHER"
+		}
+		set.Templates = append(set.Templates, jetbrainsTemplate{
+			Name:             t.Prefix,
+			Value:            strings.ReplaceAll(strings.ReplaceAll(t.Code, "\r\n", "\n"), "$", "$$"),
+			Description:      t.Name,
+			ToShortenFQNames: true,
+			Context:          []jetbrainsOption{{Name: context, Value: true}},
+		})
+	}
+
+	body, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(body, '\n'), nil
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"path"
+	"strings"
+)
+
+// languageExtensions maps snippet languages to the file extension used when downloading them
+var languageExtensions = map[string]string{
+	"go": ".go", "typescript": ".ts", "javascript": ".js", "python": ".py", "ruby": ".rb", "rust": ".rs",
+	"java": ".java", "kotlin": ".kt", "swift": ".swift", "c": ".c", "cpp": ".cpp", "csharp": ".cs",
+	"php": ".php", "scala": ".scala", "bash": ".sh", "shell": ".sh", "powershell": ".ps1", "sql": ".sql",
+	"html": ".html", "css": ".css", "scss": ".scss", "json": ".json", "yaml": ".yaml", "toml": ".toml",
+	"markdown": ".md", "protobuf": ".proto", "dart": ".dart", "elixir": ".ex", "haskell": ".hs",
+	"lua": ".lua", "r": ".r", "vue": ".vue", "svelte": ".svelte", "hcl": ".tf", "perl": ".pl",
+	"objectivec": ".m", "xml": ".xml", "graphql": ".graphql",
+}
+
+// languageFileNames are languages whose files have a conventional name rather than an extension
+var languageFileNames = map[string]string{
+	"dockerfile": "Dockerfile",
+	"makefile":   "Makefile",
+}
+
+// languageContentTypes maps snippet languages to the media type of their raw source. Others are text/plain.
+var languageContentTypes = map[string]string{
+	"go": "text/x-go", "typescript": "text/x-typescript", "javascript": "text/javascript",
+	"python": "text/x-python", "ruby": "text/x-ruby", "rust": "text/x-rust", "java": "text/x-java",
+	"kotlin": "text/x-kotlin", "swift": "text/x-swift", "c": "text/x-c", "cpp": "text/x-c++",
+	"csharp": "text/x-csharp", "php": "text/x-php", "scala": "text/x-scala", "bash": "text/x-shellscript",
+	"shell": "text/x-shellscript", "sql": "application/sql", "html": "text/html", "css": "text/css",
+	"scss": "text/x-scss", "json": "application/json", "yaml": "application/yaml", "toml": "application/toml",
+	"markdown": "text/markdown", "xml": "application/xml", "graphql": "application/graphql",
+}
+
+// SnippetContentType returns the media type of a snippet's raw code
+func SnippetContentType(language string) string {
+	contentType, ok := languageContentTypes[strings.ToLower(language)]
+	if !ok {
+		contentType = "text/plain"
+	}
+	return contentType + "; charset=utf-8"
+}
+
+// SnippetFileName returns a file name for downloading a snippet, from its title and language,
+// e.g. "Binary search in Go" in go becomes binary-search-in-go.go
+func SnippetFileName(s *Snippet) string {
+	language := strings.ToLower(s.Language)
+	if name, ok := languageFileNames[language]; ok {
+		return name
+	}
+	ext, ok := languageExtensions[language]
+	if !ok {
+		ext = ".txt"
+	}
+
+	// A title that is already a file name, like main.go, keeps its own extension
+	title := strings.ToLower(s.Title)
+	if titleExt := path.Ext(title); extensionLanguages[titleExt] == language && titleExt != "" {
+		ext = titleExt
+		title = strings.TrimSuffix(title, ext)
+	}
+	name := strings.Trim(nonSlugChars.ReplaceAllString(title, "-"), "-")
+	if len(name) > 80 {
+		name = strings.TrimRight(name[:80], "-")
+	}
+	if name == "" {
+		name = "snippet"
+	}
+	return name + ext
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Feed item types
+const (
+	FeedItemEntry   = "entry"
+	FeedItemSnippet = "snippet"
+)
+
+// PublicProfile is what anyone can see about a user
+type PublicProfile struct {
+	ID          uuid.UUID `json:"id"`
+	DisplayName string    `json:"displayName"`
+	Followers   int       `json:"followers"`
+	Following   int       `json:"following"`
+	IsFollowing bool      `json:"isFollowing"` // whether the requesting user follows them
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// UserSummary identifies a user in lists such as followers and feed items
+type UserSummary struct {
+	ID          uuid.UUID `json:"id"`
+	DisplayName string    `json:"displayName"`
+}
+
+// FeedItem is a public entry or snippet in a feed. Exactly one of Entry and Snippet is set.
+type FeedItem struct {
+	Type      string        `json:"type"` // entry, snippet
+	Author    UserSummary   `json:"author"`
+	Entry     *JournalEntry `json:"entry,omitempty"`
+	Snippet   *Snippet      `json:"snippet,omitempty"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+// FeedPage is a page of a feed, newest first. NextCursor fetches the following page and is
+// empty on the last page.
+type FeedPage struct {
+	Data       []FeedItem `json:"data"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
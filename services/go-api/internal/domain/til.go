@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxTILLength caps a TIL to a single short line
+const MaxTILLength = 280
+
+// TILEntry is a lightweight "Today I Learned" one-liner
+type TILEntry struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"userId"`
+	Content   string    `json:"content"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NewTILEntry creates a new TIL entry with a timestamp
+func NewTILEntry(userID uuid.UUID, content string, tags []string) *TILEntry {
+	if tags == nil {
+		tags = []string{}
+	}
+	return &TILEntry{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Content:   content,
+		Tags:      tags,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// CreateTILRequest represents the request to create a TIL entry
+type CreateTILRequest struct {
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
+}
@@ -12,8 +12,12 @@ type User struct {
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"` // Never expose in JSON
 	DisplayName  string    `json:"displayName"`
+	IsAdmin      bool      `json:"isAdmin"`
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
+
+	// DeactivatedAt is set while the user is deactivated, and they can't sign in
+	DeactivatedAt *time.Time `json:"deactivatedAt,omitempty"`
 }
 
 // NewUser creates a new user with generated ID and timestamps
@@ -28,3 +32,15 @@ func NewUser(email, passwordHash, displayName string) *User {
 		UpdatedAt:    now,
 	}
 }
+
+// RefreshToken lets a client get a new sign-in token without the user's password. Each is used
+// once and replaced by the next in its family, which starts at a login.
+type RefreshToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	FamilyID  uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time // when it was exchanged for its successor
+	CreatedAt time.Time
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Workspace member roles
+const (
+	WorkspaceRoleOwner  = "owner"
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleMember = "member"
+)
+
+// Workspace partitions a user's entries, snippets, and study groups, e.g. "personal" or "team-acme".
+// Every user has a personal workspace whose ID equals their user ID.
+type Workspace struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`
+	OwnerID    uuid.UUID `json:"ownerId"`
+	IsPersonal bool      `json:"isPersonal"`
+	Role       string    `json:"role,omitempty"` // the requesting user's role
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// NewWorkspace creates a shared workspace owned by ownerID
+func NewWorkspace(name, slug string, ownerID uuid.UUID) *Workspace {
+	return &Workspace{
+		ID:        uuid.New(),
+		Name:      name,
+		Slug:      slug,
+		OwnerID:   ownerID,
+		Role:      WorkspaceRoleOwner,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// NewPersonalWorkspace creates the personal workspace for a user
+func NewPersonalWorkspace(user *User) *Workspace {
+	return &Workspace{
+		ID:         user.ID,
+		Name:       "Personal",
+		Slug:       "personal-" + user.ID.String(),
+		OwnerID:    user.ID,
+		IsPersonal: true,
+		Role:       WorkspaceRoleOwner,
+		CreatedAt:  user.CreatedAt,
+	}
+}
+
+// WorkspaceMember represents a user's membership in a workspace
+type WorkspaceMember struct {
+	WorkspaceID uuid.UUID `json:"workspaceId"`
+	UserID      uuid.UUID `json:"userId"`
+	DisplayName string    `json:"displayName,omitempty"`
+	Role        string    `json:"role"`
+	JoinedAt    time.Time `json:"joinedAt"`
+}
+
+// CreateWorkspaceRequest represents the request to create a workspace
+type CreateWorkspaceRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// AddWorkspaceMemberRequest represents the request to add a user to a workspace by email
+type AddWorkspaceMemberRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"` // admin or member (default)
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Yearly review limits
+const (
+	MinYearlyReviewYear = 2000
+	YearlyReviewTopTags = 10
+)
+
+// YearlyReview is a user's "year in review": what they wrote and coded in a calendar year (UTC)
+// in one workspace. Reports are cached and recomputed as the year goes on; Final reports were
+// computed after the year ended and won't change.
+type YearlyReview struct {
+	Year               int             `json:"year"`
+	TotalEntries       int             `json:"totalEntries"`
+	TotalWords         int             `json:"totalWords"`
+	TotalSnippets      int             `json:"totalSnippets"`
+	SnippetsByLanguage []LanguageCount `json:"snippetsByLanguage"` // Most snippets first
+	LongestStreak      int             `json:"longestStreak"`      // Longest run of active days within the year
+	BusiestWeek        *BusiestWeek    `json:"busiestWeek,omitempty"`
+	TopTags            []TagCount      `json:"topTags"` // Most used entry tags, most first
+	Moods              []MoodCount     `json:"moods"`   // Entries by mood, most first
+	Final              bool            `json:"final"`
+	ComputedAt         time.Time       `json:"computedAt"`
+}
+
+// LanguageCount is how many snippets were written in a language
+type LanguageCount struct {
+	Language string `json:"language"`
+	Count    int    `json:"count"`
+}
+
+// TagCount is how many entries carry a tag
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// MoodCount is how many entries were written in a mood
+type MoodCount struct {
+	Mood  string `json:"mood"`
+	Count int    `json:"count"`
+}
+
+// BusiestWeek is the week (starting Monday) with the most journal entries
+type BusiestWeek struct {
+	WeekStart time.Time `json:"weekStart"`
+	Entries   int       `json:"entries"`
+	Words     int       `json:"words"`
+}
+
+// YearlyReviewKey identifies a cached yearly review
+type YearlyReviewKey struct {
+	UserID      uuid.UUID
+	WorkspaceID uuid.UUID
+	Year        int
+}
+
+// YearBounds returns the start of a year and of the next one, in UTC
+func YearBounds(year int) (time.Time, time.Time) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(1, 0, 0)
+}
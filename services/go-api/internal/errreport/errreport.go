@@ -0,0 +1,79 @@
+// Package errreport sends panics and unexpected errors to an error tracker, such as Sentry, with
+// the user and request they happened in. Logs stay the primary record; reporters add alerting.
+package errreport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Event levels
+const (
+	LevelFatal = "fatal" // a recovered panic
+	LevelError = "error"
+)
+
+// Event is a panic or error with what is known about where it happened
+type Event struct {
+	Level     string
+	Message   string
+	Stack     string // goroutine stack, for panics
+	RequestID string // X-Request-ID, or the WebSocket connection ID
+	UserID    string
+	Method    string
+	Path      string
+	Tags      map[string]string // e.g. component, room
+	Time      time.Time
+}
+
+// Reporter sends events to an error tracker. Report is called on request and WebSocket
+// goroutines, so it must not block.
+type Reporter interface {
+	Report(ev *Event)
+}
+
+// Panic builds an event for a recovered panic value and the stack it was recovered on
+func Panic(v interface{}, stack []byte) *Event {
+	return &Event{Level: LevelFatal, Message: fmt.Sprintf("panic: %v", v), Stack: string(stack), Time: time.Now().UTC()}
+}
+
+// Error builds an event for an unexpected error
+func Error(message string, err error) *Event {
+	return &Event{Level: LevelError, Message: fmt.Sprintf("%s: %v", message, err), Time: time.Now().UTC()}
+}
+
+// scope holds the user a request is for. Authentication happens per route, inside the
+// middleware that recovers panics, so it is filled in from further down the chain.
+type scope struct {
+	mu     sync.Mutex
+	userID string
+}
+
+type scopeKey struct{}
+
+// WithScope returns a context that SetUser can record the request's user in
+func WithScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeKey{}, &scope{})
+}
+
+// SetUser records the user a request is for, if its context was made by WithScope
+func SetUser(ctx context.Context, userID string) {
+	if s, ok := ctx.Value(scopeKey{}).(*scope); ok {
+		s.mu.Lock()
+		s.userID = userID
+		s.mu.Unlock()
+	}
+}
+
+// User returns the user recorded by SetUser, or ""
+func User(ctx context.Context) string {
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.userID
+}
@@ -0,0 +1,80 @@
+package errreport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewSentry(t *testing.T) {
+	for dsn, want := range map[string]string{
+		"https://abc@o1.ingest.sentry.io/42":         "https://o1.ingest.sentry.io/api/42/envelope/",
+		"http://abc@glitchtip.local:8000/sub/path/7": "http://glitchtip.local:8000/sub/path/api/7/envelope/",
+	} {
+		s, err := NewSentry(dsn, "")
+		if err != nil || s.endpoint != want {
+			t.Errorf("NewSentry(%q) endpoint = %v, %v; want %s", dsn, s, err, want)
+		}
+	}
+	for _, dsn := range []string{"", "https://o1.ingest.sentry.io/42", "https://abc@o1.ingest.sentry.io/", "ftp://abc@host/1"} {
+		if _, err := NewSentry(dsn, ""); err == nil {
+			t.Errorf("NewSentry(%q) succeeded; want an error", dsn)
+		}
+	}
+}
+
+func TestSentrySend(t *testing.T) {
+	var auth string
+	var lines []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("X-Sentry-Auth")
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var line map[string]interface{}
+			json.Unmarshal(scanner.Bytes(), &line)
+			lines = append(lines, line)
+		}
+	}))
+	defer server.Close()
+
+	s, err := NewSentry(strings.Replace(server.URL, "://", "://key@", 1)+"/1", "production")
+	if err != nil {
+		t.Fatalf("NewSentry: %v", err)
+	}
+	ev := Error("WebSocket write failed", errors.New("broken pipe"))
+	ev.RequestID, ev.UserID = "req-1", "user-1"
+	ev.Tags = map[string]string{"component": "websocket"}
+	if err := s.send(context.Background(), ev); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	if !strings.Contains(auth, "sentry_key=key") {
+		t.Errorf("X-Sentry-Auth = %q", auth)
+	}
+	if len(lines) != 3 || lines[1]["type"] != "event" {
+		t.Fatalf("envelope = %v; want a header, an item header, and an event", lines)
+	}
+	event := lines[2]
+	tags, _ := event["tags"].(map[string]interface{})
+	user, _ := event["user"].(map[string]interface{})
+	if event["level"] != LevelError || event["environment"] != "production" || tags["request_id"] != "req-1" ||
+		tags["component"] != "websocket" || user["id"] != "user-1" {
+		t.Errorf("event = %v", event)
+	}
+}
+
+func TestScope(t *testing.T) {
+	SetUser(context.Background(), "ignored")
+
+	ctx := WithScope(context.Background())
+	// Set further down the middleware chain, on a derived context
+	SetUser(context.WithValue(ctx, struct{}{}, 1), "user-1")
+	if got := User(ctx); got != "user-1" {
+		t.Errorf("User = %q; want user-1", got)
+	}
+}
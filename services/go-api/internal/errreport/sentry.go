@@ -0,0 +1,162 @@
+package errreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// sentryQueueSize bounds events waiting to be sent; beyond it events are dropped (they are
+// logged regardless) rather than slowing requests down
+const sentryQueueSize = 256
+
+// Sentry sends events to Sentry, or a compatible tracker such as GlitchTip, through its
+// envelope endpoint
+type Sentry struct {
+	dsn         string
+	endpoint    string
+	auth        string
+	environment string
+	serverName  string
+	client      *http.Client
+	queue       chan *Event
+}
+
+// NewSentry creates a reporter from a project DSN such as https://key@o1.ingest.sentry.io/42.
+// Events are sent by Run.
+func NewSentry(dsn, environment string) (*Sentry, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User == nil || u.User.Username() == "" {
+		return nil, errors.New("Sentry DSN must look like https://key@host/project")
+	}
+	path, project := "", strings.TrimPrefix(u.Path, "/")
+	if i := strings.LastIndex(project, "/"); i >= 0 {
+		path, project = "/"+project[:i], project[i+1:]
+	}
+	if project == "" {
+		return nil, errors.New("Sentry DSN has no project ID")
+	}
+	hostname, _ := os.Hostname()
+
+	return &Sentry{
+		dsn:         dsn,
+		endpoint:    fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, path, project),
+		auth:        fmt.Sprintf("Sentry sentry_version=7, sentry_client=devjournal/1.0, sentry_key=%s", u.User.Username()),
+		environment: environment,
+		serverName:  hostname,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		queue:       make(chan *Event, sentryQueueSize),
+	}, nil
+}
+
+// Report queues an event for Run to send, dropping it if the queue is full
+func (s *Sentry) Report(ev *Event) {
+	select {
+	case s.queue <- ev:
+	default:
+		log.Printf("WARN: Sentry queue full, dropping event: %s", ev.Message)
+	}
+}
+
+// Run sends queued events until ctx is cancelled
+func (s *Sentry) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-s.queue:
+			if err := s.send(ctx, ev); err != nil {
+				log.Printf("WARN: Failed to send event to Sentry: %v", err)
+			}
+		}
+	}
+}
+
+// send posts one event as an envelope
+func (s *Sentry) send(ctx context.Context, ev *Event) error {
+	body, err := s.envelope(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", s.auth)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sentry returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// envelope encodes an event in Sentry's envelope format: a header line, an item header line,
+// and the event itself
+func (s *Sentry) envelope(ev *Event) ([]byte, error) {
+	id := make([]byte, 16)
+	rand.Read(id)
+	eventID := hex.EncodeToString(id)
+
+	event := map[string]interface{}{
+		"event_id":    eventID,
+		"timestamp":   ev.Time.UTC().Format(time.RFC3339Nano),
+		"platform":    "go",
+		"level":       ev.Level,
+		"logger":      "devjournal",
+		"server_name": s.serverName,
+		"message":     map[string]string{"formatted": ev.Message},
+	}
+	if s.environment != "" {
+		event["environment"] = s.environment
+	}
+	tags := map[string]string{}
+	for k, v := range ev.Tags {
+		tags[k] = v
+	}
+	if ev.RequestID != "" {
+		tags["request_id"] = ev.RequestID
+	}
+	if len(tags) > 0 {
+		event["tags"] = tags
+	}
+	if ev.UserID != "" {
+		event["user"] = map[string]string{"id": ev.UserID}
+	}
+	if ev.Method != "" {
+		event["request"] = map[string]string{"method": ev.Method, "url": ev.Path}
+	}
+	if ev.Stack != "" {
+		event["extra"] = map[string]string{"stack": ev.Stack}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, line := range []interface{}{
+		map[string]string{"event_id": eventID, "dsn": s.dsn, "sent_at": time.Now().UTC().Format(time.RFC3339)},
+		map[string]string{"type": "event"},
+		event,
+	} {
+		if err := enc.Encode(line); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
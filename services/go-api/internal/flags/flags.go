@@ -0,0 +1,186 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Known feature flags
+const (
+	PublicProfiles   = "public_profiles"
+	PublicSnippets   = "public_snippets"
+	AIFeatures       = "ai_features"
+	RegistrationOpen = "registration_open"
+	InviteOnly       = "invite_only" // registration needs an invite code from an admin
+)
+
+// defaults apply to flags missing from the flags file and environment
+var defaults = map[string]bool{
+	PublicProfiles:   true,
+	PublicSnippets:   true,
+	AIFeatures:       false,
+	RegistrationOpen: true,
+	InviteOnly:       false,
+}
+
+// Set is a reloadable collection of feature flags backed by an optional JSON file of
+// {"flag_name": true}. FLAG_<NAME> environment variables override the file.
+type Set struct {
+	path string
+
+	mu      sync.RWMutex
+	values  map[string]bool
+	modTime time.Time
+}
+
+// Load reads flags from path. An empty path uses defaults and environment overrides only.
+func Load(path string) (*Set, error) {
+	s := &Set{path: path}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload re-reads the flags file and environment, keeping the previous values on error
+func (s *Set) Reload() error {
+	values := make(map[string]bool, len(defaults))
+	for name, enabled := range defaults {
+		values[name] = enabled
+	}
+
+	var modTime time.Time
+	if s.path != "" {
+		info, err := os.Stat(s.path)
+		if err != nil {
+			return fmt.Errorf("failed to stat flags file: %w", err)
+		}
+		data, err := os.ReadFile(s.path)
+		if err != nil {
+			return fmt.Errorf("failed to read flags file: %w", err)
+		}
+		var fileValues map[string]bool
+		if err := json.Unmarshal(data, &fileValues); err != nil {
+			return fmt.Errorf("failed to parse flags file: %w", err)
+		}
+		for name, enabled := range fileValues {
+			values[name] = enabled
+		}
+		modTime = info.ModTime()
+	}
+
+	for name := range values {
+		if v, ok := os.LookupEnv("FLAG_" + strings.ToUpper(name)); ok {
+			if enabled, err := strconv.ParseBool(v); err == nil {
+				values[name] = enabled
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.values = values
+	s.modTime = modTime
+	s.mu.Unlock()
+	return nil
+}
+
+// Enabled reports whether the named flag is on. Unknown flags are off.
+func (s *Set) Enabled(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[name]
+}
+
+// All returns a copy of every flag value
+func (s *Set) All() map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	all := make(map[string]bool, len(s.values))
+	for name, enabled := range s.values {
+		all[name] = enabled
+	}
+	return all
+}
+
+// Poller returns a background job that reloads the flags file whenever it changes
+func (s *Set) Poller() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if s.path == "" {
+			return nil
+		}
+		info, err := os.Stat(s.path)
+		if err != nil {
+			return fmt.Errorf("failed to stat flags file: %w", err)
+		}
+		s.mu.RLock()
+		unchanged := info.ModTime().Equal(s.modTime)
+		s.mu.RUnlock()
+		if unchanged {
+			return nil
+		}
+		if err := s.Reload(); err != nil {
+			return err
+		}
+		log.Printf("Reloaded feature flags from %s", s.path)
+		return nil
+	}
+}
+
+// ReloadOnSIGHUP reloads the flags every time the process receives SIGHUP, until ctx is cancelled
+func (s *Set) ReloadOnSIGHUP(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := s.Reload(); err != nil {
+				log.Printf("ERROR: Failed to reload feature flags: %v", err)
+				continue
+			}
+			log.Println("Reloaded feature flags on SIGHUP")
+		}
+	}
+}
+
+// current is the process-wide flag set consulted by Enabled
+var current atomic.Pointer[Set]
+
+// Use installs s as the process-wide flag set
+func Use(s *Set) {
+	current.Store(s)
+}
+
+// All returns every flag value in the process-wide flag set
+func All() map[string]bool {
+	if s := current.Load(); s != nil {
+		return s.All()
+	}
+	all := make(map[string]bool, len(defaults))
+	for name, enabled := range defaults {
+		all[name] = enabled
+	}
+	return all
+}
+
+// Enabled reports whether the named flag is on in the process-wide flag set.
+// Before Use is called, flags report their built-in defaults.
+func Enabled(ctx context.Context, name string) bool {
+	if s := current.Load(); s != nil {
+		return s.Enabled(name)
+	}
+	return defaults[name]
+}
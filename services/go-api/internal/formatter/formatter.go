@@ -0,0 +1,120 @@
+// Package formatter formats snippet code: Go and JSON with the standard library, and web
+// languages with a Prettier sidecar
+package formatter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxResponseBytes bounds sidecar responses read into memory
+const maxResponseBytes = 4 << 20
+
+// ErrSyntax is wrapped by errors for code that does not parse in its language
+var ErrSyntax = errors.New("code has a syntax error")
+
+// prettierParsers maps snippet languages to the Prettier parser for them
+var prettierParsers = map[string]string{
+	"javascript": "babel",
+	"typescript": "typescript",
+	"css":        "css",
+	"scss":       "scss",
+	"html":       "html",
+	"vue":        "vue",
+	"markdown":   "markdown",
+	"yaml":       "yaml",
+}
+
+// Formatter formats code
+type Formatter struct {
+	prettierURL string
+	client      *http.Client
+}
+
+// New creates a formatter. Without a Prettier sidecar URL, only Go and JSON are formatted.
+func New(prettierURL string) *Formatter {
+	return &Formatter{
+		prettierURL: strings.TrimSuffix(prettierURL, "/"),
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Supports reports whether code in a snippet language can be formatted
+func (f *Formatter) Supports(language string) bool {
+	if language == "go" || language == "json" {
+		return true
+	}
+	_, ok := prettierParsers[language]
+	return ok && f.prettierURL != ""
+}
+
+// Format returns code formatted for its language. Code in a language without a formatter is
+// returned as is.
+func (f *Formatter) Format(ctx context.Context, language, code string) (string, error) {
+	switch language {
+	case "go":
+		formatted, err := format.Source([]byte(code))
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		return string(formatted), nil
+	case "json":
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(strings.TrimSpace(code)), "", "  "); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		buf.WriteByte('\n')
+		return buf.String(), nil
+	}
+	if !f.Supports(language) {
+		return code, nil
+	}
+	return f.prettier(ctx, prettierParsers[language], code)
+}
+
+// prettier formats code with the sidecar, which takes {"code", "parser"} and replies with
+// {"code"}, or 400 and {"error"} when the code does not parse
+func (f *Formatter) prettier(ctx context.Context, parser, code string) (string, error) {
+	payload, err := json.Marshal(map[string]string{"code": code, "parser": parser})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.prettierURL+"/format", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("prettier: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return "", fmt.Errorf("prettier: %w", err)
+	}
+
+	var result struct {
+		Code  *string `json:"code"`
+		Error string  `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("prettier: unexpected response with status %d: %q", resp.StatusCode, body)
+	}
+	if resp.StatusCode == http.StatusBadRequest {
+		return "", fmt.Errorf("%w: %s", ErrSyntax, result.Error)
+	}
+	if resp.StatusCode != http.StatusOK || result.Code == nil {
+		return "", fmt.Errorf("prettier: formatting failed with status %d: %s", resp.StatusCode, result.Error)
+	}
+	return *result.Code, nil
+}
@@ -0,0 +1,62 @@
+package formatter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Code, Parser string }
+		json.NewDecoder(r.Body).Decode(&req)
+		switch {
+		case r.URL.Path != "/format":
+			w.WriteHeader(http.StatusNotFound)
+		case req.Code == "const x =":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "Unexpected token (1:10)"}`))
+		case req.Parser == "typescript":
+			w.Write([]byte(`{"code": "const x = 1;\n"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error": "unexpected parser ` + req.Parser + `"}`))
+		}
+	}))
+	defer server.Close()
+
+	f := New(server.URL + "/")
+	tests := []struct {
+		language string
+		code     string
+		want     string
+	}{
+		{"go", "func sum(a,b int) int {return a+b}", "func sum(a, b int) int { return a + b }"},
+		{"json", `{"a":[1,2]}`, "{\n  \"a\": [\n    1,\n    2\n  ]\n}\n"},
+		{"typescript", "const x=1", "const x = 1;\n"},
+		{"python", "x=1", "x=1"},
+	}
+	for _, tt := range tests {
+		got, err := f.Format(context.Background(), tt.language, tt.code)
+		if err != nil || got != tt.want {
+			t.Errorf("Format(%s) = %q, %v; want %q", tt.language, got, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct{ language, code string }{{"go", "func sum("}, {"json", "{"}, {"typescript", "const x ="}} {
+		if _, err := f.Format(context.Background(), tt.language, tt.code); !errors.Is(err, ErrSyntax) {
+			t.Errorf("Format(%s, %q) = %v, want ErrSyntax", tt.language, tt.code, err)
+		}
+	}
+	if _, err := f.Format(context.Background(), "css", "a{}"); err == nil || errors.Is(err, ErrSyntax) {
+		t.Errorf("Format with a failing sidecar = %v, want a non-syntax error", err)
+	}
+
+	// Without a sidecar, web languages are left as they are
+	if got, err := New("").Format(context.Background(), "typescript", "const x=1"); err != nil || got != "const x=1" {
+		t.Errorf("Format without a sidecar = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,322 @@
+// Package gitactivity reads GitHub and GitLab webhooks into commits and pull requests, and
+// summarizes a day of them as a journal entry draft
+package gitactivity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/pkg/apperr"
+)
+
+// ErrInvalidSignature is returned when a webhook's signature or token does not match the secret
+var ErrInvalidSignature = apperr.New(apperr.ErrUnauthorized, "invalid webhook signature")
+
+// maxTitleLength caps recorded commit and pull request titles, in characters
+const maxTitleLength = 500
+
+// VerifyGitHub checks the X-Hub-Signature-256 header ("sha256=<hex hmac>") against
+// HMAC-SHA256(body) keyed with the webhook secret
+func VerifyGitHub(secret string, body []byte, signature string) error {
+	sig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok || secret == "" {
+		return ErrInvalidSignature
+	}
+	decoded, err := hex.DecodeString(sig)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(decoded, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyGitLab checks the X-Gitlab-Token header, which GitLab sets to the webhook secret as is
+func VerifyGitLab(secret, token string) error {
+	if secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(token)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// gitUser is how both providers describe a commit author
+type gitUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+func (u gitUser) identities() []string {
+	return nonEmpty(u.Username, u.Name, u.Email)
+}
+
+// ParseGitHub reads a GitHub webhook by its X-GitHub-Event header. Events other than pushes and
+// pull requests, such as the ping sent when a webhook is added, yield nothing.
+func ParseGitHub(event string, body []byte) ([]domain.GitActivity, error) {
+	switch event {
+	case "push":
+		var push struct {
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+			Commits []struct {
+				ID        string   `json:"id"`
+				Message   string   `json:"message"`
+				Timestamp string   `json:"timestamp"`
+				URL       string   `json:"url"`
+				Author    gitUser  `json:"author"`
+				Added     []string `json:"added"`
+				Removed   []string `json:"removed"`
+				Modified  []string `json:"modified"`
+			} `json:"commits"`
+		}
+		if err := json.Unmarshal(body, &push); err != nil {
+			return nil, fmt.Errorf("invalid push event: %w", err)
+		}
+		activities := make([]domain.GitActivity, 0, len(push.Commits))
+		for _, c := range push.Commits {
+			activities = append(activities, commit(domain.GitProviderGitHub, push.Repository.FullName, c.ID, c.Message, c.URL,
+				c.Timestamp, len(c.Added)+len(c.Removed)+len(c.Modified), c.Author.identities()))
+		}
+		return activities, nil
+
+	case "pull_request":
+		var event struct {
+			Action      string `json:"action"`
+			PullRequest struct {
+				Number    int    `json:"number"`
+				Title     string `json:"title"`
+				HTMLURL   string `json:"html_url"`
+				Merged    bool   `json:"merged"`
+				Additions int    `json:"additions"`
+				Deletions int    `json:"deletions"`
+				Changed   int    `json:"changed_files"`
+				UpdatedAt string `json:"updated_at"`
+				User      struct {
+					Login string `json:"login"`
+				} `json:"user"`
+			} `json:"pull_request"`
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("invalid pull_request event: %w", err)
+		}
+		pr := event.PullRequest
+		action := event.Action
+		switch {
+		case action == "closed" && pr.Merged:
+			action = domain.GitPullRequestMerged
+		case action != domain.GitPullRequestOpened && action != domain.GitPullRequestClosed && action != domain.GitPullRequestReopened:
+			return nil, nil
+		}
+		return []domain.GitActivity{pullRequest(domain.GitProviderGitHub, event.Repository.FullName, pr.Number, action,
+			pr.Title, pr.HTMLURL, pr.UpdatedAt, pr.Additions, pr.Deletions, pr.Changed, nonEmpty(pr.User.Login))}, nil
+	}
+	return nil, nil
+}
+
+// gitlabActions maps merge request actions to pull request actions
+var gitlabActions = map[string]string{
+	"open":   domain.GitPullRequestOpened,
+	"merge":  domain.GitPullRequestMerged,
+	"close":  domain.GitPullRequestClosed,
+	"reopen": domain.GitPullRequestReopened,
+}
+
+// ParseGitLab reads a GitLab webhook by its X-Gitlab-Event header. Events other than pushes and
+// merge requests yield nothing.
+func ParseGitLab(event string, body []byte) ([]domain.GitActivity, error) {
+	switch event {
+	case "Push Hook":
+		var push struct {
+			Project struct {
+				PathWithNamespace string `json:"path_with_namespace"`
+			} `json:"project"`
+			Commits []struct {
+				ID        string   `json:"id"`
+				Message   string   `json:"message"`
+				Timestamp string   `json:"timestamp"`
+				URL       string   `json:"url"`
+				Author    gitUser  `json:"author"`
+				Added     []string `json:"added"`
+				Removed   []string `json:"removed"`
+				Modified  []string `json:"modified"`
+			} `json:"commits"`
+		}
+		if err := json.Unmarshal(body, &push); err != nil {
+			return nil, fmt.Errorf("invalid push hook: %w", err)
+		}
+		activities := make([]domain.GitActivity, 0, len(push.Commits))
+		for _, c := range push.Commits {
+			activities = append(activities, commit(domain.GitProviderGitLab, push.Project.PathWithNamespace, c.ID, c.Message, c.URL,
+				c.Timestamp, len(c.Added)+len(c.Removed)+len(c.Modified), c.Author.identities()))
+		}
+		return activities, nil
+
+	case "Merge Request Hook":
+		var event struct {
+			User    gitUser `json:"user"`
+			Project struct {
+				PathWithNamespace string `json:"path_with_namespace"`
+			} `json:"project"`
+			Attributes struct {
+				IID       int    `json:"iid"`
+				Title     string `json:"title"`
+				URL       string `json:"url"`
+				Action    string `json:"action"`
+				UpdatedAt string `json:"updated_at"`
+			} `json:"object_attributes"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("invalid merge request hook: %w", err)
+		}
+		mr := event.Attributes
+		action, ok := gitlabActions[mr.Action]
+		if !ok {
+			return nil, nil
+		}
+		// GitLab reports who acted on the merge request, not its author
+		return []domain.GitActivity{pullRequest(domain.GitProviderGitLab, event.Project.PathWithNamespace, mr.IID, action,
+			mr.Title, mr.URL, mr.UpdatedAt, 0, 0, 0, event.User.identities())}, nil
+	}
+	return nil, nil
+}
+
+func commit(provider, repo, sha, message, url, timestamp string, files int, authors []string) domain.GitActivity {
+	title, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	ref := sha
+	if len(ref) > 7 {
+		ref = ref[:7]
+	}
+	return domain.GitActivity{
+		Provider:     provider,
+		Kind:         domain.GitActivityCommit,
+		ExternalID:   "commit:" + repo + "@" + sha,
+		Repo:         repo,
+		Ref:          ref,
+		Title:        truncate(strings.TrimSpace(title)),
+		URL:          url,
+		Authors:      authors,
+		FilesChanged: files,
+		OccurredAt:   parseTime(timestamp),
+	}
+}
+
+func pullRequest(provider, repo string, number int, action, title, url, updatedAt string, additions, deletions, files int, authors []string) domain.GitActivity {
+	return domain.GitActivity{
+		Provider:     provider,
+		Kind:         domain.GitActivityPullRequest,
+		ExternalID:   "pr:" + repo + "#" + strconv.Itoa(number) + ":" + action,
+		Repo:         repo,
+		Ref:          "#" + strconv.Itoa(number),
+		Action:       action,
+		Title:        truncate(strings.TrimSpace(title)),
+		URL:          url,
+		Authors:      authors,
+		Additions:    additions,
+		Deletions:    deletions,
+		FilesChanged: files,
+		OccurredAt:   parseTime(updatedAt),
+	}
+}
+
+// parseTime reads provider timestamps, which are RFC 3339 except for GitLab merge requests.
+// Events without a readable time are dated when they arrive.
+func parseTime(value string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Now().UTC()
+}
+
+func truncate(s string) string {
+	if runes := []rune(s); len(runes) > maxTitleLength {
+		return string(runes[:maxTitleLength])
+	}
+	return s
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Matches reports whether an activity passes a webhook's filters
+func Matches(hook *domain.GitHook, a *domain.GitActivity) bool {
+	switch a.Kind {
+	case domain.GitActivityCommit:
+		if !hook.IncludeCommits {
+			return false
+		}
+	case domain.GitActivityPullRequest:
+		if !hook.IncludePullRequests {
+			return false
+		}
+	}
+	if len(hook.Repos) > 0 && !containsFold(hook.Repos, a.Repo) {
+		return false
+	}
+	if len(hook.Authors) == 0 {
+		return true
+	}
+	for _, author := range a.Authors {
+		if containsFold(hook.Authors, author) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is a webhook request as received, before it is verified
+type Delivery struct {
+	Provider  string // github or gitlab, from the event header present
+	Event     string // X-GitHub-Event or X-Gitlab-Event
+	Signature string // X-Hub-Signature-256, for GitHub
+	Token     string // X-Gitlab-Token, for GitLab
+	Body      []byte
+}
+
+// Verify checks a delivery against the webhook secret
+func (d *Delivery) Verify(secret string) error {
+	if d.Provider == domain.GitProviderGitLab {
+		return VerifyGitLab(secret, d.Token)
+	}
+	return VerifyGitHub(secret, d.Body, d.Signature)
+}
+
+// Parse reads a verified delivery's commits and pull requests
+func (d *Delivery) Parse() ([]domain.GitActivity, error) {
+	if d.Provider == domain.GitProviderGitLab {
+		return ParseGitLab(d.Event, d.Body)
+	}
+	return ParseGitHub(d.Event, d.Body)
+}
@@ -0,0 +1,122 @@
+package gitactivity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"devjournal/internal/domain"
+)
+
+const githubPush = `{
+	"ref": "refs/heads/main",
+	"repository": {"full_name": "ada/engine"},
+	"commits": [
+		{"id": "0123456789abcdef", "message": "Fix race in watcher\n\nLonger body", "timestamp": "2024-05-01T09:00:00+02:00",
+		 "url": "https://github.com/ada/engine/commit/0123456", "author": {"name": "Ada", "email": "ada@example.com", "username": "ada"},
+		 "added": ["a.go"], "removed": [], "modified": ["b.go", "c.go"]},
+		{"id": "fedcba9876543210", "message": "Bump deps", "timestamp": "2024-05-01T10:00:00Z",
+		 "author": {"name": "Bot", "email": "bot@example.com"}}
+	]
+}`
+
+func TestParseGitHub(t *testing.T) {
+	activities, err := ParseGitHub("push", []byte(githubPush))
+	if err != nil || len(activities) != 2 {
+		t.Fatalf("ParseGitHub(push) = %d activities, %v; want 2", len(activities), err)
+	}
+	c := activities[0]
+	if c.Kind != domain.GitActivityCommit || c.Repo != "ada/engine" || c.Ref != "0123456" || c.Title != "Fix race in watcher" ||
+		c.FilesChanged != 3 || !c.OccurredAt.Equal(time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("commit = %+v", c)
+	}
+
+	merged, err := ParseGitHub("pull_request", []byte(`{"action": "closed", "repository": {"full_name": "ada/engine"},
+		"pull_request": {"number": 12, "title": "Add cache", "merged": true, "additions": 100, "deletions": 20,
+		"updated_at": "2024-05-01T12:00:00Z", "user": {"login": "ada"}}}`))
+	if err != nil || len(merged) != 1 || merged[0].Action != domain.GitPullRequestMerged || merged[0].Ref != "#12" || merged[0].Additions != 100 {
+		t.Errorf("ParseGitHub(merged pull_request) = %+v, %v", merged, err)
+	}
+
+	for event, body := range map[string]string{
+		"pull_request": `{"action": "synchronize", "pull_request": {"number": 1}}`,
+		"ping":         `{"zen": "Keep it logically awesome."}`,
+	} {
+		if activities, err := ParseGitHub(event, []byte(body)); err != nil || len(activities) != 0 {
+			t.Errorf("ParseGitHub(%s) = %+v, %v; want nothing", event, activities, err)
+		}
+	}
+}
+
+func TestParseGitLab(t *testing.T) {
+	mr, err := ParseGitLab("Merge Request Hook", []byte(`{"user": {"username": "ada"}, "project": {"path_with_namespace": "ada/engine"},
+		"object_attributes": {"iid": 7, "title": "Add cache", "action": "merge", "updated_at": "2024-05-01 12:00:00 UTC"}}`))
+	if err != nil || len(mr) != 1 || mr[0].Action != domain.GitPullRequestMerged || mr[0].Authors[0] != "ada" ||
+		!mr[0].OccurredAt.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseGitLab(merge) = %+v, %v", mr, err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(githubPush)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if err := VerifyGitHub("s3cret", body, signature); err != nil {
+		t.Errorf("VerifyGitHub(valid) = %v", err)
+	}
+	if err := VerifyGitHub("other", body, signature); err != ErrInvalidSignature {
+		t.Errorf("VerifyGitHub(wrong secret) = %v", err)
+	}
+	if err := VerifyGitLab("s3cret", "s3cret"); err != nil {
+		t.Errorf("VerifyGitLab(valid) = %v", err)
+	}
+	if err := VerifyGitLab("", ""); err != ErrInvalidSignature {
+		t.Errorf("VerifyGitLab(no secret) = %v", err)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	activities, _ := ParseGitHub("push", []byte(githubPush))
+	hook := &domain.GitHook{IncludeCommits: true, Repos: []string{"Ada/Engine"}, Authors: []string{"ADA@example.com"}}
+	if !Matches(hook, &activities[0]) || Matches(hook, &activities[1]) {
+		t.Error("author filter should keep Ada's commit and drop the bot's")
+	}
+	if Matches(&domain.GitHook{IncludePullRequests: true}, &activities[0]) {
+		t.Error("commits recorded with includeCommits off")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	activities, _ := ParseGitHub("push", []byte(githubPush))
+	pr, _ := ParseGitHub("pull_request", []byte(`{"action": "opened", "repository": {"full_name": "ada/engine"},
+		"pull_request": {"number": 12, "title": "Add [cache]", "html_url": "https://github.com/ada/engine/pull/12",
+		"additions": 100, "deletions": 20, "changed_files": 4, "updated_at": "2024-05-01T12:00:00Z"}}`))
+	activities = append(activities, pr...)
+
+	title, content, tags := Summarize(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), activities)
+	if title != "Git activity: May 1, 2024" {
+		t.Errorf("title = %q", title)
+	}
+	for _, want := range []string{
+		"2 commits and 1 pull request across 1 repository, touching 7 files (+100 −20 lines in pull requests).",
+		"### ada/engine",
+		`- Opened [#12 Add \[cache\]](https://github.com/ada/engine/pull/12) (+100 −20)`,
+		"- [`0123456`](https://github.com/ada/engine/commit/0123456) Fix race in watcher",
+		"- `fedcba9` Bump deps",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content lacks %q:\n%s", want, content)
+		}
+	}
+	if strings.Index(content, "#12") > strings.Index(content, "0123456") {
+		t.Error("pull requests should be listed before commits")
+	}
+	if len(tags) != 2 || tags[0] != domain.GitActivityTag || tags[1] != "engine" {
+		t.Errorf("tags = %v", tags)
+	}
+}
@@ -0,0 +1,112 @@
+package gitactivity
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+)
+
+// pullRequestVerbs describe pull request actions in a summary
+var pullRequestVerbs = map[string]string{
+	domain.GitPullRequestOpened:   "Opened",
+	domain.GitPullRequestMerged:   "Merged",
+	domain.GitPullRequestClosed:   "Closed",
+	domain.GitPullRequestReopened: "Reopened",
+}
+
+// Summarize drafts a journal entry from a day's activity: a line of totals, then each repository's
+// pull requests and commits in Markdown. Tags are the git-activity tag and the repository names.
+func Summarize(day time.Time, activities []domain.GitActivity) (title, content string, tags []string) {
+	byRepo := make(map[string][]domain.GitActivity)
+	var commits, pullRequests, files, additions, deletions int
+	for _, a := range activities {
+		byRepo[a.Repo] = append(byRepo[a.Repo], a)
+		files += a.FilesChanged
+		additions += a.Additions
+		deletions += a.Deletions
+		if a.Kind == domain.GitActivityCommit {
+			commits++
+		} else {
+			pullRequests++
+		}
+	}
+	repos := make([]string, 0, len(byRepo))
+	for repo := range byRepo {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	var b strings.Builder
+	var counts []string
+	if commits > 0 {
+		counts = append(counts, count(commits, "commit"))
+	}
+	if pullRequests > 0 {
+		counts = append(counts, count(pullRequests, "pull request"))
+	}
+	fmt.Fprintf(&b, "%s across %s", strings.Join(counts, " and "), count(len(repos), "repository"))
+	if files > 0 {
+		fmt.Fprintf(&b, ", touching %s", count(files, "file"))
+	}
+	if additions > 0 || deletions > 0 {
+		fmt.Fprintf(&b, " (+%d −%d lines in pull requests)", additions, deletions)
+	}
+	b.WriteString(".\n")
+
+	tags = []string{domain.GitActivityTag}
+	for _, repo := range repos {
+		if name := strings.ToLower(path.Base(repo)); name != "" && name != "." && name != "/" && !containsFold(tags, name) {
+			tags = append(tags, name)
+		}
+
+		items := byRepo[repo]
+		// Pull requests first, then commits, each in the order they happened
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].Kind != items[j].Kind {
+				return items[i].Kind == domain.GitActivityPullRequest
+			}
+			return items[i].OccurredAt.Before(items[j].OccurredAt)
+		})
+
+		fmt.Fprintf(&b, "\n### %s\n\n", repo)
+		for _, a := range items {
+			b.WriteString("- ")
+			if a.Kind == domain.GitActivityPullRequest {
+				fmt.Fprintf(&b, "%s %s", pullRequestVerbs[a.Action], link(a.Ref+" "+a.Title, a.URL))
+				if a.Additions > 0 || a.Deletions > 0 {
+					fmt.Fprintf(&b, " (+%d −%d)", a.Additions, a.Deletions)
+				}
+			} else {
+				fmt.Fprintf(&b, "%s %s", link("`"+a.Ref+"`", a.URL), a.Title)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	title = "Git activity: " + day.Format("January 2, 2006")
+	return title, b.String(), tags
+}
+
+// linkText escapes brackets, which would end a Markdown link's text early
+var linkText = strings.NewReplacer("[", `\[`, "]", `\]`)
+
+func link(text, url string) string {
+	if url == "" {
+		return text
+	}
+	return "[" + linkText.Replace(text) + "](" + url + ")"
+}
+
+func count(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	if strings.HasSuffix(noun, "y") {
+		return fmt.Sprintf("%d %sies", n, strings.TrimSuffix(noun, "y"))
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -2,11 +2,16 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"connectrpc.com/connect"
+	"github.com/google/uuid"
 
+	"devjournal/internal/middleware"
 	"devjournal/internal/service"
+	"devjournal/internal/tenant"
+	"devjournal/internal/vault"
 )
 
 // AuthInterceptor creates a Connect interceptor for authentication
@@ -28,13 +33,29 @@ func AuthInterceptor(authService *service.AuthService) connect.UnaryInterceptorF
 			token := parts[1]
 
 			// Validate token
-			claims, err := authService.ValidateToken(token)
+			claims, err := authService.ValidateSession(ctx, token)
 			if err != nil {
 				return nil, connect.NewError(connect.CodeUnauthenticated, err)
 			}
+			// Impersonation is audited per REST request, whose methods tell reads from writes
+			if claims.Impersonation != nil {
+				return nil, connect.NewError(connect.CodePermissionDenied, errors.New("impersonation tokens only work with the REST API"))
+			}
 
 			// Add user ID to context
 			ctx = WithUserID(ctx, claims.UserID)
+			if claims.WorkspaceID != uuid.Nil {
+				ctx = tenant.WithWorkspace(ctx, claims.WorkspaceID)
+			}
+
+			// A vault session token unlocks vault entries, as with middleware.VaultSession
+			if vaultToken := req.Header().Get(middleware.VaultTokenHeader); vaultToken != "" {
+				expiresAt, err := authService.ValidateVaultToken(vaultToken, claims.UserID)
+				if err != nil {
+					return nil, connect.NewError(connect.CodeUnauthenticated, err)
+				}
+				ctx = vault.WithSession(ctx, expiresAt)
+			}
 
 			return next(ctx, req)
 		}
@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"devjournal/internal/requestid"
+	"devjournal/pkg/apperr"
+)
+
+// toConnectError maps a service error to its Connect code, mirroring httputil.WriteError.
+// The error code is sent in the Error-Code metadata, the request ID in X-Request-ID, and any
+// details as a google.protobuf.Struct. Errors without a kind are logged and reported as a
+// generic internal error.
+func toConnectError(ctx context.Context, err error) *connect.Error {
+	id := requestid.FromContext(ctx)
+	code := connectCode(err)
+	if code == connect.CodeInternal {
+		log.Printf("ERROR: Connect handler failed: %v (request %s)", err, id)
+		connectErr := connect.NewError(code, errors.New("internal error"))
+		connectErr.Meta().Set("Error-Code", apperr.CodeInternal)
+		connectErr.Meta().Set(requestid.Header, id)
+		return connectErr
+	}
+
+	connectErr := connect.NewError(code, err)
+	connectErr.Meta().Set("Error-Code", apperr.Code(err))
+	connectErr.Meta().Set(requestid.Header, id)
+	if details := apperr.Details(err); details != nil {
+		if detail, ok := structDetail(details); ok {
+			connectErr.AddDetail(detail)
+		}
+	}
+	return connectErr
+}
+
+// connectCode returns the Connect code for an error's kind
+func connectCode(err error) connect.Code {
+	switch {
+	case errors.Is(err, apperr.ErrValidation):
+		return connect.CodeInvalidArgument
+	case errors.Is(err, apperr.ErrUnauthorized):
+		return connect.CodeUnauthenticated
+	case errors.Is(err, apperr.ErrPaymentRequired):
+		return connect.CodeResourceExhausted
+	case errors.Is(err, apperr.ErrForbidden):
+		return connect.CodePermissionDenied
+	case errors.Is(err, apperr.ErrNotFound):
+		return connect.CodeNotFound
+	case errors.Is(err, apperr.ErrConflict):
+		return connect.CodeAlreadyExists
+	case errors.Is(err, apperr.ErrPrecondition):
+		return connect.CodeFailedPrecondition
+	case errors.Is(err, apperr.ErrUnavailable):
+		return connect.CodeUnavailable
+	default:
+		return connect.CodeInternal
+	}
+}
+
+// structDetail converts error details to a Connect error detail via their JSON form
+func structDetail(details map[string]interface{}) (*connect.ErrorDetail, bool) {
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return nil, false
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, false
+	}
+	s, err := structpb.NewStruct(generic)
+	if err != nil {
+		return nil, false
+	}
+	detail, err := connect.NewErrorDetail(s)
+	if err != nil {
+		return nil, false
+	}
+	return detail, true
+}
@@ -16,12 +16,16 @@ import (
 // JournalConnectHandler implements the Connect RPC JournalService
 type JournalConnectHandler struct {
 	devjournalv1connect.UnimplementedJournalServiceHandler
-	journalService *service.JournalService
+	journalService  *service.JournalService
+	settingsService *service.SettingsService
 }
 
 // NewJournalConnectHandler creates a new Connect RPC journal handler
-func NewJournalConnectHandler(journalService *service.JournalService) *JournalConnectHandler {
-	return &JournalConnectHandler{journalService: journalService}
+func NewJournalConnectHandler(journalService *service.JournalService, settingsService *service.SettingsService) *JournalConnectHandler {
+	return &JournalConnectHandler{
+		journalService:  journalService,
+		settingsService: settingsService,
+	}
 }
 
 // CreateEntry creates a new journal entry
@@ -37,15 +41,17 @@ func (h *JournalConnectHandler) CreateEntry(
 
 	// Create the entry
 	domainReq := &domain.CreateJournalEntryRequest{
-		Title:   req.Msg.Title,
-		Content: req.Msg.Content,
-		Mood:    req.Msg.Mood,
-		Tags:    req.Msg.Tags,
+		Title:         req.Msg.Title,
+		Content:       req.Msg.Content,
+		Mood:          req.Msg.Mood,
+		Tags:          req.Msg.Tags,
+		ContentFormat: req.Msg.ContentFormat,
+		Encryption:    protoToDomainEncryption(req.Msg.Encryption),
 	}
 
 	entry, err := h.journalService.Create(ctx, userID, domainReq)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, toConnectError(ctx, err)
 	}
 
 	return connect.NewResponse(domainToProtoJournalEntry(entry)), nil
@@ -68,10 +74,10 @@ func (h *JournalConnectHandler) GetEntry(
 
 	entry, err := h.journalService.GetByID(ctx, entryID, userID)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, toConnectError(ctx, err)
 	}
 	if entry == nil {
-		return nil, connect.NewError(connect.CodeNotFound, nil)
+		return nil, toConnectError(ctx, service.ErrEntryNotFound)
 	}
 
 	return connect.NewResponse(domainToProtoJournalEntry(entry)), nil
@@ -90,16 +96,23 @@ func (h *JournalConnectHandler) ListEntries(
 	var entries []domain.JournalEntry
 	var total int
 
-	if req.Msg.Mood != "" {
-		entries, err = h.journalService.ListByMood(ctx, userID, req.Msg.Mood, int(req.Msg.Limit), int(req.Msg.Offset))
+	limit := h.settingsService.PageSize(ctx, userID, int(req.Msg.Limit))
+
+	if req.Msg.Mood == domain.FilterNone {
+		entries, total, err = h.journalService.ListWithoutMood(ctx, userID, limit, int(req.Msg.Offset))
+		if err != nil {
+			return nil, toConnectError(ctx, err)
+		}
+	} else if req.Msg.Mood != "" {
+		entries, err = h.journalService.ListByMood(ctx, userID, req.Msg.Mood, limit, int(req.Msg.Offset))
 		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, err)
+			return nil, toConnectError(ctx, err)
 		}
 		total = len(entries) // For mood filter, we don't have exact total
 	} else {
-		entries, total, err = h.journalService.List(ctx, userID, int(req.Msg.Limit), int(req.Msg.Offset))
+		entries, total, err = h.journalService.List(ctx, userID, limit, int(req.Msg.Offset))
 		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, err)
+			return nil, toConnectError(ctx, err)
 		}
 	}
 
@@ -110,8 +123,9 @@ func (h *JournalConnectHandler) ListEntries(
 	}
 
 	return connect.NewResponse(&pb.ListEntriesResponse{
-		Entries:    protoEntries,
-		TotalCount: int32(total),
+		Entries:     protoEntries,
+		TotalCount:  int32(total),
+		MaxPageSize: domain.MaxPageSize,
 	}), nil
 }
 
@@ -131,15 +145,17 @@ func (h *JournalConnectHandler) UpdateEntry(
 	}
 
 	domainReq := &domain.UpdateJournalEntryRequest{
-		Title:   req.Msg.Title,
-		Content: req.Msg.Content,
-		Mood:    req.Msg.Mood,
-		Tags:    req.Msg.Tags,
+		Title:         req.Msg.Title,
+		Content:       req.Msg.Content,
+		Mood:          req.Msg.Mood,
+		Tags:          req.Msg.Tags,
+		ContentFormat: req.Msg.ContentFormat,
+		Encryption:    protoToDomainEncryption(req.Msg.Encryption),
 	}
 
 	entry, err := h.journalService.Update(ctx, entryID, userID, domainReq)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, toConnectError(ctx, err)
 	}
 
 	return connect.NewResponse(domainToProtoJournalEntry(entry)), nil
@@ -162,7 +178,7 @@ func (h *JournalConnectHandler) DeleteEntry(
 
 	err = h.journalService.Delete(ctx, entryID, userID)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, toConnectError(ctx, err)
 	}
 
 	return connect.NewResponse(&pb.DeleteEntryResponse{Success: true}), nil
@@ -178,9 +194,10 @@ func (h *JournalConnectHandler) SearchEntries(
 		return nil, connect.NewError(connect.CodeUnauthenticated, err)
 	}
 
-	entries, err := h.journalService.Search(ctx, userID, req.Msg.Query, int(req.Msg.Limit), int(req.Msg.Offset))
+	limit := h.settingsService.PageSize(ctx, userID, int(req.Msg.Limit))
+	entries, err := h.journalService.Search(ctx, userID, req.Msg.Query, limit, int(req.Msg.Offset))
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, toConnectError(ctx, err)
 	}
 
 	protoEntries := make([]*pb.JournalEntry, len(entries))
@@ -190,21 +207,47 @@ func (h *JournalConnectHandler) SearchEntries(
 	}
 
 	return connect.NewResponse(&pb.ListEntriesResponse{
-		Entries:    protoEntries,
-		TotalCount: int32(len(entries)),
+		Entries:     protoEntries,
+		TotalCount:  int32(len(entries)),
+		MaxPageSize: domain.MaxPageSize,
 	}), nil
 }
 
 // domainToProtoJournalEntry converts a domain JournalEntry to proto
 func domainToProtoJournalEntry(entry *domain.JournalEntry) *pb.JournalEntry {
-	return &pb.JournalEntry{
-		Id:        entry.ID.String(),
-		UserId:    entry.UserID.String(),
-		Title:     entry.Title,
-		Content:   entry.Content,
-		Mood:      entry.Mood,
-		Tags:      entry.Tags,
-		CreatedAt: timestamppb.New(entry.CreatedAt),
-		UpdatedAt: timestamppb.New(entry.UpdatedAt),
+	protoEntry := &pb.JournalEntry{
+		Id:            entry.ID.String(),
+		UserId:        entry.UserID.String(),
+		Title:         entry.Title,
+		Content:       entry.Content,
+		Mood:          entry.Mood,
+		Tags:          entry.Tags,
+		CreatedAt:     timestamppb.New(entry.CreatedAt),
+		UpdatedAt:     timestamppb.New(entry.UpdatedAt),
+		ContentFormat: entry.ContentFormat,
+	}
+	if entry.Encryption != nil {
+		protoEntry.Encryption = &pb.EncryptionMetadata{
+			Algorithm: entry.Encryption.Algorithm,
+			KeyId:     entry.Encryption.KeyID,
+			Nonce:     entry.Encryption.Nonce,
+			Kdf:       entry.Encryption.KDF,
+			Salt:      entry.Encryption.Salt,
+		}
+	}
+	return protoEntry
+}
+
+// protoToDomainEncryption converts proto encryption metadata to domain
+func protoToDomainEncryption(enc *pb.EncryptionMetadata) *domain.EncryptionMetadata {
+	if enc == nil {
+		return nil
+	}
+	return &domain.EncryptionMetadata{
+		Algorithm: enc.Algorithm,
+		KeyID:     enc.KeyId,
+		Nonce:     enc.Nonce,
+		KDF:       enc.Kdf,
+		Salt:      enc.Salt,
 	}
 }
@@ -0,0 +1,38 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"devjournal/internal/requestid"
+)
+
+// LoggingInterceptor logs each RPC like the REST Logging middleware logs requests, with the
+// Connect code in place of the status
+func LoggingInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			start := time.Now()
+
+			res, err := next(ctx, req)
+
+			code := "ok"
+			if err != nil {
+				code = connect.CodeOf(err).String()
+			}
+			log.Printf(
+				"%s %s %s %s %s %s",
+				req.Peer().Addr,
+				req.HTTPMethod(),
+				req.Spec().Procedure,
+				code,
+				time.Since(start),
+				requestid.FromContext(ctx),
+			)
+			return res, err
+		}
+	}
+}
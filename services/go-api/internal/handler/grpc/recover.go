@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"connectrpc.com/connect"
+
+	"devjournal/internal/errreport"
+	"devjournal/internal/requestid"
+	"devjournal/pkg/apperr"
+)
+
+// RecoverHandler is a connect.WithRecover handler that logs panics and reports them, with the
+// procedure and user they happened in, to reporter if it is not nil
+func RecoverHandler(reporter errreport.Reporter) func(context.Context, connect.Spec, http.Header, any) error {
+	return func(ctx context.Context, spec connect.Spec, _ http.Header, v any) error {
+		stack := debug.Stack()
+		id := requestid.FromContext(ctx)
+		log.Printf("PANIC: %v in %s (request %s)\n%s", v, spec.Procedure, id, stack)
+		if reporter != nil {
+			ev := errreport.Panic(v, stack)
+			ev.RequestID = id
+			if userID, err := getUserIDFromContext(ctx); err == nil {
+				ev.UserID = userID.String()
+			}
+			ev.Method, ev.Path = http.MethodPost, spec.Procedure
+			ev.Tags = map[string]string{"component": "connect"}
+			reporter.Report(ev)
+		}
+
+		connectErr := connect.NewError(connect.CodeInternal, errors.New("internal error"))
+		connectErr.Meta().Set("Error-Code", apperr.CodeInternal)
+		connectErr.Meta().Set(requestid.Header, id)
+		return connectErr
+	}
+}
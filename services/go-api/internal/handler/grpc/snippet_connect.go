@@ -16,12 +16,16 @@ import (
 // SnippetConnectHandler implements the Connect RPC SnippetService
 type SnippetConnectHandler struct {
 	devjournalv1connect.UnimplementedSnippetServiceHandler
-	snippetService *service.SnippetService
+	snippetService  *service.SnippetService
+	settingsService *service.SettingsService
 }
 
 // NewSnippetConnectHandler creates a new Connect RPC snippet handler
-func NewSnippetConnectHandler(snippetService *service.SnippetService) *SnippetConnectHandler {
-	return &SnippetConnectHandler{snippetService: snippetService}
+func NewSnippetConnectHandler(snippetService *service.SnippetService, settingsService *service.SettingsService) *SnippetConnectHandler {
+	return &SnippetConnectHandler{
+		snippetService:  snippetService,
+		settingsService: settingsService,
+	}
 }
 
 // CreateSnippet creates a new code snippet
@@ -41,14 +45,17 @@ func (h *SnippetConnectHandler) CreateSnippet(
 		Description: req.Msg.Description,
 		Code:        req.Msg.Code,
 		Language:    req.Msg.Language,
+		Files:       protoToDomainFiles(req.Msg.Files),
 		Tags:        req.Msg.Tags,
 		Metadata:    metadata,
 		IsPublic:    req.Msg.IsPublic,
+		// CreateSnippetRequest has no field to override the duplicate check with, so it is skipped
+		AllowDuplicate: true,
 	}
 
 	snippet, err := h.snippetService.Create(ctx, userID.String(), domainReq)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, toConnectError(ctx, err)
 	}
 
 	return connect.NewResponse(domainToProtoSnippet(snippet)), nil
@@ -66,10 +73,10 @@ func (h *SnippetConnectHandler) GetSnippet(
 
 	snippet, err := h.snippetService.GetByID(ctx, req.Msg.Id, userID.String())
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, toConnectError(ctx, err)
 	}
 	if snippet == nil {
-		return nil, connect.NewError(connect.CodeNotFound, nil)
+		return nil, toConnectError(ctx, service.ErrSnippetNotFound)
 	}
 
 	return connect.NewResponse(domainToProtoSnippet(snippet)), nil
@@ -85,26 +92,16 @@ func (h *SnippetConnectHandler) ListSnippets(
 		return nil, connect.NewError(connect.CodeUnauthenticated, err)
 	}
 
-	var snippets []domain.Snippet
-	var total int64
+	limit := int64(h.settingsService.PageSize(ctx, userID, int(req.Msg.Limit)))
 
-	if req.Msg.Language != "" {
-		snippets, err = h.snippetService.ListByLanguage(ctx, userID.String(), req.Msg.Language, int64(req.Msg.Limit), int64(req.Msg.Offset))
-		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, err)
-		}
-		total = int64(len(snippets))
-	} else if len(req.Msg.Tags) > 0 {
-		snippets, err = h.snippetService.ListByTags(ctx, userID.String(), req.Msg.Tags, int64(req.Msg.Limit), int64(req.Msg.Offset))
-		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, err)
-		}
-		total = int64(len(snippets))
-	} else {
-		snippets, total, err = h.snippetService.List(ctx, userID.String(), int64(req.Msg.Limit), int64(req.Msg.Offset))
-		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, err)
-		}
+	// Language and tags combine rather than taking precedence over one another
+	filter := &domain.SnippetFilter{
+		Language: req.Msg.Language,
+		Tags:     req.Msg.Tags,
+	}
+	snippets, total, err := h.snippetService.ListFiltered(ctx, userID.String(), filter, limit, int64(req.Msg.Offset))
+	if err != nil {
+		return nil, toConnectError(ctx, err)
 	}
 
 	protoSnippets := make([]*pb.Snippet, len(snippets))
@@ -114,8 +111,9 @@ func (h *SnippetConnectHandler) ListSnippets(
 	}
 
 	return connect.NewResponse(&pb.ListSnippetsResponse{
-		Snippets:   protoSnippets,
-		TotalCount: total,
+		Snippets:    protoSnippets,
+		TotalCount:  total,
+		MaxPageSize: domain.MaxPageSize,
 	}), nil
 }
 
@@ -136,6 +134,7 @@ func (h *SnippetConnectHandler) UpdateSnippet(
 		Description: req.Msg.Description,
 		Code:        req.Msg.Code,
 		Language:    req.Msg.Language,
+		Files:       protoToDomainFiles(req.Msg.Files),
 		Tags:        req.Msg.Tags,
 		Metadata:    metadata,
 		IsPublic:    req.Msg.IsPublic,
@@ -143,7 +142,7 @@ func (h *SnippetConnectHandler) UpdateSnippet(
 
 	snippet, err := h.snippetService.Update(ctx, req.Msg.Id, userID.String(), domainReq)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, toConnectError(ctx, err)
 	}
 
 	return connect.NewResponse(domainToProtoSnippet(snippet)), nil
@@ -161,7 +160,7 @@ func (h *SnippetConnectHandler) DeleteSnippet(
 
 	err = h.snippetService.Delete(ctx, req.Msg.Id, userID.String())
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, toConnectError(ctx, err)
 	}
 
 	return connect.NewResponse(&pb.DeleteSnippetResponse{Success: true}), nil
@@ -177,9 +176,10 @@ func (h *SnippetConnectHandler) SearchSnippets(
 		return nil, connect.NewError(connect.CodeUnauthenticated, err)
 	}
 
-	snippets, err := h.snippetService.Search(ctx, userID.String(), req.Msg.Query, int64(req.Msg.Limit), int64(req.Msg.Offset))
+	limit := int64(h.settingsService.PageSize(ctx, userID, int(req.Msg.Limit)))
+	snippets, err := h.snippetService.Search(ctx, userID.String(), req.Msg.Query, limit, int64(req.Msg.Offset))
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, toConnectError(ctx, err)
 	}
 
 	protoSnippets := make([]*pb.Snippet, len(snippets))
@@ -189,8 +189,9 @@ func (h *SnippetConnectHandler) SearchSnippets(
 	}
 
 	return connect.NewResponse(&pb.ListSnippetsResponse{
-		Snippets:   protoSnippets,
-		TotalCount: int64(len(snippets)),
+		Snippets:    protoSnippets,
+		TotalCount:  int64(len(snippets)),
+		MaxPageSize: domain.MaxPageSize,
 	}), nil
 }
 
@@ -206,17 +207,33 @@ func (h *SnippetConnectHandler) GetLanguageStats(
 
 	stats, err := h.snippetService.GetLanguageStats(ctx, userID.String())
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, toConnectError(ctx, err)
 	}
+	resp := &pb.GetLanguageStatsResponse{LanguageCounts: stats}
 
-	return connect.NewResponse(&pb.GetLanguageStatsResponse{
-		LanguageCounts: stats,
-	}), nil
+	if req.Msg.Interval != "" || req.Msg.Range != "" {
+		buckets, err := h.snippetService.GetLanguageStatsOverTime(ctx, userID.String(), req.Msg.Interval, req.Msg.Range)
+		if err != nil {
+			return nil, toConnectError(ctx, err)
+		}
+		for _, bucket := range buckets {
+			resp.Buckets = append(resp.Buckets, &pb.LanguageStatsBucket{
+				PeriodStart:    timestamppb.New(bucket.PeriodStart),
+				LanguageCounts: bucket.Counts,
+			})
+		}
+	}
+
+	return connect.NewResponse(resp), nil
 }
 
 // domainToProtoSnippet converts a domain Snippet to proto
 func domainToProtoSnippet(snippet *domain.Snippet) *pb.Snippet {
 	metadata, _ := structpb.NewStruct(snippet.Metadata)
+	files := make([]*pb.SnippetFile, len(snippet.Files))
+	for i, f := range snippet.Files {
+		files[i] = &pb.SnippetFile{Name: f.Name, Language: f.Language, Code: f.Code}
+	}
 
 	return &pb.Snippet{
 		Id:          snippet.ID,
@@ -231,7 +248,20 @@ func domainToProtoSnippet(snippet *domain.Snippet) *pb.Snippet {
 		ViewsCount:  int32(snippet.ViewsCount),
 		CreatedAt:   timestamppb.New(snippet.CreatedAt),
 		UpdatedAt:   timestamppb.New(snippet.UpdatedAt),
+		Files:       files,
+	}
+}
+
+// protoToDomainFiles converts proto snippet files to domain
+func protoToDomainFiles(files []*pb.SnippetFile) []domain.SnippetFile {
+	if len(files) == 0 {
+		return nil
+	}
+	result := make([]domain.SnippetFile, len(files))
+	for i, f := range files {
+		result[i] = domain.SnippetFile{Name: f.Name, Language: f.Language, Code: f.Code}
 	}
+	return result
 }
 
 // structToMap converts a protobuf Struct to a Go map
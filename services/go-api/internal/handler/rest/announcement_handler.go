@@ -0,0 +1,147 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// AnnouncementHandler handles system announcements: admins publishing them, and users reading
+// them in their notification center
+type AnnouncementHandler struct {
+	announcementService *service.AnnouncementService
+	settingsService     *service.SettingsService
+}
+
+// NewAnnouncementHandler creates a new announcement handler
+func NewAnnouncementHandler(announcementService *service.AnnouncementService, settingsService *service.SettingsService) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		announcementService: announcementService,
+		settingsService:     settingsService,
+	}
+}
+
+// Create handles POST /api/admin/announcements
+func (h *AnnouncementHandler) Create(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserUUID(r.Context())
+
+	var req domain.CreateAnnouncementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	announcement, err := h.announcementService.Create(r.Context(), adminID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create announcement")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, announcement)
+}
+
+// ListAll handles GET /api/admin/announcements
+func (h *AnnouncementHandler) ListAll(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := h.page(r)
+
+	announcements, total, err := h.announcementService.List(r.Context(), pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list announcements")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        announcements,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// Delete handles DELETE /api/admin/announcements/{id}
+func (h *AnnouncementHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid announcement ID")
+		return
+	}
+
+	if err := h.announcementService.Delete(r.Context(), id); err != nil {
+		httputil.WriteError(w, err, "failed to delete announcement")
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// List handles GET /api/announcements?unread=true
+func (h *AnnouncementHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	page, pageSize := h.page(r)
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	announcements, total, unread, err := h.announcementService.ListForUser(r.Context(), userID, unreadOnly, pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list announcements")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        announcements,
+		"unread":      unread,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// MarkRead handles POST /api/announcements/read. An empty body marks every announcement read.
+func (h *AnnouncementHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req domain.MarkAnnouncementsReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	updated, err := h.announcementService.MarkRead(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to mark announcements read")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]int{"updated": updated})
+}
+
+// page returns the requested page and page size
+func (h *AnnouncementHandler) page(r *http.Request) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if page <= 0 {
+		page = 1
+	}
+	return page, h.settingsService.PageSize(r.Context(), middleware.GetUserUUID(r.Context()), pageSize)
+}
@@ -4,19 +4,25 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"time"
 
+	"devjournal/internal/flags"
+	"devjournal/internal/middleware"
 	"devjournal/internal/service"
 	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
 )
 
 // AuthHandler handles authentication endpoints
 type AuthHandler struct {
-	authService *service.AuthService
+	authService   *service.AuthService
+	inviteService *service.InviteService
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService *service.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService *service.AuthService, inviteService *service.InviteService) *AuthHandler {
+	return &AuthHandler{authService: authService, inviteService: inviteService}
 }
 
 // RegisterRequest represents the registration request body
@@ -24,6 +30,7 @@ type RegisterRequest struct {
 	Email       string `json:"email"`
 	Password    string `json:"password"`
 	DisplayName string `json:"displayName"`
+	InviteCode  string `json:"inviteCode"` // required while registration is invite-only
 }
 
 // LoginRequest represents the login request body
@@ -32,10 +39,27 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// RefreshRequest represents the token refresh request body
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
 // AuthResponse represents the authentication response
 type AuthResponse struct {
-	Token string      `json:"token"`
-	User  UserProfile `json:"user"`
+	Token        string      `json:"token"`
+	RefreshToken string      `json:"refreshToken,omitempty"` // exchanged at /auth/refresh once Token expires
+	User         UserProfile `json:"user"`
+}
+
+// OpenVaultRequest represents the step-up verification request body
+type OpenVaultRequest struct {
+	Password string `json:"password"`
+}
+
+// VaultSessionResponse carries a vault session token, sent back in the X-Vault-Token header
+type VaultSessionResponse struct {
+	VaultToken string    `json:"vaultToken"`
+	ExpiresAt  time.Time `json:"expiresAt"`
 }
 
 // UserProfile represents the user data in responses
@@ -45,8 +69,14 @@ type UserProfile struct {
 	DisplayName string `json:"displayName"`
 }
 
-// Register handles user registration
+// Register handles user registration. While the invite_only flag is on, it takes an invite
+// code, which the registration uses up one use of.
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	if !flags.Enabled(r.Context(), flags.RegistrationOpen) {
+		httputil.Error(w, http.StatusForbidden, "registration is closed")
+		return
+	}
+
 	var req RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		httputil.Error(w, http.StatusBadRequest, "invalid request body")
@@ -64,22 +94,35 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var inviteID uuid.UUID
+	if flags.Enabled(r.Context(), flags.InviteOnly) {
+		id, err := h.inviteService.Redeem(r.Context(), req.InviteCode)
+		if err != nil {
+			httputil.WriteError(w, err, "failed to redeem invite")
+			return
+		}
+		inviteID = id
+	}
+
 	// Register user
 	user, token, err := h.authService.Register(r.Context(), req.Email, req.Password, req.DisplayName)
 	if err != nil {
-		if err == service.ErrEmailAlreadyExists {
-			httputil.Error(w, http.StatusConflict, "email already exists")
-			return
+		if inviteID != uuid.Nil {
+			h.inviteService.Release(r.Context(), inviteID)
 		}
-		// @REVIEW: Log actual error for debugging
-		log.Printf("[ERROR] Registration failed for %s: %v", req.Email, err)
-		httputil.Error(w, http.StatusInternalServerError, "failed to register user")
+		httputil.WriteError(w, err, "failed to register user")
 		return
 	}
+	// The account exists now, so sign the user in without a refresh token rather than fail
+	refreshToken, err := h.authService.IssueRefreshToken(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("WARN: Failed to issue refresh token for new user %s: %v", user.ID, err)
+	}
 
 	// Return response
 	response := AuthResponse{
-		Token: token,
+		Token:        token,
+		RefreshToken: refreshToken,
 		User: UserProfile{
 			ID:          user.ID.String(),
 			Email:       user.Email,
@@ -107,19 +150,19 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	// Login user
 	user, token, err := h.authService.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
-		if err == service.ErrInvalidCredentials {
-			httputil.Error(w, http.StatusUnauthorized, "invalid email or password")
-			return
-		}
-		// @REVIEW: Log actual error for debugging
-		log.Printf("[ERROR] Login failed for %s: %v", req.Email, err)
-		httputil.Error(w, http.StatusInternalServerError, "failed to login")
+		httputil.WriteError(w, err, "failed to login")
+		return
+	}
+	refreshToken, err := h.authService.IssueRefreshToken(r.Context(), user.ID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to login")
 		return
 	}
 
 	// Return response
 	response := AuthResponse{
-		Token: token,
+		Token:        token,
+		RefreshToken: refreshToken,
 		User: UserProfile{
 			ID:          user.ID.String(),
 			Email:       user.Email,
@@ -129,3 +172,60 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	httputil.JSON(w, http.StatusOK, response)
 }
+
+// Refresh handles POST /api/auth/refresh, exchanging a refresh token for a new token and the
+// refresh token to use next time
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.RefreshToken == "" {
+		httputil.Error(w, http.StatusBadRequest, "refreshToken is required")
+		return
+	}
+
+	user, token, refreshToken, err := h.authService.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to refresh token")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, AuthResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		User: UserProfile{
+			ID:          user.ID.String(),
+			Email:       user.Email,
+			DisplayName: user.DisplayName,
+		},
+	})
+}
+
+// OpenVault handles POST /api/auth/vault, re-checking the user's password to open a vault session
+func (h *AuthHandler) OpenVault(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req OpenVaultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Password == "" {
+		httputil.Error(w, http.StatusBadRequest, "password is required")
+		return
+	}
+
+	token, expiresAt, err := h.authService.OpenVault(r.Context(), userID, req.Password)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to open vault")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, VaultSessionResponse{VaultToken: token, ExpiresAt: expiresAt})
+}
@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"net/http"
+
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+)
+
+// BackupHandler handles admin endpoints for backing up the databases
+type BackupHandler struct {
+	backupService *service.BackupService
+}
+
+// NewBackupHandler creates a new backup handler
+func NewBackupHandler(backupService *service.BackupService) *BackupHandler {
+	return &BackupHandler{backupService: backupService}
+}
+
+// Create handles POST /api/admin/backups. The backup runs in the background; list backups
+// to see when it completes.
+func (h *BackupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	backup, err := h.backupService.Create(r.Context())
+	if err != nil {
+		httputil.WriteError(w, err, "failed to start backup")
+		return
+	}
+
+	httputil.JSON(w, http.StatusAccepted, backup)
+}
+
+// List handles GET /api/admin/backups
+func (h *BackupHandler) List(w http.ResponseWriter, r *http.Request) {
+	backups, err := h.backupService.List(r.Context())
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list backups")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{"data": backups})
+}
+
+// Verify handles POST /api/admin/backups/{name}/verify
+func (h *BackupHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	result, err := h.backupService.Verify(r.Context(), r.PathValue("name"))
+	if err != nil {
+		httputil.WriteError(w, err, "failed to verify backup")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, result)
+}
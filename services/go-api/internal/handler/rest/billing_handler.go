@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// maxWebhookBytes bounds the Stripe webhook payload read into memory
+const maxWebhookBytes = 64 << 10
+
+// BillingHandler handles plan, subscription, checkout, and Stripe webhook endpoints
+type BillingHandler struct {
+	billingService *service.BillingService
+}
+
+// NewBillingHandler creates a new billing handler
+func NewBillingHandler(billingService *service.BillingService) *BillingHandler {
+	return &BillingHandler{billingService: billingService}
+}
+
+// ListPlans handles GET /api/billing/plans
+func (h *BillingHandler) ListPlans(w http.ResponseWriter, r *http.Request) {
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":    h.billingService.Plans(),
+		"enabled": h.billingService.Enabled(),
+	})
+}
+
+// GetSubscription handles GET /api/billing/subscription
+func (h *BillingHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	sub, err := h.billingService.Subscription(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get subscription")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, sub)
+}
+
+// Checkout handles POST /api/billing/checkout, returning the Stripe checkout URL
+func (h *BillingHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	session, err := h.billingService.Checkout(r.Context(), userID, req.Plan)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create checkout session")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, session)
+}
+
+// Webhook handles POST /api/billing/webhook from Stripe
+func (h *BillingHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBytes))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+
+	if err := h.billingService.HandleWebhook(r.Context(), payload, r.Header.Get("Stripe-Signature")); err != nil {
+		httputil.WriteError(w, err, "failed to handle webhook")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]bool{"received": true})
+}
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// BookmarkHandler handles the links a user saved to come back to
+type BookmarkHandler struct {
+	bookmarkService *service.BookmarkService
+	settingsService *service.SettingsService
+}
+
+// NewBookmarkHandler creates a new bookmark handler
+func NewBookmarkHandler(bookmarkService *service.BookmarkService, settingsService *service.SettingsService) *BookmarkHandler {
+	return &BookmarkHandler{bookmarkService: bookmarkService, settingsService: settingsService}
+}
+
+// List handles GET /api/bookmarks?tag=
+func (h *BookmarkHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), userID, pageSize)
+
+	bookmarks, total, err := h.bookmarkService.List(r.Context(), userID, r.URL.Query().Get("tag"), pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list bookmarks")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        bookmarks,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// Create handles POST /api/bookmarks
+func (h *BookmarkHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.CreateBookmarkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	bookmark, err := h.bookmarkService.Create(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create bookmark")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, bookmark)
+}
+
+// Delete handles DELETE /api/bookmarks/{id}
+func (h *BookmarkHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+	bookmarkID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid bookmark ID")
+		return
+	}
+
+	if err := h.bookmarkService.Delete(r.Context(), userID, bookmarkID); err != nil {
+		httputil.WriteError(w, err, "failed to delete bookmark")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
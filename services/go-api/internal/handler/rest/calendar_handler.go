@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"net/http"
+
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// CalendarHandler handles the private iCal feed and its subscription token
+type CalendarHandler struct {
+	calendarService *service.CalendarService
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(calendarService *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{calendarService: calendarService}
+}
+
+// Feed handles GET /api/users/me/calendar.ics?token=. Calendar apps can't send a bearer
+// token, so the feed token in the query identifies the user.
+func (h *CalendarHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	body, err := h.calendarService.Feed(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		httputil.WriteError(w, err, "failed to build calendar")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="devjournal.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// Get handles GET /api/users/me/calendar
+func (h *CalendarHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	feed, err := h.calendarService.Get(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get calendar feed")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, feed)
+}
+
+// Rotate handles POST /api/users/me/calendar/token, returning the feed URL. Any previous URL stops working.
+func (h *CalendarHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	feed, err := h.calendarService.Rotate(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create calendar feed")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, feed)
+}
+
+// Revoke handles DELETE /api/users/me/calendar/token
+func (h *CalendarHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.calendarService.Revoke(r.Context(), userID); err != nil {
+		httputil.WriteError(w, err, "failed to revoke calendar feed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,199 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// CaptureHandler handles the quick-capture inbox, where browser extensions, AI chats, and other
+// clients send text to triage later into journal entries, snippets, or bookmarks
+type CaptureHandler struct {
+	captureService  *service.CaptureService
+	progressService *service.ProgressService
+	settingsService *service.SettingsService
+}
+
+// NewCaptureHandler creates a new capture handler
+func NewCaptureHandler(captureService *service.CaptureService, progressService *service.ProgressService, settingsService *service.SettingsService) *CaptureHandler {
+	return &CaptureHandler{
+		captureService:  captureService,
+		progressService: progressService,
+		settingsService: settingsService,
+	}
+}
+
+// Capture handles POST /api/capture, adding text to the inbox
+func (h *CaptureHandler) Capture(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.CaptureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	c, err := h.captureService.Create(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to capture")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, c)
+}
+
+// List handles GET /api/inbox?status=, the captures waiting in the inbox or, with status
+// archived or converted, those that left it
+func (h *CaptureHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), userID, pageSize)
+
+	captures, total, err := h.captureService.List(r.Context(), userID, r.URL.Query().Get("status"), pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list captures")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        captures,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// Update handles PUT /api/inbox/{id}
+func (h *CaptureHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, captureID, ok := captureRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateCaptureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	// Captures become entries, snippets, or bookmarks, which need both
+	if req.Title == "" || req.Content == "" {
+		httputil.Error(w, http.StatusBadRequest, "title and content are required")
+		return
+	}
+
+	c, err := h.captureService.Update(r.Context(), captureID, userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update capture")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, c)
+}
+
+// Convert handles POST /api/inbox/{id}/convert, creating the entry, snippet, or bookmark and
+// returning the capture with its publishedId
+func (h *CaptureHandler) Convert(w http.ResponseWriter, r *http.Request) {
+	userID, captureID, ok := captureRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.ConvertCaptureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	c, err := h.captureService.Convert(r.Context(), captureID, userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to convert capture")
+		return
+	}
+
+	// Record the new entry or snippet for progress tracking
+	var recordErr error
+	switch c.Kind {
+	case domain.CaptureEntry:
+		recordErr = h.progressService.RecordJournalEntry(r.Context(), userID)
+	case domain.CaptureSnippet:
+		recordErr = h.progressService.RecordSnippet(r.Context(), userID)
+	}
+	if recordErr != nil {
+		log.Printf("WARN: Failed to record capture for progress: %v", recordErr)
+		// Don't fail the request, progress tracking is secondary
+	}
+
+	httputil.JSON(w, http.StatusCreated, c)
+}
+
+// Archive handles POST /api/inbox/{id}/archive
+func (h *CaptureHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	userID, captureID, ok := captureRequest(w, r)
+	if !ok {
+		return
+	}
+
+	c, err := h.captureService.Archive(r.Context(), captureID, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to archive capture")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, c)
+}
+
+// Dismiss handles DELETE /api/inbox/{id}
+func (h *CaptureHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	userID, captureID, ok := captureRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.captureService.Dismiss(r.Context(), captureID, userID); err != nil {
+		httputil.WriteError(w, err, "failed to dismiss capture")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// captureRequest returns the user and capture a request is for, writing an error if either is invalid
+func captureRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	captureID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid capture ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, captureID, true
+}
@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/handler/websocket"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// ChatHandler serves group chat history kept by the WebSocket hub
+type ChatHandler struct {
+	groupService *service.StudyGroupService
+	hub          *websocket.Hub
+}
+
+// NewChatHandler creates a new chat handler
+func NewChatHandler(groupService *service.StudyGroupService, hub *websocket.Hub) *ChatHandler {
+	return &ChatHandler{groupService: groupService, hub: hub}
+}
+
+// Thread handles GET /api/groups/{id}/messages/{messageId}/thread?channel=, returning a
+// message and its replies while they are in the hub's recent history
+func (h *ChatHandler) Thread(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	if err := h.groupService.CheckMember(r.Context(), groupID, userID); err != nil {
+		httputil.WriteError(w, err, "failed to check group membership")
+		return
+	}
+
+	thread := h.hub.Thread(domain.ChannelRoom(groupID, r.URL.Query().Get("channel")), r.PathValue("messageId"))
+	if thread == nil {
+		httputil.Error(w, http.StatusNotFound, "message not found")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, thread)
+}
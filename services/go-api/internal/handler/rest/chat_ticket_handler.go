@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// ChatTicketHandler issues the tickets chat WebSockets are opened with
+type ChatTicketHandler struct {
+	chatTicketService *service.ChatTicketService
+}
+
+// NewChatTicketHandler creates a new chat ticket handler
+func NewChatTicketHandler(chatTicketService *service.ChatTicketService) *ChatTicketHandler {
+	return &ChatTicketHandler{chatTicketService: chatTicketService}
+}
+
+// Create handles POST /api/ws/ticket, returning a one-time ticket for /ws/chat/{room}
+func (h *ChatTicketHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.CreateChatTicketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	ticket, err := h.chatTicketService.Issue(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create chat ticket")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, ticket)
+}
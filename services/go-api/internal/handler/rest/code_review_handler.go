@@ -0,0 +1,200 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// CodeReviewHandler handles study group code review endpoints
+type CodeReviewHandler struct {
+	reviewService   *service.CodeReviewService
+	settingsService *service.SettingsService
+}
+
+// NewCodeReviewHandler creates a new code review handler
+func NewCodeReviewHandler(reviewService *service.CodeReviewService, settingsService *service.SettingsService) *CodeReviewHandler {
+	return &CodeReviewHandler{
+		reviewService:   reviewService,
+		settingsService: settingsService,
+	}
+}
+
+// List handles GET /api/groups/{id}/code-reviews?status=open
+func (h *CodeReviewHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := h.group(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("pageSize"))
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), userID, pageSize)
+
+	reviews, total, err := h.reviewService.List(r.Context(), userID, groupID, query.Get("status"), pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list code reviews")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        reviews,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// Create handles POST /api/groups/{id}/code-reviews
+func (h *CodeReviewHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := h.group(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.CreateCodeReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	review, err := h.reviewService.Create(r.Context(), userID, groupID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to request code review")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, review)
+}
+
+// Get handles GET /api/groups/{id}/code-reviews/{reviewId}
+func (h *CodeReviewHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, reviewID, ok := h.review(w, r)
+	if !ok {
+		return
+	}
+
+	review, err := h.reviewService.Get(r.Context(), userID, groupID, reviewID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get code review")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, review)
+}
+
+// Delete handles DELETE /api/groups/{id}/code-reviews/{reviewId}
+func (h *CodeReviewHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, reviewID, ok := h.review(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.reviewService.Delete(r.Context(), userID, groupID, reviewID); err != nil {
+		httputil.WriteError(w, err, "failed to delete code review")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// Resolve handles PUT and DELETE /api/groups/{id}/code-reviews/{reviewId}/resolved
+func (h *CodeReviewHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, reviewID, ok := h.review(w, r)
+	if !ok {
+		return
+	}
+
+	review, err := h.reviewService.Resolve(r.Context(), userID, groupID, reviewID, r.Method == http.MethodPut)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to resolve code review")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, review)
+}
+
+// Comment handles POST /api/groups/{id}/code-reviews/{reviewId}/comments
+func (h *CodeReviewHandler) Comment(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, reviewID, ok := h.review(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.CodeReviewCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	comment, err := h.reviewService.Comment(r.Context(), userID, groupID, reviewID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to add comment")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, comment)
+}
+
+// ResolveComment handles PUT and DELETE /api/groups/{id}/code-reviews/{reviewId}/comments/{commentId}/resolved
+func (h *CodeReviewHandler) ResolveComment(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, reviewID, ok := h.review(w, r)
+	if !ok {
+		return
+	}
+	commentID, err := uuid.Parse(r.PathValue("commentId"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid comment ID")
+		return
+	}
+
+	comment, err := h.reviewService.ResolveComment(r.Context(), userID, groupID, reviewID, commentID, r.Method == http.MethodPut)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to resolve comment")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, comment)
+}
+
+// group reads the caller and the group ID from the request, writing an error if either is invalid
+func (h *CodeReviewHandler) group(w http.ResponseWriter, r *http.Request) (userID, groupID uuid.UUID, ok bool) {
+	userID = middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid group ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, groupID, true
+}
+
+// review reads the caller, the group ID, and the review ID from the request
+func (h *CodeReviewHandler) review(w http.ResponseWriter, r *http.Request) (userID, groupID, reviewID uuid.UUID, ok bool) {
+	userID, groupID, ok = h.group(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	reviewID, err := uuid.Parse(r.PathValue("reviewId"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid review ID")
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	return userID, groupID, reviewID, true
+}
@@ -0,0 +1,141 @@
+package rest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"devjournal/internal/codingtime"
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// maxHeartbeatBytes bounds heartbeat requests read into memory
+const maxHeartbeatBytes = 1 << 20
+
+// CodingHandler handles where coding time comes from: a WakaTime account, or heartbeats sent by
+// WakaTime editor plugins pointed at DevJournal
+type CodingHandler struct {
+	codingService *service.CodingService
+}
+
+// NewCodingHandler creates a new coding handler
+func NewCodingHandler(codingService *service.CodingService) *CodingHandler {
+	return &CodingHandler{codingService: codingService}
+}
+
+// GetSource handles GET /api/coding/source
+func (h *CodingHandler) GetSource(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	src, err := h.codingService.Get(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get coding time source")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, src)
+}
+
+// ConnectWakaTime handles PUT /api/coding/wakatime
+func (h *CodingHandler) ConnectWakaTime(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req domain.ConnectWakaTimeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	src, err := h.codingService.ConnectWakaTime(r.Context(), userID, req.APIKey)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to connect WakaTime")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, src)
+}
+
+// CreateHeartbeatToken handles POST /api/coding/heartbeat-token, returning the api_url and a new
+// key to set in a WakaTime editor plugin
+func (h *CodingHandler) CreateHeartbeatToken(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	src, err := h.codingService.CreateHeartbeatToken(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create heartbeat token")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, src)
+}
+
+// Disconnect handles DELETE /api/coding/source
+func (h *CodingHandler) Disconnect(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.codingService.Disconnect(r.Context(), userID); err != nil {
+		httputil.WriteError(w, err, "failed to disconnect coding time source")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Heartbeats handles POST /api/coding/users/current/heartbeats and its .bulk variant, the paths
+// WakaTime plugins send to under their api_url. It authenticates by the heartbeat token, so it
+// needs no bearer token of the user's session.
+func (h *CodingHandler) Heartbeats(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHeartbeatBytes))
+	if err != nil {
+		httputil.Error(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+	beats, err := codingtime.ParseHeartbeats(body)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token := codingtime.APIKey(r.Header.Get("Authorization"))
+	if token == "" {
+		// Plugins that cannot set headers pass the key as a query parameter
+		token = r.URL.Query().Get("api_key")
+	}
+	if err := h.codingService.RecordHeartbeats(r.Context(), token, beats); err != nil {
+		httputil.WriteError(w, err, "failed to record heartbeats")
+		return
+	}
+
+	// Reply the way WakaTime does, so plugins don't queue the heartbeats to resend
+	if !strings.HasSuffix(r.URL.Path, ".bulk") {
+		httputil.JSON(w, http.StatusCreated, map[string]interface{}{"data": map[string]interface{}{}})
+		return
+	}
+	responses := make([][]interface{}, len(beats))
+	for i := range responses {
+		responses[i] = []interface{}{map[string]interface{}{"data": map[string]interface{}{}}, http.StatusCreated}
+	}
+	httputil.JSON(w, http.StatusAccepted, map[string]interface{}{"responses": responses})
+}
@@ -0,0 +1,118 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// DeviceAuthHandler handles the device sign-in flow used by editor plugins:
+// the plugin starts a sign-in and polls, while the user approves it in the web app
+type DeviceAuthHandler struct {
+	deviceAuthService *service.DeviceAuthService
+}
+
+// NewDeviceAuthHandler creates a new device auth handler
+func NewDeviceAuthHandler(deviceAuthService *service.DeviceAuthService) *DeviceAuthHandler {
+	return &DeviceAuthHandler{deviceAuthService: deviceAuthService}
+}
+
+// Start issues a device code and a user code for the plugin to display
+func (h *DeviceAuthHandler) Start(w http.ResponseWriter, r *http.Request) {
+	var req domain.StartDeviceAuthRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httputil.Error(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	resp, err := h.deviceAuthService.Start(r.Context(), req.ClientName)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to start device sign-in")
+		return
+	}
+	httputil.JSON(w, http.StatusOK, resp)
+}
+
+// Token exchanges an approved device code for a JWT. Until approval it fails with
+// details.error set to authorization_pending, slow_down, access_denied, or expired_token.
+func (h *DeviceAuthHandler) Token(w http.ResponseWriter, r *http.Request) {
+	var req domain.DeviceTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.DeviceCode == "" {
+		httputil.Error(w, http.StatusBadRequest, "deviceCode is required")
+		return
+	}
+
+	user, token, refreshToken, err := h.deviceAuthService.Poll(r.Context(), req.DeviceCode)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to complete device sign-in")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, AuthResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		User: UserProfile{
+			ID:          user.ID.String(),
+			Email:       user.Email,
+			DisplayName: user.DisplayName,
+		},
+	})
+}
+
+// Get shows the pending sign-in for a user code, so the user can check which client is asking
+func (h *DeviceAuthHandler) Get(w http.ResponseWriter, r *http.Request) {
+	auth, err := h.deviceAuthService.Describe(r.Context(), r.PathValue("userCode"))
+	if err != nil {
+		httputil.WriteError(w, err, "failed to find device sign-in")
+		return
+	}
+	httputil.JSON(w, http.StatusOK, auth)
+}
+
+// Approve signs the device in as the current user
+func (h *DeviceAuthHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.resolve(w, r, h.deviceAuthService.Approve)
+}
+
+// Deny rejects the device sign-in
+func (h *DeviceAuthHandler) Deny(w http.ResponseWriter, r *http.Request) {
+	h.resolve(w, r, h.deviceAuthService.Deny)
+}
+
+func (h *DeviceAuthHandler) resolve(w http.ResponseWriter, r *http.Request, action func(ctx context.Context, userCode string, userID uuid.UUID) (*domain.DeviceAuthorization, error)) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.DeviceApprovalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.UserCode == "" {
+		httputil.Error(w, http.StatusBadRequest, "userCode is required")
+		return
+	}
+
+	auth, err := action(r.Context(), req.UserCode, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update device sign-in")
+		return
+	}
+	httputil.JSON(w, http.StatusOK, auth)
+}
@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRecentSnippets = 10
+	maxRecentSnippets     = 50
+)
+
+// EditorHandler serves editor plugins: capturing a selection as a snippet and
+// listing recent snippets to insert
+type EditorHandler struct {
+	snippetService  *service.SnippetService
+	progressService *service.ProgressService
+}
+
+// NewEditorHandler creates a new editor handler
+func NewEditorHandler(snippetService *service.SnippetService, progressService *service.ProgressService) *EditorHandler {
+	return &EditorHandler{snippetService: snippetService, progressService: progressService}
+}
+
+// Capture saves an editor selection as a snippet, recording the file, lines, and
+// repository it came from
+func (h *EditorHandler) Capture(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.CaptureSnippetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// Validate request
+	if strings.TrimSpace(req.Code) == "" || req.FilePath == "" {
+		httputil.Error(w, http.StatusBadRequest, "code and filePath are required")
+		return
+	}
+	if len(req.Code) > domain.MaxCaptureLength {
+		httputil.Error(w, http.StatusBadRequest, "selection is too large to capture")
+		return
+	}
+	if req.StartLine < 0 || req.EndLine < 0 || (req.EndLine > 0 && req.EndLine < req.StartLine) {
+		httputil.Error(w, http.StatusBadRequest, "startLine and endLine must be a valid range")
+		return
+	}
+
+	snippet, err := h.snippetService.Create(r.Context(), userID, req.SnippetRequest())
+	if err != nil {
+		httputil.WriteError(w, err, "failed to capture snippet")
+		return
+	}
+
+	// Record snippet creation for progress tracking
+	if userUUID, err := uuid.Parse(userID); err == nil {
+		if err := h.progressService.RecordSnippet(r.Context(), userUUID); err != nil {
+			log.Printf("WARN: Failed to record snippet for progress: %v", err)
+		}
+	}
+
+	httputil.JSON(w, http.StatusCreated, snippet)
+}
+
+// Recent lists the user's newest snippets with their code, optionally in one language
+func (h *EditorHandler) Recent(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = defaultRecentSnippets
+	}
+	limit = min(limit, maxRecentSnippets)
+
+	filter := &domain.SnippetFilter{Language: domain.NormalizeEditorLanguage(r.URL.Query().Get("language"))}
+	snippets, _, err := h.snippetService.ListFiltered(r.Context(), userID, filter, int64(limit), 0)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list snippets")
+		return
+	}
+
+	data := make([]domain.EditorSnippet, 0, len(snippets))
+	for i := range snippets {
+		data = append(data, domain.NewEditorSnippet(&snippets[i]))
+	}
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
@@ -0,0 +1,201 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/flags"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+)
+
+// embedCSP lets the embed page run only its own inline script and styles
+const embedCSP = "default-src 'none'; style-src 'unsafe-inline'; script-src 'unsafe-inline'; base-uri 'none'; form-action 'none'"
+
+// embedPage renders a snippet for an iframe, one section per file. The script copies a file's
+// code and reports the page height to the parent so the JS loader can size the iframe.
+var embedPage = template.Must(template.New("embed").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Snippet.Title}} · DevJournal</title>
+<link rel="canonical" href="{{.PageURL}}">
+<link rel="alternate" type="application/json+oembed" href="{{.OEmbedURL}}" title="{{.Snippet.Title}}">
+<style>
+  :root { --bg: #ffffff; --fg: #1f2328; --muted: #656d76; --border: #d0d7de; --code-bg: #f6f8fa; --accent: #4f46e5; }
+  .dark { --bg: #0d1117; --fg: #e6edf3; --muted: #8d96a0; --border: #30363d; --code-bg: #161b22; --accent: #818cf8; }
+  * { box-sizing: border-box; }
+  body { margin: 0; background: transparent; font: 14px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; color: var(--fg); }
+  figure { margin: 0; border: 1px solid var(--border); border-radius: 6px; background: var(--bg); overflow: hidden; }
+  header, footer, .file { display: flex; align-items: center; gap: 8px; padding: 8px 12px; }
+  header { border-bottom: 1px solid var(--border); }
+  section + section { border-top: 1px solid var(--border); }
+  .file { padding: 4px 12px; font-size: 12px; }
+  .file span:first-child { flex: 1; font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+  footer { border-top: 1px solid var(--border); color: var(--muted); font-size: 12px; }
+  header a { flex: 1; color: var(--fg); font-weight: 600; text-decoration: none; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+  footer a { color: var(--accent); text-decoration: none; margin-left: auto; }
+  footer span a { margin-left: 0; }
+  .lang { color: var(--muted); font-size: 12px; }
+  button { font: inherit; font-size: 12px; color: var(--fg); background: var(--code-bg); border: 1px solid var(--border); border-radius: 4px; padding: 2px 8px; cursor: pointer; }
+  pre { margin: 0; padding: 12px; background: var(--code-bg); overflow: auto; max-height: 480px; }
+  code { font: 13px/20px ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; tab-size: 4; }
+</style>
+</head>
+<body class="{{.Theme}}">
+<figure>
+  <header>
+    <a href="{{.PageURL}}" target="_blank" rel="noopener">{{.Snippet.Title}}</a>
+  </header>
+  {{range .Snippet.Files}}<section>
+    <div class="file">
+      <span>{{.Name}}</span>
+      {{with .Language}}<span class="lang">{{.}}</span>{{end}}
+      <button type="button" class="copy">Copy</button>
+    </div>
+    <pre><code class="language-{{.Language}}">{{.Code}}</code></pre>
+  </section>
+  {{end}}  <footer>
+    <span>by {{.AuthorName}}
+      {{- with .Snippet.SourceURL}} · <a href="{{.}}" target="_blank" rel="noopener nofollow">source</a>{{end}}
+      {{- with .LicenseURL}} · <a href="{{.}}" target="_blank" rel="license noopener">{{$.Snippet.License}}</a>{{end -}}
+    </span>
+    <a href="{{.PageURL}}" target="_blank" rel="noopener">View on DevJournal</a>
+  </footer>
+</figure>
+<script>
+  (function () {
+    document.querySelectorAll("button.copy").forEach(function (button) {
+      button.addEventListener("click", function () {
+        navigator.clipboard.writeText(button.closest("section").querySelector("code").textContent).then(function () {
+          button.textContent = "Copied";
+          setTimeout(function () { button.textContent = "Copy"; }, 1500);
+        });
+      });
+    });
+    function resize() {
+      parent.postMessage({ type: "devjournal:embed-height", slug: {{.Slug}}, height: document.body.scrollHeight }, "*");
+    }
+    window.addEventListener("load", resize);
+    window.addEventListener("resize", resize);
+  })();
+</script>
+</body>
+</html>
+`))
+
+// embedLoader is served as <slug>.js. It replaces its own script tag with an iframe of the embed
+// page and resizes the iframe to fit, so a snippet embeds with a single tag like a gist.
+const embedLoader = `(function () {
+  var script = document.currentScript;
+  var frame = document.createElement("iframe");
+  frame.src = %s;
+  frame.title = %s;
+  frame.loading = "lazy";
+  frame.style.cssText = "border:0;width:100%%;height:%dpx";
+  script.parentNode.insertBefore(frame, script);
+  window.addEventListener("message", function (event) {
+    if (event.source === frame.contentWindow && event.data && event.data.type === "devjournal:embed-height") {
+      frame.style.height = event.data.height + "px";
+    }
+  });
+})();
+`
+
+// EmbedHandler serves public snippets for embedding in other sites
+type EmbedHandler struct {
+	embedService *service.EmbedService
+}
+
+// NewEmbedHandler creates a new embed handler
+func NewEmbedHandler(embedService *service.EmbedService) *EmbedHandler {
+	return &EmbedHandler{embedService: embedService}
+}
+
+// Snippet handles GET /embed/snippets/{slug}?theme=light|dark, serving the embed page, or the
+// script that loads it when the slug ends in .js
+func (h *EmbedHandler) Snippet(w http.ResponseWriter, r *http.Request) {
+	if !flags.Enabled(r.Context(), flags.PublicSnippets) {
+		http.Error(w, "public snippets are disabled", http.StatusNotFound)
+		return
+	}
+
+	slug, loader := strings.CutSuffix(r.PathValue("slug"), ".js")
+	embed, err := h.embedService.Snippet(r.Context(), slug)
+	if err != nil {
+		status := httputil.StatusFor(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("ERROR: failed to load embed %s: %v", slug, err)
+		}
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	theme := "light"
+	if r.URL.Query().Get("theme") == "dark" {
+		theme = "dark"
+	}
+	src := embed.EmbedURL
+	if theme == "dark" {
+		src += "?theme=dark"
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if loader {
+		srcJSON, _ := json.Marshal(src)
+		titleJSON, _ := json.Marshal(embed.Snippet.Title)
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, embedLoader, srcJSON, titleJSON, embed.Height)
+		return
+	}
+
+	var body bytes.Buffer
+	err = embedPage.Execute(&body, struct {
+		*domain.SnippetEmbed
+		Theme string
+	}{embed, theme})
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", embedCSP)
+	w.Header().Set("Link", "<"+embed.OEmbedURL+`>; rel="alternate"; type="application/json+oembed"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+// OEmbed handles GET /api/oembed?url=&maxwidth=&maxheight=&format=json
+func (h *EmbedHandler) OEmbed(w http.ResponseWriter, r *http.Request) {
+	if !flags.Enabled(r.Context(), flags.PublicSnippets) {
+		httputil.Error(w, http.StatusNotFound, "public snippets are disabled")
+		return
+	}
+
+	query := r.URL.Query()
+	if format := query.Get("format"); format != "" && format != "json" {
+		httputil.Error(w, http.StatusNotImplemented, "only the json format is supported")
+		return
+	}
+	maxWidth, _ := strconv.Atoi(query.Get("maxwidth"))
+	maxHeight, _ := strconv.Atoi(query.Get("maxheight"))
+
+	oembed, err := h.embedService.OEmbed(r.Context(), query.Get("url"), maxWidth, maxHeight)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to build embed")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	httputil.JSON(w, http.StatusOK, oembed)
+}
@@ -0,0 +1,167 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// EntryTemplateHandler handles study group entry templates and the group view of their answers
+type EntryTemplateHandler struct {
+	templateService *service.EntryTemplateService
+}
+
+// NewEntryTemplateHandler creates a new entry template handler
+func NewEntryTemplateHandler(templateService *service.EntryTemplateService) *EntryTemplateHandler {
+	return &EntryTemplateHandler{templateService: templateService}
+}
+
+// List handles GET /api/groups/{id}/templates
+func (h *EntryTemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	templates, err := h.templateService.List(r.Context(), groupID, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list entry templates")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{"data": templates})
+}
+
+// Create handles POST /api/groups/{id}/templates
+func (h *EntryTemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.EntryTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	template, err := h.templateService.Create(r.Context(), groupID, userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create entry template")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, template)
+}
+
+// Update handles PUT /api/groups/{id}/templates/{templateId}
+func (h *EntryTemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, templateID, ok := templateRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.EntryTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	template, err := h.templateService.Update(r.Context(), groupID, userID, templateID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update entry template")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, template)
+}
+
+// Delete handles DELETE /api/groups/{id}/templates/{templateId}
+func (h *EntryTemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, templateID, ok := templateRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.templateService.Delete(r.Context(), groupID, userID, templateID); err != nil {
+		httputil.WriteError(w, err, "failed to delete entry template")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Instantiate handles POST /api/groups/{id}/templates/{templateId}/entries. The body is optional.
+func (h *EntryTemplateHandler) Instantiate(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, templateID, ok := templateRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.InstantiateTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	entry, err := h.templateService.Instantiate(r.Context(), groupID, userID, templateID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to start entry from template")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, entry)
+}
+
+// ListInstances handles GET /api/groups/{id}/templates/{templateId}/instances
+func (h *EntryTemplateHandler) ListInstances(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, templateID, ok := templateRequest(w, r)
+	if !ok {
+		return
+	}
+
+	instances, err := h.templateService.ListInstances(r.Context(), groupID, userID, templateID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list template instances")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{"data": instances})
+}
+
+// GetInstance handles GET /api/groups/{id}/templates/{templateId}/instances/{instance}
+func (h *EntryTemplateHandler) GetInstance(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, templateID, ok := templateRequest(w, r)
+	if !ok {
+		return
+	}
+
+	responses, err := h.templateService.GetInstance(r.Context(), groupID, userID, templateID, r.PathValue("instance"))
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get template responses")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, responses)
+}
+
+// templateRequest reads the user and the group and template IDs of a template request
+func templateRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, uuid.UUID, bool) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	templateID, err := uuid.Parse(r.PathValue("templateId"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid template ID")
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	return userID, groupID, templateID, true
+}
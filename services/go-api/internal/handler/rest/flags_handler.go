@@ -0,0 +1,13 @@
+package rest
+
+import (
+	"net/http"
+
+	"devjournal/internal/flags"
+	"devjournal/pkg/httputil"
+)
+
+// ListFlags handles GET /api/flags so clients can hide disabled features
+func ListFlags(w http.ResponseWriter, r *http.Request) {
+	httputil.JSON(w, http.StatusOK, flags.All())
+}
@@ -0,0 +1,228 @@
+package rest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/gitactivity"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// maxGitWebhookBytes bounds GitHub and GitLab webhook payloads read into memory
+const maxGitWebhookBytes = 5 << 20
+
+// GitActivityHandler handles the Git activity webhook, its settings, and the daily entry drafts
+// made from it
+type GitActivityHandler struct {
+	gitActivityService *service.GitActivityService
+}
+
+// NewGitActivityHandler creates a new Git activity handler
+func NewGitActivityHandler(gitActivityService *service.GitActivityService) *GitActivityHandler {
+	return &GitActivityHandler{gitActivityService: gitActivityService}
+}
+
+// Webhook handles POST /api/git/webhooks/{id} from GitHub or GitLab. It authenticates by the
+// webhook secret, so it needs no bearer token.
+func (h *GitActivityHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	hookID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.WriteError(w, service.ErrGitHookNotFound, "")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxGitWebhookBytes))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+
+	delivery := &gitactivity.Delivery{Body: body}
+	switch {
+	case r.Header.Get("X-GitHub-Event") != "":
+		delivery.Provider = domain.GitProviderGitHub
+		delivery.Event = r.Header.Get("X-GitHub-Event")
+		delivery.Signature = r.Header.Get("X-Hub-Signature-256")
+	case r.Header.Get("X-Gitlab-Event") != "":
+		delivery.Provider = domain.GitProviderGitLab
+		delivery.Event = r.Header.Get("X-Gitlab-Event")
+		delivery.Token = r.Header.Get("X-Gitlab-Token")
+	}
+
+	recorded, err := h.gitActivityService.Receive(r.Context(), hookID, delivery)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to record git activity")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]int{"recorded": recorded})
+}
+
+// GetHook handles GET /api/git/hook
+func (h *GitActivityHandler) GetHook(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	hook, err := h.gitActivityService.GetHook(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get git webhook")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, hook)
+}
+
+// RotateSecret handles POST /api/git/hook/secret, returning the webhook URL and a new secret to
+// enter in GitHub or GitLab. Deliveries signed with any previous secret are refused.
+func (h *GitActivityHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	hook, err := h.gitActivityService.RotateSecret(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create git webhook")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, hook)
+}
+
+// UpdateHook handles PUT /api/git/hook
+func (h *GitActivityHandler) UpdateHook(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req domain.UpdateGitHookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hook, err := h.gitActivityService.UpdateHook(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update git webhook")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, hook)
+}
+
+// DeleteHook handles DELETE /api/git/hook
+func (h *GitActivityHandler) DeleteHook(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.gitActivityService.DeleteHook(r.Context(), userID); err != nil {
+		httputil.WriteError(w, err, "failed to delete git webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDrafts handles GET /api/git/drafts
+func (h *GitActivityHandler) ListDrafts(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	drafts, err := h.gitActivityService.ListDrafts(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list git drafts")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{"data": drafts})
+}
+
+// draftRequest returns the user and draft a draft request is for, writing an error if either is invalid
+func draftRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+	draftID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid draft ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, draftID, true
+}
+
+// UpdateDraft handles PUT /api/git/drafts/{id}
+func (h *GitActivityHandler) UpdateDraft(w http.ResponseWriter, r *http.Request) {
+	userID, draftID, ok := draftRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateGitDraftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	// Drafts become entries, which need both
+	if req.Title == "" || req.Content == "" {
+		httputil.Error(w, http.StatusBadRequest, "title and content are required")
+		return
+	}
+
+	draft, err := h.gitActivityService.UpdateDraft(r.Context(), draftID, userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update git draft")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, draft)
+}
+
+// PublishDraft handles POST /api/git/drafts/{id}/publish, returning the created journal entry
+func (h *GitActivityHandler) PublishDraft(w http.ResponseWriter, r *http.Request) {
+	userID, draftID, ok := draftRequest(w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := h.gitActivityService.PublishDraft(r.Context(), draftID, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to publish git draft")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, entry)
+}
+
+// DismissDraft handles DELETE /api/git/drafts/{id}
+func (h *GitActivityHandler) DismissDraft(w http.ResponseWriter, r *http.Request) {
+	userID, draftID, ok := draftRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.gitActivityService.DismissDraft(r.Context(), draftID, userID); err != nil {
+		httputil.WriteError(w, err, "failed to dismiss git draft")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+)
+
+// GroupChannelHandler handles study group chat channels
+type GroupChannelHandler struct {
+	channelService *service.GroupChannelService
+}
+
+// NewGroupChannelHandler creates a new group channel handler
+func NewGroupChannelHandler(channelService *service.GroupChannelService) *GroupChannelHandler {
+	return &GroupChannelHandler{channelService: channelService}
+}
+
+// List handles GET /api/groups/{id}/channels
+func (h *GroupChannelHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	channels, err := h.channelService.List(r.Context(), groupID, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list channels")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{"data": channels})
+}
+
+// Create handles POST /api/groups/{id}/channels
+func (h *GroupChannelHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.CreateGroupChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	channel, err := h.channelService.Create(r.Context(), groupID, userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create channel")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, channel)
+}
+
+// Update handles PUT /api/groups/{id}/channels/{name}
+func (h *GroupChannelHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateGroupChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	channel, err := h.channelService.Update(r.Context(), groupID, userID, r.PathValue("name"), &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update channel")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, channel)
+}
+
+// Delete handles DELETE /api/groups/{id}/channels/{name}
+func (h *GroupChannelHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.channelService.Delete(r.Context(), groupID, userID, r.PathValue("name")); err != nil {
+		httputil.WriteError(w, err, "failed to delete channel")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
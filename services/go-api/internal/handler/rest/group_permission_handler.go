@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+)
+
+// GroupPermissionHandler handles who in a study group may share entries, post snippets, and
+// start sessions
+type GroupPermissionHandler struct {
+	permissionService *service.GroupPermissionService
+}
+
+// NewGroupPermissionHandler creates a new group permission handler
+func NewGroupPermissionHandler(permissionService *service.GroupPermissionService) *GroupPermissionHandler {
+	return &GroupPermissionHandler{permissionService: permissionService}
+}
+
+// Get handles GET /api/groups/{id}/permissions
+func (h *GroupPermissionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	permissions, err := h.permissionService.Get(r.Context(), groupID, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get group permissions")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, permissions)
+}
+
+// Update handles PUT /api/groups/{id}/permissions
+func (h *GroupPermissionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateGroupPermissionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	permissions, err := h.permissionService.Update(r.Context(), groupID, userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update group permissions")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, permissions)
+}
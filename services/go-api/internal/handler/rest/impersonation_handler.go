@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// ImpersonationHandler handles admins acting as users for support, and users seeing when they were
+type ImpersonationHandler struct {
+	impersonationService *service.ImpersonationService
+	settingsService      *service.SettingsService
+}
+
+// NewImpersonationHandler creates a new impersonation handler
+func NewImpersonationHandler(impersonationService *service.ImpersonationService, settingsService *service.SettingsService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+		settingsService:      settingsService,
+	}
+}
+
+// Start handles POST /api/admin/impersonations, returning a token that acts as the user
+func (h *ImpersonationHandler) Start(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserUUID(r.Context())
+
+	var req domain.StartImpersonationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.impersonationService.Start(r.Context(), adminID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to start impersonation")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, resp)
+}
+
+// List handles GET /api/admin/impersonations, the audit log of impersonation sessions
+func (h *ImpersonationHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := h.page(r)
+
+	imps, total, err := h.impersonationService.List(r.Context(), pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list impersonations")
+		return
+	}
+
+	h.writePage(w, imps, total, page, pageSize)
+}
+
+// End handles DELETE /api/admin/impersonations/{id}, revoking the session's token
+func (h *ImpersonationHandler) End(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid impersonation ID")
+		return
+	}
+
+	if err := h.impersonationService.End(r.Context(), middleware.GetUserUUID(r.Context()), id); err != nil {
+		httputil.WriteError(w, err, "failed to end impersonation")
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// ListMine handles GET /api/users/me/impersonations, the times admins acted as the user
+func (h *ImpersonationHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	page, pageSize := h.page(r)
+
+	imps, total, err := h.impersonationService.ListForUser(r.Context(), userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list impersonations")
+		return
+	}
+
+	h.writePage(w, imps, total, page, pageSize)
+}
+
+// page returns the requested page and page size
+func (h *ImpersonationHandler) page(r *http.Request) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	return page, h.settingsService.PageSize(r.Context(), middleware.GetUserUUID(r.Context()), pageSize)
+}
+
+func (h *ImpersonationHandler) writePage(w http.ResponseWriter, imps []domain.Impersonation, total, page, pageSize int) {
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        imps,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
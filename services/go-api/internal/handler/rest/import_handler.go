@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// ImportHandler handles importing exports from other journaling apps
+type ImportHandler struct {
+	importService *service.ImportService
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(importService *service.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// Create handles POST /api/imports?source=notion|obsidian|dayone. The body is the export file
+// itself; the import runs in the background, so this responds 202 with the queued import.
+func (h *ImportHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, service.MaxImportSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			httputil.Error(w, http.StatusRequestEntityTooLarge, "export is too large; the limit is 50 MB")
+			return
+		}
+		httputil.Error(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+
+	imp, err := h.importService.Start(r.Context(), userID, r.URL.Query().Get("source"), data)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to start import")
+		return
+	}
+
+	httputil.JSON(w, http.StatusAccepted, imp)
+}
+
+// List handles GET /api/imports
+func (h *ImportHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	imports, err := h.importService.List(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list imports")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{"data": imports})
+}
+
+// Get handles GET /api/imports/{id}, which clients poll for an import's progress
+func (h *ImportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid import ID")
+		return
+	}
+
+	imp, err := h.importService.Get(r.Context(), id, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get import")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, imp)
+}
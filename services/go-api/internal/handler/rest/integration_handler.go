@@ -0,0 +1,184 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// maxIntegrationEventBytes bounds Slack and Discord request payloads read into memory
+const maxIntegrationEventBytes = 64 << 10
+
+// IntegrationHandler handles Slack/Discord group integrations: installing the apps, their
+// settings, and the inbound event endpoints the providers call
+type IntegrationHandler struct {
+	integrationService *service.IntegrationService
+}
+
+// NewIntegrationHandler creates a new integration handler
+func NewIntegrationHandler(integrationService *service.IntegrationService) *IntegrationHandler {
+	return &IntegrationHandler{integrationService: integrationService}
+}
+
+// List handles GET /api/groups/{id}/integrations
+func (h *IntegrationHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	integrations, err := h.integrationService.List(r.Context(), groupID, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list integrations")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":      integrations,
+		"available": h.integrationService.Providers(),
+	})
+}
+
+// Install handles POST /api/groups/{id}/integrations/{provider}/install, returning the
+// provider page where the user picks a channel
+func (h *IntegrationHandler) Install(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	url, err := h.integrationService.InstallURL(r.Context(), groupID, userID, r.PathValue("provider"))
+	if err != nil {
+		httputil.WriteError(w, err, "failed to start install")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]string{"url": url})
+}
+
+// Callback handles GET /api/integrations/{provider}/callback, where the provider redirects
+// after install, and sends the user back to the web app
+func (h *IntegrationHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+	query := r.URL.Query()
+
+	var err error
+	if reason := query.Get("error"); reason != "" {
+		// The user cancelled on the provider's page
+		err = errors.New(reason)
+	} else {
+		_, err = h.integrationService.CompleteInstall(r.Context(), provider, query.Get("code"), query.Get("state"))
+		if err != nil {
+			log.Printf("WARN: Failed to complete %s install: %v", provider, err)
+		}
+	}
+
+	http.Redirect(w, r, h.integrationService.ReturnURL(provider, err), http.StatusFound)
+}
+
+// Update handles PUT /api/groups/{id}/integrations/{provider}
+func (h *IntegrationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateIntegrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	integ, err := h.integrationService.Update(r.Context(), groupID, userID, r.PathValue("provider"), &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update integration")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, integ)
+}
+
+// Delete handles DELETE /api/groups/{id}/integrations/{provider}
+func (h *IntegrationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.integrationService.Remove(r.Context(), groupID, userID, r.PathValue("provider")); err != nil {
+		httputil.WriteError(w, err, "failed to remove integration")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SlackEvents handles POST /api/integrations/slack/events from the Slack Events API
+func (h *IntegrationHandler) SlackEvents(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxIntegrationEventBytes))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+	// Slack redelivers events it thinks timed out; the first delivery was already relayed
+	if r.Header.Get("X-Slack-Retry-Num") != "" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	challenge, err := h.integrationService.HandleSlackEvent(r.Context(), body,
+		r.Header.Get("X-Slack-Request-Timestamp"), r.Header.Get("X-Slack-Signature"))
+	if err != nil {
+		httputil.WriteError(w, err, "failed to handle event")
+		return
+	}
+
+	if challenge != "" {
+		httputil.JSON(w, http.StatusOK, map[string]string{"challenge": challenge})
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// DiscordInteractions handles POST /api/integrations/discord/interactions from Discord
+func (h *IntegrationHandler) DiscordInteractions(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxIntegrationEventBytes))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+
+	resp, err := h.integrationService.HandleDiscordInteraction(r.Context(), body,
+		r.Header.Get("X-Signature-Timestamp"), r.Header.Get("X-Signature-Ed25519"))
+	if err != nil {
+		httputil.WriteError(w, err, "failed to handle interaction")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, resp)
+}
+
+// groupRequest reads the caller and the {id} group from a request, writing an error if either is invalid
+func groupRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid group ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, groupID, true
+}
@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// InviteHandler handles the invite codes admins create for invite-only registration
+type InviteHandler struct {
+	inviteService   *service.InviteService
+	settingsService *service.SettingsService
+}
+
+// NewInviteHandler creates a new invite handler
+func NewInviteHandler(inviteService *service.InviteService, settingsService *service.SettingsService) *InviteHandler {
+	return &InviteHandler{
+		inviteService:   inviteService,
+		settingsService: settingsService,
+	}
+}
+
+// Create handles POST /api/admin/invites. The response is the only time the code is shown.
+func (h *InviteHandler) Create(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserUUID(r.Context())
+
+	var req domain.CreateInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	invite, err := h.inviteService.Create(r.Context(), adminID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create invite")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, invite)
+}
+
+// List handles GET /api/admin/invites
+func (h *InviteHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	pageSize = h.settingsService.PageSize(r.Context(), middleware.GetUserUUID(r.Context()), pageSize)
+
+	invites, total, err := h.inviteService.List(r.Context(), pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list invites")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        invites,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// Delete handles DELETE /api/admin/invites/{id}
+func (h *InviteHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid invite ID")
+		return
+	}
+
+	if err := h.inviteService.Delete(r.Context(), id); err != nil {
+		httputil.WriteError(w, err, "failed to delete invite")
+		return
+	}
+
+	httputil.NoContent(w)
+}
@@ -1,10 +1,13 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"devjournal/internal/domain"
 	"devjournal/internal/middleware"
@@ -16,15 +19,21 @@ import (
 
 // JournalHandler handles journal entry endpoints
 type JournalHandler struct {
-	journalService  *service.JournalService
-	progressService *service.ProgressService
+	journalService      *service.JournalService
+	snippetService      *service.SnippetService
+	entrySnippetService *service.EntrySnippetService
+	progressService     *service.ProgressService
+	settingsService     *service.SettingsService
 }
 
 // NewJournalHandler creates a new journal handler
-func NewJournalHandler(journalService *service.JournalService, progressService *service.ProgressService) *JournalHandler {
+func NewJournalHandler(journalService *service.JournalService, snippetService *service.SnippetService, entrySnippetService *service.EntrySnippetService, progressService *service.ProgressService, settingsService *service.SettingsService) *JournalHandler {
 	return &JournalHandler{
-		journalService:  journalService,
-		progressService: progressService,
+		journalService:      journalService,
+		snippetService:      snippetService,
+		entrySnippetService: entrySnippetService,
+		progressService:     progressService,
+		settingsService:     settingsService,
 	}
 }
 
@@ -42,14 +51,19 @@ func (h *JournalHandler) List(w http.ResponseWriter, r *http.Request) {
 	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
 	mood := r.URL.Query().Get("mood")
 	search := r.URL.Query().Get("search")
+	tags := parseTagsParam(r.URL.Query().Get("tags"))
+	match := r.URL.Query().Get("match")
 
-	// Default values
+	if match != "" && match != domain.TagMatchAny && match != domain.TagMatchAll {
+		httputil.Error(w, http.StatusBadRequest, "match must be any or all")
+		return
+	}
+
+	// Default values - an omitted pageSize falls back to the user's preference
 	if page <= 0 {
 		page = 1
 	}
-	if pageSize <= 0 {
-		pageSize = 10
-	}
+	pageSize = h.settingsService.PageSize(r.Context(), userID, pageSize)
 
 	// Convert to limit/offset for internal use
 	limit := pageSize
@@ -61,6 +75,12 @@ func (h *JournalHandler) List(w http.ResponseWriter, r *http.Request) {
 	if search != "" {
 		entries, err = h.journalService.Search(r.Context(), userID, search, limit, offset)
 		total = len(entries)
+	} else if len(tags) == 1 && tags[0] == domain.FilterNone {
+		entries, total, err = h.journalService.ListUntagged(r.Context(), userID, limit, offset)
+	} else if len(tags) > 0 {
+		entries, total, err = h.journalService.ListByTags(r.Context(), userID, tags, match, limit, offset)
+	} else if mood == domain.FilterNone {
+		entries, total, err = h.journalService.ListWithoutMood(r.Context(), userID, limit, offset)
 	} else if mood != "" {
 		entries, err = h.journalService.ListByMood(r.Context(), userID, mood, limit, offset)
 		total = len(entries)
@@ -69,7 +89,7 @@ func (h *JournalHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to list entries")
+		httputil.WriteError(w, err, "failed to list entries")
 		return
 	}
 
@@ -78,16 +98,28 @@ func (h *JournalHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	// Return format matching Angular's PaginatedResponse
 	response := map[string]interface{}{
-		"data":       entries,
-		"total":      total,
-		"page":       page,
-		"pageSize":   pageSize,
-		"totalPages": totalPages,
+		"data":        entries,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  totalPages,
+		"maxPageSize": domain.MaxPageSize,
 	}
 
 	httputil.JSON(w, http.StatusOK, response)
 }
 
+// parseTagsParam splits a comma-separated tags query parameter, dropping blanks
+func parseTagsParam(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
 // Get handles GET /api/entries/{id}
 func (h *JournalHandler) Get(w http.ResponseWriter, r *http.Request) {
 	userIDStr := middleware.GetUserID(r.Context())
@@ -106,7 +138,7 @@ func (h *JournalHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	entry, err := h.journalService.GetByID(r.Context(), entryID, userID)
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to get entry")
+		httputil.WriteError(w, err, "failed to get entry")
 		return
 	}
 	if entry == nil {
@@ -114,6 +146,11 @@ func (h *JournalHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	entry.RelatedSnippets, err = h.entrySnippetService.RelatedSnippets(r.Context(), userID, entry.ID)
+	if err != nil {
+		log.Printf("WARN: Failed to load snippets related to entry %s: %v", entry.ID, err)
+	}
+
 	httputil.JSON(w, http.StatusOK, entry)
 }
 
@@ -140,7 +177,7 @@ func (h *JournalHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	entry, err := h.journalService.Create(r.Context(), userID, &req)
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to create entry")
+		httputil.WriteError(w, err, "failed to create entry")
 		return
 	}
 
@@ -183,13 +220,111 @@ func (h *JournalHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	entry, err := h.journalService.Update(r.Context(), entryID, userID, &req)
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to update entry")
+		httputil.WriteError(w, err, "failed to update entry")
 		return
 	}
 
 	httputil.JSON(w, http.StatusOK, entry)
 }
 
+// ExtractSnippets handles POST /api/entries/{id}/extract-snippets, saving the entry's fenced code
+// blocks as snippets
+func (h *JournalHandler) ExtractSnippets(w http.ResponseWriter, r *http.Request) {
+	userIDStr := middleware.GetUserID(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	entryID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid entry ID")
+		return
+	}
+
+	entry, err := h.journalService.GetByID(r.Context(), entryID, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get entry")
+		return
+	}
+	if entry == nil {
+		httputil.Error(w, http.StatusNotFound, "entry not found")
+		return
+	}
+
+	result, err := h.snippetService.ExtractFromEntry(r.Context(), userIDStr, entry)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to extract snippets")
+		return
+	}
+
+	// Record snippet creation for progress tracking
+	for range result.Created {
+		if err := h.progressService.RecordSnippet(r.Context(), userID); err != nil {
+			log.Printf("WARN: Failed to record snippet for progress: %v", err)
+		}
+	}
+
+	status := http.StatusOK
+	if len(result.Created) > 0 {
+		status = http.StatusCreated
+	}
+	httputil.JSON(w, status, result)
+}
+
+// LinkSnippet handles PUT /api/entries/{id}/snippets/{snippetId}
+func (h *JournalHandler) LinkSnippet(w http.ResponseWriter, r *http.Request) {
+	h.changeSnippetLink(w, r, h.entrySnippetService.Link, "failed to link snippet")
+}
+
+// UnlinkSnippet handles DELETE /api/entries/{id}/snippets/{snippetId}
+func (h *JournalHandler) UnlinkSnippet(w http.ResponseWriter, r *http.Request) {
+	h.changeSnippetLink(w, r, h.entrySnippetService.Unlink, "failed to unlink snippet")
+}
+
+type snippetLinkFunc func(ctx context.Context, userID, entryID uuid.UUID, snippetID string) error
+
+func (h *JournalHandler) changeSnippetLink(w http.ResponseWriter, r *http.Request, change snippetLinkFunc, fallback string) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	entryID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid entry ID")
+		return
+	}
+
+	if err := change(r.Context(), userID, entryID, r.PathValue("snippetId")); err != nil {
+		httputil.WriteError(w, err, fallback)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Export handles GET /api/entries/export
+func (h *JournalHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	export, err := h.journalService.Export(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to export entries")
+		return
+	}
+
+	filename := fmt.Sprintf("devjournal-entries-%s.json", export.ExportedAt.Format("2006-01-02"))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	httputil.JSON(w, http.StatusOK, export)
+}
+
 // Delete handles DELETE /api/entries/{id}
 func (h *JournalHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	userIDStr := middleware.GetUserID(r.Context())
@@ -207,7 +342,7 @@ func (h *JournalHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.journalService.Delete(r.Context(), entryID, userID); err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to delete entry")
+		httputil.WriteError(w, err, "failed to delete entry")
 		return
 	}
 
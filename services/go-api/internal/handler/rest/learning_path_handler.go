@@ -0,0 +1,371 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// LearningPathHandler handles learning path endpoints
+type LearningPathHandler struct {
+	pathService     *service.LearningPathService
+	settingsService *service.SettingsService
+}
+
+// NewLearningPathHandler creates a new learning path handler
+func NewLearningPathHandler(pathService *service.LearningPathService, settingsService *service.SettingsService) *LearningPathHandler {
+	return &LearningPathHandler{
+		pathService:     pathService,
+		settingsService: settingsService,
+	}
+}
+
+// List handles GET /api/paths
+func (h *LearningPathHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), userID, pageSize)
+
+	paths, total, err := h.pathService.List(r.Context(), userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list learning paths")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        paths,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// ListByGroup handles GET /api/groups/{id}/paths
+func (h *LearningPathHandler) ListByGroup(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	paths, err := h.pathService.ListByGroup(r.Context(), userID, groupID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list learning paths")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, paths)
+}
+
+// Create handles POST /api/paths
+func (h *LearningPathHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.LearningPathRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	path, err := h.pathService.Create(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create learning path")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, path)
+}
+
+// Get handles GET /api/paths/{id}
+func (h *LearningPathHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, pathID, ok := h.path(w, r)
+	if !ok {
+		return
+	}
+
+	path, err := h.pathService.Get(r.Context(), userID, pathID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get learning path")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, path)
+}
+
+// Update handles PUT /api/paths/{id}
+func (h *LearningPathHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, pathID, ok := h.path(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.LearningPathRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	path, err := h.pathService.Update(r.Context(), userID, pathID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update learning path")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, path)
+}
+
+// Delete handles DELETE /api/paths/{id}
+func (h *LearningPathHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, pathID, ok := h.path(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.pathService.Delete(r.Context(), userID, pathID); err != nil {
+		httputil.WriteError(w, err, "failed to delete learning path")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// Share handles PUT /api/paths/{id}/share
+func (h *LearningPathHandler) Share(w http.ResponseWriter, r *http.Request) {
+	userID, pathID, ok := h.path(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.SharePathRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.GroupID == uuid.Nil {
+		httputil.Error(w, http.StatusBadRequest, "groupId is required")
+		return
+	}
+
+	path, err := h.pathService.Share(r.Context(), userID, pathID, req.GroupID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to share learning path")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, path)
+}
+
+// Unshare handles DELETE /api/paths/{id}/share
+func (h *LearningPathHandler) Unshare(w http.ResponseWriter, r *http.Request) {
+	userID, pathID, ok := h.path(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.pathService.Unshare(r.Context(), userID, pathID); err != nil {
+		httputil.WriteError(w, err, "failed to unshare learning path")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddTopic handles POST /api/paths/{id}/topics
+func (h *LearningPathHandler) AddTopic(w http.ResponseWriter, r *http.Request) {
+	userID, pathID, ok := h.path(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.TopicRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	topic, err := h.pathService.AddTopic(r.Context(), userID, pathID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to add topic")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, topic)
+}
+
+// ReorderTopics handles PUT /api/paths/{id}/order
+func (h *LearningPathHandler) ReorderTopics(w http.ResponseWriter, r *http.Request) {
+	userID, pathID, ok := h.path(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.ReorderTopicsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	path, err := h.pathService.ReorderTopics(r.Context(), userID, pathID, req.TopicIDs)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to reorder topics")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, path)
+}
+
+// UpdateTopic handles PUT /api/paths/{id}/topics/{topicId}
+func (h *LearningPathHandler) UpdateTopic(w http.ResponseWriter, r *http.Request) {
+	userID, pathID, topicID, ok := h.topic(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.TopicRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	topic, err := h.pathService.UpdateTopic(r.Context(), userID, pathID, topicID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update topic")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, topic)
+}
+
+// DeleteTopic handles DELETE /api/paths/{id}/topics/{topicId}
+func (h *LearningPathHandler) DeleteTopic(w http.ResponseWriter, r *http.Request) {
+	userID, pathID, topicID, ok := h.topic(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.pathService.DeleteTopic(r.Context(), userID, pathID, topicID); err != nil {
+		httputil.WriteError(w, err, "failed to delete topic")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CompleteTopic handles PUT /api/paths/{id}/topics/{topicId}/complete
+func (h *LearningPathHandler) CompleteTopic(w http.ResponseWriter, r *http.Request) {
+	h.setCompleted(w, r, true)
+}
+
+// ReopenTopic handles DELETE /api/paths/{id}/topics/{topicId}/complete
+func (h *LearningPathHandler) ReopenTopic(w http.ResponseWriter, r *http.Request) {
+	h.setCompleted(w, r, false)
+}
+
+func (h *LearningPathHandler) setCompleted(w http.ResponseWriter, r *http.Request, completed bool) {
+	userID, pathID, topicID, ok := h.topic(w, r)
+	if !ok {
+		return
+	}
+
+	path, err := h.pathService.SetCompleted(r.Context(), userID, pathID, topicID, completed)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update topic progress")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, path)
+}
+
+// LinkEntry handles PUT /api/paths/{id}/topics/{topicId}/entries/{entryId}
+func (h *LearningPathHandler) LinkEntry(w http.ResponseWriter, r *http.Request) {
+	h.changeEvidence(w, r, domain.EvidenceEntry, r.PathValue("entryId"), true)
+}
+
+// UnlinkEntry handles DELETE /api/paths/{id}/topics/{topicId}/entries/{entryId}
+func (h *LearningPathHandler) UnlinkEntry(w http.ResponseWriter, r *http.Request) {
+	h.changeEvidence(w, r, domain.EvidenceEntry, r.PathValue("entryId"), false)
+}
+
+// LinkSnippet handles PUT /api/paths/{id}/topics/{topicId}/snippets/{snippetId}
+func (h *LearningPathHandler) LinkSnippet(w http.ResponseWriter, r *http.Request) {
+	h.changeEvidence(w, r, domain.EvidenceSnippet, r.PathValue("snippetId"), true)
+}
+
+// UnlinkSnippet handles DELETE /api/paths/{id}/topics/{topicId}/snippets/{snippetId}
+func (h *LearningPathHandler) UnlinkSnippet(w http.ResponseWriter, r *http.Request) {
+	h.changeEvidence(w, r, domain.EvidenceSnippet, r.PathValue("snippetId"), false)
+}
+
+func (h *LearningPathHandler) changeEvidence(w http.ResponseWriter, r *http.Request, evidenceType, refID string, link bool) {
+	userID, pathID, topicID, ok := h.topic(w, r)
+	if !ok {
+		return
+	}
+
+	var path *domain.LearningPath
+	var err error
+	if link {
+		path, err = h.pathService.LinkEvidence(r.Context(), userID, pathID, topicID, evidenceType, refID)
+	} else {
+		path, err = h.pathService.UnlinkEvidence(r.Context(), userID, pathID, topicID, evidenceType, refID)
+	}
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update topic evidence")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, path)
+}
+
+// path reads the caller and the path ID from the request, writing an error if either is invalid
+func (h *LearningPathHandler) path(w http.ResponseWriter, r *http.Request) (userID, pathID uuid.UUID, ok bool) {
+	userID = middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	pathID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid learning path ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, pathID, true
+}
+
+// topic reads the caller, path ID, and topic ID from the request, writing an error if any is invalid
+func (h *LearningPathHandler) topic(w http.ResponseWriter, r *http.Request) (userID, pathID, topicID uuid.UUID, ok bool) {
+	userID, pathID, ok = h.path(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	topicID, err := uuid.Parse(r.PathValue("topicId"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid topic ID")
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	return userID, pathID, topicID, true
+}
@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// MentionHandler handles listing where the user was @mentioned
+type MentionHandler struct {
+	mentionService  *service.MentionService
+	settingsService *service.SettingsService
+}
+
+// NewMentionHandler creates a new mention handler
+func NewMentionHandler(mentionService *service.MentionService, settingsService *service.SettingsService) *MentionHandler {
+	return &MentionHandler{
+		mentionService:  mentionService,
+		settingsService: settingsService,
+	}
+}
+
+// List handles GET /api/mentions?unread=true
+func (h *MentionHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("pageSize"))
+	unreadOnly := query.Get("unread") == "true"
+
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), userID, pageSize)
+
+	mentions, total, unread, err := h.mentionService.List(r.Context(), userID, unreadOnly, pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list mentions")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        mentions,
+		"unread":      unread,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// MarkRead handles POST /api/mentions/read. An empty body marks every mention read.
+func (h *MentionHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req domain.MarkMentionsReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	updated, err := h.mentionService.MarkRead(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to mark mentions read")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]int{"updated": updated})
+}
@@ -0,0 +1,272 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/flags"
+	"devjournal/internal/handler/websocket"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// ModerationHandler handles chat moderation settings and message reports
+type ModerationHandler struct {
+	moderationService *service.ModerationService
+	settingsService   *service.SettingsService
+	hub               *websocket.Hub
+}
+
+// NewModerationHandler creates a new moderation handler
+func NewModerationHandler(moderationService *service.ModerationService, settingsService *service.SettingsService, hub *websocket.Hub) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+		settingsService:   settingsService,
+		hub:               hub,
+	}
+}
+
+// GetSettings handles GET /api/groups/{id}/moderation
+func (h *ModerationHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	settings, err := h.moderationService.GetSettings(r.Context(), groupID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get moderation settings")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /api/groups/{id}/moderation
+func (h *ModerationHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	var req domain.UpdateModerationSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	settings, err := h.moderationService.UpdateSettings(r.Context(), groupID, userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update moderation settings")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, settings)
+}
+
+// ReportMessage handles POST /api/groups/{id}/messages/{messageId}/report?channel=
+func (h *ModerationHandler) ReportMessage(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	var req domain.ReportMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		httputil.Error(w, http.StatusBadRequest, "reason is required")
+		return
+	}
+
+	// Reports snapshot the message from the hub's recent history
+	message := h.hub.FindMessage(domain.ChannelRoom(groupID, r.URL.Query().Get("channel")), r.PathValue("messageId"))
+	if message == nil || message.Type != "message" {
+		httputil.Error(w, http.StatusNotFound, "message not found")
+		return
+	}
+
+	report, err := h.moderationService.ReportMessage(r.Context(), groupID, userID, message, req.Reason)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to report message")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, report)
+}
+
+// ListReports handles GET /api/admin/moderation/reports
+func (h *ModerationHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	status := r.URL.Query().Get("status")
+
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), middleware.GetUserUUID(r.Context()), pageSize)
+
+	reports, total, err := h.moderationService.ListReports(r.Context(), status, pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list reports")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        reports,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// ResolveReport handles POST /api/admin/moderation/reports/{id}/resolve
+func (h *ModerationHandler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserUUID(r.Context())
+
+	reportID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid report ID")
+		return
+	}
+
+	var req domain.ResolveReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.moderationService.ResolveReport(r.Context(), reportID, adminID, &req); err != nil {
+		httputil.WriteError(w, err, "failed to resolve report")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// ReportSnippet handles POST /api/public/snippets/{id}/report
+func (h *ModerationHandler) ReportSnippet(w http.ResponseWriter, r *http.Request) {
+	if !flags.Enabled(r.Context(), flags.PublicSnippets) {
+		httputil.Error(w, http.StatusNotFound, "public snippets are disabled")
+		return
+	}
+
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req domain.ReportMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		httputil.Error(w, http.StatusBadRequest, "reason is required")
+		return
+	}
+
+	report, err := h.moderationService.ReportSnippet(r.Context(), r.PathValue("id"), userID, req.Reason)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to report snippet")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, report)
+}
+
+// ListContentReports handles GET /api/admin/reports
+func (h *ModerationHandler) ListContentReports(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	status := r.URL.Query().Get("status")
+
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), middleware.GetUserUUID(r.Context()), pageSize)
+
+	reports, total, err := h.moderationService.ListContentReports(r.Context(), status, pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list reports")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        reports,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// ResolveContentReport handles POST /api/admin/reports/{id}/resolve
+func (h *ModerationHandler) ResolveContentReport(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserUUID(r.Context())
+
+	reportID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid report ID")
+		return
+	}
+
+	var req domain.ResolveReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.moderationService.ResolveContentReport(r.Context(), reportID, adminID, &req); err != nil {
+		httputil.WriteError(w, err, "failed to resolve report")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// ListMyWarnings handles GET /api/users/me/warnings
+func (h *ModerationHandler) ListMyWarnings(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	warnings, err := h.moderationService.ListWarnings(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list warnings")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, warnings)
+}
@@ -0,0 +1,148 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// OrganizationHandler handles organization, seat, and org analytics endpoints.
+// Member management is served by WorkspaceHandler since an organization is a workspace.
+type OrganizationHandler struct {
+	orgService *service.OrganizationService
+}
+
+// NewOrganizationHandler creates a new organization handler
+func NewOrganizationHandler(orgService *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{orgService: orgService}
+}
+
+// List handles GET /api/orgs
+func (h *OrganizationHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	orgs, err := h.orgService.List(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list organizations")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, orgs)
+}
+
+// Create handles POST /api/orgs
+func (h *OrganizationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.CreateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	org, err := h.orgService.Create(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create organization")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, org)
+}
+
+// Get handles GET /api/orgs/{id}
+func (h *OrganizationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid organization ID")
+		return
+	}
+
+	org, err := h.orgService.Get(r.Context(), orgID, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get organization")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, org)
+}
+
+// UpdateSeats handles PUT /api/orgs/{id}/seats
+func (h *OrganizationHandler) UpdateSeats(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid organization ID")
+		return
+	}
+
+	var req domain.UpdateSeatsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	org, err := h.orgService.UpdateSeats(r.Context(), orgID, userID, req.SeatLimit)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update seats")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, org)
+}
+
+// Analytics handles GET /api/orgs/{id}/analytics?days=30
+func (h *OrganizationHandler) Analytics(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid organization ID")
+		return
+	}
+
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	if days <= 0 {
+		days = 30
+	}
+	if days > 365 {
+		days = 365
+	}
+
+	analytics, err := h.orgService.Analytics(r.Context(), orgID, userID, days)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get organization analytics")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, analytics)
+}
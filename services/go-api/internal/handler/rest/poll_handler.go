@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// PollHandler handles polls in study group chat
+type PollHandler struct {
+	pollService *service.PollService
+}
+
+// NewPollHandler creates a new poll handler
+func NewPollHandler(pollService *service.PollService) *PollHandler {
+	return &PollHandler{pollService: pollService}
+}
+
+// List handles GET /api/groups/{id}/polls
+func (h *PollHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	page, err := h.pollService.List(r.Context(), groupID, userID, query.Get("channel"), query.Get("before"), limit)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list polls")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, page)
+}
+
+// Create handles POST /api/groups/{id}/polls
+func (h *PollHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.CreatePollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	poll, err := h.pollService.Create(r.Context(), groupID, userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create poll")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, poll)
+}
+
+// Vote handles POST /api/groups/{id}/polls/{pollId}/votes
+func (h *PollHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := groupRequest(w, r)
+	if !ok {
+		return
+	}
+	pollID, err := uuid.Parse(r.PathValue("pollId"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid poll ID")
+		return
+	}
+
+	var req domain.PollVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	poll, err := h.pollService.Vote(r.Context(), groupID, userID, pollID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to vote in poll")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, poll)
+}
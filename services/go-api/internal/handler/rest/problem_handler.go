@@ -0,0 +1,180 @@
+package rest
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// ProblemHandler handles the log of solved LeetCode, Advent of Code, and similar problems
+type ProblemHandler struct {
+	problemService  *service.ProblemService
+	progressService *service.ProgressService
+	settingsService *service.SettingsService
+}
+
+// NewProblemHandler creates a new problem handler
+func NewProblemHandler(problemService *service.ProblemService, progressService *service.ProgressService, settingsService *service.SettingsService) *ProblemHandler {
+	return &ProblemHandler{
+		problemService:  problemService,
+		progressService: progressService,
+		settingsService: settingsService,
+	}
+}
+
+// List handles GET /api/problems?platform=&difficulty=
+func (h *ProblemHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), userID, pageSize)
+
+	q := r.URL.Query()
+	problems, total, err := h.problemService.List(r.Context(), userID, q.Get("platform"), q.Get("difficulty"), pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list problems")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        problems,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// Create handles POST /api/problems
+func (h *ProblemHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.ProblemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	problem, err := h.problemService.Create(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to log problem")
+		return
+	}
+
+	// Solved problems count toward the streak of the day they were solved
+	if err := h.progressService.RecordProblem(r.Context(), userID, problem.SolvedAt); err != nil {
+		log.Printf("WARN: Failed to record problem for progress: %v", err)
+		// Don't fail the request, progress tracking is secondary
+	}
+
+	httputil.JSON(w, http.StatusCreated, problem)
+}
+
+// Get handles GET /api/problems/{id}
+func (h *ProblemHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, problemID, ok := problemRequest(w, r)
+	if !ok {
+		return
+	}
+
+	problem, err := h.problemService.Get(r.Context(), userID, problemID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get problem")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, problem)
+}
+
+// Update handles PUT /api/problems/{id}
+func (h *ProblemHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, problemID, ok := problemRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.ProblemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	problem, err := h.problemService.Update(r.Context(), userID, problemID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update problem")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, problem)
+}
+
+// Delete handles DELETE /api/problems/{id}
+func (h *ProblemHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, problemID, ok := problemRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.problemService.Delete(r.Context(), userID, problemID); err != nil {
+		httputil.WriteError(w, err, "failed to delete problem")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Stats handles GET /api/problems/stats?weeks=
+func (h *ProblemHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	weeks, _ := strconv.Atoi(r.URL.Query().Get("weeks"))
+
+	stats, err := h.problemService.Stats(r.Context(), userID, weeks)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get problem stats")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, stats)
+}
+
+// problemRequest returns the user and problem a request is for, writing an error if either is invalid
+func problemRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	problemID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid problem ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, problemID, true
+}
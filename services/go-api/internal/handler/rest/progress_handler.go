@@ -1,8 +1,12 @@
 package rest
 
 import (
+	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"devjournal/internal/domain"
 	"devjournal/internal/middleware"
 	"devjournal/internal/service"
 	"devjournal/pkg/httputil"
@@ -14,11 +18,19 @@ import (
 // @REVIEW - Phase 7: Progress Tracking REST handler
 type ProgressHandler struct {
 	progressService *service.ProgressService
+	journalService  *service.JournalService
+	pathService     *service.LearningPathService
+	codingService   *service.CodingService
 }
 
 // NewProgressHandler creates a new progress handler
-func NewProgressHandler(progressService *service.ProgressService) *ProgressHandler {
-	return &ProgressHandler{progressService: progressService}
+func NewProgressHandler(progressService *service.ProgressService, journalService *service.JournalService, pathService *service.LearningPathService, codingService *service.CodingService) *ProgressHandler {
+	return &ProgressHandler{
+		progressService: progressService,
+		journalService:  journalService,
+		pathService:     pathService,
+		codingService:   codingService,
+	}
 }
 
 // GetSummary handles GET /api/progress/summary
@@ -32,7 +44,15 @@ func (h *ProgressHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
 
 	summary, err := h.progressService.GetSummary(r.Context(), userID)
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to get progress summary")
+		httputil.WriteError(w, err, "failed to get progress summary")
+		return
+	}
+	if summary.LearningPaths, err = h.pathService.Followed(r.Context(), userID); err != nil {
+		httputil.WriteError(w, err, "failed to get learning path progress")
+		return
+	}
+	if summary.CodingLanguages, err = h.codingService.Languages(r.Context(), userID, 0); err != nil {
+		httputil.WriteError(w, err, "failed to get coding time by language")
 		return
 	}
 
@@ -50,7 +70,7 @@ func (h *ProgressHandler) GetToday(w http.ResponseWriter, r *http.Request) {
 
 	progress, err := h.progressService.GetTodayProgress(r.Context(), userID)
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to get today's progress")
+		httputil.WriteError(w, err, "failed to get today's progress")
 		return
 	}
 
@@ -68,7 +88,7 @@ func (h *ProgressHandler) GetWeekly(w http.ResponseWriter, r *http.Request) {
 
 	progressList, err := h.progressService.GetWeeklyProgress(r.Context(), userID)
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to get weekly progress")
+		httputil.WriteError(w, err, "failed to get weekly progress")
 		return
 	}
 
@@ -89,7 +109,7 @@ func (h *ProgressHandler) GetMonthly(w http.ResponseWriter, r *http.Request) {
 
 	progressList, err := h.progressService.GetMonthlyProgress(r.Context(), userID)
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to get monthly progress")
+		httputil.WriteError(w, err, "failed to get monthly progress")
 		return
 	}
 
@@ -99,6 +119,32 @@ func (h *ProgressHandler) GetMonthly(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Query handles POST /api/progress/query, returning several bucketed series in one call
+func (h *ProgressHandler) Query(w http.ResponseWriter, r *http.Request) {
+	userIDStr := middleware.GetUserID(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.ProgressQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	series, err := h.progressService.Query(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to query progress")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"series": series,
+	})
+}
+
 // GetStreak handles GET /api/progress/streak
 func (h *ProgressHandler) GetStreak(w http.ResponseWriter, r *http.Request) {
 	userIDStr := middleware.GetUserID(r.Context())
@@ -110,7 +156,7 @@ func (h *ProgressHandler) GetStreak(w http.ResponseWriter, r *http.Request) {
 
 	streak, err := h.progressService.GetCurrentStreak(r.Context(), userID)
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to get streak")
+		httputil.WriteError(w, err, "failed to get streak")
 		return
 	}
 
@@ -118,3 +164,105 @@ func (h *ProgressHandler) GetStreak(w http.ResponseWriter, r *http.Request) {
 		"currentStreak": streak,
 	})
 }
+
+// ListAchievements handles GET /api/progress/achievements
+func (h *ProgressHandler) ListAchievements(w http.ResponseWriter, r *http.Request) {
+	userIDStr := middleware.GetUserID(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	achievements, err := h.progressService.ListAchievements(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list achievements")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data": achievements,
+	})
+}
+
+// GetWriting handles GET /api/progress/writing
+func (h *ProgressHandler) GetWriting(w http.ResponseWriter, r *http.Request) {
+	userIDStr := middleware.GetUserID(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	weeks, _ := strconv.Atoi(r.URL.Query().Get("weeks"))
+
+	stats, err := h.journalService.GetWritingStats(r.Context(), userID, weeks)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get writing stats")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, stats)
+}
+
+// GetLanguages handles GET /api/progress/languages, the coding time by language over the last
+// days days (default 30)
+func (h *ProgressHandler) GetLanguages(w http.ResponseWriter, r *http.Request) {
+	userIDStr := middleware.GetUserID(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	days := 30
+	if v := r.URL.Query().Get("days"); v != "" {
+		if days, err = strconv.Atoi(v); err != nil || days < 1 {
+			httputil.Error(w, http.StatusBadRequest, "days must be a positive number")
+			return
+		}
+	}
+
+	languages, err := h.codingService.Languages(r.Context(), userID, days)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get coding time by language")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"languages": languages,
+		"days":      days,
+	})
+}
+
+// Compare handles GET /api/progress/compare?userIds=, comparing the user's consistency with
+// friends who share their progress
+func (h *ProgressHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	userIDStr := middleware.GetUserID(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var userIDs []uuid.UUID
+	for _, v := range strings.Split(r.URL.Query().Get("userIds"), ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		id, err := uuid.Parse(v)
+		if err != nil {
+			httputil.Error(w, http.StatusBadRequest, "invalid user ID")
+			return
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	comparison, err := h.progressService.Compare(r.Context(), userID, userIDs)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to compare progress")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, comparison)
+}
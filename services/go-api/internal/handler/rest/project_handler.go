@@ -0,0 +1,276 @@
+package rest
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// ProjectHandler handles project endpoints
+type ProjectHandler struct {
+	projectService  *service.ProjectService
+	progressService *service.ProgressService
+	settingsService *service.SettingsService
+}
+
+// NewProjectHandler creates a new project handler
+func NewProjectHandler(projectService *service.ProjectService, progressService *service.ProgressService, settingsService *service.SettingsService) *ProjectHandler {
+	return &ProjectHandler{
+		projectService:  projectService,
+		progressService: progressService,
+		settingsService: settingsService,
+	}
+}
+
+// List handles GET /api/projects?status=
+func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), userID, pageSize)
+
+	projects, total, err := h.projectService.List(r.Context(), userID, r.URL.Query().Get("status"), pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list projects")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        projects,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// Create handles POST /api/projects
+func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.ProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	project, err := h.projectService.Create(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create project")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, project)
+}
+
+// Get handles GET /api/projects/{id}
+func (h *ProjectHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, projectID, ok := h.project(w, r)
+	if !ok {
+		return
+	}
+
+	project, err := h.projectService.Get(r.Context(), userID, projectID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get project")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, project)
+}
+
+// Update handles PUT /api/projects/{id}
+func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, projectID, ok := h.project(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.ProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	project, err := h.projectService.Update(r.Context(), userID, projectID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update project")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, project)
+}
+
+// Delete handles DELETE /api/projects/{id}
+func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, projectID, ok := h.project(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.projectService.Delete(r.Context(), userID, projectID); err != nil {
+		httputil.WriteError(w, err, "failed to delete project")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// AttachEntry handles PUT /api/projects/{id}/entries/{entryId}
+func (h *ProjectHandler) AttachEntry(w http.ResponseWriter, r *http.Request) {
+	h.setEntry(w, r, true)
+}
+
+// DetachEntry handles DELETE /api/projects/{id}/entries/{entryId}
+func (h *ProjectHandler) DetachEntry(w http.ResponseWriter, r *http.Request) {
+	h.setEntry(w, r, false)
+}
+
+func (h *ProjectHandler) setEntry(w http.ResponseWriter, r *http.Request, attach bool) {
+	userID, projectID, ok := h.project(w, r)
+	if !ok {
+		return
+	}
+	entryID, err := uuid.Parse(r.PathValue("entryId"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid entry ID")
+		return
+	}
+
+	if err := h.projectService.SetEntry(r.Context(), userID, projectID, entryID, attach); err != nil {
+		httputil.WriteError(w, err, "failed to update project entries")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AttachSnippet handles PUT /api/projects/{id}/snippets/{snippetId}
+func (h *ProjectHandler) AttachSnippet(w http.ResponseWriter, r *http.Request) {
+	h.setSnippet(w, r, true)
+}
+
+// DetachSnippet handles DELETE /api/projects/{id}/snippets/{snippetId}
+func (h *ProjectHandler) DetachSnippet(w http.ResponseWriter, r *http.Request) {
+	h.setSnippet(w, r, false)
+}
+
+func (h *ProjectHandler) setSnippet(w http.ResponseWriter, r *http.Request, attach bool) {
+	userID, projectID, ok := h.project(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.projectService.SetSnippet(r.Context(), userID, projectID, r.PathValue("snippetId"), attach); err != nil {
+		httputil.WriteError(w, err, "failed to update project snippets")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogFocus handles POST /api/projects/{id}/focus
+func (h *ProjectHandler) LogFocus(w http.ResponseWriter, r *http.Request) {
+	userID, projectID, ok := h.project(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.LogFocusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	session, err := h.projectService.LogFocus(r.Context(), userID, projectID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to log focus time")
+		return
+	}
+
+	// Focus time counts toward the day's learning time
+	if err := h.progressService.RecordFocusTime(r.Context(), userID, session.StartedAt, session.Minutes); err != nil {
+		log.Printf("WARN: Failed to record focus time for progress: %v", err)
+	}
+
+	httputil.JSON(w, http.StatusCreated, session)
+}
+
+// DeleteFocus handles DELETE /api/projects/{id}/focus/{sessionId}
+func (h *ProjectHandler) DeleteFocus(w http.ResponseWriter, r *http.Request) {
+	userID, projectID, ok := h.project(w, r)
+	if !ok {
+		return
+	}
+	sessionID, err := uuid.Parse(r.PathValue("sessionId"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid focus session ID")
+		return
+	}
+
+	session, err := h.projectService.DeleteFocus(r.Context(), userID, projectID, sessionID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to delete focus session")
+		return
+	}
+
+	if err := h.progressService.RecordFocusTime(r.Context(), userID, session.StartedAt, -session.Minutes); err != nil {
+		log.Printf("WARN: Failed to remove focus time from progress: %v", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Timeline handles GET /api/projects/{id}/timeline?before=&limit=
+func (h *ProjectHandler) Timeline(w http.ResponseWriter, r *http.Request) {
+	userID, projectID, ok := h.project(w, r)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	timeline, err := h.projectService.Timeline(r.Context(), userID, projectID, r.URL.Query().Get("before"), limit)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to load project timeline")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, timeline)
+}
+
+// project reads the caller and the project ID from the path, writing an error if either is invalid
+func (h *ProjectHandler) project(w http.ResponseWriter, r *http.Request) (userID, projectID uuid.UUID, ok bool) {
+	userID = middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	projectID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid project ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, projectID, true
+}
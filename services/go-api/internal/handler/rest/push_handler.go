@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// PushHandler handles mobile device registration for push notifications
+type PushHandler struct {
+	pushService *service.PushService
+}
+
+// NewPushHandler creates a new push handler
+func NewPushHandler(pushService *service.PushService) *PushHandler {
+	return &PushHandler{pushService: pushService}
+}
+
+// Register handles POST /api/users/me/push-devices
+func (h *PushHandler) Register(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req domain.RegisterPushDeviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	device, err := h.pushService.Register(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to register device")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, device)
+}
+
+// List handles GET /api/users/me/push-devices
+func (h *PushHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	devices, err := h.pushService.List(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list devices")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{"data": devices})
+}
+
+// Unregister handles DELETE /api/users/me/push-devices/{id}
+func (h *PushHandler) Unregister(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid device ID")
+		return
+	}
+
+	if err := h.pushService.Unregister(r.Context(), userID, id); err != nil {
+		httputil.WriteError(w, err, "failed to unregister device")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
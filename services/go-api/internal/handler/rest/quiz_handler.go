@@ -0,0 +1,167 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// QuizHandler handles study group quiz and leaderboard endpoints
+type QuizHandler struct {
+	quizService *service.QuizService
+}
+
+// NewQuizHandler creates a new quiz handler
+func NewQuizHandler(quizService *service.QuizService) *QuizHandler {
+	return &QuizHandler{quizService: quizService}
+}
+
+// List handles GET /api/groups/{id}/quizzes
+func (h *QuizHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := h.group(w, r)
+	if !ok {
+		return
+	}
+
+	quizzes, err := h.quizService.List(r.Context(), userID, groupID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list quizzes")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, quizzes)
+}
+
+// Create handles POST /api/groups/{id}/quizzes
+func (h *QuizHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := h.group(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.QuizRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	quiz, err := h.quizService.Create(r.Context(), userID, groupID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create quiz")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, quiz)
+}
+
+// Get handles GET /api/groups/{id}/quizzes/{quizId}
+func (h *QuizHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := h.group(w, r)
+	if !ok {
+		return
+	}
+
+	quiz, err := h.quizService.Get(r.Context(), userID, groupID, r.PathValue("quizId"))
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get quiz")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, quiz)
+}
+
+// Update handles PUT /api/groups/{id}/quizzes/{quizId}
+func (h *QuizHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := h.group(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.QuizRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	quiz, err := h.quizService.Update(r.Context(), userID, groupID, r.PathValue("quizId"), &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update quiz")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, quiz)
+}
+
+// Delete handles DELETE /api/groups/{id}/quizzes/{quizId}
+func (h *QuizHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := h.group(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.quizService.Delete(r.Context(), userID, groupID, r.PathValue("quizId")); err != nil {
+		httputil.WriteError(w, err, "failed to delete quiz")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// Submit handles POST /api/groups/{id}/quizzes/{quizId}/attempts
+func (h *QuizHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := h.group(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.QuizAttemptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	attempt, err := h.quizService.Submit(r.Context(), userID, groupID, r.PathValue("quizId"), &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to submit quiz")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, attempt)
+}
+
+// Leaderboard handles GET /api/groups/{id}/leaderboard
+func (h *QuizHandler) Leaderboard(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := h.group(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := h.quizService.Leaderboard(r.Context(), userID, groupID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get leaderboard")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, entries)
+}
+
+// group reads the caller and the group ID from the request, writing an error if either is invalid
+func (h *QuizHandler) group(w http.ResponseWriter, r *http.Request) (userID, groupID uuid.UUID, ok bool) {
+	userID = middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid group ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, groupID, true
+}
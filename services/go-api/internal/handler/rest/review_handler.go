@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// ReviewHandler handles spaced-repetition review endpoints
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+}
+
+// NewReviewHandler creates a new review handler
+func NewReviewHandler(reviewService *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewService: reviewService}
+}
+
+// Next handles GET /api/review/next
+func (h *ReviewHandler) Next(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	card, err := h.reviewService.Next(r.Context(), userID, r.URL.Query().Get("type"))
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get next review item")
+		return
+	}
+	if card == nil {
+		// Nothing due and nothing old enough to enroll yet
+		httputil.NoContent(w)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, card)
+}
+
+// Feedback handles POST /api/review/{id}/feedback
+func (h *ReviewHandler) Feedback(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	itemID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid review item ID")
+		return
+	}
+
+	var req domain.ReviewFeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	item, err := h.reviewService.Feedback(r.Context(), userID, itemID, req.Result)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to record review feedback")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, item)
+}
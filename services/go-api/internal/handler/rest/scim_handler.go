@@ -0,0 +1,191 @@
+package rest
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/service"
+	"devjournal/pkg/apperr"
+	"devjournal/pkg/httputil"
+)
+
+// scimContentType is the media type of SCIM requests and responses (RFC 7644 3.1)
+const scimContentType = "application/scim+json"
+
+// SCIMHandler serves the SCIM 2.0 Users endpoint identity providers provision accounts through.
+// It speaks SCIM's own JSON and errors rather than the API's, and authenticates the provider with
+// a bearer token of its own.
+type SCIMHandler struct {
+	scimService *service.SCIMService
+	token       string
+}
+
+// NewSCIMHandler creates a new SCIM handler. SCIM is disabled when token is empty.
+func NewSCIMHandler(scimService *service.SCIMService, token string) *SCIMHandler {
+	return &SCIMHandler{scimService: scimService, token: token}
+}
+
+// Authenticate checks the provider's bearer token before passing requests on
+func (h *SCIMHandler) Authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.token == "" {
+			writeSCIMError(w, http.StatusServiceUnavailable, "", "SCIM provisioning is not configured on the server")
+			return
+		}
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+			writeSCIMError(w, http.StatusUnauthorized, "", "invalid or missing bearer token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// ServiceProviderConfig handles GET /scim/v2/ServiceProviderConfig, which tells providers what
+// this server supports
+func (h *SCIMHandler) ServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	supported := func(ok bool) map[string]bool { return map[string]bool{"supported": ok} }
+	writeSCIM(w, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{domain.SCIMSchemaSPConfig},
+		"patch":          supported(true),
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": 100},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]interface{}{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "The SCIM_TOKEN configured on the server",
+			"primary":     true,
+		}},
+	})
+}
+
+// List handles GET /scim/v2/Users
+func (h *SCIMHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	startIndex, _ := strconv.Atoi(query.Get("startIndex"))
+	count := 100
+	if v, err := strconv.Atoi(query.Get("count")); err == nil {
+		count = v
+	}
+
+	list, err := h.scimService.List(r.Context(), query.Get("filter"), startIndex, count)
+	if err != nil {
+		writeSCIMServiceError(w, err, "failed to list users")
+		return
+	}
+	writeSCIM(w, http.StatusOK, list)
+}
+
+// Get handles GET /scim/v2/Users/{id}
+func (h *SCIMHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user, err := h.scimService.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeSCIMServiceError(w, err, "failed to get user")
+		return
+	}
+	writeSCIM(w, http.StatusOK, user)
+}
+
+// Create handles POST /scim/v2/Users
+func (h *SCIMHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req domain.SCIMUser
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeSCIMError(w, http.StatusBadRequest, "invalidSyntax", "invalid request body")
+		return
+	}
+
+	user, err := h.scimService.Create(r.Context(), &req)
+	if err != nil {
+		writeSCIMServiceError(w, err, "failed to create user")
+		return
+	}
+	w.Header().Set("Location", user.Meta.Location)
+	writeSCIM(w, http.StatusCreated, user)
+}
+
+// Replace handles PUT /scim/v2/Users/{id}
+func (h *SCIMHandler) Replace(w http.ResponseWriter, r *http.Request) {
+	var req domain.SCIMUser
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeSCIMError(w, http.StatusBadRequest, "invalidSyntax", "invalid request body")
+		return
+	}
+
+	user, err := h.scimService.Replace(r.Context(), r.PathValue("id"), &req)
+	if err != nil {
+		writeSCIMServiceError(w, err, "failed to replace user")
+		return
+	}
+	writeSCIM(w, http.StatusOK, user)
+}
+
+// Patch handles PATCH /scim/v2/Users/{id}
+func (h *SCIMHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	var req domain.SCIMPatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeSCIMError(w, http.StatusBadRequest, "invalidSyntax", "invalid request body")
+		return
+	}
+
+	user, err := h.scimService.Patch(r.Context(), r.PathValue("id"), &req)
+	if err != nil {
+		writeSCIMServiceError(w, err, "failed to update user")
+		return
+	}
+	writeSCIM(w, http.StatusOK, user)
+}
+
+// Delete handles DELETE /scim/v2/Users/{id}, which deactivates the user
+func (h *SCIMHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.scimService.Delete(r.Context(), r.PathValue("id")); err != nil {
+		writeSCIMServiceError(w, err, "failed to delete user")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeSCIM(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", scimContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeSCIMError(w http.ResponseWriter, status int, scimType, detail string) {
+	writeSCIM(w, status, domain.SCIMError{
+		Schemas:  []string{domain.SCIMSchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// writeSCIMServiceError writes a service error as a SCIM error, logging internal ones
+func writeSCIMServiceError(w http.ResponseWriter, err error, fallback string) {
+	status := httputil.StatusFor(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("ERROR: SCIM %s: %v", fallback, err)
+		writeSCIMError(w, status, "", fallback)
+		return
+	}
+
+	scimType := ""
+	switch {
+	case errors.Is(err, service.ErrSCIMFilter):
+		scimType = "invalidFilter"
+	case errors.Is(err, apperr.ErrConflict):
+		scimType = "uniqueness"
+	case errors.Is(err, apperr.ErrValidation):
+		scimType = "invalidValue"
+	}
+	writeSCIMError(w, status, scimType, err.Error())
+}
@@ -0,0 +1,176 @@
+package rest
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/flags"
+	"devjournal/internal/ogimage"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// seoCacheAge is how long crawlers and unfurlers may cache the sitemap and page metadata, in seconds
+const seoCacheAge = 3600
+
+// Share images change only when a title, author name, or streak does, so they are cached for a
+// day and served stale for a week while revalidating
+const (
+	shareImageCacheControl = "public, max-age=86400, stale-while-revalidate=604800"
+	shareImageCacheSize    = 512
+)
+
+// SEOHandler serves the sitemap, robots.txt, and metadata and share images of public pages
+type SEOHandler struct {
+	seoService *service.SEOService
+	sitemapURL string
+	images     *ogimage.Cache
+}
+
+// NewSEOHandler creates a new SEO handler. sitemapURL is where crawlers fetch the sitemap.
+func NewSEOHandler(seoService *service.SEOService, sitemapURL string) *SEOHandler {
+	return &SEOHandler{seoService: seoService, sitemapURL: sitemapURL, images: ogimage.NewCache(shareImageCacheSize)}
+}
+
+// Sitemap handles GET /sitemap.xml
+func (h *SEOHandler) Sitemap(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	urls, err := h.seoService.Sitemap(ctx, flags.Enabled(ctx, flags.PublicSnippets), flags.Enabled(ctx, flags.PublicProfiles))
+	if err != nil {
+		log.Printf("ERROR: failed to build sitemap: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	body, err := domain.Sitemap(urls)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", seoCacheAge))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// Robots handles GET /robots.txt, keeping crawlers out of the API and pointing them at the sitemap
+func (h *SEOHandler) Robots(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", seoCacheAge))
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "User-agent: *\nDisallow: /api/\n\nSitemap: %s\n", h.sitemapURL)
+}
+
+// SnippetMeta handles GET /api/public/meta/snippets/{slug}
+func (h *SEOHandler) SnippetMeta(w http.ResponseWriter, r *http.Request) {
+	if !flags.Enabled(r.Context(), flags.PublicSnippets) {
+		httputil.Error(w, http.StatusNotFound, "public snippets are disabled")
+		return
+	}
+
+	meta, err := h.seoService.SnippetMeta(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		httputil.WriteError(w, err, "failed to describe snippet")
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", seoCacheAge))
+	httputil.JSON(w, http.StatusOK, meta)
+}
+
+// ProfileMeta handles GET /api/public/meta/users/{userId}
+func (h *SEOHandler) ProfileMeta(w http.ResponseWriter, r *http.Request) {
+	if !flags.Enabled(r.Context(), flags.PublicProfiles) {
+		httputil.Error(w, http.StatusNotFound, "public profiles are disabled")
+		return
+	}
+	userID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	meta, err := h.seoService.ProfileMeta(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to describe profile")
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", seoCacheAge))
+	httputil.JSON(w, http.StatusOK, meta)
+}
+
+// SnippetImage handles GET /api/public/og/snippets/{slug}, where the slug ends in .png or .svg
+func (h *SEOHandler) SnippetImage(w http.ResponseWriter, r *http.Request) {
+	if !flags.Enabled(r.Context(), flags.PublicSnippets) {
+		httputil.Error(w, http.StatusNotFound, "public snippets are disabled")
+		return
+	}
+
+	slug, format := imageFormat(r.PathValue("slug"))
+	card, err := h.seoService.SnippetCard(r.Context(), slug)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to render snippet image")
+		return
+	}
+	h.writeImage(w, r, card, format)
+}
+
+// EntryImage handles GET /api/public/og/entries/{id}, where the ID ends in .png or .svg.
+// Public entries are shown on profiles, so they follow the public profiles flag.
+func (h *SEOHandler) EntryImage(w http.ResponseWriter, r *http.Request) {
+	if !flags.Enabled(r.Context(), flags.PublicProfiles) {
+		httputil.Error(w, http.StatusNotFound, "public profiles are disabled")
+		return
+	}
+
+	id, format := imageFormat(r.PathValue("id"))
+	entryID, err := uuid.Parse(id)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid entry ID")
+		return
+	}
+	card, err := h.seoService.EntryCard(r.Context(), entryID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to render entry image")
+		return
+	}
+	h.writeImage(w, r, card, format)
+}
+
+// writeImage renders a share card, answering 304 when the client already has it
+func (h *SEOHandler) writeImage(w http.ResponseWriter, r *http.Request, card *ogimage.Card, format string) {
+	img, etag, err := h.images.Render(card, format)
+	if err != nil {
+		log.Printf("ERROR: failed to render share image: %v", err)
+		httputil.Error(w, http.StatusInternalServerError, "failed to render image")
+		return
+	}
+
+	w.Header().Set("Cache-Control", shareImageCacheControl)
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	contentType := "image/png"
+	if format == ogimage.SVG {
+		contentType = "image/svg+xml"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(img)
+}
+
+// imageFormat splits the image format off the last path segment, defaulting to PNG
+func imageFormat(name string) (string, string) {
+	if base, ok := strings.CutSuffix(name, ".svg"); ok {
+		return base, ogimage.SVG
+	}
+	return strings.TrimSuffix(name, ".png"), ogimage.PNG
+}
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// SettingsHandler handles user settings endpoints
+type SettingsHandler struct {
+	settingsService *service.SettingsService
+}
+
+// NewSettingsHandler creates a new settings handler
+func NewSettingsHandler(settingsService *service.SettingsService) *SettingsHandler {
+	return &SettingsHandler{settingsService: settingsService}
+}
+
+// Get handles GET /api/users/me/settings
+func (h *SettingsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	settings, err := h.settingsService.Get(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get settings")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, settings)
+}
+
+// Update handles PUT /api/users/me/settings
+func (h *SettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.UpdateUserSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	settings, err := h.settingsService.Update(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update settings")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, settings)
+}
@@ -0,0 +1,113 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// SiteHandler handles publishing public entries to the user's own static site
+type SiteHandler struct {
+	sitePublishService *service.SitePublishService
+	settingsService    *service.SettingsService
+}
+
+// NewSiteHandler creates a new site handler
+func NewSiteHandler(sitePublishService *service.SitePublishService, settingsService *service.SettingsService) *SiteHandler {
+	return &SiteHandler{sitePublishService: sitePublishService, settingsService: settingsService}
+}
+
+// GetPublisher handles GET /api/site/publisher
+func (h *SiteHandler) GetPublisher(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	p, err := h.sitePublishService.Get(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get site publisher")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, p)
+}
+
+// UpdatePublisher handles PUT /api/site/publisher, setting up where newly public entries are sent
+func (h *SiteHandler) UpdatePublisher(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.UpdateSitePublisherRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	p, err := h.sitePublishService.Update(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to update site publisher")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, p)
+}
+
+// DeletePublisher handles DELETE /api/site/publisher
+func (h *SiteHandler) DeletePublisher(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	if err := h.sitePublishService.Delete(r.Context(), userID); err != nil {
+		httputil.WriteError(w, err, "failed to delete site publisher")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /api/site/deliveries, the entries sent or waiting to be sent
+func (h *SiteHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), userID, pageSize)
+
+	deliveries, total, err := h.sitePublishService.Deliveries(r.Context(), userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list site deliveries")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        deliveries,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// SnippetCommentHandler handles line comment endpoints on snippets
+type SnippetCommentHandler struct {
+	commentService *service.SnippetCommentService
+}
+
+// NewSnippetCommentHandler creates a new snippet comment handler
+func NewSnippetCommentHandler(commentService *service.SnippetCommentService) *SnippetCommentHandler {
+	return &SnippetCommentHandler{commentService: commentService}
+}
+
+// List handles GET /api/snippets/{id}/comments?resolved=true. Guests see the comments on public snippets.
+func (h *SnippetCommentHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil && !middleware.IsGuest(r.Context()) {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	includeResolved := r.URL.Query().Get("resolved") == "true"
+	comments, err := h.commentService.List(r.Context(), userID, r.PathValue("id"), includeResolved)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list comments")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, comments)
+}
+
+// Create handles POST /api/snippets/{id}/comments
+func (h *SnippetCommentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.SnippetCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	comment, err := h.commentService.Create(r.Context(), userID, r.PathValue("id"), &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to add comment")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, comment)
+}
+
+// Resolve handles PUT and DELETE /api/snippets/{id}/comments/{commentId}/resolved
+func (h *SnippetCommentHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+	commentID, err := uuid.Parse(r.PathValue("commentId"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid comment ID")
+		return
+	}
+
+	comment, err := h.commentService.Resolve(r.Context(), userID, r.PathValue("id"), commentID, r.Method == http.MethodPut)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to resolve comment")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, comment)
+}
@@ -1,13 +1,20 @@
 package rest
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"devjournal/internal/domain"
+	"devjournal/internal/flags"
 	"devjournal/internal/middleware"
 	"devjournal/internal/service"
 	"devjournal/pkg/httputil"
@@ -17,15 +24,21 @@ import (
 
 // SnippetHandler handles code snippet endpoints
 type SnippetHandler struct {
-	snippetService  *service.SnippetService
-	progressService *service.ProgressService
+	snippetService      *service.SnippetService
+	entrySnippetService *service.EntrySnippetService
+	progressService     *service.ProgressService
+	settingsService     *service.SettingsService
+	playgroundService   *service.PlaygroundService
 }
 
 // NewSnippetHandler creates a new snippet handler
-func NewSnippetHandler(snippetService *service.SnippetService, progressService *service.ProgressService) *SnippetHandler {
+func NewSnippetHandler(snippetService *service.SnippetService, entrySnippetService *service.EntrySnippetService, progressService *service.ProgressService, settingsService *service.SettingsService, playgroundService *service.PlaygroundService) *SnippetHandler {
 	return &SnippetHandler{
-		snippetService:  snippetService,
-		progressService: progressService,
+		snippetService:      snippetService,
+		entrySnippetService: entrySnippetService,
+		progressService:     progressService,
+		settingsService:     settingsService,
+		playgroundService:   playgroundService,
 	}
 }
 
@@ -40,43 +53,27 @@ func (h *SnippetHandler) List(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters - support both page/pageSize and limit/offset
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
-	language := r.URL.Query().Get("language")
-	tagsParam := r.URL.Query().Get("tags")
-	search := r.URL.Query().Get("search")
 
-	// Default values
+	filter, err := parseSnippetFilter(r)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Default values - an omitted pageSize falls back to the user's preference
 	if page <= 0 {
 		page = 1
 	}
-	if pageSize <= 0 {
-		pageSize = 10
-	}
+	pageSize = h.settingsService.PageSize(r.Context(), middleware.GetUserUUID(r.Context()), pageSize)
 
 	// Convert to limit/offset for internal use
 	limit := int64(pageSize)
 	offset := int64((page - 1) * pageSize)
 
-	var snippets []domain.Snippet
-	var total int64
-	var err error
-
-	if search != "" {
-		snippets, err = h.snippetService.Search(r.Context(), userID, search, limit, offset)
-		total = int64(len(snippets))
-	} else if tagsParam != "" {
-		tags := strings.Split(tagsParam, ",")
-		snippets, err = h.snippetService.ListByTags(r.Context(), userID, tags, limit, offset)
-		total = int64(len(snippets))
-	} else if language != "" {
-		snippets, err = h.snippetService.ListByLanguage(r.Context(), userID, language, limit, offset)
-		total = int64(len(snippets))
-	} else {
-		snippets, total, err = h.snippetService.List(r.Context(), userID, limit, offset)
-	}
-
+	// search, tags, language, dependency, visibility, and date range all combine into one query
+	snippets, total, err := h.snippetService.ListFiltered(r.Context(), userID, filter, limit, offset)
 	if err != nil {
-		log.Printf("ERROR: Failed to list snippets for user %s: %v", userID, err)
-		httputil.Error(w, http.StatusInternalServerError, "failed to list snippets")
+		httputil.WriteError(w, err, "failed to list snippets")
 		return
 	}
 
@@ -85,20 +82,292 @@ func (h *SnippetHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	// Return format matching Angular's PaginatedResponse
 	response := map[string]interface{}{
-		"data":       snippets,
-		"total":      total,
-		"page":       page,
-		"pageSize":   pageSize,
-		"totalPages": totalPages,
+		"data":        snippets,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  totalPages,
+		"maxPageSize": domain.MaxPageSize,
 	}
 
 	httputil.JSON(w, http.StatusOK, response)
 }
 
+// parseSnippetFilter builds a SnippetFilter from the list query parameters.
+// from/to accept RFC 3339 timestamps or YYYY-MM-DD dates; a date-only "to" is inclusive.
+func parseSnippetFilter(r *http.Request) (*domain.SnippetFilter, error) {
+	q := r.URL.Query()
+	filter := &domain.SnippetFilter{
+		Search:     strings.TrimSpace(q.Get("search")),
+		Tags:       parseTagsParam(q.Get("tags")),
+		Language:   q.Get("language"),
+		Dependency: strings.TrimSpace(q.Get("dependency")),
+		Visibility: q.Get("visibility"),
+	}
+
+	switch filter.Visibility {
+	case "", domain.SnippetVisibilityPublic, domain.SnippetVisibilityPrivate:
+	default:
+		return nil, fmt.Errorf("visibility must be public or private")
+	}
+
+	if v := q.Get("from"); v != "" {
+		from, _, err := parseDateParam(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date")
+		}
+		filter.From = &from
+	}
+	if v := q.Get("to"); v != "" {
+		to, dateOnly, err := parseDateParam(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date")
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	return filter, nil
+}
+
+// parseDateParam parses an RFC 3339 timestamp or a YYYY-MM-DD date (reported via dateOnly)
+func parseDateParam(value string) (t time.Time, dateOnly bool, err error) {
+	if t, err = time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), false, nil
+	}
+	if t, err = time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, err
+}
+
+// editorExportFiles names the file each editor export format downloads as
+var editorExportFiles = map[string]struct{ name, contentType string }{
+	domain.SnippetExportVSCode:    {"devjournal.code-snippets", "application/json"},
+	domain.SnippetExportJetBrains: {"DevJournal.xml", "application/xml"},
+}
+
+// Export handles GET /api/snippets/export?format=vscode|jetbrains, downloading the user's snippets
+// as an editor snippets file. The list filters narrow what is exported.
+func (h *SnippetHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	filter, err := parseSnippetFilter(r)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	body, err := h.snippetService.ExportEditor(r.Context(), userID, format, filter)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to export snippets")
+		return
+	}
+
+	file := editorExportFiles[format]
+	w.Header().Set("Content-Type", file.contentType+"; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.name}))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// Stats handles GET /api/snippets/stats
+func (h *SnippetHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	groupBy := r.URL.Query().Get("groupBy")
+	if groupBy != "" && groupBy != "week" && groupBy != "month" {
+		httputil.Error(w, http.StatusBadRequest, "groupBy must be week or month")
+		return
+	}
+	months, _ := strconv.Atoi(r.URL.Query().Get("months"))
+
+	stats, err := h.snippetService.GetCodeStats(r.Context(), userID, groupBy, months)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get snippet stats")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, stats)
+}
+
+// LanguageStats handles GET /api/snippets/languages
+// Without an interval it returns lifetime counts; with ?interval=month&range=1y it returns time buckets.
+func (h *SnippetHandler) LanguageStats(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	interval := r.URL.Query().Get("interval")
+	rangeParam := r.URL.Query().Get("range")
+
+	if interval == "" && rangeParam == "" {
+		stats, err := h.snippetService.GetLanguageStats(r.Context(), userID)
+		if err != nil {
+			httputil.WriteError(w, err, "failed to get language stats")
+			return
+		}
+		httputil.JSON(w, http.StatusOK, map[string]interface{}{"counts": stats})
+		return
+	}
+
+	buckets, err := h.snippetService.GetLanguageStatsOverTime(r.Context(), userID, interval, rangeParam)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get language stats")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{"buckets": buckets})
+}
+
+// DependencyStats handles GET /api/snippets/dependencies?limit=, the packages the user's
+// snippets import most
+func (h *SnippetHandler) DependencyStats(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	stats, err := h.snippetService.GetDependencyStats(r.Context(), userID, limit)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get dependency stats")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{"data": stats})
+}
+
+// Trending handles GET /api/public/snippets/trending (no auth required)
+func (h *SnippetHandler) Trending(w http.ResponseWriter, r *http.Request) {
+	if !flags.Enabled(r.Context(), flags.PublicSnippets) {
+		httputil.Error(w, http.StatusNotFound, "public snippets are disabled")
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), uuid.Nil, pageSize)
+
+	snippets, err := h.snippetService.ListTrending(r.Context(), int64(pageSize), int64((page-1)*pageSize))
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list trending snippets")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":     snippets,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// Raw handles GET /api/public/snippets/{slug}/raw, serving a public snippet's code as-is:
+// the file named by ?file=, or the first file
+func (h *SnippetHandler) Raw(w http.ResponseWriter, r *http.Request) {
+	h.serveCode(w, r, false)
+}
+
+// Download handles GET /api/public/snippets/{slug}/download, serving a public snippet's code as a file.
+// Without ?file=, a snippet with several files downloads as a zip of all of them.
+func (h *SnippetHandler) Download(w http.ResponseWriter, r *http.Request) {
+	h.serveCode(w, r, true)
+}
+
+// serveCode writes a public snippet's code with the content type of its language. The sandbox CSP
+// keeps HTML and SVG snippets from running scripts on the API's origin.
+func (h *SnippetHandler) serveCode(w http.ResponseWriter, r *http.Request, attachment bool) {
+	if !flags.Enabled(r.Context(), flags.PublicSnippets) {
+		httputil.Error(w, http.StatusNotFound, "public snippets are disabled")
+		return
+	}
+
+	snippet, err := h.snippetService.GetPublic(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get snippet")
+		return
+	}
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	name := r.URL.Query().Get("file")
+	if name == "" && attachment && len(snippet.Files) > 1 {
+		h.serveZip(w, r, snippet)
+		return
+	}
+
+	if name == "" && len(snippet.Files) > 0 {
+		name = snippet.Files[0].Name
+	}
+	file := snippet.File(name)
+	if file == nil {
+		httputil.Error(w, http.StatusNotFound, "file not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", domain.SnippetContentType(file.Language))
+	if attachment {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": file.Name,
+		}))
+	}
+	http.ServeContent(w, r, "", snippet.UpdatedAt, strings.NewReader(file.Code))
+}
+
+// serveZip writes all of a public snippet's files as a zip named after its slug
+func (h *SnippetHandler) serveZip(w http.ResponseWriter, r *http.Request, snippet *domain.Snippet) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range snippet.Files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.Name, Method: zip.Deflate, Modified: snippet.UpdatedAt})
+		if err == nil {
+			_, err = io.WriteString(fw, f.Code)
+		}
+		if err != nil {
+			httputil.Error(w, http.StatusInternalServerError, "failed to build archive")
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "failed to build archive")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": domain.SnippetSlug(snippet) + ".zip",
+	}))
+	http.ServeContent(w, r, "", snippet.UpdatedAt, bytes.NewReader(buf.Bytes()))
+}
+
 // Get handles GET /api/snippets/{id}
 func (h *SnippetHandler) Get(w http.ResponseWriter, r *http.Request) {
+	// Guests have no user ID, so only public snippets are found
 	userID := middleware.GetUserID(r.Context())
-	if userID == "" {
+	if userID == "" && !middleware.IsGuest(r.Context()) {
 		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
 		return
 	}
@@ -111,7 +380,7 @@ func (h *SnippetHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	snippet, err := h.snippetService.GetByID(r.Context(), snippetID, userID)
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to get snippet")
+		httputil.WriteError(w, err, "failed to get snippet")
 		return
 	}
 	if snippet == nil {
@@ -119,6 +388,17 @@ func (h *SnippetHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Only the owner sees which of their entries link to the snippet
+	if userUUID, err := uuid.Parse(userID); err == nil && snippet.UserID == userID {
+		snippet.RelatedEntries, err = h.entrySnippetService.RelatedEntries(r.Context(), userUUID, snippet.ID)
+		if err != nil {
+			log.Printf("WARN: Failed to load entries related to snippet %s: %v", snippet.ID, err)
+		}
+	}
+
+	// Lists carry the cached link; fetching a snippet makes one if its code has none yet
+	h.playgroundService.Attach(r.Context(), snippet)
+
 	httputil.JSON(w, http.StatusOK, snippet)
 }
 
@@ -136,16 +416,15 @@ func (h *SnippetHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate request
-	if req.Title == "" || req.Code == "" || req.Language == "" {
-		httputil.Error(w, http.StatusBadRequest, "title, code, and language are required")
+	// Validate request; files take the place of code and language
+	if req.Title == "" || (len(req.Files) == 0 && (req.Code == "" || req.Language == "")) {
+		httputil.Error(w, http.StatusBadRequest, "title, and code and language or files, are required")
 		return
 	}
 
 	snippet, err := h.snippetService.Create(r.Context(), userID, &req)
 	if err != nil {
-		log.Printf("ERROR: Failed to create snippet for user %s: %v", userID, err)
-		httputil.Error(w, http.StatusInternalServerError, "failed to create snippet")
+		httputil.WriteError(w, err, "failed to create snippet")
 		return
 	}
 
@@ -180,15 +459,15 @@ func (h *SnippetHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate request
-	if req.Title == "" || req.Code == "" || req.Language == "" {
-		httputil.Error(w, http.StatusBadRequest, "title, code, and language are required")
+	// Validate request; files take the place of code and language
+	if req.Title == "" || (len(req.Files) == 0 && (req.Code == "" || req.Language == "")) {
+		httputil.Error(w, http.StatusBadRequest, "title, and code and language or files, are required")
 		return
 	}
 
 	snippet, err := h.snippetService.Update(r.Context(), snippetID, userID, &req)
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to update snippet")
+		httputil.WriteError(w, err, "failed to update snippet")
 		return
 	}
 
@@ -210,9 +489,53 @@ func (h *SnippetHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.snippetService.Delete(r.Context(), snippetID, userID); err != nil {
-		httputil.Error(w, http.StatusInternalServerError, "failed to delete snippet")
+		httputil.WriteError(w, err, "failed to delete snippet")
 		return
 	}
 
 	httputil.JSON(w, http.StatusOK, map[string]bool{"success": true})
 }
+
+// Merge handles POST /api/snippets/merge
+func (h *SnippetHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.MergeSnippetsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.TargetID == "" || req.SourceID == "" {
+		httputil.Error(w, http.StatusBadRequest, "targetId and sourceId are required")
+		return
+	}
+
+	snippet, err := h.snippetService.Merge(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to merge snippets")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, snippet)
+}
+
+// Scan handles POST /api/snippets/{id}/scan
+func (h *SnippetHandler) Scan(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	result, err := h.snippetService.Scan(r.Context(), r.PathValue("id"), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to scan snippet")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, result)
+}
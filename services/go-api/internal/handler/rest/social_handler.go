@@ -0,0 +1,183 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/flags"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// SocialHandler handles public profiles, follows, and the feed
+type SocialHandler struct {
+	socialService   *service.SocialService
+	settingsService *service.SettingsService
+}
+
+// NewSocialHandler creates a new social handler
+func NewSocialHandler(socialService *service.SocialService, settingsService *service.SettingsService) *SocialHandler {
+	return &SocialHandler{
+		socialService:   socialService,
+		settingsService: settingsService,
+	}
+}
+
+// Feed handles GET /api/feed?before=&limit=
+func (h *SocialHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	if !flags.Enabled(r.Context(), flags.PublicProfiles) {
+		httputil.Error(w, http.StatusNotFound, "public profiles are disabled")
+		return
+	}
+
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	feed, err := h.socialService.Feed(r.Context(), userID, r.URL.Query().Get("before"), limit)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to load feed")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, feed)
+}
+
+// Profile handles GET /api/users/{userId}
+func (h *SocialHandler) Profile(w http.ResponseWriter, r *http.Request) {
+	viewerID, userID, ok := h.users(w, r)
+	if !ok {
+		return
+	}
+
+	profile, err := h.socialService.Profile(r.Context(), viewerID, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get profile")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, profile)
+}
+
+// Activity handles GET /api/users/{userId}/activity?before=&limit=
+func (h *SocialHandler) Activity(w http.ResponseWriter, r *http.Request) {
+	_, userID, ok := h.users(w, r)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	activity, err := h.socialService.Activity(r.Context(), userID, r.URL.Query().Get("before"), limit)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to load activity")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, activity)
+}
+
+// Follow handles PUT /api/users/{userId}/follow
+func (h *SocialHandler) Follow(w http.ResponseWriter, r *http.Request) {
+	followerID, userID, ok := h.users(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.socialService.Follow(r.Context(), followerID, userID); err != nil {
+		httputil.WriteError(w, err, "failed to follow user")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Unfollow handles DELETE /api/users/{userId}/follow
+func (h *SocialHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	followerID, userID, ok := h.users(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.socialService.Unfollow(r.Context(), followerID, userID); err != nil {
+		httputil.WriteError(w, err, "failed to unfollow user")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Followers handles GET /api/users/{userId}/followers
+func (h *SocialHandler) Followers(w http.ResponseWriter, r *http.Request) {
+	h.listUsers(w, r, h.socialService.Followers, "failed to list followers")
+}
+
+// Following handles GET /api/users/{userId}/following
+func (h *SocialHandler) Following(w http.ResponseWriter, r *http.Request) {
+	h.listUsers(w, r, h.socialService.Following, "failed to list followed users")
+}
+
+type listUsersFunc func(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.UserSummary, int, error)
+
+func (h *SocialHandler) listUsers(w http.ResponseWriter, r *http.Request, list listUsersFunc, fallback string) {
+	viewerID, userID, ok := h.users(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("pageSize"))
+
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), viewerID, pageSize)
+
+	users, total, err := list(r.Context(), userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, fallback)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        users,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// users returns the requesting user and the user in the path, where "me" means the requesting user
+func (h *SocialHandler) users(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	if !flags.Enabled(r.Context(), flags.PublicProfiles) {
+		httputil.Error(w, http.StatusNotFound, "public profiles are disabled")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	// Guests have no user ID of their own, so they can't ask for "me"
+	viewerID := middleware.GetUserUUID(r.Context())
+	if viewerID == uuid.Nil && (!middleware.IsGuest(r.Context()) || r.PathValue("userId") == "me") {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if r.PathValue("userId") == "me" {
+		return viewerID, viewerID, true
+	}
+	userID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid user ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return viewerID, userID, true
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+)
+
+// ssoStateCookie holds the state of a sign-in in progress, tying the callback to the browser that
+// started it so nobody can sign someone else in to their own account
+const ssoStateCookie = "devjournal_sso_state"
+
+// SSOHandler handles single sign-on through an OpenID Connect provider
+type SSOHandler struct {
+	ssoService *service.SSOService
+}
+
+// NewSSOHandler creates a new single sign-on handler
+func NewSSOHandler(ssoService *service.SSOService) *SSOHandler {
+	return &SSOHandler{ssoService: ssoService}
+}
+
+// Status handles GET /api/auth/oidc, so the login page knows whether to offer single sign-on
+func (h *SSOHandler) Status(w http.ResponseWriter, r *http.Request) {
+	httputil.JSON(w, http.StatusOK, map[string]bool{"enabled": h.ssoService.Enabled()})
+}
+
+// Login handles GET /api/auth/oidc/login, redirecting the browser to the provider's sign-in page
+func (h *SSOHandler) Login(w http.ResponseWriter, r *http.Request) {
+	url, state, err := h.ssoService.LoginURL(r.Context())
+	if err != nil {
+		httputil.WriteError(w, err, "failed to start sign-in")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookie,
+		Value:    state,
+		Path:     "/api",
+		MaxAge:   int(service.SSOStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, url, http.StatusFound)
+}
+
+// Callback handles GET /api/auth/oidc/callback, where the provider redirects after sign-in. The
+// browser is sent on to the web app with a token and refresh token, or with the reason sign-in
+// failed.
+func (h *SSOHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	http.SetCookie(w, &http.Cookie{Name: ssoStateCookie, Path: "/api", MaxAge: -1})
+
+	var token, refreshToken string
+	var err error
+	if reason := query.Get("error"); reason != "" {
+		// The user cancelled, or the provider refused them
+		err = errors.New(reason)
+	} else if cookie, cerr := r.Cookie(ssoStateCookie); cerr != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(query.Get("state"))) != 1 {
+		err = service.ErrInvalidSSOState
+	} else {
+		_, token, refreshToken, err = h.ssoService.CompleteLogin(r.Context(), query.Get("code"), query.Get("state"))
+	}
+	if err != nil {
+		log.Printf("WARN: Failed to complete single sign-on: %v", err)
+	}
+
+	http.Redirect(w, r, h.ssoService.ReturnURL(token, refreshToken, err), http.StatusFound)
+}
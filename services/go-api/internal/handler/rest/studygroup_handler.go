@@ -2,9 +2,13 @@ package rest
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
+	"devjournal/internal/domain"
 	"devjournal/internal/middleware"
 	"devjournal/internal/service"
 	"devjournal/pkg/httputil"
@@ -12,14 +16,46 @@ import (
 	"github.com/google/uuid"
 )
 
+// GroupMemberResponse is a study group member's public profile. It is built field by field from
+// domain.MemberProfile, so private fields added to users never reach other members.
+type GroupMemberResponse struct {
+	UserID      string    `json:"userId"`
+	DisplayName string    `json:"displayName"`
+	Handle      string    `json:"handle"`
+	AvatarURL   string    `json:"avatarUrl,omitempty"`
+	StreakBadge string    `json:"streakBadge,omitempty"` // the achievement kind of their longest streak milestone
+	Role        string    `json:"role"`
+	JoinedAt    time.Time `json:"joinedAt"`
+}
+
+// newGroupMemberResponse returns the public profile of a member
+func newGroupMemberResponse(p *domain.MemberProfile) GroupMemberResponse {
+	resp := GroupMemberResponse{
+		UserID:      p.UserID.String(),
+		DisplayName: p.DisplayName,
+		Handle:      domain.MentionHandle(p.DisplayName),
+		AvatarURL:   p.AvatarURL,
+		Role:        p.Role,
+		JoinedAt:    p.JoinedAt,
+	}
+	if p.StreakMilestone > 0 {
+		resp.StreakBadge = domain.StreakAchievement(p.StreakMilestone)
+	}
+	return resp
+}
+
 // StudyGroupHandler handles study group HTTP requests
 type StudyGroupHandler struct {
-	groupService *service.StudyGroupService
+	groupService    *service.StudyGroupService
+	settingsService *service.SettingsService
 }
 
 // NewStudyGroupHandler creates a new study group handler
-func NewStudyGroupHandler(groupService *service.StudyGroupService) *StudyGroupHandler {
-	return &StudyGroupHandler{groupService: groupService}
+func NewStudyGroupHandler(groupService *service.StudyGroupService, settingsService *service.SettingsService) *StudyGroupHandler {
+	return &StudyGroupHandler{
+		groupService:    groupService,
+		settingsService: settingsService,
+	}
 }
 
 // List returns all study groups for the current user
@@ -32,8 +68,7 @@ func (h *StudyGroupHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	groups, err := h.groupService.ListByUser(r.Context(), userID)
 	if err != nil {
-		log.Printf("ERROR: ListByUser failed for user %s: %v", userID, err)
-		httputil.Error(w, http.StatusInternalServerError, err.Error())
+		httputil.WriteError(w, err, "failed to list study groups")
 		return
 	}
 
@@ -42,20 +77,31 @@ func (h *StudyGroupHandler) List(w http.ResponseWriter, r *http.Request) {
 
 // ListPublic returns all public study groups for discovery
 func (h *StudyGroupHandler) ListPublic(w http.ResponseWriter, r *http.Request) {
-	groups, total, err := h.groupService.ListPublic(r.Context(), 50, 0)
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), middleware.GetUserUUID(r.Context()), pageSize)
+
+	groups, total, err := h.groupService.ListPublic(r.Context(), pageSize, (page-1)*pageSize)
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, err.Error())
+		httputil.WriteError(w, err, "failed to list study groups")
 		return
 	}
 
 	httputil.JSON(w, http.StatusOK, map[string]interface{}{
-		"data":  groups,
-		"total": total,
+		"data":        groups,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"maxPageSize": domain.MaxPageSize,
 	})
 }
 
-// Get returns a single study group by ID
+// Get returns a single study group by ID, if the user belongs to it or it is public
 func (h *StudyGroupHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
 	idStr := r.PathValue("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -63,9 +109,9 @@ func (h *StudyGroupHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	group, err := h.groupService.GetByID(r.Context(), id)
+	group, err := h.groupService.GetByID(r.Context(), id, userID)
 	if err != nil {
-		httputil.Error(w, http.StatusNotFound, "group not found")
+		httputil.WriteError(w, err, "failed to get group")
 		return
 	}
 
@@ -94,7 +140,7 @@ func (h *StudyGroupHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	group, err := h.groupService.Create(r.Context(), userID, &req)
 	if err != nil {
-		httputil.Error(w, http.StatusBadRequest, err.Error())
+		httputil.WriteError(w, err, "failed to create study group")
 		return
 	}
 
@@ -116,14 +162,44 @@ func (h *StudyGroupHandler) Join(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.groupService.Join(r.Context(), groupID, userID); err != nil {
-		httputil.Error(w, http.StatusBadRequest, err.Error())
+	var req domain.JoinGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.groupService.Join(r.Context(), groupID, userID, req.InviteCode); err != nil {
+		httputil.WriteError(w, err, "failed to join study group")
 		return
 	}
 
 	httputil.JSON(w, http.StatusOK, map[string]string{"message": "joined successfully"})
 }
 
+// CreateInvite creates a single-use invite to a study group, for its owners and admins
+func (h *StudyGroupHandler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	idStr := r.PathValue("id")
+	groupID, err := uuid.Parse(idStr)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	invite, err := h.groupService.CreateInvite(r.Context(), groupID, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create group invite")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, invite)
+}
+
 // Leave removes the current user from a study group
 func (h *StudyGroupHandler) Leave(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserUUID(r.Context())
@@ -140,15 +216,16 @@ func (h *StudyGroupHandler) Leave(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.groupService.Leave(r.Context(), groupID, userID); err != nil {
-		httputil.Error(w, http.StatusBadRequest, err.Error())
+		httputil.WriteError(w, err, "failed to leave study group")
 		return
 	}
 
 	httputil.JSON(w, http.StatusOK, map[string]string{"message": "left successfully"})
 }
 
-// GetMembers returns all members of a study group
+// GetMembers returns all members of a study group, if the user belongs to it or it is public
 func (h *StudyGroupHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
 	idStr := r.PathValue("id")
 	groupID, err := uuid.Parse(idStr)
 	if err != nil {
@@ -156,12 +233,16 @@ func (h *StudyGroupHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	members, err := h.groupService.GetMembers(r.Context(), groupID)
+	profiles, err := h.groupService.GetMembers(r.Context(), groupID, userID)
 	if err != nil {
-		httputil.Error(w, http.StatusInternalServerError, err.Error())
+		httputil.WriteError(w, err, "failed to get study group members")
 		return
 	}
 
+	members := make([]GroupMemberResponse, len(profiles))
+	for i := range profiles {
+		members[i] = newGroupMemberResponse(&profiles[i])
+	}
 	httputil.JSON(w, http.StatusOK, members)
 }
 
@@ -181,9 +262,33 @@ func (h *StudyGroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.groupService.Delete(r.Context(), groupID, userID); err != nil {
-		httputil.Error(w, http.StatusForbidden, err.Error())
+		httputil.WriteError(w, err, "failed to delete study group")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// Reactivate unarchives a study group and restarts its inactivity clock
+func (h *StudyGroupHandler) Reactivate(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	idStr := r.PathValue("id")
+	groupID, err := uuid.Parse(idStr)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	group, err := h.groupService.Reactivate(r.Context(), groupID, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to reactivate study group")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, group)
+}
@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// TILHandler handles "Today I Learned" micro-entry endpoints
+type TILHandler struct {
+	tilService      *service.TILService
+	progressService *service.ProgressService
+	settingsService *service.SettingsService
+}
+
+// NewTILHandler creates a new TIL handler
+func NewTILHandler(tilService *service.TILService, progressService *service.ProgressService, settingsService *service.SettingsService) *TILHandler {
+	return &TILHandler{
+		tilService:      tilService,
+		progressService: progressService,
+		settingsService: settingsService,
+	}
+}
+
+// List handles GET /api/til
+func (h *TILHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+
+	if page <= 0 {
+		page = 1
+	}
+	pageSize = h.settingsService.PageSize(r.Context(), userID, pageSize)
+
+	tils, total, err := h.tilService.List(r.Context(), userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list TILs")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":        tils,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"totalPages":  (total + pageSize - 1) / pageSize,
+		"maxPageSize": domain.MaxPageSize,
+	})
+}
+
+// Create handles POST /api/til
+func (h *TILHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.CreateTILRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	til, err := h.tilService.Create(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create TIL")
+		return
+	}
+
+	// TILs count toward the daily streak just like full entries
+	if err := h.progressService.RecordTIL(r.Context(), userID); err != nil {
+		log.Printf("WARN: Failed to record TIL for progress: %v", err)
+		// Don't fail the request, progress tracking is secondary
+	}
+
+	httputil.JSON(w, http.StatusCreated, til)
+}
+
+// Delete handles DELETE /api/til/{id}
+func (h *TILHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	tilID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid TIL ID")
+		return
+	}
+
+	if err := h.tilService.Delete(r.Context(), tilID, userID); err != nil {
+		httputil.WriteError(w, err, "failed to delete TIL")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]bool{"success": true})
+}
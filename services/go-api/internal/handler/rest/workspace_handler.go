@@ -0,0 +1,209 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/internal/tenant"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceHandler handles workspace, membership, and workspace switcher endpoints
+type WorkspaceHandler struct {
+	workspaceService *service.WorkspaceService
+}
+
+// NewWorkspaceHandler creates a new workspace handler
+func NewWorkspaceHandler(workspaceService *service.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{workspaceService: workspaceService}
+}
+
+// List handles GET /api/workspaces
+func (h *WorkspaceHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	workspaces, err := h.workspaceService.List(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list workspaces")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]interface{}{
+		"data":     workspaces,
+		"activeId": tenant.WorkspaceID(r.Context(), userID),
+	})
+}
+
+// Create handles POST /api/workspaces
+func (h *WorkspaceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	var req domain.CreateWorkspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	ws, err := h.workspaceService.Create(r.Context(), userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to create workspace")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, ws)
+}
+
+// Switch handles POST /api/workspaces/{id}/switch, returning a token scoped to the workspace
+func (h *WorkspaceHandler) Switch(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	workspaceID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid workspace ID")
+		return
+	}
+
+	token, err := h.workspaceService.Switch(r.Context(), userID, workspaceID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to switch workspace")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]string{
+		"token":       token,
+		"workspaceId": workspaceID.String(),
+	})
+}
+
+// ListMembers handles GET /api/workspaces/{id}/members
+func (h *WorkspaceHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	workspaceID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid workspace ID")
+		return
+	}
+
+	members, err := h.workspaceService.ListMembers(r.Context(), workspaceID, userID)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to list workspace members")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, members)
+}
+
+// AddMember handles POST /api/workspaces/{id}/members
+func (h *WorkspaceHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	workspaceID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid workspace ID")
+		return
+	}
+
+	var req domain.AddWorkspaceMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Email == "" {
+		httputil.Error(w, http.StatusBadRequest, "email is required")
+		return
+	}
+
+	member, err := h.workspaceService.AddMember(r.Context(), workspaceID, userID, &req)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to add workspace member")
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, member)
+}
+
+// RemoveMember handles DELETE /api/workspaces/{id}/members/{userId}
+func (h *WorkspaceHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	workspaceID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid workspace ID")
+		return
+	}
+	memberID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	if err := h.workspaceService.RemoveMember(r.Context(), workspaceID, userID, memberID); err != nil {
+		httputil.WriteError(w, err, "failed to remove workspace member")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// UpdateMemberRole handles PUT /api/workspaces/{id}/members/{userId}
+func (h *WorkspaceHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	workspaceID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid workspace ID")
+		return
+	}
+	memberID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	var req domain.UpdateMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.workspaceService.UpdateMemberRole(r.Context(), workspaceID, userID, memberID, req.Role); err != nil {
+		httputil.WriteError(w, err, "failed to update workspace member role")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]bool{"success": true})
+}
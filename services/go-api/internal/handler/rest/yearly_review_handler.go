@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"devjournal/internal/middleware"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// YearlyReviewHandler handles "year in review" endpoints
+type YearlyReviewHandler struct {
+	yearlyReviewService *service.YearlyReviewService
+}
+
+// NewYearlyReviewHandler creates a new yearly review handler
+func NewYearlyReviewHandler(yearlyReviewService *service.YearlyReviewService) *YearlyReviewHandler {
+	return &YearlyReviewHandler{yearlyReviewService: yearlyReviewService}
+}
+
+// Get handles GET /api/reviews/yearly/{year}
+func (h *YearlyReviewHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserUUID(r.Context())
+	if userID == uuid.Nil {
+		httputil.Error(w, http.StatusUnauthorized, "invalid user ID")
+		return
+	}
+
+	year, err := strconv.Atoi(r.PathValue("year"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	review, err := h.yearlyReviewService.Get(r.Context(), userID, year)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to get yearly review")
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, review)
+}
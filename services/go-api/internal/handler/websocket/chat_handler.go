@@ -3,38 +3,51 @@ package websocket
 import (
 	"log"
 	"net/http"
+	"strings"
 
-	"devjournal/internal/middleware"
+	"devjournal/internal/requestid"
 	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
 
 	"github.com/gorilla/websocket"
 )
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		// In production, validate the origin
-		// For development, allow all origins
-		return true
-	},
-}
-
 // ChatHandler handles WebSocket connections for chat
 type ChatHandler struct {
-	hub         *Hub
-	authService *service.AuthService
+	hub               *Hub
+	chatTicketService *service.ChatTicketService
+	upgrader          websocket.Upgrader
 }
 
-// NewChatHandler creates a new chat handler
-func NewChatHandler(hub *Hub, authService *service.AuthService) *ChatHandler {
+// NewChatHandler creates a new chat handler that accepts connections from the given browser
+// origins; "*" allows any origin
+func NewChatHandler(hub *Hub, chatTicketService *service.ChatTicketService, allowedOrigins []string) *ChatHandler {
 	return &ChatHandler{
-		hub:         hub,
-		authService: authService,
+		hub:               hub,
+		chatTicketService: chatTicketService,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     checkOrigin(allowedOrigins),
+		},
+	}
+}
+
+// checkOrigin allows requests from the given origins, and those without an Origin header,
+// which come from non-browser clients that cross-site requests cannot be forged through
+func checkOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || allowed["*"] || allowed[origin]
 	}
 }
 
-// HandleWebSocket handles WebSocket upgrade and connection
+// HandleWebSocket handles WebSocket upgrade and connection. Connections are opened with a
+// one-time ticket from POST /api/ws/ticket, passed as ?ticket=, for the room in the path.
 func (h *ChatHandler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	// Get room from path
 	room := r.PathValue("room")
@@ -43,43 +56,34 @@ func (h *ChatHandler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get user info from context (set by auth middleware)
-	userID := middleware.GetUserID(r.Context())
-	userName := middleware.GetUserName(r.Context())
-	userEmail := middleware.GetUserEmail(r.Context())
-
-	if userID == "" {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	// The ticket says who is connecting, and was only issued if they may join the room
+	ticket, err := h.chatTicketService.Redeem(r.Context(), r.URL.Query().Get("ticket"), room)
+	if err != nil {
+		httputil.WriteError(w, err, "failed to redeem chat ticket")
 		return
 	}
+	userID := ticket.UserID.String()
+	userName := displayName(ticket.DisplayName, ticket.Email)
 
-	// Fallback to email prefix if display name is empty
-	if userName == "" {
-		if userEmail != "" {
-			// Use email prefix as display name
-			for i, c := range userEmail {
-				if c == '@' {
-					userName = userEmail[:i]
-					break
-				}
-			}
-		}
-		if userName == "" {
-			userName = "User"
-		}
+	// The session is identified by its upgrade request's ID, so it can be traced from the REST logs
+	connID := requestid.FromContext(r.Context())
+	if connID == "" {
+		connID = requestid.New()
 	}
 
-	log.Printf("WebSocket connection: userID=%s, userName=%s, room=%s", userID, userName, room)
+	log.Printf("WebSocket connection: userID=%s, userName=%s, room=%s, connection=%s", userID, userName, room, connID)
 
 	// Upgrade HTTP connection to WebSocket
-	conn, err := upgrader.Upgrade(w, r, nil)
+	conn, err := h.upgrader.Upgrade(w, r, http.Header{requestid.Header: {connID}})
 	if err != nil {
-		log.Printf("WebSocket upgrade failed: %v", err)
+		log.Printf("WebSocket upgrade failed (connection %s): %v", connID, err)
 		return
 	}
 
 	// Create client
-	client := NewClient(h.hub, conn, room, userID, userName)
+	client := NewClient(h.hub, conn, connID, room, userID, userName)
+	client.readOnly = ticket.ReadOnly
+	client.joinVoiceOnly = ticket.JoinVoiceOnly
 
 	// Register client with hub
 	h.hub.register <- client
@@ -88,3 +92,14 @@ func (h *ChatHandler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	go client.WritePump()
 	go client.ReadPump()
 }
+
+// displayName falls back to the email prefix if the user has no display name
+func displayName(name, email string) string {
+	if name != "" {
+		return name
+	}
+	if prefix, _, ok := strings.Cut(email, "@"); ok && prefix != "" {
+		return prefix
+	}
+	return "User"
+}
@@ -0,0 +1,46 @@
+package websocket
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	check := checkOrigin([]string{"https://devjournal.app/", "http://localhost:4200"})
+	cases := map[string]bool{
+		"":                       true,
+		"https://devjournal.app": true,
+		"http://localhost:4200":  true,
+		"https://evil.example":   false,
+		"http://devjournal.app":  false,
+	}
+	for origin, want := range cases {
+		r := httptest.NewRequest("GET", "/ws/chat/general", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if got := check(r); got != want {
+			t.Errorf("checkOrigin(%q) = %v, want %v", origin, got, want)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/ws/chat/general", nil)
+	r.Header.Set("Origin", "https://evil.example")
+	if !checkOrigin([]string{"*"})(r) {
+		t.Error("* should allow any origin")
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	cases := []struct{ name, email, want string }{
+		{"Ada", "ada@example.com", "Ada"},
+		{"", "ada@example.com", "ada"},
+		{"", "", "User"},
+		{"", "@example.com", "User"},
+	}
+	for _, c := range cases {
+		if got := displayName(c.name, c.email); got != c.want {
+			t.Errorf("displayName(%q, %q) = %q, want %q", c.name, c.email, got, c.want)
+		}
+	}
+}
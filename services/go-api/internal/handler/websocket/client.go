@@ -1,11 +1,14 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"time"
 
 	"devjournal/internal/domain"
+	"devjournal/internal/errreport"
 
 	"github.com/gorilla/websocket"
 )
@@ -20,16 +23,17 @@ const (
 	// Send pings to peer with this period (must be less than pongWait)
 	pingPeriod = (pongWait * 9) / 10
 
-	// Maximum message size allowed from peer
-	maxMessageSize = 4096
+	// Maximum message size allowed from peer (large enough for WebRTC SDP offers)
+	maxMessageSize = 16384
 )
 
 // Client represents a single WebSocket connection
 type Client struct {
 	hub *Hub
 
-	// The WebSocket connection
-	conn *websocket.Conn
+	// The WebSocket connection, and the ID it is logged and reported under
+	conn   *websocket.Conn
+	connID string
 
 	// Buffered channel of outbound messages
 	send chan *domain.ChatMessage
@@ -40,17 +44,50 @@ type Client struct {
 	// User information
 	userID   string
 	userName string
+
+	// Close frame sent when the hub closes the connection, if it is not a normal closure
+	closeCode   int
+	closeReason string
+
+	// Limits how fast this connection sends chat messages
+	flood *floodGuard
+
+	// The user can read the room but not post in it, e.g. a member in an announcements channel
+	readOnly bool
+
+	// The user can join the room's voice session but not start one
+	joinVoiceOnly bool
+}
+
+// incomingMessage is a message sent by a client over the WebSocket
+type incomingMessage struct {
+	Content      string          `json:"content"`
+	Type         string          `json:"type"`
+	ReplyTo      string          `json:"replyTo"`
+	TargetUserID string          `json:"targetUserId"`
+	Signal       json.RawMessage `json:"signal"`
+}
+
+// parseIncomingMessage decodes a raw WebSocket frame from a client
+func parseIncomingMessage(data []byte) (*incomingMessage, error) {
+	var msg incomingMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
 }
 
 // NewClient creates a new Client instance
-func NewClient(hub *Hub, conn *websocket.Conn, room, userID, userName string) *Client {
+func NewClient(hub *Hub, conn *websocket.Conn, connID, room, userID, userName string) *Client {
 	return &Client{
 		hub:      hub,
 		conn:     conn,
+		connID:   connID,
 		send:     make(chan *domain.ChatMessage, 256),
 		room:     room,
 		userID:   userID,
 		userName: userName,
+		flood:    newFloodGuard(hub.messageRate, hub.messageBurst),
 	}
 }
 
@@ -60,6 +97,7 @@ func (c *Client) ReadPump() {
 		c.hub.unregister <- c
 		c.conn.Close()
 	}()
+	defer c.hub.recoverPanic(c)
 
 	c.conn.SetReadLimit(maxMessageSize)
 	c.conn.SetReadDeadline(time.Now().Add(pongWait))
@@ -72,18 +110,55 @@ func (c *Client) ReadPump() {
 		_, messageBytes, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				log.Printf("WebSocket error: %v", err)
+				log.Printf("WebSocket error (connection %s): %v", c.connID, err)
+				c.hub.report(c, errreport.Error("WebSocket read failed", err))
 			}
 			break
 		}
 
 		// Parse incoming message
-		var incomingMessage struct {
-			Content string `json:"content"`
-			Type    string `json:"type"`
+		incomingMessage, err := parseIncomingMessage(messageBytes)
+		if err != nil {
+			log.Printf("Failed to parse message (connection %s): %v", c.connID, err)
+			continue
 		}
-		if err := json.Unmarshal(messageBytes, &incomingMessage); err != nil {
-			log.Printf("Failed to parse message: %v", err)
+
+		// WebRTC signaling bypasses the chat pipeline and is never broadcast
+		if isPeerSignal(incomingMessage.Type) {
+			if incomingMessage.TargetUserID == "" || len(incomingMessage.Signal) == 0 {
+				c.notify("signaling messages require targetUserId and signal")
+				continue
+			}
+			c.hub.signal <- &signalMessage{
+				client: c,
+				message: domain.NewSignalMessage(
+					c.room,
+					c.userID,
+					c.userName,
+					incomingMessage.Type,
+					incomingMessage.TargetUserID,
+					incomingMessage.Signal,
+				),
+			}
+			continue
+		}
+		if isVoicePresence(incomingMessage.Type) {
+			c.hub.signal <- &signalMessage{
+				client:  c,
+				message: domain.NewChatMessage(c.room, c.userID, c.userName, "", incomingMessage.Type),
+			}
+			continue
+		}
+
+		if c.readOnly {
+			c.notify("only group owners and admins can post in this channel")
+			continue
+		}
+
+		if ok, notice := c.flood.allow(time.Now()); !ok {
+			if notice != "" {
+				c.notify(notice)
+			}
 			continue
 		}
 
@@ -96,11 +171,52 @@ func (c *Client) ReadPump() {
 			"message",
 		)
 
+		// Replies join the thread of the message they answer; a reply to a reply joins its thread
+		if incomingMessage.ReplyTo != "" {
+			parent := c.hub.FindMessage(c.room, incomingMessage.ReplyTo)
+			if parent == nil || parent.Type != "message" {
+				c.notify("the message you replied to is no longer available")
+				continue
+			}
+			message.ReplyTo = parent.ID
+			if parent.ReplyTo != "" {
+				message.ReplyTo = parent.ReplyTo
+			}
+		}
+
+		// Run the moderation pipeline before anything reaches the room
+		if err := c.hub.filters.Apply(context.Background(), message); err != nil {
+			var rejection *RejectionError
+			if !errors.As(err, &rejection) {
+				log.Printf("Message filter error (connection %s): %v", c.connID, err)
+				c.hub.report(c, errreport.Error("chat message filter failed", err))
+				continue
+			}
+			c.notify(rejection.Reason)
+			continue
+		}
+
 		// Broadcast to room
 		c.hub.broadcast <- message
 	}
 }
 
+// notify sends a system message to this client only, with the connection ID for the user to
+// quote if they report it
+func (c *Client) notify(content string) {
+	message := domain.NewChatMessage(c.room, "", "System", content, "system")
+	message.ConnectionID = c.connID
+	c.hub.direct <- &directMessage{client: c, message: message}
+}
+
+// closeMessage is the close frame WritePump sends when the hub closes the connection
+func (c *Client) closeMessage() []byte {
+	if c.closeCode == 0 {
+		return []byte{}
+	}
+	return websocket.FormatCloseMessage(c.closeCode, c.closeReason)
+}
+
 // WritePump pumps messages from the hub to the WebSocket connection
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)
@@ -108,6 +224,7 @@ func (c *Client) WritePump() {
 		ticker.Stop()
 		c.conn.Close()
 	}()
+	defer c.hub.recoverPanic(c)
 
 	for {
 		select {
@@ -115,13 +232,14 @@ func (c *Client) WritePump() {
 			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if !ok {
 				// The hub closed the channel
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				c.conn.WriteMessage(websocket.CloseMessage, c.closeMessage())
 				return
 			}
 
 			// Write JSON message
 			if err := c.conn.WriteJSON(message); err != nil {
-				log.Printf("Failed to write message: %v", err)
+				log.Printf("Failed to write message (connection %s): %v", c.connID, err)
+				c.hub.report(c, errreport.Error("WebSocket write failed", err))
 				return
 			}
 
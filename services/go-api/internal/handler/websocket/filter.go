@@ -0,0 +1,250 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// RejectionError is returned by a filter to stop a message from being broadcast.
+// The reason is sent back to the sender as a system message.
+type RejectionError struct {
+	Reason string
+}
+
+func (e *RejectionError) Error() string {
+	return "message rejected: " + e.Reason
+}
+
+// reject builds a RejectionError with a formatted reason
+func reject(format string, args ...interface{}) error {
+	return &RejectionError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// MessageFilter inspects a chat message before it is broadcast.
+// Filters may rewrite msg.Content in place; returning an error rejects the message.
+type MessageFilter interface {
+	Apply(ctx context.Context, msg *domain.ChatMessage) error
+}
+
+// MessageFilterFunc adapts an ordinary function to the MessageFilter interface
+type MessageFilterFunc func(ctx context.Context, msg *domain.ChatMessage) error
+
+// Apply calls f(ctx, msg)
+func (f MessageFilterFunc) Apply(ctx context.Context, msg *domain.ChatMessage) error {
+	return f(ctx, msg)
+}
+
+// FilterPipeline runs a chain of filters in registration order, stopping at the first rejection
+type FilterPipeline struct {
+	mu      sync.RWMutex
+	filters []MessageFilter
+}
+
+// NewFilterPipeline creates a pipeline with the given filters
+func NewFilterPipeline(filters ...MessageFilter) *FilterPipeline {
+	return &FilterPipeline{filters: filters}
+}
+
+// Use appends filters to the end of the pipeline
+func (p *FilterPipeline) Use(filters ...MessageFilter) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.filters = append(p.filters, filters...)
+}
+
+// Apply runs every filter against the message. A nil pipeline accepts everything.
+func (p *FilterPipeline) Apply(ctx context.Context, msg *domain.ChatMessage) error {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.RLock()
+	filters := p.filters
+	p.mu.RUnlock()
+
+	for _, filter := range filters {
+		if err := filter.Apply(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MaxLengthFilter rejects empty messages and messages longer than max characters
+func MaxLengthFilter(max int) MessageFilter {
+	return MessageFilterFunc(func(ctx context.Context, msg *domain.ChatMessage) error {
+		if strings.TrimSpace(msg.Content) == "" {
+			return reject("message is empty")
+		}
+		if max > 0 && utf8.RuneCountInString(msg.Content) > max {
+			return reject("message exceeds %d characters", max)
+		}
+		return nil
+	})
+}
+
+// rateLimitFilter tracks recent send times per user across all rooms
+type rateLimitFilter struct {
+	limit  int
+	window time.Duration
+
+	mu    sync.Mutex
+	sent  map[string][]time.Time
+	swept time.Time // when users who stopped sending were last forgotten
+}
+
+// RateLimitFilter allows each user at most limit messages per sliding window
+func RateLimitFilter(limit int, window time.Duration) MessageFilter {
+	return &rateLimitFilter{
+		limit:  limit,
+		window: window,
+		sent:   make(map[string][]time.Time),
+	}
+}
+
+func (f *rateLimitFilter) Apply(ctx context.Context, msg *domain.ChatMessage) error {
+	if f.limit <= 0 {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-f.window)
+
+	// Once a window, forget users with nothing left in it
+	if now.Sub(f.swept) >= f.window {
+		for userID, times := range f.sent {
+			if !times[len(times)-1].After(cutoff) {
+				delete(f.sent, userID)
+			}
+		}
+		f.swept = now
+	}
+
+	// Drop timestamps that have fallen out of the window
+	recent := f.sent[msg.UserID][:0]
+	for _, t := range f.sent[msg.UserID] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= f.limit {
+		f.sent[msg.UserID] = recent
+		return reject("you are sending messages too quickly, please slow down")
+	}
+
+	f.sent[msg.UserID] = append(recent, now)
+	return nil
+}
+
+// profanityPattern matches a small built-in list of common profanities as whole words
+var profanityPattern = regexp.MustCompile(`(?i)\b(fuck\w*|shit\w*|bitch\w*|bastard\w*|asshole\w*|dick|cunt\w*|motherfuck\w*)\b`)
+
+// maskProfanity replaces profane words with asterisks of the same length
+func maskProfanity(content string) string {
+	return profanityPattern.ReplaceAllStringFunc(content, func(word string) string {
+		return strings.Repeat("*", utf8.RuneCountInString(word))
+	})
+}
+
+// groupRules is the compiled form of a group's moderation settings
+type groupRules struct {
+	settings *domain.GroupModerationSettings
+	patterns []*regexp.Regexp
+	loadedAt time.Time
+}
+
+// groupRulesFilter applies per-group settings (profanity masking, blocked patterns, length)
+type groupRulesFilter struct {
+	moderationService *service.ModerationService
+	ttl               time.Duration
+
+	mu    sync.Mutex
+	cache map[string]*groupRules
+}
+
+// GroupRulesFilter applies the moderation settings configured for the message's group.
+// Settings are cached per room for ttl so the database isn't hit on every message.
+func GroupRulesFilter(moderationService *service.ModerationService, ttl time.Duration) MessageFilter {
+	return &groupRulesFilter{
+		moderationService: moderationService,
+		ttl:               ttl,
+		cache:             make(map[string]*groupRules),
+	}
+}
+
+func (f *groupRulesFilter) Apply(ctx context.Context, msg *domain.ChatMessage) error {
+	// Every channel in a group shares its rules; rooms that aren't study groups have none
+	groupID, _, ok := domain.GroupRoom(msg.Room)
+	if !ok {
+		return nil
+	}
+
+	rules, err := f.rules(ctx, groupID)
+	if err != nil {
+		// Fail open: a settings lookup failure shouldn't take chat down
+		log.Printf("WARN: Failed to load moderation settings for room %s: %v", msg.Room, err)
+		return nil
+	}
+
+	if rules.settings.MaxMessageLength > 0 && utf8.RuneCountInString(msg.Content) > rules.settings.MaxMessageLength {
+		return reject("message exceeds %d characters", rules.settings.MaxMessageLength)
+	}
+
+	for _, pattern := range rules.patterns {
+		if pattern.MatchString(msg.Content) {
+			return reject("message contains blocked content")
+		}
+	}
+
+	if rules.settings.ProfanityFilter {
+		msg.Content = maskProfanity(msg.Content)
+	}
+
+	return nil
+}
+
+// rules returns cached rules for a group, reloading them once the TTL expires
+func (f *groupRulesFilter) rules(ctx context.Context, groupID uuid.UUID) (*groupRules, error) {
+	key := groupID.String()
+
+	f.mu.Lock()
+	cached, ok := f.cache[key]
+	f.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) < f.ttl {
+		return cached, nil
+	}
+
+	settings, err := f.moderationService.GetSettings(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := &groupRules{settings: settings, loadedAt: time.Now()}
+	for _, pattern := range settings.BlockedPatterns {
+		// Patterns are validated on save; skip anything that no longer compiles
+		if re, err := regexp.Compile(pattern); err == nil {
+			rules.patterns = append(rules.patterns, re)
+		}
+	}
+
+	f.mu.Lock()
+	f.cache[key] = rules
+	f.mu.Unlock()
+
+	return rules, nil
+}
@@ -0,0 +1,35 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"devjournal/internal/domain"
+)
+
+func TestRateLimitFilterForgetsIdleUsers(t *testing.T) {
+	filter := RateLimitFilter(2, 20*time.Millisecond).(*rateLimitFilter)
+	send := func(userID string) error {
+		return filter.Apply(context.Background(), domain.NewChatMessage("general", userID, userID, "hi", "message"))
+	}
+
+	if send("ada") != nil || send("ada") != nil {
+		t.Fatal("messages within the limit were rejected")
+	}
+	if send("ada") == nil {
+		t.Fatal("message over the limit was allowed")
+	}
+
+	// Once ada's messages fall out of the window, the next sweep forgets ada
+	time.Sleep(30 * time.Millisecond)
+	if err := send("grace"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := filter.sent["ada"]; ok || len(filter.sent) != 1 {
+		t.Fatalf("idle users were kept: %v", filter.sent)
+	}
+	if send("ada") != nil {
+		t.Fatal("message after the window was rejected")
+	}
+}
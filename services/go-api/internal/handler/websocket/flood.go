@@ -0,0 +1,77 @@
+package websocket
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// floodWarnings is how many times a connection is told to slow down before it is muted
+	floodWarnings = 3
+
+	// floodMute is the first mute; each further mute on the same connection doubles, up to floodMaxMute
+	floodMute    = 30 * time.Second
+	floodMaxMute = 10 * time.Minute
+)
+
+// floodGuard limits how fast one connection may send chat messages with a token bucket, so a
+// client cannot flood a room between the per-minute checks of RateLimitFilter. Going over warns
+// the sender, and after floodWarnings warnings mutes them for a while. It is only used from the
+// connection's ReadPump.
+type floodGuard struct {
+	rate  float64 // tokens added per second
+	burst float64
+
+	tokens     float64
+	last       time.Time
+	warnings   int
+	mutes      int
+	mutedUntil time.Time
+}
+
+// newFloodGuard allows perSecond messages a second with bursts of up to burst. It returns nil,
+// which allows everything, if perSecond is not positive.
+func newFloodGuard(perSecond float64, burst int) *floodGuard {
+	if perSecond <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &floodGuard{rate: perSecond, burst: float64(burst), tokens: float64(burst)}
+}
+
+// allow reports whether a message sent at now may go through, and if not, the system message
+// to send back, if any. Messages dropped while muted get no reply, so a flooding client is
+// not answered with a flood.
+func (g *floodGuard) allow(now time.Time) (bool, string) {
+	if g == nil {
+		return true, ""
+	}
+	if now.Before(g.mutedUntil) {
+		return false, ""
+	}
+
+	if !g.last.IsZero() {
+		g.tokens = min(g.burst, g.tokens+now.Sub(g.last).Seconds()*g.rate)
+	}
+	g.last = now
+	if g.tokens >= 1 {
+		g.tokens--
+		return true, ""
+	}
+
+	g.warnings++
+	if g.warnings <= floodWarnings {
+		return false, "you are sending messages too quickly, please slow down"
+	}
+
+	mute := floodMute << g.mutes
+	if mute > floodMaxMute || mute <= 0 {
+		mute = floodMaxMute
+	}
+	g.mutes++
+	g.warnings = 0
+	g.mutedUntil = now.Add(mute)
+	return false, fmt.Sprintf("you are muted for %s for sending messages too quickly", mute)
+}
@@ -0,0 +1,54 @@
+package websocket
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFloodGuard(t *testing.T) {
+	g := newFloodGuard(5, 10)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 10; i++ {
+		if ok, _ := g.allow(now); !ok {
+			t.Fatalf("message %d of the burst was dropped", i+1)
+		}
+	}
+
+	for i := 0; i < floodWarnings; i++ {
+		ok, notice := g.allow(now)
+		if ok || !strings.Contains(notice, "slow down") {
+			t.Fatalf("allow = %v, %q; want a warning", ok, notice)
+		}
+	}
+	ok, notice := g.allow(now)
+	if ok || !strings.Contains(notice, "muted for 30s") {
+		t.Fatalf("allow = %v, %q; want a 30s mute", ok, notice)
+	}
+
+	// Muted messages are dropped without a reply, even once tokens are back
+	if ok, notice := g.allow(now.Add(29 * time.Second)); ok || notice != "" {
+		t.Fatalf("allow while muted = %v, %q", ok, notice)
+	}
+
+	now = now.Add(31 * time.Second)
+	if ok, _ := g.allow(now); !ok {
+		t.Fatal("message after the mute was dropped")
+	}
+
+	// A second mute lasts twice as long
+	for i := 0; i < 9; i++ {
+		g.allow(now)
+	}
+	for i := 0; i < floodWarnings; i++ {
+		g.allow(now)
+	}
+	if _, notice := g.allow(now); !strings.Contains(notice, "muted for 1m0s") {
+		t.Fatalf("second mute notice = %q, want 1m0s", notice)
+	}
+
+	if ok, _ := newFloodGuard(0, 0).allow(now); !ok {
+		t.Fatal("a disabled guard dropped a message")
+	}
+}
@@ -0,0 +1,71 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+)
+
+func FuzzParseIncomingMessage(f *testing.F) {
+	f.Add([]byte(`{"content":"hello","type":"message"}`))
+	f.Add([]byte(`{"type":"offer","targetUserId":"u2","signal":{"sdp":"v=0\r\n","type":"offer"}}`))
+	f.Add([]byte(`{"type":"ice-candidate","targetUserId":"u2","signal":null}`))
+	f.Add([]byte(`{"type":"voice-join"}`))
+	f.Add([]byte(`{"content":"\u0000\ud83d","signal":[[[[[]]]]]}`))
+	f.Add([]byte(`{"content":1}`))
+	f.Add([]byte(`not json`))
+	f.Add([]byte{})
+
+	filter := MaxLengthFilter(2000)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		incoming, err := parseIncomingMessage(data)
+		if err != nil {
+			return
+		}
+
+		// Mirror ReadPump: whatever it builds must survive the trip back out through WriteJSON
+		var message *domain.ChatMessage
+		switch {
+		case isPeerSignal(incoming.Type):
+			if incoming.TargetUserID == "" || len(incoming.Signal) == 0 {
+				return
+			}
+			message = domain.NewSignalMessage("room", "u1", "Ada", incoming.Type, incoming.TargetUserID, incoming.Signal)
+		case isVoicePresence(incoming.Type):
+			message = domain.NewChatMessage("room", "u1", "Ada", "", incoming.Type)
+		default:
+			message = domain.NewChatMessage("room", "u1", "Ada", incoming.Content, "message")
+			if err := filter.Apply(context.Background(), message); err != nil {
+				if _, ok := err.(*RejectionError); !ok {
+					t.Fatalf("MaxLengthFilter returned %T, want *RejectionError", err)
+				}
+				return
+			}
+		}
+
+		if _, err := json.Marshal(message); err != nil {
+			t.Fatalf("message parsed from %q cannot be encoded: %v", data, err)
+		}
+	})
+}
+
+func FuzzMaskProfanity(f *testing.F) {
+	f.Add("well shit, that compiled")
+	f.Add("SHITTY code, fucking tests")
+	f.Add("dickens is not profane")
+	f.Add("\xff\xfeshit\x00")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, content string) {
+		masked := maskProfanity(content)
+		if utf8.RuneCountInString(masked) != utf8.RuneCountInString(content) {
+			t.Fatalf("maskProfanity(%q) = %q changed the message length", content, masked)
+		}
+		if again := maskProfanity(masked); again != masked {
+			t.Fatalf("maskProfanity is not idempotent: %q then %q", masked, again)
+		}
+	})
+}
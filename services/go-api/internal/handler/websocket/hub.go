@@ -1,16 +1,50 @@
 package websocket
 
 import (
+	"context"
+	"log"
+	"runtime/debug"
 	"sync"
 
 	"devjournal/internal/domain"
+	"devjournal/internal/errreport"
 )
 
+// historySize is the number of recent messages kept per room for report lookups
+const historySize = 200
+
+// CloseTooManyConnections is the close code sent to a connection evicted because its user
+// opened more than the hub allows
+const CloseTooManyConnections = 4029
+
+// directMessage is a message addressed to a single client rather than a room
+type directMessage struct {
+	client  *Client
+	message *domain.ChatMessage
+}
+
+// userNotice is a message delivered to every connection of some users, in whichever room
+type userNotice struct {
+	userIDs []string
+	message *domain.ChatMessage
+}
+
 // Hub maintains the set of active clients and broadcasts messages to rooms
 type Hub struct {
 	// Registered clients by room
 	rooms map[string]map[*Client]bool
 
+	// Registered clients by user ID, oldest first
+	users map[string][]*Client
+
+	// Most connections a user may have open, in all rooms and in one room; 0 means no limit
+	maxPerUser     int
+	maxPerUserRoom int
+
+	// How fast each connection may send chat messages; 0 means no limit
+	messageRate  float64
+	messageBurst int
+
 	// Register requests from clients
 	register chan *Client
 
@@ -20,33 +54,176 @@ type Hub struct {
 	// Broadcast messages to a room
 	broadcast chan *domain.ChatMessage
 
+	// Messages delivered only to one client (e.g. moderation notices)
+	direct chan *directMessage
+
+	// Messages delivered to all connections of some users (e.g. announcements)
+	notice chan *userNotice
+
+	// WebRTC signaling and voice presence messages
+	signal chan *signalMessage
+
+	// Voice session participants by room
+	voice map[string]map[*Client]bool
+
+	// Filters every user message passes through before broadcast
+	filters *FilterPipeline
+
+	// Recently broadcast messages by room, oldest first, kept while the room has clients
+	history map[string][]*domain.ChatMessage
+
+	// Called with every chat message broadcast to a room (e.g. to mirror it to Slack)
+	listeners []func(*domain.ChatMessage)
+
+	// Receives panics and connection errors; nil only logs them
+	reporter errreport.Reporter
+
 	// Mutex for thread-safe room access
 	mu sync.RWMutex
 }
 
-// NewHub creates a new Hub instance
-func NewHub() *Hub {
+// NewHub creates a new Hub instance. A nil pipeline broadcasts messages unfiltered.
+func NewHub(filters *FilterPipeline) *Hub {
 	return &Hub{
 		rooms:      make(map[string]map[*Client]bool),
+		users:      make(map[string][]*Client),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast:  make(chan *domain.ChatMessage),
+		direct:     make(chan *directMessage),
+		notice:     make(chan *userNotice),
+		signal:     make(chan *signalMessage),
+		voice:      make(map[string]map[*Client]bool),
+		filters:    filters,
+		history:    make(map[string][]*domain.ChatMessage),
 	}
 }
 
 // Run starts the hub's main loop
 func (h *Hub) Run() {
 	for {
-		select {
-		case client := <-h.register:
-			h.registerClient(client)
+		h.handleNext()
+	}
+}
 
-		case client := <-h.unregister:
-			h.unregisterClient(client)
+// handleNext handles the next event on the hub's loop. A panic is reported and the event
+// dropped, so one bad message cannot take down chat for every room.
+func (h *Hub) handleNext() {
+	defer h.recoverPanic(nil)
 
-		case message := <-h.broadcast:
-			h.broadcastMessage(message)
-		}
+	select {
+	case client := <-h.register:
+		h.registerClient(client)
+
+	case client := <-h.unregister:
+		h.unregisterClient(client)
+
+	case message := <-h.broadcast:
+		h.broadcastMessage(message)
+
+	case dm := <-h.direct:
+		h.sendDirect(dm)
+
+	case n := <-h.notice:
+		h.notifyUsers(n)
+
+	case sm := <-h.signal:
+		h.handleSignal(sm)
+	}
+}
+
+// ReportErrors sends panics on the hub's loop and client goroutines, and unexpected connection
+// errors, to reporter. Call it before Run.
+func (h *Hub) ReportErrors(reporter errreport.Reporter) {
+	h.reporter = reporter
+}
+
+// LimitConnections caps the connections one user may have open, in all rooms and in any one
+// room; 0 means no limit. When a user opens one too many, their oldest connection is closed
+// with CloseTooManyConnections. Call it before Run.
+func (h *Hub) LimitConnections(perUser, perUserRoom int) {
+	h.maxPerUser = perUser
+	h.maxPerUserRoom = perUserRoom
+}
+
+// LimitMessageRate lets each connection send perSecond chat messages a second, in bursts of
+// up to burst. Connections that keep going over are warned, then muted for increasing periods.
+// 0 means no limit. Call it before Run.
+func (h *Hub) LimitMessageRate(perSecond float64, burst int) {
+	h.messageRate = perSecond
+	h.messageBurst = burst
+}
+
+// recoverPanic logs and reports a panic on the hub's loop or, if client is not nil, one of its
+// goroutines. It must be deferred.
+func (h *Hub) recoverPanic(client *Client) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	stack := debug.Stack()
+	if client != nil {
+		log.Printf("PANIC in WebSocket connection %s: %v\n%s", client.connID, v, stack)
+	} else {
+		log.Printf("PANIC in WebSocket hub: %v\n%s", v, stack)
+	}
+	h.report(client, errreport.Panic(v, stack))
+}
+
+// report sends an event to the reporter, with the session it happened in if client is not nil
+func (h *Hub) report(client *Client, ev *errreport.Event) {
+	if h.reporter == nil {
+		return
+	}
+	ev.Tags = map[string]string{"component": "websocket"}
+	if client != nil {
+		ev.RequestID = client.connID
+		ev.UserID = client.userID
+		ev.Tags["room"] = client.room
+	}
+	h.reporter.Report(ev)
+}
+
+// OnMessage registers fn to be called with every chat message broadcast to a room.
+// fn runs on the hub's loop and must not block. Register listeners before calling Run.
+func (h *Hub) OnMessage(fn func(*domain.ChatMessage)) {
+	h.listeners = append(h.listeners, fn)
+}
+
+// Publish runs a message from outside the WebSocket (e.g. relayed from Slack) through the
+// filter pipeline and broadcasts it to its room
+func (h *Hub) Publish(ctx context.Context, msg *domain.ChatMessage) error {
+	if err := h.Moderate(ctx, msg); err != nil {
+		return err
+	}
+	return h.Broadcast(ctx, msg)
+}
+
+// Moderate runs a message through the filter pipeline without broadcasting it
+func (h *Hub) Moderate(ctx context.Context, msg *domain.ChatMessage) error {
+	return h.filters.Apply(ctx, msg)
+}
+
+// Broadcast sends a message from outside the WebSocket to its room without filtering it, for
+// messages with no user content to moderate (e.g. updated poll results)
+func (h *Hub) Broadcast(ctx context.Context, msg *domain.ChatMessage) error {
+	select {
+	case h.broadcast <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Notify sends a message from outside the WebSocket to every connection of the given users,
+// whichever room it is in, without recording it in room history (e.g. system announcements).
+// Users who aren't connected don't get it.
+func (h *Hub) Notify(ctx context.Context, userIDs []string, msg *domain.ChatMessage) error {
+	select {
+	case h.notice <- &userNotice{userIDs: userIDs, message: msg}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
@@ -61,6 +238,7 @@ func (h *Hub) registerClient(client *Client) {
 	}
 
 	h.rooms[client.room][client] = true
+	h.users[client.userID] = append(h.users[client.userID], client)
 
 	// Broadcast join message to room
 	joinMessage := domain.NewChatMessage(
@@ -71,6 +249,39 @@ func (h *Hub) registerClient(client *Client) {
 		"join",
 	)
 	h.broadcastToRoom(client.room, joinMessage)
+
+	h.enforceLimits(client)
+}
+
+// enforceLimits closes a user's oldest connections while the one just registered puts them over
+// a limit (must hold lock)
+func (h *Hub) enforceLimits(client *Client) {
+	if h.maxPerUserRoom > 0 {
+		for {
+			var inRoom []*Client
+			for _, c := range h.users[client.userID] {
+				if c.room == client.room {
+					inRoom = append(inRoom, c)
+				}
+			}
+			if len(inRoom) <= h.maxPerUserRoom {
+				break
+			}
+			h.evict(inRoom[0], "too many connections to this room")
+		}
+	}
+	if h.maxPerUser > 0 {
+		for len(h.users[client.userID]) > h.maxPerUser {
+			h.evict(h.users[client.userID][0], "too many connections")
+		}
+	}
+}
+
+// evict closes a connection with CloseTooManyConnections (must hold lock)
+func (h *Hub) evict(client *Client, reason string) {
+	log.Printf("Closing WebSocket connection %s: %s for user %s", client.connID, reason, client.userID)
+	client.closeCode, client.closeReason = CloseTooManyConnections, reason
+	h.leaveRoom(client)
 }
 
 // unregisterClient removes a client from a room
@@ -78,8 +289,15 @@ func (h *Hub) unregisterClient(client *Client) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	h.leaveRoom(client)
+}
+
+// leaveRoom announces a client is leaving and removes it, if it is still registered (must hold lock)
+func (h *Hub) leaveRoom(client *Client) {
 	if room, ok := h.rooms[client.room]; ok {
 		if _, ok := room[client]; ok {
+			h.leaveVoice(client)
+
 			// Broadcast leave message before removing
 			leaveMessage := domain.NewChatMessage(
 				client.room,
@@ -90,25 +308,95 @@ func (h *Hub) unregisterClient(client *Client) {
 			)
 			h.broadcastToRoomExcept(client.room, leaveMessage, client)
 
-			delete(room, client)
-			close(client.send)
+			h.drop(client)
+		}
+	}
+}
 
-			// Clean up empty rooms
-			if len(room) == 0 {
-				delete(h.rooms, client.room)
-			}
+// drop removes a client from its room and closes its send channel, which ends its
+// connection. The last client out takes the room and its history with it (must hold lock).
+func (h *Hub) drop(client *Client) {
+	room := h.rooms[client.room]
+	delete(room, client)
+	close(client.send)
+	if len(room) == 0 {
+		delete(h.rooms, client.room)
+		delete(h.history, client.room)
+	}
+
+	conns := h.users[client.userID]
+	for i, c := range conns {
+		if c == client {
+			conns = append(conns[:i:i], conns[i+1:]...)
+			break
 		}
 	}
+	if len(conns) == 0 {
+		delete(h.users, client.userID)
+	} else {
+		h.users[client.userID] = conns
+	}
 }
 
-// broadcastMessage sends a message to all clients in a room
+// broadcastMessage sends a message to all clients in a room and records it in the room history,
+// if anyone is there to see it
 func (h *Hub) broadcastMessage(message *domain.ChatMessage) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if message.Type == "message" {
+		if message.ReplyTo != "" {
+			message.ReplyCount = len(h.replies(message.Room, message.ReplyTo)) + 1
+		}
+		if len(h.rooms[message.Room]) > 0 {
+			history := append(h.history[message.Room], message)
+			if len(history) > historySize {
+				history = history[len(history)-historySize:]
+			}
+			h.history[message.Room] = history
+		}
+
+		for _, fn := range h.listeners {
+			fn(message)
+		}
+	}
 
 	h.broadcastToRoom(message.Room, message)
 }
 
+// sendDirect delivers a message to a single client if it is still connected
+func (h *Hub) sendDirect(dm *directMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients, ok := h.rooms[dm.client.room]
+	if !ok || !clients[dm.client] {
+		return
+	}
+	select {
+	case dm.client.send <- dm.message:
+	default:
+		h.drop(dm.client)
+	}
+}
+
+// notifyUsers delivers a notice to each connection of its users
+func (h *Hub) notifyUsers(n *userNotice) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, userID := range n.userIDs {
+		// drop copies the user's connections, so this slice stays intact
+		for _, client := range h.users[userID] {
+			select {
+			case client.send <- n.message:
+			default:
+				h.drop(client)
+			}
+		}
+	}
+}
+
 // broadcastToRoom sends a message to all clients in a specific room (must hold lock)
 func (h *Hub) broadcastToRoom(room string, message *domain.ChatMessage) {
 	if clients, ok := h.rooms[room]; ok {
@@ -117,8 +405,7 @@ func (h *Hub) broadcastToRoom(room string, message *domain.ChatMessage) {
 			case client.send <- message:
 			default:
 				// Client's send buffer is full, close connection
-				close(client.send)
-				delete(clients, client)
+				h.drop(client)
 			}
 		}
 	}
@@ -134,13 +421,57 @@ func (h *Hub) broadcastToRoomExcept(room string, message *domain.ChatMessage, ex
 			select {
 			case client.send <- message:
 			default:
-				close(client.send)
-				delete(clients, client)
+				h.drop(client)
 			}
 		}
 	}
 }
 
+// FindMessage returns a recently broadcast message from a room, or nil if it is no longer retained
+func (h *Hub) FindMessage(room, id string) *domain.ChatMessage {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.findMessage(room, id)
+}
+
+// findMessage returns a retained message from a room, or nil (must hold lock)
+func (h *Hub) findMessage(room, id string) *domain.ChatMessage {
+	for _, message := range h.history[room] {
+		if message.ID == id {
+			return message
+		}
+	}
+	return nil
+}
+
+// Thread returns a recently broadcast message and the replies to it that are still retained,
+// or nil if the message is no longer retained. Given a reply, it returns the reply's thread.
+func (h *Hub) Thread(room, id string) *domain.ChatThread {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	root := h.findMessage(room, id)
+	if root != nil && root.ReplyTo != "" {
+		root = h.findMessage(room, root.ReplyTo)
+	}
+	if root == nil || root.Type != "message" {
+		return nil
+	}
+	return &domain.ChatThread{Root: root, Replies: h.replies(room, root.ID)}
+}
+
+// replies returns the retained replies to a message, oldest first (must hold lock)
+func (h *Hub) replies(room, id string) []*domain.ChatMessage {
+	replies := []*domain.ChatMessage{}
+	for _, message := range h.history[room] {
+		if message.ReplyTo == id {
+			replies = append(replies, message)
+		}
+	}
+	return replies
+}
+
 // GetRoomClients returns the number of clients in a room
 func (h *Hub) GetRoomClients(room string) int {
 	h.mu.RLock()
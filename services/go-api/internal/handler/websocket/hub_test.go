@@ -0,0 +1,188 @@
+package websocket
+
+import (
+	"testing"
+
+	"devjournal/internal/domain"
+)
+
+func TestHubLimitConnections(t *testing.T) {
+	hub := NewHub(nil)
+	hub.LimitConnections(3, 2)
+
+	connect := func(room, userID string) *Client {
+		client := NewClient(hub, nil, room+"-"+userID, room, userID, userID)
+		hub.registerClient(client)
+		return client
+	}
+	closed := func(c *Client) bool {
+		for {
+			select {
+			case _, ok := <-c.send:
+				if !ok {
+					return true
+				}
+			default:
+				return false
+			}
+		}
+	}
+
+	first := connect("general", "ada")
+	second := connect("general", "ada")
+	connect("general", "grace")
+	if closed(first) || closed(second) {
+		t.Fatal("connections within the limits were closed")
+	}
+
+	third := connect("general", "ada")
+	if !closed(first) || first.closeCode != CloseTooManyConnections {
+		t.Fatalf("oldest connection in the room was not evicted (close code %d)", first.closeCode)
+	}
+	if closed(second) || closed(third) {
+		t.Fatal("newer connections in the room were closed")
+	}
+
+	connect("go", "ada")
+	connect("rust", "ada")
+	if !closed(second) {
+		t.Fatal("oldest connection overall was not evicted")
+	}
+	if got := len(hub.users["ada"]); got != 3 {
+		t.Fatalf("ada has %d connections, want 3", got)
+	}
+	if got := hub.GetRoomClients("general"); got != 2 {
+		t.Fatalf("general has %d clients, want 2", got)
+	}
+}
+
+func TestHubThread(t *testing.T) {
+	hub := NewHub(nil)
+	hub.registerClient(NewClient(hub, nil, "general-grace", "general", "grace", "grace"))
+	post := func(content, replyTo string) *domain.ChatMessage {
+		message := domain.NewChatMessage("general", "ada", "Ada", content, "message")
+		message.ReplyTo = replyTo
+		hub.broadcastMessage(message)
+		return message
+	}
+
+	root := post("How do I cancel a goroutine?", "")
+	post("Unrelated", "")
+	first := post("Pass it a context", root.ID)
+	second := post("And select on ctx.Done()", root.ID)
+	if first.ReplyCount != 1 || second.ReplyCount != 2 {
+		t.Fatalf("reply counts = %d, %d; want 1, 2", first.ReplyCount, second.ReplyCount)
+	}
+
+	thread := hub.Thread("general", root.ID)
+	if thread == nil || thread.Root != root || len(thread.Replies) != 2 || thread.Replies[0] != first {
+		t.Fatalf("Thread(root) = %+v", thread)
+	}
+	if fromReply := hub.Thread("general", second.ID); fromReply == nil || fromReply.Root != root {
+		t.Fatalf("Thread(reply) = %+v, want the root's thread", fromReply)
+	}
+	if hub.Thread("general", "missing") != nil || hub.Thread("other", root.ID) != nil {
+		t.Fatal("Thread found a message that is not in the room")
+	}
+}
+
+func TestHubHistoryEviction(t *testing.T) {
+	hub := NewHub(nil)
+	post := func(room string) *domain.ChatMessage {
+		message := domain.NewChatMessage(room, "ada", "Ada", "Anyone here?", "message")
+		hub.broadcastMessage(message)
+		return message
+	}
+
+	// Nobody is in the room to see it, so there is nothing to report or reply to
+	if message := post("empty"); hub.FindMessage("empty", message.ID) != nil || len(hub.history) != 0 {
+		t.Fatal("history kept for a room without clients")
+	}
+
+	ada := NewClient(hub, nil, "general-ada", "general", "ada", "ada")
+	grace := NewClient(hub, nil, "general-grace", "general", "grace", "grace")
+	hub.registerClient(ada)
+	hub.registerClient(grace)
+	message := post("general")
+
+	hub.unregisterClient(ada)
+	if hub.FindMessage("general", message.ID) == nil {
+		t.Fatal("history dropped while the room still has a client")
+	}
+	hub.unregisterClient(grace)
+	if hub.FindMessage("general", message.ID) != nil || len(hub.history) != 0 || len(hub.rooms) != 0 {
+		t.Fatalf("last client left but %d rooms and %d histories remain", len(hub.rooms), len(hub.history))
+	}
+}
+
+func TestHubJoinVoiceOnly(t *testing.T) {
+	hub := NewHub(nil)
+	connect := func(userID string, joinOnly bool) *Client {
+		client := NewClient(hub, nil, "general-"+userID, "general", userID, userID)
+		client.joinVoiceOnly = joinOnly
+		hub.registerClient(client)
+		return client
+	}
+	received := func(c *Client) []string {
+		var types []string
+		for {
+			select {
+			case message := <-c.send:
+				types = append(types, message.Type)
+			default:
+				return types
+			}
+		}
+	}
+	join := func(c *Client) {
+		hub.handleSignal(&signalMessage{client: c, message: domain.NewChatMessage(c.room, c.userID, c.userID, "", SignalVoiceJoin)})
+	}
+
+	member := connect("grace", true)
+	owner := connect("ada", false)
+	received(member)
+	received(owner)
+
+	join(member)
+	if got := received(member); len(got) != 1 || got[0] != "system" || len(hub.voice["general"]) != 0 {
+		t.Fatalf("join-only client starting a session got %v, participants %d", got, len(hub.voice["general"]))
+	}
+
+	join(owner)
+	join(member)
+	if got := received(member); len(got) != 2 || got[0] != SignalVoiceJoin || got[1] != SignalVoicePeers || len(hub.voice["general"]) != 2 {
+		t.Fatalf("join-only client joining a session got %v, participants %d", got, len(hub.voice["general"]))
+	}
+}
+
+func TestHubNotifyUsers(t *testing.T) {
+	hub := NewHub(nil)
+	connect := func(room, userID string) *Client {
+		client := NewClient(hub, nil, room+"-"+userID, room, userID, userID)
+		hub.registerClient(client)
+		return client
+	}
+	notices := func(c *Client) int {
+		count := 0
+		for {
+			select {
+			case message := <-c.send:
+				if message.Type == "system" {
+					count++
+				}
+			default:
+				return count
+			}
+		}
+	}
+
+	adaGeneral, adaGo := connect("general", "ada"), connect("go", "ada")
+	grace := connect("general", "grace")
+	hub.notifyUsers(&userNotice{
+		userIDs: []string{"ada", "linus"},
+		message: domain.NewChatMessage("", "", "System", "Maintenance tonight", "system"),
+	})
+	if notices(adaGeneral) != 1 || notices(adaGo) != 1 || notices(grace) != 0 {
+		t.Fatal("notice did not reach exactly every connection of the notified users")
+	}
+}
@@ -0,0 +1,124 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"devjournal/internal/domain"
+)
+
+// WebRTC signaling message types. offer/answer/ice are relayed peer-to-peer within
+// a room; voice-join/voice-leave announce voice session membership to the room.
+const (
+	SignalOffer      = "offer"
+	SignalAnswer     = "answer"
+	SignalICE        = "ice"
+	SignalVoiceJoin  = "voice-join"
+	SignalVoiceLeave = "voice-leave"
+	SignalVoicePeers = "voice-peers"
+)
+
+// isPeerSignal reports whether a message type is relayed to a single peer
+func isPeerSignal(msgType string) bool {
+	return msgType == SignalOffer || msgType == SignalAnswer || msgType == SignalICE
+}
+
+// isVoicePresence reports whether a message type changes voice session membership
+func isVoicePresence(msgType string) bool {
+	return msgType == SignalVoiceJoin || msgType == SignalVoiceLeave
+}
+
+// relaySignal forwards a peer signal to the target user's connections in the same room (must hold lock)
+func (h *Hub) relaySignal(message *domain.ChatMessage, sender *Client) {
+	clients, ok := h.rooms[message.Room]
+	if !ok {
+		return
+	}
+	for client := range clients {
+		if client == sender || client.userID != message.TargetUserID {
+			continue
+		}
+		select {
+		case client.send <- message:
+		default:
+			h.drop(client)
+		}
+	}
+}
+
+// joinVoice adds a client to its room's voice session, replies with the current
+// participants so it can start offers, and announces the join to the room. Clients that may
+// only join a session are told so if the room has none yet (must hold lock).
+func (h *Hub) joinVoice(client *Client) {
+	participants, ok := h.voice[client.room]
+	if !ok && client.joinVoiceOnly {
+		notice := domain.NewChatMessage(client.room, "", "System", "only group owners and admins can start a voice session in this group", "system")
+		notice.ConnectionID = client.connID
+		select {
+		case client.send <- notice:
+		default:
+		}
+		return
+	}
+	if !ok {
+		participants = make(map[*Client]bool)
+		h.voice[client.room] = participants
+	}
+
+	peers := make([]string, 0, len(participants))
+	for peer := range participants {
+		if peer.userID != client.userID {
+			peers = append(peers, peer.userID)
+		}
+	}
+	participants[client] = true
+
+	peerList, _ := json.Marshal(peers)
+	reply := domain.NewChatMessage(client.room, "", "System", "", SignalVoicePeers)
+	reply.Signal = peerList
+	select {
+	case client.send <- reply:
+	default:
+	}
+
+	h.broadcastToRoomExcept(client.room, domain.NewChatMessage(client.room, client.userID, client.userName, "", SignalVoiceJoin), client)
+}
+
+// leaveVoice removes a client from its room's voice session and announces it (must hold lock)
+func (h *Hub) leaveVoice(client *Client) {
+	participants, ok := h.voice[client.room]
+	if !ok || !participants[client] {
+		return
+	}
+	delete(participants, client)
+	if len(participants) == 0 {
+		delete(h.voice, client.room)
+	}
+
+	h.broadcastToRoomExcept(client.room, domain.NewChatMessage(client.room, client.userID, client.userName, "", SignalVoiceLeave), client)
+}
+
+// handleSignal routes a signaling message from a client
+func (h *Hub) handleSignal(sm *signalMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Ignore signals from clients the hub has already dropped
+	if clients, ok := h.rooms[sm.client.room]; !ok || !clients[sm.client] {
+		return
+	}
+
+	switch {
+	case isPeerSignal(sm.message.Type):
+		h.relaySignal(sm.message, sm.client)
+	case sm.message.Type == SignalVoiceJoin:
+		h.joinVoice(sm.client)
+	case sm.message.Type == SignalVoiceLeave:
+		h.leaveVoice(sm.client)
+	}
+}
+
+// signalMessage is a signaling message along with the client that sent it
+type signalMessage struct {
+	client  *Client
+	message *domain.ChatMessage
+}
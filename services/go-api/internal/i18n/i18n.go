@@ -0,0 +1,228 @@
+// Package i18n translates user-facing server messages: error messages, push notifications, and
+// emails. Messages are keyed by their English text, so messages without a translation, and
+// locales without a catalog, fall back to English.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the locale messages are written in
+const DefaultLocale = "en"
+
+// Catalog looks up translations of messages
+type Catalog interface {
+	// Lookup returns the translation of an English message into locale, if there is one
+	Lookup(locale, message string) (string, bool)
+	// Locales lists the locales the catalog translates into, as BCP 47 tags
+	Locales() []string
+}
+
+//go:embed locales/*.json
+var builtin embed.FS
+
+// Bundle is a Catalog held in memory, loaded from JSON files named after their locale (e.g.
+// es.json or pt-BR.json), each an object mapping English messages to their translation
+type Bundle struct {
+	messages map[string]map[string]string // by locale, then English message
+}
+
+// Load reads the built-in catalogs, then the JSON files in dir, which add locales or override
+// built-in translations. An empty dir loads only the built-in catalogs.
+func Load(dir string) (*Bundle, error) {
+	b := &Bundle{messages: make(map[string]map[string]string)}
+	if err := b.add(builtin, "locales"); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := b.add(os.DirFS(dir), "."); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (b *Bundle) add(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		tag, err := language.Parse(strings.TrimSuffix(path.Base(file), ".json"))
+		if err != nil {
+			return fmt.Errorf("catalog %s is not named after a locale: %w", file, err)
+		}
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("failed to read catalog %s: %w", file, err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("failed to parse catalog %s: %w", file, err)
+		}
+
+		locale := tag.String()
+		if b.messages[locale] == nil {
+			b.messages[locale] = make(map[string]string, len(messages))
+		}
+		for message, translation := range messages {
+			b.messages[locale][message] = translation
+		}
+	}
+	return nil
+}
+
+// Lookup returns the translation of message into locale
+func (b *Bundle) Lookup(locale, message string) (string, bool) {
+	translation, ok := b.messages[locale][message]
+	return translation, ok && translation != ""
+}
+
+// Locales returns the bundle's locales in order
+func (b *Bundle) Locales() []string {
+	locales := make([]string, 0, len(b.messages))
+	for locale := range b.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// installed is a catalog with a matcher over its locales and English
+type installed struct {
+	catalog Catalog
+	locales []string // DefaultLocale first
+	matcher language.Matcher
+}
+
+func install(c Catalog) *installed {
+	in := &installed{catalog: c, locales: []string{DefaultLocale}}
+	for _, locale := range c.Locales() {
+		if locale != DefaultLocale {
+			in.locales = append(in.locales, locale)
+		}
+	}
+	tags := make([]language.Tag, len(in.locales))
+	for i, locale := range in.locales {
+		tags[i] = language.Make(locale)
+	}
+	in.matcher = language.NewMatcher(tags)
+	return in
+}
+
+// current is the process-wide catalog
+var current atomic.Pointer[installed]
+
+var builtinCatalog = sync.OnceValue(func() *installed {
+	b, err := Load("")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: invalid built-in catalog: %v", err))
+	}
+	return install(b)
+})
+
+func active() *installed {
+	if in := current.Load(); in != nil {
+		return in
+	}
+	return builtinCatalog()
+}
+
+// Use installs c as the process-wide catalog. Before Use is called, the built-in catalogs are used.
+func Use(c Catalog) {
+	current.Store(install(c))
+}
+
+// Locales lists the supported locales, English first
+func Locales() []string {
+	return append([]string(nil), active().locales...)
+}
+
+// Match returns the supported locale that best fits an Accept-Language header, or DefaultLocale
+func Match(acceptLanguage string) string {
+	in := active()
+	_, i := language.MatchStrings(in.matcher, acceptLanguage)
+	return in.locales[i]
+}
+
+// Supported returns the supported locale that a locale setting such as "es" or "es-MX" selects,
+// or false if there is none
+func Supported(locale string) (string, bool) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", false
+	}
+	in := active()
+	_, i, confidence := in.matcher.Match(tag)
+	if confidence < language.High {
+		return "", false
+	}
+	return in.locales[i], true
+}
+
+// T translates an English message into locale and, given args, formats it like fmt.Sprintf.
+// An empty locale means English.
+func T(locale, message string, args ...any) string {
+	if locale != "" && locale != DefaultLocale {
+		if translation, ok := active().catalog.Lookup(locale, message); ok {
+			message = translation
+		}
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Message is text to translate once the locale of whoever reads it is known, such as a
+// notification queued for several recipients
+type Message struct {
+	Text string
+	Args []any
+	raw  bool
+}
+
+// M creates a message from an English format and its arguments
+func M(text string, args ...any) Message {
+	return Message{Text: text, Args: args}
+}
+
+// Raw creates a message that is shown as is in every locale, such as user content
+func Raw(text string) Message {
+	return Message{Text: text, raw: true}
+}
+
+// In returns the message in locale
+func (m Message) In(locale string) string {
+	if m.raw {
+		return m.Text
+	}
+	return T(locale, m.Text, m.Args...)
+}
+
+type contextKey struct{}
+
+// WithLocale returns a context carrying the locale of the request being served
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale of the request being served, or DefaultLocale
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(contextKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,74 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// verb matches fmt verbs, with an optional explicit argument index
+var verb = regexp.MustCompile(`%(\[\d+\])?[a-zA-Z%]`)
+
+func TestBuiltinCatalogsKeepVerbs(t *testing.T) {
+	b, err := Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, locale := range b.Locales() {
+		for message, translation := range b.messages[locale] {
+			want, got := verb.FindAllString(message, -1), verb.FindAllString(translation, -1)
+			slices.Sort(want)
+			slices.Sort(got)
+			if !slices.Equal(want, got) {
+				t.Errorf("%s: %q has verbs %v, want %v like %q", locale, translation, got, want, message)
+			}
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"", "en"},
+		{"es", "es"},
+		{"es-MX,es;q=0.9,en;q=0.8", "es"},
+		{"ja, fr;q=0.5", "fr"},
+		{"ja", "en"},
+		{"de-CH;q=0.9, en;q=0.1", "de"},
+		{"not a header", "en"},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.acceptLanguage); got != tt.want {
+			t.Errorf("Match(%q) = %q, want %q", tt.acceptLanguage, got, tt.want)
+		}
+	}
+}
+
+func TestSupported(t *testing.T) {
+	for locale, want := range map[string]string{"es": "es", "fr-CA": "fr", "en-GB": "en", "ja": "", "xx-invalid!": ""} {
+		got, ok := Supported(locale)
+		if got != want || ok != (want != "") {
+			t.Errorf("Supported(%q) = %q, %v, want %q", locale, got, ok, want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T("es", "snippet not found"); got != "fragmento no encontrado" {
+		t.Errorf("T(es) = %q", got)
+	}
+	if got := T("es", "%d of %d comments resolved", 2, 3); got != "2 de 3 comentarios resueltos" {
+		t.Errorf("T(es) with args = %q", got)
+	}
+	if got := T("es", "no translation for 100%"); got != "no translation for 100%" {
+		t.Errorf("T without a translation or args = %q", got)
+	}
+	if got := M("%s reopened the review of %s", "Ada", "sort.go").In(""); got != "Ada reopened the review of sort.go" {
+		t.Errorf("Message.In(\"\") = %q", got)
+	}
+	if got := Raw("50% done").In("de"); got != "50% done" {
+		t.Errorf("Raw.In = %q", got)
+	}
+}
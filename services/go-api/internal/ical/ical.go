@@ -0,0 +1,136 @@
+// Package ical writes iCalendar (RFC 5545) feeds for calendar apps to subscribe to
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding
+const maxLineOctets = 75
+
+// Event is a calendar event. All-day events use only the dates of Start and End,
+// with End exclusive (the day after the last day).
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Summary     string
+	Description string
+	URL         string
+	Transparent bool // does not block time as busy
+}
+
+// Calendar is a feed of events
+type Calendar struct {
+	ProdID          string
+	Name            string
+	Description     string
+	RefreshInterval time.Duration // how often subscribers should re-fetch
+	Events          []Event
+}
+
+// Write encodes the calendar as text/calendar
+func (c *Calendar) Write(w io.Writer, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	l := lineWriter{w: bw}
+
+	l.line("BEGIN:VCALENDAR")
+	l.line("VERSION:2.0")
+	l.line("PRODID:" + c.ProdID)
+	l.line("CALSCALE:GREGORIAN")
+	l.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		l.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	if c.Description != "" {
+		l.line("X-WR-CALDESC:" + escape(c.Description))
+	}
+	if c.RefreshInterval > 0 {
+		interval := duration(c.RefreshInterval)
+		l.line("REFRESH-INTERVAL;VALUE=DURATION:" + interval)
+		l.line("X-PUBLISHED-TTL:" + interval)
+	}
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, e := range c.Events {
+		l.line("BEGIN:VEVENT")
+		l.line("UID:" + e.UID)
+		l.line("DTSTAMP:" + stamp)
+		if e.AllDay {
+			l.line("DTSTART;VALUE=DATE:" + e.Start.Format("20060102"))
+			l.line("DTEND;VALUE=DATE:" + e.End.Format("20060102"))
+		} else {
+			l.line("DTSTART:" + e.Start.UTC().Format("20060102T150405Z"))
+			l.line("DTEND:" + e.End.UTC().Format("20060102T150405Z"))
+		}
+		l.line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			l.line("DESCRIPTION:" + escape(e.Description))
+		}
+		if e.URL != "" {
+			l.line("URL:" + e.URL)
+		}
+		if e.Transparent {
+			l.line("TRANSP:TRANSPARENT")
+		}
+		l.line("END:VEVENT")
+	}
+	l.line("END:VCALENDAR")
+
+	if l.err != nil {
+		return l.err
+	}
+	return bw.Flush()
+}
+
+// lineWriter writes CRLF-terminated content lines, folding long ones, and remembers the first error
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes one content line, folding it into 75-octet lines without splitting a UTF-8 character
+func (l *lineWriter) line(s string) {
+	if l.err != nil {
+		return
+	}
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		l.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1 // continuation lines start with a space
+	}
+	l.write(s + "\r\n")
+}
+
+func (l *lineWriter) write(s string) {
+	if l.err == nil {
+		_, l.err = l.w.WriteString(s)
+	}
+}
+
+// textEscaper escapes TEXT values per RFC 5545 section 3.3.11
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escape(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// duration formats a duration as an RFC 5545 DURATION in whole minutes, e.g. PT6H or PT90M
+func duration(d time.Duration) string {
+	minutes := int(d.Minutes())
+	if minutes%60 == 0 {
+		return fmt.Sprintf("PT%dH", minutes/60)
+	}
+	return fmt.Sprintf("PT%dM", minutes)
+}
@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+)
+
+// dayOneExport is the JSON file of a Day One journal export
+type dayOneExport struct {
+	Entries []struct {
+		CreationDate string   `json:"creationDate"`
+		ModifiedDate string   `json:"modifiedDate"`
+		Text         string   `json:"text"`
+		Tags         []string `json:"tags"`
+	} `json:"entries"`
+}
+
+// maxDayOneTitle is how much of an entry's first line becomes its title when it has no heading
+const maxDayOneTitle = 80
+
+// parseDayOne reads a Day One journal. Entries have no title of their own, so a leading
+// "# Heading" becomes the title, or else the first line, or else the entry's date.
+// Entries without text are skipped.
+func parseDayOne(res *Result, name string, data []byte, _ time.Time) error {
+	var export dayOneExport
+	if err := json.Unmarshal(bytes.TrimPrefix(data, []byte("\ufeff")), &export); err != nil {
+		return fmt.Errorf("%s is not a Day One JSON export: %w", name, err)
+	}
+
+	for _, e := range export.Entries {
+		text := strings.ReplaceAll(strings.TrimSpace(e.Text), "\r\n", "\n")
+		if text == "" {
+			// Photo-only entries have nothing to import
+			res.Skipped++
+			continue
+		}
+		note := domain.ImportedNote{
+			CreatedAt: parseTime(e.CreationDate, time.RFC3339),
+			UpdatedAt: parseTime(e.ModifiedDate, time.RFC3339),
+			Tags:      addTags(nil, e.Tags...),
+		}
+
+		line, rest := splitFirstLine(text)
+		switch {
+		case strings.HasPrefix(line, "# "):
+			note.Title = strings.TrimPrefix(line, "# ")
+			note.Content = rest
+		default:
+			note.Title = line
+			if runes := []rune(line); len(runes) > maxDayOneTitle {
+				note.Title = string(runes[:maxDayOneTitle]) + "…"
+			}
+			note.Content = text
+		}
+		// Day One escapes Markdown characters, which read as clutter in a title
+		note.Title = strings.ReplaceAll(note.Title, `\`, "")
+		if strings.TrimSpace(note.Title) == "" && !note.CreatedAt.IsZero() {
+			note.Title = note.CreatedAt.Format("January 2, 2006")
+		}
+		res.Notes = append(res.Notes, note)
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+// Package importer reads exports from other journaling apps (Notion, Obsidian, and Day One) as
+// notes to import as journal entries
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+)
+
+const (
+	// MaxNotes caps how many notes one import reads; the rest are skipped
+	MaxNotes = 10000
+
+	// maxNoteSize caps one note's file in Notion and Obsidian exports; bigger notes are skipped
+	maxNoteSize = 1 << 20
+
+	// maxUncompressed caps the total size read from an archive, so a zip bomb can't exhaust memory
+	maxUncompressed = 256 << 20
+
+	// maxTitleLength is the longest journal entry title, in characters
+	maxTitleLength = 255
+)
+
+var (
+	// ErrUnknownSource is returned for sources other than notion, obsidian, and dayone
+	ErrUnknownSource = errors.New("unknown import source")
+	// ErrNoNotes is returned when an export has nothing to import
+	ErrNoNotes = errors.New("no notes found in the export")
+)
+
+// Result is what an export holds: the notes to import in a stable order, and how many were
+// skipped for breaking the limits
+type Result struct {
+	Notes   []domain.ImportedNote
+	Skipped int
+}
+
+// Parse reads an export from the given source. The same data always yields the same notes in
+// the same order, so an interrupted import can resume by position.
+func Parse(source string, data []byte) (*Result, error) {
+	var res *Result
+	var err error
+	switch source {
+	case domain.ImportNotion:
+		res, err = parseArchive(data, ".md", maxNoteSize, parseNotion)
+	case domain.ImportObsidian:
+		res, err = parseArchive(data, ".md", maxNoteSize, parseObsidian)
+	case domain.ImportDayOne:
+		// Day One exports a whole journal as one JSON file
+		if isZip(data) {
+			res, err = parseArchive(data, ".json", maxUncompressed, parseDayOne)
+		} else {
+			res = &Result{}
+			err = parseDayOne(res, "Journal.json", data, time.Time{})
+		}
+	default:
+		return nil, ErrUnknownSource
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Notes) > MaxNotes {
+		res.Skipped += len(res.Notes) - MaxNotes
+		res.Notes = res.Notes[:MaxNotes]
+	}
+	if len(res.Notes) == 0 && res.Skipped == 0 {
+		return nil, ErrNoNotes
+	}
+	for i := range res.Notes {
+		clean(&res.Notes[i])
+	}
+	return res, nil
+}
+
+// fileParser reads the notes in one file of an archive. modified is the file's time in the archive.
+type fileParser func(res *Result, name string, data []byte, modified time.Time) error
+
+func isZip(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("PK\x03\x04"))
+}
+
+// parseArchive runs parse over the files of a zip with the given extension, including those in
+// zips inside it, as Notion splits large exports into parts. Files bigger than fileLimit are
+// skipped, and other files, such as attachments, are ignored.
+func parseArchive(data []byte, ext string, fileLimit int64, parse fileParser) (*Result, error) {
+	res := &Result{}
+	budget := int64(maxUncompressed)
+	if err := walkZip(data, ext, fileLimit, 0, &budget, res, parse); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func walkZip(data []byte, ext string, fileLimit int64, depth int, budget *int64, res *Result, parse fileParser) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("the upload is not a zip archive: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || hiddenPath(f.Name) {
+			continue
+		}
+		nested := strings.EqualFold(path.Ext(f.Name), ".zip")
+		limit := min(fileLimit, *budget)
+		switch {
+		case nested && depth == 0:
+			limit = *budget
+		case nested || !strings.EqualFold(path.Ext(f.Name), ext):
+			continue
+		}
+		if f.UncompressedSize64 > uint64(limit) {
+			res.Skipped++
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", f.Name, err)
+		}
+		body, err := io.ReadAll(io.LimitReader(rc, limit+1))
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		if int64(len(body)) > limit {
+			res.Skipped++
+			continue
+		}
+		if *budget -= int64(len(body)); *budget < 0 {
+			return errors.New("the export is too large")
+		}
+
+		if nested {
+			if err := walkZip(body, ext, fileLimit, depth+1, budget, res, parse); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := parse(res, f.Name, body, f.Modified); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hiddenPath reports whether a path is inside a dot folder or is a dot file, such as .obsidian,
+// .trash, or macOS's __MACOSX metadata
+func hiddenPath(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
+
+// splitFirstLine returns the first line of text and the rest
+func splitFirstLine(text string) (string, string) {
+	line, rest, _ := strings.Cut(text, "\n")
+	return strings.TrimRight(line, "\r"), rest
+}
+
+// addTags appends tags that aren't there yet, trimming spaces and leading #
+func addTags(tags []string, add ...string) []string {
+	for _, tag := range add {
+		tag = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" || containsFold(tags, tag) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTime reads a date in any of the layouts exports use, returning the zero time if none fit
+func parseTime(value string, layouts ...string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// clean makes a note storable: valid UTF-8 without NUL bytes, a title of at most maxTitleLength
+// characters, and tags that are never nil
+func clean(note *domain.ImportedNote) {
+	fix := func(s string) string {
+		return strings.ReplaceAll(strings.ToValidUTF8(s, "\uFFFD"), "\x00", "")
+	}
+	note.Title = strings.TrimSpace(fix(note.Title))
+	if utf8.RuneCountInString(note.Title) > maxTitleLength {
+		note.Title = strings.TrimSpace(string([]rune(note.Title)[:maxTitleLength]))
+	}
+	note.Content = strings.TrimSpace(fix(note.Content))
+	tags := make([]string, 0, len(note.Tags))
+	for _, tag := range note.Tags {
+		tags = addTags(tags, fix(tag))
+	}
+	note.Tags = tags
+	if note.UpdatedAt.Before(note.CreatedAt) {
+		note.UpdatedAt = note.CreatedAt
+	}
+}
+
+// Check cheaply tells whether data looks like an export from source, so uploads of the wrong
+// kind of file are refused up front rather than failing in the background
+func Check(source string, data []byte) error {
+	switch source {
+	case domain.ImportNotion, domain.ImportObsidian:
+		if !isZip(data) {
+			return errors.New("the upload is not a zip archive")
+		}
+	case domain.ImportDayOne:
+		if !isZip(data) && !bytes.HasPrefix(bytes.TrimLeft(data, "\ufeff \t\r\n"), []byte("{")) {
+			return errors.New("the upload is neither a zip archive nor a Day One JSON export")
+		}
+	default:
+		return ErrUnknownSource
+	}
+	return nil
+}
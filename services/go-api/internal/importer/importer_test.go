@@ -0,0 +1,215 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+)
+
+// zipOf builds a zip archive of name/content pairs
+func zipOf(t testing.TB, files ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i < len(files); i += 2 {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     files[i],
+			Method:   zip.Deflate,
+			Modified: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParse(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	fileTime := date("2024-03-01T12:00:00Z")
+
+	tests := []struct {
+		name        string
+		source      string
+		data        []byte
+		want        []domain.ImportedNote
+		wantSkipped int
+	}{
+		{
+			name:   "notion page with properties",
+			source: domain.ImportNotion,
+			data: zipOf(t,
+				"Export/Goroutines 0123456789abcdef0123456789abcdef.md",
+				"# Goroutines\n\nTags: go, concurrency\nCreated: January 5, 2024 9:30 AM\nStatus: Done\n\nChannels are typed.",
+				"Export/Goroutines 0123456789abcdef0123456789abcdef/diagram.png", "\x89PNG",
+			),
+			want: []domain.ImportedNote{{
+				Title:     "Goroutines",
+				Content:   "Status: Done\n\nChannels are typed.",
+				Tags:      []string{"go", "concurrency"},
+				CreatedAt: date("2024-01-05T09:30:00Z"),
+				UpdatedAt: fileTime,
+			}},
+		},
+		{
+			name:   "notion parts in nested zips",
+			source: domain.ImportNotion,
+			data: zipOf(t,
+				"Part-1.zip", string(zipOf(t, "Ideas 0123456789abcdef0123456789abcdef.md", "Plain text"))),
+			want: []domain.ImportedNote{{
+				Title:     "Ideas",
+				Content:   "Plain text",
+				Tags:      []string{},
+				CreatedAt: fileTime,
+				UpdatedAt: fileTime,
+			}},
+		},
+		{
+			name:   "obsidian front matter and inline tags",
+			source: domain.ImportObsidian,
+			data: zipOf(t,
+				"Vault/Daily/2024-02-10.md",
+				"---\ntags: [til, Rust]\ncreated: 2024-02-10\nupdated: 2024-02-11T08:00:00Z\n---\n# Borrowing\nLearned about #lifetimes and #rust.\n```\n#include <stdio.h>\n```",
+				"Vault/.obsidian/app.json", "{}",
+				"Vault/.trash/Old.md", "deleted",
+			),
+			want: []domain.ImportedNote{{
+				Title:     "2024-02-10",
+				Content:   "# Borrowing\nLearned about #lifetimes and #rust.\n```\n#include <stdio.h>\n```",
+				Tags:      []string{"til", "Rust", "lifetimes"},
+				CreatedAt: date("2024-02-10T00:00:00Z"),
+				UpdatedAt: date("2024-02-11T08:00:00Z"),
+			}},
+		},
+		{
+			name:   "day one json",
+			source: domain.ImportDayOne,
+			data: []byte(`{"metadata":{"version":"1.0"},"entries":[
+				{"creationDate":"2023-11-02T18:04:00Z","modifiedDate":"2023-11-03T07:00:00Z","text":"# Shipped v2\\.0\nFinally.","tags":["work"]},
+				{"creationDate":"2023-11-04T10:00:00Z","text":"A quiet day","tags":["#journal"]},
+				{"creationDate":"2023-11-05T10:00:00Z","text":""}
+			]}`),
+			want: []domain.ImportedNote{
+				{
+					Title:     "Shipped v2.0",
+					Content:   "Finally.",
+					Tags:      []string{"work"},
+					CreatedAt: date("2023-11-02T18:04:00Z"),
+					UpdatedAt: date("2023-11-03T07:00:00Z"),
+				},
+				{
+					Title:     "A quiet day",
+					Content:   "A quiet day",
+					Tags:      []string{"journal"},
+					CreatedAt: date("2023-11-04T10:00:00Z"),
+					UpdatedAt: date("2023-11-04T10:00:00Z"),
+				},
+			},
+			wantSkipped: 1,
+		},
+		{
+			name:   "oversized notes are skipped",
+			source: domain.ImportObsidian,
+			data: zipOf(t,
+				"big.md", strings.Repeat("x", maxNoteSize+1),
+				"small.md", "ok",
+			),
+			want: []domain.ImportedNote{{
+				Title:     "small",
+				Content:   "ok",
+				Tags:      []string{},
+				CreatedAt: fileTime,
+				UpdatedAt: fileTime,
+			}},
+			wantSkipped: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Parse(tt.source, tt.data)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if res.Skipped != tt.wantSkipped {
+				t.Errorf("Skipped = %d, want %d", res.Skipped, tt.wantSkipped)
+			}
+			if len(res.Notes) != len(tt.want) {
+				t.Fatalf("got %d notes, want %d: %+v", len(res.Notes), len(tt.want), res.Notes)
+			}
+			for i, got := range res.Notes {
+				want := tt.want[i]
+				if got.Title != want.Title || got.Content != want.Content || !slices.Equal(got.Tags, want.Tags) ||
+					!got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) {
+					t.Errorf("note %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	if _, err := Parse("evernote", []byte("x")); err != ErrUnknownSource {
+		t.Errorf("unknown source: err = %v", err)
+	}
+	if _, err := Parse(domain.ImportNotion, []byte("not a zip")); err == nil {
+		t.Error("Notion export that isn't a zip: want an error")
+	}
+	if _, err := Parse(domain.ImportObsidian, zipOf(t, "image.png", "\x89PNG")); err != ErrNoNotes {
+		t.Errorf("vault without notes: err = %v, want ErrNoNotes", err)
+	}
+	if err := Check(domain.ImportDayOne, []byte("\ufeff {\"entries\":[]}")); err != nil {
+		t.Errorf("Check(Day One JSON) = %v", err)
+	}
+	if err := Check(domain.ImportObsidian, []byte("{}")); err == nil {
+		t.Error("Check(Obsidian JSON): want an error")
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add(domain.ImportNotion, zipOf(f, "a 0123456789abcdef0123456789abcdef.md", "# A\nTags: x\n\nbody"))
+	f.Add(domain.ImportObsidian, zipOf(f, "n.md", "---\ntags: 1\ncreated: [x]\n---\n#a #1 #b/c"))
+	f.Add(domain.ImportObsidian, zipOf(f, "n.md", "---\n\x00: [\n---"))
+	f.Add(domain.ImportDayOne, []byte(`{"entries":[{"text":"\u0000\ud800# x","tags":["#",""]}]}`))
+	f.Add(domain.ImportDayOne, zipOf(f, "Journal.json", `{"entries":[{}]}`))
+
+	f.Fuzz(func(t *testing.T, source string, data []byte) {
+		res, err := Parse(source, data)
+		if err != nil {
+			return
+		}
+		if len(res.Notes) > MaxNotes {
+			t.Fatalf("%d notes, over the limit", len(res.Notes))
+		}
+		for _, note := range res.Notes {
+			for _, s := range append([]string{note.Title, note.Content}, note.Tags...) {
+				if !utf8.ValidString(s) || strings.ContainsRune(s, 0) {
+					t.Fatalf("note has text that can't be stored: %q", s)
+				}
+			}
+			if utf8.RuneCountInString(note.Title) > maxTitleLength {
+				t.Fatalf("title %q is over %d characters", note.Title, maxTitleLength)
+			}
+			if note.Tags == nil || note.UpdatedAt.Before(note.CreatedAt) {
+				t.Fatalf("note isn't cleaned: %+v", note)
+			}
+		}
+	})
+}
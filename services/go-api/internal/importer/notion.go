@@ -0,0 +1,67 @@
+package importer
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+)
+
+// notionID matches the page ID Notion appends to exported file names, e.g. "Ideas 0f3c...e1.md"
+var notionID = regexp.MustCompile(`\s+[0-9a-f]{32}$`)
+
+// notionTimeLayouts are the date formats Notion writes database properties in
+var notionTimeLayouts = []string{
+	"January 2, 2006 3:04 PM",
+	"January 2, 2006",
+	"2006/01/02 15:04",
+	"2006-01-02",
+	time.RFC3339,
+}
+
+// parseNotion reads one page of a Notion Markdown export. Pages start with a "# Title" heading,
+// and pages from databases then list their properties as "Key: Value" lines, of which tags and
+// dates are kept.
+func parseNotion(res *Result, name string, data []byte, modified time.Time) error {
+	note := domain.ImportedNote{
+		Title:     notionID.ReplaceAllString(strings.TrimSuffix(path.Base(name), path.Ext(name)), ""),
+		CreatedAt: modified,
+		UpdatedAt: modified,
+	}
+
+	body := strings.TrimLeft(strings.ReplaceAll(string(data), "\r\n", "\n"), "\ufeff\n")
+	if line, rest := splitFirstLine(body); strings.HasPrefix(line, "# ") {
+		note.Title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		body = strings.TrimLeft(rest, "\n")
+	}
+
+properties:
+	for body != "" {
+		line, rest := splitFirstLine(body)
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			break
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "tags", "tag", "labels":
+			note.Tags = addTags(note.Tags, strings.Split(value, ",")...)
+		case "created", "created time", "date":
+			if t := parseTime(value, notionTimeLayouts...); !t.IsZero() {
+				note.CreatedAt = t
+			}
+		case "last edited time", "updated":
+			if t := parseTime(value, notionTimeLayouts...); !t.IsZero() {
+				note.UpdatedAt = t
+			}
+		default:
+			// Other properties, and text that happens to contain ": ", stay in the content
+			break properties
+		}
+		body = rest
+	}
+	note.Content = body
+	res.Notes = append(res.Notes, note)
+	return nil
+}
@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"devjournal/internal/domain"
+)
+
+// inlineTag matches #tags in Obsidian notes; headings ("# Title") and anchors in links don't match
+var inlineTag = regexp.MustCompile(`(?:^|[\s(])#([\p{L}\p{N}_/-]*[\p{L}_/-][\p{L}\p{N}_/-]*)`)
+
+// obsidianTimeLayouts are the date formats seen in front matter written by hand or by plugins
+var obsidianTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// obsidianFrontMatter is the part of a note's YAML front matter that is imported
+type obsidianFrontMatter struct {
+	Tags     any `yaml:"tags"`
+	Tag      any `yaml:"tag"`
+	Created  any `yaml:"created"`
+	Date     any `yaml:"date"`
+	Updated  any `yaml:"updated"`
+	Modified any `yaml:"modified"`
+}
+
+// parseObsidian reads one note of a zipped Obsidian vault. Tags come from the front matter and
+// from #tags in the text, and dates from the front matter, or the file's time if it has none.
+func parseObsidian(res *Result, name string, data []byte, modified time.Time) error {
+	note := domain.ImportedNote{
+		Title:     strings.TrimSuffix(path.Base(name), path.Ext(name)),
+		CreatedAt: modified,
+		UpdatedAt: modified,
+	}
+
+	body := strings.TrimPrefix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\ufeff")
+	if line, rest := splitFirstLine(body); line == "---" {
+		if raw, after, ok := strings.Cut("\n"+rest, "\n---"); ok {
+			var fm obsidianFrontMatter
+			// Notes with front matter that isn't valid YAML are imported with it as text
+			if yaml.Unmarshal([]byte(raw), &fm) == nil {
+				note.Tags = addTags(note.Tags, yamlStrings(fm.Tags)...)
+				note.Tags = addTags(note.Tags, yamlStrings(fm.Tag)...)
+				if t := firstTime(fm.Created, fm.Date); !t.IsZero() {
+					note.CreatedAt = t
+				}
+				if t := firstTime(fm.Updated, fm.Modified); !t.IsZero() {
+					note.UpdatedAt = t
+				}
+				_, body = splitFirstLine(after)
+			}
+		}
+	}
+
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, m := range inlineTag.FindAllStringSubmatch(line, -1) {
+			note.Tags = addTags(note.Tags, m[1])
+		}
+	}
+
+	note.Content = body
+	res.Notes = append(res.Notes, note)
+	return nil
+}
+
+// yamlStrings reads a front matter value that may be a list or a comma or space separated string
+func yamlStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' })
+	case []any:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// firstTime returns the first front matter value that is a date
+func firstTime(values ...any) time.Time {
+	for _, v := range values {
+		switch v := v.(type) {
+		case time.Time:
+			return v.UTC()
+		case string:
+			if t := parseTime(v, obsidianTimeLayouts...); !t.IsZero() {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,193 @@
+package integrations
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	discordAPI          = "https://discord.com/api/v10"
+	discordAuthorizeURL = "https://discord.com/oauth2/authorize"
+
+	// discordScopes creates a webhook in the channel picked at install time and enables the slash command
+	discordScopes = "webhook.incoming applications.commands"
+
+	// DiscordCommand is the slash command members use to post back into the study group
+	DiscordCommand = "devjournal"
+
+	// maxDiscordUsername is Discord's limit on a webhook message's username
+	maxDiscordUsername = 80
+)
+
+// Discord interaction and response types
+const (
+	DiscordPing               = 1
+	DiscordApplicationCommand = 2
+
+	DiscordPong            = 1
+	DiscordChannelResponse = 4
+	discordEphemeral       = 1 << 6
+)
+
+// Discord installs the Discord app, posts to webhooks, and verifies interaction requests
+type Discord struct {
+	clientID     string
+	clientSecret string
+	publicKey    ed25519.PublicKey
+}
+
+// NewDiscord creates a Discord client for an app's OAuth credentials and hex-encoded public key
+func NewDiscord(clientID, clientSecret, publicKeyHex string) *Discord {
+	d := &Discord{clientID: clientID, clientSecret: clientSecret}
+	if key, err := hex.DecodeString(publicKeyHex); err == nil && len(key) == ed25519.PublicKeySize {
+		d.publicKey = key
+	}
+	return d
+}
+
+// Configured reports whether the Discord app credentials are set
+func (d *Discord) Configured() bool {
+	return d.clientID != "" && d.clientSecret != ""
+}
+
+// AuthorizeURL is where the user picks a server and channel to install the app into
+func (d *Discord) AuthorizeURL(state, redirectURI string) string {
+	q := url.Values{}
+	q.Set("client_id", d.clientID)
+	q.Set("response_type", "code")
+	q.Set("scope", discordScopes)
+	q.Set("state", state)
+	q.Set("redirect_uri", redirectURI)
+	return discordAuthorizeURL + "?" + q.Encode()
+}
+
+// Exchange completes the install, returning the webhook Discord created in the chosen channel
+func (d *Discord) Exchange(ctx context.Context, code, redirectURI string) (*Installation, error) {
+	form := url.Values{}
+	form.Set("client_id", d.clientID)
+	form.Set("client_secret", d.clientSecret)
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+
+	var resp struct {
+		Webhook struct {
+			Name      string `json:"name"`
+			ChannelID string `json:"channel_id"`
+			GuildID   string `json:"guild_id"`
+			URL       string `json:"url"`
+		} `json:"webhook"`
+	}
+	if err := postForm(ctx, discordAPI+"/oauth2/token", form, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Webhook.URL == "" {
+		return nil, fmt.Errorf("discord oauth returned no webhook")
+	}
+
+	return &Installation{
+		TeamID:      resp.Webhook.GuildID,
+		ChannelID:   resp.Webhook.ChannelID,
+		ChannelName: resp.Webhook.Name,
+		WebhookURL:  resp.Webhook.URL,
+	}, nil
+}
+
+// VerifyRequest checks the X-Signature-Ed25519 header against the app's public key.
+// The signed message is the X-Signature-Timestamp header followed by the body.
+func (d *Discord) VerifyRequest(body []byte, timestamp, signature string, now time.Time) error {
+	if d.publicKey == nil {
+		return ErrInvalidSignature
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || !fresh(ts, now) {
+		return ErrInvalidSignature
+	}
+	sig, err := hex.DecodeString(signature)
+	if err != nil || !ed25519.Verify(d.publicKey, append([]byte(timestamp), body...), sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// DiscordInteraction is the subset of an interaction request the bridge reads
+type DiscordInteraction struct {
+	Type      int    `json:"type"`
+	GuildID   string `json:"guild_id"`
+	ChannelID string `json:"channel_id"`
+	Data      struct {
+		Name    string `json:"name"`
+		Options []struct {
+			Name  string          `json:"name"`
+			Value json.RawMessage `json:"value"`
+		} `json:"options"`
+	} `json:"data"`
+	Member struct {
+		Nick string      `json:"nick"`
+		User DiscordUser `json:"user"`
+	} `json:"member"`
+}
+
+// DiscordUser is a Discord account
+type DiscordUser struct {
+	ID         string `json:"id"`
+	Username   string `json:"username"`
+	GlobalName string `json:"global_name"`
+}
+
+// Option returns a string option of the invoked command
+func (i *DiscordInteraction) Option(name string) string {
+	for _, opt := range i.Data.Options {
+		if opt.Name == name {
+			var value string
+			json.Unmarshal(opt.Value, &value)
+			return value
+		}
+	}
+	return ""
+}
+
+// AuthorName returns the name the invoking member shows in the server
+func (i *DiscordInteraction) AuthorName() string {
+	for _, name := range []string{i.Member.Nick, i.Member.User.GlobalName, i.Member.User.Username} {
+		if name != "" {
+			return name
+		}
+	}
+	return "Discord user"
+}
+
+// DiscordResponse builds an interaction response. Ephemeral replies are shown only to the invoker.
+func DiscordResponse(responseType int, content string, ephemeral bool) map[string]interface{} {
+	resp := map[string]interface{}{"type": responseType}
+	if content != "" {
+		data := map[string]interface{}{"content": content}
+		if ephemeral {
+			data["flags"] = discordEphemeral
+		}
+		resp["data"] = data
+	}
+	return resp
+}
+
+// DiscordMessage builds a webhook payload for a chat message, posted under the author's name.
+// Mentions are disabled so relayed text cannot ping @everyone.
+func DiscordMessage(author, content string) []byte {
+	for utf8.RuneCountInString(author) > maxDiscordUsername {
+		_, size := utf8.DecodeLastRuneInString(author)
+		author = author[:len(author)-size]
+	}
+	payload, _ := json.Marshal(map[string]interface{}{
+		"content":          content,
+		"username":         author,
+		"allowed_mentions": map[string]interface{}{"parse": []string{}},
+	})
+	return payload
+}
@@ -0,0 +1,57 @@
+package integrations
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDiscordVerifyRequest(t *testing.T) {
+	now := time.Unix(1714550400, 0)
+	body := `{"type":2,"guild_id":"G1","channel_id":"C1","data":{"name":"journal"}}`
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(key ed25519.PrivateKey, t time.Time, body string) (string, string) {
+		ts := strconv.FormatInt(t.Unix(), 10)
+		return ts, hex.EncodeToString(ed25519.Sign(key, []byte(ts+body)))
+	}
+	ts, valid := sign(privateKey, now, body)
+	staleTS, stale := sign(privateKey, now.Add(-6*time.Minute), body)
+	_, wrongKey := sign(otherKey, now, body)
+
+	tests := []struct {
+		name      string
+		publicKey string
+		body      string
+		timestamp string
+		signature string
+		wantErr   bool
+	}{
+		{name: "valid", publicKey: hex.EncodeToString(publicKey), body: body, timestamp: ts, signature: valid},
+		{name: "stale timestamp", publicKey: hex.EncodeToString(publicKey), body: body, timestamp: staleTS, signature: stale, wantErr: true},
+		{name: "timestamp changed after signing", publicKey: hex.EncodeToString(publicKey), body: body, timestamp: strconv.FormatInt(now.Unix()+1, 10), signature: valid, wantErr: true},
+		{name: "tampered body", publicKey: hex.EncodeToString(publicKey), body: body + " ", timestamp: ts, signature: valid, wantErr: true},
+		{name: "signed with the wrong key", publicKey: hex.EncodeToString(publicKey), body: body, timestamp: ts, signature: wrongKey, wantErr: true},
+		{name: "non-hex signature", publicKey: hex.EncodeToString(publicKey), body: body, timestamp: ts, signature: "zz" + valid[2:], wantErr: true},
+		{name: "no public key", publicKey: "", body: body, timestamp: ts, signature: valid, wantErr: true},
+		{name: "malformed public key", publicKey: "abcd", body: body, timestamp: ts, signature: valid, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := NewDiscord("client", "secret", tt.publicKey).VerifyRequest([]byte(tt.body), tt.timestamp, tt.signature, now)
+		if tt.wantErr && err != ErrInvalidSignature {
+			t.Errorf("%s: VerifyRequest = %v, want ErrInvalidSignature", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: VerifyRequest = %v", tt.name, err)
+		}
+	}
+}
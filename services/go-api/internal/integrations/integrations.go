@@ -0,0 +1,95 @@
+// Package integrations holds minimal Slack and Discord clients for mirroring study group chat:
+// OAuth app installation, incoming webhooks, and verification of inbound requests
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"devjournal/pkg/apperr"
+)
+
+// ErrInvalidSignature is returned when an inbound request's signature does not verify
+var ErrInvalidSignature = apperr.New(apperr.ErrUnauthorized, "invalid request signature")
+
+// ErrWebhookGone is returned when a channel's webhook was deleted or the app was removed,
+// so retrying the delivery cannot succeed
+var ErrWebhookGone = errors.New("webhook no longer exists")
+
+// requestTolerance is how old an inbound request timestamp may be before it is rejected as a replay
+const requestTolerance = 5 * time.Minute
+
+// Installation is what an OAuth install grants: the channel picked by the user and how to post to it
+type Installation struct {
+	TeamID      string
+	ChannelID   string
+	ChannelName string
+	WebhookURL  string
+	BotToken    string
+}
+
+// httpClient is shared by both providers
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// PostWebhook sends a JSON payload to an incoming webhook
+func PostWebhook(ctx context.Context, webhookURL string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	switch resp.StatusCode {
+	case http.StatusForbidden, http.StatusNotFound, http.StatusGone:
+		return fmt.Errorf("%w: %d %s", ErrWebhookGone, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// postForm exchanges an OAuth code and decodes the JSON response
+func postForm(ctx context.Context, endpoint string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("oauth request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read oauth response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oauth exchange returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, out)
+}
+
+// fresh reports whether a unix timestamp is within the replay tolerance of now
+func fresh(ts int64, now time.Time) bool {
+	age := now.Sub(time.Unix(ts, 0))
+	return age <= requestTolerance && age >= -requestTolerance
+}
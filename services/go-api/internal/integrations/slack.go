@@ -0,0 +1,200 @@
+package integrations
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	slackAPI          = "https://slack.com/api"
+	slackAuthorizeURL = "https://slack.com/oauth/v2/authorize"
+
+	// slackScopes lets the app post to the channel picked at install time and read it back
+	slackScopes = "incoming-webhook,channels:history,groups:history,users:read"
+)
+
+// Slack installs the Slack app, posts to incoming webhooks, and verifies Events API requests
+type Slack struct {
+	clientID      string
+	clientSecret  string
+	signingSecret string
+}
+
+// NewSlack creates a Slack client for an app's OAuth credentials and signing secret
+func NewSlack(clientID, clientSecret, signingSecret string) *Slack {
+	return &Slack{clientID: clientID, clientSecret: clientSecret, signingSecret: signingSecret}
+}
+
+// Configured reports whether the Slack app credentials are set
+func (s *Slack) Configured() bool {
+	return s.clientID != "" && s.clientSecret != ""
+}
+
+// AuthorizeURL is where the user picks a workspace and channel to install the app into
+func (s *Slack) AuthorizeURL(state, redirectURI string) string {
+	q := url.Values{}
+	q.Set("client_id", s.clientID)
+	q.Set("scope", slackScopes)
+	q.Set("state", state)
+	q.Set("redirect_uri", redirectURI)
+	return slackAuthorizeURL + "?" + q.Encode()
+}
+
+// Exchange completes the install, returning the channel's incoming webhook and the bot token
+func (s *Slack) Exchange(ctx context.Context, code, redirectURI string) (*Installation, error) {
+	form := url.Values{}
+	form.Set("client_id", s.clientID)
+	form.Set("client_secret", s.clientSecret)
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+
+	var resp struct {
+		Ok          bool   `json:"ok"`
+		Error       string `json:"error"`
+		AccessToken string `json:"access_token"`
+		Team        struct {
+			ID string `json:"id"`
+		} `json:"team"`
+		IncomingWebhook struct {
+			Channel   string `json:"channel"`
+			ChannelID string `json:"channel_id"`
+			URL       string `json:"url"`
+		} `json:"incoming_webhook"`
+	}
+	if err := postForm(ctx, slackAPI+"/oauth.v2.access", form, &resp); err != nil {
+		return nil, err
+	}
+	if !resp.Ok {
+		return nil, fmt.Errorf("slack oauth failed: %s", resp.Error)
+	}
+	if resp.IncomingWebhook.URL == "" {
+		return nil, fmt.Errorf("slack oauth returned no incoming webhook")
+	}
+
+	return &Installation{
+		TeamID:      resp.Team.ID,
+		ChannelID:   resp.IncomingWebhook.ChannelID,
+		ChannelName: resp.IncomingWebhook.Channel,
+		WebhookURL:  resp.IncomingWebhook.URL,
+		BotToken:    resp.AccessToken,
+	}, nil
+}
+
+// VerifyRequest checks the X-Slack-Signature header ("v0=<hex hmac>") against
+// HMAC-SHA256("v0:<timestamp>:<body>") and rejects stale timestamps
+func (s *Slack) VerifyRequest(body []byte, timestamp, signature string, now time.Time) error {
+	if s.signingSecret == "" {
+		return ErrInvalidSignature
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || !fresh(ts, now) {
+		return ErrInvalidSignature
+	}
+	sig, ok := strings.CutPrefix(signature, "v0=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	decoded, err := hex.DecodeString(sig)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	if !hmac.Equal(decoded, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// UserName looks up a Slack user's display name with the bot token
+func (s *Slack) UserName(ctx context.Context, botToken, userID string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, slackAPI+"/users.info?user="+url.QueryEscape(userID), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+botToken)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("slack request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Ok    bool   `json:"ok"`
+		Error string `json:"error"`
+		User  struct {
+			Name    string `json:"name"`
+			Profile struct {
+				DisplayName string `json:"display_name"`
+				RealName    string `json:"real_name"`
+			} `json:"profile"`
+		} `json:"user"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("failed to decode slack user: %w", err)
+	}
+	if !out.Ok {
+		return "", fmt.Errorf("slack users.info failed: %s", out.Error)
+	}
+	for _, name := range []string{out.User.Profile.DisplayName, out.User.Profile.RealName, out.User.Name} {
+		if name != "" {
+			return name, nil
+		}
+	}
+	return userID, nil
+}
+
+// SlackEnvelope is an Events API request: a URL verification challenge or an event callback
+type SlackEnvelope struct {
+	Type      string     `json:"type"` // url_verification, event_callback
+	Challenge string     `json:"challenge"`
+	TeamID    string     `json:"team_id"`
+	Event     SlackEvent `json:"event"`
+}
+
+// SlackEvent is the subset of a message event the bridge reads
+type SlackEvent struct {
+	Type    string `json:"type"`
+	Subtype string `json:"subtype"` // set for edits, joins, and bot posts, none of which are relayed
+	BotID   string `json:"bot_id"`
+	User    string `json:"user"`
+	Channel string `json:"channel"`
+	Text    string `json:"text"`
+}
+
+// Relayable reports whether the event is a plain message written by a person
+func (e *SlackEvent) Relayable() bool {
+	return e.Type == "message" && e.Subtype == "" && e.BotID == "" && e.User != "" && strings.TrimSpace(e.Text) != ""
+}
+
+// slackEscaper escapes the characters Slack treats as markup in message text
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackUnescaper reverses slackEscaper for text read from Slack
+var slackUnescaper = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">")
+
+// SlackMessage builds an incoming webhook payload for a chat message
+func SlackMessage(author, content string) []byte {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"text":         fmt.Sprintf("*%s*: %s", slackEscaper.Replace(author), slackEscaper.Replace(content)),
+		"unfurl_links": false,
+	})
+	return payload
+}
+
+// SlackText converts message text from Slack to plain text
+func SlackText(text string) string {
+	return slackUnescaper.Replace(text)
+}
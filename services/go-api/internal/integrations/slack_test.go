@@ -0,0 +1,52 @@
+package integrations
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSlackVerifyRequest(t *testing.T) {
+	now := time.Unix(1714550400, 0)
+	body := `token=x&team_id=T1&channel_id=C1&user_id=U1&text=hello`
+	sign := func(secret string, t time.Time, body string) (string, string) {
+		ts := strconv.FormatInt(t.Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte("v0:" + ts + ":" + body))
+		return ts, "v0=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	ts, valid := sign("signing-secret", now, body)
+	staleTS, stale := sign("signing-secret", now.Add(-6*time.Minute), body)
+	_, wrongSecret := sign("other-secret", now, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      string
+		timestamp string
+		signature string
+		wantErr   bool
+	}{
+		{name: "valid", secret: "signing-secret", body: body, timestamp: ts, signature: valid},
+		{name: "stale timestamp", secret: "signing-secret", body: body, timestamp: staleTS, signature: stale, wantErr: true},
+		{name: "timestamp changed after signing", secret: "signing-secret", body: body, timestamp: strconv.FormatInt(now.Unix()+1, 10), signature: valid, wantErr: true},
+		{name: "non-numeric timestamp", secret: "signing-secret", body: body, timestamp: "now", signature: valid, wantErr: true},
+		{name: "missing v0= prefix", secret: "signing-secret", body: body, timestamp: ts, signature: valid[len("v0="):], wantErr: true},
+		{name: "non-hex signature", secret: "signing-secret", body: body, timestamp: ts, signature: "v0=zz" + valid[5:], wantErr: true},
+		{name: "tampered body", secret: "signing-secret", body: body + "&admin=true", timestamp: ts, signature: valid, wantErr: true},
+		{name: "wrong secret", secret: "signing-secret", body: body, timestamp: ts, signature: wrongSecret, wantErr: true},
+		{name: "no signing secret", secret: "", body: body, timestamp: ts, signature: valid, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := NewSlack("client", "secret", tt.secret).VerifyRequest([]byte(tt.body), tt.timestamp, tt.signature, now)
+		if tt.wantErr && err != ErrInvalidSignature {
+			t.Errorf("%s: VerifyRequest = %v, want ErrInvalidSignature", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: VerifyRequest = %v", tt.name, err)
+		}
+	}
+}
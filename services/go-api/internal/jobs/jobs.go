@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Func is a unit of periodic background work
+type Func func(ctx context.Context) error
+
+// Every runs fn once per interval until ctx is cancelled. Failures are logged and
+// retried on the next tick; each run gets its own timeout of one interval.
+func Every(ctx context.Context, name string, interval time.Duration, fn Func) {
+	if interval <= 0 {
+		log.Printf("WARN: Background job %s disabled (interval %s)", name, interval)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("Starting background job %s every %s", name, interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx, cancel := context.WithTimeout(ctx, interval)
+			if err := fn(runCtx); err != nil {
+				log.Printf("ERROR: Background job %s failed: %v", name, err)
+			}
+			cancel()
+		}
+	}
+}
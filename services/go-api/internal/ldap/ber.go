@@ -0,0 +1,364 @@
+package ldap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BER tags of the LDAPv3 messages the client sends and reads (RFC 4511)
+const (
+	tagBoolean     = 0x01
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagEnumerated  = 0x0a
+	tagSequence    = 0x30
+	tagSet         = 0x31
+
+	appBindRequest      = 0x60
+	appBindResponse     = 0x61
+	appUnbindRequest    = 0x42
+	appSearchRequest    = 0x63
+	appSearchEntry      = 0x64
+	appSearchDone       = 0x65
+	appSearchReference  = 0x73
+	appExtendedRequest  = 0x77
+	appExtendedResponse = 0x78
+
+	// Context-specific tags
+	tagSimpleAuth   = 0x80 // BindRequest simple password
+	tagExtendedName = 0x80 // ExtendedRequest OID
+
+	// Filter choices
+	filterAnd        = 0xa0
+	filterOr         = 0xa1
+	filterNot        = 0xa2
+	filterEquality   = 0xa3
+	filterSubstrings = 0xa4
+	filterGreater    = 0xa5
+	filterLess       = 0xa6
+	filterPresent    = 0x87
+	filterApprox     = 0xa8
+	substringInitial = 0x80
+	substringAny     = 0x81
+	substringFinal   = 0x82
+)
+
+// maxPacketSize bounds a message read from the server
+const maxPacketSize = 1 << 20
+
+var errMalformed = errors.New("malformed ldap message")
+
+// element is one decoded BER tag-length-value
+type element struct {
+	tag  byte
+	data []byte
+}
+
+func encode(tag byte, parts ...[]byte) []byte {
+	n := 0
+	for _, p := range parts {
+		n += len(p)
+	}
+	out := append([]byte{tag}, encodeLength(n)...)
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func encodeLength(n int) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+	var b []byte
+	for ; n > 0; n >>= 8 {
+		b = append([]byte{byte(n)}, b...)
+	}
+	return append([]byte{0x80 | byte(len(b))}, b...)
+}
+
+// encodeInt encodes a non-negative integer
+func encodeInt(tag byte, v int) []byte {
+	b := []byte{byte(v)}
+	for v >>= 8; v > 0; v >>= 8 {
+		b = append([]byte{byte(v)}, b...)
+	}
+	if b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	return encode(tag, b)
+}
+
+func encodeString(tag byte, s string) []byte {
+	return encode(tag, []byte(s))
+}
+
+// parseElement decodes the element at the start of b, returning it and the rest of b
+func parseElement(b []byte) (element, []byte, error) {
+	if len(b) < 2 {
+		return element{}, nil, errMalformed
+	}
+	n, size, err := parseLength(b[1:])
+	if err != nil {
+		return element{}, nil, err
+	}
+	start := 1 + size
+	if len(b)-start < n {
+		return element{}, nil, errMalformed
+	}
+	return element{tag: b[0], data: b[start : start+n]}, b[start+n:], nil
+}
+
+// parseLength decodes a definite length, returning it and how many bytes it took
+func parseLength(b []byte) (int, int, error) {
+	if b[0] < 0x80 {
+		return int(b[0]), 1, nil
+	}
+	k := int(b[0] & 0x7f)
+	if k == 0 || k > 4 || len(b) < 1+k {
+		return 0, 0, errMalformed
+	}
+	n := 0
+	for _, c := range b[1 : 1+k] {
+		n = n<<8 | int(c)
+	}
+	if n < 0 {
+		return 0, 0, errMalformed
+	}
+	return n, 1 + k, nil
+}
+
+// children decodes the elements a constructed element contains
+func (e element) children() ([]element, error) {
+	var out []element
+	for rest := e.data; len(rest) > 0; {
+		child, next, err := parseElement(rest)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, child)
+		rest = next
+	}
+	return out, nil
+}
+
+// int decodes a non-negative integer or enumerated value
+func (e element) int() int {
+	v := 0
+	for _, c := range e.data {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+// readPacket reads one whole message from the server
+func readPacket(r *bufio.Reader) ([]byte, error) {
+	header := make([]byte, 2, 6)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if header[1]&0x80 != 0 {
+		k := int(header[1] & 0x7f)
+		if k == 0 || k > 4 {
+			return nil, errMalformed
+		}
+		header = header[:2+k]
+		if _, err := io.ReadFull(r, header[2:]); err != nil {
+			return nil, err
+		}
+	}
+	n, _, err := parseLength(header[1:])
+	if err != nil {
+		return nil, err
+	}
+	if n > maxPacketSize {
+		return nil, fmt.Errorf("ldap message of %d bytes is too large", n)
+	}
+	packet := make([]byte, len(header)+n)
+	copy(packet, header)
+	if _, err := io.ReadFull(r, packet[len(header):]); err != nil {
+		return nil, err
+	}
+	return packet, nil
+}
+
+// EscapeFilter escapes a value for use in a search filter, so a username can't change the
+// filter's meaning (RFC 4515)
+func EscapeFilter(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '*', '(', ')', '\\', 0:
+			fmt.Fprintf(&b, `\%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// compileFilter encodes a string search filter such as (&(objectClass=person)(uid=ada))
+func compileFilter(s string) ([]byte, error) {
+	p := &filterParser{s: s}
+	out, err := p.filter()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("invalid ldap filter %q: trailing characters", s)
+	}
+	return out, nil
+}
+
+type filterParser struct {
+	s   string
+	pos int
+}
+
+func (p *filterParser) errorf(msg string) error {
+	return fmt.Errorf("invalid ldap filter %q at %d: %s", p.s, p.pos, msg)
+}
+
+func (p *filterParser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *filterParser) filter() ([]byte, error) {
+	if p.peek() != '(' {
+		return nil, p.errorf("expected (")
+	}
+	p.pos++
+
+	var out []byte
+	switch op := p.peek(); op {
+	case '&', '|':
+		p.pos++
+		var parts [][]byte
+		for p.peek() == '(' {
+			part, err := p.filter()
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, part)
+		}
+		if len(parts) == 0 {
+			return nil, p.errorf("empty filter list")
+		}
+		tag := byte(filterAnd)
+		if op == '|' {
+			tag = filterOr
+		}
+		out = encode(tag, parts...)
+	case '!':
+		p.pos++
+		part, err := p.filter()
+		if err != nil {
+			return nil, err
+		}
+		out = encode(filterNot, part)
+	default:
+		item, err := p.item()
+		if err != nil {
+			return nil, err
+		}
+		out = item
+	}
+
+	if p.peek() != ')' {
+		return nil, p.errorf("expected )")
+	}
+	p.pos++
+	return out, nil
+}
+
+// item encodes a comparison such as uid=ada, mail=*@example.com, or cn=*
+func (p *filterParser) item() ([]byte, error) {
+	end := strings.IndexByte(p.s[p.pos:], ')')
+	if end < 0 {
+		return nil, p.errorf("unterminated comparison")
+	}
+	item := p.s[p.pos : p.pos+end]
+	p.pos += end
+
+	attr, value, ok := strings.Cut(item, "=")
+	if !ok || attr == "" {
+		return nil, p.errorf("expected attribute=value")
+	}
+	tag := byte(filterEquality)
+	switch attr[len(attr)-1] {
+	case '>':
+		tag = filterGreater
+	case '<':
+		tag = filterLess
+	case '~':
+		tag = filterApprox
+	}
+	if tag != filterEquality {
+		attr = attr[:len(attr)-1]
+		if attr == "" {
+			return nil, p.errorf("expected attribute")
+		}
+	}
+
+	if tag == filterEquality && value == "*" {
+		return encodeString(filterPresent, attr), nil
+	}
+	if tag == filterEquality && strings.Contains(value, "*") {
+		parts := strings.Split(value, "*")
+		var subs [][]byte
+		for i, part := range parts {
+			if part == "" {
+				continue
+			}
+			v, err := unescapeFilter(part)
+			if err != nil {
+				return nil, p.errorf(err.Error())
+			}
+			sub := byte(substringAny)
+			if i == 0 {
+				sub = substringInitial
+			} else if i == len(parts)-1 {
+				sub = substringFinal
+			}
+			subs = append(subs, encodeString(sub, v))
+		}
+		return encode(filterSubstrings, encodeString(tagOctetString, attr), encode(tagSequence, subs...)), nil
+	}
+
+	v, err := unescapeFilter(value)
+	if err != nil {
+		return nil, p.errorf(err.Error())
+	}
+	return encode(tag, encodeString(tagOctetString, attr), encodeString(tagOctetString, v)), nil
+}
+
+// unescapeFilter decodes the \XX escapes of a filter value
+func unescapeFilter(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+3 > len(s) {
+			return "", errors.New("incomplete escape")
+		}
+		c, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", errors.New("invalid escape")
+		}
+		b.WriteByte(byte(c))
+		i += 2
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,383 @@
+// Package ldap is a minimal LDAPv3 client for checking passwords against a directory: it binds
+// as a service account, searches for the user, and binds as them with their password
+package ldap
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// requestTimeout bounds a whole login when the context has no deadline
+	requestTimeout = 10 * time.Second
+
+	// startTLSOID is the extended operation that upgrades a connection to TLS (RFC 4511 4.14)
+	startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+	// LDAP result codes the client tells apart
+	resultSuccess            = 0
+	resultSizeLimitExceeded  = 4
+	resultInvalidCredentials = 49
+)
+
+var (
+	// ErrInvalidCredentials is returned when the directory rejects a password
+	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	// ErrUserNotFound is returned when no directory entry matches the username
+	ErrUserNotFound = errors.New("user not found in directory")
+)
+
+// Config holds where the directory is and how users are found in it
+type Config struct {
+	URL            string // ldap://host:389 or ldaps://host:636
+	StartTLS       bool   // upgrade ldap:// connections to TLS before binding
+	BindDN         string // service account that searches for users; empty binds anonymously
+	BindPassword   string
+	SearchBase     string // DN users are searched for under
+	UserFilter     string // search filter, with {username} replaced by the escaped username
+	EmailAttribute string
+	NameAttribute  string
+}
+
+// Entry is the directory entry a user signed in as
+type Entry struct {
+	DN    string
+	Email string
+	Name  string
+}
+
+// Directory checks usernames and passwords against an LDAP directory
+type Directory struct {
+	cfg Config
+}
+
+// New creates a directory client
+func New(cfg Config) *Directory {
+	return &Directory{cfg: cfg}
+}
+
+// Configured reports whether a directory URL and search base are set
+func (d *Directory) Configured() bool {
+	return d.cfg.URL != "" && d.cfg.SearchBase != ""
+}
+
+// Authenticate finds the entry a username matches and checks the password by binding as it
+func (d *Directory) Authenticate(ctx context.Context, username, password string) (*Entry, error) {
+	// An empty password is an unauthenticated bind, which servers accept for any DN
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	filter, err := compileFilter(strings.ReplaceAll(d.cfg.UserFilter, "{username}", EscapeFilter(username)))
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := d.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	// The service account's failure is a configuration problem, not the user's
+	if err := c.bind(d.cfg.BindDN, d.cfg.BindPassword); err != nil {
+		return nil, fmt.Errorf("ldap service bind failed: %v", err)
+	}
+	attrs := []string{d.cfg.EmailAttribute, d.cfg.NameAttribute}
+	entries, err := c.search(d.cfg.SearchBase, filter, attrs)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrUserNotFound
+	}
+	if len(entries) > 1 {
+		return nil, fmt.Errorf("ldap user filter matched more than one entry for %q", username)
+	}
+
+	found := entries[0]
+	if err := c.bind(found.dn, password); err != nil {
+		return nil, err
+	}
+	return &Entry{
+		DN:    found.dn,
+		Email: found.first(d.cfg.EmailAttribute),
+		Name:  found.first(d.cfg.NameAttribute),
+	}, nil
+}
+
+// Verify checks the password of a known entry by binding as it
+func (d *Directory) Verify(ctx context.Context, dn, password string) error {
+	if dn == "" || password == "" {
+		return ErrInvalidCredentials
+	}
+	c, err := d.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.close()
+	return c.bind(dn, password)
+}
+
+// conn is one connection to the directory
+type conn struct {
+	c      net.Conn
+	r      *bufio.Reader
+	nextID int
+}
+
+func (d *Directory) dial(ctx context.Context) (*conn, error) {
+	u, err := url.Parse(d.cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ldap url: %w", err)
+	}
+	host := u.Hostname()
+	port := u.Port()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(requestTimeout)
+	}
+	dialer := &net.Dialer{Deadline: deadline}
+	tlsConfig := &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+
+	var nc net.Conn
+	switch u.Scheme {
+	case "ldap":
+		if port == "" {
+			port = "389"
+		}
+		nc, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	case "ldaps":
+		if port == "" {
+			port = "636"
+		}
+		nc, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	default:
+		return nil, fmt.Errorf("ldap url must be ldap:// or ldaps://, not %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ldap connection failed: %w", err)
+	}
+	nc.SetDeadline(deadline)
+
+	c := &conn{c: nc, r: bufio.NewReader(nc)}
+	if u.Scheme == "ldap" && d.cfg.StartTLS {
+		if err := c.startTLS(tlsConfig); err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *conn) close() {
+	c.nextID++
+	c.c.Write(encode(tagSequence, encodeInt(tagInteger, c.nextID), []byte{appUnbindRequest, 0}))
+	c.c.Close()
+}
+
+// send writes a request, returning its message ID
+func (c *conn) send(op []byte) (int, error) {
+	c.nextID++
+	if _, err := c.c.Write(encode(tagSequence, encodeInt(tagInteger, c.nextID), op)); err != nil {
+		return 0, fmt.Errorf("ldap request failed: %w", err)
+	}
+	return c.nextID, nil
+}
+
+// receive reads the next response to a request
+func (c *conn) receive(id int) (element, error) {
+	for {
+		packet, err := readPacket(c.r)
+		if err != nil {
+			return element{}, fmt.Errorf("ldap response failed: %w", err)
+		}
+		msg, _, err := parseElement(packet)
+		if err != nil {
+			return element{}, err
+		}
+		fields, err := msg.children()
+		if err != nil || len(fields) < 2 {
+			return element{}, errMalformed
+		}
+		switch fields[0].int() {
+		case id:
+			return fields[1], nil
+		case 0:
+			// An unsolicited notice, which servers only send before disconnecting
+			return element{}, fmt.Errorf("ldap server closed the connection: %w", result(fields[1]))
+		}
+	}
+}
+
+func (c *conn) bind(dn, password string) error {
+	id, err := c.send(encode(appBindRequest,
+		encodeInt(tagInteger, 3),
+		encodeString(tagOctetString, dn),
+		encodeString(tagSimpleAuth, password),
+	))
+	if err != nil {
+		return err
+	}
+	op, err := c.receive(id)
+	if err != nil {
+		return err
+	}
+	if op.tag != appBindResponse {
+		return errMalformed
+	}
+	return result(op)
+}
+
+func (c *conn) startTLS(config *tls.Config) error {
+	id, err := c.send(encode(appExtendedRequest, encodeString(tagExtendedName, startTLSOID)))
+	if err != nil {
+		return err
+	}
+	op, err := c.receive(id)
+	if err != nil {
+		return err
+	}
+	if op.tag != appExtendedResponse {
+		return errMalformed
+	}
+	if err := result(op); err != nil {
+		return fmt.Errorf("ldap starttls refused: %w", err)
+	}
+
+	tc := tls.Client(c.c, config)
+	if err := tc.Handshake(); err != nil {
+		return fmt.Errorf("ldap starttls failed: %w", err)
+	}
+	c.c = tc
+	c.r = bufio.NewReader(tc)
+	return nil
+}
+
+// searchEntry is a search result, with attribute names lowercased
+type searchEntry struct {
+	dn    string
+	attrs map[string][]string
+}
+
+func (e *searchEntry) first(attr string) string {
+	if values := e.attrs[strings.ToLower(attr)]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// search runs a subtree search. It asks for at most two entries, which is enough to tell a unique
+// match from an ambiguous one.
+func (c *conn) search(base string, filter []byte, attrs []string) ([]searchEntry, error) {
+	attrList := make([][]byte, 0, len(attrs))
+	for _, attr := range attrs {
+		if attr != "" {
+			attrList = append(attrList, encodeString(tagOctetString, attr))
+		}
+	}
+	id, err := c.send(encode(appSearchRequest,
+		encodeString(tagOctetString, base),
+		encodeInt(tagEnumerated, 2), // wholeSubtree
+		encodeInt(tagEnumerated, 0), // neverDerefAliases
+		encodeInt(tagInteger, 2),    // sizeLimit
+		encodeInt(tagInteger, int(requestTimeout.Seconds())),
+		encode(tagBoolean, []byte{0}), // typesOnly
+		filter,
+		encode(tagSequence, attrList...),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []searchEntry
+	for {
+		op, err := c.receive(id)
+		if err != nil {
+			return nil, err
+		}
+		switch op.tag {
+		case appSearchEntry:
+			entry, err := parseSearchEntry(op)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		case appSearchReference:
+			// Referrals to other servers aren't followed
+		case appSearchDone:
+			err := result(op)
+			var resultErr *ResultError
+			if errors.As(err, &resultErr) && resultErr.Code == resultSizeLimitExceeded && len(entries) > 0 {
+				err = nil
+			}
+			return entries, err
+		default:
+			return nil, errMalformed
+		}
+	}
+}
+
+func parseSearchEntry(op element) (searchEntry, error) {
+	fields, err := op.children()
+	if err != nil || len(fields) < 2 {
+		return searchEntry{}, errMalformed
+	}
+	entry := searchEntry{dn: string(fields[0].data), attrs: map[string][]string{}}
+	attrs, err := fields[1].children()
+	if err != nil {
+		return searchEntry{}, err
+	}
+	for _, attr := range attrs {
+		parts, err := attr.children()
+		if err != nil || len(parts) < 2 {
+			return searchEntry{}, errMalformed
+		}
+		values, err := parts[1].children()
+		if err != nil {
+			return searchEntry{}, err
+		}
+		name := strings.ToLower(string(parts[0].data))
+		for _, v := range values {
+			entry.attrs[name] = append(entry.attrs[name], string(v.data))
+		}
+	}
+	return entry, nil
+}
+
+// ResultError is an LDAP result other than success
+type ResultError struct {
+	Code    int
+	Message string
+}
+
+func (e *ResultError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ldap result %d", e.Code)
+	}
+	return fmt.Sprintf("ldap result %d: %s", e.Code, e.Message)
+}
+
+// result decodes an LDAPResult: resultCode, matchedDN, diagnosticMessage
+func result(op element) error {
+	fields, err := op.children()
+	if err != nil || len(fields) < 3 {
+		return errMalformed
+	}
+	switch code := fields[0].int(); code {
+	case resultSuccess:
+		return nil
+	case resultInvalidCredentials:
+		return ErrInvalidCredentials
+	default:
+		return &ResultError{Code: code, Message: string(fields[2].data)}
+	}
+}
@@ -0,0 +1,207 @@
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestEscapeFilter(t *testing.T) {
+	if got := EscapeFilter(`ada*)(uid=\`); got != `ada\2a\29\28uid=\5c` {
+		t.Errorf("EscapeFilter = %q", got)
+	}
+}
+
+func TestCompileFilter(t *testing.T) {
+	got, err := compileFilter(`(&(objectClass=person)(!(mail=*))(cn=Ad*L\2a*ce))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := encode(filterAnd,
+		encode(filterEquality, encodeString(tagOctetString, "objectClass"), encodeString(tagOctetString, "person")),
+		encode(filterNot, encodeString(filterPresent, "mail")),
+		encode(filterSubstrings, encodeString(tagOctetString, "cn"), encode(tagSequence,
+			encodeString(substringInitial, "Ad"),
+			encodeString(substringAny, "L*"),
+			encodeString(substringFinal, "ce"),
+		)),
+	)
+	if !bytes.Equal(got, want) {
+		t.Errorf("compileFilter = %x; want %x", got, want)
+	}
+
+	for _, bad := range []string{``, `uid=ada`, `(uid=ada`, `(&)`, `(=ada)`, `(uid=ada))`, `(uid=\2)`} {
+		if _, err := compileFilter(bad); err == nil {
+			t.Errorf("compileFilter(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestEncodeLength(t *testing.T) {
+	long := strings.Repeat("x", 300)
+	el, rest, err := parseElement(encodeString(tagOctetString, long))
+	if err != nil || len(rest) != 0 || string(el.data) != long {
+		t.Fatalf("round trip of %d bytes failed: %v", len(long), err)
+	}
+	if got := (element{data: encodeInt(tagInteger, 200)[2:]}).int(); got != 200 {
+		t.Errorf("int = %d; want 200", got)
+	}
+}
+
+// fakeEntry is a user in fakeDirectory
+type fakeEntry struct {
+	dn       string
+	password string
+	attrs    map[string]string
+}
+
+// fakeDirectory answers binds and searches for a few entries, matching filters of equality
+// comparisons joined by & and |
+func fakeDirectory(t *testing.T, entries []fakeEntry) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	respond := func(nc net.Conn, id int, op []byte) {
+		nc.Write(encode(tagSequence, encodeInt(tagInteger, id), op))
+	}
+	ldapResult := func(tag byte, code int) []byte {
+		return encode(tag, encodeInt(tagEnumerated, code), encodeString(tagOctetString, ""), encodeString(tagOctetString, ""))
+	}
+	var matches func(f element, e fakeEntry) bool
+	matches = func(f element, e fakeEntry) bool {
+		parts, _ := f.children()
+		switch f.tag {
+		case filterAnd, filterOr:
+			for _, p := range parts {
+				if matches(p, e) == (f.tag == filterOr) {
+					return f.tag == filterOr
+				}
+			}
+			return f.tag == filterAnd
+		case filterEquality:
+			return e.attrs[string(parts[0].data)] == string(parts[1].data)
+		}
+		return false
+	}
+
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer nc.Close()
+				r := bufio.NewReader(nc)
+				bound := false
+				for {
+					packet, err := readPacket(r)
+					if err != nil {
+						return
+					}
+					msg, _, _ := parseElement(packet)
+					fields, _ := msg.children()
+					id, op := fields[0].int(), fields[1]
+					parts, _ := op.children()
+					switch op.tag {
+					case appBindRequest:
+						dn, password := string(parts[1].data), string(parts[2].data)
+						code := resultInvalidCredentials
+						for _, e := range entries {
+							if e.dn == dn && e.password == password {
+								code = resultSuccess
+							}
+						}
+						bound = code == resultSuccess
+						respond(nc, id, ldapResult(appBindResponse, code))
+					case appSearchRequest:
+						if !bound {
+							respond(nc, id, ldapResult(appSearchDone, 50)) // insufficientAccessRights
+							continue
+						}
+						for _, e := range entries {
+							if e.attrs != nil && matches(parts[6], e) {
+								var attrs [][]byte
+								for name, value := range e.attrs {
+									attrs = append(attrs, encode(tagSequence, encodeString(tagOctetString, name), encode(tagSet, encodeString(tagOctetString, value))))
+								}
+								respond(nc, id, encode(appSearchEntry, encodeString(tagOctetString, e.dn), encode(tagSequence, attrs...)))
+							}
+						}
+						respond(nc, id, ldapResult(appSearchDone, resultSuccess))
+					case appUnbindRequest:
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return "ldap://" + ln.Addr().String()
+}
+
+func TestAuthenticate(t *testing.T) {
+	url := fakeDirectory(t, []fakeEntry{
+		{dn: "cn=search,dc=example,dc=com", password: "service"},
+		{dn: "uid=ada,ou=people,dc=example,dc=com", password: "analytical", attrs: map[string]string{"uid": "ada", "mail": "ada@example.com", "cn": "Ada Lovelace"}},
+		{dn: "uid=twin1,ou=people,dc=example,dc=com", password: "x", attrs: map[string]string{"uid": "twin", "mail": "twin@example.com"}},
+		{dn: "uid=twin2,ou=people,dc=example,dc=com", password: "x", attrs: map[string]string{"uid": "twin", "mail": "twin@example.com"}},
+	})
+	d := New(Config{
+		URL:            url,
+		BindDN:         "cn=search,dc=example,dc=com",
+		BindPassword:   "service",
+		SearchBase:     "ou=people,dc=example,dc=com",
+		UserFilter:     "(|(uid={username})(mail={username}))",
+		EmailAttribute: "mail",
+		NameAttribute:  "CN",
+	})
+	ctx := context.Background()
+
+	for _, username := range []string{"ada", "ada@example.com"} {
+		entry, err := d.Authenticate(ctx, username, "analytical")
+		if err != nil {
+			t.Fatalf("Authenticate(%s): %v", username, err)
+		}
+		want := Entry{DN: "uid=ada,ou=people,dc=example,dc=com", Email: "ada@example.com", Name: "Ada Lovelace"}
+		if *entry != want {
+			t.Errorf("Authenticate(%s) = %+v; want %+v", username, *entry, want)
+		}
+	}
+
+	if _, err := d.Authenticate(ctx, "ada", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("wrong password: err = %v; want ErrInvalidCredentials", err)
+	}
+	if _, err := d.Authenticate(ctx, "ada", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("empty password: err = %v; want ErrInvalidCredentials", err)
+	}
+	if _, err := d.Authenticate(ctx, "grace", "analytical"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("unknown user: err = %v; want ErrUserNotFound", err)
+	}
+	if _, err := d.Authenticate(ctx, "*", "analytical"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("wildcard username: err = %v; want ErrUserNotFound", err)
+	}
+	if _, err := d.Authenticate(ctx, "twin", "x"); err == nil || errors.Is(err, ErrUserNotFound) {
+		t.Errorf("ambiguous user: err = %v; want an error", err)
+	}
+
+	if err := d.Verify(ctx, "uid=ada,ou=people,dc=example,dc=com", "analytical"); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if err := d.Verify(ctx, "uid=ada,ou=people,dc=example,dc=com", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Verify(wrong) = %v; want ErrInvalidCredentials", err)
+	}
+
+	// A rejected service account is a server error, not a wrong password
+	d.cfg.BindPassword = "rotated"
+	if _, err := d.Authenticate(ctx, "ada", "analytical"); err == nil || errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("bad service account: err = %v", err)
+	}
+}
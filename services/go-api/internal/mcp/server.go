@@ -0,0 +1,260 @@
+// Package mcp implements the tool side of the Model Context Protocol over stdio, so AI
+// assistants and editors can call DevJournal as a set of tools.
+//
+// Messages are newline-delimited JSON-RPC 2.0. The server answers initialize, ping,
+// tools/list, and tools/call; everything else gets a method-not-found error.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+)
+
+// Protocol revisions the server speaks, newest first
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// maxMessageSize bounds a single request line
+const maxMessageSize = 4 << 20
+
+// ToolFunc runs a tool with its JSON arguments and returns a JSON-encodable result.
+// Errors are reported to the model as a failed tool call, not as a protocol error.
+type ToolFunc func(ctx context.Context, args json.RawMessage) (interface{}, error)
+
+// Tool is a callable tool advertised in tools/list
+type Tool struct {
+	Name        string
+	Description string
+	InputSchema map[string]interface{} // JSON Schema for the arguments object
+	ReadOnly    bool                   // hints to the client that the tool has no side effects
+	Handler     ToolFunc
+}
+
+// Server dispatches MCP requests to registered tools
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+	byName  map[string]Tool
+}
+
+// NewServer creates a server that identifies itself with name and version
+func NewServer(name, version string) *Server {
+	return &Server{name: name, version: version, byName: make(map[string]Tool)}
+}
+
+// AddTool registers a tool. Tools are listed in registration order.
+func (s *Server) AddTool(tool Tool) {
+	if _, exists := s.byName[tool.Name]; exists {
+		panic("mcp: duplicate tool " + tool.Name)
+	}
+	s.tools = append(s.tools, tool)
+	s.byName[tool.Name] = tool
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// Serve reads requests from r and writes responses to w until r is exhausted or ctx is done.
+// Requests are handled concurrently, so a slow tool call does not block pings.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		writeMu sync.Mutex
+		wg      sync.WaitGroup
+	)
+	encoder := json.NewEncoder(w)
+	write := func(resp *response) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if err := encoder.Encode(resp); err != nil {
+			log.Printf("mcp: write response: %v", err)
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			write(&response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error"}})
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			if req.ID != nil {
+				write(&response{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{codeInvalidRequest, "invalid request"}})
+			}
+			continue
+		}
+		// Notifications (no id) never get a response
+		if req.ID == nil {
+			continue
+		}
+
+		// initialize is answered before anything after it is read
+		if req.Method == "initialize" {
+			write(s.respond(ctx, &req))
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			write(s.respond(ctx, &req))
+		}()
+	}
+
+	wg.Wait()
+	return scanner.Err()
+}
+
+// respond runs a request and wraps its result or error in a response
+func (s *Server) respond(ctx context.Context, req *request) *response {
+	result, err := s.handle(ctx, req)
+	if err != nil {
+		var rpcErr *rpcError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &rpcError{codeInternalError, err.Error()}
+		}
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// handle runs a single request
+func (s *Server) handle(ctx context.Context, req *request) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		return s.initialize(req.Params)
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return s.listTools(), nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	default:
+		return nil, &rpcError{codeMethodNotFound, fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+// initialize negotiates the protocol version: the client's if supported, otherwise our newest
+func (s *Server) initialize(params json.RawMessage) (interface{}, error) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{codeInvalidParams, "invalid initialize params"}
+		}
+	}
+	version := protocolVersions[0]
+	for _, supported := range protocolVersions {
+		if p.ProtocolVersion == supported {
+			version = supported
+		}
+	}
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+		"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+	}, nil
+}
+
+// listTools returns every tool's name, description, and input schema
+func (s *Server) listTools() interface{} {
+	tools := make([]map[string]interface{}, 0, len(s.tools))
+	for _, tool := range s.tools {
+		tools = append(tools, map[string]interface{}{
+			"name":        tool.Name,
+			"description": tool.Description,
+			"inputSchema": tool.InputSchema,
+			"annotations": map[string]bool{"readOnlyHint": tool.ReadOnly},
+		})
+	}
+	return map[string]interface{}{"tools": tools}
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type callResult struct {
+	Content           []textContent `json:"content"`
+	StructuredContent interface{}   `json:"structuredContent,omitempty"`
+	IsError           bool          `json:"isError,omitempty"`
+}
+
+// callTool runs a tool. Tool failures are returned as results with isError set, so the
+// model sees the message and can correct its arguments.
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{codeInvalidParams, "invalid tools/call params"}
+	}
+	tool, ok := s.byName[p.Name]
+	if !ok {
+		return nil, &rpcError{codeInvalidParams, fmt.Sprintf("unknown tool %q", p.Name)}
+	}
+	if len(p.Arguments) == 0 || string(p.Arguments) == "null" {
+		p.Arguments = json.RawMessage("{}")
+	}
+
+	result, err := tool.Handler(ctx, p.Arguments)
+	if err != nil {
+		return &callResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode %s result: %w", p.Name, err)
+	}
+	out := &callResult{Content: []textContent{{Type: "text", Text: string(text)}}}
+	// structuredContent must be an object
+	if len(text) > 0 && text[0] == '{' {
+		out.StructuredContent = result
+	}
+	return out, nil
+}
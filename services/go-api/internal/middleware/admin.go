@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"devjournal/internal/policy"
+	"devjournal/internal/service"
+	"devjournal/pkg/httputil"
+
+	"github.com/google/uuid"
+)
+
+// AdminOnly restricts a route to platform admins. It must run after AuthMiddleware.
+func AdminOnly(authService *service.AuthService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := GetUserUUID(r.Context())
+			if userID == uuid.Nil {
+				httputil.Error(w, http.StatusUnauthorized, "missing authorization")
+				return
+			}
+
+			isAdmin, err := authService.IsAdmin(r.Context(), userID)
+			if err != nil {
+				log.Printf("ERROR: Admin check failed for user %s: %v", userID, err)
+				httputil.Error(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+			subject := policy.Subject{UserID: userID, Admin: isAdmin}
+			if policy.Authorize(subject, policy.Administer, policy.Platform{}) != policy.Allow {
+				httputil.Error(w, http.StatusForbidden, "admin access required")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -2,10 +2,14 @@ package middleware
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"strings"
 
+	"devjournal/internal/errreport"
 	"devjournal/internal/service"
+	"devjournal/internal/tenant"
+	"devjournal/pkg/httputil"
 
 	"github.com/google/uuid"
 )
@@ -22,45 +26,53 @@ const (
 func AuthMiddleware(authService *service.AuthService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			var tokenString string
-
-			// Try Authorization header first
-			authHeader := r.Header.Get("Authorization")
-			if authHeader != "" {
-				// Parse "Bearer <token>"
-				const prefix = "Bearer "
-				if len(authHeader) >= len(prefix) && strings.HasPrefix(authHeader, prefix) {
-					tokenString = authHeader[len(prefix):]
-				}
-			}
-
-			// Fall back to query parameter (for WebSocket connections)
+			tokenString := bearerToken(r)
 			if tokenString == "" {
-				tokenString = r.URL.Query().Get("token")
-			}
-
-			if tokenString == "" {
-				http.Error(w, `{"error":"missing authorization"}`, http.StatusUnauthorized)
+				httputil.Error(w, http.StatusUnauthorized, "missing authorization")
 				return
 			}
 
 			// Validate token
-			claims, err := authService.ValidateToken(tokenString)
+			claims, err := authService.ValidateSession(r.Context(), tokenString)
 			if err != nil {
-				http.Error(w, `{"error":"invalid or expired token"}`, http.StatusUnauthorized)
+				httputil.Error(w, http.StatusUnauthorized, "invalid or expired token")
 				return
 			}
 
+			// Impersonation tokens only read unless the admin asked for write access, can't be
+			// used to sign in elsewhere, and leave an audit line for every request
+			if imp := claims.Impersonation; imp != nil {
+				readOnly := r.Method == http.MethodGet || r.Method == http.MethodHead
+				if strings.HasPrefix(r.URL.Path, "/api/auth/") || (imp.ReadOnly && !readOnly) {
+					httputil.Error(w, http.StatusForbidden, "not allowed while impersonating a user")
+					return
+				}
+				log.Printf("AUDIT: admin %s acting as user %s (impersonation %s): %s %s", imp.AdminID, claims.UserID, imp.SessionID, r.Method, r.URL.Path)
+			}
+
 			// Add user info to context
 			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID) // Already a uuid.UUID
+			errreport.SetUser(ctx, claims.UserID.String())
 			ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
 			ctx = context.WithValue(ctx, UserNameKey, claims.DisplayName)
+			if claims.WorkspaceID != uuid.Nil {
+				ctx = tenant.WithWorkspace(ctx, claims.WorkspaceID)
+			}
+			ctx = service.WithImpersonation(ctx, claims)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// bearerToken returns the token from the request's Authorization header
+func bearerToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+	return ""
+}
+
 // GetUserID extracts the user ID from context as string
 func GetUserID(ctx context.Context) string {
 	if userID, ok := ctx.Value(UserIDKey).(uuid.UUID); ok {
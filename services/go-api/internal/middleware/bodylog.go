@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// bodyRecorder wraps http.ResponseWriter to keep the first max bytes of the response body
+type bodyRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	max        int
+	body       bytes.Buffer
+	truncated  bool
+}
+
+func (rw *bodyRecorder) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *bodyRecorder) Write(b []byte) (int, error) {
+	if room := rw.max - rw.body.Len(); room > 0 {
+		rw.body.Write(b[:min(len(b), room)])
+	}
+	if rw.body.Len()+len(b) > rw.max {
+		rw.truncated = true
+	}
+	return rw.ResponseWriter.Write(b)
+}
+
+// Hijack implements http.Hijacker interface for WebSocket support
+func (rw *bodyRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := rw.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// BodyLogging logs a sample of request and response bodies for debugging.
+// sampleRate is the fraction of requests logged (0 to 1) and maxBytes caps each logged body.
+// Passwords, tokens, secrets, and snippet code are redacted from JSON bodies; other bodies
+// are logged by size only.
+func BodyLogging(sampleRate float64, maxBytes int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if sampleRate <= 0 || rand.Float64() >= sampleRate || r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var reqBody []byte
+			if r.Body != nil {
+				reqBody, _ = io.ReadAll(io.LimitReader(r.Body, int64(maxBytes)+1))
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(reqBody), r.Body), r.Body}
+			}
+
+			recorder := &bodyRecorder{ResponseWriter: w, statusCode: http.StatusOK, max: maxBytes}
+			next.ServeHTTP(recorder, r)
+
+			log.Printf("DEBUG: %s %s request=%s response(%d)=%s",
+				r.Method,
+				r.URL.Path,
+				redactBody(reqBody, maxBytes, len(reqBody) > maxBytes),
+				recorder.statusCode,
+				redactBody(recorder.body.Bytes(), maxBytes, recorder.truncated),
+			)
+		})
+	}
+}
+
+// redactBody renders a body for the log with sensitive JSON fields replaced
+func redactBody(body []byte, maxBytes int, truncated bool) string {
+	if len(body) == 0 {
+		return "-"
+	}
+	if truncated {
+		return fmt.Sprintf("[truncated body, over %d bytes]", maxBytes)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Sprintf("[non-JSON body, %d bytes]", len(body))
+	}
+
+	// The error envelope's code is a machine-readable error code, not source code
+	isEnvelope := false
+	if obj, ok := value.(map[string]interface{}); ok {
+		_, isEnvelope = obj["message"]
+	}
+
+	out, err := json.Marshal(redactValue(value, !isEnvelope))
+	if err != nil {
+		return fmt.Sprintf("[unloggable body, %d bytes]", len(body))
+	}
+	return string(out)
+}
+
+// redactValue replaces sensitive fields in a decoded JSON value.
+// redactCode controls whether top-level "code" fields count as snippet code.
+func redactValue(value interface{}, redactCode bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveField(key, redactCode) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(field, true)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item, true)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// isSensitiveField reports whether a JSON key holds a password, token, secret, ticket, or code body
+func isSensitiveField(key string, redactCode bool) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	switch {
+	case normalized == "code":
+		return redactCode
+	case normalized == "originalcode": // a snippet file's source before formatting
+		return true
+	case strings.Contains(normalized, "password"),
+		strings.Contains(normalized, "secret"),
+		strings.HasSuffix(normalized, "token"),
+		normalized == "apikey",
+		normalized == "authorization",
+		normalized == "devicecode", // polls for a sign-in token in the device flow
+		normalized == "usercode",
+		normalized == "ticket": // opens a chat WebSocket
+		return true
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		hidden []string
+		shown  []string
+	}{
+		{
+			name:   "credentials",
+			body:   `{"email": "ada@example.com", "password": "hunter2", "token": "jwt-value"}`,
+			hidden: []string{"hunter2", "jwt-value"},
+			shown:  []string{"ada@example.com"},
+		},
+		{
+			name:   "device flow codes",
+			body:   `{"deviceCode": "device-secret", "user_code": "BDFG-HJKL", "interval": 5}`,
+			hidden: []string{"device-secret", "BDFG-HJKL"},
+			shown:  []string{`"interval":5`},
+		},
+		{
+			name:   "chat ticket",
+			body:   `{"ticket": "ticket-value", "expiresIn": 30}`,
+			hidden: []string{"ticket-value"},
+			shown:  []string{`"expiresIn":30`},
+		},
+		{
+			name:   "snippet source",
+			body:   `{"files": [{"name": "main.go", "code": "package main", "originalCode": "package  main"}]}`,
+			hidden: []string{"package main", "package  main"},
+			shown:  []string{"main.go"},
+		},
+		{
+			name:  "error envelope code",
+			body:  `{"code": "NOT_FOUND", "message": "snippet not found"}`,
+			shown: []string{"NOT_FOUND"},
+		},
+	}
+	for _, tt := range tests {
+		got := redactBody([]byte(tt.body), 1024, false)
+		for _, s := range tt.hidden {
+			if strings.Contains(got, s) {
+				t.Errorf("%s: logged %s, want %q redacted", tt.name, got, s)
+			}
+		}
+		for _, s := range tt.shown {
+			if !strings.Contains(got, s) {
+				t.Errorf("%s: logged %s, want %q kept", tt.name, got, s)
+			}
+		}
+	}
+}
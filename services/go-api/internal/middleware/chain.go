@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"devjournal/internal/errreport"
+)
+
+// Chain combines middleware into one, with the first listed outermost, so a stack reads in the
+// order requests pass through it
+func Chain(middleware ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			next = middleware[i](next)
+		}
+		return next
+	}
+}
+
+// Edge is the stack both the REST and Connect servers start with: every request, preflights
+// included, gets a request ID and panic recovery, and preflights are answered by CORS before
+// anything that authenticates
+func Edge(reporter errreport.Reporter) []func(http.Handler) http.Handler {
+	return []func(http.Handler) http.Handler{
+		RequestID,
+		RecoveryWithReporter(reporter),
+		CORS,
+	}
+}
@@ -2,18 +2,27 @@ package middleware
 
 import "net/http"
 
+const (
+	corsAllowMethods = "GET, POST, PUT, DELETE, OPTIONS, PATCH"
+
+	// corsAllowHeaders includes the headers Connect and gRPC-Web clients send, so browsers may
+	// call the Connect server as well as the REST API
+	corsAllowHeaders = "Accept, Accept-Version, Authorization, Connect-Protocol-Version, Connect-Timeout-Ms, " +
+		"Content-Type, Grpc-Timeout, X-CSRF-Token, X-Grpc-Web, X-Request-ID, X-Requested-With, X-User-Agent, X-Vault-Token"
+
+	// corsExposeHeaders includes the headers gRPC-Web clients read errors from
+	corsExposeHeaders = "API-Version, Deprecation, Error-Code, Grpc-Message, Grpc-Status, Grpc-Status-Details-Bin, " +
+		"Link, Sunset, X-Request-ID"
+)
+
 // CORS adds CORS headers to responses
 func CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Set CORS headers
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token, X-Requested-With")
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
-		w.Header().Set("Access-Control-Max-Age", "86400")
+		setCORSHeaders(w)
 
-		// Handle preflight requests
-		if r.Method == http.MethodOptions {
+		if isPreflight(r) {
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
@@ -40,13 +49,10 @@ func CORSWithOrigins(allowedOrigins []string) func(http.Handler) http.Handler {
 				// Use first allowed origin as default
 				w.Header().Set("Access-Control-Allow-Origin", allowedOrigins[0])
 			}
+			w.Header().Add("Vary", "Origin")
+			setCORSHeaders(w)
 
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-			w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token, X-Requested-With")
-			w.Header().Set("Access-Control-Allow-Credentials", "true")
-			w.Header().Set("Access-Control-Max-Age", "86400")
-
-			if r.Method == http.MethodOptions {
+			if isPreflight(r) {
 				w.WriteHeader(http.StatusNoContent)
 				return
 			}
@@ -55,3 +61,18 @@ func CORSWithOrigins(allowedOrigins []string) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+func setCORSHeaders(w http.ResponseWriter) {
+	w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+	w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Set("Access-Control-Max-Age", "86400")
+	w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+}
+
+// isPreflight reports whether a request is a browser's CORS preflight. It is answered here,
+// before authentication, since browsers never send credentials with it; other OPTIONS
+// requests go on to the routes.
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"devjournal/internal/service"
+)
+
+// GuestKey marks a request from a signed-out guest
+const GuestKey contextKey = "guest"
+
+// GuestOrAuth lets signed-out guests read public content when guest access is enabled.
+// Requests with a token are authenticated as usual; GET and HEAD requests without one are
+// served with a guest context that has no user, so handlers only return public data. With
+// guest access disabled it is the same as AuthMiddleware.
+func GuestOrAuth(authService *service.AuthService, enabled bool) func(http.Handler) http.Handler {
+	auth := AuthMiddleware(authService)
+	return func(next http.Handler) http.Handler {
+		authenticated := auth(next)
+		if !enabled {
+			return authenticated
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hasToken := bearerToken(r) != ""
+			readOnly := r.Method == http.MethodGet || r.Method == http.MethodHead
+			if hasToken || !readOnly {
+				authenticated.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), GuestKey, true)))
+		})
+	}
+}
+
+// IsGuest reports whether the request is from a signed-out guest
+func IsGuest(ctx context.Context) bool {
+	guest, _ := ctx.Value(GuestKey).(bool)
+	return guest
+}
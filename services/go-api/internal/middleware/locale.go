@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"devjournal/internal/i18n"
+	"devjournal/internal/service"
+)
+
+// Locale picks the language of each response: the signed-in user's locale setting, otherwise
+// the best match for the Accept-Language header. It sets Content-Language, which httputil
+// translates error messages into, and puts the locale in the request context.
+func Locale(authService *service.AuthService, settingsService *service.SettingsService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := ""
+			if token := bearerToken(r); token != "" {
+				// Authentication itself happens per route; an invalid token just has no preference
+				if claims, err := authService.ValidateToken(token); err == nil {
+					locale = settingsService.Locale(r.Context(), claims.UserID)
+				}
+			}
+			if locale == "" {
+				locale = i18n.Match(r.Header.Get("Accept-Language"))
+			}
+
+			w.Header().Set("Content-Language", locale)
+			w.Header().Add("Vary", "Accept-Language")
+			next.ServeHTTP(w, r.WithContext(i18n.WithLocale(r.Context(), locale)))
+		})
+	}
+}
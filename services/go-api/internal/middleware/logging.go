@@ -6,6 +6,8 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"devjournal/internal/requestid"
 )
 
 // responseWriter wraps http.ResponseWriter to capture status code
@@ -57,13 +59,14 @@ func Logging(next http.Handler) http.Handler {
 
 		// Log request details
 		log.Printf(
-			"%s %s %s %d %d %s",
+			"%s %s %s %d %d %s %s",
 			r.RemoteAddr,
 			r.Method,
 			r.URL.Path,
 			wrapped.statusCode,
 			wrapped.size,
 			duration,
+			requestid.FromContext(r.Context()),
 		)
 	})
 }
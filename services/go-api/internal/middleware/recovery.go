@@ -4,25 +4,46 @@ import (
 	"log"
 	"net/http"
 	"runtime/debug"
+
+	"devjournal/internal/errreport"
+	"devjournal/internal/requestid"
+	"devjournal/pkg/httputil"
 )
 
 // Recovery recovers from panics and returns a 500 error
 func Recovery(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		defer func() {
-			if err := recover(); err != nil {
-				// Log the panic with stack trace
-				log.Printf("PANIC: %v\n%s", err, debug.Stack())
-
-				// Return 500 error
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusInternalServerError)
-				w.Write([]byte(`{"error":"internal server error"}`))
-			}
-		}()
-
-		next.ServeHTTP(w, r)
-	})
+	return RecoveryWithReporter(nil)(next)
+}
+
+// RecoveryWithReporter is Recovery that also reports panics, with the request and user they
+// happened in, to reporter if it is not nil
+func RecoveryWithReporter(reporter errreport.Reporter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := errreport.WithScope(r.Context())
+			defer func() {
+				if err := recover(); err != nil {
+					stack := debug.Stack()
+
+					// Log the panic with stack trace
+					log.Printf("PANIC: %v (request %s)\n%s", err, requestid.FromContext(ctx), stack)
+					if reporter != nil {
+						ev := errreport.Panic(err, stack)
+						ev.RequestID = requestid.FromContext(ctx)
+						ev.UserID = errreport.User(ctx)
+						ev.Method, ev.Path = r.Method, r.URL.Path
+						ev.Tags = map[string]string{"component": "http"}
+						reporter.Report(ev)
+					}
+
+					// Return 500 error
+					httputil.Error(w, http.StatusInternalServerError, "internal server error")
+				}
+			}()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
 // RecoveryWithHandler creates a recovery middleware with custom error handler
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"devjournal/internal/requestid"
+)
+
+// RequestID gives each request an ID: the client's X-Request-ID if it sent a usable one,
+// otherwise a new one. The ID is echoed in the X-Request-ID response header, which httputil
+// copies into error bodies, and put in the request context for logs.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+		w.Header().Set(requestid.Header, id)
+		next.ServeHTTP(w, r.WithContext(requestid.WithID(r.Context(), id)))
+	})
+}
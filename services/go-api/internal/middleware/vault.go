@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"devjournal/internal/service"
+	"devjournal/internal/vault"
+	"devjournal/pkg/httputil"
+)
+
+// VaultTokenHeader carries the vault session token returned by POST /api/auth/vault
+const VaultTokenHeader = "X-Vault-Token"
+
+// VaultSession opens the vault for requests with a valid vault session token, letting them read
+// and edit vault entries. Requests without one are served with the vault locked; an invalid or
+// expired token is rejected so clients know to ask for the password again. It must run after
+// AuthMiddleware.
+func VaultSession(authService *service.AuthService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get(VaultTokenHeader)
+			if token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			expiresAt, err := authService.ValidateVaultToken(token, GetUserUUID(r.Context()))
+			if err != nil {
+				httputil.WriteError(w, err, "invalid or expired vault session")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(vault.WithSession(r.Context(), expiresAt)))
+		})
+	}
+}
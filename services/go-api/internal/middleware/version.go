@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"devjournal/pkg/httputil"
+)
+
+// API versions served by this build, oldest first. The last one is current.
+var supportedAPIVersions = []string{"v1"}
+
+// currentAPIVersion is served to unversioned requests that do not ask for a version
+var currentAPIVersion = supportedAPIVersions[len(supportedAPIVersions)-1]
+
+// APIVersion routes /api/{version}/... to the handlers registered under /api/... and keeps the
+// unversioned /api/... paths working as a deprecated alias.
+//
+// Version negotiation:
+//   - A version in the path (/api/v1/entries) always wins; unknown versions get 404.
+//   - Unversioned paths use the Accept-Version header if set (406 if unsupported),
+//     otherwise the current version, and carry Deprecation and Link headers pointing
+//     at the versioned path. sunset, if set, is sent as the Sunset header (an HTTP-date).
+//
+// Every /api response carries the version that served it in API-Version.
+func APIVersion(sunset string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rest, ok := strings.CutPrefix(r.URL.Path, "/api/")
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			segment, _, _ := strings.Cut(rest, "/")
+			if isVersionSegment(segment) {
+				if !isSupportedAPIVersion(segment) {
+					httputil.Error(w, http.StatusNotFound, "unsupported API version")
+					return
+				}
+				w.Header().Set("API-Version", segment)
+				next.ServeHTTP(w, withPath(r, "/api/"+strings.TrimPrefix(rest, segment+"/")))
+				return
+			}
+
+			version := currentAPIVersion
+			if requested := r.Header.Get("Accept-Version"); requested != "" {
+				if !isSupportedAPIVersion(requested) {
+					httputil.Error(w, http.StatusNotAcceptable, "unsupported API version")
+					return
+				}
+				version = requested
+			}
+
+			w.Header().Set("API-Version", version)
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", "</api/"+version+"/"+rest+`>; rel="successor-version"`)
+			if sunset != "" {
+				w.Header().Set("Sunset", sunset)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isVersionSegment reports whether a path segment looks like "v1", "v2", ...
+func isVersionSegment(segment string) bool {
+	if len(segment) < 2 || segment[0] != 'v' {
+		return false
+	}
+	for _, c := range segment[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isSupportedAPIVersion(version string) bool {
+	for _, v := range supportedAPIVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// withPath returns a shallow copy of r with its URL path replaced, like http.StripPrefix
+func withPath(r *http.Request, path string) *http.Request {
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = path
+	r2.URL.RawPath = ""
+	return r2
+}
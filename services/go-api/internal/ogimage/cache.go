@@ -0,0 +1,68 @@
+package ogimage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// designVersion changes whenever the card design does, so cached images and ETags are replaced
+const designVersion = 1
+
+// Cache keeps recently rendered cards, so crawlers and chat apps fetching the same image don't
+// render it again
+type Cache struct {
+	mu     sync.Mutex
+	size   int
+	images map[string][]byte
+	order  []string // keys, oldest first
+}
+
+// NewCache creates a cache of up to size rendered images
+func NewCache(size int) *Cache {
+	return &Cache{size: size, images: make(map[string][]byte, size)}
+}
+
+// Render returns the card in format (PNG or SVG) and an ETag that changes with what the card shows
+func (c *Cache) Render(card *Card, format string) ([]byte, string, error) {
+	sum := sha256.New()
+	fmt.Fprintf(sum, "%d\x00%s\x00%s\x00%s\x00%s\x00%s\x00%d", designVersion, format, card.Kind, card.Title, card.Author, card.Language, card.Streak)
+	key := hex.EncodeToString(sum.Sum(nil))[:32]
+	etag := `"` + key + `"`
+
+	c.mu.Lock()
+	img, ok := c.images[key]
+	c.mu.Unlock()
+	if ok {
+		return img, etag, nil
+	}
+
+	var b bytes.Buffer
+	var err error
+	switch format {
+	case PNG:
+		err = card.PNG(&b)
+	case SVG:
+		err = card.SVG(&b)
+	default:
+		err = fmt.Errorf("unknown image format %q", format)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	img = b.Bytes()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.images[key]; !ok {
+		if len(c.order) >= c.size {
+			delete(c.images, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.images[key] = img
+		c.order = append(c.order, key)
+	}
+	return img, etag, nil
+}
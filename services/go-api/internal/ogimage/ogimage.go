@@ -0,0 +1,370 @@
+// Package ogimage renders Open Graph share cards, the images link previews show for public
+// snippets and journal entries, as PNG or SVG
+package ogimage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// Card size, the 1.91:1 ratio link previews crop to
+const (
+	Width  = 1200
+	Height = 630
+)
+
+// Image formats
+const (
+	PNG = "png"
+	SVG = "svg"
+)
+
+const (
+	margin      = 80
+	accentWidth = 16
+	titleLines  = 3
+	badgeHeight = 56
+	badgePad    = 24
+)
+
+// fontFamily names the Go fonts the PNG is drawn with, falling back to similar sans-serifs in SVG viewers
+const fontFamily = "Go, 'Helvetica Neue', Arial, sans-serif"
+
+// Colors of the card, matching the web app's dark theme
+var (
+	background = rgb(0x0f172a)
+	foreground = rgb(0xf8fafc)
+	muted      = rgb(0x94a3b8)
+	brand      = rgb(0x818cf8)
+	streak     = rgb(0xea580c)
+)
+
+// Card is what a share card shows
+type Card struct {
+	Kind     string // label above the title, e.g. "Snippet"
+	Title    string
+	Author   string
+	Language string // drawn as a badge in the corner; empty for journal entries
+	Streak   int    // the author's current learning streak in days; 0 hides the badge
+}
+
+// text is a line of text on the card. X, Y is the start of its baseline.
+type text struct {
+	X, Y  int
+	S     string
+	Size  float64
+	Bold  bool
+	Color color.RGBA
+}
+
+// badge is a pill with a label
+type badge struct {
+	Rect  image.Rectangle
+	Fill  color.RGBA
+	Label text
+}
+
+// layout is where everything goes on a card, shared by the PNG and SVG renderers so both wrap
+// text the same way
+type layout struct {
+	Accent color.RGBA // the bar down the left edge
+	Badges []badge
+	Texts  []text
+}
+
+// PNG writes the card as a PNG image
+func (c *Card) PNG(w io.Writer) error {
+	l := c.layout()
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, accentWidth, Height), image.NewUniform(l.Accent), image.Point{}, draw.Src)
+
+	for _, b := range l.Badges {
+		fillPill(img, b.Rect, b.Fill)
+		drawText(img, b.Label)
+	}
+	for _, t := range l.Texts {
+		drawText(img, t)
+	}
+	return png.Encode(w, img)
+}
+
+// SVG writes the card as an SVG image
+func (c *Card) SVG(w io.Writer) error {
+	l := c.layout()
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", Width, Height, Width, Height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`+"\n", Width, Height, hexColor(background))
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`+"\n", accentWidth, Height, hexColor(l.Accent))
+	fmt.Fprintf(&b, `<g font-family="%s">`+"\n", fontFamily)
+	for _, badge := range l.Badges {
+		r := badge.Rect
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="%d" fill="%s"/>`+"\n", r.Min.X, r.Min.Y, r.Dx(), r.Dy(), r.Dy()/2, hexColor(badge.Fill))
+		writeSVGText(&b, badge.Label)
+	}
+	for _, t := range l.Texts {
+		writeSVGText(&b, t)
+	}
+	b.WriteString("</g>\n</svg>\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func (c *Card) layout() *layout {
+	l := &layout{Accent: brand}
+	contentWidth := Width - 2*margin
+
+	// Kind label, with the language badge opposite it
+	l.Texts = append(l.Texts, text{X: margin, Y: 120, S: strings.ToUpper(c.Kind), Size: 28, Bold: true, Color: muted})
+	if c.Language != "" {
+		label, fill := languageBadge(c.Language)
+		l.Accent = fill
+		b := newBadge(label, fill, 0, 78)
+		shift := Width - margin - b.Rect.Dx()
+		b.Rect = b.Rect.Add(image.Pt(shift, 0))
+		b.Label.X += shift
+		l.Badges = append(l.Badges, b)
+	}
+
+	size, lines := fitTitle(c.Title, contentWidth)
+	lineHeight := int(size * 1.25)
+	y := 190 + int(size)
+	for _, line := range lines {
+		l.Texts = append(l.Texts, text{X: margin, Y: y, S: line, Size: size, Bold: true, Color: foreground})
+		y += lineHeight
+	}
+
+	// Author and streak along the bottom, with the wordmark opposite
+	bottom := Height - margin
+	wordmark := text{Y: bottom, S: "DevJournal", Size: 32, Bold: true, Color: brand}
+	wordmark.X = Width - margin - measure(wordmark)
+	l.Texts = append(l.Texts, wordmark)
+
+	author := text{X: margin, Y: bottom, Size: 34, Color: foreground}
+	author.S = truncate(c.Author, author.Size, false, contentWidth/2)
+	l.Texts = append(l.Texts, author)
+	if c.Streak > 0 {
+		label := fmt.Sprintf("%d-day streak", c.Streak)
+		x := margin
+		if author.S != "" {
+			x += measure(author) + badgePad
+		}
+		l.Badges = append(l.Badges, newBadge(label, streak, x, bottom-40))
+	}
+	return l
+}
+
+// newBadge places a pill sized to its label at x, y
+func newBadge(label string, fill color.RGBA, x, y int) badge {
+	t := text{S: label, Size: 28, Bold: true, Color: contrast(fill)}
+	width := measure(t) + 2*badgePad
+	t.X = x + badgePad
+	t.Y = y + (badgeHeight+int(t.Size*0.7))/2 // center the cap height
+	return badge{Rect: image.Rect(x, y, x+width, y+badgeHeight), Fill: fill, Label: t}
+}
+
+// fitTitle picks the largest title size that fits in titleLines lines, truncating the title at the smallest
+func fitTitle(title string, width int) (float64, []string) {
+	sizes := []float64{72, 60, 48}
+	for _, size := range sizes[:len(sizes)-1] {
+		if lines, ok := wrap(title, size, width, titleLines); ok {
+			return size, lines
+		}
+	}
+	size := sizes[len(sizes)-1]
+	lines, _ := wrap(title, size, width, titleLines)
+	return size, lines
+}
+
+// wrap breaks s into at most maxLines lines of bold text no wider than width. ok is false when
+// s did not fit and the last line was truncated.
+func wrap(s string, size float64, width, maxLines int) (lines []string, ok bool) {
+	fits := func(line string) bool {
+		return measure(text{S: line, Size: size, Bold: true}) <= width
+	}
+
+	var line string
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if fits(candidate) {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		// Break words too long for a line of their own
+		for !fits(word) {
+			n := nextRune(word)
+			for n < len(word) && fits(word[:n+nextRune(word[n:])]) {
+				n += nextRune(word[n:])
+			}
+			lines = append(lines, word[:n])
+			word = word[n:]
+		}
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	if len(lines) <= maxLines {
+		return lines, true
+	}
+	lines = lines[:maxLines]
+	lines[maxLines-1] = truncate(lines[maxLines-1]+"…", size, true, width)
+	return lines, false
+}
+
+// truncate shortens s with an ellipsis until it is no wider than width
+func truncate(s string, size float64, bold bool, width int) string {
+	t := text{S: s, Size: size, Bold: bold}
+	if measure(t) <= width {
+		return s
+	}
+	s = strings.TrimSuffix(s, "…")
+	for s != "" {
+		_, n := utf8.DecodeLastRuneInString(s)
+		s = s[:len(s)-n]
+		t.S = strings.TrimRight(s, " ") + "…"
+		if measure(t) <= width {
+			return t.S
+		}
+	}
+	return ""
+}
+
+func nextRune(s string) int {
+	_, n := utf8.DecodeRuneInString(s)
+	return n
+}
+
+var fonts = sync.OnceValue(func() [2]*opentype.Font {
+	return [2]*opentype.Font{mustParse(goregular.TTF), mustParse(gobold.TTF)}
+})
+
+func mustParse(ttf []byte) *opentype.Font {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		panic(fmt.Sprintf("ogimage: failed to parse font: %v", err))
+	}
+	return f
+}
+
+// face returns a font face for t. Faces are not safe for concurrent use, so each caller gets its own.
+func face(t text) font.Face {
+	f := fonts()[0]
+	if t.Bold {
+		f = fonts()[1]
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: t.Size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		panic(fmt.Sprintf("ogimage: failed to create font face: %v", err))
+	}
+	return face
+}
+
+// measure returns the width of t in pixels
+func measure(t text) int {
+	return font.MeasureString(face(t), t.S).Ceil()
+}
+
+func drawText(dst draw.Image, t text) {
+	d := font.Drawer{Dst: dst, Src: image.NewUniform(t.Color), Face: face(t), Dot: fixed.P(t.X, t.Y)}
+	d.DrawString(t.S)
+}
+
+// fillPill draws an anti-aliased rectangle with fully rounded ends
+func fillPill(dst draw.Image, r image.Rectangle, fill color.RGBA) {
+	w, h := float32(r.Dx()), float32(r.Dy())
+	radius := h / 2
+	k := radius * 0.5523 // control point distance approximating a quarter circle with a cubic
+
+	z := vector.NewRasterizer(r.Dx(), r.Dy())
+	z.MoveTo(radius, 0)
+	z.LineTo(w-radius, 0)
+	z.CubeTo(w-radius+k, 0, w, radius-k, w, radius)
+	z.CubeTo(w, radius+k, w-radius+k, h, w-radius, h)
+	z.LineTo(radius, h)
+	z.CubeTo(radius-k, h, 0, radius+k, 0, radius)
+	z.CubeTo(0, radius-k, radius-k, 0, radius, 0)
+	z.ClosePath()
+	z.Draw(dst, r, image.NewUniform(fill), image.Point{})
+}
+
+func writeSVGText(b *bytes.Buffer, t text) {
+	weight := "normal"
+	if t.Bold {
+		weight = "bold"
+	}
+	fmt.Fprintf(b, `<text x="%d" y="%d" font-size="%g" font-weight="%s" fill="%s">`, t.X, t.Y, t.Size, weight, hexColor(t.Color))
+	xml.EscapeText(b, []byte(t.S))
+	b.WriteString("</text>\n")
+}
+
+// languageBadges are the labels and colors of language badges, after GitHub's language colors
+var languageBadges = map[string]struct {
+	label string
+	color uint32
+}{
+	"go": {"GO", 0x00add8}, "typescript": {"TS", 0x3178c6}, "javascript": {"JS", 0xf1e05a},
+	"python": {"PY", 0x3572a5}, "ruby": {"RB", 0x701516}, "rust": {"RS", 0xdea584},
+	"java": {"JAVA", 0xb07219}, "kotlin": {"KT", 0xa97bff}, "swift": {"SWIFT", 0xf05138},
+	"c": {"C", 0x555555}, "cpp": {"C++", 0xf34b7d}, "csharp": {"C#", 0x178600},
+	"php": {"PHP", 0x4f5d95}, "scala": {"SCALA", 0xc22d40}, "bash": {"SH", 0x89e051},
+	"shell": {"SH", 0x89e051}, "powershell": {"PS", 0x012456}, "sql": {"SQL", 0xe38c00},
+	"html": {"HTML", 0xe34c26}, "css": {"CSS", 0x563d7c}, "scss": {"SCSS", 0xc6538c},
+	"json": {"JSON", 0x292929}, "yaml": {"YAML", 0xcb171e}, "markdown": {"MD", 0x083fa1},
+	"dart": {"DART", 0x00b4ab}, "elixir": {"EX", 0x6e4a7e}, "haskell": {"HS", 0x5e5086},
+	"lua": {"LUA", 0x000080}, "r": {"R", 0x198ce7}, "vue": {"VUE", 0x41b883},
+	"svelte": {"SVELTE", 0xff3e00}, "dockerfile": {"DOCKER", 0x384d54}, "graphql": {"GQL", 0xe10098},
+}
+
+// languageBadge returns the label and color of a language's badge. Unknown languages get
+// their name on a neutral badge.
+func languageBadge(language string) (string, color.RGBA) {
+	if b, ok := languageBadges[strings.ToLower(language)]; ok {
+		return b.label, rgb(b.color)
+	}
+	label := strings.ToUpper(language)
+	if utf8.RuneCountInString(label) > 10 {
+		label = string([]rune(label)[:10])
+	}
+	return label, rgb(0x334155)
+}
+
+// contrast returns a text color readable on fill
+func contrast(fill color.RGBA) color.RGBA {
+	luminance := 0.299*float64(fill.R) + 0.587*float64(fill.G) + 0.114*float64(fill.B)
+	if luminance > 160 {
+		return background
+	}
+	return foreground
+}
+
+func rgb(hex uint32) color.RGBA {
+	return color.RGBA{R: uint8(hex >> 16), G: uint8(hex >> 8), B: uint8(hex), A: 0xff}
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
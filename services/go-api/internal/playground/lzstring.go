@@ -0,0 +1,115 @@
+package playground
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// uriSafeAlphabet is lz-string's alphabet for compressToEncodedURIComponent
+const uriSafeAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+-$"
+
+// compressToEncodedURIComponent matches lz-string's function of the same name, which the
+// TypeScript playground decodes its #code/ fragment with. Like JavaScript, it works on UTF-16
+// code units.
+func compressToEncodedURIComponent(input string) string {
+	units := utf16.Encode([]rune(input))
+	w := &lzBitWriter{bitsPerChar: 6}
+
+	// Dictionary keys are sequences of code units, two bytes each
+	key := func(seq []uint16) string {
+		var b strings.Builder
+		for _, u := range seq {
+			b.WriteByte(byte(u >> 8))
+			b.WriteByte(byte(u))
+		}
+		return b.String()
+	}
+
+	dictionary := make(map[string]int)
+	toCreate := make(map[string]bool)
+	enlargeIn, dictSize, numBits := 2, 3, 2
+	var current []uint16
+
+	// emit writes the code for current, adding its first unit to the output if it is new
+	emit := func() {
+		k := key(current)
+		if toCreate[k] {
+			if current[0] < 256 {
+				w.write(0, numBits)
+				w.write(int(current[0]), 8)
+			} else {
+				w.write(1, numBits)
+				w.write(int(current[0]), 16)
+			}
+			enlargeIn--
+			if enlargeIn == 0 {
+				enlargeIn = 1 << numBits
+				numBits++
+			}
+			delete(toCreate, k)
+		} else {
+			w.write(dictionary[k], numBits)
+		}
+		enlargeIn--
+		if enlargeIn == 0 {
+			enlargeIn = 1 << numBits
+			numBits++
+		}
+	}
+
+	for _, u := range units {
+		c := key([]uint16{u})
+		if _, ok := dictionary[c]; !ok {
+			dictionary[c] = dictSize
+			dictSize++
+			toCreate[c] = true
+		}
+
+		extended := append(append([]uint16{}, current...), u)
+		if _, ok := dictionary[key(extended)]; ok {
+			current = extended
+			continue
+		}
+		emit()
+		dictionary[key(extended)] = dictSize
+		dictSize++
+		current = []uint16{u}
+	}
+	if len(current) > 0 {
+		emit()
+	}
+
+	// Mark the end of the stream, then flush the last character
+	w.write(2, numBits)
+	for {
+		w.value <<= 1
+		if w.position == w.bitsPerChar-1 {
+			w.out.WriteByte(uriSafeAlphabet[w.value])
+			break
+		}
+		w.position++
+	}
+	return w.out.String()
+}
+
+// lzBitWriter packs values, least significant bit first, into characters of bitsPerChar bits
+type lzBitWriter struct {
+	bitsPerChar int
+	value       int
+	position    int
+	out         strings.Builder
+}
+
+func (w *lzBitWriter) write(value, bits int) {
+	for i := 0; i < bits; i++ {
+		w.value = w.value<<1 | value&1
+		if w.position == w.bitsPerChar-1 {
+			w.position = 0
+			w.out.WriteByte(uriSafeAlphabet[w.value])
+			w.value = 0
+		} else {
+			w.position++
+		}
+		value >>= 1
+	}
+}
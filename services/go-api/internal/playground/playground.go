@@ -0,0 +1,125 @@
+// Package playground makes links that open a snippet's code in its language's online
+// playground: shares saved with the Go and Rust playgrounds, and TypeScript playground links
+// that carry the code in the URL
+package playground
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxResponseBytes bounds playground responses read into memory
+const maxResponseBytes = 64 << 10
+
+// Playgrounds holds the base URLs of the playgrounds links are made for
+type Playgrounds struct {
+	GoURL         string // e.g. https://go.dev
+	RustURL       string // e.g. https://play.rust-lang.org
+	TypeScriptURL string // e.g. https://www.typescriptlang.org/play
+}
+
+// Client makes playground links
+type Client struct {
+	urls   Playgrounds
+	client *http.Client
+}
+
+// New creates a playground client
+func New(urls Playgrounds) *Client {
+	urls.GoURL = strings.TrimSuffix(urls.GoURL, "/")
+	urls.RustURL = strings.TrimSuffix(urls.RustURL, "/")
+	urls.TypeScriptURL = strings.TrimSuffix(urls.TypeScriptURL, "/")
+	return &Client{urls: urls, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Supported reports whether code in a snippet language can be opened in a playground
+func Supported(language string) bool {
+	switch language {
+	case "go", "rust", "typescript", "javascript":
+		return true
+	}
+	return false
+}
+
+// Uploads reports whether making a link for a language saves the code with the playground,
+// where anyone with the link can read it, rather than only putting it in the link
+func Uploads(language string) bool {
+	return language == "go" || language == "rust"
+}
+
+// Link returns a link that opens code in the playground for its language
+func (c *Client) Link(ctx context.Context, language, code string) (string, error) {
+	switch language {
+	case "go":
+		return c.shareGo(ctx, code)
+	case "rust":
+		return c.shareRust(ctx, code)
+	case "typescript":
+		return c.urls.TypeScriptURL + "?#code/" + compressToEncodedURIComponent(code), nil
+	case "javascript":
+		return c.urls.TypeScriptURL + "?filetype=js#code/" + compressToEncodedURIComponent(code), nil
+	}
+	return "", fmt.Errorf("no playground for %s", language)
+}
+
+// shareGo saves code with the Go playground, which replies with the ID of the share
+func (c *Client) shareGo(ctx context.Context, code string) (string, error) {
+	body, err := c.post(ctx, c.urls.GoURL+"/_/share", "text/plain; charset=utf-8", []byte(code))
+	if err != nil {
+		return "", fmt.Errorf("go playground: %w", err)
+	}
+	id := strings.TrimSpace(string(body))
+	if id == "" || strings.ContainsAny(id, "/?# \n") {
+		return "", fmt.Errorf("go playground: unexpected share ID %q", id)
+	}
+	return c.urls.GoURL + "/play/p/" + id, nil
+}
+
+// shareRust saves code as a gist through the Rust playground
+func (c *Client) shareRust(ctx context.Context, code string) (string, error) {
+	payload, err := json.Marshal(map[string]string{"code": code})
+	if err != nil {
+		return "", err
+	}
+	body, err := c.post(ctx, c.urls.RustURL+"/meta/gist", "application/json", payload)
+	if err != nil {
+		return "", fmt.Errorf("rust playground: %w", err)
+	}
+	var gist struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &gist); err != nil || gist.ID == "" {
+		return "", fmt.Errorf("rust playground: unexpected response %q", body)
+	}
+	query := url.Values{"version": {"stable"}, "mode": {"debug"}, "edition": {"2021"}, "gist": {gist.ID}}
+	return c.urls.RustURL + "/?" + query.Encode(), nil
+}
+
+func (c *Client) post(ctx context.Context, endpoint, contentType string, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("share failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,79 @@
+package playground
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompressToEncodedURIComponent(t *testing.T) {
+	// Expected values are from lz-string's compressToEncodedURIComponent
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "Q"},
+		{"a", "IZA"},
+		{"hello", "BYUwNmD2Q"},
+		{"Hello, world", "BIUwNmD2A0AEDukBOYAmQ"},
+		{"console.log('hi')", "MYewdgziA2CmB00QHMAUByAFgS3QSiA"},
+		{"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "IY18ZXEA"},
+	}
+	for _, tt := range tests {
+		if got := compressToEncodedURIComponent(tt.input); got != tt.want {
+			t.Errorf("compressToEncodedURIComponent(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/go/_/share":
+			if string(body) != "package main" {
+				t.Errorf("Go share body = %q", body)
+			}
+			w.Write([]byte("abc123"))
+		case "/rust/meta/gist":
+			var gist map[string]string
+			json.Unmarshal(body, &gist)
+			if gist["code"] != "fn main() {}" {
+				t.Errorf("Rust gist code = %q", gist["code"])
+			}
+			w.Write([]byte(`{"id": "f00d", "url": "https://gist.github.com/f00d", "code": "fn main() {}"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := New(Playgrounds{GoURL: server.URL + "/go/", RustURL: server.URL + "/rust", TypeScriptURL: "https://ts.example/play"})
+	tests := []struct {
+		language string
+		code     string
+		want     string
+	}{
+		{"go", "package main", server.URL + "/go/play/p/abc123"},
+		{"rust", "fn main() {}", server.URL + "/rust/?edition=2021&gist=f00d&mode=debug&version=stable"},
+		{"typescript", "console.log('hi')", "https://ts.example/play?#code/MYewdgziA2CmB00QHMAUByAFgS3QSiA"},
+		{"javascript", "console.log('hi')", "https://ts.example/play?filetype=js#code/MYewdgziA2CmB00QHMAUByAFgS3QSiA"},
+	}
+	for _, tt := range tests {
+		got, err := client.Link(context.Background(), tt.language, tt.code)
+		if err != nil || got != tt.want {
+			t.Errorf("Link(%s) = %q, %v; want %q", tt.language, got, err, tt.want)
+		}
+	}
+
+	if _, err := client.Link(context.Background(), "python", "print()"); err == nil {
+		t.Error("Link(python) succeeded, want an error")
+	}
+	broken := New(Playgrounds{GoURL: server.URL + "/missing"})
+	if _, err := broken.Link(context.Background(), "go", "package main"); err == nil {
+		t.Error("Link with a failing playground succeeded, want an error")
+	}
+}
@@ -0,0 +1,136 @@
+// Package policy decides who may do what. Services describe the subject acting, the action, and
+// the resource acted on, then turn the decision into their own errors, so the rules for journal
+// entries, snippets, study groups, and admin actions live in one place and can be tested without
+// a database.
+package policy
+
+import (
+	"context"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/vault"
+
+	"github.com/google/uuid"
+)
+
+// Subject is who is acting
+type Subject struct {
+	UserID        uuid.UUID // uuid.Nil for guests
+	Admin         bool      // a platform admin; only set where admin actions are checked
+	VaultUnlocked bool      // the request carries a vault session
+}
+
+// User returns the subject of a user's request, noting whether it carries a vault session
+func User(ctx context.Context, userID uuid.UUID) Subject {
+	return Subject{UserID: userID, VaultUnlocked: vault.Unlocked(ctx)}
+}
+
+// Action is what the subject wants to do with a resource
+type Action string
+
+const (
+	Read       Action = "read"
+	Update     Action = "update"
+	Delete     Action = "delete"
+	Link       Action = "link"       // attach a journal entry to a project, snippet, or learning path
+	Manage     Action = "manage"     // change a study group's settings, or reactivate it
+	Join       Action = "join"       // become a member of a study group
+	Administer Action = "administer" // use the admin API
+)
+
+// Decision is the outcome of a policy check
+type Decision int
+
+const (
+	Allow  Decision = iota
+	Deny            // the subject may know the resource exists, but not take the action
+	Hide            // the subject may not know the resource exists, so report it as not found
+	StepUp          // allowed once the user verifies their password and opens a vault session
+)
+
+// Group is a study group as the subject sees it
+type Group struct {
+	Public  bool
+	Role    string // the subject's role in the group, or "" if they aren't a member
+	Invited bool   // the subject holds a valid invite to the group
+}
+
+// Platform is the whole deployment, which admin actions act on
+type Platform struct{}
+
+// Authorize decides whether subject may take action on resource, which is a
+// *domain.JournalEntry, a *domain.Snippet, a Group, or Platform. Other resources are denied.
+func Authorize(subject Subject, action Action, resource any) Decision {
+	switch r := resource.(type) {
+	case *domain.JournalEntry:
+		return entry(subject, action, r)
+	case *domain.Snippet:
+		return snippet(subject, action, r)
+	case Group:
+		return group(action, r)
+	case Platform:
+		return platform(subject, action)
+	}
+	return Deny
+}
+
+// entry lets only an entry's author read or change it. Public entries are read through their
+// public pages, not as the author's entries. Vault entries also need a vault session, except to
+// link them, which doesn't show the entry.
+func entry(subject Subject, action Action, e *domain.JournalEntry) Decision {
+	if e == nil || subject.UserID == uuid.Nil || e.UserID != subject.UserID {
+		return Hide
+	}
+	if e.IsVault && !subject.VaultUnlocked && action != Link {
+		return StepUp
+	}
+	return Allow
+}
+
+// snippet lets anyone read public snippets moderators haven't hidden, and only the owner read
+// the rest or change any
+func snippet(subject Subject, action Action, s *domain.Snippet) Decision {
+	if s == nil {
+		return Hide
+	}
+	owner := subject.UserID != uuid.Nil && s.UserID == subject.UserID.String()
+	if owner {
+		return Allow
+	}
+	if !s.IsPublic || s.IsHidden {
+		return Hide
+	}
+	if action == Read {
+		return Allow
+	}
+	return Deny
+}
+
+// group lets members read a group, and anyone read a public one. Owners and admins manage it.
+// Anyone can join a public group, but a private one only with an invite.
+func group(action Action, g Group) Decision {
+	member := g.Role != ""
+	if action == Join && g.Invited {
+		return Allow
+	}
+	if !member && !g.Public {
+		return Hide
+	}
+	switch action {
+	case Read, Join:
+		return Allow
+	case Manage:
+		if g.Role == "owner" || g.Role == "admin" {
+			return Allow
+		}
+	}
+	return Deny
+}
+
+// platform lets only platform admins take admin actions
+func platform(subject Subject, action Action) Decision {
+	if action == Administer && subject.Admin && subject.UserID != uuid.Nil {
+		return Allow
+	}
+	return Deny
+}
@@ -0,0 +1,136 @@
+package policy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/vault"
+
+	"github.com/google/uuid"
+)
+
+func TestEntryRules(t *testing.T) {
+	author, other := uuid.New(), uuid.New()
+	entry := &domain.JournalEntry{UserID: author}
+	vaulted := &domain.JournalEntry{UserID: author, IsVault: true}
+	public := &domain.JournalEntry{UserID: author, IsPublic: true}
+
+	tests := []struct {
+		name     string
+		subject  Subject
+		action   Action
+		resource *domain.JournalEntry
+		want     Decision
+	}{
+		{"author reads", Subject{UserID: author}, Read, entry, Allow},
+		{"author deletes", Subject{UserID: author}, Delete, entry, Allow},
+		{"someone else reads", Subject{UserID: other}, Read, entry, Hide},
+		{"someone else reads a public entry", Subject{UserID: other}, Read, public, Hide},
+		{"guest reads", Subject{}, Read, entry, Hide},
+		{"missing entry", Subject{UserID: author}, Read, nil, Hide},
+		{"vault entry without a vault session", Subject{UserID: author}, Update, vaulted, StepUp},
+		{"vault entry with a vault session", Subject{UserID: author, VaultUnlocked: true}, Update, vaulted, Allow},
+		{"vault entry read without a vault session", Subject{UserID: author}, Read, vaulted, StepUp},
+		{"vault entry deleted without a vault session", Subject{UserID: author}, Delete, vaulted, StepUp},
+		{"someone else's vault entry", Subject{UserID: other, VaultUnlocked: true}, Read, vaulted, Hide},
+		{"author links an entry", Subject{UserID: author}, Link, entry, Allow},
+		{"author links a vault entry without a vault session", Subject{UserID: author}, Link, vaulted, Allow},
+		{"someone else links an entry", Subject{UserID: other}, Link, entry, Hide},
+		{"someone else links a vault entry", Subject{UserID: other, VaultUnlocked: true}, Link, vaulted, Hide},
+		{"guest links an entry", Subject{}, Link, entry, Hide},
+	}
+	for _, tt := range tests {
+		if got := Authorize(tt.subject, tt.action, tt.resource); got != tt.want {
+			t.Errorf("%s: Authorize = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSnippetRules(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	private := &domain.Snippet{UserID: owner.String()}
+	public := &domain.Snippet{UserID: owner.String(), IsPublic: true}
+	hidden := &domain.Snippet{UserID: owner.String(), IsPublic: true, IsHidden: true}
+
+	tests := []struct {
+		name     string
+		subject  Subject
+		action   Action
+		resource *domain.Snippet
+		want     Decision
+	}{
+		{"owner reads a private snippet", Subject{UserID: owner}, Read, private, Allow},
+		{"owner updates a hidden snippet", Subject{UserID: owner}, Update, hidden, Allow},
+		{"someone else reads a public snippet", Subject{UserID: other}, Read, public, Allow},
+		{"guest reads a public snippet", Subject{}, Read, public, Allow},
+		{"someone else updates a public snippet", Subject{UserID: other}, Update, public, Deny},
+		{"someone else deletes a public snippet", Subject{UserID: other}, Delete, public, Deny},
+		{"someone else reads a private snippet", Subject{UserID: other}, Read, private, Hide},
+		{"someone else updates a private snippet", Subject{UserID: other}, Update, private, Hide},
+		{"someone else reads a hidden snippet", Subject{UserID: other}, Read, hidden, Hide},
+		{"missing snippet", Subject{UserID: owner}, Read, nil, Hide},
+	}
+	for _, tt := range tests {
+		if got := Authorize(tt.subject, tt.action, tt.resource); got != tt.want {
+			t.Errorf("%s: Authorize = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGroupRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		action Action
+		group  Group
+		want   Decision
+	}{
+		{"member reads", Read, Group{Role: "member"}, Allow},
+		{"non-member reads a public group", Read, Group{Public: true}, Allow},
+		{"non-member reads a private group", Read, Group{}, Hide},
+		{"owner manages", Manage, Group{Role: "owner"}, Allow},
+		{"admin manages", Manage, Group{Role: "admin"}, Allow},
+		{"member manages", Manage, Group{Role: "member"}, Deny},
+		{"non-member manages a public group", Manage, Group{Public: true}, Deny},
+		{"non-member manages a private group", Manage, Group{}, Hide},
+		{"member deletes", Delete, Group{Role: "member"}, Deny},
+		{"non-member joins a public group", Join, Group{Public: true}, Allow},
+		{"non-member joins a private group", Join, Group{}, Hide},
+		{"invited non-member joins a private group", Join, Group{Invited: true}, Allow},
+		{"member joins again", Join, Group{Role: "member"}, Allow},
+		{"invited non-member reads a private group", Read, Group{Invited: true}, Hide},
+	}
+	for _, tt := range tests {
+		if got := Authorize(Subject{UserID: uuid.New()}, tt.action, tt.group); got != tt.want {
+			t.Errorf("%s: Authorize = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlatformRules(t *testing.T) {
+	id := uuid.New()
+	if got := Authorize(Subject{UserID: id, Admin: true}, Administer, Platform{}); got != Allow {
+		t.Errorf("admin: Authorize = %v; want Allow", got)
+	}
+	if got := Authorize(Subject{UserID: id}, Administer, Platform{}); got != Deny {
+		t.Errorf("user: Authorize = %v; want Deny", got)
+	}
+	if got := Authorize(Subject{Admin: true}, Administer, Platform{}); got != Deny {
+		t.Errorf("admin without a user ID: Authorize = %v; want Deny", got)
+	}
+	if got := Authorize(Subject{UserID: id, Admin: true}, Read, "unknown resource"); got != Deny {
+		t.Errorf("unknown resource: Authorize = %v; want Deny", got)
+	}
+}
+
+func TestUser(t *testing.T) {
+	id := uuid.New()
+	if s := User(context.Background(), id); s.UserID != id || s.VaultUnlocked || s.Admin {
+		t.Errorf("User = %+v; want a locked, non-admin subject", s)
+	}
+	ctx := vault.WithSession(context.Background(), time.Now().Add(time.Minute))
+	if s := User(ctx, id); !s.VaultUnlocked {
+		t.Errorf("User with a vault session = %+v; want unlocked", s)
+	}
+}
@@ -0,0 +1,155 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	apnsProductionURL = "https://api.push.apple.com/3/device/"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com/3/device/"
+
+	// apnsTokenLifetime is how long a provider token is reused. Apple rejects tokens older
+	// than an hour and throttles ones refreshed more often than every 20 minutes.
+	apnsTokenLifetime = 45 * time.Minute
+)
+
+// APNsConfig configures token-based authentication with the Apple Push Notification service
+type APNsConfig struct {
+	KeyPEM  []byte // contents of the .p8 signing key
+	KeyID   string
+	TeamID  string
+	Topic   string // the app's bundle ID
+	Sandbox bool   // use the development environment (apps built from Xcode)
+}
+
+// APNs sends notifications to iOS devices
+type APNs struct {
+	key     interface{}
+	keyID   string
+	teamID  string
+	topic   string
+	baseURL string
+
+	mu       sync.Mutex
+	jwt      string
+	issuedAt time.Time
+}
+
+// NewAPNs creates an APNs sender
+func NewAPNs(cfg APNsConfig) (*APNs, error) {
+	if cfg.KeyID == "" || cfg.TeamID == "" || cfg.Topic == "" {
+		return nil, errors.New("APNs requires a key ID, team ID, and topic")
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(cfg.KeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APNs key: %w", err)
+	}
+
+	baseURL := apnsProductionURL
+	if cfg.Sandbox {
+		baseURL = apnsSandboxURL
+	}
+	return &APNs{
+		key:     key,
+		keyID:   cfg.KeyID,
+		teamID:  cfg.TeamID,
+		topic:   cfg.Topic,
+		baseURL: baseURL,
+	}, nil
+}
+
+// Send delivers a notification to an iOS device token
+func (a *APNs) Send(ctx context.Context, token string, n *Notification) error {
+	providerToken, err := a.providerToken()
+	if err != nil {
+		return err
+	}
+
+	// Custom data sits beside the aps dictionary
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{"title": n.Title, "body": n.Body},
+			"sound": "default",
+		},
+	}
+	for k, v := range n.Data {
+		if k != "aps" {
+			payload[k] = v
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", a.topic)
+	req.Header.Set("apns-push-type", "alert")
+	if n.CollapseKey != "" {
+		req.Header.Set("apns-collapse-id", n.CollapseKey)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("apns request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	var failure struct {
+		Reason string `json:"reason"`
+	}
+	json.Unmarshal(respBody, &failure)
+	if resp.StatusCode == http.StatusGone || failure.Reason == "BadDeviceToken" || failure.Reason == "Unregistered" {
+		return ErrUnregistered
+	}
+	if failure.Reason == "ExpiredProviderToken" {
+		a.mu.Lock()
+		a.jwt = ""
+		a.mu.Unlock()
+	}
+	return fmt.Errorf("apns returned %d: %s", resp.StatusCode, failure.Reason)
+}
+
+// providerToken returns the signed JWT that authenticates requests, reusing it until it nears expiry
+func (a *APNs) providerToken() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	if a.jwt != "" && now.Sub(a.issuedAt) < apnsTokenLifetime {
+		return a.jwt, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": a.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = a.keyID
+	signed, err := token.SignedString(a.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign APNs token: %w", err)
+	}
+
+	a.jwt = signed
+	a.issuedAt = now
+	return a.jwt, nil
+}
@@ -0,0 +1,34 @@
+// Package push sends mobile push notifications through Firebase Cloud Messaging (Android)
+// and the Apple Push Notification service (iOS)
+package push
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrUnregistered is returned when a device token is no longer valid (the app was uninstalled
+// or the token rotated), so the token should be forgotten
+var ErrUnregistered = errors.New("device token is no longer registered")
+
+// Notification is an alert shown on a device
+type Notification struct {
+	Title string
+	Body  string
+
+	// Data is delivered to the app alongside the alert, e.g. the room to open
+	Data map[string]string
+
+	// CollapseKey replaces an undelivered notification with the same key rather than stacking it
+	CollapseKey string
+}
+
+// Sender delivers notifications to one push provider
+type Sender interface {
+	Send(ctx context.Context, token string, n *Notification) error
+}
+
+// httpClient is shared by both providers. APNs requires HTTP/2, which the default transport negotiates.
+var httpClient = &http.Client{Timeout: 10 * time.Second}
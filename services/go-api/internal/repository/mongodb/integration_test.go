@@ -0,0 +1,120 @@
+//go:build integration
+
+package mongodb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/testenv"
+	"devjournal/pkg/apperr"
+)
+
+var env *testenv.Env
+
+func TestMain(m *testing.M) {
+	testenv.Main(m, &env)
+}
+
+func newSnippet(userID, title, language string, tags ...string) *domain.Snippet {
+	return domain.NewSnippet(userID, title, "", "fmt.Println(\"hi\")", language, tags, nil, false)
+}
+
+func TestSnippetRepository(t *testing.T) {
+	repo := mongodb.NewSnippetRepository(env.Mongo, env.Reset(t))
+	ctx := context.Background()
+	owner, other := "11111111-1111-1111-1111-111111111111", "22222222-2222-2222-2222-222222222222"
+
+	snippet := newSnippet(owner, "Hello", "go", "basics")
+	if err := repo.Create(ctx, snippet); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if snippet.ID == "" {
+		t.Fatal("Create did not set the snippet ID")
+	}
+	if err := repo.Create(ctx, newSnippet(owner, "Sum", "python")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.Create(ctx, newSnippet(other, "Not mine", "go")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, snippet.ID)
+	if err != nil || found == nil || found.Title != "Hello" {
+		t.Fatalf("FindByID = %v, %v; want Hello", found, err)
+	}
+	if missing, err := repo.FindByID(ctx, "not-an-object-id"); err != nil || missing != nil {
+		t.Fatalf("FindByID(invalid) = %v, %v; want nil, nil", missing, err)
+	}
+
+	snippets, err := repo.FindByUserID(ctx, owner, 10, 0)
+	if err != nil || len(snippets) != 2 {
+		t.Fatalf("FindByUserID = %d snippets, %v; want 2", len(snippets), err)
+	}
+
+	byTag, err := repo.FindByTags(ctx, owner, []string{"basics"}, 10, 0)
+	if err != nil || len(byTag) != 1 {
+		t.Fatalf("FindByTags = %d snippets, %v; want 1", len(byTag), err)
+	}
+
+	stats, err := repo.GetLanguageStats(ctx, owner)
+	if err != nil || stats["go"] != 1 || stats["python"] != 1 {
+		t.Fatalf("GetLanguageStats = %v, %v; want one go and one python", stats, err)
+	}
+
+	snippet.Title = "Hello, world"
+	if err := repo.Update(ctx, snippet); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	if err := repo.Delete(ctx, snippet.ID, other); !errors.Is(err, apperr.ErrNotFound) {
+		t.Fatalf("Delete by another user = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(ctx, "not-an-object-id", owner); !errors.Is(err, apperr.ErrValidation) {
+		t.Fatalf("Delete(invalid) = %v, want ErrValidation", err)
+	}
+	if err := repo.Delete(ctx, snippet.ID, owner); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+}
+
+func TestSnippetRepositoryNearestReads(t *testing.T) {
+	repo := mongodb.NewSnippetRepository(env.Mongo, env.Reset(t)).ReadNearest(2 * time.Minute)
+	ctx := context.Background()
+	owner := "11111111-1111-1111-1111-111111111111"
+
+	if err := repo.Create(ctx, newSnippet(owner, "Hello", "go", "basics")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if snippets, err := repo.FindByUserID(ctx, owner, 10, 0); err != nil || len(snippets) != 1 {
+		t.Fatalf("FindByUserID = %d snippets, %v; want 1", len(snippets), err)
+	}
+	if count, err := repo.CountFiltered(ctx, owner, &domain.SnippetFilter{}); err != nil || count != 1 {
+		t.Fatalf("CountFiltered = %d, %v; want 1", count, err)
+	}
+}
+
+func TestSnippetViews(t *testing.T) {
+	repo := mongodb.NewSnippetRepository(env.Mongo, env.Reset(t))
+	ctx := context.Background()
+
+	snippet := newSnippet("11111111-1111-1111-1111-111111111111", "Viewed", "go")
+	snippet.IsPublic = true
+	if err := repo.Create(ctx, snippet); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	viewer := "33333333-3333-3333-3333-333333333333"
+	counted, first, err := repo.RecordView(ctx, snippet.ID, viewer, time.Hour)
+	if err != nil || !counted || !first {
+		t.Fatalf("first RecordView = %v, %v, %v; want counted first view", counted, first, err)
+	}
+	counted, _, err = repo.RecordView(ctx, snippet.ID, viewer, time.Hour)
+	if err != nil || counted {
+		t.Fatalf("repeat RecordView = %v, %v; want deduplicated", counted, err)
+	}
+}
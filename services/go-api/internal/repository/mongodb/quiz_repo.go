@@ -0,0 +1,147 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// QuizRepository handles study group quizzes in MongoDB
+type QuizRepository struct {
+	collection *mongo.Collection
+}
+
+// NewQuizRepository creates a new quiz repository
+func NewQuizRepository(client *mongo.Client, dbName string) *QuizRepository {
+	collection := client.Database(dbName).Collection("quizzes")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "group_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+
+	return &QuizRepository{collection: collection}
+}
+
+// quizDoc is the MongoDB representation of a quiz
+type quizDoc struct {
+	ID          primitive.ObjectID    `bson:"_id,omitempty"`
+	GroupID     string                `bson:"group_id"`
+	CreatedBy   string                `bson:"created_by"`
+	Title       string                `bson:"title"`
+	Description string                `bson:"description"`
+	Questions   []domain.QuizQuestion `bson:"questions"`
+	CreatedAt   time.Time             `bson:"created_at"`
+	UpdatedAt   time.Time             `bson:"updated_at"`
+}
+
+func quizFromDoc(doc *quizDoc) *domain.Quiz {
+	return &domain.Quiz{
+		ID:            doc.ID.Hex(),
+		GroupID:       doc.GroupID,
+		CreatedBy:     doc.CreatedBy,
+		Title:         doc.Title,
+		Description:   doc.Description,
+		Questions:     doc.Questions,
+		QuestionCount: len(doc.Questions),
+		CreatedAt:     doc.CreatedAt,
+		UpdatedAt:     doc.UpdatedAt,
+	}
+}
+
+// Create inserts a new quiz and sets its ID
+func (r *QuizRepository) Create(ctx context.Context, quiz *domain.Quiz) error {
+	doc := quizDoc{
+		GroupID:     quiz.GroupID,
+		CreatedBy:   quiz.CreatedBy,
+		Title:       quiz.Title,
+		Description: quiz.Description,
+		Questions:   quiz.Questions,
+		CreatedAt:   quiz.CreatedAt,
+		UpdatedAt:   quiz.UpdatedAt,
+	}
+	result, err := r.collection.InsertOne(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to create quiz: %w", err)
+	}
+	quiz.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return nil
+}
+
+// FindByID retrieves a quiz of a group, or nil if there is none
+func (r *QuizRepository) FindByID(ctx context.Context, id, groupID string) (*domain.Quiz, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil // Invalid ID format
+	}
+
+	var doc quizDoc
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid, "group_id": groupID}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find quiz: %w", err)
+	}
+	return quizFromDoc(&doc), nil
+}
+
+// ListByGroup retrieves a group's quizzes without their questions, newest first
+func (r *QuizRepository) ListByGroup(ctx context.Context, groupID string) ([]domain.Quiz, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"group_id": groupID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quizzes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	quizzes := []domain.Quiz{}
+	for cursor.Next(ctx) {
+		var doc quizDoc
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode quiz: %w", err)
+		}
+		quiz := quizFromDoc(&doc)
+		quiz.Questions = nil
+		quizzes = append(quizzes, *quiz)
+	}
+	return quizzes, cursor.Err()
+}
+
+// Update replaces a quiz's title, description, and questions
+func (r *QuizRepository) Update(ctx context.Context, quiz *domain.Quiz) error {
+	oid, err := primitive.ObjectIDFromHex(quiz.ID)
+	if err != nil {
+		return fmt.Errorf("invalid quiz ID: %w", err)
+	}
+	update := bson.M{"$set": bson.M{
+		"title":       quiz.Title,
+		"description": quiz.Description,
+		"questions":   quiz.Questions,
+		"updated_at":  quiz.UpdatedAt,
+	}}
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid, "group_id": quiz.GroupID}, update); err != nil {
+		return fmt.Errorf("failed to update quiz: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a quiz of a group
+func (r *QuizRepository) Delete(ctx context.Context, id, groupID string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil
+	}
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": oid, "group_id": groupID}); err != nil {
+		return fmt.Errorf("failed to delete quiz: %w", err)
+	}
+	return nil
+}
@@ -1,21 +1,32 @@
 package mongodb
 
 import (
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"log"
+	"slices"
 	"time"
 
 	"devjournal/internal/domain"
+	"devjournal/internal/tenant"
+	"devjournal/pkg/apperr"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // SnippetRepository handles snippet data persistence in MongoDB
 type SnippetRepository struct {
 	collection *mongo.Collection
+	reads      *mongo.Collection // collection, for reads that tolerate replication lag
+	views      *mongo.Collection
+	redirects  *mongo.Collection
 }
 
 // NewSnippetRepository creates a new snippet repository
@@ -30,57 +41,186 @@ func NewSnippetRepository(client *mongo.Client, dbName string) *SnippetRepositor
 		{
 			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
 		},
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
 		{
 			Keys: bson.D{{Key: "tags", Value: 1}},
 		},
 		{
-			Keys: bson.D{{Key: "prog_lang", Value: 1}},
+			Keys: bson.D{{Key: "files.prog_lang", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "dependencies", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "project_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys: bson.D{{Key: "is_public", Value: 1}, {Key: "trending_score", Value: -1}},
 		},
 		{
 			Keys: bson.D{
 				{Key: "title", Value: "text"},
 				{Key: "description", Value: "text"},
-				{Key: "code", Value: "text"},
+				{Key: "files.name", Value: "text"},
+				{Key: "files.code", Value: "text"},
 			},
 		},
 	}
 
+	// A collection has only one text index, so the one from before multi-file snippets must go first
+	collection.Indexes().DropOne(ctx, "title_text_description_text_code_text")
 	collection.Indexes().CreateMany(ctx, indexes)
 
-	return &SnippetRepository{collection: collection}
+	// Snippets created before workspaces existed belong to their owner's personal workspace
+	collection.UpdateMany(ctx,
+		bson.M{"workspace_id": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"workspace_id": "$user_id"}}}},
+	)
+
+	// Snippets created before multi-file snippets hold their code in a single file
+	repo := &SnippetRepository{collection: collection, reads: collection}
+	migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancelMigrate()
+	if migrated, err := repo.migrateFiles(migrateCtx); err != nil {
+		log.Printf("WARN: Failed to migrate snippets to files: %v", err)
+	} else if migrated > 0 {
+		log.Printf("Migrated %d snippets to files", migrated)
+	}
+
+	// Snippets saved before dependencies were extracted get them, so they can be filtered by
+	if migrated, err := repo.migrateDependencies(migrateCtx); err != nil {
+		log.Printf("WARN: Failed to extract snippet dependencies: %v", err)
+	} else if migrated > 0 {
+		log.Printf("Extracted dependencies of %d snippets", migrated)
+	}
+
+	// Snippets saved before near-duplicates were detected get simhashes, so new snippets are compared with them
+	if migrated, err := repo.migrateSimHashes(migrateCtx); err != nil {
+		log.Printf("WARN: Failed to compute snippet simhashes: %v", err)
+	} else if migrated > 0 {
+		log.Printf("Computed simhashes of %d snippets", migrated)
+	}
+
+	// One view record per viewer per snippet, used to dedupe views and count unique viewers
+	views := client.Database(dbName).Collection("snippet_views")
+	views.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "snippet_id", Value: 1}, {Key: "viewer_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	// IDs of snippets merged into others, so their share links keep working
+	redirects := client.Database(dbName).Collection("snippet_redirects")
+	redirects.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "target_id", Value: 1}}})
+
+	repo.views = views
+	repo.redirects = redirects
+	return repo
+}
+
+// ReadNearest sends list, search, and stats reads to whichever replica set member answers
+// fastest, within localThresholdMS of the URL, so each region reads from the closest copy.
+// Those members may lag behind the primary by up to maxStaleness (0 for no limit, otherwise at
+// least the 90s MongoDB allows). Reads that writes rely on, such as FindByID and Count for
+// quotas, stay on the primary.
+func (r *SnippetRepository) ReadNearest(maxStaleness time.Duration) *SnippetRepository {
+	var opts []readpref.Option
+	if maxStaleness > 0 {
+		opts = append(opts, readpref.WithMaxStaleness(max(maxStaleness, 90*time.Second)))
+	}
+	r.reads = r.collection.Database().Collection(r.collection.Name(),
+		options.Collection().SetReadPreference(readpref.Nearest(opts...)))
+	return r
 }
 
 // snippetDoc is the MongoDB document representation
 // Note: Language uses "prog_lang" BSON tag to avoid conflict with MongoDB's
 // reserved "language" field used for text index language override
 type snippetDoc struct {
-	ID          primitive.ObjectID     `bson:"_id,omitempty"`
-	UserID      string                 `bson:"user_id"`
-	Title       string                 `bson:"title"`
-	Description string                 `bson:"description"`
-	Code        string                 `bson:"code"`
-	Language    string                 `bson:"prog_lang"`
-	Tags        []string               `bson:"tags"`
-	Metadata    map[string]interface{} `bson:"metadata"`
-	IsPublic    bool                   `bson:"is_public"`
-	ViewsCount  int                    `bson:"views_count"`
-	CreatedAt   time.Time              `bson:"created_at"`
-	UpdatedAt   time.Time              `bson:"updated_at"`
+	ID            primitive.ObjectID     `bson:"_id,omitempty"`
+	UserID        string                 `bson:"user_id"`
+	WorkspaceID   string                 `bson:"workspace_id"`
+	Title         string                 `bson:"title"`
+	Description   string                 `bson:"description"`
+	Code          string                 `bson:"code"`
+	Language      string                 `bson:"prog_lang"`
+	Files         []snippetFileDoc       `bson:"files"`
+	Tags          []string               `bson:"tags"`
+	Dependencies  []string               `bson:"dependencies"`
+	Metadata      map[string]interface{} `bson:"metadata"`
+	IsPublic      bool                   `bson:"is_public"`
+	IsHidden      bool                   `bson:"is_hidden,omitempty"`
+	ProjectID     string                 `bson:"project_id,omitempty"`
+	SourceURL     string                 `bson:"source_url,omitempty"`
+	License       string                 `bson:"license,omitempty"`
+	ViewsCount    int                    `bson:"views_count"`
+	UniqueViewers int                    `bson:"unique_viewers"`
+	Stats         *domain.CodeStats      `bson:"stats,omitempty"`
+	Trending      float64                `bson:"trending_score"`
+	Playground    *playgroundDoc         `bson:"playground,omitempty"`
+	MergedFrom    []domain.MergedSnippet `bson:"merged_from,omitempty"`
+	SimHash       int64                  `bson:"simhash"` // domain.Snippet.SimHash; BSON has no unsigned integers
+	CreatedAt     time.Time              `bson:"created_at"`
+	UpdatedAt     time.Time              `bson:"updated_at"`
+}
+
+// playgroundDoc caches a snippet's playground link with a hash of the code it opens, so the
+// link is dropped once the code changes
+type playgroundDoc struct {
+	URL      string `bson:"url"`
+	CodeHash string `bson:"code_hash"`
+}
+
+// playgroundCodeHash identifies the language and code a playground link was made for
+func playgroundCodeHash(language, code string) string {
+	sum := sha256.Sum256([]byte(language + "\x00" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// snippetFileDoc is one file of a snippet document. Its language also uses "prog_lang", since the
+// text index treats a "language" field in embedded documents as a language override too.
+type snippetFileDoc struct {
+	Name     string           `bson:"name"`
+	Language string           `bson:"prog_lang"`
+	Code     string           `bson:"code"`
+	Stats    domain.CodeStats `bson:"stats"`
+	Original string           `bson:"original_code,omitempty"`
+}
+
+func toFileDocs(files []domain.SnippetFile) []snippetFileDoc {
+	docs := make([]snippetFileDoc, len(files))
+	for i, f := range files {
+		docs[i] = snippetFileDoc{Name: f.Name, Language: f.Language, Code: f.Code, Stats: f.Stats, Original: f.OriginalCode}
+	}
+	return docs
 }
 
 func toDoc(s *domain.Snippet) *snippetDoc {
 	doc := &snippetDoc{
-		UserID:      s.UserID,
-		Title:       s.Title,
-		Description: s.Description,
-		Code:        s.Code,
-		Language:    s.Language,
-		Tags:        s.Tags,
-		Metadata:    s.Metadata,
-		IsPublic:    s.IsPublic,
-		ViewsCount:  s.ViewsCount,
-		CreatedAt:   s.CreatedAt,
-		UpdatedAt:   s.UpdatedAt,
+		UserID:        s.UserID,
+		WorkspaceID:   s.WorkspaceID,
+		Title:         s.Title,
+		Description:   s.Description,
+		Code:          s.Code,
+		Language:      s.Language,
+		Files:         toFileDocs(s.Files),
+		Tags:          s.Tags,
+		Dependencies:  s.Dependencies,
+		Metadata:      s.Metadata,
+		IsPublic:      s.IsPublic,
+		IsHidden:      s.IsHidden,
+		ProjectID:     s.ProjectID,
+		SourceURL:     s.SourceURL,
+		License:       s.License,
+		ViewsCount:    s.ViewsCount,
+		UniqueViewers: s.UniqueViewers,
+		Stats:         &s.Stats,
+		MergedFrom:    s.MergedFrom,
+		SimHash:       int64(s.SimHash),
+		CreatedAt:     s.CreatedAt,
+		UpdatedAt:     s.UpdatedAt,
 	}
 	if s.ID != "" {
 		if oid, err := primitive.ObjectIDFromHex(s.ID); err == nil {
@@ -91,26 +231,143 @@ func toDoc(s *domain.Snippet) *snippetDoc {
 }
 
 func fromDoc(doc *snippetDoc) *domain.Snippet {
-	return &domain.Snippet{
-		ID:          doc.ID.Hex(),
-		UserID:      doc.UserID,
-		Title:       doc.Title,
-		Description: doc.Description,
-		Code:        doc.Code,
-		Language:    doc.Language,
-		Tags:        doc.Tags,
-		Metadata:    doc.Metadata,
-		IsPublic:    doc.IsPublic,
-		ViewsCount:  doc.ViewsCount,
-		CreatedAt:   doc.CreatedAt,
-		UpdatedAt:   doc.UpdatedAt,
+	snippet := &domain.Snippet{
+		ID:            doc.ID.Hex(),
+		UserID:        doc.UserID,
+		WorkspaceID:   doc.WorkspaceID,
+		Title:         doc.Title,
+		Description:   doc.Description,
+		Code:          doc.Code,
+		Language:      doc.Language,
+		Tags:          doc.Tags,
+		Dependencies:  doc.Dependencies,
+		Metadata:      doc.Metadata,
+		IsPublic:      doc.IsPublic,
+		IsHidden:      doc.IsHidden,
+		ProjectID:     doc.ProjectID,
+		SourceURL:     doc.SourceURL,
+		License:       doc.License,
+		ViewsCount:    doc.ViewsCount,
+		UniqueViewers: doc.UniqueViewers,
+		Trending:      doc.Trending,
+		MergedFrom:    doc.MergedFrom,
+		SimHash:       uint64(doc.SimHash),
+		CreatedAt:     doc.CreatedAt,
+		UpdatedAt:     doc.UpdatedAt,
+	}
+	if doc.Playground != nil && doc.Playground.CodeHash == playgroundCodeHash(doc.Language, doc.Code) {
+		snippet.PlaygroundURL = doc.Playground.URL
+	}
+	if len(doc.Files) == 0 {
+		// Snippets not yet migrated to files read as a single file
+		snippet.SetFiles(domain.SingleSnippetFile(doc.Title, doc.Code, doc.Language))
+		return snippet
+	}
+	snippet.Files = make([]domain.SnippetFile, len(doc.Files))
+	for i, f := range doc.Files {
+		snippet.Files[i] = domain.SnippetFile{Name: f.Name, Language: f.Language, Code: f.Code, Stats: f.Stats, OriginalCode: f.Original}
+	}
+	if doc.Stats != nil && doc.Dependencies != nil {
+		snippet.Stats = *doc.Stats
+	} else {
+		// Snippets saved before stats or dependencies were tracked get them computed on read
+		snippet.SetFiles(snippet.Files)
+	}
+	return snippet
+}
+
+// migrateFiles moves the code of snippets saved before multi-file snippets into a single file
+func (r *SnippetRepository) migrateFiles(ctx context.Context) (int, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"files": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"title": 1, "code": 1, "prog_lang": 1}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find unmigrated snippets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var doc snippetDoc
+		if err := cursor.Decode(&doc); err != nil {
+			return migrated, fmt.Errorf("failed to decode snippet: %w", err)
+		}
+		snippet := &domain.Snippet{}
+		snippet.SetFiles(domain.SingleSnippetFile(doc.Title, doc.Code, doc.Language))
+		_, err := r.collection.UpdateOne(ctx,
+			bson.M{"_id": doc.ID, "files": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"files": toFileDocs(snippet.Files), "stats": snippet.Stats}},
+		)
+		if err != nil {
+			return migrated, fmt.Errorf("failed to migrate snippet %s: %w", doc.ID.Hex(), err)
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
+
+// migrateDependencies stores the dependencies of snippets saved before they were extracted
+func (r *SnippetRepository) migrateDependencies(ctx context.Context) (int, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"dependencies": bson.M{"$exists": false}, "files": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"files": 1}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find snippets without dependencies: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var doc snippetDoc
+		if err := cursor.Decode(&doc); err != nil {
+			return migrated, fmt.Errorf("failed to decode snippet: %w", err)
+		}
+		snippet := fromDoc(&doc)
+		_, err := r.collection.UpdateOne(ctx,
+			bson.M{"_id": doc.ID, "dependencies": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"dependencies": snippet.Dependencies}},
+		)
+		if err != nil {
+			return migrated, fmt.Errorf("failed to store dependencies of snippet %s: %w", doc.ID.Hex(), err)
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
+
+// migrateSimHashes stores the simhashes of snippets saved before near-duplicates were detected
+func (r *SnippetRepository) migrateSimHashes(ctx context.Context) (int, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"simhash": bson.M{"$exists": false}, "files": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"files": 1}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find snippets without simhashes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var doc snippetDoc
+		if err := cursor.Decode(&doc); err != nil {
+			return migrated, fmt.Errorf("failed to decode snippet: %w", err)
+		}
+		snippet := fromDoc(&doc)
+		_, err := r.collection.UpdateOne(ctx,
+			bson.M{"_id": doc.ID, "simhash": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"simhash": int64(domain.SnippetSimHash(snippet.Files))}},
+		)
+		if err != nil {
+			return migrated, fmt.Errorf("failed to store simhash of snippet %s: %w", doc.ID.Hex(), err)
+		}
+		migrated++
 	}
+	return migrated, cursor.Err()
 }
 
 // Create inserts a new snippet
 func (r *SnippetRepository) Create(ctx context.Context, snippet *domain.Snippet) error {
 	snippet.CreatedAt = time.Now().UTC()
 	snippet.UpdatedAt = snippet.CreatedAt
+	if snippet.WorkspaceID == "" {
+		snippet.WorkspaceID = tenant.WorkspaceIDString(ctx, snippet.UserID)
+	}
 
 	doc := toDoc(snippet)
 	doc.ID = primitive.NewObjectID()
@@ -134,6 +391,13 @@ func (r *SnippetRepository) FindByID(ctx context.Context, id string) (*domain.Sn
 		return nil, nil // Invalid ID format
 	}
 	filter := bson.M{"_id": oid}
+	// Within a workspace, only its own snippets and public snippets are visible
+	if workspaceID, ok := tenant.FromContext(ctx); ok {
+		filter["$or"] = bson.A{
+			bson.M{"workspace_id": workspaceID.String()},
+			bson.M{"is_public": true},
+		}
+	}
 
 	var doc snippetDoc
 	err = r.collection.FindOne(ctx, filter).Decode(&doc)
@@ -148,13 +412,13 @@ func (r *SnippetRepository) FindByID(ctx context.Context, id string) (*domain.Sn
 
 // FindByUserID retrieves all snippets for a user with pagination
 func (r *SnippetRepository) FindByUserID(ctx context.Context, userID string, limit, offset int64) ([]domain.Snippet, error) {
-	filter := bson.M{"user_id": userID}
+	filter := bson.M{"user_id": userID, "workspace_id": tenant.WorkspaceIDString(ctx, userID)}
 	opts := options.Find().
 		SetSort(bson.D{{Key: "created_at", Value: -1}}).
 		SetLimit(limit).
 		SetSkip(offset)
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	cursor, err := r.reads.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find snippets: %w", err)
 	}
@@ -175,15 +439,16 @@ func (r *SnippetRepository) FindByUserID(ctx context.Context, userID string, lim
 // FindByTags retrieves snippets matching any of the given tags
 func (r *SnippetRepository) FindByTags(ctx context.Context, userID string, tags []string, limit, offset int64) ([]domain.Snippet, error) {
 	filter := bson.M{
-		"user_id": userID,
-		"tags":    bson.M{"$in": tags},
+		"user_id":      userID,
+		"workspace_id": tenant.WorkspaceIDString(ctx, userID),
+		"tags":         bson.M{"$in": tags},
 	}
 	opts := options.Find().
 		SetSort(bson.D{{Key: "created_at", Value: -1}}).
 		SetLimit(limit).
 		SetSkip(offset)
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	cursor, err := r.reads.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find snippets by tags: %w", err)
 	}
@@ -204,15 +469,16 @@ func (r *SnippetRepository) FindByTags(ctx context.Context, userID string, tags
 // FindByLanguage retrieves snippets by programming language
 func (r *SnippetRepository) FindByLanguage(ctx context.Context, userID, language string, limit, offset int64) ([]domain.Snippet, error) {
 	filter := bson.M{
-		"user_id":   userID,
-		"prog_lang": language,
+		"user_id":         userID,
+		"workspace_id":    tenant.WorkspaceIDString(ctx, userID),
+		"files.prog_lang": language,
 	}
 	opts := options.Find().
 		SetSort(bson.D{{Key: "created_at", Value: -1}}).
 		SetLimit(limit).
 		SetSkip(offset)
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	cursor, err := r.reads.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find snippets by language: %w", err)
 	}
@@ -233,15 +499,16 @@ func (r *SnippetRepository) FindByLanguage(ctx context.Context, userID, language
 // Search performs full-text search on snippets
 func (r *SnippetRepository) Search(ctx context.Context, userID, query string, limit, offset int64) ([]domain.Snippet, error) {
 	filter := bson.M{
-		"user_id": userID,
-		"$text":   bson.M{"$search": query},
+		"user_id":      userID,
+		"workspace_id": tenant.WorkspaceIDString(ctx, userID),
+		"$text":        bson.M{"$search": query},
 	}
 	opts := options.Find().
 		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}}).
 		SetLimit(limit).
 		SetSkip(offset)
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	cursor, err := r.reads.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search snippets: %w", err)
 	}
@@ -259,25 +526,143 @@ func (r *SnippetRepository) Search(ctx context.Context, userID, query string, li
 	return snippets, nil
 }
 
+// buildFilter translates a SnippetFilter into a single MongoDB query for a user's snippets
+func buildFilter(ctx context.Context, userID string, f *domain.SnippetFilter) bson.M {
+	filter := bson.M{"user_id": userID, "workspace_id": tenant.WorkspaceIDString(ctx, userID)}
+	if f == nil {
+		return filter
+	}
+
+	if f.Search != "" {
+		filter["$text"] = bson.M{"$search": f.Search}
+	}
+	if len(f.Tags) > 0 {
+		filter["tags"] = bson.M{"$in": f.Tags}
+	}
+	if f.Language != "" {
+		filter["files.prog_lang"] = f.Language
+	}
+	if f.Dependency != "" {
+		filter["dependencies"] = f.Dependency
+	}
+	switch f.Visibility {
+	case domain.SnippetVisibilityPublic:
+		filter["is_public"] = true
+	case domain.SnippetVisibilityPrivate:
+		filter["is_public"] = false
+	}
+	if f.From != nil || f.To != nil {
+		createdAt := bson.M{}
+		if f.From != nil {
+			createdAt["$gte"] = *f.From
+		}
+		if f.To != nil {
+			createdAt["$lt"] = *f.To
+		}
+		filter["created_at"] = createdAt
+	}
+
+	return filter
+}
+
+// FindFiltered retrieves a user's snippets matching every criterion in the filter
+func (r *SnippetRepository) FindFiltered(ctx context.Context, userID string, f *domain.SnippetFilter, limit, offset int64) ([]domain.Snippet, error) {
+	sort := bson.D{{Key: "created_at", Value: -1}}
+	if f != nil && f.Search != "" {
+		sort = bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}}
+	}
+	opts := options.Find().
+		SetSort(sort).
+		SetLimit(limit).
+		SetSkip(offset)
+
+	cursor, err := r.reads.Find(ctx, buildFilter(ctx, userID, f), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find filtered snippets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []snippetDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode snippets: %w", err)
+	}
+
+	snippets := make([]domain.Snippet, len(docs))
+	for i, doc := range docs {
+		snippets[i] = *fromDoc(&doc)
+	}
+	return snippets, nil
+}
+
+// CountFiltered returns the number of a user's snippets matching the filter
+func (r *SnippetRepository) CountFiltered(ctx context.Context, userID string, f *domain.SnippetFilter) (int64, error) {
+	count, err := r.reads.CountDocuments(ctx, buildFilter(ctx, userID, f))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count filtered snippets: %w", err)
+	}
+	return count, nil
+}
+
+// SampleExcluding picks a random snippet created before the cutoff whose ID is not in excludeIDs
+func (r *SnippetRepository) SampleExcluding(ctx context.Context, userID string, excludeIDs []string, before time.Time) (*domain.Snippet, error) {
+	exclude := make([]primitive.ObjectID, 0, len(excludeIDs))
+	for _, id := range excludeIDs {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			exclude = append(exclude, oid)
+		}
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"user_id":      userID,
+			"workspace_id": tenant.WorkspaceIDString(ctx, userID),
+			"created_at":   bson.M{"$lt": before},
+			"_id":          bson.M{"$nin": exclude},
+		}},
+		{"$sample": bson.M{"size": 1}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample snippet: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []snippetDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode snippets: %w", err)
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	return fromDoc(&docs[0]), nil
+}
+
 // Update updates an existing snippet
 func (r *SnippetRepository) Update(ctx context.Context, snippet *domain.Snippet) error {
 	oid, err := primitive.ObjectIDFromHex(snippet.ID)
 	if err != nil {
-		return fmt.Errorf("invalid snippet ID: %w", err)
+		return apperr.New(apperr.ErrValidation, "invalid snippet ID")
 	}
 
 	snippet.UpdatedAt = time.Now().UTC()
 
-	filter := bson.M{"_id": oid, "user_id": snippet.UserID}
+	filter := bson.M{"_id": oid, "user_id": snippet.UserID, "workspace_id": tenant.WorkspaceIDString(ctx, snippet.UserID)}
 	update := bson.M{"$set": bson.M{
-		"title":       snippet.Title,
-		"description": snippet.Description,
-		"code":        snippet.Code,
-		"prog_lang":   snippet.Language,
-		"tags":        snippet.Tags,
-		"metadata":    snippet.Metadata,
-		"is_public":   snippet.IsPublic,
-		"updated_at":  snippet.UpdatedAt,
+		"title":        snippet.Title,
+		"description":  snippet.Description,
+		"code":         snippet.Code,
+		"prog_lang":    snippet.Language,
+		"files":        toFileDocs(snippet.Files),
+		"tags":         snippet.Tags,
+		"dependencies": snippet.Dependencies,
+		"metadata":     snippet.Metadata,
+		"is_public":    snippet.IsPublic,
+		"source_url":   snippet.SourceURL,
+		"license":      snippet.License,
+		"stats":        snippet.Stats,
+		"simhash":      int64(snippet.SimHash),
+		"updated_at":   snippet.UpdatedAt,
 	}}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
@@ -285,7 +670,7 @@ func (r *SnippetRepository) Update(ctx context.Context, snippet *domain.Snippet)
 		return fmt.Errorf("failed to update snippet: %w", err)
 	}
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("snippet not found or unauthorized")
+		return apperr.New(apperr.ErrNotFound, "snippet not found or unauthorized")
 	}
 	return nil
 }
@@ -294,31 +679,64 @@ func (r *SnippetRepository) Update(ctx context.Context, snippet *domain.Snippet)
 func (r *SnippetRepository) Delete(ctx context.Context, id, userID string) error {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("invalid snippet ID: %w", err)
+		return apperr.New(apperr.ErrValidation, "invalid snippet ID")
 	}
 
-	filter := bson.M{"_id": oid, "user_id": userID}
+	filter := bson.M{"_id": oid, "user_id": userID, "workspace_id": tenant.WorkspaceIDString(ctx, userID)}
 	result, err := r.collection.DeleteOne(ctx, filter)
 	if err != nil {
 		return fmt.Errorf("failed to delete snippet: %w", err)
 	}
 	if result.DeletedCount == 0 {
-		return fmt.Errorf("snippet not found or unauthorized")
+		return apperr.New(apperr.ErrNotFound, "snippet not found or unauthorized")
 	}
 	return nil
 }
 
-// IncrementViews increments the view count for a snippet
-func (r *SnippetRepository) IncrementViews(ctx context.Context, id string) error {
+// RecordView registers a view of a snippet by a viewer. Repeat views by the same viewer
+// within window are ignored; counted reports whether this view should be counted and
+// firstView whether this is the viewer's first ever view of the snippet.
+func (r *SnippetRepository) RecordView(ctx context.Context, snippetID, viewerID string, window time.Duration) (counted, firstView bool, err error) {
+	now := time.Now().UTC()
+
+	// A returning viewer counts again once their last counted view is outside the window
+	result, err := r.views.UpdateOne(ctx,
+		bson.M{"snippet_id": snippetID, "viewer_id": viewerID, "viewed_at": bson.M{"$lt": now.Add(-window)}},
+		bson.M{"$set": bson.M{"viewed_at": now}},
+	)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to record snippet view: %w", err)
+	}
+	if result.ModifiedCount > 0 {
+		return true, false, nil
+	}
+
+	_, err = r.views.InsertOne(ctx, bson.M{"snippet_id": snippetID, "viewer_id": viewerID, "viewed_at": now})
+	if mongo.IsDuplicateKeyError(err) {
+		// Already viewed within the window
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed to record snippet view: %w", err)
+	}
+	return true, true, nil
+}
+
+// IncrementViews increments the view count for a snippet, and the unique viewer count for first-time viewers
+func (r *SnippetRepository) IncrementViews(ctx context.Context, id string, firstView bool) error {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("invalid snippet ID: %w", err)
+		return apperr.New(apperr.ErrValidation, "invalid snippet ID")
 	}
 
 	filter := bson.M{"_id": oid}
-	update := bson.M{"$inc": bson.M{"views_count": 1}}
+	// recent_views accumulates until the trending job folds it into trending_score
+	inc := bson.M{"views_count": 1, "recent_views": 1}
+	if firstView {
+		inc["unique_viewers"] = 1
+	}
 
-	_, err = r.collection.UpdateOne(ctx, filter, update)
+	_, err = r.collection.UpdateOne(ctx, filter, bson.M{"$inc": inc})
 	if err != nil {
 		return fmt.Errorf("failed to increment views: %w", err)
 	}
@@ -327,7 +745,7 @@ func (r *SnippetRepository) IncrementViews(ctx context.Context, id string) error
 
 // Count returns the total number of snippets for a user
 func (r *SnippetRepository) Count(ctx context.Context, userID string) (int64, error) {
-	filter := bson.M{"user_id": userID}
+	filter := bson.M{"user_id": userID, "workspace_id": tenant.WorkspaceIDString(ctx, userID)}
 	count, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count snippets: %w", err)
@@ -335,17 +753,26 @@ func (r *SnippetRepository) Count(ctx context.Context, userID string) (int64, er
 	return count, nil
 }
 
+// CountAllWorkspaces returns the number of snippets a user owns across every workspace
+func (r *SnippetRepository) CountAllWorkspaces(ctx context.Context, userID string) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count snippets: %w", err)
+	}
+	return count, nil
+}
+
 // GetLanguageStats returns snippet counts grouped by language
 func (r *SnippetRepository) GetLanguageStats(ctx context.Context, userID string) (map[string]int64, error) {
 	pipeline := []bson.M{
-		{"$match": bson.M{"user_id": userID}},
+		{"$match": bson.M{"user_id": userID, "workspace_id": tenant.WorkspaceIDString(ctx, userID)}},
 		{"$group": bson.M{
 			"_id":   "$prog_lang",
 			"count": bson.M{"$sum": 1},
 		}},
 	}
 
-	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	cursor, err := r.reads.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get language stats: %w", err)
 	}
@@ -365,3 +792,537 @@ func (r *SnippetRepository) GetLanguageStats(ctx context.Context, userID string)
 
 	return stats, nil
 }
+
+// CountByLanguageBetween returns how many snippets a user created in each language between two
+// times, most snippets first
+func (r *SnippetRepository) CountByLanguageBetween(ctx context.Context, userID string, from, until time.Time) ([]domain.LanguageCount, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"user_id":      userID,
+			"workspace_id": tenant.WorkspaceIDString(ctx, userID),
+			"created_at":   bson.M{"$gte": from, "$lt": until},
+		}},
+		{"$group": bson.M{
+			"_id":   "$prog_lang",
+			"count": bson.M{"$sum": 1},
+		}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count snippets by language: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	counts := []domain.LanguageCount{}
+	for cursor.Next(ctx) {
+		var result struct {
+			ID    string `bson:"_id"`
+			Count int    `bson:"count"`
+		}
+		if err := cursor.Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode stats: %w", err)
+		}
+		counts = append(counts, domain.LanguageCount{Language: result.ID, Count: result.Count})
+	}
+
+	return counts, cursor.Err()
+}
+
+// GetDependencyStats returns the packages imported by the most of a user's snippets, most used first
+func (r *SnippetRepository) GetDependencyStats(ctx context.Context, userID string, limit int64) ([]domain.DependencyCount, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"user_id": userID, "workspace_id": tenant.WorkspaceIDString(ctx, userID)}},
+		{"$unwind": "$dependencies"},
+		{"$group": bson.M{
+			"_id":   "$dependencies",
+			"count": bson.M{"$sum": 1},
+		}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$limit": limit},
+	}
+
+	cursor, err := r.reads.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependency stats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	stats := []domain.DependencyCount{}
+	for cursor.Next(ctx) {
+		var result struct {
+			ID    string `bson:"_id"`
+			Count int64  `bson:"count"`
+		}
+		if err := cursor.Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode stats: %w", err)
+		}
+		stats = append(stats, domain.DependencyCount{Name: result.ID, Count: result.Count})
+	}
+
+	return stats, cursor.Err()
+}
+
+// GetLinesByLanguage aggregates lines of code per language per period (week or month) since the given time
+func (r *SnippetRepository) GetLinesByLanguage(ctx context.Context, userID string, since time.Time, groupBy string) ([]domain.LanguageLinesPeriod, error) {
+	format := "%Y-%m"
+	if groupBy == "week" {
+		format = "%G-W%V"
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"user_id": userID, "workspace_id": tenant.WorkspaceIDString(ctx, userID), "created_at": bson.M{"$gte": since}}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"period":   bson.M{"$dateToString": bson.M{"format": format, "date": "$created_at"}},
+				"language": "$prog_lang",
+			},
+			"snippets": bson.M{"$sum": 1},
+			// Older snippets have no stored stats; fall back to counting newline-separated lines
+			"lines": bson.M{"$sum": bson.M{"$ifNull": bson.A{
+				"$stats.lines",
+				bson.M{"$size": bson.M{"$split": bson.A{"$code", "\n"}}},
+			}}},
+		}},
+		{"$project": bson.M{
+			"_id":      0,
+			"period":   "$_id.period",
+			"language": "$_id.language",
+			"snippets": 1,
+			"lines":    1,
+		}},
+		{"$sort": bson.D{{Key: "period", Value: 1}, {Key: "language", Value: 1}}},
+	}
+
+	cursor, err := r.reads.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate lines by language: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var periods []domain.LanguageLinesPeriod
+	if err := cursor.All(ctx, &periods); err != nil {
+		return nil, fmt.Errorf("failed to decode lines by language: %w", err)
+	}
+	return periods, nil
+}
+
+// GetLanguageStatsOverTime returns snippet counts per language bucketed by created_at
+// (interval is day, week, month, or year), oldest bucket first
+func (r *SnippetRepository) GetLanguageStatsOverTime(ctx context.Context, userID, interval string, since time.Time) ([]domain.LanguageStatsBucket, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"user_id": userID, "workspace_id": tenant.WorkspaceIDString(ctx, userID), "created_at": bson.M{"$gte": since}}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"period":   bson.M{"$dateTrunc": bson.M{"date": "$created_at", "unit": interval, "startOfWeek": "monday"}},
+				"language": "$prog_lang",
+			},
+			"count": bson.M{"$sum": 1},
+		}},
+		{"$sort": bson.D{{Key: "_id.period", Value: 1}}},
+	}
+
+	cursor, err := r.reads.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get language stats over time: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var buckets []domain.LanguageStatsBucket
+	for cursor.Next(ctx) {
+		var result struct {
+			ID struct {
+				Period   time.Time `bson:"period"`
+				Language string    `bson:"language"`
+			} `bson:"_id"`
+			Count int64 `bson:"count"`
+		}
+		if err := cursor.Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode stats: %w", err)
+		}
+
+		// Results are sorted by period, so a new period always starts a new bucket
+		if n := len(buckets); n == 0 || !buckets[n-1].PeriodStart.Equal(result.ID.Period) {
+			buckets = append(buckets, domain.LanguageStatsBucket{
+				PeriodStart: result.ID.Period.UTC(),
+				Counts:      make(map[string]int64),
+			})
+		}
+		buckets[len(buckets)-1].Counts[result.ID.Language] = result.Count
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating language stats: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// minTrendingScore is the score below which a snippet drops out of trending
+const minTrendingScore = 0.01
+
+// RefreshTrendingScores decays every trending score by the given factor and folds in views
+// recorded since the last refresh, resetting the recent view counters
+func (r *SnippetRepository) RefreshTrendingScores(ctx context.Context, decay float64) (int64, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"recent_views": bson.M{"$gt": 0}},
+		bson.M{"trending_score": bson.M{"$gt": 0}},
+	}}
+
+	score := bson.M{"$add": bson.A{
+		bson.M{"$multiply": bson.A{bson.M{"$ifNull": bson.A{"$trending_score", 0}}, decay}},
+		bson.M{"$ifNull": bson.A{"$recent_views", 0}},
+	}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"trending_score": score, "recent_views": 0}}},
+		{{Key: "$set", Value: bson.M{"trending_score": bson.M{"$cond": bson.A{
+			bson.M{"$lt": bson.A{"$trending_score", minTrendingScore}}, 0, "$trending_score",
+		}}}}},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to refresh trending scores: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// FindTrending retrieves public snippets ordered by trending score
+func (r *SnippetRepository) FindTrending(ctx context.Context, limit, offset int64) ([]domain.Snippet, error) {
+	filter := bson.M{
+		"is_public":      true,
+		"is_hidden":      bson.M{"$ne": true},
+		"trending_score": bson.M{"$gt": 0},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "trending_score", Value: -1}, {Key: "views_count", Value: -1}}).
+		SetLimit(limit).
+		SetSkip(offset)
+
+	cursor, err := r.reads.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trending snippets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []snippetDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode snippets: %w", err)
+	}
+
+	snippets := make([]domain.Snippet, len(docs))
+	for i, doc := range docs {
+		snippets[i] = *fromDoc(&doc)
+	}
+	return snippets, nil
+}
+
+// SetPlayground caches the playground link for a snippet's language and code
+func (r *SnippetRepository) SetPlayground(ctx context.Context, id, language, code, link string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperr.New(apperr.ErrValidation, "invalid snippet ID")
+	}
+
+	playground := playgroundDoc{URL: link, CodeHash: playgroundCodeHash(language, code)}
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"playground": playground}}); err != nil {
+		return fmt.Errorf("failed to cache playground link: %w", err)
+	}
+	return nil
+}
+
+// Merge saves a snippet another was merged into and deletes the other, whose ID then redirects
+// to the snippet, as do IDs that redirected to the other
+func (r *SnippetRepository) Merge(ctx context.Context, snippet *domain.Snippet, sourceID string) error {
+	oid, err := primitive.ObjectIDFromHex(snippet.ID)
+	if err != nil {
+		return apperr.New(apperr.ErrValidation, "invalid snippet ID")
+	}
+	sourceOID, err := primitive.ObjectIDFromHex(sourceID)
+	if err != nil {
+		return apperr.New(apperr.ErrValidation, "invalid snippet ID")
+	}
+
+	if err := r.Update(ctx, snippet); err != nil {
+		return err
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{
+		"views_count":    snippet.ViewsCount,
+		"unique_viewers": snippet.UniqueViewers,
+		"merged_from":    snippet.MergedFrom,
+		"created_at":     snippet.CreatedAt,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to save merged snippet: %w", err)
+	}
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": sourceOID, "user_id": snippet.UserID}); err != nil {
+		return fmt.Errorf("failed to delete merged snippet: %w", err)
+	}
+	_, err = r.redirects.UpdateOne(ctx,
+		bson.M{"_id": sourceID},
+		bson.M{"$set": bson.M{"target_id": snippet.ID, "created_at": time.Now().UTC()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to redirect merged snippet: %w", err)
+	}
+	if _, err := r.redirects.UpdateMany(ctx, bson.M{"target_id": sourceID}, bson.M{"$set": bson.M{"target_id": snippet.ID}}); err != nil {
+		return fmt.Errorf("failed to redirect merged snippet: %w", err)
+	}
+	return nil
+}
+
+// FindRedirect returns the ID of the snippet a merged snippet's ID redirects to, or "" if none
+func (r *SnippetRepository) FindRedirect(ctx context.Context, id string) (string, error) {
+	var redirect struct {
+		TargetID string `bson:"target_id"`
+	}
+	err := r.redirects.FindOne(ctx, bson.M{"_id": id}).Decode(&redirect)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find snippet redirect: %w", err)
+	}
+	return redirect.TargetID, nil
+}
+
+// FindSimilar returns up to limit of a user's snippets in the current workspace whose simhashes
+// differ from simhash by at most maxDistance bits, most similar first. Hamming distance cannot be
+// indexed, so the user's simhashes are compared here rather than in MongoDB.
+func (r *SnippetRepository) FindSimilar(ctx context.Context, userID string, simhash uint64, maxDistance, limit int) ([]domain.SimilarSnippet, error) {
+	filter := bson.M{
+		"user_id":      userID,
+		"workspace_id": tenant.WorkspaceIDString(ctx, userID),
+		"simhash":      bson.M{"$nin": bson.A{0, nil}},
+	}
+	cursor, err := r.collection.Find(ctx, filter,
+		options.Find().SetProjection(bson.M{"title": 1, "prog_lang": 1, "simhash": 1, "created_at": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snippets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	similar := []domain.SimilarSnippet{}
+	for cursor.Next(ctx) {
+		var doc snippetDoc
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode snippet: %w", err)
+		}
+		if domain.SimHashDistance(simhash, uint64(doc.SimHash)) > maxDistance {
+			continue
+		}
+		similar = append(similar, domain.SimilarSnippet{
+			ID:         doc.ID.Hex(),
+			Title:      doc.Title,
+			Language:   doc.Language,
+			Similarity: domain.SimHashSimilarity(simhash, uint64(doc.SimHash)),
+			CreatedAt:  doc.CreatedAt,
+		})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read snippets: %w", err)
+	}
+
+	slices.SortFunc(similar, func(a, b domain.SimilarSnippet) int {
+		if a.Similarity != b.Similarity {
+			return cmp.Compare(b.Similarity, a.Similarity)
+		}
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar, nil
+}
+
+// SetHidden hides or unhides a snippet from everyone but its owner
+func (r *SnippetRepository) SetHidden(ctx context.Context, id string, hidden bool) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperr.New(apperr.ErrValidation, "invalid snippet ID")
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"is_hidden": hidden}})
+	if err != nil {
+		return fmt.Errorf("failed to set snippet visibility: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return apperr.New(apperr.ErrNotFound, "snippet not found")
+	}
+	return nil
+}
+
+// FindPublicByUsers retrieves public snippets by any of the given users created before a time, newest first
+func (r *SnippetRepository) FindPublicByUsers(ctx context.Context, userIDs []string, before time.Time, limit int64) ([]domain.Snippet, error) {
+	filter := bson.M{
+		"user_id":    bson.M{"$in": userIDs},
+		"is_public":  true,
+		"is_hidden":  bson.M{"$ne": true},
+		"created_at": bson.M{"$lt": before},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := r.reads.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find public snippets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []snippetDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode snippets: %w", err)
+	}
+
+	snippets := make([]domain.Snippet, len(docs))
+	for i, doc := range docs {
+		snippets[i] = *fromDoc(&doc)
+	}
+	return snippets, nil
+}
+
+// FindByEntry retrieves the user's snippets extracted from a journal entry, in any workspace
+func (r *SnippetRepository) FindByEntry(ctx context.Context, userID, entryID string) ([]domain.Snippet, error) {
+	filter := bson.M{"user_id": userID, "metadata.entry.entryId": entryID}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entry snippets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []snippetDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode snippets: %w", err)
+	}
+
+	snippets := make([]domain.Snippet, len(docs))
+	for i, doc := range docs {
+		snippets[i] = *fromDoc(&doc)
+	}
+	return snippets, nil
+}
+
+// FindByIDs retrieves the user's snippets with the given IDs, in any workspace. Unknown IDs are skipped.
+func (r *SnippetRepository) FindByIDs(ctx context.Context, userID string, ids []string) ([]domain.Snippet, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	if len(oids) == 0 {
+		return []domain.Snippet{}, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": oids}, "user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snippets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []snippetDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode snippets: %w", err)
+	}
+
+	snippets := make([]domain.Snippet, len(docs))
+	for i, doc := range docs {
+		snippets[i] = *fromDoc(&doc)
+	}
+	return snippets, nil
+}
+
+// SetProject attaches one of the user's snippets to a project, or detaches it when projectID is
+// empty. It reports whether the snippet was found.
+func (r *SnippetRepository) SetProject(ctx context.Context, id, userID, projectID string) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, nil
+	}
+	update := bson.M{"$set": bson.M{"project_id": projectID}}
+	if projectID == "" {
+		update = bson.M{"$unset": bson.M{"project_id": ""}}
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid, "user_id": userID}, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to set snippet project: %w", err)
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// ClearProject detaches every snippet from a deleted project
+func (r *SnippetRepository) ClearProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"project_id": projectID}, bson.M{"$unset": bson.M{"project_id": ""}})
+	if err != nil {
+		return fmt.Errorf("failed to detach project snippets: %w", err)
+	}
+	return nil
+}
+
+// FindByProject retrieves a project's snippets created before a time, newest first
+func (r *SnippetRepository) FindByProject(ctx context.Context, projectID string, before time.Time, limit int64) ([]domain.Snippet, error) {
+	filter := bson.M{"project_id": projectID, "created_at": bson.M{"$lt": before}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := r.reads.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project snippets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []snippetDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode snippets: %w", err)
+	}
+
+	snippets := make([]domain.Snippet, len(docs))
+	for i, doc := range docs {
+		snippets[i] = *fromDoc(&doc)
+	}
+	return snippets, nil
+}
+
+// CountByProject counts a project's snippets
+func (r *SnippetRepository) CountByProject(ctx context.Context, projectID string) (int64, error) {
+	count, err := r.reads.CountDocuments(ctx, bson.M{"project_id": projectID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count project snippets: %w", err)
+	}
+	return count, nil
+}
+
+// FindPublicForSitemap retrieves the most recently updated public snippets with only their ID,
+// title, owner, and timestamps
+func (r *SnippetRepository) FindPublicForSitemap(ctx context.Context, limit int64) ([]domain.Snippet, error) {
+	filter := bson.M{
+		"is_public": true,
+		"is_hidden": bson.M{"$ne": true},
+	}
+	opts := options.Find().
+		SetProjection(bson.M{"title": 1, "user_id": 1, "created_at": 1, "updated_at": 1}).
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := r.reads.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find public snippets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []snippetDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode snippets: %w", err)
+	}
+
+	snippets := make([]domain.Snippet, len(docs))
+	for i, doc := range docs {
+		snippets[i] = *fromDoc(&doc)
+	}
+	return snippets, nil
+}
@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AnnouncementRepository handles system announcements and their recipients with raw SQL
+type AnnouncementRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAnnouncementRepository creates a new announcement repository
+func NewAnnouncementRepository(pool *pgxpool.Pool) *AnnouncementRepository {
+	return &AnnouncementRepository{pool: pool}
+}
+
+const announcementColumns = `a.id, a.title, a.body, a.audience, a.inactive_days, a.send_email,
+	a.publish_at, a.published_at, a.recipients, a.created_by, a.created_at`
+
+// scanAnnouncement scans announcementColumns, then any extra destinations
+func scanAnnouncement(row pgx.Row, a *domain.Announcement, extra ...any) error {
+	return row.Scan(append([]any{&a.ID, &a.Title, &a.Body, &a.Audience, &a.InactiveDays, &a.SendEmail,
+		&a.PublishAt, &a.PublishedAt, &a.Recipients, &a.CreatedBy, &a.CreatedAt}, extra...)...)
+}
+
+// Create saves a new announcement
+func (r *AnnouncementRepository) Create(ctx context.Context, a *domain.Announcement) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO announcements (id, title, body, audience, inactive_days, send_email, publish_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, a.ID, a.Title, a.Body, a.Audience, a.InactiveDays, a.SendEmail, a.PublishAt, a.CreatedBy, a.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create announcement: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves an announcement, or nil if it doesn't exist
+func (r *AnnouncementRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Announcement, error) {
+	var a domain.Announcement
+	err := scanAnnouncement(r.pool.QueryRow(ctx, `SELECT `+announcementColumns+` FROM announcements a WHERE a.id = $1`, id), &a)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find announcement: %w", err)
+	}
+	return &a, nil
+}
+
+// List retrieves all announcements, latest scheduled first, with the total count
+func (r *AnnouncementRepository) List(ctx context.Context, limit, offset int) ([]domain.Announcement, int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+announcementColumns+`
+		FROM announcements a
+		ORDER BY a.publish_at DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list announcements: %w", err)
+	}
+	defer rows.Close()
+
+	announcements := []domain.Announcement{}
+	for rows.Next() {
+		var a domain.Announcement
+		if err := scanAnnouncement(rows, &a); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan announcement: %w", err)
+		}
+		announcements = append(announcements, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating announcements: %w", err)
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM announcements`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count announcements: %w", err)
+	}
+	return announcements, total, nil
+}
+
+// DeleteScheduled deletes an announcement that hasn't been published yet. It returns false if
+// there is no such announcement.
+func (r *AnnouncementRepository) DeleteScheduled(ctx context.Context, id uuid.UUID) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM announcements WHERE id = $1 AND published_at IS NULL`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete announcement: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// ListDue returns the announcements scheduled for now or earlier that aren't published yet,
+// earliest first
+func (r *AnnouncementRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Announcement, error) {
+	return r.listPending(ctx, `a.published_at IS NULL AND a.publish_at <= $1`, now, limit)
+}
+
+// ListUnemailed returns the published announcements to be emailed that haven't been yet,
+// earliest first
+func (r *AnnouncementRepository) ListUnemailed(ctx context.Context, now time.Time, limit int) ([]domain.Announcement, error) {
+	return r.listPending(ctx, `a.published_at <= $1 AND a.send_email AND a.emailed_at IS NULL`, now, limit)
+}
+
+func (r *AnnouncementRepository) listPending(ctx context.Context, where string, now time.Time, limit int) ([]domain.Announcement, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+announcementColumns+`
+		FROM announcements a
+		WHERE `+where+`
+		ORDER BY a.publish_at ASC
+		LIMIT $2
+	`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending announcements: %w", err)
+	}
+	defer rows.Close()
+
+	var announcements []domain.Announcement
+	for rows.Next() {
+		var a domain.Announcement
+		if err := scanAnnouncement(rows, &a); err != nil {
+			return nil, fmt.Errorf("failed to scan announcement: %w", err)
+		}
+		announcements = append(announcements, a)
+	}
+	return announcements, rows.Err()
+}
+
+// Publish marks an announcement published and delivers it to the users in its audience as of
+// now. It returns how many users that is, and false if the announcement was already published.
+func (r *AnnouncementRepository) Publish(ctx context.Context, a *domain.Announcement, at time.Time) (int, bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `UPDATE announcements SET published_at = $2 WHERE id = $1 AND published_at IS NULL`, a.ID, at)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to publish announcement: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return 0, false, nil
+	}
+
+	audience := `SELECT id FROM users`
+	args := []any{a.ID}
+	switch a.Audience {
+	case domain.AnnouncementAudienceGroupOwners:
+		audience = `SELECT DISTINCT created_by FROM study_groups WHERE archived_at IS NULL`
+	case domain.AnnouncementAudienceInactive:
+		// Users who signed up before the period and weren't active during it
+		audience = `
+			SELECT u.id FROM users u
+			WHERE u.created_at < $2 AND NOT EXISTS (
+				SELECT 1 FROM learning_progress p
+				WHERE p.user_id = u.id AND p.date >= $2::date
+					AND (p.entries_count > 0 OR p.snippets_count > 0 OR p.tils_count > 0 OR p.problems_count > 0)
+			)`
+		args = append(args, at.AddDate(0, 0, -a.InactiveDays))
+	}
+	result, err = tx.Exec(ctx, `
+		INSERT INTO announcement_recipients (announcement_id, user_id)
+		SELECT $1, audience.id FROM (`+audience+`) AS audience(id)
+		ON CONFLICT DO NOTHING
+	`, args...)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to deliver announcement: %w", err)
+	}
+	recipients := int(result.RowsAffected())
+
+	if _, err := tx.Exec(ctx, `UPDATE announcements SET recipients = $2 WHERE id = $1`, a.ID, recipients); err != nil {
+		return 0, false, fmt.Errorf("failed to count announcement recipients: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, false, fmt.Errorf("failed to commit announcement: %w", err)
+	}
+	return recipients, true, nil
+}
+
+// MarkEmailed claims a published announcement for emailing. It returns false if it was
+// already claimed.
+func (r *AnnouncementRepository) MarkEmailed(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	result, err := r.pool.Exec(ctx, `UPDATE announcements SET emailed_at = $2 WHERE id = $1 AND emailed_at IS NULL`, id, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark announcement emailed: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// ListRecipients returns a page of an announcement's recipients ordered by user ID, starting
+// after the given one (uuid.Nil for the first page)
+func (r *AnnouncementRepository) ListRecipients(ctx context.Context, id, after uuid.UUID, limit int) ([]domain.AnnouncementRecipient, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT u.id, u.email
+		FROM announcement_recipients ar
+		JOIN users u ON u.id = ar.user_id
+		WHERE ar.announcement_id = $1 AND ar.user_id > $2
+		ORDER BY ar.user_id
+		LIMIT $3
+	`, id, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list announcement recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var recipients []domain.AnnouncementRecipient
+	for rows.Next() {
+		var recipient domain.AnnouncementRecipient
+		if err := rows.Scan(&recipient.UserID, &recipient.Email); err != nil {
+			return nil, fmt.Errorf("failed to scan announcement recipient: %w", err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, rows.Err()
+}
+
+// ListByUser retrieves the announcements delivered to a user, newest first, with the total count
+func (r *AnnouncementRepository) ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]domain.Announcement, int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+announcementColumns+`, ar.read_at
+		FROM announcement_recipients ar
+		JOIN announcements a ON a.id = ar.announcement_id
+		WHERE ar.user_id = $1 AND (NOT $2 OR ar.read_at IS NULL)
+		ORDER BY a.published_at DESC
+		LIMIT $3 OFFSET $4
+	`, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list announcements: %w", err)
+	}
+	defer rows.Close()
+
+	announcements := []domain.Announcement{}
+	for rows.Next() {
+		var a domain.Announcement
+		if err := scanAnnouncement(rows, &a, &a.ReadAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan announcement: %w", err)
+		}
+		announcements = append(announcements, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating announcements: %w", err)
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM announcement_recipients WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)`
+	if err := r.pool.QueryRow(ctx, countQuery, userID, unreadOnly).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count announcements: %w", err)
+	}
+	return announcements, total, nil
+}
+
+// CountUnread counts the announcements a user hasn't read
+func (r *AnnouncementRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM announcement_recipients WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread announcements: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks a user's announcements as read: those in ids, or all of them when ids is
+// empty. It returns how many were unread.
+func (r *AnnouncementRepository) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int, error) {
+	query := `
+		UPDATE announcement_recipients SET read_at = $3
+		WHERE user_id = $1 AND read_at IS NULL AND (cardinality($2::uuid[]) = 0 OR announcement_id = ANY($2))
+	`
+	if ids == nil {
+		ids = []uuid.UUID{}
+	}
+	result, err := r.pool.Exec(ctx, query, userID, ids, at)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark announcements read: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BookmarkRepository handles bookmarks with raw SQL
+type BookmarkRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewBookmarkRepository creates a new bookmark repository
+func NewBookmarkRepository(pool *pgxpool.Pool) *BookmarkRepository {
+	return &BookmarkRepository{pool: pool}
+}
+
+// Create saves a bookmark, reporting false if the user already bookmarked its URL
+func (r *BookmarkRepository) Create(ctx context.Context, b *domain.Bookmark) (bool, error) {
+	query := `
+		INSERT INTO bookmarks (id, user_id, url, title, notes, tags, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, url) DO NOTHING
+	`
+	result, err := r.pool.Exec(ctx, query, b.ID, b.UserID, b.URL, b.Title, b.Notes, b.Tags, b.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create bookmark: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// ListByUser retrieves a user's bookmarks, optionally with one tag, newest first
+func (r *BookmarkRepository) ListByUser(ctx context.Context, userID uuid.UUID, tag string, limit, offset int) ([]domain.Bookmark, int, error) {
+	query := `
+		SELECT id, user_id, url, title, notes, tags, created_at, COUNT(*) OVER()
+		FROM bookmarks
+		WHERE user_id = $1 AND ($2 = '' OR $2 = ANY(tags))
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.pool.Query(ctx, query, userID, tag, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	bookmarks := []domain.Bookmark{}
+	total := 0
+	for rows.Next() {
+		var b domain.Bookmark
+		if err := rows.Scan(&b.ID, &b.UserID, &b.URL, &b.Title, &b.Notes, &b.Tags, &b.CreatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan bookmark: %w", err)
+		}
+		bookmarks = append(bookmarks, b)
+	}
+	return bookmarks, total, rows.Err()
+}
+
+// Delete removes one of a user's bookmarks, reporting whether it existed
+func (r *BookmarkRepository) Delete(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM bookmarks WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete bookmark: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
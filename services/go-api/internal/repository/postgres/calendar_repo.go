@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CalendarFeedRepository handles calendar feed token persistence with raw SQL
+type CalendarFeedRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCalendarFeedRepository creates a new calendar feed repository
+func NewCalendarFeedRepository(pool *pgxpool.Pool) *CalendarFeedRepository {
+	return &CalendarFeedRepository{pool: pool}
+}
+
+func scanCalendarFeed(row pgx.Row) (*domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	err := row.Scan(&feed.UserID, &feed.TokenHash, &feed.CreatedAt, &feed.LastAccessedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find calendar feed: %w", err)
+	}
+	return &feed, nil
+}
+
+// Upsert creates a user's feed or replaces its token, invalidating the old URL
+func (r *CalendarFeedRepository) Upsert(ctx context.Context, feed *domain.CalendarFeed) error {
+	query := `
+		INSERT INTO calendar_feeds (user_id, token_hash, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			token_hash = EXCLUDED.token_hash,
+			created_at = EXCLUDED.created_at,
+			last_accessed_at = NULL
+	`
+	if _, err := r.pool.Exec(ctx, query, feed.UserID, feed.TokenHash, feed.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+	return nil
+}
+
+// FindByUserID retrieves a user's feed (nil if they have none)
+func (r *CalendarFeedRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error) {
+	query := `SELECT user_id, token_hash, created_at, last_accessed_at FROM calendar_feeds WHERE user_id = $1`
+	return scanCalendarFeed(r.pool.QueryRow(ctx, query, userID))
+}
+
+// FindByTokenHash retrieves the feed a token belongs to (nil if none)
+func (r *CalendarFeedRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	query := `SELECT user_id, token_hash, created_at, last_accessed_at FROM calendar_feeds WHERE token_hash = $1`
+	return scanCalendarFeed(r.pool.QueryRow(ctx, query, tokenHash))
+}
+
+// TouchAccessed records when a calendar app last fetched the feed
+func (r *CalendarFeedRepository) TouchAccessed(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	if _, err := r.pool.Exec(ctx, `UPDATE calendar_feeds SET last_accessed_at = $2 WHERE user_id = $1`, userID, at); err != nil {
+		return fmt.Errorf("failed to update calendar feed: %w", err)
+	}
+	return nil
+}
+
+// Delete revokes a user's feed. It reports whether there was one.
+func (r *CalendarFeedRepository) Delete(ctx context.Context, userID uuid.UUID) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM calendar_feeds WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
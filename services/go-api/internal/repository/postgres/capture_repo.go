@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CaptureRepository handles the quick-capture inbox with raw SQL
+type CaptureRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCaptureRepository creates a new capture repository
+func NewCaptureRepository(pool *pgxpool.Pool) *CaptureRepository {
+	return &CaptureRepository{pool: pool}
+}
+
+const captureColumns = `id, user_id, source, kind, title, content, language, url, tags, status, published_id, created_at, updated_at`
+
+func scanCapture(row pgx.Row) (*domain.Capture, error) {
+	var c domain.Capture
+	err := row.Scan(&c.ID, &c.UserID, &c.Source, &c.Kind, &c.Title, &c.Content, &c.Language, &c.URL, &c.Tags,
+		&c.Status, &c.PublishedID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Create adds a capture to a user's inbox
+func (r *CaptureRepository) Create(ctx context.Context, c *domain.Capture) error {
+	query := `
+		INSERT INTO captures (id, user_id, source, kind, title, content, language, url, tags, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	_, err := r.pool.Exec(ctx, query, c.ID, c.UserID, c.Source, c.Kind, c.Title, c.Content, c.Language, c.URL, c.Tags,
+		c.Status, c.CreatedAt, c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create capture: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves one of a user's captures, or nil if there is none
+func (r *CaptureRepository) FindByID(ctx context.Context, id, userID uuid.UUID) (*domain.Capture, error) {
+	query := `SELECT ` + captureColumns + ` FROM captures WHERE id = $1 AND user_id = $2`
+	c, err := scanCapture(r.pool.QueryRow(ctx, query, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find capture: %w", err)
+	}
+	return c, nil
+}
+
+// List retrieves a user's captures with a status, such as those in the inbox, newest first
+func (r *CaptureRepository) List(ctx context.Context, userID uuid.UUID, status string, limit, offset int) ([]domain.Capture, int, error) {
+	query := `
+		SELECT ` + captureColumns + `, COUNT(*) OVER()
+		FROM captures
+		WHERE user_id = $1 AND status = $2
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.pool.Query(ctx, query, userID, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list captures: %w", err)
+	}
+	defer rows.Close()
+
+	captures := []domain.Capture{}
+	total := 0
+	for rows.Next() {
+		var c domain.Capture
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Source, &c.Kind, &c.Title, &c.Content, &c.Language, &c.URL, &c.Tags,
+			&c.Status, &c.PublishedID, &c.CreatedAt, &c.UpdatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan capture: %w", err)
+		}
+		captures = append(captures, c)
+	}
+	return captures, total, rows.Err()
+}
+
+// Update saves the user's edits to a capture still in the inbox, reporting false if it has
+// since left it
+func (r *CaptureRepository) Update(ctx context.Context, c *domain.Capture) (bool, error) {
+	query := `
+		UPDATE captures SET kind = $3, title = $4, content = $5, language = $6, tags = $7, updated_at = $8
+		WHERE id = $1 AND user_id = $2 AND status = 'inbox'
+	`
+	result, err := r.pool.Exec(ctx, query, c.ID, c.UserID, c.Kind, c.Title, c.Content, c.Language, c.Tags, c.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to update capture: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// SetStatus takes a capture out of the inbox: converted into the given kind, with the ID of the
+// entry, snippet, or bookmark made from it, or archived or dismissed with its kind unchanged. It
+// reports false if the capture had already left the inbox.
+func (r *CaptureRepository) SetStatus(ctx context.Context, id uuid.UUID, status, kind, publishedID string) (bool, error) {
+	query := `
+		UPDATE captures SET status = $2, kind = COALESCE(NULLIF($3, ''), kind), published_id = $4, updated_at = NOW()
+		WHERE id = $1 AND status = 'inbox'
+	`
+	result, err := r.pool.Exec(ctx, query, id, status, kind, publishedID)
+	if err != nil {
+		return false, fmt.Errorf("failed to set capture status: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ChatTicketRepository handles chat ticket persistence with raw SQL
+type ChatTicketRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewChatTicketRepository creates a new chat ticket repository
+func NewChatTicketRepository(pool *pgxpool.Pool) *ChatTicketRepository {
+	return &ChatTicketRepository{pool: pool}
+}
+
+// Create inserts a new ticket
+func (r *ChatTicketRepository) Create(ctx context.Context, ticket *domain.ChatTicket) error {
+	query := `INSERT INTO chat_tickets (ticket_hash, user_id, room, read_only, join_voice_only, expires_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	if _, err := r.pool.Exec(ctx, query, ticket.TicketHash, ticket.UserID, ticket.Room, ticket.ReadOnly, ticket.JoinVoiceOnly, ticket.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to create chat ticket: %w", err)
+	}
+	return nil
+}
+
+// Redeem deletes a ticket and returns it with its user's name and email (nil if there is no
+// such ticket). Deleting it in the same statement means each ticket can be redeemed only once.
+func (r *ChatTicketRepository) Redeem(ctx context.Context, hash string) (*domain.ChatTicket, error) {
+	query := `
+		WITH redeemed AS (
+			DELETE FROM chat_tickets WHERE ticket_hash = $1
+			RETURNING ticket_hash, user_id, room, read_only, join_voice_only, expires_at
+		)
+		SELECT t.ticket_hash, t.user_id, t.room, t.read_only, t.join_voice_only, t.expires_at, u.display_name, u.email
+		FROM redeemed t
+		JOIN users u ON u.id = t.user_id
+	`
+	var ticket domain.ChatTicket
+	err := r.pool.QueryRow(ctx, query, hash).Scan(
+		&ticket.TicketHash, &ticket.UserID, &ticket.Room, &ticket.ReadOnly, &ticket.JoinVoiceOnly, &ticket.ExpiresAt, &ticket.DisplayName, &ticket.Email,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to redeem chat ticket: %w", err)
+	}
+	return &ticket, nil
+}
+
+// DeleteExpired removes tickets that expired before the given time without being redeemed
+func (r *ChatTicketRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM chat_tickets WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired chat tickets: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CodeReviewRepository handles study group code reviews and their line comments with raw SQL
+type CodeReviewRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCodeReviewRepository creates a new code review repository
+func NewCodeReviewRepository(pool *pgxpool.Pool) *CodeReviewRepository {
+	return &CodeReviewRepository{pool: pool}
+}
+
+const codeReviewColumns = `r.id, r.group_id, r.user_id, u.display_name, r.snippet_id, r.title, r.note, r.status,
+	r.resolved_at, r.created_at, r.updated_at,
+	(SELECT COUNT(*) FROM code_review_comments c WHERE c.review_id = r.id),
+	(SELECT COUNT(*) FROM code_review_comments c WHERE c.review_id = r.id AND c.resolved_at IS NULL)`
+
+// scanCodeReview scans codeReviewColumns followed by any extra destinations
+func scanCodeReview(row pgx.Row, extra ...any) (*domain.CodeReview, error) {
+	var cr domain.CodeReview
+	dest := append([]any{&cr.ID, &cr.GroupID, &cr.UserID, &cr.AuthorName, &cr.SnippetID, &cr.Title, &cr.Note, &cr.Status,
+		&cr.ResolvedAt, &cr.CreatedAt, &cr.UpdatedAt, &cr.CommentCount, &cr.OpenComments}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &cr, nil
+}
+
+// Create inserts a new code review with its snapshot of the snippet's files
+func (r *CodeReviewRepository) Create(ctx context.Context, cr *domain.CodeReview) error {
+	query := `
+		INSERT INTO code_reviews (id, group_id, user_id, snippet_id, title, note, files, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.pool.Exec(ctx, query, cr.ID, cr.GroupID, cr.UserID, cr.SnippetID, cr.Title, cr.Note, cr.Files, cr.Status, cr.CreatedAt, cr.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create code review: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves a code review of a group with its files, or nil if there is none
+func (r *CodeReviewRepository) FindByID(ctx context.Context, id, groupID uuid.UUID) (*domain.CodeReview, error) {
+	query := `
+		SELECT ` + codeReviewColumns + `, r.files
+		FROM code_reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.id = $1 AND r.group_id = $2
+	`
+	var files []domain.SnippetFile
+	cr, err := scanCodeReview(r.pool.QueryRow(ctx, query, id, groupID), &files)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find code review: %w", err)
+	}
+	cr.Files = files
+	return cr, nil
+}
+
+// ListByGroup retrieves a group's code reviews without their files, newest first. An empty
+// status lists reviews in any status.
+func (r *CodeReviewRepository) ListByGroup(ctx context.Context, groupID uuid.UUID, status string, limit, offset int) ([]domain.CodeReview, int, error) {
+	query := `
+		SELECT ` + codeReviewColumns + `, COUNT(*) OVER()
+		FROM code_reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.group_id = $1 AND ($2 = '' OR r.status = $2)
+		ORDER BY r.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.pool.Query(ctx, query, groupID, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list code reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []domain.CodeReview{}
+	total := 0
+	for rows.Next() {
+		cr, err := scanCodeReview(rows, &total)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan code review: %w", err)
+		}
+		reviews = append(reviews, *cr)
+	}
+	return reviews, total, rows.Err()
+}
+
+// SetStatus marks a code review resolved or open again
+func (r *CodeReviewRepository) SetStatus(ctx context.Context, id uuid.UUID, status string, resolvedAt *time.Time) error {
+	query := `UPDATE code_reviews SET status = $2, resolved_at = $3, updated_at = NOW() WHERE id = $1`
+	if _, err := r.pool.Exec(ctx, query, id, status, resolvedAt); err != nil {
+		return fmt.Errorf("failed to update code review status: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a code review and its comments
+func (r *CodeReviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM code_reviews WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete code review: %w", err)
+	}
+	return nil
+}
+
+const codeReviewCommentColumns = `c.id, c.review_id, c.user_id, u.display_name, c.file_name, c.line, c.body, c.resolved_at, c.created_at`
+
+func scanCodeReviewComment(row pgx.Row) (*domain.CodeReviewComment, error) {
+	var c domain.CodeReviewComment
+	if err := row.Scan(&c.ID, &c.ReviewID, &c.UserID, &c.AuthorName, &c.File, &c.Line, &c.Body, &c.ResolvedAt, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Comments retrieves a review's comments, oldest first
+func (r *CodeReviewRepository) Comments(ctx context.Context, reviewID uuid.UUID) ([]domain.CodeReviewComment, error) {
+	query := `
+		SELECT ` + codeReviewCommentColumns + `
+		FROM code_review_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.review_id = $1
+		ORDER BY c.created_at, c.id
+	`
+	rows, err := r.pool.Query(ctx, query, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list code review comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []domain.CodeReviewComment{}
+	for rows.Next() {
+		c, err := scanCodeReviewComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan code review comment: %w", err)
+		}
+		comments = append(comments, *c)
+	}
+	return comments, rows.Err()
+}
+
+// FindComment retrieves a comment on a review, or nil if there is none
+func (r *CodeReviewRepository) FindComment(ctx context.Context, id, reviewID uuid.UUID) (*domain.CodeReviewComment, error) {
+	query := `
+		SELECT ` + codeReviewCommentColumns + `
+		FROM code_review_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.id = $1 AND c.review_id = $2
+	`
+	c, err := scanCodeReviewComment(r.pool.QueryRow(ctx, query, id, reviewID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find code review comment: %w", err)
+	}
+	return c, nil
+}
+
+// AddComment inserts a line comment and touches its review
+func (r *CodeReviewRepository) AddComment(ctx context.Context, c *domain.CodeReviewComment) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO code_review_comments (id, review_id, user_id, file_name, line, body, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, c.ID, c.ReviewID, c.UserID, c.File, c.Line, c.Body, c.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create code review comment: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE code_reviews SET updated_at = $2 WHERE id = $1`, c.ReviewID, c.CreatedAt); err != nil {
+		return fmt.Errorf("failed to touch code review: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ResolveComment marks a comment resolved, or open again when resolvedAt is nil
+func (r *CodeReviewRepository) ResolveComment(ctx context.Context, id uuid.UUID, resolvedAt *time.Time) error {
+	if _, err := r.pool.Exec(ctx, `UPDATE code_review_comments SET resolved_at = $2 WHERE id = $1`, id, resolvedAt); err != nil {
+		return fmt.Errorf("failed to resolve code review comment: %w", err)
+	}
+	return nil
+}
+
+// Participants returns the review's author and everyone who has commented on it who is
+// still a member of its group
+func (r *CodeReviewRepository) Participants(ctx context.Context, reviewID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT p.user_id
+		FROM (
+			SELECT user_id, group_id FROM code_reviews WHERE id = $1
+			UNION
+			SELECT c.user_id, r.group_id
+			FROM code_review_comments c
+			JOIN code_reviews r ON r.id = c.review_id
+			WHERE c.review_id = $1
+		) p
+		JOIN study_group_members m ON m.group_id = p.group_id AND m.user_id = p.user_id
+	`
+	rows, err := r.pool.Query(ctx, query, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list code review participants: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan code review participant: %w", err)
+		}
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, rows.Err()
+}
@@ -0,0 +1,241 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CodingRepository handles coding time sources and the time they report with raw SQL
+type CodingRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCodingRepository creates a new coding repository
+func NewCodingRepository(pool *pgxpool.Pool) *CodingRepository {
+	return &CodingRepository{pool: pool}
+}
+
+const codingSourceColumns = `user_id, provider, api_key, COALESCE(token_hash, ''), last_heartbeat_at, last_language, synced_at, sync_error, created_at, updated_at`
+
+func scanCodingSource(row pgx.Row) (*domain.CodingSource, error) {
+	var src domain.CodingSource
+	err := row.Scan(&src.UserID, &src.Provider, &src.APIKey, &src.TokenHash, &src.LastHeartbeatAt, &src.LastLanguage,
+		&src.SyncedAt, &src.SyncError, &src.CreatedAt, &src.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan coding source: %w", err)
+	}
+	return &src, nil
+}
+
+// FindSource retrieves a user's coding time source, or nil if they have none
+func (r *CodingRepository) FindSource(ctx context.Context, userID uuid.UUID) (*domain.CodingSource, error) {
+	return scanCodingSource(r.pool.QueryRow(ctx, `SELECT `+codingSourceColumns+` FROM coding_sources WHERE user_id = $1`, userID))
+}
+
+// FindSourceByToken retrieves the heartbeat source a token hash belongs to, or nil if there is none
+func (r *CodingRepository) FindSourceByToken(ctx context.Context, tokenHash string) (*domain.CodingSource, error) {
+	query := `SELECT ` + codingSourceColumns + ` FROM coding_sources WHERE token_hash = $1 AND provider = 'heartbeat'`
+	return scanCodingSource(r.pool.QueryRow(ctx, query, tokenHash))
+}
+
+// SaveSource sets a user's coding time source, replacing any other. WakaTime sources are synced
+// right away; heartbeat sources start counting from their next heartbeat.
+func (r *CodingRepository) SaveSource(ctx context.Context, src *domain.CodingSource) error {
+	query := `
+		INSERT INTO coding_sources (user_id, provider, api_key, token_hash, next_sync_at, created_at, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), CASE WHEN $2 = 'wakatime' THEN $5::timestamptz END, $5, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			provider = EXCLUDED.provider, api_key = EXCLUDED.api_key, token_hash = EXCLUDED.token_hash,
+			next_sync_at = EXCLUDED.next_sync_at, last_heartbeat_at = NULL, last_language = '',
+			synced_at = NULL, sync_error = '', updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.pool.Exec(ctx, query, src.UserID, src.Provider, src.APIKey, src.TokenHash, src.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save coding source: %w", err)
+	}
+	return nil
+}
+
+// DeleteSource removes a user's coding time source, reporting whether there was one. Time
+// already recorded is kept.
+func (r *CodingRepository) DeleteSource(ctx context.Context, userID uuid.UUID) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM coding_sources WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete coding source: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// ClaimDueSync picks a WakaTime source whose sync is due and schedules its next sync after
+// interval, so other instances skip it. It returns nil when none is due.
+func (r *CodingRepository) ClaimDueSync(ctx context.Context, now time.Time, interval time.Duration) (*domain.CodingSource, error) {
+	query := `
+		UPDATE coding_sources SET next_sync_at = $2
+		WHERE user_id = (
+			SELECT user_id FROM coding_sources
+			WHERE provider = 'wakatime' AND next_sync_at <= $1
+			ORDER BY next_sync_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + codingSourceColumns
+	return scanCodingSource(r.pool.QueryRow(ctx, query, now, now.Add(interval)))
+}
+
+// FinishSync records the outcome of a WakaTime sync
+func (r *CodingRepository) FinishSync(ctx context.Context, userID uuid.UUID, syncedAt time.Time, syncErr string) error {
+	query := `
+		UPDATE coding_sources
+		SET synced_at = CASE WHEN $3 = '' THEN $2 ELSE synced_at END, sync_error = $3
+		WHERE user_id = $1 AND provider = 'wakatime'
+	`
+	if _, err := r.pool.Exec(ctx, query, userID, syncedAt, syncErr); err != nil {
+		return fmt.Errorf("failed to finish coding sync: %w", err)
+	}
+	return nil
+}
+
+// ReplaceDays overwrites a user's coding time on the given days, as synced from WakaTime. It
+// returns how many whole minutes each day's total changed by.
+func (r *CodingRepository) ReplaceDays(ctx context.Context, userID uuid.UUID, days []domain.CodingDay) (map[time.Time]int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	changed := make(map[time.Time]int)
+	for _, day := range days {
+		before, err := dayMinutes(ctx, tx, userID, day.Date)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM coding_time WHERE user_id = $1 AND day = $2::date`, userID, day.Date); err != nil {
+			return nil, fmt.Errorf("failed to clear coding time: %w", err)
+		}
+		after, err := addCodingTime(ctx, tx, userID, day)
+		if err != nil {
+			return nil, err
+		}
+		if after != before {
+			changed[day.Date] = after - before
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit coding time: %w", err)
+	}
+	return changed, nil
+}
+
+// AddHeartbeatTime adds time totalled from heartbeats and moves the user's latest heartbeat
+// from prev to last. It reports false, recording nothing, if another request moved the latest
+// heartbeat first; the caller should total the heartbeats again. It returns how many whole
+// minutes each day's total grew by.
+func (r *CodingRepository) AddHeartbeatTime(ctx context.Context, userID uuid.UUID, prev, last *time.Time, lastLanguage string, days []domain.CodingDay) (map[time.Time]int, bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE coding_sources SET last_heartbeat_at = $3, last_language = $4
+		WHERE user_id = $1 AND provider = 'heartbeat' AND last_heartbeat_at IS NOT DISTINCT FROM $2
+	`, userID, prev, last, lastLanguage)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to move latest heartbeat: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, false, nil
+	}
+
+	changed := make(map[time.Time]int)
+	for _, day := range days {
+		before, err := dayMinutes(ctx, tx, userID, day.Date)
+		if err != nil {
+			return nil, false, err
+		}
+		after, err := addCodingTime(ctx, tx, userID, day)
+		if err != nil {
+			return nil, false, err
+		}
+		if after != before {
+			changed[day.Date] = after - before
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, fmt.Errorf("failed to commit coding time: %w", err)
+	}
+	return changed, true, nil
+}
+
+// dayMinutes returns a user's coding time on a day in whole minutes
+func dayMinutes(ctx context.Context, tx pgx.Tx, userID uuid.UUID, day time.Time) (int, error) {
+	var minutes int
+	err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(seconds), 0) / 60 FROM coding_time WHERE user_id = $1 AND day = $2::date`,
+		userID, day).Scan(&minutes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to total coding time: %w", err)
+	}
+	return minutes, nil
+}
+
+// addCodingTime adds a day's time by language and returns the day's new total in whole minutes
+func addCodingTime(ctx context.Context, tx pgx.Tx, userID uuid.UUID, day domain.CodingDay) (int, error) {
+	for language, seconds := range day.Seconds {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO coding_time (user_id, day, language, seconds)
+			VALUES ($1, $2::date, $3, $4)
+			ON CONFLICT (user_id, day, language) DO UPDATE SET seconds = coding_time.seconds + EXCLUDED.seconds
+		`, userID, day.Date, language, seconds)
+		if err != nil {
+			return 0, fmt.Errorf("failed to add coding time: %w", err)
+		}
+	}
+	return dayMinutes(ctx, tx, userID, day.Date)
+}
+
+// LanguageTotals retrieves a user's coding time by language on days from start on (all days
+// when start is zero), most time first
+func (r *CodingRepository) LanguageTotals(ctx context.Context, userID uuid.UUID, start time.Time) ([]domain.LanguageTime, error) {
+	query := `
+		SELECT language, SUM(seconds) / 60 AS minutes
+		FROM coding_time
+		WHERE user_id = $1 AND ($2::date IS NULL OR day >= $2::date)
+		GROUP BY language
+		HAVING SUM(seconds) >= 60
+		ORDER BY minutes DESC, language
+	`
+	var from *time.Time
+	if !start.IsZero() {
+		from = &start
+	}
+	rows, err := r.pool.Query(ctx, query, userID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total coding time by language: %w", err)
+	}
+	defer rows.Close()
+
+	totals := []domain.LanguageTime{}
+	for rows.Next() {
+		var t domain.LanguageTime
+		if err := rows.Scan(&t.Language, &t.Minutes); err != nil {
+			return nil, fmt.Errorf("failed to scan language time: %w", err)
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
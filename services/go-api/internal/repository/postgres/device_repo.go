@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DeviceAuthRepository handles device authorization persistence with raw SQL
+type DeviceAuthRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewDeviceAuthRepository creates a new device authorization repository
+func NewDeviceAuthRepository(pool *pgxpool.Pool) *DeviceAuthRepository {
+	return &DeviceAuthRepository{pool: pool}
+}
+
+const deviceAuthColumns = `id, device_code_hash, user_code, client_name, status, user_id,
+	poll_interval, last_polled_at, expires_at, created_at`
+
+func scanDeviceAuth(row pgx.Row) (*domain.DeviceAuthorization, error) {
+	var auth domain.DeviceAuthorization
+	err := row.Scan(
+		&auth.ID, &auth.DeviceCodeHash, &auth.UserCode, &auth.ClientName, &auth.Status, &auth.UserID,
+		&auth.PollInterval, &auth.LastPolledAt, &auth.ExpiresAt, &auth.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find device authorization: %w", err)
+	}
+	return &auth, nil
+}
+
+// Create inserts a new device authorization
+func (r *DeviceAuthRepository) Create(ctx context.Context, auth *domain.DeviceAuthorization) error {
+	query := `
+		INSERT INTO device_authorizations (id, device_code_hash, user_code, client_name, status,
+			poll_interval, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		auth.ID, auth.DeviceCodeHash, auth.UserCode, auth.ClientName, auth.Status,
+		auth.PollInterval, auth.ExpiresAt, auth.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create device authorization: %w", err)
+	}
+	return nil
+}
+
+// FindByDeviceCodeHash retrieves the authorization a device is polling for (nil if none)
+func (r *DeviceAuthRepository) FindByDeviceCodeHash(ctx context.Context, hash string) (*domain.DeviceAuthorization, error) {
+	query := `SELECT ` + deviceAuthColumns + ` FROM device_authorizations WHERE device_code_hash = $1`
+	return scanDeviceAuth(r.pool.QueryRow(ctx, query, hash))
+}
+
+// FindByUserCode retrieves the authorization a user is approving (nil if none)
+func (r *DeviceAuthRepository) FindByUserCode(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error) {
+	query := `SELECT ` + deviceAuthColumns + ` FROM device_authorizations WHERE user_code = $1`
+	return scanDeviceAuth(r.pool.QueryRow(ctx, query, userCode))
+}
+
+// Resolve approves or denies a pending, unexpired authorization. It reports false if the
+// authorization was already resolved or has expired.
+func (r *DeviceAuthRepository) Resolve(ctx context.Context, id uuid.UUID, status string, userID uuid.UUID, now time.Time) (bool, error) {
+	query := `
+		UPDATE device_authorizations
+		SET status = $2, user_id = $3
+		WHERE id = $1 AND status = 'pending' AND expires_at > $4
+	`
+	result, err := r.pool.Exec(ctx, query, id, status, userID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve device authorization: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// RecordPoll stores when the device last polled and the interval it must now respect
+func (r *DeviceAuthRepository) RecordPoll(ctx context.Context, id uuid.UUID, at time.Time, interval int) error {
+	query := `UPDATE device_authorizations SET last_polled_at = $2, poll_interval = $3 WHERE id = $1`
+	if _, err := r.pool.Exec(ctx, query, id, at, interval); err != nil {
+		return fmt.Errorf("failed to record device poll: %w", err)
+	}
+	return nil
+}
+
+// Consume marks an approved authorization as exchanged for a token. It reports false if
+// another poll already consumed it, so each approval yields exactly one token.
+func (r *DeviceAuthRepository) Consume(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `UPDATE device_authorizations SET status = 'consumed' WHERE id = $1 AND status = 'approved'`
+	result, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume device authorization: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// DeleteExpired removes authorizations that expired before the given time
+func (r *DeviceAuthRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM device_authorizations WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired device authorizations: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"devjournal/internal/database"
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EntrySnippetRepository handles links between journal entries and snippets with raw SQL
+type EntrySnippetRepository struct {
+	pool    *pgxpool.Pool
+	nearest *database.NearestPool
+}
+
+// NewEntrySnippetRepository creates a new entry snippet repository
+func NewEntrySnippetRepository(pool *pgxpool.Pool) *EntrySnippetRepository {
+	return &EntrySnippetRepository{pool: pool}
+}
+
+// ReadNearest sends the links shown on entries and snippets to the nearest of the primary and
+// its read replicas. A link made a moment ago may not show up until the replica catches up.
+func (r *EntrySnippetRepository) ReadNearest(nearest *database.NearestPool) *EntrySnippetRepository {
+	r.nearest = nearest
+	return r
+}
+
+// reads returns the pool for reads that tolerate replication lag
+func (r *EntrySnippetRepository) reads() *pgxpool.Pool {
+	if r.nearest == nil {
+		return r.pool
+	}
+	return r.nearest.Pool()
+}
+
+// Link links a snippet to an entry. Linking them again is a no-op.
+func (r *EntrySnippetRepository) Link(ctx context.Context, entryID uuid.UUID, snippetID string, userID uuid.UUID) error {
+	query := `
+		INSERT INTO entry_snippets (entry_id, snippet_id, user_id, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := r.pool.Exec(ctx, query, entryID, snippetID, userID); err != nil {
+		return fmt.Errorf("failed to link snippet: %w", err)
+	}
+	return nil
+}
+
+// Unlink removes a link. It reports whether there was one.
+func (r *EntrySnippetRepository) Unlink(ctx context.Context, entryID uuid.UUID, snippetID string) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM entry_snippets WHERE entry_id = $1 AND snippet_id = $2`, entryID, snippetID)
+	if err != nil {
+		return false, fmt.Errorf("failed to unlink snippet: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// DeleteBySnippet removes every link to a snippet, for when it is deleted
+func (r *EntrySnippetRepository) DeleteBySnippet(ctx context.Context, snippetID string) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM entry_snippets WHERE snippet_id = $1`, snippetID); err != nil {
+		return fmt.Errorf("failed to delete snippet links: %w", err)
+	}
+	return nil
+}
+
+// MoveToSnippet moves the links of a snippet merged into another to that snippet. Entries
+// linked to both keep one link.
+func (r *EntrySnippetRepository) MoveToSnippet(ctx context.Context, fromID, toID string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO entry_snippets (entry_id, snippet_id, user_id, created_at)
+		SELECT entry_id, $2, user_id, created_at FROM entry_snippets WHERE snippet_id = $1
+		ON CONFLICT DO NOTHING
+	`, fromID, toID)
+	if err != nil {
+		return fmt.Errorf("failed to move snippet links: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM entry_snippets WHERE snippet_id = $1`, fromID); err != nil {
+		return fmt.Errorf("failed to move snippet links: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// SnippetLinks returns the IDs of the snippets linked to an entry and when they were linked, oldest first
+func (r *EntrySnippetRepository) SnippetLinks(ctx context.Context, entryID uuid.UUID) ([]domain.RelatedSnippet, error) {
+	query := `
+		SELECT snippet_id, created_at
+		FROM entry_snippets
+		WHERE entry_id = $1
+		ORDER BY created_at, snippet_id
+	`
+	rows, err := r.reads().Query(ctx, query, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entry snippets: %w", err)
+	}
+	defer rows.Close()
+
+	var links []domain.RelatedSnippet
+	for rows.Next() {
+		var link domain.RelatedSnippet
+		if err := rows.Scan(&link.ID, &link.LinkedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan entry snippet: %w", err)
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+// RelatedEntries returns the user's entries linked to a snippet, oldest link first
+func (r *EntrySnippetRepository) RelatedEntries(ctx context.Context, snippetID string, userID uuid.UUID) ([]domain.RelatedEntry, error) {
+	query := `
+		SELECT j.id, j.title, es.created_at
+		FROM entry_snippets es
+		JOIN journal_entries j ON j.id = es.entry_id
+		WHERE es.snippet_id = $1 AND j.user_id = $2
+		ORDER BY es.created_at, j.id
+	`
+	rows, err := r.reads().Query(ctx, query, snippetID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snippet entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []domain.RelatedEntry{}
+	for rows.Next() {
+		var entry domain.RelatedEntry
+		if err := rows.Scan(&entry.ID, &entry.Title, &entry.LinkedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan snippet entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
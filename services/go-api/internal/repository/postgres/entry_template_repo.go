@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EntryTemplateRepository handles study group entry templates and the entries made from them with raw SQL
+type EntryTemplateRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewEntryTemplateRepository creates a new entry template repository
+func NewEntryTemplateRepository(pool *pgxpool.Pool) *EntryTemplateRepository {
+	return &EntryTemplateRepository{pool: pool}
+}
+
+const entryTemplateColumns = `id, group_id, created_by, name, prompts, tags, created_at, updated_at`
+
+func scanEntryTemplate(row pgx.Row) (*domain.EntryTemplate, error) {
+	var t domain.EntryTemplate
+	if err := row.Scan(&t.ID, &t.GroupID, &t.CreatedBy, &t.Name, &t.Prompts, &t.Tags, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Create inserts a new entry template
+func (r *EntryTemplateRepository) Create(ctx context.Context, t *domain.EntryTemplate) error {
+	query := `
+		INSERT INTO entry_templates (id, group_id, created_by, name, prompts, tags, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.pool.Exec(ctx, query, t.ID, t.GroupID, t.CreatedBy, t.Name, t.Prompts, t.Tags, t.CreatedAt, t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create entry template: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves a template of a group, or nil if there is none
+func (r *EntryTemplateRepository) FindByID(ctx context.Context, id, groupID uuid.UUID) (*domain.EntryTemplate, error) {
+	query := `SELECT ` + entryTemplateColumns + ` FROM entry_templates WHERE id = $1 AND group_id = $2`
+	t, err := scanEntryTemplate(r.pool.QueryRow(ctx, query, id, groupID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entry template: %w", err)
+	}
+	return t, nil
+}
+
+// ListByGroup retrieves a group's templates in the order they were published
+func (r *EntryTemplateRepository) ListByGroup(ctx context.Context, groupID uuid.UUID) ([]domain.EntryTemplate, error) {
+	query := `SELECT ` + entryTemplateColumns + ` FROM entry_templates WHERE group_id = $1 ORDER BY created_at, name`
+	rows, err := r.pool.Query(ctx, query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entry templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []domain.EntryTemplate{}
+	for rows.Next() {
+		t, err := scanEntryTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan entry template: %w", err)
+		}
+		templates = append(templates, *t)
+	}
+	return templates, rows.Err()
+}
+
+// Count returns how many templates a group has
+func (r *EntryTemplateRepository) Count(ctx context.Context, groupID uuid.UUID) (int, error) {
+	var count int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM entry_templates WHERE group_id = $1`, groupID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count entry templates: %w", err)
+	}
+	return count, nil
+}
+
+// Update replaces a template's name, prompts, and tags, returning false if the group has no such template
+func (r *EntryTemplateRepository) Update(ctx context.Context, t *domain.EntryTemplate) (bool, error) {
+	query := `
+		UPDATE entry_templates
+		SET name = $3, prompts = $4, tags = $5, updated_at = $6
+		WHERE id = $1 AND group_id = $2
+	`
+	result, err := r.pool.Exec(ctx, query, t.ID, t.GroupID, t.Name, t.Prompts, t.Tags, t.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to update entry template: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// Delete removes a template and its responses, returning false if the group has no such
+// template. The members' entries are kept.
+func (r *EntryTemplateRepository) Delete(ctx context.Context, id, groupID uuid.UUID) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM entry_templates WHERE id = $1 AND group_id = $2`, id, groupID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete entry template: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// FindResponse returns the ID of a member's entry for an instance of a template, or uuid.Nil if
+// they have none
+func (r *EntryTemplateRepository) FindResponse(ctx context.Context, templateID uuid.UUID, instance string, userID uuid.UUID) (uuid.UUID, error) {
+	query := `SELECT entry_id FROM entry_template_responses WHERE template_id = $1 AND instance = $2 AND user_id = $3`
+	var entryID uuid.UUID
+	err := r.pool.QueryRow(ctx, query, templateID, instance, userID).Scan(&entryID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to find template response: %w", err)
+	}
+	return entryID, nil
+}
+
+// AddResponse records a member's entry as their response to an instance of a template,
+// returning false if they already have one
+func (r *EntryTemplateRepository) AddResponse(ctx context.Context, templateID uuid.UUID, instance string, userID, entryID uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO entry_template_responses (template_id, instance, user_id, entry_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (template_id, instance, user_id) DO NOTHING
+	`
+	result, err := r.pool.Exec(ctx, query, templateID, instance, userID, entryID)
+	if err != nil {
+		return false, fmt.Errorf("failed to add template response: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// ListInstances returns the instances of a template with how many current members of its group
+// responded, newest first
+func (r *EntryTemplateRepository) ListInstances(ctx context.Context, templateID uuid.UUID) ([]domain.TemplateInstance, error) {
+	query := `
+		SELECT r.instance, COUNT(*), MAX(r.created_at)
+		FROM entry_template_responses r
+		JOIN entry_templates t ON t.id = r.template_id
+		JOIN study_group_members m ON m.group_id = t.group_id AND m.user_id = r.user_id
+		WHERE r.template_id = $1
+		GROUP BY r.instance
+		ORDER BY MAX(r.created_at) DESC, r.instance DESC
+	`
+	rows, err := r.pool.Query(ctx, query, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list template instances: %w", err)
+	}
+	defer rows.Close()
+
+	instances := []domain.TemplateInstance{}
+	for rows.Next() {
+		var instance domain.TemplateInstance
+		if err := rows.Scan(&instance.Instance, &instance.Responses, &instance.LastResponseAt); err != nil {
+			return nil, fmt.Errorf("failed to scan template instance: %w", err)
+		}
+		instances = append(instances, instance)
+	}
+	return instances, rows.Err()
+}
+
+// ListResponses returns the entries current members of a template's group made for an
+// instance of it, in the order they were started
+func (r *EntryTemplateRepository) ListResponses(ctx context.Context, templateID uuid.UUID, instance string) ([]domain.TemplateResponseEntry, error) {
+	query := `
+		SELECT r.user_id, u.display_name, e.id, e.content, e.content_format, e.is_vault, e.updated_at
+		FROM entry_template_responses r
+		JOIN entry_templates t ON t.id = r.template_id
+		JOIN study_group_members m ON m.group_id = t.group_id AND m.user_id = r.user_id
+		JOIN journal_entries e ON e.id = r.entry_id
+		JOIN users u ON u.id = r.user_id
+		WHERE r.template_id = $1 AND r.instance = $2
+		ORDER BY r.created_at
+	`
+	rows, err := r.pool.Query(ctx, query, templateID, instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list template responses: %w", err)
+	}
+	defer rows.Close()
+
+	var responses []domain.TemplateResponseEntry
+	for rows.Next() {
+		var response domain.TemplateResponseEntry
+		if err := rows.Scan(&response.UserID, &response.DisplayName, &response.EntryID, &response.Content,
+			&response.ContentFormat, &response.IsVault, &response.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan template response: %w", err)
+		}
+		responses = append(responses, response)
+	}
+	return responses, rows.Err()
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// FollowRepository handles follow relationships with raw SQL
+type FollowRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewFollowRepository creates a new follow repository
+func NewFollowRepository(pool *pgxpool.Pool) *FollowRepository {
+	return &FollowRepository{pool: pool}
+}
+
+// Follow makes follower follow followee. Following someone already followed is a no-op.
+func (r *FollowRepository) Follow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	query := `
+		INSERT INTO follows (follower_id, followee_id, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := r.pool.Exec(ctx, query, followerID, followeeID); err != nil {
+		return fmt.Errorf("failed to follow user: %w", err)
+	}
+	return nil
+}
+
+// Unfollow removes a follow. It reports whether there was one.
+func (r *FollowRepository) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`, followerID, followeeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to unfollow user: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// IsFollowing checks whether follower follows followee
+func (r *FollowRepository) IsFollowing(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM follows WHERE follower_id = $1 AND followee_id = $2)`
+	if err := r.pool.QueryRow(ctx, query, followerID, followeeID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check follow: %w", err)
+	}
+	return exists, nil
+}
+
+// Counts returns how many followers a user has and how many users they follow
+func (r *FollowRepository) Counts(ctx context.Context, userID uuid.UUID) (followers, following int, err error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM follows WHERE followee_id = $1),
+			(SELECT COUNT(*) FROM follows WHERE follower_id = $1)
+	`
+	if err := r.pool.QueryRow(ctx, query, userID).Scan(&followers, &following); err != nil {
+		return 0, 0, fmt.Errorf("failed to count follows: %w", err)
+	}
+	return followers, following, nil
+}
+
+// ListFollowers retrieves the users following userID, most recent first
+func (r *FollowRepository) ListFollowers(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.UserSummary, error) {
+	query := `
+		SELECT u.id, u.display_name
+		FROM follows f
+		JOIN users u ON u.id = f.follower_id
+		WHERE f.followee_id = $1
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	return r.listUsers(ctx, query, userID, limit, offset)
+}
+
+// ListFollowing retrieves the users userID follows, most recent first
+func (r *FollowRepository) ListFollowing(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.UserSummary, error) {
+	query := `
+		SELECT u.id, u.display_name
+		FROM follows f
+		JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = $1
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	return r.listUsers(ctx, query, userID, limit, offset)
+}
+
+func (r *FollowRepository) listUsers(ctx context.Context, query string, args ...interface{}) ([]domain.UserSummary, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list follows: %w", err)
+	}
+	defer rows.Close()
+
+	users := []domain.UserSummary{}
+	for rows.Next() {
+		var u domain.UserSummary
+		if err := rows.Scan(&u.ID, &u.DisplayName); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating follows: %w", err)
+	}
+	return users, nil
+}
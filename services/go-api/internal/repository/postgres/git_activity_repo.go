@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GitActivityRepository handles Git webhooks, the activity they report, and the drafts made from
+// it with raw SQL
+type GitActivityRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewGitActivityRepository creates a new Git activity repository
+func NewGitActivityRepository(pool *pgxpool.Pool) *GitActivityRepository {
+	return &GitActivityRepository{pool: pool}
+}
+
+const gitHookColumns = `id, user_id, secret, enabled, include_commits, include_pull_requests, repos, authors, created_at, updated_at`
+
+func scanGitHook(row pgx.Row) (*domain.GitHook, error) {
+	var hook domain.GitHook
+	err := row.Scan(&hook.ID, &hook.UserID, &hook.Secret, &hook.Enabled, &hook.IncludeCommits,
+		&hook.IncludePullRequests, &hook.Repos, &hook.Authors, &hook.CreatedAt, &hook.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan git hook: %w", err)
+	}
+	return &hook, nil
+}
+
+// FindHookByUser retrieves a user's webhook, or nil if they have none
+func (r *GitActivityRepository) FindHookByUser(ctx context.Context, userID uuid.UUID) (*domain.GitHook, error) {
+	return scanGitHook(r.pool.QueryRow(ctx, `SELECT `+gitHookColumns+` FROM git_hooks WHERE user_id = $1`, userID))
+}
+
+// FindHookByID retrieves the webhook a delivery is addressed to, or nil if there is none
+func (r *GitActivityRepository) FindHookByID(ctx context.Context, id uuid.UUID) (*domain.GitHook, error) {
+	return scanGitHook(r.pool.QueryRow(ctx, `SELECT `+gitHookColumns+` FROM git_hooks WHERE id = $1`, id))
+}
+
+// SaveHookSecret creates a user's webhook with the given ID and secret, or replaces the secret of
+// the existing one, keeping its ID and settings. It returns the saved webhook.
+func (r *GitActivityRepository) SaveHookSecret(ctx context.Context, hook *domain.GitHook) (*domain.GitHook, error) {
+	query := `
+		INSERT INTO git_hooks (id, user_id, secret, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, updated_at = EXCLUDED.updated_at
+		RETURNING ` + gitHookColumns
+	return scanGitHook(r.pool.QueryRow(ctx, query, hook.ID, hook.UserID, hook.Secret, hook.UpdatedAt))
+}
+
+// UpdateHookSettings saves a webhook's filters, returning nil if the user has no webhook
+func (r *GitActivityRepository) UpdateHookSettings(ctx context.Context, hook *domain.GitHook) (*domain.GitHook, error) {
+	query := `
+		UPDATE git_hooks
+		SET enabled = $2, include_commits = $3, include_pull_requests = $4, repos = $5, authors = $6, updated_at = NOW()
+		WHERE user_id = $1
+		RETURNING ` + gitHookColumns
+	return scanGitHook(r.pool.QueryRow(ctx, query, hook.UserID, hook.Enabled, hook.IncludeCommits,
+		hook.IncludePullRequests, hook.Repos, hook.Authors))
+}
+
+// DeleteHook removes a user's webhook, reporting whether there was one
+func (r *GitActivityRepository) DeleteHook(ctx context.Context, userID uuid.UUID) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM git_hooks WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete git hook: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// RecordActivity stores activity for a user, skipping events already recorded, and returns the
+// ones that are new
+func (r *GitActivityRepository) RecordActivity(ctx context.Context, userID uuid.UUID, activities []domain.GitActivity) ([]domain.GitActivity, error) {
+	query := `
+		INSERT INTO git_activity (id, user_id, provider, kind, external_id, repo, ref, action, title, url,
+			additions, deletions, files_changed, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (user_id, provider, external_id) DO NOTHING
+	`
+	recorded := []domain.GitActivity{}
+	for _, a := range activities {
+		a.ID, a.UserID = uuid.New(), userID
+		result, err := r.pool.Exec(ctx, query, a.ID, a.UserID, a.Provider, a.Kind, a.ExternalID, a.Repo, a.Ref,
+			a.Action, a.Title, a.URL, a.Additions, a.Deletions, a.FilesChanged, a.OccurredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record git activity: %w", err)
+		}
+		if result.RowsAffected() > 0 {
+			recorded = append(recorded, a)
+		}
+	}
+	return recorded, nil
+}
+
+// FindActivity retrieves a user's activity in [from, to), oldest first
+func (r *GitActivityRepository) FindActivity(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]domain.GitActivity, error) {
+	query := `
+		SELECT id, user_id, provider, kind, external_id, repo, ref, action, title, url, additions, deletions,
+			files_changed, occurred_at
+		FROM git_activity
+		WHERE user_id = $1 AND occurred_at >= $2 AND occurred_at < $3
+		ORDER BY occurred_at
+	`
+	rows, err := r.pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find git activity: %w", err)
+	}
+	defer rows.Close()
+
+	activities := []domain.GitActivity{}
+	for rows.Next() {
+		var a domain.GitActivity
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Provider, &a.Kind, &a.ExternalID, &a.Repo, &a.Ref, &a.Action,
+			&a.Title, &a.URL, &a.Additions, &a.Deletions, &a.FilesChanged, &a.OccurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan git activity: %w", err)
+		}
+		activities = append(activities, a)
+	}
+	return activities, rows.Err()
+}
+
+const gitDraftColumns = `id, user_id, day, title, content, tags, status, edited, entry_id, created_at, updated_at`
+
+func scanGitDraft(row pgx.Row) (*domain.GitDraft, error) {
+	var d domain.GitDraft
+	err := row.Scan(&d.ID, &d.UserID, &d.Day, &d.Title, &d.Content, &d.Tags, &d.Status, &d.Edited, &d.EntryID,
+		&d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// RefreshDraft creates the draft for a user's day, or replaces its text with a fresh summary
+// unless the user has edited, published, or dismissed it
+func (r *GitActivityRepository) RefreshDraft(ctx context.Context, draft *domain.GitDraft) error {
+	query := `
+		INSERT INTO git_drafts (id, user_id, day, title, content, tags, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (user_id, day) DO UPDATE SET
+			title = EXCLUDED.title, content = EXCLUDED.content, tags = EXCLUDED.tags, updated_at = EXCLUDED.updated_at
+		WHERE git_drafts.status = 'draft' AND NOT git_drafts.edited
+	`
+	_, err := r.pool.Exec(ctx, query, draft.ID, draft.UserID, draft.Day, draft.Title, draft.Content, draft.Tags, draft.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to refresh git draft: %w", err)
+	}
+	return nil
+}
+
+// ListDrafts retrieves a user's unpublished drafts, most recent day first
+func (r *GitActivityRepository) ListDrafts(ctx context.Context, userID uuid.UUID, limit int) ([]domain.GitDraft, error) {
+	query := `SELECT ` + gitDraftColumns + ` FROM git_drafts WHERE user_id = $1 AND status = 'draft' ORDER BY day DESC LIMIT $2`
+	rows, err := r.pool.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list git drafts: %w", err)
+	}
+	defer rows.Close()
+
+	drafts := []domain.GitDraft{}
+	for rows.Next() {
+		d, err := scanGitDraft(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan git draft: %w", err)
+		}
+		drafts = append(drafts, *d)
+	}
+	return drafts, rows.Err()
+}
+
+// FindDraft retrieves a user's draft, or nil if there is none
+func (r *GitActivityRepository) FindDraft(ctx context.Context, id, userID uuid.UUID) (*domain.GitDraft, error) {
+	query := `SELECT ` + gitDraftColumns + ` FROM git_drafts WHERE id = $1 AND user_id = $2`
+	d, err := scanGitDraft(r.pool.QueryRow(ctx, query, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find git draft: %w", err)
+	}
+	return d, nil
+}
+
+// UpdateDraft saves the user's edits to a draft, which then stops following new activity
+func (r *GitActivityRepository) UpdateDraft(ctx context.Context, draft *domain.GitDraft) error {
+	query := `
+		UPDATE git_drafts SET title = $3, content = $4, tags = $5, edited = true, updated_at = $6
+		WHERE id = $1 AND user_id = $2
+	`
+	_, err := r.pool.Exec(ctx, query, draft.ID, draft.UserID, draft.Title, draft.Content, draft.Tags, draft.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update git draft: %w", err)
+	}
+	return nil
+}
+
+// SetDraftStatus marks a draft published, with the entry made from it, or dismissed
+func (r *GitActivityRepository) SetDraftStatus(ctx context.Context, id uuid.UUID, status string, entryID *uuid.UUID) error {
+	query := `UPDATE git_drafts SET status = $2, entry_id = $3, updated_at = NOW() WHERE id = $1`
+	if _, err := r.pool.Exec(ctx, query, id, status, entryID); err != nil {
+		return fmt.Errorf("failed to set git draft status: %w", err)
+	}
+	return nil
+}
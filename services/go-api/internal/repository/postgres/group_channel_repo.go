@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GroupChannelRepository handles study group chat channel persistence with raw SQL
+type GroupChannelRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewGroupChannelRepository creates a new group channel repository
+func NewGroupChannelRepository(pool *pgxpool.Pool) *GroupChannelRepository {
+	return &GroupChannelRepository{pool: pool}
+}
+
+// List returns a group's stored channels in the order they were created
+func (r *GroupChannelRepository) List(ctx context.Context, groupID uuid.UUID) ([]*domain.GroupChannel, error) {
+	query := `
+		SELECT group_id, name, post_policy, created_at
+		FROM group_channels
+		WHERE group_id = $1
+		ORDER BY created_at, name
+	`
+	rows, err := r.pool.Query(ctx, query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group channels: %w", err)
+	}
+	defer rows.Close()
+
+	var channels []*domain.GroupChannel
+	for rows.Next() {
+		var channel domain.GroupChannel
+		if err := rows.Scan(&channel.GroupID, &channel.Name, &channel.PostPolicy, &channel.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group channel: %w", err)
+		}
+		channel.Room = domain.ChannelRoom(channel.GroupID, channel.Name)
+		channels = append(channels, &channel)
+	}
+	return channels, rows.Err()
+}
+
+// Find retrieves a channel by name (nil if the group has no such stored channel)
+func (r *GroupChannelRepository) Find(ctx context.Context, groupID uuid.UUID, name string) (*domain.GroupChannel, error) {
+	query := `
+		SELECT group_id, name, post_policy, created_at
+		FROM group_channels
+		WHERE group_id = $1 AND name = $2
+	`
+	var channel domain.GroupChannel
+	err := r.pool.QueryRow(ctx, query, groupID, name).Scan(&channel.GroupID, &channel.Name, &channel.PostPolicy, &channel.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group channel: %w", err)
+	}
+	channel.Room = domain.ChannelRoom(channel.GroupID, channel.Name)
+	return &channel, nil
+}
+
+// Create inserts a channel, returning false if the group already has one with that name
+func (r *GroupChannelRepository) Create(ctx context.Context, channel *domain.GroupChannel) (bool, error) {
+	query := `
+		INSERT INTO group_channels (group_id, name, post_policy, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (group_id, name) DO NOTHING
+	`
+	result, err := r.pool.Exec(ctx, query, channel.GroupID, channel.Name, channel.PostPolicy, channel.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create group channel: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// Upsert creates a channel or replaces its posting policy, keeping its creation time
+func (r *GroupChannelRepository) Upsert(ctx context.Context, channel *domain.GroupChannel) error {
+	query := `
+		INSERT INTO group_channels (group_id, name, post_policy, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (group_id, name)
+		DO UPDATE SET post_policy = $3
+		RETURNING created_at
+	`
+	err := r.pool.QueryRow(ctx, query, channel.GroupID, channel.Name, channel.PostPolicy, channel.CreatedAt).Scan(&channel.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save group channel: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a channel, returning false if the group has no such stored channel
+func (r *GroupChannelRepository) Delete(ctx context.Context, groupID uuid.UUID, name string) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM group_channels WHERE group_id = $1 AND name = $2`, groupID, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete group channel: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// Count returns how many channels a group has added besides general
+func (r *GroupChannelRepository) Count(ctx context.Context, groupID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM group_channels WHERE group_id = $1 AND name <> $2`
+	var count int
+	if err := r.pool.QueryRow(ctx, query, groupID, domain.DefaultChannel).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count group channels: %w", err)
+	}
+	return count, nil
+}
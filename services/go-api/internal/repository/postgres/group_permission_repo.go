@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GroupPermissionRepository handles study group permission settings with raw SQL
+type GroupPermissionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewGroupPermissionRepository creates a new group permission repository
+func NewGroupPermissionRepository(pool *pgxpool.Pool) *GroupPermissionRepository {
+	return &GroupPermissionRepository{pool: pool}
+}
+
+// Get retrieves the permissions of a group (nil if never configured)
+func (r *GroupPermissionRepository) Get(ctx context.Context, groupID uuid.UUID) (*domain.GroupPermissions, error) {
+	query := `
+		SELECT group_id, share_entries, post_snippets, start_sessions, updated_at
+		FROM study_group_permissions
+		WHERE group_id = $1
+	`
+	var p domain.GroupPermissions
+	err := r.pool.QueryRow(ctx, query, groupID).Scan(&p.GroupID, &p.ShareEntries, &p.PostSnippets, &p.StartSessions, &p.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group permissions: %w", err)
+	}
+	return &p, nil
+}
+
+// Upsert creates or replaces the permissions of a group
+func (r *GroupPermissionRepository) Upsert(ctx context.Context, p *domain.GroupPermissions) error {
+	query := `
+		INSERT INTO study_group_permissions (group_id, share_entries, post_snippets, start_sessions, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (group_id)
+		DO UPDATE SET share_entries = $2, post_snippets = $3, start_sessions = $4, updated_at = $5
+	`
+	if _, err := r.pool.Exec(ctx, query, p.GroupID, p.ShareEntries, p.PostSnippets, p.StartSessions, p.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update group permissions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IdentityRepository handles users' single sign-on identities with raw SQL
+type IdentityRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewIdentityRepository creates a new identity repository
+func NewIdentityRepository(pool *pgxpool.Pool) *IdentityRepository {
+	return &IdentityRepository{pool: pool}
+}
+
+// FindUserID returns the user an identity is linked to, or uuid.Nil if it isn't linked
+func (r *IdentityRepository) FindUserID(ctx context.Context, issuer, subject string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		SELECT user_id FROM user_identities WHERE issuer = $1 AND subject = $2
+	`, issuer, subject).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to find identity: %w", err)
+	}
+	return userID, nil
+}
+
+// Link records a sign-in with an identity, linking it to the user the first time
+func (r *IdentityRepository) Link(ctx context.Context, identity *domain.UserIdentity) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO user_identities (issuer, subject, user_id, email, created_at, last_login_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (issuer, subject) DO UPDATE SET email = EXCLUDED.email, last_login_at = EXCLUDED.last_login_at
+	`, identity.Issuer, identity.Subject, identity.UserID, identity.Email, identity.CreatedAt, identity.LastLoginAt)
+	if err != nil {
+		return fmt.Errorf("failed to link identity: %w", err)
+	}
+	return nil
+}
+
+// FindSubject returns the subject a user is linked to at an issuer, or "" if they aren't linked
+func (r *IdentityRepository) FindSubject(ctx context.Context, userID uuid.UUID, issuer string) (string, error) {
+	var subject string
+	err := r.pool.QueryRow(ctx, `
+		SELECT subject FROM user_identities WHERE user_id = $1 AND issuer = $2
+		ORDER BY last_login_at DESC LIMIT 1
+	`, userID, issuer).Scan(&subject)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find identity: %w", err)
+	}
+	return subject, nil
+}
+
+// ListSubjects returns the subjects users are linked to at an issuer, by user
+func (r *IdentityRepository) ListSubjects(ctx context.Context, issuer string, userIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT DISTINCT ON (user_id) user_id, subject FROM user_identities
+		WHERE issuer = $1 AND user_id = ANY($2)
+		ORDER BY user_id, last_login_at DESC
+	`, issuer, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+	defer rows.Close()
+
+	subjects := make(map[uuid.UUID]string)
+	for rows.Next() {
+		var userID uuid.UUID
+		var subject string
+		if err := rows.Scan(&userID, &subject); err != nil {
+			return nil, fmt.Errorf("failed to scan identity: %w", err)
+		}
+		subjects[userID] = subject
+	}
+	return subjects, rows.Err()
+}
+
+// Unlink removes a user's identities at an issuer
+func (r *IdentityRepository) Unlink(ctx context.Context, userID uuid.UUID, issuer string) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM user_identities WHERE user_id = $1 AND issuer = $2`, userID, issuer); err != nil {
+		return fmt.Errorf("failed to unlink identity: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ImpersonationRepository handles the audit log of admin impersonation sessions with raw SQL
+type ImpersonationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewImpersonationRepository creates a new impersonation repository
+func NewImpersonationRepository(pool *pgxpool.Pool) *ImpersonationRepository {
+	return &ImpersonationRepository{pool: pool}
+}
+
+const impersonationColumns = `i.id, i.admin_id, a.display_name, i.user_id, i.reason, i.read_only, i.started_at, i.expires_at, i.ended_at`
+
+func scanImpersonation(row pgx.Row, extra ...any) (*domain.Impersonation, error) {
+	var i domain.Impersonation
+	err := row.Scan(append([]any{&i.ID, &i.AdminID, &i.AdminName, &i.UserID, &i.Reason, &i.ReadOnly, &i.StartedAt, &i.ExpiresAt, &i.EndedAt}, extra...)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find impersonation: %w", err)
+	}
+	return &i, nil
+}
+
+// Create records the start of an impersonation session
+func (r *ImpersonationRepository) Create(ctx context.Context, imp *domain.Impersonation) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO impersonations (id, admin_id, user_id, reason, read_only, started_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, imp.ID, imp.AdminID, imp.UserID, imp.Reason, imp.ReadOnly, imp.StartedAt, imp.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create impersonation: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves an impersonation session (nil if none)
+func (r *ImpersonationRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Impersonation, error) {
+	return scanImpersonation(r.pool.QueryRow(ctx, `
+		SELECT `+impersonationColumns+`
+		FROM impersonations i JOIN users a ON a.id = i.admin_id
+		WHERE i.id = $1
+	`, id))
+}
+
+// IsActive reports whether an impersonation session has neither expired nor been ended
+func (r *ImpersonationRepository) IsActive(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	var active bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT ended_at IS NULL AND expires_at > $2 FROM impersonations WHERE id = $1
+	`, id, now).Scan(&active)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check impersonation: %w", err)
+	}
+	return active, nil
+}
+
+// End ends an impersonation session early. It returns false if the session had already ended or
+// expired.
+func (r *ImpersonationRepository) End(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE impersonations SET ended_at = $2 WHERE id = $1 AND ended_at IS NULL AND expires_at > $2
+	`, id, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to end impersonation: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// List retrieves all impersonation sessions, newest first, with the total count
+func (r *ImpersonationRepository) List(ctx context.Context, limit, offset int) ([]domain.Impersonation, int, error) {
+	return r.list(ctx, `
+		SELECT `+impersonationColumns+`, COUNT(*) OVER()
+		FROM impersonations i JOIN users a ON a.id = i.admin_id
+		ORDER BY i.started_at DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+}
+
+// ListByUser retrieves the impersonation sessions of a user, newest first, with the total count
+func (r *ImpersonationRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Impersonation, int, error) {
+	return r.list(ctx, `
+		SELECT `+impersonationColumns+`, COUNT(*) OVER()
+		FROM impersonations i JOIN users a ON a.id = i.admin_id
+		WHERE i.user_id = $3
+		ORDER BY i.started_at DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset, userID)
+}
+
+func (r *ImpersonationRepository) list(ctx context.Context, query string, args ...any) ([]domain.Impersonation, int, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list impersonations: %w", err)
+	}
+	defer rows.Close()
+
+	imps := []domain.Impersonation{}
+	total := 0
+	for rows.Next() {
+		imp, err := scanImpersonation(rows, &total)
+		if err != nil {
+			return nil, 0, err
+		}
+		imps = append(imps, *imp)
+	}
+	return imps, total, rows.Err()
+}
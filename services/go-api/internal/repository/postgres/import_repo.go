@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ImportRepository handles import job persistence with raw SQL
+type ImportRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewImportRepository creates a new import repository
+func NewImportRepository(pool *pgxpool.Pool) *ImportRepository {
+	return &ImportRepository{pool: pool}
+}
+
+const importColumns = `id, user_id, workspace_id, source, status, total, processed, imported, skipped, error,
+	created_at, updated_at, finished_at`
+
+func scanImport(row pgx.Row) (*domain.Import, error) {
+	var imp domain.Import
+	err := row.Scan(
+		&imp.ID, &imp.UserID, &imp.WorkspaceID, &imp.Source, &imp.Status, &imp.Total, &imp.Processed,
+		&imp.Imported, &imp.Skipped, &imp.Error, &imp.CreatedAt, &imp.UpdatedAt, &imp.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &imp, nil
+}
+
+// Create stores a pending import with its upload
+func (r *ImportRepository) Create(ctx context.Context, imp *domain.Import) error {
+	query := `
+		INSERT INTO imports (id, user_id, workspace_id, source, status, archive, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.pool.Exec(ctx, query, imp.ID, imp.UserID, imp.WorkspaceID, imp.Source, imp.Status,
+		imp.Archive, imp.CreatedAt, imp.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create import: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves a user's import, or nil if there is none
+func (r *ImportRepository) FindByID(ctx context.Context, id, userID uuid.UUID) (*domain.Import, error) {
+	query := `SELECT ` + importColumns + ` FROM imports WHERE id = $1 AND user_id = $2`
+	imp, err := scanImport(r.pool.QueryRow(ctx, query, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find import: %w", err)
+	}
+	return imp, nil
+}
+
+// ListByUser retrieves a user's most recent imports, newest first
+func (r *ImportRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]domain.Import, error) {
+	query := `SELECT ` + importColumns + ` FROM imports WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2`
+	rows, err := r.pool.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list imports: %w", err)
+	}
+	defer rows.Close()
+
+	imports := []domain.Import{}
+	for rows.Next() {
+		imp, err := scanImport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import: %w", err)
+		}
+		imports = append(imports, *imp)
+	}
+	return imports, rows.Err()
+}
+
+// ClaimNext locks the oldest import that is pending, or running under an expired lease, marks it
+// running under a new lease, and returns it with its upload. It returns nil when there is none.
+func (r *ImportRepository) ClaimNext(ctx context.Context, now time.Time, lease time.Duration) (*domain.Import, error) {
+	query := `
+		WITH next AS (
+			SELECT id FROM imports
+			WHERE status = 'pending' OR (status = 'running' AND lease_until <= $1)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE imports i
+		SET status = 'running', lease_until = $2, updated_at = $1
+		FROM next
+		WHERE i.id = next.id
+		RETURNING i.id, i.user_id, i.workspace_id, i.source, i.status, i.total, i.processed, i.imported,
+			i.skipped, i.error, i.created_at, i.updated_at, i.finished_at, i.archive
+	`
+	var imp domain.Import
+	err := r.pool.QueryRow(ctx, query, now, now.Add(lease)).Scan(
+		&imp.ID, &imp.UserID, &imp.WorkspaceID, &imp.Source, &imp.Status, &imp.Total, &imp.Processed,
+		&imp.Imported, &imp.Skipped, &imp.Error, &imp.CreatedAt, &imp.UpdatedAt, &imp.FinishedAt, &imp.Archive,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim import: %w", err)
+	}
+	return &imp, nil
+}
+
+// SetTotal records how many notes an import's upload holds, and how many were skipped reading it
+func (r *ImportRepository) SetTotal(ctx context.Context, id uuid.UUID, total, skipped int) error {
+	query := `UPDATE imports SET total = $2, skipped = $3, updated_at = NOW() WHERE id = $1`
+	if _, err := r.pool.Exec(ctx, query, id, total, skipped); err != nil {
+		return fmt.Errorf("failed to set import total: %w", err)
+	}
+	return nil
+}
+
+// RecordProgress counts one more processed note, as imported or skipped, and renews the lease
+func (r *ImportRepository) RecordProgress(ctx context.Context, id uuid.UUID, imported bool, leaseUntil time.Time) error {
+	query := `
+		UPDATE imports
+		SET processed = processed + 1,
+			imported = imported + CASE WHEN $2 THEN 1 ELSE 0 END,
+			skipped = skipped + CASE WHEN $2 THEN 0 ELSE 1 END,
+			lease_until = $3, updated_at = NOW()
+		WHERE id = $1
+	`
+	if _, err := r.pool.Exec(ctx, query, id, imported, leaseUntil); err != nil {
+		return fmt.Errorf("failed to record import progress: %w", err)
+	}
+	return nil
+}
+
+// Finish marks an import completed or failed and drops its upload
+func (r *ImportRepository) Finish(ctx context.Context, id uuid.UUID, status, errMsg string) error {
+	query := `
+		UPDATE imports
+		SET status = $2, error = $3, archive = NULL, lease_until = NULL, updated_at = NOW(), finished_at = NOW()
+		WHERE id = $1
+	`
+	if _, err := r.pool.Exec(ctx, query, id, status, errMsg); err != nil {
+		return fmt.Errorf("failed to finish import: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IntegrationRepository handles group integration and delivery queue persistence with raw SQL
+type IntegrationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewIntegrationRepository creates a new integration repository
+func NewIntegrationRepository(pool *pgxpool.Pool) *IntegrationRepository {
+	return &IntegrationRepository{pool: pool}
+}
+
+const integrationColumns = `id, group_id, provider, team_id, channel_id, channel_name, webhook_url, bot_token,
+	enabled, sync_inbound, created_by, created_at, updated_at`
+
+func scanIntegration(row pgx.Row) (*domain.GroupIntegration, error) {
+	var integ domain.GroupIntegration
+	err := row.Scan(
+		&integ.ID, &integ.GroupID, &integ.Provider, &integ.TeamID, &integ.ChannelID, &integ.ChannelName,
+		&integ.WebhookURL, &integ.BotToken, &integ.Enabled, &integ.SyncInbound, &integ.CreatedBy,
+		&integ.CreatedAt, &integ.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &integ, nil
+}
+
+func (r *IntegrationRepository) list(ctx context.Context, query string, args ...interface{}) ([]domain.GroupIntegration, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list integrations: %w", err)
+	}
+	defer rows.Close()
+
+	integrations := []domain.GroupIntegration{}
+	for rows.Next() {
+		integ, err := scanIntegration(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan integration: %w", err)
+		}
+		integrations = append(integrations, *integ)
+	}
+	return integrations, rows.Err()
+}
+
+// Upsert connects a group to a channel, replacing any earlier connection to the same provider
+func (r *IntegrationRepository) Upsert(ctx context.Context, integ *domain.GroupIntegration) error {
+	query := `
+		INSERT INTO group_integrations (id, group_id, provider, team_id, channel_id, channel_name, webhook_url,
+			bot_token, enabled, sync_inbound, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (group_id, provider) DO UPDATE SET
+			team_id = EXCLUDED.team_id,
+			channel_id = EXCLUDED.channel_id,
+			channel_name = EXCLUDED.channel_name,
+			webhook_url = EXCLUDED.webhook_url,
+			bot_token = EXCLUDED.bot_token,
+			enabled = true,
+			created_by = EXCLUDED.created_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, sync_inbound, created_at
+	`
+	err := r.pool.QueryRow(ctx, query,
+		integ.ID, integ.GroupID, integ.Provider, integ.TeamID, integ.ChannelID, integ.ChannelName, integ.WebhookURL,
+		integ.BotToken, integ.Enabled, integ.SyncInbound, integ.CreatedBy, integ.CreatedAt, integ.UpdatedAt,
+	).Scan(&integ.ID, &integ.SyncInbound, &integ.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save integration: %w", err)
+	}
+	integ.Enabled = true
+	return nil
+}
+
+// FindByGroup retrieves a group's integration with a provider (nil if not connected)
+func (r *IntegrationRepository) FindByGroup(ctx context.Context, groupID uuid.UUID, provider string) (*domain.GroupIntegration, error) {
+	query := `SELECT ` + integrationColumns + ` FROM group_integrations WHERE group_id = $1 AND provider = $2`
+	integ, err := scanIntegration(r.pool.QueryRow(ctx, query, groupID, provider))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find integration: %w", err)
+	}
+	return integ, nil
+}
+
+// ListByGroup returns every integration of a group
+func (r *IntegrationRepository) ListByGroup(ctx context.Context, groupID uuid.UUID) ([]domain.GroupIntegration, error) {
+	query := `SELECT ` + integrationColumns + ` FROM group_integrations WHERE group_id = $1 ORDER BY provider`
+	return r.list(ctx, query, groupID)
+}
+
+// ListInbound returns the enabled integrations relaying a channel's messages back into their groups
+func (r *IntegrationRepository) ListInbound(ctx context.Context, provider, channelID string) ([]domain.GroupIntegration, error) {
+	query := `SELECT ` + integrationColumns + ` FROM group_integrations
+		WHERE provider = $1 AND channel_id = $2 AND enabled AND sync_inbound`
+	return r.list(ctx, query, provider, channelID)
+}
+
+// UpdateSettings saves an integration's enabled and sync settings
+func (r *IntegrationRepository) UpdateSettings(ctx context.Context, integ *domain.GroupIntegration) error {
+	query := `UPDATE group_integrations SET enabled = $2, sync_inbound = $3, updated_at = $4 WHERE id = $1`
+	if _, err := r.pool.Exec(ctx, query, integ.ID, integ.Enabled, integ.SyncInbound, integ.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update integration: %w", err)
+	}
+	return nil
+}
+
+// Delete disconnects a group from a provider. Queued deliveries are dropped with it.
+func (r *IntegrationRepository) Delete(ctx context.Context, groupID uuid.UUID, provider string) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM group_integrations WHERE group_id = $1 AND provider = $2`, groupID, provider)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete integration: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// Enqueue queues a message for every enabled integration of a group except the provider it came from.
+// It returns how many deliveries were queued.
+func (r *IntegrationRepository) Enqueue(ctx context.Context, groupID uuid.UUID, messageID, skipProvider string, payloads map[string][]byte) (int64, error) {
+	query := `
+		INSERT INTO integration_deliveries (integration_id, message_id, payload)
+		SELECT id, $2, CASE provider WHEN 'slack' THEN $4::jsonb ELSE $5::jsonb END
+		FROM group_integrations
+		WHERE group_id = $1 AND enabled AND provider <> $3
+	`
+	result, err := r.pool.Exec(ctx, query, groupID, messageID, skipProvider,
+		string(payloads[domain.IntegrationSlack]), string(payloads[domain.IntegrationDiscord]))
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue integration deliveries: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// ClaimDue locks up to limit pending deliveries that are due and pushes their next attempt out by
+// lease, so a concurrent worker or a crash mid-delivery cannot send them twice in that window
+func (r *IntegrationRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.IntegrationDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT id FROM integration_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE integration_deliveries d
+		SET next_attempt_at = $2
+		FROM due, group_integrations i
+		WHERE d.id = due.id AND i.id = d.integration_id
+		RETURNING d.id, d.integration_id, d.message_id, d.payload::text, i.webhook_url, d.status,
+			d.attempts, d.last_error, d.next_attempt_at, d.created_at
+	`
+	rows, err := r.pool.Query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim integration deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.IntegrationDelivery{}
+	for rows.Next() {
+		var d domain.IntegrationDelivery
+		var payload string
+		if err := rows.Scan(&d.ID, &d.IntegrationID, &d.MessageID, &payload, &d.WebhookURL, &d.Status,
+			&d.Attempts, &d.LastError, &d.NextAttemptAt, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan integration delivery: %w", err)
+		}
+		d.Payload = []byte(payload)
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// MarkDelivered records a successful delivery
+func (r *IntegrationRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE integration_deliveries SET status = 'delivered', attempts = attempts + 1, last_error = '' WHERE id = $1`
+	if _, err := r.pool.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to mark delivery delivered: %w", err)
+	}
+	return nil
+}
+
+// MarkAttemptFailed records a failed delivery. A nil retryAt gives up on the delivery.
+func (r *IntegrationRepository) MarkAttemptFailed(ctx context.Context, id uuid.UUID, lastError string, retryAt *time.Time) error {
+	query := `
+		UPDATE integration_deliveries
+		SET attempts = attempts + 1, last_error = $2,
+			status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			next_attempt_at = COALESCE($3, next_attempt_at)
+		WHERE id = $1
+	`
+	if _, err := r.pool.Exec(ctx, query, id, lastError, retryAt); err != nil {
+		return fmt.Errorf("failed to record delivery failure: %w", err)
+	}
+	return nil
+}
+
+// DeleteFinishedBefore removes delivered and failed deliveries created before the given time
+func (r *IntegrationRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM integration_deliveries WHERE status <> 'pending' AND created_at < $1`
+	result, err := r.pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete finished deliveries: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,1995 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"devjournal/internal/database"
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/internal/testenv"
+	"devjournal/pkg/apperr"
+)
+
+var env *testenv.Env
+
+func TestMain(m *testing.M) {
+	testenv.Main(m, &env)
+}
+
+func TestUserRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewUserRepository(env.Pool)
+
+	user := domain.NewUser("ada@devjournal.test", "hash", "Ada")
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	found, err := repo.FindByEmail(ctx, "ada@devjournal.test")
+	if err != nil || found == nil || found.ID != user.ID {
+		t.Fatalf("FindByEmail = %v, %v; want user %s", found, err, user.ID)
+	}
+
+	missing, err := repo.FindByEmail(ctx, "nobody@devjournal.test")
+	if err != nil || missing != nil {
+		t.Fatalf("FindByEmail(missing) = %v, %v; want nil, nil", missing, err)
+	}
+
+	if err := repo.Create(ctx, domain.NewUser("ada@devjournal.test", "hash", "Ada again")); err == nil {
+		t.Fatal("Create with a duplicate email succeeded")
+	}
+
+	grace := domain.NewUser("grace@devjournal.test", "hash", "Grace")
+	if err := repo.CreateWithWorkspace(ctx, grace, domain.NewPersonalWorkspace(grace)); err != nil {
+		t.Fatalf("CreateWithWorkspace: %v", err)
+	}
+	if role, err := postgres.NewWorkspaceRepository(env.Pool).GetMemberRole(ctx, grace.ID, grace.ID); err != nil || role != domain.WorkspaceRoleOwner {
+		t.Fatalf("personal workspace role = %q, %v; want owner", role, err)
+	}
+
+	// A workspace that can't be created takes the user with it, so signing up again works
+	linus := domain.NewUser("linus@devjournal.test", "hash", "Linus")
+	clash := domain.NewPersonalWorkspace(linus)
+	clash.Slug = "personal-" + grace.ID.String()
+	if err := repo.CreateWithWorkspace(ctx, linus, clash); err == nil {
+		t.Fatal("CreateWithWorkspace with a taken slug succeeded")
+	}
+	if found, err := repo.FindByEmail(ctx, "linus@devjournal.test"); err != nil || found != nil {
+		t.Fatalf("FindByEmail after a failed CreateWithWorkspace = %v, %v; want nil, nil", found, err)
+	}
+}
+
+func TestJournalRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewJournalRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+	other := env.CreateUser(t, "Other")
+
+	entry := env.CreateEntry(t, owner, "Goroutines", "go")
+	env.CreateEntry(t, owner, "Channels", "go", "concurrency")
+	env.CreateEntry(t, other, "Not mine")
+
+	found, err := repo.FindByID(ctx, entry.ID)
+	if err != nil || found == nil || found.Title != "Goroutines" {
+		t.Fatalf("FindByID = %v, %v; want the Goroutines entry", found, err)
+	}
+
+	entries, err := repo.FindByUserID(ctx, owner.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("FindByUserID: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("FindByUserID returned %d entries, want 2", len(entries))
+	}
+
+	count, err := repo.Count(ctx, owner.ID)
+	if err != nil || count != 2 {
+		t.Fatalf("Count = %d, %v; want 2", count, err)
+	}
+
+	tagged, err := repo.FindByTags(ctx, owner.ID, []string{"concurrency"}, domain.TagMatchAny, 10, 0)
+	if err != nil || len(tagged) != 1 || tagged[0].Title != "Channels" {
+		t.Fatalf("FindByTags = %v, %v; want only Channels", tagged, err)
+	}
+
+	results, err := repo.Search(ctx, owner.ID, "gorout", false, 10, 0)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Search = %v, %v; want one match", results, err)
+	}
+
+	// Vault entries are left out before paging, so a locked search still fills its pages
+	sealed := domain.NewJournalEntry(owner.ID, "Goroutine leak at work", "Private notes", "stressed", nil)
+	sealed.IsVault = true
+	if err := repo.Create(ctx, sealed); err != nil {
+		t.Fatalf("Create vault entry: %v", err)
+	}
+	if results, err := repo.Search(ctx, owner.ID, "gorout", false, 1, 0); err != nil || len(results) != 1 || results[0].IsVault {
+		t.Fatalf("locked Search = %v, %v; want only the non-vault match", results, err)
+	}
+	if results, err := repo.Search(ctx, owner.ID, "gorout", true, 10, 0); err != nil || len(results) != 2 {
+		t.Fatalf("unlocked Search = %v, %v; want both matches", results, err)
+	}
+	if err := repo.Delete(ctx, sealed.ID, owner.ID); err != nil {
+		t.Fatalf("Delete vault entry: %v", err)
+	}
+
+	entry.Title = "Goroutines, revisited"
+	entry.UpdatedAt = time.Now().UTC()
+	if err := repo.Update(ctx, entry); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	if err := repo.Delete(ctx, entry.ID, other.ID); !errors.Is(err, apperr.ErrNotFound) {
+		t.Fatalf("Delete by another user = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(ctx, entry.ID, owner.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if found, _ := repo.FindByID(ctx, entry.ID); found != nil {
+		t.Fatal("entry still exists after Delete")
+	}
+}
+
+func TestTILRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewTILRepository(env.Pool)
+	user := env.CreateUser(t, "Learner")
+
+	til := domain.NewTILEntry(user.ID, "TIL: range over int", []string{"go"})
+	if err := repo.Create(ctx, til); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tils, err := repo.FindByUserID(ctx, user.ID, 10, 0)
+	if err != nil || len(tils) != 1 {
+		t.Fatalf("FindByUserID = %v, %v; want one entry", tils, err)
+	}
+
+	if err := repo.Delete(ctx, uuid.New(), user.ID); !errors.Is(err, apperr.ErrNotFound) {
+		t.Fatalf("Delete(missing) = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(ctx, til.ID, user.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+}
+
+func TestStudyGroupRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewStudyGroupRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+	member := env.CreateUser(t, "Member")
+
+	group := env.CreateGroup(t, owner, "Go study group")
+
+	role, err := repo.GetMemberRole(ctx, group.ID, owner.ID)
+	if err != nil || role != "owner" {
+		t.Fatalf("creator role = %q, %v; want owner", role, err)
+	}
+
+	if err := repo.AddMember(ctx, &domain.StudyGroupMember{GroupID: group.ID, UserID: member.ID, Role: "member", JoinedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+	count, err := repo.GetMemberCount(ctx, group.ID)
+	if err != nil || count != 2 {
+		t.Fatalf("GetMemberCount = %d, %v; want 2", count, err)
+	}
+
+	missing, err := repo.FindByID(ctx, uuid.New())
+	if err != nil || missing != nil {
+		t.Fatalf("FindByID(missing) = %v, %v; want nil, nil", missing, err)
+	}
+
+	// Inactive groups are warned, then archived and hidden from discovery
+	later := group.LastActiveAt.Add(time.Hour).Truncate(time.Microsecond)
+	inactive, err := repo.ListInactive(ctx, later, false, time.Time{}, 10)
+	if err != nil || len(inactive) != 1 {
+		t.Fatalf("ListInactive(unwarned) = %v, %v; want the group", inactive, err)
+	}
+	lastActive := inactive[0].LastActiveAt
+	if marked, err := repo.MarkArchiveWarned(ctx, group.ID, lastActive, later); err != nil || !marked {
+		t.Fatalf("MarkArchiveWarned = %v, %v", marked, err)
+	}
+	if inactive, err := repo.ListInactive(ctx, later, false, time.Time{}, 10); err != nil || len(inactive) != 0 {
+		t.Fatalf("ListInactive(unwarned) after warning = %v, %v; want none", inactive, err)
+	}
+	if inactive, err := repo.ListInactive(ctx, later, true, later, 10); err != nil || len(inactive) != 0 {
+		t.Fatalf("ListInactive(warned before the warning) = %v, %v; want none", inactive, err)
+	}
+	if inactive, err := repo.ListInactive(ctx, later, true, later.Add(time.Minute), 10); err != nil || len(inactive) != 1 {
+		t.Fatalf("ListInactive(warned) = %v, %v; want the group", inactive, err)
+	}
+	if archived, err := repo.Archive(ctx, group.ID, lastActive.Add(-time.Second), later); err != nil || archived {
+		t.Fatalf("Archive after new activity = %v, %v; want false", archived, err)
+	}
+	if archived, err := repo.Archive(ctx, group.ID, lastActive, later); err != nil || !archived {
+		t.Fatalf("Archive = %v, %v", archived, err)
+	}
+	if archived, err := repo.IsArchived(ctx, group.ID); err != nil || !archived {
+		t.Fatalf("IsArchived = %v, %v; want true", archived, err)
+	}
+	public, err := repo.ListPublic(ctx, 10, 0)
+	if err != nil || len(public) != 0 {
+		t.Fatalf("ListPublic with the group archived = %v, %v; want none", public, err)
+	}
+	if err := repo.RecordActivity(ctx, map[uuid.UUID]time.Time{group.ID: later}); err != nil {
+		t.Fatalf("RecordActivity: %v", err)
+	}
+	if found, err := repo.FindByID(ctx, group.ID); err != nil || !found.Archived() || !found.LastActiveAt.Equal(lastActive) {
+		t.Fatalf("archived group after activity = %+v, %v; want it left alone", found, err)
+	}
+	if err := repo.Reactivate(ctx, group.ID, later); err != nil {
+		t.Fatalf("Reactivate: %v", err)
+	}
+	if count, err := repo.CountPublic(ctx); err != nil || count != 1 {
+		t.Fatalf("CountPublic after reactivating = %d, %v; want 1", count, err)
+	}
+	if err := repo.RecordActivity(ctx, map[uuid.UUID]time.Time{group.ID: later.Add(time.Hour)}); err != nil {
+		t.Fatalf("RecordActivity: %v", err)
+	}
+	found, err := repo.FindByID(ctx, group.ID)
+	if err != nil || found.Archived() || found.ArchiveWarnedAt != nil || !found.LastActiveAt.Equal(later.Add(time.Hour)) {
+		t.Fatalf("reactivated group after activity = %+v, %v", found, err)
+	}
+
+	if err := repo.Delete(ctx, group.ID, member.ID); !errors.Is(err, apperr.ErrNotFound) {
+		t.Fatalf("Delete by a non-owner = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(ctx, group.ID, owner.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+}
+
+func TestWorkspaceRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewWorkspaceRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+	member := env.CreateUser(t, "Member")
+
+	ws := domain.NewWorkspace("Team", "team", owner.ID)
+	if err := repo.Create(ctx, ws); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if exists, err := repo.SlugExists(ctx, "team"); err != nil || !exists {
+		t.Fatalf("SlugExists = %v, %v; want true", exists, err)
+	}
+
+	if err := repo.AddMember(ctx, &domain.WorkspaceMember{WorkspaceID: ws.ID, UserID: member.ID, Role: domain.WorkspaceRoleMember, JoinedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+
+	workspaces, err := repo.ListForUser(ctx, member.ID)
+	if err != nil {
+		t.Fatalf("ListForUser: %v", err)
+	}
+	if len(workspaces) != 2 {
+		t.Fatalf("member sees %d workspaces, want personal + team", len(workspaces))
+	}
+
+	if err := repo.RemoveMember(ctx, ws.ID, member.ID); err != nil {
+		t.Fatalf("RemoveMember: %v", err)
+	}
+	if role, err := repo.GetMemberRole(ctx, ws.ID, member.ID); err != nil || role != "" {
+		t.Fatalf("role after RemoveMember = %q, %v; want none", role, err)
+	}
+}
+
+func TestProgressRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewProgressRepository(env.Pool)
+	user := env.CreateUser(t, "Streaker")
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for day := 0; day < 3; day++ {
+		progress := domain.NewLearningProgress(user.ID, today.AddDate(0, 0, -day))
+		progress.EntriesCount = 1
+		if err := repo.Upsert(ctx, progress); err != nil {
+			t.Fatalf("Upsert: %v", err)
+		}
+	}
+
+	streak, err := repo.CalculateStreak(ctx, user.ID)
+	if err != nil || streak != 3 {
+		t.Fatalf("CalculateStreak = %d, %v; want 3", streak, err)
+	}
+
+	// Buckets sum only the days within the range, leaving out periods without progress
+	buckets, err := repo.Buckets(ctx, user.ID, today.AddDate(0, 0, -1), today, domain.StatsIntervalDay)
+	if err != nil || len(buckets) != 2 || !buckets[1].PeriodStart.Equal(today) || buckets[1].EntriesCount != 1 || buckets[1].ActiveDays != 1 {
+		t.Fatalf("Buckets by day = %+v, %v; want yesterday and today", buckets, err)
+	}
+	since := today.AddDate(0, 0, -2)
+	buckets, err = repo.Buckets(ctx, user.ID, since, today, domain.StatsIntervalMonth)
+	entries := 0
+	for _, b := range buckets {
+		entries += b.EntriesCount
+		if b.PeriodStart.Day() != 1 {
+			t.Fatalf("Buckets by month start on %s", b.PeriodStart)
+		}
+	}
+	if err != nil || entries != 3 {
+		t.Fatalf("Buckets by month = %+v, %v; want 3 entries", buckets, err)
+	}
+
+	// Streaks count what the user's settings ask for: only today has 30 minutes of learning time
+	settingsRepo := postgres.NewSettingsRepository(env.Pool)
+	settings := domain.NewUserSettings(user.ID)
+	settings.StreakCounts = domain.StreakCountsMinutes
+	settings.StreakMinutes = 30
+	if err := settingsRepo.Upsert(ctx, settings); err != nil {
+		t.Fatalf("Upsert settings: %v", err)
+	}
+	if err := repo.AddLearningTime(ctx, user.ID, today, 45); err != nil {
+		t.Fatalf("AddLearningTime: %v", err)
+	}
+	if streak, err := repo.CalculateStreak(ctx, user.ID); err != nil || streak != 1 {
+		t.Fatalf("CalculateStreak counting minutes = %d, %v; want 1", streak, err)
+	}
+	settings.StreakCounts = domain.StreakCountsSnippets
+	if err := settingsRepo.Upsert(ctx, settings); err != nil {
+		t.Fatalf("Upsert settings: %v", err)
+	}
+	if streak, err := repo.CalculateStreak(ctx, user.ID); err != nil || streak != 0 {
+		t.Fatalf("CalculateStreak counting snippets = %d, %v; want 0", streak, err)
+	}
+
+	// Achievements are only awarded once
+	kinds := []string{domain.StreakAchievement(7), domain.StreakAchievement(30)}
+	if awarded, err := repo.AwardAchievements(ctx, user.ID, kinds[:1], time.Now().UTC()); err != nil || len(awarded) != 1 {
+		t.Fatalf("AwardAchievements = %v, %v; want streak_7", awarded, err)
+	}
+	if awarded, err := repo.AwardAchievements(ctx, user.ID, kinds, time.Now().UTC()); err != nil || len(awarded) != 1 || awarded[0] != kinds[1] {
+		t.Fatalf("AwardAchievements again = %v, %v; want only streak_30", awarded, err)
+	}
+	if achievements, err := repo.ListAchievements(ctx, user.ID); err != nil || len(achievements) != 2 {
+		t.Fatalf("ListAchievements = %+v, %v; want 2", achievements, err)
+	}
+
+	// Group mates who share their progress can be compared
+	mate := env.CreateUser(t, "Mate")
+	private := env.CreateUser(t, "Private")
+	group := env.CreateGroup(t, user, "Streakers")
+	groupRepo := postgres.NewStudyGroupRepository(env.Pool)
+	for _, member := range []*domain.User{mate, private} {
+		if err := groupRepo.AddMember(ctx, &domain.StudyGroupMember{GroupID: group.ID, UserID: member.ID, Role: "member", JoinedAt: time.Now().UTC()}); err != nil {
+			t.Fatalf("AddMember: %v", err)
+		}
+	}
+	for _, sharer := range []*domain.User{user, mate} {
+		settings := domain.NewUserSettings(sharer.ID)
+		settings.ShareProgress = true
+		if err := settingsRepo.Upsert(ctx, settings); err != nil {
+			t.Fatalf("Upsert settings: %v", err)
+		}
+	}
+	if shares, err := repo.SharesProgress(ctx, private.ID); err != nil || shares {
+		t.Fatalf("SharesProgress(private) = %v, %v; want false", shares, err)
+	}
+	friends, err := repo.ComparableUsers(ctx, user.ID, []uuid.UUID{mate.ID, private.ID, user.ID})
+	if err != nil || len(friends) != 1 || friends[0] != mate.ID {
+		t.Fatalf("ComparableUsers = %v, %v; want the mate", friends, err)
+	}
+	activity, err := repo.CompareActivity(ctx, []uuid.UUID{user.ID, mate.ID}, today.AddDate(0, 0, -1))
+	if err != nil || len(activity) != 2 {
+		t.Fatalf("CompareActivity = %+v, %v", activity, err)
+	}
+	for _, a := range activity {
+		want := 0
+		if a.UserID == user.ID {
+			want = 2
+		}
+		if a.ActiveDays != want || a.Entries != want {
+			t.Fatalf("CompareActivity(%s) = %+v, want %d active days and entries", a.UserID, a, want)
+		}
+	}
+}
+
+func TestIntegrationRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewIntegrationRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+	group := env.CreateGroup(t, owner, "Go study group")
+
+	now := time.Now().UTC()
+	for _, provider := range []string{domain.IntegrationSlack, domain.IntegrationDiscord} {
+		integ := &domain.GroupIntegration{
+			ID: uuid.New(), GroupID: group.ID, Provider: provider, ChannelID: "C1",
+			WebhookURL: "https://hooks.devjournal.test/" + provider, Enabled: true,
+			CreatedBy: owner.ID, CreatedAt: now, UpdatedAt: now,
+		}
+		if err := repo.Upsert(ctx, integ); err != nil {
+			t.Fatalf("Upsert(%s): %v", provider, err)
+		}
+	}
+
+	payloads := map[string][]byte{
+		domain.IntegrationSlack:   []byte(`{"text":"hi"}`),
+		domain.IntegrationDiscord: []byte(`{"content":"hi"}`),
+	}
+	queued, err := repo.Enqueue(ctx, group.ID, "msg-1", domain.IntegrationSlack, payloads)
+	if err != nil || queued != 1 {
+		t.Fatalf("Enqueue skipping slack = %d, %v; want 1", queued, err)
+	}
+
+	claimed, err := repo.ClaimDue(ctx, time.Now().UTC(), time.Minute, 10)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimDue = %d deliveries, %v; want 1", len(claimed), err)
+	}
+	if claimed[0].WebhookURL != "https://hooks.devjournal.test/discord" || string(claimed[0].Payload) != `{"content": "hi"}` {
+		t.Fatalf("claimed delivery = %s %s, want the discord payload", claimed[0].WebhookURL, claimed[0].Payload)
+	}
+
+	// The lease hides a claimed delivery from other workers
+	again, err := repo.ClaimDue(ctx, time.Now().UTC(), time.Minute, 10)
+	if err != nil || len(again) != 0 {
+		t.Fatalf("ClaimDue during lease = %d deliveries, %v; want 0", len(again), err)
+	}
+
+	retryAt := time.Now().UTC().Add(-time.Second)
+	if err := repo.MarkAttemptFailed(ctx, claimed[0].ID, "boom", &retryAt); err != nil {
+		t.Fatalf("MarkAttemptFailed: %v", err)
+	}
+	retried, err := repo.ClaimDue(ctx, time.Now().UTC(), time.Minute, 10)
+	if err != nil || len(retried) != 1 || retried[0].Attempts != 1 || retried[0].LastError != "boom" {
+		t.Fatalf("ClaimDue after failure = %+v, %v; want one retry with 1 attempt", retried, err)
+	}
+
+	if err := repo.MarkAttemptFailed(ctx, claimed[0].ID, "gone", nil); err != nil {
+		t.Fatalf("MarkAttemptFailed(give up): %v", err)
+	}
+	if exhausted, _ := repo.ClaimDue(ctx, time.Now().UTC().Add(time.Hour), time.Minute, 10); len(exhausted) != 0 {
+		t.Fatalf("ClaimDue after giving up = %d deliveries, want 0", len(exhausted))
+	}
+
+	deleted, err := repo.Delete(ctx, group.ID, domain.IntegrationDiscord)
+	if err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v; want true", deleted, err)
+	}
+}
+
+func TestPushRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewPushRepository(env.Pool)
+	progressRepo := postgres.NewProgressRepository(env.Pool)
+	first := env.CreateUser(t, "First")
+	second := env.CreateUser(t, "Second")
+
+	now := time.Now().UTC()
+	device := &domain.PushDevice{ID: uuid.New(), UserID: first.ID, Platform: domain.PushPlatformIOS, Token: "tok-1", CreatedAt: now, UpdatedAt: now}
+	if err := repo.Register(ctx, device); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	// The same token signed in to another account moves to it
+	moved := &domain.PushDevice{ID: uuid.New(), UserID: second.ID, Platform: domain.PushPlatformIOS, Token: "tok-1", CreatedAt: now, UpdatedAt: now}
+	if err := repo.Register(ctx, moved); err != nil || moved.ID != device.ID {
+		t.Fatalf("Register existing token = %s, %v; want reused ID %s", moved.ID, err, device.ID)
+	}
+	if devices, err := repo.ListByUser(ctx, first.ID); err != nil || len(devices) != 0 {
+		t.Fatalf("ListByUser(first) = %d devices, %v; want 0", len(devices), err)
+	}
+
+	// Active yesterday, not today: due a reminder once
+	today := now.Truncate(24 * time.Hour)
+	yesterday := domain.NewLearningProgress(second.ID, today.AddDate(0, 0, -1))
+	yesterday.EntriesCount = 1
+	yesterday.StreakDays = 4
+	if err := progressRepo.Upsert(ctx, yesterday); err != nil {
+		t.Fatalf("Upsert progress: %v", err)
+	}
+	reminders, err := repo.ListStreakReminders(ctx, today, 10)
+	if err != nil || len(reminders) != 1 || reminders[0].UserID != second.ID || reminders[0].StreakDays != 4 {
+		t.Fatalf("ListStreakReminders = %+v, %v; want one for the second user", reminders, err)
+	}
+
+	// Not due one when the user only counts snippets, since yesterday's entry didn't count
+	settingsRepo := postgres.NewSettingsRepository(env.Pool)
+	settings := domain.NewUserSettings(second.ID)
+	settings.StreakCounts = domain.StreakCountsSnippets
+	if err := settingsRepo.Upsert(ctx, settings); err != nil {
+		t.Fatalf("Upsert settings: %v", err)
+	}
+	if reminders, _ := repo.ListStreakReminders(ctx, today, 10); len(reminders) != 0 {
+		t.Fatalf("ListStreakReminders counting snippets = %d, want 0", len(reminders))
+	}
+	settings.StreakCounts = domain.StreakCountsEntries
+	if err := settingsRepo.Upsert(ctx, settings); err != nil {
+		t.Fatalf("Upsert settings: %v", err)
+	}
+	if reminders, _ := repo.ListStreakReminders(ctx, today, 10); len(reminders) != 1 || reminders[0].StreakCounts != domain.StreakCountsEntries {
+		t.Fatalf("ListStreakReminders counting entries = %+v, want one", reminders)
+	}
+	if err := repo.MarkReminded(ctx, second.ID, today); err != nil {
+		t.Fatalf("MarkReminded: %v", err)
+	}
+	if reminders, _ := repo.ListStreakReminders(ctx, today, 10); len(reminders) != 0 {
+		t.Fatalf("ListStreakReminders after reminding = %d, want 0", len(reminders))
+	}
+
+	if err := repo.DeleteToken(ctx, "tok-1"); err != nil {
+		t.Fatalf("DeleteToken: %v", err)
+	}
+	if deleted, err := repo.Delete(ctx, second.ID, device.ID); err != nil || deleted {
+		t.Fatalf("Delete after DeleteToken = %v, %v; want false", deleted, err)
+	}
+}
+
+func TestMentionRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewMentionRepository(env.Pool)
+	author := env.CreateUser(t, "Ada Lovelace")
+	reader := env.CreateUser(t, "Grace Hopper")
+	group := env.CreateGroup(t, author, "Go study group")
+
+	now := time.Now().UTC()
+	mention := domain.Mention{
+		ID: uuid.New(), UserID: reader.ID, AuthorID: author.ID, SourceType: domain.MentionSourceChat,
+		SourceID: "msg-1", ContextID: group.ID, ContextName: group.Name, Excerpt: "hi @gracehopper", CreatedAt: now,
+	}
+	created, err := repo.Create(ctx, []domain.Mention{mention})
+	if err != nil || len(created) != 1 {
+		t.Fatalf("Create = %d, %v; want 1", len(created), err)
+	}
+
+	// The same source mentioning the same user again is not a new mention
+	again := mention
+	again.ID = uuid.New()
+	if created, err := repo.Create(ctx, []domain.Mention{again}); err != nil || len(created) != 0 {
+		t.Fatalf("Create duplicate = %d, %v; want 0", len(created), err)
+	}
+
+	mentions, total, err := repo.ListByUser(ctx, reader.ID, true, 10, 0)
+	if err != nil || total != 1 || len(mentions) != 1 || mentions[0].AuthorName != "Ada Lovelace" {
+		t.Fatalf("ListByUser(unread) = %+v, %d, %v; want one mention by Ada Lovelace", mentions, total, err)
+	}
+
+	if updated, err := repo.MarkRead(ctx, reader.ID, nil, now); err != nil || updated != 1 {
+		t.Fatalf("MarkRead(all) = %d, %v; want 1", updated, err)
+	}
+	if unread, err := repo.CountUnread(ctx, reader.ID); err != nil || unread != 0 {
+		t.Fatalf("CountUnread = %d, %v; want 0", unread, err)
+	}
+	if _, total, _ := repo.ListByUser(ctx, reader.ID, false, 10, 0); total != 1 {
+		t.Fatalf("ListByUser(all) total = %d, want 1", total)
+	}
+}
+
+func TestFollowRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewFollowRepository(env.Pool)
+	ada := env.CreateUser(t, "Ada Lovelace")
+	grace := env.CreateUser(t, "Grace Hopper")
+
+	// Following twice is a no-op
+	for i := 0; i < 2; i++ {
+		if err := repo.Follow(ctx, ada.ID, grace.ID); err != nil {
+			t.Fatalf("Follow: %v", err)
+		}
+	}
+	if following, err := repo.IsFollowing(ctx, ada.ID, grace.ID); err != nil || !following {
+		t.Fatalf("IsFollowing = %v, %v; want true", following, err)
+	}
+	if followers, following, err := repo.Counts(ctx, grace.ID); err != nil || followers != 1 || following != 0 {
+		t.Fatalf("Counts = %d, %d, %v; want 1, 0", followers, following, err)
+	}
+	users, err := repo.ListFollowers(ctx, grace.ID, 10, 0)
+	if err != nil || len(users) != 1 || users[0].DisplayName != "Ada Lovelace" {
+		t.Fatalf("ListFollowers = %+v, %v; want Ada Lovelace", users, err)
+	}
+
+	if removed, err := repo.Unfollow(ctx, ada.ID, grace.ID); err != nil || !removed {
+		t.Fatalf("Unfollow = %v, %v; want true", removed, err)
+	}
+	if removed, err := repo.Unfollow(ctx, ada.ID, grace.ID); err != nil || removed {
+		t.Fatalf("Unfollow again = %v, %v; want false", removed, err)
+	}
+}
+
+func TestEntrySnippetRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewEntrySnippetRepository(env.Pool)
+	ada := env.CreateUser(t, "Ada Lovelace")
+	entry := env.CreateEntry(t, ada, "Goroutines")
+	const snippetID = "6650f1c2a4b3c2d1e0f9a8b7"
+
+	// Linking twice is a no-op
+	for i := 0; i < 2; i++ {
+		if err := repo.Link(ctx, entry.ID, snippetID, ada.ID); err != nil {
+			t.Fatalf("Link: %v", err)
+		}
+	}
+	links, err := repo.SnippetLinks(ctx, entry.ID)
+	if err != nil || len(links) != 1 || links[0].ID != snippetID {
+		t.Fatalf("SnippetLinks = %+v, %v; want %s", links, err, snippetID)
+	}
+	entries, err := repo.RelatedEntries(ctx, snippetID, ada.ID)
+	if err != nil || len(entries) != 1 || entries[0].Title != "Goroutines" {
+		t.Fatalf("RelatedEntries = %+v, %v; want Goroutines", entries, err)
+	}
+
+	// Merging moves links, keeping one per entry
+	const mergedID = "6650f1c2a4b3c2d1e0f9a8b8"
+	if err := repo.Link(ctx, entry.ID, mergedID, ada.ID); err != nil {
+		t.Fatalf("Link: %v", err)
+	}
+	if err := repo.MoveToSnippet(ctx, snippetID, mergedID); err != nil {
+		t.Fatalf("MoveToSnippet: %v", err)
+	}
+	if links, err := repo.SnippetLinks(ctx, entry.ID); err != nil || len(links) != 1 || links[0].ID != mergedID {
+		t.Fatalf("SnippetLinks after MoveToSnippet = %+v, %v; want %s", links, err, mergedID)
+	}
+	if err := repo.Link(ctx, entry.ID, snippetID, ada.ID); err != nil {
+		t.Fatalf("Link: %v", err)
+	}
+	if err := repo.DeleteBySnippet(ctx, mergedID); err != nil {
+		t.Fatalf("DeleteBySnippet: %v", err)
+	}
+
+	if err := repo.DeleteBySnippet(ctx, snippetID); err != nil {
+		t.Fatalf("DeleteBySnippet: %v", err)
+	}
+	if removed, err := repo.Unlink(ctx, entry.ID, snippetID); err != nil || removed {
+		t.Fatalf("Unlink after DeleteBySnippet = %v, %v; want false", removed, err)
+	}
+
+	// Deleting the entry removes its links
+	if err := repo.Link(ctx, entry.ID, snippetID, ada.ID); err != nil {
+		t.Fatalf("Link: %v", err)
+	}
+	if err := postgres.NewJournalRepository(env.Pool).Delete(ctx, entry.ID, ada.ID); err != nil {
+		t.Fatalf("Delete entry: %v", err)
+	}
+	if entries, err := repo.RelatedEntries(ctx, snippetID, ada.ID); err != nil || len(entries) != 0 {
+		t.Fatalf("RelatedEntries after deleting the entry = %+v, %v; want none", entries, err)
+	}
+}
+
+func TestEntrySnippetRepositoryNearestReads(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	ada := env.CreateUser(t, "Ada Lovelace")
+	entry := env.CreateEntry(t, ada, "Goroutines")
+	const snippetID = "6650f1c2a4b3c2d1e0f9a8b7"
+
+	// The test database stands in for a replica of itself
+	nearest, err := database.NewNearestPool(ctx, env.Pool, []string{env.Pool.Config().ConnString()})
+	if err != nil {
+		t.Fatalf("NewNearestPool: %v", err)
+	}
+	defer nearest.Close()
+	if err := nearest.Measurer()(ctx); err != nil {
+		t.Fatalf("Measurer: %v", err)
+	}
+
+	repo := postgres.NewEntrySnippetRepository(env.Pool).ReadNearest(nearest)
+	if err := repo.Link(ctx, entry.ID, snippetID, ada.ID); err != nil {
+		t.Fatalf("Link: %v", err)
+	}
+	if links, err := repo.SnippetLinks(ctx, entry.ID); err != nil || len(links) != 1 {
+		t.Fatalf("SnippetLinks = %+v, %v; want one", links, err)
+	}
+	if entries, err := repo.RelatedEntries(ctx, snippetID, ada.ID); err != nil || len(entries) != 1 {
+		t.Fatalf("RelatedEntries = %+v, %v; want one", entries, err)
+	}
+}
+
+func TestProjectRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewProjectRepository(env.Pool)
+	journalRepo := postgres.NewJournalRepository(env.Pool)
+	ada := env.CreateUser(t, "Ada Lovelace")
+	entry := env.CreateEntry(t, ada, "Analytical engine notes")
+
+	project := domain.NewProject(ada.ID, "Analytical engine", "")
+	if err := repo.Create(ctx, project); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if found, err := journalRepo.SetProject(ctx, entry.ID, ada.ID, &project.ID); err != nil || !found {
+		t.Fatalf("SetProject = %v, %v; want true", found, err)
+	}
+	started := time.Now().UTC().Add(-time.Hour)
+	focus := &domain.FocusSession{ID: uuid.New(), UserID: ada.ID, ProjectID: project.ID, StartedAt: started, Minutes: 45, CreatedAt: time.Now().UTC()}
+	if err := repo.CreateFocus(ctx, focus); err != nil {
+		t.Fatalf("CreateFocus: %v", err)
+	}
+
+	stats, err := repo.Stats(ctx, project.ID)
+	if err != nil || stats.Entries != 1 || stats.FocusMinutes != 45 {
+		t.Fatalf("Stats = %+v, %v; want 1 entry and 45 minutes", stats, err)
+	}
+	entries, err := journalRepo.FindByProject(ctx, project.ID, time.Now().UTC(), 10)
+	if err != nil || len(entries) != 1 || *entries[0].ProjectID != project.ID {
+		t.Fatalf("FindByProject = %+v, %v; want the attached entry", entries, err)
+	}
+	if sessions, err := repo.FindFocus(ctx, project.ID, started, 10); err != nil || len(sessions) != 0 {
+		t.Fatalf("FindFocus before the session = %+v, %v; want none", sessions, err)
+	}
+
+	projects, total, err := repo.ListByUser(ctx, ada.ID, domain.ProjectActive, 10, 0)
+	if err != nil || total != 1 || len(projects) != 1 {
+		t.Fatalf("ListByUser = %+v, %d, %v; want the project", projects, total, err)
+	}
+	if _, total, err := repo.ListByUser(ctx, ada.ID, domain.ProjectArchived, 10, 0); err != nil || total != 0 {
+		t.Fatalf("ListByUser(archived) total = %d, %v; want 0", total, err)
+	}
+
+	// Deleting the project detaches its entries and removes its focus sessions
+	if err := repo.Delete(ctx, project.ID, ada.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	found, err := journalRepo.FindByID(ctx, entry.ID)
+	if err != nil || found == nil || found.ProjectID != nil {
+		t.Fatalf("entry after deleting the project = %+v, %v; want it detached", found, err)
+	}
+	if deleted, err := repo.DeleteFocus(ctx, focus.ID, project.ID); err != nil || deleted != nil {
+		t.Fatalf("DeleteFocus after deleting the project = %+v, %v; want nil", deleted, err)
+	}
+}
+
+func TestLearningPathRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewLearningPathRepository(env.Pool)
+	groupRepo := postgres.NewStudyGroupRepository(env.Pool)
+	ada := env.CreateUser(t, "Ada Lovelace")
+	grace := env.CreateUser(t, "Grace Hopper")
+	group := env.CreateGroup(t, ada, "Compilers")
+	if err := groupRepo.AddMember(ctx, &domain.StudyGroupMember{GroupID: group.ID, UserID: grace.ID, Role: "member", JoinedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+
+	path := domain.NewLearningPath(ada.ID, "Compilers", "")
+	for i, title := range []string{"Lexing", "Parsing", "Codegen"} {
+		path.Topics = append(path.Topics, domain.PathTopic{ID: uuid.New(), Position: i, Title: title})
+	}
+	if err := repo.Create(ctx, path); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Grace can only see the path once it is shared into her group
+	if _, total, err := repo.ListAccessible(ctx, grace.ID, 10, 0); err != nil || total != 0 {
+		t.Fatalf("ListAccessible before sharing total = %d, %v; want 0", total, err)
+	}
+	if err := repo.SetGroup(ctx, path.ID, &group.ID); err != nil {
+		t.Fatalf("SetGroup: %v", err)
+	}
+	paths, total, err := repo.ListAccessible(ctx, grace.ID, 10, 0)
+	if err != nil || total != 1 || paths[0].Progress.Total != 3 {
+		t.Fatalf("ListAccessible after sharing = %+v, %d, %v; want the path with 3 topics", paths, total, err)
+	}
+
+	// Progress is per user, and evidence resolves entry titles
+	entry := env.CreateEntry(t, grace, "Wrote a tokenizer")
+	lexing := path.Topics[0].ID
+	if err := repo.LinkEvidence(ctx, lexing, grace.ID, domain.EvidenceEntry, entry.ID.String()); err != nil {
+		t.Fatalf("LinkEvidence: %v", err)
+	}
+	if err := repo.Complete(ctx, lexing, grace.ID, time.Now().UTC()); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	followed, err := repo.ListFollowed(ctx, grace.ID, 10)
+	if err != nil || len(followed) != 1 || followed[0].Progress.Percent != 33 {
+		t.Fatalf("ListFollowed = %+v, %v; want the path at 33%%", followed, err)
+	}
+	if found, err := repo.FindByID(ctx, path.ID, ada.ID); err != nil || found.Progress.Completed != 0 {
+		t.Fatalf("owner progress = %+v, %v; want nothing completed", found, err)
+	}
+	evidence, err := repo.Evidence(ctx, path.ID, grace.ID)
+	if err != nil || len(evidence[lexing]) != 1 || evidence[lexing][0].Title != "Wrote a tokenizer" {
+		t.Fatalf("Evidence = %+v, %v; want the linked entry", evidence, err)
+	}
+
+	// Deleting a topic closes the gap in the order, and reordering rewrites positions
+	if err := repo.DeleteTopic(ctx, path.Topics[1].ID, path.ID); err != nil {
+		t.Fatalf("DeleteTopic: %v", err)
+	}
+	if err := repo.ReorderTopics(ctx, path.ID, []uuid.UUID{path.Topics[2].ID, lexing}); err != nil {
+		t.Fatalf("ReorderTopics: %v", err)
+	}
+	topics, err := repo.Topics(ctx, path.ID, grace.ID)
+	if err != nil || len(topics) != 2 || topics[0].Title != "Codegen" || topics[1].Position != 1 || topics[1].CompletedAt == nil {
+		t.Fatalf("Topics = %+v, %v; want Codegen then a completed Lexing", topics, err)
+	}
+}
+
+func TestQuizAttemptRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewQuizAttemptRepository(env.Pool)
+	groupRepo := postgres.NewStudyGroupRepository(env.Pool)
+	ada := env.CreateUser(t, "Ada Lovelace")
+	grace := env.CreateUser(t, "Grace Hopper")
+	group := env.CreateGroup(t, ada, "Compilers")
+	if err := groupRepo.AddMember(ctx, &domain.StudyGroupMember{GroupID: group.ID, UserID: grace.ID, Role: "member", JoinedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+
+	attempt := func(quizID string, user *domain.User, score int) {
+		t.Helper()
+		a := &domain.QuizAttempt{ID: uuid.New(), QuizID: quizID, GroupID: group.ID, UserID: user.ID, Score: score, Total: 5, CreatedAt: time.Now().UTC()}
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	// Only the best attempt at each quiz counts
+	attempt("quiz-one", ada, 2)
+	attempt("quiz-one", ada, 4)
+	attempt("quiz-one", grace, 5)
+	attempt("quiz-two", grace, 1)
+
+	board, err := repo.Leaderboard(ctx, group.ID, 10)
+	if err != nil || len(board) != 2 {
+		t.Fatalf("Leaderboard = %+v, %v; want 2 members", board, err)
+	}
+	if board[0].UserID != grace.ID || board[0].Points != 6 || board[0].Quizzes != 2 || board[1].Points != 4 || board[1].Rank != 2 {
+		t.Fatalf("Leaderboard = %+v; want Grace with 6 points over 2 quizzes, then Ada with 4", board)
+	}
+	if scores, err := repo.BestScores(ctx, group.ID, ada.ID); err != nil || scores["quiz-one"] != 4 {
+		t.Fatalf("BestScores = %v, %v; want 4 on quiz-one", scores, err)
+	}
+
+	// Deleting a quiz takes its points off the board, and members who left drop off it
+	if err := repo.DeleteByQuiz(ctx, "quiz-one"); err != nil {
+		t.Fatalf("DeleteByQuiz: %v", err)
+	}
+	if err := groupRepo.RemoveMember(ctx, group.ID, ada.ID); err != nil {
+		t.Fatalf("RemoveMember: %v", err)
+	}
+	board, err = repo.Leaderboard(ctx, group.ID, 10)
+	if err != nil || len(board) != 1 || board[0].Points != 1 {
+		t.Fatalf("Leaderboard after deleting quiz-one = %+v, %v; want Grace with 1 point", board, err)
+	}
+}
+
+func TestCodeReviewRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewCodeReviewRepository(env.Pool)
+	groupRepo := postgres.NewStudyGroupRepository(env.Pool)
+	ada := env.CreateUser(t, "Ada Lovelace")
+	grace := env.CreateUser(t, "Grace Hopper")
+	group := env.CreateGroup(t, ada, "Compilers")
+	if err := groupRepo.AddMember(ctx, &domain.StudyGroupMember{GroupID: group.ID, UserID: grace.ID, Role: "member", JoinedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+
+	now := time.Now().UTC()
+	review := &domain.CodeReview{
+		ID: uuid.New(), GroupID: group.ID, UserID: ada.ID, SnippetID: "snippet-one", Title: "Parser",
+		Files:  []domain.SnippetFile{{Name: "parse.go", Language: "go", Code: "package parse\n\nfunc Parse() {}\n"}},
+		Status: domain.CodeReviewOpen, CreatedAt: now, UpdatedAt: now,
+	}
+	if err := repo.Create(ctx, review); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	comment := &domain.CodeReviewComment{ID: uuid.New(), ReviewID: review.ID, UserID: grace.ID, File: "parse.go", Line: 3, Body: "Return an error", CreatedAt: now}
+	if err := repo.AddComment(ctx, comment); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	if err := repo.ResolveComment(ctx, comment.ID, &now); err != nil {
+		t.Fatalf("ResolveComment: %v", err)
+	}
+
+	got, err := repo.FindByID(ctx, review.ID, group.ID)
+	if err != nil || got == nil {
+		t.Fatalf("FindByID = %v, %v", got, err)
+	}
+	if got.AuthorName != "Ada Lovelace" || len(got.Files) != 1 || got.Files[0].Name != "parse.go" || got.CommentCount != 1 || got.OpenComments != 0 {
+		t.Fatalf("FindByID = %+v; want Ada's review of parse.go with 1 resolved comment", got)
+	}
+	if other, err := repo.FindByID(ctx, review.ID, uuid.New()); err != nil || other != nil {
+		t.Fatalf("FindByID in another group = %v, %v; want nil", other, err)
+	}
+	if comments, err := repo.Comments(ctx, review.ID); err != nil || len(comments) != 1 || comments[0].AuthorName != "Grace Hopper" || comments[0].ResolvedAt == nil {
+		t.Fatalf("Comments = %+v, %v; want Grace's resolved comment", comments, err)
+	}
+	if participants, err := repo.Participants(ctx, review.ID); err != nil || len(participants) != 2 {
+		t.Fatalf("Participants = %v, %v; want Ada and Grace", participants, err)
+	}
+
+	if err := repo.SetStatus(ctx, review.ID, domain.CodeReviewResolved, &now); err != nil {
+		t.Fatalf("SetStatus: %v", err)
+	}
+	if open, total, err := repo.ListByGroup(ctx, group.ID, domain.CodeReviewOpen, 10, 0); err != nil || len(open) != 0 || total != 0 {
+		t.Fatalf("ListByGroup(open) = %+v, %d, %v; want none", open, total, err)
+	}
+	reviews, total, err := repo.ListByGroup(ctx, group.ID, "", 10, 0)
+	if err != nil || total != 1 || reviews[0].Status != domain.CodeReviewResolved || reviews[0].Files != nil {
+		t.Fatalf("ListByGroup = %+v, %d, %v; want the resolved review without files", reviews, total, err)
+	}
+
+	// Members who left are no longer notified
+	if err := groupRepo.RemoveMember(ctx, group.ID, grace.ID); err != nil {
+		t.Fatalf("RemoveMember: %v", err)
+	}
+	if participants, err := repo.Participants(ctx, review.ID); err != nil || len(participants) != 1 || participants[0] != ada.ID {
+		t.Fatalf("Participants after Grace left = %v, %v; want Ada", participants, err)
+	}
+}
+
+func TestSnippetCommentRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewSnippetCommentRepository(env.Pool)
+	ada := env.CreateUser(t, "Ada Lovelace")
+	const snippetID = "6650f1c2a4b3c2d1e0f9a8b7"
+
+	now := time.Now().UTC()
+	comment := &domain.SnippetComment{ID: uuid.New(), SnippetID: snippetID, UserID: ada.ID, File: "main.go", StartLine: 2, EndLine: 3, Body: "Use a buffered channel", CreatedAt: now, UpdatedAt: now}
+	if err := repo.Create(ctx, comment); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	comment.StartLine, comment.EndLine = 4, 5
+	if err := repo.UpdateAnchors(ctx, []domain.SnippetComment{*comment}); err != nil {
+		t.Fatalf("UpdateAnchors: %v", err)
+	}
+	got, err := repo.FindByID(ctx, comment.ID, snippetID)
+	if err != nil || got == nil || got.StartLine != 4 || got.EndLine != 5 || got.AuthorName != "Ada Lovelace" || got.Outdated {
+		t.Fatalf("FindByID = %+v, %v; want Ada's comment on lines 4-5", got, err)
+	}
+
+	// Resolved comments are left out unless asked for
+	if err := repo.Resolve(ctx, comment.ID, &now); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if open, err := repo.ListBySnippet(ctx, snippetID, false); err != nil || len(open) != 0 {
+		t.Fatalf("ListBySnippet = %+v, %v; want none open", open, err)
+	}
+	if all, err := repo.ListBySnippet(ctx, snippetID, true); err != nil || len(all) != 1 || all[0].ResolvedAt == nil {
+		t.Fatalf("ListBySnippet(includeResolved) = %+v, %v; want the resolved comment", all, err)
+	}
+
+	// Merging moves comments and follows renamed files
+	const mergedID = "6650f1c2a4b3c2d1e0f9a8b8"
+	if err := repo.MoveToSnippet(ctx, snippetID, mergedID, map[string]string{"main.go": "main-2.go"}); err != nil {
+		t.Fatalf("MoveToSnippet: %v", err)
+	}
+	if got, err := repo.FindByID(ctx, comment.ID, mergedID); err != nil || got == nil || got.File != "main-2.go" {
+		t.Fatalf("FindByID after MoveToSnippet = %+v, %v; want the comment on main-2.go", got, err)
+	}
+	if err := repo.MoveToSnippet(ctx, mergedID, snippetID, nil); err != nil {
+		t.Fatalf("MoveToSnippet: %v", err)
+	}
+
+	if err := repo.DeleteBySnippet(ctx, snippetID); err != nil {
+		t.Fatalf("DeleteBySnippet: %v", err)
+	}
+	if got, err := repo.FindByID(ctx, comment.ID, snippetID); err != nil || got != nil {
+		t.Fatalf("FindByID after DeleteBySnippet = %+v, %v; want nil", got, err)
+	}
+}
+
+func TestImportRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewImportRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+
+	now := time.Now().UTC()
+	imp := &domain.Import{
+		ID: uuid.New(), UserID: owner.ID, WorkspaceID: owner.ID, Source: domain.ImportDayOne,
+		Status: domain.ImportPending, Archive: []byte(`{"entries":[]}`), CreatedAt: now, UpdatedAt: now,
+	}
+	if err := repo.Create(ctx, imp); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	claimed, err := repo.ClaimNext(ctx, time.Now().UTC(), time.Minute)
+	if err != nil || claimed == nil || claimed.ID != imp.ID || claimed.Status != domain.ImportRunning || string(claimed.Archive) != `{"entries":[]}` {
+		t.Fatalf("ClaimNext = %+v, %v; want the running import with its upload", claimed, err)
+	}
+	if again, err := repo.ClaimNext(ctx, time.Now().UTC(), time.Minute); err != nil || again != nil {
+		t.Fatalf("ClaimNext during lease = %+v, %v; want nil", again, err)
+	}
+
+	if err := repo.SetTotal(ctx, imp.ID, 3, 1); err != nil {
+		t.Fatalf("SetTotal: %v", err)
+	}
+	if err := repo.RecordProgress(ctx, imp.ID, true, time.Now().UTC().Add(-time.Second)); err != nil {
+		t.Fatalf("RecordProgress: %v", err)
+	}
+	if err := repo.RecordProgress(ctx, imp.ID, false, time.Now().UTC().Add(-time.Second)); err != nil {
+		t.Fatalf("RecordProgress(skipped): %v", err)
+	}
+
+	// An import whose lease ran out is resumed with its progress
+	resumed, err := repo.ClaimNext(ctx, time.Now().UTC(), time.Minute)
+	if err != nil || resumed == nil || resumed.Processed != 2 || resumed.Imported != 1 || resumed.Skipped != 2 || resumed.Total != 3 {
+		t.Fatalf("ClaimNext after lease = %+v, %v; want 2 of 3 processed", resumed, err)
+	}
+
+	if err := repo.Finish(ctx, imp.ID, domain.ImportCompleted, ""); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	found, err := repo.FindByID(ctx, imp.ID, owner.ID)
+	if err != nil || found == nil || found.Status != domain.ImportCompleted || found.FinishedAt == nil {
+		t.Fatalf("FindByID after Finish = %+v, %v", found, err)
+	}
+	if other, err := repo.FindByID(ctx, imp.ID, uuid.New()); err != nil || other != nil {
+		t.Fatalf("FindByID(other user) = %+v, %v; want nil", other, err)
+	}
+	if list, err := repo.ListByUser(ctx, owner.ID, 10); err != nil || len(list) != 1 {
+		t.Fatalf("ListByUser = %d imports, %v; want 1", len(list), err)
+	}
+}
+
+func TestGitActivityRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewGitActivityRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+
+	now := time.Now().UTC()
+	hook, err := repo.SaveHookSecret(ctx, &domain.GitHook{ID: uuid.New(), UserID: owner.ID, Secret: "first", UpdatedAt: now})
+	if err != nil || hook == nil || !hook.Enabled || !hook.IncludeCommits || hook.Secret != "first" {
+		t.Fatalf("SaveHookSecret = %+v, %v; want an enabled hook", hook, err)
+	}
+	// Rotating keeps the webhook's ID, so its URL stays valid
+	rotated, err := repo.SaveHookSecret(ctx, &domain.GitHook{ID: uuid.New(), UserID: owner.ID, Secret: "second", UpdatedAt: now})
+	if err != nil || rotated.ID != hook.ID || rotated.Secret != "second" {
+		t.Fatalf("SaveHookSecret(rotate) = %+v, %v; want the same hook with the new secret", rotated, err)
+	}
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	activity := []domain.GitActivity{{
+		Provider: domain.GitProviderGitHub, Kind: domain.GitActivityCommit, ExternalID: "commit:ada/engine@abc",
+		Repo: "ada/engine", Ref: "abc", Title: "Fix race", FilesChanged: 2, OccurredAt: day.Add(9 * time.Hour),
+	}}
+	if recorded, err := repo.RecordActivity(ctx, owner.ID, activity); err != nil || len(recorded) != 1 {
+		t.Fatalf("RecordActivity = %d, %v; want 1", len(recorded), err)
+	}
+	if recorded, err := repo.RecordActivity(ctx, owner.ID, activity); err != nil || len(recorded) != 0 {
+		t.Fatalf("RecordActivity(redelivered) = %d, %v; want 0", len(recorded), err)
+	}
+	if found, err := repo.FindActivity(ctx, owner.ID, day, day.Add(24*time.Hour)); err != nil || len(found) != 1 || found[0].Title != "Fix race" {
+		t.Fatalf("FindActivity = %+v, %v", found, err)
+	}
+
+	draft := &domain.GitDraft{ID: uuid.New(), UserID: owner.ID, Day: day, Title: "Summary", Content: "one", Tags: []string{"git-activity"}, UpdatedAt: now}
+	if err := repo.RefreshDraft(ctx, draft); err != nil {
+		t.Fatalf("RefreshDraft: %v", err)
+	}
+	drafts, err := repo.ListDrafts(ctx, owner.ID, 10)
+	if err != nil || len(drafts) != 1 || drafts[0].Content != "one" {
+		t.Fatalf("ListDrafts = %+v, %v", drafts, err)
+	}
+	saved := drafts[0]
+
+	saved.Content = "my own words"
+	if err := repo.UpdateDraft(ctx, &saved); err != nil {
+		t.Fatalf("UpdateDraft: %v", err)
+	}
+	// Edited drafts are not overwritten by later summaries
+	draft.ID, draft.Content = uuid.New(), "two"
+	if err := repo.RefreshDraft(ctx, draft); err != nil {
+		t.Fatalf("RefreshDraft(edited): %v", err)
+	}
+	found, err := repo.FindDraft(ctx, saved.ID, owner.ID)
+	if err != nil || found == nil || found.Content != "my own words" || !found.Edited {
+		t.Fatalf("FindDraft after refresh = %+v, %v; want the user's edit", found, err)
+	}
+
+	if err := repo.SetDraftStatus(ctx, saved.ID, domain.GitDraftDismissed, nil); err != nil {
+		t.Fatalf("SetDraftStatus: %v", err)
+	}
+	if drafts, err := repo.ListDrafts(ctx, owner.ID, 10); err != nil || len(drafts) != 0 {
+		t.Fatalf("ListDrafts after dismiss = %d, %v; want 0", len(drafts), err)
+	}
+	if deleted, err := repo.DeleteHook(ctx, owner.ID); err != nil || !deleted {
+		t.Fatalf("DeleteHook = %v, %v", deleted, err)
+	}
+	if gone, err := repo.FindHookByID(ctx, hook.ID); err != nil || gone != nil {
+		t.Fatalf("FindHookByID after delete = %+v, %v; want nil", gone, err)
+	}
+}
+
+func TestCodingRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewCodingRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+
+	now := time.Now().UTC()
+	if err := repo.SaveSource(ctx, &domain.CodingSource{UserID: owner.ID, Provider: domain.CodingSourceHeartbeat, TokenHash: "hash", UpdatedAt: now}); err != nil {
+		t.Fatalf("SaveSource: %v", err)
+	}
+	src, err := repo.FindSourceByToken(ctx, "hash")
+	if err != nil || src == nil || src.UserID != owner.ID || src.LastHeartbeatAt != nil {
+		t.Fatalf("FindSourceByToken = %+v, %v", src, err)
+	}
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	last := day.Add(10 * time.Hour)
+	added := []domain.CodingDay{{Date: day, Seconds: map[string]int{"Go": 90, "SQL": 45}}}
+	changed, ok, err := repo.AddHeartbeatTime(ctx, owner.ID, nil, &last, "Go", added)
+	if err != nil || !ok || changed[day] != 2 {
+		t.Fatalf("AddHeartbeatTime = %v, %v, %v; want 2 minutes added", changed, ok, err)
+	}
+	// A request that counted from a stale latest heartbeat records nothing
+	if _, ok, err := repo.AddHeartbeatTime(ctx, owner.ID, nil, &last, "Go", added); err != nil || ok {
+		t.Fatalf("AddHeartbeatTime(stale) = %v, %v; want a conflict", ok, err)
+	}
+
+	// Switching to WakaTime makes it due for a sync
+	if err := repo.SaveSource(ctx, &domain.CodingSource{UserID: owner.ID, Provider: domain.CodingSourceWakaTime, APIKey: "waka", UpdatedAt: now}); err != nil {
+		t.Fatalf("SaveSource(wakatime): %v", err)
+	}
+	if old, err := repo.FindSourceByToken(ctx, "hash"); err != nil || old != nil {
+		t.Fatalf("FindSourceByToken after switching = %+v, %v; want nil", old, err)
+	}
+	claimed, err := repo.ClaimDueSync(ctx, time.Now().UTC(), time.Hour)
+	if err != nil || claimed == nil || claimed.APIKey != "waka" {
+		t.Fatalf("ClaimDueSync = %+v, %v", claimed, err)
+	}
+	if again, err := repo.ClaimDueSync(ctx, time.Now().UTC(), time.Hour); err != nil || again != nil {
+		t.Fatalf("ClaimDueSync before the next sync = %+v, %v; want nil", again, err)
+	}
+
+	changed, err = repo.ReplaceDays(ctx, owner.ID, []domain.CodingDay{{Date: day, Seconds: map[string]int{"Go": 600}}})
+	if err != nil || changed[day] != 8 {
+		t.Fatalf("ReplaceDays = %v, %v; want 2 minutes to become 10", changed, err)
+	}
+	if err := repo.FinishSync(ctx, owner.ID, time.Now().UTC(), ""); err != nil {
+		t.Fatalf("FinishSync: %v", err)
+	}
+	if synced, err := repo.FindSource(ctx, owner.ID); err != nil || synced.SyncedAt == nil || synced.SyncError != "" {
+		t.Fatalf("FindSource after sync = %+v, %v", synced, err)
+	}
+
+	totals, err := repo.LanguageTotals(ctx, owner.ID, time.Time{})
+	if err != nil || len(totals) != 1 || totals[0].Language != "Go" || totals[0].Minutes != 10 {
+		t.Fatalf("LanguageTotals = %+v, %v; want 10 minutes of Go", totals, err)
+	}
+	if recent, err := repo.LanguageTotals(ctx, owner.ID, day.AddDate(0, 0, 1)); err != nil || len(recent) != 0 {
+		t.Fatalf("LanguageTotals(since May 2) = %+v, %v; want none", recent, err)
+	}
+}
+
+func TestProblemRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewProblemRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+
+	now := time.Now().UTC()
+	twoSum := &domain.Problem{ID: uuid.New(), UserID: owner.ID, Platform: domain.PlatformLeetCode, ProblemID: "two-sum",
+		Title: "Two Sum", Difficulty: domain.DifficultyEasy, SolvedAt: now, CreatedAt: now, UpdatedAt: now}
+	if created, err := repo.Create(ctx, twoSum); err != nil || !created {
+		t.Fatalf("Create = %v, %v", created, err)
+	}
+	again := *twoSum
+	again.ID = uuid.New()
+	if created, err := repo.Create(ctx, &again); err != nil || created {
+		t.Fatalf("Create(duplicate) = %v, %v; want false", created, err)
+	}
+	aoc := &domain.Problem{ID: uuid.New(), UserID: owner.ID, Platform: domain.PlatformAdventOfCode, ProblemID: "2023/12",
+		Title: "Hot Springs", SolvedAt: now.AddDate(0, 0, -21), CreatedAt: now, UpdatedAt: now}
+	if _, err := repo.Create(ctx, aoc); err != nil {
+		t.Fatalf("Create(aoc): %v", err)
+	}
+
+	problems, total, err := repo.ListByUser(ctx, owner.ID, "", domain.DifficultyEasy, 10, 0)
+	if err != nil || total != 1 || len(problems) != 1 || problems[0].ID != twoSum.ID {
+		t.Fatalf("ListByUser(easy) = %+v, %d, %v; want two-sum", problems, total, err)
+	}
+	if taken, err := repo.Exists(ctx, owner.ID, domain.PlatformLeetCode, "two-sum", aoc.ID); err != nil || !taken {
+		t.Fatalf("Exists = %v, %v; want true", taken, err)
+	}
+	if taken, err := repo.Exists(ctx, owner.ID, domain.PlatformLeetCode, "two-sum", twoSum.ID); err != nil || taken {
+		t.Fatalf("Exists(except itself) = %v, %v; want false", taken, err)
+	}
+
+	aoc.Difficulty = domain.DifficultyHard
+	if err := repo.Update(ctx, aoc); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if found, err := repo.FindByID(ctx, aoc.ID, owner.ID); err != nil || found.Difficulty != domain.DifficultyHard {
+		t.Fatalf("FindByID after update = %+v, %v", found, err)
+	}
+
+	stats, err := repo.Stats(ctx, owner.ID, 4)
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Total != 2 || stats.ByDifficulty[domain.DifficultyEasy] != 1 || stats.ByDifficulty[domain.DifficultyHard] != 1 ||
+		stats.ByPlatform[domain.PlatformAdventOfCode] != 1 {
+		t.Fatalf("Stats = %+v", stats)
+	}
+	if len(stats.Weekly) != 4 || stats.Weekly[3].Solved != 1 {
+		t.Fatalf("Stats.Weekly = %+v; want 4 weeks with one solve this week", stats.Weekly)
+	}
+
+	if deleted, err := repo.Delete(ctx, twoSum.ID, owner.ID); err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v", deleted, err)
+	}
+	if deleted, err := repo.Delete(ctx, twoSum.ID, owner.ID); err != nil || deleted {
+		t.Fatalf("Delete(again) = %v, %v; want false", deleted, err)
+	}
+}
+
+func TestCaptureRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewCaptureRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+
+	now := time.Now().UTC()
+	note := &domain.Capture{ID: uuid.New(), UserID: owner.ID, Source: "chatgpt", Kind: domain.CaptureEntry, Title: "Channels",
+		Content: "Unbuffered channels block", Tags: []string{"capture", "chatgpt"}, Status: domain.CaptureInbox, CreatedAt: now, UpdatedAt: now}
+	code := &domain.Capture{ID: uuid.New(), UserID: owner.ID, Source: "browser-extension", Kind: domain.CaptureSnippet, Title: "Retry",
+		Content: "for {}", Language: "go", Tags: []string{"capture"}, Status: domain.CaptureInbox, CreatedAt: now.Add(time.Second), UpdatedAt: now}
+	for _, c := range []*domain.Capture{note, code} {
+		if err := repo.Create(ctx, c); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	inbox, total, err := repo.List(ctx, owner.ID, domain.CaptureInbox, 10, 0)
+	if err != nil || total != 2 || len(inbox) != 2 || inbox[0].ID != code.ID {
+		t.Fatalf("ListInbox = %+v, %d, %v; want the snippet first", inbox, total, err)
+	}
+
+	note.Title = "Unbuffered channels"
+	if updated, err := repo.Update(ctx, note); err != nil || !updated {
+		t.Fatalf("Update = %v, %v", updated, err)
+	}
+	if converted, err := repo.SetStatus(ctx, note.ID, domain.CaptureConverted, domain.CaptureBookmark, uuid.NewString()); err != nil || !converted {
+		t.Fatalf("SetStatus(converted) = %v, %v", converted, err)
+	}
+	// Converted captures leave the inbox and can't be changed again
+	if updated, err := repo.Update(ctx, note); err != nil || updated {
+		t.Fatalf("Update after converting = %v, %v; want false", updated, err)
+	}
+	if archived, err := repo.SetStatus(ctx, note.ID, domain.CaptureArchived, "", ""); err != nil || archived {
+		t.Fatalf("SetStatus(archived) after converting = %v, %v; want false", archived, err)
+	}
+	found, err := repo.FindByID(ctx, note.ID, owner.ID)
+	if err != nil || found.Title != "Unbuffered channels" || found.Status != domain.CaptureConverted ||
+		found.Kind != domain.CaptureBookmark || found.PublishedID == "" {
+		t.Fatalf("FindByID = %+v, %v", found, err)
+	}
+
+	// Archiving keeps the kind
+	if archived, err := repo.SetStatus(ctx, code.ID, domain.CaptureArchived, "", ""); err != nil || !archived {
+		t.Fatalf("SetStatus(archived) = %v, %v", archived, err)
+	}
+	if inbox, total, err := repo.List(ctx, owner.ID, domain.CaptureInbox, 10, 0); err != nil || total != 0 || len(inbox) != 0 {
+		t.Fatalf("List(inbox) after triage = %+v, %d, %v; want none", inbox, total, err)
+	}
+	archive, total, err := repo.List(ctx, owner.ID, domain.CaptureArchived, 10, 0)
+	if err != nil || total != 1 || archive[0].ID != code.ID || archive[0].Kind != domain.CaptureSnippet {
+		t.Fatalf("List(archived) = %+v, %d, %v", archive, total, err)
+	}
+}
+
+func TestBookmarkRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewBookmarkRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+
+	now := time.Now().UTC()
+	docs := &domain.Bookmark{ID: uuid.New(), UserID: owner.ID, URL: "https://go.dev/doc", Title: "Go docs", Tags: []string{"go"}, CreatedAt: now}
+	if created, err := repo.Create(ctx, docs); err != nil || !created {
+		t.Fatalf("Create = %v, %v", created, err)
+	}
+	again := *docs
+	again.ID = uuid.New()
+	if created, err := repo.Create(ctx, &again); err != nil || created {
+		t.Fatalf("Create(same url) = %v, %v; want false", created, err)
+	}
+	blog := &domain.Bookmark{ID: uuid.New(), UserID: owner.ID, URL: "https://go.dev/blog", Title: "Go blog", Tags: []string{}, CreatedAt: now.Add(time.Second)}
+	if _, err := repo.Create(ctx, blog); err != nil {
+		t.Fatalf("Create(blog): %v", err)
+	}
+
+	if all, total, err := repo.ListByUser(ctx, owner.ID, "", 10, 0); err != nil || total != 2 || all[0].ID != blog.ID {
+		t.Fatalf("ListByUser = %+v, %d, %v; want the newest first", all, total, err)
+	}
+	if tagged, total, err := repo.ListByUser(ctx, owner.ID, "go", 10, 0); err != nil || total != 1 || tagged[0].ID != docs.ID {
+		t.Fatalf("ListByUser(go) = %+v, %d, %v", tagged, total, err)
+	}
+
+	if deleted, err := repo.Delete(ctx, docs.ID, owner.ID); err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v", deleted, err)
+	}
+	if deleted, err := repo.Delete(ctx, docs.ID, owner.ID); err != nil || deleted {
+		t.Fatalf("Delete(again) = %v, %v; want false", deleted, err)
+	}
+}
+
+func TestSiteRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewSiteRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+	other := env.CreateUser(t, "Other")
+	entry := env.CreateEntry(t, owner, "Goroutines", "go")
+
+	// Without a publisher nothing is queued
+	if queued, err := repo.Enqueue(ctx, owner.ID, entry.ID); err != nil || queued {
+		t.Fatalf("Enqueue(no publisher) = %v, %v; want false", queued, err)
+	}
+
+	p := &domain.SitePublisher{UserID: owner.ID, Kind: domain.SitePublisherGitHub, Enabled: true, Repo: "owner/site", Path: "_posts", Token: "tok"}
+	if err := repo.SavePublisher(ctx, p); err != nil {
+		t.Fatalf("SavePublisher: %v", err)
+	}
+	if found, err := repo.FindPublisher(ctx, owner.ID); err != nil || found == nil || found.Token != "tok" || !found.HasToken {
+		t.Fatalf("FindPublisher = %+v, %v", found, err)
+	}
+	if found, err := repo.FindPublisher(ctx, other.ID); err != nil || found != nil {
+		t.Fatalf("FindPublisher(other) = %+v, %v; want nil", found, err)
+	}
+
+	if queued, err := repo.Enqueue(ctx, owner.ID, entry.ID); err != nil || !queued {
+		t.Fatalf("Enqueue = %v, %v", queued, err)
+	}
+	// Entries are only sent once
+	if queued, err := repo.Enqueue(ctx, owner.ID, entry.ID); err != nil || queued {
+		t.Fatalf("Enqueue(again) = %v, %v; want false", queued, err)
+	}
+
+	now := time.Now().UTC()
+	due, err := repo.ClaimDue(ctx, now, time.Minute, 10)
+	if err != nil || len(due) != 1 || due[0].EntryID != entry.ID || due[0].UserID != owner.ID {
+		t.Fatalf("ClaimDue = %+v, %v", due, err)
+	}
+	if again, err := repo.ClaimDue(ctx, now, time.Minute, 10); err != nil || len(again) != 0 {
+		t.Fatalf("ClaimDue(leased) = %+v, %v; want none", again, err)
+	}
+	if err := repo.MarkAttemptFailed(ctx, due[0].ID, "github returned 500", nil); err != nil {
+		t.Fatalf("MarkAttemptFailed: %v", err)
+	}
+
+	deliveries, total, err := repo.ListDeliveries(ctx, owner.ID, 10, 0)
+	if err != nil || total != 1 || deliveries[0].EntryTitle != "Goroutines" || deliveries[0].Status != "failed" || deliveries[0].Attempts != 1 {
+		t.Fatalf("ListDeliveries = %+v, %d, %v", deliveries, total, err)
+	}
+
+	if deleted, err := repo.DeletePublisher(ctx, owner.ID); err != nil || !deleted {
+		t.Fatalf("DeletePublisher = %v, %v", deleted, err)
+	}
+	if deleted, err := repo.DeletePublisher(ctx, owner.ID); err != nil || deleted {
+		t.Fatalf("DeletePublisher(again) = %v, %v; want false", deleted, err)
+	}
+}
+
+func TestChatTicketRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewChatTicketRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+
+	now := time.Now().UTC()
+	ticket := &domain.ChatTicket{TicketHash: strings.Repeat("a", 64), UserID: owner.ID, Room: "general", ReadOnly: true, JoinVoiceOnly: true, ExpiresAt: now.Add(30 * time.Second)}
+	if err := repo.Create(ctx, ticket); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	expired := &domain.ChatTicket{TicketHash: strings.Repeat("b", 64), UserID: owner.ID, Room: "general", ExpiresAt: now.Add(-time.Minute)}
+	if err := repo.Create(ctx, expired); err != nil {
+		t.Fatalf("Create(expired): %v", err)
+	}
+
+	redeemed, err := repo.Redeem(ctx, ticket.TicketHash)
+	if err != nil || redeemed == nil || redeemed.UserID != owner.ID || redeemed.Room != "general" || !redeemed.ReadOnly || !redeemed.JoinVoiceOnly || redeemed.DisplayName != "Owner" {
+		t.Fatalf("Redeem = %+v, %v", redeemed, err)
+	}
+	if again, err := repo.Redeem(ctx, ticket.TicketHash); err != nil || again != nil {
+		t.Fatalf("Redeem(again) = %+v, %v; want nil", again, err)
+	}
+
+	if deleted, err := repo.DeleteExpired(ctx, now); err != nil || deleted != 1 {
+		t.Fatalf("DeleteExpired = %d, %v; want 1", deleted, err)
+	}
+}
+
+func TestGroupChannelRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewGroupChannelRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+	group := env.CreateGroup(t, owner, "Compilers")
+
+	announcements := domain.NewGroupChannel(group.ID, "announcements", domain.ChannelPostAdmins)
+	if created, err := repo.Create(ctx, announcements); err != nil || !created {
+		t.Fatalf("Create = %v, %v", created, err)
+	}
+	if created, err := repo.Create(ctx, domain.NewGroupChannel(group.ID, "announcements", domain.ChannelPostMembers)); err != nil || created {
+		t.Fatalf("Create(duplicate) = %v, %v; want false", created, err)
+	}
+
+	// Saving general's policy stores it without counting it as an added channel
+	general := domain.NewGroupChannel(group.ID, domain.DefaultChannel, domain.ChannelPostAdmins)
+	if err := repo.Upsert(ctx, general); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if count, err := repo.Count(ctx, group.ID); err != nil || count != 1 {
+		t.Fatalf("Count = %d, %v; want 1", count, err)
+	}
+
+	found, err := repo.Find(ctx, group.ID, "announcements")
+	if err != nil || found == nil || found.PostPolicy != domain.ChannelPostAdmins || found.Room != group.ID.String()+":announcements" {
+		t.Fatalf("Find = %+v, %v", found, err)
+	}
+	if missing, err := repo.Find(ctx, group.ID, "help"); err != nil || missing != nil {
+		t.Fatalf("Find(help) = %+v, %v; want nil", missing, err)
+	}
+
+	channels, err := repo.List(ctx, group.ID)
+	if err != nil || len(channels) != 2 {
+		t.Fatalf("List = %d channels, %v; want 2", len(channels), err)
+	}
+
+	if deleted, err := repo.Delete(ctx, group.ID, "announcements"); err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v", deleted, err)
+	}
+	if deleted, err := repo.Delete(ctx, group.ID, "announcements"); err != nil || deleted {
+		t.Fatalf("Delete(again) = %v, %v; want false", deleted, err)
+	}
+}
+
+func TestEntryTemplateRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewEntryTemplateRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+	group := env.CreateGroup(t, owner, "Retros")
+
+	now := time.Now().UTC()
+	template := &domain.EntryTemplate{
+		ID: uuid.New(), GroupID: group.ID, CreatedBy: owner.ID, Name: "Weekly retro",
+		Prompts: []string{"What went well?"}, Tags: []string{"retro"}, CreatedAt: now, UpdatedAt: now,
+	}
+	if err := repo.Create(ctx, template); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	found, err := repo.FindByID(ctx, template.ID, group.ID)
+	if err != nil || found == nil || found.Name != "Weekly retro" || len(found.Prompts) != 1 {
+		t.Fatalf("FindByID = %+v, %v", found, err)
+	}
+
+	entry := env.CreateEntry(t, owner, "Weekly retro 2026-W42")
+	if added, err := repo.AddResponse(ctx, template.ID, "2026-W42", owner.ID, entry.ID); err != nil || !added {
+		t.Fatalf("AddResponse = %v, %v", added, err)
+	}
+	if added, err := repo.AddResponse(ctx, template.ID, "2026-W42", owner.ID, entry.ID); err != nil || added {
+		t.Fatalf("AddResponse(again) = %v, %v; want false", added, err)
+	}
+	if entryID, err := repo.FindResponse(ctx, template.ID, "2026-W42", owner.ID); err != nil || entryID != entry.ID {
+		t.Fatalf("FindResponse = %s, %v; want %s", entryID, err, entry.ID)
+	}
+
+	instances, err := repo.ListInstances(ctx, template.ID)
+	if err != nil || len(instances) != 1 || instances[0].Instance != "2026-W42" || instances[0].Responses != 1 {
+		t.Fatalf("ListInstances = %+v, %v", instances, err)
+	}
+	responses, err := repo.ListResponses(ctx, template.ID, "2026-W42")
+	if err != nil || len(responses) != 1 || responses[0].EntryID != entry.ID || responses[0].DisplayName != "Owner" {
+		t.Fatalf("ListResponses = %+v, %v", responses, err)
+	}
+
+	// Deleting the template keeps the entry
+	if deleted, err := repo.Delete(ctx, template.ID, group.ID); err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v", deleted, err)
+	}
+	var entries int
+	if err := env.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM journal_entries WHERE id = $1`, entry.ID).Scan(&entries); err != nil || entries != 1 {
+		t.Fatalf("entry count after deleting template = %d, %v", entries, err)
+	}
+}
+
+func TestPollRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewPollRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+	member := env.CreateUser(t, "Member")
+	group := env.CreateGroup(t, owner, "Polls")
+
+	poll := &domain.Poll{
+		ID: uuid.New(), GroupID: group.ID, Channel: domain.DefaultChannel, CreatedBy: owner.ID,
+		Question: "Next topic?", MultipleChoice: true, CreatedAt: time.Now().UTC(),
+		Options: []domain.PollOption{{Text: "Generics"}, {Text: "Iterators"}, {Text: "Fuzzing"}},
+	}
+	if err := repo.Create(ctx, poll); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	found, err := repo.FindByID(ctx, poll.ID, group.ID)
+	if err != nil || found == nil || found.Room != group.ID.String() || len(found.Options) != 3 || found.Options[1].Text != "Iterators" {
+		t.Fatalf("FindByID = %+v, %v", found, err)
+	}
+
+	// A member's second vote replaces their first
+	if err := repo.Vote(ctx, poll.ID, member.ID, []int{0, 1}); err != nil {
+		t.Fatalf("Vote: %v", err)
+	}
+	if err := repo.Vote(ctx, poll.ID, member.ID, []int{1, 2}); err != nil {
+		t.Fatalf("Vote(again): %v", err)
+	}
+	if err := repo.Vote(ctx, poll.ID, owner.ID, []int{1}); err != nil {
+		t.Fatalf("Vote(owner): %v", err)
+	}
+	if err := repo.Tally(ctx, []*domain.Poll{found}, member.ID); err != nil {
+		t.Fatalf("Tally: %v", err)
+	}
+	if found.Voters != 2 || found.Options[0].Votes != 0 || found.Options[1].Votes != 2 || found.Options[2].Votes != 1 ||
+		len(found.MyVotes) != 2 || found.MyVotes[0] != 1 || found.MyVotes[1] != 2 {
+		t.Fatalf("Tally = %+v", found)
+	}
+
+	polls, err := repo.ListByGroup(ctx, group.ID, "", time.Now().UTC().Add(time.Minute), 10)
+	if err != nil || len(polls) != 1 || polls[0].ID != poll.ID {
+		t.Fatalf("ListByGroup = %+v, %v", polls, err)
+	}
+	if polls, err := repo.ListByGroup(ctx, group.ID, "announcements", time.Now().UTC().Add(time.Minute), 10); err != nil || len(polls) != 0 {
+		t.Fatalf("ListByGroup(other channel) = %+v, %v", polls, err)
+	}
+}
+
+func TestGroupPermissionRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewGroupPermissionRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+	group := env.CreateGroup(t, owner, "Permissions")
+
+	if permissions, err := repo.Get(ctx, group.ID); err != nil || permissions != nil {
+		t.Fatalf("Get(unconfigured) = %+v, %v; want nil", permissions, err)
+	}
+
+	permissions := domain.NewGroupPermissions(group.ID)
+	permissions.PostSnippets = domain.GroupPermissionAdmins
+	if err := repo.Upsert(ctx, permissions); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	permissions.PostSnippets = domain.GroupPermissionMembers
+	permissions.StartSessions = domain.GroupPermissionAdmins
+	if err := repo.Upsert(ctx, permissions); err != nil {
+		t.Fatalf("Upsert(again): %v", err)
+	}
+	found, err := repo.Get(ctx, group.ID)
+	if err != nil || found == nil || found.PostSnippets != "members" || found.StartSessions != "admins" || found.ShareEntries != "members" {
+		t.Fatalf("Get = %+v, %v", found, err)
+	}
+}
+
+func TestYearlyReviewRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewYearlyReviewRepository(env.Pool)
+	progressRepo := postgres.NewProgressRepository(env.Pool)
+	user := env.CreateUser(t, "Reviewer")
+
+	env.CreateEntry(t, user, "Goroutines", "go")
+	env.CreateEntry(t, user, "Channels", "go", "concurrency")
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, day := range []int{0, 1, 3} {
+		progress := domain.NewLearningProgress(user.ID, today.AddDate(0, 0, -day))
+		progress.EntriesCount = 1
+		if err := progressRepo.Upsert(ctx, progress); err != nil {
+			t.Fatalf("Upsert progress: %v", err)
+		}
+	}
+
+	review := &domain.YearlyReview{Year: today.Year(), ComputedAt: time.Now().UTC().Add(-48 * time.Hour)}
+	if err := repo.Aggregate(ctx, user.ID, review); err != nil {
+		t.Fatalf("Aggregate: %v", err)
+	}
+	if review.TotalEntries != 2 || review.TotalWords != 8 || review.BusiestWeek == nil || review.BusiestWeek.Entries != 2 {
+		t.Fatalf("Aggregate = %+v, want 2 entries and 8 words in one week", review)
+	}
+	if len(review.TopTags) != 2 || review.TopTags[0].Tag != "go" || review.TopTags[0].Count != 2 {
+		t.Fatalf("TopTags = %+v, want go first", review.TopTags)
+	}
+	if len(review.Moods) != 1 || review.Moods[0].Mood != "productive" || review.Moods[0].Count != 2 {
+		t.Fatalf("Moods = %+v, want 2 productive", review.Moods)
+	}
+	// Early January the gap day may fall in the previous year
+	if want := 2; today.YearDay() > 3 && review.LongestStreak != want {
+		t.Fatalf("LongestStreak = %d, want %d", review.LongestStreak, want)
+	}
+
+	if found, err := repo.Get(ctx, user.ID, review.Year); err != nil || found != nil {
+		t.Fatalf("Get(uncached) = %+v, %v; want nil", found, err)
+	}
+	if err := repo.Upsert(ctx, user.ID, review); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	found, err := repo.Get(ctx, user.ID, review.Year)
+	if err != nil || found == nil || found.TotalWords != 8 || len(found.TopTags) != 2 {
+		t.Fatalf("Get = %+v, %v", found, err)
+	}
+
+	// Reports of years that have ended are final and never go stale
+	yearStart, _ := domain.YearBounds(today.Year())
+	past := &domain.YearlyReview{Year: today.Year() - 1, Final: true, ComputedAt: yearStart}
+	if err := repo.Upsert(ctx, user.ID, past); err != nil {
+		t.Fatalf("Upsert(past): %v", err)
+	}
+	stale, err := repo.ListStale(ctx, time.Now().UTC().Add(-24*time.Hour), 10)
+	if err != nil || len(stale) != 1 || stale[0].UserID != user.ID || stale[0].Year != review.Year {
+		t.Fatalf("ListStale = %+v, %v; want this year's report", stale, err)
+	}
+}
+
+func TestAnnouncementRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewAnnouncementRepository(env.Pool)
+	admin := env.CreateUser(t, "Admin")
+	owner := env.CreateUser(t, "Owner")
+	env.CreateGroup(t, owner, "Owned")
+
+	now := time.Now().UTC()
+	newAnnouncement := func(audience string, publishAt time.Time) *domain.Announcement {
+		a := &domain.Announcement{ID: uuid.New(), Title: "Hello", Body: "World", Audience: audience, PublishAt: publishAt, CreatedBy: admin.ID, CreatedAt: now}
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		return a
+	}
+	owners := newAnnouncement(domain.AnnouncementAudienceGroupOwners, now.Add(-time.Minute))
+	later := newAnnouncement(domain.AnnouncementAudienceAll, now.Add(time.Hour))
+
+	due, err := repo.ListDue(ctx, now, 10)
+	if err != nil || len(due) != 1 || due[0].ID != owners.ID {
+		t.Fatalf("ListDue = %+v, %v; want the group owners announcement", due, err)
+	}
+	recipients, published, err := repo.Publish(ctx, &due[0], now)
+	if err != nil || !published || recipients != 1 {
+		t.Fatalf("Publish = %d, %v, %v; want only the group owner", recipients, published, err)
+	}
+	if _, published, err := repo.Publish(ctx, &due[0], now); err != nil || published {
+		t.Fatalf("Publish(again) = %v, %v; want false", published, err)
+	}
+	if list, err := repo.ListRecipients(ctx, owners.ID, uuid.Nil, 10); err != nil || len(list) != 1 || list[0].UserID != owner.ID {
+		t.Fatalf("ListRecipients = %+v, %v", list, err)
+	}
+
+	inbox, total, err := repo.ListByUser(ctx, owner.ID, true, 10, 0)
+	if err != nil || total != 1 || len(inbox) != 1 || inbox[0].PublishedAt == nil || inbox[0].Recipients != 1 {
+		t.Fatalf("ListByUser = %+v (total %d), %v", inbox, total, err)
+	}
+	if updated, err := repo.MarkRead(ctx, owner.ID, nil, now); err != nil || updated != 1 {
+		t.Fatalf("MarkRead = %d, %v; want 1", updated, err)
+	}
+	if unread, err := repo.CountUnread(ctx, owner.ID); err != nil || unread != 0 {
+		t.Fatalf("CountUnread = %d, %v; want 0", unread, err)
+	}
+
+	if deleted, err := repo.DeleteScheduled(ctx, owners.ID); err != nil || deleted {
+		t.Fatalf("DeleteScheduled(published) = %v, %v; want false", deleted, err)
+	}
+	if deleted, err := repo.DeleteScheduled(ctx, later.ID); err != nil || !deleted {
+		t.Fatalf("DeleteScheduled(scheduled) = %v, %v; want true", deleted, err)
+	}
+}
+
+func TestIdentityRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewIdentityRepository(env.Pool)
+	user := env.CreateUser(t, "Ada")
+
+	if id, err := repo.FindUserID(ctx, "https://login.example.com", "ada"); err != nil || id != uuid.Nil {
+		t.Fatalf("FindUserID(unlinked) = %v, %v; want uuid.Nil", id, err)
+	}
+	now := time.Now().UTC()
+	identity := &domain.UserIdentity{UserID: user.ID, Issuer: "https://login.example.com", Subject: "ada", Email: "ada@example.com", CreatedAt: now, LastLoginAt: now}
+	if err := repo.Link(ctx, identity); err != nil {
+		t.Fatalf("Link: %v", err)
+	}
+	// Signing in again only records the sign-in
+	identity.Email = "ada@new.example.com"
+	identity.LastLoginAt = now.Add(time.Hour)
+	if err := repo.Link(ctx, identity); err != nil {
+		t.Fatalf("Link(again): %v", err)
+	}
+	if id, err := repo.FindUserID(ctx, "https://login.example.com", "ada"); err != nil || id != user.ID {
+		t.Fatalf("FindUserID = %v, %v; want %v", id, err, user.ID)
+	}
+	if id, err := repo.FindUserID(ctx, "https://other.example.com", "ada"); err != nil || id != uuid.Nil {
+		t.Fatalf("FindUserID(other issuer) = %v, %v; want uuid.Nil", id, err)
+	}
+	var email string
+	if err := env.Pool.QueryRow(ctx, `SELECT email FROM user_identities WHERE subject = 'ada'`).Scan(&email); err != nil || email != "ada@new.example.com" {
+		t.Fatalf("email = %q, %v; want the latest", email, err)
+	}
+	if subject, err := repo.FindSubject(ctx, user.ID, "https://login.example.com"); err != nil || subject != "ada" {
+		t.Fatalf("FindSubject = %q, %v; want ada", subject, err)
+	}
+	if subject, err := repo.FindSubject(ctx, user.ID, "ldap://ldap.example.com"); err != nil || subject != "" {
+		t.Fatalf("FindSubject(other issuer) = %q, %v; want none", subject, err)
+	}
+}
+
+func TestUserDeactivation(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewUserRepository(env.Pool)
+	user := env.CreateUser(t, "Ada")
+	other := env.CreateUser(t, "Grace")
+
+	for _, id := range []uuid.UUID{user.ID, other.ID} {
+		if _, err := env.Pool.Exec(ctx, `INSERT INTO calendar_feeds (user_id, token_hash) VALUES ($1, $2)`, id, id.String()); err != nil {
+			t.Fatalf("create calendar feed: %v", err)
+		}
+		if _, err := env.Pool.Exec(ctx, `INSERT INTO git_hooks (user_id, secret) VALUES ($1, 'secret')`, id); err != nil {
+			t.Fatalf("create git hook: %v", err)
+		}
+	}
+
+	now := time.Now().UTC()
+	if changed, err := repo.Deactivate(ctx, user.ID, now); err != nil || !changed {
+		t.Fatalf("Deactivate = %v, %v; want true", changed, err)
+	}
+	if changed, err := repo.Deactivate(ctx, user.ID, now); err != nil || changed {
+		t.Fatalf("Deactivate(again) = %v, %v; want false", changed, err)
+	}
+	found, err := repo.FindByID(ctx, user.ID)
+	if err != nil || found.DeactivatedAt == nil {
+		t.Fatalf("FindByID = %+v, %v; want deactivated", found, err)
+	}
+
+	// Only the deactivated user's feed token and webhook stop working
+	for id, want := range map[uuid.UUID]int{user.ID: 0, other.ID: 1} {
+		var feeds, hooks int
+		if err := env.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM calendar_feeds WHERE user_id = $1`, id).Scan(&feeds); err != nil {
+			t.Fatalf("count feeds: %v", err)
+		}
+		if err := env.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM git_hooks WHERE user_id = $1 AND enabled`, id).Scan(&hooks); err != nil {
+			t.Fatalf("count hooks: %v", err)
+		}
+		if feeds != want || hooks != want {
+			t.Errorf("user %v has %d feeds and %d enabled hooks; want %d", id, feeds, hooks, want)
+		}
+	}
+
+	if err := repo.Reactivate(ctx, user.ID, now); err != nil {
+		t.Fatalf("Reactivate: %v", err)
+	}
+	if found, err := repo.FindByID(ctx, user.ID); err != nil || found.DeactivatedAt != nil {
+		t.Fatalf("FindByID = %+v, %v; want active", found, err)
+	}
+	users, total, err := repo.List(ctx, 1, 1)
+	if err != nil || total != 2 || len(users) != 1 || users[0].ID != other.ID {
+		t.Fatalf("List(1, 1) = %v, %d, %v; want Grace of 2", users, total, err)
+	}
+}
+
+func TestImpersonationRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewImpersonationRepository(env.Pool)
+	admin := env.CreateUser(t, "Admin")
+	user := env.CreateUser(t, "Ada")
+	other := env.CreateUser(t, "Grace")
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	create := func(userID uuid.UUID, startedAt time.Time) *domain.Impersonation {
+		imp := &domain.Impersonation{
+			ID:        uuid.New(),
+			AdminID:   admin.ID,
+			UserID:    userID,
+			Reason:    "Support ticket",
+			ReadOnly:  true,
+			StartedAt: startedAt,
+			ExpiresAt: startedAt.Add(15 * time.Minute),
+		}
+		if err := repo.Create(ctx, imp); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		return imp
+	}
+	expired := create(user.ID, now.Add(-time.Hour))
+	active := create(user.ID, now)
+	create(other.ID, now.Add(-time.Minute))
+
+	found, err := repo.FindByID(ctx, active.ID)
+	if err != nil || found == nil || found.AdminName != admin.DisplayName || !found.ReadOnly || found.EndedAt != nil {
+		t.Fatalf("FindByID = %+v, %v; want the active session with its admin's name", found, err)
+	}
+	if missing, err := repo.FindByID(ctx, uuid.New()); err != nil || missing != nil {
+		t.Fatalf("FindByID(unknown) = %+v, %v; want nil", missing, err)
+	}
+
+	for id, want := range map[uuid.UUID]bool{active.ID: true, expired.ID: false, uuid.New(): false} {
+		if got, err := repo.IsActive(ctx, id, now); err != nil || got != want {
+			t.Errorf("IsActive(%v) = %v, %v; want %v", id, got, err, want)
+		}
+	}
+
+	if ended, err := repo.End(ctx, active.ID, now); err != nil || !ended {
+		t.Fatalf("End = %v, %v; want true", ended, err)
+	}
+	if ended, err := repo.End(ctx, active.ID, now); err != nil || ended {
+		t.Fatalf("End(again) = %v, %v; want false", ended, err)
+	}
+	if ended, err := repo.End(ctx, expired.ID, now); err != nil || ended {
+		t.Fatalf("End(expired) = %v, %v; want false", ended, err)
+	}
+	if got, err := repo.IsActive(ctx, active.ID, now); err != nil || got {
+		t.Fatalf("IsActive(ended) = %v, %v; want false", got, err)
+	}
+
+	all, total, err := repo.List(ctx, 2, 0)
+	if err != nil || total != 3 || len(all) != 2 || all[0].ID != active.ID || all[0].EndedAt == nil {
+		t.Fatalf("List(2, 0) = %v, %d, %v; want the ended session first of 3", all, total, err)
+	}
+	mine, total, err := repo.ListByUser(ctx, user.ID, 10, 0)
+	if err != nil || total != 2 || len(mine) != 2 || mine[1].ID != expired.ID {
+		t.Fatalf("ListByUser = %v, %d, %v; want Ada's 2 sessions, newest first", mine, total, err)
+	}
+}
+
+func TestGroupMemberProfiles(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewStudyGroupRepository(env.Pool)
+	owner := env.CreateUser(t, "Ada Lovelace")
+	member := env.CreateUser(t, "Grace")
+
+	group := domain.NewStudyGroup("Compilers", "", false, 10, owner.ID)
+	if err := repo.Create(ctx, group); err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	if err := repo.AddMember(ctx, &domain.StudyGroupMember{GroupID: group.ID, UserID: member.ID, Role: "member", JoinedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	settings := domain.NewUserSettings(owner.ID)
+	settings.AvatarURL = "https://example.com/ada.png"
+	if err := postgres.NewSettingsRepository(env.Pool).Upsert(ctx, settings); err != nil {
+		t.Fatalf("save settings: %v", err)
+	}
+	for _, kind := range []string{"streak_7", "streak_30", "streak_100_bonus"} {
+		if _, err := env.Pool.Exec(ctx, `INSERT INTO achievements (user_id, kind, earned_at) VALUES ($1, $2, NOW())`, owner.ID, kind); err != nil {
+			t.Fatalf("award %s: %v", kind, err)
+		}
+	}
+
+	profiles, err := repo.GetMemberProfiles(ctx, group.ID)
+	if err != nil {
+		t.Fatalf("GetMemberProfiles: %v", err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("GetMemberProfiles = %+v; want 2 profiles", profiles)
+	}
+	if p := profiles[0]; p.UserID != owner.ID || p.AvatarURL != "https://example.com/ada.png" || p.StreakMilestone != 30 || p.Role != "owner" {
+		t.Errorf("owner profile = %+v", p)
+	}
+	if p := profiles[1]; p.UserID != member.ID || p.AvatarURL != "" || p.StreakMilestone != 0 || p.DisplayName != "Grace" {
+		t.Errorf("member profile = %+v", p)
+	}
+}
+
+func TestGroupInvites(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewStudyGroupRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+	invitee := env.CreateUser(t, "Invitee")
+
+	group := domain.NewStudyGroup("Compilers", "", false, 10, owner.ID)
+	if err := repo.Create(ctx, group); err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	now := time.Now().UTC()
+	invite := &domain.GroupInvite{ID: uuid.New(), GroupID: group.ID, CodeHash: strings.Repeat("a", 64), CreatedBy: owner.ID, ExpiresAt: now.Add(time.Hour), CreatedAt: now}
+	expired := &domain.GroupInvite{ID: uuid.New(), GroupID: group.ID, CodeHash: strings.Repeat("b", 64), CreatedBy: owner.ID, ExpiresAt: now.Add(-time.Minute), CreatedAt: now}
+	for _, i := range []*domain.GroupInvite{invite, expired} {
+		if err := repo.CreateInvite(ctx, i); err != nil {
+			t.Fatalf("CreateInvite: %v", err)
+		}
+	}
+
+	if ok, err := repo.HasInvite(ctx, group.ID, invite.CodeHash, now); err != nil || !ok {
+		t.Fatalf("HasInvite = %v, %v; want true", ok, err)
+	}
+	if ok, err := repo.HasInvite(ctx, group.ID, expired.CodeHash, now); err != nil || ok {
+		t.Fatalf("HasInvite(expired) = %v, %v; want false", ok, err)
+	}
+
+	member := &domain.StudyGroupMember{GroupID: group.ID, UserID: invitee.ID, Role: "member", JoinedAt: now}
+	if ok, err := repo.AddMemberWithInvite(ctx, member, invite.CodeHash, now); err != nil || !ok {
+		t.Fatalf("AddMemberWithInvite = %v, %v; want true", ok, err)
+	}
+	if role, err := repo.GetMemberRole(ctx, group.ID, invitee.ID); err != nil || role != "member" {
+		t.Fatalf("GetMemberRole = %q, %v; want member", role, err)
+	}
+	if ok, err := repo.AddMemberWithInvite(ctx, member, invite.CodeHash, now); err != nil || ok {
+		t.Fatalf("AddMemberWithInvite(used) = %v, %v; want false", ok, err)
+	}
+
+	if deleted, err := repo.DeleteExpiredInvites(ctx, now); err != nil || deleted != 1 {
+		t.Fatalf("DeleteExpiredInvites = %d, %v; want 1", deleted, err)
+	}
+}
+
+func TestRefreshTokenRepository(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+	repo := postgres.NewRefreshTokenRepository(env.Pool)
+	owner := env.CreateUser(t, "Owner")
+
+	now := time.Now().UTC()
+	token := func(hash string, expiresAt time.Time) *domain.RefreshToken {
+		return &domain.RefreshToken{ID: uuid.New(), TokenHash: strings.Repeat(hash, 64), ExpiresAt: expiresAt, CreatedAt: now}
+	}
+	first := token("a", now.Add(time.Hour))
+	first.UserID, first.FamilyID = owner.ID, uuid.New()
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	expired := token("b", now.Add(-time.Minute))
+	expired.UserID, expired.FamilyID = owner.ID, uuid.New()
+	if err := repo.Create(ctx, expired); err != nil {
+		t.Fatalf("Create(expired): %v", err)
+	}
+
+	second := token("c", now.Add(time.Hour))
+	used, reused, err := repo.Rotate(ctx, first.TokenHash, second, now)
+	if err != nil || reused || used == nil || used.ID != first.ID || used.UsedAt == nil {
+		t.Fatalf("Rotate = %+v, %v, %v", used, reused, err)
+	}
+	if second.UserID != owner.ID || second.FamilyID != first.FamilyID {
+		t.Fatalf("successor = %+v, want the same user and family", second)
+	}
+	if used, reused, err := repo.Rotate(ctx, expired.TokenHash, token("d", now.Add(time.Hour)), now); err != nil || reused || used != nil {
+		t.Fatalf("Rotate(expired) = %+v, %v, %v; want nil", used, reused, err)
+	}
+
+	// Replaying the first token revokes its successor too
+	if used, reused, err := repo.Rotate(ctx, first.TokenHash, token("e", now.Add(time.Hour)), now); err != nil || !reused || used != nil {
+		t.Fatalf("Rotate(replayed) = %+v, %v, %v; want reused", used, reused, err)
+	}
+	if used, reused, err := repo.Rotate(ctx, second.TokenHash, token("f", now.Add(time.Hour)), now); err != nil || reused || used != nil {
+		t.Fatalf("Rotate(revoked) = %+v, %v, %v; want nil", used, reused, err)
+	}
+
+	if deleted, err := repo.DeleteExpired(ctx, now); err != nil || deleted != 1 {
+		t.Fatalf("DeleteExpired = %d, %v; want 1", deleted, err)
+	}
+}
+
+func TestSchemaVersion(t *testing.T) {
+	env.Reset(t)
+	ctx := context.Background()
+
+	if err := database.CheckSchema(ctx, env.Pool); err != nil {
+		t.Fatalf("migrated schema refused: %v", err)
+	}
+
+	// A migration newer builds need, which this build can't run against
+	next := database.SchemaVersion() + 1
+	if _, err := env.Pool.Exec(ctx, `INSERT INTO schema_version (version, compatible_from) VALUES ($1, $1)`, next); err != nil {
+		t.Fatalf("record version: %v", err)
+	}
+	t.Cleanup(func() {
+		env.Pool.Exec(context.Background(), `DELETE FROM schema_version WHERE version = $1`, next)
+	})
+	if err := database.CheckSchema(ctx, env.Pool); !errors.Is(err, database.ErrUnsupportedSchema) {
+		t.Fatalf("expected ErrUnsupportedSchema, got %v", err)
+	}
+}
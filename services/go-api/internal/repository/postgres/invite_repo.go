@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// InviteRepository handles registration invites with raw SQL
+type InviteRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewInviteRepository creates a new invite repository
+func NewInviteRepository(pool *pgxpool.Pool) *InviteRepository {
+	return &InviteRepository{pool: pool}
+}
+
+// Create saves a new invite under the hash of its code
+func (r *InviteRepository) Create(ctx context.Context, invite *domain.Invite, codeHash string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO invites (id, code_hash, note, max_uses, expires_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, invite.ID, codeHash, invite.Note, invite.MaxUses, invite.ExpiresAt, invite.CreatedBy, invite.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create invite: %w", err)
+	}
+	return nil
+}
+
+// List retrieves all invites, newest first, with the total count
+func (r *InviteRepository) List(ctx context.Context, limit, offset int) ([]domain.Invite, int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, note, max_uses, uses, expires_at, created_by, created_at
+		FROM invites
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list invites: %w", err)
+	}
+	defer rows.Close()
+
+	invites := []domain.Invite{}
+	for rows.Next() {
+		var i domain.Invite
+		if err := rows.Scan(&i.ID, &i.Note, &i.MaxUses, &i.Uses, &i.ExpiresAt, &i.CreatedBy, &i.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan invite: %w", err)
+		}
+		invites = append(invites, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating invites: %w", err)
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM invites`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count invites: %w", err)
+	}
+	return invites, total, nil
+}
+
+// Delete deletes an invite, so its code stops working. It returns false if there is no such invite.
+func (r *InviteRepository) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM invites WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete invite: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// Redeem uses up one use of the invite with a code hash, unless it expired or has none left.
+// It returns the invite's ID, or uuid.Nil if the code can't be used.
+func (r *InviteRepository) Redeem(ctx context.Context, codeHash string, now time.Time) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		UPDATE invites SET uses = uses + 1
+		WHERE code_hash = $1 AND uses < max_uses AND (expires_at IS NULL OR expires_at > $2)
+		RETURNING id
+	`, codeHash, now).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to redeem invite: %w", err)
+	}
+	return id, nil
+}
+
+// Release gives back a use Redeem took, for a registration that failed after all
+func (r *InviteRepository) Release(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `UPDATE invites SET uses = uses - 1 WHERE id = $1 AND uses > 0`, id); err != nil {
+		return fmt.Errorf("failed to release invite: %w", err)
+	}
+	return nil
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"devjournal/internal/domain"
+	"devjournal/internal/tenant"
+	"devjournal/pkg/apperr"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -25,8 +28,8 @@ func NewJournalRepository(pool *pgxpool.Pool) *JournalRepository {
 // Create inserts a new journal entry
 func (r *JournalRepository) Create(ctx context.Context, entry *domain.JournalEntry) error {
 	query := `
-		INSERT INTO journal_entries (id, user_id, title, content, mood, tags, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO journal_entries (id, user_id, title, content, mood, tags, word_count, content_format, encryption, created_at, updated_at, workspace_id, is_public, is_vault)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 	_, err := r.pool.Exec(ctx, query,
 		entry.ID,
@@ -35,8 +38,14 @@ func (r *JournalRepository) Create(ctx context.Context, entry *domain.JournalEnt
 		entry.Content,
 		entry.Mood,
 		entry.Tags,
+		entry.WordCount,
+		entry.ContentFormat,
+		entry.Encryption,
 		entry.CreatedAt,
 		entry.UpdatedAt,
+		tenant.WorkspaceID(ctx, entry.UserID),
+		entry.IsPublic,
+		entry.IsVault,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create journal entry: %w", err)
@@ -47,11 +56,11 @@ func (r *JournalRepository) Create(ctx context.Context, entry *domain.JournalEnt
 // FindByID retrieves a journal entry by ID
 func (r *JournalRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.JournalEntry, error) {
 	query := `
-		SELECT id, user_id, title, content, mood, tags, created_at, updated_at
+		SELECT id, user_id, title, content, mood, tags, word_count, content_format, encryption, is_public, is_vault, project_id, created_at, updated_at
 		FROM journal_entries
-		WHERE id = $1
+		WHERE id = $1 AND ($2::uuid IS NULL OR workspace_id = $2)
 	`
-	row := r.pool.QueryRow(ctx, query, id)
+	row := r.pool.QueryRow(ctx, query, id, optionalWorkspace(ctx))
 
 	var entry domain.JournalEntry
 	err := row.Scan(
@@ -61,6 +70,12 @@ func (r *JournalRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain
 		&entry.Content,
 		&entry.Mood,
 		&entry.Tags,
+		&entry.WordCount,
+		&entry.ContentFormat,
+		&entry.Encryption,
+		&entry.IsPublic,
+		&entry.IsVault,
+		&entry.ProjectID,
 		&entry.CreatedAt,
 		&entry.UpdatedAt,
 	)
@@ -76,13 +91,13 @@ func (r *JournalRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain
 // FindByUserID retrieves all journal entries for a user with pagination
 func (r *JournalRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.JournalEntry, error) {
 	query := `
-		SELECT id, user_id, title, content, mood, tags, created_at, updated_at
+		SELECT id, user_id, title, content, mood, tags, word_count, content_format, encryption, is_public, is_vault, project_id, created_at, updated_at
 		FROM journal_entries
-		WHERE user_id = $1
+		WHERE user_id = $1 AND workspace_id = $4
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset, tenant.WorkspaceID(ctx, userID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to find journal entries: %w", err)
 	}
@@ -98,6 +113,12 @@ func (r *JournalRepository) FindByUserID(ctx context.Context, userID uuid.UUID,
 			&entry.Content,
 			&entry.Mood,
 			&entry.Tags,
+			&entry.WordCount,
+			&entry.ContentFormat,
+			&entry.Encryption,
+			&entry.IsPublic,
+			&entry.IsVault,
+			&entry.ProjectID,
 			&entry.CreatedAt,
 			&entry.UpdatedAt,
 		)
@@ -117,13 +138,13 @@ func (r *JournalRepository) FindByUserID(ctx context.Context, userID uuid.UUID,
 // FindByMood retrieves journal entries filtered by mood
 func (r *JournalRepository) FindByMood(ctx context.Context, userID uuid.UUID, mood string, limit, offset int) ([]domain.JournalEntry, error) {
 	query := `
-		SELECT id, user_id, title, content, mood, tags, created_at, updated_at
+		SELECT id, user_id, title, content, mood, tags, word_count, content_format, encryption, is_public, is_vault, project_id, created_at, updated_at
 		FROM journal_entries
-		WHERE user_id = $1 AND mood = $2
+		WHERE user_id = $1 AND workspace_id = $5 AND mood = $2
 		ORDER BY created_at DESC
 		LIMIT $3 OFFSET $4
 	`
-	rows, err := r.pool.Query(ctx, query, userID, mood, limit, offset)
+	rows, err := r.pool.Query(ctx, query, userID, mood, limit, offset, tenant.WorkspaceID(ctx, userID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to find journal entries by mood: %w", err)
 	}
@@ -139,6 +160,12 @@ func (r *JournalRepository) FindByMood(ctx context.Context, userID uuid.UUID, mo
 			&entry.Content,
 			&entry.Mood,
 			&entry.Tags,
+			&entry.WordCount,
+			&entry.ContentFormat,
+			&entry.Encryption,
+			&entry.IsPublic,
+			&entry.IsVault,
+			&entry.ProjectID,
 			&entry.CreatedAt,
 			&entry.UpdatedAt,
 		)
@@ -151,18 +178,211 @@ func (r *JournalRepository) FindByMood(ctx context.Context, userID uuid.UUID, mo
 	return entries, nil
 }
 
-// Search searches journal entries by title or content
-func (r *JournalRepository) Search(ctx context.Context, userID uuid.UUID, searchTerm string, limit, offset int) ([]domain.JournalEntry, error) {
+// tagMatchOperator returns the array operator for a tag match mode: overlap (any) or contains (all).
+// Both are served by the GIN index on journal_entries.tags.
+func tagMatchOperator(match string) string {
+	if match == domain.TagMatchAll {
+		return "@>"
+	}
+	return "&&"
+}
+
+// FindByTags retrieves journal entries carrying any or all of the given tags
+func (r *JournalRepository) FindByTags(ctx context.Context, userID uuid.UUID, tags []string, match string, limit, offset int) ([]domain.JournalEntry, error) {
 	query := `
-		SELECT id, user_id, title, content, mood, tags, created_at, updated_at
+		SELECT id, user_id, title, content, mood, tags, word_count, content_format, encryption, is_public, is_vault, project_id, created_at, updated_at
 		FROM journal_entries
-		WHERE user_id = $1
+		WHERE user_id = $1 AND workspace_id = $5 AND tags ` + tagMatchOperator(match) + ` $2
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.pool.Query(ctx, query, userID, tags, limit, offset, tenant.WorkspaceID(ctx, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find journal entries by tags: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.JournalEntry
+	for rows.Next() {
+		var entry domain.JournalEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Title,
+			&entry.Content,
+			&entry.Mood,
+			&entry.Tags,
+			&entry.WordCount,
+			&entry.ContentFormat,
+			&entry.Encryption,
+			&entry.IsPublic,
+			&entry.IsVault,
+			&entry.ProjectID,
+			&entry.CreatedAt,
+			&entry.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating journal entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CountByTags returns the number of entries carrying any or all of the given tags
+func (r *JournalRepository) CountByTags(ctx context.Context, userID uuid.UUID, tags []string, match string) (int, error) {
+	query := `SELECT COUNT(*) FROM journal_entries WHERE user_id = $1 AND workspace_id = $3 AND tags ` + tagMatchOperator(match) + ` $2`
+	var count int
+	err := r.pool.QueryRow(ctx, query, userID, tags, tenant.WorkspaceID(ctx, userID)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count journal entries by tags: %w", err)
+	}
+	return count, nil
+}
+
+// untaggedCondition matches entries whose tags array is null or empty
+const untaggedCondition = `(tags IS NULL OR cardinality(tags) = 0)`
+
+// noMoodCondition matches entries with no mood recorded
+const noMoodCondition = `(mood IS NULL OR mood = '')`
+
+// FindUntagged retrieves journal entries that have no tags
+func (r *JournalRepository) FindUntagged(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.JournalEntry, error) {
+	query := `
+		SELECT id, user_id, title, content, mood, tags, word_count, content_format, encryption, is_public, is_vault, project_id, created_at, updated_at
+		FROM journal_entries
+		WHERE user_id = $1 AND workspace_id = $4 AND ` + untaggedCondition + `
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset, tenant.WorkspaceID(ctx, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find untagged journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.JournalEntry
+	for rows.Next() {
+		var entry domain.JournalEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Title,
+			&entry.Content,
+			&entry.Mood,
+			&entry.Tags,
+			&entry.WordCount,
+			&entry.ContentFormat,
+			&entry.Encryption,
+			&entry.IsPublic,
+			&entry.IsVault,
+			&entry.ProjectID,
+			&entry.CreatedAt,
+			&entry.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating journal entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CountUntagged returns the number of entries that have no tags
+func (r *JournalRepository) CountUntagged(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM journal_entries WHERE user_id = $1 AND workspace_id = $2 AND ` + untaggedCondition
+	var count int
+	err := r.pool.QueryRow(ctx, query, userID, tenant.WorkspaceID(ctx, userID)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count untagged journal entries: %w", err)
+	}
+	return count, nil
+}
+
+// FindWithoutMood retrieves journal entries that have no mood
+func (r *JournalRepository) FindWithoutMood(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.JournalEntry, error) {
+	query := `
+		SELECT id, user_id, title, content, mood, tags, word_count, content_format, encryption, is_public, is_vault, project_id, created_at, updated_at
+		FROM journal_entries
+		WHERE user_id = $1 AND workspace_id = $4 AND ` + noMoodCondition + `
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset, tenant.WorkspaceID(ctx, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find journal entries without mood: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.JournalEntry
+	for rows.Next() {
+		var entry domain.JournalEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Title,
+			&entry.Content,
+			&entry.Mood,
+			&entry.Tags,
+			&entry.WordCount,
+			&entry.ContentFormat,
+			&entry.Encryption,
+			&entry.IsPublic,
+			&entry.IsVault,
+			&entry.ProjectID,
+			&entry.CreatedAt,
+			&entry.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating journal entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CountWithoutMood returns the number of entries that have no mood
+func (r *JournalRepository) CountWithoutMood(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM journal_entries WHERE user_id = $1 AND workspace_id = $2 AND ` + noMoodCondition
+	var count int
+	err := r.pool.QueryRow(ctx, query, userID, tenant.WorkspaceID(ctx, userID)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count journal entries without mood: %w", err)
+	}
+	return count, nil
+}
+
+// Search searches journal entries by title or content.
+// End-to-end encrypted entries are excluded since their content is ciphertext, and vault entries
+// unless includeVault is set.
+func (r *JournalRepository) Search(ctx context.Context, userID uuid.UUID, searchTerm string, includeVault bool, limit, offset int) ([]domain.JournalEntry, error) {
+	query := `
+		SELECT id, user_id, title, content, mood, tags, word_count, content_format, encryption, is_public, is_vault, project_id, created_at, updated_at
+		FROM journal_entries
+		WHERE user_id = $1 AND workspace_id = $5
+		  AND content_format = 'plain'
+		  AND (NOT is_vault OR $6)
 		  AND (title ILIKE $2 OR content ILIKE $2)
 		ORDER BY created_at DESC
 		LIMIT $3 OFFSET $4
 	`
 	searchPattern := "%" + searchTerm + "%"
-	rows, err := r.pool.Query(ctx, query, userID, searchPattern, limit, offset)
+	rows, err := r.pool.Query(ctx, query, userID, searchPattern, limit, offset, tenant.WorkspaceID(ctx, userID), includeVault)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search journal entries: %w", err)
 	}
@@ -178,6 +398,12 @@ func (r *JournalRepository) Search(ctx context.Context, userID uuid.UUID, search
 			&entry.Content,
 			&entry.Mood,
 			&entry.Tags,
+			&entry.WordCount,
+			&entry.ContentFormat,
+			&entry.Encryption,
+			&entry.IsPublic,
+			&entry.IsVault,
+			&entry.ProjectID,
 			&entry.CreatedAt,
 			&entry.UpdatedAt,
 		)
@@ -194,8 +420,9 @@ func (r *JournalRepository) Search(ctx context.Context, userID uuid.UUID, search
 func (r *JournalRepository) Update(ctx context.Context, entry *domain.JournalEntry) error {
 	query := `
 		UPDATE journal_entries
-		SET title = $2, content = $3, mood = $4, tags = $5, updated_at = $6
-		WHERE id = $1 AND user_id = $7
+		SET title = $2, content = $3, mood = $4, tags = $5, word_count = $6,
+		    content_format = $7, encryption = $8, updated_at = $9, is_public = $12, is_vault = $13
+		WHERE id = $1 AND user_id = $10 AND workspace_id = $11
 	`
 	result, err := r.pool.Exec(ctx, query,
 		entry.ID,
@@ -203,38 +430,273 @@ func (r *JournalRepository) Update(ctx context.Context, entry *domain.JournalEnt
 		entry.Content,
 		entry.Mood,
 		entry.Tags,
+		entry.WordCount,
+		entry.ContentFormat,
+		entry.Encryption,
 		entry.UpdatedAt,
 		entry.UserID,
+		tenant.WorkspaceID(ctx, entry.UserID),
+		entry.IsPublic,
+		entry.IsVault,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update journal entry: %w", err)
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("journal entry not found or unauthorized")
+		return apperr.New(apperr.ErrNotFound, "journal entry not found or unauthorized")
 	}
 	return nil
 }
 
 // Delete removes a journal entry
 func (r *JournalRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
-	query := `DELETE FROM journal_entries WHERE id = $1 AND user_id = $2`
-	result, err := r.pool.Exec(ctx, query, id, userID)
+	query := `DELETE FROM journal_entries WHERE id = $1 AND user_id = $2 AND workspace_id = $3`
+	result, err := r.pool.Exec(ctx, query, id, userID, tenant.WorkspaceID(ctx, userID))
 	if err != nil {
 		return fmt.Errorf("failed to delete journal entry: %w", err)
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("journal entry not found or unauthorized")
+		return apperr.New(apperr.ErrNotFound, "journal entry not found or unauthorized")
 	}
 	return nil
 }
 
+// FindAllByUserID retrieves every journal entry for a user, oldest first
+func (r *JournalRepository) FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]domain.JournalEntry, error) {
+	query := `
+		SELECT id, user_id, title, content, mood, tags, word_count, content_format, encryption, is_public, is_vault, project_id, created_at, updated_at
+		FROM journal_entries
+		WHERE user_id = $1 AND workspace_id = $2
+		ORDER BY created_at ASC
+	`
+	rows, err := r.pool.Query(ctx, query, userID, tenant.WorkspaceID(ctx, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.JournalEntry
+	for rows.Next() {
+		var entry domain.JournalEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Title,
+			&entry.Content,
+			&entry.Mood,
+			&entry.Tags,
+			&entry.WordCount,
+			&entry.ContentFormat,
+			&entry.Encryption,
+			&entry.IsPublic,
+			&entry.IsVault,
+			&entry.ProjectID,
+			&entry.CreatedAt,
+			&entry.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating journal entries: %w", err)
+	}
+
+	return entries, nil
+}
+
 // Count returns the total number of entries for a user
 func (r *JournalRepository) Count(ctx context.Context, userID uuid.UUID) (int, error) {
-	query := `SELECT COUNT(*) FROM journal_entries WHERE user_id = $1`
+	query := `SELECT COUNT(*) FROM journal_entries WHERE user_id = $1 AND workspace_id = $2`
 	var count int
-	err := r.pool.QueryRow(ctx, query, userID).Scan(&count)
+	err := r.pool.QueryRow(ctx, query, userID, tenant.WorkspaceID(ctx, userID)).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count journal entries: %w", err)
 	}
 	return count, nil
 }
+
+// GetWritingStats returns word totals for a user plus per-week totals for the given number of weeks
+func (r *JournalRepository) GetWritingStats(ctx context.Context, userID uuid.UUID, weeks int) (*domain.WritingStats, error) {
+	query := `
+		SELECT COALESCE(SUM(word_count), 0), COUNT(*)
+		FROM journal_entries
+		WHERE user_id = $1 AND workspace_id = $2
+	`
+	var stats domain.WritingStats
+	err := r.pool.QueryRow(ctx, query, userID, tenant.WorkspaceID(ctx, userID)).Scan(&stats.TotalWords, &stats.TotalEntries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get writing totals: %w", err)
+	}
+
+	// Generate every week in the range so weeks without entries report zero
+	weeklyQuery := `
+		SELECT w.week_start, COALESCE(SUM(je.word_count), 0), COUNT(je.id)
+		FROM generate_series(
+			DATE_TRUNC('week', CURRENT_DATE) - ($2 - 1) * INTERVAL '1 week',
+			DATE_TRUNC('week', CURRENT_DATE),
+			INTERVAL '1 week'
+		) AS w(week_start)
+		LEFT JOIN journal_entries je
+			ON je.user_id = $1 AND je.workspace_id = $3
+			AND je.created_at >= w.week_start
+			AND je.created_at < w.week_start + INTERVAL '1 week'
+		GROUP BY w.week_start
+		ORDER BY w.week_start ASC
+	`
+	rows, err := r.pool.Query(ctx, weeklyQuery, userID, weeks, tenant.WorkspaceID(ctx, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly word counts: %w", err)
+	}
+	defer rows.Close()
+
+	stats.Weekly = []domain.WeeklyWordCount{}
+	for rows.Next() {
+		var week domain.WeeklyWordCount
+		if err := rows.Scan(&week.WeekStart, &week.Words, &week.Entries); err != nil {
+			return nil, fmt.Errorf("failed to scan weekly word count: %w", err)
+		}
+		stats.Weekly = append(stats.Weekly, week)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating weekly word counts: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// FindPublicByUsers retrieves public entries by any of the given users created before a time, newest first
+func (r *JournalRepository) FindPublicByUsers(ctx context.Context, userIDs []uuid.UUID, before time.Time, limit int) ([]domain.JournalEntry, error) {
+	query := `
+		SELECT id, user_id, title, content, mood, tags, word_count, content_format, encryption, is_public, is_vault, project_id, created_at, updated_at
+		FROM journal_entries
+		WHERE user_id = ANY($1) AND is_public = true AND created_at < $2
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+	rows, err := r.pool.Query(ctx, query, userIDs, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find public journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.JournalEntry
+	for rows.Next() {
+		var entry domain.JournalEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Title,
+			&entry.Content,
+			&entry.Mood,
+			&entry.Tags,
+			&entry.WordCount,
+			&entry.ContentFormat,
+			&entry.Encryption,
+			&entry.IsPublic,
+			&entry.IsVault,
+			&entry.ProjectID,
+			&entry.CreatedAt,
+			&entry.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating journal entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// FindByProject retrieves a project's entries created before a time, newest first
+func (r *JournalRepository) FindByProject(ctx context.Context, projectID uuid.UUID, before time.Time, limit int) ([]domain.JournalEntry, error) {
+	query := `
+		SELECT id, user_id, title, content, mood, tags, word_count, content_format, encryption, is_public, is_vault, project_id, created_at, updated_at
+		FROM journal_entries
+		WHERE project_id = $1 AND created_at < $2
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+	rows, err := r.pool.Query(ctx, query, projectID, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.JournalEntry
+	for rows.Next() {
+		var entry domain.JournalEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Title,
+			&entry.Content,
+			&entry.Mood,
+			&entry.Tags,
+			&entry.WordCount,
+			&entry.ContentFormat,
+			&entry.Encryption,
+			&entry.IsPublic,
+			&entry.IsVault,
+			&entry.ProjectID,
+			&entry.CreatedAt,
+			&entry.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating journal entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// SetProject attaches one of the user's entries to a project, or detaches it when projectID is nil.
+// It reports whether the entry was found.
+func (r *JournalRepository) SetProject(ctx context.Context, id, userID uuid.UUID, projectID *uuid.UUID) (bool, error) {
+	query := `UPDATE journal_entries SET project_id = $3 WHERE id = $1 AND user_id = $2`
+	result, err := r.pool.Exec(ctx, query, id, userID, projectID)
+	if err != nil {
+		return false, fmt.Errorf("failed to set journal entry project: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// PublicAuthors returns when each user with public entries last updated one, most recent first
+func (r *JournalRepository) PublicAuthors(ctx context.Context, limit int) (map[uuid.UUID]time.Time, error) {
+	query := `
+		SELECT user_id, MAX(updated_at)
+		FROM journal_entries
+		WHERE is_public = true
+		GROUP BY user_id
+		ORDER BY MAX(updated_at) DESC
+		LIMIT $1
+	`
+	rows, err := r.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find public authors: %w", err)
+	}
+	defer rows.Close()
+
+	authors := make(map[uuid.UUID]time.Time)
+	for rows.Next() {
+		var userID uuid.UUID
+		var updatedAt time.Time
+		if err := rows.Scan(&userID, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan public author: %w", err)
+		}
+		authors[userID] = updatedAt
+	}
+	return authors, rows.Err()
+}
@@ -0,0 +1,382 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/tenant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LearningPathRepository handles learning paths, their topics, and each user's progress with raw SQL
+type LearningPathRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewLearningPathRepository creates a new learning path repository
+func NewLearningPathRepository(pool *pgxpool.Pool) *LearningPathRepository {
+	return &LearningPathRepository{pool: pool}
+}
+
+const learningPathColumns = `p.id, p.user_id, p.group_id, p.title, p.description, p.created_at, p.updated_at`
+
+// pathProgressColumns counts a path's topics and those completed by the user in $1
+const pathProgressColumns = `
+	(SELECT COUNT(*) FROM learning_path_topics t WHERE t.path_id = p.id),
+	(SELECT COUNT(*) FROM learning_path_topics t
+		JOIN learning_path_completions c ON c.topic_id = t.id AND c.user_id = $1
+		WHERE t.path_id = p.id)`
+
+// scanPathWithProgress scans learningPathColumns followed by pathProgressColumns and any extra destinations
+func scanPathWithProgress(row pgx.Row, extra ...any) (*domain.LearningPath, error) {
+	var p domain.LearningPath
+	var total, completed int
+	dest := append([]any{&p.ID, &p.UserID, &p.GroupID, &p.Title, &p.Description, &p.CreatedAt, &p.UpdatedAt, &total, &completed}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	p.Progress = domain.NewPathProgress(completed, total)
+	return &p, nil
+}
+
+// Create inserts a new learning path and its topics in the current workspace
+func (r *LearningPathRepository) Create(ctx context.Context, p *domain.LearningPath) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO learning_paths (id, user_id, workspace_id, title, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, p.ID, p.UserID, tenant.WorkspaceID(ctx, p.UserID), p.Title, p.Description, p.CreatedAt, p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create learning path: %w", err)
+	}
+
+	for _, t := range p.Topics {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO learning_path_topics (id, path_id, position, title, description)
+			VALUES ($1, $2, $3, $4, $5)
+		`, t.ID, p.ID, t.Position, t.Title, t.Description)
+		if err != nil {
+			return fmt.Errorf("failed to create learning path topic: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// FindByID retrieves a learning path with the user's progress
+func (r *LearningPathRepository) FindByID(ctx context.Context, id, userID uuid.UUID) (*domain.LearningPath, error) {
+	query := `
+		SELECT ` + learningPathColumns + `,` + pathProgressColumns + `
+		FROM learning_paths p
+		WHERE p.id = $2 AND ($3::uuid IS NULL OR p.workspace_id = $3)
+	`
+	p, err := scanPathWithProgress(r.pool.QueryRow(ctx, query, userID, id, optionalWorkspace(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find learning path: %w", err)
+	}
+	return p, nil
+}
+
+// ListAccessible retrieves the paths in the current workspace that the user owns or that are
+// shared into one of their study groups, most recently updated first
+func (r *LearningPathRepository) ListAccessible(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.LearningPath, int, error) {
+	query := `
+		SELECT ` + learningPathColumns + `,` + pathProgressColumns + `, COUNT(*) OVER()
+		FROM learning_paths p
+		WHERE p.workspace_id = $2 AND (p.user_id = $1 OR p.group_id IN (
+			SELECT group_id FROM study_group_members WHERE user_id = $1
+		))
+		ORDER BY p.updated_at DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.pool.Query(ctx, query, userID, tenant.WorkspaceID(ctx, userID), limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list learning paths: %w", err)
+	}
+	defer rows.Close()
+
+	paths := []domain.LearningPath{}
+	total := 0
+	for rows.Next() {
+		p, err := scanPathWithProgress(rows, &total)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan learning path: %w", err)
+		}
+		paths = append(paths, *p)
+	}
+	return paths, total, rows.Err()
+}
+
+// ListFollowed retrieves up to limit paths in the current workspace that the user owns or has
+// completed a topic of, most recently updated first
+func (r *LearningPathRepository) ListFollowed(ctx context.Context, userID uuid.UUID, limit int) ([]domain.LearningPath, error) {
+	query := `
+		SELECT ` + learningPathColumns + `,` + pathProgressColumns + `
+		FROM learning_paths p
+		WHERE p.workspace_id = $2 AND (p.user_id = $1 OR EXISTS (
+			SELECT 1 FROM learning_path_topics t
+			JOIN learning_path_completions c ON c.topic_id = t.id AND c.user_id = $1
+			WHERE t.path_id = p.id
+		))
+		ORDER BY p.updated_at DESC
+		LIMIT $3
+	`
+	return r.queryPaths(ctx, query, userID, tenant.WorkspaceID(ctx, userID), limit)
+}
+
+// ListByGroup retrieves the paths shared into a study group with the user's progress
+func (r *LearningPathRepository) ListByGroup(ctx context.Context, groupID, userID uuid.UUID) ([]domain.LearningPath, error) {
+	query := `
+		SELECT ` + learningPathColumns + `,` + pathProgressColumns + `
+		FROM learning_paths p
+		WHERE p.group_id = $2
+		ORDER BY p.updated_at DESC
+	`
+	return r.queryPaths(ctx, query, userID, groupID)
+}
+
+func (r *LearningPathRepository) queryPaths(ctx context.Context, query string, args ...any) ([]domain.LearningPath, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list learning paths: %w", err)
+	}
+	defer rows.Close()
+
+	paths := []domain.LearningPath{}
+	for rows.Next() {
+		p, err := scanPathWithProgress(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan learning path: %w", err)
+		}
+		paths = append(paths, *p)
+	}
+	return paths, rows.Err()
+}
+
+// Update saves a path's title and description
+func (r *LearningPathRepository) Update(ctx context.Context, p *domain.LearningPath) error {
+	query := `UPDATE learning_paths SET title = $3, description = $4, updated_at = $5 WHERE id = $1 AND user_id = $2`
+	if _, err := r.pool.Exec(ctx, query, p.ID, p.UserID, p.Title, p.Description, p.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update learning path: %w", err)
+	}
+	return nil
+}
+
+// SetGroup shares a path into a study group, or stops sharing it when groupID is nil
+func (r *LearningPathRepository) SetGroup(ctx context.Context, id uuid.UUID, groupID *uuid.UUID) error {
+	query := `UPDATE learning_paths SET group_id = $2, updated_at = NOW() WHERE id = $1`
+	if _, err := r.pool.Exec(ctx, query, id, groupID); err != nil {
+		return fmt.Errorf("failed to share learning path: %w", err)
+	}
+	return nil
+}
+
+// Touch bumps a path's updated_at after its topics change
+func (r *LearningPathRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `UPDATE learning_paths SET updated_at = NOW() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to update learning path: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a path with its topics and everyone's progress on it
+func (r *LearningPathRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM learning_paths WHERE id = $1 AND user_id = $2`, id, userID); err != nil {
+		return fmt.Errorf("failed to delete learning path: %w", err)
+	}
+	return nil
+}
+
+// Topics retrieves a path's topics in order with the user's completion times
+func (r *LearningPathRepository) Topics(ctx context.Context, pathID, userID uuid.UUID) ([]domain.PathTopic, error) {
+	query := `
+		SELECT t.id, t.path_id, t.position, t.title, t.description, c.completed_at
+		FROM learning_path_topics t
+		LEFT JOIN learning_path_completions c ON c.topic_id = t.id AND c.user_id = $2
+		WHERE t.path_id = $1
+		ORDER BY t.position, t.created_at
+	`
+	rows, err := r.pool.Query(ctx, query, pathID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list learning path topics: %w", err)
+	}
+	defer rows.Close()
+
+	topics := []domain.PathTopic{}
+	for rows.Next() {
+		var t domain.PathTopic
+		if err := rows.Scan(&t.ID, &t.PathID, &t.Position, &t.Title, &t.Description, &t.CompletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan learning path topic: %w", err)
+		}
+		t.Evidence = []domain.PathEvidence{}
+		topics = append(topics, t)
+	}
+	return topics, rows.Err()
+}
+
+// FindTopic retrieves a topic of a path, or nil if there is none
+func (r *LearningPathRepository) FindTopic(ctx context.Context, id, pathID uuid.UUID) (*domain.PathTopic, error) {
+	var t domain.PathTopic
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, path_id, position, title, description FROM learning_path_topics WHERE id = $1 AND path_id = $2
+	`, id, pathID).Scan(&t.ID, &t.PathID, &t.Position, &t.Title, &t.Description)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find learning path topic: %w", err)
+	}
+	return &t, nil
+}
+
+// CountTopics counts a path's topics
+func (r *LearningPathRepository) CountTopics(ctx context.Context, pathID uuid.UUID) (int, error) {
+	var count int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM learning_path_topics WHERE path_id = $1`, pathID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count learning path topics: %w", err)
+	}
+	return count, nil
+}
+
+// AddTopic appends a topic to the end of its path and sets its position
+func (r *LearningPathRepository) AddTopic(ctx context.Context, t *domain.PathTopic) error {
+	query := `
+		INSERT INTO learning_path_topics (id, path_id, position, title, description)
+		SELECT $1, $2, COALESCE(MAX(position) + 1, 0), $3, $4 FROM learning_path_topics WHERE path_id = $2
+		RETURNING position
+	`
+	if err := r.pool.QueryRow(ctx, query, t.ID, t.PathID, t.Title, t.Description).Scan(&t.Position); err != nil {
+		return fmt.Errorf("failed to add learning path topic: %w", err)
+	}
+	return nil
+}
+
+// UpdateTopic saves a topic's title and description
+func (r *LearningPathRepository) UpdateTopic(ctx context.Context, t *domain.PathTopic) error {
+	query := `UPDATE learning_path_topics SET title = $3, description = $4 WHERE id = $1 AND path_id = $2`
+	if _, err := r.pool.Exec(ctx, query, t.ID, t.PathID, t.Title, t.Description); err != nil {
+		return fmt.Errorf("failed to update learning path topic: %w", err)
+	}
+	return nil
+}
+
+// DeleteTopic removes a topic and closes the gap it leaves in the path's order
+func (r *LearningPathRepository) DeleteTopic(ctx context.Context, id, pathID uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var position int
+	err = tx.QueryRow(ctx, `DELETE FROM learning_path_topics WHERE id = $1 AND path_id = $2 RETURNING position`, id, pathID).Scan(&position)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete learning path topic: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE learning_path_topics SET position = position - 1 WHERE path_id = $1 AND position > $2`, pathID, position); err != nil {
+		return fmt.Errorf("failed to reorder learning path topics: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ReorderTopics sets each topic's position to its index in ids
+func (r *LearningPathRepository) ReorderTopics(ctx context.Context, pathID uuid.UUID, ids []uuid.UUID) error {
+	query := `UPDATE learning_path_topics SET position = array_position($2::uuid[], id) - 1 WHERE path_id = $1 AND id = ANY($2)`
+	if _, err := r.pool.Exec(ctx, query, pathID, ids); err != nil {
+		return fmt.Errorf("failed to reorder learning path topics: %w", err)
+	}
+	return nil
+}
+
+// Complete marks a topic completed by the user. Completing it again keeps the first time.
+func (r *LearningPathRepository) Complete(ctx context.Context, topicID, userID uuid.UUID, at time.Time) error {
+	query := `
+		INSERT INTO learning_path_completions (topic_id, user_id, completed_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := r.pool.Exec(ctx, query, topicID, userID, at); err != nil {
+		return fmt.Errorf("failed to complete learning path topic: %w", err)
+	}
+	return nil
+}
+
+// Reopen clears the user's completion of a topic
+func (r *LearningPathRepository) Reopen(ctx context.Context, topicID, userID uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM learning_path_completions WHERE topic_id = $1 AND user_id = $2`, topicID, userID); err != nil {
+		return fmt.Errorf("failed to reopen learning path topic: %w", err)
+	}
+	return nil
+}
+
+// LinkEvidence links an entry or snippet to a topic for the user
+func (r *LearningPathRepository) LinkEvidence(ctx context.Context, topicID, userID uuid.UUID, evidenceType, refID string) error {
+	query := `
+		INSERT INTO learning_path_evidence (topic_id, user_id, type, ref_id, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := r.pool.Exec(ctx, query, topicID, userID, evidenceType, refID); err != nil {
+		return fmt.Errorf("failed to link learning path evidence: %w", err)
+	}
+	return nil
+}
+
+// UnlinkEvidence removes evidence from a topic and reports whether it was linked
+func (r *LearningPathRepository) UnlinkEvidence(ctx context.Context, topicID, userID uuid.UUID, evidenceType, refID string) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM learning_path_evidence WHERE topic_id = $1 AND user_id = $2 AND type = $3 AND ref_id = $4
+	`, topicID, userID, evidenceType, refID)
+	if err != nil {
+		return false, fmt.Errorf("failed to unlink learning path evidence: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Evidence retrieves the user's evidence for a path's topics, keyed by topic, oldest first.
+// Entry evidence is titled here and entries that were deleted are left out; snippet titles are
+// filled in from MongoDB.
+func (r *LearningPathRepository) Evidence(ctx context.Context, pathID, userID uuid.UUID) (map[uuid.UUID][]domain.PathEvidence, error) {
+	query := `
+		SELECT e.topic_id, e.type, e.ref_id, COALESCE(j.title, ''), e.created_at
+		FROM learning_path_evidence e
+		JOIN learning_path_topics t ON t.id = e.topic_id
+		LEFT JOIN journal_entries j ON e.type = 'entry' AND j.id::text = e.ref_id
+		WHERE t.path_id = $1 AND e.user_id = $2 AND (e.type <> 'entry' OR j.id IS NOT NULL)
+		ORDER BY e.created_at
+	`
+	rows, err := r.pool.Query(ctx, query, pathID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list learning path evidence: %w", err)
+	}
+	defer rows.Close()
+
+	evidence := make(map[uuid.UUID][]domain.PathEvidence)
+	for rows.Next() {
+		var topicID uuid.UUID
+		var e domain.PathEvidence
+		if err := rows.Scan(&topicID, &e.Type, &e.ID, &e.Title, &e.LinkedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan learning path evidence: %w", err)
+		}
+		evidence[topicID] = append(evidence[topicID], e)
+	}
+	return evidence, rows.Err()
+}
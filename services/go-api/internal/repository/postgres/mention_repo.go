@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MentionRepository handles mention persistence with raw SQL
+type MentionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewMentionRepository creates a new mention repository
+func NewMentionRepository(pool *pgxpool.Pool) *MentionRepository {
+	return &MentionRepository{pool: pool}
+}
+
+// Create saves mentions, skipping users already mentioned by the same source, and returns the new ones
+func (r *MentionRepository) Create(ctx context.Context, mentions []domain.Mention) ([]domain.Mention, error) {
+	query := `
+		INSERT INTO mentions (id, user_id, author_id, source_type, source_id, context_id, context_name, excerpt, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id, source_type, source_id) DO NOTHING
+	`
+	var created []domain.Mention
+	for _, m := range mentions {
+		result, err := r.pool.Exec(ctx, query,
+			m.ID, m.UserID, m.AuthorID, m.SourceType, m.SourceID, m.ContextID, m.ContextName, m.Excerpt, m.CreatedAt,
+		)
+		if err != nil {
+			return created, fmt.Errorf("failed to create mention: %w", err)
+		}
+		if result.RowsAffected() == 1 {
+			created = append(created, m)
+		}
+	}
+	return created, nil
+}
+
+// ListByUser retrieves where a user was mentioned, newest first, with the total count
+func (r *MentionRepository) ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]domain.Mention, int, error) {
+	query := `
+		SELECT m.id, m.user_id, m.author_id, u.display_name, m.source_type, m.source_id,
+			m.context_id, m.context_name, m.excerpt, m.created_at, m.read_at
+		FROM mentions m
+		JOIN users u ON u.id = m.author_id
+		WHERE m.user_id = $1 AND (NOT $2 OR m.read_at IS NULL)
+		ORDER BY m.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.pool.Query(ctx, query, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list mentions: %w", err)
+	}
+	defer rows.Close()
+
+	mentions := []domain.Mention{}
+	for rows.Next() {
+		var m domain.Mention
+		if err := rows.Scan(&m.ID, &m.UserID, &m.AuthorID, &m.AuthorName, &m.SourceType, &m.SourceID,
+			&m.ContextID, &m.ContextName, &m.Excerpt, &m.CreatedAt, &m.ReadAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan mention: %w", err)
+		}
+		mentions = append(mentions, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating mentions: %w", err)
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM mentions WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)`
+	if err := r.pool.QueryRow(ctx, countQuery, userID, unreadOnly).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count mentions: %w", err)
+	}
+	return mentions, total, nil
+}
+
+// CountUnread counts a user's unread mentions
+func (r *MentionRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM mentions WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread mentions: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks a user's mentions as read: those in ids, or all of them when ids is empty.
+// It returns how many were unread.
+func (r *MentionRepository) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int, error) {
+	query := `
+		UPDATE mentions SET read_at = $3
+		WHERE user_id = $1 AND read_at IS NULL AND (cardinality($2::uuid[]) = 0 OR id = ANY($2))
+	`
+	if ids == nil {
+		ids = []uuid.UUID{}
+	}
+	result, err := r.pool.Exec(ctx, query, userID, ids, at)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark mentions read: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
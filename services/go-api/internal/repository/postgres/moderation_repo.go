@@ -0,0 +1,407 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ModerationRepository handles chat moderation settings and message reports
+type ModerationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewModerationRepository creates a new moderation repository
+func NewModerationRepository(pool *pgxpool.Pool) *ModerationRepository {
+	return &ModerationRepository{pool: pool}
+}
+
+// GetSettings retrieves the moderation settings for a group (nil if never configured)
+func (r *ModerationRepository) GetSettings(ctx context.Context, groupID uuid.UUID) (*domain.GroupModerationSettings, error) {
+	query := `
+		SELECT group_id, profanity_filter, blocked_patterns, max_message_length, updated_at
+		FROM study_group_moderation
+		WHERE group_id = $1
+	`
+	var settings domain.GroupModerationSettings
+	err := r.pool.QueryRow(ctx, query, groupID).Scan(
+		&settings.GroupID,
+		&settings.ProfanityFilter,
+		&settings.BlockedPatterns,
+		&settings.MaxMessageLength,
+		&settings.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// UpsertSettings creates or replaces the moderation settings for a group
+func (r *ModerationRepository) UpsertSettings(ctx context.Context, settings *domain.GroupModerationSettings) error {
+	query := `
+		INSERT INTO study_group_moderation (group_id, profanity_filter, blocked_patterns, max_message_length, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (group_id)
+		DO UPDATE SET
+			profanity_filter = $2,
+			blocked_patterns = $3,
+			max_message_length = $4,
+			updated_at = $5
+	`
+	_, err := r.pool.Exec(ctx, query,
+		settings.GroupID,
+		settings.ProfanityFilter,
+		settings.BlockedPatterns,
+		settings.MaxMessageLength,
+		settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert moderation settings: %w", err)
+	}
+	return nil
+}
+
+// CreateReport inserts a message report; duplicate reports by the same user are ignored
+func (r *ModerationRepository) CreateReport(ctx context.Context, report *domain.MessageReport) error {
+	query := `
+		INSERT INTO message_reports (id, group_id, message_id, message_user_id, message_content, reporter_id, reason, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (message_id, reporter_id) DO NOTHING
+	`
+	var messageUserID *uuid.UUID
+	if report.MessageUserID != uuid.Nil {
+		messageUserID = &report.MessageUserID
+	}
+	_, err := r.pool.Exec(ctx, query,
+		report.ID,
+		report.GroupID,
+		report.MessageID,
+		messageUserID,
+		report.MessageContent,
+		report.ReporterID,
+		report.Reason,
+		report.Status,
+		report.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create message report: %w", err)
+	}
+	return nil
+}
+
+// ListReports retrieves message reports with the given status, oldest first
+func (r *ModerationRepository) ListReports(ctx context.Context, status string, limit, offset int) ([]domain.MessageReport, error) {
+	query := `
+		SELECT id, group_id, message_id, message_user_id, message_content, reporter_id, reason,
+		       status, COALESCE(resolution_note, ''), resolved_by, resolved_at, created_at
+		FROM message_reports
+		WHERE status = $1
+		ORDER BY created_at ASC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.pool.Query(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list message reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []domain.MessageReport
+	for rows.Next() {
+		var report domain.MessageReport
+		var messageUserID *uuid.UUID
+		err := rows.Scan(
+			&report.ID,
+			&report.GroupID,
+			&report.MessageID,
+			&messageUserID,
+			&report.MessageContent,
+			&report.ReporterID,
+			&report.Reason,
+			&report.Status,
+			&report.ResolutionNote,
+			&report.ResolvedBy,
+			&report.ResolvedAt,
+			&report.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message report: %w", err)
+		}
+		if messageUserID != nil {
+			report.MessageUserID = *messageUserID
+		}
+		reports = append(reports, report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating message reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// CountReports returns the number of reports with the given status
+func (r *ModerationRepository) CountReports(ctx context.Context, status string) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM message_reports WHERE status = $1`, status).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count message reports: %w", err)
+	}
+	return count, nil
+}
+
+// ResolveReport records an admin decision on a pending report
+func (r *ModerationRepository) ResolveReport(ctx context.Context, id uuid.UUID, status, note string, resolvedBy uuid.UUID) error {
+	query := `
+		UPDATE message_reports
+		SET status = $2, resolution_note = $3, resolved_by = $4, resolved_at = $5
+		WHERE id = $1 AND status = 'pending'
+	`
+	result, err := r.pool.Exec(ctx, query, id, status, note, resolvedBy, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to resolve message report: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperr.New(apperr.ErrNotFound, "message report not found or already resolved")
+	}
+	return nil
+}
+
+// FindReport retrieves a message report by ID
+func (r *ModerationRepository) FindReport(ctx context.Context, id uuid.UUID) (*domain.MessageReport, error) {
+	query := `
+		SELECT id, group_id, message_id, message_user_id, message_content, reporter_id, reason,
+		       status, COALESCE(resolution_note, ''), resolved_by, resolved_at, created_at
+		FROM message_reports
+		WHERE id = $1
+	`
+	var report domain.MessageReport
+	var messageUserID *uuid.UUID
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&report.ID,
+		&report.GroupID,
+		&report.MessageID,
+		&messageUserID,
+		&report.MessageContent,
+		&report.ReporterID,
+		&report.Reason,
+		&report.Status,
+		&report.ResolutionNote,
+		&report.ResolvedBy,
+		&report.ResolvedAt,
+		&report.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find message report: %w", err)
+	}
+	if messageUserID != nil {
+		report.MessageUserID = *messageUserID
+	}
+	return &report, nil
+}
+
+// CreateContentReport inserts a content report; duplicate reports by the same user are ignored
+func (r *ModerationRepository) CreateContentReport(ctx context.Context, report *domain.ContentReport) error {
+	query := `
+		INSERT INTO content_reports (id, content_type, content_id, content_owner_id, content_snapshot, reporter_id, reason, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (content_type, content_id, reporter_id) DO NOTHING
+	`
+	var ownerID *uuid.UUID
+	if report.ContentOwnerID != uuid.Nil {
+		ownerID = &report.ContentOwnerID
+	}
+	_, err := r.pool.Exec(ctx, query,
+		report.ID,
+		report.ContentType,
+		report.ContentID,
+		ownerID,
+		report.ContentSnapshot,
+		report.ReporterID,
+		report.Reason,
+		report.Status,
+		report.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create content report: %w", err)
+	}
+	return nil
+}
+
+const contentReportColumns = `
+	id, content_type, content_id, content_owner_id, content_snapshot, reporter_id, reason,
+	status, COALESCE(resolution_note, ''), resolved_by, resolved_at, created_at
+`
+
+// scanContentReport scans a single content_reports row
+func scanContentReport(row pgx.Row) (*domain.ContentReport, error) {
+	var report domain.ContentReport
+	var ownerID *uuid.UUID
+	err := row.Scan(
+		&report.ID,
+		&report.ContentType,
+		&report.ContentID,
+		&ownerID,
+		&report.ContentSnapshot,
+		&report.ReporterID,
+		&report.Reason,
+		&report.Status,
+		&report.ResolutionNote,
+		&report.ResolvedBy,
+		&report.ResolvedAt,
+		&report.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID != nil {
+		report.ContentOwnerID = *ownerID
+	}
+	return &report, nil
+}
+
+// FindContentReport retrieves a content report by ID
+func (r *ModerationRepository) FindContentReport(ctx context.Context, id uuid.UUID) (*domain.ContentReport, error) {
+	report, err := scanContentReport(r.pool.QueryRow(ctx, `SELECT `+contentReportColumns+` FROM content_reports WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find content report: %w", err)
+	}
+	return report, nil
+}
+
+// ListContentReports retrieves content reports with the given status, oldest first
+func (r *ModerationRepository) ListContentReports(ctx context.Context, status string, limit, offset int) ([]domain.ContentReport, error) {
+	query := `
+		SELECT ` + contentReportColumns + `
+		FROM content_reports
+		WHERE status = $1
+		ORDER BY created_at ASC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.pool.Query(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list content reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []domain.ContentReport
+	for rows.Next() {
+		report, err := scanContentReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan content report: %w", err)
+		}
+		reports = append(reports, *report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating content reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// CountContentReports returns the number of content reports with the given status
+func (r *ModerationRepository) CountContentReports(ctx context.Context, status string) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM content_reports WHERE status = $1`, status).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count content reports: %w", err)
+	}
+	return count, nil
+}
+
+// ResolveContentReport records an admin decision on a pending content report
+func (r *ModerationRepository) ResolveContentReport(ctx context.Context, id uuid.UUID, status, note string, resolvedBy uuid.UUID) error {
+	query := `
+		UPDATE content_reports
+		SET status = $2, resolution_note = $3, resolved_by = $4, resolved_at = $5
+		WHERE id = $1 AND status = 'pending'
+	`
+	result, err := r.pool.Exec(ctx, query, id, status, note, resolvedBy, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to resolve content report: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperr.New(apperr.ErrNotFound, "content report not found or already resolved")
+	}
+	return nil
+}
+
+// CreateWarning records a warning issued to a user
+func (r *ModerationRepository) CreateWarning(ctx context.Context, warning *domain.UserWarning) error {
+	query := `
+		INSERT INTO user_warnings (id, user_id, issued_by, reason, report_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		warning.ID,
+		warning.UserID,
+		warning.IssuedBy,
+		warning.Reason,
+		warning.ReportID,
+		warning.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create user warning: %w", err)
+	}
+	return nil
+}
+
+// ListWarnings retrieves the warnings issued to a user, newest first
+func (r *ModerationRepository) ListWarnings(ctx context.Context, userID uuid.UUID) ([]domain.UserWarning, error) {
+	query := `
+		SELECT id, user_id, issued_by, reason, report_id, created_at
+		FROM user_warnings
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user warnings: %w", err)
+	}
+	defer rows.Close()
+
+	var warnings []domain.UserWarning
+	for rows.Next() {
+		var warning domain.UserWarning
+		var issuedBy *uuid.UUID
+		err := rows.Scan(
+			&warning.ID,
+			&warning.UserID,
+			&issuedBy,
+			&warning.Reason,
+			&warning.ReportID,
+			&warning.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user warning: %w", err)
+		}
+		if issuedBy != nil {
+			warning.IssuedBy = *issuedBy
+		}
+		warnings = append(warnings, warning)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user warnings: %w", err)
+	}
+
+	return warnings, nil
+}
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OrganizationRepository handles organization persistence with raw SQL
+type OrganizationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOrganizationRepository creates a new organization repository
+func NewOrganizationRepository(pool *pgxpool.Pool) *OrganizationRepository {
+	return &OrganizationRepository{pool: pool}
+}
+
+// Create inserts an organization together with its workspace and owner membership
+func (r *OrganizationRepository) Create(ctx context.Context, org *domain.Organization) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO workspaces (id, name, slug, owner_id, is_personal, created_at)
+		VALUES ($1, $2, $3, $4, false, $5)
+	`, org.ID, org.Name, org.Slug, org.OwnerID, org.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert workspace: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO workspace_members (workspace_id, user_id, role, joined_at)
+		VALUES ($1, $2, 'owner', $3)
+	`, org.ID, org.OwnerID, org.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add organization owner: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO organizations (id, seat_limit, created_at)
+		VALUES ($1, $2, $3)
+	`, org.ID, org.SeatLimit, org.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert organization: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// FindByID retrieves an organization with its seat usage and the user's role
+func (r *OrganizationRepository) FindByID(ctx context.Context, id, userID uuid.UUID) (*domain.Organization, error) {
+	query := `
+		SELECT o.id, w.name, w.slug, w.owner_id, o.seat_limit,
+		       (SELECT COUNT(*) FROM workspace_members WHERE workspace_id = o.id),
+		       COALESCE((SELECT role FROM workspace_members WHERE workspace_id = o.id AND user_id = $2), ''),
+		       o.created_at
+		FROM organizations o
+		JOIN workspaces w ON w.id = o.id
+		WHERE o.id = $1
+	`
+	var org domain.Organization
+	err := r.pool.QueryRow(ctx, query, id, userID).Scan(
+		&org.ID, &org.Name, &org.Slug, &org.OwnerID, &org.SeatLimit, &org.SeatsUsed, &org.Role, &org.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find organization: %w", err)
+	}
+	return &org, nil
+}
+
+// ListForUser retrieves every organization a user belongs to
+func (r *OrganizationRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]domain.Organization, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT o.id, w.name, w.slug, w.owner_id, o.seat_limit,
+		       (SELECT COUNT(*) FROM workspace_members WHERE workspace_id = o.id),
+		       wm.role, o.created_at
+		FROM organizations o
+		JOIN workspaces w ON w.id = o.id
+		JOIN workspace_members wm ON wm.workspace_id = o.id
+		WHERE wm.user_id = $1
+		ORDER BY w.name ASC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query organizations: %w", err)
+	}
+	defer rows.Close()
+
+	var orgs []domain.Organization
+	for rows.Next() {
+		var org domain.Organization
+		if err := rows.Scan(&org.ID, &org.Name, &org.Slug, &org.OwnerID, &org.SeatLimit, &org.SeatsUsed, &org.Role, &org.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan organization: %w", err)
+		}
+		orgs = append(orgs, org)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating organizations: %w", err)
+	}
+
+	return orgs, nil
+}
+
+// UpdateSeatLimit changes an organization's seat limit
+func (r *OrganizationRepository) UpdateSeatLimit(ctx context.Context, id uuid.UUID, seatLimit int) error {
+	_, err := r.pool.Exec(ctx, `UPDATE organizations SET seat_limit = $2 WHERE id = $1`, id, seatLimit)
+	if err != nil {
+		return fmt.Errorf("failed to update seat limit: %w", err)
+	}
+	return nil
+}
+
+// CountActiveMembers counts members with any learning activity since the given date
+func (r *OrganizationRepository) CountActiveMembers(ctx context.Context, id uuid.UUID, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT lp.user_id)
+		FROM learning_progress lp
+		JOIN workspace_members wm ON wm.user_id = lp.user_id
+		WHERE wm.workspace_id = $1 AND lp.date >= $2
+		  AND (lp.entries_count > 0 OR lp.snippets_count > 0 OR lp.tils_count > 0 OR lp.problems_count > 0)
+	`
+	var count int
+	if err := r.pool.QueryRow(ctx, query, id, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count active members: %w", err)
+	}
+	return count, nil
+}
+
+// Contributors returns every member with the number of journal entries they wrote
+// in the organization's workspace since the given time, most active first
+func (r *OrganizationRepository) Contributors(ctx context.Context, id uuid.UUID, since time.Time) ([]domain.OrgContributor, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT wm.user_id, u.display_name, COUNT(je.id)
+		FROM workspace_members wm
+		JOIN users u ON u.id = wm.user_id
+		LEFT JOIN journal_entries je
+		       ON je.user_id = wm.user_id AND je.workspace_id = wm.workspace_id AND je.created_at >= $2
+		WHERE wm.workspace_id = $1
+		GROUP BY wm.user_id, u.display_name
+		ORDER BY COUNT(je.id) DESC, u.display_name ASC
+	`, id, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contributors: %w", err)
+	}
+	defer rows.Close()
+
+	var contributors []domain.OrgContributor
+	for rows.Next() {
+		var c domain.OrgContributor
+		if err := rows.Scan(&c.UserID, &c.DisplayName, &c.EntriesCount); err != nil {
+			return nil, fmt.Errorf("failed to scan contributor: %w", err)
+		}
+		contributors = append(contributors, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating contributors: %w", err)
+	}
+
+	return contributors, nil
+}
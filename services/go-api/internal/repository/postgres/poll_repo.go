@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PollRepository handles group chat polls and their votes with raw SQL
+type PollRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPollRepository creates a new poll repository
+func NewPollRepository(pool *pgxpool.Pool) *PollRepository {
+	return &PollRepository{pool: pool}
+}
+
+const pollColumns = `id, group_id, channel, created_by, question, options, multiple_choice, closes_at, created_at`
+
+func scanPoll(row pgx.Row) (*domain.Poll, error) {
+	var p domain.Poll
+	var options []string
+	if err := row.Scan(&p.ID, &p.GroupID, &p.Channel, &p.CreatedBy, &p.Question, &options, &p.MultipleChoice, &p.ClosesAt, &p.CreatedAt); err != nil {
+		return nil, err
+	}
+	p.Room = domain.ChannelRoom(p.GroupID, p.Channel)
+	p.Options = make([]domain.PollOption, len(options))
+	for i, text := range options {
+		p.Options[i].Text = text
+	}
+	return &p, nil
+}
+
+// Create inserts a new poll
+func (r *PollRepository) Create(ctx context.Context, p *domain.Poll) error {
+	query := `
+		INSERT INTO polls (id, group_id, channel, created_by, question, options, multiple_choice, closes_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.pool.Exec(ctx, query, p.ID, p.GroupID, p.Channel, p.CreatedBy, p.Question, p.OptionTexts(), p.MultipleChoice, p.ClosesAt, p.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create poll: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a poll and its votes
+func (r *PollRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM polls WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete poll: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves a poll of a group without its counts, or nil if there is none
+func (r *PollRepository) FindByID(ctx context.Context, id, groupID uuid.UUID) (*domain.Poll, error) {
+	query := `SELECT ` + pollColumns + ` FROM polls WHERE id = $1 AND group_id = $2`
+	p, err := scanPoll(r.pool.QueryRow(ctx, query, id, groupID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find poll: %w", err)
+	}
+	return p, nil
+}
+
+// ListByGroup retrieves a group's polls created before a time without their counts, newest
+// first. An empty channel lists polls in every channel.
+func (r *PollRepository) ListByGroup(ctx context.Context, groupID uuid.UUID, channel string, before time.Time, limit int) ([]*domain.Poll, error) {
+	query := `
+		SELECT ` + pollColumns + `
+		FROM polls
+		WHERE group_id = $1 AND ($2 = '' OR channel = $2) AND created_at < $3
+		ORDER BY created_at DESC
+		LIMIT $4
+	`
+	rows, err := r.pool.Query(ctx, query, groupID, channel, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list polls: %w", err)
+	}
+	defer rows.Close()
+
+	polls := []*domain.Poll{}
+	for rows.Next() {
+		p, err := scanPoll(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan poll: %w", err)
+		}
+		polls = append(polls, p)
+	}
+	return polls, rows.Err()
+}
+
+// Vote replaces a member's vote in a poll with the given options
+func (r *PollRepository) Vote(ctx context.Context, pollID, userID uuid.UUID, options []int) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM poll_votes WHERE poll_id = $1 AND user_id = $2`, pollID, userID); err != nil {
+		return fmt.Errorf("failed to clear poll vote: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO poll_votes (poll_id, user_id, option_index)
+		SELECT $1, $2, UNNEST($3::SMALLINT[])
+	`, pollID, userID, options)
+	if err != nil {
+		return fmt.Errorf("failed to record poll vote: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Tally fills in the votes for each option of the polls, how many members voted in each, and
+// the options userID voted for
+func (r *PollRepository) Tally(ctx context.Context, polls []*domain.Poll, userID uuid.UUID) error {
+	if len(polls) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*domain.Poll, len(polls))
+	ids := make([]uuid.UUID, len(polls))
+	for i, p := range polls {
+		byID[p.ID], ids[i] = p, p.ID
+		p.Voters, p.MyVotes = 0, nil
+		for j := range p.Options {
+			p.Options[j].Votes = 0
+		}
+	}
+
+	query := `
+		SELECT poll_id, option_index, COUNT(*), BOOL_OR(user_id = $2)
+		FROM poll_votes
+		WHERE poll_id = ANY($1)
+		GROUP BY poll_id, option_index
+		ORDER BY poll_id, option_index
+	`
+	rows, err := r.pool.Query(ctx, query, ids, userID)
+	if err != nil {
+		return fmt.Errorf("failed to tally poll votes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var pollID uuid.UUID
+		var option, votes int
+		var mine bool
+		if err := rows.Scan(&pollID, &option, &votes, &mine); err != nil {
+			return fmt.Errorf("failed to scan poll votes: %w", err)
+		}
+		p := byID[pollID]
+		if option >= len(p.Options) {
+			continue
+		}
+		p.Options[option].Votes = votes
+		if mine {
+			p.MyVotes = append(p.MyVotes, option)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	voterRows, err := r.pool.Query(ctx, `
+		SELECT poll_id, COUNT(DISTINCT user_id) FROM poll_votes WHERE poll_id = ANY($1) GROUP BY poll_id
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to count poll voters: %w", err)
+	}
+	defer voterRows.Close()
+	for voterRows.Next() {
+		var pollID uuid.UUID
+		var voters int
+		if err := voterRows.Scan(&pollID, &voters); err != nil {
+			return fmt.Errorf("failed to scan poll voters: %w", err)
+		}
+		byID[pollID].Voters = voters
+	}
+	return voterRows.Err()
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ProblemRepository handles the solved problem log with raw SQL
+type ProblemRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewProblemRepository creates a new problem repository
+func NewProblemRepository(pool *pgxpool.Pool) *ProblemRepository {
+	return &ProblemRepository{pool: pool}
+}
+
+const problemColumns = `id, user_id, platform, problem_id, title, difficulty, url, snippet_id, notes, solved_at, created_at, updated_at`
+
+func scanProblem(row pgx.Row) (*domain.Problem, error) {
+	var p domain.Problem
+	err := row.Scan(&p.ID, &p.UserID, &p.Platform, &p.ProblemID, &p.Title, &p.Difficulty, &p.URL, &p.SnippetID,
+		&p.Notes, &p.SolvedAt, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Create logs a solved problem, reporting false if the user already logged it
+func (r *ProblemRepository) Create(ctx context.Context, p *domain.Problem) (bool, error) {
+	query := `
+		INSERT INTO problems (id, user_id, platform, problem_id, title, difficulty, url, snippet_id, notes, solved_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (user_id, platform, problem_id) DO NOTHING
+	`
+	result, err := r.pool.Exec(ctx, query, p.ID, p.UserID, p.Platform, p.ProblemID, p.Title, p.Difficulty, p.URL,
+		p.SnippetID, p.Notes, p.SolvedAt, p.CreatedAt, p.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create problem: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// FindByID retrieves one of a user's problems, or nil if there is none
+func (r *ProblemRepository) FindByID(ctx context.Context, id, userID uuid.UUID) (*domain.Problem, error) {
+	query := `SELECT ` + problemColumns + ` FROM problems WHERE id = $1 AND user_id = $2`
+	p, err := scanProblem(r.pool.QueryRow(ctx, query, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find problem: %w", err)
+	}
+	return p, nil
+}
+
+// Exists reports whether a user logged a platform's problem, other than the problem with ID except
+func (r *ProblemRepository) Exists(ctx context.Context, userID uuid.UUID, platform, problemID string, except uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM problems WHERE user_id = $1 AND platform = $2 AND problem_id = $3 AND id <> $4)`
+	var exists bool
+	if err := r.pool.QueryRow(ctx, query, userID, platform, problemID, except).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check problem: %w", err)
+	}
+	return exists, nil
+}
+
+// ListByUser retrieves a user's problems, optionally on one platform or of one difficulty, most
+// recently solved first
+func (r *ProblemRepository) ListByUser(ctx context.Context, userID uuid.UUID, platform, difficulty string, limit, offset int) ([]domain.Problem, int, error) {
+	query := `
+		SELECT ` + problemColumns + `, COUNT(*) OVER()
+		FROM problems
+		WHERE user_id = $1 AND ($2 = '' OR platform = $2) AND ($3 = '' OR difficulty = $3)
+		ORDER BY solved_at DESC, id
+		LIMIT $4 OFFSET $5
+	`
+	rows, err := r.pool.Query(ctx, query, userID, platform, difficulty, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list problems: %w", err)
+	}
+	defer rows.Close()
+
+	problems := []domain.Problem{}
+	total := 0
+	for rows.Next() {
+		var p domain.Problem
+		if err := rows.Scan(&p.ID, &p.UserID, &p.Platform, &p.ProblemID, &p.Title, &p.Difficulty, &p.URL, &p.SnippetID,
+			&p.Notes, &p.SolvedAt, &p.CreatedAt, &p.UpdatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan problem: %w", err)
+		}
+		problems = append(problems, p)
+	}
+	return problems, total, rows.Err()
+}
+
+// Update saves a problem's details
+func (r *ProblemRepository) Update(ctx context.Context, p *domain.Problem) error {
+	query := `
+		UPDATE problems
+		SET platform = $3, problem_id = $4, title = $5, difficulty = $6, url = $7, snippet_id = $8, notes = $9,
+			solved_at = $10, updated_at = $11
+		WHERE id = $1 AND user_id = $2
+	`
+	_, err := r.pool.Exec(ctx, query, p.ID, p.UserID, p.Platform, p.ProblemID, p.Title, p.Difficulty, p.URL,
+		p.SnippetID, p.Notes, p.SolvedAt, p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update problem: %w", err)
+	}
+	return nil
+}
+
+// Delete removes one of a user's problems, reporting whether it existed
+func (r *ProblemRepository) Delete(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM problems WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete problem: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// Stats counts a user's solved problems by difficulty and platform, and per week over the last
+// weeks weeks
+func (r *ProblemRepository) Stats(ctx context.Context, userID uuid.UUID, weeks int) (*domain.ProblemStats, error) {
+	stats := &domain.ProblemStats{
+		ByDifficulty: map[string]int{domain.DifficultyEasy: 0, domain.DifficultyMedium: 0, domain.DifficultyHard: 0, "unrated": 0},
+		ByPlatform:   map[string]int{},
+		Weekly:       []domain.WeeklySolveCount{},
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT platform, difficulty, COUNT(*)
+		FROM problems
+		WHERE user_id = $1
+		GROUP BY platform, difficulty
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count problems: %w", err)
+	}
+	for rows.Next() {
+		var platform, difficulty string
+		var count int
+		if err := rows.Scan(&platform, &difficulty, &count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan problem count: %w", err)
+		}
+		if difficulty == "" {
+			difficulty = "unrated"
+		}
+		stats.Total += count
+		stats.ByDifficulty[difficulty] += count
+		stats.ByPlatform[platform] += count
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating problem counts: %w", err)
+	}
+
+	// Generate every week in the range so weeks without solves report zero
+	weeklyQuery := `
+		SELECT w.week_start, COUNT(p.id)
+		FROM generate_series(
+			DATE_TRUNC('week', CURRENT_DATE) - ($2 - 1) * INTERVAL '1 week',
+			DATE_TRUNC('week', CURRENT_DATE),
+			INTERVAL '1 week'
+		) AS w(week_start)
+		LEFT JOIN problems p
+			ON p.user_id = $1
+			AND p.solved_at >= w.week_start
+			AND p.solved_at < w.week_start + INTERVAL '1 week'
+		GROUP BY w.week_start
+		ORDER BY w.week_start ASC
+	`
+	rows, err = r.pool.Query(ctx, weeklyQuery, userID, weeks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly solve counts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var week domain.WeeklySolveCount
+		if err := rows.Scan(&week.WeekStart, &week.Solved); err != nil {
+			return nil, fmt.Errorf("failed to scan weekly solve count: %w", err)
+		}
+		stats.Weekly = append(stats.Weekly, week)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating weekly solve counts: %w", err)
+	}
+
+	return stats, nil
+}
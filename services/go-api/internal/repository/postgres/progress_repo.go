@@ -26,14 +26,16 @@ func NewProgressRepository(pool *pgxpool.Pool) *ProgressRepository {
 // Upsert creates or updates a progress record for a specific date
 func (r *ProgressRepository) Upsert(ctx context.Context, progress *domain.LearningProgress) error {
 	query := `
-		INSERT INTO learning_progress (id, user_id, date, entries_count, snippets_count, streak_days, total_learning_time, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO learning_progress (id, user_id, date, entries_count, snippets_count, tils_count, problems_count, streak_days, total_learning_time, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		ON CONFLICT (user_id, date)
 		DO UPDATE SET
 			entries_count = $4,
 			snippets_count = $5,
-			streak_days = $6,
-			total_learning_time = $7
+			tils_count = $6,
+			problems_count = $7,
+			streak_days = $8,
+			total_learning_time = $9
 	`
 	_, err := r.pool.Exec(ctx, query,
 		progress.ID,
@@ -41,6 +43,8 @@ func (r *ProgressRepository) Upsert(ctx context.Context, progress *domain.Learni
 		progress.Date,
 		progress.EntriesCount,
 		progress.SnippetsCount,
+		progress.TILsCount,
+		progress.ProblemsCount,
 		progress.StreakDays,
 		progress.TotalLearningTime,
 		progress.CreatedAt,
@@ -54,7 +58,7 @@ func (r *ProgressRepository) Upsert(ctx context.Context, progress *domain.Learni
 // FindByUserAndDate retrieves progress for a specific user and date
 func (r *ProgressRepository) FindByUserAndDate(ctx context.Context, userID uuid.UUID, date time.Time) (*domain.LearningProgress, error) {
 	query := `
-		SELECT id, user_id, date, entries_count, snippets_count, streak_days, total_learning_time, created_at
+		SELECT id, user_id, date, entries_count, snippets_count, tils_count, problems_count, streak_days, total_learning_time, created_at
 		FROM learning_progress
 		WHERE user_id = $1 AND date = $2
 	`
@@ -67,6 +71,8 @@ func (r *ProgressRepository) FindByUserAndDate(ctx context.Context, userID uuid.
 		&progress.Date,
 		&progress.EntriesCount,
 		&progress.SnippetsCount,
+		&progress.TILsCount,
+		&progress.ProblemsCount,
 		&progress.StreakDays,
 		&progress.TotalLearningTime,
 		&progress.CreatedAt,
@@ -83,7 +89,7 @@ func (r *ProgressRepository) FindByUserAndDate(ctx context.Context, userID uuid.
 // FindByUserRange retrieves progress records within a date range
 func (r *ProgressRepository) FindByUserRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]domain.LearningProgress, error) {
 	query := `
-		SELECT id, user_id, date, entries_count, snippets_count, streak_days, total_learning_time, created_at
+		SELECT id, user_id, date, entries_count, snippets_count, tils_count, problems_count, streak_days, total_learning_time, created_at
 		FROM learning_progress
 		WHERE user_id = $1 AND date >= $2 AND date <= $3
 		ORDER BY date DESC
@@ -103,6 +109,8 @@ func (r *ProgressRepository) FindByUserRange(ctx context.Context, userID uuid.UU
 			&progress.Date,
 			&progress.EntriesCount,
 			&progress.SnippetsCount,
+			&progress.TILsCount,
+			&progress.ProblemsCount,
 			&progress.StreakDays,
 			&progress.TotalLearningTime,
 			&progress.CreatedAt,
@@ -116,20 +124,66 @@ func (r *ProgressRepository) FindByUserRange(ctx context.Context, userID uuid.UU
 	return progressList, nil
 }
 
-// CalculateStreak calculates the current streak for a user
+// Buckets sums a user's progress between two dates, inclusive, per day, week, or month.
+// Periods without progress are left out.
+func (r *ProgressRepository) Buckets(ctx context.Context, userID uuid.UUID, from, to time.Time, groupBy string) ([]domain.ProgressBucket, error) {
+	query := `
+		SELECT
+			date_trunc($4, date::timestamp)::date,
+			SUM(entries_count), SUM(snippets_count), SUM(tils_count), SUM(problems_count), SUM(total_learning_time),
+			COUNT(*) FILTER (WHERE entries_count > 0 OR snippets_count > 0 OR tils_count > 0 OR problems_count > 0)
+		FROM learning_progress
+		WHERE user_id = $1 AND date >= $2 AND date <= $3
+		GROUP BY 1
+		ORDER BY 1
+	`
+	rows, err := r.pool.Query(ctx, query, userID, from, to, groupBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query progress: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []domain.ProgressBucket
+	for rows.Next() {
+		var b domain.ProgressBucket
+		if err := rows.Scan(&b.PeriodStart, &b.EntriesCount, &b.SnippetsCount, &b.TILsCount, &b.ProblemsCount,
+			&b.TotalLearningTime, &b.ActiveDays); err != nil {
+			return nil, fmt.Errorf("failed to scan progress bucket: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+// streakDay returns the SQL condition for a learning_progress row, aliased progress, counting
+// toward the streak of a user whose user_settings row, aliased settings, may be missing
+func streakDay(progress, settings string) string {
+	return fmt.Sprintf(`CASE COALESCE(%[2]s.streak_counts, 'any')
+		WHEN 'entries' THEN %[1]s.entries_count > 0
+		WHEN 'snippets' THEN %[1]s.snippets_count > 0
+		WHEN 'entries_or_snippets' THEN %[1]s.entries_count > 0 OR %[1]s.snippets_count > 0
+		WHEN 'minutes' THEN %[1]s.total_learning_time >= %[2]s.streak_minutes
+		ELSE %[1]s.entries_count > 0 OR %[1]s.snippets_count > 0 OR %[1]s.tils_count > 0 OR %[1]s.problems_count > 0
+	END`, progress, settings)
+}
+
+// CalculateStreak calculates the current streak for a user, counting the days with the activity
+// their streakCounts setting asks for
 func (r *ProgressRepository) CalculateStreak(ctx context.Context, userID uuid.UUID) (int, error) {
 	query := `
 		WITH RECURSIVE streak AS (
-			SELECT date, 1 as streak_count
-			FROM learning_progress
-			WHERE user_id = $1 AND date = CURRENT_DATE AND (entries_count > 0 OR snippets_count > 0)
+			SELECT lp.date, 1 as streak_count
+			FROM learning_progress lp
+			LEFT JOIN user_settings us ON us.user_id = lp.user_id
+			WHERE lp.user_id = $1 AND lp.date = CURRENT_DATE AND (` + streakDay("lp", "us") + `)
 
 			UNION ALL
 
 			SELECT lp.date, s.streak_count + 1
 			FROM learning_progress lp
 			JOIN streak s ON lp.date = s.date - INTERVAL '1 day'
-			WHERE lp.user_id = $1 AND (lp.entries_count > 0 OR lp.snippets_count > 0)
+			LEFT JOIN user_settings us ON us.user_id = lp.user_id
+			WHERE lp.user_id = $1 AND (` + streakDay("lp", "us") + `)
 		)
 		SELECT COALESCE(MAX(streak_count), 0) FROM streak
 	`
@@ -141,12 +195,65 @@ func (r *ProgressRepository) CalculateStreak(ctx context.Context, userID uuid.UU
 	return streak, nil
 }
 
+// AwardAchievements records the achievements a user earned, returning the kinds they hadn't
+// earned before
+func (r *ProgressRepository) AwardAchievements(ctx context.Context, userID uuid.UUID, kinds []string, earnedAt time.Time) ([]string, error) {
+	query := `
+		INSERT INTO achievements (user_id, kind, earned_at)
+		SELECT $1, kind, $3 FROM UNNEST($2::text[]) AS kind
+		ON CONFLICT (user_id, kind) DO NOTHING
+		RETURNING kind
+	`
+	rows, err := r.pool.Query(ctx, query, userID, kinds, earnedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to award achievements: %w", err)
+	}
+	defer rows.Close()
+
+	var awarded []string
+	for rows.Next() {
+		var kind string
+		if err := rows.Scan(&kind); err != nil {
+			return nil, fmt.Errorf("failed to scan achievement: %w", err)
+		}
+		awarded = append(awarded, kind)
+	}
+	return awarded, rows.Err()
+}
+
+// ListAchievements returns a user's achievements, most recently earned first
+func (r *ProgressRepository) ListAchievements(ctx context.Context, userID uuid.UUID) ([]domain.Achievement, error) {
+	query := `
+		SELECT kind, earned_at
+		FROM achievements
+		WHERE user_id = $1
+		ORDER BY earned_at DESC, kind
+	`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list achievements: %w", err)
+	}
+	defer rows.Close()
+
+	achievements := []domain.Achievement{}
+	for rows.Next() {
+		var a domain.Achievement
+		if err := rows.Scan(&a.Kind, &a.EarnedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan achievement: %w", err)
+		}
+		achievements = append(achievements, a)
+	}
+	return achievements, rows.Err()
+}
+
 // GetSummary retrieves a summary of learning progress for a user
 func (r *ProgressRepository) GetSummary(ctx context.Context, userID uuid.UUID) (*domain.ProgressSummary, error) {
 	query := `
 		SELECT
 			COALESCE(SUM(entries_count), 0) as total_entries,
 			COALESCE(SUM(snippets_count), 0) as total_snippets,
+			COALESCE(SUM(tils_count), 0) as total_tils,
+			COALESCE(SUM(problems_count), 0) as total_problems,
 			COALESCE(SUM(total_learning_time), 0) as total_time,
 			COALESCE(MAX(streak_days), 0) as longest_streak
 		FROM learning_progress
@@ -156,6 +263,8 @@ func (r *ProgressRepository) GetSummary(ctx context.Context, userID uuid.UUID) (
 	err := r.pool.QueryRow(ctx, query, userID).Scan(
 		&summary.TotalEntries,
 		&summary.TotalSnippets,
+		&summary.TotalTILs,
+		&summary.TotalProblems,
 		&summary.TotalLearningTime,
 		&summary.LongestStreak,
 	)
@@ -195,6 +304,88 @@ func (r *ProgressRepository) GetSummary(ctx context.Context, userID uuid.UUID) (
 	return &summary, nil
 }
 
+// SharesProgress reports whether a user turned on shareProgress in their settings
+func (r *ProgressRepository) SharesProgress(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var shares bool
+	query := `SELECT EXISTS(SELECT 1 FROM user_settings WHERE user_id = $1 AND share_progress)`
+	if err := r.pool.QueryRow(ctx, query, userID).Scan(&shares); err != nil {
+		return false, fmt.Errorf("failed to check progress sharing: %w", err)
+	}
+	return shares, nil
+}
+
+// ComparableUsers returns which candidates userID may compare progress with: those who turned
+// on shareProgress and either follow userID back or share a study group with them
+func (r *ProgressRepository) ComparableUsers(ctx context.Context, userID uuid.UUID, candidates []uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT c.id
+		FROM UNNEST($2::uuid[]) AS c(id)
+		JOIN user_settings us ON us.user_id = c.id AND us.share_progress
+		WHERE c.id <> $1 AND (
+			(EXISTS(SELECT 1 FROM follows WHERE follower_id = $1 AND followee_id = c.id)
+				AND EXISTS(SELECT 1 FROM follows WHERE follower_id = c.id AND followee_id = $1))
+			OR EXISTS(
+				SELECT 1 FROM study_group_members mine
+				JOIN study_group_members theirs ON theirs.group_id = mine.group_id
+				WHERE mine.user_id = $1 AND theirs.user_id = c.id
+			)
+		)
+	`
+	rows, err := r.pool.Query(ctx, query, userID, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find comparable users: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan comparable user: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// CompareActivity returns each user's streaks, active days, and entries since a date
+func (r *ProgressRepository) CompareActivity(ctx context.Context, userIDs []uuid.UUID, since time.Time) ([]domain.ProgressComparisonEntry, error) {
+	query := `
+		SELECT
+			u.id,
+			COALESCE(MAX(lp.streak_days), 0),
+			COUNT(lp.date) FILTER (WHERE lp.date >= $2 AND (lp.entries_count > 0 OR lp.snippets_count > 0 OR lp.tils_count > 0 OR lp.problems_count > 0)),
+			COALESCE(SUM(lp.entries_count) FILTER (WHERE lp.date >= $2), 0)
+		FROM UNNEST($1::uuid[]) AS u(id)
+		LEFT JOIN learning_progress lp ON lp.user_id = u.id
+		GROUP BY u.id
+	`
+	rows, err := r.pool.Query(ctx, query, userIDs, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare progress: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.ProgressComparisonEntry
+	for rows.Next() {
+		var e domain.ProgressComparisonEntry
+		if err := rows.Scan(&e.UserID, &e.LongestStreak, &e.ActiveDays, &e.Entries); err != nil {
+			return nil, fmt.Errorf("failed to scan progress comparison: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		if entries[i].CurrentStreak, err = r.CalculateStreak(ctx, entries[i].UserID); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
 // IncrementEntries increments the entry count for today
 func (r *ProgressRepository) IncrementEntries(ctx context.Context, userID uuid.UUID) error {
 	query := `
@@ -224,3 +415,48 @@ func (r *ProgressRepository) IncrementSnippets(ctx context.Context, userID uuid.
 	}
 	return nil
 }
+
+// IncrementTILs increments the TIL count for today
+func (r *ProgressRepository) IncrementTILs(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		INSERT INTO learning_progress (id, user_id, date, tils_count, created_at)
+		VALUES ($1, $2, CURRENT_DATE, 1, NOW())
+		ON CONFLICT (user_id, date)
+		DO UPDATE SET tils_count = learning_progress.tils_count + 1
+	`
+	_, err := r.pool.Exec(ctx, query, uuid.New(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to increment TILs: %w", err)
+	}
+	return nil
+}
+
+// IncrementProblems increments the solved problem count for the day a problem was solved
+func (r *ProgressRepository) IncrementProblems(ctx context.Context, userID uuid.UUID, date time.Time) error {
+	query := `
+		INSERT INTO learning_progress (id, user_id, date, problems_count, created_at)
+		VALUES ($1, $2, $3::date, 1, NOW())
+		ON CONFLICT (user_id, date)
+		DO UPDATE SET problems_count = learning_progress.problems_count + 1
+	`
+	_, err := r.pool.Exec(ctx, query, uuid.New(), userID, date)
+	if err != nil {
+		return fmt.Errorf("failed to increment problems: %w", err)
+	}
+	return nil
+}
+
+// AddLearningTime adds minutes, or removes them when negative, from a day's learning time
+func (r *ProgressRepository) AddLearningTime(ctx context.Context, userID uuid.UUID, date time.Time, minutes int) error {
+	query := `
+		INSERT INTO learning_progress (id, user_id, date, total_learning_time, created_at)
+		VALUES ($1, $2, $3::date, GREATEST($4, 0), NOW())
+		ON CONFLICT (user_id, date)
+		DO UPDATE SET total_learning_time = GREATEST(learning_progress.total_learning_time + $4, 0)
+	`
+	_, err := r.pool.Exec(ctx, query, uuid.New(), userID, date, minutes)
+	if err != nil {
+		return fmt.Errorf("failed to add learning time: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/tenant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ProjectRepository handles projects and their focus sessions with raw SQL
+type ProjectRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewProjectRepository creates a new project repository
+func NewProjectRepository(pool *pgxpool.Pool) *ProjectRepository {
+	return &ProjectRepository{pool: pool}
+}
+
+const projectColumns = `id, user_id, name, description, status, created_at, updated_at`
+
+func scanProject(row pgx.Row) (*domain.Project, error) {
+	var p domain.Project
+	if err := row.Scan(&p.ID, &p.UserID, &p.Name, &p.Description, &p.Status, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Create inserts a new project in the current workspace
+func (r *ProjectRepository) Create(ctx context.Context, p *domain.Project) error {
+	query := `
+		INSERT INTO projects (id, user_id, workspace_id, name, description, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.pool.Exec(ctx, query, p.ID, p.UserID, tenant.WorkspaceID(ctx, p.UserID), p.Name, p.Description, p.Status, p.CreatedAt, p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create project: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves a project by ID
+func (r *ProjectRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE id = $1 AND ($2::uuid IS NULL OR workspace_id = $2)`
+	p, err := scanProject(r.pool.QueryRow(ctx, query, id, optionalWorkspace(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	return p, nil
+}
+
+// ListByUser retrieves the user's projects in the current workspace, optionally with one status,
+// most recently updated first
+func (r *ProjectRepository) ListByUser(ctx context.Context, userID uuid.UUID, status string, limit, offset int) ([]domain.Project, int, error) {
+	query := `
+		SELECT ` + projectColumns + `, COUNT(*) OVER()
+		FROM projects
+		WHERE user_id = $1 AND workspace_id = $2 AND ($3 = '' OR status = $3)
+		ORDER BY updated_at DESC
+		LIMIT $4 OFFSET $5
+	`
+	rows, err := r.pool.Query(ctx, query, userID, tenant.WorkspaceID(ctx, userID), status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []domain.Project{}
+	total := 0
+	for rows.Next() {
+		var p domain.Project
+		if err := rows.Scan(&p.ID, &p.UserID, &p.Name, &p.Description, &p.Status, &p.CreatedAt, &p.UpdatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, p)
+	}
+	return projects, total, rows.Err()
+}
+
+// Update saves a project's name, description, and status
+func (r *ProjectRepository) Update(ctx context.Context, p *domain.Project) error {
+	query := `
+		UPDATE projects SET name = $3, description = $4, status = $5, updated_at = $6
+		WHERE id = $1 AND user_id = $2
+	`
+	if _, err := r.pool.Exec(ctx, query, p.ID, p.UserID, p.Name, p.Description, p.Status, p.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a project and its focus sessions. Its entries are detached.
+func (r *ProjectRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM projects WHERE id = $1 AND user_id = $2`, id, userID); err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	return nil
+}
+
+// Stats counts a project's entries and focus minutes. Snippets are counted in MongoDB.
+func (r *ProjectRepository) Stats(ctx context.Context, id uuid.UUID) (*domain.ProjectStats, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM journal_entries WHERE project_id = $1),
+			(SELECT COALESCE(SUM(minutes), 0) FROM focus_sessions WHERE project_id = $1)
+	`
+	var stats domain.ProjectStats
+	if err := r.pool.QueryRow(ctx, query, id).Scan(&stats.Entries, &stats.FocusMinutes); err != nil {
+		return nil, fmt.Errorf("failed to count project work: %w", err)
+	}
+	return &stats, nil
+}
+
+// CreateFocus records a focus session
+func (r *ProjectRepository) CreateFocus(ctx context.Context, f *domain.FocusSession) error {
+	query := `
+		INSERT INTO focus_sessions (id, user_id, project_id, started_at, minutes, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	if _, err := r.pool.Exec(ctx, query, f.ID, f.UserID, f.ProjectID, f.StartedAt, f.Minutes, f.Note, f.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create focus session: %w", err)
+	}
+	return nil
+}
+
+// DeleteFocus removes a focus session of a project and returns it, or nil if there was none
+func (r *ProjectRepository) DeleteFocus(ctx context.Context, id, projectID uuid.UUID) (*domain.FocusSession, error) {
+	query := `
+		DELETE FROM focus_sessions WHERE id = $1 AND project_id = $2
+		RETURNING id, user_id, project_id, started_at, minutes, note, created_at
+	`
+	var f domain.FocusSession
+	err := r.pool.QueryRow(ctx, query, id, projectID).Scan(&f.ID, &f.UserID, &f.ProjectID, &f.StartedAt, &f.Minutes, &f.Note, &f.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete focus session: %w", err)
+	}
+	return &f, nil
+}
+
+// FindFocus retrieves a project's focus sessions started before a time, newest first
+func (r *ProjectRepository) FindFocus(ctx context.Context, projectID uuid.UUID, before time.Time, limit int) ([]domain.FocusSession, error) {
+	query := `
+		SELECT id, user_id, project_id, started_at, minutes, note, created_at
+		FROM focus_sessions
+		WHERE project_id = $1 AND started_at < $2
+		ORDER BY started_at DESC
+		LIMIT $3
+	`
+	rows, err := r.pool.Query(ctx, query, projectID, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find focus sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []domain.FocusSession
+	for rows.Next() {
+		var f domain.FocusSession
+		if err := rows.Scan(&f.ID, &f.UserID, &f.ProjectID, &f.StartedAt, &f.Minutes, &f.Note, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan focus session: %w", err)
+		}
+		sessions = append(sessions, f)
+	}
+	return sessions, rows.Err()
+}
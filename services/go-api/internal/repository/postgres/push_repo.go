@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PushRepository handles push device and reminder persistence with raw SQL
+type PushRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPushRepository creates a new push repository
+func NewPushRepository(pool *pgxpool.Pool) *PushRepository {
+	return &PushRepository{pool: pool}
+}
+
+// Register saves a device token. A token already registered (e.g. by another account on the
+// same phone) moves to this user, and device.ID is set to the stored row's ID.
+func (r *PushRepository) Register(ctx context.Context, device *domain.PushDevice) error {
+	query := `
+		INSERT INTO push_devices (id, user_id, platform, token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (token) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			platform = EXCLUDED.platform,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+	err := r.pool.QueryRow(ctx, query,
+		device.ID, device.UserID, device.Platform, device.Token, device.CreatedAt, device.UpdatedAt,
+	).Scan(&device.ID, &device.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to register push device: %w", err)
+	}
+	return nil
+}
+
+// ListByUser retrieves a user's devices, most recently registered first
+func (r *PushRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.PushDevice, error) {
+	query := `
+		SELECT id, user_id, platform, token, created_at, updated_at
+		FROM push_devices
+		WHERE user_id = $1
+		ORDER BY updated_at DESC
+	`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list push devices: %w", err)
+	}
+	defer rows.Close()
+
+	devices := []domain.PushDevice{}
+	for rows.Next() {
+		var d domain.PushDevice
+		if err := rows.Scan(&d.ID, &d.UserID, &d.Platform, &d.Token, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan push device: %w", err)
+		}
+		devices = append(devices, d)
+	}
+	return devices, rows.Err()
+}
+
+// Delete removes one of a user's devices. It reports whether the device existed.
+func (r *PushRepository) Delete(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM push_devices WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete push device: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// DeleteToken forgets a token the push provider reported as no longer registered
+func (r *PushRepository) DeleteToken(ctx context.Context, token string) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM push_devices WHERE token = $1`, token); err != nil {
+		return fmt.Errorf("failed to delete push token: %w", err)
+	}
+	return nil
+}
+
+// ListStreakReminders finds users with a device whose streak ran through the day before day,
+// who have done nothing on day that counts toward it yet, and who have not been reminded on day
+// yet. What counts follows each user's streakCounts setting.
+func (r *PushRepository) ListStreakReminders(ctx context.Context, day time.Time, limit int) ([]domain.StreakReminder, error) {
+	query := `
+		SELECT yesterday.user_id, yesterday.streak_days, COALESCE(us.streak_counts, 'any'), COALESCE(us.streak_minutes, 0)
+		FROM learning_progress yesterday
+		LEFT JOIN learning_progress today
+			ON today.user_id = yesterday.user_id AND today.date = $1::date
+		LEFT JOIN user_settings us ON us.user_id = yesterday.user_id
+		LEFT JOIN push_reminders pr ON pr.user_id = yesterday.user_id
+		WHERE yesterday.date = $1::date - 1
+			AND (` + streakDay("yesterday", "us") + `)
+			AND (today.user_id IS NULL OR NOT (` + streakDay("today", "us") + `))
+			AND (pr.last_sent_on IS NULL OR pr.last_sent_on < $1::date)
+			AND EXISTS (SELECT 1 FROM push_devices pd WHERE pd.user_id = yesterday.user_id)
+		LIMIT $2
+	`
+	rows, err := r.pool.Query(ctx, query, day, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list streak reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []domain.StreakReminder
+	for rows.Next() {
+		var reminder domain.StreakReminder
+		if err := rows.Scan(&reminder.UserID, &reminder.StreakDays, &reminder.StreakCounts, &reminder.StreakMinutes); err != nil {
+			return nil, fmt.Errorf("failed to scan streak reminder: %w", err)
+		}
+		reminders = append(reminders, reminder)
+	}
+	return reminders, rows.Err()
+}
+
+// MarkReminded records that a user was sent their streak reminder for day
+func (r *PushRepository) MarkReminded(ctx context.Context, userID uuid.UUID, day time.Time) error {
+	query := `
+		INSERT INTO push_reminders (user_id, last_sent_on)
+		VALUES ($1, $2::date)
+		ON CONFLICT (user_id) DO UPDATE SET last_sent_on = EXCLUDED.last_sent_on
+	`
+	if _, err := r.pool.Exec(ctx, query, userID, day); err != nil {
+		return fmt.Errorf("failed to mark streak reminder: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// QuizAttemptRepository handles scored quiz attempts with raw SQL
+type QuizAttemptRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewQuizAttemptRepository creates a new quiz attempt repository
+func NewQuizAttemptRepository(pool *pgxpool.Pool) *QuizAttemptRepository {
+	return &QuizAttemptRepository{pool: pool}
+}
+
+// Create records a graded attempt
+func (r *QuizAttemptRepository) Create(ctx context.Context, a *domain.QuizAttempt) error {
+	query := `
+		INSERT INTO quiz_attempts (id, quiz_id, group_id, user_id, score, total, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	if _, err := r.pool.Exec(ctx, query, a.ID, a.QuizID, a.GroupID, a.UserID, a.Score, a.Total, a.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create quiz attempt: %w", err)
+	}
+	return nil
+}
+
+// BestScores returns the user's best score on each quiz of a group they have taken, by quiz ID
+func (r *QuizAttemptRepository) BestScores(ctx context.Context, groupID, userID uuid.UUID) (map[string]int, error) {
+	query := `
+		SELECT quiz_id, MAX(score)
+		FROM quiz_attempts
+		WHERE group_id = $1 AND user_id = $2
+		GROUP BY quiz_id
+	`
+	rows, err := r.pool.Query(ctx, query, groupID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get best quiz scores: %w", err)
+	}
+	defer rows.Close()
+
+	scores := make(map[string]int)
+	for rows.Next() {
+		var quizID string
+		var score int
+		if err := rows.Scan(&quizID, &score); err != nil {
+			return nil, fmt.Errorf("failed to scan quiz score: %w", err)
+		}
+		scores[quizID] = score
+	}
+	return scores, rows.Err()
+}
+
+// DeleteByQuiz removes every attempt at a quiz, taking its points off the leaderboard
+func (r *QuizAttemptRepository) DeleteByQuiz(ctx context.Context, quizID string) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM quiz_attempts WHERE quiz_id = $1`, quizID); err != nil {
+		return fmt.Errorf("failed to delete quiz attempts: %w", err)
+	}
+	return nil
+}
+
+// Leaderboard ranks a group's current members by the sum of their best score on each quiz.
+// Members who have not taken a quiz are left out.
+func (r *QuizAttemptRepository) Leaderboard(ctx context.Context, groupID uuid.UUID, limit int) ([]domain.LeaderboardEntry, error) {
+	query := `
+		SELECT RANK() OVER (ORDER BY SUM(best.score) DESC), best.user_id, u.display_name, SUM(best.score), COUNT(*)
+		FROM (
+			SELECT user_id, quiz_id, MAX(score) AS score
+			FROM quiz_attempts
+			WHERE group_id = $1
+			GROUP BY user_id, quiz_id
+		) best
+		JOIN study_group_members m ON m.group_id = $1 AND m.user_id = best.user_id
+		JOIN users u ON u.id = best.user_id
+		GROUP BY best.user_id, u.display_name
+		ORDER BY SUM(best.score) DESC, u.display_name
+		LIMIT $2
+	`
+	rows, err := r.pool.Query(ctx, query, groupID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []domain.LeaderboardEntry{}
+	for rows.Next() {
+		var e domain.LeaderboardEntry
+		if err := rows.Scan(&e.Rank, &e.UserID, &e.DisplayName, &e.Points, &e.Quizzes); err != nil {
+			return nil, fmt.Errorf("failed to scan leaderboard entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
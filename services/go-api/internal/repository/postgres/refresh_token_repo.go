@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RefreshTokenRepository handles refresh token persistence with raw SQL
+type RefreshTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(pool *pgxpool.Pool) *RefreshTokenRepository {
+	return &RefreshTokenRepository{pool: pool}
+}
+
+const refreshTokenColumns = `id, user_id, family_id, token_hash, expires_at, used_at, created_at`
+
+func scanRefreshToken(row pgx.Row) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	err := row.Scan(&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+	return &token, nil
+}
+
+// Create inserts a new refresh token
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.pool.Exec(ctx, query, token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return nil
+}
+
+// Rotate marks the unused, unexpired token with the given hash as used and stores next as its
+// successor, in the same family and for the same user, returning the used token. A token that was
+// already used has been replayed, perhaps by someone who stole it, so Rotate deletes its whole
+// family, signing out whoever holds the latest one, and reports reused. Unknown and expired
+// tokens return nil.
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, hash string, next *domain.RefreshToken, now time.Time) (*domain.RefreshToken, bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Locks the row, so of two refreshes racing with one token only the first rotates it
+	used, err := scanRefreshToken(tx.QueryRow(ctx, `
+		UPDATE refresh_tokens SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING `+refreshTokenColumns, hash, now))
+	if err != nil {
+		return nil, false, err
+	}
+	if used == nil {
+		result, err := tx.Exec(ctx, `
+			DELETE FROM refresh_tokens
+			WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1 AND used_at IS NOT NULL)
+		`, hash)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to revoke refresh token family: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, false, fmt.Errorf("failed to commit refresh token revocation: %w", err)
+		}
+		return nil, result.RowsAffected() > 0, nil
+	}
+
+	next.UserID = used.UserID
+	next.FamilyID = used.FamilyID
+	_, err = tx.Exec(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, next.ID, next.UserID, next.FamilyID, next.TokenHash, next.ExpiresAt, next.CreatedAt)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, fmt.Errorf("failed to commit refresh token rotation: %w", err)
+	}
+	return used, false, nil
+}
+
+// DeleteExpired removes tokens that expired before the given time. Used tokens are kept until
+// then, so replaying them is still caught.
+func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM refresh_tokens WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/tenant"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ReviewRepository handles spaced-repetition review schedules with raw SQL
+type ReviewRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewReviewRepository creates a new review repository
+func NewReviewRepository(pool *pgxpool.Pool) *ReviewRepository {
+	return &ReviewRepository{pool: pool}
+}
+
+const reviewItemColumns = `id, user_id, item_type, item_id, ease_factor, interval_days, repetitions, due_at, last_reviewed_at, created_at`
+
+// scanReviewItem scans a single review_items row
+func scanReviewItem(row pgx.Row) (*domain.ReviewItem, error) {
+	var item domain.ReviewItem
+	err := row.Scan(
+		&item.ID,
+		&item.UserID,
+		&item.ItemType,
+		&item.ItemID,
+		&item.EaseFactor,
+		&item.IntervalDays,
+		&item.Repetitions,
+		&item.DueAt,
+		&item.LastReviewedAt,
+		&item.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Create enrolls an item for review
+func (r *ReviewRepository) Create(ctx context.Context, item *domain.ReviewItem) error {
+	query := `
+		INSERT INTO review_items (id, user_id, item_type, item_id, ease_factor, interval_days, repetitions, due_at, created_at, workspace_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		item.ID,
+		item.UserID,
+		item.ItemType,
+		item.ItemID,
+		item.EaseFactor,
+		item.IntervalDays,
+		item.Repetitions,
+		item.DueAt,
+		item.CreatedAt,
+		tenant.WorkspaceID(ctx, item.UserID),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create review item: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves a review item by ID
+func (r *ReviewRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.ReviewItem, error) {
+	query := `SELECT ` + reviewItemColumns + ` FROM review_items WHERE id = $1 AND ($2::uuid IS NULL OR workspace_id = $2)`
+	item, err := scanReviewItem(r.pool.QueryRow(ctx, query, id, optionalWorkspace(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find review item: %w", err)
+	}
+	return item, nil
+}
+
+// FindDue retrieves the most overdue review item for a user, optionally limited to one item type
+func (r *ReviewRepository) FindDue(ctx context.Context, userID uuid.UUID, itemType string, now time.Time) (*domain.ReviewItem, error) {
+	query := `
+		SELECT ` + reviewItemColumns + `
+		FROM review_items
+		WHERE user_id = $1 AND workspace_id = $4 AND due_at <= $2 AND ($3 = '' OR item_type = $3)
+		ORDER BY due_at ASC
+		LIMIT 1
+	`
+	item, err := scanReviewItem(r.pool.QueryRow(ctx, query, userID, now, itemType, tenant.WorkspaceID(ctx, userID)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due review item: %w", err)
+	}
+	return item, nil
+}
+
+// CountDueByDay counts a user's review items due before until in every workspace, grouped by UTC day.
+// Overdue items are counted on the day of from.
+func (r *ReviewRepository) CountDueByDay(ctx context.Context, userID uuid.UUID, from, until time.Time) ([]domain.ReviewDay, error) {
+	query := `
+		SELECT (GREATEST(due_at, $2) AT TIME ZONE 'UTC')::date AS day, COUNT(*)
+		FROM review_items
+		WHERE user_id = $1 AND due_at < $3
+		GROUP BY day
+		ORDER BY day
+	`
+	rows, err := r.pool.Query(ctx, query, userID, from, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count due review items: %w", err)
+	}
+	defer rows.Close()
+
+	var days []domain.ReviewDay
+	for rows.Next() {
+		var day domain.ReviewDay
+		if err := rows.Scan(&day.Date, &day.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan due review items: %w", err)
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
+// FindUnscheduledEntryID picks a random journal entry created before the cutoff
+// that has not been enrolled for review yet (uuid.Nil if there is none)
+func (r *ReviewRepository) FindUnscheduledEntryID(ctx context.Context, userID uuid.UUID, before time.Time) (uuid.UUID, error) {
+	query := `
+		SELECT je.id
+		FROM journal_entries je
+		WHERE je.user_id = $1 AND je.workspace_id = $3 AND je.created_at < $2 AND NOT je.is_vault
+		  AND NOT EXISTS (
+			SELECT 1 FROM review_items ri
+			WHERE ri.user_id = je.user_id AND ri.item_type = 'journal' AND ri.item_id = je.id::text
+		  )
+		ORDER BY random()
+		LIMIT 1
+	`
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx, query, userID, before, tenant.WorkspaceID(ctx, userID)).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to find unscheduled journal entry: %w", err)
+	}
+	return id, nil
+}
+
+// ListItemIDs returns the IDs of every item of a type the user has enrolled for review
+func (r *ReviewRepository) ListItemIDs(ctx context.Context, userID uuid.UUID, itemType string) ([]string, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT item_id FROM review_items
+		WHERE user_id = $1 AND workspace_id = $3 AND item_type = $2
+	`, userID, itemType, tenant.WorkspaceID(ctx, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review item IDs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan review item ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review item IDs: %w", err)
+	}
+
+	return ids, nil
+}
+
+// UpdateSchedule stores the result of a review
+func (r *ReviewRepository) UpdateSchedule(ctx context.Context, item *domain.ReviewItem) error {
+	query := `
+		UPDATE review_items
+		SET ease_factor = $2, interval_days = $3, repetitions = $4, due_at = $5, last_reviewed_at = $6
+		WHERE id = $1
+	`
+	result, err := r.pool.Exec(ctx, query,
+		item.ID,
+		item.EaseFactor,
+		item.IntervalDays,
+		item.Repetitions,
+		item.DueAt,
+		item.LastReviewedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update review item: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperr.New(apperr.ErrNotFound, "review item not found")
+	}
+	return nil
+}
+
+// Delete removes a review item (e.g. when the underlying entry or snippet is gone)
+func (r *ReviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM review_items WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete review item: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SettingsRepository handles user settings persistence with raw SQL
+type SettingsRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSettingsRepository creates a new settings repository
+func NewSettingsRepository(pool *pgxpool.Pool) *SettingsRepository {
+	return &SettingsRepository{pool: pool}
+}
+
+// FindByUserID retrieves a user's settings (nil if the user never saved any)
+func (r *SettingsRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*domain.UserSettings, error) {
+	query := `
+		SELECT user_id, default_page_size, locale, share_progress, celebrate_milestones, streak_counts, streak_minutes, avatar_url, updated_at
+		FROM user_settings
+		WHERE user_id = $1
+	`
+	var settings domain.UserSettings
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&settings.UserID,
+		&settings.DefaultPageSize,
+		&settings.Locale,
+		&settings.ShareProgress,
+		&settings.CelebrateMilestones,
+		&settings.StreakCounts,
+		&settings.StreakMinutes,
+		&settings.AvatarURL,
+		&settings.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// Upsert creates or replaces a user's settings
+func (r *SettingsRepository) Upsert(ctx context.Context, settings *domain.UserSettings) error {
+	query := `
+		INSERT INTO user_settings (user_id, default_page_size, locale, share_progress, celebrate_milestones, streak_counts, streak_minutes, avatar_url, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			default_page_size = $2,
+			locale = $3,
+			share_progress = $4,
+			celebrate_milestones = $5,
+			streak_counts = $6,
+			streak_minutes = $7,
+			avatar_url = $8,
+			updated_at = $9
+	`
+	_, err := r.pool.Exec(ctx, query,
+		settings.UserID,
+		settings.DefaultPageSize,
+		settings.Locale,
+		settings.ShareProgress,
+		settings.CelebrateMilestones,
+		settings.StreakCounts,
+		settings.StreakMinutes,
+		settings.AvatarURL,
+		settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert user settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SiteRepository handles site publishers and their delivery queue with raw SQL
+type SiteRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSiteRepository creates a new site repository
+func NewSiteRepository(pool *pgxpool.Pool) *SiteRepository {
+	return &SiteRepository{pool: pool}
+}
+
+// FindPublisher retrieves a user's site publisher, or nil if they have none
+func (r *SiteRepository) FindPublisher(ctx context.Context, userID uuid.UUID) (*domain.SitePublisher, error) {
+	query := `
+		SELECT user_id, kind, enabled, webhook_url, repo, branch, path, token, created_at, updated_at
+		FROM site_publishers
+		WHERE user_id = $1
+	`
+	var p domain.SitePublisher
+	err := r.pool.QueryRow(ctx, query, userID).Scan(&p.UserID, &p.Kind, &p.Enabled, &p.WebhookURL, &p.Repo,
+		&p.Branch, &p.Path, &p.Token, &p.CreatedAt, &p.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find site publisher: %w", err)
+	}
+	p.HasToken = p.Token != ""
+	return &p, nil
+}
+
+// SavePublisher creates or replaces a user's site publisher
+func (r *SiteRepository) SavePublisher(ctx context.Context, p *domain.SitePublisher) error {
+	query := `
+		INSERT INTO site_publishers (user_id, kind, enabled, webhook_url, repo, branch, path, token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		ON CONFLICT (user_id) DO UPDATE SET
+			kind = EXCLUDED.kind, enabled = EXCLUDED.enabled, webhook_url = EXCLUDED.webhook_url,
+			repo = EXCLUDED.repo, branch = EXCLUDED.branch, path = EXCLUDED.path, token = EXCLUDED.token,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at, updated_at
+	`
+	err := r.pool.QueryRow(ctx, query, p.UserID, p.Kind, p.Enabled, p.WebhookURL, p.Repo, p.Branch, p.Path, p.Token,
+		time.Now().UTC()).Scan(&p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save site publisher: %w", err)
+	}
+	p.HasToken = p.Token != ""
+	return nil
+}
+
+// DeletePublisher removes a user's site publisher and drops their pending deliveries, reporting
+// false if they had no publisher
+func (r *SiteRepository) DeletePublisher(ctx context.Context, userID uuid.UUID) (bool, error) {
+	query := `
+		WITH pending AS (
+			DELETE FROM site_deliveries WHERE user_id = $1 AND status = 'pending'
+		)
+		DELETE FROM site_publishers WHERE user_id = $1
+	`
+	result, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete site publisher: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// Enqueue queues an entry to be sent to its author's site, if they have an enabled publisher.
+// Entries are sent once, so it reports false when there is no publisher or the entry was queued
+// before.
+func (r *SiteRepository) Enqueue(ctx context.Context, userID, entryID uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO site_deliveries (user_id, entry_id)
+		SELECT user_id, $2 FROM site_publishers WHERE user_id = $1 AND enabled
+		ON CONFLICT (user_id, entry_id) DO NOTHING
+	`
+	result, err := r.pool.Exec(ctx, query, userID, entryID)
+	if err != nil {
+		return false, fmt.Errorf("failed to queue site delivery: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// ClaimDue locks up to limit pending deliveries that are due and pushes their next attempt out by
+// lease, so a concurrent worker or a crash mid-delivery cannot send them twice in that window
+func (r *SiteRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.SiteDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT id FROM site_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE site_deliveries d
+		SET next_attempt_at = $2
+		FROM due
+		WHERE d.id = due.id
+		RETURNING d.id, d.user_id, d.entry_id, d.status, d.attempts, d.last_error, d.next_attempt_at, d.created_at
+	`
+	rows, err := r.pool.Query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim site deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.SiteDelivery{}
+	for rows.Next() {
+		var d domain.SiteDelivery
+		if err := rows.Scan(&d.ID, &d.UserID, &d.EntryID, &d.Status, &d.Attempts, &d.LastError,
+			&d.NextAttemptAt, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan site delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// MarkDelivered records a successful delivery
+func (r *SiteRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE site_deliveries SET status = 'delivered', attempts = attempts + 1, last_error = '' WHERE id = $1`
+	if _, err := r.pool.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to mark site delivery delivered: %w", err)
+	}
+	return nil
+}
+
+// MarkAttemptFailed records a failed delivery. A nil retryAt gives up on the delivery.
+func (r *SiteRepository) MarkAttemptFailed(ctx context.Context, id uuid.UUID, lastError string, retryAt *time.Time) error {
+	query := `
+		UPDATE site_deliveries
+		SET attempts = attempts + 1, last_error = $2,
+			status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			next_attempt_at = COALESCE($3, next_attempt_at)
+		WHERE id = $1
+	`
+	if _, err := r.pool.Exec(ctx, query, id, lastError, retryAt); err != nil {
+		return fmt.Errorf("failed to record site delivery failure: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries retrieves a user's recent deliveries with their entry titles, newest first
+func (r *SiteRepository) ListDeliveries(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.SiteDelivery, int, error) {
+	query := `
+		SELECT d.id, d.user_id, d.entry_id, e.title, d.status, d.attempts, d.last_error, d.next_attempt_at,
+			d.created_at, COUNT(*) OVER()
+		FROM site_deliveries d
+		JOIN journal_entries e ON e.id = d.entry_id
+		WHERE d.user_id = $1
+		ORDER BY d.created_at DESC, d.id
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list site deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.SiteDelivery{}
+	total := 0
+	for rows.Next() {
+		var d domain.SiteDelivery
+		if err := rows.Scan(&d.ID, &d.UserID, &d.EntryID, &d.EntryTitle, &d.Status, &d.Attempts, &d.LastError,
+			&d.NextAttemptAt, &d.CreatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan site delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, total, rows.Err()
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SnippetCommentRepository handles line comments on snippets with raw SQL
+type SnippetCommentRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSnippetCommentRepository creates a new snippet comment repository
+func NewSnippetCommentRepository(pool *pgxpool.Pool) *SnippetCommentRepository {
+	return &SnippetCommentRepository{pool: pool}
+}
+
+const snippetCommentColumns = `c.id, c.snippet_id, c.user_id, u.display_name, c.file_name, c.start_line, c.end_line,
+	c.body, c.outdated, c.resolved_at, c.created_at, c.updated_at`
+
+func scanSnippetComment(row pgx.Row) (*domain.SnippetComment, error) {
+	var c domain.SnippetComment
+	err := row.Scan(&c.ID, &c.SnippetID, &c.UserID, &c.AuthorName, &c.File, &c.StartLine, &c.EndLine,
+		&c.Body, &c.Outdated, &c.ResolvedAt, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Create inserts a new comment
+func (r *SnippetCommentRepository) Create(ctx context.Context, c *domain.SnippetComment) error {
+	query := `
+		INSERT INTO snippet_comments (id, snippet_id, user_id, file_name, start_line, end_line, body, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.pool.Exec(ctx, query, c.ID, c.SnippetID, c.UserID, c.File, c.StartLine, c.EndLine, c.Body, c.CreatedAt, c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create snippet comment: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves a comment on a snippet, or nil if there is none
+func (r *SnippetCommentRepository) FindByID(ctx context.Context, id uuid.UUID, snippetID string) (*domain.SnippetComment, error) {
+	query := `
+		SELECT ` + snippetCommentColumns + `
+		FROM snippet_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.id = $1 AND c.snippet_id = $2
+	`
+	c, err := scanSnippetComment(r.pool.QueryRow(ctx, query, id, snippetID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snippet comment: %w", err)
+	}
+	return c, nil
+}
+
+// ListBySnippet retrieves a snippet's comments by file and line, leaving out resolved ones
+// unless includeResolved is set
+func (r *SnippetCommentRepository) ListBySnippet(ctx context.Context, snippetID string, includeResolved bool) ([]domain.SnippetComment, error) {
+	query := `
+		SELECT ` + snippetCommentColumns + `
+		FROM snippet_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.snippet_id = $1 AND ($2 OR c.resolved_at IS NULL)
+		ORDER BY c.file_name, c.start_line, c.created_at
+	`
+	rows, err := r.pool.Query(ctx, query, snippetID, includeResolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snippet comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []domain.SnippetComment{}
+	for rows.Next() {
+		c, err := scanSnippetComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan snippet comment: %w", err)
+		}
+		comments = append(comments, *c)
+	}
+	return comments, rows.Err()
+}
+
+// Resolve marks a comment resolved, or open again when resolvedAt is nil
+func (r *SnippetCommentRepository) Resolve(ctx context.Context, id uuid.UUID, resolvedAt *time.Time) error {
+	query := `UPDATE snippet_comments SET resolved_at = $2, updated_at = NOW() WHERE id = $1`
+	if _, err := r.pool.Exec(ctx, query, id, resolvedAt); err != nil {
+		return fmt.Errorf("failed to resolve snippet comment: %w", err)
+	}
+	return nil
+}
+
+// UpdateAnchors saves the lines and outdated flag of comments moved by an edit of their snippet
+func (r *SnippetCommentRepository) UpdateAnchors(ctx context.Context, comments []domain.SnippetComment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, c := range comments {
+		_, err := tx.Exec(ctx, `
+			UPDATE snippet_comments SET start_line = $2, end_line = $3, outdated = $4, updated_at = NOW()
+			WHERE id = $1
+		`, c.ID, c.StartLine, c.EndLine, c.Outdated)
+		if err != nil {
+			return fmt.Errorf("failed to update snippet comment anchor: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// MoveToSnippet moves the comments on a snippet merged into another to that snippet, following
+// files renamed by the merge from their old names to their new
+func (r *SnippetCommentRepository) MoveToSnippet(ctx context.Context, fromID, toID string, renamed map[string]string) error {
+	oldNames := make([]string, 0, len(renamed))
+	newNames := make([]string, 0, len(renamed))
+	for oldName, newName := range renamed {
+		oldNames = append(oldNames, oldName)
+		newNames = append(newNames, newName)
+	}
+	query := `
+		UPDATE snippet_comments c
+		SET snippet_id = $2,
+		    file_name = COALESCE((SELECT r.new_name FROM UNNEST($3::text[], $4::text[]) AS r(old_name, new_name) WHERE r.old_name = c.file_name), c.file_name),
+		    updated_at = NOW()
+		WHERE c.snippet_id = $1
+	`
+	if _, err := r.pool.Exec(ctx, query, fromID, toID, oldNames, newNames); err != nil {
+		return fmt.Errorf("failed to move snippet comments: %w", err)
+	}
+	return nil
+}
+
+// DeleteBySnippet removes every comment on a snippet, for when it is deleted
+func (r *SnippetCommentRepository) DeleteBySnippet(ctx context.Context, snippetID string) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM snippet_comments WHERE snippet_id = $1`, snippetID); err != nil {
+		return fmt.Errorf("failed to delete snippet comments: %w", err)
+	}
+	return nil
+}
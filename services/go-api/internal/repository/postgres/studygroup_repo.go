@@ -2,11 +2,16 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"devjournal/internal/domain"
+	"devjournal/internal/tenant"
+	"devjournal/pkg/apperr"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -20,6 +25,31 @@ func NewStudyGroupRepository(pool *pgxpool.Pool) *StudyGroupRepository {
 	return &StudyGroupRepository{pool: pool}
 }
 
+const studyGroupColumns = `id, name, description, is_public, max_members, created_by, created_at, updated_at, workspace_id, last_active_at, archive_warned_at, archived_at`
+
+func scanStudyGroup(row pgx.Row) (*domain.StudyGroup, error) {
+	var group domain.StudyGroup
+	err := row.Scan(&group.ID, &group.Name, &group.Description, &group.IsPublic, &group.MaxMembers, &group.CreatedBy, &group.CreatedAt, &group.UpdatedAt, &group.WorkspaceID, &group.LastActiveAt, &group.ArchiveWarnedAt, &group.ArchivedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func collectStudyGroups(rows pgx.Rows) ([]domain.StudyGroup, error) {
+	defer rows.Close()
+
+	var groups []domain.StudyGroup
+	for rows.Next() {
+		group, err := scanStudyGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan study group: %w", err)
+		}
+		groups = append(groups, *group)
+	}
+	return groups, rows.Err()
+}
+
 // Create creates a new study group and adds the creator as owner
 func (r *StudyGroupRepository) Create(ctx context.Context, group *domain.StudyGroup) error {
 	tx, err := r.pool.Begin(ctx)
@@ -28,11 +58,15 @@ func (r *StudyGroupRepository) Create(ctx context.Context, group *domain.StudyGr
 	}
 	defer tx.Rollback(ctx)
 
+	if group.WorkspaceID == uuid.Nil {
+		group.WorkspaceID = tenant.WorkspaceID(ctx, group.CreatedBy)
+	}
+
 	// Insert study group
 	_, err = tx.Exec(ctx, `
-		INSERT INTO study_groups (id, name, description, is_public, max_members, created_by, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`, group.ID, group.Name, group.Description, group.IsPublic, group.MaxMembers, group.CreatedBy, group.CreatedAt, group.UpdatedAt)
+		INSERT INTO study_groups (id, name, description, is_public, max_members, created_by, created_at, updated_at, workspace_id, last_active_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, group.ID, group.Name, group.Description, group.IsPublic, group.MaxMembers, group.CreatedBy, group.CreatedAt, group.UpdatedAt, group.WorkspaceID, group.LastActiveAt)
 	if err != nil {
 		return fmt.Errorf("failed to insert study group: %w", err)
 	}
@@ -49,70 +83,65 @@ func (r *StudyGroupRepository) Create(ctx context.Context, group *domain.StudyGr
 	return tx.Commit(ctx)
 }
 
-// FindByID retrieves a study group by ID
+// FindByID retrieves a study group by ID, in the active workspace if the request has one. Without
+// one it isn't scoped: chat, mentions, and background jobs look groups up from room names with no
+// request workspace, and callers decide access with policy.Authorize, which hides private groups
+// from non-members.
 func (r *StudyGroupRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.StudyGroup, error) {
-	var group domain.StudyGroup
-	err := r.pool.QueryRow(ctx, `
-		SELECT id, name, description, is_public, max_members, created_by, created_at, updated_at
+	group, err := scanStudyGroup(r.pool.QueryRow(ctx, `
+		SELECT `+studyGroupColumns+`
 		FROM study_groups
-		WHERE id = $1
-	`, id).Scan(&group.ID, &group.Name, &group.Description, &group.IsPublic, &group.MaxMembers, &group.CreatedBy, &group.CreatedAt, &group.UpdatedAt)
+		WHERE id = $1 AND ($2::uuid IS NULL OR workspace_id = $2)
+	`, id, optionalWorkspace(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to find study group: %w", err)
 	}
-	return &group, nil
+	return group, nil
 }
 
 // FindByUserID retrieves all study groups a user is a member of
 func (r *StudyGroupRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.StudyGroup, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT sg.id, sg.name, sg.description, sg.is_public, sg.max_members, sg.created_by, sg.created_at, sg.updated_at
-		FROM study_groups sg
-		JOIN study_group_members sgm ON sg.id = sgm.group_id
-		WHERE sgm.user_id = $1
-		ORDER BY sg.created_at DESC
-	`, userID)
+		SELECT `+studyGroupColumns+`
+		FROM study_groups
+		WHERE id IN (SELECT group_id FROM study_group_members WHERE user_id = $1) AND workspace_id = $2
+		ORDER BY created_at DESC
+	`, userID, tenant.WorkspaceID(ctx, userID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query study groups: %w", err)
 	}
-	defer rows.Close()
-
-	var groups []domain.StudyGroup
-	for rows.Next() {
-		var group domain.StudyGroup
-		if err := rows.Scan(&group.ID, &group.Name, &group.Description, &group.IsPublic, &group.MaxMembers, &group.CreatedBy, &group.CreatedAt, &group.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan study group: %w", err)
-		}
-		groups = append(groups, group)
-	}
-
-	return groups, nil
+	return collectStudyGroups(rows)
 }
 
-// ListPublic retrieves all public study groups (for discovery)
+// ListPublic retrieves the public study groups that aren't archived (for discovery), in the active
+// workspace or, without one, in personal workspaces, so a team's groups aren't listed to outsiders
 func (r *StudyGroupRepository) ListPublic(ctx context.Context, limit, offset int) ([]domain.StudyGroup, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT id, name, description, is_public, max_members, created_by, created_at, updated_at
+		SELECT `+studyGroupColumns+`
 		FROM study_groups
-		WHERE is_public = true
+		WHERE is_public = true AND archived_at IS NULL
+		  AND workspace_id IN (SELECT id FROM workspaces WHERE id = $3 OR ($3::uuid IS NULL AND is_personal))
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
-	`, limit, offset)
+	`, limit, offset, optionalWorkspace(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query study groups: %w", err)
 	}
-	defer rows.Close()
-
-	var groups []domain.StudyGroup
-	for rows.Next() {
-		var group domain.StudyGroup
-		if err := rows.Scan(&group.ID, &group.Name, &group.Description, &group.IsPublic, &group.MaxMembers, &group.CreatedBy, &group.CreatedAt, &group.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan study group: %w", err)
-		}
-		groups = append(groups, group)
-	}
+	return collectStudyGroups(rows)
+}
 
-	return groups, nil
+// CountPublic returns the number of study groups ListPublic pages through
+func (r *StudyGroupRepository) CountPublic(ctx context.Context) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM study_groups
+		WHERE is_public = true AND archived_at IS NULL
+		  AND workspace_id IN (SELECT id FROM workspaces WHERE id = $1 OR ($1::uuid IS NULL AND is_personal))
+	`, optionalWorkspace(ctx)).Scan(&count)
+	return count, err
 }
 
 // AddMember adds a user to a study group
@@ -134,6 +163,71 @@ func (r *StudyGroupRepository) RemoveMember(ctx context.Context, groupID, userID
 	return err
 }
 
+// CreateInvite inserts a new invite to a study group
+func (r *StudyGroupRepository) CreateInvite(ctx context.Context, invite *domain.GroupInvite) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO study_group_invites (id, group_id, code_hash, created_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, invite.ID, invite.GroupID, invite.CodeHash, invite.CreatedBy, invite.ExpiresAt, invite.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create group invite: %w", err)
+	}
+	return nil
+}
+
+// HasInvite reports whether an unexpired invite to the group has the given code hash
+func (r *StudyGroupRepository) HasInvite(ctx context.Context, groupID uuid.UUID, codeHash string, now time.Time) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM study_group_invites WHERE group_id = $1 AND code_hash = $2 AND expires_at > $3)
+	`, groupID, codeHash, now).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check group invite: %w", err)
+	}
+	return exists, nil
+}
+
+// AddMemberWithInvite uses up an unexpired invite to the group and adds the member in one
+// transaction. It reports false, adding no one, if the invite was already used or has expired.
+func (r *StudyGroupRepository) AddMemberWithInvite(ctx context.Context, member *domain.StudyGroupMember, codeHash string, now time.Time) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		DELETE FROM study_group_invites WHERE group_id = $1 AND code_hash = $2 AND expires_at > $3
+	`, member.GroupID, codeHash, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to use group invite: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO study_group_members (group_id, user_id, role, joined_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (group_id, user_id) DO NOTHING
+	`, member.GroupID, member.UserID, member.Role, member.JoinedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to add group member: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit group join: %w", err)
+	}
+	return true, nil
+}
+
+// DeleteExpiredInvites removes group invites that expired before the given time
+func (r *StudyGroupRepository) DeleteExpiredInvites(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM study_group_invites WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired group invites: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
 // GetMembers retrieves all members of a study group with display names
 func (r *StudyGroupRepository) GetMembers(ctx context.Context, groupID uuid.UUID) ([]domain.StudyGroupMember, error) {
 	rows, err := r.pool.Query(ctx, `
@@ -160,6 +254,36 @@ func (r *StudyGroupRepository) GetMembers(ctx context.Context, groupID uuid.UUID
 	return members, nil
 }
 
+// GetMemberProfiles retrieves the public profiles of a study group's members, longest-standing first
+func (r *StudyGroupRepository) GetMemberProfiles(ctx context.Context, groupID uuid.UUID) ([]domain.MemberProfile, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT sgm.user_id, u.display_name, COALESCE(us.avatar_url, ''), sgm.role, sgm.joined_at,
+			COALESCE((
+				SELECT MAX(SUBSTRING(a.kind FROM 8)::int) FROM achievements a
+				WHERE a.user_id = sgm.user_id AND a.kind ~ '^streak_[0-9]+$'
+			), 0)
+		FROM study_group_members sgm
+		JOIN users u ON sgm.user_id = u.id
+		LEFT JOIN user_settings us ON us.user_id = sgm.user_id
+		WHERE sgm.group_id = $1
+		ORDER BY sgm.joined_at ASC
+	`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query member profiles: %w", err)
+	}
+	defer rows.Close()
+
+	profiles := []domain.MemberProfile{}
+	for rows.Next() {
+		var p domain.MemberProfile
+		if err := rows.Scan(&p.UserID, &p.DisplayName, &p.AvatarURL, &p.Role, &p.JoinedAt, &p.StreakMilestone); err != nil {
+			return nil, fmt.Errorf("failed to scan member profile: %w", err)
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, rows.Err()
+}
+
 // IsMember checks if a user is a member of a study group
 func (r *StudyGroupRepository) IsMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error) {
 	var exists bool
@@ -172,25 +296,40 @@ func (r *StudyGroupRepository) IsMember(ctx context.Context, groupID, userID uui
 	return exists, err
 }
 
+// GetMemberRole returns a user's role in a study group ("" if not a member)
+func (r *StudyGroupRepository) GetMemberRole(ctx context.Context, groupID, userID uuid.UUID) (string, error) {
+	var role string
+	err := r.pool.QueryRow(ctx, `
+		SELECT role FROM study_group_members
+		WHERE group_id = $1 AND user_id = $2
+	`, groupID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return role, err
+}
+
 // Delete removes a study group (only by owner)
 func (r *StudyGroupRepository) Delete(ctx context.Context, id, ownerID uuid.UUID) error {
 	result, err := r.pool.Exec(ctx, `
 		DELETE FROM study_groups
-		WHERE id = $1 AND created_by = $2
-	`, id, ownerID)
+		WHERE id = $1 AND created_by = $2 AND workspace_id = $3
+	`, id, ownerID, tenant.WorkspaceID(ctx, ownerID))
 	if err != nil {
 		return err
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("study group not found or not authorized")
+		return apperr.New(apperr.ErrNotFound, "study group not found or not authorized")
 	}
 	return nil
 }
 
-// Count returns the total number of study groups
+// Count returns the total number of study groups in the active workspace
 func (r *StudyGroupRepository) Count(ctx context.Context) (int, error) {
 	var count int
-	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM study_groups`).Scan(&count)
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM study_groups WHERE ($1::uuid IS NULL OR workspace_id = $1)
+	`, optionalWorkspace(ctx)).Scan(&count)
 	return count, err
 }
 
@@ -202,3 +341,96 @@ func (r *StudyGroupRepository) GetMemberCount(ctx context.Context, groupID uuid.
 	`, groupID).Scan(&count)
 	return count, err
 }
+
+// IsArchived reports whether a study group was archived for inactivity
+func (r *StudyGroupRepository) IsArchived(ctx context.Context, groupID uuid.UUID) (bool, error) {
+	var archived bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT archived_at IS NOT NULL FROM study_groups WHERE id = $1
+	`, groupID).Scan(&archived)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return archived, err
+}
+
+// RecordActivity moves the last activity of groups forward to the given times, clearing any
+// archival warning. Archived groups are left alone; only reactivating them resumes tracking.
+func (r *StudyGroupRepository) RecordActivity(ctx context.Context, activity map[uuid.UUID]time.Time) error {
+	if len(activity) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(activity))
+	times := make([]time.Time, 0, len(activity))
+	for id, at := range activity {
+		ids = append(ids, id)
+		times = append(times, at)
+	}
+	_, err := r.pool.Exec(ctx, `
+		UPDATE study_groups sg
+		SET last_active_at = a.at, archive_warned_at = NULL
+		FROM UNNEST($1::uuid[], $2::timestamptz[]) AS a(id, at)
+		WHERE sg.id = a.id AND sg.archived_at IS NULL AND sg.last_active_at < a.at
+	`, ids, times)
+	if err != nil {
+		return fmt.Errorf("failed to record group activity: %w", err)
+	}
+	return nil
+}
+
+// ListInactive retrieves groups across workspaces that aren't archived and were last active
+// before a time, least recently active first. With warned, it lists only groups whose owner was
+// warned of archival before warnedBefore; without, only groups not warned yet.
+func (r *StudyGroupRepository) ListInactive(ctx context.Context, activeBefore time.Time, warned bool, warnedBefore time.Time, limit int) ([]domain.StudyGroup, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+studyGroupColumns+`
+		FROM study_groups
+		WHERE archived_at IS NULL AND last_active_at < $1
+			AND CASE WHEN $2 THEN archive_warned_at < $3 ELSE archive_warned_at IS NULL END
+		ORDER BY last_active_at ASC
+		LIMIT $4
+	`, activeBefore, warned, warnedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inactive study groups: %w", err)
+	}
+	return collectStudyGroups(rows)
+}
+
+// MarkArchiveWarned records that a group's owner was warned of archival. It returns false if
+// the group was active or warned since it was listed.
+func (r *StudyGroupRepository) MarkArchiveWarned(ctx context.Context, groupID uuid.UUID, lastActiveAt, at time.Time) (bool, error) {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE study_groups SET archive_warned_at = $3
+		WHERE id = $1 AND last_active_at = $2 AND archive_warned_at IS NULL AND archived_at IS NULL
+	`, groupID, lastActiveAt, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark archive warning: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// Archive archives a group. It returns false if the group was active or archived since it was
+// listed.
+func (r *StudyGroupRepository) Archive(ctx context.Context, groupID uuid.UUID, lastActiveAt, at time.Time) (bool, error) {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE study_groups SET archived_at = $3, updated_at = $3
+		WHERE id = $1 AND last_active_at = $2 AND archived_at IS NULL
+	`, groupID, lastActiveAt, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to archive study group: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// Reactivate unarchives a group and restarts its inactivity clock at the given time
+func (r *StudyGroupRepository) Reactivate(ctx context.Context, groupID uuid.UUID, at time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE study_groups
+		SET archived_at = NULL, archive_warned_at = NULL, last_active_at = $2, updated_at = $2
+		WHERE id = $1
+	`, groupID, at)
+	if err != nil {
+		return fmt.Errorf("failed to reactivate study group: %w", err)
+	}
+	return nil
+}
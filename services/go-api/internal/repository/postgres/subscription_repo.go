@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"devjournal/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SubscriptionRepository handles billing subscription persistence with raw SQL
+type SubscriptionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSubscriptionRepository creates a new subscription repository
+func NewSubscriptionRepository(pool *pgxpool.Pool) *SubscriptionRepository {
+	return &SubscriptionRepository{pool: pool}
+}
+
+const subscriptionColumns = `user_id, plan, status, COALESCE(stripe_customer_id, ''), COALESCE(stripe_subscription_id, ''),
+	current_period_end, cancel_at_period_end, updated_at`
+
+func scanSubscription(row pgx.Row) (*domain.Subscription, error) {
+	var sub domain.Subscription
+	err := row.Scan(
+		&sub.UserID, &sub.Plan, &sub.Status, &sub.StripeCustomerID, &sub.StripeSubscriptionID,
+		&sub.CurrentPeriodEnd, &sub.CancelAtPeriodEnd, &sub.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+// FindByUserID retrieves a user's subscription (nil if they never subscribed)
+func (r *SubscriptionRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*domain.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE user_id = $1`
+	return scanSubscription(r.pool.QueryRow(ctx, query, userID))
+}
+
+// FindByCustomerID retrieves the subscription for a Stripe customer
+func (r *SubscriptionRepository) FindByCustomerID(ctx context.Context, customerID string) (*domain.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE stripe_customer_id = $1`
+	return scanSubscription(r.pool.QueryRow(ctx, query, customerID))
+}
+
+// Upsert creates or replaces a user's subscription
+func (r *SubscriptionRepository) Upsert(ctx context.Context, sub *domain.Subscription) error {
+	query := `
+		INSERT INTO subscriptions (user_id, plan, status, stripe_customer_id, stripe_subscription_id,
+			current_period_end, cancel_at_period_end, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8)
+		ON CONFLICT (user_id) DO UPDATE SET
+			plan = EXCLUDED.plan,
+			status = EXCLUDED.status,
+			stripe_customer_id = COALESCE(EXCLUDED.stripe_customer_id, subscriptions.stripe_customer_id),
+			stripe_subscription_id = COALESCE(EXCLUDED.stripe_subscription_id, subscriptions.stripe_subscription_id),
+			current_period_end = EXCLUDED.current_period_end,
+			cancel_at_period_end = EXCLUDED.cancel_at_period_end,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.pool.Exec(ctx, query,
+		sub.UserID, sub.Plan, sub.Status, sub.StripeCustomerID, sub.StripeSubscriptionID,
+		sub.CurrentPeriodEnd, sub.CancelAtPeriodEnd, sub.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert subscription: %w", err)
+	}
+	return nil
+}
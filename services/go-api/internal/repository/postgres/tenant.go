@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"context"
+
+	"devjournal/internal/tenant"
+
+	"github.com/google/uuid"
+)
+
+// optionalWorkspace returns the request's active workspace, or nil when there is none
+// (background jobs, admin tooling). Lookups by primary key filter with
+// ($n::uuid IS NULL OR workspace_id = $n) so they stay scoped for user requests.
+func optionalWorkspace(ctx context.Context) *uuid.UUID {
+	if id, ok := tenant.FromContext(ctx); ok {
+		return &id
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"devjournal/internal/domain"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TILRepository handles TIL micro-entry persistence with raw SQL
+type TILRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTILRepository creates a new TIL repository
+func NewTILRepository(pool *pgxpool.Pool) *TILRepository {
+	return &TILRepository{pool: pool}
+}
+
+// Create inserts a new TIL entry
+func (r *TILRepository) Create(ctx context.Context, til *domain.TILEntry) error {
+	query := `
+		INSERT INTO til_entries (id, user_id, content, tags, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.pool.Exec(ctx, query, til.ID, til.UserID, til.Content, til.Tags, til.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create TIL entry: %w", err)
+	}
+	return nil
+}
+
+// FindByUserID retrieves a user's TIL feed, newest first
+func (r *TILRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.TILEntry, error) {
+	query := `
+		SELECT id, user_id, content, tags, created_at
+		FROM til_entries
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find TIL entries: %w", err)
+	}
+	defer rows.Close()
+
+	var tils []domain.TILEntry
+	for rows.Next() {
+		var til domain.TILEntry
+		err := rows.Scan(
+			&til.ID,
+			&til.UserID,
+			&til.Content,
+			&til.Tags,
+			&til.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan TIL entry: %w", err)
+		}
+		tils = append(tils, til)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating TIL entries: %w", err)
+	}
+
+	return tils, nil
+}
+
+// Count returns the total number of TIL entries for a user
+func (r *TILRepository) Count(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM til_entries WHERE user_id = $1`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count TIL entries: %w", err)
+	}
+	return count, nil
+}
+
+// Delete removes a TIL entry
+func (r *TILRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM til_entries WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete TIL entry: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperr.New(apperr.ErrNotFound, "TIL entry not found or unauthorized")
+	}
+	return nil
+}
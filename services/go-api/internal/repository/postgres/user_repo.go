@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"devjournal/internal/domain"
+	"devjournal/pkg/apperr"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -42,10 +44,32 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	return nil
 }
 
+// CreateWithWorkspace inserts a new user and their personal workspace together, so a failed
+// workspace insert doesn't leave behind a user who can't sign up again
+func (r *UserRepository) CreateWithWorkspace(ctx context.Context, user *domain.User, ws *domain.Workspace) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO users (id, email, password_hash, display_name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, user.ID, user.Email, user.PasswordHash, user.DisplayName, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	if err := insertWorkspace(ctx, tx, ws); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
 // FindByEmail retrieves a user by email
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, display_name, created_at, updated_at
+		SELECT id, email, password_hash, display_name, is_admin, deactivated_at, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -57,6 +81,8 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain
 		&user.Email,
 		&user.PasswordHash,
 		&user.DisplayName,
+		&user.IsAdmin,
+		&user.DeactivatedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -72,7 +98,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain
 // FindByID retrieves a user by ID
 func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, display_name, created_at, updated_at
+		SELECT id, email, password_hash, display_name, is_admin, deactivated_at, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -84,6 +110,8 @@ func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Us
 		&user.Email,
 		&user.PasswordHash,
 		&user.DisplayName,
+		&user.IsAdmin,
+		&user.DeactivatedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -113,7 +141,85 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("user not found")
+		return apperr.New(apperr.ErrNotFound, "user not found")
+	}
+	return nil
+}
+
+// List retrieves users, oldest first, with the total count
+func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]domain.User, int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, email, password_hash, display_name, is_admin, deactivated_at, created_at, updated_at
+		FROM users
+		ORDER BY created_at, id
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.DisplayName, &u.IsAdmin, &u.DeactivatedAt, &u.CreatedAt, &u.UpdatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM users`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return users, total, nil
+}
+
+// Deactivate marks a user deactivated and revokes what acts for them without signing in: their
+// calendar feed and heartbeat tokens, push devices, and chat tickets, while Git webhooks and site
+// publishing are paused. It returns false if the user was already deactivated.
+func (r *UserRepository) Deactivate(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `UPDATE users SET deactivated_at = $2, updated_at = $2 WHERE id = $1 AND deactivated_at IS NULL`, id, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to deactivate user: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+	for _, revoke := range []string{
+		`DELETE FROM calendar_feeds WHERE user_id = $1`,
+		`DELETE FROM push_devices WHERE user_id = $1`,
+		`DELETE FROM chat_tickets WHERE user_id = $1`,
+		`DELETE FROM refresh_tokens WHERE user_id = $1`,
+		`DELETE FROM device_authorizations WHERE user_id = $1 AND status = 'approved'`,
+		`UPDATE coding_sources SET token_hash = NULL WHERE user_id = $1`,
+		`UPDATE git_hooks SET enabled = false WHERE user_id = $1`,
+		`UPDATE site_publishers SET enabled = false WHERE user_id = $1`,
+	} {
+		if _, err := tx.Exec(ctx, revoke, id); err != nil {
+			return false, fmt.Errorf("failed to revoke access of deactivated user: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit deactivation: %w", err)
+	}
+	return true, nil
+}
+
+// Reactivate lets a deactivated user sign in again. Revoked tokens stay revoked.
+func (r *UserRepository) Reactivate(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := r.pool.Exec(ctx, `UPDATE users SET deactivated_at = NULL, updated_at = $2 WHERE id = $1 AND deactivated_at IS NOT NULL`, id, at)
+	if err != nil {
+		return fmt.Errorf("failed to reactivate user: %w", err)
 	}
 	return nil
 }
@@ -126,7 +232,7 @@ func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("user not found")
+		return apperr.New(apperr.ErrNotFound, "user not found")
 	}
 	return nil
 }
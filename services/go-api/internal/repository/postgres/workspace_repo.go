@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"devjournal/internal/domain"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WorkspaceRepository handles workspace and membership persistence with raw SQL
+type WorkspaceRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWorkspaceRepository creates a new workspace repository
+func NewWorkspaceRepository(pool *pgxpool.Pool) *WorkspaceRepository {
+	return &WorkspaceRepository{pool: pool}
+}
+
+// Create inserts a workspace and adds its owner as a member
+func (r *WorkspaceRepository) Create(ctx context.Context, ws *domain.Workspace) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertWorkspace(ctx, tx, ws); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// insertWorkspace inserts a workspace and its owner's membership in a transaction
+func insertWorkspace(ctx context.Context, tx pgx.Tx, ws *domain.Workspace) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO workspaces (id, name, slug, owner_id, is_personal, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, ws.ID, ws.Name, ws.Slug, ws.OwnerID, ws.IsPersonal, ws.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert workspace: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO workspace_members (workspace_id, user_id, role, joined_at)
+		VALUES ($1, $2, 'owner', $3)
+	`, ws.ID, ws.OwnerID, ws.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add workspace owner: %w", err)
+	}
+	return nil
+}
+
+// SlugExists reports whether a workspace slug is taken
+func (r *WorkspaceRepository) SlugExists(ctx context.Context, slug string) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM workspaces WHERE slug = $1)`, slug).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check workspace slug: %w", err)
+	}
+	return exists, nil
+}
+
+// ListForUser retrieves every workspace a user belongs to, personal first
+func (r *WorkspaceRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]domain.Workspace, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT w.id, w.name, w.slug, w.owner_id, w.is_personal, wm.role, w.created_at
+		FROM workspaces w
+		JOIN workspace_members wm ON wm.workspace_id = w.id
+		WHERE wm.user_id = $1
+		ORDER BY w.is_personal DESC, w.name ASC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	var workspaces []domain.Workspace
+	for rows.Next() {
+		var ws domain.Workspace
+		if err := rows.Scan(&ws.ID, &ws.Name, &ws.Slug, &ws.OwnerID, &ws.IsPersonal, &ws.Role, &ws.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, ws)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating workspaces: %w", err)
+	}
+
+	return workspaces, nil
+}
+
+// GetMemberRole returns a user's role in a workspace ("" if not a member)
+func (r *WorkspaceRepository) GetMemberRole(ctx context.Context, workspaceID, userID uuid.UUID) (string, error) {
+	var role string
+	err := r.pool.QueryRow(ctx, `
+		SELECT role FROM workspace_members
+		WHERE workspace_id = $1 AND user_id = $2
+	`, workspaceID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get workspace role: %w", err)
+	}
+	return role, nil
+}
+
+// AddMember adds a user to a workspace, updating their role if they are already a member
+func (r *WorkspaceRepository) AddMember(ctx context.Context, member *domain.WorkspaceMember) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO workspace_members (workspace_id, user_id, role, joined_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = EXCLUDED.role
+		WHERE workspace_members.role != 'owner'
+	`, member.WorkspaceID, member.UserID, member.Role, member.JoinedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add workspace member: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember removes a non-owner from a workspace
+func (r *WorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `
+		DELETE FROM workspace_members
+		WHERE workspace_id = $1 AND user_id = $2 AND role != 'owner'
+	`, workspaceID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove workspace member: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperr.New(apperr.ErrNotFound, "workspace member not found")
+	}
+	return nil
+}
+
+// GetMembers retrieves all members of a workspace with display names
+func (r *WorkspaceRepository) GetMembers(ctx context.Context, workspaceID uuid.UUID) ([]domain.WorkspaceMember, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT wm.workspace_id, wm.user_id, u.display_name, wm.role, wm.joined_at
+		FROM workspace_members wm
+		JOIN users u ON wm.user_id = u.id
+		WHERE wm.workspace_id = $1
+		ORDER BY wm.joined_at ASC
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workspace members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []domain.WorkspaceMember
+	for rows.Next() {
+		var m domain.WorkspaceMember
+		if err := rows.Scan(&m.WorkspaceID, &m.UserID, &m.DisplayName, &m.Role, &m.JoinedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan workspace member: %w", err)
+		}
+		members = append(members, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating workspace members: %w", err)
+	}
+
+	return members, nil
+}
+
+// UpdateMemberRole changes a non-owner member's role
+func (r *WorkspaceRepository) UpdateMemberRole(ctx context.Context, workspaceID, userID uuid.UUID, role string) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE workspace_members SET role = $3
+		WHERE workspace_id = $1 AND user_id = $2 AND role != 'owner'
+	`, workspaceID, userID, role)
+	if err != nil {
+		return fmt.Errorf("failed to update workspace member role: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperr.New(apperr.ErrNotFound, "workspace member not found")
+	}
+	return nil
+}
+
+// SeatUsage returns the seat limit and member count of an organization workspace.
+// The limit is 0 for workspaces that are not organizations.
+func (r *WorkspaceRepository) SeatUsage(ctx context.Context, workspaceID uuid.UUID) (limit, used int, err error) {
+	err = r.pool.QueryRow(ctx, `
+		SELECT COALESCE((SELECT seat_limit FROM organizations WHERE id = $1), 0),
+		       (SELECT COUNT(*) FROM workspace_members WHERE workspace_id = $1)
+	`, workspaceID).Scan(&limit, &used)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get seat usage: %w", err)
+	}
+	return limit, used, nil
+}
+
+// CountOwned counts the shared workspaces, including organizations, a user owns
+func (r *WorkspaceRepository) CountOwned(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM workspaces WHERE owner_id = $1 AND NOT is_personal
+	`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count owned workspaces: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/tenant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// YearlyReviewRepository aggregates a user's year from their journal and progress, and caches
+// the resulting reports, with raw SQL
+type YearlyReviewRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewYearlyReviewRepository creates a new yearly review repository
+func NewYearlyReviewRepository(pool *pgxpool.Pool) *YearlyReviewRepository {
+	return &YearlyReviewRepository{pool: pool}
+}
+
+// Get retrieves a cached report for the active workspace, or nil if there is none
+func (r *YearlyReviewRepository) Get(ctx context.Context, userID uuid.UUID, year int) (*domain.YearlyReview, error) {
+	var report []byte
+	err := r.pool.QueryRow(ctx, `
+		SELECT report FROM yearly_reviews WHERE user_id = $1 AND workspace_id = $2 AND year = $3
+	`, userID, tenant.WorkspaceID(ctx, userID), year).Scan(&report)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find yearly review: %w", err)
+	}
+	var review domain.YearlyReview
+	if err := json.Unmarshal(report, &review); err != nil {
+		return nil, fmt.Errorf("failed to decode yearly review: %w", err)
+	}
+	return &review, nil
+}
+
+// Upsert caches a report for the active workspace, replacing any earlier one
+func (r *YearlyReviewRepository) Upsert(ctx context.Context, userID uuid.UUID, review *domain.YearlyReview) error {
+	report, err := json.Marshal(review)
+	if err != nil {
+		return fmt.Errorf("failed to encode yearly review: %w", err)
+	}
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO yearly_reviews (user_id, workspace_id, year, report, computed_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, workspace_id, year)
+		DO UPDATE SET report = $4, computed_at = $5
+	`, userID, tenant.WorkspaceID(ctx, userID), review.Year, report, review.ComputedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save yearly review: %w", err)
+	}
+	return nil
+}
+
+// ListStale returns the cached reports computed before computedBefore that aren't final yet,
+// least recently computed first
+func (r *YearlyReviewRepository) ListStale(ctx context.Context, computedBefore time.Time, limit int) ([]domain.YearlyReviewKey, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT user_id, workspace_id, year
+		FROM yearly_reviews
+		WHERE computed_at < LEAST($1, make_timestamptz(year + 1, 1, 1, 0, 0, 0, 'UTC'))
+		ORDER BY computed_at ASC
+		LIMIT $2
+	`, computedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stale yearly reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []domain.YearlyReviewKey
+	for rows.Next() {
+		var key domain.YearlyReviewKey
+		if err := rows.Scan(&key.UserID, &key.WorkspaceID, &key.Year); err != nil {
+			return nil, fmt.Errorf("failed to scan yearly review: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// Aggregate fills in a report's journal totals, busiest week, top tags, and moods from the
+// active workspace, and its longest streak, for the year
+func (r *YearlyReviewRepository) Aggregate(ctx context.Context, userID uuid.UUID, review *domain.YearlyReview) error {
+	from, until := domain.YearBounds(review.Year)
+	workspaceID := tenant.WorkspaceID(ctx, userID)
+
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(word_count), 0)
+		FROM journal_entries
+		WHERE user_id = $1 AND workspace_id = $2 AND created_at >= $3 AND created_at < $4
+	`, userID, workspaceID, from, until).Scan(&review.TotalEntries, &review.TotalWords)
+	if err != nil {
+		return fmt.Errorf("failed to total journal entries: %w", err)
+	}
+
+	var week domain.BusiestWeek
+	err = r.pool.QueryRow(ctx, `
+		SELECT DATE_TRUNC('week', created_at AT TIME ZONE 'UTC') AS week_start, COUNT(*), COALESCE(SUM(word_count), 0)
+		FROM journal_entries
+		WHERE user_id = $1 AND workspace_id = $2 AND created_at >= $3 AND created_at < $4
+		GROUP BY week_start
+		ORDER BY COUNT(*) DESC, week_start ASC
+		LIMIT 1
+	`, userID, workspaceID, from, until).Scan(&week.WeekStart, &week.Entries, &week.Words)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		review.BusiestWeek = nil
+	case err != nil:
+		return fmt.Errorf("failed to find busiest week: %w", err)
+	default:
+		week.WeekStart = week.WeekStart.UTC()
+		review.BusiestWeek = &week
+	}
+
+	tagRows, err := r.pool.Query(ctx, `
+		SELECT tag, COUNT(*)
+		FROM journal_entries, UNNEST(tags) AS tag
+		WHERE user_id = $1 AND workspace_id = $2 AND created_at >= $3 AND created_at < $4
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag ASC
+		LIMIT $5
+	`, userID, workspaceID, from, until, domain.YearlyReviewTopTags)
+	if err != nil {
+		return fmt.Errorf("failed to count tags: %w", err)
+	}
+	defer tagRows.Close()
+	review.TopTags = []domain.TagCount{}
+	for tagRows.Next() {
+		var t domain.TagCount
+		if err := tagRows.Scan(&t.Tag, &t.Count); err != nil {
+			return fmt.Errorf("failed to scan tag count: %w", err)
+		}
+		review.TopTags = append(review.TopTags, t)
+	}
+	if err := tagRows.Err(); err != nil {
+		return err
+	}
+
+	moodRows, err := r.pool.Query(ctx, `
+		SELECT mood, COUNT(*)
+		FROM journal_entries
+		WHERE user_id = $1 AND workspace_id = $2 AND created_at >= $3 AND created_at < $4 AND COALESCE(mood, '') <> ''
+		GROUP BY mood
+		ORDER BY COUNT(*) DESC, mood ASC
+	`, userID, workspaceID, from, until)
+	if err != nil {
+		return fmt.Errorf("failed to count moods: %w", err)
+	}
+	defer moodRows.Close()
+	review.Moods = []domain.MoodCount{}
+	for moodRows.Next() {
+		var m domain.MoodCount
+		if err := moodRows.Scan(&m.Mood, &m.Count); err != nil {
+			return fmt.Errorf("failed to scan mood count: %w", err)
+		}
+		review.Moods = append(review.Moods, m)
+	}
+	if err := moodRows.Err(); err != nil {
+		return err
+	}
+
+	// Consecutive active days share date minus row number, so each run is one group
+	err = r.pool.QueryRow(ctx, `
+		SELECT COALESCE(MAX(days), 0)
+		FROM (
+			SELECT COUNT(*) AS days
+			FROM (
+				SELECT date - (ROW_NUMBER() OVER (ORDER BY date))::int AS run
+				FROM learning_progress
+				WHERE user_id = $1 AND date >= $2 AND date < $3
+					AND (entries_count > 0 OR snippets_count > 0 OR tils_count > 0 OR problems_count > 0)
+			) active
+			GROUP BY run
+		) runs
+	`, userID, from, until).Scan(&review.LongestStreak)
+	if err != nil {
+		return fmt.Errorf("failed to find longest streak: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package requestid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	for id, want := range map[string]bool{
+		New():                    true,
+		"req_01HZX.2:a":          true,
+		"":                       false,
+		"a b":                    false,
+		"id\nPANIC: forged":      false,
+		strings.Repeat("a", 129): false,
+		strings.Repeat("a", 128): true,
+	} {
+		if got := Valid(id); got != want {
+			t.Errorf("Valid(%q) = %v; want %v", id, got, want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/mail"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+// Announcement delivery batch sizes
+const (
+	announcementDueBatch       = 20  // announcements one publishing run handles at most
+	announcementRecipientBatch = 500 // recipients notified over chat, or emailed, at a time
+)
+
+var (
+	ErrAnnouncementNotFound     = apperr.New(ErrNotFound, "announcement not found")
+	ErrAnnouncementTitle        = apperr.Newf(ErrValidation, "title is required and must be at most %d characters", domain.MaxAnnouncementTitleLength)
+	ErrAnnouncementBody         = apperr.Newf(ErrValidation, "body is required and must be at most %d characters", domain.MaxAnnouncementBodyLength)
+	ErrAnnouncementAudience     = apperr.New(ErrValidation, "audience must be all, group_owners, or inactive")
+	ErrAnnouncementInactiveDays = apperr.Newf(ErrValidation, "inactiveDays must be between 1 and %d, and only set for the inactive audience", domain.MaxAnnouncementInactiveDays)
+	ErrAnnouncementPublished    = apperr.New(ErrConflict, "the announcement was already published")
+)
+
+// AnnouncementNotifier sends system messages to users' open chat connections
+type AnnouncementNotifier interface {
+	// Notify sends a message to every connection of the given users, whichever room it is in
+	Notify(ctx context.Context, userIDs []string, msg *domain.ChatMessage) error
+}
+
+// AnnouncementService handles system announcements admins send to all users, group owners, or
+// inactive users: in each recipient's notification center, as a system message in open chat
+// connections, and optionally by email
+type AnnouncementService struct {
+	announcementRepo *postgres.AnnouncementRepository
+	notifier         AnnouncementNotifier
+	mailer           mail.Sender // nil disables email
+}
+
+// NewAnnouncementService creates a new announcement service. A nil mailer disables
+// announcement emails.
+func NewAnnouncementService(announcementRepo *postgres.AnnouncementRepository, notifier AnnouncementNotifier, mailer mail.Sender) *AnnouncementService {
+	return &AnnouncementService{
+		announcementRepo: announcementRepo,
+		notifier:         notifier,
+		mailer:           mailer,
+	}
+}
+
+// Create saves an announcement and publishes it right away unless it is scheduled for later.
+// Emails go out with the next run of Publisher.
+func (s *AnnouncementService) Create(ctx context.Context, adminID uuid.UUID, req *domain.CreateAnnouncementRequest) (*domain.Announcement, error) {
+	now := time.Now().UTC()
+	a := &domain.Announcement{
+		ID:           uuid.New(),
+		Title:        strings.TrimSpace(req.Title),
+		Body:         strings.TrimSpace(req.Body),
+		Audience:     req.Audience,
+		InactiveDays: req.InactiveDays,
+		SendEmail:    req.SendEmail,
+		PublishAt:    now,
+		CreatedBy:    adminID,
+		CreatedAt:    now,
+	}
+	if a.Title == "" || utf8.RuneCountInString(a.Title) > domain.MaxAnnouncementTitleLength {
+		return nil, ErrAnnouncementTitle
+	}
+	if a.Body == "" || utf8.RuneCountInString(a.Body) > domain.MaxAnnouncementBodyLength {
+		return nil, ErrAnnouncementBody
+	}
+	if a.Audience == "" {
+		a.Audience = domain.AnnouncementAudienceAll
+	}
+	if !domain.ValidAnnouncementAudience(a.Audience) {
+		return nil, ErrAnnouncementAudience
+	}
+	if a.Audience == domain.AnnouncementAudienceInactive && a.InactiveDays == 0 {
+		a.InactiveDays = domain.DefaultAnnouncementInactiveDays
+	}
+	if a.InactiveDays != 0 && (a.Audience != domain.AnnouncementAudienceInactive || a.InactiveDays < 1 || a.InactiveDays > domain.MaxAnnouncementInactiveDays) {
+		return nil, ErrAnnouncementInactiveDays
+	}
+	if req.PublishAt != nil && req.PublishAt.After(now) {
+		a.PublishAt = req.PublishAt.UTC()
+	}
+
+	if err := s.announcementRepo.Create(ctx, a); err != nil {
+		return nil, err
+	}
+	if a.PublishAt.After(now) {
+		return a, nil
+	}
+	if err := s.publish(ctx, a, now); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// List returns all announcements, latest scheduled first, for admins
+func (s *AnnouncementService) List(ctx context.Context, limit, offset int) ([]domain.Announcement, int, error) {
+	return s.announcementRepo.List(ctx, limit, offset)
+}
+
+// Delete cancels an announcement that hasn't been published yet
+func (s *AnnouncementService) Delete(ctx context.Context, id uuid.UUID) error {
+	deleted, err := s.announcementRepo.DeleteScheduled(ctx, id)
+	if err != nil || deleted {
+		return err
+	}
+	a, err := s.announcementRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if a == nil {
+		return ErrAnnouncementNotFound
+	}
+	return ErrAnnouncementPublished
+}
+
+// ListForUser returns the announcements in a user's notification center, newest first, and
+// how many are unread
+func (s *AnnouncementService) ListForUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]domain.Announcement, int, int, error) {
+	announcements, total, err := s.announcementRepo.ListByUser(ctx, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	unread, err := s.announcementRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return announcements, total, unread, nil
+}
+
+// MarkRead marks the user's announcements as read, all of them when req has no IDs
+func (s *AnnouncementService) MarkRead(ctx context.Context, userID uuid.UUID, req *domain.MarkAnnouncementsReadRequest) (int, error) {
+	return s.announcementRepo.MarkRead(ctx, userID, req.IDs, time.Now().UTC())
+}
+
+// Publisher returns a job that publishes scheduled announcements once they are due, and emails
+// published announcements that should be
+func (s *AnnouncementService) Publisher() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		now := time.Now().UTC()
+		due, err := s.announcementRepo.ListDue(ctx, now, announcementDueBatch)
+		if err != nil {
+			return err
+		}
+		for i := range due {
+			if err := s.publish(ctx, &due[i], now); err != nil {
+				return err
+			}
+		}
+
+		if s.mailer == nil {
+			return nil
+		}
+		unemailed, err := s.announcementRepo.ListUnemailed(ctx, now, announcementDueBatch)
+		if err != nil {
+			return err
+		}
+		for i := range unemailed {
+			// Claim first so a failing provider can't cause repeat emails every run
+			claimed, err := s.announcementRepo.MarkEmailed(ctx, unemailed[i].ID, now)
+			if err != nil {
+				return err
+			}
+			if claimed {
+				s.email(ctx, &unemailed[i])
+			}
+		}
+		return nil
+	}
+}
+
+// publish delivers an announcement to its audience's notification centers and sends it to
+// those connected to chat, unless it was already published
+func (s *AnnouncementService) publish(ctx context.Context, a *domain.Announcement, now time.Time) error {
+	recipients, published, err := s.announcementRepo.Publish(ctx, a, now)
+	if err != nil || !published {
+		return err
+	}
+	a.PublishedAt, a.Recipients = &now, recipients
+	log.Printf("Published announcement %s to %d users", a.ID, recipients)
+
+	msg := domain.NewChatMessage("", "", "System", a.Title+"\n\n"+a.Body, "system")
+	msg.Announcement = a
+	err = s.eachRecipientBatch(ctx, a.ID, func(batch []domain.AnnouncementRecipient) {
+		userIDs := make([]string, len(batch))
+		for i, recipient := range batch {
+			userIDs[i] = recipient.UserID.String()
+		}
+		if err := s.notifier.Notify(ctx, userIDs, msg); err != nil {
+			log.Printf("WARN: Failed to send announcement %s over chat: %v", a.ID, err)
+		}
+	})
+	if err != nil {
+		// It is in everyone's notification center regardless
+		log.Printf("WARN: Failed to send announcement %s over chat: %v", a.ID, err)
+	}
+	return nil
+}
+
+// email sends an announcement to each of its recipients
+func (s *AnnouncementService) email(ctx context.Context, a *domain.Announcement) {
+	err := s.eachRecipientBatch(ctx, a.ID, func(batch []domain.AnnouncementRecipient) {
+		for _, recipient := range batch {
+			if err := s.mailer.Send(ctx, recipient.Email, a.Title, a.Body+"\n"); err != nil {
+				log.Printf("WARN: Failed to email announcement %s to user %s: %v", a.ID, recipient.UserID, err)
+			}
+		}
+	})
+	if err != nil {
+		log.Printf("WARN: Failed to email announcement %s: %v", a.ID, err)
+	}
+}
+
+// eachRecipientBatch calls fn with an announcement's recipients, a batch at a time
+func (s *AnnouncementService) eachRecipientBatch(ctx context.Context, id uuid.UUID, fn func([]domain.AnnouncementRecipient)) error {
+	after := uuid.Nil
+	for {
+		batch, err := s.announcementRepo.ListRecipients(ctx, id, after, announcementRecipientBatch)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		fn(batch)
+		after = batch[len(batch)-1].UserID
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"devjournal/internal/backup"
+	"devjournal/internal/domain"
+	"devjournal/pkg/apperr"
+)
+
+// backupNamePattern matches the names of the archives BackupService writes
+var backupNamePattern = regexp.MustCompile(`^devjournal-\d{8}T\d{6}Z\.tar\.gz$`)
+
+var (
+	ErrBackupNotFound = apperr.New(ErrNotFound, "backup not found")
+	ErrBackupRunning  = apperr.New(ErrConflict, "a backup is already running")
+)
+
+// BackupService writes backups of both databases to the backup directory in the background,
+// one at a time, and verifies them. Backups are written to a .partial file first, so the
+// directory only ever lists complete archives.
+type BackupService struct {
+	dumper *backup.Dumper
+	dir    string
+
+	mu      sync.Mutex
+	running *domain.Backup
+	failed  *domain.Backup // the last backup, if it failed
+}
+
+// NewBackupService creates a new backup service writing archives to dir
+func NewBackupService(dumper *backup.Dumper, dir string) *BackupService {
+	return &BackupService{dumper: dumper, dir: dir}
+}
+
+// Create starts a backup and returns it while it runs
+func (s *BackupService) Create(ctx context.Context) (*domain.Backup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running != nil {
+		return nil, ErrBackupRunning
+	}
+
+	now := time.Now().UTC()
+	b := &domain.Backup{
+		Name:      "devjournal-" + now.Format("20060102T150405Z") + ".tar.gz",
+		Status:    domain.BackupRunning,
+		StartedAt: &now,
+	}
+	s.running, s.failed = b, nil
+	running := *b
+	// The backup outlives the request that started it
+	go s.run(context.WithoutCancel(ctx), b)
+	return &running, nil
+}
+
+// run writes a backup and records how it went
+func (s *BackupService) run(ctx context.Context, b *domain.Backup) {
+	manifest, err := s.write(ctx, b.Name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = nil
+	if err != nil {
+		log.Printf("ERROR: Backup %s failed: %v", b.Name, err)
+		now := time.Now().UTC()
+		b.Status, b.CompletedAt, b.Error = domain.BackupFailed, &now, err.Error()
+		s.failed = b
+		return
+	}
+	log.Printf("Backup %s completed: %d tables and %d collections as of %s",
+		b.Name, len(manifest.Postgres), len(manifest.Mongo), manifest.SnapshotAt.Format(time.RFC3339))
+}
+
+// write writes a backup archive to the backup directory
+func (s *BackupService) write(ctx context.Context, name string) (*domain.BackupManifest, error) {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(s.dir, name)
+	f, err := os.OpenFile(path+".partial", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	manifest, err := s.dumper.Write(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, os.Rename(f.Name(), path)
+}
+
+// List returns the backups in the backup directory, newest first, after the one running or
+// the last one if it failed
+func (s *BackupService) List(ctx context.Context) ([]domain.Backup, error) {
+	backups := []domain.Backup{}
+	s.mu.Lock()
+	if s.running != nil {
+		backups = append(backups, *s.running)
+	}
+	if s.failed != nil {
+		backups = append(backups, *s.failed)
+	}
+	s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return backups, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var completed []domain.Backup
+	for _, entry := range entries {
+		if entry.IsDir() || !backupNamePattern.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		modified := info.ModTime().UTC()
+		completed = append(completed, domain.Backup{
+			Name:        entry.Name(),
+			Status:      domain.BackupCompleted,
+			Size:        info.Size(),
+			CompletedAt: &modified,
+		})
+	}
+	// Names start with the time the backup was taken
+	sort.Slice(completed, func(i, j int) bool { return completed[i].Name > completed[j].Name })
+	return append(backups, completed...), nil
+}
+
+// Verify checks that a backup in the backup directory can be restored
+func (s *BackupService) Verify(ctx context.Context, name string) (*domain.BackupVerification, error) {
+	if !backupNamePattern.MatchString(name) {
+		return nil, ErrBackupNotFound
+	}
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrBackupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	result := backup.Verify(f)
+	result.Name = name
+	return result, nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"devjournal/internal/billing"
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrBillingDisabled = apperr.New(ErrUnavailable, "billing is not configured")
+	ErrInvalidPlan     = apperr.New(ErrValidation, "plan must be pro or team")
+)
+
+// BillingConfig holds the Stripe settings billing needs
+type BillingConfig struct {
+	SecretKey     string
+	WebhookSecret string
+	PriceIDs      map[string]string // plan ID -> Stripe price ID
+	SuccessURL    string
+	CancelURL     string
+}
+
+// BillingService handles plans, Stripe checkout, and subscription webhooks
+type BillingService struct {
+	subscriptionRepo *postgres.SubscriptionRepository
+	userRepo         *postgres.UserRepository
+	stripe           *billing.Client
+	cfg              BillingConfig
+}
+
+// NewBillingService creates a new billing service
+func NewBillingService(subscriptionRepo *postgres.SubscriptionRepository, userRepo *postgres.UserRepository, cfg BillingConfig) *BillingService {
+	return &BillingService{
+		subscriptionRepo: subscriptionRepo,
+		userRepo:         userRepo,
+		stripe:           billing.NewClient(cfg.SecretKey),
+		cfg:              cfg,
+	}
+}
+
+// Enabled reports whether Stripe is configured
+func (s *BillingService) Enabled() bool {
+	return s.cfg.SecretKey != ""
+}
+
+// Plans returns every plan
+func (s *BillingService) Plans() []domain.Plan {
+	return domain.Plans
+}
+
+// Subscription returns a user's subscription, defaulting to an active free plan
+func (s *BillingService) Subscription(ctx context.Context, userID uuid.UUID) (*domain.Subscription, error) {
+	sub, err := s.subscriptionRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		sub = &domain.Subscription{UserID: userID, Plan: domain.PlanFree, Status: domain.SubscriptionActive}
+	}
+	sub.Plan = sub.EffectivePlan()
+	return sub, nil
+}
+
+// Checkout creates a Stripe checkout session for a paid plan and returns it
+func (s *BillingService) Checkout(ctx context.Context, userID uuid.UUID, planID string) (*billing.CheckoutSession, error) {
+	if !s.Enabled() {
+		return nil, ErrBillingDisabled
+	}
+	priceID := s.cfg.PriceIDs[planID]
+	if priceID == "" {
+		return nil, ErrInvalidPlan
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, apperr.New(ErrNotFound, "user not found")
+	}
+	existing, err := s.subscriptionRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := billing.CheckoutParams{
+		PriceID:           priceID,
+		CustomerEmail:     user.Email,
+		ClientReferenceID: userID.String(),
+		SuccessURL:        s.cfg.SuccessURL,
+		CancelURL:         s.cfg.CancelURL,
+		Metadata:          map[string]string{"user_id": userID.String(), "plan": planID},
+	}
+	if existing != nil {
+		params.CustomerID = existing.StripeCustomerID
+	}
+
+	session, err := s.stripe.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkout session: %w", err)
+	}
+	return session, nil
+}
+
+// stripeSubscription is the subset of a Stripe subscription object the webhook reads
+type stripeSubscription struct {
+	ID                string            `json:"id"`
+	Customer          string            `json:"customer"`
+	Status            string            `json:"status"`
+	CurrentPeriodEnd  int64             `json:"current_period_end"`
+	CancelAtPeriodEnd bool              `json:"cancel_at_period_end"`
+	Metadata          map[string]string `json:"metadata"`
+	Items             struct {
+		Data []struct {
+			Price struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// stripeCheckoutSession is the subset of a completed checkout session the webhook reads
+type stripeCheckoutSession struct {
+	ClientReferenceID string            `json:"client_reference_id"`
+	Customer          string            `json:"customer"`
+	Subscription      string            `json:"subscription"`
+	Metadata          map[string]string `json:"metadata"`
+}
+
+// HandleWebhook verifies and applies a Stripe webhook event.
+// Events that do not affect subscriptions are acknowledged and ignored.
+func (s *BillingService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	if !s.Enabled() || s.cfg.WebhookSecret == "" {
+		return ErrBillingDisabled
+	}
+	event, err := billing.ConstructEvent(payload, signature, s.cfg.WebhookSecret)
+	if err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case "checkout.session.completed":
+		var session stripeCheckoutSession
+		if err := json.Unmarshal(event.Data.Object, &session); err != nil {
+			return fmt.Errorf("invalid checkout session: %w", err)
+		}
+		return s.applyCheckout(ctx, &session)
+	case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+		var sub stripeSubscription
+		if err := json.Unmarshal(event.Data.Object, &sub); err != nil {
+			return fmt.Errorf("invalid subscription: %w", err)
+		}
+		return s.applySubscription(ctx, &sub)
+	default:
+		return nil
+	}
+}
+
+// applyCheckout records the customer and subscription created by a completed checkout
+func (s *BillingService) applyCheckout(ctx context.Context, session *stripeCheckoutSession) error {
+	userID, err := uuid.Parse(session.ClientReferenceID)
+	if err != nil {
+		log.Printf("WARN: Ignoring checkout session without a user reference (customer %s)", session.Customer)
+		return nil
+	}
+	plan := session.Metadata["plan"]
+	if domain.FindPlan(plan) == nil {
+		plan = domain.PlanFree
+	}
+
+	return s.subscriptionRepo.Upsert(ctx, &domain.Subscription{
+		UserID:               userID,
+		Plan:                 plan,
+		Status:               domain.SubscriptionActive,
+		StripeCustomerID:     session.Customer,
+		StripeSubscriptionID: session.Subscription,
+		UpdatedAt:            time.Now().UTC(),
+	})
+}
+
+// applySubscription mirrors a subscription's lifecycle state onto the user's plan
+func (s *BillingService) applySubscription(ctx context.Context, sub *stripeSubscription) error {
+	userID, err := uuid.Parse(sub.Metadata["user_id"])
+	if err != nil {
+		existing, err := s.subscriptionRepo.FindByCustomerID(ctx, sub.Customer)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			log.Printf("WARN: Ignoring subscription %s for unknown customer %s", sub.ID, sub.Customer)
+			return nil
+		}
+		userID = existing.UserID
+	}
+
+	record := &domain.Subscription{
+		UserID:               userID,
+		Plan:                 s.planForSubscription(sub),
+		Status:               sub.Status,
+		StripeCustomerID:     sub.Customer,
+		StripeSubscriptionID: sub.ID,
+		CancelAtPeriodEnd:    sub.CancelAtPeriodEnd,
+		UpdatedAt:            time.Now().UTC(),
+	}
+	if sub.CurrentPeriodEnd > 0 {
+		end := time.Unix(sub.CurrentPeriodEnd, 0).UTC()
+		record.CurrentPeriodEnd = &end
+	}
+	return s.subscriptionRepo.Upsert(ctx, record)
+}
+
+// planForSubscription resolves a plan from the subscription's price, falling back to its metadata
+func (s *BillingService) planForSubscription(sub *stripeSubscription) string {
+	for _, item := range sub.Items.Data {
+		for plan, priceID := range s.cfg.PriceIDs {
+			if priceID != "" && priceID == item.Price.ID {
+				return plan
+			}
+		}
+	}
+	if plan := sub.Metadata["plan"]; domain.FindPlan(plan) != nil {
+		return plan
+	}
+	return domain.PlanFree
+}
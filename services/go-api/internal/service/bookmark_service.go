@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrBookmarkNotFound     = apperr.New(ErrNotFound, "bookmark not found")
+	ErrBookmarkExists       = apperr.New(ErrConflict, "this link is already bookmarked")
+	ErrInvalidBookmarkURL   = apperr.New(ErrValidation, "url is required and must be an http or https link")
+	ErrInvalidBookmarkTitle = apperr.New(ErrValidation, "title must be at most 255 characters")
+	ErrBookmarkNotesTooLong = apperr.Newf(ErrValidation, "notes must be at most %d characters", domain.MaxCaptureLength)
+)
+
+// BookmarkService handles the links a user saved to come back to
+type BookmarkService struct {
+	repo *postgres.BookmarkRepository
+}
+
+// NewBookmarkService creates a new bookmark service
+func NewBookmarkService(repo *postgres.BookmarkRepository) *BookmarkService {
+	return &BookmarkService{repo: repo}
+}
+
+// Create saves a bookmark. Each link can be bookmarked once.
+func (s *BookmarkService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateBookmarkRequest) (*domain.Bookmark, error) {
+	link := strings.TrimSpace(req.URL)
+	if !validLink(link) {
+		return nil, ErrInvalidBookmarkURL
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = link
+		if utf8.RuneCountInString(title) > 255 {
+			title = string([]rune(title)[:255])
+		}
+	}
+	if utf8.RuneCountInString(title) > 255 {
+		return nil, ErrInvalidBookmarkTitle
+	}
+	if utf8.RuneCountInString(req.Notes) > domain.MaxCaptureLength {
+		return nil, ErrBookmarkNotesTooLong
+	}
+
+	bookmark := &domain.Bookmark{
+		ID:        uuid.New(),
+		UserID:    userID,
+		URL:       link,
+		Title:     title,
+		Notes:     req.Notes,
+		Tags:      trimValues(req.Tags),
+		CreatedAt: time.Now().UTC(),
+	}
+	created, err := s.repo.Create(ctx, bookmark)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrBookmarkExists
+	}
+	return bookmark, nil
+}
+
+// List returns a user's bookmarks, optionally with one tag, newest first
+func (s *BookmarkService) List(ctx context.Context, userID uuid.UUID, tag string, limit, offset int) ([]domain.Bookmark, int, error) {
+	return s.repo.ListByUser(ctx, userID, strings.TrimSpace(tag), limit, offset)
+}
+
+// Delete removes a bookmark
+func (s *BookmarkService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	deleted, err := s.repo.Delete(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+// validLink reports whether link is an absolute http or https URL
+func validLink(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
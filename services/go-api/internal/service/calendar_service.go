@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/ical"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCalendarFeedNotFound = apperr.New(ErrNotFound, "calendar feed not enabled")
+	ErrInvalidFeedToken     = apperr.New(ErrUnauthorized, "invalid or revoked calendar token")
+)
+
+const (
+	// calendarHistory is how far back journaling activity appears in the feed
+	calendarHistory = 180 * 24 * time.Hour
+
+	// calendarHorizon is how far ahead scheduled review sessions appear
+	calendarHorizon = 60 * 24 * time.Hour
+
+	// calendarRefresh asks calendar apps to re-fetch the feed this often (Google Calendar decides for itself)
+	calendarRefresh = 6 * time.Hour
+)
+
+// CalendarService publishes a private iCal feed of a user's journaling activity and scheduled reviews
+type CalendarService struct {
+	feedRepo     *postgres.CalendarFeedRepository
+	progressRepo *postgres.ProgressRepository
+	reviewRepo   *postgres.ReviewRepository
+	feedURL      string
+}
+
+// NewCalendarService creates a new calendar service. feedURL is the public URL of the .ics endpoint.
+func NewCalendarService(feedRepo *postgres.CalendarFeedRepository, progressRepo *postgres.ProgressRepository, reviewRepo *postgres.ReviewRepository, feedURL string) *CalendarService {
+	return &CalendarService{
+		feedRepo:     feedRepo,
+		progressRepo: progressRepo,
+		reviewRepo:   reviewRepo,
+		feedURL:      feedURL,
+	}
+}
+
+// Get returns a user's feed without its URL, which is only shown when created
+func (s *CalendarService) Get(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error) {
+	feed, err := s.feedRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+	return feed, nil
+}
+
+// Rotate creates the user's feed, or replaces its token so the old URL stops working
+func (s *CalendarService) Rotate(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error) {
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	feed := &domain.CalendarFeed{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.feedRepo.Upsert(ctx, feed); err != nil {
+		return nil, err
+	}
+	feed.URL = s.feedURL + "?token=" + url.QueryEscape(token)
+	return feed, nil
+}
+
+// Revoke disables the user's feed
+func (s *CalendarService) Revoke(ctx context.Context, userID uuid.UUID) error {
+	deleted, err := s.feedRepo.Delete(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+// Feed renders the iCal feed for a token
+func (s *CalendarService) Feed(ctx context.Context, token string) ([]byte, error) {
+	if token == "" {
+		return nil, ErrInvalidFeedToken
+	}
+	feed, err := s.feedRepo.FindByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, ErrInvalidFeedToken
+	}
+
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+
+	progress, err := s.progressRepo.FindByUserRange(ctx, feed.UserID, today.Add(-calendarHistory), today)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.reviewRepo.CountDueByDay(ctx, feed.UserID, today, today.Add(calendarHorizon))
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &ical.Calendar{
+		ProdID:          "-//DevJournal//Calendar Feed//EN",
+		Name:            "DevJournal",
+		Description:     "Journaling activity and scheduled reviews",
+		RefreshInterval: calendarRefresh,
+	}
+	for _, day := range progress {
+		if summary := activitySummary(&day); summary != "" {
+			cal.Events = append(cal.Events, allDayEvent(feed.UserID, "activity", day.Date, summary))
+		}
+	}
+	for _, day := range reviews {
+		summary := fmt.Sprintf("Review session: %d item%s due", day.Count, plural(day.Count))
+		cal.Events = append(cal.Events, allDayEvent(feed.UserID, "review", day.Date, summary))
+	}
+
+	var buf bytes.Buffer
+	if err := cal.Write(&buf, now); err != nil {
+		return nil, fmt.Errorf("failed to write calendar: %w", err)
+	}
+
+	if err := s.feedRepo.TouchAccessed(ctx, feed.UserID, now); err != nil {
+		log.Printf("WARN: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// activitySummary describes a day's journaling, e.g. "Journaled: 2 entries, 1 TIL (5-day streak)"
+func activitySummary(p *domain.LearningProgress) string {
+	var parts []string
+	if p.EntriesCount > 0 {
+		noun := "entries"
+		if p.EntriesCount == 1 {
+			noun = "entry"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", p.EntriesCount, noun))
+	}
+	if p.SnippetsCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d snippet%s", p.SnippetsCount, plural(p.SnippetsCount)))
+	}
+	if p.TILsCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d TIL%s", p.TILsCount, plural(p.TILsCount)))
+	}
+	if p.ProblemsCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d problem%s solved", p.ProblemsCount, plural(p.ProblemsCount)))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	summary := "Journaled: " + strings.Join(parts, ", ")
+	if p.StreakDays > 1 {
+		summary += fmt.Sprintf(" (%d-day streak)", p.StreakDays)
+	}
+	return summary
+}
+
+// allDayEvent builds a feed event with a UID that stays stable across fetches
+func allDayEvent(userID uuid.UUID, kind string, date time.Time, summary string) ical.Event {
+	return ical.Event{
+		UID:         fmt.Sprintf("%s-%s-%s@devjournal", kind, date.Format("20060102"), userID),
+		Start:       date,
+		End:         date.AddDate(0, 0, 1),
+		AllDay:      true,
+		Summary:     summary,
+		Transparent: true,
+	}
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
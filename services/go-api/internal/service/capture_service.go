@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/capture"
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCaptureNotFound      = apperr.New(ErrNotFound, "capture not found")
+	ErrCaptureClosed        = apperr.New(ErrConflict, "capture is no longer in the inbox")
+	ErrInvalidCaptureSource = apperr.New(ErrValidation, "source is required and must be at most 40 lowercase letters, digits, dots, dashes, or underscores")
+	ErrInvalidCaptureKind   = apperr.New(ErrValidation, "kind must be entry, snippet, or empty")
+	ErrCaptureEmpty         = apperr.New(ErrValidation, "content is required")
+	ErrCaptureTooLong       = apperr.New(ErrValidation, fmt.Sprintf("content must be at most %d characters", domain.MaxCaptureLength))
+	ErrInvalidCaptureTitle  = apperr.New(ErrValidation, "title must be at most 255 characters")
+	ErrInvalidCaptureURL    = apperr.New(ErrValidation, "url must be an http or https link")
+	ErrInvalidCaptureLang   = apperr.Newf(ErrValidation, "language must be at most %d characters", maxCaptureLanguageLength)
+	ErrInvalidCaptureTarget = apperr.New(ErrValidation, "to must be entry, snippet, bookmark, or empty")
+	ErrInvalidCaptureStatus = apperr.New(ErrValidation, "status must be inbox, archived, or converted")
+	ErrCaptureNoLink        = apperr.New(ErrValidation, "capture has no url or link in its content to bookmark")
+)
+
+// captureSource matches capture sources such as browser-extension or chatgpt
+var captureSource = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,39}$`)
+
+// maxCaptureLanguageLength caps a captured snippet's language; longer fence info strings are dropped
+const maxCaptureLanguageLength = 50
+
+// CaptureService keeps the quick-capture inbox: text sent from browser extensions, AI chats,
+// and other clients, which waits in the inbox until the user converts it into an entry, snippet,
+// or bookmark, archives it, or dismisses it
+type CaptureService struct {
+	repo            *postgres.CaptureRepository
+	journalService  *JournalService
+	snippetService  *SnippetService
+	bookmarkService *BookmarkService
+}
+
+// NewCaptureService creates a new capture service
+func NewCaptureService(repo *postgres.CaptureRepository, journalService *JournalService, snippetService *SnippetService, bookmarkService *BookmarkService) *CaptureService {
+	return &CaptureService{repo: repo, journalService: journalService, snippetService: snippetService, bookmarkService: bookmarkService}
+}
+
+// Create adds text to a user's inbox. Unless the request says otherwise, it becomes a snippet
+// when it is a single fenced code block and an entry otherwise, titled after its first line and
+// tagged with its source, #tags, code languages, and the technologies it mentions.
+func (s *CaptureService) Create(ctx context.Context, userID uuid.UUID, req *domain.CaptureRequest) (*domain.Capture, error) {
+	source := strings.ToLower(strings.TrimSpace(req.Source))
+	if !captureSource.MatchString(source) {
+		return nil, ErrInvalidCaptureSource
+	}
+	link := strings.TrimSpace(req.URL)
+	if link != "" && !validLink(link) {
+		return nil, ErrInvalidCaptureURL
+	}
+	if err := checkCaptureContent(req.Kind, req.Title, req.Content); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	c := &domain.Capture{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Source:    source,
+		Kind:      req.Kind,
+		Title:     strings.TrimSpace(req.Title),
+		Content:   req.Content,
+		URL:       link,
+		Tags:      capture.Tags(source, req.Content),
+		Status:    domain.CaptureInbox,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, tag := range trimValues(req.Tags) {
+		if !containsTag(c.Tags, tag) {
+			c.Tags = append(c.Tags, tag)
+		}
+	}
+	if c.Kind == "" {
+		c.Kind = capture.Kind(req.Content)
+	}
+	if c.Title == "" {
+		c.Title = capture.Title(req.Content)
+	}
+	if c.Title == "" {
+		c.Title = "Captured from " + source
+	}
+	if c.Kind == domain.CaptureSnippet {
+		c.Content, c.Language = capture.Snippet(req.Content)
+		if utf8.RuneCountInString(c.Language) > maxCaptureLanguageLength {
+			c.Language = ""
+		}
+	}
+
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// List returns a user's captures in the inbox, or those archived or converted, newest first
+func (s *CaptureService) List(ctx context.Context, userID uuid.UUID, status string, limit, offset int) ([]domain.Capture, int, error) {
+	switch status {
+	case "":
+		status = domain.CaptureInbox
+	case domain.CaptureInbox, domain.CaptureArchived, domain.CaptureConverted:
+	default:
+		return nil, 0, ErrInvalidCaptureStatus
+	}
+	return s.repo.List(ctx, userID, status, limit, offset)
+}
+
+// open returns a user's capture that is still in the inbox
+func (s *CaptureService) open(ctx context.Context, id, userID uuid.UUID) (*domain.Capture, error) {
+	c, err := s.repo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCaptureNotFound
+	}
+	if c.Status != domain.CaptureInbox {
+		return nil, ErrCaptureClosed
+	}
+	return c, nil
+}
+
+// Update saves a user's edits to a capture in the inbox
+func (s *CaptureService) Update(ctx context.Context, id, userID uuid.UUID, req *domain.UpdateCaptureRequest) (*domain.Capture, error) {
+	c, err := s.open(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Kind == "" {
+		req.Kind = c.Kind
+	}
+	if err := checkCaptureContent(req.Kind, req.Title, req.Content); err != nil {
+		return nil, err
+	}
+	if utf8.RuneCountInString(req.Language) > maxCaptureLanguageLength {
+		return nil, ErrInvalidCaptureLang
+	}
+
+	c.Kind = req.Kind
+	c.Title = strings.TrimSpace(req.Title)
+	c.Content = req.Content
+	c.Language = strings.TrimSpace(req.Language)
+	c.Tags = trimValues(req.Tags)
+	c.UpdatedAt = time.Now().UTC()
+	updated, err := s.repo.Update(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrCaptureClosed
+	}
+	return c, nil
+}
+
+// Convert turns a capture into a journal entry or snippet in the active workspace, or a
+// bookmark, returning the capture with the ID of what was made from it
+func (s *CaptureService) Convert(ctx context.Context, id, userID uuid.UUID, req *domain.ConvertCaptureRequest) (*domain.Capture, error) {
+	c, err := s.open(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	to := req.To
+	if to == "" {
+		to = c.Kind
+	}
+
+	switch to {
+	case domain.CaptureEntry:
+		content := c.Content
+		if c.Kind == domain.CaptureSnippet {
+			content = "```" + c.Language + "\n" + strings.TrimRight(content, "\n") + "\n```"
+		}
+		if c.URL != "" && !strings.Contains(content, c.URL) {
+			content += "\n\nSource: " + c.URL
+		}
+		entry, err := s.journalService.Create(ctx, userID, &domain.CreateJournalEntryRequest{
+			Title:   c.Title,
+			Content: content,
+			Tags:    c.Tags,
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.PublishedID = entry.ID.String()
+	case domain.CaptureSnippet:
+		code, language := c.Content, c.Language
+		if c.Kind != domain.CaptureSnippet {
+			code, language = capture.Snippet(c.Content)
+		}
+		snippet, err := s.snippetService.Create(ctx, userID.String(), &domain.CreateSnippetRequest{
+			Title:       c.Title,
+			Description: c.URL,
+			Code:        code,
+			Language:    language,
+			Tags:        c.Tags,
+			Metadata: map[string]interface{}{
+				"capture": map[string]interface{}{"id": c.ID.String(), "source": c.Source, "url": c.URL},
+			},
+			AcknowledgeSecrets: req.AcknowledgeSecrets,
+			AllowDuplicate:     req.AllowDuplicate,
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.PublishedID = snippet.ID
+	case domain.CaptureBookmark:
+		link := c.URL
+		if link == "" {
+			link = capture.Link(c.Content)
+		}
+		if link == "" {
+			return nil, ErrCaptureNoLink
+		}
+		notes := c.Content
+		if notes == link {
+			notes = ""
+		}
+		bookmark, err := s.bookmarkService.Create(ctx, userID, &domain.CreateBookmarkRequest{
+			URL:   link,
+			Title: c.Title,
+			Notes: notes,
+			Tags:  c.Tags,
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.PublishedID = bookmark.ID.String()
+	default:
+		return nil, ErrInvalidCaptureTarget
+	}
+
+	if _, err := s.repo.SetStatus(ctx, c.ID, domain.CaptureConverted, to, c.PublishedID); err != nil {
+		return nil, err
+	}
+	c.Kind = to
+	c.Status = domain.CaptureConverted
+	return c, nil
+}
+
+// Archive takes a capture out of the inbox, keeping it for reference without converting it
+func (s *CaptureService) Archive(ctx context.Context, id, userID uuid.UUID) (*domain.Capture, error) {
+	c, err := s.open(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.close(ctx, c, domain.CaptureArchived); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Dismiss removes a capture from the inbox without converting or keeping it
+func (s *CaptureService) Dismiss(ctx context.Context, id, userID uuid.UUID) error {
+	c, err := s.open(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	return s.close(ctx, c, domain.CaptureDismissed)
+}
+
+// close moves a capture from the inbox to an archived or dismissed status
+func (s *CaptureService) close(ctx context.Context, c *domain.Capture, status string) error {
+	closed, err := s.repo.SetStatus(ctx, c.ID, status, "", "")
+	if err != nil {
+		return err
+	}
+	if !closed {
+		return ErrCaptureClosed
+	}
+	c.Status = status
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// checkCaptureContent validates the kind, title, and content of a capture
+func checkCaptureContent(kind, title, content string) error {
+	if kind != "" && kind != domain.CaptureEntry && kind != domain.CaptureSnippet {
+		return ErrInvalidCaptureKind
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(title)) > 255 {
+		return ErrInvalidCaptureTitle
+	}
+	if strings.TrimSpace(content) == "" {
+		return ErrCaptureEmpty
+	}
+	if utf8.RuneCountInString(content) > domain.MaxCaptureLength {
+		return ErrCaptureTooLong
+	}
+	return nil
+}
+
+// containsTag reports whether tags holds tag, ignoring case
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
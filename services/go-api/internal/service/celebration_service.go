@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/internal/tenant"
+)
+
+// celebrationQueueSize bounds streak milestones waiting to be celebrated
+const celebrationQueueSize = 256
+
+// CelebrationService posts a system message in a user's study groups when they reach a streak
+// milestone, for users who turned on celebrateMilestones in their settings
+type CelebrationService struct {
+	groupRepo       *postgres.StudyGroupRepository
+	userRepo        *postgres.UserRepository
+	settingsService *SettingsService
+	publisher       ChatPublisher
+	milestones      chan domain.StreakMilestone
+}
+
+// NewCelebrationService creates a new celebration service. Messages go to group chat through
+// publisher.
+func NewCelebrationService(groupRepo *postgres.StudyGroupRepository, userRepo *postgres.UserRepository, settingsService *SettingsService, publisher ChatPublisher) *CelebrationService {
+	return &CelebrationService{
+		groupRepo:       groupRepo,
+		userRepo:        userRepo,
+		settingsService: settingsService,
+		publisher:       publisher,
+		milestones:      make(chan domain.StreakMilestone, celebrationQueueSize),
+	}
+}
+
+// StreakMilestone queues a milestone to be celebrated. It is a ProgressService.OnMilestone
+// listener and never blocks the request.
+func (s *CelebrationService) StreakMilestone(_ context.Context, milestone domain.StreakMilestone) {
+	select {
+	case s.milestones <- milestone:
+	default:
+		log.Printf("WARN: Celebration queue full, dropping %d-day streak of %s", milestone.Days, milestone.UserID)
+	}
+}
+
+// Run celebrates queued milestones until ctx is cancelled
+func (s *CelebrationService) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case milestone := <-s.milestones:
+			if err := s.celebrate(ctx, milestone); err != nil {
+				log.Printf("WARN: Failed to celebrate %d-day streak of %s: %v", milestone.Days, milestone.UserID, err)
+			}
+		}
+	}
+}
+
+// celebrate posts a milestone to every group of the workspace the user was active in that isn't
+// archived, if the user asked for it
+func (s *CelebrationService) celebrate(ctx context.Context, milestone domain.StreakMilestone) error {
+	settings, err := s.settingsService.Get(ctx, milestone.UserID)
+	if err != nil {
+		return err
+	}
+	if !settings.CelebrateMilestones {
+		return nil
+	}
+	user, err := s.userRepo.FindByID(ctx, milestone.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	groups, err := s.groupRepo.FindByUserID(tenant.WithWorkspace(ctx, milestone.WorkspaceID), milestone.UserID)
+	if err != nil {
+		return err
+	}
+	content := fmt.Sprintf("%s reached a %d-day learning streak!", user.DisplayName, milestone.Days)
+	for _, group := range groups {
+		if group.ArchivedAt != nil {
+			continue
+		}
+		msg := domain.NewChatMessage(group.ID.String(), milestone.UserID.String(), user.DisplayName, content, "system")
+		if err := s.publisher.Publish(ctx, msg); err != nil {
+			log.Printf("WARN: Failed to celebrate streak in group %s: %v", group.ID, err)
+		}
+	}
+	return nil
+}
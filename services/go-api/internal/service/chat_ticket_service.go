@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// chatTicketTTL is how long a chat ticket can be redeemed for: long enough to open the
+	// WebSocket right after requesting it, short enough that one leaked from a log is useless
+	chatTicketTTL = 30 * time.Second
+
+	maxChatRoomLength = 255
+)
+
+var (
+	ErrChatRoomRequired  = apperr.New(ErrValidation, "room is required")
+	ErrChatRoomTooLong   = apperr.Newf(ErrValidation, "room must be at most %d characters", maxChatRoomLength)
+	ErrChatRoomForbidden = apperr.New(ErrForbidden, "not a member of this direct message room")
+	ErrChatTicketInvalid = apperr.New(ErrUnauthorized, "invalid or expired ticket")
+)
+
+// ChatTicketService issues the one-time tickets chat WebSockets are opened with
+type ChatTicketService struct {
+	ticketRepo     *postgres.ChatTicketRepository
+	channelService *GroupChannelService
+	permissions    *GroupPermissionService
+}
+
+// NewChatTicketService creates a new chat ticket service
+func NewChatTicketService(ticketRepo *postgres.ChatTicketRepository, channelService *GroupChannelService, permissions *GroupPermissionService) *ChatTicketService {
+	return &ChatTicketService{ticketRepo: ticketRepo, channelService: channelService, permissions: permissions}
+}
+
+// Issue creates a ticket for a user to join a room. Direct message rooms are only open to
+// their two users, and group channels to the group's members; the ticket records whether the
+// channel lets them post, and whether the group lets them start voice sessions.
+func (s *ChatTicketService) Issue(ctx context.Context, userID uuid.UUID, req *domain.CreateChatTicketRequest) (*domain.ChatTicketResponse, error) {
+	room := strings.TrimSpace(req.Room)
+	if room == "" {
+		return nil, ErrChatRoomRequired
+	}
+	if len(room) > maxChatRoomLength {
+		return nil, ErrChatRoomTooLong
+	}
+	if a, b, ok := domain.DirectRoomMembers(room); ok && userID != a && userID != b {
+		return nil, ErrChatRoomForbidden
+	}
+	readOnly, err := s.channelService.Access(ctx, room, userID)
+	if err != nil {
+		return nil, err
+	}
+	joinVoiceOnly := false
+	if groupID, _, ok := domain.GroupRoom(room); ok {
+		canStart, err := s.permissions.Can(ctx, groupID, userID, domain.GroupActionStartSessions)
+		if err != nil {
+			return nil, err
+		}
+		joinVoiceOnly = !canStart
+	}
+
+	ticket, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().UTC().Add(chatTicketTTL)
+	if err := s.ticketRepo.Create(ctx, &domain.ChatTicket{
+		TicketHash:    hashToken(ticket),
+		UserID:        userID,
+		Room:          room,
+		ReadOnly:      readOnly,
+		JoinVoiceOnly: joinVoiceOnly,
+		ExpiresAt:     expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &domain.ChatTicketResponse{Ticket: ticket, Room: room, ReadOnly: readOnly, JoinVoiceOnly: joinVoiceOnly, ExpiresAt: expiresAt}, nil
+}
+
+// Redeem uses up a ticket to open a WebSocket in the given room, returning who it was issued to
+func (s *ChatTicketService) Redeem(ctx context.Context, ticket, room string) (*domain.ChatTicket, error) {
+	if ticket == "" {
+		return nil, ErrChatTicketInvalid
+	}
+	redeemed, err := s.ticketRepo.Redeem(ctx, hashToken(ticket))
+	if err != nil {
+		return nil, err
+	}
+	if redeemed == nil || redeemed.Room != room || !time.Now().Before(redeemed.ExpiresAt) {
+		return nil, ErrChatTicketInvalid
+	}
+	return redeemed, nil
+}
+
+// ExpiredCleaner returns a job that deletes tickets that expired without being redeemed
+func (s *ChatTicketService) ExpiredCleaner() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		deleted, err := s.ticketRepo.DeleteExpired(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			log.Printf("Deleted %d expired chat tickets", deleted)
+		}
+		return nil
+	}
+}
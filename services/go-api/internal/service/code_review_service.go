@@ -0,0 +1,445 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/i18n"
+	"devjournal/internal/mail"
+	"devjournal/internal/push"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// codeReviewQueueSize bounds review notifications waiting to be sent
+	codeReviewQueueSize = 256
+	// codeReviewExcerptLength caps how much of a comment is shown in its notification
+	codeReviewExcerptLength = 140
+)
+
+var (
+	ErrCodeReviewNotFound        = apperr.New(ErrNotFound, "code review not found")
+	ErrCodeReviewCommentNotFound = apperr.New(ErrNotFound, "comment not found")
+	ErrNotCodeReviewAuthor       = apperr.New(ErrForbidden, "only the review's author can resolve it")
+	ErrCodeReviewDelete          = apperr.New(ErrForbidden, "only the review's author or a group admin can delete it")
+	ErrCodeReviewStatus          = apperr.New(ErrValidation, "status must be open or resolved")
+	ErrCodeReviewNote            = apperr.New(ErrValidation, "note must be at most 2000 characters")
+	ErrCodeReviewComment         = apperr.New(ErrValidation, "comment must be between 1 and 5000 characters")
+	ErrCodeReviewFile            = apperr.New(ErrValidation, "file is not part of the review")
+	ErrCodeReviewLine            = apperr.New(ErrValidation, "line is outside the file")
+)
+
+// CodeReviewService handles snippets shared into study groups for review, reviewers' line
+// comments, and notifying the people involved by push, or by email when they have no mobile
+// device registered
+type CodeReviewService struct {
+	reviewRepo  *postgres.CodeReviewRepository
+	groupRepo   *postgres.StudyGroupRepository
+	snippetRepo *mongodb.SnippetRepository
+	userRepo    *postgres.UserRepository
+	permissions *GroupPermissionService
+	pushService *PushService
+	mailer      mail.Sender // nil disables email
+	events      chan codeReviewEvent
+}
+
+// codeReviewEvent is a notification about a review waiting to be sent
+type codeReviewEvent struct {
+	recipients []uuid.UUID
+	title      i18n.Message
+	body       i18n.Message
+	data       map[string]string
+}
+
+// NewCodeReviewService creates a new code review service. A nil mailer disables review emails.
+func NewCodeReviewService(reviewRepo *postgres.CodeReviewRepository, groupRepo *postgres.StudyGroupRepository, snippetRepo *mongodb.SnippetRepository, userRepo *postgres.UserRepository, permissions *GroupPermissionService, pushService *PushService, mailer mail.Sender) *CodeReviewService {
+	return &CodeReviewService{
+		reviewRepo:  reviewRepo,
+		groupRepo:   groupRepo,
+		snippetRepo: snippetRepo,
+		userRepo:    userRepo,
+		permissions: permissions,
+		pushService: pushService,
+		mailer:      mailer,
+		events:      make(chan codeReviewEvent, codeReviewQueueSize),
+	}
+}
+
+// List returns a group's code reviews, newest first, optionally only those in status
+func (s *CodeReviewService) List(ctx context.Context, userID, groupID uuid.UUID, status string, limit, offset int) ([]domain.CodeReview, int, error) {
+	if status != "" && status != domain.CodeReviewOpen && status != domain.CodeReviewResolved {
+		return nil, 0, ErrCodeReviewStatus
+	}
+	if _, _, err := s.checkMember(ctx, groupID, userID); err != nil {
+		return nil, 0, err
+	}
+	return s.reviewRepo.ListByGroup(ctx, groupID, status, limit, offset)
+}
+
+// Get returns a code review with its files and comments
+func (s *CodeReviewService) Get(ctx context.Context, userID, groupID, reviewID uuid.UUID) (*domain.CodeReview, error) {
+	if _, _, err := s.checkMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	review, err := s.find(ctx, groupID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	review.Comments, err = s.reviewRepo.Comments(ctx, review.ID)
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// Create shares one of the user's snippets into a group for review and notifies the other
+// members (members the group lets post snippets only)
+func (s *CodeReviewService) Create(ctx context.Context, userID, groupID uuid.UUID, req *domain.CreateCodeReviewRequest) (*domain.CodeReview, error) {
+	group, _, err := s.checkMember(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.permissions.Check(ctx, groupID, userID, domain.GroupActionPostSnippets); err != nil {
+		return nil, err
+	}
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > 2000 {
+		return nil, ErrCodeReviewNote
+	}
+	snippet, err := s.snippetRepo.FindByID(ctx, req.SnippetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snippet: %w", err)
+	}
+	if err := checkSnippetOwner(snippet, userID.String()); err != nil {
+		return nil, err
+	}
+	author, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, ErrUserNotFound
+	}
+
+	now := time.Now().UTC()
+	review := &domain.CodeReview{
+		ID:         uuid.New(),
+		GroupID:    groupID,
+		UserID:     userID,
+		AuthorName: author.DisplayName,
+		SnippetID:  snippet.ID,
+		Title:      snippet.Title,
+		Note:       note,
+		Files:      snippet.Files,
+		Status:     domain.CodeReviewOpen,
+		Comments:   []domain.CodeReviewComment{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.reviewRepo.Create(ctx, review); err != nil {
+		return nil, err
+	}
+
+	members, err := s.groupRepo.GetMembers(ctx, groupID)
+	if err != nil {
+		log.Printf("WARN: Failed to load members of group %s to notify of review %s: %v", groupID, review.ID, err)
+		return review, nil
+	}
+	recipients := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		if m.UserID != userID {
+			recipients = append(recipients, m.UserID)
+		}
+	}
+	body := review.Title
+	if review.Note != "" {
+		body += ": " + review.Note
+	}
+	s.queue(review, "created", recipients,
+		i18n.M("%s requested a review in %s", author.DisplayName, group.Name),
+		i18n.Raw(codeReviewExcerpt(body)))
+	return review, nil
+}
+
+// Delete removes a code review. Its author and group owners and admins may delete it.
+func (s *CodeReviewService) Delete(ctx context.Context, userID, groupID, reviewID uuid.UUID) error {
+	_, role, err := s.checkMember(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	review, err := s.find(ctx, groupID, reviewID)
+	if err != nil {
+		return err
+	}
+	if review.UserID != userID && !isGroupManager(role) {
+		return ErrCodeReviewDelete
+	}
+	return s.reviewRepo.Delete(ctx, review.ID)
+}
+
+// Comment adds a reviewer's comment on a line of one of the review's files and notifies the
+// review's author and the other commenters
+func (s *CodeReviewService) Comment(ctx context.Context, userID, groupID, reviewID uuid.UUID, req *domain.CodeReviewCommentRequest) (*domain.CodeReviewComment, error) {
+	if _, _, err := s.checkMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	review, err := s.find(ctx, groupID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" || utf8.RuneCountInString(body) > 5000 {
+		return nil, ErrCodeReviewComment
+	}
+	if err := checkCodeReviewLine(review.Files, req.File, req.Line); err != nil {
+		return nil, err
+	}
+	author, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, ErrUserNotFound
+	}
+
+	comment := &domain.CodeReviewComment{
+		ID:         uuid.New(),
+		ReviewID:   review.ID,
+		UserID:     userID,
+		AuthorName: author.DisplayName,
+		File:       req.File,
+		Line:       req.Line,
+		Body:       body,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := s.reviewRepo.AddComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	s.notifyParticipants(ctx, review, userID, "commented",
+		i18n.M("%s commented on %s", author.DisplayName, review.Title),
+		i18n.Raw(codeReviewExcerpt(fmt.Sprintf("%s:%d: %s", comment.File, comment.Line, comment.Body))))
+	return comment, nil
+}
+
+// ResolveComment marks a comment resolved, or open again, and notifies the comment's author.
+// Only the review's author can resolve comments.
+func (s *CodeReviewService) ResolveComment(ctx context.Context, userID, groupID, reviewID, commentID uuid.UUID, resolved bool) (*domain.CodeReviewComment, error) {
+	review, err := s.findOwn(ctx, userID, groupID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.reviewRepo.FindComment(ctx, commentID, review.ID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil {
+		return nil, ErrCodeReviewCommentNotFound
+	}
+	if resolved == (comment.ResolvedAt != nil) {
+		return comment, nil
+	}
+
+	comment.ResolvedAt = nil
+	if resolved {
+		now := time.Now().UTC()
+		comment.ResolvedAt = &now
+	}
+	if err := s.reviewRepo.ResolveComment(ctx, comment.ID, comment.ResolvedAt); err != nil {
+		return nil, err
+	}
+
+	if resolved && comment.UserID != userID {
+		s.queue(review, "comment_resolved", []uuid.UUID{comment.UserID},
+			i18n.M("%s resolved your comment on %s", review.AuthorName, review.Title),
+			i18n.Raw(codeReviewExcerpt(fmt.Sprintf("%s:%d: %s", comment.File, comment.Line, comment.Body))))
+	}
+	return comment, nil
+}
+
+// Resolve marks a review resolved, or open again, and notifies the reviewers. Only the
+// review's author can resolve it.
+func (s *CodeReviewService) Resolve(ctx context.Context, userID, groupID, reviewID uuid.UUID, resolved bool) (*domain.CodeReview, error) {
+	review, err := s.findOwn(ctx, userID, groupID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if resolved == (review.Status == domain.CodeReviewResolved) {
+		return review, nil
+	}
+
+	review.Status = domain.CodeReviewOpen
+	review.ResolvedAt = nil
+	action, title := "reopened", "%s reopened the review of %s"
+	if resolved {
+		now := time.Now().UTC()
+		review.Status = domain.CodeReviewResolved
+		review.ResolvedAt = &now
+		action, title = "resolved", "%s marked as resolved the review of %s"
+	}
+	if err := s.reviewRepo.SetStatus(ctx, review.ID, review.Status, review.ResolvedAt); err != nil {
+		return nil, err
+	}
+	review.UpdatedAt = time.Now().UTC()
+
+	s.notifyParticipants(ctx, review, userID, action,
+		i18n.M(title, review.AuthorName, review.Title),
+		i18n.M("%d of %d comments resolved", review.CommentCount-review.OpenComments, review.CommentCount))
+	return review, nil
+}
+
+// Run sends queued review notifications until ctx is cancelled
+func (s *CodeReviewService) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-s.events:
+			for _, userID := range event.recipients {
+				s.notify(ctx, userID, &event)
+			}
+		}
+	}
+}
+
+// notifyParticipants queues a notification to the review's author and commenters other than the actor
+func (s *CodeReviewService) notifyParticipants(ctx context.Context, review *domain.CodeReview, actorID uuid.UUID, event string, title, body i18n.Message) {
+	participants, err := s.reviewRepo.Participants(ctx, review.ID)
+	if err != nil {
+		log.Printf("WARN: Failed to load participants of review %s: %v", review.ID, err)
+		return
+	}
+	recipients := make([]uuid.UUID, 0, len(participants))
+	for _, id := range participants {
+		if id != actorID {
+			recipients = append(recipients, id)
+		}
+	}
+	s.queue(review, event, recipients, title, body)
+}
+
+// queue hands a notification to Run without blocking the request
+func (s *CodeReviewService) queue(review *domain.CodeReview, event string, recipients []uuid.UUID, title, body i18n.Message) {
+	if len(recipients) == 0 {
+		return
+	}
+	e := codeReviewEvent{
+		recipients: recipients,
+		title:      title,
+		body:       body,
+		data: map[string]string{
+			"type":     domain.PushCodeReview,
+			"event":    event,
+			"groupId":  review.GroupID.String(),
+			"reviewId": review.ID.String(),
+		},
+	}
+	select {
+	case s.events <- e:
+	default:
+		log.Printf("WARN: Code review queue full, not notifying %d users of review %s", len(recipients), review.ID)
+	}
+}
+
+// notify sends a push notification in the user's locale, or an email if the user has no device to receive it
+func (s *CodeReviewService) notify(ctx context.Context, userID uuid.UUID, e *codeReviewEvent) {
+	locale := s.pushService.RecipientLocale(ctx, userID)
+	title, body := e.title.In(locale), e.body.In(locale)
+	sent, err := s.pushService.Notify(ctx, userID, &push.Notification{
+		Title: title,
+		Body:  body,
+		Data:  e.data,
+	})
+	if err != nil {
+		log.Printf("WARN: Failed to push review %s to user %s: %v", e.data["reviewId"], userID, err)
+	}
+	if sent > 0 || s.mailer == nil {
+		return
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil || user == nil {
+		log.Printf("WARN: Failed to load user %s to email review %s: %v", userID, e.data["reviewId"], err)
+		return
+	}
+	email := i18n.T(locale, "%s:\n\n%s\n\nOpen the review in DevJournal.\n", title, body)
+	if err := s.mailer.Send(ctx, user.Email, title, email); err != nil {
+		log.Printf("WARN: Failed to email review %s to user %s: %v", e.data["reviewId"], userID, err)
+	}
+}
+
+// find returns a code review of the group, or ErrCodeReviewNotFound
+func (s *CodeReviewService) find(ctx context.Context, groupID, reviewID uuid.UUID) (*domain.CodeReview, error) {
+	review, err := s.reviewRepo.FindByID(ctx, reviewID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrCodeReviewNotFound
+	}
+	return review, nil
+}
+
+// findOwn returns a code review of the group requested by the user
+func (s *CodeReviewService) findOwn(ctx context.Context, userID, groupID, reviewID uuid.UUID) (*domain.CodeReview, error) {
+	if _, _, err := s.checkMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	review, err := s.find(ctx, groupID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != userID {
+		return nil, ErrNotCodeReviewAuthor
+	}
+	return review, nil
+}
+
+// checkMember returns the group and the user's role in it, or an error unless they belong to it
+func (s *CodeReviewService) checkMember(ctx context.Context, groupID, userID uuid.UUID) (*domain.StudyGroup, string, error) {
+	group, err := s.groupRepo.FindByID(ctx, groupID)
+	if err != nil {
+		return nil, "", err
+	}
+	if group == nil {
+		return nil, "", ErrStudyGroupNotFound
+	}
+	role, err := groupRole(ctx, s.groupRepo, groupID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	return group, role, nil
+}
+
+// checkCodeReviewLine checks that line is within the named file of a review
+func checkCodeReviewLine(files []domain.SnippetFile, name string, line int) error {
+	for _, f := range files {
+		if f.Name != name {
+			continue
+		}
+		lines := strings.Count(strings.TrimRight(f.Code, "\n"), "\n") + 1
+		if line < 1 || line > lines {
+			return ErrCodeReviewLine
+		}
+		return nil
+	}
+	return ErrCodeReviewFile
+}
+
+// codeReviewExcerpt collapses whitespace in s and shortens it for a notification
+func codeReviewExcerpt(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= codeReviewExcerptLength {
+		return s
+	}
+	return string([]rune(s)[:codeReviewExcerptLength]) + "…"
+}
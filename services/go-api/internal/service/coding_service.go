@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"devjournal/internal/codingtime"
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCodingSourceNotFound  = apperr.New(ErrNotFound, "no coding time source connected")
+	ErrInvalidHeartbeatToken = apperr.New(ErrUnauthorized, "invalid or revoked heartbeat token")
+	ErrMissingWakaTimeKey    = apperr.New(ErrValidation, "apiKey is required")
+	ErrHeartbeatConflict     = apperr.New(ErrConflict, "heartbeats were sent concurrently; send them again")
+)
+
+const (
+	// codingSyncInterval is how often each WakaTime account is synced
+	codingSyncInterval = time.Hour
+	codingSyncPoll     = time.Minute
+
+	// codingSyncDays is how many days back each sync reads, so late heartbeats and edits in
+	// WakaTime are picked up
+	codingSyncDays = 7
+
+	// heartbeatClockSkew is how far in the future a heartbeat may be dated; later ones are ignored
+	// so a bad clock cannot stop time from being counted
+	heartbeatClockSkew = 5 * time.Minute
+
+	// heartbeatAttempts bounds retries when concurrent requests move the latest heartbeat
+	heartbeatAttempts = 3
+
+	// maxLanguageDays caps the window of the per-language breakdown
+	maxLanguageDays = 365
+)
+
+// CodingService feeds coding time into learning progress, syncing it from WakaTime or totalling
+// heartbeats editor plugins send directly
+type CodingService struct {
+	codingRepo      *postgres.CodingRepository
+	progressService *ProgressService
+	wakatime        *codingtime.WakaTime
+	apiURL          string
+
+	wake chan struct{}
+}
+
+// NewCodingService creates a new coding service. wakatimeURL is the WakaTime API base URL, and
+// apiURL the public URL editor plugins send heartbeats under.
+func NewCodingService(codingRepo *postgres.CodingRepository, progressService *ProgressService, wakatimeURL, apiURL string) *CodingService {
+	return &CodingService{
+		codingRepo:      codingRepo,
+		progressService: progressService,
+		wakatime:        codingtime.NewWakaTime(wakatimeURL),
+		apiURL:          strings.TrimSuffix(apiURL, "/"),
+		wake:            make(chan struct{}, 1),
+	}
+}
+
+// Get returns a user's coding time source
+func (s *CodingService) Get(ctx context.Context, userID uuid.UUID) (*domain.CodingSource, error) {
+	src, err := s.codingRepo.FindSource(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if src == nil {
+		return nil, ErrCodingSourceNotFound
+	}
+	if src.Provider == domain.CodingSourceHeartbeat {
+		src.APIURL = s.apiURL
+	}
+	return src, nil
+}
+
+// ConnectWakaTime syncs a user's coding time from WakaTime, replacing any heartbeat token. The
+// key is checked with WakaTime first.
+func (s *CodingService) ConnectWakaTime(ctx context.Context, userID uuid.UUID, apiKey string) (*domain.CodingSource, error) {
+	apiKey = strings.TrimSpace(apiKey)
+	if apiKey == "" {
+		return nil, ErrMissingWakaTimeKey
+	}
+	if err := s.wakatime.Verify(ctx, apiKey); err != nil {
+		if errors.Is(err, codingtime.ErrInvalidAPIKey) {
+			return nil, err
+		}
+		return nil, apperr.New(ErrUnavailable, "could not reach WakaTime; try again later")
+	}
+
+	src := &domain.CodingSource{UserID: userID, Provider: domain.CodingSourceWakaTime, APIKey: apiKey, UpdatedAt: time.Now().UTC()}
+	if err := s.codingRepo.SaveSource(ctx, src); err != nil {
+		return nil, err
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return s.Get(ctx, userID)
+}
+
+// CreateHeartbeatToken switches a user to sending heartbeats directly, or replaces their token
+// so the old one stops working. The token is returned this once.
+func (s *CodingService) CreateHeartbeatToken(ctx context.Context, userID uuid.UUID) (*domain.CodingSource, error) {
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	src := &domain.CodingSource{UserID: userID, Provider: domain.CodingSourceHeartbeat, TokenHash: hashToken(token), UpdatedAt: time.Now().UTC()}
+	if err := s.codingRepo.SaveSource(ctx, src); err != nil {
+		return nil, err
+	}
+	created, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	created.Token = token
+	return created, nil
+}
+
+// Disconnect stops recording a user's coding time. Time already recorded is kept.
+func (s *CodingService) Disconnect(ctx context.Context, userID uuid.UUID) error {
+	deleted, err := s.codingRepo.DeleteSource(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCodingSourceNotFound
+	}
+	return nil
+}
+
+// RecordHeartbeats counts the time between heartbeats sent with a user's heartbeat token
+func (s *CodingService) RecordHeartbeats(ctx context.Context, token string, beats []codingtime.Heartbeat) error {
+	if token == "" {
+		return ErrInvalidHeartbeatToken
+	}
+	src, err := s.codingRepo.FindSourceByToken(ctx, hashToken(token))
+	if err != nil {
+		return err
+	}
+	if src == nil {
+		return ErrInvalidHeartbeatToken
+	}
+
+	latest := float64(time.Now().Add(heartbeatClockSkew).Unix())
+	valid := beats[:0]
+	for _, b := range beats {
+		if b.Time <= latest {
+			valid = append(valid, b)
+		}
+	}
+
+	for attempt := 0; attempt < heartbeatAttempts; attempt++ {
+		days, last, lastLanguage := codingtime.Tally(src.LastHeartbeatAt, src.LastLanguage, valid)
+		changed, ok, err := s.codingRepo.AddHeartbeatTime(ctx, src.UserID, src.LastHeartbeatAt, last, lastLanguage, days)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return s.recordLearningTime(ctx, src.UserID, changed)
+		}
+		// Another request counted heartbeats first; total ours again from where it left off
+		if src, err = s.codingRepo.FindSourceByToken(ctx, hashToken(token)); err != nil {
+			return err
+		}
+		if src == nil {
+			return ErrInvalidHeartbeatToken
+		}
+	}
+	return ErrHeartbeatConflict
+}
+
+// Languages returns a user's coding time by language over the last days days, or all time when
+// days is zero
+func (s *CodingService) Languages(ctx context.Context, userID uuid.UUID, days int) ([]domain.LanguageTime, error) {
+	if days < 0 || days > maxLanguageDays {
+		return nil, apperr.Newf(ErrValidation, "days must be between 1 and %d", maxLanguageDays)
+	}
+	var start time.Time
+	if days > 0 {
+		start = time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+	}
+	return s.codingRepo.LanguageTotals(ctx, userID, start)
+}
+
+// Run syncs WakaTime accounts as they fall due until ctx is cancelled
+func (s *CodingService) Run(ctx context.Context) {
+	ticker := time.NewTicker(codingSyncPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+			s.syncDue(ctx)
+		case <-ticker.C:
+			s.syncDue(ctx)
+		}
+	}
+}
+
+// syncDue syncs every WakaTime account whose sync is due
+func (s *CodingService) syncDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		src, err := s.codingRepo.ClaimDueSync(ctx, time.Now().UTC(), codingSyncInterval)
+		if err != nil {
+			log.Printf("ERROR: Failed to claim coding sync: %v", err)
+			return
+		}
+		if src == nil {
+			return
+		}
+
+		syncErr := ""
+		if err := s.sync(ctx, src); err != nil {
+			log.Printf("WARN: WakaTime sync for user %s failed: %v", src.UserID, err)
+			syncErr = err.Error()
+		}
+		if err := s.codingRepo.FinishSync(ctx, src.UserID, time.Now().UTC(), syncErr); err != nil {
+			log.Printf("ERROR: %v", err)
+		}
+	}
+}
+
+// sync replaces the last codingSyncDays days of a user's coding time with WakaTime's
+func (s *CodingService) sync(ctx context.Context, src *domain.CodingSource) error {
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	days, err := s.wakatime.Summaries(ctx, src.APIKey, end.AddDate(0, 0, 1-codingSyncDays), end)
+	if err != nil {
+		return err
+	}
+	changed, err := s.codingRepo.ReplaceDays(ctx, src.UserID, days)
+	if err != nil {
+		return err
+	}
+	return s.recordLearningTime(ctx, src.UserID, changed)
+}
+
+// recordLearningTime moves changes in daily coding time into learning progress
+func (s *CodingService) recordLearningTime(ctx context.Context, userID uuid.UUID, changed map[time.Time]int) error {
+	for day, minutes := range changed {
+		if err := s.progressService.RecordCodingTime(ctx, userID, day, minutes); err != nil {
+			return fmt.Errorf("failed to update learning time: %w", err)
+		}
+	}
+	return nil
+}
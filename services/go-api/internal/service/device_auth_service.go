@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	deviceCodeTTL          = 10 * time.Minute
+	devicePollInterval     = 5 // seconds
+	deviceSlowDownIncrease = 5 // seconds added to the interval each time a device polls too fast
+	maxClientNameLength    = 100
+
+	// userCodeAlphabet has no vowels, so codes never spell words, and no easily confused characters
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+)
+
+var (
+	ErrDeviceCodeNotFound = apperr.New(ErrNotFound, "device code not found")
+	ErrUserCodeNotFound   = apperr.New(ErrNotFound, "code not found or expired")
+)
+
+// DeviceFlowError is returned when a device polls before its sign-in produced a token.
+// Reason is one of the domain.DeviceErr* values.
+type DeviceFlowError struct {
+	Reason   string
+	Interval int // seconds the device must now wait between polls
+}
+
+func (e *DeviceFlowError) Error() string {
+	switch e.Reason {
+	case domain.DeviceErrAuthorizationPending:
+		return "waiting for the user to approve the sign-in"
+	case domain.DeviceErrSlowDown:
+		return fmt.Sprintf("polling too fast; wait %d seconds between requests", e.Interval)
+	case domain.DeviceErrAccessDenied:
+		return "the user denied the sign-in"
+	default:
+		return "the device code has expired; start again"
+	}
+}
+
+func (e *DeviceFlowError) Unwrap() error {
+	if e.Reason == domain.DeviceErrAccessDenied {
+		return ErrForbidden
+	}
+	return ErrPrecondition
+}
+
+// Details exposes the RFC 8628 error name and poll interval to the device
+func (e *DeviceFlowError) Details() map[string]interface{} {
+	return map[string]interface{}{"error": e.Reason, "interval": e.Interval}
+}
+
+// DeviceAuthService signs in devices that cannot show a login form, such as editor plugins,
+// using the OAuth device authorization grant (RFC 8628)
+type DeviceAuthService struct {
+	deviceRepo      *postgres.DeviceAuthRepository
+	authService     *AuthService
+	verificationURL string
+}
+
+// NewDeviceAuthService creates a new device auth service. verificationURL is the web app page
+// where users enter the code shown by the device.
+func NewDeviceAuthService(deviceRepo *postgres.DeviceAuthRepository, authService *AuthService, verificationURL string) *DeviceAuthService {
+	return &DeviceAuthService{
+		deviceRepo:      deviceRepo,
+		authService:     authService,
+		verificationURL: verificationURL,
+	}
+}
+
+// Start begins a device sign-in and returns the codes the device shows and polls with
+func (s *DeviceAuthService) Start(ctx context.Context, clientName string) (*domain.DeviceCodeResponse, error) {
+	clientName = strings.TrimSpace(clientName)
+	if clientName == "" {
+		clientName = "Editor plugin"
+	}
+	for utf8.RuneCountInString(clientName) > maxClientNameLength {
+		_, size := utf8.DecodeLastRuneInString(clientName)
+		clientName = clientName[:len(clientName)-size]
+	}
+
+	deviceCode, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	userCode, err := randomUserCode()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	auth := &domain.DeviceAuthorization{
+		ID:             uuid.New(),
+		DeviceCodeHash: hashToken(deviceCode),
+		UserCode:       userCode,
+		ClientName:     clientName,
+		Status:         domain.DeviceAuthPending,
+		PollInterval:   devicePollInterval,
+		ExpiresAt:      now.Add(deviceCodeTTL),
+		CreatedAt:      now,
+	}
+	if err := s.deviceRepo.Create(ctx, auth); err != nil {
+		return nil, err
+	}
+
+	return &domain.DeviceCodeResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         s.verificationURL,
+		VerificationURIComplete: s.verificationURL + "?code=" + url.QueryEscape(userCode),
+		ExpiresIn:               int(deviceCodeTTL.Seconds()),
+		Interval:                devicePollInterval,
+	}, nil
+}
+
+// Poll exchanges an approved device code for a token and a refresh token to renew it with. Until
+// then it returns a DeviceFlowError saying whether to keep polling, slow down, or give up.
+func (s *DeviceAuthService) Poll(ctx context.Context, deviceCode string) (*domain.User, string, string, error) {
+	auth, err := s.deviceRepo.FindByDeviceCodeHash(ctx, hashToken(deviceCode))
+	if err != nil {
+		return nil, "", "", err
+	}
+	if auth == nil {
+		return nil, "", "", ErrDeviceCodeNotFound
+	}
+
+	now := time.Now().UTC()
+	if auth.Expired(now) || auth.Status == domain.DeviceAuthConsumed {
+		return nil, "", "", &DeviceFlowError{Reason: domain.DeviceErrExpiredToken, Interval: auth.PollInterval}
+	}
+
+	switch auth.Status {
+	case domain.DeviceAuthDenied:
+		return nil, "", "", &DeviceFlowError{Reason: domain.DeviceErrAccessDenied, Interval: auth.PollInterval}
+
+	case domain.DeviceAuthPending:
+		reason, interval := domain.DeviceErrAuthorizationPending, auth.PollInterval
+		if auth.LastPolledAt != nil && now.Sub(*auth.LastPolledAt) < time.Duration(interval)*time.Second {
+			reason, interval = domain.DeviceErrSlowDown, interval+deviceSlowDownIncrease
+		}
+		if err := s.deviceRepo.RecordPoll(ctx, auth.ID, now, interval); err != nil {
+			return nil, "", "", err
+		}
+		return nil, "", "", &DeviceFlowError{Reason: reason, Interval: interval}
+	}
+
+	// Approved: only the first poll to consume the approval gets a token
+	consumed, err := s.deviceRepo.Consume(ctx, auth.ID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if !consumed || auth.UserID == nil {
+		return nil, "", "", &DeviceFlowError{Reason: domain.DeviceErrExpiredToken, Interval: auth.PollInterval}
+	}
+
+	user, err := s.authService.GetUserByID(ctx, *auth.UserID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if user == nil {
+		return nil, "", "", ErrInvalidToken
+	}
+	token, err := s.authService.IssueWorkspaceToken(ctx, user.ID, uuid.Nil)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to issue device token: %w", err)
+	}
+	refreshToken, err := s.authService.IssueRefreshToken(ctx, user.ID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return user, token, refreshToken, nil
+}
+
+// Describe returns the pending sign-in for a user code, so the approval page can show which
+// client is asking
+func (s *DeviceAuthService) Describe(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error) {
+	code := normalizeUserCode(userCode)
+	if code == "" {
+		return nil, ErrUserCodeNotFound
+	}
+	auth, err := s.deviceRepo.FindByUserCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if auth == nil || auth.Status != domain.DeviceAuthPending || auth.Expired(time.Now()) {
+		return nil, ErrUserCodeNotFound
+	}
+	return auth, nil
+}
+
+// Approve lets the device that showed userCode sign in as userID
+func (s *DeviceAuthService) Approve(ctx context.Context, userCode string, userID uuid.UUID) (*domain.DeviceAuthorization, error) {
+	return s.resolve(ctx, userCode, userID, domain.DeviceAuthApproved)
+}
+
+// Deny rejects the sign-in for userCode
+func (s *DeviceAuthService) Deny(ctx context.Context, userCode string, userID uuid.UUID) (*domain.DeviceAuthorization, error) {
+	return s.resolve(ctx, userCode, userID, domain.DeviceAuthDenied)
+}
+
+func (s *DeviceAuthService) resolve(ctx context.Context, userCode string, userID uuid.UUID, status string) (*domain.DeviceAuthorization, error) {
+	auth, err := s.Describe(ctx, userCode)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := s.deviceRepo.Resolve(ctx, auth.ID, status, userID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !resolved {
+		return nil, ErrUserCodeNotFound
+	}
+	auth.Status = status
+	return auth, nil
+}
+
+// ExpiredCleaner returns a job that deletes device authorizations a day after they expire
+func (s *DeviceAuthService) ExpiredCleaner() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		deleted, err := s.deviceRepo.DeleteExpired(ctx, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			log.Printf("Deleted %d expired device authorizations", deleted)
+		}
+		return nil
+	}
+}
+
+// randomToken returns an unguessable URL-safe token, such as the code a device polls with
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// randomUserCode returns a short code formatted for typing, e.g. BDFG-HJKL
+func randomUserCode() (string, error) {
+	b := make([]byte, userCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate user code: %w", err)
+	}
+	code := make([]byte, userCodeLength)
+	for i := range b {
+		// 256 is not a multiple of 20, but the bias is negligible for a code that lives ten minutes
+		code[i] = userCodeAlphabet[int(b[i])%len(userCodeAlphabet)]
+	}
+	return string(code[:4]) + "-" + string(code[4:]), nil
+}
+
+// normalizeUserCode accepts codes typed in any case, with or without the dash
+func normalizeUserCode(input string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(input) {
+		if strings.ContainsRune(userCodeAlphabet, r) {
+			b.WriteRune(r)
+		} else if r != '-' && r != ' ' {
+			return ""
+		}
+	}
+	code := b.String()
+	if len(code) != userCodeLength {
+		return ""
+	}
+	return code[:4] + "-" + code[4:]
+}
+
+// hashToken is how bearer tokens from randomToken are stored, so a database leak cannot be used to sign in
+func hashToken(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"strings"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var ErrEmbedURL = apperr.New(ErrNotFound, "url is not a DevJournal snippet")
+
+const (
+	// Default and maximum iframe size of oEmbed responses, in pixels
+	embedWidth     = 640
+	embedMaxHeight = 600
+
+	// embedChromeHeight is the header and footer around the files, embedFileHeight the name bar and
+	// padding of each file, and embedLineHeight one line of code
+	embedChromeHeight = 60
+	embedFileHeight   = 54
+	embedLineHeight   = 20
+
+	// embedCacheAge is how long oEmbed consumers may cache a response, in seconds
+	embedCacheAge = 3600
+)
+
+// EmbedService serves public snippets for embedding in blogs and other sites, like gists
+type EmbedService struct {
+	snippetRepo *mongodb.SnippetRepository
+	userRepo    *postgres.UserRepository
+	embedURL    string
+	pageURL     string
+}
+
+// NewEmbedService creates a new embed service. embedURL is the public base of the embed pages
+// and pageURL the base of snippet pages in the web app; snippet slugs and IDs are appended to them.
+func NewEmbedService(snippetRepo *mongodb.SnippetRepository, userRepo *postgres.UserRepository, embedURL, pageURL string) *EmbedService {
+	return &EmbedService{
+		snippetRepo: snippetRepo,
+		userRepo:    userRepo,
+		embedURL:    strings.TrimRight(embedURL, "/"),
+		pageURL:     strings.TrimRight(pageURL, "/"),
+	}
+}
+
+// Snippet returns a public snippet by slug. Private and hidden snippets are not found.
+func (s *EmbedService) Snippet(ctx context.Context, slug string) (*domain.SnippetEmbed, error) {
+	snippet, err := findPublicSnippet(ctx, s.snippetRepo, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	authorName := "DevJournal user"
+	if authorID, err := uuid.Parse(snippet.UserID); err == nil {
+		author, err := s.userRepo.FindByID(ctx, authorID)
+		if err != nil {
+			return nil, err
+		}
+		if author != nil {
+			authorName = author.DisplayName
+		}
+	}
+
+	slug = domain.SnippetSlug(snippet)
+	embedURL := s.embedURL + "/" + slug
+	licenseURL := ""
+	if snippet.License != "" {
+		licenseURL = domain.LicenseURL(snippet.License)
+	}
+	return &domain.SnippetEmbed{
+		Snippet:    snippet,
+		AuthorName: authorName,
+		Slug:       slug,
+		EmbedURL:   embedURL,
+		PageURL:    s.pageURL + "/" + snippet.ID,
+		OEmbedURL:  origin(s.embedURL) + "/api/v1/oembed?format=json&url=" + url.QueryEscape(embedURL),
+		LicenseURL: licenseURL,
+		Height:     min(embedChromeHeight+embedFileHeight*len(snippet.Files)+embedLineHeight*max(snippet.Stats.Lines, 1), embedMaxHeight),
+	}, nil
+}
+
+// OEmbed returns the oEmbed response for an embed or web app URL of a public snippet.
+// maxWidth and maxHeight are the consumer's limits, or 0 for none.
+func (s *EmbedService) OEmbed(ctx context.Context, rawURL string, maxWidth, maxHeight int) (*domain.OEmbed, error) {
+	slug, ok := s.slugFromURL(rawURL)
+	if !ok {
+		return nil, ErrEmbedURL
+	}
+	embed, err := s.Snippet(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	width := embedWidth
+	if maxWidth > 0 && maxWidth < width {
+		width = maxWidth
+	}
+	height := embed.Height
+	if maxHeight > 0 && maxHeight < height {
+		height = maxHeight
+	}
+
+	return &domain.OEmbed{
+		Type:         "rich",
+		Version:      "1.0",
+		Title:        embed.Snippet.Title,
+		AuthorName:   embed.AuthorName,
+		ProviderName: "DevJournal",
+		ProviderURL:  origin(s.pageURL),
+		HTML: fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" style="border:0;max-width:100%%" title="%s" loading="lazy"></iframe>`,
+			html.EscapeString(embed.EmbedURL), width, height, html.EscapeString(embed.Snippet.Title)),
+		Width:    width,
+		Height:   height,
+		CacheAge: embedCacheAge,
+	}, nil
+}
+
+// slugFromURL returns the snippet slug of an embed URL or the snippet ID of a web app URL
+func (s *EmbedService) slugFromURL(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+	u.RawQuery, u.Fragment = "", ""
+	clean := strings.TrimRight(u.String(), "/")
+	for _, base := range []string{s.embedURL, s.pageURL} {
+		if rest, ok := strings.CutPrefix(clean, base+"/"); ok && rest != "" && !strings.Contains(rest, "/") {
+			return strings.TrimSuffix(rest, path.Ext(rest)), true
+		}
+	}
+	return "", false
+}
+
+// origin returns the scheme and host of a URL
+func origin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Scheme + "://" + u.Host
+}
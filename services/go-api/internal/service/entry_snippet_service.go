@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/policy"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var ErrSnippetNotLinked = apperr.New(ErrNotFound, "snippet is not linked to this entry")
+
+// EntrySnippetService links journal entries to the snippets they discuss. Links are stored in
+// Postgres next to the entries, so they go away with an entry; SnippetService removes the links
+// of deleted snippets.
+type EntrySnippetService struct {
+	linkRepo    *postgres.EntrySnippetRepository
+	journalRepo *postgres.JournalRepository
+	snippetRepo *mongodb.SnippetRepository
+}
+
+// NewEntrySnippetService creates a new entry snippet service
+func NewEntrySnippetService(linkRepo *postgres.EntrySnippetRepository, journalRepo *postgres.JournalRepository, snippetRepo *mongodb.SnippetRepository) *EntrySnippetService {
+	return &EntrySnippetService{linkRepo: linkRepo, journalRepo: journalRepo, snippetRepo: snippetRepo}
+}
+
+// Link links one of the user's snippets to one of their entries
+func (s *EntrySnippetService) Link(ctx context.Context, userID, entryID uuid.UUID, snippetID string) error {
+	if err := s.checkEntryOwner(ctx, userID, entryID); err != nil {
+		return err
+	}
+	snippet, err := s.snippetRepo.FindByID(ctx, snippetID)
+	if err != nil {
+		return fmt.Errorf("failed to find snippet: %w", err)
+	}
+	if err := checkSnippetOwner(snippet, userID.String()); err != nil {
+		return err
+	}
+	return s.linkRepo.Link(ctx, entryID, snippet.ID, userID)
+}
+
+// Unlink removes the link between an entry and a snippet
+func (s *EntrySnippetService) Unlink(ctx context.Context, userID, entryID uuid.UUID, snippetID string) error {
+	if err := s.checkEntryOwner(ctx, userID, entryID); err != nil {
+		return err
+	}
+	removed, err := s.linkRepo.Unlink(ctx, entryID, snippetID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrSnippetNotLinked
+	}
+	return nil
+}
+
+// RelatedSnippets returns the snippets linked to one of the user's entries. Links to snippets
+// that no longer exist are removed.
+func (s *EntrySnippetService) RelatedSnippets(ctx context.Context, userID, entryID uuid.UUID) ([]domain.RelatedSnippet, error) {
+	links, err := s.linkRepo.SnippetLinks(ctx, entryID)
+	if err != nil || len(links) == 0 {
+		return []domain.RelatedSnippet{}, err
+	}
+
+	ids := make([]string, len(links))
+	for i, link := range links {
+		ids[i] = link.ID
+	}
+	snippets, err := s.snippetRepo.FindByIDs(ctx, userID.String(), ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*domain.Snippet, len(snippets))
+	for i := range snippets {
+		byID[snippets[i].ID] = &snippets[i]
+	}
+
+	related := make([]domain.RelatedSnippet, 0, len(links))
+	for _, link := range links {
+		snippet, ok := byID[link.ID]
+		if !ok {
+			if _, err := s.linkRepo.Unlink(ctx, entryID, link.ID); err != nil {
+				log.Printf("WARN: Failed to remove link to missing snippet %s: %v", link.ID, err)
+			}
+			continue
+		}
+		link.Title = snippet.Title
+		link.Language = snippet.Language
+		related = append(related, link)
+	}
+	return related, nil
+}
+
+// RelatedEntries returns the user's entries linked to a snippet
+func (s *EntrySnippetService) RelatedEntries(ctx context.Context, userID uuid.UUID, snippetID string) ([]domain.RelatedEntry, error) {
+	return s.linkRepo.RelatedEntries(ctx, snippetID, userID)
+}
+
+// checkEntryOwner returns ErrEntryNotFound unless the entry exists and belongs to the user
+func (s *EntrySnippetService) checkEntryOwner(ctx context.Context, userID, entryID uuid.UUID) error {
+	entry, err := s.journalRepo.FindByID(ctx, entryID)
+	if err != nil {
+		return fmt.Errorf("failed to find journal entry: %w", err)
+	}
+	if policy.Authorize(policy.User(ctx, userID), policy.Link, entry) != policy.Allow {
+		return ErrEntryNotFound
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+// maxEntryTemplates caps how many templates a group can publish
+const maxEntryTemplates = 50
+
+// templateInstancePattern keeps instance names short and readable in entry titles
+var templateInstancePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 ._-]{0,39}$`)
+
+var (
+	ErrEntryTemplateNotFound = apperr.New(ErrNotFound, "entry template not found")
+	ErrNotTemplateManager    = apperr.New(ErrForbidden, "only group owners and admins can manage entry templates")
+	ErrTemplateName          = apperr.New(ErrValidation, "name is required and must be at most 100 characters")
+	ErrTemplatePrompts       = apperr.Newf(ErrValidation, "a template needs between 1 and %d prompts, each one line of at most 200 characters", domain.MaxTemplatePrompts)
+	ErrTooManyTemplates      = apperr.Newf(ErrValidation, "a group can have at most %d entry templates", maxEntryTemplates)
+	ErrTemplateInstance      = apperr.New(ErrValidation, "instance must be 1-40 letters, digits, spaces, dots, dashes or underscores, starting with a letter or digit")
+	ErrTemplateAnswered      = apperr.New(ErrConflict, "you already have an entry for this instance of the template")
+)
+
+// EntryTemplateService manages the entry templates study group admins publish, the entries
+// members make from them, and the group's view of the answers
+type EntryTemplateService struct {
+	templateRepo   *postgres.EntryTemplateRepository
+	groupRepo      *postgres.StudyGroupRepository
+	permissions    *GroupPermissionService
+	journalService *JournalService
+}
+
+// NewEntryTemplateService creates a new entry template service
+func NewEntryTemplateService(templateRepo *postgres.EntryTemplateRepository, groupRepo *postgres.StudyGroupRepository, permissions *GroupPermissionService, journalService *JournalService) *EntryTemplateService {
+	return &EntryTemplateService{templateRepo: templateRepo, groupRepo: groupRepo, permissions: permissions, journalService: journalService}
+}
+
+// List returns a group's templates (group members only)
+func (s *EntryTemplateService) List(ctx context.Context, groupID, userID uuid.UUID) ([]domain.EntryTemplate, error) {
+	if err := s.checkMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	return s.templateRepo.ListByGroup(ctx, groupID)
+}
+
+// Create publishes a template to a group (group owners and admins only)
+func (s *EntryTemplateService) Create(ctx context.Context, groupID, userID uuid.UUID, req *domain.EntryTemplateRequest) (*domain.EntryTemplate, error) {
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	template := &domain.EntryTemplate{
+		ID:        uuid.New(),
+		GroupID:   groupID,
+		CreatedBy: userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := setTemplateFields(template, req); err != nil {
+		return nil, err
+	}
+
+	count, err := s.templateRepo.Count(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxEntryTemplates {
+		return nil, ErrTooManyTemplates
+	}
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Update replaces a template's name, prompts, and tags (group owners and admins only). Entries
+// already made from it keep their headings; answers to removed prompts drop out of the group view.
+func (s *EntryTemplateService) Update(ctx context.Context, groupID, userID, templateID uuid.UUID, req *domain.EntryTemplateRequest) (*domain.EntryTemplate, error) {
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	template, err := s.find(ctx, groupID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := setTemplateFields(template, req); err != nil {
+		return nil, err
+	}
+	template.UpdatedAt = time.Now().UTC()
+
+	updated, err := s.templateRepo.Update(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrEntryTemplateNotFound
+	}
+	return template, nil
+}
+
+// Delete removes a template from a group (group owners and admins only). Entries made from it
+// stay in their authors' journals.
+func (s *EntryTemplateService) Delete(ctx context.Context, groupID, userID, templateID uuid.UUID) error {
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return err
+	}
+	deleted, err := s.templateRepo.Delete(ctx, templateID, groupID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrEntryTemplateNotFound
+	}
+	return nil
+}
+
+// Instantiate starts a journal entry from a template for an instance of it, with a heading per
+// prompt to answer under (members the group lets share entries only). Each member has one entry
+// per instance.
+func (s *EntryTemplateService) Instantiate(ctx context.Context, groupID, userID, templateID uuid.UUID, req *domain.InstantiateTemplateRequest) (*domain.JournalEntry, error) {
+	if err := s.permissions.Check(ctx, groupID, userID, domain.GroupActionShareEntries); err != nil {
+		return nil, err
+	}
+	template, err := s.find(ctx, groupID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := templateInstance(req.Instance)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.templateRepo.FindResponse(ctx, template.ID, instance, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != uuid.Nil {
+		return nil, ErrTemplateAnswered
+	}
+
+	entry, err := s.journalService.Create(ctx, userID, &domain.CreateJournalEntryRequest{
+		Title:   template.Title(instance),
+		Content: template.Content(),
+		Tags:    template.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	added, err := s.templateRepo.AddResponse(ctx, template.ID, instance, userID, entry.ID)
+	if err == nil && !added {
+		err = ErrTemplateAnswered
+	}
+	if err != nil {
+		// Another request started this member's entry first, or it could not be recorded
+		if deleteErr := s.journalService.Delete(ctx, entry.ID, userID); deleteErr != nil {
+			log.Printf("WARN: Failed to delete unrecorded template entry %s: %v", entry.ID, deleteErr)
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ListInstances returns the instances of a template members have answered, newest first
+// (group members only)
+func (s *EntryTemplateService) ListInstances(ctx context.Context, groupID, userID, templateID uuid.UUID) ([]domain.TemplateInstance, error) {
+	if err := s.checkMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.find(ctx, groupID, templateID); err != nil {
+		return nil, err
+	}
+	return s.templateRepo.ListInstances(ctx, templateID)
+}
+
+// GetInstance returns every current member's answer to each prompt of a template for an
+// instance (group members only). Answers are read from the entries as they are now, so edits
+// show up; encrypted and vault entries are counted as withheld.
+func (s *EntryTemplateService) GetInstance(ctx context.Context, groupID, userID, templateID uuid.UUID, instance string) (*domain.TemplateInstanceResponses, error) {
+	if err := s.checkMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	template, err := s.find(ctx, groupID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.templateRepo.ListResponses(ctx, templateID, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.TemplateInstanceResponses{
+		TemplateID: template.ID,
+		Instance:   instance,
+		Responses:  len(entries),
+		Prompts:    make([]domain.TemplatePromptResponse, len(template.Prompts)),
+	}
+	for i, prompt := range template.Prompts {
+		result.Prompts[i] = domain.TemplatePromptResponse{Prompt: prompt, Answers: []domain.TemplateAnswer{}}
+	}
+	for _, entry := range entries {
+		if entry.ContentFormat == domain.ContentFormatE2EE || entry.IsVault {
+			result.Withheld++
+			continue
+		}
+		for i, answer := range domain.TemplateAnswers(template.Prompts, entry.Content) {
+			if answer == "" {
+				continue
+			}
+			result.Prompts[i].Answers = append(result.Prompts[i].Answers, domain.TemplateAnswer{
+				UserID:      entry.UserID,
+				DisplayName: entry.DisplayName,
+				EntryID:     entry.EntryID,
+				Answer:      answer,
+				UpdatedAt:   entry.UpdatedAt,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (s *EntryTemplateService) find(ctx context.Context, groupID, templateID uuid.UUID) (*domain.EntryTemplate, error) {
+	template, err := s.templateRepo.FindByID(ctx, templateID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, ErrEntryTemplateNotFound
+	}
+	return template, nil
+}
+
+func (s *EntryTemplateService) checkMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	_, err := groupRole(ctx, s.groupRepo, groupID, userID)
+	return err
+}
+
+func (s *EntryTemplateService) checkManager(ctx context.Context, groupID, userID uuid.UUID) error {
+	role, err := s.groupRepo.GetMemberRole(ctx, groupID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check group role: %w", err)
+	}
+	if !isGroupManager(role) {
+		return ErrNotTemplateManager
+	}
+	return nil
+}
+
+// setTemplateFields validates a template request and copies it onto the template
+func setTemplateFields(template *domain.EntryTemplate, req *domain.EntryTemplateRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return ErrTemplateName
+	}
+	if len(req.Prompts) == 0 || len(req.Prompts) > domain.MaxTemplatePrompts {
+		return ErrTemplatePrompts
+	}
+	prompts := make([]string, len(req.Prompts))
+	for i, prompt := range req.Prompts {
+		prompts[i] = strings.TrimSpace(prompt)
+		if prompts[i] == "" || len(prompts[i]) > 200 || strings.ContainsAny(prompts[i], "\r\n") {
+			return ErrTemplatePrompts
+		}
+	}
+	tags := req.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	template.Name, template.Prompts, template.Tags = name, prompts, tags
+	return nil
+}
+
+// templateInstance validates an instance name, defaulting to the current ISO week
+func templateInstance(instance string) (string, error) {
+	instance = strings.TrimSpace(instance)
+	if instance == "" {
+		return domain.TemplateInstanceKey(time.Now().UTC()), nil
+	}
+	if !templateInstancePattern.MatchString(instance) {
+		return "", ErrTemplateInstance
+	}
+	return instance, nil
+}
@@ -0,0 +1,16 @@
+package service
+
+import "devjournal/pkg/apperr"
+
+// Error kinds. Every service sentinel wraps one of these so handlers can map it to a
+// status code with errors.Is, e.g. errors.Is(ErrSnippetNotFound, ErrNotFound).
+var (
+	ErrValidation      = apperr.ErrValidation
+	ErrUnauthorized    = apperr.ErrUnauthorized
+	ErrPaymentRequired = apperr.ErrPaymentRequired
+	ErrForbidden       = apperr.ErrForbidden
+	ErrNotFound        = apperr.ErrNotFound
+	ErrConflict        = apperr.ErrConflict
+	ErrPrecondition    = apperr.ErrPrecondition
+	ErrUnavailable     = apperr.ErrUnavailable
+)
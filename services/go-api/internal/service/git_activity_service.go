@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/gitactivity"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrGitHookNotFound      = apperr.New(ErrNotFound, "git webhook not set up")
+	ErrGitDraftNotFound     = apperr.New(ErrNotFound, "git activity draft not found")
+	ErrGitDraftClosed       = apperr.New(ErrConflict, "draft was already published or dismissed")
+	ErrUnknownGitProvider   = apperr.New(ErrValidation, "request is not a GitHub or GitLab webhook")
+	ErrInvalidGitHookFilter = apperr.Newf(ErrValidation, "repos and authors take at most %d values each", maxGitHookFilters)
+)
+
+const (
+	// maxGitHookFilters caps the repos and authors a webhook filters by
+	maxGitHookFilters = 50
+
+	gitDraftListLimit = 30
+)
+
+// GitActivityService drafts daily journal entries from commits and pull requests that GitHub and
+// GitLab report to a user's webhook
+type GitActivityService struct {
+	repo           *postgres.GitActivityRepository
+	journalService *JournalService
+	webhookURL     string
+}
+
+// NewGitActivityService creates a new Git activity service. webhookURL is the public base URL of
+// the webhook endpoint, without the webhook ID.
+func NewGitActivityService(repo *postgres.GitActivityRepository, journalService *JournalService, webhookURL string) *GitActivityService {
+	return &GitActivityService{repo: repo, journalService: journalService, webhookURL: strings.TrimSuffix(webhookURL, "/")}
+}
+
+// GetHook returns a user's webhook without its secret, which is only shown when created
+func (s *GitActivityService) GetHook(ctx context.Context, userID uuid.UUID) (*domain.GitHook, error) {
+	hook, err := s.repo.FindHookByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if hook == nil {
+		return nil, ErrGitHookNotFound
+	}
+	hook.Secret = ""
+	hook.URL = s.webhookURL + "/" + hook.ID.String()
+	return hook, nil
+}
+
+// RotateSecret creates the user's webhook, or replaces its secret so deliveries signed with the
+// old one are refused. The new secret is returned this once.
+func (s *GitActivityService) RotateSecret(ctx context.Context, userID uuid.UUID) (*domain.GitHook, error) {
+	secret, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	hook, err := s.repo.SaveHookSecret(ctx, &domain.GitHook{ID: uuid.New(), UserID: userID, Secret: secret, UpdatedAt: now})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save git webhook: %w", err)
+	}
+	hook.URL = s.webhookURL + "/" + hook.ID.String()
+	return hook, nil
+}
+
+// UpdateHook changes which activity a user's webhook records
+func (s *GitActivityService) UpdateHook(ctx context.Context, userID uuid.UUID, req *domain.UpdateGitHookRequest) (*domain.GitHook, error) {
+	repos, authors := trimValues(req.Repos), trimValues(req.Authors)
+	if len(repos) > maxGitHookFilters || len(authors) > maxGitHookFilters {
+		return nil, ErrInvalidGitHookFilter
+	}
+	hook, err := s.repo.UpdateHookSettings(ctx, &domain.GitHook{
+		UserID:              userID,
+		Enabled:             req.Enabled,
+		IncludeCommits:      req.IncludeCommits,
+		IncludePullRequests: req.IncludePullRequests,
+		Repos:               repos,
+		Authors:             authors,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update git webhook: %w", err)
+	}
+	if hook == nil {
+		return nil, ErrGitHookNotFound
+	}
+	hook.Secret = ""
+	hook.URL = s.webhookURL + "/" + hook.ID.String()
+	return hook, nil
+}
+
+// DeleteHook removes a user's webhook. Recorded activity and drafts are kept.
+func (s *GitActivityService) DeleteHook(ctx context.Context, userID uuid.UUID) error {
+	deleted, err := s.repo.DeleteHook(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrGitHookNotFound
+	}
+	return nil
+}
+
+// Receive records the activity in a webhook delivery that passes the webhook's filters, and
+// refreshes the drafts of the days it happened on. It returns how many events were new.
+func (s *GitActivityService) Receive(ctx context.Context, hookID uuid.UUID, delivery *gitactivity.Delivery) (int, error) {
+	if delivery.Provider != domain.GitProviderGitHub && delivery.Provider != domain.GitProviderGitLab {
+		return 0, ErrUnknownGitProvider
+	}
+	hook, err := s.repo.FindHookByID(ctx, hookID)
+	if err != nil {
+		return 0, err
+	}
+	if hook == nil {
+		return 0, ErrGitHookNotFound
+	}
+	if err := delivery.Verify(hook.Secret); err != nil {
+		return 0, err
+	}
+	if !hook.Enabled {
+		return 0, nil
+	}
+
+	activities, err := delivery.Parse()
+	if err != nil {
+		return 0, apperr.New(ErrValidation, err.Error())
+	}
+	matching := activities[:0]
+	for i := range activities {
+		if gitactivity.Matches(hook, &activities[i]) {
+			matching = append(matching, activities[i])
+		}
+	}
+	if len(matching) == 0 {
+		return 0, nil
+	}
+
+	recorded, err := s.repo.RecordActivity(ctx, hook.UserID, matching)
+	if err != nil {
+		return 0, err
+	}
+	days := make(map[time.Time]bool)
+	for _, a := range recorded {
+		days[a.OccurredAt.UTC().Truncate(24*time.Hour)] = true
+	}
+	for day := range days {
+		if err := s.refreshDraft(ctx, hook.UserID, day); err != nil {
+			return 0, err
+		}
+	}
+	return len(recorded), nil
+}
+
+// refreshDraft summarizes a day's activity into its draft
+func (s *GitActivityService) refreshDraft(ctx context.Context, userID uuid.UUID, day time.Time) error {
+	activities, err := s.repo.FindActivity(ctx, userID, day, day.Add(24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if len(activities) == 0 {
+		return nil
+	}
+	title, content, tags := gitactivity.Summarize(day, activities)
+	return s.repo.RefreshDraft(ctx, &domain.GitDraft{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Day:       day,
+		Title:     title,
+		Content:   content,
+		Tags:      tags,
+		UpdatedAt: time.Now().UTC(),
+	})
+}
+
+// ListDrafts returns a user's drafts waiting to be published, most recent day first
+func (s *GitActivityService) ListDrafts(ctx context.Context, userID uuid.UUID) ([]domain.GitDraft, error) {
+	return s.repo.ListDrafts(ctx, userID, gitDraftListLimit)
+}
+
+// openDraft returns a user's draft that is still waiting to be published
+func (s *GitActivityService) openDraft(ctx context.Context, id, userID uuid.UUID) (*domain.GitDraft, error) {
+	draft, err := s.repo.FindDraft(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if draft == nil {
+		return nil, ErrGitDraftNotFound
+	}
+	if draft.Status != domain.GitDraftPending {
+		return nil, ErrGitDraftClosed
+	}
+	return draft, nil
+}
+
+// UpdateDraft saves a user's edits to a draft. Edited drafts no longer change with new activity.
+func (s *GitActivityService) UpdateDraft(ctx context.Context, id, userID uuid.UUID, req *domain.UpdateGitDraftRequest) (*domain.GitDraft, error) {
+	draft, err := s.openDraft(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	draft.Title = req.Title
+	draft.Content = req.Content
+	draft.Tags = trimValues(req.Tags)
+	draft.Edited = true
+	draft.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateDraft(ctx, draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// PublishDraft turns a draft into a journal entry in the active workspace
+func (s *GitActivityService) PublishDraft(ctx context.Context, id, userID uuid.UUID) (*domain.JournalEntry, error) {
+	draft, err := s.openDraft(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.journalService.Create(ctx, userID, &domain.CreateJournalEntryRequest{
+		Title:   draft.Title,
+		Content: draft.Content,
+		Tags:    draft.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetDraftStatus(ctx, draft.ID, domain.GitDraftPublished, &entry.ID); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DismissDraft discards a draft; later activity on its day doesn't bring it back
+func (s *GitActivityService) DismissDraft(ctx context.Context, id, userID uuid.UUID) error {
+	draft, err := s.openDraft(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.SetDraftStatus(ctx, draft.ID, domain.GitDraftDismissed, nil)
+}
+
+// trimValues drops blank values, never returning nil
+func trimValues(values []string) []string {
+	trimmed := []string{}
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			trimmed = append(trimmed, v)
+		}
+	}
+	return trimmed
+}
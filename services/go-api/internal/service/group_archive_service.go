@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/i18n"
+	"devjournal/internal/mail"
+	"devjournal/internal/push"
+	"devjournal/internal/repository/postgres"
+
+	"github.com/google/uuid"
+)
+
+// groupArchiveBatch is how many groups one archival run warns, and archives, at most
+const groupArchiveBatch = 200
+
+// GroupArchiveService archives study groups nobody has been active in for a while, so the
+// discovery list only shows live groups. Owners are warned ahead of time, and a chat message,
+// a new member, or reactivating the group resets the clock.
+type GroupArchiveService struct {
+	groupRepo     *postgres.StudyGroupRepository
+	userRepo      *postgres.UserRepository
+	pushService   *PushService
+	mailer        mail.Sender // nil disables email
+	inactiveAfter time.Duration
+	warning       time.Duration
+
+	mu       sync.Mutex
+	activity map[uuid.UUID]time.Time // latest chat message per group, not yet recorded
+}
+
+// NewGroupArchiveService creates a new group archive service. Groups are archived once inactive
+// for inactiveAfter, with owners warned the given time before; a zero inactiveAfter disables
+// archival. A nil mailer disables archival emails.
+func NewGroupArchiveService(groupRepo *postgres.StudyGroupRepository, userRepo *postgres.UserRepository, pushService *PushService, mailer mail.Sender, inactiveAfter, warning time.Duration) *GroupArchiveService {
+	return &GroupArchiveService{
+		groupRepo:     groupRepo,
+		userRepo:      userRepo,
+		pushService:   pushService,
+		mailer:        mailer,
+		inactiveAfter: inactiveAfter,
+		warning:       min(max(warning, 0), inactiveAfter),
+		activity:      make(map[uuid.UUID]time.Time),
+	}
+}
+
+// OnChatMessage notes activity in the group of a chat message, to be recorded by
+// ActivityRecorder. It is a hub listener and doesn't block.
+func (s *GroupArchiveService) OnChatMessage(msg *domain.ChatMessage) {
+	if msg.UserID == "" {
+		return
+	}
+	groupID, _, ok := domain.GroupRoom(msg.Room)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	if msg.Timestamp.After(s.activity[groupID]) {
+		s.activity[groupID] = msg.Timestamp
+	}
+	s.mu.Unlock()
+}
+
+// ActivityRecorder returns a job that saves the group activity noted since its last run
+func (s *GroupArchiveService) ActivityRecorder() func(ctx context.Context) error {
+	return s.recordActivity
+}
+
+// Archiver returns a job that warns the owners of groups about to be archived and archives
+// groups whose owners were warned and stayed inactive
+func (s *GroupArchiveService) Archiver() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if s.inactiveAfter <= 0 {
+			return nil
+		}
+		// Activity not yet recorded could otherwise get a live group archived
+		if err := s.recordActivity(ctx); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+
+		warn, err := s.groupRepo.ListInactive(ctx, now.Add(-(s.inactiveAfter - s.warning)), false, time.Time{}, groupArchiveBatch)
+		if err != nil {
+			return err
+		}
+		for _, group := range warn {
+			// Mark first so a failing provider can't cause repeat warnings every run
+			marked, err := s.groupRepo.MarkArchiveWarned(ctx, group.ID, group.LastActiveAt, now)
+			if err != nil {
+				return err
+			}
+			if marked {
+				s.notify(ctx, &group, "warning", now.Add(s.warning))
+			}
+		}
+
+		// Groups are archived no sooner than the warning period after their owner was warned
+		archive, err := s.groupRepo.ListInactive(ctx, now.Add(-s.inactiveAfter), true, now.Add(-s.warning), groupArchiveBatch)
+		if err != nil {
+			return err
+		}
+		for _, group := range archive {
+			archived, err := s.groupRepo.Archive(ctx, group.ID, group.LastActiveAt, now)
+			if err != nil {
+				return err
+			}
+			if archived {
+				log.Printf("Archived study group %s, inactive since %s", group.ID, group.LastActiveAt.Format(time.RFC3339))
+				s.notify(ctx, &group, "archived", now)
+			}
+		}
+		return nil
+	}
+}
+
+// recordActivity saves the noted group activity, keeping it for the next run if that fails
+func (s *GroupArchiveService) recordActivity(ctx context.Context) error {
+	s.mu.Lock()
+	activity := s.activity
+	s.activity = make(map[uuid.UUID]time.Time)
+	s.mu.Unlock()
+
+	if err := s.groupRepo.RecordActivity(ctx, activity); err != nil {
+		s.mu.Lock()
+		for groupID, at := range activity {
+			if at.After(s.activity[groupID]) {
+				s.activity[groupID] = at
+			}
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// notify tells a group's owner it will be, or was, archived: by push in their locale, or by
+// email if they have no device to receive it
+func (s *GroupArchiveService) notify(ctx context.Context, group *domain.StudyGroup, event string, at time.Time) {
+	locale := s.pushService.RecipientLocale(ctx, group.CreatedBy)
+	days := int(s.inactiveAfter.Hours() / 24)
+	title := i18n.T(locale, "%s was archived", group.Name)
+	body := i18n.T(locale, "Nobody was active in %s for %d days, so it's hidden from discovery and read-only. Reactivate it to open it again.", group.Name, days)
+	if event == "warning" {
+		title = i18n.T(locale, "%s will be archived", group.Name)
+		body = i18n.T(locale, "Nobody has been active in %s for a while. Unless someone posts or you reactivate it, it will be archived on %s.", group.Name, at.Format(time.DateOnly))
+	}
+
+	sent, err := s.pushService.Notify(ctx, group.CreatedBy, &push.Notification{
+		Title:       title,
+		Body:        body,
+		Data:        map[string]string{"type": domain.PushGroupArchival, "event": event, "groupId": group.ID.String()},
+		CollapseKey: domain.PushGroupArchival + ":" + group.ID.String(),
+	})
+	if err != nil {
+		log.Printf("WARN: Failed to push archival of group %s to user %s: %v", group.ID, group.CreatedBy, err)
+	}
+	if sent > 0 || s.mailer == nil {
+		return
+	}
+
+	user, err := s.userRepo.FindByID(ctx, group.CreatedBy)
+	if err != nil || user == nil {
+		log.Printf("WARN: Failed to load user %s to email archival of group %s: %v", group.CreatedBy, group.ID, err)
+		return
+	}
+	email := i18n.T(locale, "%s:\n\n%s\n\nOpen the group in DevJournal.\n", title, body)
+	if err := s.mailer.Send(ctx, user.Email, title, email); err != nil {
+		log.Printf("WARN: Failed to email archival of group %s to user %s: %v", group.ID, group.CreatedBy, err)
+	}
+}
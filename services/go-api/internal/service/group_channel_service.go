@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/policy"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+// maxGroupChannels caps how many channels a group can add besides general
+const maxGroupChannels = 20
+
+// channelNamePattern keeps channel names short, lowercase and safe to put in a room name
+var channelNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+var (
+	ErrInvalidChannelName = apperr.New(ErrValidation, "channel name must be 1-32 lowercase letters, digits or dashes, starting with a letter or digit")
+	ErrInvalidPostPolicy  = apperr.New(ErrValidation, "postPolicy must be members or admins")
+	ErrTooManyChannels    = apperr.Newf(ErrValidation, "a group can have at most %d channels besides general", maxGroupChannels)
+	ErrDefaultChannel     = apperr.New(ErrValidation, "the general channel cannot be deleted")
+	ErrChannelExists      = apperr.New(ErrConflict, "the group already has a channel with this name")
+	ErrChannelNotFound    = apperr.New(ErrNotFound, "channel not found")
+	ErrNotChannelManager  = apperr.New(ErrForbidden, "only group owners and admins can manage channels")
+)
+
+// GroupChannelService manages study group chat channels and who can post in them
+type GroupChannelService struct {
+	channelRepo *postgres.GroupChannelRepository
+	groupRepo   *postgres.StudyGroupRepository
+}
+
+// NewGroupChannelService creates a new group channel service
+func NewGroupChannelService(channelRepo *postgres.GroupChannelRepository, groupRepo *postgres.StudyGroupRepository) *GroupChannelService {
+	return &GroupChannelService{channelRepo: channelRepo, groupRepo: groupRepo}
+}
+
+// List returns a group's channels, general first (group members only)
+func (s *GroupChannelService) List(ctx context.Context, groupID, userID uuid.UUID) ([]*domain.GroupChannel, error) {
+	if _, err := groupRole(ctx, s.groupRepo, groupID, userID); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.channelRepo.List(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	general := domain.NewGroupChannel(groupID, domain.DefaultChannel, domain.ChannelPostMembers)
+	channels := []*domain.GroupChannel{general}
+	for _, channel := range stored {
+		if channel.Name == domain.DefaultChannel {
+			*general = *channel
+			continue
+		}
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
+
+// Create adds a channel to a group (group owners and admins only)
+func (s *GroupChannelService) Create(ctx context.Context, groupID, userID uuid.UUID, req *domain.CreateGroupChannelRequest) (*domain.GroupChannel, error) {
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if !channelNamePattern.MatchString(name) {
+		return nil, ErrInvalidChannelName
+	}
+	if name == domain.DefaultChannel {
+		return nil, ErrChannelExists
+	}
+	policy := req.PostPolicy
+	if policy == "" {
+		policy = domain.ChannelPostMembers
+	}
+	if !validPostPolicy(policy) {
+		return nil, ErrInvalidPostPolicy
+	}
+
+	count, err := s.channelRepo.Count(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxGroupChannels {
+		return nil, ErrTooManyChannels
+	}
+
+	channel := domain.NewGroupChannel(groupID, name, policy)
+	created, err := s.channelRepo.Create(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrChannelExists
+	}
+	return channel, nil
+}
+
+// Update changes who can post in a channel, including general (group owners and admins only)
+func (s *GroupChannelService) Update(ctx context.Context, groupID, userID uuid.UUID, name string, req *domain.UpdateGroupChannelRequest) (*domain.GroupChannel, error) {
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	if !validPostPolicy(req.PostPolicy) {
+		return nil, ErrInvalidPostPolicy
+	}
+
+	if name != domain.DefaultChannel {
+		existing, err := s.channelRepo.Find(ctx, groupID, name)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, ErrChannelNotFound
+		}
+	}
+
+	channel := domain.NewGroupChannel(groupID, name, req.PostPolicy)
+	if err := s.channelRepo.Upsert(ctx, channel); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// Delete removes a channel from a group (group owners and admins only). Members connected to
+// it stay connected until they leave, but can't rejoin.
+func (s *GroupChannelService) Delete(ctx context.Context, groupID, userID uuid.UUID, name string) error {
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return err
+	}
+	if name == domain.DefaultChannel {
+		return ErrDefaultChannel
+	}
+
+	deleted, err := s.channelRepo.Delete(ctx, groupID, name)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrChannelNotFound
+	}
+	return nil
+}
+
+// Access checks a user can join a chat room, returning whether they can only read it. Group
+// rooms are open to the group's members, read-only in channels where only admins post and in
+// archived groups; other rooms are not restricted here.
+func (s *GroupChannelService) Access(ctx context.Context, room string, userID uuid.UUID) (bool, error) {
+	groupID, name, ok := domain.GroupRoom(room)
+	if !ok {
+		return false, nil
+	}
+
+	role, err := groupRole(ctx, s.groupRepo, groupID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	channel, err := s.channelRepo.Find(ctx, groupID, name)
+	if err != nil {
+		return false, err
+	}
+	archived, err := s.groupRepo.IsArchived(ctx, groupID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check group archival: %w", err)
+	}
+	if archived {
+		return true, nil
+	}
+	policy := domain.ChannelPostMembers
+	switch {
+	case channel != nil:
+		policy = channel.PostPolicy
+	case name != domain.DefaultChannel:
+		return false, ErrChannelNotFound
+	}
+
+	return policy == domain.ChannelPostAdmins && !isGroupManager(role), nil
+}
+
+func (s *GroupChannelService) checkManager(ctx context.Context, groupID, userID uuid.UUID) error {
+	role, err := s.groupRepo.GetMemberRole(ctx, groupID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check group role: %w", err)
+	}
+	if !isGroupManager(role) {
+		return ErrNotChannelManager
+	}
+	return nil
+}
+
+// isGroupManager reports whether a member with role may manage their group
+func isGroupManager(role string) bool {
+	return policy.Authorize(policy.Subject{}, policy.Manage, policy.Group{Role: role}) == policy.Allow
+}
+
+func validPostPolicy(policy string) bool {
+	return policy == domain.ChannelPostMembers || policy == domain.ChannelPostAdmins
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidGroupPermission = apperr.New(ErrValidation, "permissions must be members or admins")
+	ErrNotPermissionManager   = apperr.New(ErrForbidden, "only group owners and admins can change group permissions")
+)
+
+// groupActionErrors are returned when a member's group keeps an action to its owners and admins
+var groupActionErrors = map[string]error{
+	domain.GroupActionShareEntries:  apperr.New(ErrForbidden, "only group owners and admins can share entries in this group"),
+	domain.GroupActionPostSnippets:  apperr.New(ErrForbidden, "only group owners and admins can post snippets in this group"),
+	domain.GroupActionStartSessions: apperr.New(ErrForbidden, "only group owners and admins can start sessions in this group"),
+}
+
+// GroupPermissionService manages who in a study group may share entries, post snippets, and
+// start sessions, and resolves those permissions for the services behind each action
+type GroupPermissionService struct {
+	permissionRepo *postgres.GroupPermissionRepository
+	groupRepo      *postgres.StudyGroupRepository
+}
+
+// NewGroupPermissionService creates a new group permission service
+func NewGroupPermissionService(permissionRepo *postgres.GroupPermissionRepository, groupRepo *postgres.StudyGroupRepository) *GroupPermissionService {
+	return &GroupPermissionService{permissionRepo: permissionRepo, groupRepo: groupRepo}
+}
+
+// Get returns a group's permissions (group members only)
+func (s *GroupPermissionService) Get(ctx context.Context, groupID, userID uuid.UUID) (*domain.GroupPermissions, error) {
+	if _, err := s.role(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	return s.permissions(ctx, groupID)
+}
+
+// Update replaces a group's permissions (group owners and admins only)
+func (s *GroupPermissionService) Update(ctx context.Context, groupID, userID uuid.UUID, req *domain.UpdateGroupPermissionsRequest) (*domain.GroupPermissions, error) {
+	role, err := s.role(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isGroupManager(role) {
+		return nil, ErrNotPermissionManager
+	}
+
+	permissions := domain.NewGroupPermissions(groupID)
+	for _, field := range []struct {
+		value string
+		dest  *string
+	}{
+		{req.ShareEntries, &permissions.ShareEntries},
+		{req.PostSnippets, &permissions.PostSnippets},
+		{req.StartSessions, &permissions.StartSessions},
+	} {
+		switch field.value {
+		case "":
+		case domain.GroupPermissionMembers, domain.GroupPermissionAdmins:
+			*field.dest = field.value
+		default:
+			return nil, ErrInvalidGroupPermission
+		}
+	}
+
+	if err := s.permissionRepo.Upsert(ctx, permissions); err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// Can reports whether a member may take an action in a group. It returns ErrNotGroupMember or
+// ErrStudyGroupNotFound if the user doesn't belong to the group.
+func (s *GroupPermissionService) Can(ctx context.Context, groupID, userID uuid.UUID, action string) (bool, error) {
+	role, err := s.role(ctx, groupID, userID)
+	if err != nil {
+		return false, err
+	}
+	if isGroupManager(role) {
+		return true, nil
+	}
+	permissions, err := s.permissions(ctx, groupID)
+	if err != nil {
+		return false, err
+	}
+	return permissions.Policy(action) == domain.GroupPermissionMembers, nil
+}
+
+// Check returns an error unless the user is a member of the group who may take the action and
+// the group isn't archived
+func (s *GroupPermissionService) Check(ctx context.Context, groupID, userID uuid.UUID, action string) error {
+	archived, err := s.groupRepo.IsArchived(ctx, groupID)
+	if err != nil {
+		return fmt.Errorf("failed to check group archival: %w", err)
+	}
+	if archived {
+		return ErrGroupArchived
+	}
+	can, err := s.Can(ctx, groupID, userID, action)
+	if err != nil {
+		return err
+	}
+	if !can {
+		return groupActionErrors[action]
+	}
+	return nil
+}
+
+// permissions returns a group's permissions, falling back to defaults
+func (s *GroupPermissionService) permissions(ctx context.Context, groupID uuid.UUID) (*domain.GroupPermissions, error) {
+	permissions, err := s.permissionRepo.Get(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if permissions == nil {
+		permissions = domain.NewGroupPermissions(groupID)
+	}
+	return permissions, nil
+}
+
+// role returns the user's role in the group, or an error unless they belong to it
+func (s *GroupPermissionService) role(ctx context.Context, groupID, userID uuid.UUID) (string, error) {
+	role, err := groupRole(ctx, s.groupRepo, groupID, userID)
+	if err != nil {
+		return "", err
+	}
+	return role, nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/i18n"
+	"devjournal/internal/mail"
+	"devjournal/internal/push"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+// ImpersonationTTL is how long an admin can act as a user before starting a new session
+const ImpersonationTTL = 15 * time.Minute
+
+var (
+	ErrImpersonationNotFound = apperr.New(ErrNotFound, "impersonation not found")
+	ErrImpersonationReason   = apperr.Newf(ErrValidation, "reason is required and must be at most %d characters", domain.MaxImpersonationReasonLength)
+	ErrImpersonationTarget   = apperr.New(ErrForbidden, "admins can't be impersonated")
+	ErrImpersonationEnded    = apperr.New(ErrConflict, "the impersonation already ended")
+	ErrImpersonationUser     = apperr.New(ErrNotFound, "user not found")
+)
+
+// ImpersonationService lets admins act as a user to debug issues only that user sees. Every
+// session is kept as an audit log with the admin's reason, logged, and announced to the user.
+type ImpersonationService struct {
+	impersonationRepo *postgres.ImpersonationRepository
+	userRepo          *postgres.UserRepository
+	authService       *AuthService
+	pushService       *PushService
+	mailer            mail.Sender // nil disables email
+}
+
+// NewImpersonationService creates a new impersonation service. A nil mailer disables the email
+// telling users they were impersonated.
+func NewImpersonationService(impersonationRepo *postgres.ImpersonationRepository, userRepo *postgres.UserRepository, authService *AuthService, pushService *PushService, mailer mail.Sender) *ImpersonationService {
+	return &ImpersonationService{
+		impersonationRepo: impersonationRepo,
+		userRepo:          userRepo,
+		authService:       authService,
+		pushService:       pushService,
+		mailer:            mailer,
+	}
+}
+
+// Start records an impersonation session and returns a token acting as the user until it
+// expires. The token is read-only unless req asks for write access.
+func (s *ImpersonationService) Start(ctx context.Context, adminID uuid.UUID, req *domain.StartImpersonationRequest) (*domain.ImpersonationResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" || utf8.RuneCountInString(reason) > domain.MaxImpersonationReasonLength {
+		return nil, ErrImpersonationReason
+	}
+	user, err := s.userRepo.FindByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrImpersonationUser
+	}
+	// An admin's token would carry admin rights to whoever impersonates them
+	if user.IsAdmin {
+		return nil, ErrImpersonationTarget
+	}
+	if user.DeactivatedAt != nil {
+		return nil, ErrAccountDeactivated
+	}
+	admin, err := s.userRepo.FindByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if admin == nil {
+		return nil, ErrInvalidToken
+	}
+
+	now := time.Now().UTC()
+	imp := &domain.Impersonation{
+		ID:        uuid.New(),
+		AdminID:   adminID,
+		AdminName: admin.DisplayName,
+		UserID:    user.ID,
+		Reason:    reason,
+		ReadOnly:  !req.Write,
+		StartedAt: now,
+		ExpiresAt: now.Add(ImpersonationTTL),
+	}
+	if err := s.impersonationRepo.Create(ctx, imp); err != nil {
+		return nil, err
+	}
+	token, err := s.authService.IssueImpersonationToken(user, imp)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("AUDIT: admin %s started impersonating user %s (impersonation %s, read-only %t): %s", adminID, user.ID, imp.ID, imp.ReadOnly, reason)
+	s.notify(ctx, user, imp)
+	return &domain.ImpersonationResponse{Impersonation: imp, Token: token}, nil
+}
+
+// End ends an impersonation session early, so its tokens stop working
+func (s *ImpersonationService) End(ctx context.Context, adminID, id uuid.UUID) error {
+	ended, err := s.impersonationRepo.End(ctx, id, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !ended {
+		imp, err := s.impersonationRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if imp == nil {
+			return ErrImpersonationNotFound
+		}
+		return ErrImpersonationEnded
+	}
+	log.Printf("AUDIT: admin %s ended impersonation %s", adminID, id)
+	return nil
+}
+
+// List returns the audit log of impersonation sessions, newest first, for admins
+func (s *ImpersonationService) List(ctx context.Context, limit, offset int) ([]domain.Impersonation, int, error) {
+	return s.impersonationRepo.List(ctx, limit, offset)
+}
+
+// ListForUser returns the sessions admins impersonated a user in, newest first
+func (s *ImpersonationService) ListForUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Impersonation, int, error) {
+	return s.impersonationRepo.ListByUser(ctx, userID, limit, offset)
+}
+
+// notify tells a user an admin is acting as them, by push and by email
+func (s *ImpersonationService) notify(ctx context.Context, user *domain.User, imp *domain.Impersonation) {
+	locale := s.pushService.RecipientLocale(ctx, user.ID)
+	title := i18n.T(locale, "%s from support is accessing your account", imp.AdminName)
+	body := i18n.T(locale, "Reason: %s", imp.Reason)
+
+	if _, err := s.pushService.Notify(ctx, user.ID, &push.Notification{
+		Title: title,
+		Body:  body,
+		Data: map[string]string{
+			"type":            domain.PushImpersonation,
+			"impersonationId": imp.ID.String(),
+		},
+	}); err != nil {
+		log.Printf("WARN: Failed to push impersonation %s: %v", imp.ID, err)
+	}
+
+	if s.mailer == nil {
+		return
+	}
+	text := i18n.T(locale, "%s:\n\n%s\n\nYou can see every time your account was accessed in DevJournal.\n", title, body)
+	if err := s.mailer.Send(ctx, user.Email, title, text); err != nil {
+		log.Printf("WARN: Failed to email impersonation %s: %v", imp.ID, err)
+	}
+}
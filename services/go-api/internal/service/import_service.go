@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/importer"
+	"devjournal/internal/repository/postgres"
+	"devjournal/internal/tenant"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownImportSource = apperr.New(ErrValidation, "source must be notion, obsidian, or dayone")
+	ErrImportNotFound      = apperr.New(ErrNotFound, "import not found")
+	ErrEmptyImport         = apperr.New(ErrValidation, "upload an export file as the request body")
+)
+
+const (
+	// MaxImportSize caps an uploaded export
+	MaxImportSize = 50 << 20
+
+	importLease     = 2 * time.Minute
+	importPoll      = 10 * time.Second
+	importListLimit = 20
+)
+
+// ImportService imports exports from Notion, Obsidian, and Day One as journal entries. Uploads
+// are queued and imported by a background worker; clients poll an import for its progress.
+type ImportService struct {
+	importRepo  *postgres.ImportRepository
+	journalRepo *postgres.JournalRepository
+	wake        chan struct{}
+}
+
+// NewImportService creates a new import service
+func NewImportService(importRepo *postgres.ImportRepository, journalRepo *postgres.JournalRepository) *ImportService {
+	return &ImportService{importRepo: importRepo, journalRepo: journalRepo, wake: make(chan struct{}, 1)}
+}
+
+// Start queues an export for import into the active workspace
+func (s *ImportService) Start(ctx context.Context, userID uuid.UUID, source string, data []byte) (*domain.Import, error) {
+	if len(data) == 0 {
+		return nil, ErrEmptyImport
+	}
+	if err := importer.Check(source, data); err != nil {
+		if errors.Is(err, importer.ErrUnknownSource) {
+			return nil, ErrUnknownImportSource
+		}
+		return nil, apperr.New(ErrValidation, err.Error())
+	}
+
+	now := time.Now().UTC()
+	imp := &domain.Import{
+		ID:          uuid.New(),
+		UserID:      userID,
+		WorkspaceID: tenant.WorkspaceID(ctx, userID),
+		Source:      source,
+		Status:      domain.ImportPending,
+		Archive:     data,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.importRepo.Create(ctx, imp); err != nil {
+		return nil, fmt.Errorf("failed to create import: %w", err)
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return imp, nil
+}
+
+// Get returns one of the user's imports with its progress
+func (s *ImportService) Get(ctx context.Context, id, userID uuid.UUID) (*domain.Import, error) {
+	imp, err := s.importRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find import: %w", err)
+	}
+	if imp == nil {
+		return nil, ErrImportNotFound
+	}
+	return imp, nil
+}
+
+// List returns the user's recent imports, newest first
+func (s *ImportService) List(ctx context.Context, userID uuid.UUID) ([]domain.Import, error) {
+	imports, err := s.importRepo.ListByUser(ctx, userID, importListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list imports: %w", err)
+	}
+	return imports, nil
+}
+
+// Run imports queued exports one at a time until ctx is cancelled. Imports left running by a
+// stopped server are resumed once their lease runs out.
+func (s *ImportService) Run(ctx context.Context) {
+	ticker := time.NewTicker(importPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+			s.runQueued(ctx)
+		case <-ticker.C:
+			s.runQueued(ctx)
+		}
+	}
+}
+
+// runQueued imports every claimable import
+func (s *ImportService) runQueued(ctx context.Context) {
+	for ctx.Err() == nil {
+		imp, err := s.importRepo.ClaimNext(ctx, time.Now().UTC(), importLease)
+		if err != nil {
+			log.Printf("ERROR: Failed to claim import: %v", err)
+			return
+		}
+		if imp == nil {
+			return
+		}
+		s.process(ctx, imp)
+	}
+}
+
+// process imports the notes of a claimed import, skipping those a previous run already processed
+func (s *ImportService) process(ctx context.Context, imp *domain.Import) {
+	res, err := importer.Parse(imp.Source, imp.Archive)
+	if err != nil {
+		s.finish(ctx, imp, domain.ImportFailed, err.Error())
+		return
+	}
+	if imp.Processed == 0 {
+		if err := s.importRepo.SetTotal(ctx, imp.ID, len(res.Notes), res.Skipped); err != nil {
+			log.Printf("ERROR: Failed to start import %s: %v", imp.ID, err)
+			return
+		}
+	}
+
+	// Entries are created in the workspace the import was started from
+	ctx = tenant.WithWorkspace(ctx, imp.WorkspaceID)
+	for i := imp.Processed; i < len(res.Notes); i++ {
+		if ctx.Err() != nil {
+			// Picked up again when the lease runs out
+			return
+		}
+		imported, err := s.importNote(ctx, imp, i, &res.Notes[i])
+		if err != nil {
+			log.Printf("ERROR: Failed to import note %d of import %s: %v", i, imp.ID, err)
+			return
+		}
+		if err := s.importRepo.RecordProgress(ctx, imp.ID, imported, time.Now().UTC().Add(importLease)); err != nil {
+			log.Printf("ERROR: Failed to record progress of import %s: %v", imp.ID, err)
+			return
+		}
+	}
+	s.finish(ctx, imp, domain.ImportCompleted, "")
+}
+
+// importNote creates the journal entry for the i-th note of an import, reporting false for empty
+// notes. Entry IDs derive from the import and position, so a note whose progress wasn't recorded
+// before a crash isn't imported twice.
+func (s *ImportService) importNote(ctx context.Context, imp *domain.Import, i int, note *domain.ImportedNote) (bool, error) {
+	if note.Title == "" && note.Content == "" {
+		return false, nil
+	}
+
+	id := uuid.NewSHA1(imp.ID, []byte(strconv.Itoa(i)))
+	existing, err := s.journalRepo.FindByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		return true, nil
+	}
+
+	title := note.Title
+	if title == "" {
+		title = "Untitled"
+	}
+	entry := domain.NewJournalEntry(imp.UserID, title, note.Content, "", note.Tags)
+	entry.ID = id
+	if !note.CreatedAt.IsZero() {
+		entry.CreatedAt, entry.UpdatedAt = note.CreatedAt, note.UpdatedAt
+	}
+	if err := s.journalRepo.Create(ctx, entry); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *ImportService) finish(ctx context.Context, imp *domain.Import, status, errMsg string) {
+	if err := s.importRepo.Finish(ctx, imp.ID, status, errMsg); err != nil {
+		log.Printf("ERROR: Failed to finish import %s: %v", imp.ID, err)
+	}
+}
@@ -0,0 +1,494 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/integrations"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownIntegration     = apperr.New(ErrNotFound, "integration provider must be slack or discord")
+	ErrIntegrationUnavailable = apperr.New(ErrUnavailable, "this integration is not configured on the server")
+	ErrIntegrationNotFound    = apperr.New(ErrNotFound, "integration not found")
+	ErrNotIntegrationManager  = apperr.New(ErrForbidden, "only group owners and admins can manage integrations")
+	ErrInvalidInstallState    = apperr.New(ErrValidation, "invalid or expired install link; start again from the group")
+)
+
+const (
+	installStateTTL = 10 * time.Minute
+
+	// Delivery retry: attempts back off exponentially from deliveryBaseDelay, giving up after
+	// maxDeliveryAttempts (about two hours in total)
+	maxDeliveryAttempts = 8
+	deliveryBaseDelay   = 30 * time.Second
+	deliveryLease       = time.Minute
+	deliveryBatchSize   = 50
+	deliveryPoll        = 10 * time.Second
+
+	// mirrorQueueSize bounds chat messages waiting to be queued for delivery; beyond it messages are dropped
+	// rather than stalling the chat hub
+	mirrorQueueSize = 1024
+
+	// deliveryRetention is how long delivered and failed deliveries are kept for inspection
+	deliveryRetention = 7 * 24 * time.Hour
+)
+
+// ChatPublisher posts a message into a study group's live chat, running the same moderation
+// filters as messages typed in the app
+type ChatPublisher interface {
+	Publish(ctx context.Context, msg *domain.ChatMessage) error
+}
+
+// IntegrationConfig holds the Slack and Discord app settings integrations need
+type IntegrationConfig struct {
+	SlackClientID       string
+	SlackClientSecret   string
+	SlackSigningSecret  string
+	DiscordClientID     string
+	DiscordClientSecret string
+	DiscordPublicKey    string
+	CallbackURL         string // API base the OAuth callbacks live under, e.g. https://api.example.com/api/v1/integrations
+	ReturnURL           string // web app page users land on after installing
+	StateSecret         string // signs OAuth state so callbacks cannot be forged
+}
+
+// IntegrationService mirrors study group chat to Slack and Discord channels, and relays
+// channel messages back into groups that opt in
+type IntegrationService struct {
+	integrationRepo *postgres.IntegrationRepository
+	groupRepo       *postgres.StudyGroupRepository
+	slack           *integrations.Slack
+	discord         *integrations.Discord
+	publisher       ChatPublisher
+	cfg             IntegrationConfig
+
+	mirror chan *domain.ChatMessage
+	wake   chan struct{}
+}
+
+// NewIntegrationService creates a new integration service
+func NewIntegrationService(integrationRepo *postgres.IntegrationRepository, groupRepo *postgres.StudyGroupRepository, publisher ChatPublisher, cfg IntegrationConfig) *IntegrationService {
+	return &IntegrationService{
+		integrationRepo: integrationRepo,
+		groupRepo:       groupRepo,
+		slack:           integrations.NewSlack(cfg.SlackClientID, cfg.SlackClientSecret, cfg.SlackSigningSecret),
+		discord:         integrations.NewDiscord(cfg.DiscordClientID, cfg.DiscordClientSecret, cfg.DiscordPublicKey),
+		publisher:       publisher,
+		cfg:             cfg,
+		mirror:          make(chan *domain.ChatMessage, mirrorQueueSize),
+		wake:            make(chan struct{}, 1),
+	}
+}
+
+// Providers reports which integrations the server has app credentials for
+func (s *IntegrationService) Providers() map[string]bool {
+	return map[string]bool{
+		domain.IntegrationSlack:   s.slack.Configured(),
+		domain.IntegrationDiscord: s.discord.Configured(),
+	}
+}
+
+// List returns a group's integrations (group members only)
+func (s *IntegrationService) List(ctx context.Context, groupID, userID uuid.UUID) ([]domain.GroupIntegration, error) {
+	if _, err := groupRole(ctx, s.groupRepo, groupID, userID); err != nil {
+		return nil, err
+	}
+	return s.integrationRepo.ListByGroup(ctx, groupID)
+}
+
+// InstallURL returns the provider page where a group owner or admin picks the channel to connect
+func (s *IntegrationService) InstallURL(ctx context.Context, groupID, userID uuid.UUID, provider string) (string, error) {
+	if err := s.checkProvider(provider); err != nil {
+		return "", err
+	}
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return "", err
+	}
+
+	state := s.signState(groupID, userID, provider, time.Now().Add(installStateTTL))
+	if provider == domain.IntegrationSlack {
+		return s.slack.AuthorizeURL(state, s.redirectURI(provider)), nil
+	}
+	return s.discord.AuthorizeURL(state, s.redirectURI(provider)), nil
+}
+
+// CompleteInstall exchanges the OAuth code from the provider's redirect and connects the channel
+func (s *IntegrationService) CompleteInstall(ctx context.Context, provider, code, state string) (*domain.GroupIntegration, error) {
+	if err := s.checkProvider(provider); err != nil {
+		return nil, err
+	}
+	groupID, userID, err := s.verifyState(state, provider, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	// The installer may have lost their role while on the provider's page
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+
+	var install *integrations.Installation
+	if provider == domain.IntegrationSlack {
+		install, err = s.slack.Exchange(ctx, code, s.redirectURI(provider))
+	} else {
+		install, err = s.discord.Exchange(ctx, code, s.redirectURI(provider))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to install %s app: %w", provider, err)
+	}
+
+	now := time.Now().UTC()
+	integ := &domain.GroupIntegration{
+		ID:          uuid.New(),
+		GroupID:     groupID,
+		Provider:    provider,
+		TeamID:      install.TeamID,
+		ChannelID:   install.ChannelID,
+		ChannelName: install.ChannelName,
+		WebhookURL:  install.WebhookURL,
+		BotToken:    install.BotToken,
+		Enabled:     true,
+		CreatedBy:   userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.integrationRepo.Upsert(ctx, integ); err != nil {
+		return nil, err
+	}
+	return integ, nil
+}
+
+// ReturnURL is the web app page to send the installer back to
+func (s *IntegrationService) ReturnURL(provider string, err error) string {
+	status := "connected"
+	if err != nil {
+		status = "error"
+	}
+	sep := "?"
+	if strings.Contains(s.cfg.ReturnURL, "?") {
+		sep = "&"
+	}
+	return s.cfg.ReturnURL + sep + "integration=" + provider + "&status=" + status
+}
+
+// Update changes whether a group's integration is enabled and relays messages back
+func (s *IntegrationService) Update(ctx context.Context, groupID, userID uuid.UUID, provider string, req *domain.UpdateIntegrationRequest) (*domain.GroupIntegration, error) {
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	integ, err := s.integrationRepo.FindByGroup(ctx, groupID, provider)
+	if err != nil {
+		return nil, err
+	}
+	if integ == nil {
+		return nil, ErrIntegrationNotFound
+	}
+
+	if req.Enabled != nil {
+		integ.Enabled = *req.Enabled
+	}
+	if req.SyncInbound != nil {
+		integ.SyncInbound = *req.SyncInbound
+	}
+	integ.UpdatedAt = time.Now().UTC()
+	if err := s.integrationRepo.UpdateSettings(ctx, integ); err != nil {
+		return nil, err
+	}
+	return integ, nil
+}
+
+// Remove disconnects a group from a provider
+func (s *IntegrationService) Remove(ctx context.Context, groupID, userID uuid.UUID, provider string) error {
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return err
+	}
+	deleted, err := s.integrationRepo.Delete(ctx, groupID, provider)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrIntegrationNotFound
+	}
+	return nil
+}
+
+// Mirror queues a broadcast chat message for delivery to its group's channels. It never blocks,
+// so it is safe to call from the chat hub; messages are dropped if the queue is full.
+func (s *IntegrationService) Mirror(msg *domain.ChatMessage) {
+	if msg.Type != "message" {
+		return
+	}
+	select {
+	case s.mirror <- msg:
+	default:
+		log.Printf("WARN: Integration mirror queue full, dropping message %s", msg.ID)
+	}
+}
+
+// Run queues mirrored messages and delivers them, retrying failures with backoff, until ctx is cancelled
+func (s *IntegrationService) Run(ctx context.Context) {
+	ticker := time.NewTicker(deliveryPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-s.mirror:
+			s.enqueue(ctx, msg)
+		case <-s.wake:
+			s.deliverDue(ctx)
+		case <-ticker.C:
+			s.deliverDue(ctx)
+		}
+	}
+}
+
+// enqueue stores deliveries for a message so they survive restarts and can be retried
+func (s *IntegrationService) enqueue(ctx context.Context, msg *domain.ChatMessage) {
+	// Only the general channel is mirrored, as a connection links a group to one channel
+	groupID, channel, ok := domain.GroupRoom(msg.Room)
+	if !ok || channel != domain.DefaultChannel {
+		return
+	}
+	payloads := map[string][]byte{
+		domain.IntegrationSlack:   integrations.SlackMessage(msg.UserDisplayName, msg.Content),
+		domain.IntegrationDiscord: integrations.DiscordMessage(msg.UserDisplayName, msg.Content),
+	}
+	// Messages relayed from a channel are not echoed back to it
+	queued, err := s.integrationRepo.Enqueue(ctx, groupID, msg.ID, msg.Source, payloads)
+	if err != nil {
+		log.Printf("ERROR: Failed to queue message %s for integrations: %v", msg.ID, err)
+		return
+	}
+	if queued > 0 {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// deliverDue sends every delivery that is due, scheduling retries for failures
+func (s *IntegrationService) deliverDue(ctx context.Context) {
+	for {
+		deliveries, err := s.integrationRepo.ClaimDue(ctx, time.Now().UTC(), deliveryLease, deliveryBatchSize)
+		if err != nil {
+			log.Printf("ERROR: Failed to claim integration deliveries: %v", err)
+			return
+		}
+		for _, d := range deliveries {
+			s.deliver(ctx, &d)
+		}
+		if len(deliveries) < deliveryBatchSize {
+			return
+		}
+	}
+}
+
+// deliver posts one delivery and records the outcome
+func (s *IntegrationService) deliver(ctx context.Context, d *domain.IntegrationDelivery) {
+	sendErr := integrations.PostWebhook(ctx, d.WebhookURL, d.Payload)
+	if sendErr == nil {
+		if err := s.integrationRepo.MarkDelivered(ctx, d.ID); err != nil {
+			log.Printf("ERROR: %v", err)
+		}
+		return
+	}
+
+	var retryAt *time.Time
+	if attempt := d.Attempts + 1; attempt < maxDeliveryAttempts && !errors.Is(sendErr, integrations.ErrWebhookGone) {
+		next := time.Now().UTC().Add(deliveryBaseDelay << (attempt - 1))
+		retryAt = &next
+	} else {
+		log.Printf("WARN: Giving up on integration delivery %s after %d attempts: %v", d.ID, attempt, sendErr)
+	}
+	if err := s.integrationRepo.MarkAttemptFailed(ctx, d.ID, sendErr.Error(), retryAt); err != nil {
+		log.Printf("ERROR: %v", err)
+	}
+}
+
+// DeliveryCleaner returns a job that deletes finished deliveries after the retention period
+func (s *IntegrationService) DeliveryCleaner() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		deleted, err := s.integrationRepo.DeleteFinishedBefore(ctx, time.Now().Add(-deliveryRetention))
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			log.Printf("Deleted %d finished integration deliveries", deleted)
+		}
+		return nil
+	}
+}
+
+// HandleSlackEvent verifies and handles a Slack Events API request. For URL verification it
+// returns the challenge to echo back.
+func (s *IntegrationService) HandleSlackEvent(ctx context.Context, body []byte, timestamp, signature string) (string, error) {
+	if err := s.slack.VerifyRequest(body, timestamp, signature, time.Now()); err != nil {
+		return "", err
+	}
+	var envelope integrations.SlackEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return "", apperr.New(ErrValidation, "invalid event payload")
+	}
+	if envelope.Type == "url_verification" {
+		return envelope.Challenge, nil
+	}
+	if envelope.Type != "event_callback" || !envelope.Event.Relayable() {
+		return "", nil
+	}
+
+	targets, err := s.integrationRepo.ListInbound(ctx, domain.IntegrationSlack, envelope.Event.Channel)
+	if err != nil {
+		return "", err
+	}
+	for _, integ := range targets {
+		if integ.TeamID != envelope.TeamID {
+			continue
+		}
+		name, err := s.slack.UserName(ctx, integ.BotToken, envelope.Event.User)
+		if err != nil {
+			log.Printf("WARN: Failed to look up Slack user %s: %v", envelope.Event.User, err)
+			name = envelope.Event.User
+		}
+		s.relay(ctx, &integ, envelope.Event.User, name, integrations.SlackText(envelope.Event.Text))
+	}
+	return "", nil
+}
+
+// HandleDiscordInteraction verifies and handles a Discord interaction: pings and the slash
+// command members use to post into the group. It returns the interaction response.
+func (s *IntegrationService) HandleDiscordInteraction(ctx context.Context, body []byte, timestamp, signature string) (map[string]interface{}, error) {
+	if err := s.discord.VerifyRequest(body, timestamp, signature, time.Now()); err != nil {
+		return nil, err
+	}
+	var interaction integrations.DiscordInteraction
+	if err := json.Unmarshal(body, &interaction); err != nil {
+		return nil, apperr.New(ErrValidation, "invalid interaction payload")
+	}
+
+	switch {
+	case interaction.Type == integrations.DiscordPing:
+		return integrations.DiscordResponse(integrations.DiscordPong, "", false), nil
+	case interaction.Type != integrations.DiscordApplicationCommand || interaction.Data.Name != integrations.DiscordCommand:
+		return integrations.DiscordResponse(integrations.DiscordChannelResponse, "Unknown command.", true), nil
+	}
+
+	content := strings.TrimSpace(interaction.Option("message"))
+	if content == "" {
+		return integrations.DiscordResponse(integrations.DiscordChannelResponse, "Add a message to send.", true), nil
+	}
+	targets, err := s.integrationRepo.ListInbound(ctx, domain.IntegrationDiscord, interaction.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	sent := 0
+	for _, integ := range targets {
+		if integ.TeamID != interaction.GuildID {
+			continue
+		}
+		if err := s.relay(ctx, &integ, interaction.Member.User.ID, interaction.AuthorName(), content); err != nil {
+			return integrations.DiscordResponse(integrations.DiscordChannelResponse, err.Error(), true), nil
+		}
+		sent++
+	}
+	if sent == 0 {
+		return integrations.DiscordResponse(integrations.DiscordChannelResponse,
+			"This channel is not relaying messages to a DevJournal study group.", true), nil
+	}
+	// Visible to the channel, so the message shows up there as well as in the group
+	return integrations.DiscordResponse(integrations.DiscordChannelResponse,
+		fmt.Sprintf("**%s**: %s", interaction.AuthorName(), content), false), nil
+}
+
+// relay posts a channel message into the integration's study group
+func (s *IntegrationService) relay(ctx context.Context, integ *domain.GroupIntegration, externalUserID, name, content string) error {
+	msg := domain.NewChatMessage(integ.GroupID.String(), integ.Provider+":"+externalUserID, name, content, "message")
+	msg.Source = integ.Provider
+	if err := s.publisher.Publish(ctx, msg); err != nil {
+		log.Printf("WARN: Relayed %s message rejected for group %s: %v", integ.Provider, integ.GroupID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *IntegrationService) checkProvider(provider string) error {
+	switch provider {
+	case domain.IntegrationSlack, domain.IntegrationDiscord:
+	default:
+		return ErrUnknownIntegration
+	}
+	if !s.Providers()[provider] {
+		return ErrIntegrationUnavailable
+	}
+	return nil
+}
+
+func (s *IntegrationService) checkManager(ctx context.Context, groupID, userID uuid.UUID) error {
+	role, err := s.groupRepo.GetMemberRole(ctx, groupID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check group role: %w", err)
+	}
+	if !isGroupManager(role) {
+		return ErrNotIntegrationManager
+	}
+	return nil
+}
+
+func (s *IntegrationService) redirectURI(provider string) string {
+	return strings.TrimSuffix(s.cfg.CallbackURL, "/") + "/" + provider + "/callback"
+}
+
+// signState encodes "<group>:<user>:<provider>:<expiry>" with an HMAC so the OAuth callback can trust it
+func (s *IntegrationService) signState(groupID, userID uuid.UUID, provider string, expires time.Time) string {
+	payload := strings.Join([]string{groupID.String(), userID.String(), provider, strconv.FormatInt(expires.Unix(), 10)}, ":")
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + s.stateMAC(encoded)
+}
+
+// verifyState checks a state from signState and returns the group and user it was issued for
+func (s *IntegrationService) verifyState(state, provider string, now time.Time) (uuid.UUID, uuid.UUID, error) {
+	encoded, mac, ok := strings.Cut(state, ".")
+	if !ok || !hmac.Equal([]byte(mac), []byte(s.stateMAC(encoded))) {
+		return uuid.Nil, uuid.Nil, ErrInvalidInstallState
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrInvalidInstallState
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 4 || parts[2] != provider {
+		return uuid.Nil, uuid.Nil, ErrInvalidInstallState
+	}
+	expires, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil || now.Unix() > expires {
+		return uuid.Nil, uuid.Nil, ErrInvalidInstallState
+	}
+	groupID, err1 := uuid.Parse(parts[0])
+	userID, err2 := uuid.Parse(parts[1])
+	if err1 != nil || err2 != nil {
+		return uuid.Nil, uuid.Nil, ErrInvalidInstallState
+	}
+	return groupID, userID, nil
+}
+
+func (s *IntegrationService) stateMAC(encoded string) string {
+	mac := hmac.New(sha256.New, []byte("integration-state:"+s.cfg.StateSecret))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInviteNotFound = apperr.New(ErrNotFound, "invite not found")
+	ErrInviteMaxUses  = apperr.Newf(ErrValidation, "maxUses must be between 1 and %d", domain.MaxInviteUses)
+	ErrInviteNote     = apperr.Newf(ErrValidation, "note must be at most %d characters", domain.MaxInviteNoteLength)
+	ErrInviteExpired  = apperr.New(ErrValidation, "expiresAt must be in the future")
+	ErrInviteRequired = apperr.New(ErrForbidden, "an invite code is required to register")
+	ErrInvalidInvite  = apperr.New(ErrForbidden, "the invite code is invalid, expired, or used up")
+)
+
+// InviteService handles the invite codes admins create while registration is invite-only
+type InviteService struct {
+	inviteRepo *postgres.InviteRepository
+}
+
+// NewInviteService creates a new invite service
+func NewInviteService(inviteRepo *postgres.InviteRepository) *InviteService {
+	return &InviteService{inviteRepo: inviteRepo}
+}
+
+// Create creates an invite. Its code is only returned now; just a hash of it is stored.
+func (s *InviteService) Create(ctx context.Context, adminID uuid.UUID, req *domain.CreateInviteRequest) (*domain.Invite, error) {
+	now := time.Now().UTC()
+	invite := &domain.Invite{
+		ID:        uuid.New(),
+		Note:      strings.TrimSpace(req.Note),
+		MaxUses:   req.MaxUses,
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: adminID,
+		CreatedAt: now,
+	}
+	if invite.MaxUses == 0 {
+		invite.MaxUses = domain.DefaultInviteUses
+	}
+	if invite.MaxUses < 1 || invite.MaxUses > domain.MaxInviteUses {
+		return nil, ErrInviteMaxUses
+	}
+	if utf8.RuneCountInString(invite.Note) > domain.MaxInviteNoteLength {
+		return nil, ErrInviteNote
+	}
+	if invite.ExpiresAt != nil && !invite.ExpiresAt.After(now) {
+		return nil, ErrInviteExpired
+	}
+
+	code, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.inviteRepo.Create(ctx, invite, hashToken(code)); err != nil {
+		return nil, err
+	}
+	invite.Code = code
+	return invite, nil
+}
+
+// List returns every invite, newest first, with the total count
+func (s *InviteService) List(ctx context.Context, limit, offset int) ([]domain.Invite, int, error) {
+	return s.inviteRepo.List(ctx, limit, offset)
+}
+
+// Delete revokes an invite, so its code can't be used anymore
+func (s *InviteService) Delete(ctx context.Context, id uuid.UUID) error {
+	deleted, err := s.inviteRepo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrInviteNotFound
+	}
+	return nil
+}
+
+// Redeem uses up one use of an invite code for a registration, returning the invite's ID for
+// Release if the registration fails
+func (s *InviteService) Redeem(ctx context.Context, code string) (uuid.UUID, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return uuid.Nil, ErrInviteRequired
+	}
+	id, err := s.inviteRepo.Redeem(ctx, hashToken(code), time.Now().UTC())
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if id == uuid.Nil {
+		return uuid.Nil, ErrInvalidInvite
+	}
+	return id, nil
+}
+
+// Release gives back the use Redeem took for a registration that failed. Failures are logged,
+// since the registration already failed.
+func (s *InviteService) Release(ctx context.Context, id uuid.UUID) {
+	if err := s.inviteRepo.Release(ctx, id); err != nil {
+		log.Printf("WARN: Failed to release invite %s: %v", id, err)
+	}
+}
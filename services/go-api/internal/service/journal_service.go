@@ -2,32 +2,70 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"log"
 	"time"
 
 	"devjournal/internal/domain"
+	"devjournal/internal/policy"
 	"devjournal/internal/repository/postgres"
+	"devjournal/internal/vault"
+	"devjournal/pkg/apperr"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidContentFormat = apperr.New(ErrValidation, "contentFormat must be plain or e2ee")
+	ErrInvalidEncryption    = apperr.New(ErrValidation, "e2ee entries need base64 ciphertext content and encryption algorithm, keyId, and nonce")
+	ErrEntryNotFound        = apperr.New(ErrNotFound, "journal entry not found")
+	ErrPublicEncryptedEntry = apperr.New(ErrValidation, "e2ee entries cannot be public")
+	ErrPublicVaultEntry     = apperr.New(ErrValidation, "vault entries cannot be public")
+	ErrVaultLocked          = apperr.New(ErrForbidden, "vault entry requires a vault session; verify your password to open it")
+)
+
 // JournalService handles journal entry business logic
 type JournalService struct {
-	journalRepo *postgres.JournalRepository
+	journalRepo    *postgres.JournalRepository
+	mentionService *MentionService
+	publishers     []func(context.Context, *domain.JournalEntry)
+}
+
+// NewJournalService creates a new journal service. A nil mention service skips @mentions.
+func NewJournalService(journalRepo *postgres.JournalRepository, mentionService *MentionService) *JournalService {
+	return &JournalService{journalRepo: journalRepo, mentionService: mentionService}
 }
 
-// NewJournalService creates a new journal service
-func NewJournalService(journalRepo *postgres.JournalRepository) *JournalService {
-	return &JournalService{journalRepo: journalRepo}
+// OnPublish registers fn to be called with every entry that becomes public, whether created
+// public or made public later. fn runs in the request and must not block. Register listeners
+// before serving requests.
+func (s *JournalService) OnPublish(fn func(context.Context, *domain.JournalEntry)) {
+	s.publishers = append(s.publishers, fn)
 }
 
 // Create creates a new journal entry
 func (s *JournalService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateJournalEntryRequest) (*domain.JournalEntry, error) {
 	entry := domain.NewJournalEntry(userID, req.Title, req.Content, req.Mood, req.Tags)
+	if err := applyContentFormat(entry, req.ContentFormat, req.Encryption); err != nil {
+		return nil, err
+	}
+	entry.IsPublic = req.IsPublic
+	entry.IsVault = req.IsVault
+	if entry.IsPublic && entry.ContentFormat == domain.ContentFormatE2EE {
+		return nil, ErrPublicEncryptedEntry
+	}
+	if entry.IsPublic && entry.IsVault {
+		return nil, ErrPublicVaultEntry
+	}
 
 	if err := s.journalRepo.Create(ctx, entry); err != nil {
 		return nil, fmt.Errorf("failed to create journal entry: %w", err)
 	}
+	s.recordMentions(ctx, entry)
+	if entry.IsPublic {
+		s.published(ctx, entry)
+	}
 
 	return entry, nil
 }
@@ -38,12 +76,11 @@ func (s *JournalService) GetByID(ctx context.Context, id, userID uuid.UUID) (*do
 	if err != nil {
 		return nil, fmt.Errorf("failed to find journal entry: %w", err)
 	}
-	if entry == nil {
-		return nil, nil
-	}
-	// Verify ownership
-	if entry.UserID != userID {
+	switch policy.Authorize(policy.User(ctx, userID), policy.Read, entry) {
+	case policy.Hide:
 		return nil, nil
+	case policy.StepUp:
+		return nil, ErrVaultLocked
 	}
 	return entry, nil
 }
@@ -53,8 +90,8 @@ func (s *JournalService) List(ctx context.Context, userID uuid.UUID, limit, offs
 	if limit <= 0 {
 		limit = 20
 	}
-	if limit > 100 {
-		limit = 100
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
 	}
 
 	entries, err := s.journalRepo.FindByUserID(ctx, userID, limit, offset)
@@ -67,6 +104,7 @@ func (s *JournalService) List(ctx context.Context, userID uuid.UUID, limit, offs
 		return nil, 0, fmt.Errorf("failed to count journal entries: %w", err)
 	}
 
+	sealVault(ctx, entries)
 	return entries, total, nil
 }
 
@@ -81,32 +119,107 @@ func (s *JournalService) ListByMood(ctx context.Context, userID uuid.UUID, mood
 		return nil, fmt.Errorf("failed to list journal entries by mood: %w", err)
 	}
 
+	sealVault(ctx, entries)
 	return entries, nil
 }
 
+// ListByTags retrieves journal entries matching any or all of the given tags
+func (s *JournalService) ListByTags(ctx context.Context, userID uuid.UUID, tags []string, match string, limit, offset int) ([]domain.JournalEntry, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
+	}
+	if match != domain.TagMatchAll {
+		match = domain.TagMatchAny
+	}
+
+	entries, err := s.journalRepo.FindByTags(ctx, userID, tags, match, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list journal entries by tags: %w", err)
+	}
+
+	total, err := s.journalRepo.CountByTags(ctx, userID, tags, match)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count journal entries by tags: %w", err)
+	}
+
+	sealVault(ctx, entries)
+	return entries, total, nil
+}
+
+// ListUntagged retrieves journal entries that have no tags
+func (s *JournalService) ListUntagged(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.JournalEntry, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
+	}
+
+	entries, err := s.journalRepo.FindUntagged(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list untagged journal entries: %w", err)
+	}
+
+	total, err := s.journalRepo.CountUntagged(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count untagged journal entries: %w", err)
+	}
+
+	sealVault(ctx, entries)
+	return entries, total, nil
+}
+
+// ListWithoutMood retrieves journal entries that have no mood
+func (s *JournalService) ListWithoutMood(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.JournalEntry, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
+	}
+
+	entries, err := s.journalRepo.FindWithoutMood(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list journal entries without mood: %w", err)
+	}
+
+	total, err := s.journalRepo.CountWithoutMood(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count journal entries without mood: %w", err)
+	}
+
+	sealVault(ctx, entries)
+	return entries, total, nil
+}
+
 // Search searches journal entries by title or content
 func (s *JournalService) Search(ctx context.Context, userID uuid.UUID, searchTerm string, limit, offset int) ([]domain.JournalEntry, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 
-	entries, err := s.journalRepo.Search(ctx, userID, searchTerm, limit, offset)
+	// A match on a sealed entry would reveal its content, so vault entries only match when unlocked
+	entries, err := s.journalRepo.Search(ctx, userID, searchTerm, vault.Unlocked(ctx), limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search journal entries: %w", err)
 	}
-
 	return entries, nil
 }
 
 // Update updates an existing journal entry
 func (s *JournalService) Update(ctx context.Context, id, userID uuid.UUID, req *domain.UpdateJournalEntryRequest) (*domain.JournalEntry, error) {
-	// Verify entry exists and belongs to user
 	existing, err := s.journalRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find journal entry: %w", err)
 	}
-	if existing == nil || existing.UserID != userID {
-		return nil, fmt.Errorf("journal entry not found")
+	switch policy.Authorize(policy.User(ctx, userID), policy.Update, existing) {
+	case policy.Hide:
+		return nil, ErrEntryNotFound
+	case policy.StepUp:
+		return nil, ErrVaultLocked
 	}
 
 	// Update fields
@@ -114,17 +227,157 @@ func (s *JournalService) Update(ctx context.Context, id, userID uuid.UUID, req *
 	existing.Content = req.Content
 	existing.Mood = req.Mood
 	existing.Tags = req.Tags
+	existing.WordCount = domain.CountWords(req.Content)
 	existing.UpdatedAt = time.Now().UTC()
 
+	// An omitted format keeps the entry's current one, and its metadata unless new metadata is sent
+	format, encryption := req.ContentFormat, req.Encryption
+	if format == "" {
+		format = existing.ContentFormat
+	}
+	if encryption == nil && format == existing.ContentFormat {
+		encryption = existing.Encryption
+	}
+	if err := applyContentFormat(existing, format, encryption); err != nil {
+		return nil, err
+	}
+	wasPublic := existing.IsPublic
+	if req.IsPublic != nil {
+		existing.IsPublic = *req.IsPublic
+	}
+	if req.IsVault != nil {
+		existing.IsVault = *req.IsVault
+	}
+	if existing.IsPublic && existing.ContentFormat == domain.ContentFormatE2EE {
+		return nil, ErrPublicEncryptedEntry
+	}
+	if existing.IsPublic && existing.IsVault {
+		return nil, ErrPublicVaultEntry
+	}
+
 	if err := s.journalRepo.Update(ctx, existing); err != nil {
 		return nil, fmt.Errorf("failed to update journal entry: %w", err)
 	}
+	s.recordMentions(ctx, existing)
+	if existing.IsPublic && !wasPublic {
+		s.published(ctx, existing)
+	}
 
 	return existing, nil
 }
 
+// recordMentions records @mentions in a saved entry. The entry is already saved, so failures are only logged.
+// Vault entries are skipped, since a mention would show an excerpt to the mentioned user.
+func (s *JournalService) recordMentions(ctx context.Context, entry *domain.JournalEntry) {
+	if s.mentionService == nil || entry.IsVault {
+		return
+	}
+	if err := s.mentionService.FromEntry(ctx, entry); err != nil {
+		log.Printf("WARN: Failed to record mentions in entry %s: %v", entry.ID, err)
+	}
+}
+
+// published tells the OnPublish listeners about an entry that just became public
+func (s *JournalService) published(ctx context.Context, entry *domain.JournalEntry) {
+	for _, fn := range s.publishers {
+		fn(ctx, entry)
+	}
+}
+
+// sealVault withholds the content of vault entries unless the request has a vault session
+func sealVault(ctx context.Context, entries []domain.JournalEntry) {
+	if vault.Unlocked(ctx) {
+		return
+	}
+	for i := range entries {
+		if entries[i].IsVault {
+			entries[i].Seal()
+		}
+	}
+}
+
+// applyContentFormat validates and sets an entry's content format. The server cannot
+// count words in ciphertext, so e2ee entries don't contribute to writing stats.
+func applyContentFormat(entry *domain.JournalEntry, format string, encryption *domain.EncryptionMetadata) error {
+	switch format {
+	case "", domain.ContentFormatPlain:
+		entry.ContentFormat = domain.ContentFormatPlain
+		entry.Encryption = nil
+		return nil
+	case domain.ContentFormatE2EE:
+	default:
+		return ErrInvalidContentFormat
+	}
+
+	if encryption == nil || encryption.Algorithm == "" || encryption.KeyID == "" || encryption.Nonce == "" {
+		return ErrInvalidEncryption
+	}
+	if _, err := base64.StdEncoding.DecodeString(entry.Content); err != nil {
+		return ErrInvalidEncryption
+	}
+
+	entry.ContentFormat = domain.ContentFormatE2EE
+	entry.Encryption = encryption
+	entry.WordCount = 0
+	return nil
+}
+
+// Export returns every journal entry for a user, including encrypted entries as opaque blobs
+func (s *JournalService) Export(ctx context.Context, userID uuid.UUID) (*domain.JournalExport, error) {
+	entries, err := s.journalRepo.FindAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export journal entries: %w", err)
+	}
+	if entries == nil {
+		entries = []domain.JournalEntry{}
+	}
+
+	sealVault(ctx, entries)
+
+	export := &domain.JournalExport{
+		ExportedAt: time.Now().UTC(),
+		EntryCount: len(entries),
+		Entries:    entries,
+	}
+	for _, entry := range entries {
+		if entry.ContentFormat == domain.ContentFormatE2EE {
+			export.EncryptedCount++
+		}
+	}
+	return export, nil
+}
+
+// GetWritingStats returns word count totals, average entry length, and weekly word trends
+func (s *JournalService) GetWritingStats(ctx context.Context, userID uuid.UUID, weeks int) (*domain.WritingStats, error) {
+	if weeks <= 0 {
+		weeks = 12
+	}
+	if weeks > 52 {
+		weeks = 52
+	}
+
+	stats, err := s.journalRepo.GetWritingStats(ctx, userID, weeks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get writing stats: %w", err)
+	}
+	if stats.TotalEntries > 0 {
+		stats.AverageEntryLength = stats.TotalWords / stats.TotalEntries
+	}
+
+	return stats, nil
+}
+
 // Delete removes a journal entry
 func (s *JournalService) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	if !vault.Unlocked(ctx) {
+		entry, err := s.journalRepo.FindByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to find journal entry: %w", err)
+		}
+		if policy.Authorize(policy.User(ctx, userID), policy.Delete, entry) == policy.StepUp {
+			return ErrVaultLocked
+		}
+	}
 	if err := s.journalRepo.Delete(ctx, id, userID); err != nil {
 		return fmt.Errorf("failed to delete journal entry: %w", err)
 	}
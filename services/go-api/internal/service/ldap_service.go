@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/ldap"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var ErrLDAPNoEmail = apperr.New(ErrForbidden, "your directory entry has no email address; ask an admin to add one")
+
+// LDAPConfig holds the LDAP directory users sign in against
+type LDAPConfig struct {
+	URL            string
+	StartTLS       bool
+	BindDN         string
+	BindPassword   string
+	SearchBase     string
+	UserFilter     string // {username} is replaced by what the user typed
+	EmailAttribute string
+	NameAttribute  string
+}
+
+// LDAPService checks passwords against an LDAP directory, mapping directory users to local users:
+// by the identity linked at an earlier login, else by email, else by creating one
+type LDAPService struct {
+	identityRepo *postgres.IdentityRepository
+	userRepo     *postgres.UserRepository
+	authService  *AuthService
+	directory    *ldap.Directory
+	issuer       string // identities are recorded under the directory's URL
+}
+
+// NewLDAPService creates a new LDAP service
+func NewLDAPService(identityRepo *postgres.IdentityRepository, userRepo *postgres.UserRepository, authService *AuthService, cfg LDAPConfig) *LDAPService {
+	return &LDAPService{
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		directory: ldap.New(ldap.Config{
+			URL:            cfg.URL,
+			StartTLS:       cfg.StartTLS,
+			BindDN:         cfg.BindDN,
+			BindPassword:   cfg.BindPassword,
+			SearchBase:     cfg.SearchBase,
+			UserFilter:     cfg.UserFilter,
+			EmailAttribute: cfg.EmailAttribute,
+			NameAttribute:  cfg.NameAttribute,
+		}),
+		issuer: strings.TrimSuffix(cfg.URL, "/"),
+	}
+}
+
+// Configured reports whether a directory URL and search base are set
+func (s *LDAPService) Configured() bool {
+	return s.directory.Configured()
+}
+
+// Authenticate checks a username and password against the directory and returns the local user
+// they map to, creating it at their first login
+func (s *LDAPService) Authenticate(ctx context.Context, username, password string) (*domain.User, error) {
+	entry, err := s.directory.Authenticate(ctx, username, password)
+	switch {
+	case errors.Is(err, ldap.ErrUserNotFound):
+		return nil, ErrNotInDirectory
+	case errors.Is(err, ldap.ErrInvalidCredentials):
+		return nil, ErrInvalidCredentials
+	case err != nil:
+		return nil, fmt.Errorf("failed to check directory: %w", err)
+	}
+
+	user, err := s.resolveUser(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if err := s.identityRepo.Link(ctx, &domain.UserIdentity{
+		UserID:      user.ID,
+		Issuer:      s.issuer,
+		Subject:     entry.DN,
+		Email:       entry.Email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Verify re-checks the password of a user who signs in through the directory
+func (s *LDAPService) Verify(ctx context.Context, userID uuid.UUID, password string) error {
+	dn, err := s.identityRepo.FindSubject(ctx, userID, s.issuer)
+	if err != nil {
+		return err
+	}
+	if dn == "" {
+		return ErrNotInDirectory
+	}
+	err = s.directory.Verify(ctx, dn, password)
+	switch {
+	case errors.Is(err, ldap.ErrInvalidCredentials):
+		return ErrWrongPassword
+	case err != nil:
+		return fmt.Errorf("failed to check directory: %w", err)
+	}
+	return nil
+}
+
+// resolveUser finds or creates the local user a directory entry maps to
+func (s *LDAPService) resolveUser(ctx context.Context, entry *ldap.Entry) (*domain.User, error) {
+	userID, err := s.identityRepo.FindUserID(ctx, s.issuer, entry.DN)
+	if err != nil {
+		return nil, err
+	}
+	if userID != uuid.Nil {
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+		if user != nil {
+			return user, nil
+		}
+	}
+
+	if entry.Email == "" {
+		return nil, ErrLDAPNoEmail
+	}
+	// The directory is the source of truth for who owns an email
+	existing, err := s.userRepo.FindByEmail(ctx, entry.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	displayName := entry.Name
+	if displayName == "" {
+		displayName, _, _ = strings.Cut(entry.Email, "@")
+	}
+	return s.authService.Provision(ctx, entry.Email, displayName)
+}
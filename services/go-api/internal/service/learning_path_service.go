@@ -0,0 +1,442 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/policy"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+// dashboardPaths caps the learning paths shown in the progress summary
+const dashboardPaths = 10
+
+var (
+	ErrLearningPathNotFound = apperr.New(ErrNotFound, "learning path not found")
+	ErrLearningPathNotOwned = apperr.New(ErrForbidden, "only the learning path's owner can change it")
+	ErrLearningPathTitle    = apperr.New(ErrValidation, "title is required and must be at most 100 characters")
+	ErrPathTopicTitle       = apperr.New(ErrValidation, "topic title is required and must be at most 200 characters")
+	ErrTooManyPathTopics    = apperr.New(ErrValidation, fmt.Sprintf("a learning path can have at most %d topics", domain.MaxPathTopics))
+	ErrPathTopicNotFound    = apperr.New(ErrNotFound, "topic not found")
+	ErrPathTopicOrder       = apperr.New(ErrValidation, "topicIds must list every topic of the path exactly once")
+	ErrEvidenceNotLinked    = apperr.New(ErrNotFound, "evidence is not linked to this topic")
+)
+
+// LearningPathService handles learning paths, their topics, and each user's progress through them
+type LearningPathService struct {
+	pathRepo    *postgres.LearningPathRepository
+	groupRepo   *postgres.StudyGroupRepository
+	journalRepo *postgres.JournalRepository
+	snippetRepo *mongodb.SnippetRepository
+}
+
+// NewLearningPathService creates a new learning path service
+func NewLearningPathService(pathRepo *postgres.LearningPathRepository, groupRepo *postgres.StudyGroupRepository, journalRepo *postgres.JournalRepository, snippetRepo *mongodb.SnippetRepository) *LearningPathService {
+	return &LearningPathService{pathRepo: pathRepo, groupRepo: groupRepo, journalRepo: journalRepo, snippetRepo: snippetRepo}
+}
+
+// Create creates a new learning path with its initial topics
+func (s *LearningPathService) Create(ctx context.Context, userID uuid.UUID, req *domain.LearningPathRequest) (*domain.LearningPath, error) {
+	path := domain.NewLearningPath(userID, strings.TrimSpace(req.Title), req.Description)
+	if err := validateLearningPath(path); err != nil {
+		return nil, err
+	}
+	if len(req.Topics) > domain.MaxPathTopics {
+		return nil, ErrTooManyPathTopics
+	}
+
+	path.Topics = make([]domain.PathTopic, len(req.Topics))
+	for i, t := range req.Topics {
+		topic := domain.PathTopic{ID: uuid.New(), PathID: path.ID, Position: i, Title: strings.TrimSpace(t.Title), Description: t.Description, Evidence: []domain.PathEvidence{}}
+		if err := validatePathTopic(&topic); err != nil {
+			return nil, err
+		}
+		path.Topics[i] = topic
+	}
+	if err := s.pathRepo.Create(ctx, path); err != nil {
+		return nil, err
+	}
+	path.Progress = domain.NewPathProgress(0, len(path.Topics))
+	return path, nil
+}
+
+// Get returns a learning path with its topics and the user's completion and evidence
+func (s *LearningPathService) Get(ctx context.Context, userID, id uuid.UUID) (*domain.LearningPath, error) {
+	path, err := s.view(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if path.Topics, err = s.pathRepo.Topics(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	evidence, err := s.evidence(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range path.Topics {
+		if e, ok := evidence[path.Topics[i].ID]; ok {
+			path.Topics[i].Evidence = e
+		}
+	}
+	return path, nil
+}
+
+// List returns the paths the user owns or can follow through their study groups
+func (s *LearningPathService) List(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.LearningPath, int, error) {
+	return s.pathRepo.ListAccessible(ctx, userID, limit, offset)
+}
+
+// Followed returns the paths the user owns or has started, for the progress summary
+func (s *LearningPathService) Followed(ctx context.Context, userID uuid.UUID) ([]domain.LearningPath, error) {
+	return s.pathRepo.ListFollowed(ctx, userID, dashboardPaths)
+}
+
+// ListByGroup returns the paths shared into one of the user's study groups
+func (s *LearningPathService) ListByGroup(ctx context.Context, userID, groupID uuid.UUID) ([]domain.LearningPath, error) {
+	if err := s.checkMember(ctx, userID, groupID); err != nil {
+		return nil, err
+	}
+	return s.pathRepo.ListByGroup(ctx, groupID, userID)
+}
+
+// Update changes a path's title and description
+func (s *LearningPathService) Update(ctx context.Context, userID, id uuid.UUID, req *domain.LearningPathRequest) (*domain.LearningPath, error) {
+	path, err := s.own(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	path.Title = strings.TrimSpace(req.Title)
+	path.Description = req.Description
+	if err := validateLearningPath(path); err != nil {
+		return nil, err
+	}
+	path.UpdatedAt = time.Now().UTC()
+	if err := s.pathRepo.Update(ctx, path); err != nil {
+		return nil, err
+	}
+	return path, nil
+}
+
+// Delete removes a path along with everyone's progress on it
+func (s *LearningPathService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.own(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.pathRepo.Delete(ctx, id, userID)
+}
+
+// Share shares a path into one of the owner's study groups so its members can follow it.
+// A path is shared into at most one group; sharing again moves it.
+func (s *LearningPathService) Share(ctx context.Context, userID, id, groupID uuid.UUID) (*domain.LearningPath, error) {
+	path, err := s.own(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMember(ctx, userID, groupID); err != nil {
+		return nil, err
+	}
+	if err := s.pathRepo.SetGroup(ctx, id, &groupID); err != nil {
+		return nil, err
+	}
+	path.GroupID = &groupID
+	return path, nil
+}
+
+// Unshare stops sharing a path. Members keep their progress in case it is shared again.
+func (s *LearningPathService) Unshare(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.own(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.pathRepo.SetGroup(ctx, id, nil)
+}
+
+// AddTopic appends a topic to a path
+func (s *LearningPathService) AddTopic(ctx context.Context, userID, id uuid.UUID, req *domain.TopicRequest) (*domain.PathTopic, error) {
+	if _, err := s.own(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	topic := &domain.PathTopic{ID: uuid.New(), PathID: id, Title: strings.TrimSpace(req.Title), Description: req.Description, Evidence: []domain.PathEvidence{}}
+	if err := validatePathTopic(topic); err != nil {
+		return nil, err
+	}
+	count, err := s.pathRepo.CountTopics(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxPathTopics {
+		return nil, ErrTooManyPathTopics
+	}
+	if err := s.pathRepo.AddTopic(ctx, topic); err != nil {
+		return nil, err
+	}
+	return topic, s.pathRepo.Touch(ctx, id)
+}
+
+// UpdateTopic changes a topic's title and description
+func (s *LearningPathService) UpdateTopic(ctx context.Context, userID, id, topicID uuid.UUID, req *domain.TopicRequest) (*domain.PathTopic, error) {
+	if _, err := s.own(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	topic, err := s.findTopic(ctx, id, topicID)
+	if err != nil {
+		return nil, err
+	}
+	topic.Title = strings.TrimSpace(req.Title)
+	topic.Description = req.Description
+	if err := validatePathTopic(topic); err != nil {
+		return nil, err
+	}
+	if err := s.pathRepo.UpdateTopic(ctx, topic); err != nil {
+		return nil, err
+	}
+	return topic, s.pathRepo.Touch(ctx, id)
+}
+
+// DeleteTopic removes a topic along with everyone's progress on it
+func (s *LearningPathService) DeleteTopic(ctx context.Context, userID, id, topicID uuid.UUID) error {
+	if _, err := s.own(ctx, userID, id); err != nil {
+		return err
+	}
+	if _, err := s.findTopic(ctx, id, topicID); err != nil {
+		return err
+	}
+	if err := s.pathRepo.DeleteTopic(ctx, topicID, id); err != nil {
+		return err
+	}
+	return s.pathRepo.Touch(ctx, id)
+}
+
+// ReorderTopics puts a path's topics in the given order
+func (s *LearningPathService) ReorderTopics(ctx context.Context, userID, id uuid.UUID, topicIDs []uuid.UUID) (*domain.LearningPath, error) {
+	if _, err := s.own(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	topics, err := s.pathRepo.Topics(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(topicIDs) != len(topics) {
+		return nil, ErrPathTopicOrder
+	}
+	seen := make(map[uuid.UUID]bool, len(topics))
+	for _, t := range topics {
+		seen[t.ID] = false
+	}
+	for _, topicID := range topicIDs {
+		if listed, ok := seen[topicID]; !ok || listed {
+			return nil, ErrPathTopicOrder
+		}
+		seen[topicID] = true
+	}
+
+	if err := s.pathRepo.ReorderTopics(ctx, id, topicIDs); err != nil {
+		return nil, err
+	}
+	if err := s.pathRepo.Touch(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, id)
+}
+
+// SetCompleted marks a topic completed by the user, or reopens it
+func (s *LearningPathService) SetCompleted(ctx context.Context, userID, id, topicID uuid.UUID, completed bool) (*domain.LearningPath, error) {
+	if _, err := s.view(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if _, err := s.findTopic(ctx, id, topicID); err != nil {
+		return nil, err
+	}
+	var err error
+	if completed {
+		err = s.pathRepo.Complete(ctx, topicID, userID, time.Now().UTC())
+	} else {
+		err = s.pathRepo.Reopen(ctx, topicID, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, id)
+}
+
+// LinkEvidence links one of the user's entries or snippets to a topic and marks the topic
+// completed. Unlinking evidence later leaves the topic completed.
+func (s *LearningPathService) LinkEvidence(ctx context.Context, userID, id, topicID uuid.UUID, evidenceType, refID string) (*domain.LearningPath, error) {
+	if _, err := s.view(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if _, err := s.findTopic(ctx, id, topicID); err != nil {
+		return nil, err
+	}
+	if err := s.checkEvidenceOwner(ctx, userID, evidenceType, refID); err != nil {
+		return nil, err
+	}
+
+	if err := s.pathRepo.LinkEvidence(ctx, topicID, userID, evidenceType, refID); err != nil {
+		return nil, err
+	}
+	if err := s.pathRepo.Complete(ctx, topicID, userID, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, id)
+}
+
+// UnlinkEvidence removes an entry or snippet from a topic's evidence
+func (s *LearningPathService) UnlinkEvidence(ctx context.Context, userID, id, topicID uuid.UUID, evidenceType, refID string) (*domain.LearningPath, error) {
+	if _, err := s.view(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	removed, err := s.pathRepo.UnlinkEvidence(ctx, topicID, userID, evidenceType, refID)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, ErrEvidenceNotLinked
+	}
+	return s.Get(ctx, userID, id)
+}
+
+// evidence returns the user's evidence for a path's topics, titling snippets and leaving out
+// the ones that were deleted
+func (s *LearningPathService) evidence(ctx context.Context, userID, id uuid.UUID) (map[uuid.UUID][]domain.PathEvidence, error) {
+	evidence, err := s.pathRepo.Evidence(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var snippetIDs []string
+	for _, items := range evidence {
+		for _, e := range items {
+			if e.Type == domain.EvidenceSnippet {
+				snippetIDs = append(snippetIDs, e.ID)
+			}
+		}
+	}
+	if len(snippetIDs) == 0 {
+		return evidence, nil
+	}
+	snippets, err := s.snippetRepo.FindByIDs(ctx, userID.String(), snippetIDs)
+	if err != nil {
+		return nil, err
+	}
+	titles := make(map[string]string, len(snippets))
+	for _, snippet := range snippets {
+		titles[snippet.ID] = snippet.Title
+	}
+
+	for topicID, items := range evidence {
+		kept := items[:0]
+		for _, e := range items {
+			if e.Type == domain.EvidenceSnippet {
+				title, ok := titles[e.ID]
+				if !ok {
+					continue
+				}
+				e.Title = title
+			}
+			kept = append(kept, e)
+		}
+		evidence[topicID] = kept
+	}
+	return evidence, nil
+}
+
+// checkEvidenceOwner returns a not found error unless the entry or snippet belongs to the user
+func (s *LearningPathService) checkEvidenceOwner(ctx context.Context, userID uuid.UUID, evidenceType, refID string) error {
+	if evidenceType == domain.EvidenceSnippet {
+		snippet, err := s.snippetRepo.FindByID(ctx, refID)
+		if err != nil {
+			return fmt.Errorf("failed to find snippet: %w", err)
+		}
+		return checkSnippetOwner(snippet, userID.String())
+	}
+
+	entryID, err := uuid.Parse(refID)
+	if err != nil {
+		return ErrEntryNotFound
+	}
+	entry, err := s.journalRepo.FindByID(ctx, entryID)
+	if err != nil {
+		return fmt.Errorf("failed to find journal entry: %w", err)
+	}
+	if policy.Authorize(policy.User(ctx, userID), policy.Link, entry) != policy.Allow {
+		return ErrEntryNotFound
+	}
+	return nil
+}
+
+// view returns a path the user owns or can follow through the study group it is shared into
+func (s *LearningPathService) view(ctx context.Context, userID, id uuid.UUID) (*domain.LearningPath, error) {
+	path, err := s.pathRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if path == nil {
+		return nil, ErrLearningPathNotFound
+	}
+	if path.UserID == userID {
+		return path, nil
+	}
+	if path.GroupID != nil {
+		member, err := s.groupRepo.IsMember(ctx, *path.GroupID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if member {
+			return path, nil
+		}
+	}
+	return nil, ErrLearningPathNotFound
+}
+
+// own returns a path the user can change
+func (s *LearningPathService) own(ctx context.Context, userID, id uuid.UUID) (*domain.LearningPath, error) {
+	path, err := s.view(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if path.UserID != userID {
+		return nil, ErrLearningPathNotOwned
+	}
+	return path, nil
+}
+
+// findTopic returns a topic of the path, or ErrPathTopicNotFound
+func (s *LearningPathService) findTopic(ctx context.Context, id, topicID uuid.UUID) (*domain.PathTopic, error) {
+	topic, err := s.pathRepo.FindTopic(ctx, topicID, id)
+	if err != nil {
+		return nil, err
+	}
+	if topic == nil {
+		return nil, ErrPathTopicNotFound
+	}
+	return topic, nil
+}
+
+// checkMember returns an error unless the study group exists and the user belongs to it
+func (s *LearningPathService) checkMember(ctx context.Context, userID, groupID uuid.UUID) error {
+	_, err := groupRole(ctx, s.groupRepo, groupID, userID)
+	return err
+}
+
+// validateLearningPath checks a path's title
+func validateLearningPath(p *domain.LearningPath) error {
+	if p.Title == "" || len(p.Title) > 100 {
+		return ErrLearningPathTitle
+	}
+	return nil
+}
+
+// validatePathTopic checks a topic's title
+func validatePathTopic(t *domain.PathTopic) error {
+	if t.Title == "" || len(t.Title) > 200 {
+		return ErrPathTopicTitle
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/i18n"
+	"devjournal/internal/mail"
+	"devjournal/internal/push"
+	"devjournal/internal/repository/postgres"
+	"devjournal/internal/tenant"
+
+	"github.com/google/uuid"
+)
+
+// mentionQueueSize bounds chat messages and new mentions waiting to be processed
+const mentionQueueSize = 256
+
+// MentionService records @mentions in study group chat and shared journal entries and notifies
+// the mentioned users: in-app through the mentions list, by push, or by email when they have no
+// mobile device registered
+type MentionService struct {
+	mentionRepo   *postgres.MentionRepository
+	groupRepo     *postgres.StudyGroupRepository
+	workspaceRepo *postgres.WorkspaceRepository
+	userRepo      *postgres.UserRepository
+	pushService   *PushService
+	mailer        mail.Sender // nil disables email
+	chat          chan *domain.ChatMessage
+	created       chan []domain.Mention
+}
+
+// NewMentionService creates a new mention service. A nil mailer disables mention emails.
+func NewMentionService(mentionRepo *postgres.MentionRepository, groupRepo *postgres.StudyGroupRepository, workspaceRepo *postgres.WorkspaceRepository, userRepo *postgres.UserRepository, pushService *PushService, mailer mail.Sender) *MentionService {
+	return &MentionService{
+		mentionRepo:   mentionRepo,
+		groupRepo:     groupRepo,
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+		pushService:   pushService,
+		mailer:        mailer,
+		chat:          make(chan *domain.ChatMessage, mentionQueueSize),
+		created:       make(chan []domain.Mention, mentionQueueSize),
+	}
+}
+
+// List returns where the user was mentioned, newest first, and how many are unread
+func (s *MentionService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]domain.Mention, int, int, error) {
+	mentions, total, err := s.mentionRepo.ListByUser(ctx, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	unread, err := s.mentionRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return mentions, total, unread, nil
+}
+
+// MarkRead marks the user's mentions as read, all of them when req has no IDs
+func (s *MentionService) MarkRead(ctx context.Context, userID uuid.UUID, req *domain.MarkMentionsReadRequest) (int, error) {
+	return s.mentionRepo.MarkRead(ctx, userID, req.IDs, time.Now().UTC())
+}
+
+// OnChatMessage queues a broadcast chat message to be checked for mentions. It never blocks the hub.
+func (s *MentionService) OnChatMessage(msg *domain.ChatMessage) {
+	if msg.Type != "message" {
+		return
+	}
+	select {
+	case s.chat <- msg:
+	default:
+		log.Printf("WARN: Mention queue full, dropping message %s", msg.ID)
+	}
+}
+
+// FromEntry records mentions of workspace members in a journal entry. Entries in a personal
+// workspace have no one else to mention, and encrypted entries can't be read.
+func (s *MentionService) FromEntry(ctx context.Context, entry *domain.JournalEntry) error {
+	if entry.ContentFormat == domain.ContentFormatE2EE {
+		return nil
+	}
+	workspaceID := tenant.WorkspaceID(ctx, entry.UserID)
+	if workspaceID == entry.UserID {
+		return nil
+	}
+	handles := domain.MentionHandles(entry.Title + " " + entry.Content)
+	if len(handles) == 0 {
+		return nil
+	}
+
+	members, err := s.workspaceRepo.GetMembers(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	candidates := make([]mentionCandidate, len(members))
+	for i, m := range members {
+		candidates[i] = mentionCandidate{userID: m.UserID, displayName: m.DisplayName}
+	}
+
+	return s.record(ctx, handles, candidates, domain.Mention{
+		AuthorID:    entry.UserID,
+		SourceType:  domain.MentionSourceEntry,
+		SourceID:    entry.ID.String(),
+		ContextID:   workspaceID,
+		ContextName: entry.Title,
+	}, entry.Content)
+}
+
+// Run processes queued chat messages and sends notifications for new mentions until ctx is cancelled
+func (s *MentionService) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-s.chat:
+			if err := s.fromChat(ctx, msg); err != nil {
+				log.Printf("WARN: Failed to record mentions in message %s: %v", msg.ID, err)
+			}
+		case mentions := <-s.created:
+			for _, m := range mentions {
+				s.notify(ctx, &m)
+			}
+		}
+	}
+}
+
+// fromChat records mentions of study group members in a group chat message
+func (s *MentionService) fromChat(ctx context.Context, msg *domain.ChatMessage) error {
+	handles := domain.MentionHandles(msg.Content)
+	if len(handles) == 0 {
+		return nil
+	}
+	groupID, channel, ok := domain.GroupRoom(msg.Room)
+	if !ok {
+		// Only study group rooms have members to mention
+		return nil
+	}
+	authorID, err := uuid.Parse(msg.UserID)
+	if err != nil {
+		return nil
+	}
+	group, err := s.groupRepo.FindByID(ctx, groupID)
+	if err != nil || group == nil {
+		return err
+	}
+
+	members, err := s.groupRepo.GetMembers(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	candidates := make([]mentionCandidate, len(members))
+	for i, m := range members {
+		candidates[i] = mentionCandidate{userID: m.UserID, displayName: m.DisplayName}
+	}
+	contextName := group.Name
+	if channel != domain.DefaultChannel {
+		contextName += " #" + channel
+	}
+
+	return s.record(ctx, handles, candidates, domain.Mention{
+		AuthorID:    authorID,
+		SourceType:  domain.MentionSourceChat,
+		SourceID:    msg.ID,
+		ContextID:   groupID,
+		ContextName: contextName,
+	}, msg.Content)
+}
+
+// mentionCandidate is someone who can be mentioned in a group or workspace
+type mentionCandidate struct {
+	userID      uuid.UUID
+	displayName string
+}
+
+// record saves a mention for each candidate whose handle appears, other than the author,
+// and queues notifications for the ones not already recorded
+func (s *MentionService) record(ctx context.Context, handles []string, candidates []mentionCandidate, base domain.Mention, content string) error {
+	mentioned := make(map[string]bool, len(handles))
+	for _, handle := range handles {
+		mentioned[handle] = true
+	}
+
+	now := time.Now().UTC()
+	var mentions []domain.Mention
+	for _, c := range candidates {
+		handle := domain.MentionHandle(c.displayName)
+		if c.userID == base.AuthorID || !mentioned[handle] {
+			continue
+		}
+		m := base
+		m.ID = uuid.New()
+		m.UserID = c.userID
+		m.Excerpt = domain.MentionExcerpt(content, handle)
+		m.CreatedAt = now
+		mentions = append(mentions, m)
+	}
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	created, err := s.mentionRepo.Create(ctx, mentions)
+	if len(created) > 0 {
+		select {
+		case s.created <- created:
+		default:
+			log.Printf("WARN: Mention queue full, not notifying %d mentions", len(created))
+		}
+	}
+	return err
+}
+
+// notify sends a push notification for a mention, or an email if the user has no device to receive it
+func (s *MentionService) notify(ctx context.Context, m *domain.Mention) {
+	author, err := s.userRepo.FindByID(ctx, m.AuthorID)
+	if err != nil || author == nil {
+		log.Printf("WARN: Failed to load mention author %s: %v", m.AuthorID, err)
+		return
+	}
+	locale := s.pushService.RecipientLocale(ctx, m.UserID)
+	title := i18n.T(locale, "%s mentioned you in %s", author.DisplayName, m.ContextName)
+
+	sent, err := s.pushService.Notify(ctx, m.UserID, &push.Notification{
+		Title: title,
+		Body:  m.Excerpt,
+		Data: map[string]string{
+			"type":       domain.PushMention,
+			"mentionId":  m.ID.String(),
+			"sourceType": m.SourceType,
+			"sourceId":   m.SourceID,
+			"contextId":  m.ContextID.String(),
+		},
+	})
+	if err != nil {
+		log.Printf("WARN: Failed to push mention %s: %v", m.ID, err)
+	}
+	if sent > 0 || s.mailer == nil {
+		return
+	}
+
+	user, err := s.userRepo.FindByID(ctx, m.UserID)
+	if err != nil || user == nil {
+		log.Printf("WARN: Failed to load mentioned user %s: %v", m.UserID, err)
+		return
+	}
+	body := i18n.T(locale, "%s:\n\n%s\n\nSee all your mentions in DevJournal.\n", title, m.Excerpt)
+	if err := s.mailer.Send(ctx, user.Email, title, body); err != nil {
+		log.Printf("WARN: Failed to email mention %s: %v", m.ID, err)
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotGroupMember    = apperr.New(ErrForbidden, "not a member of this study group")
+	ErrNotGroupModerator = apperr.New(ErrForbidden, "only group owners and admins can change moderation settings")
+	ErrInvalidPattern    = apperr.New(ErrValidation, "invalid blocked pattern")
+	ErrSelfReport        = apperr.New(ErrValidation, "cannot report your own message")
+	ErrInvalidResolution = apperr.New(ErrValidation, "status must be dismissed or actioned")
+	ErrContentNotFound   = apperr.New(ErrNotFound, "content not found")
+	ErrReportNotFound    = apperr.New(ErrNotFound, "report not found or already resolved")
+)
+
+// maxBlockedPatterns caps how many regex filters a single group may configure
+const maxBlockedPatterns = 50
+
+// ModerationService handles chat moderation settings and the message report queue
+type ModerationService struct {
+	moderationRepo *postgres.ModerationRepository
+	groupRepo      *postgres.StudyGroupRepository
+	snippetRepo    *mongodb.SnippetRepository
+}
+
+// NewModerationService creates a new moderation service
+func NewModerationService(moderationRepo *postgres.ModerationRepository, groupRepo *postgres.StudyGroupRepository, snippetRepo *mongodb.SnippetRepository) *ModerationService {
+	return &ModerationService{
+		moderationRepo: moderationRepo,
+		groupRepo:      groupRepo,
+		snippetRepo:    snippetRepo,
+	}
+}
+
+// GetSettings returns a group's moderation settings, falling back to defaults
+func (s *ModerationService) GetSettings(ctx context.Context, groupID uuid.UUID) (*domain.GroupModerationSettings, error) {
+	settings, err := s.moderationRepo.GetSettings(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation settings: %w", err)
+	}
+	if settings == nil {
+		settings = domain.NewGroupModerationSettings(groupID)
+	}
+	return settings, nil
+}
+
+// UpdateSettings replaces a group's moderation settings (group owners and admins only)
+func (s *ModerationService) UpdateSettings(ctx context.Context, groupID, userID uuid.UUID, req *domain.UpdateModerationSettingsRequest) (*domain.GroupModerationSettings, error) {
+	role, err := s.groupRepo.GetMemberRole(ctx, groupID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check group role: %w", err)
+	}
+	if !isGroupManager(role) {
+		return nil, ErrNotGroupModerator
+	}
+
+	if len(req.BlockedPatterns) > maxBlockedPatterns {
+		return nil, fmt.Errorf("%w: at most %d patterns allowed", ErrInvalidPattern, maxBlockedPatterns)
+	}
+	for _, pattern := range req.BlockedPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("%w %q: %v", ErrInvalidPattern, pattern, err)
+		}
+	}
+	if req.MaxMessageLength < 0 {
+		req.MaxMessageLength = 0
+	}
+
+	settings := domain.NewGroupModerationSettings(groupID)
+	settings.ProfanityFilter = req.ProfanityFilter
+	if req.BlockedPatterns != nil {
+		settings.BlockedPatterns = req.BlockedPatterns
+	}
+	settings.MaxMessageLength = req.MaxMessageLength
+	settings.UpdatedAt = time.Now().UTC()
+
+	if err := s.moderationRepo.UpsertSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to update moderation settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// ReportMessage files a report against a chat message on behalf of a group member
+func (s *ModerationService) ReportMessage(ctx context.Context, groupID, reporterID uuid.UUID, message *domain.ChatMessage, reason string) (*domain.MessageReport, error) {
+	if _, err := groupRole(ctx, s.groupRepo, groupID, reporterID); err != nil {
+		return nil, err
+	}
+	if message.UserID == reporterID.String() {
+		return nil, ErrSelfReport
+	}
+
+	report := domain.NewMessageReport(groupID, message, reporterID, reason)
+	if err := s.moderationRepo.CreateReport(ctx, report); err != nil {
+		return nil, fmt.Errorf("failed to report message: %w", err)
+	}
+
+	return report, nil
+}
+
+// ListReports returns the moderation queue for the given status (pending by default)
+func (s *ModerationService) ListReports(ctx context.Context, status string, limit, offset int) ([]domain.MessageReport, int, error) {
+	if status == "" {
+		status = domain.ReportStatusPending
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
+	}
+
+	reports, err := s.moderationRepo.ListReports(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list message reports: %w", err)
+	}
+	if reports == nil {
+		reports = []domain.MessageReport{}
+	}
+
+	total, err := s.moderationRepo.CountReports(ctx, status)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count message reports: %w", err)
+	}
+
+	return reports, total, nil
+}
+
+// ResolveReport records an admin decision on a pending message report, optionally warning the author
+func (s *ModerationService) ResolveReport(ctx context.Context, id, adminID uuid.UUID, req *domain.ResolveReportRequest) error {
+	if req.Status != domain.ReportStatusDismissed && req.Status != domain.ReportStatusActioned {
+		return ErrInvalidResolution
+	}
+
+	report, err := s.moderationRepo.FindReport(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find message report: %w", err)
+	}
+	if report == nil || report.Status != domain.ReportStatusPending {
+		return ErrReportNotFound
+	}
+
+	if err := s.moderationRepo.ResolveReport(ctx, id, req.Status, req.Note, adminID); err != nil {
+		return fmt.Errorf("failed to resolve message report: %w", err)
+	}
+
+	if req.Status == domain.ReportStatusActioned && req.WarnUser && report.MessageUserID != uuid.Nil {
+		if err := s.warn(ctx, report.MessageUserID, adminID, req.Note, report.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReportSnippet files a report against a public snippet
+func (s *ModerationService) ReportSnippet(ctx context.Context, snippetID string, reporterID uuid.UUID, reason string) (*domain.ContentReport, error) {
+	snippet, err := s.snippetRepo.FindByID(ctx, snippetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snippet: %w", err)
+	}
+	// Only content other users can actually see is reportable
+	if snippet == nil || !snippet.IsPublic || snippet.IsHidden {
+		return nil, ErrContentNotFound
+	}
+	if snippet.UserID == reporterID.String() {
+		return nil, ErrSelfReport
+	}
+
+	report := domain.NewSnippetReport(snippet, reporterID, reason)
+	if err := s.moderationRepo.CreateContentReport(ctx, report); err != nil {
+		return nil, fmt.Errorf("failed to report snippet: %w", err)
+	}
+
+	return report, nil
+}
+
+// ListContentReports returns the public content moderation queue for the given status (pending by default)
+func (s *ModerationService) ListContentReports(ctx context.Context, status string, limit, offset int) ([]domain.ContentReport, int, error) {
+	if status == "" {
+		status = domain.ReportStatusPending
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
+	}
+
+	reports, err := s.moderationRepo.ListContentReports(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list content reports: %w", err)
+	}
+	if reports == nil {
+		reports = []domain.ContentReport{}
+	}
+
+	total, err := s.moderationRepo.CountContentReports(ctx, status)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count content reports: %w", err)
+	}
+
+	return reports, total, nil
+}
+
+// ResolveContentReport records an admin decision on a content report, optionally
+// hiding the content and warning its author
+func (s *ModerationService) ResolveContentReport(ctx context.Context, id, adminID uuid.UUID, req *domain.ResolveReportRequest) error {
+	if req.Status != domain.ReportStatusDismissed && req.Status != domain.ReportStatusActioned {
+		return ErrInvalidResolution
+	}
+
+	report, err := s.moderationRepo.FindContentReport(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find content report: %w", err)
+	}
+	if report == nil || report.Status != domain.ReportStatusPending {
+		return ErrReportNotFound
+	}
+
+	if req.Status == domain.ReportStatusActioned && req.HideContent && report.ContentType == domain.ContentTypeSnippet {
+		if err := s.snippetRepo.SetHidden(ctx, report.ContentID, true); err != nil {
+			return fmt.Errorf("failed to hide snippet: %w", err)
+		}
+	}
+
+	if err := s.moderationRepo.ResolveContentReport(ctx, id, req.Status, req.Note, adminID); err != nil {
+		return fmt.Errorf("failed to resolve content report: %w", err)
+	}
+
+	if req.Status == domain.ReportStatusActioned && req.WarnUser && report.ContentOwnerID != uuid.Nil {
+		if err := s.warn(ctx, report.ContentOwnerID, adminID, req.Note, report.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListWarnings returns the warnings issued to a user
+func (s *ModerationService) ListWarnings(ctx context.Context, userID uuid.UUID) ([]domain.UserWarning, error) {
+	warnings, err := s.moderationRepo.ListWarnings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list warnings: %w", err)
+	}
+	if warnings == nil {
+		warnings = []domain.UserWarning{}
+	}
+	return warnings, nil
+}
+
+// warn issues a warning to a user on behalf of an admin
+func (s *ModerationService) warn(ctx context.Context, userID, adminID uuid.UUID, reason string, reportID uuid.UUID) error {
+	if reason == "" {
+		reason = "Your content was reported and found to violate the community guidelines."
+	}
+	warning := domain.NewUserWarning(userID, adminID, reason, &reportID)
+	if err := s.moderationRepo.CreateWarning(ctx, warning); err != nil {
+		return fmt.Errorf("failed to warn user: %w", err)
+	}
+	return nil
+}
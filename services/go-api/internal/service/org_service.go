@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOrganizationNotFound = apperr.New(ErrNotFound, "organization not found")
+	ErrInvalidSeatLimit     = apperr.New(ErrValidation, "seat limit must cover current members and be at most 10000")
+	ErrNotOrgOwner          = apperr.New(ErrForbidden, "only the organization owner can change seats")
+)
+
+// Top contributors returned by organization analytics
+const orgTopContributors = 10
+
+// OrganizationService handles organizations, seats, and org-wide analytics.
+// Membership is managed through the organization's workspace.
+type OrganizationService struct {
+	orgRepo          *postgres.OrganizationRepository
+	progressRepo     *postgres.ProgressRepository
+	workspaceService *WorkspaceService
+	quotaService     *QuotaService
+}
+
+// NewOrganizationService creates a new organization service
+func NewOrganizationService(orgRepo *postgres.OrganizationRepository, progressRepo *postgres.ProgressRepository, workspaceService *WorkspaceService, quotaService *QuotaService) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:          orgRepo,
+		progressRepo:     progressRepo,
+		workspaceService: workspaceService,
+		quotaService:     quotaService,
+	}
+}
+
+// List returns every organization a user belongs to
+func (s *OrganizationService) List(ctx context.Context, userID uuid.UUID) ([]domain.Organization, error) {
+	orgs, err := s.orgRepo.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	if orgs == nil {
+		orgs = []domain.Organization{}
+	}
+	return orgs, nil
+}
+
+// Create creates an organization owned by userID, along with its workspace
+func (s *OrganizationService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateOrganizationRequest) (*domain.Organization, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, ErrInvalidWorkspace
+	}
+	seats := req.SeatLimit
+	if seats == 0 {
+		seats = domain.DefaultOrgSeatLimit
+	}
+	if seats < 1 || seats > domain.MaxOrgSeatLimit {
+		return nil, ErrInvalidSeatLimit
+	}
+
+	if err := s.quotaService.CheckWorkspaces(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.quotaService.CheckSeats(ctx, userID, seats); err != nil {
+		return nil, err
+	}
+
+	slug, err := s.workspaceService.claimSlug(ctx, name, req.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	org := &domain.Organization{
+		ID:        uuid.New(),
+		Name:      name,
+		Slug:      slug,
+		OwnerID:   userID,
+		SeatLimit: seats,
+		SeatsUsed: 1,
+		Role:      domain.WorkspaceRoleOwner,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.orgRepo.Create(ctx, org); err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+	return org, nil
+}
+
+// Get returns an organization the user belongs to
+func (s *OrganizationService) Get(ctx context.Context, id, userID uuid.UUID) (*domain.Organization, error) {
+	org, err := s.orgRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	if org.Role == "" {
+		return nil, ErrNotWorkspaceMember
+	}
+	return org, nil
+}
+
+// UpdateSeats changes an organization's seat limit. Only the owner can change seats,
+// and the limit cannot drop below the current member count.
+func (s *OrganizationService) UpdateSeats(ctx context.Context, id, userID uuid.UUID, seatLimit int) (*domain.Organization, error) {
+	org, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if org.Role != domain.WorkspaceRoleOwner {
+		return nil, ErrNotOrgOwner
+	}
+	if seatLimit < org.SeatsUsed || seatLimit < 1 || seatLimit > domain.MaxOrgSeatLimit {
+		return nil, ErrInvalidSeatLimit
+	}
+	if err := s.quotaService.CheckSeats(ctx, userID, seatLimit); err != nil {
+		return nil, err
+	}
+
+	if err := s.orgRepo.UpdateSeatLimit(ctx, id, seatLimit); err != nil {
+		return nil, err
+	}
+	org.SeatLimit = seatLimit
+	return org, nil
+}
+
+// Analytics aggregates streaks and journal activity across an organization's members
+// over the last days days. Only owners and admins can view analytics.
+func (s *OrganizationService) Analytics(ctx context.Context, id, userID uuid.UUID, days int) (*domain.OrgAnalytics, error) {
+	org, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if org.Role != domain.WorkspaceRoleOwner && org.Role != domain.WorkspaceRoleAdmin {
+		return nil, ErrNotWorkspaceAdmin
+	}
+	// Analytics is billed to the organization owner's plan
+	if err := s.quotaService.RequireFeature(ctx, org.OwnerID, domain.FeatureOrgAnalytics); err != nil {
+		return nil, err
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	active, err := s.orgRepo.CountActiveMembers(ctx, id, since)
+	if err != nil {
+		return nil, err
+	}
+	contributors, err := s.orgRepo.Contributors(ctx, id, since)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &domain.OrgAnalytics{
+		OrganizationID:  id,
+		Days:            days,
+		MemberCount:     len(contributors),
+		ActiveMembers:   active,
+		TopContributors: []domain.OrgContributor{},
+	}
+
+	totalStreak := 0
+	for i := range contributors {
+		streak, err := s.progressRepo.CalculateStreak(ctx, contributors[i].UserID)
+		if err != nil {
+			return nil, err
+		}
+		contributors[i].CurrentStreak = streak
+		totalStreak += streak
+		analytics.EntriesCount += contributors[i].EntriesCount
+		if streak > 0 {
+			analytics.MembersOnStreak++
+		}
+		if streak > analytics.LongestStreak {
+			analytics.LongestStreak = streak
+		}
+	}
+	if len(contributors) > 0 {
+		analytics.AverageStreak = float64(totalStreak) / float64(len(contributors))
+	}
+
+	// Contributors are ordered by entries written; members with none are left out
+	for _, c := range contributors {
+		if c.EntriesCount == 0 || len(analytics.TopContributors) == orgTopContributors {
+			break
+		}
+		analytics.TopContributors = append(analytics.TopContributors, c)
+	}
+
+	return analytics, nil
+}
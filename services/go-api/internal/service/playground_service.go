@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/playground"
+	"devjournal/internal/repository/mongodb"
+)
+
+// playgroundTimeout bounds how long a snippet fetch waits for a playground to share its code
+const playgroundTimeout = 5 * time.Second
+
+// PlaygroundService links snippets to the online playground for their language
+type PlaygroundService struct {
+	snippetRepo *mongodb.SnippetRepository
+	playgrounds *playground.Client
+}
+
+// NewPlaygroundService creates a new playground service
+func NewPlaygroundService(snippetRepo *mongodb.SnippetRepository, playgrounds *playground.Client) *PlaygroundService {
+	return &PlaygroundService{snippetRepo: snippetRepo, playgrounds: playgrounds}
+}
+
+// Attach sets a snippet's playground link, making and caching one if its code has none yet.
+// Playgrounds that keep a copy of the code are only sent public snippets. Failures leave the
+// link out rather than failing the fetch.
+func (s *PlaygroundService) Attach(ctx context.Context, snippet *domain.Snippet) {
+	if s == nil || snippet.PlaygroundURL != "" || !playground.Supported(snippet.Language) {
+		return
+	}
+	if playground.Uploads(snippet.Language) && (!snippet.IsPublic || snippet.IsHidden) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, playgroundTimeout)
+	defer cancel()
+	link, err := s.playgrounds.Link(ctx, snippet.Language, snippet.Code)
+	if err != nil {
+		log.Printf("WARN: Failed to make playground link for snippet %s: %v", snippet.ID, err)
+		return
+	}
+	snippet.PlaygroundURL = link
+
+	if err := s.snippetRepo.SetPlayground(ctx, snippet.ID, snippet.Language, snippet.Code, link); err != nil {
+		log.Printf("WARN: Failed to cache playground link for snippet %s: %v", snippet.ID, err)
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+// Poll history page sizes
+const (
+	defaultPollLimit = 20
+	maxPollLimit     = 50
+)
+
+var (
+	ErrPollNotFound    = apperr.New(ErrNotFound, "poll not found")
+	ErrPollQuestion    = apperr.New(ErrValidation, "question is required and must be at most 300 characters")
+	ErrPollOptions     = apperr.Newf(ErrValidation, "a poll needs between %d and %d distinct options, each at most 100 characters", domain.MinPollOptions, domain.MaxPollOptions)
+	ErrPollClosesAt    = apperr.New(ErrValidation, "closesAt must be in the future")
+	ErrPollVote        = apperr.New(ErrValidation, "options must pick at least one of the poll's options, and only one unless the poll is multiple choice")
+	ErrPollClosed      = apperr.New(ErrConflict, "the poll is closed")
+	ErrPollReadOnly    = apperr.New(ErrForbidden, "only group owners and admins can post in this channel")
+	ErrPollNotPostable = apperr.New(ErrValidation, "the poll could not be posted to the channel")
+)
+
+// PollBroadcaster sends polls and their updated results to everyone in a study group chat room
+type PollBroadcaster interface {
+	// Moderate runs a message through chat moderation without sending it. Filters may rewrite
+	// its content.
+	Moderate(ctx context.Context, msg *domain.ChatMessage) error
+
+	// Broadcast sends a message to its room as it is
+	Broadcast(ctx context.Context, msg *domain.ChatMessage) error
+}
+
+// PollService handles polls in study group chat channels and the votes on them
+type PollService struct {
+	pollRepo       *postgres.PollRepository
+	groupRepo      *postgres.StudyGroupRepository
+	userRepo       *postgres.UserRepository
+	channelService *GroupChannelService
+	broadcaster    PollBroadcaster
+}
+
+// NewPollService creates a new poll service. Polls and their results are sent to group chat
+// through broadcaster.
+func NewPollService(pollRepo *postgres.PollRepository, groupRepo *postgres.StudyGroupRepository, userRepo *postgres.UserRepository, channelService *GroupChannelService, broadcaster PollBroadcaster) *PollService {
+	return &PollService{
+		pollRepo:       pollRepo,
+		groupRepo:      groupRepo,
+		userRepo:       userRepo,
+		channelService: channelService,
+		broadcaster:    broadcaster,
+	}
+}
+
+// Create posts a poll to a group channel as a poll chat message (members who can post in the
+// channel only). The question goes through the same moderation as chat messages.
+func (s *PollService) Create(ctx context.Context, groupID, userID uuid.UUID, req *domain.CreatePollRequest) (*domain.Poll, error) {
+	channel := req.Channel
+	if channel == "" {
+		channel = domain.DefaultChannel
+	}
+	room := domain.ChannelRoom(groupID, channel)
+	readOnly, err := s.channelService.Access(ctx, room, userID)
+	if err != nil {
+		return nil, err
+	}
+	if readOnly {
+		return nil, ErrPollReadOnly
+	}
+
+	now := time.Now().UTC()
+	poll := &domain.Poll{
+		ID:             uuid.New(),
+		GroupID:        groupID,
+		Channel:        channel,
+		Room:           room,
+		CreatedBy:      userID,
+		MultipleChoice: req.MultipleChoice,
+		CreatedAt:      now,
+	}
+	if err := setPollFields(poll, req, now); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	// The question is moderated like a chat message, and kept as the filters leave it
+	msg := domain.NewChatMessage(room, userID.String(), user.DisplayName, poll.Question, "poll")
+	if err := s.broadcaster.Moderate(ctx, msg); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Printf("Poll rejected in room %s: %v", room, err)
+		return nil, ErrPollNotPostable
+	}
+	poll.Question = msg.Content
+
+	if err := s.pollRepo.Create(ctx, poll); err != nil {
+		return nil, err
+	}
+	msg.Poll = poll
+	if err := s.broadcaster.Broadcast(ctx, msg); err != nil {
+		log.Printf("WARN: Failed to post poll %s to room %s: %v", poll.ID, room, err)
+	}
+	return poll, nil
+}
+
+// Vote records a member's vote in a poll, replacing any earlier one, and broadcasts the new
+// results to the poll's room. It returns the poll with the member's votes.
+func (s *PollService) Vote(ctx context.Context, groupID, userID, pollID uuid.UUID, req *domain.PollVoteRequest) (*domain.Poll, error) {
+	poll, err := s.pollRepo.FindByID(ctx, pollID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+	if _, err := s.channelService.Access(ctx, poll.Room, userID); err != nil {
+		return nil, err
+	}
+	if poll.Closed(time.Now()) {
+		return nil, ErrPollClosed
+	}
+	ballot, ok := poll.Ballot(req.Options)
+	if !ok {
+		return nil, ErrPollVote
+	}
+
+	if err := s.pollRepo.Vote(ctx, poll.ID, userID, ballot); err != nil {
+		return nil, err
+	}
+	if err := s.pollRepo.Tally(ctx, []*domain.Poll{poll}, userID); err != nil {
+		return nil, err
+	}
+	s.broadcastResults(ctx, poll)
+	return poll, nil
+}
+
+// List returns a page of a group's polls with their results, newest first, optionally in one
+// channel (group members only)
+func (s *PollService) List(ctx context.Context, groupID, userID uuid.UUID, channel, cursor string, limit int) (*domain.PollPage, error) {
+	if _, err := groupRole(ctx, s.groupRepo, groupID, userID); err != nil {
+		return nil, err
+	}
+	before, err := parseFeedCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultPollLimit
+	}
+	if limit > maxPollLimit {
+		limit = maxPollLimit
+	}
+
+	polls, err := s.pollRepo.ListByGroup(ctx, groupID, channel, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.pollRepo.Tally(ctx, polls, userID); err != nil {
+		return nil, err
+	}
+	page := &domain.PollPage{Data: polls}
+	if len(polls) == limit {
+		page.NextCursor = polls[len(polls)-1].CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return page, nil
+}
+
+// broadcastResults sends a poll's new counts to its room. Failures are logged, since the vote
+// is already recorded.
+func (s *PollService) broadcastResults(ctx context.Context, poll *domain.Poll) {
+	results := *poll
+	results.MyVotes = nil
+	msg := domain.NewChatMessage(poll.Room, "", "System", "", "poll_results")
+	msg.Poll = &results
+	if err := s.broadcaster.Broadcast(ctx, msg); err != nil {
+		log.Printf("WARN: Failed to broadcast results of poll %s: %v", poll.ID, err)
+	}
+}
+
+// setPollFields validates a poll request and copies it onto the poll
+func setPollFields(poll *domain.Poll, req *domain.CreatePollRequest, now time.Time) error {
+	question := strings.TrimSpace(req.Question)
+	if question == "" || utf8.RuneCountInString(question) > 300 {
+		return ErrPollQuestion
+	}
+	if len(req.Options) < domain.MinPollOptions || len(req.Options) > domain.MaxPollOptions {
+		return ErrPollOptions
+	}
+	options := make([]domain.PollOption, len(req.Options))
+	seen := make(map[string]bool, len(req.Options))
+	for i, text := range req.Options {
+		text = strings.TrimSpace(text)
+		if text == "" || utf8.RuneCountInString(text) > 100 || seen[strings.ToLower(text)] {
+			return ErrPollOptions
+		}
+		seen[strings.ToLower(text)] = true
+		options[i] = domain.PollOption{Text: text}
+	}
+	if req.ClosesAt != nil && !req.ClosesAt.After(now) {
+		return ErrPollClosesAt
+	}
+
+	poll.Question, poll.Options = question, options
+	if req.ClosesAt != nil {
+		closesAt := req.ClosesAt.UTC()
+		poll.ClosesAt = &closesAt
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProblemNotFound     = apperr.New(ErrNotFound, "problem not found")
+	ErrProblemLogged       = apperr.New(ErrConflict, "this problem is already in your log")
+	ErrInvalidPlatform     = apperr.New(ErrValidation, "platform must be leetcode, adventofcode, codeforces, hackerrank, or other")
+	ErrInvalidDifficulty   = apperr.New(ErrValidation, "difficulty must be easy, medium, hard, or empty")
+	ErrInvalidProblemID    = apperr.New(ErrValidation, "problemId is required and must be at most 100 characters")
+	ErrInvalidProblemTitle = apperr.New(ErrValidation, "title must be at most 200 characters")
+	ErrInvalidProblemURL   = apperr.New(ErrValidation, "url must be an http or https link")
+	ErrProblemNotesLength  = apperr.New(ErrValidation, fmt.Sprintf("notes must be at most %d characters", domain.MaxProblemNotesLength))
+	ErrSolvedInFuture      = apperr.New(ErrValidation, "solvedAt cannot be in the future")
+)
+
+// ProblemService handles the log of coding problems a user solved
+type ProblemService struct {
+	problemRepo *postgres.ProblemRepository
+	snippetRepo *mongodb.SnippetRepository
+}
+
+// NewProblemService creates a new problem service
+func NewProblemService(problemRepo *postgres.ProblemRepository, snippetRepo *mongodb.SnippetRepository) *ProblemService {
+	return &ProblemService{problemRepo: problemRepo, snippetRepo: snippetRepo}
+}
+
+// Create logs a solved problem. Each platform's problem can be logged once.
+func (s *ProblemService) Create(ctx context.Context, userID uuid.UUID, req *domain.ProblemRequest) (*domain.Problem, error) {
+	now := time.Now().UTC()
+	problem := &domain.Problem{ID: uuid.New(), UserID: userID, SolvedAt: now, CreatedAt: now, UpdatedAt: now}
+	if err := s.apply(ctx, problem, req); err != nil {
+		return nil, err
+	}
+	created, err := s.problemRepo.Create(ctx, problem)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrProblemLogged
+	}
+	return problem, nil
+}
+
+// Get returns one of the user's problems
+func (s *ProblemService) Get(ctx context.Context, userID, id uuid.UUID) (*domain.Problem, error) {
+	problem, err := s.problemRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if problem == nil {
+		return nil, ErrProblemNotFound
+	}
+	return problem, nil
+}
+
+// List returns the user's problems, optionally on one platform or of one difficulty
+func (s *ProblemService) List(ctx context.Context, userID uuid.UUID, platform, difficulty string, limit, offset int) ([]domain.Problem, int, error) {
+	if platform != "" && !domain.ValidPlatform(platform) {
+		return nil, 0, ErrInvalidPlatform
+	}
+	if !domain.ValidDifficulty(difficulty) {
+		return nil, 0, ErrInvalidDifficulty
+	}
+	return s.problemRepo.ListByUser(ctx, userID, platform, difficulty, limit, offset)
+}
+
+// Update changes a logged problem. A missing solvedAt keeps the current one.
+func (s *ProblemService) Update(ctx context.Context, userID, id uuid.UUID, req *domain.ProblemRequest) (*domain.Problem, error) {
+	problem, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(ctx, problem, req); err != nil {
+		return nil, err
+	}
+	taken, err := s.problemRepo.Exists(ctx, userID, problem.Platform, problem.ProblemID, problem.ID)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrProblemLogged
+	}
+	problem.UpdatedAt = time.Now().UTC()
+	if err := s.problemRepo.Update(ctx, problem); err != nil {
+		return nil, err
+	}
+	return problem, nil
+}
+
+// Delete removes a problem from the user's log
+func (s *ProblemService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	deleted, err := s.problemRepo.Delete(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrProblemNotFound
+	}
+	return nil
+}
+
+// Stats counts the user's solved problems by difficulty and platform, and per week over the
+// last weeks weeks (1-52, default 12)
+func (s *ProblemService) Stats(ctx context.Context, userID uuid.UUID, weeks int) (*domain.ProblemStats, error) {
+	if weeks <= 0 {
+		weeks = 12
+	}
+	if weeks > 52 {
+		weeks = 52
+	}
+	return s.problemRepo.Stats(ctx, userID, weeks)
+}
+
+// apply validates a request and copies it onto a problem
+func (s *ProblemService) apply(ctx context.Context, p *domain.Problem, req *domain.ProblemRequest) error {
+	platform := strings.ToLower(strings.TrimSpace(req.Platform))
+	if !domain.ValidPlatform(platform) {
+		return ErrInvalidPlatform
+	}
+	difficulty := strings.ToLower(strings.TrimSpace(req.Difficulty))
+	if !domain.ValidDifficulty(difficulty) {
+		return ErrInvalidDifficulty
+	}
+	problemID := strings.TrimSpace(req.ProblemID)
+	if problemID == "" || utf8.RuneCountInString(problemID) > 100 {
+		return ErrInvalidProblemID
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = problemID
+	}
+	if utf8.RuneCountInString(title) > 200 {
+		return ErrInvalidProblemTitle
+	}
+	link := strings.TrimSpace(req.URL)
+	if link != "" {
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidProblemURL
+		}
+	}
+	if utf8.RuneCountInString(req.Notes) > domain.MaxProblemNotesLength {
+		return ErrProblemNotesLength
+	}
+	if req.SolvedAt != nil {
+		if req.SolvedAt.After(time.Now()) {
+			return ErrSolvedInFuture
+		}
+		p.SolvedAt = req.SolvedAt.UTC()
+	}
+
+	snippetID := strings.TrimSpace(req.SnippetID)
+	if snippetID != "" && snippetID != p.SnippetID {
+		snippet, err := s.snippetRepo.FindByID(ctx, snippetID)
+		if err != nil {
+			return fmt.Errorf("failed to find snippet: %w", err)
+		}
+		if err := checkSnippetOwner(snippet, p.UserID.String()); err != nil {
+			return err
+		}
+	}
+
+	p.Platform = platform
+	p.Difficulty = difficulty
+	p.ProblemID = problemID
+	p.Title = title
+	p.URL = link
+	p.SnippetID = snippetID
+	p.Notes = req.Notes
+	return nil
+}
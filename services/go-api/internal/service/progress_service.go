@@ -1,19 +1,31 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"devjournal/internal/domain"
 	"devjournal/internal/repository/postgres"
+	"devjournal/internal/tenant"
+	"devjournal/pkg/apperr"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrProgressNotShared       = apperr.New(ErrPrecondition, "turn on shareProgress in your settings to compare progress")
+	ErrTooManyProgressCompared = apperr.Newf(ErrValidation, "userIds must list between 1 and %d users", domain.MaxProgressComparisonUsers)
+	ErrTooManyProgressSeries   = apperr.Newf(ErrValidation, "series must list between 1 and %d queries", domain.MaxProgressQuerySeries)
+	ErrInvalidProgressGroupBy  = apperr.New(ErrValidation, "groupBy must be day, week, or month")
+)
+
 // ProgressService handles learning progress business logic
 type ProgressService struct {
 	progressRepo *postgres.ProgressRepository
+	milestones   []func(context.Context, domain.StreakMilestone)
 }
 
 // NewProgressService creates a new progress service
@@ -21,6 +33,18 @@ func NewProgressService(progressRepo *postgres.ProgressRepository) *ProgressServ
 	return &ProgressService{progressRepo: progressRepo}
 }
 
+// OnMilestone registers fn to be called when a user first reaches one of
+// domain.StreakMilestones. fn runs in the request and must not block. Register listeners before
+// serving requests.
+func (s *ProgressService) OnMilestone(fn func(context.Context, domain.StreakMilestone)) {
+	s.milestones = append(s.milestones, fn)
+}
+
+// ListAchievements returns the achievements a user earned, most recent first
+func (s *ProgressService) ListAchievements(ctx context.Context, userID uuid.UUID) ([]domain.Achievement, error) {
+	return s.progressRepo.ListAchievements(ctx, userID)
+}
+
 // GetSummary retrieves the learning progress summary for a user
 func (s *ProgressService) GetSummary(ctx context.Context, userID uuid.UUID) (*domain.ProgressSummary, error) {
 	summary, err := s.progressRepo.GetSummary(ctx, userID)
@@ -82,6 +106,59 @@ func (s *ProgressService) GetMonthlyProgress(ctx context.Context, userID uuid.UU
 	return progressList, nil
 }
 
+// Query returns several series of progress at once, each summed per day, week, or month over
+// its own date range, with empty periods filled in
+func (s *ProgressService) Query(ctx context.Context, userID uuid.UUID, req *domain.ProgressQueryRequest) ([]domain.ProgressSeries, error) {
+	if len(req.Series) == 0 || len(req.Series) > domain.MaxProgressQuerySeries {
+		return nil, ErrTooManyProgressSeries
+	}
+
+	series := make([]domain.ProgressSeries, len(req.Series))
+	for i, q := range req.Series {
+		from, err := time.Parse(time.DateOnly, q.From)
+		if err != nil {
+			return nil, apperr.Newf(ErrValidation, "series %d: from must be a YYYY-MM-DD date", i)
+		}
+		to, err := time.Parse(time.DateOnly, q.To)
+		if err != nil {
+			return nil, apperr.Newf(ErrValidation, "series %d: to must be a YYYY-MM-DD date", i)
+		}
+		if to.Before(from) {
+			return nil, apperr.Newf(ErrValidation, "series %d: to must not be before from", i)
+		}
+		switch q.GroupBy {
+		case domain.StatsIntervalDay, domain.StatsIntervalWeek, domain.StatsIntervalMonth:
+		default:
+			return nil, ErrInvalidProgressGroupBy
+		}
+
+		var periods []time.Time
+		for p := domain.ProgressPeriodStart(from, q.GroupBy); !p.After(to); p = domain.NextProgressPeriod(p, q.GroupBy) {
+			if len(periods) == domain.MaxProgressQueryBuckets {
+				return nil, apperr.Newf(ErrValidation, "series %d spans more than %d %ss", i, domain.MaxProgressQueryBuckets, q.GroupBy)
+			}
+			periods = append(periods, p)
+		}
+
+		found, err := s.progressRepo.Buckets(ctx, userID, from, to, q.GroupBy)
+		if err != nil {
+			return nil, err
+		}
+		byPeriod := make(map[int64]domain.ProgressBucket, len(found))
+		for _, b := range found {
+			byPeriod[b.PeriodStart.Unix()] = b
+		}
+
+		buckets := make([]domain.ProgressBucket, len(periods))
+		for j, p := range periods {
+			buckets[j] = byPeriod[p.Unix()]
+			buckets[j].PeriodStart = p
+		}
+		series[i] = domain.ProgressSeries{From: q.From, To: q.To, GroupBy: q.GroupBy, Buckets: buckets}
+	}
+	return series, nil
+}
+
 // RecordJournalEntry records that a journal entry was created
 func (s *ProgressService) RecordJournalEntry(ctx context.Context, userID uuid.UUID) error {
 	if err := s.progressRepo.IncrementEntries(ctx, userID); err != nil {
@@ -110,6 +187,34 @@ func (s *ProgressService) RecordSnippet(ctx context.Context, userID uuid.UUID) e
 	return nil
 }
 
+// RecordTIL records that a TIL micro-entry was created
+func (s *ProgressService) RecordTIL(ctx context.Context, userID uuid.UUID) error {
+	if err := s.progressRepo.IncrementTILs(ctx, userID); err != nil {
+		return fmt.Errorf("failed to record TIL: %w", err)
+	}
+
+	// Update streak
+	if err := s.updateStreak(ctx, userID); err != nil {
+		return fmt.Errorf("failed to update streak: %w", err)
+	}
+
+	return nil
+}
+
+// RecordProblem records that a problem was solved on the given day
+func (s *ProgressService) RecordProblem(ctx context.Context, userID uuid.UUID, solvedAt time.Time) error {
+	if err := s.progressRepo.IncrementProblems(ctx, userID, solvedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to record problem: %w", err)
+	}
+
+	// Update streak
+	if err := s.updateStreak(ctx, userID); err != nil {
+		return fmt.Errorf("failed to update streak: %w", err)
+	}
+
+	return nil
+}
+
 // updateStreak calculates and updates the current streak
 func (s *ProgressService) updateStreak(ctx context.Context, userID uuid.UUID) error {
 	streak, err := s.progressRepo.CalculateStreak(ctx, userID)
@@ -125,9 +230,49 @@ func (s *ProgressService) updateStreak(ctx context.Context, userID uuid.UUID) er
 
 	if progress != nil {
 		progress.StreakDays = streak
-		return s.progressRepo.Upsert(ctx, progress)
+		if err := s.progressRepo.Upsert(ctx, progress); err != nil {
+			return err
+		}
+	}
+
+	return s.awardMilestones(ctx, userID, streak)
+}
+
+// awardMilestones records the streak milestones a streak reached as achievements, and tells the
+// OnMilestone listeners about the longest one the user hadn't reached before. Each milestone is
+// celebrated once, however many times the user's streak passes it, and a streak that predates
+// achievements is only celebrated for its longest milestone.
+func (s *ProgressService) awardMilestones(ctx context.Context, userID uuid.UUID, streak int) error {
+	var kinds []string
+	for _, days := range domain.StreakMilestones {
+		if streak >= days {
+			kinds = append(kinds, domain.StreakAchievement(days))
+		}
+	}
+	if len(kinds) == 0 {
+		return nil
 	}
 
+	now := time.Now().UTC()
+	awarded, err := s.progressRepo.AwardAchievements(ctx, userID, kinds, now)
+	if err != nil {
+		return err
+	}
+	for _, days := range slices.Backward(domain.StreakMilestones) {
+		if !slices.Contains(awarded, domain.StreakAchievement(days)) {
+			continue
+		}
+		milestone := domain.StreakMilestone{
+			UserID:      userID,
+			WorkspaceID: tenant.WorkspaceID(ctx, userID),
+			Days:        days,
+			ReachedAt:   now,
+		}
+		for _, fn := range s.milestones {
+			fn(ctx, milestone)
+		}
+		break
+	}
 	return nil
 }
 
@@ -139,3 +284,80 @@ func (s *ProgressService) GetCurrentStreak(ctx context.Context, userID uuid.UUID
 	}
 	return streak, nil
 }
+
+// RecordFocusTime adds minutes of focus time, or removes them when negative, from the learning
+// time of the day the session started
+func (s *ProgressService) RecordFocusTime(ctx context.Context, userID uuid.UUID, startedAt time.Time, minutes int) error {
+	if err := s.progressRepo.AddLearningTime(ctx, userID, startedAt.UTC(), minutes); err != nil {
+		return fmt.Errorf("failed to record focus time: %w", err)
+	}
+
+	// Update streak, which counts learning time for users who asked for it
+	if err := s.updateStreak(ctx, userID); err != nil {
+		return fmt.Errorf("failed to update streak: %w", err)
+	}
+	return nil
+}
+
+// RecordCodingTime adds minutes of coding time, or removes them when negative, from a day's
+// learning time
+func (s *ProgressService) RecordCodingTime(ctx context.Context, userID uuid.UUID, day time.Time, minutes int) error {
+	if err := s.progressRepo.AddLearningTime(ctx, userID, day, minutes); err != nil {
+		return fmt.Errorf("failed to record coding time: %w", err)
+	}
+
+	// Update streak, which counts learning time for users who asked for it
+	if err := s.updateStreak(ctx, userID); err != nil {
+		return fmt.Errorf("failed to update streak: %w", err)
+	}
+	return nil
+}
+
+// Compare compares the user's consistency over the last domain.ProgressComparisonDays with the
+// given users. Only users who turned on shareProgress and either mutually follow the user or
+// share a study group with them are included; the others are left out without saying why. The
+// user has to share their own progress to see anyone else's.
+func (s *ProgressService) Compare(ctx context.Context, userID uuid.UUID, userIDs []uuid.UUID) (*domain.ProgressComparison, error) {
+	others := slices.DeleteFunc(slices.Clone(userIDs), func(id uuid.UUID) bool { return id == userID })
+	slices.SortFunc(others, func(a, b uuid.UUID) int { return cmp.Compare(a.String(), b.String()) })
+	others = slices.Compact(others)
+	if len(others) == 0 || len(others) > domain.MaxProgressComparisonUsers {
+		return nil, ErrTooManyProgressCompared
+	}
+
+	shares, err := s.progressRepo.SharesProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !shares {
+		return nil, ErrProgressNotShared
+	}
+	friends, err := s.progressRepo.ComparableUsers(ctx, userID, others)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, 1-domain.ProgressComparisonDays)
+	users, err := s.progressRepo.CompareActivity(ctx, append(friends, userID), since)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		u := &users[i]
+		u.You = u.UserID == userID
+		u.LongestStreak = max(u.LongestStreak, u.CurrentStreak)
+		u.Consistency = float64(u.ActiveDays) / domain.ProgressComparisonDays
+	}
+	slices.SortFunc(users, func(a, b domain.ProgressComparisonEntry) int {
+		return cmp.Or(
+			cmp.Compare(b.ActiveDays, a.ActiveDays),
+			cmp.Compare(b.CurrentStreak, a.CurrentStreak),
+			cmp.Compare(a.UserID.String(), b.UserID.String()),
+		)
+	})
+	for i := range users {
+		users[i].Rank = i + 1
+	}
+	return &domain.ProgressComparison{Days: domain.ProgressComparisonDays, Users: users}, nil
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/policy"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProjectNotFound      = apperr.New(ErrNotFound, "project not found")
+	ErrProjectName          = apperr.New(ErrValidation, "name is required and must be at most 100 characters")
+	ErrInvalidProjectStatus = apperr.New(ErrValidation, "status must be active, paused, completed, or archived")
+	ErrFocusMinutes         = apperr.New(ErrValidation, fmt.Sprintf("minutes must be between 1 and %d", domain.MaxFocusMinutes))
+	ErrFocusInFuture        = apperr.New(ErrValidation, "startedAt cannot be in the future")
+	ErrFocusNotFound        = apperr.New(ErrNotFound, "focus session not found")
+)
+
+// ProjectService handles projects and the entries, snippets, and focus time attached to them
+type ProjectService struct {
+	projectRepo *postgres.ProjectRepository
+	journalRepo *postgres.JournalRepository
+	snippetRepo *mongodb.SnippetRepository
+}
+
+// NewProjectService creates a new project service
+func NewProjectService(projectRepo *postgres.ProjectRepository, journalRepo *postgres.JournalRepository, snippetRepo *mongodb.SnippetRepository) *ProjectService {
+	return &ProjectService{projectRepo: projectRepo, journalRepo: journalRepo, snippetRepo: snippetRepo}
+}
+
+// Create creates a new project
+func (s *ProjectService) Create(ctx context.Context, userID uuid.UUID, req *domain.ProjectRequest) (*domain.Project, error) {
+	project := domain.NewProject(userID, strings.TrimSpace(req.Name), req.Description)
+	if req.Status != "" {
+		project.Status = req.Status
+	}
+	if err := validateProject(project); err != nil {
+		return nil, err
+	}
+	if err := s.projectRepo.Create(ctx, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// Get returns one of the user's projects with counts of the work attached to it
+func (s *ProjectService) Get(ctx context.Context, userID, id uuid.UUID) (*domain.Project, error) {
+	project, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.projectRepo.Stats(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	snippets, err := s.snippetRepo.CountByProject(ctx, id.String())
+	if err != nil {
+		return nil, err
+	}
+	stats.Snippets = int(snippets)
+	project.Stats = stats
+	return project, nil
+}
+
+// List returns the user's projects in the current workspace, optionally with one status
+func (s *ProjectService) List(ctx context.Context, userID uuid.UUID, status string, limit, offset int) ([]domain.Project, int, error) {
+	if status != "" && !domain.ValidProjectStatus(status) {
+		return nil, 0, ErrInvalidProjectStatus
+	}
+	return s.projectRepo.ListByUser(ctx, userID, status, limit, offset)
+}
+
+// Update changes a project's name, description, and status
+func (s *ProjectService) Update(ctx context.Context, userID, id uuid.UUID, req *domain.ProjectRequest) (*domain.Project, error) {
+	project, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	project.Name = strings.TrimSpace(req.Name)
+	project.Description = req.Description
+	if req.Status != "" {
+		project.Status = req.Status
+	}
+	if err := validateProject(project); err != nil {
+		return nil, err
+	}
+	project.UpdatedAt = time.Now().UTC()
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// Delete removes a project and its focus sessions, and detaches its entries and snippets
+func (s *ProjectService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.find(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.snippetRepo.ClearProject(ctx, id.String()); err != nil {
+		return err
+	}
+	return s.projectRepo.Delete(ctx, id, userID)
+}
+
+// SetEntry attaches one of the user's entries to the project, or detaches it
+func (s *ProjectService) SetEntry(ctx context.Context, userID, id, entryID uuid.UUID, attach bool) error {
+	if _, err := s.find(ctx, userID, id); err != nil {
+		return err
+	}
+	entry, err := s.journalRepo.FindByID(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	if policy.Authorize(policy.User(ctx, userID), policy.Link, entry) != policy.Allow {
+		return ErrEntryNotFound
+	}
+
+	var projectID *uuid.UUID
+	if attach {
+		projectID = &id
+	} else if entry.ProjectID == nil || *entry.ProjectID != id {
+		// Only detach from this project, not whichever one the entry moved to
+		return nil
+	}
+	if _, err := s.journalRepo.SetProject(ctx, entryID, userID, projectID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetSnippet attaches one of the user's snippets to the project, or detaches it
+func (s *ProjectService) SetSnippet(ctx context.Context, userID, id uuid.UUID, snippetID string, attach bool) error {
+	if _, err := s.find(ctx, userID, id); err != nil {
+		return err
+	}
+	snippet, err := s.snippetRepo.FindByID(ctx, snippetID)
+	if err != nil {
+		return fmt.Errorf("failed to find snippet: %w", err)
+	}
+	if err := checkSnippetOwner(snippet, userID.String()); err != nil {
+		return err
+	}
+
+	projectID := ""
+	if attach {
+		projectID = id.String()
+	} else if snippet.ProjectID != id.String() {
+		return nil
+	}
+	_, err = s.snippetRepo.SetProject(ctx, snippet.ID, snippet.UserID, projectID)
+	return err
+}
+
+// LogFocus records time spent focused on the project
+func (s *ProjectService) LogFocus(ctx context.Context, userID, id uuid.UUID, req *domain.LogFocusRequest) (*domain.FocusSession, error) {
+	if _, err := s.find(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if req.Minutes <= 0 || req.Minutes > domain.MaxFocusMinutes {
+		return nil, ErrFocusMinutes
+	}
+
+	now := time.Now().UTC()
+	startedAt := now.Add(-time.Duration(req.Minutes) * time.Minute)
+	if req.StartedAt != nil {
+		startedAt = req.StartedAt.UTC()
+	}
+	if startedAt.After(now) {
+		return nil, ErrFocusInFuture
+	}
+
+	session := &domain.FocusSession{
+		ID:        uuid.New(),
+		UserID:    userID,
+		ProjectID: id,
+		StartedAt: startedAt,
+		Minutes:   req.Minutes,
+		Note:      req.Note,
+		CreatedAt: now,
+	}
+	if err := s.projectRepo.CreateFocus(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// DeleteFocus removes a focus session from the project and returns it
+func (s *ProjectService) DeleteFocus(ctx context.Context, userID, id, sessionID uuid.UUID) (*domain.FocusSession, error) {
+	if _, err := s.find(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	session, err := s.projectRepo.DeleteFocus(ctx, sessionID, id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrFocusNotFound
+	}
+	return session, nil
+}
+
+// Timeline returns a page of the project's entries, snippets, and focus sessions, newest first.
+// Like the feed, each source is queried for a full page before the cursor and the results merged.
+func (s *ProjectService) Timeline(ctx context.Context, userID, id uuid.UUID, cursor string, limit int) (*domain.TimelinePage, error) {
+	before, err := parseFeedCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	project, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultFeedLimit
+	}
+	if limit > MaxFeedLimit {
+		limit = MaxFeedLimit
+	}
+
+	entries, err := s.journalRepo.FindByProject(ctx, id, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	sealVault(ctx, entries)
+	snippets, err := s.snippetRepo.FindByProject(ctx, id.String(), before, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.projectRepo.FindFocus(ctx, id, before, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.TimelineItem, 0, len(entries)+len(snippets)+len(sessions))
+	for i := range entries {
+		items = append(items, domain.TimelineItem{Type: domain.TimelineEntry, At: entries[i].CreatedAt, Entry: &entries[i]})
+	}
+	for i := range snippets {
+		items = append(items, domain.TimelineItem{Type: domain.TimelineSnippet, At: snippets[i].CreatedAt, Snippet: &snippets[i]})
+	}
+	for i := range sessions {
+		items = append(items, domain.TimelineItem{Type: domain.TimelineFocus, At: sessions[i].StartedAt, Focus: &sessions[i]})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].At.After(items[j].At)
+	})
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	page := &domain.TimelinePage{Project: project, Data: items}
+	if len(items) == limit {
+		page.NextCursor = items[len(items)-1].At.UTC().Format(time.RFC3339Nano)
+	}
+	return page, nil
+}
+
+// find returns one of the user's projects, or ErrProjectNotFound
+func (s *ProjectService) find(ctx context.Context, userID, id uuid.UUID) (*domain.Project, error) {
+	project, err := s.projectRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil || project.UserID != userID {
+		return nil, ErrProjectNotFound
+	}
+	return project, nil
+}
+
+// validateProject checks a project's name and status
+func validateProject(p *domain.Project) error {
+	if p.Name == "" || len(p.Name) > 100 {
+		return ErrProjectName
+	}
+	if !domain.ValidProjectStatus(p.Status) {
+		return ErrInvalidProjectStatus
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/i18n"
+	"devjournal/internal/push"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPushDeviceNotFound   = apperr.New(ErrNotFound, "push device not found")
+	ErrPushPlatformDisabled = apperr.New(ErrUnavailable, "push notifications are not configured for this platform")
+)
+
+const (
+	// pushQueueSize bounds direct messages, and separately streak milestones, waiting to be
+	// notified
+	pushQueueSize = 256
+
+	// pushPreviewLength is how much of a chat message is shown in its notification
+	pushPreviewLength = 120
+
+	// streakReminderBatch is how many reminders one job run sends at most
+	streakReminderBatch = 500
+)
+
+// PushService registers mobile devices and sends them streak reminders, streak milestone and
+// direct message notifications, and delivers notifications for other services such as mentions
+type PushService struct {
+	pushRepo        *postgres.PushRepository
+	settingsService *SettingsService
+	senders         map[string]push.Sender // by platform; a missing platform is not configured
+	chat            chan *domain.ChatMessage
+	milestones      chan domain.StreakMilestone
+}
+
+// NewPushService creates a new push service. senders maps domain.PushPlatform* to the provider
+// for it. Notifications are written in each recipient's locale setting.
+func NewPushService(pushRepo *postgres.PushRepository, settingsService *SettingsService, senders map[string]push.Sender) *PushService {
+	return &PushService{
+		pushRepo:        pushRepo,
+		settingsService: settingsService,
+		senders:         senders,
+		chat:            make(chan *domain.ChatMessage, pushQueueSize),
+		milestones:      make(chan domain.StreakMilestone, pushQueueSize),
+	}
+}
+
+// RecipientLocale returns the locale to notify a user in, by push or email: their locale
+// setting, or English
+func (s *PushService) RecipientLocale(ctx context.Context, userID uuid.UUID) string {
+	if locale := s.settingsService.Locale(ctx, userID); locale != "" {
+		return locale
+	}
+	return i18n.DefaultLocale
+}
+
+// Register saves a device token for the user
+func (s *PushService) Register(ctx context.Context, userID uuid.UUID, req *domain.RegisterPushDeviceRequest) (*domain.PushDevice, error) {
+	req.Token = strings.TrimSpace(req.Token)
+	if req.Token == "" || len(req.Token) > 512 {
+		return nil, apperr.New(ErrValidation, "token is required and must be at most 512 characters")
+	}
+	if req.Platform != domain.PushPlatformIOS && req.Platform != domain.PushPlatformAndroid {
+		return nil, apperr.New(ErrValidation, "platform must be ios or android")
+	}
+	if s.senders[req.Platform] == nil {
+		return nil, ErrPushPlatformDisabled
+	}
+
+	now := time.Now().UTC()
+	device := &domain.PushDevice{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Platform:  req.Platform,
+		Token:     req.Token,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.pushRepo.Register(ctx, device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// List returns the user's registered devices
+func (s *PushService) List(ctx context.Context, userID uuid.UUID) ([]domain.PushDevice, error) {
+	return s.pushRepo.ListByUser(ctx, userID)
+}
+
+// Unregister removes one of the user's devices, e.g. on sign-out
+func (s *PushService) Unregister(ctx context.Context, userID, id uuid.UUID) error {
+	deleted, err := s.pushRepo.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPushDeviceNotFound
+	}
+	return nil
+}
+
+// OnChatMessage queues a broadcast chat message so that, in a direct message room, the
+// recipient is notified. It never blocks the hub.
+func (s *PushService) OnChatMessage(msg *domain.ChatMessage) {
+	if msg.Type != "message" || len(s.senders) == 0 {
+		return
+	}
+	if _, _, ok := domain.DirectRoomMembers(msg.Room); !ok {
+		return
+	}
+	select {
+	case s.chat <- msg:
+	default:
+		log.Printf("WARN: Push queue full, dropping notification for message %s", msg.ID)
+	}
+}
+
+// StreakMilestone queues a congratulation for a user who reached a streak milestone. It is a
+// ProgressService.OnMilestone listener and never blocks the request.
+func (s *PushService) StreakMilestone(_ context.Context, milestone domain.StreakMilestone) {
+	if len(s.senders) == 0 {
+		return
+	}
+	select {
+	case s.milestones <- milestone:
+	default:
+		log.Printf("WARN: Push queue full, dropping %d-day streak notification for %s", milestone.Days, milestone.UserID)
+	}
+}
+
+// Run sends notifications for queued chat messages and streak milestones until ctx is cancelled
+func (s *PushService) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-s.chat:
+			if _, err := s.notifyDirect(ctx, msg); err != nil {
+				log.Printf("WARN: Failed to send push notification for message %s: %v", msg.ID, err)
+			}
+		case milestone := <-s.milestones:
+			locale := s.RecipientLocale(ctx, milestone.UserID)
+			_, err := s.Notify(ctx, milestone.UserID, &push.Notification{
+				Title:       i18n.T(locale, "%d-day streak!", milestone.Days),
+				Body:        i18n.T(locale, "You've learned something every day for %d days. Keep it going!", milestone.Days),
+				Data:        map[string]string{"type": domain.PushStreakMilestone, "achievement": domain.StreakAchievement(milestone.Days)},
+				CollapseKey: domain.PushStreakMilestone,
+			})
+			if err != nil {
+				log.Printf("WARN: Failed to send streak milestone notification to %s: %v", milestone.UserID, err)
+			}
+		}
+	}
+}
+
+// notifyDirect notifies the other user of a direct message room
+func (s *PushService) notifyDirect(ctx context.Context, msg *domain.ChatMessage) (int, error) {
+	a, b, _ := domain.DirectRoomMembers(msg.Room)
+	recipient := a
+	if recipient.String() == msg.UserID {
+		recipient = b
+	}
+	return s.Notify(ctx, recipient, &push.Notification{
+		Title:       msg.UserDisplayName,
+		Body:        preview(msg.Content),
+		Data:        map[string]string{"type": domain.PushDirectMessage, "roomId": msg.Room, "messageId": msg.ID},
+		CollapseKey: msg.Room,
+	})
+}
+
+// StreakReminder returns a job that, once the UTC hour reaches hour, reminds users who were
+// active yesterday but not yet today that their streak is about to end
+func (s *PushService) StreakReminder(hour int) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		now := time.Now().UTC()
+		if len(s.senders) == 0 || now.Hour() < hour {
+			return nil
+		}
+		today := now.Truncate(24 * time.Hour)
+
+		reminders, err := s.pushRepo.ListStreakReminders(ctx, today, streakReminderBatch)
+		if err != nil {
+			return err
+		}
+		for _, reminder := range reminders {
+			// Mark first so a failing provider can't cause repeat reminders every run
+			if err := s.pushRepo.MarkReminded(ctx, reminder.UserID, today); err != nil {
+				return err
+			}
+			locale := s.RecipientLocale(ctx, reminder.UserID)
+			body := streakReminderBody(locale, reminder)
+			if reminder.StreakDays > 1 {
+				body = i18n.T(locale, "Your %d-day streak ends at midnight. Log something today to keep it going.", reminder.StreakDays)
+			}
+			_, err := s.Notify(ctx, reminder.UserID, &push.Notification{
+				Title:       i18n.T(locale, "Keep your streak alive"),
+				Body:        body,
+				Data:        map[string]string{"type": domain.PushStreakReminder},
+				CollapseKey: domain.PushStreakReminder,
+			})
+			if err != nil {
+				log.Printf("WARN: Failed to send streak reminder to %s: %v", reminder.UserID, err)
+			}
+		}
+		return nil
+	}
+}
+
+// streakReminderBody asks a user to do what their streakCounts setting counts toward the streak
+func streakReminderBody(locale string, reminder domain.StreakReminder) string {
+	switch reminder.StreakCounts {
+	case domain.StreakCountsEntries:
+		return i18n.T(locale, "Write an entry today to keep your streak going.")
+	case domain.StreakCountsSnippets:
+		return i18n.T(locale, "Save a snippet today to keep your streak going.")
+	case domain.StreakCountsEntriesOrSnippets:
+		return i18n.T(locale, "Write an entry or save a snippet today to keep your streak going.")
+	case domain.StreakCountsMinutes:
+		return i18n.T(locale, "Log %d minutes of learning today to keep your streak going.", reminder.StreakMinutes)
+	default:
+		return i18n.T(locale, "Write an entry, snippet, or TIL today to keep your streak going.")
+	}
+}
+
+// Notify sends a notification to every device a user has, forgetting tokens the provider no
+// longer accepts. It returns how many devices it reached.
+func (s *PushService) Notify(ctx context.Context, userID uuid.UUID, n *push.Notification) (int, error) {
+	if len(s.senders) == 0 {
+		return 0, nil
+	}
+	devices, err := s.pushRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var sent int
+	var failed error
+	for _, device := range devices {
+		sender := s.senders[device.Platform]
+		if sender == nil {
+			continue
+		}
+		err := sender.Send(ctx, device.Token, n)
+		if errors.Is(err, push.ErrUnregistered) {
+			if err := s.pushRepo.DeleteToken(ctx, device.Token); err != nil {
+				log.Printf("WARN: %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			failed = err
+			continue
+		}
+		sent++
+	}
+	return sent, failed
+}
+
+// preview shortens a chat message for a notification body
+func preview(content string) string {
+	content = strings.Join(strings.Fields(content), " ")
+	if utf8.RuneCountInString(content) <= pushPreviewLength {
+		return content
+	}
+	runes := []rune(content)
+	return string(runes[:pushPreviewLength-1]) + "…"
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+// leaderboardSize caps the members shown on a group leaderboard
+const leaderboardSize = 50
+
+var (
+	ErrQuizNotFound     = apperr.New(ErrNotFound, "quiz not found")
+	ErrNotQuizManager   = apperr.New(ErrForbidden, "only group owners and admins can manage quizzes")
+	ErrQuizTitle        = apperr.New(ErrValidation, "title is required and must be at most 100 characters")
+	ErrQuizQuestions    = apperr.New(ErrValidation, fmt.Sprintf("a quiz needs between 1 and %d questions", domain.MaxQuizQuestions))
+	ErrQuizAnswerCount  = apperr.New(ErrValidation, "answers must have one entry per question")
+	ErrQuizAnswerChoice = apperr.New(ErrValidation, "each answer must be an option index, or -1 to skip")
+)
+
+// QuizService handles study group quizzes, attempts, and the group leaderboard
+type QuizService struct {
+	quizRepo    *mongodb.QuizRepository
+	attemptRepo *postgres.QuizAttemptRepository
+	groupRepo   *postgres.StudyGroupRepository
+	userRepo    *postgres.UserRepository
+	publisher   ChatPublisher
+}
+
+// NewQuizService creates a new quiz service. Results are announced to group chat through publisher.
+func NewQuizService(quizRepo *mongodb.QuizRepository, attemptRepo *postgres.QuizAttemptRepository, groupRepo *postgres.StudyGroupRepository, userRepo *postgres.UserRepository, publisher ChatPublisher) *QuizService {
+	return &QuizService{
+		quizRepo:    quizRepo,
+		attemptRepo: attemptRepo,
+		groupRepo:   groupRepo,
+		userRepo:    userRepo,
+		publisher:   publisher,
+	}
+}
+
+// List returns a group's quizzes with the member's best score on each
+func (s *QuizService) List(ctx context.Context, userID, groupID uuid.UUID) ([]domain.Quiz, error) {
+	if _, err := s.checkMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	quizzes, err := s.quizRepo.ListByGroup(ctx, groupID.String())
+	if err != nil {
+		return nil, err
+	}
+	scores, err := s.attemptRepo.BestScores(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range quizzes {
+		if score, ok := scores[quizzes[i].ID]; ok {
+			quizzes[i].BestScore = &score
+		}
+	}
+	return quizzes, nil
+}
+
+// Get returns a quiz with its questions. Answers are hidden unless the member manages quizzes.
+func (s *QuizService) Get(ctx context.Context, userID, groupID uuid.UUID, quizID string) (*domain.Quiz, error) {
+	role, err := s.checkMember(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	quiz, err := s.find(ctx, groupID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if !isGroupManager(role) {
+		quiz.Redact()
+	}
+	scores, err := s.attemptRepo.BestScores(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if score, ok := scores[quiz.ID]; ok {
+		quiz.BestScore = &score
+	}
+	return quiz, nil
+}
+
+// Create adds a quiz to a group
+func (s *QuizService) Create(ctx context.Context, userID, groupID uuid.UUID, req *domain.QuizRequest) (*domain.Quiz, error) {
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	quiz := &domain.Quiz{
+		GroupID:       groupID.String(),
+		CreatedBy:     userID.String(),
+		Title:         strings.TrimSpace(req.Title),
+		Description:   req.Description,
+		Questions:     req.Questions,
+		QuestionCount: len(req.Questions),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := validateQuiz(quiz); err != nil {
+		return nil, err
+	}
+	if err := s.quizRepo.Create(ctx, quiz); err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+// Update replaces a quiz's title, description, and questions. Earlier attempts keep their scores.
+func (s *QuizService) Update(ctx context.Context, userID, groupID uuid.UUID, quizID string, req *domain.QuizRequest) (*domain.Quiz, error) {
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	quiz, err := s.find(ctx, groupID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	quiz.Title = strings.TrimSpace(req.Title)
+	quiz.Description = req.Description
+	quiz.Questions = req.Questions
+	quiz.QuestionCount = len(req.Questions)
+	if err := validateQuiz(quiz); err != nil {
+		return nil, err
+	}
+	quiz.UpdatedAt = time.Now().UTC()
+	if err := s.quizRepo.Update(ctx, quiz); err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+// Delete removes a quiz and its attempts, taking its points off the leaderboard
+func (s *QuizService) Delete(ctx context.Context, userID, groupID uuid.UUID, quizID string) error {
+	if err := s.checkManager(ctx, groupID, userID); err != nil {
+		return err
+	}
+	quiz, err := s.find(ctx, groupID, quizID)
+	if err != nil {
+		return err
+	}
+	if err := s.quizRepo.Delete(ctx, quiz.ID, quiz.GroupID); err != nil {
+		return err
+	}
+	return s.attemptRepo.DeleteByQuiz(ctx, quiz.ID)
+}
+
+// Submit grades a member's answers, records the attempt, and announces the score in group chat
+func (s *QuizService) Submit(ctx context.Context, userID, groupID uuid.UUID, quizID string, req *domain.QuizAttemptRequest) (*domain.QuizAttempt, error) {
+	if _, err := s.checkMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	quiz, err := s.find(ctx, groupID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Answers) != len(quiz.Questions) {
+		return nil, ErrQuizAnswerCount
+	}
+	for i, answer := range req.Answers {
+		if answer < -1 || answer >= len(quiz.Questions[i].Options) {
+			return nil, ErrQuizAnswerChoice
+		}
+	}
+
+	score, results := domain.GradeQuiz(quiz.Questions, req.Answers)
+	attempt := &domain.QuizAttempt{
+		ID:        uuid.New(),
+		QuizID:    quiz.ID,
+		GroupID:   groupID,
+		UserID:    userID,
+		Score:     score,
+		Total:     len(quiz.Questions),
+		Results:   results,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.attemptRepo.Create(ctx, attempt); err != nil {
+		return nil, err
+	}
+
+	s.announce(ctx, quiz, attempt)
+	return attempt, nil
+}
+
+// Leaderboard ranks a group's members by their quiz points
+func (s *QuizService) Leaderboard(ctx context.Context, userID, groupID uuid.UUID) ([]domain.LeaderboardEntry, error) {
+	if _, err := s.checkMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	return s.attemptRepo.Leaderboard(ctx, groupID, leaderboardSize)
+}
+
+// announce posts an attempt's score to the group's chat. Failures are logged, since the
+// attempt is already recorded.
+func (s *QuizService) announce(ctx context.Context, quiz *domain.Quiz, attempt *domain.QuizAttempt) {
+	user, err := s.userRepo.FindByID(ctx, attempt.UserID)
+	if err != nil || user == nil {
+		log.Printf("WARN: Failed to find user %s to announce quiz score: %v", attempt.UserID, err)
+		return
+	}
+	content := fmt.Sprintf("%s scored %d/%d on the quiz %q", user.DisplayName, attempt.Score, attempt.Total, quiz.Title)
+	msg := domain.NewChatMessage(quiz.GroupID, attempt.UserID.String(), user.DisplayName, content, "system")
+	if err := s.publisher.Publish(ctx, msg); err != nil {
+		log.Printf("WARN: Failed to announce quiz score in group %s: %v", quiz.GroupID, err)
+	}
+}
+
+// find returns a quiz of the group, or ErrQuizNotFound
+func (s *QuizService) find(ctx context.Context, groupID uuid.UUID, quizID string) (*domain.Quiz, error) {
+	quiz, err := s.quizRepo.FindByID(ctx, quizID, groupID.String())
+	if err != nil {
+		return nil, err
+	}
+	if quiz == nil {
+		return nil, ErrQuizNotFound
+	}
+	return quiz, nil
+}
+
+// checkMember returns the user's role in the group, or an error unless they belong to it
+func (s *QuizService) checkMember(ctx context.Context, groupID, userID uuid.UUID) (string, error) {
+	role, err := groupRole(ctx, s.groupRepo, groupID, userID)
+	if err != nil {
+		return "", err
+	}
+	return role, nil
+}
+
+func (s *QuizService) checkManager(ctx context.Context, groupID, userID uuid.UUID) error {
+	role, err := s.checkMember(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if !isGroupManager(role) {
+		return ErrNotQuizManager
+	}
+	return nil
+}
+
+// validateQuiz checks a quiz's title and that every question has options and a valid answer
+func validateQuiz(q *domain.Quiz) error {
+	if q.Title == "" || len(q.Title) > 100 {
+		return ErrQuizTitle
+	}
+	if len(q.Questions) == 0 || len(q.Questions) > domain.MaxQuizQuestions {
+		return ErrQuizQuestions
+	}
+	for i, question := range q.Questions {
+		n := i + 1
+		if strings.TrimSpace(question.Prompt) == "" {
+			return apperr.New(ErrValidation, fmt.Sprintf("question %d needs a prompt", n))
+		}
+		if len(question.Options) < 2 || len(question.Options) > domain.MaxQuizOptions {
+			return apperr.New(ErrValidation, fmt.Sprintf("question %d needs between 2 and %d options", n, domain.MaxQuizOptions))
+		}
+		for _, option := range question.Options {
+			if strings.TrimSpace(option) == "" {
+				return apperr.New(ErrValidation, fmt.Sprintf("question %d has an empty option", n))
+			}
+		}
+		if question.Answer == nil || *question.Answer < 0 || *question.Answer >= len(question.Options) {
+			return apperr.New(ErrValidation, fmt.Sprintf("question %d needs an answer that is one of its option indexes", n))
+		}
+	}
+	return nil
+}
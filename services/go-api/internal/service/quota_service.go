@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+
+	"github.com/google/uuid"
+)
+
+// QuotaExceededError is returned when an action needs a higher plan
+type QuotaExceededError struct {
+	Plan     string // the user's current plan
+	Resource string // e.g. "snippets", "workspaces", "seats", or a gated feature
+	Limit    int    // 0 for gated features
+}
+
+func (e *QuotaExceededError) Error() string {
+	if e.Limit == 0 {
+		return fmt.Sprintf("%s is not included in the %s plan", e.Resource, e.Plan)
+	}
+	return fmt.Sprintf("the %s plan allows at most %d %s", e.Plan, e.Limit, e.Resource)
+}
+
+func (e *QuotaExceededError) Unwrap() error { return ErrPaymentRequired }
+
+// Details exposes the plan and limit so clients can offer the right upgrade
+func (e *QuotaExceededError) Details() map[string]interface{} {
+	return map[string]interface{}{"plan": e.Plan, "resource": e.Resource, "limit": e.Limit}
+}
+
+// QuotaService enforces plan limits and plan-gated features.
+// When billing is disabled every user is unlimited.
+type QuotaService struct {
+	subscriptionRepo *postgres.SubscriptionRepository
+	workspaceRepo    *postgres.WorkspaceRepository
+	snippetRepo      *mongodb.SnippetRepository
+	enabled          bool
+}
+
+// NewQuotaService creates a new quota service; enabled is false when billing is not configured
+func NewQuotaService(subscriptionRepo *postgres.SubscriptionRepository, workspaceRepo *postgres.WorkspaceRepository, snippetRepo *mongodb.SnippetRepository, enabled bool) *QuotaService {
+	return &QuotaService{
+		subscriptionRepo: subscriptionRepo,
+		workspaceRepo:    workspaceRepo,
+		snippetRepo:      snippetRepo,
+		enabled:          enabled,
+	}
+}
+
+// Plan returns the plan currently granted to a user
+func (s *QuotaService) Plan(ctx context.Context, userID uuid.UUID) (*domain.Plan, error) {
+	sub, err := s.subscriptionRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	plan := domain.FindPlan(sub.EffectivePlan())
+	if plan == nil {
+		plan = domain.FindPlan(domain.PlanFree)
+	}
+	return plan, nil
+}
+
+// CheckSnippets fails if the user cannot create another snippet
+func (s *QuotaService) CheckSnippets(ctx context.Context, userID string) error {
+	if !s.enabled {
+		return nil
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+	plan, err := s.Plan(ctx, uid)
+	if err != nil || plan.Limits.MaxSnippets == 0 {
+		return err
+	}
+	count, err := s.snippetRepo.CountAllWorkspaces(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if count >= int64(plan.Limits.MaxSnippets) {
+		return &QuotaExceededError{Plan: plan.ID, Resource: "snippets", Limit: plan.Limits.MaxSnippets}
+	}
+	return nil
+}
+
+// CheckWorkspaces fails if the user cannot own another shared workspace or organization
+func (s *QuotaService) CheckWorkspaces(ctx context.Context, userID uuid.UUID) error {
+	if !s.enabled {
+		return nil
+	}
+	plan, err := s.Plan(ctx, userID)
+	if err != nil || plan.Limits.MaxWorkspaces == 0 {
+		return err
+	}
+	count, err := s.workspaceRepo.CountOwned(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if count >= plan.Limits.MaxWorkspaces {
+		return &QuotaExceededError{Plan: plan.ID, Resource: "workspaces", Limit: plan.Limits.MaxWorkspaces}
+	}
+	return nil
+}
+
+// CheckSeats fails if an organization owned by ownerID cannot have seats seats
+func (s *QuotaService) CheckSeats(ctx context.Context, ownerID uuid.UUID, seats int) error {
+	if !s.enabled {
+		return nil
+	}
+	plan, err := s.Plan(ctx, ownerID)
+	if err != nil || plan.Limits.MaxSeats == 0 {
+		return err
+	}
+	if seats > plan.Limits.MaxSeats {
+		return &QuotaExceededError{Plan: plan.ID, Resource: "seats", Limit: plan.Limits.MaxSeats}
+	}
+	return nil
+}
+
+// RequireFeature fails unless the user's plan includes a gated feature
+func (s *QuotaService) RequireFeature(ctx context.Context, userID uuid.UUID, feature string) error {
+	if !s.enabled {
+		return nil
+	}
+	plan, err := s.Plan(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !plan.HasFeature(feature) {
+		return &QuotaExceededError{Plan: plan.ID, Resource: feature}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/internal/vault"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReviewItemNotFound  = apperr.New(ErrNotFound, "review item not found")
+	ErrInvalidReviewResult = apperr.New(ErrValidation, "result must be got_it or again")
+	ErrInvalidReviewType   = apperr.New(ErrValidation, "type must be journal or snippet")
+)
+
+const (
+	// reviewMinAge keeps brand-new entries and snippets out of review until they're at least a day old
+	reviewMinAge = 24 * time.Hour
+
+	// maxStaleReviewItems bounds how many orphaned review items Next cleans up in one call
+	maxStaleReviewItems = 10
+)
+
+// ReviewService resurfaces past journal entries and snippets on a spaced-repetition schedule
+type ReviewService struct {
+	reviewRepo  *postgres.ReviewRepository
+	journalRepo *postgres.JournalRepository
+	snippetRepo *mongodb.SnippetRepository
+}
+
+// NewReviewService creates a new review service
+func NewReviewService(reviewRepo *postgres.ReviewRepository, journalRepo *postgres.JournalRepository, snippetRepo *mongodb.SnippetRepository) *ReviewService {
+	return &ReviewService{
+		reviewRepo:  reviewRepo,
+		journalRepo: journalRepo,
+		snippetRepo: snippetRepo,
+	}
+}
+
+// Next returns the next item to review: the most overdue scheduled item, otherwise a
+// random past entry or snippet that is enrolled on the spot. Returns nil when there is nothing to review.
+func (s *ReviewService) Next(ctx context.Context, userID uuid.UUID, itemType string) (*domain.ReviewCard, error) {
+	if itemType != "" && itemType != domain.ReviewItemJournal && itemType != domain.ReviewItemSnippet {
+		return nil, ErrInvalidReviewType
+	}
+
+	now := time.Now().UTC()
+	for range maxStaleReviewItems {
+		item, err := s.reviewRepo.FindDue(ctx, userID, itemType, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find due review item: %w", err)
+		}
+		if item == nil {
+			break
+		}
+
+		card, err := s.loadCard(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		if card != nil {
+			return card, nil
+		}
+
+		// The entry or snippet was deleted; drop it from the schedule and try the next one
+		if err := s.reviewRepo.Delete(ctx, item.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.enrollNew(ctx, userID, itemType, now.Add(-reviewMinAge))
+}
+
+// Feedback records a "got it / review again" result and reschedules the item
+func (s *ReviewService) Feedback(ctx context.Context, userID, itemID uuid.UUID, result string) (*domain.ReviewItem, error) {
+	if result != domain.ReviewGotIt && result != domain.ReviewAgain {
+		return nil, ErrInvalidReviewResult
+	}
+
+	item, err := s.reviewRepo.FindByID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find review item: %w", err)
+	}
+	if item == nil || item.UserID != userID {
+		return nil, ErrReviewItemNotFound
+	}
+
+	item.Schedule(result, time.Now().UTC())
+	if err := s.reviewRepo.UpdateSchedule(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to reschedule review item: %w", err)
+	}
+
+	return item, nil
+}
+
+// loadCard fetches the content behind a review item (nil if it no longer exists)
+func (s *ReviewService) loadCard(ctx context.Context, item *domain.ReviewItem) (*domain.ReviewCard, error) {
+	switch item.ItemType {
+	case domain.ReviewItemJournal:
+		entryID, err := uuid.Parse(item.ItemID)
+		if err != nil {
+			return nil, nil
+		}
+		entry, err := s.journalRepo.FindByID(ctx, entryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load journal entry for review: %w", err)
+		}
+		if entry == nil || entry.UserID != item.UserID {
+			return nil, nil
+		}
+		if entry.IsVault && !vault.Unlocked(ctx) {
+			entry.Seal()
+		}
+		return &domain.ReviewCard{Item: item, Entry: entry}, nil
+
+	case domain.ReviewItemSnippet:
+		snippet, err := s.snippetRepo.FindByID(ctx, item.ItemID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load snippet for review: %w", err)
+		}
+		if snippet == nil || snippet.UserID != item.UserID.String() {
+			return nil, nil
+		}
+		return &domain.ReviewCard{Item: item, Snippet: snippet}, nil
+	}
+	return nil, nil
+}
+
+// enrollNew picks a random unscheduled entry or snippet created before the cutoff and schedules it
+func (s *ReviewService) enrollNew(ctx context.Context, userID uuid.UUID, itemType string, before time.Time) (*domain.ReviewCard, error) {
+	types := []string{domain.ReviewItemJournal, domain.ReviewItemSnippet}
+	if itemType != "" {
+		types = []string{itemType}
+	} else if rand.Intn(2) == 1 {
+		types[0], types[1] = types[1], types[0]
+	}
+
+	for _, t := range types {
+		var card *domain.ReviewCard
+
+		switch t {
+		case domain.ReviewItemJournal:
+			entryID, err := s.reviewRepo.FindUnscheduledEntryID(ctx, userID, before)
+			if err != nil {
+				return nil, err
+			}
+			if entryID == uuid.Nil {
+				continue
+			}
+			entry, err := s.journalRepo.FindByID(ctx, entryID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load journal entry for review: %w", err)
+			}
+			if entry == nil {
+				continue
+			}
+			card = &domain.ReviewCard{Item: domain.NewReviewItem(userID, t, entry.ID.String()), Entry: entry}
+
+		case domain.ReviewItemSnippet:
+			scheduled, err := s.reviewRepo.ListItemIDs(ctx, userID, t)
+			if err != nil {
+				return nil, err
+			}
+			snippet, err := s.snippetRepo.SampleExcluding(ctx, userID.String(), scheduled, before)
+			if err != nil {
+				return nil, fmt.Errorf("failed to pick snippet for review: %w", err)
+			}
+			if snippet == nil {
+				continue
+			}
+			card = &domain.ReviewCard{Item: domain.NewReviewItem(userID, t, snippet.ID), Snippet: snippet}
+		}
+
+		if err := s.reviewRepo.Create(ctx, card.Item); err != nil {
+			return nil, fmt.Errorf("failed to schedule review item: %w", err)
+		}
+		return card, nil
+	}
+
+	return nil, nil
+}
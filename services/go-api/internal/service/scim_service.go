@@ -0,0 +1,454 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSCIMUserNotFound = apperr.New(ErrNotFound, "user not found")
+	ErrSCIMUserName     = apperr.New(ErrValidation, "userName must be the user's email address")
+	ErrSCIMFilter       = apperr.New(ErrValidation, `unsupported filter; use userName eq "..." or externalId eq "..."`)
+	ErrSCIMPatchValue   = apperr.New(ErrValidation, "invalid patch value")
+	ErrSCIMExternalID   = apperr.New(ErrConflict, "externalId is already in use")
+)
+
+const (
+	// scimIssuer is the user_identities issuer that externalIds are linked under
+	scimIssuer = "scim"
+
+	// scimMaxCount bounds a page of users
+	scimMaxCount = 100
+)
+
+// scimFilter matches the filters identity providers send to look a user up before creating them
+var scimFilter = regexp.MustCompile(`^(?i:(userName|externalId))\s+(?i:eq)\s+"((?:[^"\\]|\\.)*)"$`)
+
+// SCIMService provisions and deprovisions users for an identity provider over SCIM 2.0.
+// Deleting a user deactivates them rather than erasing their journal.
+type SCIMService struct {
+	userRepo     *postgres.UserRepository
+	identityRepo *postgres.IdentityRepository
+	authService  *AuthService
+}
+
+// NewSCIMService creates a new SCIM service
+func NewSCIMService(userRepo *postgres.UserRepository, identityRepo *postgres.IdentityRepository, authService *AuthService) *SCIMService {
+	return &SCIMService{userRepo: userRepo, identityRepo: identityRepo, authService: authService}
+}
+
+// List returns a page of users, optionally filtered by userName or externalId. startIndex is
+// 1-based, as in SCIM.
+func (s *SCIMService) List(ctx context.Context, filter string, startIndex, count int) (*domain.SCIMListResponse, error) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = 0
+	}
+	if count > scimMaxCount {
+		count = scimMaxCount
+	}
+
+	var users []domain.User
+	total := 0
+	if filter == "" {
+		var err error
+		users, total, err = s.userRepo.List(ctx, count, startIndex-1)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		user, err := s.findByFilter(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil {
+			total = 1
+			if startIndex == 1 && count > 0 {
+				users = []domain.User{*user}
+			}
+		}
+	}
+
+	ids := make([]uuid.UUID, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	externalIDs, err := s.identityRepo.ListSubjects(ctx, scimIssuer, ids)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]domain.SCIMUser, len(users))
+	for i := range users {
+		resources[i] = toSCIMUser(&users[i], externalIDs[users[i].ID])
+	}
+	return &domain.SCIMListResponse{
+		Schemas:      []string{domain.SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, nil
+}
+
+// findByFilter finds the user a filter matches, or nil
+func (s *SCIMService) findByFilter(ctx context.Context, filter string) (*domain.User, error) {
+	m := scimFilter.FindStringSubmatch(strings.TrimSpace(filter))
+	if m == nil {
+		return nil, ErrSCIMFilter
+	}
+	value, err := strconv.Unquote(`"` + m[2] + `"`)
+	if err != nil {
+		return nil, ErrSCIMFilter
+	}
+
+	if strings.EqualFold(m[1], "userName") {
+		user, err := s.userRepo.FindByEmail(ctx, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+		return user, nil
+	}
+	userID, err := s.identityRepo.FindUserID(ctx, scimIssuer, value)
+	if err != nil || userID == uuid.Nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return user, nil
+}
+
+// Get returns a user
+func (s *SCIMService) Get(ctx context.Context, id string) (*domain.SCIMUser, error) {
+	user, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.present(ctx, user)
+}
+
+// Create provisions a user. They sign in through single sign-on, so their password is random.
+func (s *SCIMService) Create(ctx context.Context, in *domain.SCIMUser) (*domain.SCIMUser, error) {
+	email, err := scimEmail(in)
+	if err != nil {
+		return nil, err
+	}
+	if in.ExternalID != "" {
+		if linked, err := s.identityRepo.FindUserID(ctx, scimIssuer, in.ExternalID); err != nil {
+			return nil, err
+		} else if linked != uuid.Nil {
+			return nil, ErrSCIMExternalID
+		}
+	}
+	user, err := s.authService.Provision(ctx, email, scimDisplayName(in, email))
+	if err != nil {
+		return nil, err
+	}
+	if in.Active != nil && !*in.Active {
+		if _, err := s.authService.Deactivate(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.linkExternalID(ctx, user.ID, email, in.ExternalID); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, user.ID.String())
+}
+
+// Replace sets all of a user's provisioned attributes
+func (s *SCIMService) Replace(ctx context.Context, id string, in *domain.SCIMUser) (*domain.SCIMUser, error) {
+	user, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	email, err := scimEmail(in)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.update(ctx, user, email, scimDisplayName(in, email)); err != nil {
+		return nil, err
+	}
+	if in.Active != nil {
+		if err := s.setActive(ctx, user.ID, *in.Active); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.linkExternalID(ctx, user.ID, email, in.ExternalID); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, id)
+}
+
+// Patch applies add, replace, and remove operations to a user. Providers deprovision users by
+// replacing active with false.
+func (s *SCIMService) Patch(ctx context.Context, id string, req *domain.SCIMPatchRequest) (*domain.SCIMUser, error) {
+	user, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	externalID, err := s.identityRepo.FindSubject(ctx, user.ID, scimIssuer)
+	if err != nil {
+		return nil, err
+	}
+
+	email, displayName := user.Email, user.DisplayName
+	var active *bool
+	for _, op := range req.Operations {
+		kind := strings.ToLower(op.Op)
+		if kind != "add" && kind != "replace" && kind != "remove" {
+			return nil, apperr.Newf(ErrValidation, "unsupported patch operation %q", op.Op)
+		}
+
+		// Without a path the value is an object of attributes to set, as Okta sends
+		values := map[string]json.RawMessage{}
+		if op.Path == "" {
+			if kind == "remove" || json.Unmarshal(op.Value, &values) != nil {
+				return nil, ErrSCIMPatchValue
+			}
+		} else {
+			values[op.Path] = op.Value
+		}
+
+		for path, raw := range values {
+			switch strings.ToLower(path) {
+			case "active":
+				if kind == "remove" {
+					return nil, ErrSCIMPatchValue
+				}
+				v, err := scimBool(raw)
+				if err != nil {
+					return nil, err
+				}
+				active = &v
+			case "username", `emails[type eq "work"].value`, "emails":
+				v, err := scimPatchEmail(raw)
+				if err != nil || kind == "remove" {
+					return nil, ErrSCIMPatchValue
+				}
+				email = v
+			case "displayname", "name.formatted":
+				if kind == "remove" {
+					continue
+				}
+				if json.Unmarshal(raw, &displayName) != nil {
+					return nil, ErrSCIMPatchValue
+				}
+			case "externalid":
+				externalID = ""
+				if kind != "remove" && json.Unmarshal(raw, &externalID) != nil {
+					return nil, ErrSCIMPatchValue
+				}
+			case "name", "name.givenname", "name.familyname", "title", "preferredlanguage", "locale", "timezone":
+				// Attributes we don't store
+			default:
+				return nil, apperr.Newf(ErrValidation, "unsupported patch path %q", path)
+			}
+		}
+	}
+
+	if strings.TrimSpace(displayName) == "" {
+		displayName = user.DisplayName
+	}
+	if err := s.update(ctx, user, email, displayName); err != nil {
+		return nil, err
+	}
+	if active != nil {
+		if err := s.setActive(ctx, user.ID, *active); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.linkExternalID(ctx, user.ID, email, externalID); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, id)
+}
+
+// Delete deprovisions a user. Their account is deactivated, not erased, so a mistake in the
+// provider doesn't cost anyone their journal.
+func (s *SCIMService) Delete(ctx context.Context, id string) error {
+	user, err := s.find(ctx, id)
+	if err != nil {
+		return err
+	}
+	_, err = s.authService.Deactivate(ctx, user.ID)
+	return err
+}
+
+func (s *SCIMService) find(ctx context.Context, id string) (*domain.User, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrSCIMUserNotFound
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrSCIMUserNotFound
+	}
+	return user, nil
+}
+
+func (s *SCIMService) present(ctx context.Context, user *domain.User) (*domain.SCIMUser, error) {
+	externalID, err := s.identityRepo.FindSubject(ctx, user.ID, scimIssuer)
+	if err != nil {
+		return nil, err
+	}
+	out := toSCIMUser(user, externalID)
+	return &out, nil
+}
+
+// update changes a user's email and display name, refusing an email another account has
+func (s *SCIMService) update(ctx context.Context, user *domain.User, email, displayName string) error {
+	if strings.EqualFold(email, user.Email) && displayName == user.DisplayName {
+		return nil
+	}
+	if !strings.EqualFold(email, user.Email) {
+		existing, err := s.userRepo.FindByEmail(ctx, email)
+		if err != nil {
+			return fmt.Errorf("failed to check existing user: %w", err)
+		}
+		if existing != nil && existing.ID != user.ID {
+			return ErrEmailAlreadyExists
+		}
+	}
+	user.Email = email
+	user.DisplayName = displayName
+	user.UpdatedAt = time.Now().UTC()
+	return s.userRepo.Update(ctx, user)
+}
+
+func (s *SCIMService) setActive(ctx context.Context, userID uuid.UUID, active bool) error {
+	if active {
+		return s.authService.Reactivate(ctx, userID)
+	}
+	_, err := s.authService.Deactivate(ctx, userID)
+	return err
+}
+
+// linkExternalID records the provider's ID for a user, so it can look them up by it
+func (s *SCIMService) linkExternalID(ctx context.Context, userID uuid.UUID, email, externalID string) error {
+	current, err := s.identityRepo.FindSubject(ctx, userID, scimIssuer)
+	if err != nil || current == externalID {
+		return err
+	}
+	if err := s.identityRepo.Unlink(ctx, userID, scimIssuer); err != nil {
+		return err
+	}
+	if externalID == "" {
+		return nil
+	}
+	if linked, err := s.identityRepo.FindUserID(ctx, scimIssuer, externalID); err != nil {
+		return err
+	} else if linked != uuid.Nil {
+		return ErrSCIMExternalID
+	}
+	now := time.Now().UTC()
+	return s.identityRepo.Link(ctx, &domain.UserIdentity{
+		UserID:      userID,
+		Issuer:      scimIssuer,
+		Subject:     externalID,
+		Email:       email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	})
+}
+
+func toSCIMUser(user *domain.User, externalID string) domain.SCIMUser {
+	active := user.DeactivatedAt == nil
+	return domain.SCIMUser{
+		Schemas:     []string{domain.SCIMSchemaUser},
+		ID:          user.ID.String(),
+		ExternalID:  externalID,
+		UserName:    user.Email,
+		Name:        &domain.SCIMName{Formatted: user.DisplayName},
+		DisplayName: user.DisplayName,
+		Emails:      []domain.SCIMEmail{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &domain.SCIMMeta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     "/scim/v2/Users/" + user.ID.String(),
+		},
+	}
+}
+
+// scimEmail is the email a SCIM user signs in with: their userName, or else their primary email
+func scimEmail(in *domain.SCIMUser) (string, error) {
+	email := strings.TrimSpace(in.UserName)
+	if !strings.Contains(email, "@") {
+		email = ""
+		for _, e := range in.Emails {
+			if e.Primary || email == "" {
+				email = strings.TrimSpace(e.Value)
+			}
+		}
+	}
+	if !strings.Contains(email, "@") {
+		return "", ErrSCIMUserName
+	}
+	return email, nil
+}
+
+func scimDisplayName(in *domain.SCIMUser, email string) string {
+	if name := strings.TrimSpace(in.DisplayName); name != "" {
+		return name
+	}
+	if in.Name != nil {
+		if name := strings.TrimSpace(in.Name.Formatted); name != "" {
+			return name
+		}
+		if name := strings.TrimSpace(in.Name.GivenName + " " + in.Name.FamilyName); name != "" {
+			return name
+		}
+	}
+	name, _, _ := strings.Cut(email, "@")
+	return name
+}
+
+// scimBool decodes a boolean patch value. Microsoft Entra ID sends them as the strings "True" and
+// "False".
+func scimBool(raw json.RawMessage) (bool, error) {
+	var v bool
+	if json.Unmarshal(raw, &v) == nil {
+		return v, nil
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+	return false, ErrSCIMPatchValue
+}
+
+// scimPatchEmail decodes an email patch value, either a string or a list of emails
+func scimPatchEmail(raw json.RawMessage) (string, error) {
+	var email string
+	if json.Unmarshal(raw, &email) != nil {
+		var emails []domain.SCIMEmail
+		if err := json.Unmarshal(raw, &emails); err != nil {
+			return "", err
+		}
+		email, _ = scimEmail(&domain.SCIMUser{Emails: emails})
+	}
+	if email = strings.TrimSpace(email); !strings.Contains(email, "@") {
+		return "", ErrSCIMPatchValue
+	}
+	return email, nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/ogimage"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+
+	"github.com/google/uuid"
+)
+
+// siteName is the og:site_name of public pages
+const siteName = "DevJournal"
+
+// sitemapProfiles caps the profiles listed in the sitemap, leaving the rest for snippets
+const sitemapProfiles = 10000
+
+// SEOService describes public snippets and profiles for search engines and link previews
+type SEOService struct {
+	snippetRepo    *mongodb.SnippetRepository
+	journalRepo    *postgres.JournalRepository
+	userRepo       *postgres.UserRepository
+	followRepo     *postgres.FollowRepository
+	progressRepo   *postgres.ProgressRepository
+	snippetPageURL string
+	profilePageURL string
+	oembedURL      string
+	imageURL       string
+}
+
+// NewSEOService creates a new SEO service. snippetPageURL and profilePageURL are the bases of
+// snippet and profile pages in the web app; snippet IDs and user IDs are appended to them.
+// embedURL is the public base of snippet embeds, whose oEmbed and share image endpoints share its origin.
+func NewSEOService(snippetRepo *mongodb.SnippetRepository, journalRepo *postgres.JournalRepository, userRepo *postgres.UserRepository, followRepo *postgres.FollowRepository, progressRepo *postgres.ProgressRepository, snippetPageURL, profilePageURL, embedURL string) *SEOService {
+	return &SEOService{
+		snippetRepo:    snippetRepo,
+		journalRepo:    journalRepo,
+		userRepo:       userRepo,
+		followRepo:     followRepo,
+		progressRepo:   progressRepo,
+		snippetPageURL: strings.TrimRight(snippetPageURL, "/"),
+		profilePageURL: strings.TrimRight(profilePageURL, "/"),
+		oembedURL:      origin(embedURL) + "/api/v1/oembed?format=json&url=",
+		imageURL:       origin(embedURL) + "/api/v1/public/og",
+	}
+}
+
+// Sitemap lists the pages of public snippets and, when profiles is set, the profiles of their
+// authors and of users with public journal entries, most recently updated first
+func (s *SEOService) Sitemap(ctx context.Context, snippets, profiles bool) ([]domain.SitemapURL, error) {
+	authors := make(map[uuid.UUID]time.Time)
+	if profiles {
+		var err error
+		if authors, err = s.journalRepo.PublicAuthors(ctx, sitemapProfiles); err != nil {
+			return nil, err
+		}
+	}
+
+	var urls []domain.SitemapURL
+	if snippets {
+		public, err := s.snippetRepo.FindPublicForSitemap(ctx, domain.MaxSitemapURLs-sitemapProfiles)
+		if err != nil {
+			return nil, err
+		}
+		for _, snippet := range public {
+			urls = append(urls, domain.SitemapURL{Loc: s.snippetPageURL + "/" + snippet.ID, LastMod: snippet.UpdatedAt})
+			if !profiles {
+				continue
+			}
+			// Snippets come newest first, so an author's first snippet is their latest
+			authorID, err := uuid.Parse(snippet.UserID)
+			if err != nil {
+				continue
+			}
+			lastMod, listed := authors[authorID]
+			if (listed && snippet.UpdatedAt.After(lastMod)) || (!listed && len(authors) < sitemapProfiles) {
+				authors[authorID] = snippet.UpdatedAt
+			}
+		}
+	}
+
+	for userID, lastMod := range authors {
+		urls = append(urls, domain.SitemapURL{Loc: s.profilePageURL + "/" + userID.String(), LastMod: lastMod})
+	}
+	sort.SliceStable(urls, func(i, j int) bool { return urls[i].LastMod.After(urls[j].LastMod) })
+	return urls, nil
+}
+
+// SnippetMeta describes a public snippet's page. Private and hidden snippets are not found.
+func (s *SEOService) SnippetMeta(ctx context.Context, slug string) (*domain.PageMeta, error) {
+	snippet, err := findPublicSnippet(ctx, s.snippetRepo, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	authorName, err := s.authorName(ctx, snippet.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	description := snippet.Description
+	if strings.TrimSpace(description) == "" {
+		description = fmt.Sprintf("A %s snippet by %s on %s", languageName(snippet.Language), authorName, siteName)
+	}
+	code := ""
+	if len(snippet.Files) > 0 {
+		code = snippet.Files[0].Code
+	}
+	subtitle := fmt.Sprintf("by %s", authorName)
+	if len(snippet.Files) > 1 {
+		subtitle += fmt.Sprintf(" · %d files", len(snippet.Files))
+	}
+
+	pageURL := s.snippetPageURL + "/" + snippet.ID
+	return &domain.PageMeta{
+		Type:        "article",
+		Title:       snippet.Title,
+		Description: domain.MetaDescription(description),
+		URL:         pageURL,
+		SiteName:    siteName,
+		AuthorName:  authorName,
+		Tags:        snippet.Tags,
+		PublishedAt: &snippet.CreatedAt,
+		ModifiedAt:  &snippet.UpdatedAt,
+		Image: domain.ShareImage{
+			Title:    snippet.Title,
+			Subtitle: subtitle,
+			Language: snippet.Language,
+			Lines:    domain.ShareImageLines(code),
+		},
+		ImageURL:  s.imageURL + "/snippets/" + snippet.ID + ".png",
+		OEmbedURL: s.oembedURL + url.QueryEscape(pageURL),
+	}, nil
+}
+
+// ProfileMeta describes a user's public profile page
+func (s *SEOService) ProfileMeta(ctx context.Context, userID uuid.UUID) (*domain.PageMeta, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	followers, _, err := s.followRepo.Counts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	subtitle := fmt.Sprintf("%d followers · joined %s", followers, user.CreatedAt.Format("January 2006"))
+	if followers == 1 {
+		subtitle = fmt.Sprintf("1 follower · joined %s", user.CreatedAt.Format("January 2006"))
+	}
+	return &domain.PageMeta{
+		Type:        "profile",
+		Title:       fmt.Sprintf("%s on %s", user.DisplayName, siteName),
+		Description: domain.MetaDescription(fmt.Sprintf("Public journal entries and code snippets by %s. %s.", user.DisplayName, subtitle)),
+		URL:         s.profilePageURL + "/" + user.ID.String(),
+		SiteName:    siteName,
+		AuthorName:  user.DisplayName,
+		Image: domain.ShareImage{
+			Title:    user.DisplayName,
+			Subtitle: subtitle,
+		},
+	}, nil
+}
+
+// SnippetCard returns the share image card of a public snippet
+func (s *SEOService) SnippetCard(ctx context.Context, slug string) (*ogimage.Card, error) {
+	snippet, err := findPublicSnippet(ctx, s.snippetRepo, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.card(ctx, "Snippet", snippet.Title, snippet.UserID, snippet.Language)
+}
+
+// EntryCard returns the share image card of a public journal entry. Only the title is shown,
+// so the content of end-to-end encrypted entries stays private.
+func (s *SEOService) EntryCard(ctx context.Context, entryID uuid.UUID) (*ogimage.Card, error) {
+	entry, err := s.journalRepo.FindByID(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil || !entry.IsPublic {
+		return nil, ErrEntryNotFound
+	}
+	return s.card(ctx, "Journal entry", entry.Title, entry.UserID.String(), "")
+}
+
+// card builds a share card with the author's name and current streak
+func (s *SEOService) card(ctx context.Context, kind, title, authorID, language string) (*ogimage.Card, error) {
+	authorName, err := s.authorName(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+	card := &ogimage.Card{Kind: kind, Title: title, Author: authorName, Language: language}
+	if id, err := uuid.Parse(authorID); err == nil {
+		if card.Streak, err = s.progressRepo.CalculateStreak(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return card, nil
+}
+
+// authorName returns the display name of a public page's author
+func (s *SEOService) authorName(ctx context.Context, userID string) (string, error) {
+	authorID, err := uuid.Parse(userID)
+	if err != nil {
+		return "DevJournal user", nil
+	}
+	author, err := s.userRepo.FindByID(ctx, authorID)
+	if err != nil {
+		return "", err
+	}
+	if author == nil {
+		return "DevJournal user", nil
+	}
+	return author.DisplayName, nil
+}
+
+// languageName returns a snippet language for prose, e.g. "code" when it has none
+func languageName(language string) string {
+	if language == "" || strings.EqualFold(language, "plaintext") {
+		return "code"
+	}
+	return language
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/i18n"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidPageSize      = apperr.Newf(ErrValidation, "defaultPageSize must be between 1 and %d", domain.MaxPageSize)
+	ErrInvalidStreakCounts  = apperr.New(ErrValidation, "streakCounts must be any, entries, snippets, entries_or_snippets, or minutes")
+	ErrInvalidStreakMinutes = apperr.Newf(ErrValidation, "streakMinutes must be between 1 and %d when streakCounts is minutes", domain.MaxStreakMinutes)
+	ErrInvalidAvatarURL     = apperr.Newf(ErrValidation, "avatarUrl must be an https URL of at most %d characters", domain.MaxAvatarURLLength)
+)
+
+const (
+	// localeCacheTTL is how long a user's locale is remembered. Every authenticated request
+	// looks it up, and other instances see a change after at most this long.
+	localeCacheTTL = time.Minute
+
+	// localeCacheSize caps remembered locales; the cache is emptied when it fills
+	localeCacheSize = 10000
+)
+
+// SettingsService handles user preferences
+type SettingsService struct {
+	settingsRepo *postgres.SettingsRepository
+
+	mu      sync.Mutex
+	locales map[uuid.UUID]cachedLocale
+}
+
+type cachedLocale struct {
+	locale  string
+	expires time.Time
+}
+
+// NewSettingsService creates a new settings service
+func NewSettingsService(settingsRepo *postgres.SettingsRepository) *SettingsService {
+	return &SettingsService{settingsRepo: settingsRepo, locales: make(map[uuid.UUID]cachedLocale)}
+}
+
+// Get returns a user's settings, falling back to defaults
+func (s *SettingsService) Get(ctx context.Context, userID uuid.UUID) (*domain.UserSettings, error) {
+	settings, err := s.settingsRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user settings: %w", err)
+	}
+	if settings == nil {
+		settings = domain.NewUserSettings(userID)
+	}
+	return settings, nil
+}
+
+// Update replaces a user's settings
+func (s *SettingsService) Update(ctx context.Context, userID uuid.UUID, req *domain.UpdateUserSettingsRequest) (*domain.UserSettings, error) {
+	if req.DefaultPageSize < 1 || req.DefaultPageSize > domain.MaxPageSize {
+		return nil, ErrInvalidPageSize
+	}
+
+	settings, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.DefaultPageSize = req.DefaultPageSize
+	if req.Locale != nil {
+		settings.Locale = ""
+		if *req.Locale != "" {
+			locale, ok := i18n.Supported(*req.Locale)
+			if !ok {
+				return nil, apperr.Newf(ErrValidation, "locale must be one of %s", strings.Join(i18n.Locales(), ", "))
+			}
+			settings.Locale = locale
+		}
+	}
+	if req.ShareProgress != nil {
+		settings.ShareProgress = *req.ShareProgress
+	}
+	if req.CelebrateMilestones != nil {
+		settings.CelebrateMilestones = *req.CelebrateMilestones
+	}
+	if req.StreakCounts != nil {
+		switch *req.StreakCounts {
+		case domain.StreakCountsAny, domain.StreakCountsEntries, domain.StreakCountsSnippets, domain.StreakCountsEntriesOrSnippets, domain.StreakCountsMinutes:
+			settings.StreakCounts = *req.StreakCounts
+		default:
+			return nil, ErrInvalidStreakCounts
+		}
+	}
+	if req.StreakMinutes != nil {
+		settings.StreakMinutes = *req.StreakMinutes
+	}
+	if settings.StreakCounts == domain.StreakCountsMinutes && (settings.StreakMinutes < 1 || settings.StreakMinutes > domain.MaxStreakMinutes) {
+		return nil, ErrInvalidStreakMinutes
+	}
+	if req.AvatarURL != nil {
+		// Avatars are shown to other users, so plain http would be mixed content
+		link := strings.TrimSpace(*req.AvatarURL)
+		if u, err := url.Parse(link); link != "" && (len(link) > domain.MaxAvatarURLLength || err != nil || u.Scheme != "https" || u.Host == "") {
+			return nil, ErrInvalidAvatarURL
+		}
+		settings.AvatarURL = link
+	}
+	settings.UpdatedAt = time.Now().UTC()
+
+	if err := s.settingsRepo.Upsert(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to update user settings: %w", err)
+	}
+
+	s.mu.Lock()
+	delete(s.locales, userID)
+	s.mu.Unlock()
+	return settings, nil
+}
+
+// Locale returns the user's locale setting, or "" if they follow Accept-Language. Lookups are
+// cached briefly, and failures are logged and treated as no setting.
+func (s *SettingsService) Locale(ctx context.Context, userID uuid.UUID) string {
+	now := time.Now()
+	s.mu.Lock()
+	cached, ok := s.locales[userID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.locale
+	}
+
+	settings, err := s.Get(ctx, userID)
+	if err != nil {
+		log.Printf("WARN: Failed to load locale preference for user %s: %v", userID, err)
+		return ""
+	}
+
+	s.mu.Lock()
+	if len(s.locales) >= localeCacheSize {
+		clear(s.locales)
+	}
+	s.locales[userID] = cachedLocale{locale: settings.Locale, expires: now.Add(localeCacheTTL)}
+	s.mu.Unlock()
+	return settings.Locale
+}
+
+// PageSize resolves the page size for a list request: an explicit request wins
+// (capped at domain.MaxPageSize), otherwise the user's preferred default is used.
+func (s *SettingsService) PageSize(ctx context.Context, userID uuid.UUID, requested int) int {
+	if requested > 0 {
+		return min(requested, domain.MaxPageSize)
+	}
+	if userID == uuid.Nil {
+		return domain.DefaultPageSize
+	}
+
+	settings, err := s.Get(ctx, userID)
+	if err != nil {
+		// Fall back to the server default rather than failing the list request
+		log.Printf("WARN: Failed to load page size preference for user %s: %v", userID, err)
+		return domain.DefaultPageSize
+	}
+	return settings.DefaultPageSize
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/internal/sitepublish"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidSitePublisher = apperr.New(ErrValidation, "kind must be webhook or github")
+	ErrInvalidSiteWebhook   = apperr.New(ErrValidation, "webhookUrl must be an http or https URL")
+	ErrSiteWebhookAddress   = apperr.New(ErrValidation, "webhookUrl must resolve to a public internet address")
+	ErrInvalidSiteRepo      = apperr.New(ErrValidation, "repo must be a GitHub repository as owner/name")
+	ErrInvalidSitePath      = apperr.New(ErrValidation, "path must be a directory inside the repository")
+	ErrSiteTokenRequired    = apperr.New(ErrValidation, "a GitHub token is required to commit to the repository")
+	ErrSitePublisherMissing = apperr.New(ErrNotFound, "site publishing is not set up")
+)
+
+// githubRepoPattern matches owner/name with GitHub's allowed characters
+var githubRepoPattern = regexp.MustCompile(`^[A-Za-z0-9-]+/[A-Za-z0-9._-]+$`)
+
+// SitePublishService sends newly public journal entries to the user's own static site. Entries
+// are queued when they become public and delivered in the background with retries, the same way
+// study group chat is mirrored to Slack and Discord.
+type SitePublishService struct {
+	siteRepo    *postgres.SiteRepository
+	journalRepo *postgres.JournalRepository
+	github      *sitepublish.GitHub
+
+	wake chan struct{}
+}
+
+// NewSitePublishService creates a new site publish service
+func NewSitePublishService(siteRepo *postgres.SiteRepository, journalRepo *postgres.JournalRepository, github *sitepublish.GitHub) *SitePublishService {
+	return &SitePublishService{
+		siteRepo:    siteRepo,
+		journalRepo: journalRepo,
+		github:      github,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Get returns the user's site publisher
+func (s *SitePublishService) Get(ctx context.Context, userID uuid.UUID) (*domain.SitePublisher, error) {
+	p, err := s.siteRepo.FindPublisher(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrSitePublisherMissing
+	}
+	return p, nil
+}
+
+// Update sets up or changes the user's site publisher. Webhooks must be on the public internet,
+// and GitHub tokens are checked against the repository so a typo shows up now rather than as
+// failed deliveries.
+func (s *SitePublishService) Update(ctx context.Context, userID uuid.UUID, req *domain.UpdateSitePublisherRequest) (*domain.SitePublisher, error) {
+	existing, err := s.siteRepo.FindPublisher(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &domain.SitePublisher{UserID: userID, Kind: req.Kind, Enabled: req.Enabled}
+	switch req.Kind {
+	case domain.SitePublisherWebhook:
+		p.WebhookURL = strings.TrimSpace(req.WebhookURL)
+		if !validLink(p.WebhookURL) {
+			return nil, ErrInvalidSiteWebhook
+		}
+		if err := sitepublish.CheckWebhookURL(ctx, p.WebhookURL); err != nil {
+			return nil, ErrSiteWebhookAddress
+		}
+	case domain.SitePublisherGitHub:
+		p.Repo = strings.TrimSpace(req.Repo)
+		if !githubRepoPattern.MatchString(p.Repo) {
+			return nil, ErrInvalidSiteRepo
+		}
+		p.Branch = strings.TrimSpace(req.Branch)
+		dir, ok := cleanSitePath(req.Path)
+		if !ok {
+			return nil, ErrInvalidSitePath
+		}
+		p.Path = dir
+
+		p.Token = strings.TrimSpace(req.Token)
+		if p.Token == "" && existing != nil {
+			p.Token = existing.Token
+		}
+		if p.Token == "" {
+			return nil, ErrSiteTokenRequired
+		}
+		if err := s.github.Verify(ctx, p.Token, p.Repo); err != nil {
+			if errors.Is(err, sitepublish.ErrNoAccess) {
+				return nil, err
+			}
+			return nil, apperr.Newf(ErrUnavailable, "could not reach GitHub to check the token: %v", err)
+		}
+	default:
+		return nil, ErrInvalidSitePublisher
+	}
+
+	if err := s.siteRepo.SavePublisher(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// cleanSitePath normalizes a repository directory, rejecting paths that climb out of it
+func cleanSitePath(dir string) (string, bool) {
+	dir = strings.Trim(strings.TrimSpace(dir), "/")
+	if dir == "" {
+		return "", true
+	}
+	dir = path.Clean(dir)
+	if dir == ".." || strings.HasPrefix(dir, "../") {
+		return "", false
+	}
+	return dir, true
+}
+
+// Delete stops publishing to the user's site, dropping entries still waiting to be sent
+func (s *SitePublishService) Delete(ctx context.Context, userID uuid.UUID) error {
+	deleted, err := s.siteRepo.DeletePublisher(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrSitePublisherMissing
+	}
+	return nil
+}
+
+// Deliveries lists the entries sent, or waiting to be sent, to the user's site
+func (s *SitePublishService) Deliveries(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.SiteDelivery, int, error) {
+	return s.siteRepo.ListDeliveries(ctx, userID, limit, offset)
+}
+
+// EntryPublished queues an entry that just became public for its author's site. It is a
+// JournalService.OnPublish listener, so failures are only logged.
+func (s *SitePublishService) EntryPublished(ctx context.Context, entry *domain.JournalEntry) {
+	queued, err := s.siteRepo.Enqueue(ctx, entry.UserID, entry.ID)
+	if err != nil {
+		log.Printf("ERROR: Failed to queue entry %s for the author's site: %v", entry.ID, err)
+		return
+	}
+	if queued {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run delivers queued entries until ctx is cancelled, polling for retries that come due
+func (s *SitePublishService) Run(ctx context.Context) {
+	ticker := time.NewTicker(deliveryPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+			s.deliverDue(ctx)
+		case <-ticker.C:
+			s.deliverDue(ctx)
+		}
+	}
+}
+
+// deliverDue sends every delivery that is due, scheduling retries for failures
+func (s *SitePublishService) deliverDue(ctx context.Context) {
+	for {
+		deliveries, err := s.siteRepo.ClaimDue(ctx, time.Now().UTC(), deliveryLease, deliveryBatchSize)
+		if err != nil {
+			log.Printf("ERROR: Failed to claim site deliveries: %v", err)
+			return
+		}
+		for _, d := range deliveries {
+			s.deliver(ctx, &d)
+		}
+		if len(deliveries) < deliveryBatchSize {
+			return
+		}
+	}
+}
+
+// deliver sends one entry to its author's site and records the outcome
+func (s *SitePublishService) deliver(ctx context.Context, d *domain.SiteDelivery) {
+	sendErr := s.send(ctx, d)
+	if sendErr == nil {
+		if err := s.siteRepo.MarkDelivered(ctx, d.ID); err != nil {
+			log.Printf("ERROR: %v", err)
+		}
+		return
+	}
+
+	var retryAt *time.Time
+	if attempt := d.Attempts + 1; attempt < maxDeliveryAttempts && !errors.Is(sendErr, sitepublish.ErrRejected) {
+		next := time.Now().UTC().Add(deliveryBaseDelay << (attempt - 1))
+		retryAt = &next
+	} else {
+		log.Printf("WARN: Giving up on site delivery %s after %d attempts: %v", d.ID, attempt, sendErr)
+	}
+	if err := s.siteRepo.MarkAttemptFailed(ctx, d.ID, sendErr.Error(), retryAt); err != nil {
+		log.Printf("ERROR: %v", err)
+	}
+}
+
+// send posts or commits the entry of a delivery, as the publisher is set up now. Entries made
+// private again, and publishers turned off, since the entry was queued are not sent.
+func (s *SitePublishService) send(ctx context.Context, d *domain.SiteDelivery) error {
+	p, err := s.siteRepo.FindPublisher(ctx, d.UserID)
+	if err != nil {
+		return err
+	}
+	if p == nil || !p.Enabled {
+		return fmt.Errorf("%w: site publishing was turned off", sitepublish.ErrRejected)
+	}
+	entry, err := s.journalRepo.FindByID(ctx, d.EntryID)
+	if err != nil {
+		return err
+	}
+	if entry == nil || !entry.IsPublic {
+		return fmt.Errorf("%w: the entry is no longer public", sitepublish.ErrRejected)
+	}
+
+	if p.Kind == domain.SitePublisherGitHub {
+		return s.github.Commit(ctx, p.Token, p.Repo, p.Branch, p.Path, entry)
+	}
+	return sitepublish.PostWebhook(ctx, p.WebhookURL, entry)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/policy"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSnippetCommentNotFound = apperr.New(ErrNotFound, "comment not found")
+	ErrSnippetCommentResolve  = apperr.New(ErrForbidden, "only the snippet's owner or the comment's author can resolve it")
+	ErrSnippetCommentBody     = apperr.New(ErrValidation, "comment must be between 1 and 5000 characters")
+	ErrSnippetCommentFile     = apperr.New(ErrValidation, "file is not part of the snippet")
+	ErrSnippetCommentLines    = apperr.New(ErrValidation, "lines must be within the file, with endLine not before startLine")
+)
+
+// SnippetCommentService handles line comments on snippets. Comments are stored in Postgres;
+// SnippetService moves them when their snippet is edited and removes them when it is deleted.
+type SnippetCommentService struct {
+	commentRepo *postgres.SnippetCommentRepository
+	snippetRepo *mongodb.SnippetRepository
+	userRepo    *postgres.UserRepository
+}
+
+// NewSnippetCommentService creates a new snippet comment service
+func NewSnippetCommentService(commentRepo *postgres.SnippetCommentRepository, snippetRepo *mongodb.SnippetRepository, userRepo *postgres.UserRepository) *SnippetCommentService {
+	return &SnippetCommentService{commentRepo: commentRepo, snippetRepo: snippetRepo, userRepo: userRepo}
+}
+
+// List returns the comments on a snippet the user can see, leaving out resolved ones unless includeResolved is set
+func (s *SnippetCommentService) List(ctx context.Context, userID uuid.UUID, snippetID string, includeResolved bool) ([]domain.SnippetComment, error) {
+	snippet, err := s.findVisible(ctx, userID, snippetID)
+	if err != nil {
+		return nil, err
+	}
+	return s.commentRepo.ListBySnippet(ctx, snippet.ID, includeResolved)
+}
+
+// Create comments on a line or range of lines of a snippet the user can see
+func (s *SnippetCommentService) Create(ctx context.Context, userID uuid.UUID, snippetID string, req *domain.SnippetCommentRequest) (*domain.SnippetComment, error) {
+	snippet, err := s.findVisible(ctx, userID, snippetID)
+	if err != nil {
+		return nil, err
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" || utf8.RuneCountInString(body) > 5000 {
+		return nil, ErrSnippetCommentBody
+	}
+	file := snippet.File(req.File)
+	if file == nil {
+		return nil, ErrSnippetCommentFile
+	}
+	endLine := req.EndLine
+	if endLine == 0 {
+		endLine = req.StartLine
+	}
+	if req.StartLine < 1 || endLine < req.StartLine || endLine > file.Stats.Lines {
+		return nil, ErrSnippetCommentLines
+	}
+	author, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, ErrUserNotFound
+	}
+
+	now := time.Now().UTC()
+	comment := &domain.SnippetComment{
+		ID:         uuid.New(),
+		SnippetID:  snippet.ID,
+		UserID:     userID,
+		AuthorName: author.DisplayName,
+		File:       file.Name,
+		StartLine:  req.StartLine,
+		EndLine:    endLine,
+		Body:       body,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// Resolve marks a comment resolved, or open again. The snippet's owner and the comment's author can resolve it.
+func (s *SnippetCommentService) Resolve(ctx context.Context, userID uuid.UUID, snippetID string, commentID uuid.UUID, resolved bool) (*domain.SnippetComment, error) {
+	snippet, err := s.findVisible(ctx, userID, snippetID)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.commentRepo.FindByID(ctx, commentID, snippet.ID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil {
+		return nil, ErrSnippetCommentNotFound
+	}
+	if snippet.UserID != userID.String() && comment.UserID != userID {
+		return nil, ErrSnippetCommentResolve
+	}
+	if resolved == (comment.ResolvedAt != nil) {
+		return comment, nil
+	}
+
+	now := time.Now().UTC()
+	comment.ResolvedAt = nil
+	if resolved {
+		comment.ResolvedAt = &now
+	}
+	if err := s.commentRepo.Resolve(ctx, comment.ID, comment.ResolvedAt); err != nil {
+		return nil, err
+	}
+	comment.UpdatedAt = now
+	return comment, nil
+}
+
+// findVisible returns a snippet the user owns or that is public, or ErrSnippetNotFound
+func (s *SnippetCommentService) findVisible(ctx context.Context, userID uuid.UUID, snippetID string) (*domain.Snippet, error) {
+	snippet, err := s.snippetRepo.FindByID(ctx, snippetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snippet: %w", err)
+	}
+	if policy.Authorize(policy.Subject{UserID: userID}, policy.Read, snippet) == policy.Hide {
+		return nil, ErrSnippetNotFound
+	}
+	return snippet, nil
+}
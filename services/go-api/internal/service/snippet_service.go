@@ -2,25 +2,108 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"devjournal/internal/domain"
+	"devjournal/internal/formatter"
+	"devjournal/internal/policy"
 	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidStatsInterval = apperr.New(ErrValidation, "interval must be day, week, month, or year")
+	ErrInvalidStatsRange    = apperr.New(ErrValidation, "range must look like 30d, 12w, 6m, or 1y")
+	ErrSnippetNotFound      = apperr.New(ErrNotFound, "snippet not found")
+	ErrSnippetNotOwned      = apperr.New(ErrForbidden, "only the snippet's owner can change it")
+	ErrTooManySnippetFiles  = apperr.New(ErrValidation, fmt.Sprintf("a snippet can have at most %d files", domain.MaxSnippetFiles))
+	ErrSnippetFileName      = apperr.New(ErrValidation, "file names must be non-empty, at most 255 characters, and contain no slashes")
+	ErrDuplicateFileName    = apperr.New(ErrValidation, "file names must be unique within a snippet")
+	ErrEmptySnippet         = apperr.New(ErrValidation, "snippet has no code")
+	ErrExtractEncrypted     = apperr.New(ErrValidation, "code blocks cannot be extracted from e2ee entries")
+	ErrInvalidExportFormat  = apperr.New(ErrValidation, "format must be vscode or jetbrains")
+	ErrFormatterUnavailable = apperr.New(ErrUnavailable, "the code formatter is unavailable; try again or save without formatting")
+	ErrInvalidSourceURL     = apperr.New(ErrValidation, fmt.Sprintf("sourceUrl must be an http or https URL of at most %d characters", domain.MaxSourceURLLength))
+	ErrUnknownLicense       = apperr.New(ErrValidation, "license must be an SPDX license ID such as MIT or Apache-2.0")
+	ErrMergeSameSnippet     = apperr.New(ErrValidation, "targetId and sourceId must be two different snippets")
+	ErrMergeWorkspaces      = apperr.New(ErrValidation, "only snippets in the same workspace can be merged")
+)
+
+// SecretsDetectedError is returned when publishing a snippet that appears to contain credentials
+type SecretsDetectedError struct {
+	Findings []domain.SecretFinding
+}
+
+func (e *SecretsDetectedError) Error() string {
+	return fmt.Sprintf("snippet appears to contain %d secret(s); remove them or acknowledge to publish anyway", len(e.Findings))
+}
+
+func (e *SecretsDetectedError) Unwrap() error { return ErrPrecondition }
+
+// Details exposes the findings so clients can show where the secrets are
+func (e *SecretsDetectedError) Details() map[string]interface{} {
+	return map[string]interface{}{"findings": e.Findings}
+}
+
+// DuplicateSnippetError is returned when creating a snippet nearly the same as ones the user
+// already has. It is advisory: the client can create it anyway with allowDuplicate.
+type DuplicateSnippetError struct {
+	Matches []domain.SimilarSnippet
+}
+
+func (e *DuplicateSnippetError) Error() string {
+	return fmt.Sprintf("%d very similar snippet(s) already exist; merge into one or set allowDuplicate to create it anyway", len(e.Matches))
+}
+
+func (e *DuplicateSnippetError) Unwrap() error { return ErrConflict }
+
+// Details exposes the similar snippets so clients can offer to open one instead
+func (e *DuplicateSnippetError) Details() map[string]interface{} {
+	return map[string]interface{}{"matches": e.Matches}
+}
+
+// maxDuplicateMatches is how many similar snippets a DuplicateSnippetError lists
+const maxDuplicateMatches = 5
+
+// viewDedupWindow is how long repeat views by the same viewer are ignored
+const viewDedupWindow = 24 * time.Hour
+
+// Number of packages GET /api/snippets/dependencies returns by default, and at most
+const (
+	defaultDependencyStats = 20
+	maxDependencyStats     = 100
 )
 
 // SnippetService handles code snippet business logic
 type SnippetService struct {
-	snippetRepo *mongodb.SnippetRepository
+	snippetRepo  *mongodb.SnippetRepository
+	linkRepo     *postgres.EntrySnippetRepository
+	commentRepo  *postgres.SnippetCommentRepository
+	quotaService *QuotaService
+	formatter    *formatter.Formatter
 }
 
 // NewSnippetService creates a new snippet service
-func NewSnippetService(snippetRepo *mongodb.SnippetRepository) *SnippetService {
-	return &SnippetService{snippetRepo: snippetRepo}
+func NewSnippetService(snippetRepo *mongodb.SnippetRepository, linkRepo *postgres.EntrySnippetRepository, commentRepo *postgres.SnippetCommentRepository, quotaService *QuotaService, formatter *formatter.Formatter) *SnippetService {
+	return &SnippetService{snippetRepo: snippetRepo, linkRepo: linkRepo, commentRepo: commentRepo, quotaService: quotaService, formatter: formatter}
 }
 
 // Create creates a new code snippet
 func (s *SnippetService) Create(ctx context.Context, userID string, req *domain.CreateSnippetRequest) (*domain.Snippet, error) {
+	if err := s.quotaService.CheckSnippets(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	snippet := domain.NewSnippet(
 		userID,
 		req.Title,
@@ -31,6 +114,34 @@ func (s *SnippetService) Create(ctx context.Context, userID string, req *domain.
 		req.Metadata,
 		req.IsPublic,
 	)
+	files := snippet.Files
+	if len(req.Files) > 0 {
+		if err := validateSnippetFiles(req.Files); err != nil {
+			return nil, err
+		}
+		files = req.Files
+		for i := range files {
+			files[i].OriginalCode = ""
+		}
+	}
+	if req.Format {
+		if err := s.formatFiles(ctx, files); err != nil {
+			return nil, err
+		}
+	}
+	snippet.SetFiles(files)
+	if err := setAttribution(snippet, &req.SourceURL, &req.License); err != nil {
+		return nil, err
+	}
+
+	if err := checkPublishSecrets(snippet, req.AcknowledgeSecrets); err != nil {
+		return nil, err
+	}
+	if !req.AllowDuplicate {
+		if err := s.checkDuplicates(ctx, snippet); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.snippetRepo.Create(ctx, snippet); err != nil {
 		return nil, fmt.Errorf("failed to create snippet: %w", err)
@@ -39,36 +150,105 @@ func (s *SnippetService) Create(ctx context.Context, userID string, req *domain.
 	return snippet, nil
 }
 
-// GetByID retrieves a snippet by ID
-func (s *SnippetService) GetByID(ctx context.Context, id, userID string) (*domain.Snippet, error) {
-	snippet, err := s.snippetRepo.FindByID(ctx, id)
+// ExtractFromEntry creates a snippet for each fenced code block in a journal entry, linked to the
+// entry and recording it in its metadata. Blocks already extracted from the entry are skipped, so extracting
+// again after editing the entry only adds the new blocks.
+func (s *SnippetService) ExtractFromEntry(ctx context.Context, userID string, entry *domain.JournalEntry) (*domain.ExtractSnippetsResult, error) {
+	if entry.ContentFormat == domain.ContentFormatE2EE {
+		return nil, ErrExtractEncrypted
+	}
+
+	existing, err := s.snippetRepo.FindByEntry(ctx, userID, entry.ID.String())
 	if err != nil {
-		return nil, fmt.Errorf("failed to find snippet: %w", err)
+		return nil, err
 	}
-	if snippet == nil {
-		return nil, nil
+	extracted := make(map[string]bool, len(existing))
+	for _, snippet := range existing {
+		extracted[strings.TrimSpace(snippet.Code)] = true
 	}
 
-	// Check access
-	if snippet.UserID != userID && !snippet.IsPublic {
+	blocks := domain.ExtractCodeBlocks(entry.Content)
+	result := &domain.ExtractSnippetsResult{Created: []domain.Snippet{}}
+	for i, block := range blocks {
+		code := strings.TrimSpace(block.Code)
+		if code == "" || extracted[code] || len(result.Created) == domain.MaxExtractedSnippets {
+			result.Skipped++
+			continue
+		}
+		extracted[code] = true
+
+		title := entry.Title
+		if len(blocks) > 1 {
+			title = fmt.Sprintf("%s #%d", entry.Title, i+1)
+		}
+		snippet, err := s.Create(ctx, userID, &domain.CreateSnippetRequest{
+			Title:       title,
+			Description: fmt.Sprintf("From journal entry %q", entry.Title),
+			Code:        block.Code,
+			Language:    block.Language,
+			Tags:        entry.Tags,
+			Metadata: map[string]interface{}{
+				"entry": domain.EntrySnippetSource{EntryID: entry.ID.String(), Block: i, Line: block.Line},
+			},
+			AllowDuplicate: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := s.linkRepo.Link(ctx, entry.ID, snippet.ID, entry.UserID); err != nil {
+			log.Printf("WARN: Failed to link snippet %s to entry %s: %v", snippet.ID, entry.ID, err)
+		}
+		result.Created = append(result.Created, *snippet)
+	}
+	return result, nil
+}
+
+// GetByID retrieves a snippet by ID, following the IDs of merged snippets
+func (s *SnippetService) GetByID(ctx context.Context, id, userID string) (*domain.Snippet, error) {
+	snippet, err := findSnippet(ctx, s.snippetRepo, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snippet: %w", err)
+	}
+	if policy.Authorize(snippetSubject(userID), policy.Read, snippet) != policy.Allow {
 		return nil, nil
 	}
 
-	// Increment views if not owner
-	if snippet.UserID != userID {
-		s.snippetRepo.IncrementViews(ctx, id)
+	// Count views from other users, once per viewer per viewDedupWindow. Guests can't be told
+	// apart, so their views are not counted.
+	if userID != "" && snippet.UserID != userID {
+		s.recordView(ctx, snippet, userID)
 	}
 
 	return snippet, nil
 }
 
+// recordView counts a view by viewerID unless they already viewed the snippet within the dedup window
+func (s *SnippetService) recordView(ctx context.Context, snippet *domain.Snippet, viewerID string) {
+	counted, firstView, err := s.snippetRepo.RecordView(ctx, snippet.ID, viewerID, viewDedupWindow)
+	if err != nil {
+		log.Printf("WARN: Failed to record view of snippet %s: %v", snippet.ID, err)
+		return
+	}
+	if !counted {
+		return
+	}
+	if err := s.snippetRepo.IncrementViews(ctx, snippet.ID, firstView); err != nil {
+		log.Printf("WARN: Failed to increment views of snippet %s: %v", snippet.ID, err)
+		return
+	}
+	snippet.ViewsCount++
+	if firstView {
+		snippet.UniqueViewers++
+	}
+}
+
 // List retrieves all snippets for a user
 func (s *SnippetService) List(ctx context.Context, userID string, limit, offset int64) ([]domain.Snippet, int64, error) {
 	if limit <= 0 {
 		limit = 20
 	}
-	if limit > 100 {
-		limit = 100
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
 	}
 
 	snippets, err := s.snippetRepo.FindByUserID(ctx, userID, limit, offset)
@@ -84,6 +264,47 @@ func (s *SnippetService) List(ctx context.Context, userID string, limit, offset
 	return snippets, total, nil
 }
 
+// ListFiltered retrieves a user's snippets matching all criteria in the filter
+func (s *SnippetService) ListFiltered(ctx context.Context, userID string, filter *domain.SnippetFilter, limit, offset int64) ([]domain.Snippet, int64, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
+	}
+
+	snippets, err := s.snippetRepo.FindFiltered(ctx, userID, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list snippets: %w", err)
+	}
+
+	total, err := s.snippetRepo.CountFiltered(ctx, userID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count snippets: %w", err)
+	}
+
+	return snippets, total, nil
+}
+
+// ExportEditor renders a user's snippets matching the filter as an editor snippets file in the
+// given format: a VS Code .code-snippets file or a JetBrains live template set
+func (s *SnippetService) ExportEditor(ctx context.Context, userID, format string, filter *domain.SnippetFilter) ([]byte, error) {
+	if format != domain.SnippetExportVSCode && format != domain.SnippetExportJetBrains {
+		return nil, ErrInvalidExportFormat
+	}
+
+	snippets, err := s.snippetRepo.FindFiltered(ctx, userID, filter, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export snippets: %w", err)
+	}
+
+	templates := domain.EditorSnippetTemplates(snippets)
+	if format == domain.SnippetExportJetBrains {
+		return domain.JetBrainsTemplates("DevJournal", templates)
+	}
+	return domain.VSCodeSnippets(templates)
+}
+
 // ListByTags retrieves snippets matching any of the given tags
 func (s *SnippetService) ListByTags(ctx context.Context, userID string, tags []string, limit, offset int64) ([]domain.Snippet, error) {
 	if limit <= 0 {
@@ -133,32 +354,350 @@ func (s *SnippetService) Update(ctx context.Context, id, userID string, req *dom
 	if err != nil {
 		return nil, fmt.Errorf("failed to find snippet: %w", err)
 	}
-	if existing == nil || existing.UserID != userID {
-		return nil, fmt.Errorf("snippet not found")
+	if err := checkSnippetOwner(existing, userID); err != nil {
+		return nil, err
 	}
 
 	// Update fields
 	existing.Title = req.Title
 	existing.Description = req.Description
-	existing.Code = req.Code
-	existing.Language = req.Language
 	existing.Tags = req.Tags
 	existing.Metadata = req.Metadata
 	existing.IsPublic = req.IsPublic
 	existing.UpdatedAt = time.Now().UTC()
 
+	files := req.Files
+	for i := range files {
+		files[i].OriginalCode = ""
+	}
+	if len(files) == 0 {
+		// Single-file clients edit the first file and leave the rest alone
+		files = append([]domain.SnippetFile{}, existing.Files...)
+		if len(files) == 0 {
+			files = domain.SingleSnippetFile(req.Title, req.Code, req.Language)
+		}
+		if files[0].Code != req.Code || files[0].Language != req.Language {
+			files[0].Code, files[0].Language, files[0].OriginalCode = req.Code, req.Language, ""
+		}
+	}
+	if err := validateSnippetFiles(files); err != nil {
+		return nil, err
+	}
+	if req.Format {
+		if err := s.formatFiles(ctx, files); err != nil {
+			return nil, err
+		}
+	}
+	before := existing.Files
+	existing.SetFiles(files)
+	if err := setAttribution(existing, req.SourceURL, req.License); err != nil {
+		return nil, err
+	}
+
+	if err := checkPublishSecrets(existing, req.AcknowledgeSecrets); err != nil {
+		return nil, err
+	}
+
 	if err := s.snippetRepo.Update(ctx, existing); err != nil {
 		return nil, fmt.Errorf("failed to update snippet: %w", err)
 	}
+	if err := s.reanchorComments(ctx, existing.ID, before, existing.Files); err != nil {
+		log.Printf("WARN: Failed to move comments on snippet %s: %v", existing.ID, err)
+	}
 
 	return existing, nil
 }
 
+// Merge merges the source snippet into the target, both owned by userID. The target gains the
+// source's files, tags, and views, keeps the earlier creation time, and records the source in
+// MergedFrom. The source's entry links and comments move to the target, and its ID redirects to it.
+func (s *SnippetService) Merge(ctx context.Context, userID string, req *domain.MergeSnippetsRequest) (*domain.Snippet, error) {
+	if req.TargetID == req.SourceID {
+		return nil, ErrMergeSameSnippet
+	}
+	target, err := s.snippetRepo.FindByID(ctx, req.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snippet: %w", err)
+	}
+	if err := checkSnippetOwner(target, userID); err != nil {
+		return nil, err
+	}
+	source, err := s.snippetRepo.FindByID(ctx, req.SourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snippet: %w", err)
+	}
+	if err := checkSnippetOwner(source, userID); err != nil {
+		return nil, err
+	}
+	if source.WorkspaceID != target.WorkspaceID {
+		return nil, ErrMergeWorkspaces
+	}
+
+	files, renamed := domain.MergeSnippetFiles(target.Files, source.Files)
+	if len(files) > domain.MaxSnippetFiles {
+		return nil, ErrTooManySnippetFiles
+	}
+	target.SetFiles(files)
+
+	for _, tag := range source.Tags {
+		if !slices.Contains(target.Tags, tag) {
+			target.Tags = append(target.Tags, tag)
+		}
+	}
+	if strings.TrimSpace(target.Description) == "" {
+		target.Description = source.Description
+	}
+	if target.Metadata == nil {
+		target.Metadata = make(map[string]interface{})
+	}
+	for key, value := range source.Metadata {
+		if _, ok := target.Metadata[key]; !ok {
+			target.Metadata[key] = value
+		}
+	}
+	if target.SourceURL == "" {
+		target.SourceURL = source.SourceURL
+	}
+	if target.License == "" {
+		target.License = source.License
+	}
+
+	now := time.Now().UTC()
+	target.ViewsCount += source.ViewsCount
+	target.UniqueViewers += source.UniqueViewers
+	if source.CreatedAt.Before(target.CreatedAt) {
+		target.CreatedAt = source.CreatedAt
+	}
+	target.MergedFrom = append(target.MergedFrom, source.MergedFrom...)
+	target.MergedFrom = append(target.MergedFrom, domain.MergedSnippet{
+		ID:        source.ID,
+		Title:     source.Title,
+		CreatedAt: source.CreatedAt,
+		MergedAt:  now,
+	})
+	target.UpdatedAt = now
+
+	if err := checkPublishSecrets(target, req.AcknowledgeSecrets); err != nil {
+		return nil, err
+	}
+
+	if err := s.snippetRepo.Merge(ctx, target, source.ID); err != nil {
+		return nil, fmt.Errorf("failed to merge snippets: %w", err)
+	}
+	if err := s.linkRepo.MoveToSnippet(ctx, source.ID, target.ID); err != nil {
+		log.Printf("WARN: Failed to move entry links of snippet %s to %s: %v", source.ID, target.ID, err)
+	}
+	if err := s.commentRepo.MoveToSnippet(ctx, source.ID, target.ID, renamed); err != nil {
+		log.Printf("WARN: Failed to move comments on snippet %s to %s: %v", source.ID, target.ID, err)
+	}
+	return target, nil
+}
+
+// reanchorComments moves comments on an edited snippet to where their lines are in the new
+// files. Snippets keep no version history, so each file's previous code is diffed against
+// its new code; comments whose lines changed or whose file is gone are marked outdated.
+func (s *SnippetService) reanchorComments(ctx context.Context, snippetID string, before, after []domain.SnippetFile) error {
+	comments, err := s.commentRepo.ListBySnippet(ctx, snippetID, true)
+	if err != nil || len(comments) == 0 {
+		return err
+	}
+
+	afterCode := make(map[string]string, len(after))
+	for _, f := range after {
+		afterCode[f.Name] = f.Code
+	}
+	lineMaps := make(map[string][]int, len(before))
+	for _, f := range before {
+		code, ok := afterCode[f.Name]
+		if !ok || code == f.Code {
+			continue
+		}
+		lineMaps[f.Name] = domain.LineMap(f.Code, code)
+	}
+
+	var moved []domain.SnippetComment
+	for _, c := range comments {
+		lineMap, changed := lineMaps[c.File]
+		if _, kept := afterCode[c.File]; kept && !changed {
+			continue
+		}
+		if c.Reanchor(lineMap) {
+			moved = append(moved, c)
+		}
+	}
+	return s.commentRepo.UpdateAnchors(ctx, moved)
+}
+
+// Scan checks a snippet owned by userID for accidentally included secrets
+func (s *SnippetService) Scan(ctx context.Context, id, userID string) (*domain.SecretScanResult, error) {
+	snippet, err := s.snippetRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snippet: %w", err)
+	}
+	if err := checkSnippetOwner(snippet, userID); err != nil {
+		return nil, err
+	}
+
+	findings := domain.ScanSnippetForSecrets(snippet)
+	return &domain.SecretScanResult{
+		SnippetID: snippet.ID,
+		Clean:     len(findings) == 0,
+		Findings:  findings,
+	}, nil
+}
+
+// checkSnippetOwner reports whether userID may change a snippet. Snippets the user
+// cannot see are reported as not found so their existence is not leaked.
+func checkSnippetOwner(snippet *domain.Snippet, userID string) error {
+	switch policy.Authorize(snippetSubject(userID), policy.Update, snippet) {
+	case policy.Allow:
+		return nil
+	case policy.Deny:
+		return ErrSnippetNotOwned
+	}
+	return ErrSnippetNotFound
+}
+
+// snippetSubject returns the policy subject for a user ID as stored with snippets. Guests have
+// no ID.
+func snippetSubject(userID string) policy.Subject {
+	id, _ := uuid.Parse(userID)
+	return policy.Subject{UserID: id}
+}
+
+// validateSnippetFiles checks a snippet's files have unique, usable names and some code between them
+func validateSnippetFiles(files []domain.SnippetFile) error {
+	if len(files) > domain.MaxSnippetFiles {
+		return ErrTooManySnippetFiles
+	}
+	names := make(map[string]bool, len(files))
+	hasCode := false
+	for i := range files {
+		name := strings.TrimSpace(files[i].Name)
+		if name == "" || len(name) > 255 || strings.ContainsAny(name, "/\\") || name == "." || name == ".." {
+			return ErrSnippetFileName
+		}
+		files[i].Name = name
+		if names[strings.ToLower(name)] {
+			return ErrDuplicateFileName
+		}
+		names[strings.ToLower(name)] = true
+		hasCode = hasCode || strings.TrimSpace(files[i].Code) != ""
+	}
+	if !hasCode {
+		return ErrEmptySnippet
+	}
+	return nil
+}
+
+// formatFiles formats the code of each file for its language, keeping the code as given in
+// OriginalCode when formatting changes it. Files in languages without a formatter are left alone.
+func (s *SnippetService) formatFiles(ctx context.Context, files []domain.SnippetFile) error {
+	for i := range files {
+		f := &files[i]
+		language := f.Language
+		if language == "" {
+			language = domain.LanguageFromPath(f.Name)
+		}
+		formatted, err := s.formatter.Format(ctx, language, f.Code)
+		if errors.Is(err, formatter.ErrSyntax) {
+			return apperr.Newf(ErrValidation, "%s could not be formatted: %v", f.Name, err)
+		}
+		if err != nil {
+			log.Printf("WARN: Failed to format %s code: %v", language, err)
+			return ErrFormatterUnavailable
+		}
+		if formatted != f.Code {
+			f.OriginalCode, f.Code = f.Code, formatted
+		}
+	}
+	return nil
+}
+
+// setAttribution validates and sets where a snippet's code came from and its license, leaving
+// either alone when nil. Snippets given an empty license, or left without one, get the first
+// license header found in their files.
+func setAttribution(snippet *domain.Snippet, sourceURL, license *string) error {
+	if sourceURL != nil {
+		link := strings.TrimSpace(*sourceURL)
+		if link != "" && (len(link) > domain.MaxSourceURLLength || !validLink(link)) {
+			return ErrInvalidSourceURL
+		}
+		snippet.SourceURL = link
+	}
+
+	if license != nil && strings.TrimSpace(*license) != "" {
+		canonical, ok := domain.NormalizeLicense(*license)
+		if !ok {
+			return ErrUnknownLicense
+		}
+		snippet.License = canonical
+		return nil
+	}
+	if license != nil || snippet.License == "" {
+		snippet.License = ""
+		for _, f := range snippet.Files {
+			if snippet.License = domain.DetectLicense(f.Code); snippet.License != "" {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// checkDuplicates returns a DuplicateSnippetError when the user already has snippets nearly the
+// same as a new one. The check is advisory, so failing to run it does not block creating.
+func (s *SnippetService) checkDuplicates(ctx context.Context, snippet *domain.Snippet) error {
+	if snippet.SimHash == 0 {
+		return nil
+	}
+	matches, err := s.snippetRepo.FindSimilar(ctx, snippet.UserID, snippet.SimHash, domain.MaxDuplicateDistance, maxDuplicateMatches)
+	if err != nil {
+		log.Printf("WARN: Failed to check for duplicates of snippet %q: %v", snippet.Title, err)
+		return nil
+	}
+	if len(matches) > 0 {
+		return &DuplicateSnippetError{Matches: matches}
+	}
+	return nil
+}
+
+// checkPublishSecrets scans public snippets before they are saved. High severity findings
+// block publishing unless acknowledged; anything else is attached as warnings.
+func checkPublishSecrets(snippet *domain.Snippet, acknowledged bool) error {
+	if !snippet.IsPublic {
+		return nil
+	}
+	findings := domain.ScanSnippetForSecrets(snippet)
+	if len(findings) == 0 {
+		return nil
+	}
+	if domain.HasHighSeveritySecret(findings) && !acknowledged {
+		return &SecretsDetectedError{Findings: findings}
+	}
+	snippet.SecretWarnings = findings
+	return nil
+}
+
 // Delete removes a snippet
 func (s *SnippetService) Delete(ctx context.Context, id, userID string) error {
+	existing, err := s.snippetRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find snippet: %w", err)
+	}
+	if err := checkSnippetOwner(existing, userID); err != nil {
+		return err
+	}
+
 	if err := s.snippetRepo.Delete(ctx, id, userID); err != nil {
 		return fmt.Errorf("failed to delete snippet: %w", err)
 	}
+	if err := s.linkRepo.DeleteBySnippet(ctx, id); err != nil {
+		log.Printf("WARN: Failed to remove entry links of snippet %s: %v", id, err)
+	}
+	if err := s.commentRepo.DeleteBySnippet(ctx, id); err != nil {
+		log.Printf("WARN: Failed to remove comments on snippet %s: %v", id, err)
+	}
 	return nil
 }
 
@@ -170,3 +709,166 @@ func (s *SnippetService) GetLanguageStats(ctx context.Context, userID string) (m
 	}
 	return stats, nil
 }
+
+// GetDependencyStats returns the packages a user's snippets import most, up to limit
+func (s *SnippetService) GetDependencyStats(ctx context.Context, userID string, limit int) ([]domain.DependencyCount, error) {
+	if limit <= 0 {
+		limit = defaultDependencyStats
+	}
+	limit = min(limit, maxDependencyStats)
+	stats, err := s.snippetRepo.GetDependencyStats(ctx, userID, int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependency stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetCodeStats aggregates lines of code by language per week or month over the given number of months
+func (s *SnippetService) GetCodeStats(ctx context.Context, userID, groupBy string, months int) (*domain.SnippetCodeStats, error) {
+	if groupBy != "week" {
+		groupBy = "month"
+	}
+	if months <= 0 {
+		months = 12
+	}
+	if months > 36 {
+		months = 36
+	}
+
+	now := time.Now().UTC()
+	since := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+
+	periods, err := s.snippetRepo.GetLinesByLanguage(ctx, userID, since, groupBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code stats: %w", err)
+	}
+
+	stats := &domain.SnippetCodeStats{
+		GroupBy:    groupBy,
+		ByLanguage: make(map[string]int),
+		Periods:    periods,
+	}
+	if stats.Periods == nil {
+		stats.Periods = []domain.LanguageLinesPeriod{}
+	}
+	for _, p := range periods {
+		stats.TotalLines += p.Lines
+		stats.ByLanguage[p.Language] += p.Lines
+	}
+
+	return stats, nil
+}
+
+// GetLanguageStatsOverTime returns per-language snippet counts bucketed by interval over
+// the given range (e.g. "6m", "1y"). Both default to monthly buckets over one year.
+func (s *SnippetService) GetLanguageStatsOverTime(ctx context.Context, userID, interval, rangeParam string) ([]domain.LanguageStatsBucket, error) {
+	switch interval {
+	case "":
+		interval = domain.StatsIntervalMonth
+	case domain.StatsIntervalDay, domain.StatsIntervalWeek, domain.StatsIntervalMonth, domain.StatsIntervalYear:
+	default:
+		return nil, ErrInvalidStatsInterval
+	}
+	if rangeParam == "" {
+		rangeParam = "1y"
+	}
+
+	since, err := parseStatsRange(rangeParam, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err := s.snippetRepo.GetLanguageStatsOverTime(ctx, userID, interval, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get language stats over time: %w", err)
+	}
+	if buckets == nil {
+		buckets = []domain.LanguageStatsBucket{}
+	}
+	return buckets, nil
+}
+
+// parseStatsRange turns a range like "30d", "12w", "6m", or "1y" into its start time
+func parseStatsRange(value string, now time.Time) (time.Time, error) {
+	if len(value) < 2 {
+		return time.Time{}, ErrInvalidStatsRange
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n <= 0 || n > 3650 {
+		return time.Time{}, ErrInvalidStatsRange
+	}
+
+	switch value[len(value)-1] {
+	case 'd':
+		return now.AddDate(0, 0, -n), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*n), nil
+	case 'm':
+		return now.AddDate(0, -n, 0), nil
+	case 'y':
+		return now.AddDate(-n, 0, 0), nil
+	}
+	return time.Time{}, ErrInvalidStatsRange
+}
+
+// GetPublic retrieves a public snippet by slug or ID for anyone, signed in or not
+func (s *SnippetService) GetPublic(ctx context.Context, slug string) (*domain.Snippet, error) {
+	return findPublicSnippet(ctx, s.snippetRepo, slug)
+}
+
+// findSnippet looks up a snippet by ID, or the snippet it was merged into
+func findSnippet(ctx context.Context, snippetRepo *mongodb.SnippetRepository, id string) (*domain.Snippet, error) {
+	snippet, err := snippetRepo.FindByID(ctx, id)
+	if err != nil || snippet != nil {
+		return snippet, err
+	}
+	targetID, err := snippetRepo.FindRedirect(ctx, id)
+	if err != nil || targetID == "" {
+		return nil, err
+	}
+	return snippetRepo.FindByID(ctx, targetID)
+}
+
+// findPublicSnippet looks up a snippet by slug. Private and hidden snippets are not found.
+func findPublicSnippet(ctx context.Context, snippetRepo *mongodb.SnippetRepository, slug string) (*domain.Snippet, error) {
+	snippet, err := findSnippet(ctx, snippetRepo, domain.SnippetIDFromSlug(slug))
+	if err != nil {
+		return nil, err
+	}
+	if snippet == nil || !snippet.IsPublic || snippet.IsHidden {
+		return nil, ErrSnippetNotFound
+	}
+	return snippet, nil
+}
+
+// ListTrending retrieves the most-viewed public snippets by time-decayed score
+func (s *SnippetService) ListTrending(ctx context.Context, limit, offset int64) ([]domain.Snippet, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
+	}
+
+	snippets, err := s.snippetRepo.FindTrending(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trending snippets: %w", err)
+	}
+	return snippets, nil
+}
+
+// TrendingRefresher returns a background job that decays trending scores so a view's
+// weight halves every halfLife, assuming the job runs once per interval
+func (s *SnippetService) TrendingRefresher(interval, halfLife time.Duration) func(ctx context.Context) error {
+	decay := math.Pow(0.5, interval.Hours()/halfLife.Hours())
+	return func(ctx context.Context) error {
+		updated, err := s.snippetRepo.RefreshTrendingScores(ctx, decay)
+		if err != nil {
+			return err
+		}
+		if updated > 0 {
+			log.Printf("Refreshed trending scores for %d snippets", updated)
+		}
+		return nil
+	}
+}
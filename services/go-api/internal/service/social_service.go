@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUserNotFound  = apperr.New(ErrNotFound, "user not found")
+	ErrFollowSelf    = apperr.New(ErrValidation, "you cannot follow yourself")
+	ErrNotFollowing  = apperr.New(ErrNotFound, "not following this user")
+	ErrInvalidCursor = apperr.New(ErrValidation, "invalid feed cursor")
+)
+
+const (
+	// DefaultFeedLimit and MaxFeedLimit bound how many items a feed page returns
+	DefaultFeedLimit = 20
+	MaxFeedLimit     = 50
+
+	// maxFeedFollowees caps how many followed users a feed is built from
+	maxFeedFollowees = 1000
+)
+
+// SocialService handles following users and building feeds of their public entries and snippets.
+// Feeds are pulled at read time rather than fanned out on write: each page queries the followed
+// users' public work older than the cursor and merges entries and snippets by creation time.
+type SocialService struct {
+	followRepo  *postgres.FollowRepository
+	userRepo    *postgres.UserRepository
+	journalRepo *postgres.JournalRepository
+	snippetRepo *mongodb.SnippetRepository
+}
+
+// NewSocialService creates a new social service
+func NewSocialService(followRepo *postgres.FollowRepository, userRepo *postgres.UserRepository, journalRepo *postgres.JournalRepository, snippetRepo *mongodb.SnippetRepository) *SocialService {
+	return &SocialService{
+		followRepo:  followRepo,
+		userRepo:    userRepo,
+		journalRepo: journalRepo,
+		snippetRepo: snippetRepo,
+	}
+}
+
+// Profile returns a user's public profile as seen by viewerID
+func (s *SocialService) Profile(ctx context.Context, viewerID, userID uuid.UUID) (*domain.PublicProfile, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	followers, following, err := s.followRepo.Counts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	isFollowing := false
+	if viewerID != uuid.Nil && viewerID != userID {
+		if isFollowing, err = s.followRepo.IsFollowing(ctx, viewerID, userID); err != nil {
+			return nil, err
+		}
+	}
+	return &domain.PublicProfile{
+		ID:          user.ID,
+		DisplayName: user.DisplayName,
+		Followers:   followers,
+		Following:   following,
+		IsFollowing: isFollowing,
+		CreatedAt:   user.CreatedAt,
+	}, nil
+}
+
+// Follow makes followerID follow followeeID. Following someone twice is not an error.
+func (s *SocialService) Follow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	if followerID == followeeID {
+		return ErrFollowSelf
+	}
+	if _, err := s.findUser(ctx, followeeID); err != nil {
+		return err
+	}
+	return s.followRepo.Follow(ctx, followerID, followeeID)
+}
+
+// Unfollow stops followerID following followeeID
+func (s *SocialService) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	removed, err := s.followRepo.Unfollow(ctx, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrNotFollowing
+	}
+	return nil
+}
+
+// Followers lists the users following userID
+func (s *SocialService) Followers(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.UserSummary, int, error) {
+	if _, err := s.findUser(ctx, userID); err != nil {
+		return nil, 0, err
+	}
+	users, err := s.followRepo.ListFollowers(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	followers, _, err := s.followRepo.Counts(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, followers, nil
+}
+
+// Following lists the users userID follows
+func (s *SocialService) Following(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.UserSummary, int, error) {
+	if _, err := s.findUser(ctx, userID); err != nil {
+		return nil, 0, err
+	}
+	users, err := s.followRepo.ListFollowing(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	_, following, err := s.followRepo.Counts(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, following, nil
+}
+
+// Feed returns public entries and snippets from the users userID follows, newest first.
+// cursor is the NextCursor of the previous page, or empty for the first page.
+func (s *SocialService) Feed(ctx context.Context, userID uuid.UUID, cursor string, limit int) (*domain.FeedPage, error) {
+	before, err := parseFeedCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	authors, err := s.followRepo.ListFollowing(ctx, userID, maxFeedFollowees, 0)
+	if err != nil {
+		return nil, err
+	}
+	return s.page(ctx, authors, before, limit)
+}
+
+// Activity returns a user's own public entries and snippets, newest first
+func (s *SocialService) Activity(ctx context.Context, userID uuid.UUID, cursor string, limit int) (*domain.FeedPage, error) {
+	before, err := parseFeedCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.page(ctx, []domain.UserSummary{{ID: user.ID, DisplayName: user.DisplayName}}, before, limit)
+}
+
+// page merges the authors' public entries and snippets created before a time into one page
+func (s *SocialService) page(ctx context.Context, authors []domain.UserSummary, before time.Time, limit int) (*domain.FeedPage, error) {
+	if limit <= 0 {
+		limit = DefaultFeedLimit
+	}
+	if limit > MaxFeedLimit {
+		limit = MaxFeedLimit
+	}
+	feed := &domain.FeedPage{Data: []domain.FeedItem{}}
+	if len(authors) == 0 {
+		return feed, nil
+	}
+
+	byID := make(map[string]domain.UserSummary, len(authors))
+	userIDs := make([]uuid.UUID, len(authors))
+	snippetUserIDs := make([]string, len(authors))
+	for i, a := range authors {
+		byID[a.ID.String()] = a
+		userIDs[i] = a.ID
+		snippetUserIDs[i] = a.ID.String()
+	}
+
+	// Each source can fill the whole page on its own, so fetch limit from both and keep the newest
+	entries, err := s.journalRepo.FindPublicByUsers(ctx, userIDs, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	snippets, err := s.snippetRepo.FindPublicByUsers(ctx, snippetUserIDs, before, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.FeedItem, 0, len(entries)+len(snippets))
+	for i := range entries {
+		e := &entries[i]
+		items = append(items, domain.FeedItem{
+			Type:      domain.FeedItemEntry,
+			Author:    byID[e.UserID.String()],
+			Entry:     e,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	for i := range snippets {
+		sn := &snippets[i]
+		items = append(items, domain.FeedItem{
+			Type:      domain.FeedItemSnippet,
+			Author:    byID[sn.UserID],
+			Snippet:   sn,
+			CreatedAt: sn.CreatedAt,
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	feed.Data = items
+	// A full page may have more behind it; a short one means both sources ran out
+	if len(items) == limit {
+		feed.NextCursor = items[len(items)-1].CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return feed, nil
+}
+
+func (s *SocialService) findUser(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// parseFeedCursor reads a feed cursor, which is the creation time of the last item on the previous page
+func parseFeedCursor(cursor string) (time.Time, error) {
+	if cursor == "" {
+		return time.Now().UTC(), nil
+	}
+	before, err := time.Parse(time.RFC3339Nano, cursor)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	return before, nil
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/oidc"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSSOUnavailable   = apperr.New(ErrUnavailable, "single sign-on is not configured on the server")
+	ErrInvalidSSOState  = apperr.New(ErrValidation, "invalid or expired sign-in link; start again from the login page")
+	ErrSSOEmail         = apperr.New(ErrForbidden, "your identity provider did not share a verified email address")
+	ErrSSONoAccount     = apperr.New(ErrForbidden, "there is no account for you yet; ask an admin to create one")
+	ErrSSOAccountExists = apperr.New(ErrConflict, "an account with your email already exists; sign in with your password")
+)
+
+// SSOStateTTL is how long a user has to sign in at the provider
+const SSOStateTTL = 10 * time.Minute
+
+// SSOConfig holds the OpenID Connect provider single sign-on goes through
+type SSOConfig struct {
+	IssuerURL     string
+	ClientID      string
+	ClientSecret  string
+	CallbackURL   string // public URL of GET /api/auth/oidc/callback, registered with the provider
+	ReturnURL     string // web app page that receives the token after sign-in
+	AutoProvision bool   // create accounts for people signing in for the first time
+	LinkByEmail   bool   // link first sign-ins to existing accounts with the same verified email
+	StateSecret   string // signs OAuth state so callbacks cannot be forged
+}
+
+// SSOService signs users in through an OpenID Connect provider, creating accounts for people
+// signing in for the first time when AutoProvision is on
+type SSOService struct {
+	identityRepo *postgres.IdentityRepository
+	userRepo     *postgres.UserRepository
+	authService  *AuthService
+	provider     *oidc.Provider
+	cfg          SSOConfig
+}
+
+// NewSSOService creates a new single sign-on service
+func NewSSOService(identityRepo *postgres.IdentityRepository, userRepo *postgres.UserRepository, authService *AuthService, cfg SSOConfig) *SSOService {
+	return &SSOService{
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		provider:     oidc.NewProvider(cfg.IssuerURL, cfg.ClientID, cfg.ClientSecret),
+		cfg:          cfg,
+	}
+}
+
+// Enabled reports whether single sign-on is configured
+func (s *SSOService) Enabled() bool {
+	return s.provider.Configured()
+}
+
+// LoginURL returns where to send the user to sign in at the provider, and the state the callback
+// must come back with. Callers bind the state to the browser, so a callback started by someone
+// else is refused.
+func (s *SSOService) LoginURL(ctx context.Context) (string, string, error) {
+	if !s.Enabled() {
+		return "", "", ErrSSOUnavailable
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+	state := s.signState(nonce, time.Now().Add(SSOStateTTL))
+	authorizeURL, err := s.provider.AuthorizeURL(ctx, state, nonce, s.cfg.CallbackURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to start sign-in: %w", err)
+	}
+	return authorizeURL, state, nil
+}
+
+// CompleteLogin exchanges the code from the provider's redirect and signs the user in, returning
+// a token for their personal workspace and a refresh token to renew it with
+func (s *SSOService) CompleteLogin(ctx context.Context, code, state string) (*domain.User, string, string, error) {
+	if !s.Enabled() {
+		return nil, "", "", ErrSSOUnavailable
+	}
+	nonce, err := s.verifyState(state, time.Now())
+	if err != nil {
+		return nil, "", "", err
+	}
+	identity, err := s.provider.Exchange(ctx, code, nonce, s.cfg.CallbackURL)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to sign in with provider: %w", err)
+	}
+
+	user, err := s.resolveUser(ctx, identity)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if user.DeactivatedAt != nil {
+		return nil, "", "", ErrAccountDeactivated
+	}
+	now := time.Now().UTC()
+	if err := s.identityRepo.Link(ctx, &domain.UserIdentity{
+		UserID:      user.ID,
+		Issuer:      identity.Issuer,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	}); err != nil {
+		return nil, "", "", err
+	}
+
+	token, err := s.authService.IssueWorkspaceToken(ctx, user.ID, uuid.Nil)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	refreshToken, err := s.authService.IssueRefreshToken(ctx, user.ID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return user, token, refreshToken, nil
+}
+
+// resolveUser finds the account an identity signs in to: the one it is linked to, else an
+// account with the same verified email if LinkByEmail is on, else a new one if AutoProvision is on
+func (s *SSOService) resolveUser(ctx context.Context, identity *oidc.Identity) (*domain.User, error) {
+	userID, err := s.identityRepo.FindUserID(ctx, identity.Issuer, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if userID != uuid.Nil {
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+		if user != nil {
+			return user, nil
+		}
+	}
+
+	// Emails the provider hasn't verified could claim someone else's account
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrSSOEmail
+	}
+	existing, err := s.userRepo.FindByEmail(ctx, identity.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if existing != nil {
+		if !s.cfg.LinkByEmail {
+			return nil, ErrSSOAccountExists
+		}
+		return existing, nil
+	}
+	if !s.cfg.AutoProvision {
+		return nil, ErrSSONoAccount
+	}
+
+	displayName := identity.Name
+	if displayName == "" {
+		displayName, _, _ = strings.Cut(identity.Email, "@")
+	}
+	return s.authService.Provision(ctx, identity.Email, displayName)
+}
+
+// ReturnURL is the web app page to send the user back to. The token and refresh token go in the
+// fragment, which browsers don't send to servers or in Referer headers; failures carry a short
+// reason instead.
+func (s *SSOService) ReturnURL(token, refreshToken string, err error) string {
+	if err == nil {
+		fragment := url.Values{"token": {token}}
+		if refreshToken != "" {
+			fragment.Set("refreshToken", refreshToken)
+		}
+		return s.cfg.ReturnURL + "#" + fragment.Encode()
+	}
+	reason := "failed"
+	switch {
+	case errors.Is(err, ErrInvalidSSOState):
+		reason = "expired"
+	case errors.Is(err, ErrSSOEmail):
+		reason = "email_unverified"
+	case errors.Is(err, ErrSSONoAccount):
+		reason = "no_account"
+	case errors.Is(err, ErrSSOAccountExists):
+		reason = "account_exists"
+	case errors.Is(err, ErrAccountDeactivated):
+		reason = "deactivated"
+	}
+	sep := "?"
+	if strings.Contains(s.cfg.ReturnURL, "?") {
+		sep = "&"
+	}
+	return s.cfg.ReturnURL + sep + "sso=error&reason=" + reason
+}
+
+// signState encodes "<nonce>:<expiry>" with an HMAC so the callback can trust it
+func (s *SSOService) signState(nonce string, expires time.Time) string {
+	payload := nonce + ":" + strconv.FormatInt(expires.Unix(), 10)
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + s.stateMAC(encoded)
+}
+
+// verifyState checks a state from signState and returns the nonce it was issued with
+func (s *SSOService) verifyState(state string, now time.Time) (string, error) {
+	encoded, mac, ok := strings.Cut(state, ".")
+	if !ok || !hmac.Equal([]byte(mac), []byte(s.stateMAC(encoded))) {
+		return "", ErrInvalidSSOState
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidSSOState
+	}
+	nonce, expiry, ok := strings.Cut(string(raw), ":")
+	if !ok || nonce == "" {
+		return "", ErrInvalidSSOState
+	}
+	expires, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || now.Unix() > expires {
+		return "", ErrInvalidSSOState
+	}
+	return nonce, nil
+}
+
+func (s *SSOService) stateMAC(encoded string) string {
+	mac := hmac.New(sha256.New, []byte("sso-state:"+s.cfg.StateSecret))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
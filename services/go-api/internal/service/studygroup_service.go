@@ -3,14 +3,25 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"devjournal/internal/domain"
+	"devjournal/internal/policy"
 	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrGroupNameRequired   = apperr.New(ErrValidation, "group name is required")
+	ErrStudyGroupNotFound  = apperr.New(ErrNotFound, "study group not found")
+	ErrGroupArchived       = apperr.New(ErrForbidden, "the study group is archived; an owner or admin can reactivate it")
+	ErrNotGroupReactivator = apperr.New(ErrForbidden, "only group owners and admins can reactivate a group")
+	ErrNotGroupInviter     = apperr.New(ErrForbidden, "only group owners and admins can invite people to a group")
+)
+
 // StudyGroupService handles study group business logic
 type StudyGroupService struct {
 	groupRepo *postgres.StudyGroupRepository
@@ -32,7 +43,7 @@ type CreateGroupRequest struct {
 // Create creates a new study group
 func (s *StudyGroupService) Create(ctx context.Context, userID uuid.UUID, req *CreateGroupRequest) (*domain.StudyGroup, error) {
 	if req.Name == "" {
-		return nil, fmt.Errorf("group name is required")
+		return nil, ErrGroupNameRequired
 	}
 
 	// Default max members if not specified
@@ -49,9 +60,24 @@ func (s *StudyGroupService) Create(ctx context.Context, userID uuid.UUID, req *C
 	return group, nil
 }
 
-// GetByID retrieves a study group by ID
-func (s *StudyGroupService) GetByID(ctx context.Context, id uuid.UUID) (*domain.StudyGroup, error) {
-	return s.groupRepo.FindByID(ctx, id)
+// GetByID retrieves a study group the user can see: one they belong to, or a public one.
+// Private groups are reported as not found to non-members, so they can't learn they exist.
+func (s *StudyGroupService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.StudyGroup, error) {
+	group, err := s.groupRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, ErrStudyGroupNotFound
+	}
+	role, err := s.groupRepo.GetMemberRole(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check group role: %w", err)
+	}
+	if policy.Authorize(policy.User(ctx, userID), policy.Read, policy.Group{Public: group.IsPublic, Role: role}) != policy.Allow {
+		return nil, ErrStudyGroupNotFound
+	}
+	return group, nil
 }
 
 // ListByUser retrieves all study groups a user is a member of
@@ -59,13 +85,13 @@ func (s *StudyGroupService) ListByUser(ctx context.Context, userID uuid.UUID) ([
 	return s.groupRepo.FindByUserID(ctx, userID)
 }
 
-// ListPublic retrieves all public study groups for discovery
+// ListPublic retrieves public study groups for discovery, leaving out archived ones
 func (s *StudyGroupService) ListPublic(ctx context.Context, limit, offset int) ([]domain.StudyGroup, int, error) {
 	if limit <= 0 {
 		limit = 20
 	}
-	if limit > 100 {
-		limit = 100
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
 	}
 
 	groups, err := s.groupRepo.ListPublic(ctx, limit, offset)
@@ -73,7 +99,7 @@ func (s *StudyGroupService) ListPublic(ctx context.Context, limit, offset int) (
 		return nil, 0, err
 	}
 
-	total, err := s.groupRepo.Count(ctx)
+	total, err := s.groupRepo.CountPublic(ctx)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -81,22 +107,117 @@ func (s *StudyGroupService) ListPublic(ctx context.Context, limit, offset int) (
 	return groups, total, nil
 }
 
-// Join adds a user to a study group
-func (s *StudyGroupService) Join(ctx context.Context, groupID, userID uuid.UUID) error {
-	// Check if group exists
+// Join adds a user to a study group. Anyone can join a public group, but a private one needs an
+// invite code, which joining uses up; without one, private groups are reported as not found.
+// Archived groups can't be joined.
+func (s *StudyGroupService) Join(ctx context.Context, groupID, userID uuid.UUID, inviteCode string) error {
 	group, err := s.groupRepo.FindByID(ctx, groupID)
-	if err != nil || group == nil {
-		return fmt.Errorf("study group not found")
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		return ErrStudyGroupNotFound
+	}
+	role, err := s.groupRepo.GetMemberRole(ctx, groupID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check group role: %w", err)
+	}
+	now := time.Now().UTC()
+	invited := false
+	if role == "" && !group.IsPublic && inviteCode != "" {
+		if invited, err = s.groupRepo.HasInvite(ctx, groupID, hashToken(inviteCode), now); err != nil {
+			return err
+		}
+	}
+	target := policy.Group{Public: group.IsPublic, Role: role, Invited: invited}
+	if policy.Authorize(policy.User(ctx, userID), policy.Join, target) != policy.Allow {
+		return ErrStudyGroupNotFound
+	}
+	if group.Archived() {
+		return ErrGroupArchived
+	}
+	if role != "" {
+		return nil
 	}
 
 	member := &domain.StudyGroupMember{
 		GroupID:  groupID,
 		UserID:   userID,
 		Role:     "member",
-		JoinedAt: time.Now().UTC(),
+		JoinedAt: now,
+	}
+	if invited {
+		joined, err := s.groupRepo.AddMemberWithInvite(ctx, member, hashToken(inviteCode), now)
+		if err != nil {
+			return err
+		}
+		if !joined {
+			return ErrStudyGroupNotFound
+		}
+	} else if err := s.groupRepo.AddMember(ctx, member); err != nil {
+		return err
 	}
+	return s.groupRepo.RecordActivity(ctx, map[uuid.UUID]time.Time{groupID: member.JoinedAt})
+}
 
-	return s.groupRepo.AddMember(ctx, member)
+// CreateInvite creates a single-use invite to a study group (group owners and admins only)
+func (s *StudyGroupService) CreateInvite(ctx context.Context, groupID, userID uuid.UUID) (*domain.GroupInvite, error) {
+	role, err := groupRole(ctx, s.groupRepo, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isGroupManager(role) {
+		return nil, ErrNotGroupInviter
+	}
+	code, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	invite := &domain.GroupInvite{
+		ID:        uuid.New(),
+		GroupID:   groupID,
+		Code:      code,
+		CodeHash:  hashToken(code),
+		CreatedBy: userID,
+		ExpiresAt: now.Add(domain.GroupInviteTTL),
+		CreatedAt: now,
+	}
+	if err := s.groupRepo.CreateInvite(ctx, invite); err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+// ExpiredInviteCleaner returns a job that deletes group invites that expired unused
+func (s *StudyGroupService) ExpiredInviteCleaner() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		deleted, err := s.groupRepo.DeleteExpiredInvites(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			log.Printf("Deleted %d expired group invites", deleted)
+		}
+		return nil
+	}
+}
+
+// Reactivate unarchives a group and restarts its inactivity clock (group owners and admins
+// only). Reactivating a group that isn't archived clears any pending archival.
+func (s *StudyGroupService) Reactivate(ctx context.Context, groupID, userID uuid.UUID) (*domain.StudyGroup, error) {
+	role, err := groupRole(ctx, s.groupRepo, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isGroupManager(role) {
+		return nil, ErrNotGroupReactivator
+	}
+
+	if err := s.groupRepo.Reactivate(ctx, groupID, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, groupID, userID)
 }
 
 // Leave removes a user from a study group
@@ -104,14 +225,18 @@ func (s *StudyGroupService) Leave(ctx context.Context, groupID, userID uuid.UUID
 	return s.groupRepo.RemoveMember(ctx, groupID, userID)
 }
 
-// GetMembers retrieves all members of a study group
-func (s *StudyGroupService) GetMembers(ctx context.Context, groupID uuid.UUID) ([]domain.StudyGroupMember, error) {
-	return s.groupRepo.GetMembers(ctx, groupID)
+// GetMembers retrieves the public profiles of the members of a study group the user can see
+func (s *StudyGroupService) GetMembers(ctx context.Context, groupID, userID uuid.UUID) ([]domain.MemberProfile, error) {
+	if _, err := s.GetByID(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	return s.groupRepo.GetMemberProfiles(ctx, groupID)
 }
 
-// IsMember checks if a user is a member of a study group
-func (s *StudyGroupService) IsMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error) {
-	return s.groupRepo.IsMember(ctx, groupID, userID)
+// CheckMember returns an error unless the user belongs to the group
+func (s *StudyGroupService) CheckMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	_, err := groupRole(ctx, s.groupRepo, groupID, userID)
+	return err
 }
 
 // Delete removes a study group (only by owner)
@@ -119,6 +244,27 @@ func (s *StudyGroupService) Delete(ctx context.Context, id, ownerID uuid.UUID) e
 	return s.groupRepo.Delete(ctx, id, ownerID)
 }
 
+// groupRole returns the user's role in a group they belong to. Non-members get
+// ErrNotGroupMember for public groups, and ErrStudyGroupNotFound for private ones, so they can't
+// learn private groups exist.
+func groupRole(ctx context.Context, groupRepo *postgres.StudyGroupRepository, groupID, userID uuid.UUID) (string, error) {
+	role, err := groupRepo.GetMemberRole(ctx, groupID, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to check group role: %w", err)
+	}
+	if role != "" {
+		return role, nil
+	}
+	group, err := groupRepo.FindByID(ctx, groupID)
+	if err != nil {
+		return "", err
+	}
+	if group == nil || policy.Authorize(policy.User(ctx, userID), policy.Read, policy.Group{Public: group.IsPublic}) == policy.Hide {
+		return "", ErrStudyGroupNotFound
+	}
+	return "", ErrNotGroupMember
+}
+
 // GetMemberCount returns the number of members in a group
 func (s *StudyGroupService) GetMemberCount(ctx context.Context, groupID uuid.UUID) (int, error) {
 	return s.groupRepo.GetMemberCount(ctx, groupID)
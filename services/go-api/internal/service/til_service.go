@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidTIL  = apperr.New(ErrValidation, fmt.Sprintf("TIL must be a single line of 1 to %d characters", domain.MaxTILLength))
+	ErrTILNotFound = apperr.New(ErrNotFound, "TIL entry not found")
+)
+
+// TILService handles "Today I Learned" micro-entries
+type TILService struct {
+	tilRepo *postgres.TILRepository
+}
+
+// NewTILService creates a new TIL service
+func NewTILService(tilRepo *postgres.TILRepository) *TILService {
+	return &TILService{tilRepo: tilRepo}
+}
+
+// Create records a new one-liner TIL
+func (s *TILService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateTILRequest) (*domain.TILEntry, error) {
+	content := strings.TrimSpace(req.Content)
+	if content == "" || strings.ContainsAny(content, "\r\n") || utf8.RuneCountInString(content) > domain.MaxTILLength {
+		return nil, ErrInvalidTIL
+	}
+
+	til := domain.NewTILEntry(userID, content, req.Tags)
+	if err := s.tilRepo.Create(ctx, til); err != nil {
+		return nil, fmt.Errorf("failed to create TIL: %w", err)
+	}
+
+	return til, nil
+}
+
+// List retrieves a user's TIL feed
+func (s *TILService) List(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.TILEntry, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
+	}
+
+	tils, err := s.tilRepo.FindByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list TILs: %w", err)
+	}
+	if tils == nil {
+		tils = []domain.TILEntry{}
+	}
+
+	total, err := s.tilRepo.Count(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count TILs: %w", err)
+	}
+
+	return tils, total, nil
+}
+
+// Delete removes a TIL
+func (s *TILService) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	if err := s.tilRepo.Delete(ctx, id, userID); err != nil {
+		return ErrTILNotFound
+	}
+	return nil
+}
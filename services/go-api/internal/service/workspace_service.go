@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidWorkspace     = apperr.New(ErrValidation, "workspace name is required (max 100 characters)")
+	ErrWorkspaceSlugTaken   = apperr.New(ErrConflict, "workspace slug is already taken")
+	ErrNotWorkspaceMember   = apperr.New(ErrForbidden, "not a member of this workspace")
+	ErrNotWorkspaceAdmin    = apperr.New(ErrForbidden, "only workspace owners and admins can manage members")
+	ErrPersonalWorkspace    = apperr.New(ErrValidation, "personal workspaces cannot have other members")
+	ErrWorkspaceUserUnknown = apperr.New(ErrNotFound, "no user with that email")
+	ErrSeatLimitReached     = apperr.New(ErrConflict, "organization has no free seats")
+	ErrInvalidMemberRole    = apperr.New(ErrValidation, "role must be admin or member")
+)
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// WorkspaceService handles workspaces, membership, and workspace switching
+type WorkspaceService struct {
+	workspaceRepo *postgres.WorkspaceRepository
+	userRepo      *postgres.UserRepository
+	authService   *AuthService
+	quotaService  *QuotaService
+}
+
+// NewWorkspaceService creates a new workspace service
+func NewWorkspaceService(workspaceRepo *postgres.WorkspaceRepository, userRepo *postgres.UserRepository, authService *AuthService, quotaService *QuotaService) *WorkspaceService {
+	return &WorkspaceService{
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+		authService:   authService,
+		quotaService:  quotaService,
+	}
+}
+
+// List returns every workspace a user belongs to
+func (s *WorkspaceService) List(ctx context.Context, userID uuid.UUID) ([]domain.Workspace, error) {
+	workspaces, err := s.workspaceRepo.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	if workspaces == nil {
+		workspaces = []domain.Workspace{}
+	}
+	return workspaces, nil
+}
+
+// Create creates a shared workspace owned by userID. The slug defaults to one derived from the name.
+func (s *WorkspaceService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateWorkspaceRequest) (*domain.Workspace, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, ErrInvalidWorkspace
+	}
+
+	if err := s.quotaService.CheckWorkspaces(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	slug, err := s.claimSlug(ctx, name, req.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	ws := domain.NewWorkspace(name, slug, userID)
+	if err := s.workspaceRepo.Create(ctx, ws); err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	return ws, nil
+}
+
+// Switch issues a token whose workspace claim scopes subsequent requests to workspaceID
+func (s *WorkspaceService) Switch(ctx context.Context, userID, workspaceID uuid.UUID) (string, error) {
+	if _, err := s.requireRole(ctx, workspaceID, userID); err != nil {
+		return "", err
+	}
+	return s.authService.IssueWorkspaceToken(ctx, userID, workspaceID)
+}
+
+// ListMembers returns the members of a workspace the user belongs to
+func (s *WorkspaceService) ListMembers(ctx context.Context, workspaceID, userID uuid.UUID) ([]domain.WorkspaceMember, error) {
+	if _, err := s.requireRole(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	members, err := s.workspaceRepo.GetMembers(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if members == nil {
+		members = []domain.WorkspaceMember{}
+	}
+	return members, nil
+}
+
+// AddMember adds a user, by email, to a shared workspace. Only owners and admins can add members.
+func (s *WorkspaceService) AddMember(ctx context.Context, workspaceID, actorID uuid.UUID, req *domain.AddWorkspaceMemberRequest) (*domain.WorkspaceMember, error) {
+	if workspaceID == actorID {
+		return nil, ErrPersonalWorkspace
+	}
+	role, err := s.requireRole(ctx, workspaceID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if role != domain.WorkspaceRoleOwner && role != domain.WorkspaceRoleAdmin {
+		return nil, ErrNotWorkspaceAdmin
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, strings.TrimSpace(req.Email))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrWorkspaceUserUnknown
+	}
+	// A user's personal workspace shares their ID, so nobody else can be added to it
+	if workspaceID == user.ID {
+		return nil, ErrPersonalWorkspace
+	}
+
+	// Organizations cap their membership at the number of billed seats
+	existing, err := s.workspaceRepo.GetMemberRole(ctx, workspaceID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == "" {
+		limit, used, err := s.workspaceRepo.SeatUsage(ctx, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if limit > 0 && used >= limit {
+			return nil, ErrSeatLimitReached
+		}
+	}
+
+	member := &domain.WorkspaceMember{
+		WorkspaceID: workspaceID,
+		UserID:      user.ID,
+		DisplayName: user.DisplayName,
+		Role:        domain.WorkspaceRoleMember,
+		JoinedAt:    time.Now().UTC(),
+	}
+	if req.Role == domain.WorkspaceRoleAdmin {
+		member.Role = domain.WorkspaceRoleAdmin
+	}
+
+	if err := s.workspaceRepo.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember removes a member from a workspace. Admins can remove anyone but the owner;
+// members can only remove themselves.
+func (s *WorkspaceService) RemoveMember(ctx context.Context, workspaceID, actorID, userID uuid.UUID) error {
+	role, err := s.requireRole(ctx, workspaceID, actorID)
+	if err != nil {
+		return err
+	}
+	if actorID != userID && role != domain.WorkspaceRoleOwner && role != domain.WorkspaceRoleAdmin {
+		return ErrNotWorkspaceAdmin
+	}
+	if err := s.workspaceRepo.RemoveMember(ctx, workspaceID, userID); err != nil {
+		return err
+	}
+	// Their tokens for the workspace stop working on the next request
+	s.authService.forgetActive(userID)
+	return nil
+}
+
+// UpdateMemberRole changes a member's role. Only owners and admins can change roles, and the owner's role is fixed.
+func (s *WorkspaceService) UpdateMemberRole(ctx context.Context, workspaceID, actorID, userID uuid.UUID, role string) error {
+	if role != domain.WorkspaceRoleAdmin && role != domain.WorkspaceRoleMember {
+		return ErrInvalidMemberRole
+	}
+	actorRole, err := s.requireRole(ctx, workspaceID, actorID)
+	if err != nil {
+		return err
+	}
+	if actorRole != domain.WorkspaceRoleOwner && actorRole != domain.WorkspaceRoleAdmin {
+		return ErrNotWorkspaceAdmin
+	}
+	return s.workspaceRepo.UpdateMemberRole(ctx, workspaceID, userID, role)
+}
+
+// claimSlug normalizes a slug, defaulting to one derived from the name, and checks it is free
+func (s *WorkspaceService) claimSlug(ctx context.Context, name, slug string) (string, error) {
+	if slug == "" {
+		slug = name
+	}
+	slug = strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(slug), "-"), "-")
+	// "personal-" slugs are reserved for personal workspaces
+	if slug == "" || len(slug) > 100 || strings.HasPrefix(slug, "personal-") {
+		return "", ErrInvalidWorkspace
+	}
+
+	taken, err := s.workspaceRepo.SlugExists(ctx, slug)
+	if err != nil {
+		return "", err
+	}
+	if taken {
+		return "", ErrWorkspaceSlugTaken
+	}
+	return slug, nil
+}
+
+// requireRole returns the user's role in a workspace, or ErrNotWorkspaceMember
+func (s *WorkspaceService) requireRole(ctx context.Context, workspaceID, userID uuid.UUID) (string, error) {
+	role, err := s.workspaceRepo.GetMemberRole(ctx, workspaceID, userID)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", ErrNotWorkspaceMember
+	}
+	return role, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/mongodb"
+	"devjournal/internal/repository/postgres"
+	"devjournal/internal/tenant"
+	"devjournal/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// yearlyReviewStaleAfter is how long a report of a year still going on is served before the
+	// refresh job recomputes it
+	yearlyReviewStaleAfter = 24 * time.Hour
+
+	// yearlyReviewBatch is how many reports one refresh run recomputes at most
+	yearlyReviewBatch = 100
+)
+
+// YearlyReviewService builds "year in review" reports from a user's journal, snippets, and
+// progress. Reports are aggregated once and cached; a background job keeps those of the
+// current year up to date.
+type YearlyReviewService struct {
+	yearlyRepo  *postgres.YearlyReviewRepository
+	snippetRepo *mongodb.SnippetRepository
+}
+
+// NewYearlyReviewService creates a new yearly review service
+func NewYearlyReviewService(yearlyRepo *postgres.YearlyReviewRepository, snippetRepo *mongodb.SnippetRepository) *YearlyReviewService {
+	return &YearlyReviewService{yearlyRepo: yearlyRepo, snippetRepo: snippetRepo}
+}
+
+// Get returns the user's report for a year in the active workspace. Reports are served from
+// the cache, and aggregated on the spot the first time a year is asked for.
+func (s *YearlyReviewService) Get(ctx context.Context, userID uuid.UUID, year int) (*domain.YearlyReview, error) {
+	now := time.Now().UTC()
+	if year < domain.MinYearlyReviewYear || year > now.Year() {
+		return nil, apperr.Newf(ErrValidation, "year must be between %d and %d", domain.MinYearlyReviewYear, now.Year())
+	}
+
+	review, err := s.yearlyRepo.Get(ctx, userID, year)
+	if err != nil {
+		return nil, err
+	}
+	if review != nil {
+		return review, nil
+	}
+	return s.compute(ctx, userID, year, now)
+}
+
+// Refresher returns a job that recomputes cached reports of years that hadn't ended when they
+// were computed, once they are a day old
+func (s *YearlyReviewService) Refresher() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		now := time.Now().UTC()
+		stale, err := s.yearlyRepo.ListStale(ctx, now.Add(-yearlyReviewStaleAfter), yearlyReviewBatch)
+		if err != nil {
+			return err
+		}
+		for _, key := range stale {
+			if _, err := s.compute(tenant.WithWorkspace(ctx, key.WorkspaceID), key.UserID, key.Year, now); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				log.Printf("WARN: Failed to refresh %d review of user %s: %v", key.Year, key.UserID, err)
+			}
+		}
+		return nil
+	}
+}
+
+// compute aggregates a user's year and caches the report
+func (s *YearlyReviewService) compute(ctx context.Context, userID uuid.UUID, year int, now time.Time) (*domain.YearlyReview, error) {
+	from, until := domain.YearBounds(year)
+	review := &domain.YearlyReview{Year: year, Final: !now.Before(until), ComputedAt: now}
+	if err := s.yearlyRepo.Aggregate(ctx, userID, review); err != nil {
+		return nil, err
+	}
+
+	languages, err := s.snippetRepo.CountByLanguageBetween(ctx, userID.String(), from, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count snippets: %w", err)
+	}
+	review.SnippetsByLanguage = languages
+	for _, language := range languages {
+		review.TotalSnippets += language.Count
+	}
+
+	if err := s.yearlyRepo.Upsert(ctx, userID, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
@@ -0,0 +1,330 @@
+// Package sitepublish sends public journal entries to a user's static site: as JSON to a build
+// webhook, or as Markdown files with front matter committed to a GitHub repository
+package sitepublish
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unicode"
+
+	"devjournal/internal/domain"
+	"devjournal/pkg/apperr"
+)
+
+// EventPublished is the event of a site webhook delivery
+const EventPublished = "entry.published"
+
+// maxSlugLength caps the slug part of post file names, in characters
+const maxSlugLength = 80
+
+var (
+	// ErrRejected is returned when a site refuses a delivery in a way retrying cannot fix, such as
+	// a deleted webhook or a revoked token
+	ErrRejected = errors.New("site refused the entry")
+
+	// ErrNoAccess is returned when GitHub doesn't let a token commit to a repository
+	ErrNoAccess = apperr.New(apperr.ErrValidation, "GitHub did not accept the token for this repository")
+)
+
+// Slug makes a URL-safe file name part from an entry title: lowercase letters and digits joined
+// by dashes, or "entry" if the title has none
+func Slug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	slug := []rune(b.String())
+	if len(slug) > maxSlugLength {
+		slug = []rune(strings.TrimRight(string(slug[:maxSlugLength]), "-"))
+	}
+	if len(slug) == 0 {
+		return "entry"
+	}
+	return string(slug)
+}
+
+// FileName names an entry's post the way Jekyll and Hugo expect: its date and slug
+func FileName(entry *domain.JournalEntry) string {
+	return entry.CreatedAt.UTC().Format("2006-01-02") + "-" + Slug(entry.Title) + ".md"
+}
+
+// Markdown renders an entry as a Markdown post with YAML front matter
+func Markdown(entry *domain.JournalEntry) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", strconv.Quote(entry.Title))
+	fmt.Fprintf(&b, "date: %s\n", entry.CreatedAt.UTC().Format(time.RFC3339))
+	tags := make([]string, len(entry.Tags))
+	for i, tag := range entry.Tags {
+		tags[i] = strconv.Quote(tag)
+	}
+	fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(tags, ", "))
+	if entry.Mood != "" {
+		fmt.Fprintf(&b, "mood: %s\n", strconv.Quote(entry.Mood))
+	}
+	b.WriteString("---\n\n")
+	b.WriteString(strings.TrimSpace(entry.Content))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// Post returns the webhook body for a newly public entry
+func Post(entry *domain.JournalEntry) *domain.SitePost {
+	tags := entry.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return &domain.SitePost{
+		Event: EventPublished,
+		Entry: domain.SitePostEntry{
+			ID:        entry.ID,
+			Title:     entry.Title,
+			Slug:      Slug(entry.Title),
+			Content:   entry.Content,
+			Mood:      entry.Mood,
+			Tags:      tags,
+			Markdown:  Markdown(entry),
+			CreatedAt: entry.CreatedAt,
+			UpdatedAt: entry.UpdatedAt,
+		},
+	}
+}
+
+// PostWebhook sends a newly public entry to a site's build webhook. Webhook URLs come from users,
+// so the request is only made to public internet addresses, checked when connecting so a DNS
+// answer that changes after CheckWebhookURL can't reach internal services. Redirects aren't
+// followed, and the reply body is never read, so nothing from the site ends up in delivery errors.
+func PostWebhook(ctx context.Context, webhookURL string, entry *domain.JournalEntry) error {
+	return postWebhook(ctx, webhookClient, webhookURL, entry)
+}
+
+func postWebhook(ctx context.Context, client *http.Client, webhookURL string, entry *domain.JournalEntry) error {
+	payload, err := json.Marshal(Post(entry))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrPrivateAddress) {
+			return fmt.Errorf("%w: %v", ErrRejected, ErrPrivateAddress)
+		}
+		return errors.New("webhook request failed")
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusForbidden, resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusGone:
+		return fmt.Errorf("%w: webhook returned %d", ErrRejected, resp.StatusCode)
+	}
+	return fmt.Errorf("webhook returned %d", resp.StatusCode)
+}
+
+// ErrPrivateAddress is returned for webhooks on loopback, private, link-local, and other
+// addresses that aren't on the public internet
+var ErrPrivateAddress = errors.New("webhook address is not on the public internet")
+
+// webhookClient posts to site webhooks, refusing to connect to non-public addresses
+var webhookClient = newWebhookClient(privateAddr)
+
+// newWebhookClient returns a client that doesn't follow redirects and refuses to connect to
+// addresses blocked reports
+func newWebhookClient(blocked func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil || blocked(addr.Unmap()) {
+				return ErrPrivateAddress
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// CheckWebhookURL checks that a webhook URL is http or https, and that its host resolves only to
+// public internet addresses
+func CheckWebhookURL(ctx context.Context, webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("webhook URL must be an http or https URL")
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("could not resolve webhook host: %w", err)
+	}
+	for _, addr := range addrs {
+		if privateAddr(addr.Unmap()) {
+			return ErrPrivateAddress
+		}
+	}
+	return nil
+}
+
+// privateAddr reports whether an address is loopback, private, link-local (which includes cloud
+// metadata services), unspecified, or multicast
+func privateAddr(addr netip.Addr) bool {
+	return !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified()
+}
+
+// GitHub commits posts through the GitHub REST API, or a GitHub Enterprise server's
+type GitHub struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewGitHub creates a GitHub client for an API base URL, e.g. https://api.github.com
+func NewGitHub(baseURL string) *GitHub {
+	return &GitHub{baseURL: strings.TrimSuffix(baseURL, "/"), client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Verify checks that a token can push to a repository, given as owner/name
+func (g *GitHub) Verify(ctx context.Context, token, repo string) error {
+	var info struct {
+		Permissions struct {
+			Push bool `json:"push"`
+		} `json:"permissions"`
+	}
+	status, err := g.do(ctx, http.MethodGet, token, "/repos/"+repo, nil, &info)
+	if err != nil {
+		if status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusNotFound {
+			return ErrNoAccess
+		}
+		return err
+	}
+	if !info.Permissions.Push {
+		return ErrNoAccess
+	}
+	return nil
+}
+
+// Commit creates or replaces the post of an entry in a repository's directory dir, on branch or,
+// if branch is empty, the default branch
+func (g *GitHub) Commit(ctx context.Context, token, repo, branch, dir string, entry *domain.JournalEntry) error {
+	filePath := strings.TrimPrefix(path.Join(dir, FileName(entry)), "/")
+	contentsURL := "/repos/" + repo + "/contents/" + escapePath(filePath)
+
+	// Replacing a file needs its current blob SHA
+	var existing struct {
+		SHA string `json:"sha"`
+	}
+	query := ""
+	if branch != "" {
+		query = "?ref=" + url.QueryEscape(branch)
+	}
+	status, err := g.do(ctx, http.MethodGet, token, contentsURL+query, nil, &existing)
+	if err != nil && status != http.StatusNotFound {
+		return g.permanent(status, err)
+	}
+
+	body := map[string]string{
+		"message": "Publish " + entry.Title,
+		"content": base64.StdEncoding.EncodeToString([]byte(Markdown(entry))),
+	}
+	if branch != "" {
+		body["branch"] = branch
+	}
+	if existing.SHA != "" {
+		body["sha"] = existing.SHA
+	}
+	status, err = g.do(ctx, http.MethodPut, token, contentsURL, body, nil)
+	return g.permanent(status, err)
+}
+
+// permanent marks errors from statuses retrying cannot fix as ErrRejected
+func (g *GitHub) permanent(status int, err error) error {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity:
+		return fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	return err
+}
+
+// do sends an API request and decodes the JSON response into out, returning the response status
+func (g *GitHub) do(ctx context.Context, method, token, endpoint string, body, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+endpoint, reader)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("github request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, fmt.Errorf("github returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode github response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// escapePath escapes each segment of a repository file path
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
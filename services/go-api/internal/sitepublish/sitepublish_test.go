@@ -0,0 +1,161 @@
+package sitepublish
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"devjournal/internal/domain"
+)
+
+func testEntry() *domain.JournalEntry {
+	return &domain.JournalEntry{
+		Title:     `Learning "context" in Go`,
+		Content:   "Cancellation flows down.\n",
+		Mood:      "productive",
+		Tags:      []string{"go", "concurrency"},
+		CreatedAt: time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Learning Go: Part 2!", "learning-go-part-2"},
+		{"  --Café  notes--  ", "café-notes"},
+		{"!!!", "entry"},
+		{strings.Repeat("ab ", 40), strings.TrimRight(strings.Repeat("ab-", 27), "-")},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.title); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	want := "---\n" +
+		"title: \"Learning \\\"context\\\" in Go\"\n" +
+		"date: 2024-05-01T09:30:00Z\n" +
+		"tags: [\"go\", \"concurrency\"]\n" +
+		"mood: \"productive\"\n" +
+		"---\n\n" +
+		"Cancellation flows down.\n"
+	if got := Markdown(testEntry()); got != want {
+		t.Errorf("Markdown =\n%s\nwant\n%s", got, want)
+	}
+	if got := FileName(testEntry()); got != "2024-05-01-learning-context-in-go.md" {
+		t.Errorf("FileName = %q", got)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	var put map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/repos/me/site/contents/_posts/2024-05-01-learning-context-in-go.md" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("ref") != "main" {
+				t.Errorf("ref = %q, want main", r.URL.Query().Get("ref"))
+			}
+			w.Write([]byte(`{"sha": "abc123"}`))
+		case http.MethodPut:
+			json.NewDecoder(r.Body).Decode(&put)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	gh := NewGitHub(server.URL + "/")
+	if err := gh.Commit(context.Background(), "tok", "me/site", "main", "/_posts/", testEntry()); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	content, _ := base64.StdEncoding.DecodeString(put["content"])
+	if put["sha"] != "abc123" || put["branch"] != "main" || string(content) != Markdown(testEntry()) {
+		t.Errorf("PUT body = %v", put)
+	}
+
+	// A revoked token is not worth retrying
+	if err := gh.Commit(context.Background(), "revoked", "me/site", "main", "_posts", testEntry()); !errors.Is(err, ErrRejected) {
+		t.Errorf("Commit(revoked) = %v, want ErrRejected", err)
+	}
+}
+
+func TestCheckWebhookURL(t *testing.T) {
+	for _, link := range []string{
+		"http://127.0.0.1:8080/build",
+		"http://localhost/build",
+		"http://10.0.0.5/build",
+		"http://192.168.1.1/build",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/build",
+		"http://[::ffff:127.0.0.1]/build",
+		"http://0.0.0.0/build",
+	} {
+		if err := CheckWebhookURL(context.Background(), link); !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("CheckWebhookURL(%s) = %v, want ErrPrivateAddress", link, err)
+		}
+	}
+	if err := CheckWebhookURL(context.Background(), "ftp://93.184.216.34/"); err == nil {
+		t.Error("CheckWebhookURL(ftp) = nil, want an error")
+	}
+	if err := CheckWebhookURL(context.Background(), "https://93.184.216.34/hooks/build"); err != nil {
+		t.Errorf("CheckWebhookURL(public) = %v", err)
+	}
+}
+
+func TestPostWebhook(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "/build", http.StatusFound)
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("internal secret"))
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	// The test server is on loopback, which only a client without the address check can reach
+	if err := PostWebhook(context.Background(), server.URL+"/build", testEntry()); !errors.Is(err, ErrRejected) || calls != 0 {
+		t.Fatalf("PostWebhook(loopback) = %v after %d calls, want ErrRejected before connecting", err, calls)
+	}
+
+	client := newWebhookClient(func(netip.Addr) bool { return false })
+	if err := postWebhook(context.Background(), client, server.URL+"/build", testEntry()); err != nil {
+		t.Fatalf("postWebhook = %v", err)
+	}
+	calls = 0
+	if err := postWebhook(context.Background(), client, server.URL+"/redirect", testEntry()); err == nil || calls != 1 {
+		t.Errorf("postWebhook(redirect) = %v after %d calls, want an error without following", err, calls)
+	}
+	if err := postWebhook(context.Background(), client, server.URL+"/broken", testEntry()); err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("postWebhook(broken) = %v, want an error without the response body", err)
+	}
+	if err := postWebhook(context.Background(), client, server.URL+"/gone", testEntry()); !errors.Is(err, ErrRejected) {
+		t.Errorf("postWebhook(gone) = %v, want ErrRejected", err)
+	}
+}
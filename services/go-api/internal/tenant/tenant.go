@@ -0,0 +1,37 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type contextKey struct{}
+
+// WithWorkspace returns a context scoped to the given workspace
+func WithWorkspace(ctx context.Context, workspaceID uuid.UUID) context.Context {
+	return context.WithValue(ctx, contextKey{}, workspaceID)
+}
+
+// FromContext returns the active workspace, if the request carries one
+func FromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(contextKey{}).(uuid.UUID)
+	return id, ok && id != uuid.Nil
+}
+
+// WorkspaceID returns the active workspace for a user's request. Without one it falls
+// back to the user's personal workspace, which shares the user's ID.
+func WorkspaceID(ctx context.Context, userID uuid.UUID) uuid.UUID {
+	if id, ok := FromContext(ctx); ok {
+		return id
+	}
+	return userID
+}
+
+// WorkspaceIDString is WorkspaceID for string user IDs (as stored in MongoDB)
+func WorkspaceIDString(ctx context.Context, userID string) string {
+	if id, ok := FromContext(ctx); ok {
+		return id.String()
+	}
+	return userID
+}
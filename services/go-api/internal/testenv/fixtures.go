@@ -0,0 +1,51 @@
+//go:build integration
+
+package testenv
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"devjournal/internal/domain"
+	"devjournal/internal/repository/postgres"
+)
+
+var userSeq atomic.Int64
+
+// CreateUser inserts a user with their personal workspace. The password hash is not a
+// valid bcrypt hash, so fixture users cannot log in; register through the API for that.
+func (e *Env) CreateUser(t *testing.T, displayName string) *domain.User {
+	t.Helper()
+	ctx := context.Background()
+
+	email := fmt.Sprintf("fixture%d@devjournal.test", userSeq.Add(1))
+	user := domain.NewUser(email, "fixture-hash", displayName)
+	if err := postgres.NewUserRepository(e.Pool).CreateWithWorkspace(ctx, user, domain.NewPersonalWorkspace(user)); err != nil {
+		t.Fatalf("create fixture user: %v", err)
+	}
+	return user
+}
+
+// CreateEntry inserts a journal entry owned by user
+func (e *Env) CreateEntry(t *testing.T, user *domain.User, title string, tags ...string) *domain.JournalEntry {
+	t.Helper()
+
+	entry := domain.NewJournalEntry(user.ID, title, "Fixture content for "+title, "productive", tags)
+	if err := postgres.NewJournalRepository(e.Pool).Create(context.Background(), entry); err != nil {
+		t.Fatalf("create fixture entry: %v", err)
+	}
+	return entry
+}
+
+// CreateGroup inserts a public study group owned by user
+func (e *Env) CreateGroup(t *testing.T, owner *domain.User, name string) *domain.StudyGroup {
+	t.Helper()
+
+	group := domain.NewStudyGroup(name, "Fixture group", true, 10, owner.ID)
+	if err := postgres.NewStudyGroupRepository(e.Pool).Create(context.Background(), group); err != nil {
+		t.Fatalf("create fixture group: %v", err)
+	}
+	return group
+}
@@ -0,0 +1,227 @@
+//go:build integration
+
+// Package testenv runs integration tests against throwaway PostgreSQL and MongoDB instances.
+//
+// Start launches both databases in Docker via dockertest and applies the SQL migrations.
+// Set TEST_DB_URL and TEST_MONGO_URL to use existing instances instead; their data is
+// wiped between tests, so never point them at a database you care about.
+//
+//	go test -tags integration -p 1 ./...
+package testenv
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"devjournal/internal/database"
+)
+
+const (
+	postgresImage = "postgres"
+	postgresTag   = "16-alpine"
+	mongoImage    = "mongo"
+	mongoTag      = "7"
+
+	startTimeout = 2 * time.Minute
+)
+
+// Env holds connections to the test databases
+type Env struct {
+	Pool  *pgxpool.Pool
+	Mongo *mongo.Client
+
+	pool      *dockertest.Pool
+	resources []*dockertest.Resource
+}
+
+// Start connects to the test databases, starting containers unless TEST_DB_URL and
+// TEST_MONGO_URL are set, and migrates PostgreSQL to the latest schema
+func Start() (*Env, error) {
+	env := &Env{}
+	ctx := context.Background()
+
+	dbURL, mongoURL := os.Getenv("TEST_DB_URL"), os.Getenv("TEST_MONGO_URL")
+	if dbURL == "" || mongoURL == "" {
+		if err := env.startContainers(&dbURL, &mongoURL); err != nil {
+			env.Close()
+			return nil, err
+		}
+	}
+
+	err := env.retry(func() error {
+		pool, err := database.NewPostgresPool(ctx, dbURL)
+		if err != nil {
+			return err
+		}
+		env.Pool = pool
+		return nil
+	})
+	if err != nil {
+		env.Close()
+		return nil, fmt.Errorf("postgres did not become ready: %w", err)
+	}
+
+	err = env.retry(func() error {
+		client, err := database.NewMongoClient(ctx, mongoURL)
+		if err != nil {
+			return err
+		}
+		env.Mongo = client
+		return nil
+	})
+	if err != nil {
+		env.Close()
+		return nil, fmt.Errorf("mongodb did not become ready: %w", err)
+	}
+
+	if err := env.migrate(ctx); err != nil {
+		env.Close()
+		return nil, err
+	}
+	return env, nil
+}
+
+// Main is a TestMain body: it starts the environment, runs the tests, and tears it down
+func Main(m *testing.M, env **Env) {
+	started, err := Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "testenv: %v\n", err)
+		os.Exit(1)
+	}
+	*env = started
+	code := m.Run()
+	started.Close()
+	os.Exit(code)
+}
+
+// Close disconnects from the databases and removes any containers Start created
+func (e *Env) Close() {
+	if e.Pool != nil {
+		e.Pool.Close()
+	}
+	if e.Mongo != nil {
+		e.Mongo.Disconnect(context.Background())
+	}
+	for _, resource := range e.resources {
+		e.pool.Purge(resource)
+	}
+}
+
+// Reset empties every PostgreSQL table and returns the name of a fresh MongoDB database
+// that is dropped when the test ends, so each test starts from a clean slate
+func (e *Env) Reset(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+
+	// schema_version describes the schema rather than holding data, so it is kept
+	rows, err := e.Pool.Query(ctx, `SELECT tablename FROM pg_tables WHERE schemaname = 'public' AND tablename <> 'schema_version'`)
+	if err != nil {
+		t.Fatalf("list tables: %v", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("scan table name: %v", err)
+		}
+		tables = append(tables, `"`+name+`"`)
+	}
+	rows.Close()
+	if len(tables) > 0 {
+		if _, err := e.Pool.Exec(ctx, "TRUNCATE "+strings.Join(tables, ", ")+" CASCADE"); err != nil {
+			t.Fatalf("truncate tables: %v", err)
+		}
+	}
+
+	suffix := make([]byte, 6)
+	rand.Read(suffix)
+	mongoDB := "test_" + hex.EncodeToString(suffix)
+	t.Cleanup(func() {
+		e.Mongo.Database(mongoDB).Drop(context.Background())
+	})
+	return mongoDB
+}
+
+func (e *Env) startContainers(dbURL, mongoURL *string) error {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return fmt.Errorf("connect to docker: %w", err)
+	}
+	pool.MaxWait = startTimeout
+	e.pool = pool
+
+	hostConfig := func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	}
+
+	pg, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: postgresImage,
+		Tag:        postgresTag,
+		Env:        []string{"POSTGRES_USER=devjournal", "POSTGRES_PASSWORD=devjournal", "POSTGRES_DB=devjournal"},
+	}, hostConfig)
+	if err != nil {
+		return fmt.Errorf("start postgres: %w", err)
+	}
+	e.resources = append(e.resources, pg)
+	pg.Expire(uint(startTimeout.Seconds()) * 5)
+	*dbURL = fmt.Sprintf("postgres://devjournal:devjournal@%s/devjournal?sslmode=disable", pg.GetHostPort("5432/tcp"))
+
+	mg, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: mongoImage,
+		Tag:        mongoTag,
+	}, hostConfig)
+	if err != nil {
+		return fmt.Errorf("start mongodb: %w", err)
+	}
+	e.resources = append(e.resources, mg)
+	mg.Expire(uint(startTimeout.Seconds()) * 5)
+	*mongoURL = fmt.Sprintf("mongodb://%s", mg.GetHostPort("27017/tcp"))
+
+	return nil
+}
+
+// retry calls fn until it succeeds, waiting for containers that are still starting
+func (e *Env) retry(fn func() error) error {
+	if e.pool != nil {
+		return e.pool.Retry(fn)
+	}
+	return fn()
+}
+
+// migrate applies every migration in internal/database/migrations in file name order
+func (e *Env) migrate(ctx context.Context) error {
+	_, file, _, _ := runtime.Caller(0)
+	dir := filepath.Join(filepath.Dir(file), "..", "database", "migrations")
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return fmt.Errorf("list migrations: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, path := range files {
+		sql, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read migration %s: %w", filepath.Base(path), err)
+		}
+		if _, err := e.Pool.Exec(ctx, string(sql)); err != nil {
+			return fmt.Errorf("apply migration %s: %w", filepath.Base(path), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+// Package vault carries a request's vault session: proof that the user recently re-entered their
+// password, which reading or editing a vault entry requires
+package vault
+
+import (
+	"context"
+	"time"
+)
+
+// SessionTTL is how long a vault session lasts after the user verifies their password
+const SessionTTL = 15 * time.Minute
+
+type contextKey struct{}
+
+// WithSession returns a context with a vault session that expires at expiresAt
+func WithSession(ctx context.Context, expiresAt time.Time) context.Context {
+	return context.WithValue(ctx, contextKey{}, expiresAt)
+}
+
+// Unlocked reports whether the request carries an unexpired vault session
+func Unlocked(ctx context.Context) bool {
+	expiresAt, ok := ctx.Value(contextKey{}).(time.Time)
+	return ok && time.Now().Before(expiresAt)
+}
@@ -0,0 +1,161 @@
+// DevJournal load-test scenarios for k6 (https://k6.io).
+//
+// Seed the databases first so every virtual user has an account and data to read:
+//   go run ./cmd/seed -users 200 -out loadtest/seed-users.json
+//   k6 run -e API_URL=http://localhost:8080 loadtest/k6/scenario.js
+//
+// Environment:
+//   API_URL     - base URL of the API server (default: http://localhost:8080)
+//   USERS_FILE  - credentials written by cmd/seed (default: ../seed-users.json)
+//   BROWSE_VUS  - virtual users reading entries, snippets, and progress (default: 50)
+//   WRITE_VUS   - virtual users creating entries and snippets (default: 10)
+//   CHAT_VUS    - virtual users chatting in study groups (default: 10)
+//   DURATION    - how long each scenario runs (default: 2m)
+
+import http from 'k6/http';
+import ws from 'k6/ws';
+import { check, sleep } from 'k6';
+import { SharedArray } from 'k6/data';
+
+const API_URL = (__ENV.API_URL || 'http://localhost:8080').replace(/\/$/, '');
+const DURATION = __ENV.DURATION || '2m';
+
+const users = new SharedArray('users', () => JSON.parse(open(__ENV.USERS_FILE || '../seed-users.json')));
+
+export const options = {
+  scenarios: {
+    browse: {
+      executor: 'constant-vus',
+      exec: 'browse',
+      vus: Number(__ENV.BROWSE_VUS || 50),
+      duration: DURATION,
+    },
+    write: {
+      executor: 'constant-vus',
+      exec: 'write',
+      vus: Number(__ENV.WRITE_VUS || 10),
+      duration: DURATION,
+    },
+    chat: {
+      executor: 'constant-vus',
+      exec: 'chat',
+      vus: Number(__ENV.CHAT_VUS || 10),
+      duration: DURATION,
+    },
+  },
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{scenario:browse}': ['p(95)<300'],
+    'http_req_duration{scenario:write}': ['p(95)<500'],
+  },
+};
+
+// Each VU logs in once as its own seeded user and reuses the token
+let session = null;
+
+function login() {
+  if (session) {
+    return session;
+  }
+  const user = users[(__VU - 1) % users.length];
+  const res = http.post(`${API_URL}/api/v1/auth/login`, JSON.stringify({ email: user.email, password: user.password }), {
+    headers: { 'Content-Type': 'application/json' },
+    tags: { name: 'login' },
+  });
+  check(res, { 'logged in': (r) => r.status === 200 });
+  session = { token: res.json('token'), user };
+  return session;
+}
+
+function params(token, name) {
+  return {
+    headers: { Authorization: `Bearer ${token}`, 'Content-Type': 'application/json' },
+    tags: { name },
+  };
+}
+
+export function browse() {
+  const { token } = login();
+
+  const entries = http.get(`${API_URL}/api/v1/entries?page=1&pageSize=20`, params(token, 'list entries'));
+  check(entries, { 'entries listed': (r) => r.status === 200 });
+
+  const first = entries.status === 200 ? (entries.json('data') || [])[0] : null;
+  if (first) {
+    const entry = http.get(`${API_URL}/api/v1/entries/${first.id}`, params(token, 'get entry'));
+    check(entry, { 'entry fetched': (r) => r.status === 200 });
+  }
+
+  check(http.get(`${API_URL}/api/v1/snippets?page=1&pageSize=20`, params(token, 'list snippets')), {
+    'snippets listed': (r) => r.status === 200,
+  });
+  check(http.get(`${API_URL}/api/v1/public/snippets/trending`, params(token, 'trending snippets')), {
+    'trending listed': (r) => r.status === 200,
+  });
+  check(http.get(`${API_URL}/api/v1/progress/summary`, params(token, 'progress summary')), {
+    'progress fetched': (r) => r.status === 200,
+  });
+  check(http.get(`${API_URL}/api/v1/groups`, params(token, 'list groups')), {
+    'groups listed': (r) => r.status === 200,
+  });
+
+  sleep(1);
+}
+
+export function write() {
+  const { token } = login();
+
+  const entry = http.post(
+    `${API_URL}/api/v1/entries`,
+    JSON.stringify({
+      title: `Load test entry ${__VU}-${__ITER}`,
+      content: 'Measured how the API behaves under load today. Latency stayed flat.',
+      mood: 'productive',
+      tags: ['loadtest'],
+    }),
+    params(token, 'create entry'),
+  );
+  check(entry, { 'entry created': (r) => r.status === 201 });
+
+  const snippet = http.post(
+    `${API_URL}/api/v1/snippets`,
+    JSON.stringify({
+      title: `Load test snippet ${__VU}-${__ITER}`,
+      code: 'for i := range 10 {\n\tfmt.Println(i)\n}',
+      language: 'go',
+      tags: ['loadtest'],
+    }),
+    params(token, 'create snippet'),
+  );
+  check(snippet, { 'snippet created': (r) => r.status === 201 || r.status === 402 });
+
+  sleep(2);
+}
+
+export function chat() {
+  const { token } = login();
+
+  const groups = http.get(`${API_URL}/api/v1/groups`, params(token, 'list groups'));
+  const group = groups.status === 200 ? (groups.json() || [])[0] : null;
+  if (!group) {
+    sleep(5);
+    return;
+  }
+
+  const ticket = http.post(`${API_URL}/api/v1/ws/ticket`, JSON.stringify({ room: group.id }), params(token, 'chat ticket'));
+  if (!check(ticket, { 'chat ticket issued': (r) => r.status === 201 })) {
+    sleep(5);
+    return;
+  }
+
+  const url = `${API_URL.replace(/^http/, 'ws')}/ws/chat/${group.id}?ticket=${encodeURIComponent(ticket.json('ticket'))}`;
+  const res = ws.connect(url, null, (socket) => {
+    socket.on('open', () => {
+      socket.setInterval(() => {
+        socket.send(JSON.stringify({ type: 'message', content: `hello from VU ${__VU}` }));
+      }, 3000);
+      socket.setTimeout(() => socket.close(), 30000);
+    });
+  });
+  check(res, { 'chat connected': (r) => r && r.status === 101 });
+}
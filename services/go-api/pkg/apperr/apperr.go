@@ -0,0 +1,79 @@
+// Package apperr classifies service errors so transports can map them to status codes
+package apperr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// kind is a sentinel error class with a machine-readable code
+type kind struct {
+	code string
+	msg  string
+}
+
+func (k *kind) Error() string { return k.msg }
+
+// Error kinds. Service errors wrap exactly one of these; anything else is internal.
+var (
+	ErrValidation      error = &kind{"VALIDATION_FAILED", "validation failed"}
+	ErrUnauthorized    error = &kind{"UNAUTHORIZED", "unauthorized"}
+	ErrPaymentRequired error = &kind{"PAYMENT_REQUIRED", "plan upgrade required"}
+	ErrForbidden       error = &kind{"FORBIDDEN", "forbidden"}
+	ErrNotFound        error = &kind{"NOT_FOUND", "not found"}
+	ErrConflict        error = &kind{"CONFLICT", "conflict"}
+	ErrPrecondition    error = &kind{"FAILED_PRECONDITION", "failed precondition"}
+	ErrUnavailable     error = &kind{"UNAVAILABLE", "unavailable"}
+)
+
+// CodeInternal is the code of errors that wrap no kind
+const CodeInternal = "INTERNAL"
+
+// Error is a service error with a kind and a message that is safe to show clients
+type Error struct {
+	kind    error
+	message string
+	format  string
+	args    []any
+}
+
+// New creates an error of the given kind, e.g. apperr.New(apperr.ErrNotFound, "snippet not found")
+func New(kind error, message string) *Error {
+	return &Error{kind: kind, message: message, format: message}
+}
+
+// Newf creates an error whose message is formatted like fmt.Sprintf, keeping the format so the
+// message can be translated, e.g. apperr.Newf(apperr.ErrValidation, "at most %d files", 20)
+func Newf(kind error, format string, args ...any) *Error {
+	return &Error{kind: kind, message: fmt.Sprintf(format, args...), format: format, args: args}
+}
+
+func (e *Error) Error() string { return e.message }
+
+// Format returns the message's format and arguments, for translating it
+func (e *Error) Format() (string, []any) { return e.format, e.args }
+
+func (e *Error) Unwrap() error { return e.kind }
+
+// Code returns the machine-readable code of err's kind, or CodeInternal
+func Code(err error) string {
+	var k *kind
+	if errors.As(err, &k) {
+		return k.code
+	}
+	return CodeInternal
+}
+
+// Detailer is implemented by errors that carry structured details for clients
+type Detailer interface {
+	Details() map[string]interface{}
+}
+
+// Details returns the structured details carried by err, if any
+func Details(err error) map[string]interface{} {
+	var d Detailer
+	if errors.As(err, &d) {
+		return d.Details()
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Register creates an account and authenticates the client as the new user
+func (c *Client) Register(ctx context.Context, email, password, displayName string) (*AuthResponse, error) {
+	return c.RegisterWithInvite(ctx, email, password, displayName, "")
+}
+
+// RegisterWithInvite is Register with an invite code from an admin, for servers where
+// registration is invite-only
+func (c *Client) RegisterWithInvite(ctx context.Context, email, password, displayName, inviteCode string) (*AuthResponse, error) {
+	body := map[string]string{
+		"email":       email,
+		"password":    password,
+		"displayName": displayName,
+	}
+	if inviteCode != "" {
+		body["inviteCode"] = inviteCode
+	}
+	var auth AuthResponse
+	if err := c.do(ctx, http.MethodPost, "/auth/register", nil, body, &auth); err != nil {
+		return nil, err
+	}
+	c.SetToken(auth.Token)
+	return &auth, nil
+}
+
+// Login authenticates the client with an email and password
+func (c *Client) Login(ctx context.Context, email, password string) (*AuthResponse, error) {
+	var auth AuthResponse
+	err := c.do(ctx, http.MethodPost, "/auth/login", nil, map[string]string{
+		"email":    email,
+		"password": password,
+	}, &auth)
+	if err != nil {
+		return nil, err
+	}
+	c.SetToken(auth.Token)
+	return &auth, nil
+}
+
+// Refresh authenticates the client with a new token in exchange for a refresh token from an
+// earlier Register, Login, or Refresh. Each refresh token works once; use the one returned next.
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (*AuthResponse, error) {
+	var auth AuthResponse
+	err := c.do(ctx, http.MethodPost, "/auth/refresh", nil, map[string]string{
+		"refreshToken": refreshToken,
+	}, &auth)
+	if err != nil {
+		return nil, err
+	}
+	c.SetToken(auth.Token)
+	return &auth, nil
+}
@@ -0,0 +1,283 @@
+// Package client is a Go SDK for the DevJournal API.
+//
+//	c := client.New("http://localhost:8080")
+//	if _, err := c.Login(ctx, "ada@example.com", "password"); err != nil { ... }
+//	for entry, err := range c.Entries(ctx, client.EntryListOptions{Tags: []string{"go"}}) { ... }
+//
+// Requests go to the versioned REST API (/api/v1). Idempotent requests are retried on
+// network errors and 502/503/504 responses, and every request is retried on 429,
+// honoring Retry-After. JournalRPC and SnippetRPC return Connect clients for the gRPC API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"devjournal/pkg/apperr"
+)
+
+const (
+	defaultTimeout    = 30 * time.Second
+	defaultRetries    = 3
+	defaultRetryDelay = 250 * time.Millisecond
+	maxRetryDelay     = 10 * time.Second
+)
+
+// Client calls the DevJournal API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	connectURL string
+	httpClient *http.Client
+	userAgent  string
+
+	maxRetries int
+	retryDelay time.Duration
+
+	mu    sync.RWMutex
+	token string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for all requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithToken authenticates requests with an existing JWT
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithRetries sets how many times a failed request is retried and the initial backoff.
+// Zero retries disables retrying.
+func WithRetries(maxRetries int, initialDelay time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryDelay = initialDelay
+	}
+}
+
+// WithConnectURL sets the base URL of the Connect RPC server, which listens on its own port
+func WithConnectURL(connectURL string) Option {
+	return func(c *Client) { c.connectURL = strings.TrimRight(connectURL, "/") }
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// New creates a client for the API served at baseURL, e.g. http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		userAgent:  "devjournal-go-client",
+		maxRetries: defaultRetries,
+		retryDelay: defaultRetryDelay,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.connectURL == "" {
+		c.connectURL = c.baseURL
+	}
+	return c
+}
+
+// Token returns the JWT the client authenticates with, if any
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+// SetToken replaces the JWT the client authenticates with
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	c.token = token
+	c.mu.Unlock()
+}
+
+// APIError is an error response from the API
+type APIError struct {
+	StatusCode int
+	Code       string                 // machine-readable code, e.g. NOT_FOUND
+	Message    string                 // safe to show users
+	Details    map[string]interface{} // structured details, e.g. secret scan findings
+	RetryAfter time.Duration          // set on 429 and 503 responses that include Retry-After
+	RequestID  string                 // X-Request-ID of the failed request, to quote when reporting it
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("devjournal: %s (%d %s)", e.Message, e.StatusCode, e.Code)
+}
+
+// Unwrap returns the apperr kind for the response status, so callers can use
+// errors.Is(err, apperr.ErrNotFound) as they would against the service layer
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return apperr.ErrValidation
+	case http.StatusUnauthorized:
+		return apperr.ErrUnauthorized
+	case http.StatusPaymentRequired:
+		return apperr.ErrPaymentRequired
+	case http.StatusForbidden:
+		return apperr.ErrForbidden
+	case http.StatusNotFound:
+		return apperr.ErrNotFound
+	case http.StatusConflict:
+		return apperr.ErrConflict
+	case http.StatusUnprocessableEntity:
+		return apperr.ErrPrecondition
+	case http.StatusServiceUnavailable:
+		return apperr.ErrUnavailable
+	default:
+		return nil
+	}
+}
+
+// get sends a GET request and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+// do sends a request to the REST API, retrying transient failures, and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("devjournal: encode request: %w", err)
+		}
+	}
+
+	target := c.baseURL + "/api/v1" + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, target, payload)
+		if err == nil && resp.StatusCode < 300 {
+			defer resp.Body.Close()
+			if out == nil {
+				return nil
+			}
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return fmt.Errorf("devjournal: decode %s %s response: %w", method, path, err)
+			}
+			return nil
+		}
+
+		if err == nil {
+			err = decodeError(resp)
+		}
+		if attempt >= c.maxRetries || !retryable(method, err) || ctx.Err() != nil {
+			return err
+		}
+		if waitErr := c.wait(ctx, attempt, err); waitErr != nil {
+			return err
+		}
+	}
+}
+
+// send performs a single HTTP request
+func (c *Client) send(ctx context.Context, method, target string, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("devjournal: build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token := c.Token(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.httpClient.Do(req)
+}
+
+// decodeError reads an error envelope, falling back to the status text for non-JSON bodies
+func decodeError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+
+	apiErr := &APIError{StatusCode: resp.StatusCode, RequestID: resp.Header.Get("X-Request-ID")}
+	var envelope struct {
+		Code    string                 `json:"code"`
+		Message string                 `json:"message"`
+		Details map[string]interface{} `json:"details"`
+	}
+	if raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20)); err == nil && json.Unmarshal(raw, &envelope) == nil {
+		apiErr.Code, apiErr.Message, apiErr.Details = envelope.Code, envelope.Message, envelope.Details
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
+
+// retryable reports whether a failed request may be sent again. Rate limited requests were
+// never processed, so they are always safe to retry; others only if the method is idempotent.
+func retryable(method string, err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return method != http.MethodPost && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return method != http.MethodPost
+	default:
+		return false
+	}
+}
+
+// wait sleeps before the next attempt: Retry-After if the server sent one, otherwise
+// exponential backoff with full jitter
+func (c *Client) wait(ctx context.Context, attempt int, err error) error {
+	delay := c.retryDelay
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > 0 {
+		delay = rand.N(min(delay, maxRetryDelay)) + 1
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		delay = min(apiErr.RetryAfter, maxRetryDelay)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"devjournal/pkg/apperr"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return New(server.URL, WithRetries(3, time.Millisecond))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestLoginStoresToken(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/auth/login":
+			writeJSON(w, http.StatusOK, AuthResponse{Token: "jwt", User: User{Email: "ada@example.com"}})
+		case "/api/v1/progress/streak":
+			if r.Header.Get("Authorization") != "Bearer jwt" {
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"code": "UNAUTHORIZED", "message": "missing token"})
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]int{"currentStreak": 4})
+		}
+	})
+
+	if _, err := c.Login(context.Background(), "ada@example.com", "password"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	streak, err := c.CurrentStreak(context.Background())
+	if err != nil || streak != 4 {
+		t.Fatalf("CurrentStreak = %d, %v; want 4", streak, err)
+	}
+}
+
+func TestRefreshStoresToken(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/v1/auth/refresh" || body["refreshToken"] != "refresh-1" {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"code": "UNAUTHORIZED", "message": "invalid or expired token"})
+			return
+		}
+		writeJSON(w, http.StatusOK, AuthResponse{Token: "jwt-2", RefreshToken: "refresh-2"})
+	})
+
+	auth, err := c.Refresh(context.Background(), "refresh-1")
+	if err != nil || auth.RefreshToken != "refresh-2" || c.Token() != "jwt-2" {
+		t.Fatalf("Refresh = %+v, %v (token %q)", auth, err, c.Token())
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "req-1")
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"code":    "FAILED_PRECONDITION",
+			"message": "snippet appears to contain 1 secret(s)",
+			"details": map[string]interface{}{"findings": []interface{}{}},
+		})
+	})
+
+	_, err := c.CreateSnippet(context.Background(), &CreateSnippetRequest{Title: "Leaky", IsPublic: true})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want *APIError", err)
+	}
+	if apiErr.Code != "FAILED_PRECONDITION" || apiErr.Details["findings"] == nil || apiErr.RequestID != "req-1" {
+		t.Fatalf("APIError = %+v", apiErr)
+	}
+	if !errors.Is(err, apperr.ErrPrecondition) {
+		t.Fatal("errors.Is(err, apperr.ErrPrecondition) = false")
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"code": "UNAVAILABLE", "message": "try again"})
+			return
+		}
+		writeJSON(w, http.StatusOK, ProgressSummary{CurrentStreak: 2})
+	})
+
+	summary, err := c.ProgressSummary(context.Background())
+	if err != nil || summary.CurrentStreak != 2 {
+		t.Fatalf("ProgressSummary = %+v, %v", summary, err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("server saw %d requests, want 3", calls.Load())
+	}
+
+	// A POST that failed with 503 may have been applied, so it is not retried
+	calls.Store(0)
+	if _, err := c.CreateEntry(context.Background(), &CreateJournalEntryRequest{Title: "Once"}); !errors.Is(err, apperr.ErrUnavailable) {
+		t.Fatalf("CreateEntry error = %v, want ErrUnavailable", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("POST was sent %d times, want 1", calls.Load())
+	}
+}
+
+func TestRetriesRateLimitedPost(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{"code": "RATE_LIMITED", "message": "slow down"})
+			return
+		}
+		writeJSON(w, http.StatusCreated, JournalEntry{Title: "Eventually"})
+	})
+
+	entry, err := c.CreateEntry(context.Background(), &CreateJournalEntryRequest{Title: "Eventually"})
+	if err != nil || entry.Title != "Eventually" {
+		t.Fatalf("CreateEntry = %+v, %v", entry, err)
+	}
+}
+
+func TestEntriesIterator(t *testing.T) {
+	const total, pageSize = 5, 2
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if r.URL.Query().Get("tags") != "go,sql" {
+			t.Errorf("tags = %q, want go,sql", r.URL.Query().Get("tags"))
+		}
+		var data []JournalEntry
+		for i := (page - 1) * pageSize; i < min(page*pageSize, total); i++ {
+			data = append(data, JournalEntry{ID: uuid.New(), Title: strconv.Itoa(i)})
+		}
+		writeJSON(w, http.StatusOK, Page[JournalEntry]{Data: data, Total: total, Page: page, PageSize: pageSize})
+	})
+
+	var titles []string
+	for entry, err := range c.Entries(context.Background(), EntryListOptions{Tags: []string{"go", "sql"}}) {
+		if err != nil {
+			t.Fatalf("Entries: %v", err)
+		}
+		titles = append(titles, entry.Title)
+	}
+	if len(titles) != total || titles[0] != "0" || titles[total-1] != "4" {
+		t.Fatalf("Entries yielded %v, want 0..4", titles)
+	}
+
+	// Breaking out of the loop stops fetching pages
+	seen := 0
+	for range c.Entries(context.Background(), EntryListOptions{Tags: []string{"go", "sql"}}) {
+		seen++
+		break
+	}
+	if seen != 1 {
+		t.Fatalf("iterated %d entries after break, want 1", seen)
+	}
+}
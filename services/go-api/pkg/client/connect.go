@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+
+	"devjournal/proto/devjournal/v1/devjournalv1connect"
+)
+
+// JournalRPC returns a Connect client for JournalService that authenticates as this client
+func (c *Client) JournalRPC(opts ...connect.ClientOption) devjournalv1connect.JournalServiceClient {
+	return devjournalv1connect.NewJournalServiceClient(c.httpClient, c.connectURL, c.connectOptions(opts)...)
+}
+
+// SnippetRPC returns a Connect client for SnippetService that authenticates as this client
+func (c *Client) SnippetRPC(opts ...connect.ClientOption) devjournalv1connect.SnippetServiceClient {
+	return devjournalv1connect.NewSnippetServiceClient(c.httpClient, c.connectURL, c.connectOptions(opts)...)
+}
+
+// connectOptions prepends an interceptor that sends the client's current token
+func (c *Client) connectOptions(opts []connect.ClientOption) []connect.ClientOption {
+	auth := connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if token := c.Token(); token != "" {
+				req.Header().Set("Authorization", "Bearer "+token)
+			}
+			req.Header().Set("User-Agent", c.userAgent)
+			return next(ctx, req)
+		}
+	})
+	return append([]connect.ClientOption{connect.WithInterceptors(auth)}, opts...)
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// EntryListOptions filters journal entries. Search, tags, and mood are mutually exclusive,
+// in that order of precedence; use "none" for Tags or Mood to list entries without any.
+type EntryListOptions struct {
+	PageSize int // 0 uses the account's preferred page size
+	Search   string
+	Tags     []string
+	Match    string // any (default) or all, when filtering by several tags
+	Mood     string
+}
+
+func (o EntryListOptions) query(page int) url.Values {
+	q := url.Values{}
+	q.Set("page", strconv.Itoa(page))
+	if o.PageSize > 0 {
+		q.Set("pageSize", strconv.Itoa(o.PageSize))
+	}
+	if o.Search != "" {
+		q.Set("search", o.Search)
+	}
+	if len(o.Tags) > 0 {
+		q.Set("tags", strings.Join(o.Tags, ","))
+	}
+	if o.Match != "" {
+		q.Set("match", o.Match)
+	}
+	if o.Mood != "" {
+		q.Set("mood", o.Mood)
+	}
+	return q
+}
+
+// ListEntries returns one page of journal entries, starting at page 1
+func (c *Client) ListEntries(ctx context.Context, page int, opts EntryListOptions) (*Page[JournalEntry], error) {
+	var result Page[JournalEntry]
+	if err := c.get(ctx, "/entries", opts.query(page), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Entries iterates over every journal entry matching opts, fetching pages as needed
+func (c *Client) Entries(ctx context.Context, opts EntryListOptions) iter.Seq2[JournalEntry, error] {
+	return all(ctx, func(ctx context.Context, page int) (*Page[JournalEntry], error) {
+		return c.ListEntries(ctx, page, opts)
+	})
+}
+
+// GetEntry returns a journal entry by ID
+func (c *Client) GetEntry(ctx context.Context, id string) (*JournalEntry, error) {
+	var entry JournalEntry
+	if err := c.get(ctx, "/entries/"+url.PathEscape(id), nil, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CreateEntry creates a journal entry
+func (c *Client) CreateEntry(ctx context.Context, req *CreateJournalEntryRequest) (*JournalEntry, error) {
+	var entry JournalEntry
+	if err := c.do(ctx, http.MethodPost, "/entries", nil, req, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// UpdateEntry replaces a journal entry's fields
+func (c *Client) UpdateEntry(ctx context.Context, id string, req *UpdateJournalEntryRequest) (*JournalEntry, error) {
+	var entry JournalEntry
+	if err := c.do(ctx, http.MethodPut, "/entries/"+url.PathEscape(id), nil, req, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// DeleteEntry deletes a journal entry
+func (c *Client) DeleteEntry(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/entries/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ExportEntries returns the whole journal, including encrypted entries as stored
+func (c *Client) ExportEntries(ctx context.Context) (*JournalExport, error) {
+	var export JournalExport
+	if err := c.get(ctx, "/entries/export", nil, &export); err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
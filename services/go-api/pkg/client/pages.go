@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+	"iter"
+)
+
+// all walks every page fetched by list, starting at page 1, and yields each item.
+// Iteration stops at the first short page, since search and mood filters don't report exact totals.
+func all[T any](ctx context.Context, list func(ctx context.Context, page int) (*Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page := 1; ; page++ {
+			result, err := list(ctx, page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range result.Data {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(result.Data) == 0 || len(result.Data) < result.PageSize {
+				return
+			}
+		}
+	}
+}
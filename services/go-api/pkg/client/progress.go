@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ProgressSummary returns streaks and totals across the user's learning history
+func (c *Client) ProgressSummary(ctx context.Context) (*ProgressSummary, error) {
+	var summary ProgressSummary
+	if err := c.get(ctx, "/progress/summary", nil, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// TodayProgress returns today's learning progress
+//
+// Deprecated: use QueryProgress, which fetches any range in one call.
+func (c *Client) TodayProgress(ctx context.Context) (*LearningProgress, error) {
+	var progress LearningProgress
+	if err := c.get(ctx, "/progress/today", nil, &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// WeeklyProgress returns daily progress for the last seven days
+//
+// Deprecated: use QueryProgress, which fetches any range in one call.
+func (c *Client) WeeklyProgress(ctx context.Context) ([]LearningProgress, error) {
+	return c.progressList(ctx, "/progress/weekly")
+}
+
+// MonthlyProgress returns daily progress for the last thirty days
+//
+// Deprecated: use QueryProgress, which fetches any range in one call.
+func (c *Client) MonthlyProgress(ctx context.Context) ([]LearningProgress, error) {
+	return c.progressList(ctx, "/progress/monthly")
+}
+
+// QueryProgress returns a series of progress buckets for each query, in one call
+func (c *Client) QueryProgress(ctx context.Context, queries ...ProgressSeriesQuery) ([]ProgressSeries, error) {
+	var result struct {
+		Series []ProgressSeries `json:"series"`
+	}
+	err := c.do(ctx, http.MethodPost, "/progress/query", nil, map[string]interface{}{"series": queries}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Series, nil
+}
+
+func (c *Client) progressList(ctx context.Context, path string) ([]LearningProgress, error) {
+	var result struct {
+		Progress []LearningProgress `json:"progress"`
+	}
+	if err := c.get(ctx, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Progress, nil
+}
+
+// CurrentStreak returns the number of consecutive days with learning activity
+func (c *Client) CurrentStreak(ctx context.Context) (int, error) {
+	var result struct {
+		CurrentStreak int `json:"currentStreak"`
+	}
+	if err := c.get(ctx, "/progress/streak", nil, &result); err != nil {
+		return 0, err
+	}
+	return result.CurrentStreak, nil
+}
+
+// Achievements returns the achievements the user earned, such as streak milestones, most
+// recent first
+func (c *Client) Achievements(ctx context.Context) ([]Achievement, error) {
+	var result struct {
+		Data []Achievement `json:"data"`
+	}
+	if err := c.get(ctx, "/progress/achievements", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// WritingStats returns journal word counts for the last weeks weeks (0 uses the server default)
+func (c *Client) WritingStats(ctx context.Context, weeks int) (*WritingStats, error) {
+	q := url.Values{}
+	if weeks > 0 {
+		q.Set("weeks", strconv.Itoa(weeks))
+	}
+	var stats WritingStats
+	if err := c.get(ctx, "/progress/writing", q, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SnippetListOptions filters snippets. All filters combine.
+type SnippetListOptions struct {
+	PageSize   int // 0 uses the account's preferred page size
+	Search     string
+	Tags       []string
+	Language   string
+	Visibility string // public or private
+	From, To   time.Time
+}
+
+func (o SnippetListOptions) query(page int) url.Values {
+	q := url.Values{}
+	q.Set("page", strconv.Itoa(page))
+	if o.PageSize > 0 {
+		q.Set("pageSize", strconv.Itoa(o.PageSize))
+	}
+	if o.Search != "" {
+		q.Set("search", o.Search)
+	}
+	if len(o.Tags) > 0 {
+		q.Set("tags", strings.Join(o.Tags, ","))
+	}
+	if o.Language != "" {
+		q.Set("language", o.Language)
+	}
+	if o.Visibility != "" {
+		q.Set("visibility", o.Visibility)
+	}
+	if !o.From.IsZero() {
+		q.Set("from", o.From.Format(time.RFC3339))
+	}
+	if !o.To.IsZero() {
+		q.Set("to", o.To.Format(time.RFC3339))
+	}
+	return q
+}
+
+// ListSnippets returns one page of snippets, starting at page 1
+func (c *Client) ListSnippets(ctx context.Context, page int, opts SnippetListOptions) (*Page[Snippet], error) {
+	var result Page[Snippet]
+	if err := c.get(ctx, "/snippets", opts.query(page), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Snippets iterates over every snippet matching opts, fetching pages as needed
+func (c *Client) Snippets(ctx context.Context, opts SnippetListOptions) iter.Seq2[Snippet, error] {
+	return all(ctx, func(ctx context.Context, page int) (*Page[Snippet], error) {
+		return c.ListSnippets(ctx, page, opts)
+	})
+}
+
+// GetSnippet returns a snippet by ID
+func (c *Client) GetSnippet(ctx context.Context, id string) (*Snippet, error) {
+	var snippet Snippet
+	if err := c.get(ctx, "/snippets/"+url.PathEscape(id), nil, &snippet); err != nil {
+		return nil, err
+	}
+	return &snippet, nil
+}
+
+// CreateSnippet creates a snippet. Publishing code that looks like it contains secrets fails
+// with a 422 whose Details list the findings, unless AcknowledgeSecrets is set. Creating a snippet
+// nearly the same as an existing one fails with a 409 whose Details list the matches, unless
+// AllowDuplicate is set.
+func (c *Client) CreateSnippet(ctx context.Context, req *CreateSnippetRequest) (*Snippet, error) {
+	var snippet Snippet
+	if err := c.do(ctx, http.MethodPost, "/snippets", nil, req, &snippet); err != nil {
+		return nil, err
+	}
+	return &snippet, nil
+}
+
+// UpdateSnippet replaces a snippet's fields
+func (c *Client) UpdateSnippet(ctx context.Context, id string, req *UpdateSnippetRequest) (*Snippet, error) {
+	var snippet Snippet
+	if err := c.do(ctx, http.MethodPut, "/snippets/"+url.PathEscape(id), nil, req, &snippet); err != nil {
+		return nil, err
+	}
+	return &snippet, nil
+}
+
+// DeleteSnippet deletes a snippet
+func (c *Client) DeleteSnippet(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/snippets/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ScanSnippet checks a snippet for accidentally included secrets
+func (c *Client) ScanSnippet(ctx context.Context, id string) (*SecretScanResult, error) {
+	var result SecretScanResult
+	if err := c.do(ctx, http.MethodPost, "/snippets/"+url.PathEscape(id)+"/scan", nil, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SnippetCodeStats returns lines of code by language, grouped by week or month
+func (c *Client) SnippetCodeStats(ctx context.Context, groupBy string, months int) (*SnippetCodeStats, error) {
+	q := url.Values{}
+	if groupBy != "" {
+		q.Set("groupBy", groupBy)
+	}
+	if months > 0 {
+		q.Set("months", strconv.Itoa(months))
+	}
+	var stats SnippetCodeStats
+	if err := c.get(ctx, "/snippets/stats", q, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// LanguageCounts returns the lifetime number of snippets per language
+func (c *Client) LanguageCounts(ctx context.Context) (map[string]int64, error) {
+	var result struct {
+		Counts map[string]int64 `json:"counts"`
+	}
+	if err := c.get(ctx, "/snippets/languages", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Counts, nil
+}
+
+// LanguageCountsOverTime returns snippets per language bucketed by interval (day, week, month,
+// or year) over rangeSpec, e.g. 30d or 1y
+func (c *Client) LanguageCountsOverTime(ctx context.Context, interval, rangeSpec string) ([]LanguageStatsBucket, error) {
+	q := url.Values{}
+	q.Set("interval", interval)
+	q.Set("range", rangeSpec)
+	var result struct {
+		Buckets []LanguageStatsBucket `json:"buckets"`
+	}
+	if err := c.get(ctx, "/snippets/languages", q, &result); err != nil {
+		return nil, err
+	}
+	return result.Buckets, nil
+}
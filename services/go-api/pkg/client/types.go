@@ -0,0 +1,51 @@
+package client
+
+import "devjournal/internal/domain"
+
+// Resource types are the server's own, so the SDK cannot drift from the API it wraps
+type (
+	JournalEntry              = domain.JournalEntry
+	CreateJournalEntryRequest = domain.CreateJournalEntryRequest
+	UpdateJournalEntryRequest = domain.UpdateJournalEntryRequest
+	EncryptionMetadata        = domain.EncryptionMetadata
+	JournalExport             = domain.JournalExport
+
+	Snippet              = domain.Snippet
+	CreateSnippetRequest = domain.CreateSnippetRequest
+	UpdateSnippetRequest = domain.UpdateSnippetRequest
+	SecretScanResult     = domain.SecretScanResult
+	SnippetCodeStats     = domain.SnippetCodeStats
+	LanguageStatsBucket  = domain.LanguageStatsBucket
+
+	LearningProgress    = domain.LearningProgress
+	ProgressSummary     = domain.ProgressSummary
+	ProgressSeriesQuery = domain.ProgressSeriesQuery
+	ProgressSeries      = domain.ProgressSeries
+	ProgressBucket      = domain.ProgressBucket
+	Achievement         = domain.Achievement
+	WritingStats        = domain.WritingStats
+)
+
+// User is the account returned by Register and Login
+type User struct {
+	ID          string `json:"id"`
+	Email       string `json:"email"`
+	DisplayName string `json:"displayName"`
+}
+
+// AuthResponse is the result of Register, Login, and Refresh
+type AuthResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken,omitempty"` // passed to Refresh once Token expires
+	User         User   `json:"user"`
+}
+
+// Page is one page of a paginated list
+type Page[T any] struct {
+	Data        []T `json:"data"`
+	Total       int `json:"total"`
+	Page        int `json:"page"`
+	PageSize    int `json:"pageSize"`
+	TotalPages  int `json:"totalPages"`
+	MaxPageSize int `json:"maxPageSize"`
+}
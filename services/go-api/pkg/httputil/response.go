@@ -2,9 +2,24 @@ package httputil
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+	"strings"
+
+	"devjournal/internal/i18n"
+	"devjournal/internal/requestid"
+	"devjournal/pkg/apperr"
 )
 
+// ErrorBody is the envelope of every error response
+type ErrorBody struct {
+	Code      string                 `json:"code"`
+	Message   string                 `json:"message"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RequestID string                 `json:"requestId,omitempty"` // for users to quote when reporting the error
+}
+
 // JSON sends a JSON response with the given status code
 func JSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -13,14 +28,86 @@ func JSON(w http.ResponseWriter, status int, data interface{}) {
 	if data != nil {
 		if err := json.NewEncoder(w).Encode(data); err != nil {
 			// If encoding fails, log it (in production, use proper logging)
-			http.Error(w, `{"error":"failed to encode response"}`, http.StatusInternalServerError)
+			http.Error(w, `{"code":"INTERNAL","message":"failed to encode response"}`, http.StatusInternalServerError)
 		}
 	}
 }
 
-// Error sends a JSON error response
+// Error sends a JSON error response whose code is derived from the status.
+// Messages are translated into the response's Content-Language, which middleware.Locale sets,
+// and the response's X-Request-ID, set by middleware.RequestID, is included.
 func Error(w http.ResponseWriter, status int, message string) {
-	JSON(w, status, map[string]string{"error": message})
+	JSON(w, status, ErrorBody{
+		Code:      statusCode(status),
+		Message:   i18n.T(w.Header().Get("Content-Language"), message),
+		RequestID: w.Header().Get(requestid.Header),
+	})
+}
+
+// WriteError maps a service error to its status and sends it in the error envelope, translated
+// like Error. Errors without a kind are logged and reported as fallback with a 500.
+func WriteError(w http.ResponseWriter, err error, fallback string) {
+	status := StatusFor(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("ERROR: %s: %v (request %s)", fallback, err, w.Header().Get(requestid.Header))
+		Error(w, status, fallback)
+		return
+	}
+	JSON(w, status, ErrorBody{
+		Code:      apperr.Code(err),
+		Message:   translate(w.Header().Get("Content-Language"), err),
+		Details:   apperr.Details(err),
+		RequestID: w.Header().Get(requestid.Header),
+	})
+}
+
+// translate returns err's message in locale. Service errors created with apperr.Newf are
+// translated by their format, so their arguments don't defeat the lookup.
+func translate(locale string, err error) string {
+	var appErr *apperr.Error
+	if errors.As(err, &appErr) && appErr.Error() == err.Error() {
+		format, args := appErr.Format()
+		return i18n.T(locale, format, args...)
+	}
+	return i18n.T(locale, err.Error())
+}
+
+// StatusFor returns the HTTP status for an error's kind
+func StatusFor(err error) int {
+	switch {
+	case errors.Is(err, apperr.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, apperr.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, apperr.ErrPaymentRequired):
+		return http.StatusPaymentRequired
+	case errors.Is(err, apperr.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, apperr.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperr.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, apperr.ErrPrecondition):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, apperr.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// statusCode names a status for errors raised by handlers rather than services, e.g. NOT_FOUND
+func statusCode(status int) string {
+	switch status {
+	case http.StatusInternalServerError:
+		return apperr.CodeInternal
+	case http.StatusServiceUnavailable:
+		return "UNAVAILABLE"
+	case http.StatusTooManyRequests:
+		return "RATE_LIMITED"
+	default:
+		return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+	}
 }
 
 // Success sends a JSON success response
@@ -33,6 +33,8 @@ type JournalEntry struct {
 	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ContentFormat string                 `protobuf:"bytes,9,opt,name=content_format,json=contentFormat,proto3" json:"content_format,omitempty"` // plain or e2ee; e2ee content is base64 ciphertext
+	Encryption    *EncryptionMetadata    `protobuf:"bytes,10,opt,name=encryption,proto3" json:"encryption,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -123,6 +125,97 @@ func (x *JournalEntry) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *JournalEntry) GetContentFormat() string {
+	if x != nil {
+		return x.ContentFormat
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetEncryption() *EncryptionMetadata {
+	if x != nil {
+		return x.Encryption
+	}
+	return nil
+}
+
+// Client-side encryption parameters for e2ee entries. Keys never leave the client.
+type EncryptionMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm     string                 `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	KeyId         string                 `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Nonce         string                 `protobuf:"bytes,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Kdf           string                 `protobuf:"bytes,4,opt,name=kdf,proto3" json:"kdf,omitempty"`
+	Salt          string                 `protobuf:"bytes,5,opt,name=salt,proto3" json:"salt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EncryptionMetadata) Reset() {
+	*x = EncryptionMetadata{}
+	mi := &file_devjournal_v1_journal_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncryptionMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptionMetadata) ProtoMessage() {}
+
+func (x *EncryptionMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_devjournal_v1_journal_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptionMetadata.ProtoReflect.Descriptor instead.
+func (*EncryptionMetadata) Descriptor() ([]byte, []int) {
+	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EncryptionMetadata) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *EncryptionMetadata) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *EncryptionMetadata) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *EncryptionMetadata) GetKdf() string {
+	if x != nil {
+		return x.Kdf
+	}
+	return ""
+}
+
+func (x *EncryptionMetadata) GetSalt() string {
+	if x != nil {
+		return x.Salt
+	}
+	return ""
+}
+
 // CreateEntryRequest is the request to create a new entry
 type CreateEntryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -130,13 +223,15 @@ type CreateEntryRequest struct {
 	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
 	Mood          string                 `protobuf:"bytes,3,opt,name=mood,proto3" json:"mood,omitempty"`
 	Tags          []string               `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	ContentFormat string                 `protobuf:"bytes,5,opt,name=content_format,json=contentFormat,proto3" json:"content_format,omitempty"`
+	Encryption    *EncryptionMetadata    `protobuf:"bytes,6,opt,name=encryption,proto3" json:"encryption,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateEntryRequest) Reset() {
 	*x = CreateEntryRequest{}
-	mi := &file_devjournal_v1_journal_proto_msgTypes[1]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -148,7 +243,7 @@ func (x *CreateEntryRequest) String() string {
 func (*CreateEntryRequest) ProtoMessage() {}
 
 func (x *CreateEntryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_journal_proto_msgTypes[1]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -161,7 +256,7 @@ func (x *CreateEntryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateEntryRequest.ProtoReflect.Descriptor instead.
 func (*CreateEntryRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{1}
+	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CreateEntryRequest) GetTitle() string {
@@ -192,6 +287,20 @@ func (x *CreateEntryRequest) GetTags() []string {
 	return nil
 }
 
+func (x *CreateEntryRequest) GetContentFormat() string {
+	if x != nil {
+		return x.ContentFormat
+	}
+	return ""
+}
+
+func (x *CreateEntryRequest) GetEncryption() *EncryptionMetadata {
+	if x != nil {
+		return x.Encryption
+	}
+	return nil
+}
+
 // GetEntryRequest is the request to retrieve an entry
 type GetEntryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -202,7 +311,7 @@ type GetEntryRequest struct {
 
 func (x *GetEntryRequest) Reset() {
 	*x = GetEntryRequest{}
-	mi := &file_devjournal_v1_journal_proto_msgTypes[2]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -214,7 +323,7 @@ func (x *GetEntryRequest) String() string {
 func (*GetEntryRequest) ProtoMessage() {}
 
 func (x *GetEntryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_journal_proto_msgTypes[2]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -227,7 +336,7 @@ func (x *GetEntryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetEntryRequest.ProtoReflect.Descriptor instead.
 func (*GetEntryRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{2}
+	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetEntryRequest) GetId() string {
@@ -240,7 +349,7 @@ func (x *GetEntryRequest) GetId() string {
 // ListEntriesRequest is the request to list entries with pagination
 type ListEntriesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // Omit (0) to use the caller's preferred default page size
 	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
 	Mood          string                 `protobuf:"bytes,3,opt,name=mood,proto3" json:"mood,omitempty"` // Optional filter by mood
 	unknownFields protoimpl.UnknownFields
@@ -249,7 +358,7 @@ type ListEntriesRequest struct {
 
 func (x *ListEntriesRequest) Reset() {
 	*x = ListEntriesRequest{}
-	mi := &file_devjournal_v1_journal_proto_msgTypes[3]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -261,7 +370,7 @@ func (x *ListEntriesRequest) String() string {
 func (*ListEntriesRequest) ProtoMessage() {}
 
 func (x *ListEntriesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_journal_proto_msgTypes[3]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -274,7 +383,7 @@ func (x *ListEntriesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListEntriesRequest.ProtoReflect.Descriptor instead.
 func (*ListEntriesRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{3}
+	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ListEntriesRequest) GetLimit() int32 {
@@ -303,13 +412,14 @@ type ListEntriesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Entries       []*JournalEntry        `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
 	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	MaxPageSize   int32                  `protobuf:"varint,3,opt,name=max_page_size,json=maxPageSize,proto3" json:"max_page_size,omitempty"` // Server-side cap applied to limit
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListEntriesResponse) Reset() {
 	*x = ListEntriesResponse{}
-	mi := &file_devjournal_v1_journal_proto_msgTypes[4]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -321,7 +431,7 @@ func (x *ListEntriesResponse) String() string {
 func (*ListEntriesResponse) ProtoMessage() {}
 
 func (x *ListEntriesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_journal_proto_msgTypes[4]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,7 +444,7 @@ func (x *ListEntriesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListEntriesResponse.ProtoReflect.Descriptor instead.
 func (*ListEntriesResponse) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{4}
+	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ListEntriesResponse) GetEntries() []*JournalEntry {
@@ -351,6 +461,13 @@ func (x *ListEntriesResponse) GetTotalCount() int32 {
 	return 0
 }
 
+func (x *ListEntriesResponse) GetMaxPageSize() int32 {
+	if x != nil {
+		return x.MaxPageSize
+	}
+	return 0
+}
+
 // UpdateEntryRequest is the request to update an entry
 type UpdateEntryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -359,13 +476,15 @@ type UpdateEntryRequest struct {
 	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
 	Mood          string                 `protobuf:"bytes,4,opt,name=mood,proto3" json:"mood,omitempty"`
 	Tags          []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	ContentFormat string                 `protobuf:"bytes,6,opt,name=content_format,json=contentFormat,proto3" json:"content_format,omitempty"`
+	Encryption    *EncryptionMetadata    `protobuf:"bytes,7,opt,name=encryption,proto3" json:"encryption,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateEntryRequest) Reset() {
 	*x = UpdateEntryRequest{}
-	mi := &file_devjournal_v1_journal_proto_msgTypes[5]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -377,7 +496,7 @@ func (x *UpdateEntryRequest) String() string {
 func (*UpdateEntryRequest) ProtoMessage() {}
 
 func (x *UpdateEntryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_journal_proto_msgTypes[5]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -390,7 +509,7 @@ func (x *UpdateEntryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateEntryRequest.ProtoReflect.Descriptor instead.
 func (*UpdateEntryRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{5}
+	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateEntryRequest) GetId() string {
@@ -428,6 +547,20 @@ func (x *UpdateEntryRequest) GetTags() []string {
 	return nil
 }
 
+func (x *UpdateEntryRequest) GetContentFormat() string {
+	if x != nil {
+		return x.ContentFormat
+	}
+	return ""
+}
+
+func (x *UpdateEntryRequest) GetEncryption() *EncryptionMetadata {
+	if x != nil {
+		return x.Encryption
+	}
+	return nil
+}
+
 // DeleteEntryRequest is the request to delete an entry
 type DeleteEntryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -438,7 +571,7 @@ type DeleteEntryRequest struct {
 
 func (x *DeleteEntryRequest) Reset() {
 	*x = DeleteEntryRequest{}
-	mi := &file_devjournal_v1_journal_proto_msgTypes[6]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -450,7 +583,7 @@ func (x *DeleteEntryRequest) String() string {
 func (*DeleteEntryRequest) ProtoMessage() {}
 
 func (x *DeleteEntryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_journal_proto_msgTypes[6]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -463,7 +596,7 @@ func (x *DeleteEntryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteEntryRequest.ProtoReflect.Descriptor instead.
 func (*DeleteEntryRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{6}
+	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DeleteEntryRequest) GetId() string {
@@ -483,7 +616,7 @@ type DeleteEntryResponse struct {
 
 func (x *DeleteEntryResponse) Reset() {
 	*x = DeleteEntryResponse{}
-	mi := &file_devjournal_v1_journal_proto_msgTypes[7]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -495,7 +628,7 @@ func (x *DeleteEntryResponse) String() string {
 func (*DeleteEntryResponse) ProtoMessage() {}
 
 func (x *DeleteEntryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_journal_proto_msgTypes[7]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -508,7 +641,7 @@ func (x *DeleteEntryResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteEntryResponse.ProtoReflect.Descriptor instead.
 func (*DeleteEntryResponse) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{7}
+	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DeleteEntryResponse) GetSuccess() bool {
@@ -530,7 +663,7 @@ type SearchEntriesRequest struct {
 
 func (x *SearchEntriesRequest) Reset() {
 	*x = SearchEntriesRequest{}
-	mi := &file_devjournal_v1_journal_proto_msgTypes[8]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -542,7 +675,7 @@ func (x *SearchEntriesRequest) String() string {
 func (*SearchEntriesRequest) ProtoMessage() {}
 
 func (x *SearchEntriesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_journal_proto_msgTypes[8]
+	mi := &file_devjournal_v1_journal_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -555,7 +688,7 @@ func (x *SearchEntriesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchEntriesRequest.ProtoReflect.Descriptor instead.
 func (*SearchEntriesRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{8}
+	return file_devjournal_v1_journal_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *SearchEntriesRequest) GetQuery() string {
@@ -583,7 +716,7 @@ var File_devjournal_v1_journal_proto protoreflect.FileDescriptor
 
 const file_devjournal_v1_journal_proto_rawDesc = "" +
 	"\n" +
-	"\x1bdevjournal/v1/journal.proto\x12\rdevjournal.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x85\x02\n" +
+	"\x1bdevjournal/v1/journal.proto\x12\rdevjournal.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xef\x02\n" +
 	"\fJournalEntry\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
@@ -594,28 +727,48 @@ const file_devjournal_v1_journal_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"l\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12%\n" +
+	"\x0econtent_format\x18\t \x01(\tR\rcontentFormat\x12A\n" +
+	"\n" +
+	"encryption\x18\n" +
+	" \x01(\v2!.devjournal.v1.EncryptionMetadataR\n" +
+	"encryption\"\x85\x01\n" +
+	"\x12EncryptionMetadata\x12\x1c\n" +
+	"\talgorithm\x18\x01 \x01(\tR\talgorithm\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\x12\x14\n" +
+	"\x05nonce\x18\x03 \x01(\tR\x05nonce\x12\x10\n" +
+	"\x03kdf\x18\x04 \x01(\tR\x03kdf\x12\x12\n" +
+	"\x04salt\x18\x05 \x01(\tR\x04salt\"\xd6\x01\n" +
 	"\x12CreateEntryRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12\x12\n" +
 	"\x04mood\x18\x03 \x01(\tR\x04mood\x12\x12\n" +
-	"\x04tags\x18\x04 \x03(\tR\x04tags\"!\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12%\n" +
+	"\x0econtent_format\x18\x05 \x01(\tR\rcontentFormat\x12A\n" +
+	"\n" +
+	"encryption\x18\x06 \x01(\v2!.devjournal.v1.EncryptionMetadataR\n" +
+	"encryption\"!\n" +
 	"\x0fGetEntryRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"V\n" +
 	"\x12ListEntriesRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
 	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x12\n" +
-	"\x04mood\x18\x03 \x01(\tR\x04mood\"m\n" +
+	"\x04mood\x18\x03 \x01(\tR\x04mood\"\x91\x01\n" +
 	"\x13ListEntriesResponse\x125\n" +
 	"\aentries\x18\x01 \x03(\v2\x1b.devjournal.v1.JournalEntryR\aentries\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\x05R\n" +
-	"totalCount\"|\n" +
+	"totalCount\x12\"\n" +
+	"\rmax_page_size\x18\x03 \x01(\x05R\vmaxPageSize\"\xe6\x01\n" +
 	"\x12UpdateEntryRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x03 \x01(\tR\acontent\x12\x12\n" +
 	"\x04mood\x18\x04 \x01(\tR\x04mood\x12\x12\n" +
-	"\x04tags\x18\x05 \x03(\tR\x04tags\"$\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12%\n" +
+	"\x0econtent_format\x18\x06 \x01(\tR\rcontentFormat\x12A\n" +
+	"\n" +
+	"encryption\x18\a \x01(\v2!.devjournal.v1.EncryptionMetadataR\n" +
+	"encryption\"$\n" +
 	"\x12DeleteEntryRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"/\n" +
 	"\x13DeleteEntryResponse\x12\x18\n" +
@@ -645,40 +798,44 @@ func file_devjournal_v1_journal_proto_rawDescGZIP() []byte {
 	return file_devjournal_v1_journal_proto_rawDescData
 }
 
-var file_devjournal_v1_journal_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_devjournal_v1_journal_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_devjournal_v1_journal_proto_goTypes = []any{
 	(*JournalEntry)(nil),          // 0: devjournal.v1.JournalEntry
-	(*CreateEntryRequest)(nil),    // 1: devjournal.v1.CreateEntryRequest
-	(*GetEntryRequest)(nil),       // 2: devjournal.v1.GetEntryRequest
-	(*ListEntriesRequest)(nil),    // 3: devjournal.v1.ListEntriesRequest
-	(*ListEntriesResponse)(nil),   // 4: devjournal.v1.ListEntriesResponse
-	(*UpdateEntryRequest)(nil),    // 5: devjournal.v1.UpdateEntryRequest
-	(*DeleteEntryRequest)(nil),    // 6: devjournal.v1.DeleteEntryRequest
-	(*DeleteEntryResponse)(nil),   // 7: devjournal.v1.DeleteEntryResponse
-	(*SearchEntriesRequest)(nil),  // 8: devjournal.v1.SearchEntriesRequest
-	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*EncryptionMetadata)(nil),    // 1: devjournal.v1.EncryptionMetadata
+	(*CreateEntryRequest)(nil),    // 2: devjournal.v1.CreateEntryRequest
+	(*GetEntryRequest)(nil),       // 3: devjournal.v1.GetEntryRequest
+	(*ListEntriesRequest)(nil),    // 4: devjournal.v1.ListEntriesRequest
+	(*ListEntriesResponse)(nil),   // 5: devjournal.v1.ListEntriesResponse
+	(*UpdateEntryRequest)(nil),    // 6: devjournal.v1.UpdateEntryRequest
+	(*DeleteEntryRequest)(nil),    // 7: devjournal.v1.DeleteEntryRequest
+	(*DeleteEntryResponse)(nil),   // 8: devjournal.v1.DeleteEntryResponse
+	(*SearchEntriesRequest)(nil),  // 9: devjournal.v1.SearchEntriesRequest
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
 }
 var file_devjournal_v1_journal_proto_depIdxs = []int32{
-	9, // 0: devjournal.v1.JournalEntry.created_at:type_name -> google.protobuf.Timestamp
-	9, // 1: devjournal.v1.JournalEntry.updated_at:type_name -> google.protobuf.Timestamp
-	0, // 2: devjournal.v1.ListEntriesResponse.entries:type_name -> devjournal.v1.JournalEntry
-	1, // 3: devjournal.v1.JournalService.CreateEntry:input_type -> devjournal.v1.CreateEntryRequest
-	2, // 4: devjournal.v1.JournalService.GetEntry:input_type -> devjournal.v1.GetEntryRequest
-	3, // 5: devjournal.v1.JournalService.ListEntries:input_type -> devjournal.v1.ListEntriesRequest
-	5, // 6: devjournal.v1.JournalService.UpdateEntry:input_type -> devjournal.v1.UpdateEntryRequest
-	6, // 7: devjournal.v1.JournalService.DeleteEntry:input_type -> devjournal.v1.DeleteEntryRequest
-	8, // 8: devjournal.v1.JournalService.SearchEntries:input_type -> devjournal.v1.SearchEntriesRequest
-	0, // 9: devjournal.v1.JournalService.CreateEntry:output_type -> devjournal.v1.JournalEntry
-	0, // 10: devjournal.v1.JournalService.GetEntry:output_type -> devjournal.v1.JournalEntry
-	4, // 11: devjournal.v1.JournalService.ListEntries:output_type -> devjournal.v1.ListEntriesResponse
-	0, // 12: devjournal.v1.JournalService.UpdateEntry:output_type -> devjournal.v1.JournalEntry
-	7, // 13: devjournal.v1.JournalService.DeleteEntry:output_type -> devjournal.v1.DeleteEntryResponse
-	4, // 14: devjournal.v1.JournalService.SearchEntries:output_type -> devjournal.v1.ListEntriesResponse
-	9, // [9:15] is the sub-list for method output_type
-	3, // [3:9] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	10, // 0: devjournal.v1.JournalEntry.created_at:type_name -> google.protobuf.Timestamp
+	10, // 1: devjournal.v1.JournalEntry.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: devjournal.v1.JournalEntry.encryption:type_name -> devjournal.v1.EncryptionMetadata
+	1,  // 3: devjournal.v1.CreateEntryRequest.encryption:type_name -> devjournal.v1.EncryptionMetadata
+	0,  // 4: devjournal.v1.ListEntriesResponse.entries:type_name -> devjournal.v1.JournalEntry
+	1,  // 5: devjournal.v1.UpdateEntryRequest.encryption:type_name -> devjournal.v1.EncryptionMetadata
+	2,  // 6: devjournal.v1.JournalService.CreateEntry:input_type -> devjournal.v1.CreateEntryRequest
+	3,  // 7: devjournal.v1.JournalService.GetEntry:input_type -> devjournal.v1.GetEntryRequest
+	4,  // 8: devjournal.v1.JournalService.ListEntries:input_type -> devjournal.v1.ListEntriesRequest
+	6,  // 9: devjournal.v1.JournalService.UpdateEntry:input_type -> devjournal.v1.UpdateEntryRequest
+	7,  // 10: devjournal.v1.JournalService.DeleteEntry:input_type -> devjournal.v1.DeleteEntryRequest
+	9,  // 11: devjournal.v1.JournalService.SearchEntries:input_type -> devjournal.v1.SearchEntriesRequest
+	0,  // 12: devjournal.v1.JournalService.CreateEntry:output_type -> devjournal.v1.JournalEntry
+	0,  // 13: devjournal.v1.JournalService.GetEntry:output_type -> devjournal.v1.JournalEntry
+	5,  // 14: devjournal.v1.JournalService.ListEntries:output_type -> devjournal.v1.ListEntriesResponse
+	0,  // 15: devjournal.v1.JournalService.UpdateEntry:output_type -> devjournal.v1.JournalEntry
+	8,  // 16: devjournal.v1.JournalService.DeleteEntry:output_type -> devjournal.v1.DeleteEntryResponse
+	5,  // 17: devjournal.v1.JournalService.SearchEntries:output_type -> devjournal.v1.ListEntriesResponse
+	12, // [12:18] is the sub-list for method output_type
+	6,  // [6:12] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_devjournal_v1_journal_proto_init() }
@@ -692,7 +849,7 @@ func file_devjournal_v1_journal_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_devjournal_v1_journal_proto_rawDesc), len(file_devjournal_v1_journal_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
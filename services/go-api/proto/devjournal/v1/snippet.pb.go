@@ -38,6 +38,7 @@ type Snippet struct {
 	ViewsCount    int32                  `protobuf:"varint,10,opt,name=views_count,json=viewsCount,proto3" json:"views_count,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Files         []*SnippetFile         `protobuf:"bytes,13,rep,name=files,proto3" json:"files,omitempty"` // code and language mirror the first file
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -156,6 +157,74 @@ func (x *Snippet) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Snippet) GetFiles() []*SnippetFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+// SnippetFile is one named file of a snippet
+type SnippetFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Language      string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"` // Detected from the name when empty
+	Code          string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnippetFile) Reset() {
+	*x = SnippetFile{}
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnippetFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnippetFile) ProtoMessage() {}
+
+func (x *SnippetFile) ProtoReflect() protoreflect.Message {
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnippetFile.ProtoReflect.Descriptor instead.
+func (*SnippetFile) Descriptor() ([]byte, []int) {
+	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SnippetFile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SnippetFile) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *SnippetFile) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
 // CreateSnippetRequest is the request to create a new snippet
 type CreateSnippetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -166,13 +235,14 @@ type CreateSnippetRequest struct {
 	Tags          []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
 	Metadata      *structpb.Struct       `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`
 	IsPublic      bool                   `protobuf:"varint,7,opt,name=is_public,json=isPublic,proto3" json:"is_public,omitempty"`
+	Files         []*SnippetFile         `protobuf:"bytes,8,rep,name=files,proto3" json:"files,omitempty"` // Takes the place of code and language when set
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateSnippetRequest) Reset() {
 	*x = CreateSnippetRequest{}
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[1]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -184,7 +254,7 @@ func (x *CreateSnippetRequest) String() string {
 func (*CreateSnippetRequest) ProtoMessage() {}
 
 func (x *CreateSnippetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[1]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -197,7 +267,7 @@ func (x *CreateSnippetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateSnippetRequest.ProtoReflect.Descriptor instead.
 func (*CreateSnippetRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{1}
+	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CreateSnippetRequest) GetTitle() string {
@@ -249,6 +319,13 @@ func (x *CreateSnippetRequest) GetIsPublic() bool {
 	return false
 }
 
+func (x *CreateSnippetRequest) GetFiles() []*SnippetFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
 // GetSnippetRequest is the request to retrieve a snippet
 type GetSnippetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -259,7 +336,7 @@ type GetSnippetRequest struct {
 
 func (x *GetSnippetRequest) Reset() {
 	*x = GetSnippetRequest{}
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[2]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -271,7 +348,7 @@ func (x *GetSnippetRequest) String() string {
 func (*GetSnippetRequest) ProtoMessage() {}
 
 func (x *GetSnippetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[2]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -284,7 +361,7 @@ func (x *GetSnippetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSnippetRequest.ProtoReflect.Descriptor instead.
 func (*GetSnippetRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{2}
+	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetSnippetRequest) GetId() string {
@@ -297,7 +374,7 @@ func (x *GetSnippetRequest) GetId() string {
 // ListSnippetsRequest is the request to list snippets with pagination
 type ListSnippetsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // Omit (0) to use the caller's preferred default page size
 	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
 	Language      string                 `protobuf:"bytes,3,opt,name=language,proto3" json:"language,omitempty"` // Optional filter by language
 	Tags          []string               `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`         // Optional filter by tags
@@ -307,7 +384,7 @@ type ListSnippetsRequest struct {
 
 func (x *ListSnippetsRequest) Reset() {
 	*x = ListSnippetsRequest{}
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[3]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -319,7 +396,7 @@ func (x *ListSnippetsRequest) String() string {
 func (*ListSnippetsRequest) ProtoMessage() {}
 
 func (x *ListSnippetsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[3]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -332,7 +409,7 @@ func (x *ListSnippetsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSnippetsRequest.ProtoReflect.Descriptor instead.
 func (*ListSnippetsRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{3}
+	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ListSnippetsRequest) GetLimit() int32 {
@@ -368,13 +445,14 @@ type ListSnippetsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Snippets      []*Snippet             `protobuf:"bytes,1,rep,name=snippets,proto3" json:"snippets,omitempty"`
 	TotalCount    int64                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	MaxPageSize   int32                  `protobuf:"varint,3,opt,name=max_page_size,json=maxPageSize,proto3" json:"max_page_size,omitempty"` // Server-side cap applied to limit
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListSnippetsResponse) Reset() {
 	*x = ListSnippetsResponse{}
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[4]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -386,7 +464,7 @@ func (x *ListSnippetsResponse) String() string {
 func (*ListSnippetsResponse) ProtoMessage() {}
 
 func (x *ListSnippetsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[4]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -399,7 +477,7 @@ func (x *ListSnippetsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSnippetsResponse.ProtoReflect.Descriptor instead.
 func (*ListSnippetsResponse) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{4}
+	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ListSnippetsResponse) GetSnippets() []*Snippet {
@@ -416,6 +494,13 @@ func (x *ListSnippetsResponse) GetTotalCount() int64 {
 	return 0
 }
 
+func (x *ListSnippetsResponse) GetMaxPageSize() int32 {
+	if x != nil {
+		return x.MaxPageSize
+	}
+	return 0
+}
+
 // UpdateSnippetRequest is the request to update a snippet
 type UpdateSnippetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -427,13 +512,14 @@ type UpdateSnippetRequest struct {
 	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
 	Metadata      *structpb.Struct       `protobuf:"bytes,7,opt,name=metadata,proto3" json:"metadata,omitempty"`
 	IsPublic      bool                   `protobuf:"varint,8,opt,name=is_public,json=isPublic,proto3" json:"is_public,omitempty"`
+	Files         []*SnippetFile         `protobuf:"bytes,9,rep,name=files,proto3" json:"files,omitempty"` // Replaces all files when set
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateSnippetRequest) Reset() {
 	*x = UpdateSnippetRequest{}
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[5]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -445,7 +531,7 @@ func (x *UpdateSnippetRequest) String() string {
 func (*UpdateSnippetRequest) ProtoMessage() {}
 
 func (x *UpdateSnippetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[5]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -458,7 +544,7 @@ func (x *UpdateSnippetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateSnippetRequest.ProtoReflect.Descriptor instead.
 func (*UpdateSnippetRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{5}
+	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateSnippetRequest) GetId() string {
@@ -517,6 +603,13 @@ func (x *UpdateSnippetRequest) GetIsPublic() bool {
 	return false
 }
 
+func (x *UpdateSnippetRequest) GetFiles() []*SnippetFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
 // DeleteSnippetRequest is the request to delete a snippet
 type DeleteSnippetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -527,7 +620,7 @@ type DeleteSnippetRequest struct {
 
 func (x *DeleteSnippetRequest) Reset() {
 	*x = DeleteSnippetRequest{}
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[6]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -539,7 +632,7 @@ func (x *DeleteSnippetRequest) String() string {
 func (*DeleteSnippetRequest) ProtoMessage() {}
 
 func (x *DeleteSnippetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[6]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -552,7 +645,7 @@ func (x *DeleteSnippetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteSnippetRequest.ProtoReflect.Descriptor instead.
 func (*DeleteSnippetRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{6}
+	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DeleteSnippetRequest) GetId() string {
@@ -572,7 +665,7 @@ type DeleteSnippetResponse struct {
 
 func (x *DeleteSnippetResponse) Reset() {
 	*x = DeleteSnippetResponse{}
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[7]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -584,7 +677,7 @@ func (x *DeleteSnippetResponse) String() string {
 func (*DeleteSnippetResponse) ProtoMessage() {}
 
 func (x *DeleteSnippetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[7]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -597,7 +690,7 @@ func (x *DeleteSnippetResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteSnippetResponse.ProtoReflect.Descriptor instead.
 func (*DeleteSnippetResponse) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{7}
+	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DeleteSnippetResponse) GetSuccess() bool {
@@ -619,7 +712,7 @@ type SearchSnippetsRequest struct {
 
 func (x *SearchSnippetsRequest) Reset() {
 	*x = SearchSnippetsRequest{}
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[8]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -631,7 +724,7 @@ func (x *SearchSnippetsRequest) String() string {
 func (*SearchSnippetsRequest) ProtoMessage() {}
 
 func (x *SearchSnippetsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[8]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -644,7 +737,7 @@ func (x *SearchSnippetsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchSnippetsRequest.ProtoReflect.Descriptor instead.
 func (*SearchSnippetsRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{8}
+	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *SearchSnippetsRequest) GetQuery() string {
@@ -668,16 +761,19 @@ func (x *SearchSnippetsRequest) GetOffset() int32 {
 	return 0
 }
 
-// GetLanguageStatsRequest is the request to get language statistics
+// GetLanguageStatsRequest is the request to get language statistics.
+// Leave interval and range empty for lifetime totals only.
 type GetLanguageStatsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interval      string                 `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"` // day, week, month, or year
+	Range         string                 `protobuf:"bytes,2,opt,name=range,proto3" json:"range,omitempty"`       // e.g. 30d, 12w, 6m, 1y
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetLanguageStatsRequest) Reset() {
 	*x = GetLanguageStatsRequest{}
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[9]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -689,7 +785,7 @@ func (x *GetLanguageStatsRequest) String() string {
 func (*GetLanguageStatsRequest) ProtoMessage() {}
 
 func (x *GetLanguageStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[9]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -702,20 +798,88 @@ func (x *GetLanguageStatsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetLanguageStatsRequest.ProtoReflect.Descriptor instead.
 func (*GetLanguageStatsRequest) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{9}
+	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetLanguageStatsRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetLanguageStatsRequest) GetRange() string {
+	if x != nil {
+		return x.Range
+	}
+	return ""
+}
+
+// LanguageStatsBucket contains snippet counts by language for one time bucket
+type LanguageStatsBucket struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PeriodStart    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=period_start,json=periodStart,proto3" json:"period_start,omitempty"`
+	LanguageCounts map[string]int64       `protobuf:"bytes,2,rep,name=language_counts,json=languageCounts,proto3" json:"language_counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *LanguageStatsBucket) Reset() {
+	*x = LanguageStatsBucket{}
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LanguageStatsBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LanguageStatsBucket) ProtoMessage() {}
+
+func (x *LanguageStatsBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LanguageStatsBucket.ProtoReflect.Descriptor instead.
+func (*LanguageStatsBucket) Descriptor() ([]byte, []int) {
+	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LanguageStatsBucket) GetPeriodStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PeriodStart
+	}
+	return nil
+}
+
+func (x *LanguageStatsBucket) GetLanguageCounts() map[string]int64 {
+	if x != nil {
+		return x.LanguageCounts
+	}
+	return nil
 }
 
 // GetLanguageStatsResponse contains snippet counts by language
 type GetLanguageStatsResponse struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	LanguageCounts map[string]int64       `protobuf:"bytes,1,rep,name=language_counts,json=languageCounts,proto3" json:"language_counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Buckets        []*LanguageStatsBucket `protobuf:"bytes,2,rep,name=buckets,proto3" json:"buckets,omitempty"` // Set when interval or range is given
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *GetLanguageStatsResponse) Reset() {
 	*x = GetLanguageStatsResponse{}
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[10]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -727,7 +891,7 @@ func (x *GetLanguageStatsResponse) String() string {
 func (*GetLanguageStatsResponse) ProtoMessage() {}
 
 func (x *GetLanguageStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_devjournal_v1_snippet_proto_msgTypes[10]
+	mi := &file_devjournal_v1_snippet_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -740,7 +904,7 @@ func (x *GetLanguageStatsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetLanguageStatsResponse.ProtoReflect.Descriptor instead.
 func (*GetLanguageStatsResponse) Descriptor() ([]byte, []int) {
-	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{10}
+	return file_devjournal_v1_snippet_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetLanguageStatsResponse) GetLanguageCounts() map[string]int64 {
@@ -750,11 +914,18 @@ func (x *GetLanguageStatsResponse) GetLanguageCounts() map[string]int64 {
 	return nil
 }
 
+func (x *GetLanguageStatsResponse) GetBuckets() []*LanguageStatsBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
 var File_devjournal_v1_snippet_proto protoreflect.FileDescriptor
 
 const file_devjournal_v1_snippet_proto_rawDesc = "" +
 	"\n" +
-	"\x1bdevjournal/v1/snippet.proto\x12\rdevjournal.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/protobuf/struct.proto\"\x97\x03\n" +
+	"\x1bdevjournal/v1/snippet.proto\x12\rdevjournal.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xc9\x03\n" +
 	"\aSnippet\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
@@ -771,7 +942,12 @@ const file_devjournal_v1_snippet_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xe4\x01\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x120\n" +
+	"\x05files\x18\r \x03(\v2\x1a.devjournal.v1.SnippetFileR\x05files\"Q\n" +
+	"\vSnippetFile\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\"\x96\x02\n" +
 	"\x14CreateSnippetRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x12\n" +
@@ -779,18 +955,20 @@ const file_devjournal_v1_snippet_proto_rawDesc = "" +
 	"\blanguage\x18\x04 \x01(\tR\blanguage\x12\x12\n" +
 	"\x04tags\x18\x05 \x03(\tR\x04tags\x123\n" +
 	"\bmetadata\x18\x06 \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12\x1b\n" +
-	"\tis_public\x18\a \x01(\bR\bisPublic\"#\n" +
+	"\tis_public\x18\a \x01(\bR\bisPublic\x120\n" +
+	"\x05files\x18\b \x03(\v2\x1a.devjournal.v1.SnippetFileR\x05files\"#\n" +
 	"\x11GetSnippetRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"s\n" +
 	"\x13ListSnippetsRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
 	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x1a\n" +
 	"\blanguage\x18\x03 \x01(\tR\blanguage\x12\x12\n" +
-	"\x04tags\x18\x04 \x03(\tR\x04tags\"k\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\"\x8f\x01\n" +
 	"\x14ListSnippetsResponse\x122\n" +
 	"\bsnippets\x18\x01 \x03(\v2\x16.devjournal.v1.SnippetR\bsnippets\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\x03R\n" +
-	"totalCount\"\xf4\x01\n" +
+	"totalCount\x12\"\n" +
+	"\rmax_page_size\x18\x03 \x01(\x05R\vmaxPageSize\"\xa6\x02\n" +
 	"\x14UpdateSnippetRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
@@ -799,7 +977,8 @@ const file_devjournal_v1_snippet_proto_rawDesc = "" +
 	"\blanguage\x18\x05 \x01(\tR\blanguage\x12\x12\n" +
 	"\x04tags\x18\x06 \x03(\tR\x04tags\x123\n" +
 	"\bmetadata\x18\a \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12\x1b\n" +
-	"\tis_public\x18\b \x01(\bR\bisPublic\"&\n" +
+	"\tis_public\x18\b \x01(\bR\bisPublic\x120\n" +
+	"\x05files\x18\t \x03(\v2\x1a.devjournal.v1.SnippetFileR\x05files\"&\n" +
 	"\x14DeleteSnippetRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"1\n" +
 	"\x15DeleteSnippetResponse\x12\x18\n" +
@@ -807,10 +986,19 @@ const file_devjournal_v1_snippet_proto_rawDesc = "" +
 	"\x15SearchSnippetsRequest\x12\x14\n" +
 	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x03 \x01(\x05R\x06offset\"\x19\n" +
-	"\x17GetLanguageStatsRequest\"\xc3\x01\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"K\n" +
+	"\x17GetLanguageStatsRequest\x12\x1a\n" +
+	"\binterval\x18\x01 \x01(\tR\binterval\x12\x14\n" +
+	"\x05range\x18\x02 \x01(\tR\x05range\"\xf8\x01\n" +
+	"\x13LanguageStatsBucket\x12=\n" +
+	"\fperiod_start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\vperiodStart\x12_\n" +
+	"\x0flanguage_counts\x18\x02 \x03(\v26.devjournal.v1.LanguageStatsBucket.LanguageCountsEntryR\x0elanguageCounts\x1aA\n" +
+	"\x13LanguageCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\x81\x02\n" +
 	"\x18GetLanguageStatsResponse\x12d\n" +
-	"\x0flanguage_counts\x18\x01 \x03(\v2;.devjournal.v1.GetLanguageStatsResponse.LanguageCountsEntryR\x0elanguageCounts\x1aA\n" +
+	"\x0flanguage_counts\x18\x01 \x03(\v2;.devjournal.v1.GetLanguageStatsResponse.LanguageCountsEntryR\x0elanguageCounts\x12<\n" +
+	"\abuckets\x18\x02 \x03(\v2\".devjournal.v1.LanguageStatsBucketR\abuckets\x1aA\n" +
 	"\x13LanguageCountsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x012\xeb\x04\n" +
@@ -837,50 +1025,59 @@ func file_devjournal_v1_snippet_proto_rawDescGZIP() []byte {
 	return file_devjournal_v1_snippet_proto_rawDescData
 }
 
-var file_devjournal_v1_snippet_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_devjournal_v1_snippet_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_devjournal_v1_snippet_proto_goTypes = []any{
 	(*Snippet)(nil),                  // 0: devjournal.v1.Snippet
-	(*CreateSnippetRequest)(nil),     // 1: devjournal.v1.CreateSnippetRequest
-	(*GetSnippetRequest)(nil),        // 2: devjournal.v1.GetSnippetRequest
-	(*ListSnippetsRequest)(nil),      // 3: devjournal.v1.ListSnippetsRequest
-	(*ListSnippetsResponse)(nil),     // 4: devjournal.v1.ListSnippetsResponse
-	(*UpdateSnippetRequest)(nil),     // 5: devjournal.v1.UpdateSnippetRequest
-	(*DeleteSnippetRequest)(nil),     // 6: devjournal.v1.DeleteSnippetRequest
-	(*DeleteSnippetResponse)(nil),    // 7: devjournal.v1.DeleteSnippetResponse
-	(*SearchSnippetsRequest)(nil),    // 8: devjournal.v1.SearchSnippetsRequest
-	(*GetLanguageStatsRequest)(nil),  // 9: devjournal.v1.GetLanguageStatsRequest
-	(*GetLanguageStatsResponse)(nil), // 10: devjournal.v1.GetLanguageStatsResponse
-	nil,                              // 11: devjournal.v1.GetLanguageStatsResponse.LanguageCountsEntry
-	(*structpb.Struct)(nil),          // 12: google.protobuf.Struct
-	(*timestamppb.Timestamp)(nil),    // 13: google.protobuf.Timestamp
+	(*SnippetFile)(nil),              // 1: devjournal.v1.SnippetFile
+	(*CreateSnippetRequest)(nil),     // 2: devjournal.v1.CreateSnippetRequest
+	(*GetSnippetRequest)(nil),        // 3: devjournal.v1.GetSnippetRequest
+	(*ListSnippetsRequest)(nil),      // 4: devjournal.v1.ListSnippetsRequest
+	(*ListSnippetsResponse)(nil),     // 5: devjournal.v1.ListSnippetsResponse
+	(*UpdateSnippetRequest)(nil),     // 6: devjournal.v1.UpdateSnippetRequest
+	(*DeleteSnippetRequest)(nil),     // 7: devjournal.v1.DeleteSnippetRequest
+	(*DeleteSnippetResponse)(nil),    // 8: devjournal.v1.DeleteSnippetResponse
+	(*SearchSnippetsRequest)(nil),    // 9: devjournal.v1.SearchSnippetsRequest
+	(*GetLanguageStatsRequest)(nil),  // 10: devjournal.v1.GetLanguageStatsRequest
+	(*LanguageStatsBucket)(nil),      // 11: devjournal.v1.LanguageStatsBucket
+	(*GetLanguageStatsResponse)(nil), // 12: devjournal.v1.GetLanguageStatsResponse
+	nil,                              // 13: devjournal.v1.LanguageStatsBucket.LanguageCountsEntry
+	nil,                              // 14: devjournal.v1.GetLanguageStatsResponse.LanguageCountsEntry
+	(*structpb.Struct)(nil),          // 15: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),    // 16: google.protobuf.Timestamp
 }
 var file_devjournal_v1_snippet_proto_depIdxs = []int32{
-	12, // 0: devjournal.v1.Snippet.metadata:type_name -> google.protobuf.Struct
-	13, // 1: devjournal.v1.Snippet.created_at:type_name -> google.protobuf.Timestamp
-	13, // 2: devjournal.v1.Snippet.updated_at:type_name -> google.protobuf.Timestamp
-	12, // 3: devjournal.v1.CreateSnippetRequest.metadata:type_name -> google.protobuf.Struct
-	0,  // 4: devjournal.v1.ListSnippetsResponse.snippets:type_name -> devjournal.v1.Snippet
-	12, // 5: devjournal.v1.UpdateSnippetRequest.metadata:type_name -> google.protobuf.Struct
-	11, // 6: devjournal.v1.GetLanguageStatsResponse.language_counts:type_name -> devjournal.v1.GetLanguageStatsResponse.LanguageCountsEntry
-	1,  // 7: devjournal.v1.SnippetService.CreateSnippet:input_type -> devjournal.v1.CreateSnippetRequest
-	2,  // 8: devjournal.v1.SnippetService.GetSnippet:input_type -> devjournal.v1.GetSnippetRequest
-	3,  // 9: devjournal.v1.SnippetService.ListSnippets:input_type -> devjournal.v1.ListSnippetsRequest
-	5,  // 10: devjournal.v1.SnippetService.UpdateSnippet:input_type -> devjournal.v1.UpdateSnippetRequest
-	6,  // 11: devjournal.v1.SnippetService.DeleteSnippet:input_type -> devjournal.v1.DeleteSnippetRequest
-	8,  // 12: devjournal.v1.SnippetService.SearchSnippets:input_type -> devjournal.v1.SearchSnippetsRequest
-	9,  // 13: devjournal.v1.SnippetService.GetLanguageStats:input_type -> devjournal.v1.GetLanguageStatsRequest
-	0,  // 14: devjournal.v1.SnippetService.CreateSnippet:output_type -> devjournal.v1.Snippet
-	0,  // 15: devjournal.v1.SnippetService.GetSnippet:output_type -> devjournal.v1.Snippet
-	4,  // 16: devjournal.v1.SnippetService.ListSnippets:output_type -> devjournal.v1.ListSnippetsResponse
-	0,  // 17: devjournal.v1.SnippetService.UpdateSnippet:output_type -> devjournal.v1.Snippet
-	7,  // 18: devjournal.v1.SnippetService.DeleteSnippet:output_type -> devjournal.v1.DeleteSnippetResponse
-	4,  // 19: devjournal.v1.SnippetService.SearchSnippets:output_type -> devjournal.v1.ListSnippetsResponse
-	10, // 20: devjournal.v1.SnippetService.GetLanguageStats:output_type -> devjournal.v1.GetLanguageStatsResponse
-	14, // [14:21] is the sub-list for method output_type
-	7,  // [7:14] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	15, // 0: devjournal.v1.Snippet.metadata:type_name -> google.protobuf.Struct
+	16, // 1: devjournal.v1.Snippet.created_at:type_name -> google.protobuf.Timestamp
+	16, // 2: devjournal.v1.Snippet.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: devjournal.v1.Snippet.files:type_name -> devjournal.v1.SnippetFile
+	15, // 4: devjournal.v1.CreateSnippetRequest.metadata:type_name -> google.protobuf.Struct
+	1,  // 5: devjournal.v1.CreateSnippetRequest.files:type_name -> devjournal.v1.SnippetFile
+	0,  // 6: devjournal.v1.ListSnippetsResponse.snippets:type_name -> devjournal.v1.Snippet
+	15, // 7: devjournal.v1.UpdateSnippetRequest.metadata:type_name -> google.protobuf.Struct
+	1,  // 8: devjournal.v1.UpdateSnippetRequest.files:type_name -> devjournal.v1.SnippetFile
+	16, // 9: devjournal.v1.LanguageStatsBucket.period_start:type_name -> google.protobuf.Timestamp
+	13, // 10: devjournal.v1.LanguageStatsBucket.language_counts:type_name -> devjournal.v1.LanguageStatsBucket.LanguageCountsEntry
+	14, // 11: devjournal.v1.GetLanguageStatsResponse.language_counts:type_name -> devjournal.v1.GetLanguageStatsResponse.LanguageCountsEntry
+	11, // 12: devjournal.v1.GetLanguageStatsResponse.buckets:type_name -> devjournal.v1.LanguageStatsBucket
+	2,  // 13: devjournal.v1.SnippetService.CreateSnippet:input_type -> devjournal.v1.CreateSnippetRequest
+	3,  // 14: devjournal.v1.SnippetService.GetSnippet:input_type -> devjournal.v1.GetSnippetRequest
+	4,  // 15: devjournal.v1.SnippetService.ListSnippets:input_type -> devjournal.v1.ListSnippetsRequest
+	6,  // 16: devjournal.v1.SnippetService.UpdateSnippet:input_type -> devjournal.v1.UpdateSnippetRequest
+	7,  // 17: devjournal.v1.SnippetService.DeleteSnippet:input_type -> devjournal.v1.DeleteSnippetRequest
+	9,  // 18: devjournal.v1.SnippetService.SearchSnippets:input_type -> devjournal.v1.SearchSnippetsRequest
+	10, // 19: devjournal.v1.SnippetService.GetLanguageStats:input_type -> devjournal.v1.GetLanguageStatsRequest
+	0,  // 20: devjournal.v1.SnippetService.CreateSnippet:output_type -> devjournal.v1.Snippet
+	0,  // 21: devjournal.v1.SnippetService.GetSnippet:output_type -> devjournal.v1.Snippet
+	5,  // 22: devjournal.v1.SnippetService.ListSnippets:output_type -> devjournal.v1.ListSnippetsResponse
+	0,  // 23: devjournal.v1.SnippetService.UpdateSnippet:output_type -> devjournal.v1.Snippet
+	8,  // 24: devjournal.v1.SnippetService.DeleteSnippet:output_type -> devjournal.v1.DeleteSnippetResponse
+	5,  // 25: devjournal.v1.SnippetService.SearchSnippets:output_type -> devjournal.v1.ListSnippetsResponse
+	12, // 26: devjournal.v1.SnippetService.GetLanguageStats:output_type -> devjournal.v1.GetLanguageStatsResponse
+	20, // [20:27] is the sub-list for method output_type
+	13, // [13:20] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_devjournal_v1_snippet_proto_init() }
@@ -894,7 +1091,7 @@ func file_devjournal_v1_snippet_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_devjournal_v1_snippet_proto_rawDesc), len(file_devjournal_v1_snippet_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},